@@ -0,0 +1,28 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+/*
+Package bls manages the BLS12-381 secret keys of the validators.
+
+The secret keys are derived from seeds as EIP-2333 by the EIP-2334 key paths,
+of which the coin type is 8217 for Klaytn, and are stored in the EIP-2335
+keystores encrypted with scrypt or PBKDF2.
+
+The public keys and the proofs of possession require the BLS12-381 curve
+arithmetic, which is not provided yet. A generated key has no public key,
+while the public key of an imported keystore is kept as is.
+*/
+package bls
@@ -0,0 +1,137 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package bls
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// CoinType is the coin type of Klaytn in the EIP-2334 key paths.
+const CoinType = 8217
+
+// SigningKeyPath is the EIP-2334 path of the signing key of the first
+// validator derived from a seed.
+var SigningKeyPath = fmt.Sprintf("m/12381/%d/0/0", CoinType)
+
+// curveOrder is the order r of the BLS12-381 subgroups, of which the secret
+// keys are the scalars.
+var curveOrder, _ = new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+
+var (
+	errShortSeed   = errors.New("seed should be at least 32 bytes")
+	errInvalidPath = errors.New("invalid EIP-2334 key path")
+)
+
+// hkdfModR derives a secret key from the key material as HKDF_mod_r of
+// EIP-2333.
+func hkdfModR(ikm []byte) *big.Int {
+	salt := []byte("BLS-SIG-KEYGEN-SALT-")
+	sk := new(big.Int)
+	for sk.Sign() == 0 {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		okm := make([]byte, 48)
+		r := hkdf.New(sha256.New, append(ikm, 0), salt, []byte{0, 48})
+		if _, err := io.ReadFull(r, okm); err != nil {
+			panic(err) // Unreachable since the length is within the limit of HKDF
+		}
+		sk.Mod(new(big.Int).SetBytes(okm), curveOrder)
+	}
+	return sk
+}
+
+// lamportPK returns the hashes of the Lamport secret key chunks derived from
+// the key material, which form the Lamport public key.
+func lamportPK(ikm, salt []byte) []byte {
+	okm := make([]byte, 32*255)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, ikm, salt, nil), okm); err != nil {
+		panic(err) // Unreachable since the length is within the limit of HKDF
+	}
+	pk := make([]byte, 0, len(okm))
+	for i := 0; i < len(okm); i += 32 {
+		h := sha256.Sum256(okm[i : i+32])
+		pk = append(pk, h[:]...)
+	}
+	return pk
+}
+
+// DeriveMasterSK derives the master secret key from the seed as EIP-2333.
+func DeriveMasterSK(seed []byte) (*big.Int, error) {
+	if len(seed) < 32 {
+		return nil, errShortSeed
+	}
+	return hkdfModR(seed), nil
+}
+
+// DeriveChildSK derives the child secret key of the index from the parent
+// secret key as EIP-2333.
+func DeriveChildSK(parent *big.Int, index uint32) *big.Int {
+	salt := make([]byte, 4)
+	binary.BigEndian.PutUint32(salt, index)
+	ikm := make([]byte, 32)
+	parent.FillBytes(ikm)
+	notIKM := make([]byte, 32)
+	for i := range ikm {
+		notIKM[i] = ^ikm[i]
+	}
+	pk := append(lamportPK(ikm, salt), lamportPK(notIKM, salt)...)
+	compressed := sha256.Sum256(pk)
+	return hkdfModR(compressed[:])
+}
+
+// ParsePath parses an EIP-2334 key path such as "m/12381/8217/0/0" into the
+// indices of its levels.
+func ParsePath(path string) ([]uint32, error) {
+	levels := strings.Split(strings.TrimSpace(path), "/")
+	if len(levels) < 2 || levels[0] != "m" || levels[1] != "12381" {
+		return nil, fmt.Errorf("%w: %s", errInvalidPath, path)
+	}
+	indices := make([]uint32, 0, len(levels)-1)
+	for _, level := range levels[1:] {
+		index, err := strconv.ParseUint(level, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidPath, path)
+		}
+		indices = append(indices, uint32(index))
+	}
+	return indices, nil
+}
+
+// DeriveSK derives the secret key of the EIP-2334 key path from the seed.
+func DeriveSK(seed []byte, path string) (*big.Int, error) {
+	indices, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	sk, err := DeriveMasterSK(seed)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indices {
+		sk = DeriveChildSK(sk, index)
+	}
+	return sk, nil
+}
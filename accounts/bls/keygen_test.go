@@ -0,0 +1,80 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package bls
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The test vectors of EIP-2333.
+var keygenTests = []struct {
+	seed     string
+	masterSK string
+	index    uint32
+	childSK  string
+}{
+	{
+		seed:     "0xc55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		masterSK: "6083874454709270928345386274498605044986640685124978867557563392430687146096",
+		index:    0,
+		childSK:  "20397789859736650942317412262472558107875392172444076792671091975210932703118",
+	},
+	{
+		seed:     "0x3141592653589793238462643383279502884197169399375105820974944592",
+		masterSK: "29757020647961307431480504535336562678282505419141012933316116377660817309383",
+		index:    3141592653,
+		childSK:  "25457201688850691947727629385191704516744796114925897962676248250929345014287",
+	},
+	{
+		seed:     "0x0099FF991111002299DD7744EE3355BBDD8844115566CC55663355668888CC00",
+		masterSK: "27580842291869792442942448775674722299803720648445448686099262467207037398656",
+		index:    4294967295,
+		childSK:  "29358610794459428860402234341874281240803786294062035874021252734817515685787",
+	},
+}
+
+func TestDeriveSK(t *testing.T) {
+	for i, tt := range keygenTests {
+		masterSK, err := DeriveMasterSK(common.FromHex(tt.seed))
+		require.NoError(t, err)
+		assert.Equal(t, tt.masterSK, masterSK.String(), "test %d", i)
+		assert.Equal(t, tt.childSK, DeriveChildSK(masterSK, tt.index).String(), "test %d", i)
+	}
+
+	_, err := DeriveMasterSK(make([]byte, 31))
+	assert.Equal(t, errShortSeed, err)
+
+	seed := common.FromHex(keygenTests[0].seed)
+	sk, err := DeriveSK(seed, SigningKeyPath)
+	require.NoError(t, err)
+	expected, _ := DeriveMasterSK(seed)
+	for _, index := range []uint32{12381, 8217, 0, 0} {
+		expected = DeriveChildSK(expected, index)
+	}
+	assert.Equal(t, expected, sk)
+	assert.True(t, sk.Cmp(curveOrder) < 0 && sk.Cmp(new(big.Int)) > 0)
+
+	for _, path := range []string{"", "m", "m/44/8217/0/0", "m/12381/-1", "m/12381/4294967296", "12381/0"} {
+		_, err := ParsePath(path)
+		assert.Error(t, err, path)
+	}
+}
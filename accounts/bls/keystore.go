@@ -0,0 +1,299 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package bls
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"unicode/utf8"
+
+	"github.com/pborman/uuid"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// The KDF functions of the EIP-2335 keystores.
+const (
+	KDFScrypt = "scrypt"
+	KDFPBKDF2 = "pbkdf2"
+)
+
+const (
+	// StandardScryptN and StandardPBKDF2C are the parameters of the KDFs
+	// recommended by EIP-2335.
+	StandardScryptN = 1 << 18
+	StandardPBKDF2C = 1 << 18
+
+	// LightScryptN is a lighter scrypt parameter, e.g. for the tests.
+	LightScryptN = 1 << 12
+
+	keystoreVersion = 4
+	kdfKeyLen       = 32
+)
+
+var (
+	ErrDecrypt           = errors.New("could not decrypt the BLS key with the given password")
+	errNonASCIIPassword  = errors.New("passwords other than printable ASCII are not supported yet")
+	errKeystoreVersion   = errors.New("unsupported BLS keystore version")
+	errUnsupportedKDF    = errors.New("unsupported KDF of the BLS keystore")
+	errUnsupportedCipher = errors.New("unsupported cipher of the BLS keystore")
+	errInvalidSecretKey  = errors.New("invalid BLS secret key")
+)
+
+// Key is a BLS12-381 secret key with the metadata of its EIP-2335 keystore.
+type Key struct {
+	SecretKey   *big.Int
+	PublicKey   []byte // Compressed G1 public key, empty if unknown
+	Path        string // EIP-2334 path the key is derived by, empty if not derived
+	Description string
+	ID          uuid.UUID
+}
+
+// NewKey returns a key of the secret key derived from the seed by the path.
+func NewKey(seed []byte, path string) (*Key, error) {
+	sk, err := DeriveSK(seed, path)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{SecretKey: sk, Path: path, ID: uuid.NewRandom()}, nil
+}
+
+// GenerateKey returns a key of the signing key derived from a random seed.
+func GenerateKey() (*Key, error) {
+	seed := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, seed); err != nil {
+		return nil, err
+	}
+	key, err := NewKey(seed, SigningKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	// The seed is not kept, so the key is not known to be derived by the path.
+	key.Path = ""
+	return key, nil
+}
+
+type module struct {
+	Function string                 `json:"function"`
+	Params   map[string]interface{} `json:"params"`
+	Message  string                 `json:"message"`
+}
+
+type keystoreJSON struct {
+	Crypto struct {
+		KDF      module `json:"kdf"`
+		Checksum module `json:"checksum"`
+		Cipher   module `json:"cipher"`
+	} `json:"crypto"`
+	Description string `json:"description"`
+	Pubkey      string `json:"pubkey"`
+	Path        string `json:"path"`
+	UUID        string `json:"uuid"`
+	Version     int    `json:"version"`
+}
+
+// processPassword processes the password as EIP-2335, stripping the control
+// codes. The NFKD normalization is the identity for the ASCII passwords, the
+// only ones supported for now.
+func processPassword(password string) ([]byte, error) {
+	var processed []byte
+	for _, c := range password {
+		if c >= utf8.RuneSelf {
+			return nil, errNonASCIIPassword
+		}
+		if c < 0x20 || c == 0x7f {
+			continue
+		}
+		processed = append(processed, byte(c))
+	}
+	return processed, nil
+}
+
+func deriveKey(kdf *module, password []byte) ([]byte, error) {
+	salt, err := hex.DecodeString(paramString(kdf.Params, "salt"))
+	if err != nil {
+		return nil, err
+	}
+	if dklen := paramInt(kdf.Params, "dklen"); dklen != kdfKeyLen {
+		return nil, fmt.Errorf("%w: dklen %d", errUnsupportedKDF, dklen)
+	}
+	switch kdf.Function {
+	case KDFScrypt:
+		return scrypt.Key(password, salt, paramInt(kdf.Params, "n"), paramInt(kdf.Params, "r"), paramInt(kdf.Params, "p"), kdfKeyLen)
+	case KDFPBKDF2:
+		if prf := paramString(kdf.Params, "prf"); prf != "hmac-sha256" {
+			return nil, fmt.Errorf("%w: prf %s", errUnsupportedKDF, prf)
+		}
+		return pbkdf2.Key(password, salt, paramInt(kdf.Params, "c"), kdfKeyLen, sha256.New), nil
+	}
+	return nil, fmt.Errorf("%w: %s", errUnsupportedKDF, kdf.Function)
+}
+
+func paramString(params map[string]interface{}, name string) string {
+	s, _ := params[name].(string)
+	return s
+}
+
+func paramInt(params map[string]interface{}, name string) int {
+	f, _ := params[name].(float64)
+	return int(f)
+}
+
+func checksum(dk, cipherText []byte) []byte {
+	h := sha256.Sum256(append(append([]byte{}, dk[16:32]...), cipherText...))
+	return h[:]
+}
+
+func aesCTR(key, iv, in []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(in))
+	cipher.NewCTR(block, iv).XORKeyStream(out, in)
+	return out, nil
+}
+
+// EncryptKey encrypts the key into an EIP-2335 keystore with the password,
+// using scrypt of the parameter n if kdf is KDFScrypt or PBKDF2 of the
+// iterations n if kdf is KDFPBKDF2.
+func EncryptKey(key *Key, password, kdf string, n int) ([]byte, error) {
+	pw, err := processPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	if key.SecretKey.Sign() <= 0 || key.SecretKey.Cmp(curveOrder) >= 0 {
+		return nil, errInvalidSecretKey
+	}
+	salt := make([]byte, 32)
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+
+	var (
+		ks     keystoreJSON
+		dk     []byte
+		params = map[string]interface{}{"dklen": kdfKeyLen, "salt": hex.EncodeToString(salt)}
+	)
+	switch kdf {
+	case KDFScrypt:
+		params["n"], params["r"], params["p"] = n, 8, 1
+		if dk, err = scrypt.Key(pw, salt, n, 8, 1, kdfKeyLen); err != nil {
+			return nil, err
+		}
+	case KDFPBKDF2:
+		params["c"], params["prf"] = n, "hmac-sha256"
+		dk = pbkdf2.Key(pw, salt, n, kdfKeyLen, sha256.New)
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedKDF, kdf)
+	}
+	ks.Crypto.KDF = module{Function: kdf, Params: params}
+	secret := make([]byte, 32)
+	key.SecretKey.FillBytes(secret)
+	cipherText, err := aesCTR(dk[:16], iv, secret)
+	if err != nil {
+		return nil, err
+	}
+	ks.Crypto.Checksum = module{Function: "sha256", Params: map[string]interface{}{}, Message: hex.EncodeToString(checksum(dk, cipherText))}
+	ks.Crypto.Cipher = module{
+		Function: "aes-128-ctr",
+		Params:   map[string]interface{}{"iv": hex.EncodeToString(iv)},
+		Message:  hex.EncodeToString(cipherText),
+	}
+	ks.Description = key.Description
+	ks.Pubkey = hex.EncodeToString(key.PublicKey)
+	ks.Path = key.Path
+	id := key.ID
+	if id == nil {
+		id = uuid.NewRandom()
+	}
+	ks.UUID = id.String()
+	ks.Version = keystoreVersion
+	return json.MarshalIndent(&ks, "", "  ")
+}
+
+// DecryptKey decrypts the key of the EIP-2335 keystore with the password.
+func DecryptKey(keyJSON []byte, password string) (*Key, error) {
+	var ks keystoreJSON
+	if err := json.Unmarshal(keyJSON, &ks); err != nil {
+		return nil, err
+	}
+	if ks.Version != keystoreVersion {
+		return nil, fmt.Errorf("%w: %d", errKeystoreVersion, ks.Version)
+	}
+	if ks.Crypto.Cipher.Function != "aes-128-ctr" || ks.Crypto.Checksum.Function != "sha256" {
+		return nil, fmt.Errorf("%w: %s, %s", errUnsupportedCipher, ks.Crypto.Cipher.Function, ks.Crypto.Checksum.Function)
+	}
+	pw, err := processPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	dk, err := deriveKey(&ks.Crypto.KDF, pw)
+	if err != nil {
+		return nil, err
+	}
+	cipherText, err := hex.DecodeString(ks.Crypto.Cipher.Message)
+	if err != nil {
+		return nil, err
+	}
+	sum, err := hex.DecodeString(ks.Crypto.Checksum.Message)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(checksum(dk, cipherText), sum) {
+		return nil, ErrDecrypt
+	}
+	iv, err := hex.DecodeString(paramString(ks.Crypto.Cipher.Params, "iv"))
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("%w: iv of %d bytes", errUnsupportedCipher, len(iv))
+	}
+	secret, err := aesCTR(dk[:16], iv, cipherText)
+	if err != nil {
+		return nil, err
+	}
+	sk := new(big.Int).SetBytes(secret)
+	if sk.Sign() == 0 || sk.Cmp(curveOrder) >= 0 {
+		return nil, errInvalidSecretKey
+	}
+	pubkey, err := hex.DecodeString(ks.Pubkey)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{
+		SecretKey:   sk,
+		PublicKey:   pubkey,
+		Path:        ks.Path,
+		Description: ks.Description,
+		ID:          uuid.Parse(ks.UUID),
+	}, nil
+}
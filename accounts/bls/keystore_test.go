@@ -0,0 +1,76 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package bls
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptKey(t *testing.T) {
+	key, err := GenerateKey()
+	require.NoError(t, err)
+	key.Description = "validator"
+
+	for _, kdf := range []string{KDFScrypt, KDFPBKDF2} {
+		keyJSON, err := EncryptKey(key, "password", kdf, LightScryptN)
+		require.NoError(t, err)
+
+		var ks keystoreJSON
+		require.NoError(t, json.Unmarshal(keyJSON, &ks))
+		assert.Equal(t, kdf, ks.Crypto.KDF.Function)
+		assert.Equal(t, 4, ks.Version)
+		assert.Equal(t, key.ID.String(), ks.UUID)
+
+		decrypted, err := DecryptKey(keyJSON, "password")
+		require.NoError(t, err)
+		assert.Equal(t, key.SecretKey, decrypted.SecretKey)
+		assert.Equal(t, key.Description, decrypted.Description)
+		assert.Equal(t, key.ID, decrypted.ID)
+
+		// The control codes are stripped from the passwords.
+		_, err = DecryptKey(keyJSON, "pass\x7fword\n")
+		assert.NoError(t, err)
+		_, err = DecryptKey(keyJSON, "Password")
+		assert.Equal(t, ErrDecrypt, err)
+	}
+
+	_, err = EncryptKey(key, "pässword", KDFScrypt, LightScryptN)
+	assert.Equal(t, errNonASCIIPassword, err)
+	_, err = EncryptKey(key, "password", "argon2id", LightScryptN)
+	assert.ErrorIs(t, err, errUnsupportedKDF)
+}
+
+func TestNewKey(t *testing.T) {
+	seed := make([]byte, 32)
+	key, err := NewKey(seed, SigningKeyPath)
+	require.NoError(t, err)
+	assert.Equal(t, "m/12381/8217/0/0", key.Path)
+
+	keyJSON, err := EncryptKey(key, "password", KDFPBKDF2, 1<<10)
+	require.NoError(t, err)
+	decrypted, err := DecryptKey(keyJSON, "password")
+	require.NoError(t, err)
+	assert.Equal(t, key.Path, decrypted.Path)
+
+	sk, err := DeriveSK(seed, decrypted.Path)
+	require.NoError(t, err)
+	assert.Equal(t, sk, decrypted.SecretKey)
+}
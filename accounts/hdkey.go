@@ -0,0 +1,105 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/klaytn/klaytn/common/math"
+	"github.com/klaytn/klaytn/crypto"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+var (
+	errInvalidMnemonic = errors.New("invalid mnemonic")
+	errInvalidHDKey    = errors.New("invalid derived key, use the next index")
+)
+
+// mnemonicWordCounts are the numbers of the words of the BIP-39 mnemonics.
+var mnemonicWordCounts = map[int]bool{12: true, 15: true, 18: true, 21: true, 24: true}
+
+// NewSeedFromMnemonic returns the BIP-39 seed of the mnemonic and the optional
+// passphrase. Only the mnemonics in ASCII, like the English ones, are accepted
+// since the seed of the others depends on the unicode normalization. The words
+// are not checked against a word list, so the derived addresses should be
+// confirmed before use.
+func NewSeedFromMnemonic(mnemonic, passphrase string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	if !mnemonicWordCounts[len(words)] {
+		return nil, fmt.Errorf("%w: %d words", errInvalidMnemonic, len(words))
+	}
+	normalized := strings.Join(words, " ")
+	for _, s := range []string{normalized, passphrase} {
+		for i := 0; i < len(s); i++ {
+			if s[i] >= 0x80 {
+				return nil, fmt.Errorf("%w: only ASCII is supported", errInvalidMnemonic)
+			}
+		}
+	}
+	return pbkdf2.Key([]byte(normalized), []byte("mnemonic"+passphrase), 2048, 64, sha512.New), nil
+}
+
+// DeriveKey derives the private key of the path from the seed by BIP-32.
+func DeriveKey(seed []byte, path DerivationPath) (*ecdsa.PrivateKey, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	n := crypto.S256().Params().N
+	key, chainCode := new(big.Int).SetBytes(sum[:32]), sum[32:]
+	if key.Sign() == 0 || key.Cmp(n) >= 0 {
+		return nil, errInvalidHDKey
+	}
+	for _, index := range path {
+		data := make([]byte, 0, 37)
+		if index >= 0x80000000 {
+			data = append(data, 0)
+			data = append(data, math.PaddedBigBytes(key, 32)...)
+		} else {
+			priv, err := crypto.ToECDSA(math.PaddedBigBytes(key, 32))
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, crypto.CompressPubkey(&priv.PublicKey)...)
+		}
+		var indexBytes [4]byte
+		binary.BigEndian.PutUint32(indexBytes[:], index)
+		data = append(data, indexBytes[:]...)
+
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		sum := mac.Sum(nil)
+
+		tweak := new(big.Int).SetBytes(sum[:32])
+		if tweak.Cmp(n) >= 0 {
+			return nil, errInvalidHDKey
+		}
+		key = tweak.Add(tweak, key).Mod(tweak, n)
+		if key.Sign() == 0 {
+			return nil, errInvalidHDKey
+		}
+		chainCode = sum[32:]
+	}
+	return crypto.ToECDSA(math.PaddedBigBytes(key, 32))
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+var testMnemonic = strings.Repeat("abandon ", 11) + "about"
+
+// Tests the seed of the BIP-39 test vector.
+func TestNewSeedFromMnemonic(t *testing.T) {
+	seed, err := NewSeedFromMnemonic(testMnemonic, "TREZOR")
+	assert.NoError(t, err)
+	assert.Equal(t, "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04", common.Bytes2Hex(seed))
+
+	// Extra whitespaces are ignored.
+	spaced, err := NewSeedFromMnemonic("  "+strings.Replace(testMnemonic, " ", "\n ", 3), "TREZOR")
+	assert.NoError(t, err)
+	assert.Equal(t, seed, spaced)
+
+	_, err = NewSeedFromMnemonic("abandon about", "")
+	assert.ErrorIs(t, err, errInvalidMnemonic)
+	_, err = NewSeedFromMnemonic(testMnemonic, "pässword")
+	assert.ErrorIs(t, err, errInvalidMnemonic)
+}
+
+// Tests the keys derived by the BIP-32 test vector 1 and the BIP-44 paths.
+func TestDeriveKey(t *testing.T) {
+	seed := common.Hex2Bytes("000102030405060708090a0b0c0d0e0f")
+	tests := []struct {
+		path string
+		key  string
+	}{
+		{"m", "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+		{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	}
+	for _, tt := range tests {
+		path := DerivationPath{}
+		if tt.path != "m" {
+			var err error
+			path, err = ParseDerivationPath(tt.path)
+			assert.NoError(t, err)
+		}
+		key, err := DeriveKey(seed, path)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.key, common.Bytes2Hex(crypto.FromECDSA(key)), tt.path)
+	}
+
+	seed, err := NewSeedFromMnemonic(testMnemonic, "")
+	assert.NoError(t, err)
+	key, err := DeriveKey(seed, DerivationPath{0x80000000 + 44, 0x80000000 + 60, 0x80000000 + 0, 0, 0})
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94"), crypto.PubkeyToAddress(key.PublicKey))
+
+	key, err = DeriveKey(seed, DefaultBaseDerivationPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "b3a102dac6adc74c556ad078399b020bb90386987883122c27443865b5ed5b9e", common.Bytes2Hex(crypto.FromECDSA(key)))
+}
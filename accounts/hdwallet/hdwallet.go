@@ -0,0 +1,190 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package hdwallet implements the BIP-32/39/44 HD wallets of mnemonics, whose
+// accounts are derived by the paths like m/44'/8217'/0'/0/0 as the other
+// Klaytn wallets do, e.g. the ones of caver and Kaikas.
+//
+// The seed of a wallet is encrypted in the keystore format, and the pinned
+// accounts are kept in the wallet file along with their derivation paths, so
+// they are listed even while the wallet is locked.
+package hdwallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/accounts/keystore"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/event"
+	"github.com/klaytn/klaytn/log"
+	"github.com/pborman/uuid"
+)
+
+// URLScheme is the scheme of the URLs of the HD wallets.
+const URLScheme = "hd"
+
+// BackendType is the reflect type of the HD wallet backend.
+var BackendType = reflect.TypeOf(&Backend{})
+
+var logger = log.NewModuleLogger(log.AccountsHDWallet)
+
+var errLocked = errors.New("HD wallet locked, open it with the passphrase")
+
+// pinnedAccount is an account pinned in the wallet file.
+type pinnedAccount struct {
+	Address common.Address `json:"address"`
+	Path    string         `json:"path"`
+}
+
+// walletFile is the content of a wallet file.
+type walletFile struct {
+	ID       string          `json:"id"`
+	Crypto   json.RawMessage `json:"crypto"`
+	Accounts []pinnedAccount `json:"accounts"`
+}
+
+// Backend is the accounts.Backend of the HD wallets in a directory.
+type Backend struct {
+	dir string
+	kdf keystore.KDFConfig
+
+	wallets []*wallet
+	feed    event.Feed
+	mu      sync.RWMutex
+}
+
+// NewBackend loads the HD wallets in the directory, whose seeds are encrypted
+// with the KDF config when imported.
+func NewBackend(dir string, kdf keystore.KDFConfig) (*Backend, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	b := &Backend{dir: dir, kdf: kdf}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, fi := range files {
+		if !fi.Mode().IsRegular() || !strings.HasSuffix(fi.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(dir, fi.Name())
+		w, err := loadWallet(b, path)
+		if err != nil {
+			logger.Warn("Ignored an invalid HD wallet", "path", path, "err", err)
+			continue
+		}
+		b.wallets = append(b.wallets, w)
+	}
+	sort.Slice(b.wallets, func(i, j int) bool { return b.wallets[i].url.Cmp(b.wallets[j].url) < 0 })
+	return b, nil
+}
+
+func loadWallet(b *Backend, path string) (*wallet, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file walletFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	w := newWallet(b, path, &file)
+	for _, pinned := range file.Accounts {
+		derivationPath, err := accounts.ParseDerivationPath(pinned.Path)
+		if err != nil {
+			return nil, err
+		}
+		w.addAccount(pinned.Address, derivationPath)
+	}
+	return w, nil
+}
+
+// Wallets implements accounts.Backend, returning the HD wallets.
+func (b *Backend) Wallets() []accounts.Wallet {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	cpy := make([]accounts.Wallet, len(b.wallets))
+	for i, w := range b.wallets {
+		cpy[i] = w
+	}
+	return cpy
+}
+
+// Subscribe implements accounts.Backend, notifying the imported wallets and
+// the opened and closed ones.
+func (b *Backend) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	return b.feed.Subscribe(sink)
+}
+
+// Import creates an HD wallet of the BIP-39 mnemonic and its optional BIP-39
+// passphrase, encrypting the seed with passphrase. The wallet is returned open
+// with the first account of accounts.DefaultBaseDerivationPath pinned.
+func (b *Backend) Import(mnemonic, bip39Passphrase, passphrase string) (accounts.Wallet, accounts.Account, error) {
+	seed, err := accounts.NewSeedFromMnemonic(mnemonic, bip39Passphrase)
+	if err != nil {
+		return nil, accounts.Account{}, err
+	}
+	crypto, err := keystore.EncryptData(seed, passphrase, b.kdf)
+	if err != nil {
+		return nil, accounts.Account{}, err
+	}
+	if err := os.MkdirAll(b.dir, 0o700); err != nil {
+		return nil, accounts.Account{}, err
+	}
+	id := uuid.NewRandom().String()
+	w := newWallet(b, filepath.Join(b.dir, id+".json"), &walletFile{ID: id, Crypto: crypto})
+	w.seed = seed
+	account, err := w.Derive(accounts.DefaultBaseDerivationPath, true)
+	if err != nil {
+		return nil, accounts.Account{}, err
+	}
+
+	b.mu.Lock()
+	b.wallets = append(b.wallets, w)
+	sort.Slice(b.wallets, func(i, j int) bool { return b.wallets[i].url.Cmp(b.wallets[j].url) < 0 })
+	b.mu.Unlock()
+
+	logger.Info("Imported an HD wallet", "url", w.url, "account", account.Address)
+	b.feed.Send(accounts.WalletEvent{Wallet: w, Kind: accounts.WalletArrived})
+	b.feed.Send(accounts.WalletEvent{Wallet: w, Kind: accounts.WalletOpened})
+	return w, account, nil
+}
+
+// save writes the wallet file atomically.
+func (b *Backend) save(path string, file *walletFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := fmt.Sprintf("%s.%s.tmp", path, uuid.NewRandom())
+	if err := ioutil.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
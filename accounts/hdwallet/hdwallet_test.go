@@ -0,0 +1,147 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package hdwallet
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn"
+	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/accounts/keystore"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMnemonic = strings.Repeat("abandon ", 11) + "about"
+
+// testStateReader is a klaytn.ChainStateReader of the used accounts.
+type testStateReader struct {
+	used map[common.Address]bool
+}
+
+func (r *testStateReader) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return new(big.Int), nil
+}
+
+func (r *testStateReader) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (r *testStateReader) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (r *testStateReader) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	if r.used[account] {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func derivedAddress(t *testing.T, index uint32) common.Address {
+	seed, err := accounts.NewSeedFromMnemonic(testMnemonic, "")
+	require.NoError(t, err)
+	path := append(accounts.DerivationPath{}, accounts.DefaultBaseDerivationPath...)
+	path[len(path)-1] = index
+	key, err := accounts.DeriveKey(seed, path)
+	require.NoError(t, err)
+	return crypto.PubkeyToAddress(key.PublicKey)
+}
+
+func TestBackend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klaytn-hdwallet-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	kdf := keystore.ScryptKDF(keystore.LightScryptN, keystore.LightScryptP)
+
+	b, err := NewBackend(dir, kdf)
+	require.NoError(t, err)
+	w, account, err := b.Import(testMnemonic, "", "password")
+	require.NoError(t, err)
+	assert.Equal(t, derivedAddress(t, 0), account.Address)
+	assert.Equal(t, []accounts.Wallet{w}, b.Wallets())
+
+	// The pinned accounts are listed while the wallet is locked.
+	b, err = NewBackend(dir, kdf)
+	require.NoError(t, err)
+	require.Len(t, b.Wallets(), 1)
+	w = b.Wallets()[0]
+	assert.Equal(t, []accounts.Account{account}, w.Accounts())
+	status, _ := w.Status()
+	assert.Equal(t, "Locked", status)
+
+	hash := crypto.Keccak256([]byte("message"))
+	_, err = w.SignHash(account, hash)
+	assert.Equal(t, errLocked, err)
+	sig, err := w.SignHashWithPassphrase(account, "password", hash)
+	require.NoError(t, err)
+	pub, err := crypto.SigToPub(hash, sig)
+	require.NoError(t, err)
+	assert.Equal(t, account.Address, crypto.PubkeyToAddress(*pub))
+
+	_, err = w.Derive(accounts.DefaultBaseDerivationPath, false)
+	assert.Equal(t, errLocked, err)
+	assert.Equal(t, keystore.ErrDecrypt, w.Open("wrong"))
+	assert.IsType(t, &accounts.AuthNeededError{}, w.Open(""))
+	require.NoError(t, w.Open("password"))
+
+	// The derived accounts are kept only if pinned.
+	path, _ := accounts.ParseDerivationPath("m/44'/8217'/0'/0/1")
+	derived, err := w.Derive(path, false)
+	require.NoError(t, err)
+	assert.Equal(t, derivedAddress(t, 1), derived.Address)
+	path[len(path)-1] = 2
+	pinned, err := w.Derive(path, true)
+	require.NoError(t, err)
+	assert.Len(t, w.Accounts(), 3)
+	_, err = w.SignHash(derived, hash)
+	assert.NoError(t, err)
+
+	require.NoError(t, w.Close())
+	assert.Equal(t, []accounts.Account{account, pinned}, w.Accounts())
+	b, err = NewBackend(dir, kdf)
+	require.NoError(t, err)
+	assert.Equal(t, []accounts.Account{account, pinned}, b.Wallets()[0].Accounts())
+}
+
+func TestWallet_SelfDerive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klaytn-hdwallet-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	b, err := NewBackend(dir, keystore.ScryptKDF(keystore.LightScryptN, keystore.LightScryptP))
+	require.NoError(t, err)
+	w, _, err := b.Import(testMnemonic, "", "password")
+	require.NoError(t, err)
+
+	// The used accounts and the first unused one are discovered.
+	reader := &testStateReader{used: map[common.Address]bool{derivedAddress(t, 0): true, derivedAddress(t, 1): true}}
+	w.(*wallet).SelfDerive(accounts.DefaultBaseDerivationPath, struct {
+		*testStateReader
+		klaytn.ChainReader // Never called
+	}{reader, nil})
+	assert.Eventually(t, func() bool { return len(w.Accounts()) == 3 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, derivedAddress(t, 2), w.Accounts()[2].Address)
+}
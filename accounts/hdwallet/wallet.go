@@ -0,0 +1,324 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package hdwallet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/klaytn/klaytn"
+	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/accounts/keystore"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+)
+
+// maxSelfDerive is the maximum number of the accounts discovered by the
+// self-derivation.
+const maxSelfDerive = 100
+
+// selfDeriveTimeout is the timeout of a query of the self-derivation.
+const selfDeriveTimeout = 5 * time.Second
+
+// wallet is the accounts.Wallet of an HD wallet file.
+type wallet struct {
+	backend *Backend
+	url     accounts.URL
+	path    string
+	file    *walletFile
+
+	seed     []byte // Seed of the mnemonic, nil while the wallet is locked
+	accounts []accounts.Account
+	paths    map[common.Address]accounts.DerivationPath
+
+	selfDeriveBase  accounts.DerivationPath
+	selfDeriveChain klaytn.ChainStateReader
+
+	mu sync.RWMutex
+}
+
+func newWallet(b *Backend, path string, file *walletFile) *wallet {
+	return &wallet{
+		backend: b,
+		url:     accounts.URL{Scheme: URLScheme, Path: path},
+		path:    path,
+		file:    file,
+		paths:   make(map[common.Address]accounts.DerivationPath),
+	}
+}
+
+// addAccount adds the account of the path if not added yet. It returns false
+// if the account exists.
+func (w *wallet) addAccount(address common.Address, path accounts.DerivationPath) (accounts.Account, bool) {
+	account := accounts.Account{Address: address, URL: w.url}
+	if _, ok := w.paths[address]; ok {
+		return account, false
+	}
+	w.paths[address] = path
+	w.accounts = append(w.accounts, account)
+	return account, true
+}
+
+func (w *wallet) URL() accounts.URL { return w.url }
+
+// Status implements accounts.Wallet, returning whether the seed is decrypted.
+func (w *wallet) Status() (string, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.seed == nil {
+		return "Locked", nil
+	}
+	return "Unlocked", nil
+}
+
+// Open implements accounts.Wallet, decrypting the seed with the passphrase.
+// The wallets opened without the passphrase, e.g. by the node on startup,
+// return an accounts.AuthNeededError.
+func (w *wallet) Open(passphrase string) error {
+	w.mu.Lock()
+	if w.seed != nil {
+		w.mu.Unlock()
+		return nil
+	}
+	seed, err := keystore.DecryptData(w.file.Crypto, passphrase)
+	if err != nil {
+		w.mu.Unlock()
+		if passphrase == "" {
+			return accounts.NewAuthNeededError("passphrase of the HD wallet")
+		}
+		return err
+	}
+	w.seed = seed
+	base, chain := w.selfDeriveBase, w.selfDeriveChain
+	w.mu.Unlock()
+
+	w.backend.feed.Send(accounts.WalletEvent{Wallet: w, Kind: accounts.WalletOpened})
+	if chain != nil {
+		go w.selfDerive(base, chain)
+	}
+	return nil
+}
+
+// Close implements accounts.Wallet, forgetting the seed and the accounts which
+// are not pinned.
+func (w *wallet) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for i := range w.seed {
+		w.seed[i] = 0
+	}
+	w.seed = nil
+	w.accounts, w.paths = nil, make(map[common.Address]accounts.DerivationPath)
+	for _, pinned := range w.file.Accounts {
+		if path, err := accounts.ParseDerivationPath(pinned.Path); err == nil {
+			w.addAccount(pinned.Address, path)
+		}
+	}
+	return nil
+}
+
+// Accounts implements accounts.Wallet, returning the pinned accounts and the
+// derived ones since the wallet is opened.
+func (w *wallet) Accounts() []accounts.Account {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	cpy := make([]accounts.Account, len(w.accounts))
+	copy(cpy, w.accounts)
+	return cpy
+}
+
+func (w *wallet) Contains(account accounts.Account) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	_, ok := w.paths[account.Address]
+	return ok && (account.URL == (accounts.URL{}) || account.URL == w.url)
+}
+
+// Derive implements accounts.Wallet, deriving the account of the path and
+// pinning it in the wallet file if requested.
+func (w *wallet) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.seed == nil {
+		return accounts.Account{}, errLocked
+	}
+	key, err := accounts.DeriveKey(w.seed, path)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	account, _ := w.addAccount(crypto.PubkeyToAddress(key.PublicKey), path)
+	if !pin {
+		return account, nil
+	}
+	for _, pinned := range w.file.Accounts {
+		if pinned.Address == account.Address {
+			return account, nil
+		}
+	}
+	file := *w.file
+	file.Accounts = append(append([]pinnedAccount{}, file.Accounts...), pinnedAccount{Address: account.Address, Path: path.String()})
+	if err := w.backend.save(w.path, &file); err != nil {
+		return accounts.Account{}, err
+	}
+	w.file = &file
+	return account, nil
+}
+
+// SelfDerive implements accounts.Wallet, discovering the used accounts from the
+// base path whenever the wallet is opened. The chain should be a
+// klaytn.ChainStateReader to look up the nonces and the balances.
+func (w *wallet) SelfDerive(base accounts.DerivationPath, chain klaytn.ChainReader) {
+	reader, _ := chain.(klaytn.ChainStateReader)
+
+	w.mu.Lock()
+	w.selfDeriveBase = append(accounts.DerivationPath{}, base...)
+	w.selfDeriveChain = reader
+	open := w.seed != nil
+	w.mu.Unlock()
+
+	if open && reader != nil && len(base) > 0 {
+		go w.selfDerive(base, reader)
+	}
+}
+
+// selfDerive adds the accounts of the paths incrementing the last component of
+// the base path, until the first account without any nonce or balance.
+func (w *wallet) selfDerive(base accounts.DerivationPath, reader klaytn.ChainStateReader) {
+	if len(base) == 0 {
+		return
+	}
+	path := append(accounts.DerivationPath{}, base...)
+	for i := 0; i < maxSelfDerive; i++ {
+		w.mu.Lock()
+		if w.seed == nil {
+			w.mu.Unlock()
+			return
+		}
+		key, err := accounts.DeriveKey(w.seed, path)
+		if err != nil {
+			w.mu.Unlock()
+			logger.Warn("Failed to self-derive an account", "url", w.url, "path", path, "err", err)
+			return
+		}
+		address := crypto.PubkeyToAddress(key.PublicKey)
+		account, added := w.addAccount(address, append(accounts.DerivationPath{}, path...))
+		w.mu.Unlock()
+		if added {
+			logger.Info("Self-derived an account", "url", w.url, "path", path, "address", account.Address)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), selfDeriveTimeout)
+		nonce, err := reader.NonceAt(ctx, address, nil)
+		if err != nil {
+			cancel()
+			logger.Warn("Failed to self-derive an account", "url", w.url, "path", path, "err", err)
+			return
+		}
+		balance, err := reader.BalanceAt(ctx, address, nil)
+		cancel()
+		if err != nil {
+			logger.Warn("Failed to self-derive an account", "url", w.url, "path", path, "err", err)
+			return
+		}
+		if nonce == 0 && balance.Sign() == 0 {
+			return
+		}
+		path[len(path)-1]++
+	}
+}
+
+// key derives the private key of the account with the seed, decrypting it
+// with the passphrase if the wallet is locked and the passphrase is given.
+func (w *wallet) key(account accounts.Account, passphrase *string) (*ecdsa.PrivateKey, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	path, ok := w.paths[account.Address]
+	if !ok || (account.URL != (accounts.URL{}) && account.URL != w.url) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	seed := w.seed
+	if seed == nil {
+		if passphrase == nil {
+			return nil, errLocked
+		}
+		var err error
+		if seed, err = keystore.DecryptData(w.file.Crypto, *passphrase); err != nil {
+			return nil, err
+		}
+	}
+	return accounts.DeriveKey(seed, path)
+}
+
+// SignHash implements accounts.Wallet, signing the hash with the derived key
+// of the account.
+func (w *wallet) SignHash(account accounts.Account, hash []byte) ([]byte, error) {
+	key, err := w.key(account, nil)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Sign(hash, key)
+}
+
+func (w *wallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return w.signTx(account, nil, tx, chainID, false)
+}
+
+func (w *wallet) SignTxAsFeePayer(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return w.signTx(account, nil, tx, chainID, true)
+}
+
+// SignHashWithPassphrase implements accounts.Wallet, decrypting the seed with
+// the passphrase if the wallet is locked.
+func (w *wallet) SignHashWithPassphrase(account accounts.Account, passphrase string, hash []byte) ([]byte, error) {
+	key, err := w.key(account, &passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Sign(hash, key)
+}
+
+func (w *wallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return w.signTx(account, &passphrase, tx, chainID, false)
+}
+
+func (w *wallet) SignTxAsFeePayerWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return w.signTx(account, &passphrase, tx, chainID, true)
+}
+
+func (w *wallet) signTx(account accounts.Account, passphrase *string, tx *types.Transaction, chainID *big.Int, feePayer bool) (*types.Transaction, error) {
+	if chainID == nil {
+		return nil, keystore.ErrChainIdNil
+	}
+	key, err := w.key(account, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if feePayer {
+		return types.SignTxAsFeePayer(tx, types.LatestSignerForChainID(chainID), key)
+	}
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), key)
+}
@@ -0,0 +1,151 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// KDFScrypt is the scrypt KDF, which is the default of the keystore files.
+	KDFScrypt = keyHeaderKDF
+
+	// KDFArgon2id is the Argon2id KDF. It is not a part of the Web3 Secret
+	// Storage definition, so the files using it are marked with argon2idCompat.
+	KDFArgon2id = "argon2id"
+
+	// DefaultArgon2Time, DefaultArgon2Memory and DefaultArgon2Threads are the
+	// parameters of Argon2id recommended by RFC 9106 for the memory-constrained
+	// environments, using 64MB memory.
+	DefaultArgon2Time    = 3
+	DefaultArgon2Memory  = 64 * 1024 // in KiB
+	DefaultArgon2Threads = 4
+
+	// argon2idCompat is the "compat" of the keystore files with the keys
+	// encrypted with Argon2id, which cannot be decrypted by the readers only
+	// supporting the standard KDFs.
+	argon2idCompat = "argon2id"
+)
+
+var errInvalidKDFConfig = errors.New("invalid KDF config")
+
+// KDFConfig is the KDF and its parameters of the keys encrypted by the keystore.
+// The zero values of the parameters are replaced with the defaults.
+type KDFConfig struct {
+	KDF string `toml:",omitempty"` // KDFScrypt or KDFArgon2id, KDFScrypt if empty
+
+	ScryptN int `toml:",omitempty"`
+	ScryptR int `toml:",omitempty"`
+	ScryptP int `toml:",omitempty"`
+
+	Argon2Time    uint32 `toml:",omitempty"`
+	Argon2Memory  uint32 `toml:",omitempty"` // in KiB
+	Argon2Threads uint8  `toml:",omitempty"`
+}
+
+// ScryptKDF returns the config of scrypt with N and P.
+func ScryptKDF(scryptN, scryptP int) KDFConfig {
+	return KDFConfig{KDF: KDFScrypt, ScryptN: scryptN, ScryptR: scryptR, ScryptP: scryptP}
+}
+
+// withDefaults returns the config whose zero values are replaced with the defaults.
+func (c KDFConfig) withDefaults() KDFConfig {
+	if c.KDF == "" {
+		c.KDF = KDFScrypt
+	}
+	if c.ScryptN == 0 {
+		c.ScryptN = StandardScryptN
+	}
+	if c.ScryptR == 0 {
+		c.ScryptR = scryptR
+	}
+	if c.ScryptP == 0 {
+		c.ScryptP = StandardScryptP
+	}
+	if c.Argon2Time == 0 {
+		c.Argon2Time = DefaultArgon2Time
+	}
+	if c.Argon2Memory == 0 {
+		c.Argon2Memory = DefaultArgon2Memory
+	}
+	if c.Argon2Threads == 0 {
+		c.Argon2Threads = DefaultArgon2Threads
+	}
+	return c
+}
+
+// Validate returns an error if the parameters cannot be used.
+func (c KDFConfig) Validate() error {
+	c = c.withDefaults()
+	switch c.KDF {
+	case KDFScrypt:
+		if c.ScryptN <= 1 || c.ScryptN&(c.ScryptN-1) != 0 {
+			return fmt.Errorf("%w: scrypt N must be a power of two greater than 1", errInvalidKDFConfig)
+		}
+		if uint64(c.ScryptR)*uint64(c.ScryptP) >= 1<<30 {
+			return fmt.Errorf("%w: scrypt r*p must be less than 2^30", errInvalidKDFConfig)
+		}
+	case KDFArgon2id:
+		if c.Argon2Memory < 8*uint32(c.Argon2Threads) {
+			return fmt.Errorf("%w: Argon2id memory must be at least 8 KiB per thread", errInvalidKDFConfig)
+		}
+	default:
+		return fmt.Errorf("%w: unknown KDF %q", errInvalidKDFConfig, c.KDF)
+	}
+	return nil
+}
+
+// deriveKey derives the key from the passphrase and the salt, and returns it
+// with the KDF parameters to be written in the keystore file.
+func (c KDFConfig) deriveKey(auth, salt []byte) ([]byte, map[string]interface{}, error) {
+	c = c.withDefaults()
+	if err := c.Validate(); err != nil {
+		return nil, nil, err
+	}
+	params := make(map[string]interface{}, 5)
+	params["dklen"] = scryptDKLen
+	params["salt"] = hex.EncodeToString(salt)
+
+	if c.KDF == KDFArgon2id {
+		params["t"] = c.Argon2Time
+		params["m"] = c.Argon2Memory
+		params["p"] = c.Argon2Threads
+		return argon2.IDKey(auth, salt, c.Argon2Time, c.Argon2Memory, c.Argon2Threads, scryptDKLen), params, nil
+	}
+	derivedKey, err := scrypt.Key(auth, salt, c.ScryptN, c.ScryptR, c.ScryptP, scryptDKLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	params["n"] = c.ScryptN
+	params["r"] = c.ScryptR
+	params["p"] = c.ScryptP
+	return derivedKey, params, nil
+}
+
+// compat returns the "compat" of the keystore files with the keys encrypted
+// with the config.
+func (c KDFConfig) compat() string {
+	if c.withDefaults().KDF == KDFArgon2id {
+		return argon2idCompat
+	}
+	return ""
+}
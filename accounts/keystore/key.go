@@ -93,6 +93,7 @@ type encryptedKeyJSONV3 struct {
 	Crypto  cryptoJSON `json:"crypto"`
 	Id      string     `json:"id"`
 	Version int        `json:"version"`
+	Compat  string     `json:"compat,omitempty"` // Set if the key needs an extension, e.g. Argon2id
 }
 
 type encryptedKeyJSONV1 struct {
@@ -185,6 +186,12 @@ func newKeyFromECDSAWithAddress(privateKeyECDSA *ecdsa.PrivateKey, address commo
 	return key
 }
 
+// NewKeyFromECDSA returns a key of the private key, whose address is derived
+// from it.
+func NewKeyFromECDSA(privateKeyECDSA *ecdsa.PrivateKey) Key {
+	return newKeyFromECDSA(privateKeyECDSA)
+}
+
 func newKeyFromECDSA(privateKeyECDSA *ecdsa.PrivateKey) Key {
 	id := uuid.NewRandom()
 	key := &KeyV4{
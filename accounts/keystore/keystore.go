@@ -35,6 +35,7 @@ import (
 
 	"github.com/klaytn/klaytn/accounts"
 	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/types/accountkey"
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/crypto"
 	"github.com/klaytn/klaytn/event"
@@ -45,6 +46,11 @@ var (
 	ErrNoMatch    = errors.New("no key for given address or file")
 	ErrDecrypt    = errors.New("could not decrypt key with given passphrase")
 	ErrChainIdNil = errors.New("Chain ID should not be nil")
+
+	ErrMultipleKeys = errors.New("account has multiple keys, which the keystore v3 format cannot store")
+
+	ErrRoleMismatch = errors.New("the role does not sign the transaction")
+	ErrNoRoleKey    = errors.New("no key for the role")
 )
 
 // KeyStoreType is the reflect type of a keystore backend.
@@ -78,8 +84,14 @@ type unlocked struct {
 
 // NewKeyStore creates a keystore for the given directory.
 func NewKeyStore(keydir string, scryptN, scryptP int) *KeyStore {
+	return NewKeyStoreWithKDF(keydir, ScryptKDF(scryptN, scryptP))
+}
+
+// NewKeyStoreWithKDF creates a keystore for the given directory, encrypting the
+// keys with the KDF config.
+func NewKeyStoreWithKDF(keydir string, kdf KDFConfig) *KeyStore {
 	keydir, _ = filepath.Abs(keydir)
-	ks := &KeyStore{storage: &keyStorePassphrase{keydir, scryptN, scryptP, false}}
+	ks := &KeyStore{storage: &keyStorePassphrase{keydir, kdf, false}}
 	ks.init(keydir)
 	return ks
 }
@@ -348,6 +360,81 @@ func (ks *KeyStore) SignTxAsFeePayerWithPassphrase(a accounts.Account, passphras
 	return types.SignTxAsFeePayer(tx, types.LatestSignerForChainID(chainID), key.GetPrivateKey())
 }
 
+// SignTxWithRole signs the transaction with the keys of the given role of the
+// requested account, as the fee payer for RoleFeePayer. It fails if the
+// transaction is not signed with the role, e.g. an account update with the
+// RoleTransaction keys.
+func (ks *KeyStore) SignTxWithRole(a accounts.Account, role accountkey.RoleType, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	// Look up the key to sign with and abort if it cannot be found
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	unlockedKey, found := ks.unlocked[a.Address]
+	if !found {
+		return nil, ErrLocked
+	}
+	return signTxWithRole(unlockedKey.Key, role, tx, chainID)
+}
+
+// SignTxWithRoleAndPassphrase signs the transaction with the keys of the given
+// role if the keys of the given address can be decrypted with the passphrase.
+func (ks *KeyStore) SignTxWithRoleAndPassphrase(a accounts.Account, passphrase string, role accountkey.RoleType, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	_, key, err := ks.getDecryptedKey(a, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer key.ResetPrivateKey()
+	return signTxWithRole(key, role, tx, chainID)
+}
+
+func signTxWithRole(key Key, role accountkey.RoleType, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if chainID == nil {
+		return nil, ErrChainIdNil
+	}
+	if err := checkRole(tx, role); err != nil {
+		return nil, err
+	}
+	prv, err := roleKeys(key, role)
+	if err != nil {
+		return nil, err
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	if role == accountkey.RoleFeePayer {
+		return types.SignTxAsFeePayerWithKeys(tx, signer, prv)
+	}
+	return types.SignTxWithKeys(tx, signer, prv)
+}
+
+// checkRole returns an error if the transaction is not signed with the role.
+func checkRole(tx *types.Transaction, role accountkey.RoleType) error {
+	if role == accountkey.RoleFeePayer {
+		if !tx.IsFeeDelegatedTransaction() {
+			return fmt.Errorf("%w: only the fee-delegated transactions have a fee payer", ErrRoleMismatch)
+		}
+		return nil
+	}
+	if expected := tx.GetRoleTypeForValidation(); role != expected {
+		return fmt.Errorf("%w: the transaction is signed with the %s key, not the %s key", ErrRoleMismatch, expected, role)
+	}
+	return nil
+}
+
+// roleKeys returns the private keys of the role. As for the role-based account
+// keys, the RoleTransaction keys are used for the roles without keys.
+func roleKeys(key Key, role accountkey.RoleType) ([]*ecdsa.PrivateKey, error) {
+	if role < accountkey.RoleTransaction || role >= accountkey.RoleLast {
+		return nil, fmt.Errorf("%w: %s", ErrNoRoleKey, role)
+	}
+	prv := key.GetPrivateKeysWithRole(int(role))
+	if len(prv) == 0 {
+		prv = key.GetPrivateKeysWithRole(int(accountkey.RoleTransaction))
+	}
+	if len(prv) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoRoleKey, role)
+	}
+	return prv, nil
+}
+
 // Unlock unlocks the given account indefinitely.
 func (ks *KeyStore) Unlock(a accounts.Account, passphrase string) error {
 	return ks.TimedUnlock(a, passphrase, 0)
@@ -467,13 +554,28 @@ func (ks *KeyStore) Export(a accounts.Account, passphrase, newPassphrase string)
 	if err != nil {
 		return nil, err
 	}
-	var N, P int
+	kdf := ScryptKDF(StandardScryptN, StandardScryptP)
 	if store, ok := ks.storage.(*keyStorePassphrase); ok {
-		N, P = store.scryptN, store.scryptP
-	} else {
-		N, P = StandardScryptN, StandardScryptP
+		kdf = store.kdf
+	}
+	return EncryptKeyWithKDF(key, newPassphrase, kdf)
+}
+
+// ExportV3 exports as a JSON key of the keystore v3 format, encrypted with
+// newPassphrase. The accounts with multiple keys cannot be exported in v3.
+func (ks *KeyStore) ExportV3(a accounts.Account, passphrase, newPassphrase string) (keyJSON []byte, err error) {
+	_, key, err := ks.getDecryptedKey(a, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if pks := key.GetPrivateKeys(); len(pks) != 1 || len(pks[0]) != 1 {
+		return nil, ErrMultipleKeys
+	}
+	kdf := ScryptKDF(StandardScryptN, StandardScryptP)
+	if store, ok := ks.storage.(*keyStorePassphrase); ok {
+		kdf = store.kdf
 	}
-	return EncryptKey(key, newPassphrase, N, P)
+	return EncryptKeyV3WithKDF(key, newPassphrase, kdf)
 }
 
 // Import stores the given encrypted JSON key into the key directory.
@@ -538,6 +640,26 @@ func (ks *KeyStore) ImportECDSA(priv *ecdsa.PrivateKey, passphrase string) (acco
 	return ks.importKey(key, passphrase)
 }
 
+// ImportRoleBasedKey stores the keys of the roles of the given address into the
+// key directory, encrypting them with the passphrase. The keys are indexed by
+// the role, e.g. keys[accountkey.RoleFeePayer] are the keys of the fee payer.
+func (ks *KeyStore) ImportRoleBasedKey(address common.Address, keys [][]*ecdsa.PrivateKey, passphrase string) (accounts.Account, error) {
+	key, err := newRoleBasedKey(address, keys)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	return ks.ImportKey(key, passphrase)
+}
+
+// ImportKey stores the given decrypted key into the key directory, encrypting
+// it with the passphrase.
+func (ks *KeyStore) ImportKey(key Key, passphrase string) (accounts.Account, error) {
+	if ks.cache.hasAddress(key.GetAddress()) {
+		return accounts.Account{}, fmt.Errorf("account already exists")
+	}
+	return ks.importKey(key, passphrase)
+}
+
 func (ks *KeyStore) importKey(key Key, passphrase string) (accounts.Account, error) {
 	a := accounts.Account{Address: key.GetAddress(), URL: accounts.URL{Scheme: KeyStoreScheme, Path: ks.storage.JoinPath(keyFileName(key.GetAddress()))}}
 	if err := ks.storage.StoreKey(a.URL.Path, key, passphrase); err != nil {
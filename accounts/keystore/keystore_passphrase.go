@@ -38,6 +38,7 @@ import (
 	"github.com/klaytn/klaytn/common/math"
 	"github.com/klaytn/klaytn/crypto"
 	"github.com/pborman/uuid"
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/pbkdf2"
 	"golang.org/x/crypto/scrypt"
 )
@@ -67,8 +68,7 @@ const (
 
 type keyStorePassphrase struct {
 	keysDirPath string
-	scryptN     int
-	scryptP     int
+	kdf         KDFConfig
 	// skipKeyFileVerification disables the security-feature which does
 	// reads and decrypts any newly created keyfiles. This should be 'false' in all
 	// cases except tests -- setting this to 'true' is not recommended.
@@ -94,12 +94,18 @@ func (ks keyStorePassphrase) GetKey(addr common.Address, filename, auth string)
 
 // StoreKey generates a key, encrypts with 'auth' and stores in the given directory
 func StoreKey(dir, auth string, scryptN, scryptP int) (common.Address, error) {
-	_, a, err := storeNewKey(&keyStorePassphrase{dir, scryptN, scryptP, false}, rand.Reader, auth)
+	return StoreKeyWithKDF(dir, auth, ScryptKDF(scryptN, scryptP))
+}
+
+// StoreKeyWithKDF generates a key, encrypts with 'auth' using the KDF config and
+// stores in the given directory
+func StoreKeyWithKDF(dir, auth string, kdf KDFConfig) (common.Address, error) {
+	_, a, err := storeNewKey(&keyStorePassphrase{dir, kdf, false}, rand.Reader, auth)
 	return a.Address, err
 }
 
 func (ks keyStorePassphrase) StoreKey(filename string, key Key, auth string) error {
-	keyjson, err := EncryptKey(key, auth, ks.scryptN, ks.scryptP)
+	keyjson, err := EncryptKeyWithKDF(key, auth, ks.kdf)
 	if err != nil {
 		return err
 	}
@@ -132,14 +138,14 @@ func (ks keyStorePassphrase) JoinPath(filename string) string {
 }
 
 // encryptCrypto encrypts a private key to a cryptoJSON object.
-func encryptCrypto(keyBytes []byte, auth string, scryptN, scryptP int) (*cryptoJSON, error) {
+func encryptCrypto(keyBytes []byte, auth string, kdf KDFConfig) (*cryptoJSON, error) {
 	authArray := []byte(auth)
 
 	salt := make([]byte, 32)
 	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
 		panic("reading from crypto/rand failed: " + err.Error())
 	}
-	derivedKey, err := scrypt.Key(authArray, salt, scryptN, scryptR, scryptP, scryptDKLen)
+	derivedKey, kdfParamsJSON, err := kdf.deriveKey(authArray, salt)
 	if err != nil {
 		return nil, err
 	}
@@ -155,13 +161,6 @@ func encryptCrypto(keyBytes []byte, auth string, scryptN, scryptP int) (*cryptoJ
 	}
 	mac := crypto.Keccak256(derivedKey[16:32], cipherText)
 
-	scryptParamsJSON := make(map[string]interface{}, 5)
-	scryptParamsJSON["n"] = scryptN
-	scryptParamsJSON["r"] = scryptR
-	scryptParamsJSON["p"] = scryptP
-	scryptParamsJSON["dklen"] = scryptDKLen
-	scryptParamsJSON["salt"] = hex.EncodeToString(salt)
-
 	cipherParamsJSON := cipherparamsJSON{
 		IV: hex.EncodeToString(iv),
 	}
@@ -170,8 +169,8 @@ func encryptCrypto(keyBytes []byte, auth string, scryptN, scryptP int) (*cryptoJ
 		Cipher:       "aes-128-ctr",
 		CipherText:   hex.EncodeToString(cipherText),
 		CipherParams: cipherParamsJSON,
-		KDF:          keyHeaderKDF,
-		KDFParams:    scryptParamsJSON,
+		KDF:          kdf.withDefaults().KDF,
+		KDFParams:    kdfParamsJSON,
 		MAC:          hex.EncodeToString(mac),
 	}, nil
 }
@@ -179,13 +178,19 @@ func encryptCrypto(keyBytes []byte, auth string, scryptN, scryptP int) (*cryptoJ
 // EncryptKey encrypts a key using the specified scrypt parameters into a json
 // blob that can be decrypted later on. It uses the keystore v4 format.
 func EncryptKey(key Key, auth string, scryptN, scryptP int) ([]byte, error) {
+	return EncryptKeyWithKDF(key, auth, ScryptKDF(scryptN, scryptP))
+}
+
+// EncryptKeyWithKDF encrypts a key using the KDF config into a json blob that
+// can be decrypted later on. It uses the keystore v4 format.
+func EncryptKeyWithKDF(key Key, auth string, kdf KDFConfig) ([]byte, error) {
 	pks := key.GetPrivateKeys()
 	crypto := make([][]cryptoJSON, len(pks))
 	for i, keys := range pks {
 		crypto[i] = make([]cryptoJSON, len(keys))
 		for j, k := range keys {
 			keyBytes := math.PaddedBigBytes(k.D, 32)
-			c, err := encryptCrypto(keyBytes, auth, scryptN, scryptP)
+			c, err := encryptCrypto(keyBytes, auth, kdf)
 			if err != nil {
 				return nil, err
 			}
@@ -193,10 +198,11 @@ func EncryptKey(key Key, auth string, scryptN, scryptP int) ([]byte, error) {
 		}
 	}
 	encryptedKeyJSONV4 := encryptedKeyJSONV4{
-		hex.EncodeToString(key.GetAddress().Bytes()),
-		crypto,
-		key.GetId().String(),
-		4,
+		Address: hex.EncodeToString(key.GetAddress().Bytes()),
+		Keyring: crypto,
+		Id:      key.GetId().String(),
+		Version: 4,
+		Compat:  kdf.compat(),
 	}
 	return json.Marshal(encryptedKeyJSONV4)
 }
@@ -204,55 +210,44 @@ func EncryptKey(key Key, auth string, scryptN, scryptP int) ([]byte, error) {
 // EncryptKeyV3 encrypts a key using the specified scrypt parameters into a json
 // blob that can be decrypted later on. It uses the keystore v3 format.
 func EncryptKeyV3(key Key, auth string, scryptN, scryptP int) ([]byte, error) {
-	authArray := []byte(auth)
+	return EncryptKeyV3WithKDF(key, auth, ScryptKDF(scryptN, scryptP))
+}
 
-	salt := make([]byte, 32)
-	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
-		panic("reading from crypto/rand failed: " + err.Error())
-	}
-	derivedKey, err := scrypt.Key(authArray, salt, scryptN, scryptR, scryptP, scryptDKLen)
+// EncryptKeyV3WithKDF encrypts a key using the KDF config into a json blob that
+// can be decrypted later on. It uses the keystore v3 format.
+func EncryptKeyV3WithKDF(key Key, auth string, kdf KDFConfig) ([]byte, error) {
+	keyBytes := math.PaddedBigBytes(key.GetPrivateKey().D, 32)
+	cryptoStruct, err := encryptCrypto(keyBytes, auth, kdf)
 	if err != nil {
 		return nil, err
 	}
-	encryptKey := derivedKey[:16]
-	keyBytes := math.PaddedBigBytes(key.GetPrivateKey().D, 32)
-
-	iv := make([]byte, aes.BlockSize) // 16
-	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
-		panic("reading from crypto/rand failed: " + err.Error())
+	encryptedKeyJSONV3 := encryptedKeyJSONV3{
+		Address: hex.EncodeToString(key.GetAddress().Bytes()),
+		Crypto:  *cryptoStruct,
+		Id:      key.GetId().String(),
+		Version: 3,
+		Compat:  kdf.compat(),
 	}
-	cipherText, err := aesCTRXOR(encryptKey, keyBytes, iv)
+	return json.Marshal(encryptedKeyJSONV3)
+}
+
+// EncryptData encrypts the data, e.g. the seed of an HD wallet, into the crypto
+// object of the keystore format.
+func EncryptData(data []byte, auth string, kdf KDFConfig) (json.RawMessage, error) {
+	cryptoStruct, err := encryptCrypto(data, auth, kdf)
 	if err != nil {
 		return nil, err
 	}
-	mac := crypto.Keccak256(derivedKey[16:32], cipherText)
-
-	scryptParamsJSON := make(map[string]interface{}, 5)
-	scryptParamsJSON["n"] = scryptN
-	scryptParamsJSON["r"] = scryptR
-	scryptParamsJSON["p"] = scryptP
-	scryptParamsJSON["dklen"] = scryptDKLen
-	scryptParamsJSON["salt"] = hex.EncodeToString(salt)
-
-	cipherParamsJSON := cipherparamsJSON{
-		IV: hex.EncodeToString(iv),
-	}
+	return json.Marshal(cryptoStruct)
+}
 
-	cryptoStruct := cryptoJSON{
-		Cipher:       "aes-128-ctr",
-		CipherText:   hex.EncodeToString(cipherText),
-		CipherParams: cipherParamsJSON,
-		KDF:          keyHeaderKDF,
-		KDFParams:    scryptParamsJSON,
-		MAC:          hex.EncodeToString(mac),
-	}
-	encryptedKeyJSONV3 := encryptedKeyJSONV3{
-		hex.EncodeToString(key.GetAddress().Bytes()),
-		cryptoStruct,
-		key.GetId().String(),
-		3,
+// DecryptData decrypts the crypto object encrypted by EncryptData.
+func DecryptData(cryptoObject json.RawMessage, auth string) ([]byte, error) {
+	var cryptoStruct cryptoJSON
+	if err := json.Unmarshal(cryptoObject, &cryptoStruct); err != nil {
+		return nil, err
 	}
-	return json.Marshal(encryptedKeyJSONV3)
+	return decryptKey(cryptoStruct, auth)
 }
 
 // DecryptKey decrypts a key from a json blob, returning the private key itself.
@@ -263,6 +258,10 @@ func DecryptKey(keyjson []byte, auth string) (Key, error) {
 	if err := json.Unmarshal(keyjson, &m); err != nil {
 		return nil, err
 	}
+	// The files needing the extensions unknown to this reader are rejected
+	if compat, ok := m["compat"]; ok && compat != argon2idCompat {
+		return nil, fmt.Errorf("unsupported keystore compat: %v", compat)
+	}
 	// Depending on the version try to parse one way or another
 	var (
 		keyBytes [][][]byte
@@ -472,6 +471,14 @@ func getKDFKey(cryptoJSON cryptoJSON, auth string) ([]byte, error) {
 		}
 		key := pbkdf2.Key(authArray, salt, c, dkLen, sha256.New)
 		return key, nil
+	} else if cryptoJSON.KDF == KDFArgon2id {
+		t := ensureInt(cryptoJSON.KDFParams["t"])
+		m := ensureInt(cryptoJSON.KDFParams["m"])
+		p := ensureInt(cryptoJSON.KDFParams["p"])
+		if t <= 0 || m <= 0 || p <= 0 || p > 255 {
+			return nil, fmt.Errorf("Invalid Argon2id parameters: t=%d, m=%d, p=%d", t, m, p)
+		}
+		return argon2.IDKey(authArray, salt, uint32(t), uint32(m), uint8(p), uint32(dkLen)), nil
 	}
 
 	return nil, fmt.Errorf("Unsupported KDF: %s", cryptoJSON.KDF)
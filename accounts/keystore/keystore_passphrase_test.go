@@ -22,6 +22,7 @@ package keystore
 
 import (
 	"crypto/ecdsa"
+	"encoding/json"
 	"io/ioutil"
 	"testing"
 
@@ -807,3 +808,47 @@ func TestKeyDecryptV3(t *testing.T) {
 
 	require.Equal(t, key, k.GetPrivateKeys()[0][0])
 }
+
+// Tests encoding and decoding of the keys with the configured KDFs.
+func TestEncryptDecryptWithKDF(t *testing.T) {
+	pk, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	key := &KeyV4{
+		Id:          uuid.NewRandom(),
+		Address:     crypto.PubkeyToAddress(pk.PublicKey),
+		PrivateKeys: [][]*ecdsa.PrivateKey{{pk}},
+	}
+
+	kdfs := []struct {
+		kdf    KDFConfig
+		compat string
+	}{
+		{KDFConfig{ScryptN: 16, ScryptR: 4, ScryptP: 2}, ""},
+		{KDFConfig{KDF: KDFArgon2id, Argon2Time: 1, Argon2Memory: 64, Argon2Threads: 2}, argon2idCompat},
+	}
+	for _, tc := range kdfs {
+		for _, encrypt := range []func(Key, string, KDFConfig) ([]byte, error){EncryptKeyWithKDF, EncryptKeyV3WithKDF} {
+			keyjson, err := encrypt(key, "password", tc.kdf)
+			require.NoError(t, err)
+
+			var header struct {
+				Compat string `json:"compat"`
+			}
+			require.NoError(t, json.Unmarshal(keyjson, &header))
+			require.Equal(t, tc.compat, header.Compat)
+
+			_, err = DecryptKey(keyjson, "bad")
+			require.Equal(t, ErrDecrypt, err)
+
+			k, err := DecryptKey(keyjson, "password")
+			require.NoError(t, err)
+			require.Equal(t, key.GetPrivateKey(), k.GetPrivateKey())
+		}
+	}
+
+	_, err = EncryptKeyWithKDF(key, "password", KDFConfig{ScryptN: 3})
+	require.ErrorIs(t, err, errInvalidKDFConfig)
+	_, err = EncryptKeyWithKDF(key, "password", KDFConfig{KDF: "pbkdf2"})
+	require.ErrorIs(t, err, errInvalidKDFConfig)
+}
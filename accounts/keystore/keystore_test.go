@@ -22,6 +22,7 @@ package keystore
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"io/ioutil"
 	"math/big"
 	"math/rand"
@@ -33,6 +34,7 @@ import (
 	"time"
 
 	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/types/accountkey"
 	"github.com/klaytn/klaytn/crypto"
 	"github.com/klaytn/klaytn/params"
 	"github.com/stretchr/testify/assert"
@@ -504,3 +506,56 @@ func TestKeyStore_SignTxAsFeePayer(t *testing.T) {
 	// Two signing functions should return the same value
 	assert.Equal(t, sig2, sig1)
 }
+
+// TestKeyStore_SignTxWithRole tests the signing with the keys of a role of a role-based key.
+func TestKeyStore_SignTxWithRole(t *testing.T) {
+	chainID := big.NewInt(1)
+	signer := types.LatestSignerForChainID(chainID)
+	_, _, tx := testTx()
+	from, _ := tx.From()
+
+	dir, ks := tmpKeyStore(t, true)
+	defer os.RemoveAll(dir)
+
+	keys := make([][]*ecdsa.PrivateKey, 3)
+	for role, n := range []int{2, 1, 1} {
+		for i := 0; i < n; i++ {
+			key, _ := crypto.GenerateKey()
+			keys[role] = append(keys[role], key)
+		}
+	}
+	_, err := ks.ImportRoleBasedKey(from, [][]*ecdsa.PrivateKey{keys[0], {}}, "")
+	assert.Error(t, err)
+	acc, err := ks.ImportRoleBasedKey(from, keys, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ks.SignTxWithRole(acc, accountkey.RoleTransaction, tx, chainID)
+	assert.Equal(t, ErrLocked, err)
+
+	// The transaction is signed with all the keys of the role.
+	signed, err := ks.SignTxWithRoleAndPassphrase(acc, "", accountkey.RoleTransaction, tx, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubs, err := types.SenderPubkey(signer, signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*ecdsa.PublicKey{&keys[0][0].PublicKey, &keys[0][1].PublicKey}, pubs)
+
+	// The value transfer is not signed with the account update keys.
+	_, err = ks.SignTxWithRoleAndPassphrase(acc, "", accountkey.RoleAccountUpdate, tx, chainID)
+	assert.True(t, errors.Is(err, ErrRoleMismatch))
+
+	signed, err = ks.SignTxWithRoleAndPassphrase(acc, "", accountkey.RoleFeePayer, tx, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubs, err = types.SenderFeePayerPubkey(signer, signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*ecdsa.PublicKey{&keys[2][0].PublicKey}, pubs)
+}
@@ -20,7 +20,9 @@ import (
 	"crypto/ecdsa"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 
+	"github.com/klaytn/klaytn/blockchain/types/accountkey"
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/crypto"
 	"github.com/pborman/uuid"
@@ -46,6 +48,7 @@ type encryptedKeyJSONV4 struct {
 	Keyring [][]cryptoJSON `json:"keyring"`
 	Id      string         `json:"id"`
 	Version int            `json:"version"`
+	Compat  string         `json:"compat,omitempty"` // Set if the keys need an extension, e.g. Argon2id
 }
 
 type encryptedKeyJSONV4Single struct {
@@ -55,6 +58,20 @@ type encryptedKeyJSONV4Single struct {
 	Version int          `json:"version"`
 }
 
+// newRoleBasedKey returns the key of the role-based keys of the address, indexed
+// by the role.
+func newRoleBasedKey(address common.Address, keys [][]*ecdsa.PrivateKey) (*KeyV4, error) {
+	if len(keys) == 0 || len(keys) > int(accountkey.RoleLast) {
+		return nil, fmt.Errorf("the number of the roles must be between 1 and %d", accountkey.RoleLast)
+	}
+	for role, prv := range keys {
+		if len(prv) == 0 || uint64(len(prv)) > accountkey.MaxNumKeysForMultiSig {
+			return nil, fmt.Errorf("the number of the %s keys must be between 1 and %d", accountkey.RoleType(role), accountkey.MaxNumKeysForMultiSig)
+		}
+	}
+	return &KeyV4{Id: uuid.NewRandom(), Address: address, PrivateKeys: keys}, nil
+}
+
 func (k *KeyV4) MarshalJSON() (j []byte, err error) {
 	privateKeys := make([][]string, len(k.PrivateKeys))
 
@@ -0,0 +1,85 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package kms
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+// awsClient is the client of AWS KMS. The credentials are taken from the
+// environment, the shared configuration or the instance role.
+type awsClient struct {
+	kms    *kms.KMS
+	prefix string // Prefix of the aliases of the keys to discover
+}
+
+func newAWSClient(region, endpoint, prefix string) (*awsClient, error) {
+	config := &aws.Config{Region: aws.String(region)}
+	if endpoint != "" {
+		config.Endpoint = aws.String(endpoint)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{Config: *config, SharedConfigState: session.SharedConfigEnable})
+	if err != nil {
+		return nil, err
+	}
+	return &awsClient{kms: kms.New(sess), prefix: prefix}, nil
+}
+
+func (c *awsClient) service() string { return "aws" }
+
+// discover returns the keys whose aliases start with the prefix, by the aliases
+// without "alias/".
+func (c *awsClient) discover(ctx context.Context) (map[string]string, error) {
+	keys := make(map[string]string)
+	err := c.kms.ListAliasesPagesWithContext(ctx, &kms.ListAliasesInput{}, func(page *kms.ListAliasesOutput, last bool) bool {
+		for _, alias := range page.Aliases {
+			name := aws.StringValue(alias.AliasName)
+			if alias.TargetKeyId == nil || !strings.HasPrefix(name, c.prefix) {
+				continue
+			}
+			keys[strings.TrimPrefix(name, "alias/")] = *alias.TargetKeyId
+		}
+		return true
+	})
+	return keys, err
+}
+
+func (c *awsClient) publicKey(ctx context.Context, keyID string) ([]byte, error) {
+	out, err := c.kms.GetPublicKeyWithContext(ctx, &kms.GetPublicKeyInput{KeyId: aws.String(keyID)})
+	if err != nil {
+		return nil, err
+	}
+	return out.PublicKey, nil
+}
+
+func (c *awsClient) sign(ctx context.Context, keyID string, digest []byte) ([]byte, error) {
+	out, err := c.kms.SignWithContext(ctx, &kms.SignInput{
+		KeyId:            aws.String(keyID),
+		Message:          digest,
+		MessageType:      aws.String(kms.MessageTypeDigest),
+		SigningAlgorithm: aws.String(kms.SigningAlgorithmSpecEcdsaSha256),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Signature, nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+/*
+Package kms implements the accounts whose private keys are kept in a cloud key
+management service, AWS KMS or Google Cloud KMS. The keys never leave the KMS,
+and the transactions and the hashes are signed by its Sign API, so the keys are
+guarded by the key policies and the audit logs of the KMS.
+
+The secp256k1 signing keys are discovered when the node starts: the keys whose
+aliases start with the configured prefix in AWS KMS, and the enabled key
+versions of the secp256k1 keys in the configured key ring of Google Cloud KMS.
+Each key is a wallet with the URL kms://aws/<alias> or kms://gcp/<key>.
+
+The passphrases given to the wallets are ignored, since the use of the keys is
+authorized by the KMS.
+
+The key whose URL is given as the consensus key signs the consensus messages,
+and its address is the validator address of the node. The node key remains the
+identity of the p2p connections, whose handshake needs a key agreement which
+the KMS doesn't provide; the peers learn the validator address from its
+signature over the node ID.
+
+Source Files
+
+  - aws.go	: Provides the client of AWS KMS
+  - gcp.go	: Provides the client of Google Cloud KMS with its REST API
+  - kms.go	: Defines `Backend` discovering the keys, and the wallet signing with a key
+*/
+package kms
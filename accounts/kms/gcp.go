@@ -0,0 +1,227 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package kms
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	gcpKMSEndpoint = "https://cloudkms.googleapis.com/v1/"
+	gcpTokenURL    = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+	// gcpTokenEnv is the environment variable of an OAuth 2.0 access token, used
+	// instead of the token of the service account of the instance.
+	gcpTokenEnv = "GOOGLE_OAUTH_ACCESS_TOKEN"
+
+	gcpSecp256k1Algorithm = "EC_SIGN_SECP256K1_SHA256"
+)
+
+// gcpClient is the client of Google Cloud KMS with its REST API. The requests
+// are authorized by the access token of the environment, or of the service
+// account of the instance from the metadata server.
+type gcpClient struct {
+	keyRing  string
+	endpoint string
+	client   *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+func newGCPClient(keyRing string) *gcpClient {
+	return &gcpClient{
+		keyRing:  strings.Trim(keyRing, "/"),
+		endpoint: gcpKMSEndpoint,
+		client:   &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (c *gcpClient) service() string { return "gcp" }
+
+// accessToken returns the access token, refreshing the token of the service
+// account before it expires.
+func (c *gcpClient) accessToken(ctx context.Context) (string, error) {
+	if token := os.Getenv(gcpTokenEnv); token != "" {
+		return token, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && time.Now().Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, gcpTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	var res struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := c.do(req.WithContext(ctx), &res); err != nil {
+		return "", fmt.Errorf("failed to get the access token from the metadata server, set %s instead: %v", gcpTokenEnv, err)
+	}
+	c.token = res.AccessToken
+	c.tokenExpiry = time.Now().Add(time.Duration(res.ExpiresIn)*time.Second - time.Minute)
+	return c.token, nil
+}
+
+// call calls the KMS API of the resource with the body, if any, and decodes the
+// response into result.
+func (c *gcpClient) call(ctx context.Context, resource string, body, result interface{}) error {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	method, reader := http.MethodGet, io.Reader(nil)
+	if body != nil {
+		enc, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		method, reader = http.MethodPost, bytes.NewReader(enc)
+	}
+	req, err := http.NewRequest(method, c.endpoint+resource, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	return c.do(req.WithContext(ctx), result)
+}
+
+func (c *gcpClient) do(req *http.Request, result interface{}) error {
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", res.Status, strings.TrimSpace(string(data)))
+	}
+	return json.Unmarshal(data, result)
+}
+
+// discover returns the latest enabled versions of the secp256k1 signing keys in
+// the key ring, by the names of the keys.
+func (c *gcpClient) discover(ctx context.Context) (map[string]string, error) {
+	keys := make(map[string]string)
+	pageToken := ""
+	for {
+		var res struct {
+			CryptoKeys []struct {
+				Name            string `json:"name"`
+				VersionTemplate struct {
+					Algorithm string `json:"algorithm"`
+				} `json:"versionTemplate"`
+			} `json:"cryptoKeys"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		query := url.Values{"filter": {"purpose=ASYMMETRIC_SIGN"}, "pageToken": {pageToken}}
+		if err := c.call(ctx, c.keyRing+"/cryptoKeys?"+query.Encode(), nil, &res); err != nil {
+			return nil, err
+		}
+		for _, key := range res.CryptoKeys {
+			if key.VersionTemplate.Algorithm != gcpSecp256k1Algorithm {
+				continue
+			}
+			version, err := c.latestVersion(ctx, key.Name)
+			if err != nil {
+				return nil, err
+			}
+			if version != "" {
+				keys[key.Name[strings.LastIndex(key.Name, "/")+1:]] = version
+			}
+		}
+		if res.NextPageToken == "" {
+			return keys, nil
+		}
+		pageToken = res.NextPageToken
+	}
+}
+
+// latestVersion returns the enabled version of the key created last, or "" if
+// no version is enabled.
+func (c *gcpClient) latestVersion(ctx context.Context, key string) (string, error) {
+	var res struct {
+		CryptoKeyVersions []struct {
+			Name       string    `json:"name"`
+			CreateTime time.Time `json:"createTime"`
+		} `json:"cryptoKeyVersions"`
+	}
+	query := url.Values{"filter": {"state=ENABLED"}, "pageSize": {"1000"}}
+	if err := c.call(ctx, key+"/cryptoKeyVersions?"+query.Encode(), nil, &res); err != nil {
+		return "", err
+	}
+	latest, created := "", time.Time{}
+	for _, version := range res.CryptoKeyVersions {
+		if latest == "" || version.CreateTime.After(created) {
+			latest, created = version.Name, version.CreateTime
+		}
+	}
+	return latest, nil
+}
+
+func (c *gcpClient) publicKey(ctx context.Context, keyID string) ([]byte, error) {
+	var res struct {
+		Pem       string `json:"pem"`
+		Algorithm string `json:"algorithm"`
+	}
+	if err := c.call(ctx, keyID+"/publicKey", nil, &res); err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode([]byte(res.Pem))
+	if block == nil {
+		return nil, errors.New("invalid PEM public key")
+	}
+	return block.Bytes, nil
+}
+
+func (c *gcpClient) sign(ctx context.Context, keyID string, digest []byte) ([]byte, error) {
+	req := map[string]interface{}{
+		"digest": map[string]string{"sha256": base64.StdEncoding.EncodeToString(digest)},
+	}
+	var res struct {
+		Signature string `json:"signature"`
+	}
+	if err := c.call(ctx, keyID+":asymmetricSign", req, &res); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(res.Signature)
+}
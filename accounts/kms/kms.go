@@ -0,0 +1,340 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package kms
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/klaytn/klaytn"
+	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/event"
+	"github.com/klaytn/klaytn/log"
+)
+
+// URLScheme is the scheme of the URLs of the KMS wallets.
+const URLScheme = "kms"
+
+// requestTimeout is the timeout of a request to the KMS.
+const requestTimeout = 10 * time.Second
+
+// BackendType is the reflect type of the KMS backend.
+var BackendType = reflect.TypeOf(&Backend{})
+
+var logger = log.NewModuleLogger(log.AccountsKMS)
+
+var (
+	errChainIDNil       = errors.New("chain ID should not be nil")
+	errInvalidSignature = errors.New("invalid signature from the KMS")
+
+	errUnknownConsensusKey = errors.New("unknown consensus key of the KMS")
+
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidSecp256k1      = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// Config is the configuration of the KMS backends. A backend is enabled by its
+// region or key ring.
+type Config struct {
+	AWSRegion      string `toml:",omitempty"` // Region of the AWS KMS keys
+	AWSEndpoint    string `toml:",omitempty"` // Endpoint of AWS KMS, if not the default of the region
+	AWSAliasPrefix string `toml:",omitempty"` // Prefix of the aliases of the AWS KMS keys to use
+	GCPKeyRing     string `toml:",omitempty"` // Key ring of the Google Cloud KMS keys, projects/*/locations/*/keyRings/*
+	ConsensusKey   string `toml:",omitempty"` // URL of the key signing the consensus messages, e.g. kms://aws/klaytn/validator
+}
+
+// DefaultAWSAliasPrefix is the prefix of the aliases of the AWS KMS keys used
+// if none is configured.
+const DefaultAWSAliasPrefix = "alias/klaytn/"
+
+// Enabled returns true if any KMS backend is configured.
+func (c Config) Enabled() bool {
+	return c.AWSRegion != "" || c.GCPKeyRing != ""
+}
+
+// keyClient is a client of a key management service.
+type keyClient interface {
+	// service returns the name of the service, used as the host of the URLs.
+	service() string
+	// discover returns the IDs of the signing keys by their names.
+	discover(ctx context.Context) (map[string]string, error)
+	// publicKey returns the DER encoded SubjectPublicKeyInfo of the key.
+	publicKey(ctx context.Context, keyID string) ([]byte, error)
+	// sign signs the digest with the key, and returns the DER encoded signature.
+	sign(ctx context.Context, keyID string, digest []byte) ([]byte, error)
+}
+
+// Backend is the accounts.Backend of the keys discovered in the KMS.
+type Backend struct {
+	wallets      []accounts.Wallet
+	feed         event.Feed
+	consensusKey string
+}
+
+// NewBackend discovers the secp256k1 keys in the configured KMS, and returns a
+// backend of their wallets.
+func NewBackend(config Config) (*Backend, error) {
+	var clients []keyClient
+	if config.AWSRegion != "" {
+		prefix := config.AWSAliasPrefix
+		if prefix == "" {
+			prefix = DefaultAWSAliasPrefix
+		}
+		client, err := newAWSClient(config.AWSRegion, config.AWSEndpoint, prefix)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, client)
+	}
+	if config.GCPKeyRing != "" {
+		clients = append(clients, newGCPClient(config.GCPKeyRing))
+	}
+	return newBackend(clients, config.ConsensusKey)
+}
+
+func newBackend(clients []keyClient, consensusKey string) (*Backend, error) {
+	b := &Backend{consensusKey: consensusKey}
+	for _, client := range clients {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		keys, err := client.discover(ctx)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover the keys of %s KMS: %v", client.service(), err)
+		}
+		for name, keyID := range keys {
+			w, err := newWallet(client, name, keyID)
+			if err != nil {
+				logger.Warn("Ignored a KMS key which is not a secp256k1 signing key", "service", client.service(), "key", name, "err", err)
+				continue
+			}
+			logger.Info("Loaded a KMS account", "url", w.url, "address", w.account.Address)
+			b.wallets = append(b.wallets, w)
+		}
+	}
+	sort.Slice(b.wallets, func(i, j int) bool { return b.wallets[i].URL().Cmp(b.wallets[j].URL()) < 0 })
+	return b, nil
+}
+
+// Wallets implements accounts.Backend, returning the wallets of the keys.
+func (b *Backend) Wallets() []accounts.Wallet {
+	cpy := make([]accounts.Wallet, len(b.wallets))
+	copy(cpy, b.wallets)
+	return cpy
+}
+
+// Subscribe implements accounts.Backend. The wallets are not changed after
+// the discovery, so no event is sent.
+func (b *Backend) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	return b.feed.Subscribe(sink)
+}
+
+// ConsensusSigner returns the address of the consensus key and the function
+// signing the hashes of the consensus messages with it. The function is nil if
+// no consensus key is configured.
+func (b *Backend) ConsensusSigner() (common.Address, func(hash []byte) ([]byte, error), error) {
+	if b.consensusKey == "" {
+		return common.Address{}, nil, nil
+	}
+	for _, w := range b.wallets {
+		if w := w.(*wallet); w.url.String() == b.consensusKey {
+			return w.account.Address, func(hash []byte) ([]byte, error) {
+				return w.SignHash(w.account, hash)
+			}, nil
+		}
+	}
+	return common.Address{}, nil, fmt.Errorf("%w: %s", errUnknownConsensusKey, b.consensusKey)
+}
+
+// wallet is the accounts.Wallet of a KMS key.
+type wallet struct {
+	url     accounts.URL
+	account accounts.Account
+	client  keyClient
+	keyID   string
+	pubkey  *ecdsa.PublicKey
+
+	mu sync.Mutex // Serializes the signing requests of the key
+}
+
+func newWallet(client keyClient, name, keyID string) (*wallet, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	der, err := client.publicKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	pubkey, err := parsePublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	url := accounts.URL{Scheme: URLScheme, Path: client.service() + "/" + name}
+	return &wallet{
+		url:     url,
+		account: accounts.Account{Address: crypto.PubkeyToAddress(*pubkey), URL: url},
+		client:  client,
+		keyID:   keyID,
+		pubkey:  pubkey,
+	}, nil
+}
+
+// parsePublicKey parses the DER encoded SubjectPublicKeyInfo of a secp256k1
+// key, which is not supported by crypto/x509.
+func parsePublicKey(der []byte) (*ecdsa.PublicKey, error) {
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if rest, err := asn1.Unmarshal(der, &spki); err != nil {
+		return nil, err
+	} else if len(rest) > 0 {
+		return nil, errors.New("trailing data after the public key")
+	}
+	if !spki.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) {
+		return nil, fmt.Errorf("not an ECDSA key: %v", spki.Algorithm.Algorithm)
+	}
+	var curve asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(spki.Algorithm.Parameters.FullBytes, &curve); err != nil || !curve.Equal(oidSecp256k1) {
+		return nil, fmt.Errorf("not a secp256k1 key: %v", curve)
+	}
+	return crypto.UnmarshalPubkey(spki.PublicKey.Bytes)
+}
+
+func (w *wallet) URL() accounts.URL { return w.url }
+
+// Status implements accounts.Wallet. The KMS is reached on each signature.
+func (w *wallet) Status() (string, error) { return "Online", nil }
+
+func (w *wallet) Open(passphrase string) error { return nil }
+
+func (w *wallet) Close() error { return nil }
+
+func (w *wallet) Accounts() []accounts.Account { return []accounts.Account{w.account} }
+
+func (w *wallet) Contains(account accounts.Account) bool {
+	return account.Address == w.account.Address && (account.URL == (accounts.URL{}) || account.URL == w.url)
+}
+
+func (w *wallet) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	return accounts.Account{}, accounts.ErrNotSupported
+}
+
+func (w *wallet) SelfDerive(base accounts.DerivationPath, chain klaytn.ChainReader) {}
+
+// SignHash signs the hash by the KMS. The signature is in the [R || S || V]
+// format where V is 0 or 1.
+func (w *wallet) SignHash(account accounts.Account, hash []byte) ([]byte, error) {
+	if !w.Contains(account) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	der, err := w.client.sign(ctx, w.keyID, hash)
+	if err != nil {
+		return nil, err
+	}
+	return recoverableSignature(der, hash, w.pubkey)
+}
+
+// recoverableSignature converts the DER encoded signature into the [R || S || V]
+// format with the lower S value, finding V by recovering the public key.
+func recoverableSignature(der, hash []byte, pubkey *ecdsa.PublicKey) ([]byte, error) {
+	var rs struct{ R, S *big.Int }
+	if rest, err := asn1.Unmarshal(der, &rs); err != nil || len(rest) > 0 {
+		return nil, errInvalidSignature
+	}
+	n := crypto.S256().Params().N
+	if rs.R.Sign() <= 0 || rs.S.Sign() <= 0 || rs.R.Cmp(n) >= 0 || rs.S.Cmp(n) >= 0 {
+		return nil, errInvalidSignature
+	}
+	if rs.S.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		rs.S.Sub(n, rs.S)
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	rs.R.FillBytes(sig[:32])
+	rs.S.FillBytes(sig[32:64])
+	expected := crypto.FromECDSAPub(pubkey)
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		if recovered, err := crypto.Ecrecover(hash, sig); err == nil && string(recovered) == string(expected) {
+			return sig, nil
+		}
+	}
+	return nil, errInvalidSignature
+}
+
+// SignTx signs the transaction by the KMS.
+func (w *wallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if chainID == nil {
+		return nil, errChainIDNil
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	hash := signer.Hash(tx)
+	sig, err := w.SignHash(account, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signer, sig)
+}
+
+// SignTxAsFeePayer signs the transaction as a fee payer by the KMS.
+func (w *wallet) SignTxAsFeePayer(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if chainID == nil {
+		return nil, errChainIDNil
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	hash, err := signer.HashFeePayer(tx)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := w.SignHash(account, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithFeePayerSignature(signer, sig)
+}
+
+// SignHashWithPassphrase implements accounts.Wallet. The passphrase is ignored
+// since the use of the key is authorized by the KMS.
+func (w *wallet) SignHashWithPassphrase(account accounts.Account, passphrase string, hash []byte) ([]byte, error) {
+	return w.SignHash(account, hash)
+}
+
+// SignTxWithPassphrase implements accounts.Wallet, ignoring the passphrase.
+func (w *wallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return w.SignTx(account, tx, chainID)
+}
+
+// SignTxAsFeePayerWithPassphrase implements accounts.Wallet, ignoring the passphrase.
+func (w *wallet) SignTxAsFeePayerWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return w.SignTxAsFeePayer(account, tx, chainID)
+}
@@ -0,0 +1,232 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package kms
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClient signs with local keys, returning the high S values as some KMS do.
+type fakeClient struct {
+	keys map[string]*ecdsa.PrivateKey
+}
+
+func (c *fakeClient) service() string { return "fake" }
+
+func (c *fakeClient) discover(ctx context.Context) (map[string]string, error) {
+	keys := make(map[string]string)
+	for id := range c.keys {
+		keys["key-"+id] = id
+	}
+	return keys, nil
+}
+
+func (c *fakeClient) publicKey(ctx context.Context, keyID string) ([]byte, error) {
+	return marshalPublicKey(&c.keys[keyID].PublicKey)
+}
+
+func (c *fakeClient) sign(ctx context.Context, keyID string, digest []byte) ([]byte, error) {
+	sig, err := crypto.Sign(digest, c.keys[keyID])
+	if err != nil {
+		return nil, err
+	}
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])
+	s.Sub(crypto.S256().Params().N, s)
+	return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+}
+
+func marshalPublicKey(pub *ecdsa.PublicKey) ([]byte, error) {
+	curve, err := asn1.Marshal(oidSecp256k1)
+	if err != nil {
+		return nil, err
+	}
+	key := crypto.FromECDSAPub(pub)
+	return asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidPublicKeyECDSA, Parameters: asn1.RawValue{FullBytes: curve}},
+		PublicKey: asn1.BitString{Bytes: key, BitLength: 8 * len(key)},
+	})
+}
+
+func TestKMSWallet(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	backend, err := newBackend([]keyClient{&fakeClient{keys: map[string]*ecdsa.PrivateKey{"1": key}}}, "")
+	if !assert.NoError(t, err) || !assert.Len(t, backend.Wallets(), 1) {
+		return
+	}
+	w := backend.Wallets()[0]
+	account := accounts.Account{Address: crypto.PubkeyToAddress(key.PublicKey)}
+	assert.Equal(t, "kms://fake/key-1", w.URL().String())
+	assert.Equal(t, []accounts.Account{{Address: account.Address, URL: w.URL()}}, w.Accounts())
+	assert.True(t, w.Contains(account))
+
+	// The signature is normalized to the lower S value with the recovery ID.
+	hash := crypto.Keccak256([]byte("hello"))
+	sig, err := w.SignHash(account, hash)
+	if assert.NoError(t, err) {
+		pub, err := crypto.SigToPub(hash, sig)
+		assert.NoError(t, err)
+		assert.Equal(t, account.Address, crypto.PubkeyToAddress(*pub))
+		assert.True(t, crypto.ValidateSignatureValues(sig[64], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]), true))
+	}
+	_, err = w.SignHash(accounts.Account{Address: common.Address{0x01}}, hash)
+	assert.Equal(t, accounts.ErrUnknownAccount, err)
+
+	chainID := big.NewInt(1001)
+	tx := types.NewTransaction(0, common.Address{0x02}, big.NewInt(1), 21000, big.NewInt(25000000000), nil)
+	signed, err := w.SignTxWithPassphrase(account, "ignored", tx, chainID)
+	if assert.NoError(t, err) {
+		sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+		assert.NoError(t, err)
+		assert.Equal(t, account.Address, sender)
+	}
+	_, err = w.SignTx(account, tx, nil)
+	assert.Equal(t, errChainIDNil, err)
+}
+
+func TestKMSConsensusSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	client := &fakeClient{keys: map[string]*ecdsa.PrivateKey{"1": key}}
+	backend, err := newBackend([]keyClient{client}, "kms://fake/key-1")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// The consensus messages are signed with the consensus key.
+	addr, sign, err := backend.ConsensusSigner()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), addr)
+	hash := crypto.Keccak256([]byte("message"))
+	sig, err := sign(hash)
+	if assert.NoError(t, err) {
+		pub, err := crypto.SigToPub(hash, sig)
+		assert.NoError(t, err)
+		assert.Equal(t, addr, crypto.PubkeyToAddress(*pub))
+	}
+
+	backend, err = newBackend([]keyClient{client}, "kms://fake/unknown")
+	if assert.NoError(t, err) {
+		_, _, err = backend.ConsensusSigner()
+		assert.ErrorIs(t, err, errUnknownConsensusKey)
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	der, err := marshalPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	pub, err := parsePublicKey(der)
+	if assert.NoError(t, err) {
+		assert.Equal(t, crypto.FromECDSAPub(&key.PublicKey), crypto.FromECDSAPub(pub))
+	}
+
+	// A P-256 key is rejected.
+	curve, _ := asn1.Marshal(asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7})
+	der, _ = asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidPublicKeyECDSA, Parameters: asn1.RawValue{FullBytes: curve}},
+		PublicKey: asn1.BitString{Bytes: []byte{4}, BitLength: 8},
+	})
+	_, err = parsePublicKey(der)
+	assert.Error(t, err)
+}
+
+func TestGCPClient(t *testing.T) {
+	os.Setenv(gcpTokenEnv, "token")
+	defer os.Unsetenv(gcpTokenEnv)
+
+	key, _ := crypto.GenerateKey()
+	fake := &fakeClient{keys: map[string]*ecdsa.PrivateKey{"1": key}}
+	const (
+		ring    = "projects/p/locations/global/keyRings/r"
+		version = ring + "/cryptoKeys/validator/cryptoKeyVersions/2"
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var res interface{}
+		switch r.URL.Path {
+		case "/v1/" + ring + "/cryptoKeys":
+			res = map[string]interface{}{"cryptoKeys": []interface{}{
+				map[string]interface{}{"name": ring + "/cryptoKeys/validator", "versionTemplate": map[string]string{"algorithm": gcpSecp256k1Algorithm}},
+				map[string]interface{}{"name": ring + "/cryptoKeys/rsa", "versionTemplate": map[string]string{"algorithm": "RSA_SIGN_PSS_2048_SHA256"}},
+			}}
+		case "/v1/" + ring + "/cryptoKeys/validator/cryptoKeyVersions":
+			res = map[string]interface{}{"cryptoKeyVersions": []interface{}{
+				map[string]string{"name": ring + "/cryptoKeys/validator/cryptoKeyVersions/1", "createTime": "2022-01-01T00:00:00Z"},
+				map[string]string{"name": version, "createTime": "2022-02-01T00:00:00Z"},
+			}}
+		case "/v1/" + version + "/publicKey":
+			der, _ := fake.publicKey(r.Context(), "1")
+			res = map[string]string{"pem": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))}
+		case "/v1/" + version + ":asymmetricSign":
+			var req struct {
+				Digest struct {
+					Sha256 []byte `json:"sha256"`
+				} `json:"digest"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			sig, _ := fake.sign(r.Context(), "1", req.Digest.Sha256)
+			res = map[string]string{"signature": base64.StdEncoding.EncodeToString(sig)}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+
+	client := newGCPClient(ring)
+	client.endpoint = server.URL + "/v1/"
+	backend, err := newBackend([]keyClient{client}, "")
+	if !assert.NoError(t, err) || !assert.Len(t, backend.Wallets(), 1) {
+		return
+	}
+	w := backend.Wallets()[0]
+	assert.Equal(t, "kms://gcp/validator", w.URL().String())
+
+	account := accounts.Account{Address: crypto.PubkeyToAddress(key.PublicKey)}
+	hash := crypto.Keccak256([]byte("hello"))
+	sig, err := w.SignHash(account, hash)
+	if assert.NoError(t, err) {
+		pub, err := crypto.SigToPub(hash, sig)
+		assert.NoError(t, err)
+		assert.Equal(t, account.Address, crypto.PubkeyToAddress(*pub))
+	}
+}
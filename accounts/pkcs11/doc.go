@@ -0,0 +1,46 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+/*
+Package pkcs11 implements the accounts whose keys are kept in a hardware
+security module reached by its PKCS#11 module, e.g. SoftHSM, AWS CloudHSM or
+Thales Luna, so the keys are never in a file of the node, not even encrypted.
+
+The module is loaded by dlopen, and the token is chosen by its label or by the
+ID of its slot. The node logs in as the user of the token with the PIN read
+from a file, and finds the EC private keys on the secp256k1 curve. Their public
+keys are read from the keys themselves or from the public keys of the same
+CKA_ID. Each key is a wallet with the URL pkcs11://<token>/<key label>.
+
+The hashes are signed by CKM_ECDSA and the S values are lowered as Klaytn
+requires. The session of the token is opened again if the HSM closes it, e.g.
+on a failover of a cluster. The passphrases given to the wallets are ignored,
+since the use of the keys is authorized by the PIN.
+
+The key given as the consensus key signs the consensus messages, and its
+address is the validator address of the node. The node key remains the
+identity of the p2p connections, and the peers learn the validator address
+from its signature over the node ID, so the consensus key never leaves the HSM.
+
+The module is loaded by cgo, so the backend is not available in the builds
+without cgo nor on Windows, where PKCS#11 packs its structures.
+
+Source Files
+
+  - module_cgo.go	: Provides the binding of the functions of a PKCS#11 module
+  - pkcs11.go	: Defines `Backend` finding the keys of the token, and the wallet signing with a key
+*/
+package pkcs11
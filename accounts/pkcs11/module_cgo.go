@@ -0,0 +1,348 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build cgo && !windows
+// +build cgo,!windows
+
+package pkcs11
+
+/*
+#cgo linux LDFLAGS: -ldl
+
+#include <dlfcn.h>
+#include <stdlib.h>
+#include <string.h>
+
+// The types of PKCS#11 used by the binding, which are not packed on the Unix
+// platforms. The functions are resolved by their names, so no header of a
+// vendor is needed.
+typedef unsigned long ck_ulong;
+typedef ck_ulong ck_rv;
+
+typedef struct {
+	ck_ulong type;
+	void *value;
+	ck_ulong len;
+} ck_attribute;
+
+typedef struct {
+	ck_ulong mechanism;
+	void *parameter;
+	ck_ulong len;
+} ck_mechanism;
+
+typedef struct {
+	void *create_mutex, *destroy_mutex, *lock_mutex, *unlock_mutex;
+	ck_ulong flags;
+	void *reserved;
+} ck_initialize_args;
+
+enum {
+	fn_initialize,
+	fn_get_slot_list,
+	fn_get_token_info,
+	fn_open_session,
+	fn_close_session,
+	fn_login,
+	fn_find_objects_init,
+	fn_find_objects,
+	fn_find_objects_final,
+	fn_get_attribute_value,
+	fn_sign_init,
+	fn_sign,
+	fn_count
+};
+
+static const char *fn_names[fn_count] = {
+	"C_Initialize",
+	"C_GetSlotList",
+	"C_GetTokenInfo",
+	"C_OpenSession",
+	"C_CloseSession",
+	"C_Login",
+	"C_FindObjectsInit",
+	"C_FindObjects",
+	"C_FindObjectsFinal",
+	"C_GetAttributeValue",
+	"C_SignInit",
+	"C_Sign",
+};
+
+typedef struct {
+	void *lib;
+	void *fn[fn_count];
+} ck_module;
+
+// ck_open loads the module and resolves its functions. It returns NULL with
+// the error of dlopen in err, or the name of the missing function in missing.
+static ck_module *ck_open(const char *path, const char **err, const char **missing) {
+	void *lib = dlopen(path, RTLD_NOW | RTLD_LOCAL);
+	if (lib == NULL) {
+		*err = dlerror();
+		return NULL;
+	}
+	ck_module *m = calloc(1, sizeof(ck_module));
+	m->lib = lib;
+	for (int i = 0; i < fn_count; i++) {
+		m->fn[i] = dlsym(lib, fn_names[i]);
+		if (m->fn[i] == NULL) {
+			*missing = fn_names[i];
+			dlclose(lib);
+			free(m);
+			return NULL;
+		}
+	}
+	return m;
+}
+
+static ck_rv ck_initialize(ck_module *m) {
+	ck_initialize_args args;
+	memset(&args, 0, sizeof(args));
+	args.flags = 0x2; // CKF_OS_LOCKING_OK, as the goroutines run on many threads
+	return ((ck_rv (*)(void *))m->fn[fn_initialize])(&args);
+}
+
+static ck_rv ck_get_slot_list(ck_module *m, ck_ulong *slots, ck_ulong *count) {
+	return ((ck_rv (*)(unsigned char, ck_ulong *, ck_ulong *))m->fn[fn_get_slot_list])(1, slots, count);
+}
+
+// ck_get_token_label copies the label, the first field of CK_TOKEN_INFO.
+static ck_rv ck_get_token_label(ck_module *m, ck_ulong slot, unsigned char *label) {
+	ck_ulong info[128];
+	ck_rv rv = ((ck_rv (*)(ck_ulong, void *))m->fn[fn_get_token_info])(slot, info);
+	if (rv == 0) {
+		memcpy(label, info, 32);
+	}
+	return rv;
+}
+
+static ck_rv ck_open_session(ck_module *m, ck_ulong slot, ck_ulong *session) {
+	// CKF_SERIAL_SESSION, read only
+	return ((ck_rv (*)(ck_ulong, ck_ulong, void *, void *, ck_ulong *))m->fn[fn_open_session])(slot, 0x4, NULL, NULL, session);
+}
+
+static ck_rv ck_close_session(ck_module *m, ck_ulong session) {
+	return ((ck_rv (*)(ck_ulong))m->fn[fn_close_session])(session);
+}
+
+static ck_rv ck_login(ck_module *m, ck_ulong session, unsigned char *pin, ck_ulong len) {
+	// CKU_USER
+	return ((ck_rv (*)(ck_ulong, ck_ulong, unsigned char *, ck_ulong))m->fn[fn_login])(session, 1, pin, len);
+}
+
+// ck_find_keys finds the EC keys of the class, with the ID if it is not NULL.
+static ck_rv ck_find_keys(ck_module *m, ck_ulong session, ck_ulong class, unsigned char *id, ck_ulong id_len, ck_ulong *keys, ck_ulong max, ck_ulong *count) {
+	ck_ulong key_type = 0x3; // CKK_EC
+	ck_attribute template[3] = {
+		{0x0, &class, sizeof(class)},          // CKA_CLASS
+		{0x100, &key_type, sizeof(key_type)}, // CKA_KEY_TYPE
+		{0x102, id, id_len},                  // CKA_ID
+	};
+	ck_rv rv = ((ck_rv (*)(ck_ulong, ck_attribute *, ck_ulong))m->fn[fn_find_objects_init])(session, template, id != NULL ? 3 : 2);
+	if (rv != 0) {
+		return rv;
+	}
+	*count = 0;
+	while (*count < max) {
+		ck_ulong n = 0;
+		rv = ((ck_rv (*)(ck_ulong, ck_ulong *, ck_ulong, ck_ulong *))m->fn[fn_find_objects])(session, keys + *count, max - *count, &n);
+		if (rv != 0 || n == 0) {
+			break;
+		}
+		*count += n;
+	}
+	ck_rv final = ((ck_rv (*)(ck_ulong))m->fn[fn_find_objects_final])(session);
+	return rv != 0 ? rv : final;
+}
+
+// ck_get_attribute gets the value of an attribute, or its length if the value
+// is NULL. The length is (ck_ulong)-1 if the attribute is not available.
+static ck_rv ck_get_attribute(ck_module *m, ck_ulong session, ck_ulong object, ck_ulong type, unsigned char *value, ck_ulong *len) {
+	ck_attribute template = {type, value, *len};
+	ck_rv rv = ((ck_rv (*)(ck_ulong, ck_ulong, ck_attribute *, ck_ulong))m->fn[fn_get_attribute_value])(session, object, &template, 1);
+	*len = template.len;
+	return rv;
+}
+
+static ck_rv ck_sign(ck_module *m, ck_ulong session, ck_ulong key, unsigned char *digest, ck_ulong digest_len, unsigned char *sig, ck_ulong *sig_len) {
+	ck_mechanism mechanism = {0x1041, NULL, 0}; // CKM_ECDSA
+	ck_rv rv = ((ck_rv (*)(ck_ulong, ck_mechanism *, ck_ulong))m->fn[fn_sign_init])(session, &mechanism, key);
+	if (rv != 0) {
+		return rv;
+	}
+	return ((ck_rv (*)(ck_ulong, unsigned char *, ck_ulong, unsigned char *, ck_ulong *))m->fn[fn_sign])(session, digest, digest_len, sig, sig_len);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// The error codes of the attributes which are not available.
+const (
+	errAttributeSensitive   Error = 0x11
+	errAttributeTypeInvalid Error = 0x12
+)
+
+// maxObjects is the maximum number of the keys found in a token.
+const maxObjects = 1024
+
+var (
+	modulesMu sync.Mutex
+	modules   = make(map[string]*cModule) // Loaded modules by their paths
+)
+
+// cModule is the module loaded by dlopen.
+type cModule struct {
+	m *C.ck_module
+}
+
+// openModule loads and initializes the PKCS#11 module of the path. A module is
+// loaded once, since it can't be initialized again.
+func openModule(path string) (module, error) {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	if mod, ok := modules[path]; ok {
+		return mod, nil
+	}
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	var cerr, missing *C.char
+	m := C.ck_open(cpath, &cerr, &missing)
+	if m == nil {
+		if missing != nil {
+			return nil, fmt.Errorf("no %s in the module", C.GoString(missing))
+		}
+		return nil, errors.New(C.GoString(cerr))
+	}
+	if err := check(C.ck_initialize(m)); err != nil && err != errCryptokiAlreadyInited {
+		return nil, err
+	}
+	mod := &cModule{m: m}
+	modules[path] = mod
+	return mod, nil
+}
+
+func check(rv C.ck_rv) error {
+	if rv != 0 {
+		return Error(rv)
+	}
+	return nil
+}
+
+func (c *cModule) slots() ([]uint, error) {
+	var count C.ck_ulong
+	if err := check(C.ck_get_slot_list(c.m, nil, &count)); err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	list := make([]C.ck_ulong, count)
+	if err := check(C.ck_get_slot_list(c.m, &list[0], &count)); err != nil {
+		return nil, err
+	}
+	slots := make([]uint, count)
+	for i := range slots {
+		slots[i] = uint(list[i])
+	}
+	return slots, nil
+}
+
+func (c *cModule) tokenLabel(slot uint) (string, error) {
+	var label [32]C.uchar
+	if err := check(C.ck_get_token_label(c.m, C.ck_ulong(slot), &label[0])); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(C.GoStringN((*C.char)(unsafe.Pointer(&label[0])), 32), " \x00"), nil
+}
+
+func (c *cModule) openSession(slot uint) (uint, error) {
+	var session C.ck_ulong
+	if err := check(C.ck_open_session(c.m, C.ck_ulong(slot), &session)); err != nil {
+		return 0, err
+	}
+	return uint(session), nil
+}
+
+func (c *cModule) closeSession(session uint) error {
+	return check(C.ck_close_session(c.m, C.ck_ulong(session)))
+}
+
+func (c *cModule) login(session uint, pin string) error {
+	cpin := C.CString(pin)
+	defer C.free(unsafe.Pointer(cpin))
+	return check(C.ck_login(c.m, C.ck_ulong(session), (*C.uchar)(unsafe.Pointer(cpin)), C.ck_ulong(len(pin))))
+}
+
+func (c *cModule) findKeys(session uint, class uint, id []byte) ([]uint, error) {
+	var cid *C.uchar
+	if id != nil {
+		cid = (*C.uchar)(C.CBytes(id))
+		defer C.free(unsafe.Pointer(cid))
+	}
+	list := make([]C.ck_ulong, maxObjects)
+	var count C.ck_ulong
+	if err := check(C.ck_find_keys(c.m, C.ck_ulong(session), C.ck_ulong(class), cid, C.ck_ulong(len(id)), &list[0], maxObjects, &count)); err != nil {
+		return nil, err
+	}
+	keys := make([]uint, count)
+	for i := range keys {
+		keys[i] = uint(list[i])
+	}
+	return keys, nil
+}
+
+// attributes returns the values of the attributes, which are nil if they are
+// not available.
+func (c *cModule) attributes(session uint, object uint, types ...uint) ([][]byte, error) {
+	values := make([][]byte, len(types))
+	for i, typ := range types {
+		var length C.ck_ulong
+		err := check(C.ck_get_attribute(c.m, C.ck_ulong(session), C.ck_ulong(object), C.ck_ulong(typ), nil, &length))
+		if err == errAttributeSensitive || err == errAttributeTypeInvalid || length == ^C.ck_ulong(0) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if length == 0 {
+			values[i] = []byte{}
+			continue
+		}
+		value := make([]byte, length)
+		if err := check(C.ck_get_attribute(c.m, C.ck_ulong(session), C.ck_ulong(object), C.ck_ulong(typ), (*C.uchar)(unsafe.Pointer(&value[0])), &length)); err != nil {
+			return nil, err
+		}
+		values[i] = value[:length]
+	}
+	return values, nil
+}
+
+func (c *cModule) sign(session uint, key uint, digest []byte) ([]byte, error) {
+	sig := make([]byte, 256)
+	length := C.ck_ulong(len(sig))
+	if err := check(C.ck_sign(c.m, C.ck_ulong(session), C.ck_ulong(key), (*C.uchar)(unsafe.Pointer(&digest[0])), C.ck_ulong(len(digest)), (*C.uchar)(unsafe.Pointer(&sig[0])), &length)); err != nil {
+		return nil, err
+	}
+	return sig[:length], nil
+}
@@ -0,0 +1,28 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !cgo || windows
+// +build !cgo windows
+
+package pkcs11
+
+import "errors"
+
+// openModule fails since the PKCS#11 modules are loaded by cgo, and the packed
+// structures of PKCS#11 on Windows are not supported.
+func openModule(path string) (module, error) {
+	return nil, errors.New("PKCS#11 is not supported in this build")
+}
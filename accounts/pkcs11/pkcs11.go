@@ -0,0 +1,487 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package pkcs11
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klaytn/klaytn"
+	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/event"
+	"github.com/klaytn/klaytn/log"
+)
+
+// URLScheme is the scheme of the URLs of the PKCS#11 wallets.
+const URLScheme = "pkcs11"
+
+// BackendType is the reflect type of the PKCS#11 backend.
+var BackendType = reflect.TypeOf(&Backend{})
+
+var logger = log.NewModuleLogger(log.AccountsPKCS11)
+
+var (
+	errChainIDNil          = errors.New("chain ID should not be nil")
+	errInvalidSignature    = errors.New("invalid signature from the PKCS#11 token")
+	errUnknownConsensusKey = errors.New("unknown consensus key of the PKCS#11 token")
+	errNoToken             = errors.New("no PKCS#11 token in the slot")
+
+	oidSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// Config is the configuration of the PKCS#11 token, enabled by its module.
+type Config struct {
+	Module       string `toml:",omitempty"` // Path of the PKCS#11 module of the HSM
+	Slot         uint   `toml:",omitempty"` // ID of the slot of the token, if the token label is not given
+	TokenLabel   string `toml:",omitempty"` // Label of the token, choosing its slot
+	PINFile      string `toml:",omitempty"` // File of the PIN of the user of the token
+	ConsensusKey string `toml:",omitempty"` // Label of the key signing the consensus messages
+}
+
+// Enabled returns true if a PKCS#11 module is configured.
+func (c Config) Enabled() bool {
+	return c.Module != ""
+}
+
+// The object classes and the attributes of PKCS#11 used by the backend.
+const (
+	classPublicKey  = 0x2
+	classPrivateKey = 0x3
+
+	attrLabel    = 0x3
+	attrID       = 0x102
+	attrECParams = 0x180
+	attrECPoint  = 0x181
+)
+
+// module is the binding of the functions of a PKCS#11 module. The keys found
+// by findKeys are the EC keys of the class, with the ID if it is not nil.
+type module interface {
+	slots() ([]uint, error)
+	tokenLabel(slot uint) (string, error)
+	openSession(slot uint) (uint, error)
+	closeSession(session uint) error
+	login(session uint, pin string) error
+	findKeys(session uint, class uint, id []byte) ([]uint, error)
+	attributes(session uint, object uint, types ...uint) ([][]byte, error)
+	sign(session uint, key uint, digest []byte) ([]byte, error)
+}
+
+// Error is an error code returned by a PKCS#11 function.
+type Error uint
+
+// The error codes handled by the backend.
+const (
+	errDeviceRemoved         Error = 0x32
+	errSessionClosed         Error = 0xB0
+	errSessionHandleInvalid  Error = 0xB3
+	errTokenNotPresent       Error = 0xE0
+	errUserAlreadyLoggedIn   Error = 0x100
+	errUserNotLoggedIn       Error = 0x101
+	errCryptokiAlreadyInited Error = 0x191
+)
+
+func (e Error) Error() string {
+	return fmt.Sprintf("pkcs11: error 0x%X", uint(e))
+}
+
+// sessionLost returns true if the error means that the session should be
+// opened again, as the HSM may close it on a failover or a timeout.
+func sessionLost(err error) bool {
+	var code Error
+	if !errors.As(err, &code) {
+		return false
+	}
+	switch code {
+	case errDeviceRemoved, errSessionClosed, errSessionHandleInvalid, errTokenNotPresent, errUserNotLoggedIn:
+		return true
+	}
+	return false
+}
+
+// token is a logged in session of a PKCS#11 token, serializing its use since
+// a PKCS#11 session can't be used concurrently.
+type token struct {
+	mod  module
+	slot uint
+	name string
+	pin  string
+
+	mu      sync.Mutex
+	session uint
+	open    bool
+}
+
+// openToken opens a session of the token chosen by the configuration, and logs
+// in as its user.
+func openToken(mod module, config Config) (*token, error) {
+	pin, err := ioutil.ReadFile(config.PINFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the PIN of the PKCS#11 token: %v", err)
+	}
+	t := &token{mod: mod, slot: config.Slot, pin: strings.TrimRight(string(pin), "\r\n")}
+	slots, err := mod.slots()
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, slot := range slots {
+		label, err := mod.tokenLabel(slot)
+		if err != nil {
+			return nil, err
+		}
+		if (config.TokenLabel != "" && label == config.TokenLabel) || (config.TokenLabel == "" && slot == config.Slot) {
+			t.slot, t.name, found = slot, label, true
+			break
+		}
+	}
+	if !found {
+		if config.TokenLabel != "" {
+			return nil, fmt.Errorf("%w: token %q", errNoToken, config.TokenLabel)
+		}
+		return nil, fmt.Errorf("%w: slot %d", errNoToken, config.Slot)
+	}
+	if t.name == "" {
+		t.name = strconv.FormatUint(uint64(t.slot), 10)
+	}
+	if err := t.reopen(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// reopen opens a new session of the token and logs in. The caller holds the
+// lock, unless the token is being opened.
+func (t *token) reopen() error {
+	if t.open {
+		t.mod.closeSession(t.session)
+		t.open = false
+	}
+	session, err := t.mod.openSession(t.slot)
+	if err != nil {
+		return err
+	}
+	if err := t.mod.login(session, t.pin); err != nil && !errors.Is(err, errUserAlreadyLoggedIn) {
+		t.mod.closeSession(session)
+		return fmt.Errorf("failed to log in to the PKCS#11 token: %w", err)
+	}
+	t.session, t.open = session, true
+	return nil
+}
+
+// do runs fn with the session, opening a new session and running it again if
+// the session has been lost.
+func (t *token) do(fn func(session uint) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.open {
+		if err := t.reopen(); err != nil {
+			return err
+		}
+	}
+	err := fn(t.session)
+	if sessionLost(err) {
+		logger.Warn("Opening a new session of the PKCS#11 token", "token", t.name, "err", err)
+		if err := t.reopen(); err != nil {
+			return err
+		}
+		err = fn(t.session)
+	}
+	return err
+}
+
+// tokenKey is a secp256k1 private key of the token with its public key.
+type tokenKey struct {
+	label  string
+	handle uint
+	pubkey *ecdsa.PublicKey
+}
+
+// keys returns the secp256k1 private keys of the token, whose public keys are
+// read from the public keys of the same IDs if the private keys don't have them.
+func (t *token) keys() ([]tokenKey, error) {
+	var keys []tokenKey
+	err := t.do(func(session uint) error {
+		keys = nil
+		handles, err := t.mod.findKeys(session, classPrivateKey, nil)
+		if err != nil {
+			return err
+		}
+		for _, handle := range handles {
+			attrs, err := t.mod.attributes(session, handle, attrLabel, attrID, attrECParams, attrECPoint)
+			if err != nil {
+				return err
+			}
+			label, id, params, point := string(attrs[0]), attrs[1], attrs[2], attrs[3]
+			var curve asn1.ObjectIdentifier
+			if _, err := asn1.Unmarshal(params, &curve); err != nil || !curve.Equal(oidSecp256k1) {
+				logger.Warn("Ignored a PKCS#11 key which is not a secp256k1 key", "token", t.name, "key", label)
+				continue
+			}
+			if point == nil && id != nil {
+				pubs, err := t.mod.findKeys(session, classPublicKey, id)
+				if err != nil {
+					return err
+				}
+				if len(pubs) > 0 {
+					attrs, err := t.mod.attributes(session, pubs[0], attrECPoint)
+					if err != nil {
+						return err
+					}
+					point = attrs[0]
+				}
+			}
+			pubkey, err := parseECPoint(point)
+			if err != nil {
+				logger.Warn("Ignored a PKCS#11 key without its public key", "token", t.name, "key", label, "err", err)
+				continue
+			}
+			keys = append(keys, tokenKey{label: label, handle: handle, pubkey: pubkey})
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// parseECPoint parses the CKA_EC_POINT attribute, which is the DER encoded
+// octet string of the uncompressed point, or the point itself on some HSMs.
+func parseECPoint(point []byte) (*ecdsa.PublicKey, error) {
+	var raw []byte
+	if rest, err := asn1.Unmarshal(point, &raw); err == nil && len(rest) == 0 {
+		point = raw
+	}
+	return crypto.UnmarshalPubkey(point)
+}
+
+// Backend is the accounts.Backend of the keys of a PKCS#11 token.
+type Backend struct {
+	wallets      []accounts.Wallet
+	consensusKey string
+	feed         event.Feed
+}
+
+// NewBackend loads the PKCS#11 module, logs in to the token and returns a
+// backend of the wallets of its secp256k1 keys.
+func NewBackend(config Config) (*Backend, error) {
+	mod, err := openModule(config.Module)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the PKCS#11 module %s: %v", config.Module, err)
+	}
+	return newBackend(mod, config)
+}
+
+func newBackend(mod module, config Config) (*Backend, error) {
+	t, err := openToken(mod, config)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := t.keys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the keys of the PKCS#11 token: %v", err)
+	}
+	b := &Backend{consensusKey: config.ConsensusKey}
+	for _, key := range keys {
+		url := accounts.URL{Scheme: URLScheme, Path: t.name + "/" + key.label}
+		w := &wallet{
+			url:     url,
+			account: accounts.Account{Address: crypto.PubkeyToAddress(*key.pubkey), URL: url},
+			token:   t,
+			label:   key.label,
+			handle:  key.handle,
+			pubkey:  key.pubkey,
+		}
+		logger.Info("Loaded a PKCS#11 account", "url", w.url, "address", w.account.Address)
+		b.wallets = append(b.wallets, w)
+	}
+	sort.Slice(b.wallets, func(i, j int) bool { return b.wallets[i].URL().Cmp(b.wallets[j].URL()) < 0 })
+	return b, nil
+}
+
+// Wallets implements accounts.Backend, returning the wallets of the keys.
+func (b *Backend) Wallets() []accounts.Wallet {
+	cpy := make([]accounts.Wallet, len(b.wallets))
+	copy(cpy, b.wallets)
+	return cpy
+}
+
+// Subscribe implements accounts.Backend. The wallets are not changed after
+// the discovery, so no event is sent.
+func (b *Backend) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	return b.feed.Subscribe(sink)
+}
+
+// ConsensusSigner returns the address of the consensus key and the function
+// signing the hashes of the consensus messages with it. The function is nil if
+// no consensus key is configured.
+func (b *Backend) ConsensusSigner() (common.Address, func(hash []byte) ([]byte, error), error) {
+	if b.consensusKey == "" {
+		return common.Address{}, nil, nil
+	}
+	for _, w := range b.wallets {
+		if w := w.(*wallet); w.label == b.consensusKey {
+			return w.account.Address, w.sign, nil
+		}
+	}
+	return common.Address{}, nil, fmt.Errorf("%w: %s", errUnknownConsensusKey, b.consensusKey)
+}
+
+// wallet is the accounts.Wallet of a key of the PKCS#11 token.
+type wallet struct {
+	url     accounts.URL
+	account accounts.Account
+	token   *token
+	label   string
+	handle  uint
+	pubkey  *ecdsa.PublicKey
+}
+
+func (w *wallet) URL() accounts.URL { return w.url }
+
+// Status implements accounts.Wallet. The session of the token is opened again
+// if it is lost, so the wallet is always online.
+func (w *wallet) Status() (string, error) { return "Online", nil }
+
+func (w *wallet) Open(passphrase string) error { return nil }
+
+func (w *wallet) Close() error { return nil }
+
+func (w *wallet) Accounts() []accounts.Account { return []accounts.Account{w.account} }
+
+func (w *wallet) Contains(account accounts.Account) bool {
+	return account.Address == w.account.Address && (account.URL == (accounts.URL{}) || account.URL == w.url)
+}
+
+func (w *wallet) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	return accounts.Account{}, accounts.ErrNotSupported
+}
+
+func (w *wallet) SelfDerive(base accounts.DerivationPath, chain klaytn.ChainReader) {}
+
+// sign signs the hash with the key by CKM_ECDSA, and returns the signature in
+// the [R || S || V] format where V is 0 or 1.
+func (w *wallet) sign(hash []byte) ([]byte, error) {
+	if len(hash) != crypto.DigestLength {
+		return nil, fmt.Errorf("hash is required to be exactly %d bytes (%d)", crypto.DigestLength, len(hash))
+	}
+	var rs []byte
+	err := w.token.do(func(session uint) error {
+		var err error
+		rs, err = w.token.mod.sign(session, w.handle, hash)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recoverableSignature(rs, hash, w.pubkey)
+}
+
+// recoverableSignature converts the R and S values returned by CKM_ECDSA into
+// the [R || S || V] format with the lower S value, finding V by recovering the
+// public key.
+func recoverableSignature(rs, hash []byte, pubkey *ecdsa.PublicKey) ([]byte, error) {
+	if len(rs) != 64 {
+		return nil, errInvalidSignature
+	}
+	r, s := new(big.Int).SetBytes(rs[:32]), new(big.Int).SetBytes(rs[32:])
+	n := crypto.S256().Params().N
+	if r.Sign() <= 0 || s.Sign() <= 0 || r.Cmp(n) >= 0 || s.Cmp(n) >= 0 {
+		return nil, errInvalidSignature
+	}
+	if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		s.Sub(n, s)
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+	expected := crypto.FromECDSAPub(pubkey)
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		if recovered, err := crypto.Ecrecover(hash, sig); err == nil && bytes.Equal(recovered, expected) {
+			return sig, nil
+		}
+	}
+	return nil, errInvalidSignature
+}
+
+// SignHash signs the hash with the key of the token.
+func (w *wallet) SignHash(account accounts.Account, hash []byte) ([]byte, error) {
+	if !w.Contains(account) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	return w.sign(hash)
+}
+
+// SignTx signs the transaction with the key of the token.
+func (w *wallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if chainID == nil {
+		return nil, errChainIDNil
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	hash := signer.Hash(tx)
+	sig, err := w.SignHash(account, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signer, sig)
+}
+
+// SignTxAsFeePayer signs the transaction as a fee payer with the key of the token.
+func (w *wallet) SignTxAsFeePayer(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if chainID == nil {
+		return nil, errChainIDNil
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	hash, err := signer.HashFeePayer(tx)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := w.SignHash(account, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithFeePayerSignature(signer, sig)
+}
+
+// SignHashWithPassphrase implements accounts.Wallet. The passphrase is ignored
+// since the token is logged in with its PIN.
+func (w *wallet) SignHashWithPassphrase(account accounts.Account, passphrase string, hash []byte) ([]byte, error) {
+	return w.SignHash(account, hash)
+}
+
+// SignTxWithPassphrase implements accounts.Wallet, ignoring the passphrase.
+func (w *wallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return w.SignTx(account, tx, chainID)
+}
+
+// SignTxAsFeePayerWithPassphrase implements accounts.Wallet, ignoring the passphrase.
+func (w *wallet) SignTxAsFeePayerWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return w.SignTxAsFeePayer(account, tx, chainID)
+}
@@ -0,0 +1,258 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package pkcs11
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeObject is an object of fakeModule.
+type fakeObject struct {
+	class uint
+	attrs map[uint][]byte
+	key   *ecdsa.PrivateKey
+}
+
+// fakeModule is a token in the slot 7 holding the keys in memory, returning
+// the high S values and closing the sessions as a HSM may do.
+type fakeModule struct {
+	pin      string
+	objects  []fakeObject
+	sessions map[uint]bool
+	next     uint
+	signs    int
+}
+
+func newFakeModule(t *testing.T, labels ...string) *fakeModule {
+	m := &fakeModule{pin: "1234", sessions: make(map[uint]bool)}
+	params, _ := asn1.Marshal(oidSecp256k1)
+	for i, label := range labels {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		id := []byte{byte(i)}
+		point, _ := asn1.Marshal(crypto.FromECDSAPub(&key.PublicKey))
+		m.objects = append(m.objects,
+			fakeObject{class: classPrivateKey, key: key, attrs: map[uint][]byte{attrLabel: []byte(label), attrID: id, attrECParams: params}},
+			fakeObject{class: classPublicKey, attrs: map[uint][]byte{attrLabel: []byte(label), attrID: id, attrECParams: params, attrECPoint: point}})
+	}
+	// A P-256 key is ignored.
+	p256, _ := asn1.Marshal(asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7})
+	m.objects = append(m.objects, fakeObject{class: classPrivateKey, attrs: map[uint][]byte{attrLabel: []byte("p256"), attrECParams: p256}})
+	return m
+}
+
+func (m *fakeModule) slots() ([]uint, error) { return []uint{3, 7}, nil }
+
+func (m *fakeModule) tokenLabel(slot uint) (string, error) {
+	if slot == 7 {
+		return "validator", nil
+	}
+	return "", nil
+}
+
+func (m *fakeModule) openSession(slot uint) (uint, error) {
+	m.next++
+	m.sessions[m.next] = false
+	return m.next, nil
+}
+
+func (m *fakeModule) closeSession(session uint) error {
+	delete(m.sessions, session)
+	return nil
+}
+
+func (m *fakeModule) login(session uint, pin string) error {
+	if pin != m.pin {
+		return Error(0xA0) // CKR_PIN_INCORRECT
+	}
+	m.sessions[session] = true
+	return nil
+}
+
+func (m *fakeModule) loggedIn(session uint) error {
+	if loggedIn, ok := m.sessions[session]; !ok {
+		return errSessionHandleInvalid
+	} else if !loggedIn {
+		return errUserNotLoggedIn
+	}
+	return nil
+}
+
+func (m *fakeModule) findKeys(session uint, class uint, id []byte) ([]uint, error) {
+	if err := m.loggedIn(session); err != nil {
+		return nil, err
+	}
+	var handles []uint
+	for i, obj := range m.objects {
+		if obj.class == class && (id == nil || bytes.Equal(obj.attrs[attrID], id)) {
+			handles = append(handles, uint(i))
+		}
+	}
+	return handles, nil
+}
+
+func (m *fakeModule) attributes(session uint, object uint, types ...uint) ([][]byte, error) {
+	if err := m.loggedIn(session); err != nil {
+		return nil, err
+	}
+	values := make([][]byte, len(types))
+	for i, typ := range types {
+		values[i] = m.objects[object].attrs[typ]
+	}
+	return values, nil
+}
+
+func (m *fakeModule) sign(session uint, key uint, digest []byte) ([]byte, error) {
+	if err := m.loggedIn(session); err != nil {
+		return nil, err
+	}
+	m.signs++
+	sig, err := crypto.Sign(digest, m.objects[key].key)
+	if err != nil {
+		return nil, err
+	}
+	s := new(big.Int).SetBytes(sig[32:64])
+	s.Sub(crypto.S256().Params().N, s).FillBytes(sig[32:64])
+	return sig[:64], nil
+}
+
+func newTestConfig(t *testing.T) (Config, func()) {
+	dir, err := ioutil.TempDir("", "klaytn-pkcs11-test")
+	require.NoError(t, err)
+	pinFile := filepath.Join(dir, "pin")
+	require.NoError(t, ioutil.WriteFile(pinFile, []byte("1234\n"), 0o600))
+	return Config{TokenLabel: "validator", PINFile: pinFile, ConsensusKey: "node"}, func() { os.RemoveAll(dir) }
+}
+
+func TestBackend(t *testing.T) {
+	config, cleanup := newTestConfig(t)
+	defer cleanup()
+	m := newFakeModule(t, "operator", "node")
+
+	b, err := newBackend(m, config)
+	require.NoError(t, err)
+	require.Len(t, b.Wallets(), 2)
+
+	w := b.Wallets()[1]
+	assert.Equal(t, accounts.URL{Scheme: URLScheme, Path: "validator/operator"}, w.URL())
+	account := w.Accounts()[0]
+	assert.Equal(t, crypto.PubkeyToAddress(m.objects[0].key.PublicKey), account.Address)
+
+	chainID := big.NewInt(1001)
+	tx, err := types.NewTransactionWithMap(types.TxTypeFeeDelegatedValueTransfer, map[types.TxValueKeyType]interface{}{
+		types.TxValueKeyNonce:    uint64(0),
+		types.TxValueKeyFrom:     account.Address,
+		types.TxValueKeyTo:       common.HexToAddress("0x1234"),
+		types.TxValueKeyAmount:   big.NewInt(1),
+		types.TxValueKeyGasLimit: uint64(100000),
+		types.TxValueKeyGasPrice: big.NewInt(25e9),
+		types.TxValueKeyFeePayer: account.Address,
+	})
+	require.NoError(t, err)
+	signed, err := w.SignTx(account, tx, chainID)
+	require.NoError(t, err)
+	pubs, err := types.SenderPubkey(types.LatestSignerForChainID(chainID), signed)
+	require.NoError(t, err)
+	assert.Equal(t, account.Address, crypto.PubkeyToAddress(*pubs[0]))
+
+	signed, err = w.SignTxAsFeePayer(account, signed, chainID)
+	require.NoError(t, err)
+	pubs, err = types.SenderFeePayerPubkey(types.LatestSignerForChainID(chainID), signed)
+	require.NoError(t, err)
+	assert.Equal(t, account.Address, crypto.PubkeyToAddress(*pubs[0]))
+
+	_, err = w.SignHash(accounts.Account{Address: common.HexToAddress("0x1234")}, make([]byte, 32))
+	assert.Equal(t, accounts.ErrUnknownAccount, err)
+
+	// The consensus messages are signed with the consensus key.
+	addr, sign, err := b.ConsensusSigner()
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(m.objects[2].key.PublicKey), addr)
+	hash := crypto.Keccak256([]byte("message"))
+	sig, err := sign(hash)
+	require.NoError(t, err)
+	pub, err := crypto.SigToPub(hash, sig)
+	require.NoError(t, err)
+	assert.Equal(t, addr, crypto.PubkeyToAddress(*pub))
+}
+
+func TestBackend_SessionLost(t *testing.T) {
+	config, cleanup := newTestConfig(t)
+	defer cleanup()
+	m := newFakeModule(t, "node")
+
+	b, err := newBackend(m, config)
+	require.NoError(t, err)
+	w := b.Wallets()[0]
+
+	// A new session is opened if the session is closed by the HSM.
+	for session := range m.sessions {
+		m.closeSession(session)
+	}
+	_, err = w.SignHash(w.Accounts()[0], make([]byte, 32))
+	require.NoError(t, err)
+	assert.Equal(t, 1, m.signs)
+	assert.Len(t, m.sessions, 1)
+
+	// The errors of the login are returned.
+	m.pin = "5678"
+	for session := range m.sessions {
+		m.closeSession(session)
+	}
+	_, err = w.SignHash(w.Accounts()[0], make([]byte, 32))
+	assert.ErrorIs(t, err, Error(0xA0))
+}
+
+func TestBackend_Token(t *testing.T) {
+	config, cleanup := newTestConfig(t)
+	defer cleanup()
+	m := newFakeModule(t, "node")
+
+	// The token is chosen by its slot if its label is not given.
+	config.TokenLabel, config.Slot = "", 3
+	b, err := newBackend(m, config)
+	require.NoError(t, err)
+	assert.Equal(t, accounts.URL{Scheme: URLScheme, Path: "3/node"}, b.Wallets()[0].URL())
+
+	config.Slot = 5
+	_, err = newBackend(m, config)
+	assert.ErrorIs(t, err, errNoToken)
+
+	config.TokenLabel = "unknown"
+	_, err = newBackend(m, config)
+	assert.ErrorIs(t, err, errNoToken)
+
+	config.TokenLabel, config.ConsensusKey = "validator", "unknown"
+	b, err = newBackend(m, config)
+	require.NoError(t, err)
+	_, _, err = b.ConsensusSigner()
+	assert.ErrorIs(t, err, errUnknownConsensusKey)
+}
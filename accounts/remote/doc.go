@@ -0,0 +1,49 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+/*
+Package remote implements the accounts whose keys are held by a remote signer,
+e.g. the MPC or threshold signing service of a custody provider, so the node
+signs without embedding the SDK of the provider.
+
+The remote signer serves the following JSON API over HTTPS, optionally
+authenticating the node by a TLS client certificate:
+
+	POST <url>/v1/keys  {"nonce"}
+	  -> {"keys": [{"id", "publicKey"}], "attestation"}
+	POST <url>/v1/sign  {"keyId", "purpose", "digest", "payload", "tx", "chainId", "nonce"}
+	  -> {"signature", "attestation"}
+
+The public keys are uncompressed secp256k1 keys and the signatures are in the
+[R || S || V] format, all hex encoded with the 0x prefix. The purpose is
+"transaction", "feePayer", "hash" or "consensus". The payload of a transaction
+is the RLP encoding whose keccak256 hash is the digest, and the tx is the
+canonical encoding of the unsigned transaction, so the signer can apply its
+policies to the transaction before signing.
+
+The attestation is the Ed25519 signature, by the attestation key of the signer,
+of the keccak256 hash of the response bound to the random nonce of the request
+(see keysStatement and signStatement). The node checks it with the configured
+attestation public key, so a response is known to come from the attested
+signer, e.g. an enclave, rather than from anything on the network path.
+
+The key given as the consensus key signs the consensus messages, and its
+address is the validator address of the node. The node key remains the
+identity of the p2p connections; the peers learn the validator address from
+its signature over the node ID, exchanged after the klay/67 handshake, so the
+consensus key may stay in the signer, e.g. sharded by the MPC provider.
+*/
+package remote
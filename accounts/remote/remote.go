@@ -0,0 +1,444 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klaytn/klaytn"
+	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/event"
+	"github.com/klaytn/klaytn/log"
+)
+
+// URLScheme is the scheme of the URLs of the remote signer wallets.
+const URLScheme = "remote"
+
+// The purposes of the signing requests.
+const (
+	PurposeTransaction = "transaction"
+	PurposeFeePayer    = "feePayer"
+	PurposeHash        = "hash"
+	PurposeConsensus   = "consensus"
+)
+
+// requestTimeout is the timeout of a request to the remote signer, which may
+// wait for the parties of an MPC protocol.
+const requestTimeout = 30 * time.Second
+
+// BackendType is the reflect type of the remote signer backend.
+var BackendType = reflect.TypeOf(&Backend{})
+
+var logger = log.NewModuleLogger(log.AccountsRemote)
+
+var (
+	errChainIDNil          = errors.New("chain ID should not be nil")
+	errInvalidSignature    = errors.New("invalid signature from the remote signer")
+	errInvalidAttestation  = errors.New("invalid attestation from the remote signer")
+	errUnknownConsensusKey = errors.New("unknown consensus key of the remote signer")
+)
+
+// Config is the configuration of the remote signer, enabled by its URL.
+type Config struct {
+	URL            string `toml:",omitempty"` // Base URL of the remote signer API
+	AttestationKey string `toml:",omitempty"` // Hex encoded Ed25519 public key attesting the responses
+	CAFile         string `toml:",omitempty"` // CA certificates of the signer, if not the system ones
+	CertFile       string `toml:",omitempty"` // TLS client certificate of the node
+	KeyFile        string `toml:",omitempty"` // TLS client key of the node
+	ConsensusKey   string `toml:",omitempty"` // ID of the key signing the consensus messages
+}
+
+// Enabled returns true if a remote signer is configured.
+func (c Config) Enabled() bool {
+	return c.URL != ""
+}
+
+// keysRequest and keysResponse are the messages of <url>/v1/keys.
+type keysRequest struct {
+	Nonce hexutil.Bytes `json:"nonce"`
+}
+
+type remoteKey struct {
+	ID        string        `json:"id"`
+	PublicKey hexutil.Bytes `json:"publicKey"`
+}
+
+type keysResponse struct {
+	Keys        []remoteKey   `json:"keys"`
+	Attestation hexutil.Bytes `json:"attestation"`
+}
+
+// signRequest and signResponse are the messages of <url>/v1/sign.
+type signRequest struct {
+	KeyID   string        `json:"keyId"`
+	Purpose string        `json:"purpose"`
+	Digest  hexutil.Bytes `json:"digest"`
+	Payload hexutil.Bytes `json:"payload,omitempty"`
+	Tx      hexutil.Bytes `json:"tx,omitempty"`
+	ChainID *hexutil.Big  `json:"chainId,omitempty"`
+	Nonce   hexutil.Bytes `json:"nonce"`
+}
+
+type signResponse struct {
+	Signature   hexutil.Bytes `json:"signature"`
+	Attestation hexutil.Bytes `json:"attestation"`
+}
+
+// keysStatement returns the hash attested for a keys response.
+func keysStatement(nonce []byte, keys []remoteKey) []byte {
+	data := [][]byte{[]byte("klaytn-remote-signer/keys"), nonce}
+	for _, key := range keys {
+		data = append(data, []byte(key.ID), key.PublicKey)
+	}
+	return crypto.Keccak256(data...)
+}
+
+// signStatement returns the hash attested for a sign response.
+func signStatement(nonce []byte, req *signRequest, signature []byte) []byte {
+	return crypto.Keccak256([]byte("klaytn-remote-signer/sign"), nonce, []byte(req.KeyID), []byte(req.Purpose), req.Digest, signature)
+}
+
+// client is the client of the remote signer API.
+type client struct {
+	url            string
+	http           *http.Client
+	attestationKey ed25519.PublicKey
+}
+
+func newClient(config Config) (*client, error) {
+	attestationKey, err := hexutil.Decode(config.AttestationKey)
+	if err != nil || len(attestationKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid attestation key of the remote signer %q", config.AttestationKey)
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.CAFile != "" {
+		pem, err := ioutil.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate in %s", config.CAFile)
+		}
+	}
+	if config.CertFile != "" || config.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return &client{
+		url:            strings.TrimSuffix(config.URL, "/"),
+		http:           &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment}},
+		attestationKey: attestationKey,
+	}, nil
+}
+
+func (c *client) call(ctx context.Context, path string, req, resp interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+	respBody, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return err
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("remote signer %s: %s: %s", path, httpResp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return json.Unmarshal(respBody, resp)
+}
+
+func newNonce() ([]byte, error) {
+	nonce := make([]byte, 32)
+	_, err := rand.Read(nonce)
+	return nonce, err
+}
+
+// keys returns the attested keys of the remote signer.
+func (c *client) keys(ctx context.Context) ([]remoteKey, error) {
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, err
+	}
+	var resp keysResponse
+	if err := c.call(ctx, "/v1/keys", &keysRequest{Nonce: nonce}, &resp); err != nil {
+		return nil, err
+	}
+	if !ed25519.Verify(c.attestationKey, keysStatement(nonce, resp.Keys), resp.Attestation) {
+		return nil, errInvalidAttestation
+	}
+	return resp.Keys, nil
+}
+
+// sign returns the attested signature of the digest of the request.
+func (c *client) sign(ctx context.Context, req *signRequest) ([]byte, error) {
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, err
+	}
+	req.Nonce = nonce
+	var resp signResponse
+	if err := c.call(ctx, "/v1/sign", req, &resp); err != nil {
+		return nil, err
+	}
+	if !ed25519.Verify(c.attestationKey, signStatement(nonce, req, resp.Signature), resp.Attestation) {
+		return nil, errInvalidAttestation
+	}
+	return resp.Signature, nil
+}
+
+// Backend is the accounts.Backend of the keys of the remote signer.
+type Backend struct {
+	wallets      []accounts.Wallet
+	consensusKey string
+	feed         event.Feed
+}
+
+// NewBackend discovers the keys of the remote signer, and returns a backend of
+// their wallets.
+func NewBackend(config Config) (*Backend, error) {
+	c, err := newClient(config)
+	if err != nil {
+		return nil, err
+	}
+	return newBackend(c, config.ConsensusKey)
+}
+
+func newBackend(c *client, consensusKey string) (*Backend, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	keys, err := c.keys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover the keys of the remote signer: %v", err)
+	}
+	b := &Backend{consensusKey: consensusKey}
+	for _, key := range keys {
+		pubkey, err := crypto.UnmarshalPubkey(key.PublicKey)
+		if err != nil {
+			logger.Warn("Ignored a remote signer key which is not a secp256k1 key", "key", key.ID, "err", err)
+			continue
+		}
+		url := accounts.URL{Scheme: URLScheme, Path: key.ID}
+		w := &wallet{
+			url:     url,
+			account: accounts.Account{Address: crypto.PubkeyToAddress(*pubkey), URL: url},
+			client:  c,
+			keyID:   key.ID,
+			pubkey:  pubkey,
+		}
+		logger.Info("Loaded a remote signer account", "url", w.url, "address", w.account.Address)
+		b.wallets = append(b.wallets, w)
+	}
+	sort.Slice(b.wallets, func(i, j int) bool { return b.wallets[i].URL().Cmp(b.wallets[j].URL()) < 0 })
+	return b, nil
+}
+
+// Wallets implements accounts.Backend, returning the wallets of the keys.
+func (b *Backend) Wallets() []accounts.Wallet {
+	cpy := make([]accounts.Wallet, len(b.wallets))
+	copy(cpy, b.wallets)
+	return cpy
+}
+
+// Subscribe implements accounts.Backend. The wallets are not changed after
+// the discovery, so no event is sent.
+func (b *Backend) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	return b.feed.Subscribe(sink)
+}
+
+// ConsensusSigner returns the address of the consensus key and the function
+// signing the hashes of the consensus messages with it. The function is nil if
+// no consensus key is configured.
+func (b *Backend) ConsensusSigner() (common.Address, func(hash []byte) ([]byte, error), error) {
+	if b.consensusKey == "" {
+		return common.Address{}, nil, nil
+	}
+	for _, w := range b.wallets {
+		if w := w.(*wallet); w.keyID == b.consensusKey {
+			return w.account.Address, func(hash []byte) ([]byte, error) {
+				return w.sign(&signRequest{Purpose: PurposeConsensus, Digest: hash})
+			}, nil
+		}
+	}
+	return common.Address{}, nil, fmt.Errorf("%w: %s", errUnknownConsensusKey, b.consensusKey)
+}
+
+// wallet is the accounts.Wallet of a key of the remote signer.
+type wallet struct {
+	url     accounts.URL
+	account accounts.Account
+	client  *client
+	keyID   string
+	pubkey  *ecdsa.PublicKey
+}
+
+func (w *wallet) URL() accounts.URL { return w.url }
+
+// Status implements accounts.Wallet. The remote signer is reached on each
+// signature.
+func (w *wallet) Status() (string, error) { return "Online", nil }
+
+func (w *wallet) Open(passphrase string) error { return nil }
+
+func (w *wallet) Close() error { return nil }
+
+func (w *wallet) Accounts() []accounts.Account { return []accounts.Account{w.account} }
+
+func (w *wallet) Contains(account accounts.Account) bool {
+	return account.Address == w.account.Address && (account.URL == (accounts.URL{}) || account.URL == w.url)
+}
+
+func (w *wallet) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	return accounts.Account{}, accounts.ErrNotSupported
+}
+
+func (w *wallet) SelfDerive(base accounts.DerivationPath, chain klaytn.ChainReader) {}
+
+// sign signs the digest of the request by the remote signer, checking that the
+// signature is of the key. The signature is in the [R || S || V] format where V
+// is 0 or 1.
+func (w *wallet) sign(req *signRequest) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	req.KeyID = w.keyID
+	sig, err := w.client.sign(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(sig) != crypto.SignatureLength {
+		return nil, errInvalidSignature
+	}
+	sig = common.CopyBytes(sig)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	recovered, err := crypto.Ecrecover(req.Digest, sig)
+	if err != nil || !bytes.Equal(recovered, crypto.FromECDSAPub(w.pubkey)) {
+		return nil, errInvalidSignature
+	}
+	return sig, nil
+}
+
+// SignHash signs the hash by the remote signer.
+func (w *wallet) SignHash(account accounts.Account, hash []byte) ([]byte, error) {
+	if !w.Contains(account) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	return w.sign(&signRequest{Purpose: PurposeHash, Digest: hash})
+}
+
+// SignTx signs the transaction by the remote signer, giving it the transaction
+// and its signing payload.
+func (w *wallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if !w.Contains(account) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	if chainID == nil {
+		return nil, errChainIDNil
+	}
+	payload, err := types.SigningPayload(tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	txBytes, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	hash := signer.Hash(tx)
+	sig, err := w.sign(&signRequest{Purpose: PurposeTransaction, Digest: hash[:], Payload: payload, Tx: txBytes, ChainID: (*hexutil.Big)(chainID)})
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signer, sig)
+}
+
+// SignTxAsFeePayer signs the transaction as a fee payer by the remote signer.
+func (w *wallet) SignTxAsFeePayer(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if !w.Contains(account) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	if chainID == nil {
+		return nil, errChainIDNil
+	}
+	payload, err := types.FeePayerSigningPayload(tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	txBytes, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	hash, err := signer.HashFeePayer(tx)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := w.sign(&signRequest{Purpose: PurposeFeePayer, Digest: hash[:], Payload: payload, Tx: txBytes, ChainID: (*hexutil.Big)(chainID)})
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithFeePayerSignature(signer, sig)
+}
+
+// SignHashWithPassphrase implements accounts.Wallet. The passphrase is ignored
+// since the use of the key is authorized by the remote signer.
+func (w *wallet) SignHashWithPassphrase(account accounts.Account, passphrase string, hash []byte) ([]byte, error) {
+	return w.SignHash(account, hash)
+}
+
+// SignTxWithPassphrase implements accounts.Wallet, ignoring the passphrase.
+func (w *wallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return w.SignTx(account, tx, chainID)
+}
+
+// SignTxAsFeePayerWithPassphrase implements accounts.Wallet, ignoring the passphrase.
+func (w *wallet) SignTxAsFeePayerWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return w.SignTxAsFeePayer(account, tx, chainID)
+}
@@ -0,0 +1,162 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package remote
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSigner is a remote signer holding the keys in memory.
+type testSigner struct {
+	attestationKey ed25519.PrivateKey
+	keys           map[string]*ecdsa.PrivateKey
+	requests       []signRequest
+}
+
+func (s *testSigner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/v1/keys":
+		var req keysRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var resp keysResponse
+		for id, key := range s.keys {
+			resp.Keys = append(resp.Keys, remoteKey{ID: id, PublicKey: crypto.FromECDSAPub(&key.PublicKey)})
+		}
+		resp.Attestation = ed25519.Sign(s.attestationKey, keysStatement(req.Nonce, resp.Keys))
+		json.NewEncoder(w).Encode(resp)
+	case "/v1/sign":
+		var req signRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		key, ok := s.keys[req.KeyID]
+		if !ok || (req.Payload != nil && crypto.Keccak256Hash(req.Payload) != common.BytesToHash(req.Digest)) {
+			http.Error(w, "refused", http.StatusForbidden)
+			return
+		}
+		s.requests = append(s.requests, req)
+		sig, _ := crypto.Sign(req.Digest, key)
+		json.NewEncoder(w).Encode(signResponse{
+			Signature:   sig,
+			Attestation: ed25519.Sign(s.attestationKey, signStatement(req.Nonce, &req, sig)),
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newTestSigner(t *testing.T) (*testSigner, *httptest.Server, Config) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	s := &testSigner{attestationKey: priv, keys: make(map[string]*ecdsa.PrivateKey)}
+	for _, id := range []string{"operator", "validator"} {
+		s.keys[id], _ = crypto.GenerateKey()
+	}
+	srv := httptest.NewServer(s)
+	return s, srv, Config{URL: srv.URL, AttestationKey: hexutil.Encode(pub), ConsensusKey: "validator"}
+}
+
+func TestBackend(t *testing.T) {
+	s, srv, config := newTestSigner(t)
+	defer srv.Close()
+
+	b, err := NewBackend(config)
+	require.NoError(t, err)
+	require.Len(t, b.Wallets(), 2)
+
+	w := b.Wallets()[0]
+	assert.Equal(t, accounts.URL{Scheme: URLScheme, Path: "operator"}, w.URL())
+	account := w.Accounts()[0]
+	assert.Equal(t, crypto.PubkeyToAddress(s.keys["operator"].PublicKey), account.Address)
+
+	// The transactions are signed with their payloads.
+	chainID := big.NewInt(1001)
+	tx, err := types.NewTransactionWithMap(types.TxTypeFeeDelegatedValueTransfer, map[types.TxValueKeyType]interface{}{
+		types.TxValueKeyNonce:    uint64(0),
+		types.TxValueKeyFrom:     account.Address,
+		types.TxValueKeyTo:       common.HexToAddress("0x1234"),
+		types.TxValueKeyAmount:   big.NewInt(1),
+		types.TxValueKeyGasLimit: uint64(100000),
+		types.TxValueKeyGasPrice: big.NewInt(25e9),
+		types.TxValueKeyFeePayer: account.Address,
+	})
+	require.NoError(t, err)
+	signed, err := w.SignTx(account, tx, chainID)
+	require.NoError(t, err)
+	pubs, err := types.SenderPubkey(types.LatestSignerForChainID(chainID), signed)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(*pubs[0]), account.Address)
+	assert.Equal(t, PurposeTransaction, s.requests[0].Purpose)
+	assert.NotEmpty(t, s.requests[0].Payload)
+
+	signed, err = w.SignTxAsFeePayer(account, signed, chainID)
+	require.NoError(t, err)
+	pubs, err = types.SenderFeePayerPubkey(types.LatestSignerForChainID(chainID), signed)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(*pubs[0]), account.Address)
+	assert.Equal(t, PurposeFeePayer, s.requests[1].Purpose)
+
+	_, err = w.SignHash(accounts.Account{Address: common.HexToAddress("0x1234")}, make([]byte, 32))
+	assert.Equal(t, accounts.ErrUnknownAccount, err)
+
+	// The consensus messages are signed with the consensus key.
+	addr, sign, err := b.ConsensusSigner()
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(s.keys["validator"].PublicKey), addr)
+	hash := crypto.Keccak256([]byte("message"))
+	sig, err := sign(hash)
+	require.NoError(t, err)
+	pub, err := crypto.SigToPub(hash, sig)
+	require.NoError(t, err)
+	assert.Equal(t, addr, crypto.PubkeyToAddress(*pub))
+	assert.Equal(t, PurposeConsensus, s.requests[2].Purpose)
+}
+
+func TestBackend_Attestation(t *testing.T) {
+	s, srv, config := newTestSigner(t)
+	defer srv.Close()
+
+	b, err := NewBackend(config)
+	require.NoError(t, err)
+
+	// The responses attested by another key are refused.
+	_, s.attestationKey, _ = ed25519.GenerateKey(nil)
+	w := b.Wallets()[0]
+	_, err = w.SignHash(w.Accounts()[0], make([]byte, 32))
+	assert.Equal(t, errInvalidAttestation, err)
+
+	_, err = NewBackend(config)
+	assert.Error(t, err)
+}
@@ -0,0 +1,222 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package remote
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/math"
+)
+
+var (
+	errNoRule          = errors.New("no rule for the key")
+	errPurposeDenied   = errors.New("purpose not allowed")
+	errDestination     = errors.New("destination not allowed")
+	errValueCap        = errors.New("value exceeds the cap")
+	errTotalValueCap   = errors.New("total value of the period exceeds the cap")
+	errRateLimit       = errors.New("number of signatures of the period exceeds the limit")
+	errPeriodUndefined = errors.New("period undefined for the limits of the period")
+)
+
+// Rule is the policy of signing with a key. A key signs only the transactions
+// by default, to any destination with any value and without a rate limit.
+type Rule struct {
+	AllowHash      bool                  `json:"allowHash"`      // Sign arbitrary hashes, e.g. personal_sign messages
+	AllowFeePayer  bool                  `json:"allowFeePayer"`  // Sign transactions as a fee payer
+	AllowConsensus bool                  `json:"allowConsensus"` // Sign the consensus messages of a validator
+	AllowedTo      []common.Address      `json:"allowedTo"`      // Allowed destinations of the transactions if not empty
+	MaxValue       *math.HexOrDecimal256 `json:"maxValue"`       // Maximum value of a transaction in peb
+	MaxTotalValue  *math.HexOrDecimal256 `json:"maxTotalValue"`  // Maximum total value of the transactions of a period
+	MaxSignatures  int                   `json:"maxSignatures"`  // Maximum number of the signatures of a period
+	Period         string                `json:"period"`         // Period of the limits, e.g. "24h"
+
+	period time.Duration
+}
+
+// signature is a signature of a key counted in the limits of its period.
+type signature struct {
+	time  time.Time
+	value *big.Int
+}
+
+// Rules approves the signing requests by the rules of the keys, loaded from a
+// JSON policy file such as:
+//
+//	{
+//	  "keys": {
+//	    "0x...": {"allowedTo": ["0x..."], "maxValue": "1000000000000000000", "maxSignatures": 100, "period": "24h"}
+//	  },
+//	  "default": {"allowFeePayer": true, "maxSignatures": 10, "period": "1h"}
+//	}
+//
+// The keys are identified by their IDs, which are their addresses for Server.
+// A request of a key without a rule, when there is no default rule, is refused.
+type Rules struct {
+	Keys    map[string]*Rule `json:"keys"`
+	Default *Rule            `json:"default"`
+
+	mu      sync.Mutex
+	history map[string][]signature // Signatures in the current period by key ID
+	now     func() time.Time
+}
+
+// LoadRules loads the rules from a JSON policy file.
+func LoadRules(file string) (*Rules, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	r := new(Rules)
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("invalid rules %s: %v", file, err)
+	}
+	if err := r.init(); err != nil {
+		return nil, fmt.Errorf("invalid rules %s: %v", file, err)
+	}
+	return r, nil
+}
+
+func (r *Rules) init() error {
+	keys := make(map[string]*Rule, len(r.Keys))
+	for id, rule := range r.Keys {
+		if err := rule.init(); err != nil {
+			return fmt.Errorf("%s: %v", id, err)
+		}
+		keys[strings.ToLower(id)] = rule
+	}
+	r.Keys = keys
+	if r.Default != nil {
+		if err := r.Default.init(); err != nil {
+			return fmt.Errorf("default: %v", err)
+		}
+	}
+	r.history = make(map[string][]signature)
+	r.now = time.Now
+	return nil
+}
+
+func (rule *Rule) init() error {
+	if rule.Period != "" {
+		period, err := time.ParseDuration(rule.Period)
+		if err != nil {
+			return err
+		}
+		rule.period = period
+	}
+	if (rule.MaxTotalValue != nil || rule.MaxSignatures > 0) && rule.period <= 0 {
+		return errPeriodUndefined
+	}
+	return nil
+}
+
+// Approve implements Approver, counting the approved request in the limits of
+// the key.
+func (r *Rules) Approve(req *Request) error {
+	id := strings.ToLower(req.KeyID)
+	rule, ok := r.Keys[id]
+	if !ok {
+		rule = r.Default
+	}
+	if rule == nil {
+		return errNoRule
+	}
+
+	value := new(big.Int)
+	switch req.Purpose {
+	case PurposeTransaction:
+		value = req.Tx.Value()
+		if err := rule.checkTx(req); err != nil {
+			return err
+		}
+	case PurposeFeePayer:
+		if !rule.AllowFeePayer {
+			return errPurposeDenied
+		}
+		if err := rule.checkTx(req); err != nil {
+			return err
+		}
+	case PurposeHash:
+		if !rule.AllowHash {
+			return errPurposeDenied
+		}
+	case PurposeConsensus:
+		// The consensus messages are not limited as the validator must keep up
+		// with the rounds.
+		if !rule.AllowConsensus {
+			return errPurposeDenied
+		}
+		return nil
+	default:
+		return errPurposeDenied
+	}
+	if rule.MaxValue != nil && value.Cmp((*big.Int)(rule.MaxValue)) > 0 {
+		return errValueCap
+	}
+	if rule.period <= 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	history := r.history[id]
+	for len(history) > 0 && now.Sub(history[0].time) >= rule.period {
+		history = history[1:]
+	}
+	if rule.MaxSignatures > 0 && len(history) >= rule.MaxSignatures {
+		r.history[id] = history
+		return errRateLimit
+	}
+	if rule.MaxTotalValue != nil {
+		total := new(big.Int).Set(value)
+		for _, sig := range history {
+			total.Add(total, sig.value)
+		}
+		if total.Cmp((*big.Int)(rule.MaxTotalValue)) > 0 {
+			r.history[id] = history
+			return errTotalValueCap
+		}
+	}
+	r.history[id] = append(history, signature{time: now, value: value})
+	return nil
+}
+
+// checkTx checks the destination of the transaction of the request.
+func (rule *Rule) checkTx(req *Request) error {
+	if len(rule.AllowedTo) == 0 {
+		return nil
+	}
+	to := req.Tx.To()
+	if to == nil {
+		return errDestination
+	}
+	for _, allowed := range rule.AllowedTo {
+		if *to == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", errDestination, to.Hex())
+}
@@ -0,0 +1,61 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package remote
+
+import (
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRules(t *testing.T) {
+	rules := newTestRules(t, `{
+		"keys": {"operator": {"allowFeePayer": true, "maxTotalValue": "0x10", "maxSignatures": 3, "period": "1h"}},
+		"default": {"allowConsensus": true}
+	}`)
+	now := time.Unix(0, 0)
+	rules.now = func() time.Time { return now }
+
+	from, to := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	txRequest := func(id string, value int64) *Request {
+		return &Request{KeyID: id, Purpose: PurposeTransaction, Tx: newValueTransferTx(t, from, to, value)}
+	}
+
+	// The total value and the number of the signatures are limited in the period.
+	assert.NoError(t, rules.Approve(txRequest("OPERATOR", 10)))
+	assert.Equal(t, errTotalValueCap, rules.Approve(txRequest("operator", 7)))
+	assert.NoError(t, rules.Approve(txRequest("operator", 6)))
+	assert.NoError(t, rules.Approve(&Request{KeyID: "operator", Purpose: PurposeFeePayer, Tx: newValueTransferTx(t, from, to, 0)}))
+	assert.Equal(t, errRateLimit, rules.Approve(txRequest("operator", 0)))
+	assert.Equal(t, errPurposeDenied, rules.Approve(&Request{KeyID: "operator", Purpose: PurposeHash}))
+
+	now = now.Add(time.Hour)
+	assert.NoError(t, rules.Approve(txRequest("operator", 16)))
+
+	// The keys without a rule are given the default rule.
+	assert.NoError(t, rules.Approve(&Request{KeyID: "validator", Purpose: PurposeConsensus}))
+	assert.NoError(t, rules.Approve(txRequest("validator", 1)))
+	assert.Equal(t, errPurposeDenied, rules.Approve(&Request{KeyID: "validator", Purpose: PurposeFeePayer}))
+
+	rules.Default = nil
+	assert.Equal(t, errNoRule, rules.Approve(txRequest("validator", 1)))
+
+	// The limits of a period require the period.
+	assert.Equal(t, errPeriodUndefined, (&Rule{MaxSignatures: 1}).init())
+}
@@ -0,0 +1,201 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package remote
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"sort"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+)
+
+// maxRequestSize is the maximum size of a request to the server.
+const maxRequestSize = 1024 * 1024
+
+var (
+	errUnknownKey       = errors.New("unknown key")
+	errUnknownPurpose   = errors.New("unknown purpose")
+	errInvalidDigest    = errors.New("invalid digest")
+	errTxMissing        = errors.New("transaction or chain ID missing")
+	errDigestMismatch   = errors.New("digest is not the hash of the transaction")
+	errMethodNotAllowed = errors.New("method not allowed")
+)
+
+// Request is a signing request given to the Approver.
+type Request struct {
+	KeyID   string
+	Address common.Address     // Address of the key
+	Purpose string             // One of the purposes, e.g. PurposeTransaction
+	Digest  []byte             // Hash to be signed
+	Tx      *types.Transaction // Transaction of the digest if the purpose is PurposeTransaction or PurposeFeePayer
+	ChainID *big.Int           // Chain ID of the transaction
+}
+
+// Approver approves the signing requests, returning the reason of a refusal.
+type Approver interface {
+	Approve(req *Request) error
+}
+
+// Server is the remote signer serving the API of the package with the keys in
+// memory, e.g. on a hardened host separate from the node. Every request is
+// approved by the approver before signing.
+type Server struct {
+	attestationKey ed25519.PrivateKey
+	keys           map[string]*ecdsa.PrivateKey
+	ids            []string
+	approver       Approver
+}
+
+// NewServer returns a remote signer of the keys whose responses are attested
+// by attestationKey. The ID of a key is its address in hex.
+func NewServer(attestationKey ed25519.PrivateKey, keys []*ecdsa.PrivateKey, approver Approver) *Server {
+	s := &Server{
+		attestationKey: attestationKey,
+		keys:           make(map[string]*ecdsa.PrivateKey, len(keys)),
+		approver:       approver,
+	}
+	for _, key := range keys {
+		id := crypto.PubkeyToAddress(key.PublicKey).Hex()
+		if _, ok := s.keys[id]; !ok {
+			s.ids = append(s.ids, id)
+		}
+		s.keys[id] = key
+	}
+	sort.Strings(s.ids)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, errMethodNotAllowed.Error(), http.StatusMethodNotAllowed)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
+	switch r.URL.Path {
+	case "/v1/keys":
+		var req keysRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, s.handleKeys(&req))
+	case "/v1/sign":
+		var req signRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resp, status, err := s.handleSign(&req)
+		if err != nil {
+			logger.Warn("Refused a signing request", "key", req.KeyID, "purpose", req.Purpose, "err", err)
+			http.Error(w, err.Error(), status)
+			return
+		}
+		writeJSON(w, resp)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, resp interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Error("Failed to write a response", "err", err)
+	}
+}
+
+func (s *Server) handleKeys(req *keysRequest) *keysResponse {
+	resp := &keysResponse{Keys: make([]remoteKey, 0, len(s.ids))}
+	for _, id := range s.ids {
+		resp.Keys = append(resp.Keys, remoteKey{ID: id, PublicKey: crypto.FromECDSAPub(&s.keys[id].PublicKey)})
+	}
+	resp.Attestation = ed25519.Sign(s.attestationKey, keysStatement(req.Nonce, resp.Keys))
+	return resp
+}
+
+// handleSign signs the digest of the request if it is approved, returning the
+// HTTP status of the failure otherwise.
+func (s *Server) handleSign(req *signRequest) (*signResponse, int, error) {
+	key, ok := s.keys[req.KeyID]
+	if !ok {
+		return nil, http.StatusNotFound, errUnknownKey
+	}
+	if len(req.Digest) != common.HashLength {
+		return nil, http.StatusBadRequest, errInvalidDigest
+	}
+	approval := &Request{
+		KeyID:   req.KeyID,
+		Address: crypto.PubkeyToAddress(key.PublicKey),
+		Purpose: req.Purpose,
+		Digest:  req.Digest,
+	}
+	switch req.Purpose {
+	case PurposeTransaction, PurposeFeePayer:
+		tx, err := decodeTx(req)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		approval.Tx, approval.ChainID = tx, req.ChainID.ToInt()
+	case PurposeHash, PurposeConsensus:
+	default:
+		return nil, http.StatusBadRequest, errUnknownPurpose
+	}
+	if err := s.approver.Approve(approval); err != nil {
+		return nil, http.StatusForbidden, err
+	}
+	sig, err := crypto.Sign(req.Digest, key)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	logger.Info("Signed a request", "key", req.KeyID, "purpose", req.Purpose, "digest", common.BytesToHash(req.Digest))
+	return &signResponse{
+		Signature:   sig,
+		Attestation: ed25519.Sign(s.attestationKey, signStatement(req.Nonce, req, sig)),
+	}, http.StatusOK, nil
+}
+
+// decodeTx decodes the transaction of the request, checking that the digest is
+// its hash for the purpose.
+func decodeTx(req *signRequest) (*types.Transaction, error) {
+	if req.Tx == nil || req.ChainID == nil {
+		return nil, errTxMissing
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalUnsignedBinary(req.Tx); err != nil {
+		return nil, err
+	}
+	signer := types.LatestSignerForChainID(req.ChainID.ToInt())
+	hash := signer.Hash(tx)
+	if req.Purpose == PurposeFeePayer {
+		var err error
+		if hash, err = signer.HashFeePayer(tx); err != nil {
+			return nil, err
+		}
+	}
+	if hash != common.BytesToHash(req.Digest) {
+		return nil, errDigestMismatch
+	}
+	return tx, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package remote
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"encoding/json"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRules(t *testing.T, policy string) *Rules {
+	r := new(Rules)
+	require.NoError(t, json.Unmarshal([]byte(policy), r))
+	require.NoError(t, r.init())
+	return r
+}
+
+func newValueTransferTx(t *testing.T, from, to common.Address, value int64) *types.Transaction {
+	tx, err := types.NewTransactionWithMap(types.TxTypeFeeDelegatedValueTransfer, map[types.TxValueKeyType]interface{}{
+		types.TxValueKeyNonce:    uint64(0),
+		types.TxValueKeyFrom:     from,
+		types.TxValueKeyTo:       to,
+		types.TxValueKeyAmount:   big.NewInt(value),
+		types.TxValueKeyGasLimit: uint64(100000),
+		types.TxValueKeyGasPrice: big.NewInt(25e9),
+		types.TxValueKeyFeePayer: from,
+	})
+	require.NoError(t, err)
+	return tx
+}
+
+func TestServer(t *testing.T) {
+	attestationPub, attestationKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	allowed := common.HexToAddress("0x1234")
+
+	rules := newTestRules(t, `{"keys": {"`+addr.Hex()+`": {"allowedTo": ["`+allowed.Hex()+`"], "maxValue": "10"}}}`)
+	srv := httptest.NewServer(NewServer(attestationKey, []*ecdsa.PrivateKey{key}, rules))
+	defer srv.Close()
+
+	b, err := NewBackend(Config{URL: srv.URL, AttestationKey: hexutil.Encode(attestationPub)})
+	require.NoError(t, err)
+	require.Len(t, b.Wallets(), 1)
+	w := b.Wallets()[0]
+	account := accounts.Account{Address: addr}
+	assert.True(t, w.Contains(account))
+
+	// The transactions are signed as allowed by the rules.
+	chainID := big.NewInt(1001)
+	signer := types.LatestSignerForChainID(chainID)
+	signed, err := w.SignTx(account, newValueTransferTx(t, addr, allowed, 10), chainID)
+	require.NoError(t, err)
+	pubs, err := types.SenderPubkey(signer, signed)
+	require.NoError(t, err)
+	assert.Equal(t, addr, crypto.PubkeyToAddress(*pubs[0]))
+
+	_, err = w.SignTx(account, newValueTransferTx(t, addr, allowed, 11), chainID)
+	assert.Error(t, err)
+	_, err = w.SignTx(account, newValueTransferTx(t, addr, common.HexToAddress("0x5678"), 1), chainID)
+	assert.Error(t, err)
+	_, err = w.SignTxAsFeePayer(account, signed, chainID)
+	assert.Error(t, err)
+	_, err = w.SignHash(account, crypto.Keccak256([]byte("message")))
+	assert.Error(t, err)
+
+	// A digest which is not the hash of the transaction is refused.
+	tx := newValueTransferTx(t, addr, allowed, 1)
+	txBytes, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	_, status, err := NewServer(attestationKey, []*ecdsa.PrivateKey{key}, rules).handleSign(&signRequest{
+		KeyID:   addr.Hex(),
+		Purpose: PurposeTransaction,
+		Digest:  crypto.Keccak256([]byte("message")),
+		Tx:      txBytes,
+		ChainID: (*hexutil.Big)(chainID),
+	})
+	assert.Equal(t, errDigestMismatch, err)
+	assert.Equal(t, 400, status)
+}
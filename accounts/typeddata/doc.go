@@ -0,0 +1,30 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+/*
+Package typeddata implements the hashing of the typed structured data defined by
+EIP-712, signed by the accounts for the meta-transactions and the permit() flows
+of the contracts.
+
+The data is the types, the primary type, the domain and the message in the JSON
+format of EIP-712. Its hash is
+
+	keccak256("\x19\x01" || hashStruct(domain) || hashStruct(message))
+
+and the signers show the data formatted by TypedData.Format to the users, so
+they confirm the structure they sign rather than an opaque hash.
+*/
+package typeddata
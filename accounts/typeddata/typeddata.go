@@ -0,0 +1,548 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package typeddata
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/common/math"
+	"github.com/klaytn/klaytn/crypto"
+)
+
+// DomainType is the name of the type of the domain.
+const DomainType = "EIP712Domain"
+
+var (
+	errUndefinedDomain = errors.New("domain is undefined")
+	errNilMessage      = errors.New("message is undefined")
+
+	referenceTypeRegexp = regexp.MustCompile(`^[A-Za-z](\w*)(\[\])?$`)
+)
+
+// Type is a field of a struct type.
+type Type struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+func (t *Type) isArray() bool {
+	return strings.HasSuffix(t.Type, "[]")
+}
+
+// typeName returns the type of the elements of an array, or the type itself.
+func (t *Type) typeName() string {
+	return strings.TrimSuffix(t.Type, "[]")
+}
+
+// isReferenceType returns true if the type is a struct, whose name starts with
+// an upper case letter.
+func (t *Type) isReferenceType() bool {
+	r, _ := utf8.DecodeRuneInString(t.Type)
+	return unicode.IsUpper(r)
+}
+
+// Types are the fields of the struct types by their names.
+type Types map[string][]Type
+
+// Domain is the domain separating the signatures of the applications.
+type Domain struct {
+	Name              string                `json:"name"`
+	Version           string                `json:"version"`
+	ChainId           *math.HexOrDecimal256 `json:"chainId"`
+	VerifyingContract string                `json:"verifyingContract"`
+	Salt              string                `json:"salt"`
+}
+
+// UnmarshalJSON decodes the domain, whose chain ID is a JSON number as sent by
+// most wallets, or a decimal or hex string.
+func (d *Domain) UnmarshalJSON(input []byte) error {
+	type domain Domain
+	var dec struct {
+		domain
+		ChainId json.RawMessage `json:"chainId"`
+	}
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	*d = Domain(dec.domain)
+	if len(dec.ChainId) == 0 || string(dec.ChainId) == "null" {
+		return nil
+	}
+	d.ChainId = new(math.HexOrDecimal256)
+	if dec.ChainId[0] == '"' {
+		return json.Unmarshal(dec.ChainId, d.ChainId)
+	}
+	return d.ChainId.UnmarshalText(dec.ChainId)
+}
+
+// Map returns the fields of the domain which are set.
+func (d *Domain) Map() map[string]interface{} {
+	m := make(map[string]interface{})
+	if d.Name != "" {
+		m["name"] = d.Name
+	}
+	if d.Version != "" {
+		m["version"] = d.Version
+	}
+	if d.ChainId != nil {
+		m["chainId"] = (*big.Int)(d.ChainId)
+	}
+	if d.VerifyingContract != "" {
+		m["verifyingContract"] = d.VerifyingContract
+	}
+	if d.Salt != "" {
+		m["salt"] = d.Salt
+	}
+	return m
+}
+
+// TypedData is the typed structured data of EIP-712.
+type TypedData struct {
+	Types       Types                  `json:"types"`
+	PrimaryType string                 `json:"primaryType"`
+	Domain      Domain                 `json:"domain"`
+	Message     map[string]interface{} `json:"message"`
+}
+
+// Hash returns the hash of the typed data signed by the accounts.
+func (td *TypedData) Hash() (common.Hash, error) {
+	if err := td.validate(); err != nil {
+		return common.Hash{}, err
+	}
+	domainSeparator, err := td.HashStruct(DomainType, td.Domain.Map())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	messageHash, err := td.HashStruct(td.PrimaryType, td.Message)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash([]byte("\x19\x01"), domainSeparator[:], messageHash[:]), nil
+}
+
+// HashStruct returns the hash of the data of the struct type.
+func (td *TypedData) HashStruct(primaryType string, data map[string]interface{}) (common.Hash, error) {
+	encoded, err := td.EncodeData(primaryType, data)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(encoded), nil
+}
+
+// Dependencies returns the struct types referenced by the type, recursively,
+// including the type itself.
+func (td *TypedData) Dependencies(primaryType string, found []string) []string {
+	primaryType = strings.TrimSuffix(primaryType, "[]")
+	for _, t := range found {
+		if t == primaryType {
+			return found
+		}
+	}
+	if td.Types[primaryType] == nil {
+		return found
+	}
+	found = append(found, primaryType)
+	for _, field := range td.Types[primaryType] {
+		found = td.Dependencies(field.Type, found)
+	}
+	return found
+}
+
+// EncodeType returns the encoding of the type, e.g.
+// "Mail(Person from,Person to,string contents)Person(string name,address wallet)".
+func (td *TypedData) EncodeType(primaryType string) []byte {
+	deps := td.Dependencies(primaryType, nil)
+	if len(deps) > 0 {
+		sort.Strings(deps[1:])
+	}
+
+	var buf bytes.Buffer
+	for _, dep := range deps {
+		fields := make([]string, len(td.Types[dep]))
+		for i, field := range td.Types[dep] {
+			fields[i] = field.Type + " " + field.Name
+		}
+		buf.WriteString(dep + "(" + strings.Join(fields, ",") + ")")
+	}
+	return buf.Bytes()
+}
+
+// TypeHash returns the hash of the encoding of the type.
+func (td *TypedData) TypeHash(primaryType string) common.Hash {
+	return crypto.Keccak256Hash(td.EncodeType(primaryType))
+}
+
+// EncodeData returns the encoding of the data of the struct type, the type hash
+// followed by the encoded fields.
+func (td *TypedData) EncodeData(primaryType string, data map[string]interface{}) ([]byte, error) {
+	fields, ok := td.Types[primaryType]
+	if !ok {
+		return nil, fmt.Errorf("undefined type %q", primaryType)
+	}
+	if len(data) > len(fields) {
+		return nil, fmt.Errorf("there is extra data in the %s message", primaryType)
+	}
+
+	var buf bytes.Buffer
+	typeHash := td.TypeHash(primaryType)
+	buf.Write(typeHash[:])
+	for _, field := range fields {
+		value := data[field.Name]
+		if field.isArray() {
+			values, ok := value.([]interface{})
+			if !ok {
+				return nil, dataMismatchError(field.Type, value)
+			}
+			var arrayBuf bytes.Buffer
+			for _, item := range values {
+				encoded, err := td.encodeValue(field.typeName(), item)
+				if err != nil {
+					return nil, err
+				}
+				arrayBuf.Write(encoded)
+			}
+			buf.Write(crypto.Keccak256(arrayBuf.Bytes()))
+			continue
+		}
+		encoded, err := td.encodeValue(field.Type, value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(encoded)
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeValue returns the 32-byte encoding of a value, which is the hash of the
+// encoded data for a struct.
+func (td *TypedData) encodeValue(typ string, value interface{}) ([]byte, error) {
+	if td.Types[typ] == nil {
+		return encodePrimitiveValue(typ, value)
+	}
+	data, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, dataMismatchError(typ, value)
+	}
+	encoded, err := td.EncodeData(typ, data)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(encoded), nil
+}
+
+// encodePrimitiveValue returns the 32-byte encoding of an atomic or a dynamic
+// value.
+func encodePrimitiveValue(typ string, value interface{}) ([]byte, error) {
+	switch typ {
+	case "address":
+		s, ok := value.(string)
+		if !ok || !common.IsHexAddress(s) {
+			return nil, dataMismatchError(typ, value)
+		}
+		return common.LeftPadBytes(common.HexToAddress(s).Bytes(), 32), nil
+	case "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, dataMismatchError(typ, value)
+		}
+		encoded := make([]byte, 32)
+		if b {
+			encoded[31] = 1
+		}
+		return encoded, nil
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, dataMismatchError(typ, value)
+		}
+		return crypto.Keccak256([]byte(s)), nil
+	case "bytes":
+		b, ok := parseBytes(value)
+		if !ok {
+			return nil, dataMismatchError(typ, value)
+		}
+		return crypto.Keccak256(b), nil
+	}
+	if strings.HasPrefix(typ, "bytes") {
+		length, err := strconv.Atoi(strings.TrimPrefix(typ, "bytes"))
+		if err != nil || length < 1 || length > 32 {
+			return nil, fmt.Errorf("invalid type %q", typ)
+		}
+		b, ok := parseBytes(value)
+		if !ok || len(b) != length {
+			return nil, dataMismatchError(typ, value)
+		}
+		return common.RightPadBytes(b, 32), nil
+	}
+	if strings.HasPrefix(typ, "int") || strings.HasPrefix(typ, "uint") {
+		n, err := parseInteger(typ, value)
+		if err != nil {
+			return nil, err
+		}
+		return math.U256Bytes(new(big.Int).Set(n)), nil
+	}
+	return nil, fmt.Errorf("unrecognized type %q", typ)
+}
+
+func dataMismatchError(typ string, value interface{}) error {
+	return fmt.Errorf("provided data %v does not match the type %q", value, typ)
+}
+
+// parseBytes returns the bytes of a hex string.
+func parseBytes(value interface{}) ([]byte, bool) {
+	switch v := value.(type) {
+	case []byte:
+		return v, true
+	case hexutil.Bytes:
+		return v, true
+	case string:
+		b, err := hexutil.Decode(v)
+		return b, err == nil
+	}
+	return nil, false
+}
+
+// parseInteger returns the integer of a JSON number or a decimal or hex string,
+// checking the range of the type.
+func parseInteger(typ string, value interface{}) (*big.Int, error) {
+	signed := strings.HasPrefix(typ, "int")
+	size, err := strconv.Atoi(strings.TrimPrefix(strings.TrimPrefix(typ, "u"), "int"))
+	if err != nil || size < 8 || size > 256 || size%8 != 0 {
+		return nil, fmt.Errorf("invalid type %q", typ)
+	}
+
+	var n *big.Int
+	switch v := value.(type) {
+	case *big.Int:
+		n = v
+	case *math.HexOrDecimal256:
+		n = (*big.Int)(v)
+	case float64:
+		// JSON numbers are parsed as float64, which are exact up to 2^53.
+		if v != float64(int64(v)) {
+			return nil, dataMismatchError(typ, value)
+		}
+		n = big.NewInt(int64(v))
+	case string:
+		neg := strings.HasPrefix(v, "-")
+		parsed, ok := math.ParseBig256(strings.TrimPrefix(v, "-"))
+		if !ok {
+			return nil, dataMismatchError(typ, value)
+		}
+		if neg {
+			parsed.Neg(parsed)
+		}
+		n = parsed
+	default:
+		return nil, dataMismatchError(typ, value)
+	}
+
+	var min, max *big.Int
+	if signed {
+		max = new(big.Int).Sub(new(big.Int).Lsh(common.Big1, uint(size-1)), common.Big1)
+		min = new(big.Int).Neg(new(big.Int).Lsh(common.Big1, uint(size-1)))
+	} else {
+		max = new(big.Int).Sub(new(big.Int).Lsh(common.Big1, uint(size)), common.Big1)
+		min = new(big.Int)
+	}
+	if n.Cmp(min) < 0 || n.Cmp(max) > 0 {
+		return nil, fmt.Errorf("integer %v is out of the range of the type %q", n, typ)
+	}
+	return n, nil
+}
+
+// validate checks the types and the domain of the typed data.
+func (td *TypedData) validate() error {
+	for name, fields := range td.Types {
+		if name == "" {
+			return errors.New("empty type name")
+		}
+		for _, field := range fields {
+			if field.Type == "" {
+				return fmt.Errorf("empty type of the field %q of %s", field.Name, name)
+			}
+			if field.Name == "" {
+				return fmt.Errorf("empty field name of %s", name)
+			}
+			if field.typeName() == name {
+				return fmt.Errorf("type %q cannot reference itself", name)
+			}
+			if field.isReferenceType() {
+				if _, ok := td.Types[field.typeName()]; !ok {
+					return fmt.Errorf("reference type %q is undefined", field.Type)
+				}
+				if !referenceTypeRegexp.MatchString(field.Type) {
+					return fmt.Errorf("unknown reference type %q", field.Type)
+				}
+			} else if !isPrimitiveType(field.typeName()) {
+				return fmt.Errorf("unknown type %q", field.Type)
+			}
+		}
+	}
+	if _, ok := td.Types[DomainType]; !ok {
+		return fmt.Errorf("type %s is undefined", DomainType)
+	}
+	if len(td.Domain.Map()) == 0 {
+		return errUndefinedDomain
+	}
+	if _, ok := td.Types[td.PrimaryType]; !ok {
+		return fmt.Errorf("primary type %q is undefined", td.PrimaryType)
+	}
+	if td.Message == nil {
+		return errNilMessage
+	}
+	return nil
+}
+
+func isPrimitiveType(typ string) bool {
+	switch typ {
+	case "address", "bool", "string", "bytes":
+		return true
+	}
+	for _, prefix := range []string{"bytes", "uint", "int"} {
+		if !strings.HasPrefix(typ, prefix) {
+			continue
+		}
+		size, err := strconv.Atoi(strings.TrimPrefix(typ, prefix))
+		if err != nil || strings.TrimPrefix(typ, prefix) != strconv.Itoa(size) {
+			return false
+		}
+		if prefix == "bytes" {
+			return size >= 1 && size <= 32
+		}
+		return size >= 8 && size <= 256 && size%8 == 0
+	}
+	return false
+}
+
+// NameValueType is a formatted field of the typed data, shown to the users
+// confirming the signing. The value of a struct is its formatted fields, and the
+// value of an array is its formatted elements.
+type NameValueType struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+	Type  string      `json:"type"`
+}
+
+// Format returns the formatted domain and message of the typed data.
+func (td *TypedData) Format() ([]*NameValueType, error) {
+	if err := td.validate(); err != nil {
+		return nil, err
+	}
+	domain, err := td.formatData(DomainType, td.Domain.Map())
+	if err != nil {
+		return nil, err
+	}
+	message, err := td.formatData(td.PrimaryType, td.Message)
+	if err != nil {
+		return nil, err
+	}
+	return []*NameValueType{
+		{Name: DomainType, Value: domain, Type: DomainType},
+		{Name: td.PrimaryType, Value: message, Type: td.PrimaryType},
+	}, nil
+}
+
+func (td *TypedData) formatData(primaryType string, data map[string]interface{}) ([]*NameValueType, error) {
+	var output []*NameValueType
+	for _, field := range td.Types[primaryType] {
+		value := data[field.Name]
+		item := &NameValueType{Name: field.Name, Type: field.Type}
+		if field.isArray() {
+			values, ok := value.([]interface{})
+			if !ok {
+				return nil, dataMismatchError(field.Type, value)
+			}
+			formatted := make([]interface{}, len(values))
+			for i, v := range values {
+				var err error
+				if formatted[i], err = td.formatValue(field.typeName(), v); err != nil {
+					return nil, err
+				}
+			}
+			item.Value = formatted
+		} else {
+			var err error
+			if item.Value, err = td.formatValue(field.Type, value); err != nil {
+				return nil, err
+			}
+		}
+		output = append(output, item)
+	}
+	return output, nil
+}
+
+func (td *TypedData) formatValue(typ string, value interface{}) (interface{}, error) {
+	if td.Types[typ] != nil {
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, dataMismatchError(typ, value)
+		}
+		return td.formatData(typ, data)
+	}
+	return formatPrimitiveValue(typ, value)
+}
+
+func formatPrimitiveValue(typ string, value interface{}) (string, error) {
+	switch typ {
+	case "address":
+		s, ok := value.(string)
+		if !ok || !common.IsHexAddress(s) {
+			return "", dataMismatchError(typ, value)
+		}
+		return common.HexToAddress(s).Hex(), nil
+	case "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return "", dataMismatchError(typ, value)
+		}
+		return strconv.FormatBool(b), nil
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return "", dataMismatchError(typ, value)
+		}
+		return s, nil
+	}
+	if strings.HasPrefix(typ, "bytes") {
+		b, ok := parseBytes(value)
+		if !ok {
+			return "", dataMismatchError(typ, value)
+		}
+		return hexutil.Encode(b), nil
+	}
+	if strings.HasPrefix(typ, "int") || strings.HasPrefix(typ, "uint") {
+		n, err := parseInteger(typ, value)
+		if err != nil {
+			return "", err
+		}
+		return n.String(), nil
+	}
+	return "", fmt.Errorf("unrecognized type %q", typ)
+}
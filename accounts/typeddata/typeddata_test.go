@@ -0,0 +1,134 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package typeddata
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mailJSON is the example of EIP-712.
+const mailJSON = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func loadMail(t *testing.T) *TypedData {
+	td := new(TypedData)
+	require.NoError(t, json.Unmarshal([]byte(mailJSON), td))
+	return td
+}
+
+func TestTypedData_Hash(t *testing.T) {
+	td := loadMail(t)
+
+	assert.Equal(t, "Mail(Person from,Person to,string contents)Person(string name,address wallet)", string(td.EncodeType("Mail")))
+	assert.Equal(t, common.HexToHash("0xa0cedeb2dc280ba39b857546d74f5549c3a1d7bdc2dd96bf881f76108e23dac2"), td.TypeHash("Mail"))
+
+	domainSeparator, err := td.HashStruct(DomainType, td.Domain.Map())
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToHash("0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f"), domainSeparator)
+
+	messageHash, err := td.HashStruct("Mail", td.Message)
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToHash("0xc52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e"), messageHash)
+
+	hash, err := td.Hash()
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToHash("0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"), hash)
+
+	// The signature of the example, signed by keccak256("cow").
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte("cow")))
+	require.NoError(t, err)
+	sig, err := crypto.Sign(hash[:], key)
+	require.NoError(t, err)
+	assert.Equal(t, hexutil.MustDecode("0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b9156201"), sig)
+}
+
+func TestTypedData_Invalid(t *testing.T) {
+	for name, modify := range map[string]func(td *TypedData){
+		"undefined primary type": func(td *TypedData) { td.PrimaryType = "Letter" },
+		"undefined reference":    func(td *TypedData) { td.Types["Mail"][0].Type = "Sender" },
+		"unknown type":           func(td *TypedData) { td.Types["Person"][0].Type = "text" },
+		"self reference":         func(td *TypedData) { td.Types["Person"][0].Type = "Person" },
+		"undefined domain":       func(td *TypedData) { td.Domain = Domain{} },
+		"invalid address":        func(td *TypedData) { td.Message["to"].(map[string]interface{})["wallet"] = "0x1234" },
+		"extra data":             func(td *TypedData) { td.Message["cc"] = "Alice" },
+		"integer out of range":   func(td *TypedData) { td.Types["Mail"][2].Type = "uint8"; td.Message["contents"] = float64(256) },
+	} {
+		td := loadMail(t)
+		modify(td)
+		_, err := td.Hash()
+		assert.Error(t, err, name)
+	}
+}
+
+func TestTypedData_Format(t *testing.T) {
+	td := loadMail(t)
+	td.Types["Mail"] = append(td.Types["Mail"], Type{Name: "cc", Type: "Person[]"})
+	td.Message["cc"] = []interface{}{
+		map[string]interface{}{"name": "Alice", "wallet": "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"},
+	}
+
+	formatted, err := td.Format()
+	require.NoError(t, err)
+	require.Len(t, formatted, 2)
+
+	domain := formatted[0].Value.([]*NameValueType)
+	assert.Equal(t, &NameValueType{Name: "chainId", Value: "1", Type: "uint256"}, domain[2])
+
+	message := formatted[1].Value.([]*NameValueType)
+	require.Len(t, message, 4)
+	from := message[0].Value.([]*NameValueType)
+	assert.Equal(t, &NameValueType{Name: "wallet", Value: "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826", Type: "address"}, from[1])
+	cc := message[3].Value.([]interface{})
+	require.Len(t, cc, 1)
+	assert.Equal(t, "Alice", cc[0].([]*NameValueType)[0].Value)
+}
@@ -0,0 +1,224 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	requestTimeout = 10 * time.Second
+
+	// tokenEnv is the environment variable of the token, used if neither a token
+	// file nor an AppRole is configured.
+	tokenEnv = "VAULT_TOKEN"
+
+	// minRenewInterval bounds the renewal interval of the tokens of short TTLs.
+	minRenewInterval = 5 * time.Second
+)
+
+var errNoAuth = errors.New("no Vault authentication is configured, give a token or an AppRole")
+
+// client is the client of the Vault HTTP API.
+type client struct {
+	config Config
+	http   *http.Client
+
+	mu    sync.RWMutex
+	token string
+
+	quit chan struct{}
+}
+
+func newClient(config Config) (*client, error) {
+	c := &client{
+		config: config,
+		http:   &http.Client{Timeout: requestTimeout},
+		quit:   make(chan struct{}),
+	}
+	ttl, renewable, err := c.login()
+	if err != nil {
+		return nil, err
+	}
+	go c.renewLoop(ttl, renewable)
+	return c, nil
+}
+
+// login logs in with the configured method, and returns the TTL of the token.
+func (c *client) login() (time.Duration, bool, error) {
+	if c.config.AppRoleID != "" {
+		secretID, err := readSecretFile(c.config.AppRoleSecretIDFile)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to read the AppRole secret ID: %v", err)
+		}
+		var res struct {
+			Auth struct {
+				ClientToken   string `json:"client_token"`
+				LeaseDuration int64  `json:"lease_duration"`
+				Renewable     bool   `json:"renewable"`
+			} `json:"auth"`
+		}
+		body := map[string]string{"role_id": c.config.AppRoleID, "secret_id": secretID}
+		if err := c.do(http.MethodPost, "auth/"+c.config.appRoleMount()+"/login", body, &res); err != nil {
+			return 0, false, fmt.Errorf("failed to log in with the AppRole: %v", err)
+		}
+		c.setToken(res.Auth.ClientToken)
+		return time.Duration(res.Auth.LeaseDuration) * time.Second, res.Auth.Renewable, nil
+	}
+
+	var token string
+	if c.config.TokenFile != "" {
+		var err error
+		if token, err = readSecretFile(c.config.TokenFile); err != nil {
+			return 0, false, fmt.Errorf("failed to read the Vault token: %v", err)
+		}
+	} else if token = os.Getenv(tokenEnv); token == "" {
+		return 0, false, errNoAuth
+	}
+	c.setToken(token)
+
+	var res struct {
+		Data struct {
+			TTL       int64 `json:"ttl"`
+			Renewable bool  `json:"renewable"`
+		} `json:"data"`
+	}
+	if err := c.do(http.MethodGet, "auth/token/lookup-self", nil, &res); err != nil {
+		return 0, false, fmt.Errorf("failed to look up the Vault token: %v", err)
+	}
+	return time.Duration(res.Data.TTL) * time.Second, res.Data.Renewable, nil
+}
+
+// renewLoop renews the token at two thirds of its TTL. A token without TTL is
+// not renewed. If the renewal fails, it logs in again, retrying until it
+// succeeds.
+func (c *client) renewLoop(ttl time.Duration, renewable bool) {
+	for ttl > 0 {
+		wait := ttl * 2 / 3
+		if wait < minRenewInterval {
+			wait = minRenewInterval
+		}
+		select {
+		case <-time.After(wait):
+		case <-c.quit:
+			return
+		}
+
+		var err error
+		if renewable {
+			if ttl, err = c.renew(); err == nil {
+				continue
+			}
+			logger.Warn("Failed to renew the Vault token, logging in again", "err", err)
+		}
+		if ttl, renewable, err = c.login(); err != nil {
+			logger.Error("Failed to log in to Vault", "err", err)
+			ttl, renewable = minRenewInterval, false
+		}
+	}
+}
+
+func (c *client) renew() (time.Duration, error) {
+	var res struct {
+		Auth struct {
+			LeaseDuration int64 `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	if err := c.do(http.MethodPost, "auth/token/renew-self", map[string]string{}, &res); err != nil {
+		return 0, err
+	}
+	return time.Duration(res.Auth.LeaseDuration) * time.Second, nil
+}
+
+func (c *client) setToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+}
+
+func (c *client) close() {
+	close(c.quit)
+}
+
+// do calls the API of the path with the body, if any, and decodes the response
+// into result. "LIST" is sent as GET with list=true.
+func (c *client) do(method, path string, body, result interface{}) error {
+	url := strings.TrimRight(c.config.Address, "/") + "/v1/" + path
+	if method == "LIST" {
+		method, url = http.MethodGet, url+"?list=true"
+	}
+	var reader io.Reader
+	if body != nil {
+		enc, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(enc)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return err
+	}
+	c.mu.RLock()
+	if c.token != "" {
+		req.Header.Set("X-Vault-Token", c.token)
+	}
+	c.mu.RUnlock()
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		var verr struct {
+			Errors []string `json:"errors"`
+		}
+		if json.Unmarshal(data, &verr) == nil && len(verr.Errors) > 0 {
+			return fmt.Errorf("%s: %s", res.Status, strings.Join(verr.Errors, "; "))
+		}
+		return errors.New(res.Status)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
+
+// readSecretFile reads a token or a secret ID from the file.
+func readSecretFile(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
@@ -0,0 +1,38 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+/*
+Package vault implements the accounts whose keystores are kept in a KV version 2
+secrets engine of HashiCorp Vault, to centralize the custody of the keys.
+
+Each secret under the configured path holds an encrypted keystore in its
+"keystore" field, and is a wallet with the URL vault://<name>. A keystore is read
+from Vault whenever it is decrypted, so the rotated or revoked keystores take
+effect without a restart. A wallet is unlocked by personal.openWallet with the
+passphrase of the keystore, or a passphrase is given for each signature.
+
+The client logs in with a token or an AppRole, and renews the token before it
+expires. If the token can't be renewed, it logs in again with the AppRole.
+
+The transit secrets engine doesn't support secp256k1 keys, so the keys are not
+signed by Vault but decrypted in the node.
+
+Source Files
+
+  - client.go	: Provides the Vault client with the login and the token renewal
+  - vault.go	: Defines `Backend` listing the keystores, and the wallet of a keystore
+*/
+package vault
@@ -0,0 +1,339 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/klaytn/klaytn"
+	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/accounts/keystore"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/event"
+	"github.com/klaytn/klaytn/log"
+)
+
+// URLScheme is the scheme of the URLs of the Vault wallets.
+const URLScheme = "vault"
+
+var logger = log.NewModuleLogger(log.AccountsVault)
+
+// Config is the configuration of the Vault backend, which is enabled by the
+// address. The token and the secret ID are read from the files so that they are
+// not written in the configuration.
+type Config struct {
+	Address             string `toml:",omitempty"` // Address of Vault, e.g. https://vault:8200
+	Mount               string `toml:",omitempty"` // Mount of the KV version 2 secrets engine
+	Path                string `toml:",omitempty"` // Path of the keystores in the mount
+	TokenFile           string `toml:",omitempty"` // File of the token, VAULT_TOKEN is used if empty
+	AppRoleMount        string `toml:",omitempty"` // Mount of the AppRole auth method
+	AppRoleID           string `toml:",omitempty"` // Role ID of the AppRole to log in with
+	AppRoleSecretIDFile string `toml:",omitempty"` // File of the secret ID of the AppRole
+}
+
+// Defaults of the Vault configuration.
+const (
+	DefaultMount        = "secret"
+	DefaultPath         = "klaytn/keystore"
+	DefaultAppRoleMount = "approle"
+)
+
+// Enabled returns true if the Vault backend is configured.
+func (c Config) Enabled() bool {
+	return c.Address != ""
+}
+
+func (c Config) mount() string {
+	if c.Mount == "" {
+		return DefaultMount
+	}
+	return strings.Trim(c.Mount, "/")
+}
+
+func (c Config) path() string {
+	if c.Path == "" {
+		return DefaultPath
+	}
+	return strings.Trim(c.Path, "/")
+}
+
+func (c Config) appRoleMount() string {
+	if c.AppRoleMount == "" {
+		return DefaultAppRoleMount
+	}
+	return strings.Trim(c.AppRoleMount, "/")
+}
+
+// Backend is the accounts.Backend of the keystores in Vault.
+type Backend struct {
+	client  *client
+	wallets []accounts.Wallet
+	feed    event.Feed
+}
+
+// NewBackend logs in to Vault and lists the keystores under the path.
+func NewBackend(config Config) (*Backend, error) {
+	c, err := newClient(config)
+	if err != nil {
+		return nil, err
+	}
+	b := &Backend{client: c}
+	if err := b.load(); err != nil {
+		c.close()
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *Backend) load() error {
+	var res struct {
+		Data struct {
+			Keys []string `json:"keys"`
+		} `json:"data"`
+	}
+	config := b.client.config
+	if err := b.client.do("LIST", config.mount()+"/metadata/"+config.path(), nil, &res); err != nil {
+		return fmt.Errorf("failed to list the keystores in Vault: %v", err)
+	}
+	for _, name := range res.Data.Keys {
+		if strings.HasSuffix(name, "/") {
+			continue // A directory
+		}
+		w := &wallet{client: b.client, name: name, url: accounts.URL{Scheme: URLScheme, Path: name}}
+		keyjson, err := w.keystore()
+		if err != nil {
+			return err
+		}
+		var header struct {
+			Address string `json:"address"`
+		}
+		if err := json.Unmarshal(keyjson, &header); err != nil || !common.IsHexAddress(header.Address) {
+			logger.Warn("Ignored a Vault secret without a keystore", "name", name)
+			continue
+		}
+		w.account = accounts.Account{Address: common.HexToAddress(header.Address), URL: w.url}
+		logger.Info("Loaded a Vault account", "url", w.url, "address", w.account.Address)
+		b.wallets = append(b.wallets, w)
+	}
+	sort.Slice(b.wallets, func(i, j int) bool { return b.wallets[i].URL().Cmp(b.wallets[j].URL()) < 0 })
+	return nil
+}
+
+// Wallets implements accounts.Backend, returning the wallets of the keystores.
+func (b *Backend) Wallets() []accounts.Wallet {
+	cpy := make([]accounts.Wallet, len(b.wallets))
+	copy(cpy, b.wallets)
+	return cpy
+}
+
+// Subscribe implements accounts.Backend. The wallets are listed at the startup,
+// so no event is sent.
+func (b *Backend) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	return b.feed.Subscribe(sink)
+}
+
+// wallet is the accounts.Wallet of a keystore in Vault.
+type wallet struct {
+	client  *client
+	name    string
+	url     accounts.URL
+	account accounts.Account
+
+	mu  sync.RWMutex
+	key keystore.Key // Decrypted key while the wallet is open
+}
+
+// keystore reads the keystore from Vault.
+func (w *wallet) keystore() ([]byte, error) {
+	var res struct {
+		Data struct {
+			Data struct {
+				Keystore json.RawMessage `json:"keystore"`
+			} `json:"data"`
+		} `json:"data"`
+	}
+	config := w.client.config
+	if err := w.client.do(http.MethodGet, config.mount()+"/data/"+config.path()+"/"+w.name, nil, &res); err != nil {
+		return nil, fmt.Errorf("failed to read the keystore %s from Vault: %v", w.name, err)
+	}
+	keyjson := []byte(res.Data.Data.Keystore)
+	// The keystore may be stored as a JSON string.
+	var s string
+	if json.Unmarshal(keyjson, &s) == nil {
+		keyjson = []byte(s)
+	}
+	return keyjson, nil
+}
+
+// decrypt reads the keystore from Vault and decrypts it.
+func (w *wallet) decrypt(passphrase string) (keystore.Key, error) {
+	keyjson, err := w.keystore()
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(keyjson, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if key.GetAddress() != w.account.Address {
+		key.ResetPrivateKey()
+		return nil, fmt.Errorf("the keystore %s is replaced with another account %x", w.name, key.GetAddress())
+	}
+	return key, nil
+}
+
+func (w *wallet) URL() accounts.URL { return w.url }
+
+func (w *wallet) Status() (string, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.key == nil {
+		return "Locked", nil
+	}
+	return "Unlocked", nil
+}
+
+// Open decrypts the keystore with the passphrase and keeps the key in memory
+// until the wallet is closed.
+func (w *wallet) Open(passphrase string) error {
+	key, err := w.decrypt(passphrase)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.key != nil {
+		w.key.ResetPrivateKey()
+	}
+	w.key = key
+	return nil
+}
+
+func (w *wallet) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.key != nil {
+		w.key.ResetPrivateKey()
+		w.key = nil
+	}
+	return nil
+}
+
+func (w *wallet) Accounts() []accounts.Account { return []accounts.Account{w.account} }
+
+func (w *wallet) Contains(account accounts.Account) bool {
+	return account.Address == w.account.Address && (account.URL == (accounts.URL{}) || account.URL == w.url)
+}
+
+func (w *wallet) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	return accounts.Account{}, accounts.ErrNotSupported
+}
+
+func (w *wallet) SelfDerive(base accounts.DerivationPath, chain klaytn.ChainReader) {}
+
+// withKey calls fn with the key of the open wallet.
+func (w *wallet) withKey(account accounts.Account, fn func(key keystore.Key) error) error {
+	if !w.Contains(account) {
+		return accounts.ErrUnknownAccount
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.key == nil {
+		return keystore.ErrLocked
+	}
+	return fn(w.key)
+}
+
+// withPassphrase calls fn with the key decrypted with the passphrase.
+func (w *wallet) withPassphrase(account accounts.Account, passphrase string, fn func(key keystore.Key) error) error {
+	if !w.Contains(account) {
+		return accounts.ErrUnknownAccount
+	}
+	key, err := w.decrypt(passphrase)
+	if err != nil {
+		return err
+	}
+	defer key.ResetPrivateKey()
+	return fn(key)
+}
+
+func signTx(key keystore.Key, tx *types.Transaction, chainID *big.Int, feePayer bool) (*types.Transaction, error) {
+	if chainID == nil {
+		return nil, keystore.ErrChainIdNil
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	if feePayer {
+		return types.SignTxAsFeePayer(tx, signer, key.GetPrivateKey())
+	}
+	return types.SignTx(tx, signer, key.GetPrivateKey())
+}
+
+func (w *wallet) SignHash(account accounts.Account, hash []byte) (sig []byte, err error) {
+	err = w.withKey(account, func(key keystore.Key) error {
+		sig, err = crypto.Sign(hash, key.GetPrivateKey())
+		return err
+	})
+	return sig, err
+}
+
+func (w *wallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (signed *types.Transaction, err error) {
+	err = w.withKey(account, func(key keystore.Key) error {
+		signed, err = signTx(key, tx, chainID, false)
+		return err
+	})
+	return signed, err
+}
+
+func (w *wallet) SignTxAsFeePayer(account accounts.Account, tx *types.Transaction, chainID *big.Int) (signed *types.Transaction, err error) {
+	err = w.withKey(account, func(key keystore.Key) error {
+		signed, err = signTx(key, tx, chainID, true)
+		return err
+	})
+	return signed, err
+}
+
+func (w *wallet) SignHashWithPassphrase(account accounts.Account, passphrase string, hash []byte) (sig []byte, err error) {
+	err = w.withPassphrase(account, passphrase, func(key keystore.Key) error {
+		sig, err = crypto.Sign(hash, key.GetPrivateKey())
+		return err
+	})
+	return sig, err
+}
+
+func (w *wallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (signed *types.Transaction, err error) {
+	err = w.withPassphrase(account, passphrase, func(key keystore.Key) error {
+		signed, err = signTx(key, tx, chainID, false)
+		return err
+	})
+	return signed, err
+}
+
+func (w *wallet) SignTxAsFeePayerWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (signed *types.Transaction, err error) {
+	err = w.withPassphrase(account, passphrase, func(key keystore.Key) error {
+		signed, err = signTx(key, tx, chainID, true)
+		return err
+	})
+	return signed, err
+}
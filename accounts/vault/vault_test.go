@@ -0,0 +1,133 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package vault
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/accounts/keystore"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+const testToken = "s.test"
+
+// newTestVault serves the keystores with the token and the KV version 2 API.
+func newTestVault(t *testing.T, keystores map[string][]byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != testToken {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		var res interface{}
+		switch path := r.URL.Path; {
+		case path == "/v1/auth/token/lookup-self":
+			res = map[string]interface{}{"data": map[string]interface{}{"ttl": 0, "renewable": false}}
+		case path == "/v1/secret/metadata/klaytn/keystore" && r.URL.Query().Get("list") == "true":
+			keys := []string{"dir/"}
+			for name := range keystores {
+				keys = append(keys, name)
+			}
+			res = map[string]interface{}{"data": map[string]interface{}{"keys": keys}}
+		case strings.HasPrefix(path, "/v1/secret/data/klaytn/keystore/"):
+			keyjson, ok := keystores[strings.TrimPrefix(path, "/v1/secret/data/klaytn/keystore/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			res = map[string]interface{}{"data": map[string]interface{}{"data": map[string]interface{}{"keystore": string(keyjson)}}}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+}
+
+func TestVaultBackend(t *testing.T) {
+	prv, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(prv.PublicKey)
+	keyjson, err := keystore.EncryptKey(keystore.NewKeyFromECDSA(prv), "pass", 2, 1)
+	assert.NoError(t, err)
+
+	server := newTestVault(t, map[string][]byte{"node": keyjson})
+	defer server.Close()
+
+	os.Setenv(tokenEnv, testToken)
+	defer os.Unsetenv(tokenEnv)
+
+	backend, err := NewBackend(Config{Address: server.URL})
+	assert.NoError(t, err)
+	defer backend.client.close()
+
+	wallets := backend.Wallets()
+	if !assert.Len(t, wallets, 1) {
+		return
+	}
+	w := wallets[0]
+	account := accounts.Account{Address: addr}
+	assert.Equal(t, accounts.URL{Scheme: URLScheme, Path: "node"}, w.URL())
+	assert.True(t, w.Contains(account))
+
+	hash := crypto.Keccak256([]byte("vault"))
+	_, err = w.SignHash(account, hash)
+	assert.Equal(t, keystore.ErrLocked, err)
+
+	_, err = w.SignHashWithPassphrase(account, "wrong", hash)
+	assert.Equal(t, keystore.ErrDecrypt, err)
+
+	sig, err := w.SignHashWithPassphrase(account, "pass", hash)
+	assert.NoError(t, err)
+	pub, err := crypto.SigToPub(hash, sig)
+	assert.NoError(t, err)
+	assert.Equal(t, addr, crypto.PubkeyToAddress(*pub))
+
+	assert.NoError(t, w.Open("pass"))
+	status, _ := w.Status()
+	assert.Equal(t, "Unlocked", status)
+
+	chainID := big.NewInt(1001)
+	tx := types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(25000000000), nil)
+	signed, err := w.SignTx(account, tx, chainID)
+	assert.NoError(t, err)
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	assert.NoError(t, err)
+	assert.Equal(t, addr, from)
+
+	_, err = w.SignTx(account, tx, nil)
+	assert.Equal(t, keystore.ErrChainIdNil, err)
+
+	assert.NoError(t, w.Close())
+	_, err = w.SignTx(account, tx, chainID)
+	assert.Equal(t, keystore.ErrLocked, err)
+}
+
+func TestVaultBackendNoAuth(t *testing.T) {
+	os.Unsetenv(tokenEnv)
+	_, err := NewBackend(Config{Address: "http://127.0.0.1:0"})
+	assert.Equal(t, errNoAuth, err)
+}
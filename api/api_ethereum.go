@@ -352,6 +352,12 @@ func (api *EthereumAPI) FeeHistory(ctx context.Context, blockCount DecimalOrHex,
 // - highestBlock:  block number of the highest block header this node has received from peers
 // - pulledStates:  number of state entries processed until now
 // - knownStates:   number of known state entries that still need to be pulled
+// - pivotBlock:    block number whose state is downloaded in fast sync
+// - resumed:       whether the sync resumed the pivot of an interrupted fast sync
+// - phase:         phase the sync is waiting for (headers, bodies, receipts, state or import)
+// - pulledHeaders, pulledBodies, pulledReceipts: number of items downloaded since the sync began
+// - headerRate, bodyRate, receiptRate, stateRate, blockRate: current rates per second
+// - eta:           estimated seconds until the sync completes, 0 if unknown
 func (api *EthereumAPI) Syncing() (interface{}, error) {
 	return api.publicKlayAPI.Syncing()
 }
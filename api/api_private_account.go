@@ -22,13 +22,16 @@ package api
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/accounts/hdwallet"
 	"github.com/klaytn/klaytn/accounts/keystore"
 	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/types/accountkey"
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/common/hexutil"
 	"github.com/klaytn/klaytn/common/math"
@@ -185,6 +188,41 @@ func (s *PrivateAccountAPI) ImportRawKey(privkey string, password string) (commo
 	return acc.Address, err
 }
 
+// ImportRoleBasedKey stores the given hex encoded ECDSA keys of the roles of the
+// address into the key directory, encrypting them with the password. The keys
+// are indexed by the role: the transaction, the account update and the fee payer
+// keys. The roles without keys are signed with the transaction keys.
+func (s *PrivateAccountAPI) ImportRoleBasedKey(address common.Address, keys [][]string, password string) (common.Address, error) {
+	prv := make([][]*ecdsa.PrivateKey, len(keys))
+	for role, hexKeys := range keys {
+		for _, hexKey := range hexKeys {
+			key, err := crypto.HexToECDSA(hexKey)
+			if err != nil {
+				return common.Address{}, fmt.Errorf("invalid %s key: %v", accountkey.RoleType(role), err)
+			}
+			prv[role] = append(prv[role], key)
+		}
+	}
+	acc, err := fetchKeystore(s.am).ImportRoleBasedKey(address, prv, password)
+	return acc.Address, err
+}
+
+// ImportMnemonic creates an HD wallet of the BIP-39 mnemonic and its optional
+// BIP-39 passphrase, encrypting the seed with the password. It returns the first
+// account of the wallet, whose URL identifies the wallet to derive the others.
+func (s *PrivateAccountAPI) ImportMnemonic(mnemonic string, password string, bip39Passphrase *string) (accounts.Account, error) {
+	backends := s.am.Backends(hdwallet.BackendType)
+	if len(backends) == 0 {
+		return accounts.Account{}, accounts.ErrNotSupported
+	}
+	passphrase := ""
+	if bip39Passphrase != nil {
+		passphrase = *bip39Passphrase
+	}
+	_, account, err := backends[0].(*hdwallet.Backend).Import(mnemonic, passphrase, password)
+	return account, err
+}
+
 // UnlockAccount will unlock the account associated with the given address with
 // the given password for duration seconds. If duration is nil it will use a
 // default of 300 seconds. It returns an indication if the account was unlocked.
@@ -402,6 +440,60 @@ func (s *PrivateAccountAPI) SignTransactionAsFeePayer(ctx context.Context, args
 	return &SignTransactionResult{data, feePayerSignedTx}, nil
 }
 
+// SignTransactionWithRole will create a transaction from the given arguments and
+// try to sign it with the keys of the role, "transaction", "accountUpdate" or
+// "feePayer", of the account. The account is args.From, or the fee payer for the
+// "feePayer" role. It fails if the transaction is not signed with the role. The
+// transaction is returned in RLP-form, not broadcast to other nodes.
+func (s *PrivateAccountAPI) SignTransactionWithRole(ctx context.Context, args SendTxArgs, role string, passwd string) (*SignTransactionResult, error) {
+	r, err := accountkey.RoleTypeFromString(role)
+	if err != nil {
+		return nil, err
+	}
+	if err := args.setDefaults(ctx, s.b); err != nil {
+		return nil, err
+	}
+	tx, err := args.toTransaction()
+	if err != nil {
+		return nil, err
+	}
+	signer := args.From
+	if r == accountkey.RoleFeePayer {
+		if args.TxSignatures != nil {
+			tx.SetSignature(args.TxSignatures.ToTxSignatures())
+		}
+		if signer, err = tx.FeePayer(); err != nil {
+			return nil, errTxArgInvalidFeePayer
+		}
+	}
+	signedTx, err := fetchKeystore(s.am).SignTxWithRoleAndPassphrase(accounts.Account{Address: signer}, passwd, r, tx, s.b.ChainConfig().ChainID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := rlp.EncodeToBytes(signedTx)
+	if err != nil {
+		return nil, err
+	}
+	return &SignTransactionResult{data, signedTx}, nil
+}
+
+// SendTransactionWithRole will create a transaction from the given arguments,
+// sign it with the keys of the role of the account like SignTransactionWithRole
+// and send it.
+func (s *PrivateAccountAPI) SendTransactionWithRole(ctx context.Context, args SendTxArgs, role string, passwd string) (common.Hash, error) {
+	if args.AccountNonce == nil {
+		// Hold the addresse's mutex around signing to prevent concurrent assignment of
+		// the same nonce to multiple accounts.
+		s.nonceLock.LockAddr(args.From)
+		defer s.nonceLock.UnlockAddr(args.From)
+	}
+	signedTx, err := s.SignTransactionWithRole(ctx, args, role, passwd)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return submitTransaction(ctx, s.b, signedTx.Tx)
+}
+
 // signHash is a helper function that calculates a hash for the given message that can be
 // safely used to calculate a signature from.
 //
@@ -0,0 +1,257 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/types/accountkey"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/rlp"
+)
+
+// The types of AccountKeyArgs.
+const (
+	accountKeyArgsNil       = "nil"
+	accountKeyArgsLegacy    = "legacy"
+	accountKeyArgsPublic    = "public"
+	accountKeyArgsFail      = "fail"
+	accountKeyArgsMultiSig  = "multisig"
+	accountKeyArgsRoleBased = "roleBased"
+)
+
+var (
+	errAccountKeyZeroThreshold = errors.New("the threshold of a multisig key should be larger than zero")
+	errAccountKeyFail          = errors.New("a fail key makes the account unable to update its key again, set force to install it")
+	errAccountKeyNilInstalled  = errors.New("a nil key can only be used for a role to keep its current key")
+)
+
+// WeightedPublicKeyArgs is a key of a multisig account key.
+type WeightedPublicKeyArgs struct {
+	Weight hexutil.Uint  `json:"weight"`
+	Key    hexutil.Bytes `json:"key"` // Compressed or uncompressed public key
+}
+
+// AccountKeyArgs describes an account key to be installed by an account update.
+type AccountKeyArgs struct {
+	Type      string                  `json:"type"`      // nil, legacy, public, fail, multisig or roleBased
+	Key       hexutil.Bytes           `json:"key"`       // Public key of a public key
+	Threshold hexutil.Uint            `json:"threshold"` // Threshold of a multisig key
+	Keys      []WeightedPublicKeyArgs `json:"keys"`      // Keys of a multisig key
+	Roles     []AccountKeyArgs        `json:"roles"`     // Keys of the transaction, account update and fee payer roles
+}
+
+// AccountUpdateArgs describes an account update built by BuildAccountUpdate.
+type AccountUpdateArgs struct {
+	From     common.Address  `json:"from"`
+	Key      AccountKeyArgs  `json:"key"`
+	Gas      *hexutil.Uint64 `json:"gas"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Nonce    *hexutil.Uint64 `json:"nonce"`
+	FeePayer *common.Address `json:"feePayer"` // Makes a fee-delegated account update if set
+	FeeRatio *types.FeeRatio `json:"feeRatio"` // Ratio of the fee paid by the fee payer
+	Force    bool            `json:"force"`    // Allows the fail keys
+}
+
+// parsePublicKey parses a compressed, uncompressed or 64-byte public key.
+func parsePublicKey(b []byte) (*accountkey.PublicKeySerializable, error) {
+	switch len(b) {
+	case 33:
+		pub, err := crypto.DecompressPubkey(b)
+		return (*accountkey.PublicKeySerializable)(pub), err
+	case 64:
+		b = append([]byte{4}, b...)
+	}
+	pub, err := crypto.UnmarshalPubkey(b)
+	return (*accountkey.PublicKeySerializable)(pub), err
+}
+
+// toAccountKey converts the arguments into the account key.
+func (args *AccountKeyArgs) toAccountKey() (accountkey.AccountKey, error) {
+	switch args.Type {
+	case accountKeyArgsNil:
+		return accountkey.NewAccountKeyNil(), nil
+	case accountKeyArgsLegacy:
+		return accountkey.NewAccountKeyLegacy(), nil
+	case accountKeyArgsFail:
+		return accountkey.NewAccountKeyFail(), nil
+	case accountKeyArgsPublic:
+		pub, err := parsePublicKey(args.Key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %v", err)
+		}
+		return accountkey.NewAccountKeyPublicWithValue((*ecdsa.PublicKey)(pub)), nil
+	case accountKeyArgsMultiSig:
+		if args.Threshold == 0 {
+			return nil, errAccountKeyZeroThreshold
+		}
+		keys := make(accountkey.WeightedPublicKeys, len(args.Keys))
+		for i, k := range args.Keys {
+			pub, err := parsePublicKey(k.Key)
+			if err != nil {
+				return nil, fmt.Errorf("invalid public key of the multisig key %d: %v", i, err)
+			}
+			keys[i] = accountkey.NewWeightedPublicKey(uint(k.Weight), pub)
+		}
+		return accountkey.NewAccountKeyWeightedMultiSigWithValues(uint(args.Threshold), keys), nil
+	case accountKeyArgsRoleBased:
+		keys := make([]accountkey.AccountKey, len(args.Roles))
+		for i := range args.Roles {
+			key, err := args.Roles[i].toAccountKey()
+			if err != nil {
+				return nil, fmt.Errorf("invalid key of the role %d: %v", i, err)
+			}
+			keys[i] = key
+		}
+		return accountkey.NewAccountKeyRoleBasedWithValues(keys), nil
+	default:
+		return nil, fmt.Errorf("unknown account key type %q", args.Type)
+	}
+}
+
+// checkAccountKey returns an error if the new key cannot replace the old key,
+// or if it would leave the account unable to update its key without force.
+func checkAccountKey(oldKey, newKey accountkey.AccountKey, blockNumber uint64, force bool) error {
+	if newKey.Type() == accountkey.AccountKeyTypeNil {
+		return errAccountKeyNilInstalled
+	}
+	if !force {
+		updateKey := newKey
+		if roleBased, ok := newKey.(*accountkey.AccountKeyRoleBased); ok && len(*roleBased) > 0 {
+			// The account update role falls back to the transaction role if not set.
+			updateKey = (*roleBased)[accountkey.RoleTransaction]
+			if len(*roleBased) > int(accountkey.RoleAccountUpdate) {
+				updateKey = (*roleBased)[accountkey.RoleAccountUpdate]
+			}
+		}
+		if updateKey.Type() == accountkey.AccountKeyTypeFail {
+			return errAccountKeyFail
+		}
+	}
+	return accountkey.CheckReplacable(oldKey, newKey, blockNumber)
+}
+
+// accountUpdateGas returns the gas of the account update installing the new key,
+// signed by all the keys of the account update role of the old key.
+func accountUpdateGas(oldKey, newKey accountkey.AccountKey, blockNumber uint64) (uint64, error) {
+	keyGas, err := newKey.AccountCreationGas(blockNumber)
+	if err != nil {
+		return 0, err
+	}
+	numSigs := 1
+	roleKey := oldKey
+	if roleBased, ok := oldKey.(*accountkey.AccountKeyRoleBased); ok {
+		roleKey = (*roleBased)[0]
+		if len(*roleBased) > int(accountkey.RoleAccountUpdate) {
+			roleKey = (*roleBased)[accountkey.RoleAccountUpdate]
+		}
+	}
+	if multiSig, ok := roleKey.(*accountkey.AccountKeyWeightedMultiSig); ok {
+		numSigs = len(multiSig.Keys)
+	}
+	sigGas, err := oldKey.SigValidationGas(blockNumber, accountkey.RoleAccountUpdate, numSigs)
+	if err != nil {
+		return 0, err
+	}
+	return params.TxGasAccountUpdate + keyGas + sigGas, nil
+}
+
+// BuildAccountKey validates the account key and returns its RLP encoding, which
+// is used as the key of the account updates.
+func (s *PublicTransactionPoolAPI) BuildAccountKey(args AccountKeyArgs) (hexutil.Bytes, error) {
+	key, err := args.toAccountKey()
+	if err != nil {
+		return nil, err
+	}
+	if key.Type() != accountkey.AccountKeyTypeNil {
+		if err := key.CheckInstallable(s.b.CurrentBlock().NumberU64()); err != nil {
+			return nil, err
+		}
+	}
+	return rlp.EncodeToBytes(accountkey.NewAccountKeySerializerWithAccountKey(key))
+}
+
+// BuildAccountUpdate builds the account update replacing the key of the account
+// after checking that the new key can replace the current one, and returns the
+// transaction arguments to be signed or sent by klay_signTransaction or
+// klay_sendTransaction. The fail keys of the account update role are refused
+// unless forced since the account cannot update its key anymore.
+func (s *PublicTransactionPoolAPI) BuildAccountUpdate(ctx context.Context, args AccountUpdateArgs) (*SendTxArgs, error) {
+	newKey, err := args.Key.toAccountKey()
+	if err != nil {
+		return nil, err
+	}
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	oldKey := state.GetKey(args.From)
+	blockNumber := header.Number.Uint64()
+	if err := checkAccountKey(oldKey, newKey, blockNumber, args.Force); err != nil {
+		return nil, err
+	}
+	key, err := rlp.EncodeToBytes(accountkey.NewAccountKeySerializerWithAccountKey(newKey))
+	if err != nil {
+		return nil, err
+	}
+
+	txType := types.TxTypeAccountUpdate
+	switch {
+	case args.FeePayer != nil && args.FeeRatio != nil:
+		txType = types.TxTypeFeeDelegatedAccountUpdateWithRatio
+	case args.FeePayer != nil:
+		txType = types.TxTypeFeeDelegatedAccountUpdate
+	case args.FeeRatio != nil:
+		return nil, errTxArgInvalidFeePayer
+	}
+	if args.Gas == nil {
+		gas, err := accountUpdateGas(oldKey, newKey, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		args.Gas = (*hexutil.Uint64)(&gas)
+	}
+	if args.GasPrice == nil {
+		price, err := s.b.SuggestPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
+		args.GasPrice = (*hexutil.Big)(price)
+	}
+	if args.Nonce == nil {
+		nonce := s.b.GetPoolNonce(ctx, args.From)
+		args.Nonce = (*hexutil.Uint64)(&nonce)
+	}
+	return &SendTxArgs{
+		TypeInt:      &txType,
+		From:         args.From,
+		GasLimit:     args.Gas,
+		Price:        args.GasPrice,
+		AccountNonce: args.Nonce,
+		Key:          (*hexutil.Bytes)(&key),
+		FeePayer:     args.FeePayer,
+		FeeRatio:     args.FeeRatio,
+	}, nil
+}
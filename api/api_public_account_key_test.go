@@ -0,0 +1,95 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain/types/accountkey"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/fork"
+	"github.com/klaytn/klaytn/kerrors"
+	"github.com/klaytn/klaytn/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountKeyArgs(t *testing.T) {
+	var pubs []hexutil.Bytes
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		pubs = append(pubs, crypto.CompressPubkey(&key.PublicKey))
+	}
+	uncompressed, _ := crypto.DecompressPubkey(pubs[0])
+
+	multiSig := AccountKeyArgs{Type: "multisig", Threshold: 2, Keys: []WeightedPublicKeyArgs{
+		{Weight: 1, Key: pubs[0]}, {Weight: 1, Key: pubs[1]}, {Weight: 1, Key: pubs[2]},
+	}}
+	testcases := []struct {
+		args AccountKeyArgs
+		err  error
+	}{
+		{AccountKeyArgs{Type: "legacy"}, nil},
+		{AccountKeyArgs{Type: "public", Key: pubs[0]}, nil},
+		{AccountKeyArgs{Type: "public", Key: crypto.FromECDSAPub(uncompressed)}, nil},
+		{multiSig, nil},
+		{AccountKeyArgs{Type: "roleBased", Roles: []AccountKeyArgs{multiSig, {Type: "public", Key: pubs[1]}}}, nil},
+		{AccountKeyArgs{Type: "multisig", Threshold: 3, Keys: multiSig.Keys[:2]}, kerrors.ErrUnsatisfiableThreshold},
+		{AccountKeyArgs{Type: "multisig", Threshold: 1, Keys: []WeightedPublicKeyArgs{{1, pubs[0]}, {1, pubs[0]}}}, kerrors.ErrDuplicatedKey},
+		{AccountKeyArgs{Type: "multisig", Keys: multiSig.Keys}, errAccountKeyZeroThreshold},
+		{AccountKeyArgs{Type: "fail"}, errAccountKeyFail},
+		{AccountKeyArgs{Type: "roleBased", Roles: []AccountKeyArgs{{Type: "legacy"}, {Type: "fail"}}}, errAccountKeyFail},
+		{AccountKeyArgs{Type: "roleBased", Roles: []AccountKeyArgs{{Type: "roleBased"}}}, kerrors.ErrNestedCompositeType},
+		{AccountKeyArgs{Type: "nil"}, errAccountKeyNilInstalled},
+	}
+	for i, tc := range testcases {
+		key, err := tc.args.toAccountKey()
+		if err == nil {
+			err = checkAccountKey(accountkey.NewAccountKeyLegacy(), key, 0, false)
+		}
+		assert.Equal(t, tc.err, err, "testcase %d", i)
+	}
+
+	_, err := (&AccountKeyArgs{Type: "public", Key: pubs[0][1:]}).toAccountKey()
+	assert.Error(t, err)
+
+	// A fail key is installed with force.
+	key, _ := (&AccountKeyArgs{Type: "fail"}).toAccountKey()
+	assert.NoError(t, checkAccountKey(accountkey.NewAccountKeyLegacy(), key, 0, true))
+}
+
+func TestAccountUpdateGas(t *testing.T) {
+	fork.SetHardForkBlockNumberConfig(&params.ChainConfig{})
+
+	multiSigArgs := AccountKeyArgs{Type: "multisig", Threshold: 1}
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		multiSigArgs.Keys = append(multiSigArgs.Keys, WeightedPublicKeyArgs{Weight: 1, Key: crypto.CompressPubkey(&key.PublicKey)})
+	}
+	multiSig, err := multiSigArgs.toAccountKey()
+	assert.NoError(t, err)
+
+	// From a legacy key to the multisig key with 3 keys
+	gas, err := accountUpdateGas(accountkey.NewAccountKeyLegacy(), multiSig, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, params.TxGasAccountUpdate+3*params.TxAccountCreationGasPerKey, gas)
+
+	// From the multisig key, signed by its 3 keys
+	gas, err = accountUpdateGas(multiSig, accountkey.NewAccountKeyLegacy(), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, params.TxGasAccountUpdate+2*params.TxValidationGasPerKey, gas)
+}
@@ -108,6 +108,12 @@ func (s *PublicKlayAPI) FeeHistory(ctx context.Context, blockCount DecimalOrHex,
 // - highestBlock:  block number of the highest block header this node has received from peers
 // - pulledStates:  number of state entries processed until now
 // - knownStates:   number of known state entries that still need to be pulled
+// - pivotBlock:    block number whose state is downloaded in fast sync
+// - resumed:       whether the sync resumed the pivot of an interrupted fast sync
+// - phase:         phase the sync is waiting for (headers, bodies, receipts, state or import)
+// - pulledHeaders, pulledBodies, pulledReceipts: number of items downloaded since the sync began
+// - headerRate, bodyRate, receiptRate, stateRate, blockRate: current rates per second
+// - eta:           estimated seconds until the sync completes, 0 if unknown
 func (s *PublicKlayAPI) Syncing() (interface{}, error) {
 	progress := s.b.Progress()
 
@@ -122,6 +128,19 @@ func (s *PublicKlayAPI) Syncing() (interface{}, error) {
 		"highestBlock":  hexutil.Uint64(progress.HighestBlock),
 		"pulledStates":  hexutil.Uint64(progress.PulledStates),
 		"knownStates":   hexutil.Uint64(progress.KnownStates),
+		"pivotBlock":    hexutil.Uint64(progress.PivotBlock),
+		"resumed":       progress.Resumed,
+
+		"phase":          progress.Phase,
+		"pulledHeaders":  hexutil.Uint64(progress.PulledHeaders),
+		"pulledBodies":   hexutil.Uint64(progress.PulledBodies),
+		"pulledReceipts": hexutil.Uint64(progress.PulledReceipts),
+		"headerRate":     progress.HeaderRate,
+		"bodyRate":       progress.BodyRate,
+		"receiptRate":    progress.ReceiptRate,
+		"stateRate":      progress.StateRate,
+		"blockRate":      progress.BlockRate,
+		"eta":            hexutil.Uint64(progress.ETA),
 	}, nil
 }
 
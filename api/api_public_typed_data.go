@@ -0,0 +1,125 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/accounts/typeddata"
+	"github.com/klaytn/klaytn/blockchain/types/accountkey"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/networks/rpc"
+)
+
+var errNoTypedDataSignature = errors.New("no signature of the typed data")
+
+// SignTypedDataResult is the signature of EIP-712 typed data with its hash and
+// its formatted domain and message, which the callers show to the users to
+// confirm what has been signed.
+type SignTypedDataResult struct {
+	Signature hexutil.Bytes              `json:"signature"`
+	Hash      common.Hash                `json:"hash"`
+	Data      []*typeddata.NameValueType `json:"data"`
+}
+
+// typedDataHash returns the hash and the formatted data of the typed data,
+// refusing the data of another chain.
+func typedDataHash(data *typeddata.TypedData, chainID *big.Int) (common.Hash, []*typeddata.NameValueType, error) {
+	if data.Domain.ChainId != nil && (*big.Int)(data.Domain.ChainId).Cmp(chainID) != 0 {
+		return common.Hash{}, nil, fmt.Errorf("the chain ID of the domain %v is not the chain ID %v", (*big.Int)(data.Domain.ChainId), chainID)
+	}
+	hash, err := data.Hash()
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+	formatted, err := data.Format()
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+	return hash, formatted, nil
+}
+
+// SignTypedData signs EIP-712 typed data with the unlocked account, e.g. the
+// permit of a token or a meta-transaction. The V value of the signature is 27
+// or 28 like klay_sign.
+func (s *PublicTransactionPoolAPI) SignTypedData(addr common.Address, data typeddata.TypedData) (*SignTypedDataResult, error) {
+	hash, formatted, err := typedDataHash(&data, s.b.ChainConfig().ChainID)
+	if err != nil {
+		return nil, err
+	}
+	// Look up the wallet containing the requested signer
+	account := accounts.Account{Address: addr}
+
+	wallet, err := s.b.AccountManager().Find(account)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := wallet.SignHash(account, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	signature[crypto.RecoveryIDOffset] += 27 // Transform V from 0/1 to 27/28 according to the yellow paper
+	return &SignTypedDataResult{Signature: signature, Hash: hash, Data: formatted}, nil
+}
+
+// VerifyTypedData returns true if the signatures of EIP-712 typed data satisfy
+// the account key of the address at the block, e.g. all the signatures of the
+// keys of a weighted multisig key reaching its threshold. The V values of the
+// signatures are 0/1 or 27/28.
+func (s *PublicTransactionPoolAPI) VerifyTypedData(ctx context.Context, addr common.Address, data typeddata.TypedData, sigs []hexutil.Bytes, blockNrOrHash rpc.BlockNumberOrHash) (bool, error) {
+	if len(sigs) == 0 {
+		return false, errNoTypedDataSignature
+	}
+	hash, _, err := typedDataHash(&data, s.b.ChainConfig().ChainID)
+	if err != nil {
+		return false, err
+	}
+	pubs := make([]*ecdsa.PublicKey, len(sigs))
+	for i, sig := range sigs {
+		if pubs[i], err = recoverTypedDataSigner(hash, sig); err != nil {
+			return false, err
+		}
+	}
+	state, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return false, err
+	}
+	key := state.GetKey(addr)
+	return accountkey.ValidateAccountKey(header.Number.Uint64(), addr, key, pubs, accountkey.RoleTransaction) == nil, nil
+}
+
+// recoverTypedDataSigner returns the public key signing the hash.
+func recoverTypedDataSigner(hash common.Hash, sig hexutil.Bytes) (*ecdsa.PublicKey, error) {
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("signature must be %d bytes long", crypto.SignatureLength)
+	}
+	sig = common.CopyBytes(sig)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27 // Transform yellow paper V from 27/28 to 0/1
+	}
+	if sig[crypto.RecoveryIDOffset] > 1 {
+		return nil, errors.New("invalid signature (V is not 0, 1, 27 or 28)")
+	}
+	return crypto.SigToPub(hash[:], sig)
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn/accounts/typeddata"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "chainId", "type": "uint256"}
+		],
+		"Permit": [
+			{"name": "owner", "type": "address"},
+			{"name": "value", "type": "uint256"}
+		]
+	},
+	"primaryType": "Permit",
+	"domain": {"name": "Token", "chainId": 1001},
+	"message": {"owner": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826", "value": "1000000000000000000000"}
+}`
+
+func TestTypedDataHash(t *testing.T) {
+	data := new(typeddata.TypedData)
+	require.NoError(t, json.Unmarshal([]byte(testTypedData), data))
+
+	// The data of another chain is refused.
+	_, _, err := typedDataHash(data, big.NewInt(8217))
+	assert.Error(t, err)
+
+	hash, formatted, err := typedDataHash(data, big.NewInt(1001))
+	require.NoError(t, err)
+	require.Len(t, formatted, 2)
+	assert.Equal(t, "1000000000000000000000", formatted[1].Value.([]*typeddata.NameValueType)[1].Value)
+
+	key, _ := crypto.GenerateKey()
+	sig, err := crypto.Sign(hash[:], key)
+	require.NoError(t, err)
+	for _, v := range []byte{0, 27} {
+		sig[crypto.RecoveryIDOffset] += v
+		pub, err := recoverTypedDataSigner(hash, sig)
+		require.NoError(t, err)
+		assert.Equal(t, key.PublicKey, *pub)
+	}
+	_, err = recoverTypedDataSigner(hash, sig[:64])
+	assert.Error(t, err)
+}
@@ -31,6 +31,7 @@ import (
 	"runtime"
 	"runtime/debug"
 	"runtime/pprof"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -62,8 +63,12 @@ type HandlerT struct {
 	handlerInited bool
 	pprofServer   *http.Server
 
-	logDir    string   // log directory path
-	vmLogFile *os.File // a file descriptor of the vmlog output file
+	logDir      string             // log directory path
+	vmLogFile   *log.RotatingFile  // a file descriptor of the vmlog output file
+	logFile     *log.RotatingFile  // the log output file if given by --log.file
+	logRotation log.RotationConfig // rotation policy of the log files
+	logFormat   string             // format of the logs given by --log.format
+	logPath     string             // path of the log output file
 }
 
 // Verbosity sets the log verbosity ceiling. The verbosity of individual packages
@@ -84,6 +89,70 @@ func (*HandlerT) VerbosityByID(mi int, level int) error {
 	return log.ChangeLogLevelWithID(log.ModuleID(mi), log.Lvl(level))
 }
 
+// SetModuleLogLevel sets the log level of the module, like "trace" for
+// "consensus/istanbul". It overrides the global verbosity and the vmodule
+// patterns for the logs of the module. The level "reset" removes it.
+func (*HandlerT) SetModuleLogLevel(moduleName, level string) error {
+	mi := log.GetModuleID(moduleName)
+	if mi == log.ModuleNameLen {
+		return fmt.Errorf("unknown log module %q", moduleName)
+	}
+	if level == "reset" {
+		glogger.ResetModuleLevel(mi)
+		return nil
+	}
+	lvl, err := log.LvlFromString(level)
+	if err != nil {
+		return err
+	}
+	glogger.SetModuleLevel(mi, lvl)
+	return nil
+}
+
+// LogConfig is the current logging configuration.
+type LogConfig struct {
+	Verbosity    string            `json:"verbosity"`
+	Vmodule      string            `json:"vmodule"`
+	ModuleLevels map[string]string `json:"moduleLevels"` // levels set by SetModuleLogLevel
+	Format       string            `json:"format"`
+	File         string            `json:"file"` // empty if the logs are written to the standard error
+	Modules      []string          `json:"modules"`
+}
+
+// GetLogConfig returns the current logging configuration and the names of the
+// log modules.
+func (h *HandlerT) GetLogConfig() *LogConfig {
+	config := &LogConfig{
+		Verbosity:    levelName(glogger.Level()),
+		Vmodule:      glogger.VmoduleRuleset(),
+		ModuleLevels: make(map[string]string),
+		Format:       h.logFormat,
+		File:         h.logPath,
+	}
+	for mi, lvl := range glogger.ModuleLevels() {
+		config.ModuleLevels[log.GetModuleName(mi)] = levelName(lvl)
+	}
+	for mi := log.ModuleID(0); mi < log.ModuleNameLen; mi++ {
+		config.Modules = append(config.Modules, log.GetModuleName(mi))
+	}
+	return config
+}
+
+// LogFilePath returns the path of the log output file given by --log.file, or
+// "" if the logs are written to the standard error.
+func (h *HandlerT) LogFilePath() string {
+	return h.logPath
+}
+
+// levelName returns the name of the level, or the number if it is out of the
+// named levels, like the silent verbosity.
+func levelName(lvl log.Lvl) string {
+	if lvl < log.LvlCrit || lvl >= log.LvlEnd {
+		return strconv.Itoa(int(lvl))
+	}
+	return strings.ToLower(lvl.AlignedString())
+}
+
 // Vmodule sets the log verbosity pattern. See package log for details on the
 // pattern syntax.
 func (*HandlerT) Vmodule(pattern string) error {
@@ -345,18 +414,19 @@ func (h *HandlerT) WriteVMLog(msg string) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	if h.vmLogFile != nil {
-		if _, err := h.vmLogFile.WriteString(msg + "\n"); err != nil {
+		if _, err := h.vmLogFile.Write([]byte(msg + "\n")); err != nil {
 			// Since vmlog is a debugging feature, write failure can be treated as a warning.
 			logger.Warn("Failed to write to a vmlog file", "msg", msg, "err", err)
 		}
 	}
 }
 
-// openVMLogFile opens a file for vmlog output as the append mode.
+// openVMLogFile opens a file for vmlog output as the append mode. The file is
+// rotated by the same policy as the log file.
 func (h *HandlerT) openVMLogFile() {
 	var err error
 	filename := filepath.Join(h.logDir, "vm.log")
-	Handler.vmLogFile, err = os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	Handler.vmLogFile, err = log.NewRotatingFile(filename, h.logRotation)
 	if err != nil {
 		logger.Warn("Failed to open a file", "filename", filename, "err", err)
 	}
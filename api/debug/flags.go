@@ -91,6 +91,35 @@ var (
 		Name:  "trace",
 		Usage: "Write execution trace to the given file",
 	}
+	logFormatFlag = cli.StringFlag{
+		Name:  "log.format",
+		Usage: "Format of the logs: terminal or json (one structured JSON object per line)",
+		Value: "terminal",
+	}
+	logFileFlag = cli.StringFlag{
+		Name:  "log.file",
+		Usage: "Write the logs to the given file instead of the standard error",
+	}
+	logRotateSizeFlag = cli.IntFlag{
+		Name:  "log.rotate.size",
+		Usage: "Rotate the log files when they exceed the size in MiB (0 = no size based rotation)",
+	}
+	logRotateIntervalFlag = cli.DurationFlag{
+		Name:  "log.rotate.interval",
+		Usage: "Rotate the log files at the interval, like 24h (0 = no time based rotation)",
+	}
+	logRotateMaxBackupsFlag = cli.IntFlag{
+		Name:  "log.rotate.max-backups",
+		Usage: "Number of the rotated log files to keep per log file (0 = all)",
+	}
+	logRotateMaxAgeFlag = cli.DurationFlag{
+		Name:  "log.rotate.max-age",
+		Usage: "Remove the rotated log files older than the duration, like 720h (0 = no removal by age)",
+	}
+	logRotateCompressFlag = cli.BoolFlag{
+		Name:  "log.rotate.compress",
+		Usage: "Compress the rotated log files with gzip",
+	}
 )
 
 // Flags holds all command-line flags required for debugging.
@@ -99,8 +128,17 @@ var Flags = []cli.Flag{
 	pprofFlag, pprofAddrFlag, pprofPortFlag,
 	memprofileFlag, memprofilerateFlag,
 	blockprofilerateFlag, cpuprofileFlag, traceFlag,
+	logFormatFlag, logFileFlag, logRotateSizeFlag, logRotateIntervalFlag,
+	logRotateMaxBackupsFlag, logRotateMaxAgeFlag, logRotateCompressFlag,
 }
 
+// VerbosityFlagName and VmoduleFlagName are the names of the logging flags,
+// which override the log levels of the configuration file.
+var (
+	VerbosityFlagName = verbosityFlag.Name
+	VmoduleFlagName   = vmoduleFlag.Name
+)
+
 var glogger *log.GlogHandler
 
 func init() {
@@ -136,6 +174,38 @@ func CreateLogDir(logDir string) {
 // It should be called as early as possible in the program.
 func Setup(ctx *cli.Context) error {
 	// logging
+	Handler.logRotation = log.RotationConfig{
+		MaxSize:    int64(ctx.GlobalInt(logRotateSizeFlag.Name)) * 1024 * 1024,
+		Interval:   ctx.GlobalDuration(logRotateIntervalFlag.Name),
+		MaxBackups: ctx.GlobalInt(logRotateMaxBackupsFlag.Name),
+		MaxAge:     ctx.GlobalDuration(logRotateMaxAgeFlag.Name),
+		Compress:   ctx.GlobalBool(logRotateCompressFlag.Name),
+	}
+	var format log.Format
+	Handler.logFormat = ctx.GlobalString(logFormatFlag.Name)
+	switch name := Handler.logFormat; name {
+	case "terminal":
+		format = log.TerminalFormat(false)
+	case "json":
+		format = log.StructuredJsonFormat()
+	default:
+		return fmt.Errorf("invalid --%s %q, want terminal or json", logFormatFlag.Name, name)
+	}
+	output := io.Writer(nil)
+	if path := ctx.GlobalString(logFileFlag.Name); path != "" {
+		f, err := log.NewRotatingFile(path, Handler.logRotation)
+		if err != nil {
+			return err
+		}
+		Handler.logFile, Handler.logPath = f, path
+		output = f
+	} else if ctx.GlobalString(logFormatFlag.Name) == "json" {
+		output = os.Stderr
+	}
+	// The standard error in the terminal format keeps the handler of init.
+	if output != nil {
+		glogger = log.NewGlogHandler(log.StreamHandler(output, format))
+	}
 	log.PrintOrigins(ctx.GlobalBool(debugFlag.Name))
 	if err := log.ChangeGlobalLogLevel(glogger, log.Lvl(ctx.GlobalInt(verbosityFlag.Name))); err != nil {
 		return err
@@ -181,6 +251,9 @@ func Exit() {
 		Handler.vmLogFile.Close()
 		Handler.vmLogFile = nil
 	}
+	if Handler.logFile != nil {
+		Handler.logFile.Close()
+	}
 	if Handler.memFile != "" {
 		Handler.WriteMemProfile(Handler.memFile)
 	}
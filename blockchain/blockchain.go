@@ -28,7 +28,6 @@ import (
 	mrand "math/rand"
 	"reflect"
 	"runtime"
-	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -129,6 +128,7 @@ type CacheConfig struct {
 	SenderTxHashIndexing bool                         // Enables saving senderTxHash to txHash mapping information to database and cache
 	TrieNodeCacheConfig  *statedb.TrieNodeCacheConfig // Configures trie node cache
 	SnapshotCacheSize    int                          // Memory allowance (MB) to use for caching snapshot entries in memory
+	TrieNodeRefCount     bool                         // Enables reference counting of trie nodes to prune the state of old blocks (archive mode only)
 }
 
 // gcBlock is used for priority queue for GC.
@@ -207,6 +207,15 @@ type BlockChain struct {
 	lastCommittedBlock uint64
 	quitWarmUp         chan struct{}
 
+	// Trie node reference counting
+	trieNodeRefMu     sync.Mutex // Lock for updating the reference range
+	pruningStateTries int32      // Whether the state tries are being pruned or not
+
+	creatingDBSnapshot int32 // Whether a database snapshot is being created or not
+
+	writeFreezeMu sync.Mutex   // Lock for freezing and thawing the database writes
+	writeFreeze   *writeFreeze // Freeze of the database writes in progress, if any
+
 	prefetchTxCh chan prefetchTx
 }
 
@@ -316,6 +325,11 @@ func NewBlockChain(db database.DBManager, cacheConfig *CacheConfig, chainConfig
 			}
 		}
 	}
+	if bc.cacheConfig.TrieNodeRefCount {
+		if err := bc.setupTrieNodeRefCount(); err != nil {
+			return nil, err
+		}
+	}
 	// Check the current state of the block hashes and make sure that we do not have any of the bad blocks in our chain
 	for hash := range BadHashes {
 		if header := bc.GetHeaderByHash(hash); header != nil {
@@ -636,9 +650,10 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, root common.Hash, repair bo
 
 	// Rewind the header chain, deleting all block bodies until then
 	delFn := func(hash common.Hash, num uint64) {
-		// Remove relative body and receipts from the active store.
+		// Remove relative lookup entries, body and receipts from the active store.
 		// The header, total difficulty and canonical hash will be
 		// removed in the hc.SetHead function.
+		deleteTxLookupEntries(bc.db, hash, num)
 		bc.db.DeleteBody(hash, num)
 		bc.db.DeleteReceipts(hash, num)
 	}
@@ -656,6 +671,9 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, root common.Hash, repair bo
 		if err := bc.hc.SetHead(head, updateFn, delFn); err != nil {
 			return 0, err
 		}
+		if err := bc.db.RewindColdStorage(bc.hc.CurrentHeader().Number.Uint64() + 1); err != nil {
+			return 0, err
+		}
 	}
 
 	// Clear out any stale content from the caches
@@ -1066,15 +1084,14 @@ func (bc *BlockChain) Stop() {
 	triedb := bc.stateCache.TrieDB()
 	if !bc.isArchiveMode() {
 		number := bc.CurrentBlock().NumberU64()
-		recent := bc.GetBlockByNumber(number)
-		if recent == nil {
+		// The other caches are released even if the recent state is not written.
+		if recent := bc.GetBlockByNumber(number); recent == nil {
 			logger.Error("Failed to find recent block from persistent", "blockNumber", number)
-			return
-		}
-
-		logger.Info("Writing cached state to disk", "block", recent.Number(), "hash", recent.Hash(), "root", recent.Root())
-		if err := triedb.Commit(recent.Root(), true, number); err != nil {
-			logger.Error("Failed to commit recent state trie", "err", err)
+		} else {
+			logger.Info("Writing cached state to disk", "block", recent.Number(), "hash", recent.Hash(), "root", recent.Root())
+			if err := triedb.Commit(recent.Root(), true, number); err != nil {
+				logger.Error("Failed to commit recent state trie", "err", err)
+			}
 		}
 		if snapBase != (common.Hash{}) {
 			logger.Info("Writing snapshot state to disk", "root", snapBase)
@@ -1334,6 +1351,9 @@ func (bc *BlockChain) writeStateTrie(block *types.Block, state *state.StateDB) e
 		if err := trieDB.Commit(root, false, block.NumberU64()); err != nil {
 			return err
 		}
+		if bc.cacheConfig.TrieNodeRefCount {
+			bc.updateTrieNodeRefHead(block.NumberU64())
+		}
 
 		bc.checkStartStateMigration(block.NumberU64(), root)
 		bc.lastCommittedBlock = block.NumberU64()
@@ -2665,18 +2685,8 @@ func GetInternalTxTrace(tracer vm.Tracer) (*vm.InternalTxTrace, error) {
 	return internalTxTrace, nil
 }
 
-// CheckBlockChainVersion checks the version of the current database and upgrade if possible.
+// CheckBlockChainVersion checks the version of the current database and upgrade
+// it by the schema migrations if possible.
 func CheckBlockChainVersion(chainDB database.DBManager) error {
-	bcVersion := chainDB.ReadDatabaseVersion()
-	if bcVersion != nil && *bcVersion > BlockChainVersion {
-		return fmt.Errorf("database version is v%d, Klaytn %s only supports v%d", *bcVersion, params.Version, BlockChainVersion)
-	} else if bcVersion == nil || *bcVersion < BlockChainVersion {
-		bcVersionStr := "N/A"
-		if bcVersion != nil {
-			bcVersionStr = strconv.Itoa(int(*bcVersion))
-		}
-		logger.Warn("Upgrade database version", "from", bcVersionStr, "to", BlockChainVersion)
-		chainDB.WriteDatabaseVersion(BlockChainVersion)
-	}
-	return nil
+	return migrateSchema(chainDB, schemaMigrations, BlockChainVersion)
 }
@@ -0,0 +1,118 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"errors"
+	"time"
+)
+
+// MaxWriteFreezeTimeout is the maximum time the database writes can be frozen,
+// after which they are thawed automatically to keep the node in sync.
+const MaxWriteFreezeTimeout = 10 * time.Minute
+
+var (
+	ErrWritesAlreadyFrozen = errors.New("the database writes are already frozen")
+	ErrWritesNotFrozen     = errors.New("the database writes are not frozen")
+)
+
+// writeFreeze is a freeze of the database writes, which holds the chain lock
+// until it is thawed or timed out.
+type writeFreeze struct {
+	thaw chan struct{} // closed to thaw the writes
+	done chan struct{} // closed when the writes are thawed
+}
+
+// FreezeWrites stops the block insertion at a consistent block boundary, flushes
+// the state of the current block and syncs the databases to the stable storage,
+// so that a filesystem snapshot taken while frozen can be opened without recovery.
+// The other writers of the database, e.g. the snapshot generator, the pruning
+// and the governance, are blocked at the DBManager while frozen.
+// The writes are thawed by ThawWrites, or automatically after the timeout.
+func (bc *BlockChain) FreezeWrites(timeout time.Duration) error {
+	bc.writeFreezeMu.Lock()
+	defer bc.writeFreezeMu.Unlock()
+
+	if bc.writeFreeze != nil {
+		select {
+		case <-bc.writeFreeze.done:
+		default:
+			return ErrWritesAlreadyFrozen
+		}
+	}
+	if timeout <= 0 || timeout > MaxWriteFreezeTimeout {
+		timeout = MaxWriteFreezeTimeout
+	}
+
+	bc.mu.Lock()
+	current := bc.CurrentBlock()
+	if !bc.isArchiveMode() {
+		if err := bc.stateCache.TrieDB().Commit(current.Root(), true, current.NumberU64()); err != nil {
+			bc.mu.Unlock()
+			return err
+		}
+	}
+	bc.db.FreezeWrites()
+	if err := bc.db.Sync(); err != nil {
+		bc.db.ThawWrites()
+		bc.mu.Unlock()
+		return err
+	}
+
+	freeze := &writeFreeze{thaw: make(chan struct{}), done: make(chan struct{})}
+	bc.writeFreeze = freeze
+
+	bc.wg.Add(1)
+	go func() {
+		defer bc.wg.Done()
+		defer close(freeze.done)
+		defer bc.mu.Unlock()
+		defer bc.db.ThawWrites()
+
+		start := time.Now()
+		select {
+		case <-freeze.thaw:
+			logger.Info("Thawed the database writes", "frozen", time.Since(start))
+		case <-time.After(timeout):
+			logger.Warn("Thawed the database writes after the timeout", "timeout", timeout)
+		case <-bc.quit:
+		}
+	}()
+	logger.Info("Froze the database writes", "number", current.NumberU64(), "hash", current.Hash(), "timeout", timeout)
+	return nil
+}
+
+// ThawWrites resumes the database writes frozen by FreezeWrites.
+func (bc *BlockChain) ThawWrites() error {
+	bc.writeFreezeMu.Lock()
+	defer bc.writeFreezeMu.Unlock()
+
+	freeze := bc.writeFreeze
+	if freeze == nil {
+		return ErrWritesNotFrozen
+	}
+	bc.writeFreeze = nil
+
+	select {
+	case <-freeze.done:
+		return ErrWritesNotFrozen
+	default:
+	}
+	close(freeze.thaw)
+	<-freeze.done
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn/consensus/gxhash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockChain_FreezeWrites(t *testing.T) {
+	db, bc, err := newCanonical(gxhash.NewFaker(), 2, true)
+	if err != nil {
+		t.Fatalf("failed to create pristine chain: %v", err)
+	}
+	defer bc.Stop()
+
+	blocks := makeBlockChain(bc.CurrentBlock(), 1, gxhash.NewFaker(), db, canonicalSeed)
+
+	assert.Equal(t, ErrWritesNotFrozen, bc.ThawWrites())
+	assert.NoError(t, bc.FreezeWrites(time.Minute))
+	assert.Equal(t, ErrWritesAlreadyFrozen, bc.FreezeWrites(time.Minute))
+
+	// The block insertion waits until the writes are thawed.
+	inserted := make(chan error)
+	go func() {
+		_, err := bc.InsertChain(blocks)
+		inserted <- err
+	}()
+	select {
+	case <-inserted:
+		t.Fatal("a block is inserted while the writes are frozen")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	assert.NoError(t, bc.ThawWrites())
+	assert.NoError(t, <-inserted)
+	assert.Equal(t, uint64(3), bc.CurrentBlock().NumberU64())
+}
+
+func TestBlockChain_FreezeWrites_Timeout(t *testing.T) {
+	_, bc, err := newCanonical(gxhash.NewFaker(), 1, true)
+	if err != nil {
+		t.Fatalf("failed to create pristine chain: %v", err)
+	}
+	defer bc.Stop()
+
+	assert.NoError(t, bc.FreezeWrites(10*time.Millisecond))
+	time.Sleep(100 * time.Millisecond)
+
+	// The writes have been thawed by the timeout, so they can be frozen again.
+	assert.NoError(t, bc.FreezeWrites(time.Minute))
+	assert.NoError(t, bc.ThawWrites())
+}
+
+func TestBlockChain_FreezeWrites_OtherWriters(t *testing.T) {
+	db, bc, err := newCanonical(gxhash.NewFaker(), 1, true)
+	if err != nil {
+		t.Fatalf("failed to create pristine chain: %v", err)
+	}
+	defer bc.Stop()
+
+	assert.NoError(t, bc.FreezeWrites(time.Minute))
+
+	// The writers other than the block insertion wait until the writes are thawed,
+	// while the reads are served.
+	written := make(chan struct{})
+	go func() {
+		assert.NoError(t, db.WriteGovernanceIdx(1000))
+		close(written)
+	}()
+	select {
+	case <-written:
+		t.Fatal("a governance history is written while the writes are frozen")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.Equal(t, bc.CurrentBlock().Hash(), db.ReadCanonicalHash(bc.CurrentBlock().NumberU64()))
+
+	assert.NoError(t, bc.ThawWrites())
+	<-written
+	idx, err := db.ReadRecentGovernanceIdx(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1000}, idx)
+}
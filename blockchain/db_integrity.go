@@ -0,0 +1,126 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+// IntegrityProblem is a problem of the chain data found by CheckIntegrity,
+// which would stop the node in the middle of the sync.
+type IntegrityProblem struct {
+	Problem     string
+	Remediation string
+}
+
+// IntegrityError is returned by CheckIntegrity with all the problems found.
+type IntegrityError struct {
+	Problems []IntegrityProblem
+}
+
+func (e *IntegrityError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d problem(s) found in the chain data", len(e.Problems))
+	for i, p := range e.Problems {
+		fmt.Fprintf(&b, "\n  %d. %s\n     remediation: %s", i+1, p.Problem, p.Remediation)
+	}
+	return b.String()
+}
+
+// CheckIntegrity checks the chain data at the startup, before the node starts
+// to sync: the genesis block and its chain config, the head pointers and the
+// continuity of the cold storage. It only reads the local metadata, so it takes
+// no time even on an archive node. The deeper checks are done by VerifyDatabase.
+func CheckIntegrity(db database.DBManager, genesisHash common.Hash) error {
+	var problems []IntegrityProblem
+	add := func(remediation, format string, args ...interface{}) {
+		problems = append(problems, IntegrityProblem{Problem: fmt.Sprintf(format, args...), Remediation: remediation})
+	}
+	const (
+		restore = "restore the data directory from a backup or a chaindata snapshot, or remove the chaindata directory to sync from the genesis"
+		verify  = "run the `db verify --repair` command with the same data directory to rewind the head block"
+	)
+
+	if stored := db.ReadCanonicalHash(0); stored != genesisHash {
+		add("use the data directory of the network given by --networkid or the genesis file",
+			"the genesis block in the database is %x, but %x is configured", stored, genesisHash)
+	} else if db.ReadChainConfig(genesisHash) == nil {
+		add("initialize the data directory again by the `init` command with the genesis file",
+			"no chain config is stored for the genesis block %x", genesisHash)
+	}
+
+	status := db.Status()
+	var headHeader *uint64
+	for _, head := range []struct {
+		name    string
+		pointer database.HeadPointer
+	}{
+		{"head header", status.HeadHeader},
+		{"head block", status.HeadBlock},
+		{"head fast block", status.HeadFastBlock},
+	} {
+		if head.pointer.Hash == (common.Hash{}) {
+			continue
+		}
+		if head.pointer.Number == nil {
+			add(restore, "the header of the %s %x is missing", head.name, head.pointer.Hash)
+			continue
+		}
+		number := *head.pointer.Number
+		if canonical := db.ReadCanonicalHash(number); canonical != head.pointer.Hash {
+			add(restore, "the %s #%d %x is not on the canonical chain, whose block #%d is %x",
+				head.name, number, head.pointer.Hash, number, canonical)
+			continue
+		}
+		if head.name == "head header" {
+			headHeader = head.pointer.Number
+			continue
+		}
+		if headHeader != nil && number > *headHeader {
+			add(restore, "the %s #%d is ahead of the head header #%d", head.name, number, *headHeader)
+		}
+		if head.name == "head block" && !db.HasBody(head.pointer.Hash, number) {
+			add(verify, "the body of the head block #%d %x is missing", number, head.pointer.Hash)
+		}
+	}
+
+	for _, cold := range status.ColdStorage {
+		if cold.NextBlock == 0 {
+			continue
+		}
+		if !cold.Enabled {
+			add("give the --db.cold.* flags used when the data was moved to the cold storage",
+				"the data of the blocks before #%d in %s are moved to the cold storage, but the cold storage is not enabled",
+				cold.NextBlock, cold.Name)
+			continue
+		}
+		if status.HeadBlock.Number != nil && cold.NextBlock > *status.HeadBlock.Number+1 {
+			add("roll the chain back by the `snapshot rewind` command, which rewinds the cold storage as well",
+				"the cold storage of %s continues from block #%d, beyond the head block #%d",
+				cold.Name, cold.NextBlock, *status.HeadBlock.Number)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &IntegrityError{Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckIntegrity(t *testing.T) {
+	db := database.NewMemoryDBManager()
+	genesis := (&Genesis{Config: params.TestChainConfig}).MustCommit(db)
+	assert.NoError(t, CheckIntegrity(db, genesis.Hash()))
+
+	// A genesis block of another network is configured.
+	err := CheckIntegrity(db, common.Hash{0x01})
+	if assert.IsType(t, &IntegrityError{}, err) {
+		assert.Len(t, err.(*IntegrityError).Problems, 1)
+	}
+
+	// The head block points to a missing header. The body of the head fast
+	// block is not required.
+	db.WriteHeadBlockHash(common.Hash{0x02})
+	db.DeleteBody(genesis.Hash(), 0)
+	db.WriteHeadFastBlockHash(genesis.Hash())
+	err = CheckIntegrity(db, genesis.Hash())
+	if assert.IsType(t, &IntegrityError{}, err) {
+		assert.Len(t, err.(*IntegrityError).Problems, 1)
+		assert.Contains(t, err.Error(), "remediation")
+	}
+
+	db.WriteHeadBlockHash(genesis.Hash())
+	err = CheckIntegrity(db, genesis.Hash())
+	if assert.IsType(t, &IntegrityError{}, err) {
+		assert.Len(t, err.(*IntegrityError).Problems, 1)
+		assert.Contains(t, err.Error(), "db verify --repair")
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+// RewindDatabase rolls the chain in the database back to the given block, for
+// recovering from bad imports without re-initializing the database from the
+// genesis. If the state of the block is not available, the chain is rolled back
+// further to the latest block with its state. The headers, bodies, receipts and
+// transaction lookup entries of the following canonical blocks are deleted,
+// including the data moved to the cold storage. It returns the number of the
+// new head block. The node must not be running.
+func RewindDatabase(db database.DBManager, number uint64) (uint64, error) {
+	headNumber := db.ReadHeaderNumber(db.ReadHeadHeaderHash())
+	if headNumber == nil {
+		return 0, errNoHeadBlock
+	}
+	if number >= *headNumber {
+		return 0, fmt.Errorf("block #%d is not behind the head header #%d", number, *headNumber)
+	}
+
+	target, hash, err := findRewindTarget(db, number)
+	if err != nil {
+		return 0, err
+	}
+
+	// The head pointers are updated first, so that the head is never higher than
+	// the data in the database even if the rewind is interrupted.
+	db.WriteHeadBlockHash(hash)
+	db.WriteHeadFastBlockHash(hash)
+	db.WriteHeadHeaderHash(hash)
+
+	var (
+		start  = time.Now()
+		logged = time.Now()
+	)
+	for n := *headNumber; n > target; n-- {
+		if time.Since(logged) > 8*time.Second {
+			logger.Info("Rewinding the database", "number", n, "target", target, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+		deleteCanonicalBlock(db, db.ReadCanonicalHash(n), n)
+	}
+	if err := db.RewindColdStorage(target + 1); err != nil {
+		return 0, err
+	}
+	db.ClearHeaderChainCache()
+	db.ClearBlockChainCache()
+
+	logger.Warn("Rewound the database", "number", target, "hash", hash, "deleted", *headNumber-target,
+		"elapsed", common.PrettyDuration(time.Since(start)))
+	return target, nil
+}
+
+// findRewindTarget returns the latest canonical block at or before the given
+// number whose body and state are available.
+func findRewindTarget(db database.DBManager, number uint64) (uint64, common.Hash, error) {
+	for n := number; ; n-- {
+		hash := db.ReadCanonicalHash(n)
+		header := db.ReadHeader(hash, n)
+		if header != nil && db.HasBody(hash, n) {
+			if ok, _ := db.HasStateTrieNode(header.Root.Bytes()); ok {
+				return n, hash, nil
+			}
+		}
+		if n == 0 {
+			return 0, common.Hash{}, fmt.Errorf("no block with the body and state is found at or before #%d", number)
+		}
+	}
+}
+
+// deleteCanonicalBlock deletes the header, body, receipts and transaction lookup
+// entries of the canonical block with the canonical hash itself.
+func deleteCanonicalBlock(db database.DBManager, hash common.Hash, number uint64) {
+	if hash != (common.Hash{}) {
+		deleteTxLookupEntries(db, hash, number)
+		db.DeleteBody(hash, number)
+		db.DeleteReceipts(hash, number)
+		db.DeleteHeader(hash, number)
+		db.DeleteTd(hash, number)
+	}
+	db.DeleteCanonicalHash(number)
+}
+
+// deleteTxLookupEntries deletes the transaction lookup entries pointing to the
+// given block. The entries of the transactions included again in another block
+// are kept.
+func deleteTxLookupEntries(db database.DBManager, hash common.Hash, number uint64) {
+	body := db.ReadBody(hash, number)
+	if body == nil {
+		return
+	}
+	for _, tx := range body.Transactions {
+		if blockHash, _, _ := db.ReadTxLookupEntry(tx.Hash()); blockHash == hash {
+			db.DeleteTxLookupEntry(tx.Hash())
+		}
+	}
+}
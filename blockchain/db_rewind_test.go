@@ -0,0 +1,105 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus/gxhash"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/klaytn/klaytn/storage/statedb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewindDatabase(t *testing.T) {
+	var (
+		gendb   = database.NewMemoryDBManager()
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{address: {Balance: big.NewInt(1000000000)}},
+		}
+		genesis = gspec.MustCommit(gendb)
+		signer  = types.LatestSignerForChainID(gspec.Config.ChainID)
+	)
+	blocks, _ := GenerateChain(gspec.Config, genesis, gxhash.NewFaker(), gendb, 10, func(i int, block *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(block.TxNonce(address), common.Address{0x00}, big.NewInt(1000), params.TxGas, nil, nil), signer, key)
+		if err != nil {
+			panic(err)
+		}
+		block.AddTx(tx)
+	})
+
+	// Archive mode is given to keep the state of every block for the rewind.
+	db := database.NewMemoryDBManager()
+	gspec.MustCommit(db)
+	cacheConfig := &CacheConfig{
+		ArchiveMode:         true,
+		CacheSize:           512,
+		BlockInterval:       DefaultBlockInterval,
+		TriesInMemory:       DefaultTriesInMemory,
+		TrieNodeCacheConfig: statedb.GetEmptyTrieNodeCacheConfig(),
+		SnapshotCacheSize:   512,
+	}
+	chain, _ := NewBlockChain(db, cacheConfig, gspec.Config, gxhash.NewFaker(), vm.Config{})
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to process block %d: %v", n, err)
+	}
+	chain.Stop()
+
+	// The head can't be rewound forward.
+	_, err := RewindDatabase(db, 10)
+	assert.Error(t, err)
+
+	head, err := RewindDatabase(db, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), head)
+	assert.Equal(t, blocks[3].Hash(), db.ReadHeadBlockHash())
+	assert.Equal(t, blocks[3].Hash(), db.ReadHeadFastBlockHash())
+	assert.Equal(t, blocks[3].Hash(), db.ReadHeadHeaderHash())
+
+	for _, block := range blocks {
+		number, hash := block.NumberU64(), block.Hash()
+		kept := number <= 4
+
+		assert.Equal(t, kept, db.ReadCanonicalHash(number) == hash, "canonical hash of block %d", number)
+		assert.Equal(t, kept, db.HasHeader(hash, number), "header of block %d", number)
+		assert.Equal(t, kept, db.HasBody(hash, number), "body of block %d", number)
+		assert.Equal(t, kept, db.ReadReceipts(hash, number) != nil, "receipts of block %d", number)
+
+		lookupHash, lookupNumber, _ := db.ReadTxLookupEntry(block.Transactions()[0].Hash())
+		if kept {
+			assert.Equal(t, hash, lookupHash)
+			assert.Equal(t, number, lookupNumber)
+		} else {
+			assert.Equal(t, common.Hash{}, lookupHash, "tx lookup entry of block %d", number)
+		}
+	}
+
+	// The rewound database is consistent.
+	result, err := VerifyDatabase(db, DBVerifyConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), result.Checked)
+	assert.Empty(t, result.Issues)
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+// SchemaMigration is a step upgrading the database schema to its version. The
+// steps are applied in the order of the versions, and the version is written
+// after each step, so an interrupted upgrade continues from the failed step.
+type SchemaMigration struct {
+	Version     uint64
+	Description string
+	Migrate     func(db database.DBManager) error // nil if no data is rewritten
+}
+
+// schemaMigrations are the steps to upgrade the database schema to
+// BlockChainVersion. A step must be added with a new BlockChainVersion.
+var schemaMigrations = []SchemaMigration{
+	{
+		// The codes in the legacy scheme are still read from the state trie
+		// database by ReadCode, so the existing codes are not rewritten.
+		Version:     4,
+		Description: "contract codes are stored with a prefix, separated from the trie nodes",
+	},
+}
+
+// migrateSchema upgrades the database schema to the target version by the
+// migrations. It refuses to open a database of a newer version.
+func migrateSchema(db database.DBManager, migrations []SchemaMigration, target uint64) error {
+	var current uint64
+	version := db.ReadDatabaseVersion()
+	if version != nil {
+		current = *version
+	}
+	if current > target {
+		return fmt.Errorf("database version is v%d, Klaytn %s only supports v%d; run a release of Klaytn supporting v%d, "+
+			"or start with a new data directory", current, params.Version, target, current)
+	}
+	if current == target && version != nil {
+		return nil
+	}
+
+	currentStr := "N/A"
+	if version != nil {
+		currentStr = strconv.FormatUint(current, 10)
+	}
+	logger.Warn("Upgrade database version", "from", currentStr, "to", target)
+	for _, m := range migrations {
+		if m.Version <= current || m.Version > target {
+			continue
+		}
+		logger.Info("Migrating the database schema", "version", m.Version, "change", m.Description)
+		if m.Migrate != nil {
+			if err := m.Migrate(db); err != nil {
+				return fmt.Errorf("failed to migrate the database to v%d (%s): %v; the migration resumes "+
+					"from this step on the next start", m.Version, m.Description, err)
+			}
+		}
+		db.WriteDatabaseVersion(m.Version)
+	}
+	db.WriteDatabaseVersion(target)
+	return nil
+}
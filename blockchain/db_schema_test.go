@@ -0,0 +1,66 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateSchema(t *testing.T) {
+	var applied []uint64
+	migrations := []SchemaMigration{
+		{Version: 2, Description: "two", Migrate: func(database.DBManager) error { applied = append(applied, 2); return nil }},
+		{Version: 3, Description: "three"},
+		{Version: 4, Description: "four", Migrate: func(database.DBManager) error { applied = append(applied, 4); return nil }},
+	}
+
+	// A new database is upgraded to the target version by all the steps.
+	db := database.NewMemoryDBManager()
+	assert.NoError(t, migrateSchema(db, migrations, 4))
+	assert.Equal(t, []uint64{2, 4}, applied)
+	assert.Equal(t, uint64(4), *db.ReadDatabaseVersion())
+
+	// Only the steps after the current version are applied.
+	applied = nil
+	db.WriteDatabaseVersion(3)
+	assert.NoError(t, migrateSchema(db, migrations, 4))
+	assert.Equal(t, []uint64{4}, applied)
+
+	// A failed step is retried on the next start.
+	db.WriteDatabaseVersion(1)
+	failed := errors.New("failed")
+	migrations[2].Migrate = func(database.DBManager) error { return failed }
+	assert.Error(t, migrateSchema(db, migrations, 4))
+	assert.Equal(t, uint64(3), *db.ReadDatabaseVersion())
+
+	// A database of a newer version is refused.
+	db.WriteDatabaseVersion(5)
+	assert.Error(t, migrateSchema(db, migrations, 4))
+	assert.Equal(t, uint64(5), *db.ReadDatabaseVersion())
+}
+
+func TestSchemaMigrations(t *testing.T) {
+	// The last step must upgrade the database to BlockChainVersion.
+	assert.Equal(t, uint64(BlockChainVersion), schemaMigrations[len(schemaMigrations)-1].Version)
+	for i := 1; i < len(schemaMigrations); i++ {
+		assert.Less(t, schemaMigrations[i-1].Version, schemaMigrations[i].Version)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"errors"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+var ErrDBSnapshotAlreadyStarted = errors.New("creating a database snapshot is already started")
+
+// StartDBSnapshot captures a consistent snapshot of the databases at the current
+// block, and writes it to the given directory in the background. If url is not
+// empty, the written snapshot is streamed to the url as a tar archive, and then
+// removed from the directory.
+func (bc *BlockChain) StartDBSnapshot(dir, url string) error {
+	if !atomic.CompareAndSwapInt32(&bc.creatingDBSnapshot, 0, 1) {
+		return ErrDBSnapshotAlreadyStarted
+	}
+	snapshot, err := bc.captureDBSnapshot(dir)
+	if err != nil {
+		atomic.StoreInt32(&bc.creatingDBSnapshot, 0)
+		return err
+	}
+
+	go func() {
+		defer atomic.StoreInt32(&bc.creatingDBSnapshot, 0)
+
+		start := time.Now()
+		if err := snapshot.Write(bc.quit); err != nil {
+			logger.Error("Failed to write the database snapshot", "dir", dir, "err", err)
+			return
+		}
+		logger.Info("Wrote the database snapshot", "dir", dir, "elapsed", time.Since(start))
+		if url == "" {
+			return
+		}
+
+		defer os.RemoveAll(dir)
+		if err := database.StreamSnapshot(dir, url); err != nil {
+			logger.Error("Failed to stream the database snapshot", "url", url, "err", err)
+			return
+		}
+		logger.Info("Streamed the database snapshot", "url", url, "elapsed", time.Since(start))
+	}()
+	return nil
+}
+
+// captureDBSnapshot captures the databases while the chain is locked. The state
+// of the current block is flushed to the database first, unless all states are
+// flushed in archive mode, so that the snapshot contains it.
+func (bc *BlockChain) captureDBSnapshot(dir string) (*database.DBSnapshot, error) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	current := bc.CurrentBlock()
+	if !bc.isArchiveMode() {
+		if err := bc.stateCache.TrieDB().Commit(current.Root(), true, current.NumberU64()); err != nil {
+			return nil, err
+		}
+	}
+	snapshot, err := bc.db.CreateSnapshot(dir)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Captured a database snapshot", "number", current.NumberU64(), "hash", current.Hash(), "dir", dir)
+	return snapshot, nil
+}
@@ -0,0 +1,230 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+// Kinds of the inconsistencies found by VerifyDatabase.
+const (
+	IssueMissingCanonicalHash = "missing canonical hash"
+	IssueMissingHeader        = "missing header"
+	IssueHeaderHashMismatch   = "header hash mismatch"
+	IssueBrokenParent         = "parent hash mismatch"
+	IssueMissingBody          = "missing body"
+	IssueTxRootMismatch       = "transaction root mismatch"
+	IssueMissingReceipts      = "missing receipts"
+	IssueReceiptRootMismatch  = "receipt root mismatch"
+	IssueTxLookupEntry        = "missing or wrong transaction lookup entry"
+	IssueMissingState         = "missing state of the head block"
+)
+
+var errNoHeadBlock = errors.New("head block is not found")
+
+// DBVerifyConfig is the configuration of VerifyDatabase.
+type DBVerifyConfig struct {
+	From   uint64
+	To     uint64 // the head block if 0
+	Repair bool   // repairs the recoverable inconsistencies
+}
+
+// DBIssue is an inconsistency of the chain data.
+type DBIssue struct {
+	Number   uint64
+	Hash     common.Hash
+	Kind     string
+	Repaired bool
+}
+
+func (i DBIssue) String() string {
+	status := ""
+	if i.Repaired {
+		status = " (repaired)"
+	}
+	return fmt.Sprintf("block #%d [%x…]: %s%s", i.Number, i.Hash[:4], i.Kind, status)
+}
+
+// DBVerifyResult is the result of VerifyDatabase.
+type DBVerifyResult struct {
+	Checked uint64 // number of the checked blocks
+	Issues  []DBIssue
+
+	// RewoundTo is the number of the new head block if the head block was
+	// rewound to re-fetch the missing or corrupt blocks from peers.
+	RewoundTo *uint64
+}
+
+// Unrepaired returns the number of the issues which are not repaired.
+func (r *DBVerifyResult) Unrepaired() int {
+	n := 0
+	for _, issue := range r.Issues {
+		if !issue.Repaired {
+			n++
+		}
+	}
+	return n
+}
+
+// VerifyDatabase walks the canonical chain and checks the consistency of the
+// headers, bodies, receipts, transaction lookup entries and the state of the head
+// block. With config.Repair, the transaction lookup entries are re-derived from
+// the bodies, and the head block is rewound before the first block whose body or
+// receipts can't be recovered locally, so that the blocks after it are fetched
+// from peers again on the next start. The node must not be running.
+func VerifyDatabase(db database.DBManager, config DBVerifyConfig) (*DBVerifyResult, error) {
+	headHash := db.ReadHeadBlockHash()
+	headNumber := db.ReadHeaderNumber(headHash)
+	if headNumber == nil {
+		return nil, errNoHeadBlock
+	}
+	genesisHash := db.ReadCanonicalHash(0)
+	if chainConfig := db.ReadChainConfig(genesisHash); chainConfig != nil {
+		InitDeriveSha(chainConfig.DeriveShaImpl)
+	} else {
+		return nil, fmt.Errorf("chain config is not found for the genesis %x", genesisHash)
+	}
+
+	to := config.To
+	if to == 0 || to > *headNumber {
+		to = *headNumber
+	}
+
+	var (
+		result      = &DBVerifyResult{}
+		firstBroken *uint64 // first block whose data can't be recovered locally
+		prevHash    common.Hash
+		start       = time.Now()
+		logged      = time.Now()
+	)
+	if config.From > 0 {
+		prevHash = db.ReadCanonicalHash(config.From - 1)
+	}
+	report := func(number uint64, hash common.Hash, kind string, repaired bool) {
+		issue := DBIssue{Number: number, Hash: hash, Kind: kind, Repaired: repaired}
+		result.Issues = append(result.Issues, issue)
+		logger.Warn("Found an inconsistency in the database", "number", number, "hash", hash, "issue", kind, "repaired", repaired)
+		if !repaired && firstBroken == nil && kind != IssueMissingState {
+			n := number
+			firstBroken = &n
+		}
+	}
+
+	for number := config.From; number <= to; number++ {
+		result.Checked++
+		if time.Since(logged) > 8*time.Second {
+			logger.Info("Verifying the database", "number", number, "to", to, "issues", len(result.Issues),
+				"elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+
+		hash := db.ReadCanonicalHash(number)
+		if hash == (common.Hash{}) {
+			report(number, hash, IssueMissingCanonicalHash, false)
+			prevHash = common.Hash{}
+			continue
+		}
+		header := db.ReadHeader(hash, number)
+		if header == nil {
+			report(number, hash, IssueMissingHeader, false)
+			prevHash = hash
+			continue
+		}
+		if header.Hash() != hash {
+			report(number, hash, IssueHeaderHashMismatch, false)
+		}
+		if number > 0 && prevHash != (common.Hash{}) && header.ParentHash != prevHash {
+			report(number, hash, IssueBrokenParent, false)
+		}
+		prevHash = hash
+
+		body := db.ReadBody(hash, number)
+		if body == nil {
+			report(number, hash, IssueMissingBody, false)
+			continue
+		}
+		if types.DeriveSha(types.Transactions(body.Transactions)) != header.TxHash {
+			report(number, hash, IssueTxRootMismatch, false)
+			continue
+		}
+
+		receipts := db.ReadReceipts(hash, number)
+		if receipts == nil && len(body.Transactions) > 0 {
+			report(number, hash, IssueMissingReceipts, false)
+		} else if types.DeriveSha(receipts) != header.ReceiptHash {
+			report(number, hash, IssueReceiptRootMismatch, false)
+		}
+
+		for i, tx := range body.Transactions {
+			blockHash, blockNumber, index := db.ReadTxLookupEntry(tx.Hash())
+			if blockHash == hash && blockNumber == number && index == uint64(i) {
+				continue
+			}
+			if config.Repair {
+				db.WriteTxLookupEntries(types.NewBlockWithHeader(header).WithBody(body.Transactions))
+			}
+			report(number, hash, IssueTxLookupEntry, config.Repair)
+			break
+		}
+	}
+
+	// The state of the head block is required to process the next blocks.
+	headHeader := db.ReadHeader(headHash, *headNumber)
+	if headHeader != nil {
+		if ok, _ := db.HasStateTrieNode(headHeader.Root.Bytes()); !ok {
+			report(*headNumber, headHash, IssueMissingState, false)
+			if firstBroken == nil || *firstBroken > *headNumber {
+				firstBroken = headNumber
+			}
+		}
+	}
+
+	if config.Repair && firstBroken != nil {
+		rewoundTo, err := rewindHeadBlock(db, *firstBroken)
+		if err != nil {
+			return result, err
+		}
+		result.RewoundTo = &rewoundTo
+	}
+	return result, nil
+}
+
+// rewindHeadBlock sets the head block to the latest canonical block before the
+// given block whose body and state are available. The header chain is kept.
+func rewindHeadBlock(db database.DBManager, before uint64) (uint64, error) {
+	for number := before; number > 0; number-- {
+		hash := db.ReadCanonicalHash(number - 1)
+		header := db.ReadHeader(hash, number-1)
+		if header == nil || !db.HasBody(hash, number-1) {
+			continue
+		}
+		if ok, _ := db.HasStateTrieNode(header.Root.Bytes()); !ok {
+			continue
+		}
+		db.WriteHeadBlockHash(hash)
+		db.WriteHeadFastBlockHash(hash)
+		logger.Warn("Rewound the head block to re-fetch the following blocks from peers", "number", number-1, "hash", hash)
+		return number - 1, nil
+	}
+	return 0, errors.New("no block with the body and state is found to rewind the head block")
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus/gxhash"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/klaytn/klaytn/storage/statedb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyDatabase(t *testing.T) {
+	var (
+		gendb   = database.NewMemoryDBManager()
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{address: {Balance: big.NewInt(1000000000)}},
+		}
+		genesis = gspec.MustCommit(gendb)
+		signer  = types.LatestSignerForChainID(gspec.Config.ChainID)
+	)
+	blocks, _ := GenerateChain(gspec.Config, genesis, gxhash.NewFaker(), gendb, 10, func(i int, block *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(block.TxNonce(address), common.Address{0x00}, big.NewInt(1000), params.TxGas, nil, nil), signer, key)
+		if err != nil {
+			panic(err)
+		}
+		block.AddTx(tx)
+	})
+
+	// Archive mode is given to keep the state of every block for the rewind.
+	db := database.NewMemoryDBManager()
+	gspec.MustCommit(db)
+	cacheConfig := &CacheConfig{
+		ArchiveMode:         true,
+		CacheSize:           512,
+		BlockInterval:       DefaultBlockInterval,
+		TriesInMemory:       DefaultTriesInMemory,
+		TrieNodeCacheConfig: statedb.GetEmptyTrieNodeCacheConfig(),
+		SnapshotCacheSize:   512,
+	}
+	chain, _ := NewBlockChain(db, cacheConfig, gspec.Config, gxhash.NewFaker(), vm.Config{})
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to process block %d: %v", n, err)
+	}
+	chain.Stop()
+
+	// A consistent database has no issue.
+	result, err := VerifyDatabase(db, DBVerifyConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(11), result.Checked)
+	assert.Empty(t, result.Issues)
+
+	// Break the lookup entry of the block 3 and the receipts of the block 7.
+	db.DeleteTxLookupEntry(blocks[2].Transactions()[0].Hash())
+	db.DeleteReceipts(blocks[6].Hash(), blocks[6].NumberU64())
+
+	result, err = VerifyDatabase(db, DBVerifyConfig{})
+	assert.NoError(t, err)
+	if assert.Equal(t, 2, len(result.Issues)) {
+		assert.Equal(t, DBIssue{Number: 3, Hash: blocks[2].Hash(), Kind: IssueTxLookupEntry}, result.Issues[0])
+		assert.Equal(t, DBIssue{Number: 7, Hash: blocks[6].Hash(), Kind: IssueMissingReceipts}, result.Issues[1])
+	}
+	assert.Equal(t, 2, result.Unrepaired())
+	assert.Nil(t, result.RewoundTo)
+
+	// The range can be limited.
+	result, err = VerifyDatabase(db, DBVerifyConfig{From: 4, To: 6})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), result.Checked)
+	assert.Empty(t, result.Issues)
+
+	// The lookup entry is repaired, and the head block is rewound before the block 7.
+	result, err = VerifyDatabase(db, DBVerifyConfig{Repair: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Unrepaired())
+	if assert.NotNil(t, result.RewoundTo) {
+		assert.Equal(t, uint64(6), *result.RewoundTo)
+	}
+	blockHash, blockNumber, index := db.ReadTxLookupEntry(blocks[2].Transactions()[0].Hash())
+	assert.Equal(t, blocks[2].Hash(), blockHash)
+	assert.Equal(t, uint64(3), blockNumber)
+	assert.Equal(t, uint64(0), index)
+	assert.Equal(t, blocks[5].Hash(), db.ReadHeadBlockHash())
+	assert.Equal(t, blocks[5].Hash(), db.ReadHeadFastBlockHash())
+}
@@ -384,6 +384,60 @@ func GenesisBlockForTesting(db database.DBManager, addr common.Address, balance
 	return g.MustCommit(db)
 }
 
+// ReadGenesis reconstructs the genesis specification from the genesis block,
+// the chain config and the state of the genesis block stored in the database.
+// The addresses and the storage keys of the accounts are restored from their
+// preimages, so the state of the genesis block must not be pruned.
+func ReadGenesis(db database.DBManager) (*Genesis, error) {
+	hash := db.ReadCanonicalHash(0)
+	if common.EmptyHash(hash) {
+		return nil, errors.New("genesis block not found, the database is not initialized")
+	}
+	header := db.ReadHeader(hash, 0)
+	if header == nil {
+		return nil, fmt.Errorf("genesis header %x not found", hash)
+	}
+	config := db.ReadChainConfig(hash)
+	if config == nil {
+		return nil, fmt.Errorf("chain config of the genesis block %x not found", hash)
+	}
+
+	stateDB, err := state.New(header.Root, state.NewDatabase(db), nil)
+	if err != nil {
+		return nil, fmt.Errorf("state of the genesis block not found: %v", err)
+	}
+	alloc := make(GenesisAlloc)
+	err = stateDB.ForEachAccount(func(addr common.Address) error {
+		account := GenesisAccount{
+			Code:    stateDB.GetCode(addr),
+			Balance: stateDB.GetBalance(addr),
+			Nonce:   stateDB.GetNonce(addr),
+		}
+		if stateDB.IsProgramAccount(addr) {
+			account.Storage = make(map[common.Hash]common.Hash)
+			// The values of ForEachStorage are encoded, so they are read by GetState.
+			stateDB.ForEachStorage(addr, func(key, _ common.Hash) bool {
+				account.Storage[key] = stateDB.GetState(addr, key)
+				return true
+			})
+		}
+		alloc[addr] = account
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Genesis{
+		Config:     config,
+		Timestamp:  header.Time.Uint64(),
+		ExtraData:  header.Extra,
+		Governance: header.Governance,
+		BlockScore: header.BlockScore,
+		Alloc:      alloc,
+	}, nil
+}
+
 // DefaultGenesisBlock returns the Cypress mainnet genesis block.
 // It is also used for default genesis block.
 func DefaultGenesisBlock() *Genesis {
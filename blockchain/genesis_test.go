@@ -381,3 +381,41 @@ func genCustomGenesisBlock(customChainId uint64) *Genesis {
 	InitDeriveSha(genesis.Config.DeriveShaImpl)
 	return genesis
 }
+
+func TestReadGenesis(t *testing.T) {
+	db := database.NewMemoryDBManager()
+	_, err := ReadGenesis(db)
+	assert.Error(t, err, "the database is not initialized")
+
+	contract := common.HexToAddress("0x0000000000000000000000000000000000000400")
+	eoa := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	genesis := &Genesis{
+		Config:     params.TestChainConfig,
+		Timestamp:  1234,
+		ExtraData:  []byte("extra"),
+		BlockScore: big.NewInt(1),
+		Alloc: GenesisAlloc{
+			contract: {
+				Code:    []byte{0x60, 0x00},
+				Balance: big.NewInt(1),
+				Storage: map[common.Hash]common.Hash{
+					common.HexToHash("0x01"): common.HexToHash("0x0100"),
+					common.HexToHash("0x02"): common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
+				},
+			},
+			eoa: {Balance: big.NewInt(1000000000000000000), Nonce: 3},
+		},
+	}
+	block := genesis.MustCommit(db)
+
+	read, err := ReadGenesis(db)
+	assert.NoError(t, err)
+	assert.Equal(t, block.Hash(), read.ToBlock(common.Hash{}, nil).Hash())
+	assert.Equal(t, genesis.Timestamp, read.Timestamp)
+	assert.Equal(t, genesis.ExtraData, read.ExtraData)
+	assert.Equal(t, genesis.Alloc[contract].Code, read.Alloc[contract].Code)
+	assert.Equal(t, genesis.Alloc[contract].Storage, read.Alloc[contract].Storage)
+	assert.Equal(t, genesis.Alloc[eoa].Balance, read.Alloc[eoa].Balance)
+	assert.Equal(t, genesis.Alloc[eoa].Nonce, read.Alloc[eoa].Nonce)
+	assert.Equal(t, genesis.Config.ChainID, read.Config.ChainID)
+}
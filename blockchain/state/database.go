@@ -25,7 +25,9 @@ import (
 	"fmt"
 
 	"github.com/VictoriaMetrics/fastcache"
+	"github.com/klaytn/klaytn/blockchain/types/account"
 	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/rlp"
 	"github.com/klaytn/klaytn/storage/database"
 	"github.com/klaytn/klaytn/storage/statedb"
 )
@@ -132,7 +134,7 @@ func getCodeSizeCache() common.Cache {
 		cacheConfig = common.FIFOCacheConfig{CacheSize: codeSizeCacheSize}
 	}
 
-	return common.NewCache(cacheConfig)
+	return common.NewCacheWithStats("codesize", cacheConfig)
 }
 
 // NewDatabaseWithNewCache creates a backing store for state. The returned database
@@ -252,3 +254,18 @@ func (db *cachingDB) RLockGCCachedNode() {
 func (db *cachingDB) RUnlockGCCachedNode() {
 	db.db.RUnlockGCCachedNode()
 }
+
+// StorageTrieRefs returns the storage trie root referenced by the given account
+// leaf of the state trie. It is used to reference count the storage trie nodes
+// together with the state trie nodes.
+func StorageTrieRefs(leaf []byte) []common.Hash {
+	serializer := account.NewAccountSerializer()
+	if err := rlp.DecodeBytes(leaf, serializer); err != nil {
+		logger.Error("Failed to decode an account leaf", "err", err)
+		return nil
+	}
+	if pa := account.GetProgramAccount(serializer.GetAccount()); pa != nil {
+		return []common.Hash{pa.GetStorageRoot()}
+	}
+	return nil
+}
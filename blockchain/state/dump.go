@@ -97,3 +97,21 @@ func (self *StateDB) Dump() []byte {
 
 	return json
 }
+
+// ForEachAccount calls the callback with the address of every account in the
+// state, which is restored from the preimage of its hashed address. It stops at
+// the first error returned by the callback, and returns an error if the state
+// cannot be iterated or a preimage is missing.
+func (self *StateDB) ForEachAccount(cb func(addr common.Address) error) error {
+	it := statedb.NewIterator(self.trie.NodeIterator(nil))
+	for it.Next() {
+		addr := self.trie.GetKey(it.Key)
+		if addr == nil {
+			return fmt.Errorf("missing preimage of the account %x", it.Key)
+		}
+		if err := cb(common.BytesToAddress(addr)); err != nil {
+			return err
+		}
+	}
+	return it.Err
+}
@@ -0,0 +1,399 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package pruner
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/types/account"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/log"
+	"github.com/klaytn/klaytn/rlp"
+	"github.com/klaytn/klaytn/snapshot"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/klaytn/klaytn/storage/statedb"
+)
+
+const (
+	// bloomFilterName is the name of the bloom filter file of the marked state.
+	// Its existence means the marking is finished and the sweeping is pending.
+	bloomFilterName = "statePruning.bloom"
+
+	// sweepMarkerName is the name of the file keeping the last swept key, from
+	// which an interrupted sweeping is resumed.
+	sweepMarkerName = "statePruning.marker"
+
+	// snapshotLayers is the maximum number of snapshot layers looked up for the
+	// state to keep, which is the number of the recent tries kept in memory.
+	snapshotLayers = 128
+
+	// MinBloomSize is the minimum size of the bloom filter in megabytes.
+	MinBloomSize = 256
+)
+
+var (
+	logger = log.NewModuleLogger(log.BlockchainState)
+
+	emptyRoot     = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+	emptyCodeHash = crypto.Keccak256(nil)
+
+	errStateMigration = errors.New("state migration is in progress, prune the state after it is finished")
+	errTrieNodeRefs   = errors.New("trie nodes are reference counted, prune the state with the reference counts instead")
+)
+
+// Config includes the configurations of the pruner.
+type Config struct {
+	Datadir   string // directory of the bloom filter and the sweep marker
+	BloomSize uint64 // size of the bloom filter in megabytes
+}
+
+// Pruner deletes the trie nodes not reachable from a recent state from the
+// database of a stopped node. The nodes of the kept state are marked in a
+// bloom filter, walking the account trie from the disk and finding the storage
+// tries and the codes of the contracts by the flat snapshot. Then every trie
+// node in the database which is not in the filter is swept.
+//
+// The filter is committed to the disk before sweeping, and the last swept key
+// is recorded periodically, so that an interrupted pruning is resumed by
+// running it again.
+type Pruner struct {
+	config   Config
+	db       database.DBManager
+	triedb   *statedb.Database
+	snaptree *snapshot.Tree
+	headRoot common.Hash
+}
+
+// NewPruner returns a pruner of the state of the given database. The snapshot
+// of the state of the head block must be available.
+func NewPruner(db database.DBManager, config Config) (*Pruner, error) {
+	if db.InMigration() {
+		return nil, errStateMigration
+	}
+	if _, _, ok := db.ReadTrieNodeRefRange(); ok {
+		return nil, errTrieNodeRefs
+	}
+	headHash := db.ReadHeadBlockHash()
+	number := db.ReadHeaderNumber(headHash)
+	if number == nil {
+		return nil, errors.New("failed to load the head block")
+	}
+	header := db.ReadHeader(headHash, *number)
+	if header == nil {
+		return nil, fmt.Errorf("failed to load the header of the head block #%d", *number)
+	}
+	if config.BloomSize < MinBloomSize {
+		logger.Warn("Sanitizing the bloom filter size", "provided(MB)", config.BloomSize, "updated(MB)", MinBloomSize)
+		config.BloomSize = MinBloomSize
+	}
+	triedb := statedb.NewDatabase(db)
+	snaptree, err := snapshot.New(db, triedb, 256, header.Root, false, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the snapshot, run the node with the snapshot enabled until it is generated: %v", err)
+	}
+	return &Pruner{
+		config:   config,
+		db:       db,
+		triedb:   triedb,
+		snaptree: snaptree,
+		headRoot: header.Root,
+	}, nil
+}
+
+// Prune keeps the state of the given root and the genesis state, and deletes
+// the other trie nodes. If root is empty, the most recent state which has both
+// the trie and the snapshot is kept. If a previous pruning was interrupted
+// after the marking, its sweeping is resumed instead.
+//
+// The states of the blocks after the kept state are deleted, so the node rolls
+// back to the block of the kept state at the next start.
+func (p *Pruner) Prune(root common.Hash) error {
+	bloomPath := filepath.Join(p.config.Datadir, bloomFilterName)
+	if common.FileExist(bloomPath) {
+		return p.resume(bloomPath)
+	}
+	if root == (common.Hash{}) {
+		var err error
+		if root, err = p.target(); err != nil {
+			return err
+		}
+	} else {
+		if p.snaptree.Snapshot(root) == nil {
+			return fmt.Errorf("snapshot of the state %x is not found", root)
+		}
+		if ok, _ := p.db.HasStateTrieNode(root.Bytes()); !ok {
+			return fmt.Errorf("state %x is not found", root)
+		}
+	}
+	if root != p.headRoot {
+		logger.Warn("The state of the head block is pruned, the node rolls back at the next start", "head", p.headRoot, "kept", root)
+	}
+
+	bloom, err := newStateBloomWithSize(p.config.BloomSize)
+	if err != nil {
+		return err
+	}
+	if err := p.mark(root, bloom); err != nil {
+		return err
+	}
+	if err := p.markGenesis(bloom); err != nil {
+		return err
+	}
+	if err := bloom.Commit(bloomPath, bloomPath+".tmp"); err != nil {
+		return err
+	}
+	logger.Info("Committed the state bloom", "path", bloomPath)
+	return p.sweep(bloom, nil)
+}
+
+// resume sweeps with the committed bloom filter from the recorded marker.
+func (p *Pruner) resume(bloomPath string) error {
+	bloom, err := newStateBloomFromDisk(bloomPath)
+	if err != nil {
+		return fmt.Errorf("failed to load the state bloom, remove %s to start over: %v", bloomPath, err)
+	}
+	start, _ := ioutil.ReadFile(filepath.Join(p.config.Datadir, sweepMarkerName))
+	logger.Info("Resuming the interrupted state pruning", "marker", common.Bytes2Hex(start))
+	return p.sweep(bloom, start)
+}
+
+// target returns the root of the most recent state which has both the trie
+// and the snapshot.
+func (p *Pruner) target() (common.Hash, error) {
+	layers := p.snaptree.Snapshots(p.headRoot, snapshotLayers, false)
+	if len(layers) == 0 {
+		return common.Hash{}, errors.New("snapshot of the head block is not found, run the node with the snapshot enabled until it is generated")
+	}
+	for _, layer := range layers {
+		if ok, _ := p.db.HasStateTrieNode(layer.Root().Bytes()); ok {
+			return layer.Root(), nil
+		}
+	}
+	return common.Hash{}, errors.New("no state with the snapshot is found")
+}
+
+// mark adds the trie nodes and the contract codes of the state to the bloom.
+// The account trie is walked from the disk, and the storage tries are walked
+// in parallel by the contracts found in the flat snapshot.
+func (p *Pruner) mark(root common.Hash, bloom *stateBloom) error {
+	var (
+		start  = time.Now()
+		nodes  uint64
+		logged = time.Now()
+	)
+	accTrie, err := statedb.NewSecureTrie(root, p.triedb)
+	if err != nil {
+		return err
+	}
+	it := accTrie.NodeIterator(nil)
+	for it.Next(true) {
+		if hash := it.Hash(); hash != (common.Hash{}) {
+			bloom.Put(hash.Bytes())
+			nodes++
+		}
+		if time.Since(logged) > 8*time.Second {
+			logger.Info("Marking the account trie", "nodes", nodes, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	logger.Info("Marked the account trie", "nodes", nodes, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	acctIt, err := p.snaptree.AccountIterator(root, common.Hash{})
+	if err != nil {
+		return err
+	}
+	defer acctIt.Release()
+
+	type contract struct {
+		storageRoot common.Hash
+		codeHash    []byte
+	}
+	var (
+		contracts = make(chan contract, 1024)
+		storages  uint64
+		wg        sync.WaitGroup
+		errMu     sync.Mutex
+		markErr   error
+	)
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range contracts {
+				if !bytes.Equal(c.codeHash, emptyCodeHash) {
+					bloom.Put(c.codeHash)
+				}
+				if c.storageRoot == emptyRoot || c.storageRoot == (common.Hash{}) {
+					continue
+				}
+				n, err := p.markTrie(c.storageRoot, bloom)
+				if err != nil {
+					errMu.Lock()
+					markErr = fmt.Errorf("storage trie %x: %v", c.storageRoot, err)
+					errMu.Unlock()
+					continue
+				}
+				atomic.AddUint64(&storages, n)
+			}
+		}()
+	}
+	var accounts uint64
+	for acctIt.Next() {
+		serializer := account.NewAccountSerializer()
+		if err := rlp.DecodeBytes(acctIt.Account(), serializer); err != nil {
+			close(contracts)
+			wg.Wait()
+			return err
+		}
+		if pa := account.GetProgramAccount(serializer.GetAccount()); pa != nil {
+			contracts <- contract{storageRoot: pa.GetStorageRoot(), codeHash: pa.GetCodeHash()}
+		}
+		accounts++
+		if time.Since(logged) > 8*time.Second {
+			logger.Info("Marking the storage tries", "accounts", accounts, "at", acctIt.Hash(), "nodes", atomic.LoadUint64(&storages),
+				"elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	close(contracts)
+	wg.Wait()
+	if err := acctIt.Error(); err != nil {
+		return err
+	}
+	if markErr != nil {
+		return markErr
+	}
+	logger.Info("Marked the state", "root", root, "accounts", accounts, "nodes", nodes+storages,
+		"elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// markTrie adds the nodes of the trie to the bloom and returns the number of them.
+func (p *Pruner) markTrie(root common.Hash, bloom *stateBloom) (uint64, error) {
+	t, err := statedb.NewSecureTrie(root, p.triedb)
+	if err != nil {
+		return 0, err
+	}
+	var nodes uint64
+	it := t.NodeIterator(nil)
+	for it.Next(true) {
+		if hash := it.Hash(); hash != (common.Hash{}) {
+			bloom.Put(hash.Bytes())
+			nodes++
+		}
+	}
+	return nodes, it.Error()
+}
+
+// markGenesis adds the genesis state to the bloom, which is not covered by the
+// snapshot. It is walked entirely from the disk.
+func (p *Pruner) markGenesis(bloom *stateBloom) error {
+	hash := p.db.ReadCanonicalHash(0)
+	header := p.db.ReadHeader(hash, 0)
+	if header == nil {
+		return errors.New("failed to load the genesis block")
+	}
+	genesis, err := state.New(header.Root, state.NewDatabase(p.db), nil)
+	if err != nil {
+		return err
+	}
+	it := state.NewNodeIterator(genesis)
+	for it.Next() {
+		if it.Hash != (common.Hash{}) {
+			bloom.Put(it.Hash.Bytes())
+		}
+	}
+	return it.Error
+}
+
+// sweep deletes the trie nodes not in the bloom from the state trie database,
+// starting from the given key. The trie nodes are the entries whose keys are
+// 32 byte hashes; the other entries like the prefixed codes are kept.
+func (p *Pruner) sweep(bloom *stateBloom, start []byte) error {
+	var (
+		db         = p.db.GetStateTrieDB()
+		batch      = db.NewBatch()
+		it         = db.NewIterator(nil, start)
+		markerPath = filepath.Join(p.config.Datadir, sweepMarkerName)
+
+		count  int
+		size   common.StorageSize
+		begin  = time.Now()
+		logged = time.Now()
+	)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) != common.HashLength || bloom.Contain(key) {
+			continue
+		}
+		size += common.StorageSize(len(key) + len(it.Value()))
+		count++
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+		if batch.ValueSize() >= database.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+			// The keys up to the marker are swept, resume from it if interrupted.
+			if err := ioutil.WriteFile(markerPath, key, 0600); err != nil {
+				return err
+			}
+		}
+		if time.Since(logged) > 8*time.Second {
+			var eta time.Duration
+			if done := binary.BigEndian.Uint64(key[:8]); done > 0 {
+				elapsed := time.Since(begin)
+				eta = time.Duration(float64(elapsed) * (float64(math.MaxUint64)/float64(done) - 1))
+			}
+			logger.Info("Pruning the state", "count", count, "size", size, "at", common.Bytes2Hex(key),
+				"elapsed", common.PrettyDuration(time.Since(begin)), "eta", common.PrettyDuration(eta))
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if batch.ValueSize() > 0 {
+		if err := batch.Write(); err != nil {
+			return err
+		}
+	}
+	logger.Info("Pruned the state", "count", count, "size", size, "elapsed", common.PrettyDuration(time.Since(begin)))
+
+	os.Remove(markerPath)
+	return os.Remove(filepath.Join(p.config.Datadir, bloomFilterName))
+}
@@ -0,0 +1,97 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package pruner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSweep checks that the sweeping deletes only the hash keys not in the
+// bloom, and removes the bloom and the marker when it is finished.
+func TestSweep(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klaytn-pruner")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db := database.NewMemoryDBManager()
+	trieDB := db.GetStateTrieDB()
+
+	bloom, err := newStateBloomWithSize(1)
+	assert.NoError(t, err)
+
+	var kept, pruned [][]byte
+	for i := byte(0); i < 100; i++ {
+		key := crypto.Keccak256([]byte{i})
+		assert.NoError(t, trieDB.Put(key, []byte{i}))
+		if i%2 == 0 {
+			assert.NoError(t, bloom.Put(key))
+			kept = append(kept, key)
+		} else {
+			pruned = append(pruned, key)
+		}
+	}
+	// Entries whose keys are not hashes are not trie nodes.
+	other := []byte("not a trie node")
+	assert.NoError(t, trieDB.Put(other, []byte{1}))
+
+	bloomPath := filepath.Join(dir, bloomFilterName)
+	assert.NoError(t, bloom.Commit(bloomPath, bloomPath+".tmp"))
+
+	p := &Pruner{config: Config{Datadir: dir}, db: db}
+	assert.NoError(t, p.sweep(bloom, nil))
+
+	for _, key := range kept {
+		ok, _ := trieDB.Has(key)
+		assert.True(t, ok)
+	}
+	for _, key := range pruned {
+		ok, _ := trieDB.Has(key)
+		assert.False(t, ok)
+	}
+	ok, _ := trieDB.Has(other)
+	assert.True(t, ok)
+	assert.False(t, common.FileExist(bloomPath))
+	assert.False(t, common.FileExist(filepath.Join(dir, sweepMarkerName)))
+}
+
+// TestStateBloomFromDisk checks that the committed bloom is loaded for resuming.
+func TestStateBloomFromDisk(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klaytn-pruner")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	bloom, err := newStateBloomWithSize(1)
+	assert.NoError(t, err)
+	key := crypto.Keccak256([]byte("node"))
+	assert.NoError(t, bloom.Put(key))
+	assert.Error(t, bloom.Put([]byte("short")))
+
+	path := filepath.Join(dir, bloomFilterName)
+	assert.NoError(t, bloom.Commit(path, path+".tmp"))
+
+	loaded, err := newStateBloomFromDisk(path)
+	assert.NoError(t, err)
+	assert.True(t, loaded.Contain(key))
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/klaytn/klaytn/params"
 	"github.com/klaytn/klaytn/rlp"
 	"github.com/klaytn/klaytn/storage/statedb"
+	"github.com/rcrowley/go-metrics"
 )
 
 type revision struct {
@@ -56,8 +57,19 @@ var (
 
 	// TODO-Klaytn EnabledExpensive and DBConfig.EnableDBPerfMetrics will be merged
 	EnabledExpensive = false
+
+	// metrics for the live state objects cached in StateDB
+	stateObjectHitCounter  = metrics.NewRegisteredCounter("klay/cache/stateobject/hits", nil)
+	stateObjectMissCounter = metrics.NewRegisteredCounter("klay/cache/stateobject/misses", nil)
 )
 
+func init() {
+	// The live state objects are never evicted; they are dropped with the StateDB.
+	common.RegisterCacheStats("stateobject", func() common.CacheStats {
+		return common.NewCacheStats(stateObjectHitCounter.Count(), stateObjectMissCounter.Count(), 0)
+	})
+}
+
 // StateDBs within the Klaytn protocol are used to cache stateObjects from Merkle Patricia Trie
 // and mediate the operations to them.
 type StateDB struct {
@@ -600,8 +612,10 @@ func (self *StateDB) getStateObject(addr common.Address) *stateObject {
 func (self *StateDB) getDeletedStateObject(addr common.Address) *stateObject {
 	// First, check stateObjects if there is "live" object.
 	if obj := self.stateObjects[addr]; obj != nil {
+		stateObjectHitCounter.Inc(1)
 		return obj
 	}
+	stateObjectMissCounter.Inc(1)
 	// If no live objects are available, attempt to use snapshots
 	var (
 		acc account.Account
@@ -337,6 +337,9 @@ func (bc *BlockChain) PrepareStateMigration() error {
 	if bc.db.InMigration() || bc.prepareStateMigration {
 		return errors.New("migration already started")
 	}
+	if bc.cacheConfig.TrieNodeRefCount {
+		return ErrTrieNodeRefCountStateMigration
+	}
 
 	bc.prepareStateMigration = true
 	logger.Info("State migration is prepared", "expectedMigrationStartingBlockNumber", bc.CurrentBlock().NumberU64()+1)
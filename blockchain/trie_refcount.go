@@ -0,0 +1,173 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/klaytn/klaytn/storage/statedb"
+)
+
+var (
+	ErrTrieNodeRefCountArchiveOnly     = errors.New("trie node reference counting is only supported in archive mode")
+	ErrTrieNodeRefCountNotBuilt        = errors.New("trie node reference counts are not built, run `db refcount` command first")
+	ErrTrieNodeRefCountDisabled        = errors.New("trie node reference counting is disabled")
+	ErrTrieNodeRefCountStateMigration  = errors.New("state migration is not supported with trie node reference counting")
+	ErrTrieNodeRefCountInterrupted     = errors.New("trie node reference counting is interrupted")
+	ErrPruningStateTriesAlreadyStarted = errors.New("pruning state tries is already started")
+)
+
+// ReferenceStateRoots references the state roots of the canonical blocks up to
+// the given block number, which are not referenced yet. If no state root is
+// referenced yet, it builds the reference counts from the genesis block. The
+// progress is recorded for every block, so it can be resumed if interrupted.
+func ReferenceStateRoots(db database.DBManager, trieDB *statedb.Database, to uint64, quit <-chan struct{}) error {
+	tail, head, ok := db.ReadTrieNodeRefRange()
+	from := head + 1
+	if !ok {
+		from = 0
+	}
+	if from > to {
+		return nil
+	}
+
+	logger.Info("Referencing state roots", "from", from, "to", to)
+	start, logged := time.Now(), time.Now()
+	for number := from; number <= to; number++ {
+		select {
+		case <-quit:
+			return ErrTrieNodeRefCountInterrupted
+		default:
+		}
+
+		header := db.ReadHeader(db.ReadCanonicalHash(number), number)
+		if header == nil {
+			return fmt.Errorf("block #%d not found", number)
+		}
+		if err := trieDB.ReferenceRoot(header.Root); err != nil {
+			return err
+		}
+		db.WriteTrieNodeRefRange(tail, number)
+
+		if time.Since(logged) > 8*time.Second {
+			logger.Info("Referencing state roots", "number", number, "to", to, "elapsed", time.Since(start))
+			logged = time.Now()
+		}
+	}
+	logger.Info("Referenced state roots", "from", from, "to", to, "elapsed", time.Since(start))
+	return nil
+}
+
+// setupTrieNodeRefCount enables the reference counting of the trie nodes, and
+// references the state roots of the blocks written while it was disabled.
+func (bc *BlockChain) setupTrieNodeRefCount() error {
+	if !bc.isArchiveMode() {
+		return ErrTrieNodeRefCountArchiveOnly
+	}
+	current := bc.CurrentBlock().NumberU64()
+	if _, _, ok := bc.db.ReadTrieNodeRefRange(); !ok && current > 0 {
+		return ErrTrieNodeRefCountNotBuilt
+	}
+
+	trieDB := bc.stateCache.TrieDB()
+	trieDB.EnableRefCount(state.StorageTrieRefs)
+	return ReferenceStateRoots(bc.db, trieDB, current, bc.quit)
+}
+
+// updateTrieNodeRefHead records that the state root of the given block is
+// referenced.
+func (bc *BlockChain) updateTrieNodeRefHead(number uint64) {
+	bc.trieNodeRefMu.Lock()
+	defer bc.trieNodeRefMu.Unlock()
+
+	if tail, head, _ := bc.db.ReadTrieNodeRefRange(); number > head {
+		bc.db.WriteTrieNodeRefRange(tail, number)
+	}
+}
+
+// StartPruningStateTries starts deleting the trie nodes only reachable from the
+// state roots of the blocks before the given block number. The state of the
+// recent blocks kept in memory can not be pruned.
+func (bc *BlockChain) StartPruningStateTries(to uint64) error {
+	if !bc.cacheConfig.TrieNodeRefCount {
+		return ErrTrieNodeRefCountDisabled
+	}
+	if current := bc.CurrentBlock().NumberU64(); to+bc.triesInMemory() > current {
+		return fmt.Errorf("the state of the recent %d blocks can not be pruned (current: %d)", bc.triesInMemory(), current)
+	}
+	if !atomic.CompareAndSwapInt32(&bc.pruningStateTries, 0, 1) {
+		return ErrPruningStateTriesAlreadyStarted
+	}
+
+	go func() {
+		defer atomic.StoreInt32(&bc.pruningStateTries, 0)
+		if err := bc.pruneStateTries(to); err != nil {
+			logger.Error("Failed to prune state tries", "to", to, "err", err)
+		}
+	}()
+	return nil
+}
+
+// pruneStateTries dereferences the state roots of the blocks from the tail of
+// the reference range to the given block number, excluding it.
+func (bc *BlockChain) pruneStateTries(to uint64) error {
+	tail, _, _ := bc.db.ReadTrieNodeRefRange()
+	if tail >= to {
+		return nil
+	}
+
+	logger.Info("Pruning state tries", "from", tail, "to", to)
+	trieDB := bc.stateCache.TrieDB()
+	start, logged, deleted := time.Now(), time.Now(), 0
+	for number := tail; number < to; number++ {
+		select {
+		case <-bc.quit:
+			return ErrTrieNodeRefCountInterrupted
+		default:
+		}
+
+		header := bc.GetHeaderByNumber(number)
+		if header == nil {
+			return fmt.Errorf("block #%d not found", number)
+		}
+
+		// The range is updated first, since dereferencing a root twice deletes the
+		// nodes still referenced while not dereferencing it only leaves garbage.
+		bc.trieNodeRefMu.Lock()
+		_, head, _ := bc.db.ReadTrieNodeRefRange()
+		bc.db.WriteTrieNodeRefRange(number+1, head)
+		bc.trieNodeRefMu.Unlock()
+
+		n, err := trieDB.DereferenceRoot(header.Root)
+		deleted += n
+		if err != nil {
+			return err
+		}
+
+		if time.Since(logged) > 8*time.Second {
+			logger.Info("Pruning state tries", "number", number, "to", to, "deleted", deleted, "elapsed", time.Since(start))
+			logged = time.Now()
+		}
+	}
+	logger.Info("Pruned state tries", "from", tail, "to", to, "deleted", deleted, "elapsed", time.Since(start))
+	return nil
+}
@@ -539,6 +539,18 @@ func (pool *TxPool) SetGasPrice(price *big.Int) {
 	}
 }
 
+// SetSlotLimits changes the limits of the executable and non-executable
+// transaction slots. The transactions exceeding the new limits are dropped when
+// the pool is promoted next time.
+func (pool *TxPool) SetSlotLimits(execAccount, execAll, nonExecAccount, nonExecAll uint64) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	logger.Info("TxPool.SetSlotLimits", "execAccount", execAccount, "execAll", execAll, "nonExecAccount", nonExecAccount, "nonExecAll", nonExecAll)
+	pool.config.ExecSlotsAccount, pool.config.ExecSlotsAll = execAccount, execAll
+	pool.config.NonExecSlotsAccount, pool.config.NonExecSlotsAll = nonExecAccount, nonExecAll
+}
+
 // Stats retrieves the current pool stats, namely the number of pending and the
 // number of queued (non-executable) transactions.
 func (pool *TxPool) Stats() (int, int) {
@@ -1168,8 +1180,9 @@ func (pool *TxPool) AddLocal(tx *types.Transaction) error {
 
 	pool.mu.RLock()
 	poolSize := uint64(len(pool.all))
+	poolLimit := pool.config.ExecSlotsAll + pool.config.NonExecSlotsAll
 	pool.mu.RUnlock()
-	if poolSize >= pool.config.ExecSlotsAll+pool.config.NonExecSlotsAll {
+	if poolSize >= poolLimit {
 		return fmt.Errorf("txpool is full: %d", poolSize)
 	}
 	return pool.addTx(tx, !pool.config.NoLocals)
@@ -1202,8 +1215,13 @@ func (pool *TxPool) AddRemotes(txs []*types.Transaction) []error {
 func (pool *TxPool) checkAndAddTxs(txs []*types.Transaction, local bool) []error {
 	pool.mu.RLock()
 	poolSize := uint64(len(pool.all))
+	poolLimit := pool.config.ExecSlotsAll + pool.config.NonExecSlotsAll
 	pool.mu.RUnlock()
-	poolCapacity := int(pool.config.ExecSlotsAll + pool.config.NonExecSlotsAll - poolSize)
+	poolCapacity := 0
+	if poolSize < poolLimit {
+		// the pool may exceed the limit lowered at runtime
+		poolCapacity = int(poolLimit - poolSize)
+	}
 	numTxs := len(txs)
 
 	if poolCapacity < numTxs {
@@ -1303,6 +1303,33 @@ func TestTransactionPendingGlobalLimiting(t *testing.T) {
 	}
 }
 
+// Tests that the slot limits lowered at runtime below the pool size reject the
+// new transactions.
+func TestTransactionPoolSetSlotLimits(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000))
+	for _, err := range pool.AddRemotes(types.Transactions{transaction(0, 100000, key), transaction(1, 100000, key), transaction(2, 100000, key)}) {
+		if err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+
+	pool.SetSlotLimits(1, 1, 1, 1)
+	if errs := pool.AddRemotes(types.Transactions{transaction(3, 100000, key)}); len(errs) != 1 || errs[0] != txPoolIsFullErr {
+		t.Fatalf("error mismatch: have %v, want %v", errs, txPoolIsFullErr)
+	}
+	if err := pool.AddLocal(transaction(3, 100000, key)); err == nil {
+		t.Fatal("the local transaction is added to the full pool")
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Tests that if transactions start being capped, transactions are also removed from 'all'
 func TestTransactionCapClearsFromAll(t *testing.T) {
 	t.Parallel()
@@ -20,6 +20,7 @@ import (
 	"crypto/ecdsa"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 
 	"github.com/klaytn/klaytn/common"
@@ -37,6 +38,29 @@ const (
 	RoleLast
 )
 
+var roleTypeNames = [...]string{
+	RoleTransaction:   "transaction",
+	RoleAccountUpdate: "accountUpdate",
+	RoleFeePayer:      "feePayer",
+}
+
+func (r RoleType) String() string {
+	if r >= 0 && r < RoleLast {
+		return roleTypeNames[r]
+	}
+	return fmt.Sprintf("role(%d)", int(r))
+}
+
+// RoleTypeFromString returns the role of the name, e.g. "feePayer".
+func RoleTypeFromString(name string) (RoleType, error) {
+	for r, n := range roleTypeNames {
+		if n == name {
+			return RoleType(r), nil
+		}
+	}
+	return RoleLast, fmt.Errorf("unknown role %q", name)
+}
+
 var (
 	errKeyLengthZero                    = errors.New("key length is zero")
 	errKeyShouldNotBeNilOrCompositeType = errors.New("key should not be nil or a composite type")
@@ -17,9 +17,13 @@
 package types
 
 import (
+	"bytes"
+	"compress/zlib"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"math/big"
 
 	"github.com/klaytn/klaytn/common"
@@ -28,10 +32,18 @@ import (
 
 const (
 	AnchoringDataType0    uint8 = 0
+	AnchoringDataType1    uint8 = 1 // zlib compressed anchoring data of the consecutive blocks
 	AnchoringJSONDataType uint8 = 128
 )
 
-var errUnknownAnchoringTxType = errors.New("unknown anchoring tx type")
+// maxAnchoringDataType1Size is the maximum size of the decompressed type1 anchoring data.
+const maxAnchoringDataType1Size = 1024 * 1024
+
+var (
+	errUnknownAnchoringTxType    = errors.New("unknown anchoring tx type")
+	errEmptyAnchoringBatch       = errors.New("no block in the anchoring batch")
+	errAnchoringBatchSizeExceeds = errors.New("the decompressed anchoring batch exceeds the size limit")
+)
 
 type AnchoringDataInternal interface {
 	GetBlockHash() common.Hash
@@ -80,8 +92,8 @@ func (data *AnchoringDataInternalType0) GetBlockNumber() *big.Int {
 	return data.BlockNumber
 }
 
-func NewAnchoringDataType0(block *Block, blockCount uint64, txCount uint64) (*AnchoringData, error) {
-	data := &AnchoringDataInternalType0{
+func NewAnchoringDataInternalType0(block *Block, blockCount uint64, txCount uint64) *AnchoringDataInternalType0 {
+	return &AnchoringDataInternalType0{
 		block.Hash(),
 		block.Header().TxHash,
 		block.Header().ParentHash,
@@ -91,13 +103,79 @@ func NewAnchoringDataType0(block *Block, blockCount uint64, txCount uint64) (*An
 		new(big.Int).SetUint64(blockCount),
 		new(big.Int).SetUint64(txCount),
 	}
-	encodedCCTxData, err := rlp.EncodeToBytes(data)
+}
+
+func NewAnchoringDataType0(block *Block, blockCount uint64, txCount uint64) (*AnchoringData, error) {
+	encodedCCTxData, err := rlp.EncodeToBytes(NewAnchoringDataInternalType0(block, blockCount, txCount))
 	if err != nil {
 		return nil, err
 	}
 	return &AnchoringData{AnchoringDataType0, encodedCCTxData}, nil
 }
 
+// AnchoringDataInternalType1 is the anchoring data of the consecutive blocks
+// anchored by a transaction. Each block has the blockCount of 1 and its own txCount.
+type AnchoringDataInternalType1 struct {
+	Blocks []*AnchoringDataInternalType0 `json:"blocks"`
+}
+
+// GetBlockHash returns the hash of the last block of the batch.
+func (data *AnchoringDataInternalType1) GetBlockHash() common.Hash {
+	return data.Blocks[len(data.Blocks)-1].BlockHash
+}
+
+// GetBlockNumber returns the number of the last block of the batch.
+func (data *AnchoringDataInternalType1) GetBlockNumber() *big.Int {
+	return data.Blocks[len(data.Blocks)-1].BlockNumber
+}
+
+// NewAnchoringDataType1 returns the anchoring data of the blocks, whose RLP
+// encoding is compressed by zlib.
+func NewAnchoringDataType1(blocks []*AnchoringDataInternalType0) (*AnchoringData, error) {
+	if len(blocks) == 0 {
+		return nil, errEmptyAnchoringBatch
+	}
+	encoded, err := rlp.EncodeToBytes(&AnchoringDataInternalType1{Blocks: blocks})
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w, err := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(encoded); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return &AnchoringData{AnchoringDataType1, buf.Bytes()}, nil
+}
+
+func decodeAnchoringDataType1(data []byte) (*AnchoringDataInternalType1, error) {
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	encoded, err := ioutil.ReadAll(io.LimitReader(r, maxAnchoringDataType1Size+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(encoded) > maxAnchoringDataType1Size {
+		return nil, errAnchoringBatchSizeExceeds
+	}
+	anchoringDataInternal := new(AnchoringDataInternalType1)
+	if err := rlp.DecodeBytes(encoded, anchoringDataInternal); err != nil {
+		return nil, err
+	}
+	if len(anchoringDataInternal.Blocks) == 0 {
+		return nil, errEmptyAnchoringBatch
+	}
+	return anchoringDataInternal, nil
+}
+
 func NewAnchoringJSONDataType(v interface{}) (*AnchoringData, error) {
 	encoded, err := json.Marshal(v)
 	if err != nil {
@@ -125,6 +203,14 @@ func DecodeAnchoringData(data []byte) (AnchoringDataInternal, error) {
 		logger.Trace("decoded type0 anchoring tx", "blockNum", anchoringDataInternal.BlockNumber.String(), "blockHash", anchoringDataInternal.BlockHash.String(), "txHash", anchoringDataInternal.TxHash.String(), "txCount", anchoringDataInternal.TxCount)
 		return anchoringDataInternal, nil
 	}
+	if anchoringData.Type == AnchoringDataType1 {
+		anchoringDataInternal, err := decodeAnchoringDataType1(anchoringData.Data)
+		if err != nil {
+			return nil, err
+		}
+		logger.Trace("decoded type1 anchoring tx", "blockNum", anchoringDataInternal.GetBlockNumber().String(), "blockHash", anchoringDataInternal.GetBlockHash().String(), "blockCount", len(anchoringDataInternal.Blocks))
+		return anchoringDataInternal, nil
+	}
 	return nil, errUnknownAnchoringTxType
 }
 
@@ -145,6 +231,9 @@ func DecodeAnchoringDataToJSON(data []byte) (interface{}, error) {
 		}
 		return anchoringDataInternal, nil
 	}
+	if anchoringData.Type == AnchoringDataType1 {
+		return decodeAnchoringDataType1(anchoringData.Data)
+	}
 	if anchoringData.Type == AnchoringJSONDataType {
 		var v map[string]interface{}
 		if err := json.Unmarshal(anchoringData.Data, &v); err != nil {
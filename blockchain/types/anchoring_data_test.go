@@ -141,3 +141,41 @@ func TestDecodingAnchoringTxJSONType(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expResult, actResult)
 }
+
+func TestDecodingAnchoringTxType1(t *testing.T) {
+	var blocks []*AnchoringDataInternalType0
+	for i := 0; i < 10; i++ {
+		block := genBlock()
+		block.header.Number = big.NewInt(int64(100 + i))
+		blocks = append(blocks, NewAnchoringDataInternalType0(block, 1, uint64(i)))
+	}
+	anchoringData, err := NewAnchoringDataType1(blocks)
+	assert.NoError(t, err)
+	assert.Equal(t, AnchoringDataType1, anchoringData.Type)
+
+	// The batch is compressed smaller than the type0 anchoring data of the blocks.
+	uncompressed, err := rlp.EncodeToBytes(&AnchoringDataInternalType1{Blocks: blocks})
+	assert.NoError(t, err)
+	assert.Less(t, len(anchoringData.Data), len(uncompressed))
+
+	data, err := rlp.EncodeToBytes(anchoringData)
+	assert.NoError(t, err)
+
+	decodedData, err := DecodeAnchoringData(data)
+	assert.NoError(t, err)
+	batch, ok := decodedData.(*AnchoringDataInternalType1)
+	assert.True(t, ok)
+	assert.Equal(t, blocks, batch.Blocks)
+	assert.Equal(t, blocks[9].BlockHash, decodedData.GetBlockHash())
+	assert.Equal(t, big.NewInt(109), decodedData.GetBlockNumber())
+
+	decodedDataJSON, err := DecodeAnchoringDataToJSON(data)
+	assert.NoError(t, err)
+	assert.Equal(t, batch, decodedDataJSON)
+
+	// An empty batch is neither encoded nor decoded.
+	_, err = NewAnchoringDataType1(nil)
+	assert.Equal(t, errEmptyAnchoringBatch, err)
+	_, err = DecodeAnchoringData(common.Hex2Bytes("c401820000"))
+	assert.Error(t, err)
+}
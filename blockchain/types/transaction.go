@@ -236,6 +236,19 @@ func (tx *Transaction) UnmarshalBinary(b []byte) error {
 	return nil
 }
 
+// UnmarshalUnsignedBinary decodes the canonical encoding of a transaction
+// without validating its signatures, e.g. of a transaction to be signed by an
+// external signer.
+func (tx *Transaction) UnmarshalUnsignedBinary(b []byte) error {
+	serializer := newTxInternalDataSerializer()
+	if err := rlp.DecodeBytes(b, serializer); err != nil {
+		return err
+	}
+
+	tx.setDecoded(serializer.tx, len(b))
+	return nil
+}
+
 // MarshalJSON encodes the web3 RPC transaction format.
 func (tx *Transaction) MarshalJSON() ([]byte, error) {
 	hash := tx.Hash()
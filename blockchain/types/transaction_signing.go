@@ -31,6 +31,7 @@ import (
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/crypto"
 	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/rlp"
 )
 
 var (
@@ -117,6 +118,85 @@ func SignTxAsFeePayer(tx *Transaction, s Signer, prv *ecdsa.PrivateKey) (*Transa
 	return tx.WithFeePayerSignature(s, sig)
 }
 
+// SignTxWithKeys signs the transaction with the private keys of a multisig account
+// key using the given signer.
+func SignTxWithKeys(tx *Transaction, s Signer, prv []*ecdsa.PrivateKey) (*Transaction, error) {
+	if len(prv) == 1 {
+		return SignTx(tx, s, prv[0])
+	}
+	cpy := &Transaction{data: tx.data, time: tx.time}
+	if err := cpy.SignWithKeys(s, prv); err != nil {
+		return nil, err
+	}
+	return cpy, nil
+}
+
+// SignTxAsFeePayerWithKeys signs the transaction as a fee payer with the private
+// keys of a multisig account key using the given signer.
+func SignTxAsFeePayerWithKeys(tx *Transaction, s Signer, prv []*ecdsa.PrivateKey) (*Transaction, error) {
+	if len(prv) == 1 {
+		return SignTxAsFeePayer(tx, s, prv[0])
+	}
+	cpy := &Transaction{data: tx.data, time: tx.time}
+	if err := cpy.SignFeePayerWithKeys(s, prv); err != nil {
+		return nil, err
+	}
+	return cpy, nil
+}
+
+// SigningPayload returns the RLP-encoded payload whose keccak256 hash is signed by
+// the sender with the latest signer of the chain ID. It is given to the signers
+// hashing the payload themselves, e.g. hardware wallets, which show the details
+// of the transaction before signing.
+func SigningPayload(tx *Transaction, chainID *big.Int) ([]byte, error) {
+	if chainID == nil {
+		return nil, ErrInvalidChainId
+	}
+	if tx.IsEthTypedTransaction() {
+		// infs[0] always has chainID
+		infs := tx.data.SerializeForSign()
+		if txChainID := tx.data.ChainId(); txChainID == nil || txChainID.BitLen() == 0 {
+			infs[0] = chainID
+		}
+		enc, err := rlp.EncodeToBytes(infs)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{byte(tx.Type())}, enc...), nil
+	}
+	if ser, ok := tx.data.(TxInternalDataSerializeForSignToByte); ok {
+		return rlp.EncodeToBytes(struct {
+			Byte    []byte
+			ChainId *big.Int
+			R       uint
+			S       uint
+		}{ser.SerializeForSignToBytes(), chainID, uint(0), uint(0)})
+	}
+	return rlp.EncodeToBytes(append(tx.data.SerializeForSign(), chainID, uint(0), uint(0)))
+}
+
+// FeePayerSigningPayload returns the RLP-encoded payload whose keccak256 hash is
+// signed by the fee payer of a fee-delegated transaction.
+func FeePayerSigningPayload(tx *Transaction, chainID *big.Int) ([]byte, error) {
+	if chainID == nil {
+		return nil, ErrInvalidChainId
+	}
+	tf, ok := tx.data.(TxInternalDataFeePayer)
+	if !ok {
+		return nil, errNotFeeDelegationTransaction
+	}
+	if ser, ok := tx.data.(TxInternalDataSerializeForSignToByte); ok {
+		return rlp.EncodeToBytes(struct {
+			Byte     []byte
+			FeePayer common.Address
+			ChainId  *big.Int
+			R        uint
+			S        uint
+		}{ser.SerializeForSignToBytes(), tf.GetFeePayer(), chainID, uint(0), uint(0)})
+	}
+	return rlp.EncodeToBytes(append(tx.data.SerializeForSign(), tf.GetFeePayer(), chainID, uint(0), uint(0)))
+}
+
 // AccountKeyPicker has a function GetKey() to retrieve an account key from statedb.
 type AccountKeyPicker interface {
 	GetKey(address common.Address) accountkey.AccountKey
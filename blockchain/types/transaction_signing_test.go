@@ -2006,3 +2006,47 @@ func testValidateFeeDelegatedChainDataAnchoringWithRatio(t *testing.T) {
 func getFunctionName(i interface{}) string {
 	return runtime.FuncForPC(reflect.ValueOf(i).Pointer()).Name()
 }
+
+// TestSigningPayload checks that the hashes of the payloads are the ones signed
+// by the latest signer for all transaction types.
+func TestSigningPayload(t *testing.T) {
+	txs := []TxInternalData{
+		genLegacyTransaction(),
+		genValueTransferTransaction(),
+		genFeeDelegatedValueTransferTransaction(),
+		genFeeDelegatedValueTransferWithRatioTransaction(),
+		genValueTransferMemoTransaction(),
+		genFeeDelegatedValueTransferMemoTransaction(),
+		genAccountUpdateTransaction(),
+		genFeeDelegatedAccountUpdateWithRatioTransaction(),
+		genSmartContractDeployTransaction(),
+		genFeeDelegatedSmartContractExecutionTransaction(),
+		genCancelTransaction(),
+		genFeeDelegatedChainDataTransaction(),
+		genAccessListTransaction(),
+		genDynamicFeeTransaction(),
+	}
+	chainID := big.NewInt(1001)
+	signer := LatestSignerForChainID(chainID)
+
+	for _, data := range txs {
+		tx := NewTx(data)
+
+		payload, err := SigningPayload(tx, chainID)
+		assert.NoError(t, err, tx.Type().String())
+		assert.Equal(t, signer.Hash(tx), crypto.Keccak256Hash(payload), tx.Type().String())
+
+		payload, err = FeePayerSigningPayload(tx, chainID)
+		if _, ok := data.(TxInternalDataFeePayer); !ok {
+			assert.Equal(t, errNotFeeDelegationTransaction, err, tx.Type().String())
+			continue
+		}
+		assert.NoError(t, err, tx.Type().String())
+		hash, err := signer.HashFeePayer(tx)
+		assert.NoError(t, err)
+		assert.Equal(t, hash, crypto.Keccak256Hash(payload), tx.Type().String())
+	}
+
+	_, err := SigningPayload(NewTx(genLegacyTransaction()), nil)
+	assert.Equal(t, ErrInvalidChainId, err)
+}
@@ -0,0 +1,106 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+)
+
+// VerifyBlocks re-executes the canonical blocks from `from` to `to` on the state
+// of the block before `from`, and checks the header and seal, the transaction
+// root, the state root, the receipt root and the stored receipts of each block.
+// It stops at the first block failing the check. The states of the re-executed
+// blocks are kept in memory, so the database is not modified.
+func (bc *BlockChain) VerifyBlocks(from, to uint64) error {
+	if from == 0 {
+		return errors.New("the genesis block can't be re-executed")
+	}
+	if from > to {
+		return fmt.Errorf("invalid block range #%d-#%d", from, to)
+	}
+	if head := bc.CurrentBlock().NumberU64(); to > head {
+		return fmt.Errorf("block #%d is after the head block #%d", to, head)
+	}
+	parent := bc.GetBlockByNumber(from - 1)
+	if parent == nil {
+		return fmt.Errorf("block #%d is not found", from-1)
+	}
+	database := state.NewDatabaseWithExistingCache(bc.db, bc.stateCache.TrieDB().TrieNodeCache())
+	statedb, err := state.New(parent.Root(), database, nil)
+	if err != nil {
+		return fmt.Errorf("state of block #%d is not available, start from a block after a block with the state: %v", from-1, err)
+	}
+
+	var (
+		start  = time.Now()
+		logged = time.Now()
+		proot  common.Hash
+	)
+	for number := from; number <= to; number++ {
+		block := bc.GetBlockByNumber(number)
+		if block == nil {
+			return fmt.Errorf("block #%d is not found", number)
+		}
+		if block.ParentHash() != parent.Hash() {
+			return fmt.Errorf("block #%d: parent hash mismatch (have %x, want %x)", number, block.ParentHash(), parent.Hash())
+		}
+		if err := bc.engine.VerifyHeader(bc, block.Header(), true); err != nil {
+			return fmt.Errorf("block #%d: invalid header: %v", number, err)
+		}
+		if hash := types.DeriveSha(block.Transactions()); hash != block.Header().TxHash {
+			return fmt.Errorf("block #%d: transaction root hash mismatch (have %x, want %x)", number, hash, block.Header().TxHash)
+		}
+		receipts, _, usedGas, _, _, err := bc.processor.Process(block, statedb, bc.vmConfig)
+		if err != nil {
+			return fmt.Errorf("block #%d: processing failed: %v", number, err)
+		}
+		if err := bc.validator.ValidateState(block, parent, statedb, receipts, usedGas); err != nil {
+			return fmt.Errorf("block #%d: %v", number, err)
+		}
+		if hash := types.DeriveSha(bc.GetReceiptsByBlockHash(block.Hash())); hash != block.ReceiptHash() {
+			return fmt.Errorf("block #%d: stored receipts mismatch (have root %x, want %x)", number, hash, block.ReceiptHash())
+		}
+
+		// Keep the state in memory to process the next block on it.
+		root, err := statedb.Commit(true)
+		if err != nil {
+			return fmt.Errorf("block #%d: state commit failed: %v", number, err)
+		}
+		if err := statedb.Reset(root); err != nil {
+			return fmt.Errorf("block #%d: state reset failed: %v", number, err)
+		}
+		database.TrieDB().Reference(root, common.Hash{})
+		if !common.EmptyHash(proot) {
+			database.TrieDB().Dereference(proot)
+		}
+		proot = root
+		parent = block
+
+		if time.Since(logged) > 8*time.Second {
+			logger.Info("Verifying the blocks", "number", number, "to", to, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	logger.Info("Verified the blocks", "from", from, "to", to, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
@@ -196,7 +196,9 @@ func buildFlags(env build.Environment) (flags []string) {
 	if env.Commit != "" {
 		ld = append(ld, "-X", "main.gitCommit="+env.Commit)
 		ld = append(ld, "-X", "github.com/klaytn/klaytn/cmd/utils/nodecmd.gitCommit="+env.Commit)
+		ld = append(ld, "-X", "github.com/klaytn/klaytn/params.GitCommit="+env.Commit)
 	}
+	ld = append(ld, "-X", "github.com/klaytn/klaytn/params.BuildDate="+time.Now().UTC().Format(time.RFC3339))
 	if env.Tag != "" {
 		ld = append(ld, "-X", "github.com/klaytn/klaytn/cmd/utils/nodecmd.gitTag="+env.Tag)
 	}
@@ -266,6 +266,19 @@ type rpcProgress struct {
 	HighestBlock  hexutil.Uint64
 	PulledStates  hexutil.Uint64
 	KnownStates   hexutil.Uint64
+	PivotBlock    hexutil.Uint64
+	Resumed       bool
+
+	Phase          string
+	PulledHeaders  hexutil.Uint64
+	PulledBodies   hexutil.Uint64
+	PulledReceipts hexutil.Uint64
+	HeaderRate     float64
+	BodyRate       float64
+	ReceiptRate    float64
+	StateRate      float64
+	BlockRate      float64
+	ETA            hexutil.Uint64
 }
 
 // SyncProgress retrieves the current progress of the sync algorithm. If there's
@@ -290,6 +303,19 @@ func (ec *Client) SyncProgress(ctx context.Context) (*klaytn.SyncProgress, error
 		HighestBlock:  uint64(progress.HighestBlock),
 		PulledStates:  uint64(progress.PulledStates),
 		KnownStates:   uint64(progress.KnownStates),
+		PivotBlock:    uint64(progress.PivotBlock),
+		Resumed:       progress.Resumed,
+
+		Phase:          progress.Phase,
+		PulledHeaders:  uint64(progress.PulledHeaders),
+		PulledBodies:   uint64(progress.PulledBodies),
+		PulledReceipts: uint64(progress.PulledReceipts),
+		HeaderRate:     progress.HeaderRate,
+		BodyRate:       progress.BodyRate,
+		ReceiptRate:    progress.ReceiptRate,
+		StateRate:      progress.StateRate,
+		BlockRate:      progress.BlockRate,
+		ETA:            uint64(progress.ETA),
 	}, nil
 }
 
@@ -68,8 +68,12 @@ var SetupCommand = cli.Command{
 
 		for Klaytn Consensus Node.
 
+		The topology type generates the node files, Terraform variables and
+		Kubernetes manifests of CNs, PNs and ENs spread over the zones of --zones,
+		where the PNs of each zone are the sentries of its CNs.
+
 Args :
-		type : [local | remote | deploy | docker (default)]
+		type : [local | remote | deploy | topology | docker (default)]
 `,
 	Action: gen,
 	Flags: []cli.Flag{
@@ -108,6 +112,8 @@ Args :
 		p2pPortFlag,
 		dataDirFlag,
 		logDirFlag,
+		zonesFlag,
+		k8sNamespaceFlag,
 		governanceFlag,
 		govModeFlag,
 		governingNodeFlag,
@@ -143,6 +149,7 @@ const (
 	TypeLocal             = 1
 	TypeRemote            = 2
 	TypeDeploy            = 3
+	TypeTopology          = 4
 	DirScript             = "scripts"
 	DirKeys               = "keys"
 	DirPnScript           = "scripts_pn"
@@ -153,7 +160,7 @@ const (
 	PNIpNetwork2          = "10.11.11"
 )
 
-var Types = [4]string{"docker", "local", "remote", "deploy"}
+var Types = [5]string{"docker", "local", "remote", "deploy", "topology"}
 
 var GrafanaFiles = [...]GrafanaFile{
 	{
@@ -621,6 +628,18 @@ func gen(ctx *cli.Context) error {
 			ctx.String(dataDirFlag.Name), ctx.String(logDirFlag.Name), "PN")
 		writePNInfoKey(ctx.Int(numOfPNsFlag.Name))
 		writePrometheusConfig(cnNum, ctx.Int(numOfPNsFlag.Name))
+	case TypeTopology:
+		zones, err := parseZones(ctx.String(zonesFlag.Name))
+		if err != nil {
+			return err
+		}
+		topology, err := makeTopology(zones, nodeKeys, privKeys, nodeAddrs, pnNum, enNum, uint16(ctx.Int(p2pPortFlag.Name)))
+		if err != nil {
+			return err
+		}
+		if err := writeTopologyFiles(ctx, topology, genesisJsonBytes); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -1012,7 +1031,7 @@ func findGenType(ctx *cli.Context) int {
 			}
 		}
 		if genType == TypeNotDefined {
-			fmt.Printf("Wrong Type : %s\nSupported Types : [docker, local, remote, deploy]\n\n", ctx.Args()[0])
+			fmt.Printf("Wrong Type : %s\nSupported Types : [docker, local, remote, deploy, topology]\n\n", ctx.Args()[0])
 			cli.ShowSubcommandHelp(ctx)
 			os.Exit(1)
 		}
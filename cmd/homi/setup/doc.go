@@ -30,5 +30,6 @@ Each file contains the following contents
  - flags.go : Defines command line flags which can be used in `setup` command
  - klaytn_config.go : Defines `KlaytnConfig` and provides a template to build it
  - prometheus_config.go : Defines `PrometheusConfig` and provides a template to build it
+ - topology.go : Spreads the nodes over zones and regions and builds their Terraform variables and Kubernetes manifests
 */
 package setup
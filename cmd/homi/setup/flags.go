@@ -232,6 +232,17 @@ var (
 		Value: "/var/klay/data",
 	}
 
+	zonesFlag = cli.StringFlag{
+		Name:  "zones",
+		Usage: "(topology only) comma separated zones to spread the nodes over, e.g., ap-northeast-2/a,ap-northeast-2/c,us-east-1/a",
+	}
+
+	k8sNamespaceFlag = cli.StringFlag{
+		Name:  "k8s-namespace",
+		Usage: "(topology only) namespace of the kubernetes manifests [default : klaytn]",
+		Value: "klaytn",
+	}
+
 	logDirFlag = cli.StringFlag{
 		Name:  "log-dir",
 		Usage: "klay.conf - Klaytn node's log directory path [default : /var/klay/log]",
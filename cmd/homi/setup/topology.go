@@ -0,0 +1,405 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package setup
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"net"
+	"path"
+	"strings"
+	"text/template"
+
+	istcommon "github.com/klaytn/klaytn/cmd/homi/common"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// The host numbers of the placeholder private IPs of the nodes in each zone.
+// The zones are 10.<10 + region index>.<zone index in the region>.0/24.
+const (
+	cnHostBase = 11
+	pnHostBase = 101
+	enHostBase = 151
+	maxHost    = 254
+)
+
+// Zone is an availability zone of a region the nodes are spread over.
+type Zone struct {
+	Region string `json:"region"`
+	Name   string `json:"zone"`
+}
+
+func (z Zone) String() string {
+	return z.Region + "/" + z.Name
+}
+
+// TopologyNode is a node of the topology and its connections.
+type TopologyNode struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Region      string   `json:"region"`
+	Zone        string   `json:"zone"`
+	IP          string   `json:"ip"`
+	Enode       string   `json:"enode"`
+	Address     string   `json:"address"`
+	StaticNodes []string `json:"staticNodes"`
+
+	nodeKey  string
+	location Zone
+}
+
+// Topology is the CNs, PNs and ENs spread over the zones. The PNs of a zone are
+// the sentries of the CNs in it: a CN connects to the other CNs and the PNs of
+// its zone only, a PN connects to the CNs of its zone and the other PNs, and an
+// EN connects to the PNs of its region, or all PNs if its region has none.
+type Topology struct {
+	Zones []Zone          `json:"zones"`
+	Nodes []*TopologyNode `json:"nodes"`
+}
+
+// parseZones parses the zones given as "region/zone" separated by commas.
+func parseZones(s string) ([]Zone, error) {
+	var zones []Zone
+	seen := make(map[Zone]bool)
+	for _, z := range strings.Split(s, ",") {
+		z = strings.TrimSpace(z)
+		if z == "" {
+			continue
+		}
+		parts := strings.Split(z, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid zone %q, it should be region/zone", z)
+		}
+		zone := Zone{Region: parts[0], Name: parts[1]}
+		if seen[zone] {
+			return nil, fmt.Errorf("duplicated zone %q", z)
+		}
+		seen[zone] = true
+		zones = append(zones, zone)
+	}
+	if len(zones) == 0 {
+		return nil, fmt.Errorf("needed at least one zone (--zones region/zone)")
+	}
+	return zones, nil
+}
+
+// makeTopology spreads the CNs of the given keys, and the given numbers of PNs
+// and ENs, over the zones in round robin.
+func makeTopology(zones []Zone, cnNodeKeys []string, cnPrivKeys []*ecdsa.PrivateKey, cnAddrs []common.Address,
+	pnNum, enNum int, p2pPort uint16,
+) (*Topology, error) {
+	var (
+		subnets = make(map[Zone]string)
+		regions = make(map[string]int)
+		inZone  = make(map[string]int)
+	)
+	for _, z := range zones {
+		if _, ok := regions[z.Region]; !ok {
+			regions[z.Region] = len(regions)
+		}
+		subnets[z] = fmt.Sprintf("10.%d.%d", 10+regions[z.Region], inZone[z.Region])
+		inZone[z.Region]++
+	}
+
+	t := &Topology{Zones: zones}
+	add := func(nodeType string, hostBase, hostLimit int, nodeTypeID discover.NodeType, nodeKeys []string, privKeys []*ecdsa.PrivateKey) error {
+		hosts := make(map[Zone]int)
+		for i := range privKeys {
+			z := zones[i%len(zones)]
+			host := hostBase + hosts[z]
+			if host >= hostLimit {
+				return fmt.Errorf("too many %ss in zone %s, at most %d", strings.ToUpper(nodeType), z, hostLimit-hostBase)
+			}
+			hosts[z]++
+
+			ip := fmt.Sprintf("%s.%d", subnets[z], host)
+			t.Nodes = append(t.Nodes, &TopologyNode{
+				Name:     fmt.Sprintf("%s%02d", nodeType, i+1),
+				Type:     nodeType,
+				Region:   z.Region,
+				Zone:     z.Name,
+				IP:       ip,
+				Enode:    discover.NewNode(discover.PubkeyID(&privKeys[i].PublicKey), net.ParseIP(ip), 0, p2pPort, nil, nodeTypeID).String(),
+				nodeKey:  nodeKeys[i],
+				location: z,
+			})
+		}
+		return nil
+	}
+	if err := add("cn", cnHostBase, pnHostBase, discover.NodeTypeCN, cnNodeKeys, cnPrivKeys); err != nil {
+		return nil, err
+	}
+	for i, addr := range cnAddrs {
+		t.Nodes[i].Address = addr.String()
+	}
+	pnPrivKeys, pnNodeKeys, _ := istcommon.GenerateKeys(pnNum)
+	if err := add("pn", pnHostBase, enHostBase, discover.NodeTypePN, pnNodeKeys, pnPrivKeys); err != nil {
+		return nil, err
+	}
+	enPrivKeys, enNodeKeys, _ := istcommon.GenerateKeys(enNum)
+	if err := add("en", enHostBase, maxHost+1, discover.NodeTypeEN, enNodeKeys, enPrivKeys); err != nil {
+		return nil, err
+	}
+
+	regionHasPN := make(map[string]bool)
+	zoneHasPN := make(map[Zone]bool)
+	for _, n := range t.nodesOf("pn") {
+		regionHasPN[n.Region] = true
+		zoneHasPN[n.location] = true
+	}
+	for _, n := range t.Nodes {
+		for _, peer := range t.Nodes {
+			if peer == n {
+				continue
+			}
+			var connect bool
+			switch n.Type {
+			case "cn":
+				connect = peer.Type == "cn" || (peer.Type == "pn" && peer.location == n.location)
+			case "pn":
+				connect = peer.Type == "pn" || (peer.Type == "cn" && peer.location == n.location)
+			case "en":
+				connect = peer.Type == "pn" && (peer.Region == n.Region || !regionHasPN[n.Region])
+			}
+			if connect {
+				n.StaticNodes = append(n.StaticNodes, peer.Enode)
+			}
+		}
+		if n.Type == "cn" && pnNum > 0 && !zoneHasPN[n.location] {
+			fmt.Printf("Warning : %s in zone %s has no PN as its sentry\n", n.Name, n.location)
+		}
+	}
+	return t, nil
+}
+
+func (t *Topology) nodesOf(nodeType string) []*TopologyNode {
+	var nodes []*TopologyNode
+	for _, n := range t.Nodes {
+		if n.Type == nodeType {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// writeTopologyFiles writes the genesis, the files of each node, the Terraform
+// variables and the Kubernetes manifests of the topology.
+func writeTopologyFiles(ctx *cli.Context, t *Topology, genesisJsonBytes []byte) error {
+	WriteFile(genesisJsonBytes, "common", "genesis.json")
+	topologyJsonBytes, _ := json.MarshalIndent(t, "", "\t")
+	WriteFile(topologyJsonBytes, "", "topology.json")
+
+	for _, n := range t.Nodes {
+		dir := path.Join("nodes", n.Region, n.Zone, n.Name)
+		WriteFile([]byte(n.nodeKey), dir, "nodekey")
+		staticNodesJsonBytes, _ := json.MarshalIndent(n.StaticNodes, "", "\t")
+		WriteFile(staticNodesJsonBytes, dir, "static-nodes.json")
+		kConfig := KlaytnConfig{
+			ctx.Int(networkIdFlag.Name),
+			ctx.Int(rpcPortFlag.Name),
+			ctx.Int(wsPortFlag.Name),
+			ctx.Int(p2pPortFlag.Name),
+			ctx.String(dataDirFlag.Name),
+			ctx.String(logDirFlag.Name),
+			"/var/run/klay",
+			strings.ToUpper(n.Type),
+		}
+		WriteFile([]byte(kConfig.String()), dir, "klay.conf")
+	}
+
+	tfvars := struct {
+		Nodes []*TopologyNode `json:"klaytn_nodes"`
+	}{t.Nodes}
+	tfvarsJsonBytes, _ := json.MarshalIndent(tfvars, "", "  ")
+	WriteFile(tfvarsJsonBytes, "terraform", "nodes.auto.tfvars.json")
+	WriteFile([]byte(terraformVariables), "terraform", "variables.tf")
+
+	namespace := ctx.String(k8sNamespaceFlag.Name)
+	genesisManifest, err := renderTemplate(k8sGenesisTemplate, struct {
+		Namespace string
+		Genesis   string
+	}{namespace, indent(string(genesisJsonBytes), "    ")})
+	if err != nil {
+		return err
+	}
+	WriteFile([]byte(genesisManifest), "k8s", "genesis.yaml")
+	for _, n := range t.Nodes {
+		staticNodesJsonBytes, _ := json.MarshalIndent(n.StaticNodes, "", "  ")
+		manifest, err := renderTemplate(k8sNodeTemplate, struct {
+			*TopologyNode
+			Namespace   string
+			Image       string
+			NetworkId   int
+			RPCPort     int
+			P2PPort     int
+			NodeKey     string
+			StaticNodes string
+		}{
+			n, namespace, ctx.String(dockerImageIdFlag.Name), ctx.Int(networkIdFlag.Name), ctx.Int(rpcPortFlag.Name),
+			ctx.Int(p2pPortFlag.Name), n.nodeKey, indent(string(staticNodesJsonBytes), "    "),
+		})
+		if err != nil {
+			return err
+		}
+		WriteFile([]byte(manifest), "k8s", n.Name+".yaml")
+	}
+	return nil
+}
+
+func renderTemplate(text string, data interface{}) (string, error) {
+	tmpl, err := template.New("topology").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	res := new(bytes.Buffer)
+	if err := tmpl.Execute(res, data); err != nil {
+		return "", err
+	}
+	return res.String(), nil
+}
+
+func indent(s, prefix string) string {
+	return prefix + strings.Replace(strings.TrimRight(s, "\n"), "\n", "\n"+prefix, -1)
+}
+
+var terraformVariables = `# The nodes generated by homi. The ips are placeholders of the private addresses
+# in 10.<10 + region index>.<zone index in the region>.0/24, which should be
+# assigned to the instances since the enodes of the static nodes refer to them.
+# The address is the validator address of a CN, and empty for the others.
+variable "klaytn_nodes" {
+  type = list(object({
+    name        = string
+    type        = string
+    region      = string
+    zone        = string
+    ip          = string
+    enode       = string
+    address     = string
+    staticNodes = list(string)
+  }))
+}
+`
+
+var k8sGenesisTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: klaytn-genesis
+  namespace: {{ .Namespace }}
+data:
+  genesis.json: |
+{{ .Genesis }}
+`
+
+var k8sNodeTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Name }}-static-nodes
+  namespace: {{ .Namespace }}
+data:
+  static-nodes.json: |
+{{ .StaticNodes }}
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: {{ .Name }}-nodekey
+  namespace: {{ .Namespace }}
+stringData:
+  nodekey: "{{ .NodeKey }}"
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .Name }}
+  namespace: {{ .Namespace }}
+spec:
+  clusterIP: None
+  selector:
+    app: {{ .Name }}
+  ports:
+  - name: p2p
+    port: {{ .P2PPort }}
+  - name: rpc
+    port: {{ .RPCPort }}
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: {{ .Name }}
+  namespace: {{ .Namespace }}
+  labels:
+    klaytn.io/type: {{ .Type }}
+spec:
+  serviceName: {{ .Name }}
+  replicas: 1
+  selector:
+    matchLabels:
+      app: {{ .Name }}
+  template:
+    metadata:
+      labels:
+        app: {{ .Name }}
+        klaytn.io/type: {{ .Type }}
+    spec:
+      nodeSelector:
+        topology.kubernetes.io/region: {{ .Region }}
+        topology.kubernetes.io/zone: {{ .Zone }}
+      containers:
+      - name: k{{ .Type }}
+        image: {{ .Image }}
+        command:
+        - sh
+        - -c
+        - |
+          cp /klaytn-static-nodes/static-nodes.json /klaytn/static-nodes.json
+          k{{ .Type }} --datadir /klaytn init /klaytn-genesis/genesis.json
+          exec k{{ .Type }} --datadir /klaytn --nodekey /klaytn-nodekey/nodekey --networkid {{ .NetworkId }} --port {{ .P2PPort }} --rpc --rpcaddr 0.0.0.0 --rpcport {{ .RPCPort }}
+        ports:
+        - containerPort: {{ .P2PPort }}
+        - containerPort: {{ .RPCPort }}
+        volumeMounts:
+        - name: data
+          mountPath: /klaytn
+        - name: genesis
+          mountPath: /klaytn-genesis
+        - name: static-nodes
+          mountPath: /klaytn-static-nodes
+        - name: nodekey
+          mountPath: /klaytn-nodekey
+      volumes:
+      - name: genesis
+        configMap:
+          name: klaytn-genesis
+      - name: static-nodes
+        configMap:
+          name: {{ .Name }}-static-nodes
+      - name: nodekey
+        secret:
+          secretName: {{ .Name }}-nodekey
+  volumeClaimTemplates:
+  - metadata:
+      name: data
+    spec:
+      accessModes: ["ReadWriteOnce"]
+      resources:
+        requests:
+          storage: 500Gi
+`
@@ -86,6 +86,10 @@ func (api *PrivateBootnodeAPI) DeleteNodeFromTable(nodekni string) error {
 	return api.bn.DeleteNodeFromTable(nodekni)
 }
 
+func (api *PrivateBootnodeAPI) GetTopicNodes(topic string) ([]*discover.Node, error) {
+	return api.bn.GetTopicNodes(topic)
+}
+
 func (api *PrivateBootnodeAPI) PutAuthorizedNodes(rawurl string) error {
 	return api.bn.PutAuthorizedNodes(rawurl)
 }
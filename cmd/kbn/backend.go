@@ -17,6 +17,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -96,6 +97,14 @@ func (b *BN) GetAuthorizedNodes() []*discover.Node {
 	return b.ntab.GetAuthorizedNodes()
 }
 
+func (b *BN) GetTopicNodes(topic string) ([]*discover.Node, error) {
+	td, ok := b.ntab.(discover.TopicDiscovery)
+	if !ok {
+		return nil, errors.New("topic discovery is not supported")
+	}
+	return td.TopicNodes(discover.Topic(topic)), nil
+}
+
 func parseNodeList(rawurl string) ([]*discover.Node, error) {
 	nodeStrings := strings.Split(rawurl, ",")
 	var nodes []*discover.Node
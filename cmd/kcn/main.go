@@ -71,6 +71,18 @@ func init() {
 
 		// See utils/nodecmd/db_migration.go:
 		nodecmd.MigrationCommand,
+
+		// See utils/nodecmd/dbcmd.go:
+		nodecmd.DBCommand,
+
+		// See utils/nodecmd/snapshotcmd.go:
+		nodecmd.SnapshotCommand,
+
+		// See utils/nodecmd/verifycmd.go:
+		nodecmd.GetVerifyCommand(nodeFlags),
+
+		// See utils/nodecmd/debugbundlecmd.go:
+		nodecmd.DebugBundleCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
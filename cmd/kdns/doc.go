@@ -0,0 +1,33 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+/*
+kdns creates, signs and publishes DNS node trees, which nodes started with
+--discovery.dns use as an additional peer source.
+
+A tree definition directory contains nodes.json, a JSON array of kni:// node
+URLs, and kntree-info.json, which holds the URL, sequence number, signature and
+links to other trees. The directory name is used as the domain name unless
+--domain is given.
+
+Commands
+
+	sync <url> [ <tree-directory> ]        Download a DNS node tree
+	sign <tree-directory> <key-file>       Sign a DNS node tree with a hex private key
+	to-txt <tree-directory> [ <output> ]   Create a DNS TXT records JSON file
+	to-route53 <tree-directory>            Deploy DNS TXT records to Amazon Route53 (--zone-id)
+*/
+package main
@@ -0,0 +1,374 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/klaytn/klaytn/cmd/utils"
+	"github.com/klaytn/klaytn/cmd/utils/nodecmd"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+	"github.com/klaytn/klaytn/networks/p2p/dnsdisc"
+	"gopkg.in/urfave/cli.v1"
+)
+
+const (
+	treeNodesFile = "nodes.json"
+	treeInfoFile  = "kntree-info.json"
+)
+
+var (
+	domainFlag = cli.StringFlag{
+		Name:  "domain",
+		Usage: "Domain name of the tree",
+	}
+	seqFlag = cli.UintFlag{
+		Name:  "seq",
+		Usage: "New sequence number of the tree (default: current sequence number + 1)",
+	}
+	timeoutFlag = cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "Timeout for DNS lookups",
+		Value: 5 * time.Second,
+	}
+
+	syncCommand = cli.Command{
+		Name:      "sync",
+		Usage:     "Download a DNS node tree",
+		ArgsUsage: "<url> [ <tree-directory> ]",
+		Action:    dnsSync,
+		Flags:     []cli.Flag{timeoutFlag},
+	}
+	signCommand = cli.Command{
+		Name:      "sign",
+		Usage:     "Sign a DNS node tree",
+		ArgsUsage: "<tree-directory> <key-file>",
+		Action:    dnsSign,
+		Flags:     []cli.Flag{domainFlag, seqFlag},
+	}
+	toTXTCommand = cli.Command{
+		Name:      "to-txt",
+		Usage:     "Create a DNS TXT records JSON file",
+		ArgsUsage: "<tree-directory> [ <output-file> ]",
+		Action:    dnsToTXT,
+	}
+	toRoute53Command = cli.Command{
+		Name:      "to-route53",
+		Usage:     "Deploy DNS TXT records to Amazon Route53",
+		ArgsUsage: "<tree-directory>",
+		Action:    dnsToRoute53,
+		Flags:     []cli.Flag{route53ZoneIDFlag},
+	}
+)
+
+func init() {
+	cli.AppHelpTemplate = utils.KgenHelpTemplate
+	cli.HelpPrinter = utils.NewHelpPrinter(nil)
+}
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "kdns"
+	app.Usage = "The command line interface to create and publish DNS node trees for Klaytn"
+	app.Copyright = "Copyright 2022 The klaytn Authors"
+	app.Commands = []cli.Command{
+		syncCommand,
+		signCommand,
+		toTXTCommand,
+		toRoute53Command,
+		nodecmd.VersionCommand,
+	}
+	app.HideVersion = true
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// dnsSync performs dnsSyncCommand.
+func dnsSync(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("need tree URL as argument")
+	}
+	var (
+		url    = ctx.Args().Get(0)
+		outdir = ctx.Args().Get(1)
+	)
+	domain, _, err := dnsdisc.ParseURL(url)
+	if err != nil {
+		return err
+	}
+	if outdir == "" {
+		outdir = domain
+	}
+
+	client := dnsdisc.NewClient(dnsdisc.Config{Timeout: ctx.Duration(timeoutFlag.Name)})
+	t, err := client.SyncTree(url)
+	if err != nil {
+		return err
+	}
+	def := treeToDefinition(url, t)
+	def.Meta.LastModified = time.Now()
+	return writeTreeDefinition(outdir, def)
+}
+
+// dnsSign performs dnsSignCommand.
+func dnsSign(ctx *cli.Context) error {
+	if ctx.NArg() < 2 {
+		return fmt.Errorf("need tree definition directory and key file as arguments")
+	}
+	var (
+		defdir  = ctx.Args().Get(0)
+		keyfile = ctx.Args().Get(1)
+		domain  = directoryName(defdir)
+	)
+	def, err := loadTreeDefinition(defdir)
+	if err != nil {
+		return err
+	}
+	if def.Meta.URL != "" {
+		d, _, err := dnsdisc.ParseURL(def.Meta.URL)
+		if err != nil {
+			return fmt.Errorf("invalid 'url' field: %v", err)
+		}
+		domain = d
+	}
+	if ctx.IsSet(domainFlag.Name) {
+		domain = ctx.String(domainFlag.Name)
+	}
+	if ctx.IsSet(seqFlag.Name) {
+		def.Meta.Seq = ctx.Uint(seqFlag.Name)
+	} else {
+		def.Meta.Seq++ // Auto-bump sequence number if not supplied via flag.
+	}
+	t, err := dnsdisc.MakeTree(def.Meta.Seq, def.Nodes, def.Meta.Links)
+	if err != nil {
+		return err
+	}
+
+	key, err := loadSigningKey(keyfile)
+	if err != nil {
+		return err
+	}
+	url, err := t.Sign(key, domain)
+	if err != nil {
+		return fmt.Errorf("can't sign: %v", err)
+	}
+
+	def = treeToDefinition(url, t)
+	def.Meta.LastModified = time.Now()
+	return writeTreeDefinition(defdir, def)
+}
+
+// directoryName returns the directory name of the given path.
+// For example, when dir is "foo/bar", it returns "bar".
+// When dir is ".", and the working directory is "example/foo", it returns "foo".
+func directoryName(dir string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	return filepath.Base(dir)
+}
+
+// dnsToTXT performs dnsTXTCommand.
+func dnsToTXT(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("need tree definition directory as argument")
+	}
+	output := ctx.Args().Get(1)
+	if output == "" {
+		output = "-" // Default to stdout.
+	}
+	domain, t, err := loadTreeDefinitionForExport(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	return writeTXTJSON(output, t.ToTXT(domain))
+}
+
+// dnsToRoute53 performs dnsRoute53Command.
+func dnsToRoute53(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("need tree definition directory as argument")
+	}
+	domain, t, err := loadTreeDefinitionForExport(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	client, err := newRoute53Client(ctx)
+	if err != nil {
+		return err
+	}
+	return client.deploy(domain, t)
+}
+
+// loadSigningKey loads a private key in hex format like the nodekey file.
+func loadSigningKey(keyfile string) (*ecdsa.PrivateKey, error) {
+	key, err := crypto.LoadECDSA(keyfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the signing key: %v", err)
+	}
+	return key, nil
+}
+
+// dnsDefinition is the content of a tree definition directory.
+type dnsDefinition struct {
+	Meta  dnsMetaJSON
+	Nodes []*discover.Node
+}
+
+type dnsMetaJSON struct {
+	URL          string    `json:"url,omitempty"`
+	Seq          uint      `json:"seq"`
+	Sig          string    `json:"signature,omitempty"`
+	Links        []string  `json:"links"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+func treeToDefinition(url string, t *dnsdisc.Tree) *dnsDefinition {
+	meta := dnsMetaJSON{
+		URL:   url,
+		Seq:   t.Seq(),
+		Sig:   t.Signature(),
+		Links: t.Links(),
+	}
+	if meta.Links == nil {
+		meta.Links = []string{}
+	}
+	return &dnsDefinition{Meta: meta, Nodes: t.Nodes()}
+}
+
+// loadTreeDefinition loads a directory in 'tree definition' format.
+func loadTreeDefinition(directory string) (*dnsDefinition, error) {
+	metaFile, nodesFile := treeDefinitionFiles(directory)
+	var def dnsDefinition
+	if err := loadJSONIfExists(metaFile, &def.Meta); err != nil {
+		return nil, err
+	}
+	if def.Meta.Links == nil {
+		def.Meta.Links = []string{}
+	}
+
+	// Check link syntax.
+	for _, link := range def.Meta.Links {
+		if _, _, err := dnsdisc.ParseURL(link); err != nil {
+			return nil, fmt.Errorf("invalid link %q: %v", link, err)
+		}
+	}
+	// Check/convert nodes.
+	var urls []string
+	if err := loadJSONIfExists(nodesFile, &urls); err != nil {
+		return nil, err
+	}
+	for _, url := range urls {
+		n, err := discover.ParseNode(url)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node %q in %s: %v", url, nodesFile, err)
+		}
+		def.Nodes = append(def.Nodes, n)
+	}
+	return &def, nil
+}
+
+// loadJSONIfExists loads the JSON file into val. It does nothing if the file does not exist.
+func loadJSONIfExists(file string, val interface{}) error {
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return nil
+	}
+	return common.LoadJSON(file, val)
+}
+
+// loadTreeDefinitionForExport loads a DNS tree and ensures it is signed.
+func loadTreeDefinitionForExport(dir string) (domain string, t *dnsdisc.Tree, err error) {
+	metaFile, _ := treeDefinitionFiles(dir)
+	def, err := loadTreeDefinition(dir)
+	if err != nil {
+		return "", nil, err
+	}
+	if def.Meta.URL == "" {
+		return "", nil, fmt.Errorf("missing 'url' field in %v", metaFile)
+	}
+	domain, pubkey, err := dnsdisc.ParseURL(def.Meta.URL)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid 'url' field in %v: %v", metaFile, err)
+	}
+	if t, err = dnsdisc.MakeTree(def.Meta.Seq, def.Nodes, def.Meta.Links); err != nil {
+		return "", nil, err
+	}
+	if err := t.SetSignature(pubkey, def.Meta.Sig); err != nil {
+		return "", nil, fmt.Errorf("invalid signature in %v: %v (run kdns sign to update)", metaFile, err)
+	}
+	return domain, t, nil
+}
+
+// writeTreeDefinition writes a DNS node tree definition to the given directory.
+func writeTreeDefinition(directory string, def *dnsDefinition) error {
+	metaJSON, err := json.MarshalIndent(&def.Meta, "", jsonIndent)
+	if err != nil {
+		return err
+	}
+	urls := make([]string, 0, len(def.Nodes))
+	for _, n := range def.Nodes {
+		urls = append(urls, n.String())
+	}
+	nodesJSON, err := json.MarshalIndent(urls, "", jsonIndent)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		return err
+	}
+	metaFile, nodesFile := treeDefinitionFiles(directory)
+	if len(def.Nodes) > 0 {
+		if err := ioutil.WriteFile(nodesFile, nodesJSON, 0o644); err != nil {
+			return err
+		}
+	} else {
+		os.RemoveAll(nodesFile)
+	}
+	return ioutil.WriteFile(metaFile, metaJSON, 0o644)
+}
+
+func treeDefinitionFiles(directory string) (string, string) {
+	meta := filepath.Join(directory, treeInfoFile)
+	nodes := filepath.Join(directory, treeNodesFile)
+	return meta, nodes
+}
+
+const jsonIndent = "    "
+
+// writeTXTJSON writes TXT records in JSON format.
+func writeTXTJSON(file string, txt map[string]string) error {
+	txtJSON, err := json.MarshalIndent(txt, "", jsonIndent)
+	if err != nil {
+		return err
+	}
+	if file == "-" {
+		os.Stdout.Write(txtJSON)
+		fmt.Println()
+		return nil
+	}
+	return ioutil.WriteFile(file, txtJSON, 0o644)
+}
@@ -0,0 +1,293 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/klaytn/klaytn/networks/p2p/dnsdisc"
+	"gopkg.in/urfave/cli.v1"
+)
+
+const (
+	// Route53 limits change sets to 32k characters and 1000 items.
+	// https://docs.aws.amazon.com/Route53/latest/DeveloperGuide/DNSLimitations.html
+	route53ChangeSizeLimit  = 32000
+	route53ChangeCountLimit = 1000
+	maxRetryLimit           = 60
+
+	// The TTL of the TXT records. The root record has a shorter TTL so that the
+	// clients notice the updates of the tree sooner.
+	rootTTL = 30 * 60
+	treeTTL = 4 * 7 * 24 * 60 * 60
+)
+
+var route53ZoneIDFlag = cli.StringFlag{
+	Name:  "zone-id",
+	Usage: "Route53 Zone ID",
+}
+
+type route53Client struct {
+	api    *route53.Route53
+	zoneID string
+}
+
+type recordSet struct {
+	values []string
+	ttl    int64
+}
+
+// newRoute53Client sets up a Route53 API client from the AWS credentials and
+// region configured in the environment or the shared config files.
+func newRoute53Client(ctx *cli.Context) (*route53Client, error) {
+	zoneID := ctx.String(route53ZoneIDFlag.Name)
+	if zoneID == "" {
+		return nil, errors.New("need --zone-id flag")
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{SharedConfigState: session.SharedConfigEnable})
+	if err != nil {
+		return nil, fmt.Errorf("can't initialize AWS session: %v", err)
+	}
+	return &route53Client{api: route53.New(sess), zoneID: zoneID}, nil
+}
+
+// deploy uploads the given tree to Route53.
+func (c *route53Client) deploy(name string, t *dnsdisc.Tree) error {
+	existing, err := c.collectRecords(name)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Found %d TXT records\n", len(existing))
+
+	records := t.ToTXT(name)
+	changes := makeDeletionChanges(existing, records)
+	changes = append(makeUpsertChanges(existing, name, records), changes...)
+	if len(changes) == 0 {
+		fmt.Println("No DNS changes needed")
+		return nil
+	}
+
+	// Submit all change batches.
+	batches := splitChanges(changes, route53ChangeSizeLimit, route53ChangeCountLimit)
+	for i, changes := range batches {
+		fmt.Printf("Submitting %d changes to Route53 (batch %d/%d)\n", len(changes), i+1, len(batches))
+		out, err := c.api.ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+			HostedZoneId: aws.String(c.zoneID),
+			ChangeBatch: &route53.ChangeBatch{
+				Changes: changes,
+				Comment: aws.String(fmt.Sprintf("kntree update %d/%d of %s at seq %d", i+1, len(batches), name, t.Seq())),
+			},
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Waiting for change request %s\n", aws.StringValue(out.ChangeInfo.Id))
+		if err := c.api.WaitUntilResourceRecordSetsChanged(&route53.GetChangeInput{Id: out.ChangeInfo.Id}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectRecords collects all TXT records below the given name.
+func (c *route53Client) collectRecords(name string) (map[string]recordSet, error) {
+	var (
+		existing = make(map[string]recordSet)
+		suffix   = "." + name
+		retries  = 0
+	)
+	input := &route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(c.zoneID),
+		StartRecordName: aws.String(name),
+		StartRecordType: aws.String(route53.RRTypeTxt),
+		MaxItems:        aws.String("500"),
+	}
+	for {
+		resp, err := c.api.ListResourceRecordSets(input)
+		if err != nil {
+			if retries >= maxRetryLimit {
+				return existing, err
+			}
+			retries++
+			continue
+		}
+		for _, set := range resp.ResourceRecordSets {
+			if aws.StringValue(set.Type) != route53.RRTypeTxt {
+				continue
+			}
+			setName := strings.TrimSuffix(aws.StringValue(set.Name), ".")
+			if setName != name && !strings.HasSuffix(setName, suffix) {
+				continue
+			}
+			s := recordSet{ttl: aws.Int64Value(set.TTL)}
+			for _, rec := range set.ResourceRecords {
+				s.values = append(s.values, aws.StringValue(rec.Value))
+			}
+			existing[setName] = s
+		}
+		if !aws.BoolValue(resp.IsTruncated) {
+			break
+		}
+		// Set the cursor to the next batch. From the AWS docs:
+		//
+		// To display the next page of results, get the values of NextRecordName,
+		// NextRecordType, and NextRecordIdentifier (if any) from the response. Then submit
+		// another ListResourceRecordSets request, and specify those values for
+		// StartRecordName, StartRecordType, and StartRecordIdentifier.
+		input.StartRecordName = resp.NextRecordName
+		input.StartRecordType = resp.NextRecordType
+		input.StartRecordIdentifier = resp.NextRecordIdentifier
+	}
+	return existing, nil
+}
+
+// makeUpsertChanges creates the changes to create or update the records. The
+// root record is updated last, so that the clients never see a root pointing
+// to the records not created yet.
+func makeUpsertChanges(prev map[string]recordSet, name string, records map[string]string) []*route53.Change {
+	var changes []*route53.Change
+	var root *route53.Change
+	for path, newValue := range records {
+		prevRecords, exists := prev[path]
+		prevValue := strings.Join(prevRecords.values, "")
+
+		// prevValue contains quoted strings, encode newValue to compare.
+		newValue = splitTXT(newValue)
+
+		// Assign TTLs.
+		ttl := int64(rootTTL)
+		if path != name {
+			ttl = treeTTL
+		}
+
+		if !exists || prevValue != newValue || prevRecords.ttl != ttl {
+			change := newTXTChange(route53.ChangeActionUpsert, path, ttl, newValue)
+			if path == name {
+				root = change
+			} else {
+				changes = append(changes, change)
+			}
+		}
+	}
+	sortChanges(changes)
+	if root != nil {
+		changes = append(changes, root)
+	}
+	return changes
+}
+
+// makeDeletionChanges creates the changes to delete the records not in the tree anymore.
+func makeDeletionChanges(prev map[string]recordSet, keep map[string]string) []*route53.Change {
+	var changes []*route53.Change
+	for path, set := range prev {
+		if _, ok := keep[path]; ok {
+			continue
+		}
+		changes = append(changes, newTXTChange(route53.ChangeActionDelete, path, set.ttl, set.values...))
+	}
+	sortChanges(changes)
+	return changes
+}
+
+// sortChanges sorts the changes by record name.
+func sortChanges(changes []*route53.Change) {
+	sort.Slice(changes, func(i, j int) bool {
+		return aws.StringValue(changes[i].ResourceRecordSet.Name) < aws.StringValue(changes[j].ResourceRecordSet.Name)
+	})
+}
+
+// splitChanges splits up DNS changes such that each change batch
+// is smaller than the given RDATA limit.
+func splitChanges(changes []*route53.Change, sizeLimit, countLimit int) [][]*route53.Change {
+	var (
+		batches    [][]*route53.Change
+		batchSize  int
+		batchCount int
+	)
+	for _, ch := range changes {
+		// Start new batch if this change pushes the current one over the limit.
+		count := changeCount(ch)
+		size := changeSize(ch) * count
+		overSize := batchSize+size > sizeLimit
+		overCount := batchCount+count > countLimit
+		if len(batches) == 0 || overSize || overCount {
+			batches = append(batches, nil)
+			batchSize = 0
+			batchCount = 0
+		}
+		batches[len(batches)-1] = append(batches[len(batches)-1], ch)
+		batchSize += size
+		batchCount += count
+	}
+	return batches
+}
+
+// changeSize returns the RDATA size of a DNS change.
+func changeSize(ch *route53.Change) int {
+	size := 0
+	for _, rr := range ch.ResourceRecordSet.ResourceRecords {
+		size += len(aws.StringValue(rr.Value))
+	}
+	return size
+}
+
+// changeCount returns the number of items counted against the limit. UPSERT
+// changes count twice.
+func changeCount(ch *route53.Change) int {
+	if aws.StringValue(ch.Action) == route53.ChangeActionUpsert {
+		return 2
+	}
+	return 1
+}
+
+// newTXTChange creates a change to a TXT record.
+func newTXTChange(action, name string, ttl int64, values ...string) *route53.Change {
+	rrs := make([]*route53.ResourceRecord, len(values))
+	for i, v := range values {
+		rrs[i] = &route53.ResourceRecord{Value: aws.String(v)}
+	}
+	return &route53.Change{
+		Action: aws.String(action),
+		ResourceRecordSet: &route53.ResourceRecordSet{
+			Name:            aws.String(name),
+			Type:            aws.String(route53.RRTypeTxt),
+			TTL:             aws.Int64(ttl),
+			ResourceRecords: rrs,
+		},
+	}
+}
+
+// splitTXT splits value into a list of quoted 255-character strings.
+func splitTXT(value string) string {
+	var result strings.Builder
+	for len(value) > 0 {
+		rlen := len(value)
+		if rlen > 253 {
+			rlen = 253
+		}
+		result.WriteString(strconv.Quote(value[:rlen]))
+		value = value[rlen:]
+	}
+	return result.String()
+}
@@ -74,6 +74,18 @@ func init() {
 
 		// See utils/nodecmd/db_migration.go:
 		nodecmd.MigrationCommand,
+
+		// See utils/nodecmd/dbcmd.go:
+		nodecmd.DBCommand,
+
+		// See utils/nodecmd/snapshotcmd.go:
+		nodecmd.SnapshotCommand,
+
+		// See utils/nodecmd/verifycmd.go:
+		nodecmd.GetVerifyCommand(nodeFlags),
+
+		// See utils/nodecmd/debugbundlecmd.go:
+		nodecmd.DebugBundleCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
@@ -89,12 +101,18 @@ func init() {
 	app.CommandNotFound = nodecmd.CommandNotExist
 
 	app.Before = func(ctx *cli.Context) error {
+		if err := utils.SetFlagsFromEnv(ctx); err != nil {
+			return err
+		}
 		runtime.GOMAXPROCS(runtime.NumCPU())
 		logDir := (&node.Config{DataDir: utils.MakeDataDir(ctx)}).ResolvePath("logs")
 		debug.CreateLogDir(logDir)
 		if err := debug.Setup(ctx); err != nil {
 			return err
 		}
+		if err := utils.ApplyNodeProfile(ctx); err != nil {
+			return err
+		}
 		metricutils.StartMetricCollectionAndExport(ctx)
 		utils.SetupNetwork(ctx)
 		return nil
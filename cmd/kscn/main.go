@@ -63,6 +63,9 @@ func init() {
 		nodecmd.GetConsoleCommand(nodeFlags, rpcFlags),
 		nodecmd.AttachCommand,
 
+		// See utils/nodecmd/bridgecmd.go:
+		nodecmd.BridgeCommand,
+
 		// See utils/nodecmd/versioncmd.go:
 		nodecmd.VersionCommand,
 
@@ -0,0 +1,42 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+/*
+ksigner is a remote signer holding the keys of the accounts on a hardened host
+separate from the RPC-exposed node. It serves the API of accounts/remote, and
+signs a request only if it is approved by the rules of a policy file, e.g. the
+allowed destinations, the value caps and the rate limits of each key.
+
+The node delegates the signing to ksigner with the --remotesigner.* flags, so
+the transactions signed by the node or by the console attached to it are
+checked by the rules.
+
+# Options
+
+All available options are as follows.
+
+	--keystore value         Directory of the keystore (default: "keystore")
+	--unlock value           Comma separated addresses of the accounts to serve
+	--password value         Password file of the accounts, one password per line
+	--rules value            JSON policy file of the signing rules
+	--attestation-key value  File of the Ed25519 key attesting the responses, generated if not exists (default: "attestation.key")
+	--addr value             Listening address of the signer (default: "127.0.0.1:8560")
+	--tls.cert value         TLS certificate of the signer
+	--tls.key value          TLS key of the signer
+	--tls.clientca value     CA certificates authenticating the nodes by their client certificates
+	--help, -h               Show help
+*/
+package main
@@ -0,0 +1,235 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/accounts/keystore"
+	"github.com/klaytn/klaytn/accounts/remote"
+	"github.com/klaytn/klaytn/cmd/utils/nodecmd"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/log"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	logger       = log.NewModuleLogger(log.CMDKSIGNER)
+	keystoreFlag = cli.StringFlag{
+		Name:  "keystore",
+		Usage: "Directory of the keystore",
+		Value: "keystore",
+	}
+	unlockFlag = cli.StringFlag{
+		Name:  "unlock",
+		Usage: "Comma separated addresses of the accounts to serve",
+	}
+	passwordFlag = cli.StringFlag{
+		Name:  "password",
+		Usage: "Password file of the accounts, one password per line",
+	}
+	rulesFlag = cli.StringFlag{
+		Name:  "rules",
+		Usage: "JSON policy file of the signing rules",
+	}
+	attestationKeyFlag = cli.StringFlag{
+		Name:  "attestation-key",
+		Usage: "File of the Ed25519 key attesting the responses, generated if not exists",
+		Value: "attestation.key",
+	}
+	addrFlag = cli.StringFlag{
+		Name:  "addr",
+		Usage: "Listening address of the signer",
+		Value: "127.0.0.1:8560",
+	}
+	tlsCertFlag = cli.StringFlag{
+		Name:  "tls.cert",
+		Usage: "TLS certificate of the signer",
+	}
+	tlsKeyFlag = cli.StringFlag{
+		Name:  "tls.key",
+		Usage: "TLS key of the signer",
+	}
+	tlsClientCAFlag = cli.StringFlag{
+		Name:  "tls.clientca",
+		Usage: "CA certificates authenticating the nodes by their client certificates",
+	}
+)
+
+func main() {
+	log.Root().SetHandler(log.LvlFilterHandler(log.LvlInfo, log.StreamHandler(os.Stderr, log.TerminalFormat(true))))
+
+	app := cli.NewApp()
+	app.Name = "ksigner"
+	app.Usage = "The remote signer of Klaytn signing by the rules of a policy file"
+	app.Copyright = "Copyright 2022 The klaytn Authors"
+	app.Action = serve
+	app.Flags = []cli.Flag{
+		keystoreFlag,
+		unlockFlag,
+		passwordFlag,
+		rulesFlag,
+		attestationKeyFlag,
+		addrFlag,
+		tlsCertFlag,
+		tlsKeyFlag,
+		tlsClientCAFlag,
+	}
+	app.Commands = []cli.Command{
+		nodecmd.VersionCommand,
+	}
+	app.HideVersion = true
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// serve serves the remote signer API until the process is terminated.
+func serve(ctx *cli.Context) error {
+	if !ctx.IsSet(rulesFlag.Name) {
+		return errors.New("the rules are required, see --" + rulesFlag.Name)
+	}
+	rules, err := remote.LoadRules(ctx.String(rulesFlag.Name))
+	if err != nil {
+		return err
+	}
+	keys, err := loadKeys(ctx)
+	if err != nil {
+		return err
+	}
+	attestationKey, err := loadAttestationKey(ctx.String(attestationKeyFlag.Name))
+	if err != nil {
+		return err
+	}
+	logger.Info("Loaded the attestation key", "public", hexutil.Encode(attestationKey.Public().(ed25519.PublicKey)))
+
+	srv := &http.Server{
+		Addr:    ctx.String(addrFlag.Name),
+		Handler: remote.NewServer(attestationKey, keys, rules),
+	}
+	if !ctx.IsSet(tlsCertFlag.Name) {
+		logger.Warn("Serving without TLS, which is only safe on a trusted network", "addr", srv.Addr)
+		return srv.ListenAndServe()
+	}
+	srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	if ctx.IsSet(tlsClientCAFlag.Name) {
+		pem, err := ioutil.ReadFile(ctx.String(tlsClientCAFlag.Name))
+		if err != nil {
+			return err
+		}
+		srv.TLSConfig.ClientCAs = x509.NewCertPool()
+		if !srv.TLSConfig.ClientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificate in %s", ctx.String(tlsClientCAFlag.Name))
+		}
+		srv.TLSConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	logger.Info("Serving the remote signer", "addr", srv.Addr, "keys", len(keys))
+	return srv.ListenAndServeTLS(ctx.String(tlsCertFlag.Name), ctx.String(tlsKeyFlag.Name))
+}
+
+// loadKeys decrypts the keys of the accounts to serve with their passwords.
+// If there are less passwords than the accounts, the last password is used
+// for the rest.
+func loadKeys(ctx *cli.Context) ([]*ecdsa.PrivateKey, error) {
+	if !ctx.IsSet(unlockFlag.Name) || !ctx.IsSet(passwordFlag.Name) {
+		return nil, fmt.Errorf("the accounts and their passwords are required, see --%s and --%s", unlockFlag.Name, passwordFlag.Name)
+	}
+	text, err := ioutil.ReadFile(ctx.String(passwordFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	passwords := strings.Split(strings.TrimRight(string(text), "\r\n"), "\n")
+	for i := range passwords {
+		passwords[i] = strings.TrimRight(passwords[i], "\r")
+	}
+
+	ks := keystore.NewKeyStore(ctx.String(keystoreFlag.Name), keystore.StandardScryptN, keystore.StandardScryptP)
+	var keys []*ecdsa.PrivateKey
+	for i, addr := range strings.Split(ctx.String(unlockFlag.Name), ",") {
+		addr = strings.TrimSpace(addr)
+		if !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("invalid account address %q", addr)
+		}
+		account, err := ks.Find(accounts.Account{Address: common.HexToAddress(addr)})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", addr, err)
+		}
+		keyJSON, err := ioutil.ReadFile(account.URL.Path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := keystore.DecryptKey(keyJSON, passwords[min(i, len(passwords)-1)])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", addr, err)
+		}
+		// The remote signer identifies an account by the address of its key,
+		// so the accounts decoupled from their keys are not served.
+		privateKey := key.GetPrivateKey()
+		if crypto.PubkeyToAddress(privateKey.PublicKey) != account.Address {
+			return nil, fmt.Errorf("%s: the account is decoupled from its key", addr)
+		}
+		keys = append(keys, privateKey)
+		logger.Info("Loaded an account", "address", account.Address)
+	}
+	return keys, nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// loadAttestationKey loads the hex encoded seed of the Ed25519 attestation
+// key from the file, or generates it if the file does not exist.
+func loadAttestationKey(file string) (ed25519.PrivateKey, error) {
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		if err := ioutil.WriteFile(file, []byte(hexutil.Encode(key.Seed())), 0600); err != nil {
+			return nil, err
+		}
+		logger.Info("Generated the attestation key", "file", file)
+		return key, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	seed, err := hexutil.Decode(strings.TrimSpace(string(data)))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid attestation key in %s", file)
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
@@ -85,12 +85,18 @@ func init() {
 	app.CommandNotFound = nodecmd.CommandNotExist
 
 	app.Before = func(ctx *cli.Context) error {
+		if err := utils.SetFlagsFromEnv(ctx); err != nil {
+			return err
+		}
 		runtime.GOMAXPROCS(runtime.NumCPU())
 		logDir := (&node.Config{DataDir: utils.MakeDataDir(ctx)}).ResolvePath("logs")
 		debug.CreateLogDir(logDir)
 		if err := debug.Setup(ctx); err != nil {
 			return err
 		}
+		if err := utils.ApplyNodeProfile(ctx); err != nil {
+			return err
+		}
 		metricutils.StartMetricCollectionAndExport(ctx)
 		utils.SetupNetwork(ctx)
 		return nil
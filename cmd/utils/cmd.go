@@ -46,12 +46,19 @@ func StartNode(stack *node.Node) {
 	if err := stack.Start(); err != nil {
 		log.Fatalf("Error starting protocol stack: %v", err)
 	}
+	// The databases are opened, and the p2p and RPC servers are listening.
+	notifySystemd(sdNotifyReady)
+	watchdogQuit := make(chan struct{})
+	startSystemdWatchdog(stack, watchdogQuit)
+
 	go func() {
 		sigc := make(chan os.Signal, 1)
 		signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
 		defer signal.Stop(sigc)
 		<-sigc
 		logger.Info("Got interrupt, shutting down...")
+		close(watchdogQuit)
+		notifySystemd(sdNotifyStopping)
 		go stack.Stop()
 		for i := 10; i > 0; i-- {
 			<-sigc
@@ -60,6 +67,20 @@ func StartNode(stack *node.Node) {
 			}
 		}
 	}()
+	go func() {
+		sighup := make(chan os.Signal, 1)
+		signal.Notify(sighup, syscall.SIGHUP)
+		for range sighup {
+			notifySystemd(sdNotifyReloading)
+			result, err := stack.ReloadConfig()
+			notifySystemd(sdNotifyReady)
+			if err != nil {
+				logger.Error("Failed to reload the configuration", "err", err)
+				continue
+			}
+			logger.Info("Reloaded the configuration", "applied", result.Applied, "restartRequired", result.RestartRequired)
+		}
+	}()
 }
 
 func ImportChain(chain *blockchain.BlockChain, fn string) error {
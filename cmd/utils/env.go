@@ -0,0 +1,74 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+// EnvVarPrefix is the prefix of the environment variables of the flags.
+const EnvVarPrefix = "KLAYTN_"
+
+var envVarReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// EnvVarName returns the environment variable of the flag, e.g. KLAYTN_RPCAPI
+// for --rpcapi and KLAYTN_DB_LEVELDB_CACHE_SIZE for --db.leveldb.cache-size.
+func EnvVarName(flagName string) string {
+	name := strings.TrimSpace(strings.Split(flagName, ",")[0])
+	return EnvVarPrefix + strings.ToUpper(envVarReplacer.Replace(name))
+}
+
+// SetFlagsFromEnv sets the flags of the context which are not given on the
+// command line from their environment variables. It is called before the
+// configuration file is loaded, so a flag on the command line has precedence
+// over its environment variable, which has precedence over the configuration
+// file. The values of the slice flags are separated by commas.
+func SetFlagsFromEnv(ctx *cli.Context) error {
+	flags, global := ctx.Command.Flags, false
+	if ctx.Command.Name == "" && ctx.App != nil {
+		flags, global = ctx.App.Flags, true
+	}
+	for _, flag := range flags {
+		name := strings.TrimSpace(strings.Split(flag.GetName(), ",")[0])
+		if name == "help" || name == "version" || ctx.IsSet(name) {
+			continue
+		}
+		// A command flag given globally on the command line is migrated later.
+		if !global && ctx.GlobalIsSet(name) {
+			continue
+		}
+		value, ok := os.LookupEnv(EnvVarName(name))
+		if !ok {
+			continue
+		}
+		values := []string{value}
+		switch flag.(type) {
+		case cli.StringSliceFlag, cli.IntSliceFlag, cli.Int64SliceFlag:
+			values = strings.Split(value, ",")
+		}
+		for _, v := range values {
+			if err := ctx.Set(name, strings.TrimSpace(v)); err != nil {
+				return fmt.Errorf("invalid value %q of %s for --%s: %v", value, EnvVarName(name), name, err)
+			}
+		}
+	}
+	return nil
+}
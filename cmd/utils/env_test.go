@@ -0,0 +1,80 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func TestEnvVarName(t *testing.T) {
+	assert.Equal(t, "KLAYTN_RPCAPI", EnvVarName("rpcapi"))
+	assert.Equal(t, "KLAYTN_DB_LEVELDB_CACHE_SIZE", EnvVarName("db.leveldb.cache-size"))
+	assert.Equal(t, "KLAYTN_OUTPUT", EnvVarName("output, o"))
+}
+
+// TestSetFlagsFromEnv checks that the environment variables set the flags not
+// given on the command line.
+func TestSetFlagsFromEnv(t *testing.T) {
+	env := map[string]string{
+		"KLAYTN_TEST_STRING": "env",
+		"KLAYTN_TEST_INT":    "7",
+		"KLAYTN_TEST_BOOL":   "true",
+		"KLAYTN_TEST_SLICE":  "a,b",
+	}
+	for k, v := range env {
+		os.Setenv(k, v)
+		defer os.Unsetenv(k)
+	}
+
+	var (
+		str   string
+		num   int
+		flag  bool
+		slice []string
+	)
+	app := cli.NewApp()
+	app.Flags = []cli.Flag{
+		cli.StringFlag{Name: "test.string"},
+		cli.IntFlag{Name: "test-int"},
+		cli.BoolFlag{Name: "test.bool"},
+		cli.StringSliceFlag{Name: "test.slice"},
+	}
+	app.Before = SetFlagsFromEnv
+	app.Action = func(ctx *cli.Context) error {
+		str, num, flag = ctx.GlobalString("test.string"), ctx.GlobalInt("test-int"), ctx.GlobalBool("test.bool")
+		slice = ctx.GlobalStringSlice("test.slice")
+		assert.True(t, ctx.GlobalIsSet("test.string"))
+		return nil
+	}
+
+	assert.NoError(t, app.Run([]string{"app"}))
+	assert.Equal(t, "env", str)
+	assert.Equal(t, 7, num)
+	assert.True(t, flag)
+	assert.Equal(t, []string{"a", "b"}, slice)
+
+	// The command line has precedence.
+	assert.NoError(t, app.Run([]string{"app", "--test.string", "flag"}))
+	assert.Equal(t, "flag", str)
+
+	os.Setenv("KLAYTN_TEST_INT", "seven")
+	assert.Error(t, app.Run([]string{"app"}))
+}
@@ -42,8 +42,19 @@ var FlagGroups = []FlagGroup{
 			KeyStoreDirFlag,
 			IdentityFlag,
 			SyncModeFlag,
+			SyncPivotDistanceFlag,
+			SyncPivotPeersFlag,
+			SyncCheckpointsFlag,
+			SyncFromFlag,
 			GCModeFlag,
 			LightKDFFlag,
+			KeyStoreKDFFlag,
+			KeyStoreScryptNFlag,
+			KeyStoreScryptRFlag,
+			KeyStoreScryptPFlag,
+			KeyStoreArgon2TimeFlag,
+			KeyStoreArgon2MemoryFlag,
+			KeyStoreArgon2ThreadsFlag,
 			SrvTypeFlag,
 			ExtraDataFlag,
 			ConfigFileFlag,
@@ -59,6 +70,29 @@ var FlagGroups = []FlagGroup{
 		Flags: []cli.Flag{
 			UnlockedAccountFlag,
 			PasswordFileFlag,
+			KMSAWSRegionFlag,
+			KMSAWSEndpointFlag,
+			KMSAWSAliasPrefixFlag,
+			KMSGCPKeyRingFlag,
+			KMSConsensusKeyFlag,
+			VaultAddrFlag,
+			VaultMountFlag,
+			VaultPathFlag,
+			VaultTokenFileFlag,
+			VaultAppRoleMountFlag,
+			VaultAppRoleIDFlag,
+			VaultAppRoleSecretIDFileFlag,
+			RemoteSignerURLFlag,
+			RemoteSignerAttestationKeyFlag,
+			RemoteSignerCAFileFlag,
+			RemoteSignerCertFileFlag,
+			RemoteSignerKeyFileFlag,
+			RemoteSignerConsensusKeyFlag,
+			PKCS11ModuleFlag,
+			PKCS11SlotFlag,
+			PKCS11TokenLabelFlag,
+			PKCS11PINFileFlag,
+			PKCS11ConsensusKeyFlag,
 		},
 	},
 	{
@@ -89,14 +123,27 @@ var FlagGroups = []FlagGroup{
 			SingleDBFlag,
 			NumStateTrieShardsFlag,
 			LevelDBCompressionTypeFlag,
+			DBCompressionFlag,
+			DBEncryptionKeyFlag,
 			LevelDBNoBufferPoolFlag,
 			DynamoDBTableNameFlag,
 			DynamoDBRegionFlag,
 			DynamoDBIsProvisionedFlag,
 			DynamoDBReadCapacityFlag,
 			DynamoDBWriteCapacityFlag,
+			DynamoDBWriteWorkersFlag,
+			ColdStorageBucketFlag,
+			ColdStorageRegionFlag,
+			ColdStorageEndpointFlag,
+			ColdStorageMinAgeFlag,
+			ColdStorageMinSizeFlag,
+			ColdStorageCacheSizeFlag,
+			AncientSourceURLFlag,
+			AncientSourceUntilFlag,
+			AncientSourceCacheSizeFlag,
 			NoParallelDBWriteFlag,
 			SenderTxHashIndexingFlag,
+			SkipIntegrityCheckFlag,
 			DBNoPerformanceMetricsFlag,
 		},
 	},
@@ -131,6 +178,9 @@ var FlagGroups = []FlagGroup{
 			ChainDataFetcherNumHandlers,
 			ChainDataFetcherJobChannelSize,
 			ChainDataFetcherChainEventSizeFlag,
+			ChainDataFetcherFilterRulesFlag,
+			ChainDataFetcherBackpressureFlag,
+			ChainDataFetcherDeadLetterFlag,
 			ChainDataFetcherKASDBHostFlag,
 			ChainDataFetcherKASDBPortFlag,
 			ChainDataFetcherKASDBNameFlag,
@@ -150,6 +200,29 @@ var FlagGroups = []FlagGroup{
 			ChainDataFetcherKafkaRequiredAcksFlag,
 			ChainDataFetcherKafkaMessageVersionFlag,
 			ChainDataFetcherKafkaProducerIdFlag,
+			ChainDataFetcherKafkaMsgEncodingFlag,
+			ChainDataFetcherKafkaSchemaRegistryFlag,
+			ChainDataFetcherKafkaMaxMessagesPerSecondFlag,
+			ChainDataFetcherPostgresDBHostFlag,
+			ChainDataFetcherPostgresDBPortFlag,
+			ChainDataFetcherPostgresDBNameFlag,
+			ChainDataFetcherPostgresDBUserFlag,
+			ChainDataFetcherPostgresDBPasswordFlag,
+			ChainDataFetcherPostgresSSLModeFlag,
+			ChainDataFetcherPostgresSchemaFlag,
+			ChainDataFetcherPostgresTablePrefixFlag,
+			ChainDataFetcherPostgresTablesFlag,
+			ChainDataFetcherPostgresBatchSizeFlag,
+			ChainDataFetcherElasticsearchURLsFlag,
+			ChainDataFetcherElasticsearchUserFlag,
+			ChainDataFetcherElasticsearchPasswordFlag,
+			ChainDataFetcherElasticsearchIndexPrefixFlag,
+			ChainDataFetcherElasticsearchIndexDateLayoutFlag,
+			ChainDataFetcherElasticsearchTemplateFileFlag,
+			ChainDataFetcherElasticsearchILMPolicyFlag,
+			ChainDataFetcherElasticsearchIndicesFlag,
+			ChainDataFetcherElasticsearchABIFilesFlag,
+			ChainDataFetcherElasticsearchBulkSizeFlag,
 		},
 	},
 	{
@@ -159,12 +232,51 @@ var FlagGroups = []FlagGroup{
 			DstDataDirFlag,
 			DstSingleDBFlag,
 			DstLevelDBCompressionTypeFlag,
+			DstDBCompressionFlag,
+			DstDBEncryptionKeyFlag,
 			DstNumStateTrieShardsFlag,
 			DstDynamoDBTableNameFlag,
 			DstDynamoDBRegionFlag,
 			DstDynamoDBIsProvisionedFlag,
 			DstDynamoDBReadCapacityFlag,
 			DstDynamoDBWriteCapacityFlag,
+			DBMigrationWorkersFlag,
+			DBMigrationMaxRateFlag,
+			DBMigrationVerifyFlag,
+		},
+	},
+	{
+		Name: "DATABASE VERIFICATION",
+		Flags: []cli.Flag{
+			DBVerifyFromFlag,
+			DBVerifyToFlag,
+			DBVerifyRepairFlag,
+			VerifyBlocksFromFlag,
+			VerifyBlocksToFlag,
+		},
+	},
+	{
+		Name: "DATABASE RESHARDING",
+		Flags: []cli.Flag{
+			DBReshardShardsFlag,
+		},
+	},
+	{
+		Name: "DATABASE INSPECTION",
+		Flags: []cli.Flag{
+			DBUnsafeFlag,
+			DBIterateLimitFlag,
+		},
+	},
+	{
+		Name: "BRIDGE RECONCILIATION",
+		Flags: []cli.Flag{
+			BridgeReconcileEndpointFlag,
+			BridgeReconcileChildFromFlag,
+			BridgeReconcileChildToFlag,
+			BridgeReconcileParentFromFlag,
+			BridgeReconcileParentToFlag,
+			BridgeReconcileResubmitFlag,
 		},
 	},
 	{
@@ -173,6 +285,7 @@ var FlagGroups = []FlagGroup{
 			TrieMemoryCacheSizeFlag,
 			TrieBlockIntervalFlag,
 			TriesInMemoryFlag,
+			TrieNodeRefCountFlag,
 		},
 	},
 	{
@@ -182,6 +295,7 @@ var FlagGroups = []FlagGroup{
 			CacheScaleFlag,
 			CacheUsageLevelFlag,
 			MemorySizeFlag,
+			NodeProfileFlag,
 			TrieNodeCacheTypeFlag,
 			NumFetcherPrefetchWorkerFlag,
 			UseSnapshotForPrefetchFlag,
@@ -198,6 +312,7 @@ var FlagGroups = []FlagGroup{
 		Flags: []cli.Flag{
 			ServiceChainSignerFlag,
 			RewardbaseFlag,
+			IstanbulHeaderOnlyVerificationFlag,
 		},
 	},
 	{
@@ -209,12 +324,33 @@ var FlagGroups = []FlagGroup{
 			MultiChannelUseFlag,
 			MaxConnectionsFlag,
 			MaxPendingPeersFlag,
+			MaxInboundPerIPFlag,
+			MaxInboundPerSubnetFlag,
+			MaxPeerEgressBytesPerSecFlag,
+			MaxEgressBytesPerSecFlag,
+			CompressionThresholdFlag,
 			TargetGasLimitFlag,
 			NATFlag,
+			IPPolicyFlag,
+			ExtIPv6Flag,
+			P2PTLSProfileFlag,
+			P2PTLSCertFlag,
+			P2PTLSKeyFlag,
+			P2PTLSCAFlag,
+			P2PTLSAllowedFlag,
 			NoDiscoverFlag,
+			DiscoveryTopicsFlag,
+			DNSDiscoveryFlag,
+			SentryNodesFlag,
+			SentryRelayFlag,
+			ProxyUpstreamsFlag,
 			RWTimerWaitTimeFlag,
 			RWTimerIntervalFlag,
 			NetrestrictFlag,
+			PeerListURLFlag,
+			PeerListSignerFlag,
+			PeerListKeyFileFlag,
+			PeerListRefreshFlag,
 			NodeKeyFileFlag,
 			NodeKeyHexFlag,
 			NetworkIdFlag,
@@ -230,6 +366,25 @@ var FlagGroups = []FlagGroup{
 			PrometheusExporterPortFlag,
 		},
 	},
+	{
+		Name: "HEALTH CHECK",
+		Flags: []cli.Flag{
+			HealthEnabledFlag,
+			HealthListenAddrFlag,
+			HealthPortFlag,
+			HealthMinPeersFlag,
+			HealthMaxBlockAgeFlag,
+			HealthAllowSyncingFlag,
+		},
+	},
+	{
+		Name: "MULTISIG",
+		Flags: []cli.Flag{
+			MultisigEnabledFlag,
+			MultisigLifetimeFlag,
+			MultisigMaxProposalsFlag,
+		},
+	},
 	{
 		Name: "VIRTUAL MACHINE",
 		Flags: []cli.Flag{
@@ -264,6 +419,7 @@ var FlagGroups = []FlagGroup{
 			JSpathFlag,
 			ExecFlag,
 			PreloadJSFlag,
+			DebugBundleCPUProfileFlag,
 			MaxRequestContentLengthFlag,
 			APIFilterGetLogsDeadlineFlag,
 			APIFilterGetLogsMaxItemsFlag,
@@ -286,10 +442,28 @@ var FlagGroups = []FlagGroup{
 			ParentChainIDFlag,
 			VTRecoveryFlag,
 			VTRecoveryIntervalFlag,
+			VTRecoveryRetryBackoffFlag,
+			VTRecoveryRetryMaxBackoffFlag,
+			VTRecoveryMaxAttemptsFlag,
 			ServiceChainAnchoringFlag,
 			ServiceChainNewAccountFlag,
 			ServiceChainParentOperatorTxGasLimitFlag,
 			ServiceChainChildOperatorTxGasLimitFlag,
+			ServiceChainAnchoringGasPriceStrategyFlag,
+			ServiceChainAnchoringGasPriceFlag,
+			ServiceChainAnchoringGasPricePremiumFlag,
+			ServiceChainAnchoringGasPriceCapFlag,
+			ServiceChainAnchoringRetryGasBumpFlag,
+			ServiceChainAnchoringRetryBackoffFlag,
+			ServiceChainAnchoringRetryMaxBackoffFlag,
+			ServiceChainAnchoringMaxRetriesFlag,
+			ServiceChainAnchoringNonceRepairFlag,
+			ServiceChainAnchoringBatchFlag,
+			ServiceChainAnchoringBatchMaxBlocksFlag,
+			ServiceChainParentHealthCheckIntervalFlag,
+			ServiceChainParentHealthTimeoutFlag,
+			ServiceChainParentQuorumFlag,
+			ServiceChainFeeDelegationPolicyFlag,
 			KASServiceChainAnchorFlag,
 			KASServiceChainAnchorPeriodFlag,
 			KASServiceChainAnchorUrlFlag,
@@ -308,6 +482,7 @@ var FlagGroups = []FlagGroup{
 			AutoRestartFlag,
 			RestartTimeOutFlag,
 			DaemonPathFlag,
+			ShutdownGracePeriodFlag,
 			KESNodeTypeServiceFlag,
 			SnapshotFlag,
 			SnapshotCacheSizeFlag,
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math/big"
+	"net"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -33,13 +34,19 @@ import (
 
 	"github.com/klaytn/klaytn/accounts"
 	"github.com/klaytn/klaytn/accounts/keystore"
+	"github.com/klaytn/klaytn/accounts/kms"
+	"github.com/klaytn/klaytn/accounts/pkcs11"
+	"github.com/klaytn/klaytn/accounts/remote"
+	"github.com/klaytn/klaytn/accounts/vault"
 	"github.com/klaytn/klaytn/api/debug"
 	"github.com/klaytn/klaytn/blockchain"
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/common/fdlimit"
 	"github.com/klaytn/klaytn/crypto"
 	"github.com/klaytn/klaytn/datasync/chaindatafetcher"
+	"github.com/klaytn/klaytn/datasync/chaindatafetcher/elasticsearch"
 	"github.com/klaytn/klaytn/datasync/chaindatafetcher/kafka"
+	"github.com/klaytn/klaytn/datasync/chaindatafetcher/postgres"
 	"github.com/klaytn/klaytn/datasync/dbsyncer"
 	"github.com/klaytn/klaytn/datasync/downloader"
 	"github.com/klaytn/klaytn/log"
@@ -126,6 +133,24 @@ var (
 		Usage: `Blockchain sync mode ("full" or "snap")`,
 		Value: &defaultSyncMode,
 	}
+	SyncPivotDistanceFlag = cli.Uint64Flag{
+		Name:  "sync.pivot.distance",
+		Usage: "Number of blocks below the remote head to select the snap sync pivot at",
+		Value: downloader.DefaultPivotDistance,
+	}
+	SyncPivotPeersFlag = cli.IntFlag{
+		Name:  "sync.pivot.peers",
+		Usage: "Number of peers which must agree on the snap sync pivot header",
+		Value: 1,
+	}
+	SyncCheckpointsFlag = cli.StringFlag{
+		Name:  "sync.checkpoints",
+		Usage: `JSON file of trusted checkpoints which the synced chain must match (e.g. [{"number": 1000, "hash": "0x..."}])`,
+	}
+	SyncFromFlag = cli.Uint64Flag{
+		Name:  "sync.from",
+		Usage: "Trusted checkpoint block from which the full sync executes the blocks, downloading the state of the checkpoint (requires --syncmode full and --sync.checkpoints)",
+	}
 	GCModeFlag = cli.StringFlag{
 		Name:  "gcmode",
 		Usage: `Blockchain garbage collection mode ("full", "archive")`,
@@ -135,6 +160,137 @@ var (
 		Name:  "lightkdf",
 		Usage: "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
 	}
+	KeyStoreKDFFlag = cli.StringFlag{
+		Name:  "keystore.kdf",
+		Usage: "KDF encrypting the new keys of the key store (scrypt, argon2id). The files with argon2id can only be read by the tools supporting it",
+		Value: keystore.KDFScrypt,
+	}
+	KeyStoreScryptNFlag = cli.IntFlag{
+		Name:  "keystore.scrypt.n",
+		Usage: "scrypt N (CPU/memory cost, a power of two) of the key store",
+		Value: keystore.StandardScryptN,
+	}
+	KeyStoreScryptRFlag = cli.IntFlag{
+		Name:  "keystore.scrypt.r",
+		Usage: "scrypt r (block size) of the key store",
+		Value: 8,
+	}
+	KeyStoreScryptPFlag = cli.IntFlag{
+		Name:  "keystore.scrypt.p",
+		Usage: "scrypt p (parallelization) of the key store",
+		Value: keystore.StandardScryptP,
+	}
+	KeyStoreArgon2TimeFlag = cli.UintFlag{
+		Name:  "keystore.argon2.time",
+		Usage: "Argon2id iterations of the key store",
+		Value: keystore.DefaultArgon2Time,
+	}
+	KeyStoreArgon2MemoryFlag = cli.UintFlag{
+		Name:  "keystore.argon2.memory",
+		Usage: "Argon2id memory of the key store in KiB",
+		Value: keystore.DefaultArgon2Memory,
+	}
+	KeyStoreArgon2ThreadsFlag = cli.UintFlag{
+		Name:  "keystore.argon2.threads",
+		Usage: "Argon2id threads of the key store",
+		Value: keystore.DefaultArgon2Threads,
+	}
+	KMSAWSRegionFlag = cli.StringFlag{
+		Name:  "kms.aws.region",
+		Usage: "AWS region of the AWS KMS keys to use as the accounts",
+	}
+	KMSAWSEndpointFlag = cli.StringFlag{
+		Name:  "kms.aws.endpoint",
+		Usage: "AWS KMS endpoint, if not the default of the region",
+	}
+	KMSAWSAliasPrefixFlag = cli.StringFlag{
+		Name:  "kms.aws.alias-prefix",
+		Usage: "Prefix of the aliases of the AWS KMS keys to use as the accounts",
+		Value: kms.DefaultAWSAliasPrefix,
+	}
+	KMSGCPKeyRingFlag = cli.StringFlag{
+		Name:  "kms.gcp.keyring",
+		Usage: "Google Cloud KMS key ring of the keys to use as the accounts (projects/<project>/locations/<location>/keyRings/<ring>)",
+	}
+	KMSConsensusKeyFlag = cli.StringFlag{
+		Name:  "kms.consensus-key",
+		Usage: "URL of the KMS key signing the consensus messages as the validator, e.g. kms://aws/klaytn/validator",
+	}
+	VaultAddrFlag = cli.StringFlag{
+		Name:  "vault.addr",
+		Usage: "Address of HashiCorp Vault keeping the keystores of the accounts",
+	}
+	VaultMountFlag = cli.StringFlag{
+		Name:  "vault.mount",
+		Usage: "Mount of the Vault KV version 2 secrets engine keeping the keystores",
+		Value: vault.DefaultMount,
+	}
+	VaultPathFlag = cli.StringFlag{
+		Name:  "vault.path",
+		Usage: "Path of the keystores in the Vault secrets engine",
+		Value: vault.DefaultPath,
+	}
+	VaultTokenFileFlag = cli.StringFlag{
+		Name:  "vault.token-file",
+		Usage: "File of the Vault token (VAULT_TOKEN is used if neither this nor an AppRole is given)",
+	}
+	VaultAppRoleMountFlag = cli.StringFlag{
+		Name:  "vault.approle.mount",
+		Usage: "Mount of the Vault AppRole auth method",
+		Value: vault.DefaultAppRoleMount,
+	}
+	VaultAppRoleIDFlag = cli.StringFlag{
+		Name:  "vault.approle.role-id",
+		Usage: "Role ID of the Vault AppRole to log in with",
+	}
+	VaultAppRoleSecretIDFileFlag = cli.StringFlag{
+		Name:  "vault.approle.secret-id-file",
+		Usage: "File of the secret ID of the Vault AppRole",
+	}
+	RemoteSignerURLFlag = cli.StringFlag{
+		Name:  "remotesigner.url",
+		Usage: "Base URL of the remote signer, e.g. an MPC signing service, holding the keys of the accounts",
+	}
+	RemoteSignerAttestationKeyFlag = cli.StringFlag{
+		Name:  "remotesigner.attestation-key",
+		Usage: "Hex encoded Ed25519 public key attesting the responses of the remote signer",
+	}
+	RemoteSignerCAFileFlag = cli.StringFlag{
+		Name:  "remotesigner.ca",
+		Usage: "CA certificates of the remote signer, if not the system ones",
+	}
+	RemoteSignerCertFileFlag = cli.StringFlag{
+		Name:  "remotesigner.cert",
+		Usage: "TLS client certificate authenticating the node to the remote signer",
+	}
+	RemoteSignerKeyFileFlag = cli.StringFlag{
+		Name:  "remotesigner.key",
+		Usage: "TLS client key authenticating the node to the remote signer",
+	}
+	RemoteSignerConsensusKeyFlag = cli.StringFlag{
+		Name:  "remotesigner.consensus-key",
+		Usage: "ID of the remote signer key signing the consensus messages as the validator",
+	}
+	PKCS11ModuleFlag = cli.StringFlag{
+		Name:  "pkcs11.module",
+		Usage: "PKCS#11 module of the HSM holding the keys of the accounts, e.g. /usr/lib/softhsm/libsofthsm2.so",
+	}
+	PKCS11SlotFlag = cli.UintFlag{
+		Name:  "pkcs11.slot",
+		Usage: "Slot ID of the PKCS#11 token, if its label is not given",
+	}
+	PKCS11TokenLabelFlag = cli.StringFlag{
+		Name:  "pkcs11.token-label",
+		Usage: "Label of the PKCS#11 token",
+	}
+	PKCS11PINFileFlag = cli.StringFlag{
+		Name:  "pkcs11.pin-file",
+		Usage: "File of the PIN of the user of the PKCS#11 token",
+	}
+	PKCS11ConsensusKeyFlag = cli.StringFlag{
+		Name:  "pkcs11.consensus-key",
+		Usage: "Label of the PKCS#11 key signing the consensus messages as the validator",
+	}
 	OverwriteGenesisFlag = cli.BoolFlag{
 		Name:  "overwrite-genesis",
 		Usage: "Overwrites genesis block with the given new genesis block for testing purpose",
@@ -236,6 +392,14 @@ var (
 		Usage: "Determines the compression method for LevelDB. 0=AllNoCompression, 1=ReceiptOnlySnappyCompression, 2=StateTrieOnlyNoCompression, 3=AllSnappyCompression",
 		Value: 0,
 	}
+	DBCompressionFlag = cli.StringFlag{
+		Name:  "db.compression",
+		Usage: "Compression of each database overriding db.leveldb.compression, as <database>=<codec>[:<level>],... (e.g. body=zstd:9,receipts=zstd,statetrie=snappy). Codecs: none, snappy, zstd. Databases: header, body, receipts, statetrie, txlookup, bridgeservice, snapshot",
+	}
+	DBEncryptionKeyFlag = cli.StringFlag{
+		Name:  "db.encryption.key",
+		Usage: "Source of the key encrypting the values of the databases with AES-256-GCM, as file:<path>, env:<name> (hex encoded key) or kms:<path> (key encrypted by AWS KMS)",
+	}
 	LevelDBNoBufferPoolFlag = cli.BoolFlag{
 		Name:  "db.leveldb.no-buffer-pool",
 		Usage: "Disables using buffer pool for LevelDB's block allocation",
@@ -267,6 +431,52 @@ var (
 		Name:  "db.dynamo.read-only",
 		Usage: "Disables write to DynamoDB. Only read is possible.",
 	}
+	ColdStorageBucketFlag = cli.StringFlag{
+		Name:  "db.cold.bucket",
+		Usage: "Enables the cold storage of the old block bodies and receipts with the given bucket name prefix.",
+	}
+	ColdStorageRegionFlag = cli.StringFlag{
+		Name:  "db.cold.region",
+		Usage: "Region of the cold storage bucket.",
+		Value: database.GetDefaultColdStorageConfig().Region,
+	}
+	ColdStorageEndpointFlag = cli.StringFlag{
+		Name:  "db.cold.endpoint",
+		Usage: "S3 compatible endpoint of the cold storage, e.g. https://storage.googleapis.com for GCS. Empty for AWS S3.",
+	}
+	ColdStorageMinAgeFlag = cli.Uint64Flag{
+		Name:  "db.cold.min-age",
+		Usage: "Number of blocks behind the head after which block bodies and receipts are moved to the cold storage.",
+		Value: database.GetDefaultColdStorageConfig().MinAge,
+	}
+	ColdStorageMinSizeFlag = cli.IntFlag{
+		Name:  "db.cold.min-size",
+		Usage: "Minimum size in bytes of the data moved to the cold storage. Smaller data stays in the local storage.",
+		Value: database.GetDefaultColdStorageConfig().MinSize,
+	}
+	ColdStorageCacheSizeFlag = cli.IntFlag{
+		Name:  "db.cold.cache",
+		Usage: "Number of the data read from the cold storage cached in memory.",
+		Value: database.GetDefaultColdStorageConfig().CacheSize,
+	}
+	AncientSourceURLFlag = cli.StringFlag{
+		Name:  "db.ancient.url",
+		Usage: "URL of the read-only source of the ancient blocks and receipts, such as an S3 bucket over HTTP. \"{db}\" in the URL is replaced by the database name.",
+	}
+	AncientSourceUntilFlag = cli.Uint64Flag{
+		Name:  "db.ancient.until",
+		Usage: "Block number before which the blocks are read from the ancient data source.",
+	}
+	AncientSourceCacheSizeFlag = cli.IntFlag{
+		Name:  "db.ancient.cache",
+		Usage: "Number of the data read from the ancient data source cached in memory.",
+		Value: database.GetDefaultAncientSourceConfig().CacheSize,
+	}
+	DynamoDBWriteWorkersFlag = cli.IntFlag{
+		Name:  "db.dynamo.write-workers",
+		Usage: "Number of parallel batch write requests to DynamoDB",
+		Value: database.GetDefaultDynamoDBConfig().WriteWorkers,
+	}
 	NoParallelDBWriteFlag = cli.BoolFlag{
 		Name:  "db.no-parallel-write",
 		Usage: "Disables parallel writes of block data to persistent database",
@@ -284,6 +494,11 @@ var (
 		Usage: "Size of in-memory cache of the state snapshot cache (in MiB)",
 		Value: 512,
 	}
+	StatePruneBloomSizeFlag = cli.Uint64Flag{
+		Name:  "bloomfilter.size",
+		Usage: "Size of the bloom filter of the state kept by snapshot prune-state (in MiB)",
+		Value: 2048,
+	}
 	TrieMemoryCacheSizeFlag = cli.IntFlag{
 		Name:  "state.cache-size",
 		Usage: "Size of in-memory cache of the global state (in MiB) to flush matured singleton trie nodes to disk",
@@ -299,6 +514,10 @@ var (
 		Usage: "The number of recent state tries residing in the memory",
 		Value: blockchain.DefaultTriesInMemory,
 	}
+	TrieNodeRefCountFlag = cli.BoolFlag{
+		Name:  "state.trie-refcount",
+		Usage: "Enables reference counting of trie nodes to prune the state of old blocks (archive mode only)",
+	}
 	CacheTypeFlag = cli.IntFlag{
 		Name:  "cache.type",
 		Usage: "Cache Type: 0=LRUCache, 1=LRUShardCache, 2=FIFOCache",
@@ -357,6 +576,10 @@ var (
 		Usage: "Period of saving in memory trie cache to file if fastcache is used, 0 means disabled",
 		Value: 0,
 	}
+	SkipIntegrityCheckFlag = cli.BoolFlag{
+		Name:  "db.skip-integrity-check",
+		Usage: "Start without the integrity check of the chain data (not recommended)",
+	}
 	SenderTxHashIndexingFlag = cli.BoolFlag{
 		Name:  "sendertxhashindexing",
 		Usage: "Enables storing mapping information of senderTxHash to txHash",
@@ -380,6 +603,10 @@ var (
 		Usage: "Public address for block consensus rewards (default = first account created)",
 		Value: "0",
 	}
+	IstanbulHeaderOnlyVerificationFlag = cli.BoolFlag{
+		Name:  "istanbul.header-only-verification",
+		Usage: "Verify block headers against the council of the nearest known snapshot if the headers record the same council, without reconstructing the council state (non-validators only)",
+	}
 	ExtraDataFlag = cli.StringFlag{
 		Name:  "extradata",
 		Usage: "Block extra data set by the work (default = client version)",
@@ -411,6 +638,29 @@ var (
 		Usage: "Password file to use for non-interactive password input",
 		Value: "",
 	}
+	SourcePasswordFileFlag = cli.StringFlag{
+		Name:  "srcpassword",
+		Usage: "Password file of the keys read by the batch commands, whose lines are tried in turn",
+		Value: "",
+	}
+	KeystoreV3Flag = cli.BoolFlag{
+		Name:  "keystore.v3",
+		Usage: "Export the keys in the keystore v3 format used by Ethereum tools",
+	}
+	MnemonicAccountsFlag = cli.IntFlag{
+		Name:  "mnemonic.accounts",
+		Usage: "Number of the accounts derived from each mnemonic on m/44'/8217'/0'/0/<index>",
+		Value: 1,
+	}
+	BatchWorkersFlag = cli.IntFlag{
+		Name:  "workers",
+		Usage: "Number of the keys encrypted or decrypted in parallel (0 = number of CPUs)",
+		Value: 0,
+	}
+	DryRunFlag = cli.BoolFlag{
+		Name:  "dryrun",
+		Usage: "Print what would be done without writing any key file",
+	}
 
 	VMEnableDebugFlag = cli.BoolFlag{
 		Name:  "vmdebug",
@@ -440,6 +690,50 @@ var (
 		Usage: "Prometheus exporter listening port",
 		Value: 61001,
 	}
+	// Health endpoint settings
+	HealthEnabledFlag = cli.BoolFlag{
+		Name:  "health",
+		Usage: "Enable the HTTP health endpoint serving the readiness at /health and the liveness at /health/live",
+	}
+	HealthListenAddrFlag = cli.StringFlag{
+		Name:  "health.addr",
+		Usage: "Health endpoint listening interface",
+		Value: cn.DefaultHealthHost,
+	}
+	HealthPortFlag = cli.IntFlag{
+		Name:  "health.port",
+		Usage: "Health endpoint listening port",
+		Value: cn.DefaultHealthPort,
+	}
+	HealthMinPeersFlag = cli.IntFlag{
+		Name:  "health.minpeers",
+		Usage: "Minimum number of peers for the node to be ready",
+		Value: cn.DefaultHealthConfig.MinPeers,
+	}
+	HealthMaxBlockAgeFlag = cli.DurationFlag{
+		Name:  "health.maxblockage",
+		Usage: "Maximum age of the head block for the node to be ready (0 = disabled)",
+		Value: cn.DefaultHealthConfig.MaxBlockAge,
+	}
+	HealthAllowSyncingFlag = cli.BoolFlag{
+		Name:  "health.allowsyncing",
+		Usage: "Report the node as ready while it is syncing",
+	}
+	// Multisig coordinator settings
+	MultisigEnabledFlag = cli.BoolFlag{
+		Name:  "multisig",
+		Usage: "Enable the multisig coordinator collecting the signatures of the co-signers via the multisig RPC namespace",
+	}
+	MultisigLifetimeFlag = cli.DurationFlag{
+		Name:  "multisig.lifetime",
+		Usage: "Time a partially-signed transaction is kept by the multisig coordinator",
+		Value: cn.DefaultMultisigConfig.Lifetime,
+	}
+	MultisigMaxProposalsFlag = cli.IntFlag{
+		Name:  "multisig.maxproposals",
+		Usage: "Maximum number of the partially-signed transactions kept by the multisig coordinator",
+		Value: cn.DefaultMultisigConfig.MaxProposals,
+	}
 	// RPC settings
 	RPCEnabledFlag = cli.BoolFlag{
 		Name:  "rpc",
@@ -561,6 +855,11 @@ var (
 		Name:  "preload",
 		Usage: "Comma separated list of JavaScript files to preload into the console",
 	}
+	DebugBundleCPUProfileFlag = cli.IntFlag{
+		Name:  "cpuprofile.seconds",
+		Usage: "Duration of the CPU profile in the diagnostics bundle in seconds (0 = no CPU profile)",
+		Value: 10,
+	}
 	APIFilterGetLogsDeadlineFlag = cli.DurationFlag{
 		Name:  "api.filter.getLogs.deadline",
 		Usage: "Execution deadline for log collecting filter APIs",
@@ -598,6 +897,11 @@ var (
 		Usage: "Klaytn node type (consensus node (cn), proxy node (pn), endpoint node (en))",
 		Value: "en",
 	}
+	NodeProfileFlag = cli.StringFlag{
+		Name: "profile",
+		Usage: "Preset of the caches, GC, handle limits, peers and txpool sized by the memory and CPUs " +
+			"('cn', 'pn', 'en-archive', 'en-rpc'). The flags given explicitly override the preset",
+	}
 	MaxConnectionsFlag = cli.IntFlag{
 		Name:  "maxconnections",
 		Usage: "Maximum number of physical connections. All single channel peers can be maxconnections peers. All multi channel peers can be maxconnections/2 peers. (network disabled if set to 0)",
@@ -608,6 +912,31 @@ var (
 		Usage: "Maximum number of pending connection attempts (defaults used if set to 0)",
 		Value: 0,
 	}
+	MaxInboundPerIPFlag = cli.IntFlag{
+		Name:  "maxinboundperip",
+		Usage: "Maximum number of concurrent inbound connections from a single IP (unlimited if set to 0)",
+		Value: 0,
+	}
+	MaxInboundPerSubnetFlag = cli.IntFlag{
+		Name:  "maxinboundpersubnet",
+		Usage: "Maximum number of concurrent inbound connections from a single /24 IPv4 or /64 IPv6 subnet (unlimited if set to 0)",
+		Value: 0,
+	}
+	MaxPeerEgressBytesPerSecFlag = cli.IntFlag{
+		Name:  "maxpeeregressbytespersec",
+		Usage: "Maximum egress bandwidth to a single peer in bytes per second, consensus messages excluded (unlimited if set to 0)",
+		Value: 0,
+	}
+	MaxEgressBytesPerSecFlag = cli.IntFlag{
+		Name:  "maxegressbytespersec",
+		Usage: "Maximum egress bandwidth to all peers in bytes per second, consensus messages excluded (unlimited if set to 0)",
+		Value: 0,
+	}
+	CompressionThresholdFlag = cli.IntFlag{
+		Name:  "compressionthreshold",
+		Usage: "Minimum size in bytes of the p2p messages compressed for the peers supporting the selective compression (defaults used if set to 0, compress all messages if negative)",
+		Value: 0,
+	}
 	ListenPortFlag = cli.IntFlag{
 		Name:  "port",
 		Usage: "Network listening port",
@@ -637,17 +966,83 @@ var (
 	}
 	NATFlag = cli.StringFlag{
 		Name:  "nat",
-		Usage: "NAT port mapping mechanism (any|none|upnp|pmp|extip:<IP>)",
+		Usage: "NAT port mapping mechanism (any|none|upnp|pmp|extip:<IP>|stun[:<host:port>])",
 		Value: "any",
 	}
+	IPPolicyFlag = cli.StringFlag{
+		Name:  "ippolicy",
+		Usage: "IP address families used for peering (dual|prefer-ipv4|prefer-ipv6|ipv4-only|ipv6-only)",
+		Value: "dual",
+	}
+	ExtIPv6Flag = cli.StringFlag{
+		Name:  "extip6",
+		Usage: "Public IPv6 address announced through the discovery if the IP policy prefers IPv6",
+	}
+	P2PTLSProfileFlag = cli.StringFlag{
+		Name:  "p2p.tls",
+		Usage: "Wraps p2p connections in mutually-authenticated TLS (tls: CA-issued certificates, tls-pinned: allowlisted certificates)",
+	}
+	P2PTLSCertFlag = cli.StringFlag{
+		Name:  "p2p.tls.cert",
+		Usage: "PEM encoded TLS certificate of the node",
+	}
+	P2PTLSKeyFlag = cli.StringFlag{
+		Name:  "p2p.tls.key",
+		Usage: "PEM encoded private key of the TLS certificate",
+	}
+	P2PTLSCAFlag = cli.StringFlag{
+		Name:  "p2p.tls.ca",
+		Usage: "PEM encoded certificates of the authorities issuing the peer certificates (profile tls)",
+	}
+	P2PTLSAllowedFlag = cli.StringFlag{
+		Name:  "p2p.tls.allowed",
+		Usage: "Comma separated SHA-256 fingerprints of the allowed peer certificates (profile tls-pinned)",
+	}
 	NoDiscoverFlag = cli.BoolFlag{
 		Name:  "nodiscover",
 		Usage: "Disables the peer discovery mechanism (manual peer addition)",
 	}
+	DiscoveryTopicsFlag = cli.StringFlag{
+		Name:  "discovery.topics",
+		Usage: "Comma separated topics advertised through the peer discovery (e.g. servicechain,archive)",
+	}
+	DNSDiscoveryFlag = cli.StringFlag{
+		Name:  "discovery.dns",
+		Usage: "Comma separated URLs of DNS node trees (kntree://<key>@<domain>) used as an additional peer source",
+	}
+	SentryNodesFlag = cli.StringFlag{
+		Name:  "sentry.nodes",
+		Usage: "Comma separated kni URLs of the sentry nodes. If set, the node connects only to the sentry nodes hiding its IP address",
+	}
+	SentryRelayFlag = cli.BoolFlag{
+		Name:  "sentry.relay",
+		Usage: "Relays the consensus messages of the validators running in sentry mode (PN only). Only the messages from the trusted nodes, i.e. the validators and the other sentries, are relayed",
+	}
+	ProxyUpstreamsFlag = cli.StringFlag{
+		Name:  "proxy.upstreams",
+		Usage: "Comma separated kni URLs of the upstream CNs of the validator in the order of priority. If set, the node runs as a validator proxy failing over between them (PN only). The consensus messages of the other validators are relayed only from the trusted nodes",
+	}
 	NetrestrictFlag = cli.StringFlag{
 		Name:  "netrestrict",
 		Usage: "Restricts network communication to the given IP network (CIDR masks)",
 	}
+	PeerListURLFlag = cli.StringFlag{
+		Name:  "peerlist.url",
+		Usage: "URL of the signed list of boot nodes and static peers",
+	}
+	PeerListSignerFlag = cli.StringFlag{
+		Name:  "peerlist.signer",
+		Usage: "Address of the key signing the peer list",
+	}
+	PeerListKeyFileFlag = cli.StringFlag{
+		Name:  "peerlist.keyfile",
+		Usage: "File containing the hex encoded 32-byte key decrypting the peer list",
+	}
+	PeerListRefreshFlag = cli.DurationFlag{
+		Name:  "peerlist.refresh",
+		Usage: "Interval of fetching the peer list",
+		Value: node.DefaultPeerListRefreshInterval,
+	}
 	AnchoringPeriodFlag = cli.Uint64Flag{
 		Name:  "chaintxperiod",
 		Usage: "The period to make and send a chain transaction to the parent chain",
@@ -742,6 +1137,19 @@ var (
 		Usage: "Set the value transfer recovery interval (seconds)",
 		Value: 5,
 	}
+	VTRecoveryRetryBackoffFlag = cli.DurationFlag{
+		Name:  "vtrecovery.retry.backoff",
+		Usage: "Time to wait before retrying a pending value transfer, doubled for each attempt (0 = every recovery interval)",
+	}
+	VTRecoveryRetryMaxBackoffFlag = cli.DurationFlag{
+		Name:  "vtrecovery.retry.maxbackoff",
+		Usage: "Maximum time to wait between retries of a pending value transfer",
+		Value: 10 * time.Minute,
+	}
+	VTRecoveryMaxAttemptsFlag = cli.Uint64Flag{
+		Name:  "vtrecovery.maxattempts",
+		Usage: "Number of attempts before a pending value transfer is dead-lettered (0 = unlimited)",
+	}
 	ServiceChainParentOperatorTxGasLimitFlag = cli.Uint64Flag{
 		Name:  "sc.parentoperator.gaslimit",
 		Usage: "Set the default value of gas limit for transactions made by bridge parent operator",
@@ -752,6 +1160,75 @@ var (
 		Usage: "Set the default value of gas limit for transactions made by bridge child operator",
 		Value: 10000000,
 	}
+	ServiceChainAnchoringGasPriceStrategyFlag = cli.StringFlag{
+		Name:  "sc.anchoring.gasprice.strategy",
+		Usage: `Gas price strategy of anchoring transactions ("parent", "fixed" or "premium")`,
+		Value: sc.AnchoringGasPriceParent,
+	}
+	ServiceChainAnchoringGasPriceFlag = cli.Uint64Flag{
+		Name:  "sc.anchoring.gasprice",
+		Usage: `Gas price of anchoring transactions for the "fixed" strategy`,
+	}
+	ServiceChainAnchoringGasPricePremiumFlag = cli.Uint64Flag{
+		Name:  "sc.anchoring.gasprice.premium",
+		Usage: `Percentage added to the parent chain gas price for the "premium" strategy`,
+	}
+	ServiceChainAnchoringGasPriceCapFlag = cli.Uint64Flag{
+		Name:  "sc.anchoring.gasprice.cap",
+		Usage: "Maximum gas price of anchoring transactions including retries (0 = no limit)",
+	}
+	ServiceChainAnchoringRetryGasBumpFlag = cli.Uint64Flag{
+		Name:  "sc.anchoring.retry.gasbump",
+		Usage: "Percentage of the gas price increased for each retry of a rejected anchoring transaction",
+		Value: sc.DefaultAnchoringRetryGasBump,
+	}
+	ServiceChainAnchoringRetryBackoffFlag = cli.DurationFlag{
+		Name:  "sc.anchoring.retry.backoff",
+		Usage: "Time to wait before retrying a rejected anchoring transaction, doubled for each retry",
+		Value: sc.DefaultAnchoringRetryBackoff,
+	}
+	ServiceChainAnchoringRetryMaxBackoffFlag = cli.DurationFlag{
+		Name:  "sc.anchoring.retry.maxbackoff",
+		Usage: "Maximum time to wait between retries of a rejected anchoring transaction",
+		Value: sc.DefaultAnchoringRetryMaxBackoff,
+	}
+	ServiceChainAnchoringMaxRetriesFlag = cli.Uint64Flag{
+		Name:  "sc.anchoring.retry.max",
+		Usage: "Number of retries before an anchoring transaction is counted as failed",
+		Value: sc.DefaultAnchoringMaxRetries,
+	}
+	ServiceChainAnchoringNonceRepairFlag = cli.BoolFlag{
+		Name:  "sc.anchoring.noncerepair",
+		Usage: "Sign pending anchoring transactions again to fill a nonce gap with the parent chain",
+	}
+	ServiceChainAnchoringBatchFlag = cli.BoolFlag{
+		Name:  "sc.anchoring.batch",
+		Usage: "Anchor all the blocks of an anchoring period (chaintxperiod) by compressed anchoring transactions",
+	}
+	ServiceChainAnchoringBatchMaxBlocksFlag = cli.Uint64Flag{
+		Name:  "sc.anchoring.batch.maxblocks",
+		Usage: "Maximum number of blocks anchored by a batched anchoring transaction",
+		Value: sc.DefaultAnchoringBatchMaxBlocks,
+	}
+	ServiceChainParentHealthCheckIntervalFlag = cli.DurationFlag{
+		Name:  "sc.parent.healthcheck.interval",
+		Usage: "Interval of the health checks of the parent chain bridges listed in main-bridges.json",
+		Value: sc.DefaultParentHealthCheckInterval,
+	}
+	ServiceChainParentHealthTimeoutFlag = cli.DurationFlag{
+		Name:  "sc.parent.healthcheck.timeout",
+		Usage: "Time without a response before the rpc connection fails over to another parent chain bridge",
+		Value: sc.DefaultParentHealthTimeout,
+	}
+	ServiceChainParentQuorumFlag = cli.IntFlag{
+		Name:  "sc.parent.quorum",
+		Usage: "Number of parent chain bridges which must agree on the parent operator nonce before it is used",
+		Value: 1,
+	}
+	ServiceChainFeeDelegationPolicyFlag = cli.StringFlag{
+		Name:  "sc.feedelegation.policy",
+		Usage: "JSON file of the policy to fee-delegate user value transfer requests by the child operator",
+	}
 	ServiceChainNewAccountFlag = cli.BoolFlag{
 		Name:  "scnewaccount",
 		Usage: "Enable account creation for the service chain (default: false). If set true, generated account can't be synced with the parent chain.",
@@ -809,7 +1286,7 @@ var (
 	}
 	ChainDataFetcherMode = cli.StringFlag{
 		Name:  "chaindatafetcher.mode",
-		Usage: "The mode of chaindatafetcher (\"kas\", \"kafka\")",
+		Usage: "The mode of chaindatafetcher (\"kas\", \"kafka\", \"postgres\", \"elasticsearch\")",
 		Value: "kas",
 	}
 	ChainDataFetcherNoDefault = cli.BoolFlag{
@@ -831,6 +1308,19 @@ var (
 		Usage: "Block received channel size",
 		Value: chaindatafetcher.DefaultJobChannelSize,
 	}
+	ChainDataFetcherFilterRulesFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.filter.rules",
+		Usage: "JSON file of the rules filtering the exported data by addresses, topics and tx types (reloadable by chaindatafetcher_reloadFilterRules)",
+	}
+	ChainDataFetcherBackpressureFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.backpressure",
+		Usage: `Behavior on the full block channel, "block" (block the chain event feed) or "drop" (drop the blocks and fetch them again later)`,
+		Value: chaindatafetcher.BackpressureBlock,
+	}
+	ChainDataFetcherDeadLetterFlag = cli.BoolFlag{
+		Name:  "chaindatafetcher.deadletter",
+		Usage: "Move the blocks failed to be exported to the dead-letter queue instead of pausing the fetching (reprocessed by chaindatafetcher_reprocessDeadLetter)",
+	}
 	ChainDataFetcherKASDBHostFlag = cli.StringFlag{
 		Name:  "chaindatafetcher.kas.db.host",
 		Usage: "KAS specific DB host in chaindatafetcher",
@@ -917,6 +1407,109 @@ var (
 		Usage: "The identifier of kafka message producer",
 		Value: kafka.GetDefaultProducerId(),
 	}
+	ChainDataFetcherKafkaMsgEncodingFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.kafka.encoding",
+		Usage: "The encoding of kafka messages (json, avro, protobuf)",
+		Value: kafka.DefaultMsgEncoding,
+	}
+	ChainDataFetcherKafkaSchemaRegistryFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.kafka.schema-registry",
+		Usage: "The URL of the schema registry, required for the avro and protobuf encodings",
+	}
+	ChainDataFetcherKafkaMaxMessagesPerSecondFlag = cli.IntFlag{
+		Name:  "chaindatafetcher.kafka.rate",
+		Usage: "The maximum number of kafka messages produced per second (0 = unlimited)",
+	}
+	ChainDataFetcherPostgresDBHostFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.postgres.db.host",
+		Usage: "Postgres DB host in chaindatafetcher",
+	}
+	ChainDataFetcherPostgresDBPortFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.postgres.db.port",
+		Usage: "Postgres DB port in chaindatafetcher",
+		Value: postgres.DefaultDBPort,
+	}
+	ChainDataFetcherPostgresDBNameFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.postgres.db.name",
+		Usage: "Postgres DB name in chaindatafetcher",
+	}
+	ChainDataFetcherPostgresDBUserFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.postgres.db.user",
+		Usage: "Postgres DB user in chaindatafetcher",
+	}
+	ChainDataFetcherPostgresDBPasswordFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.postgres.db.password",
+		Usage: "Postgres DB password in chaindatafetcher",
+	}
+	ChainDataFetcherPostgresSSLModeFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.postgres.sslmode",
+		Usage: "Postgres SSL mode in chaindatafetcher (disable, require, verify-ca, verify-full)",
+		Value: postgres.DefaultSSLMode,
+	}
+	ChainDataFetcherPostgresSchemaFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.postgres.schema",
+		Usage: "Postgres schema in which the chaindatafetcher tables are created",
+		Value: postgres.DefaultSchema,
+	}
+	ChainDataFetcherPostgresTablePrefixFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.postgres.table.prefix",
+		Usage: "Prefix of the names of the chaindatafetcher tables",
+	}
+	ChainDataFetcherPostgresTablesFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.postgres.tables",
+		Usage: "Comma separated tables loaded by chaindatafetcher (blocks, transactions, logs, traces, internal_transfers)",
+		Value: strings.Join(postgres.AllTables, ","),
+	}
+	ChainDataFetcherPostgresBatchSizeFlag = cli.IntFlag{
+		Name:  "chaindatafetcher.postgres.batch.size",
+		Usage: "Maximum number of rows inserted by a statement in chaindatafetcher",
+		Value: postgres.DefaultBatchSize,
+	}
+	ChainDataFetcherElasticsearchURLsFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.elasticsearch.urls",
+		Usage: "Comma separated URLs of the Elasticsearch or OpenSearch cluster in chaindatafetcher",
+		Value: elasticsearch.DefaultURL,
+	}
+	ChainDataFetcherElasticsearchUserFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.elasticsearch.user",
+		Usage: "Elasticsearch user in chaindatafetcher",
+	}
+	ChainDataFetcherElasticsearchPasswordFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.elasticsearch.password",
+		Usage: "Elasticsearch password in chaindatafetcher",
+	}
+	ChainDataFetcherElasticsearchIndexPrefixFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.elasticsearch.index.prefix",
+		Usage: "Prefix of the names of the chaindatafetcher indices",
+		Value: elasticsearch.DefaultIndexPrefix,
+	}
+	ChainDataFetcherElasticsearchIndexDateLayoutFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.elasticsearch.index.date",
+		Usage: "Go time layout of the date suffix of the index names by the block time (empty = no suffix)",
+		Value: elasticsearch.DefaultIndexDateLayout,
+	}
+	ChainDataFetcherElasticsearchTemplateFileFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.elasticsearch.template",
+		Usage: "JSON file of the index templates keyed by the index, replacing the default templates",
+	}
+	ChainDataFetcherElasticsearchILMPolicyFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.elasticsearch.ilm.policy",
+		Usage: "Index lifecycle policy set to the indices by the default index templates",
+	}
+	ChainDataFetcherElasticsearchIndicesFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.elasticsearch.indices",
+		Usage: "Comma separated indices loaded by chaindatafetcher (blocks, transactions, logs)",
+		Value: strings.Join(elasticsearch.AllIndices, ","),
+	}
+	ChainDataFetcherElasticsearchABIFilesFlag = cli.StringFlag{
+		Name:  "chaindatafetcher.elasticsearch.abi",
+		Usage: "Comma separated JSON ABI files of the events decoded from the logs",
+	}
+	ChainDataFetcherElasticsearchBulkSizeFlag = cli.IntFlag{
+		Name:  "chaindatafetcher.elasticsearch.bulk.size",
+		Usage: "Maximum number of documents indexed by a bulk request in chaindatafetcher",
+		Value: elasticsearch.DefaultBulkSize,
+	}
 	// DBSyncer
 	EnableDBSyncerFlag = cli.BoolFlag{
 		Name:  "dbsyncer",
@@ -1006,6 +1599,11 @@ var (
 		Usage: "The elapsed time to wait auto restart (minutes)",
 		Value: 15 * time.Minute,
 	}
+	ShutdownGracePeriodFlag = cli.DurationFlag{
+		Name:  "shutdown.grace",
+		Usage: "Maximum time to wait for the RPC requests being executed to finish on shutdown (0 = cancel immediately)",
+		Value: node.DefaultShutdownGracePeriod,
+	}
 	DaemonPathFlag = cli.StringFlag{
 		Name:  "autorestart.daemon.path",
 		Usage: "Path of node daemon. Used to give signal to kill",
@@ -1036,6 +1634,14 @@ var (
 		Usage: "Determines the compression method for LevelDB. 0=AllNoCompression, 1=ReceiptOnlySnappyCompression, 2=StateTrieOnlyNoCompression, 3=AllSnappyCompression",
 		Value: 0,
 	}
+	DstDBCompressionFlag = cli.StringFlag{
+		Name:  "db.dst.compression",
+		Usage: "Compression of each destination database overriding db.dst.leveldb.compression, in the same form as db.compression",
+	}
+	DstDBEncryptionKeyFlag = cli.StringFlag{
+		Name:  "db.dst.encryption.key",
+		Usage: "Source of the key encrypting the destination databases, in the same form as db.encryption.key",
+	}
 	DstNumStateTrieShardsFlag = cli.UintFlag{
 		Name:  "db.dst.num-statetrie-shards",
 		Usage: "Number of internal shards of state trie DB shards. Should be power of 2",
@@ -1064,11 +1670,88 @@ var (
 		Usage: "Write capacity unit of dynamoDB. If is-provisioned is not set, this flag will not be applied",
 		Value: database.GetDefaultDynamoDBConfig().WriteCapacityUnits,
 	}
+	DBMigrationWorkersFlag = cli.IntFlag{
+		Name:  "db.migration.workers",
+		Usage: "Number of parallel copy workers for each database. The progress is kept only with the same number",
+		Value: database.GetDefaultDBMigrationConfig().Workers,
+	}
+	DBMigrationMaxRateFlag = cli.IntFlag{
+		Name:  "db.migration.max-rate",
+		Usage: "Maximum copy rate of the db migration in MiB per second (0 = unlimited)",
+	}
+	DBMigrationVerifyFlag = cli.BoolFlag{
+		Name:  "db.migration.verify",
+		Usage: "Verify the migrated items against the source database after copying",
+	}
+
+	// Database verification
+	DBVerifyFromFlag = cli.Uint64Flag{
+		Name:  "verify.from",
+		Usage: "Block number to start the database verification from",
+	}
+	DBVerifyToFlag = cli.Uint64Flag{
+		Name:  "verify.to",
+		Usage: "Block number to end the database verification at (default: the head block)",
+	}
+	DBVerifyRepairFlag = cli.BoolFlag{
+		Name:  "verify.repair",
+		Usage: "Repair the recoverable inconsistencies found by the database verification",
+	}
+	VerifyBlocksFromFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "First block number to re-execute by the verify command",
+		Value: 1,
+	}
+	VerifyBlocksToFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Last block number to re-execute by the verify command (default: the head block)",
+	}
+	DBReshardShardsFlag = cli.UintFlag{
+		Name:  "reshard.shards",
+		Usage: "Number of shards of the resharded state trie DB. Should be power of 2",
+	}
+
+	// Database inspection
+	DBUnsafeFlag = cli.BoolFlag{
+		Name:  "i-know-what-i-am-doing",
+		Usage: "Allow modifying the raw key-value entries of the database, which may corrupt it",
+	}
+	DBIterateLimitFlag = cli.IntFlag{
+		Name:  "iterate.limit",
+		Usage: "Maximum number of the entries printed by the database iteration",
+		Value: 100,
+	}
+
+	// Bridge reconciliation
+	BridgeReconcileEndpointFlag = cli.StringFlag{
+		Name:  "reconcile.endpoint",
+		Usage: "RPC endpoint of the running service chain node (default: klay.ipc in the data directory)",
+	}
+	BridgeReconcileChildFromFlag = cli.Uint64Flag{
+		Name:  "reconcile.child.from",
+		Usage: "Child chain block number to start the reconciliation from",
+	}
+	BridgeReconcileChildToFlag = cli.Uint64Flag{
+		Name:  "reconcile.child.to",
+		Usage: "Child chain block number to end the reconciliation at (0 = current block)",
+	}
+	BridgeReconcileParentFromFlag = cli.Uint64Flag{
+		Name:  "reconcile.parent.from",
+		Usage: "Parent chain block number to start the reconciliation from",
+	}
+	BridgeReconcileParentToFlag = cli.Uint64Flag{
+		Name:  "reconcile.parent.to",
+		Usage: "Parent chain block number to end the reconciliation at (0 = current block)",
+	}
+	BridgeReconcileResubmitFlag = cli.BoolFlag{
+		Name:  "reconcile.resubmit",
+		Usage: "Submit the handle transactions of the unhandled value transfer requests again",
+	}
 
 	// Config
 	ConfigFileFlag = cli.StringFlag{
 		Name:  "config",
-		Usage: "TOML configuration file",
+		Usage: "TOML or YAML (.yaml, .yml) configuration file",
 	}
 	BlockGenerationIntervalFlag = cli.Int64Flag{
 		Name: "block-generation-interval",
@@ -1181,6 +1864,22 @@ func setBootstrapNodes(ctx *cli.Context, cfg *p2p.Config) {
 	}
 }
 
+// setSentryNodes parses the sentry nodes of the validator running in sentry mode.
+func setSentryNodes(ctx *cli.Context, cfg *p2p.Config) {
+	urls := ctx.GlobalString(SentryNodesFlag.Name)
+	if urls == "" {
+		return
+	}
+	for _, url := range splitAndTrim(urls) {
+		node, err := discover.ParseNode(url)
+		if err != nil {
+			log.Fatalf("Option %q: invalid sentry node %q: %v", SentryNodesFlag.Name, url, err)
+		}
+		cfg.SentryNodes = append(cfg.SentryNodes, node)
+	}
+	logger.Info("Running in sentry mode", "sentries", len(cfg.SentryNodes))
+}
+
 // setListenAddress creates a TCP listening address string from set command
 // line flags.
 func setListenAddress(ctx *cli.Context, cfg *p2p.Config) {
@@ -1206,6 +1905,80 @@ func setNAT(ctx *cli.Context, cfg *p2p.Config) {
 	}
 }
 
+// setColdStorage sets the configuration of the cold storage tier.
+func setColdStorage(ctx *cli.Context, cfg *database.ColdStorageConfig) {
+	*cfg = *database.GetDefaultColdStorageConfig()
+	if !ctx.GlobalIsSet(ColdStorageBucketFlag.Name) {
+		return
+	}
+	cfg.Enabled = true
+	cfg.Bucket = ctx.GlobalString(ColdStorageBucketFlag.Name)
+	cfg.Region = ctx.GlobalString(ColdStorageRegionFlag.Name)
+	cfg.Endpoint = ctx.GlobalString(ColdStorageEndpointFlag.Name)
+	cfg.MinAge = ctx.GlobalUint64(ColdStorageMinAgeFlag.Name)
+	cfg.MinSize = ctx.GlobalInt(ColdStorageMinSizeFlag.Name)
+	cfg.CacheSize = ctx.GlobalInt(ColdStorageCacheSizeFlag.Name)
+}
+
+// setAncientSource sets the configuration of the remote source of the ancient data.
+func setAncientSource(ctx *cli.Context, cfg *database.AncientSourceConfig) {
+	*cfg = *database.GetDefaultAncientSourceConfig()
+	if !ctx.GlobalIsSet(AncientSourceURLFlag.Name) {
+		return
+	}
+	if !ctx.GlobalIsSet(AncientSourceUntilFlag.Name) {
+		log.Fatalf("Option %s is required with %s", AncientSourceUntilFlag.Name, AncientSourceURLFlag.Name)
+	}
+	cfg.URL = ctx.GlobalString(AncientSourceURLFlag.Name)
+	cfg.Until = ctx.GlobalUint64(AncientSourceUntilFlag.Name)
+	cfg.CacheSize = ctx.GlobalInt(AncientSourceCacheSizeFlag.Name)
+}
+
+// setP2PTLS sets the TLS configuration of the p2p connections.
+func setP2PTLS(ctx *cli.Context, cfg *p2p.Config) {
+	profile := ctx.GlobalString(P2PTLSProfileFlag.Name)
+	if profile == "" {
+		return
+	}
+	if profile != p2p.TLSProfileCA && profile != p2p.TLSProfilePinned {
+		log.Fatalf("Option %s: unknown profile %q, want %s or %s", P2PTLSProfileFlag.Name, profile, p2p.TLSProfileCA, p2p.TLSProfilePinned)
+	}
+	cfg.TLS = &p2p.TLSConfig{
+		Profile:  profile,
+		CertFile: ctx.GlobalString(P2PTLSCertFlag.Name),
+		KeyFile:  ctx.GlobalString(P2PTLSKeyFlag.Name),
+		CAFile:   ctx.GlobalString(P2PTLSCAFlag.Name),
+	}
+	if cfg.TLS.CertFile == "" || cfg.TLS.KeyFile == "" {
+		log.Fatalf("Option %s requires %s and %s", P2PTLSProfileFlag.Name, P2PTLSCertFlag.Name, P2PTLSKeyFlag.Name)
+	}
+	if allowed := ctx.GlobalString(P2PTLSAllowedFlag.Name); allowed != "" {
+		cfg.TLS.AllowedCerts = splitAndTrim(allowed)
+	}
+}
+
+// setIPPolicy sets the IP address families used for peering. The policy also
+// applies to the host names of the node URLs parsed afterwards.
+func setIPPolicy(ctx *cli.Context, cfg *p2p.Config) {
+	policy, err := netutil.ParseIPPolicy(ctx.GlobalString(IPPolicyFlag.Name))
+	if err != nil {
+		log.Fatalf("Option %s: %v", IPPolicyFlag.Name, err)
+	}
+	cfg.IPPolicy = policy
+	discover.SetResolvePolicy(policy)
+
+	if s := ctx.GlobalString(ExtIPv6Flag.Name); s != "" {
+		ip := net.ParseIP(s)
+		if ip == nil || ip.To4() != nil {
+			log.Fatalf("Option %s: invalid IPv6 address %q", ExtIPv6Flag.Name, s)
+		}
+		if !policy.PrefersIPv6() {
+			logger.Warn("The IPv6 address is announced only if the IP policy prefers IPv6", "extip6", ip, "policy", policy)
+		}
+		cfg.ExtIPv6 = ip
+	}
+}
+
 // splitAndTrim splits input separated by a comma
 // and trims excessive white space from the substrings.
 func splitAndTrim(input string) []string {
@@ -1308,6 +2081,22 @@ func setgRPC(ctx *cli.Context, cfg *node.Config) {
 	}
 }
 
+// setPeerList sets the URL and the verification keys of the distributed peer list.
+func setPeerList(ctx *cli.Context, cfg *node.Config) {
+	if !ctx.GlobalIsSet(PeerListURLFlag.Name) {
+		return
+	}
+	cfg.PeerListURL = ctx.GlobalString(PeerListURLFlag.Name)
+
+	signer := ctx.GlobalString(PeerListSignerFlag.Name)
+	if !common.IsHexAddress(signer) {
+		log.Fatalf("Option %q must be a valid address: %q", PeerListSignerFlag.Name, signer)
+	}
+	cfg.PeerListSigner = common.HexToAddress(signer)
+	cfg.PeerListKeyFile = ctx.GlobalString(PeerListKeyFileFlag.Name)
+	cfg.PeerListRefreshInterval = ctx.GlobalDuration(PeerListRefreshFlag.Name)
+}
+
 // setAPIConfig sets configurations for specific APIs.
 func setAPIConfig(ctx *cli.Context) {
 	filters.GetLogsDeadline = ctx.GlobalDuration(APIFilterGetLogsDeadlineFlag.Name)
@@ -1361,7 +2150,20 @@ func setRewardbase(ctx *cli.Context, ks *keystore.KeyStore, cfg *cn.Config) {
 
 // MakePasswordList reads password lines from the file specified by the global --password flag.
 func MakePasswordList(ctx *cli.Context) []string {
-	path := ctx.GlobalString(PasswordFileFlag.Name)
+	return readPasswordList(ctx.GlobalString(PasswordFileFlag.Name))
+}
+
+// MakeSourcePasswordList reads the passwords of the keys read by the batch
+// account commands. The empty line at the end of the file is dropped.
+func MakeSourcePasswordList(ctx *cli.Context) []string {
+	lines := readPasswordList(ctx.GlobalString(SourcePasswordFileFlag.Name))
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func readPasswordList(path string) []string {
 	if path == "" {
 		return nil
 	}
@@ -1380,6 +2182,8 @@ func MakePasswordList(ctx *cli.Context) []string {
 func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 	setNodeKey(ctx, cfg)
 	setNAT(ctx, cfg)
+	setIPPolicy(ctx, cfg)
+	setP2PTLS(ctx, cfg)
 	setListenAddress(ctx, cfg)
 
 	var nodeType string
@@ -1406,8 +2210,20 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 	if ctx.GlobalIsSet(MaxPendingPeersFlag.Name) {
 		cfg.MaxPendingPeers = ctx.GlobalInt(MaxPendingPeersFlag.Name)
 	}
+	cfg.MaxInboundPerIP = ctx.GlobalInt(MaxInboundPerIPFlag.Name)
+	cfg.MaxInboundPerSubnet = ctx.GlobalInt(MaxInboundPerSubnetFlag.Name)
+	cfg.MaxPeerEgressBytesPerSec = ctx.GlobalInt(MaxPeerEgressBytesPerSecFlag.Name)
+	cfg.MaxEgressBytesPerSec = ctx.GlobalInt(MaxEgressBytesPerSecFlag.Name)
+	cfg.CompressionThreshold = ctx.GlobalInt(CompressionThresholdFlag.Name)
 
 	cfg.NoDiscovery = ctx.GlobalIsSet(NoDiscoverFlag.Name)
+	if topics := ctx.GlobalString(DiscoveryTopicsFlag.Name); topics != "" {
+		cfg.DiscoveryTopics = splitAndTrim(topics)
+	}
+	if urls := ctx.GlobalString(DNSDiscoveryFlag.Name); urls != "" {
+		cfg.DNSDiscovery = splitAndTrim(urls)
+	}
+	setSentryNodes(ctx, cfg)
 
 	cfg.RWTimerConfig = p2p.RWTimerConfig{}
 	cfg.RWTimerConfig.Interval = ctx.GlobalUint64(RWTimerIntervalFlag.Name)
@@ -1452,6 +2268,7 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	setHTTP(ctx, cfg)
 	setWS(ctx, cfg)
 	setgRPC(ctx, cfg)
+	setPeerList(ctx, cfg)
 	setAPIConfig(ctx)
 	setNodeUserIdent(ctx, cfg)
 
@@ -1468,11 +2285,133 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(LightKDFFlag.Name) {
 		cfg.UseLightweightKDF = ctx.GlobalBool(LightKDFFlag.Name)
 	}
+	setKeyStoreKDF(ctx, &cfg.KeyStoreKDF)
+	setKMS(ctx, &cfg.KMS)
+	setVault(ctx, &cfg.Vault)
+	setRemoteSigner(ctx, &cfg.RemoteSigner)
+	setPKCS11(ctx, &cfg.PKCS11)
+	if ctx.GlobalIsSet(ShutdownGracePeriodFlag.Name) {
+		cfg.ShutdownGracePeriod = ctx.GlobalDuration(ShutdownGracePeriodFlag.Name)
+	}
 	if ctx.GlobalIsSet(RPCNonEthCompatibleFlag.Name) {
 		rpc.NonEthCompatible = ctx.GlobalBool(RPCNonEthCompatibleFlag.Name)
 	}
 }
 
+// setKeyStoreKDF applies the KDF flags of the key store to the config.
+func setKeyStoreKDF(ctx *cli.Context, cfg *keystore.KDFConfig) {
+	if ctx.GlobalIsSet(KeyStoreKDFFlag.Name) {
+		cfg.KDF = ctx.GlobalString(KeyStoreKDFFlag.Name)
+	}
+	if ctx.GlobalIsSet(KeyStoreScryptNFlag.Name) {
+		cfg.ScryptN = ctx.GlobalInt(KeyStoreScryptNFlag.Name)
+	}
+	if ctx.GlobalIsSet(KeyStoreScryptRFlag.Name) {
+		cfg.ScryptR = ctx.GlobalInt(KeyStoreScryptRFlag.Name)
+	}
+	if ctx.GlobalIsSet(KeyStoreScryptPFlag.Name) {
+		cfg.ScryptP = ctx.GlobalInt(KeyStoreScryptPFlag.Name)
+	}
+	if ctx.GlobalIsSet(KeyStoreArgon2TimeFlag.Name) {
+		cfg.Argon2Time = uint32(ctx.GlobalUint(KeyStoreArgon2TimeFlag.Name))
+	}
+	if ctx.GlobalIsSet(KeyStoreArgon2MemoryFlag.Name) {
+		cfg.Argon2Memory = uint32(ctx.GlobalUint(KeyStoreArgon2MemoryFlag.Name))
+	}
+	if ctx.GlobalIsSet(KeyStoreArgon2ThreadsFlag.Name) {
+		threads := ctx.GlobalUint(KeyStoreArgon2ThreadsFlag.Name)
+		if threads > 255 {
+			log.Fatalf("Option %q must be at most 255", KeyStoreArgon2ThreadsFlag.Name)
+		}
+		cfg.Argon2Threads = uint8(threads)
+	}
+}
+
+// setKMS applies the KMS flags to the config.
+func setKMS(ctx *cli.Context, cfg *kms.Config) {
+	if ctx.GlobalIsSet(KMSAWSRegionFlag.Name) {
+		cfg.AWSRegion = ctx.GlobalString(KMSAWSRegionFlag.Name)
+	}
+	if ctx.GlobalIsSet(KMSAWSEndpointFlag.Name) {
+		cfg.AWSEndpoint = ctx.GlobalString(KMSAWSEndpointFlag.Name)
+	}
+	if ctx.GlobalIsSet(KMSAWSAliasPrefixFlag.Name) {
+		cfg.AWSAliasPrefix = ctx.GlobalString(KMSAWSAliasPrefixFlag.Name)
+	}
+	if ctx.GlobalIsSet(KMSGCPKeyRingFlag.Name) {
+		cfg.GCPKeyRing = ctx.GlobalString(KMSGCPKeyRingFlag.Name)
+	}
+	if ctx.GlobalIsSet(KMSConsensusKeyFlag.Name) {
+		cfg.ConsensusKey = ctx.GlobalString(KMSConsensusKeyFlag.Name)
+	}
+}
+
+// setVault applies the Vault flags to the config.
+func setVault(ctx *cli.Context, cfg *vault.Config) {
+	if ctx.GlobalIsSet(VaultAddrFlag.Name) {
+		cfg.Address = ctx.GlobalString(VaultAddrFlag.Name)
+	}
+	if ctx.GlobalIsSet(VaultMountFlag.Name) {
+		cfg.Mount = ctx.GlobalString(VaultMountFlag.Name)
+	}
+	if ctx.GlobalIsSet(VaultPathFlag.Name) {
+		cfg.Path = ctx.GlobalString(VaultPathFlag.Name)
+	}
+	if ctx.GlobalIsSet(VaultTokenFileFlag.Name) {
+		cfg.TokenFile = ctx.GlobalString(VaultTokenFileFlag.Name)
+	}
+	if ctx.GlobalIsSet(VaultAppRoleMountFlag.Name) {
+		cfg.AppRoleMount = ctx.GlobalString(VaultAppRoleMountFlag.Name)
+	}
+	if ctx.GlobalIsSet(VaultAppRoleIDFlag.Name) {
+		cfg.AppRoleID = ctx.GlobalString(VaultAppRoleIDFlag.Name)
+	}
+	if ctx.GlobalIsSet(VaultAppRoleSecretIDFileFlag.Name) {
+		cfg.AppRoleSecretIDFile = ctx.GlobalString(VaultAppRoleSecretIDFileFlag.Name)
+	}
+}
+
+// setRemoteSigner applies the remote signer flags to the config.
+func setRemoteSigner(ctx *cli.Context, cfg *remote.Config) {
+	if ctx.GlobalIsSet(RemoteSignerURLFlag.Name) {
+		cfg.URL = ctx.GlobalString(RemoteSignerURLFlag.Name)
+	}
+	if ctx.GlobalIsSet(RemoteSignerAttestationKeyFlag.Name) {
+		cfg.AttestationKey = ctx.GlobalString(RemoteSignerAttestationKeyFlag.Name)
+	}
+	if ctx.GlobalIsSet(RemoteSignerCAFileFlag.Name) {
+		cfg.CAFile = ctx.GlobalString(RemoteSignerCAFileFlag.Name)
+	}
+	if ctx.GlobalIsSet(RemoteSignerCertFileFlag.Name) {
+		cfg.CertFile = ctx.GlobalString(RemoteSignerCertFileFlag.Name)
+	}
+	if ctx.GlobalIsSet(RemoteSignerKeyFileFlag.Name) {
+		cfg.KeyFile = ctx.GlobalString(RemoteSignerKeyFileFlag.Name)
+	}
+	if ctx.GlobalIsSet(RemoteSignerConsensusKeyFlag.Name) {
+		cfg.ConsensusKey = ctx.GlobalString(RemoteSignerConsensusKeyFlag.Name)
+	}
+}
+
+// setPKCS11 applies the PKCS#11 flags to the config.
+func setPKCS11(ctx *cli.Context, cfg *pkcs11.Config) {
+	if ctx.GlobalIsSet(PKCS11ModuleFlag.Name) {
+		cfg.Module = ctx.GlobalString(PKCS11ModuleFlag.Name)
+	}
+	if ctx.GlobalIsSet(PKCS11SlotFlag.Name) {
+		cfg.Slot = ctx.GlobalUint(PKCS11SlotFlag.Name)
+	}
+	if ctx.GlobalIsSet(PKCS11TokenLabelFlag.Name) {
+		cfg.TokenLabel = ctx.GlobalString(PKCS11TokenLabelFlag.Name)
+	}
+	if ctx.GlobalIsSet(PKCS11PINFileFlag.Name) {
+		cfg.PINFile = ctx.GlobalString(PKCS11PINFileFlag.Name)
+	}
+	if ctx.GlobalIsSet(PKCS11ConsensusKeyFlag.Name) {
+		cfg.ConsensusKey = ctx.GlobalString(PKCS11ConsensusKeyFlag.Name)
+	}
+}
+
 func setTxPool(ctx *cli.Context, cfg *blockchain.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolNoLocalsFlag.Name) {
 		cfg.NoLocals = ctx.GlobalBool(TxPoolNoLocalsFlag.Name)
@@ -1596,6 +2535,21 @@ func SetKlayConfig(ctx *cli.Context, stack *node.Node, cfg *cn.Config) {
 			cfg.SnapshotCacheSize = 0 // Disabled
 		}
 	}
+	cfg.SyncPivotDistance = ctx.GlobalUint64(SyncPivotDistanceFlag.Name)
+	cfg.SyncPivotPeers = ctx.GlobalInt(SyncPivotPeersFlag.Name)
+	if file := ctx.GlobalString(SyncCheckpointsFlag.Name); file != "" {
+		checkpoints, err := downloader.LoadCheckpoints(file)
+		if err != nil {
+			log.Fatalf("Failed to load the trusted checkpoints: %v", err)
+		}
+		cfg.SyncCheckpoints = checkpoints
+	}
+	if ctx.GlobalIsSet(SyncFromFlag.Name) {
+		if cfg.SyncMode != downloader.FullSync {
+			log.Fatalf("--%s is only supported with --%s full", SyncFromFlag.Name, SyncModeFlag.Name)
+		}
+		cfg.SyncFrom = ctx.GlobalUint64(SyncFromFlag.Name)
+	}
 
 	if ctx.GlobalBool(KESNodeTypeServiceFlag.Name) {
 		cfg.FetcherDisable = true
@@ -1616,10 +2570,23 @@ func SetKlayConfig(ctx *cli.Context, stack *node.Node, cfg *cn.Config) {
 		log.Fatalf("%v should be power of 2 but %v is not!", NumStateTrieShardsFlag.Name, cfg.NumStateTrieShards)
 	}
 
+	cfg.Istanbul.HeaderOnlyVerification = ctx.GlobalBool(IstanbulHeaderOnlyVerificationFlag.Name)
+	if cfg.Istanbul.HeaderOnlyVerification {
+		logger.Warn("Header-only verification is enabled. The votes and the staking changes between council snapshots are not replayed")
+	}
+
 	cfg.OverwriteGenesis = ctx.GlobalBool(OverwriteGenesisFlag.Name)
+
+	setHealthConfig(ctx, &cfg.Health)
+	setMultisigConfig(ctx, &cfg.Multisig)
 	cfg.StartBlockNumber = ctx.GlobalUint64(StartBlockNumberFlag.Name)
 
 	cfg.LevelDBCompression = database.LevelDBCompressionType(ctx.GlobalInt(LevelDBCompressionTypeFlag.Name))
+	cfg.DBCompression = ctx.GlobalString(DBCompressionFlag.Name)
+	if _, err := database.ParseDBCompression(cfg.DBCompression); err != nil {
+		log.Fatalf("Invalid %v: %v", DBCompressionFlag.Name, err)
+	}
+	cfg.DBEncryptionKey = ctx.GlobalString(DBEncryptionKeyFlag.Name)
 	cfg.LevelDBBufferPool = !ctx.GlobalIsSet(LevelDBNoBufferPoolFlag.Name)
 	cfg.EnableDBPerfMetrics = !ctx.GlobalIsSet(DBNoPerformanceMetricsFlag.Name)
 	cfg.LevelDBCacheSize = ctx.GlobalInt(LevelDBCacheSizeFlag.Name)
@@ -1630,6 +2597,9 @@ func SetKlayConfig(ctx *cli.Context, stack *node.Node, cfg *cn.Config) {
 	cfg.DynamoDBConfig.ReadCapacityUnits = ctx.GlobalInt64(DynamoDBReadCapacityFlag.Name)
 	cfg.DynamoDBConfig.WriteCapacityUnits = ctx.GlobalInt64(DynamoDBWriteCapacityFlag.Name)
 	cfg.DynamoDBConfig.ReadOnly = ctx.GlobalBool(DynamoDBReadOnlyFlag.Name)
+	cfg.DynamoDBConfig.WriteWorkers = ctx.GlobalInt(DynamoDBWriteWorkersFlag.Name)
+	setColdStorage(ctx, &cfg.ColdStorageConfig)
+	setAncientSource(ctx, &cfg.AncientSourceConfig)
 
 	if gcmode := ctx.GlobalString(GCModeFlag.Name); gcmode != "full" && gcmode != "archive" {
 		log.Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
@@ -1644,6 +2614,7 @@ func SetKlayConfig(ctx *cli.Context, stack *node.Node, cfg *cn.Config) {
 	common.DefaultCacheType = common.CacheType(ctx.GlobalInt(CacheTypeFlag.Name))
 	cfg.TrieBlockInterval = ctx.GlobalUint(TrieBlockIntervalFlag.Name)
 	cfg.TriesInMemory = ctx.GlobalUint64(TriesInMemoryFlag.Name)
+	cfg.TrieNodeRefCount = ctx.GlobalBool(TrieNodeRefCountFlag.Name)
 
 	if ctx.GlobalIsSet(CacheScaleFlag.Name) {
 		common.CacheScale = ctx.GlobalInt(CacheScaleFlag.Name)
@@ -1672,6 +2643,7 @@ func SetKlayConfig(ctx *cli.Context, stack *node.Node, cfg *cn.Config) {
 	}
 
 	cfg.SenderTxHashIndexing = ctx.GlobalIsSet(SenderTxHashIndexingFlag.Name)
+	cfg.SkipIntegrityCheck = ctx.GlobalIsSet(SkipIntegrityCheckFlag.Name)
 	cfg.ParallelDBWrite = !ctx.GlobalIsSet(NoParallelDBWriteFlag.Name)
 	cfg.TrieNodeCacheConfig = statedb.TrieNodeCacheConfig{
 		CacheType: statedb.TrieNodeCacheType(ctx.GlobalString(TrieNodeCacheTypeFlag.
@@ -1745,6 +2717,18 @@ func SetKlayConfig(ctx *cli.Context, stack *node.Node, cfg *cn.Config) {
 	*/
 	// Set the Tx resending related configuration variables
 	setTxResendConfig(ctx, cfg)
+
+	cfg.SentryMode = ctx.GlobalIsSet(SentryNodesFlag.Name)
+	cfg.ConsensusRelay = ctx.GlobalBool(SentryRelayFlag.Name)
+	if urls := ctx.GlobalString(ProxyUpstreamsFlag.Name); urls != "" {
+		for _, url := range splitAndTrim(urls) {
+			node, err := discover.ParseNode(url)
+			if err != nil {
+				log.Fatalf("Option %q: invalid upstream %q: %v", ProxyUpstreamsFlag.Name, url, err)
+			}
+			cfg.ProxyUpstreams = append(cfg.ProxyUpstreams, node)
+		}
+	}
 }
 
 func MakeGenesis(ctx *cli.Context) *blockchain.Genesis {
@@ -1793,6 +2777,41 @@ func RegisterService(stack *node.Node, cfg *sc.SCConfig) {
 	}
 }
 
+// setHealthConfig applies the health endpoint flags to the configuration.
+func setHealthConfig(ctx *cli.Context, cfg *cn.HealthConfig) {
+	if ctx.GlobalIsSet(HealthEnabledFlag.Name) {
+		cfg.Enabled = ctx.GlobalBool(HealthEnabledFlag.Name)
+	}
+	if ctx.GlobalIsSet(HealthListenAddrFlag.Name) {
+		cfg.Host = ctx.GlobalString(HealthListenAddrFlag.Name)
+	}
+	if ctx.GlobalIsSet(HealthPortFlag.Name) {
+		cfg.Port = ctx.GlobalInt(HealthPortFlag.Name)
+	}
+	if ctx.GlobalIsSet(HealthMinPeersFlag.Name) {
+		cfg.MinPeers = ctx.GlobalInt(HealthMinPeersFlag.Name)
+	}
+	if ctx.GlobalIsSet(HealthMaxBlockAgeFlag.Name) {
+		cfg.MaxBlockAge = ctx.GlobalDuration(HealthMaxBlockAgeFlag.Name)
+	}
+	if ctx.GlobalIsSet(HealthAllowSyncingFlag.Name) {
+		cfg.AllowSyncing = ctx.GlobalBool(HealthAllowSyncingFlag.Name)
+	}
+}
+
+// setMultisigConfig applies the multisig coordinator flags to the configuration.
+func setMultisigConfig(ctx *cli.Context, cfg *cn.MultisigConfig) {
+	if ctx.GlobalIsSet(MultisigEnabledFlag.Name) {
+		cfg.Enabled = ctx.GlobalBool(MultisigEnabledFlag.Name)
+	}
+	if ctx.GlobalIsSet(MultisigLifetimeFlag.Name) {
+		cfg.Lifetime = ctx.GlobalDuration(MultisigLifetimeFlag.Name)
+	}
+	if ctx.GlobalIsSet(MultisigMaxProposalsFlag.Name) {
+		cfg.MaxProposals = ctx.GlobalInt(MultisigMaxProposalsFlag.Name)
+	}
+}
+
 // RegisterChainDataFetcherService adds a ChainDataFetcher to the stack
 func RegisterChainDataFetcherService(stack *node.Node, cfg *chaindatafetcher.ChainDataFetcherConfig) {
 	if cfg.EnabledChainDataFetcher {
@@ -1852,11 +2871,15 @@ func MakeConsolePreloads(ctx *cli.Context) []string {
 //
 // ken --keystore /tmp/mykeystore --lightkdf account new
 //
-// This allows the use of the existing configuration functionality.
+// This allows the use of the existing configuration functionality. The command
+// flags given by the environment variables are migrated as well.
 // When all flags are migrated this function can be removed and the existing
 // configuration functionality must be changed that is uses local flags
 func MigrateFlags(action func(ctx *cli.Context) error) func(*cli.Context) error {
 	return func(ctx *cli.Context) error {
+		if err := SetFlagsFromEnv(ctx); err != nil {
+			return err
+		}
 		for _, name := range ctx.FlagNames() {
 			if ctx.IsSet(name) {
 				ctx.GlobalSet(name, ctx.String(name))
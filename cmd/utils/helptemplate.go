@@ -36,7 +36,14 @@ COMMANDS:
 {{range .FlagGroups}}{{.Name}} OPTIONS:
   {{range .Flags}}{{.}}
   {{end}}
-{{end}}{{end}}{{if .App.Copyright }}
+{{end}}{{end}}
+ENVIRONMENT:
+   Each option can be given by the environment variable of its name in upper case
+   with the prefix KLAYTN_ and '.' and '-' replaced by '_', e.g. KLAYTN_RPCAPI for
+   --rpcapi. The values of the list options are separated by commas. An option on
+   the command line has precedence over its environment variable, which has
+   precedence over the configuration file.
+{{if .App.Copyright }}
 COPYRIGHT:
    {{.App.Copyright}}
    {{end}}
@@ -0,0 +1,420 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/accounts/keystore"
+	"github.com/klaytn/klaytn/api/debug"
+	"github.com/klaytn/klaytn/cmd/utils"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/log"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	accountImportBatchCommand = cli.Command{
+		Name:      "import-batch",
+		Usage:     "Import the keys in a directory into new accounts",
+		Action:    utils.MigrateFlags(accountImportBatch),
+		ArgsUsage: "<dir>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.KeyStoreDirFlag,
+			utils.PasswordFileFlag,
+			utils.SourcePasswordFileFlag,
+			utils.MnemonicAccountsFlag,
+			utils.BatchWorkersFlag,
+			utils.DryRunFlag,
+			utils.LightKDFFlag,
+			utils.KeyStoreKDFFlag,
+			utils.KeyStoreScryptNFlag,
+			utils.KeyStoreScryptRFlag,
+			utils.KeyStoreScryptPFlag,
+			utils.KeyStoreArgon2TimeFlag,
+			utils.KeyStoreArgon2MemoryFlag,
+			utils.KeyStoreArgon2ThreadsFlag,
+		},
+		Description: `
+    klay account import-batch [options] <dir>
+
+Imports every key file in <dir> into new accounts, and prints the address and
+the result of each key. The format of each file is detected from its content:
+
+  - a keystore file of the v3 or v4 format, decrypted by the passwords of the
+    --srcpassword file, which are tried in turn
+  - an unencrypted private key in hexadecimal format
+  - a BIP-39 mnemonic, from which --mnemonic.accounts accounts are derived on
+    m/44'/8217'/0'/0/<index> without a mnemonic passphrase
+
+The accounts are saved in encrypted format with the passphrase given by the
+--password flag or prompted once for all of them. The keys of the accounts
+already in the keystore are skipped. The keys are encrypted in parallel by
+--workers goroutines.
+
+With --dryrun, the keys are only read and the addresses are printed, which
+should be checked especially for the mnemonics since their words are not
+validated against a word list.
+`,
+	}
+	accountExportBatchCommand = cli.Command{
+		Name:      "export-batch",
+		Usage:     "Export the accounts into keystore files in a directory",
+		Action:    utils.MigrateFlags(accountExportBatch),
+		ArgsUsage: "<dir> [<address>...]",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.KeyStoreDirFlag,
+			utils.PasswordFileFlag,
+			utils.SourcePasswordFileFlag,
+			utils.KeystoreV3Flag,
+			utils.BatchWorkersFlag,
+			utils.DryRunFlag,
+			utils.LightKDFFlag,
+			utils.KeyStoreKDFFlag,
+			utils.KeyStoreScryptNFlag,
+			utils.KeyStoreScryptRFlag,
+			utils.KeyStoreScryptPFlag,
+			utils.KeyStoreArgon2TimeFlag,
+			utils.KeyStoreArgon2MemoryFlag,
+			utils.KeyStoreArgon2ThreadsFlag,
+		},
+		Description: `
+    klay account export-batch [options] <dir> [<address>...]
+
+Exports the given accounts, or all accounts in the keystore, into encrypted
+keystore files named <address>.json in <dir>. Existing files are not overwritten.
+
+Each account is unlocked by the passwords of the --srcpassword file, which are
+tried in turn, or by a passphrase prompted once for all of them. The keys are
+encrypted with the passphrase given by the --password flag, or with the
+passphrase which unlocked them if it is not given.
+
+The keys are exported in the keystore v4 format of Klaytn by default. With
+--keystore.v3, they are exported in the v3 format readable by Ethereum tools,
+and the accounts with multiple keys are reported as failures.
+
+With --dryrun, the accounts are only unlocked and the file names are printed.
+`,
+	}
+)
+
+// batchKey is a key read by import-batch, or the failure to read it.
+type batchKey struct {
+	source string
+	key    keystore.Key
+	err    error
+}
+
+// batchResult is the result of a key processed by a batch command.
+type batchResult struct {
+	source  string
+	address common.Address
+	message string
+	err     error
+}
+
+func accountImportBatch(ctx *cli.Context) error {
+	if glogger, err := debug.GetGlogger(); err == nil {
+		log.ChangeGlobalLogLevel(glogger, log.Lvl(log.LvlError))
+	}
+	if ctx.NArg() != 1 {
+		log.Fatalf("The directory of the keys must be given as argument")
+	}
+	files, err := batchKeyFiles(ctx.Args().First())
+	if err != nil {
+		log.Fatalf("Failed to read the key directory: %v", err)
+	}
+	var (
+		srcPasswords = utils.MakeSourcePasswordList(ctx)
+		mnemonicAccs = ctx.GlobalInt(utils.MnemonicAccountsFlag.Name)
+		dryRun       = ctx.GlobalBool(utils.DryRunFlag.Name)
+	)
+	if mnemonicAccs < 1 {
+		log.Fatalf("--%s must be positive", utils.MnemonicAccountsFlag.Name)
+	}
+
+	stack, _ := makeConfigNode(ctx)
+	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	var passphrase string
+	if !dryRun {
+		passphrase = getPassPhrase("The new accounts are locked with a password. Please give a password. Do not forget this password.", true, 0, utils.MakePasswordList(ctx))
+	}
+
+	// Read the files in parallel since the keystore files are decrypted by scrypt.
+	keys := make([][]batchKey, len(files))
+	runBatch(ctx, len(files), func(i int) {
+		keys[i] = readBatchKeys(files[i], srcPasswords, mnemonicAccs)
+	})
+	var flat []batchKey
+	for _, k := range keys {
+		flat = append(flat, k...)
+	}
+
+	var (
+		seen    = make(map[common.Address]bool)
+		results = make([]batchResult, len(flat))
+	)
+	for i, k := range flat {
+		results[i] = batchResult{source: k.source, err: k.err}
+		if k.err != nil {
+			continue
+		}
+		addr := k.key.GetAddress()
+		results[i].address = addr
+		switch {
+		case seen[addr]:
+			results[i].err = errors.New("duplicated in the batch")
+		case ks.HasAddress(addr):
+			results[i].message = "skipped, already in the keystore"
+		case dryRun:
+			results[i].message = "would be imported"
+		}
+		seen[addr] = true
+	}
+	runBatch(ctx, len(flat), func(i int) {
+		if results[i].err != nil || results[i].message != "" {
+			return
+		}
+		acct, err := ks.ImportKey(flat[i].key, passphrase)
+		if err != nil {
+			results[i].err = err
+			return
+		}
+		results[i].message = "imported at " + acct.URL.Path
+	})
+	for _, k := range flat {
+		if k.key != nil {
+			k.key.ResetPrivateKey()
+		}
+	}
+	return printBatchResults(results)
+}
+
+func accountExportBatch(ctx *cli.Context) error {
+	if glogger, err := debug.GetGlogger(); err == nil {
+		log.ChangeGlobalLogLevel(glogger, log.Lvl(log.LvlError))
+	}
+	if ctx.NArg() < 1 {
+		log.Fatalf("The directory of the exported keys must be given as argument")
+	}
+	dir := ctx.Args().First()
+	var (
+		srcPasswords = utils.MakeSourcePasswordList(ctx)
+		newPasswords = utils.MakePasswordList(ctx)
+		v3           = ctx.GlobalBool(utils.KeystoreV3Flag.Name)
+		dryRun       = ctx.GlobalBool(utils.DryRunFlag.Name)
+	)
+	stack, _ := makeConfigNode(ctx)
+	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+
+	accs := ks.Accounts()
+	if ctx.NArg() > 1 {
+		accs = nil
+		for _, addr := range ctx.Args()[1:] {
+			acct, err := utils.MakeAddress(ks, addr)
+			if err != nil {
+				log.Fatalf("Could not find the account %s: %v", addr, err)
+			}
+			accs = append(accs, acct)
+		}
+	}
+	if len(srcPasswords) == 0 {
+		srcPasswords = []string{getPassPhrase("Please give the password of the exported accounts.", false, 0, nil)}
+	}
+	var newPassphrase *string
+	if !dryRun && len(newPasswords) > 0 {
+		newPassphrase = &newPasswords[0]
+	}
+	if !dryRun {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			log.Fatalf("Failed to create the directory: %v", err)
+		}
+	}
+
+	results := make([]batchResult, len(accs))
+	runBatch(ctx, len(accs), func(i int) {
+		acct := accs[i]
+		path := filepath.Join(dir, hex.EncodeToString(acct.Address.Bytes())+".json")
+		results[i] = batchResult{source: acct.URL.Path, address: acct.Address}
+
+		var keyJSON []byte
+		err := keystore.ErrDecrypt
+		for _, password := range srcPasswords {
+			to := password
+			if newPassphrase != nil {
+				to = *newPassphrase
+			}
+			if v3 {
+				keyJSON, err = ks.ExportV3(acct, password, to)
+			} else {
+				keyJSON, err = ks.Export(acct, password, to)
+			}
+			if err != keystore.ErrDecrypt {
+				break
+			}
+		}
+		if err != nil {
+			results[i].err = err
+			return
+		}
+		if dryRun {
+			results[i].message = "would be exported to " + path
+			return
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			results[i].err = err
+			return
+		}
+		if _, err := f.Write(keyJSON); err != nil {
+			f.Close()
+			results[i].err = err
+			return
+		}
+		if err := f.Close(); err != nil {
+			results[i].err = err
+			return
+		}
+		results[i].message = "exported to " + path
+	})
+	return printBatchResults(results)
+}
+
+// batchKeyFiles returns the regular files in the directory, skipping the hidden
+// ones and the editor backups like the keystore does.
+func batchKeyFiles(dir string) ([]string, error) {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, fi := range fis {
+		name := fi.Name()
+		if !fi.Mode().IsRegular() || strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") {
+			continue
+		}
+		files = append(files, filepath.Join(dir, name))
+	}
+	return files, nil
+}
+
+// readBatchKeys reads the keys in the file, detecting its format.
+func readBatchKeys(file string, srcPasswords []string, mnemonicAccs int) []batchKey {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return []batchKey{{source: file, err: err}}
+	}
+	content = bytes.TrimSpace(content)
+
+	// Keystore files of the v3 or v4 format.
+	if bytes.HasPrefix(content, []byte("{")) {
+		err := keystore.ErrDecrypt
+		for _, password := range srcPasswords {
+			var key keystore.Key
+			if key, err = keystore.DecryptKey(content, password); err == nil {
+				return []batchKey{{source: file, key: key}}
+			}
+			if err != keystore.ErrDecrypt {
+				break
+			}
+		}
+		return []batchKey{{source: file, err: err}}
+	}
+	// Unencrypted private keys.
+	if hexkey := strings.TrimPrefix(string(content), "0x"); len(hexkey) == 64 {
+		if priv, err := crypto.HexToECDSA(hexkey); err == nil {
+			return []batchKey{{source: file, key: keystore.NewKeyFromECDSA(priv)}}
+		}
+	}
+	// Mnemonics.
+	seed, err := accounts.NewSeedFromMnemonic(string(content), "")
+	if err != nil {
+		return []batchKey{{source: file, err: fmt.Errorf("unknown key format: %v", err)}}
+	}
+	keys := make([]batchKey, mnemonicAccs)
+	for i := range keys {
+		path := make(accounts.DerivationPath, len(accounts.DefaultBaseDerivationPath))
+		copy(path, accounts.DefaultBaseDerivationPath)
+		path[len(path)-1] = uint32(i)
+
+		keys[i].source = file + " " + path.String()
+		priv, err := accounts.DeriveKey(seed, path)
+		if err != nil {
+			keys[i].err = err
+			continue
+		}
+		keys[i].key = keystore.NewKeyFromECDSA(priv)
+	}
+	return keys
+}
+
+// runBatch runs fn for the indexes up to n by the workers of --workers.
+func runBatch(ctx *cli.Context, n int, fn func(i int)) {
+	workers := ctx.GlobalInt(utils.BatchWorkersFlag.Name)
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	var (
+		wg   sync.WaitGroup
+		jobs = make(chan int)
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// printBatchResults prints the result of each key, and returns an error if any
+// of them failed.
+func printBatchResults(results []batchResult) error {
+	var failed int
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+			fmt.Printf("%s: failed: %v\n", r.source, r.err)
+			continue
+		}
+		fmt.Printf("%s: {%x} %s\n", r.source, r.address, r.message)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d keys failed", failed, len(results))
+	}
+	return nil
+}
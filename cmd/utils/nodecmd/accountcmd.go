@@ -40,7 +40,8 @@ var AccountCommand = cli.Command{
 	Description: `
 
 Manage accounts, list all existing accounts, import a private key into a new
-account, create a new account or update an existing account.
+account, create a new account or update an existing account. The accounts can
+also be imported or exported in batches for migrations.
 
 It supports interactive mode, when you are prompted for password as well as
 non-interactive mode where passwords are supplied via a given password file.
@@ -78,6 +79,13 @@ Print a short summary of all accounts`,
 				utils.KeyStoreDirFlag,
 				utils.PasswordFileFlag,
 				utils.LightKDFFlag,
+				utils.KeyStoreKDFFlag,
+				utils.KeyStoreScryptNFlag,
+				utils.KeyStoreScryptRFlag,
+				utils.KeyStoreScryptPFlag,
+				utils.KeyStoreArgon2TimeFlag,
+				utils.KeyStoreArgon2MemoryFlag,
+				utils.KeyStoreArgon2ThreadsFlag,
 			},
 			Description: `
     klay account new
@@ -103,6 +111,13 @@ password to file or expose in any other way.
 				utils.DataDirFlag,
 				utils.KeyStoreDirFlag,
 				utils.LightKDFFlag,
+				utils.KeyStoreKDFFlag,
+				utils.KeyStoreScryptNFlag,
+				utils.KeyStoreScryptRFlag,
+				utils.KeyStoreScryptPFlag,
+				utils.KeyStoreArgon2TimeFlag,
+				utils.KeyStoreArgon2MemoryFlag,
+				utils.KeyStoreArgon2ThreadsFlag,
 			},
 			Description: `
     klay account update <address>
@@ -132,6 +147,13 @@ changing your password is only possible interactively.
 				utils.KeyStoreDirFlag,
 				utils.PasswordFileFlag,
 				utils.LightKDFFlag,
+				utils.KeyStoreKDFFlag,
+				utils.KeyStoreScryptNFlag,
+				utils.KeyStoreScryptRFlag,
+				utils.KeyStoreScryptPFlag,
+				utils.KeyStoreArgon2TimeFlag,
+				utils.KeyStoreArgon2MemoryFlag,
+				utils.KeyStoreArgon2ThreadsFlag,
 			},
 			ArgsUsage: "<keyFile>",
 			Description: `
@@ -156,6 +178,8 @@ this import mechanism is not needed when you transfer an account between
 nodes.
 `,
 		},
+		accountImportBatchCommand,
+		accountExportBatchCommand,
 	},
 }
 
@@ -272,14 +296,14 @@ func accountCreate(ctx *cli.Context) error {
 		}
 	}
 	utils.SetNodeConfig(ctx, &cfg.Node)
-	scryptN, scryptP, keydir, err := cfg.Node.AccountConfig()
+	kdf, keydir, err := cfg.Node.AccountConfig()
 	if err != nil {
 		log.Fatalf("Failed to read configuration: %v", err)
 	}
 
 	password := getPassPhrase("Your new account is locked with a password. Please give a password. Do not forget this password.", true, 0, utils.MakePasswordList(ctx))
 
-	address, err := keystore.StoreKey(keydir, password, scryptN, scryptP)
+	address, err := keystore.StoreKeyWithKDF(keydir, password, kdf)
 	if err != nil {
 		log.Fatalf("Failed to create account: %v", err)
 	}
@@ -0,0 +1,110 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/klaytn/klaytn/cmd/utils"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/node"
+	"github.com/klaytn/klaytn/node/sc"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var BridgeCommand = cli.Command{
+	Name:     "bridge",
+	Usage:    "Service chain bridge maintenance commands",
+	Category: "BRIDGE COMMANDS",
+	Subcommands: []cli.Command{
+		{
+			Name:      "reconcile",
+			Usage:     "Reconcile the value transfers of a bridge pair",
+			ArgsUsage: "<child bridge address> <parent bridge address>",
+			Action:    utils.MigrateFlags(reconcileBridge),
+			Flags: []cli.Flag{
+				utils.DataDirFlag,
+				utils.BridgeReconcileEndpointFlag,
+				utils.BridgeReconcileChildFromFlag,
+				utils.BridgeReconcileChildToFlag,
+				utils.BridgeReconcileParentFromFlag,
+				utils.BridgeReconcileParentToFlag,
+				utils.BridgeReconcileResubmitFlag,
+			},
+			Description: `
+The reconcile command asks the running service chain node to re-scan the value
+transfer request events of both bridges of the pair over the given block ranges,
+and reports the requests whose nonces are not handled by the counterpart bridge.
+
+With --reconcile.resubmit, the node submits the handle transactions of the
+unhandled requests again, signed by the bridge operators.
+
+The bridge pair should be subscribed by the node.`,
+		},
+	},
+}
+
+func reconcileBridge(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return fmt.Errorf("need the child and the parent bridge addresses")
+	}
+	for _, addr := range ctx.Args() {
+		if !common.IsHexAddress(addr) {
+			return fmt.Errorf("invalid bridge address: %v", addr)
+		}
+	}
+	endpoint := ctx.GlobalString(utils.BridgeReconcileEndpointFlag.Name)
+	if endpoint == "" {
+		path := node.DefaultDataDir()
+		if ctx.GlobalIsSet(utils.DataDirFlag.Name) {
+			path = ctx.GlobalString(utils.DataDirFlag.Name)
+		}
+		endpoint = filepath.Join(path, "klay.ipc")
+	}
+	client, err := dialRPC(endpoint)
+	if err != nil {
+		return fmt.Errorf("unable to attach to the node: %v", err)
+	}
+	defer client.Close()
+
+	args := sc.ReconcileArgs{
+		ChildFrom:  ctx.GlobalUint64(utils.BridgeReconcileChildFromFlag.Name),
+		ChildTo:    ctx.GlobalUint64(utils.BridgeReconcileChildToFlag.Name),
+		ParentFrom: ctx.GlobalUint64(utils.BridgeReconcileParentFromFlag.Name),
+		ParentTo:   ctx.GlobalUint64(utils.BridgeReconcileParentToFlag.Name),
+		Resubmit:   ctx.GlobalBool(utils.BridgeReconcileResubmitFlag.Name),
+	}
+	var report sc.ReconcileReport
+	if err := client.CallContext(context.Background(), &report, "subbridge_reconcileBridge",
+		common.HexToAddress(ctx.Args().Get(0)), common.HexToAddress(ctx.Args().Get(1)), args); err != nil {
+		return err
+	}
+
+	enc, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(enc))
+
+	if n := len(report.ChildToParent.UnhandledRequests) + len(report.ParentToChild.UnhandledRequests); n > 0 {
+		logger.Warn("Found unhandled value transfer requests", "count", n, "resubmitted", args.Resubmit)
+	}
+	return nil
+}
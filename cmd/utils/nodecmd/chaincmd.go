@@ -23,12 +23,14 @@ package nodecmd
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"strings"
 
 	"github.com/klaytn/klaytn/blockchain"
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/cmd/utils"
+	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/governance"
 	"github.com/klaytn/klaytn/log"
 	"github.com/klaytn/klaytn/params"
@@ -73,13 +75,18 @@ It expects the genesis file as argument.`,
 		Name:      "dumpgenesis",
 		Usage:     "Dumps genesis block JSON configuration to stdout",
 		ArgsUsage: "",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			utils.CypressFlag,
 			utils.BaobabFlag,
-		},
+		}, dbInspectFlags...),
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
-The dumpgenesis command dumps the genesis block configuration in JSON format to stdout.`,
+The dumpgenesis command dumps the genesis block configuration in JSON format to stdout.
+
+If --datadir is given, the genesis is reconstructed from the initialized database,
+including the chain config, the governance parameters and the allocated accounts,
+and it is checked to produce the genesis block stored in the database. The state
+of the genesis block must not be pruned.`,
 	}
 )
 
@@ -180,6 +187,9 @@ func initGenesis(ctx *cli.Context) error {
 }
 
 func dumpGenesis(ctx *cli.Context) error {
+	if ctx.GlobalIsSet(utils.DataDirFlag.Name) {
+		return dumpStoredGenesis(ctx)
+	}
 	genesis := utils.MakeGenesis(ctx)
 	if genesis == nil {
 		genesis = blockchain.DefaultGenesisBlock()
@@ -190,6 +200,31 @@ func dumpGenesis(ctx *cli.Context) error {
 	return nil
 }
 
+// dumpStoredGenesis reconstructs the genesis from the database in the data
+// directory, and dumps it in JSON format to stdout.
+func dumpStoredGenesis(ctx *cli.Context) error {
+	chainDB, err := openChainDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer chainDB.Close()
+
+	genesis, err := blockchain.ReadGenesis(chainDB)
+	if err != nil {
+		return err
+	}
+	stored := chainDB.ReadCanonicalHash(0)
+	if hash := genesis.ToBlock(common.Hash{}, nil).Hash(); hash != stored {
+		logger.Warn("The reconstructed genesis does not produce the stored genesis block", "stored", stored, "reconstructed", hash)
+	}
+	enc, err := json.MarshalIndent(genesis, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(enc))
+	return nil
+}
+
 func ValidateGenesisConfig(g *blockchain.Genesis) error {
 	if g.Config.ChainID == nil {
 		return errors.New("chainID is not specified")
@@ -108,20 +108,7 @@ func localConsole(ctx *cli.Context) error {
 // console to it.
 func remoteConsole(ctx *cli.Context) error {
 	// Attach to a remotely running node instance and start the JavaScript console
-	endpoint := ctx.Args().First()
-	if endpoint == "" {
-		path := node.DefaultDataDir()
-		if ctx.GlobalIsSet(utils.DataDirFlag.Name) {
-			path = ctx.GlobalString(utils.DataDirFlag.Name)
-		}
-		if path != "" {
-			if ctx.GlobalBool(utils.BaobabFlag.Name) {
-				path = filepath.Join(path, "baobab")
-			}
-		}
-		endpoint = fmt.Sprintf("%s/klay.ipc", path)
-	}
-	client, err := dialRPC(endpoint)
+	client, err := dialRPC(attachEndpoint(ctx))
 	if err != nil {
 		log.Fatalf("Unable to attach to remote node: %v", err)
 	}
@@ -150,6 +137,25 @@ func remoteConsole(ctx *cli.Context) error {
 	return nil
 }
 
+// attachEndpoint returns the endpoint given as the first argument, or the IPC
+// endpoint in the data directory if none is given.
+func attachEndpoint(ctx *cli.Context) string {
+	endpoint := ctx.Args().First()
+	if endpoint == "" {
+		path := node.DefaultDataDir()
+		if ctx.GlobalIsSet(utils.DataDirFlag.Name) {
+			path = ctx.GlobalString(utils.DataDirFlag.Name)
+		}
+		if path != "" {
+			if ctx.GlobalBool(utils.BaobabFlag.Name) {
+				path = filepath.Join(path, "baobab")
+			}
+		}
+		endpoint = fmt.Sprintf("%s/klay.ipc", path)
+	}
+	return endpoint
+}
+
 // dialRPC returns a RPC client which connects to the given endpoint.
 // The check for empty endpoint implements the defaulting logic
 // for "ken attach" and "ken monitor" with no argument.
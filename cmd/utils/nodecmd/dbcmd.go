@@ -0,0 +1,577 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/cmd/utils"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/node"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/klaytn/klaytn/storage/statedb"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var DBCommand = cli.Command{
+	Name:     "db",
+	Usage:    "Database maintenance commands",
+	Category: "DATABASE COMMANDS",
+	Subcommands: []cli.Command{
+		{
+			Name:   "verify",
+			Usage:  "Verify the consistency of the chain data",
+			Action: utils.MigrateFlags(verifyDB),
+			Flags: []cli.Flag{
+				utils.DbTypeFlag,
+				utils.SingleDBFlag,
+				utils.NumStateTrieShardsFlag,
+				utils.DynamoDBTableNameFlag,
+				utils.DynamoDBRegionFlag,
+				utils.DynamoDBIsProvisionedFlag,
+				utils.DynamoDBReadCapacityFlag,
+				utils.DynamoDBWriteCapacityFlag,
+				utils.LevelDBCompressionTypeFlag,
+				utils.DBCompressionFlag,
+				utils.DBEncryptionKeyFlag,
+				utils.DataDirFlag,
+				utils.DBVerifyFromFlag,
+				utils.DBVerifyToFlag,
+				utils.DBVerifyRepairFlag,
+			},
+			Description: `
+The verify command walks the canonical chain and checks that the headers, bodies,
+receipts and transaction lookup entries of every block are present and consistent
+with each other, and that the state of the head block is present.
+
+With --verify.repair, the transaction lookup entries are re-derived from the block
+bodies, and the head block is rewound before the first block whose body or receipts
+are missing or corrupt, so that the following blocks are fetched from peers again
+on the next start.
+
+Note: Do not verify the database while a node is executing.`,
+		},
+		{
+			Name:   "migrate",
+			Usage:  "Migrate the databases to another database backend",
+			Flags:  dbMigrationFlags,
+			Action: utils.MigrateFlags(startMigration),
+			Description: `
+The migrate command copies all the databases to the dst db given by --db.dst.type
+and the other --db.dst flags. It is the same as 'db-migration start'.
+
+Each db is copied by --db.migration.workers workers in parallel, and the copy rate
+can be limited by --db.migration.max-rate. If the migration is interrupted, starting
+it again with the same dst db and the same number of workers continues from the
+progress. With --db.migration.verify, the migrated items are compared with the src
+db after copying.
+
+Note: Do not migrate the database while a node is executing.`,
+		},
+		{
+			Name:   "reshard",
+			Usage:  "Reshard the state trie database into a new number of shards",
+			Action: utils.MigrateFlags(reshardDB),
+			Flags: []cli.Flag{
+				utils.DbTypeFlag,
+				utils.SingleDBFlag,
+				utils.NumStateTrieShardsFlag,
+				utils.LevelDBCompressionTypeFlag,
+				utils.DBCompressionFlag,
+				utils.DBEncryptionKeyFlag,
+				utils.DataDirFlag,
+				utils.DBReshardShardsFlag,
+			},
+			Description: `
+The reshard command copies the state trie database into a new database with the
+number of shards given by --reshard.shards, and replaces the old database with it.
+The number of shards of each database is recorded, so --db.num-statetrie-shards
+only applies to newly created databases afterwards.
+
+The progress is stored while copying. If the command is interrupted, running it
+again with the same number of shards continues from the progress.
+
+To reshard in the background while a node is running, use admin.reshardStateTrieDB
+in the console instead. It copies only the latest state, as the state migration does.
+
+Note: Do not reshard the database while a node is executing.`,
+		},
+		{
+			Name:   "refcount",
+			Usage:  "Build the reference counts of the trie nodes of an archive database",
+			Action: utils.MigrateFlags(refCountDB),
+			Flags: []cli.Flag{
+				utils.DbTypeFlag,
+				utils.SingleDBFlag,
+				utils.NumStateTrieShardsFlag,
+				utils.LevelDBCompressionTypeFlag,
+				utils.DBCompressionFlag,
+				utils.DBEncryptionKeyFlag,
+				utils.DataDirFlag,
+			},
+			Description: `
+The refcount command counts the references of the trie nodes reachable from the
+state roots of all the canonical blocks, which is required to run an archive node
+with --state.trie-refcount on an existing database. With the reference counts,
+the state of old blocks can be pruned by admin.pruneStateTries in the console.
+
+The progress is stored for every block. If the command is interrupted, running it
+again continues from the progress.
+
+Note: Do not run the command while a node is executing.`,
+		},
+		{
+			Name:   "stats",
+			Usage:  "Print the number and the size of the keys of each database by their kinds",
+			Action: utils.MigrateFlags(keyStatsDB),
+			Flags:  dbInspectFlags,
+			Description: `
+The stats command iterates all the keys of each database, and prints their number
+and total size grouped by their kinds in the database schema, such as header,
+receipts and trie-node. The keys not known by the schema are counted as unknown.
+It reads the whole databases, so it may take a long time on a large database.
+
+Note: Do not run the command while a node is executing.`,
+		},
+		{
+			Name:   "inspect",
+			Usage:  "Print the head block pointers, the version and the layout of the databases",
+			Action: utils.MigrateFlags(inspectDB),
+			Flags:  dbInspectFlags,
+			Description: `
+The inspect command prints the metadata of the databases in JSON, such as the head
+header, block and fast block with their numbers, the database schema version, the
+state migration in progress, the boundaries of the data moved to the cold storage,
+and the directory of each database.
+
+Note: Do not run the command while a node is executing.`,
+		},
+		{
+			Name:      "get",
+			Usage:     "Print the value of a key in a database",
+			ArgsUsage: "<database> <key>",
+			Action:    utils.MigrateFlags(getDBEntry),
+			Flags:     dbInspectFlags,
+			Description: `
+The get command prints the value of the key in the database, such as header, body,
+receipts, statetrie, txlookup or misc. The key and the value are given in hex with
+the 0x prefix, or as a string otherwise.
+
+The known keys are printed with their meaning and decoded values, such as the block
+number and hash of a header key and the decoded header.`,
+		},
+		{
+			Name:      "put",
+			Usage:     "Store the value of a key in a database",
+			ArgsUsage: "<database> <key> <value>",
+			Action:    utils.MigrateFlags(putDBEntry),
+			Flags:     append(dbInspectFlags, utils.DBUnsafeFlag),
+			Description: `
+The put command stores the value of the key in the database, and prints the value
+replaced by it. It is only for the emergency surgery of a broken database, and
+requires --i-know-what-i-am-doing.
+
+Note: Do not modify the database while a node is executing.`,
+		},
+		{
+			Name:      "delete",
+			Usage:     "Delete a key from a database",
+			ArgsUsage: "<database> <key>",
+			Action:    utils.MigrateFlags(deleteDBEntry),
+			Flags:     append(dbInspectFlags, utils.DBUnsafeFlag),
+			Description: `
+The delete command deletes the key from the database, and prints the deleted value.
+It is only for the emergency surgery of a broken database, and requires
+--i-know-what-i-am-doing.
+
+Note: Do not modify the database while a node is executing.`,
+		},
+		{
+			Name:      "iterate",
+			Usage:     "Print the entries of a database",
+			ArgsUsage: "<database> [<prefix> [<start>]]",
+			Action:    utils.MigrateFlags(iterateDB),
+			Flags:     append(dbInspectFlags, utils.DBIterateLimitFlag),
+			Description: `
+The iterate command prints the entries of the database whose keys have the prefix,
+starting from the key made of the prefix and the start, up to --iterate.limit entries.
+The entries of a sharded database are not sorted.`,
+		},
+	},
+}
+
+var dbInspectFlags = []cli.Flag{
+	utils.DbTypeFlag,
+	utils.SingleDBFlag,
+	utils.NumStateTrieShardsFlag,
+	utils.LevelDBCompressionTypeFlag,
+	utils.DBCompressionFlag,
+	utils.DBEncryptionKeyFlag,
+	utils.DataDirFlag,
+}
+
+func openChainDB(ctx *cli.Context) (database.DBManager, error) {
+	_, chainDB, err := openChainDBWithStack(ctx)
+	return chainDB, err
+}
+
+// openChainDBWithStack opens the chain database like openChainDB, and also
+// returns the node whose data directory has the database.
+func openChainDBWithStack(ctx *cli.Context) (*node.Node, database.DBManager, error) {
+	dbtype := database.DBType(ctx.GlobalString(utils.DbTypeFlag.Name)).ToValid()
+	if len(dbtype) == 0 {
+		return nil, nil, fmt.Errorf("invalid dbtype %q", ctx.GlobalString(utils.DbTypeFlag.Name))
+	}
+
+	var dynamoDBConfig *database.DynamoDBConfig
+	if dbtype == database.DynamoDB {
+		dynamoDBConfig = &database.DynamoDBConfig{
+			TableName:          ctx.GlobalString(utils.DynamoDBTableNameFlag.Name),
+			Region:             ctx.GlobalString(utils.DynamoDBRegionFlag.Name),
+			IsProvisioned:      ctx.GlobalBool(utils.DynamoDBIsProvisionedFlag.Name),
+			ReadCapacityUnits:  ctx.GlobalInt64(utils.DynamoDBReadCapacityFlag.Name),
+			WriteCapacityUnits: ctx.GlobalInt64(utils.DynamoDBWriteCapacityFlag.Name),
+		}
+	}
+
+	compression, err := database.ParseDBCompression(ctx.GlobalString(utils.DBCompressionFlag.Name))
+	if err != nil {
+		return nil, nil, err
+	}
+	encryptionKey, err := loadEncryptionKey(ctx.GlobalString(utils.DBEncryptionKeyFlag.Name))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stack := MakeFullNode(ctx)
+	return stack, stack.OpenDatabase(&database.DBConfig{
+		Dir: "chaindata", DBType: dbtype, SingleDB: ctx.GlobalIsSet(utils.SingleDBFlag.Name),
+		NumStateTrieShards: ctx.GlobalUint(utils.NumStateTrieShardsFlag.Name),
+		LevelDBCompression: database.LevelDBCompressionType(ctx.GlobalInt(utils.LevelDBCompressionTypeFlag.Name)),
+		Compression:        compression,
+		EncryptionKey:      encryptionKey,
+		DynamoDBConfig:     dynamoDBConfig,
+	}), nil
+}
+
+// loadEncryptionKey loads the database encryption key from the given source,
+// or returns nil if the source is not given.
+func loadEncryptionKey(source string) ([]byte, error) {
+	if source == "" {
+		return nil, nil
+	}
+	return database.LoadEncryptionKey(source)
+}
+
+func verifyDB(ctx *cli.Context) error {
+	chainDB, err := openChainDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer chainDB.Close()
+
+	result, err := blockchain.VerifyDatabase(chainDB, blockchain.DBVerifyConfig{
+		From:   ctx.GlobalUint64(utils.DBVerifyFromFlag.Name),
+		To:     ctx.GlobalUint64(utils.DBVerifyToFlag.Name),
+		Repair: ctx.GlobalBool(utils.DBVerifyRepairFlag.Name),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Checked %d blocks, found %d issues\n", result.Checked, len(result.Issues))
+	for _, issue := range result.Issues {
+		fmt.Println(" ", issue)
+	}
+	if result.RewoundTo != nil {
+		fmt.Printf("The head block is rewound to #%d. The following blocks will be fetched from peers on the next start.\n", *result.RewoundTo)
+		return nil
+	}
+	if n := result.Unrepaired(); n > 0 {
+		return fmt.Errorf("%d issues are not repaired", n)
+	}
+	return nil
+}
+
+func reshardDB(ctx *cli.Context) error {
+	if !ctx.GlobalIsSet(utils.DBReshardShardsFlag.Name) {
+		return fmt.Errorf("--%s is required", utils.DBReshardShardsFlag.Name)
+	}
+	chainDB, err := openChainDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer chainDB.Close()
+
+	quit := make(chan struct{})
+	go func() {
+		sigc := make(chan os.Signal, 1)
+		signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(sigc)
+		<-sigc
+		logger.Info("Got interrupt, stopping the resharding...")
+		close(quit)
+	}()
+
+	numShards := ctx.GlobalUint(utils.DBReshardShardsFlag.Name)
+	if err := chainDB.ReshardStateTrieDB(numShards, quit); err != nil {
+		if err == database.ErrReshardInterrupted {
+			fmt.Println("The resharding is interrupted. Run the command again with the same number of shards to continue.")
+		}
+		return err
+	}
+	fmt.Printf("The state trie database has %d shards\n", numShards)
+	return nil
+}
+
+func refCountDB(ctx *cli.Context) error {
+	chainDB, err := openChainDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer chainDB.Close()
+
+	head := chainDB.ReadHeaderNumber(chainDB.ReadHeadBlockHash())
+	if head == nil {
+		return errors.New("head block not found")
+	}
+
+	quit := make(chan struct{})
+	go func() {
+		sigc := make(chan os.Signal, 1)
+		signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(sigc)
+		<-sigc
+		logger.Info("Got interrupt, stopping the reference counting...")
+		close(quit)
+	}()
+
+	trieDB := statedb.NewDatabase(chainDB)
+	trieDB.EnableRefCount(state.StorageTrieRefs)
+	if err := blockchain.ReferenceStateRoots(chainDB, trieDB, *head, quit); err != nil {
+		if err == blockchain.ErrTrieNodeRefCountInterrupted {
+			fmt.Println("The reference counting is interrupted. Run the command again to continue.")
+		}
+		return err
+	}
+	fmt.Printf("The trie nodes of the state up to block #%d are reference counted\n", *head)
+	return nil
+}
+
+func keyStatsDB(ctx *cli.Context) error {
+	chainDB, err := openChainDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer chainDB.Close()
+
+	quit := make(chan struct{})
+	go func() {
+		sigc := make(chan os.Signal, 1)
+		signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(sigc)
+		<-sigc
+		logger.Info("Got interrupt, stopping the key stats...")
+		close(quit)
+	}()
+
+	stats, err := chainDB.KeyStats(quit)
+	if err != nil {
+		return err
+	}
+	for _, db := range stats {
+		fmt.Printf("%s: %d keys, %v\n", db.Name, db.Total.Count, common.StorageSize(db.Total.KeySize+db.Total.ValueSize))
+		for _, ks := range db.Kinds {
+			fmt.Printf("  %-28s %12d keys, %10v of keys, %10v of values\n", ks.Kind, ks.Count, common.StorageSize(ks.KeySize), common.StorageSize(ks.ValueSize))
+		}
+	}
+	return nil
+}
+
+func inspectDB(ctx *cli.Context) error {
+	chainDB, err := openChainDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer chainDB.Close()
+
+	enc, err := json.MarshalIndent(chainDB.Status(), "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(enc))
+	return nil
+}
+
+// openDBEntryDatabase opens the database named by the first argument, and parses
+// the following arguments given in hex with the 0x prefix or as strings.
+func openDBEntryDatabase(ctx *cli.Context, numArgs int) (database.DBManager, database.Database, [][]byte, error) {
+	if ctx.NArg() < 1 || ctx.NArg() > numArgs {
+		return nil, nil, nil, fmt.Errorf("invalid arguments, usage: %s", ctx.Command.ArgsUsage)
+	}
+	entryType, ok := database.DBEntryTypeByName(ctx.Args().First())
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("unknown database %q", ctx.Args().First())
+	}
+
+	args := make([][]byte, 0, numArgs-1)
+	for _, arg := range ctx.Args().Tail() {
+		b, err := parseDBBytes(arg)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		args = append(args, b)
+	}
+
+	chainDB, err := openChainDB(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	db := chainDB.GetDatabase(entryType)
+	if db == nil {
+		chainDB.Close()
+		return nil, nil, nil, fmt.Errorf("database %q does not exist", ctx.Args().First())
+	}
+	return chainDB, db, args, nil
+}
+
+func parseDBBytes(s string) ([]byte, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		return hexutil.Decode(s)
+	}
+	return []byte(s), nil
+}
+
+// printDBEntry prints the key and the value, with their meaning in the database
+// schema if the key is known.
+func printDBEntry(key, value []byte) {
+	fmt.Printf("key: %#x\n", key)
+	if entry, ok := database.DescribeEntry(key, value); ok {
+		if enc, err := json.MarshalIndent(entry, "", "  "); err == nil {
+			fmt.Println(string(enc))
+			return
+		}
+	}
+	fmt.Printf("value: %#x\n", value)
+}
+
+func getDBEntry(ctx *cli.Context) error {
+	chainDB, db, args, err := openDBEntryDatabase(ctx, 2)
+	if err != nil {
+		return err
+	}
+	defer chainDB.Close()
+	if len(args) != 1 {
+		return fmt.Errorf("invalid arguments, usage: %s", ctx.Command.ArgsUsage)
+	}
+
+	value, err := db.Get(args[0])
+	if err != nil {
+		return err
+	}
+	printDBEntry(args[0], value)
+	return nil
+}
+
+func putDBEntry(ctx *cli.Context) error {
+	if !ctx.GlobalBool(utils.DBUnsafeFlag.Name) {
+		return fmt.Errorf("modifying the database requires --%s", utils.DBUnsafeFlag.Name)
+	}
+	chainDB, db, args, err := openDBEntryDatabase(ctx, 3)
+	if err != nil {
+		return err
+	}
+	defer chainDB.Close()
+	if len(args) != 2 {
+		return fmt.Errorf("invalid arguments, usage: %s", ctx.Command.ArgsUsage)
+	}
+
+	if prev, err := db.Get(args[0]); err == nil {
+		fmt.Println("Previous entry:")
+		printDBEntry(args[0], prev)
+	}
+	if err := db.Put(args[0], args[1]); err != nil {
+		return err
+	}
+	fmt.Println("Stored entry:")
+	printDBEntry(args[0], args[1])
+	return nil
+}
+
+func deleteDBEntry(ctx *cli.Context) error {
+	if !ctx.GlobalBool(utils.DBUnsafeFlag.Name) {
+		return fmt.Errorf("modifying the database requires --%s", utils.DBUnsafeFlag.Name)
+	}
+	chainDB, db, args, err := openDBEntryDatabase(ctx, 2)
+	if err != nil {
+		return err
+	}
+	defer chainDB.Close()
+	if len(args) != 1 {
+		return fmt.Errorf("invalid arguments, usage: %s", ctx.Command.ArgsUsage)
+	}
+
+	prev, err := db.Get(args[0])
+	if err != nil {
+		return err
+	}
+	if err := db.Delete(args[0]); err != nil {
+		return err
+	}
+	fmt.Println("Deleted entry:")
+	printDBEntry(args[0], prev)
+	return nil
+}
+
+func iterateDB(ctx *cli.Context) error {
+	chainDB, db, args, err := openDBEntryDatabase(ctx, 3)
+	if err != nil {
+		return err
+	}
+	defer chainDB.Close()
+
+	var prefix, start []byte
+	if len(args) > 0 {
+		prefix = args[0]
+	}
+	if len(args) > 1 {
+		start = args[1]
+	}
+
+	it := db.NewIterator(prefix, start)
+	defer it.Release()
+
+	limit, count := ctx.GlobalInt(utils.DBIterateLimitFlag.Name), 0
+	for count < limit && it.Next() {
+		printDBEntry(it.Key(), it.Value())
+		count++
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	fmt.Printf("Printed %d entries\n", count)
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"fmt"
+
+	"github.com/klaytn/klaytn/cmd/utils"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var DebugBundleCommand = cli.Command{
+	Action:    utils.MigrateFlags(debugBundle),
+	Name:      "debug-bundle",
+	Usage:     "Collect the diagnostics of a running node into an archive",
+	ArgsUsage: "[endpoint]",
+	Flags:     []cli.Flag{utils.DataDirFlag, utils.DebugBundleCPUProfileFlag},
+	Category:  "MISCELLANEOUS COMMANDS",
+	Description: `
+The debug-bundle command connects to a running node like the attach command, and
+calls admin_collectDiagnostics. The node writes the goroutine dump, the heap and
+CPU profiles, the end of the log file, the configuration with the secrets
+redacted, the node and peer information, the sync status, the database stats and
+the metrics into a single archive in <datadir>/<name>/diagnostics, and the path of
+the archive is printed. Attach the archive to the support escalations.
+
+The log file is included only if the node is started with --log.file.`,
+}
+
+func debugBundle(ctx *cli.Context) error {
+	client, err := dialRPC(attachEndpoint(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to attach to the node: %v", err)
+	}
+	defer client.Close()
+
+	var path string
+	if err := client.Call(&path, "admin_collectDiagnostics", ctx.GlobalInt(utils.DebugBundleCPUProfileFlag.Name)); err != nil {
+		return err
+	}
+	fmt.Println(path)
+	return nil
+}
@@ -78,6 +78,11 @@ func startNode(ctx *cli.Context, stack *node.Node) {
 		// Open any wallets already attached
 		for _, wallet := range stack.AccountManager().Wallets() {
 			if err := wallet.Open(""); err != nil {
+				if _, ok := err.(*accounts.AuthNeededError); ok {
+					// e.g. the HD wallets, opened by personal.openWallet
+					logger.Info("Wallet needs to be opened with its passphrase", "url", wallet.URL())
+					continue
+				}
 				logger.Error("Failed to open wallet", "url", wallet.URL(), "err", err)
 			}
 		}
@@ -163,6 +168,9 @@ func CheckCommands(ctx *cli.Context) error {
 }
 
 func BeforeRunKlaytn(ctx *cli.Context) error {
+	if err := utils.SetFlagsFromEnv(ctx); err != nil {
+		return err
+	}
 	if err := CheckCommands(ctx); err != nil {
 		return err
 	}
@@ -172,12 +180,18 @@ func BeforeRunKlaytn(ctx *cli.Context) error {
 	if err := debug.Setup(ctx); err != nil {
 		return err
 	}
+	if err := utils.ApplyNodeProfile(ctx); err != nil {
+		return err
+	}
 	metricutils.StartMetricCollectionAndExport(ctx)
 	utils.SetupNetwork(ctx)
 	return nil
 }
 
 func BeforeRunBootnode(ctx *cli.Context) error {
+	if err := utils.SetFlagsFromEnv(ctx); err != nil {
+		return err
+	}
 	if err := debug.Setup(ctx); err != nil {
 		return err
 	}
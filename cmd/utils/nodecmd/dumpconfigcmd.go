@@ -22,10 +22,13 @@ package nodecmd
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"unicode"
@@ -33,8 +36,10 @@ import (
 	"github.com/Shopify/sarama"
 	"github.com/klaytn/klaytn/cmd/utils"
 	"github.com/klaytn/klaytn/datasync/chaindatafetcher"
+	"github.com/klaytn/klaytn/datasync/chaindatafetcher/elasticsearch"
 	"github.com/klaytn/klaytn/datasync/chaindatafetcher/kafka"
 	"github.com/klaytn/klaytn/datasync/chaindatafetcher/kas"
+	"github.com/klaytn/klaytn/datasync/chaindatafetcher/postgres"
 	"github.com/klaytn/klaytn/datasync/dbsyncer"
 	"github.com/klaytn/klaytn/log"
 	"github.com/klaytn/klaytn/node"
@@ -43,6 +48,7 @@ import (
 	"github.com/klaytn/klaytn/params"
 	"github.com/naoina/toml"
 	"gopkg.in/urfave/cli.v1"
+	"gopkg.in/yaml.v2"
 )
 
 // These settings ensure that TOML keys use the same names as Go struct fields.
@@ -65,6 +71,12 @@ var tomlSettings = toml.Config{
 type klayConfig struct {
 	CN   cn.Config
 	Node node.Config
+
+	ServiceChain     sc.SCConfig
+	DBSyncer         dbsyncer.DBConfig
+	ChainDataFetcher chaindatafetcher.ChainDataFetcherConfig
+
+	Runtime runtimeConfig
 }
 
 // GetDumpConfigCommand returns cli.Command `dumpconfig` whose flags are initialized with nodeFlags and rpcFlags.
@@ -76,10 +88,12 @@ func GetDumpConfigCommand(nodeFlags, rpcFlags []cli.Flag) cli.Command {
 		ArgsUsage:   "",
 		Flags:       append(append(nodeFlags, rpcFlags...)),
 		Category:    "MISCELLANEOUS COMMANDS",
-		Description: `The dumpconfig command shows configuration values.`,
+		Description: `The dumpconfig command shows configuration values as a TOML file for --config.`,
 	}
 }
 
+// loadConfig loads the TOML configuration file, or the YAML one if the file has
+// the .yaml or .yml extension. Both use the same keys.
 func loadConfig(file string, cfg *klayConfig) error {
 	f, err := os.Open(file)
 	if err != nil {
@@ -87,7 +101,13 @@ func loadConfig(file string, cfg *klayConfig) error {
 	}
 	defer f.Close()
 
-	err = tomlSettings.NewDecoder(bufio.NewReader(f)).Decode(cfg)
+	var r io.Reader = bufio.NewReader(f)
+	if ext := strings.ToLower(filepath.Ext(file)); ext == ".yaml" || ext == ".yml" {
+		if r, err = yamlToTOML(r); err != nil {
+			return errors.New(file + ", " + err.Error())
+		}
+	}
+	err = tomlSettings.NewDecoder(r).Decode(cfg)
 	// Add file name to errors that have a line number.
 	if _, ok := err.(*toml.LineError); ok {
 		err = errors.New(file + ", " + err.Error())
@@ -95,6 +115,60 @@ func loadConfig(file string, cfg *klayConfig) error {
 	return err
 }
 
+// yamlToTOML converts the YAML configuration to TOML, so that it is decoded
+// and validated in the same way as the TOML configuration.
+func yamlToTOML(r io.Reader) (io.Reader, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var v map[string]interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	out, err := tomlSettings.Marshal(normalizeYAML(v))
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(out), nil
+}
+
+// normalizeYAML converts the maps decoded from YAML to the maps keyed by
+// strings, and drops the null values which have no TOML representation.
+func normalizeYAML(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if value != nil {
+				m[key] = normalizeYAML(value)
+			}
+		}
+		return m
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if value != nil {
+				m[fmt.Sprint(key)] = normalizeYAML(value)
+			}
+		}
+		return m
+	case []interface{}:
+		for i, value := range v {
+			v[i] = normalizeYAML(value)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// flagApplied returns true if the flag overrides the configuration file. All
+// the flags, including the ones not set, are applied if there is no file.
+func flagApplied(ctx *cli.Context, name string) bool {
+	return ctx.GlobalIsSet(name) || !ctx.GlobalIsSet(utils.ConfigFileFlag.Name)
+}
+
 func defaultNodeConfig() node.Config {
 	cfg := node.DefaultConfig
 	cfg.Name = clientIdentifier
@@ -105,12 +179,22 @@ func defaultNodeConfig() node.Config {
 	return cfg
 }
 
-func makeConfigNode(ctx *cli.Context) (*node.Node, klayConfig) {
-	// Load defaults.
-	cfg := klayConfig{
+func defaultKlayConfig() klayConfig {
+	return klayConfig{
 		CN:   *cn.GetDefaultConfig(),
 		Node: defaultNodeConfig(),
+
+		ServiceChain:     sc.DefaultConfig,
+		DBSyncer:         *dbsyncer.DefaultDBConfig,
+		ChainDataFetcher: defaultChainDataFetcherConfig(),
+
+		Runtime: defaultRuntimeConfig,
 	}
+}
+
+func makeConfigNode(ctx *cli.Context) (*node.Node, klayConfig) {
+	// Load defaults.
+	cfg := defaultKlayConfig()
 
 	// Load config file.
 	if file := ctx.GlobalString(utils.ConfigFileFlag.Name); file != "" {
@@ -121,6 +205,7 @@ func makeConfigNode(ctx *cli.Context) (*node.Node, klayConfig) {
 
 	// Apply flags.
 	utils.SetNodeConfig(ctx, &cfg.Node)
+	setRuntimeConfig(ctx, &cfg.Runtime)
 	stack, err := node.New(&cfg.Node)
 	if err != nil {
 		log.Fatalf("Failed to create the protocol stack: %v", err)
@@ -133,12 +218,33 @@ func makeConfigNode(ctx *cli.Context) (*node.Node, klayConfig) {
 	return stack, cfg
 }
 
-func makeChainDataFetcherConfig(ctx *cli.Context) chaindatafetcher.ChainDataFetcherConfig {
-	cfg := chaindatafetcher.DefaultChainDataFetcherConfig
+// setServiceConfigs applies the flags to the configurations of the services
+// registered with the node.
+func setServiceConfigs(ctx *cli.Context, cfg *klayConfig) {
+	cfg.ServiceChain = makeServiceChainConfig(ctx, cfg.ServiceChain)
+	cfg.ServiceChain.DataDir = cfg.Node.DataDir
+	cfg.ServiceChain.Name = cfg.Node.Name
+	cfg.DBSyncer = makeDBSyncerConfig(ctx, cfg.DBSyncer)
+	cfg.ChainDataFetcher = makeChainDataFetcherConfig(ctx, cfg.ChainDataFetcher)
+}
+
+// defaultChainDataFetcherConfig returns a copy of the default configuration,
+// which is not shared with the other configurations.
+func defaultChainDataFetcherConfig() chaindatafetcher.ChainDataFetcherConfig {
+	cfg := *chaindatafetcher.DefaultChainDataFetcherConfig
+	kasConfig := *kas.DefaultKASConfig
+	cfg.KasConfig = &kasConfig
+	cfg.KafkaConfig = kafka.GetDefaultKafkaConfig()
+	cfg.PostgresConfig = postgres.GetDefaultPostgresConfig()
+	cfg.ElasticsearchConfig = elasticsearch.GetDefaultElasticsearchConfig()
+	return cfg
+}
 
+func makeChainDataFetcherConfig(ctx *cli.Context, cfg chaindatafetcher.ChainDataFetcherConfig) chaindatafetcher.ChainDataFetcherConfig {
 	if ctx.GlobalBool(utils.EnableChainDataFetcherFlag.Name) {
 		cfg.EnabledChainDataFetcher = true
-
+	}
+	if cfg.EnabledChainDataFetcher {
 		if ctx.GlobalIsSet(utils.ChainDataFetcherNoDefault.Name) {
 			cfg.NoDefaultStart = true
 		}
@@ -151,104 +257,282 @@ func makeChainDataFetcherConfig(ctx *cli.Context) chaindatafetcher.ChainDataFetc
 		if ctx.GlobalIsSet(utils.ChainDataFetcherChainEventSizeFlag.Name) {
 			cfg.BlockChannelSize = ctx.GlobalInt(utils.ChainDataFetcherChainEventSizeFlag.Name)
 		}
+		if ctx.GlobalIsSet(utils.ChainDataFetcherFilterRulesFlag.Name) {
+			cfg.FilterRulesFile = ctx.GlobalString(utils.ChainDataFetcherFilterRulesFlag.Name)
+		}
+		if flagApplied(ctx, utils.ChainDataFetcherBackpressureFlag.Name) {
+			cfg.Backpressure = ctx.GlobalString(utils.ChainDataFetcherBackpressureFlag.Name)
+		}
+		if cfg.Backpressure != chaindatafetcher.BackpressureBlock && cfg.Backpressure != chaindatafetcher.BackpressureDrop {
+			logger.Crit("unsupported chaindatafetcher backpressure (\"block\", \"drop\")", "backpressure", cfg.Backpressure)
+		}
+		if ctx.GlobalBool(utils.ChainDataFetcherDeadLetterFlag.Name) {
+			cfg.DeadLetter = true
+		}
 
-		mode := ctx.GlobalString(utils.ChainDataFetcherMode.Name)
-		mode = strings.ToLower(mode)
-		switch mode {
-		case "kas":
-			cfg.Mode = chaindatafetcher.ModeKAS
-			cfg.KasConfig = makeKASConfig(ctx)
-		case "kafka":
-			cfg.Mode = chaindatafetcher.ModeKafka
-			cfg.KafkaConfig = makeKafkaConfig(ctx)
+		if flagApplied(ctx, utils.ChainDataFetcherMode.Name) {
+			mode := ctx.GlobalString(utils.ChainDataFetcherMode.Name)
+			mode = strings.ToLower(mode)
+			switch mode {
+			case "kas":
+				cfg.Mode = chaindatafetcher.ModeKAS
+			case "kafka":
+				cfg.Mode = chaindatafetcher.ModeKafka
+			case "postgres":
+				cfg.Mode = chaindatafetcher.ModePostgres
+			case "elasticsearch":
+				cfg.Mode = chaindatafetcher.ModeElasticsearch
+			default:
+				logger.Crit("unsupported chaindatafetcher mode (\"kas\", \"kafka\", \"postgres\", \"elasticsearch\")", "mode", mode)
+			}
+		}
+		switch cfg.Mode {
+		case chaindatafetcher.ModeKAS:
+			cfg.KasConfig = makeKASConfig(ctx, cfg.KasConfig)
+		case chaindatafetcher.ModeKafka:
+			cfg.KafkaConfig = makeKafkaConfig(ctx, cfg.KafkaConfig)
+		case chaindatafetcher.ModePostgres:
+			cfg.PostgresConfig = makePostgresConfig(ctx, cfg.PostgresConfig)
+		case chaindatafetcher.ModeElasticsearch:
+			cfg.ElasticsearchConfig = makeElasticsearchConfig(ctx, cfg.ElasticsearchConfig)
 		default:
-			logger.Crit("unsupported chaindatafetcher mode (\"kas\", \"kafka\")", "mode", cfg.Mode)
+			logger.Crit("unsupported chaindatafetcher mode", "mode", cfg.Mode)
 		}
 	}
 
-	return *cfg
+	return cfg
 }
 
-func checkKASDBConfigs(ctx *cli.Context) {
-	if !ctx.GlobalIsSet(utils.ChainDataFetcherKASDBHostFlag.Name) {
+func checkKASDBConfigs(kasConfig *kas.KASConfig) {
+	if kasConfig.DBHost == "" {
 		logger.Crit("DBHost must be set !", "key", utils.ChainDataFetcherKASDBHostFlag.Name)
 	}
-	if !ctx.GlobalIsSet(utils.ChainDataFetcherKASDBUserFlag.Name) {
+	if kasConfig.DBUser == "" {
 		logger.Crit("DBUser must be set !", "key", utils.ChainDataFetcherKASDBUserFlag.Name)
 	}
-	if !ctx.GlobalIsSet(utils.ChainDataFetcherKASDBPasswordFlag.Name) {
+	if kasConfig.DBPassword == "" {
 		logger.Crit("DBPassword must be set !", "key", utils.ChainDataFetcherKASDBPasswordFlag.Name)
 	}
-	if !ctx.GlobalIsSet(utils.ChainDataFetcherKASDBNameFlag.Name) {
+	if kasConfig.DBName == "" {
 		logger.Crit("DBName must be set !", "key", utils.ChainDataFetcherKASDBNameFlag.Name)
 	}
 }
 
-func checkKASCacheInvalidationConfigs(ctx *cli.Context) {
-	if !ctx.GlobalIsSet(utils.ChainDataFetcherKASCacheURLFlag.Name) {
+func checkKASCacheInvalidationConfigs(kasConfig *kas.KASConfig) {
+	if kasConfig.CacheInvalidationURL == "" {
 		logger.Crit("The cache invalidation url is not set")
 	}
-	if !ctx.GlobalIsSet(utils.ChainDataFetcherKASBasicAuthParamFlag.Name) {
+	if kasConfig.BasicAuthParam == "" {
 		logger.Crit("The authorization is not set")
 	}
-	if !ctx.GlobalIsSet(utils.ChainDataFetcherKASXChainIdFlag.Name) {
+	if kasConfig.XChainId == "" {
 		logger.Crit("The x-chain-id is not set")
 	}
 }
 
-func makeKASConfig(ctx *cli.Context) *kas.KASConfig {
-	kasConfig := kas.DefaultKASConfig
-
-	checkKASDBConfigs(ctx)
-	kasConfig.DBHost = ctx.GlobalString(utils.ChainDataFetcherKASDBHostFlag.Name)
-	kasConfig.DBPort = ctx.GlobalString(utils.ChainDataFetcherKASDBPortFlag.Name)
-	kasConfig.DBUser = ctx.GlobalString(utils.ChainDataFetcherKASDBUserFlag.Name)
-	kasConfig.DBPassword = ctx.GlobalString(utils.ChainDataFetcherKASDBPasswordFlag.Name)
-	kasConfig.DBName = ctx.GlobalString(utils.ChainDataFetcherKASDBNameFlag.Name)
+func makeKASConfig(ctx *cli.Context, kasConfig *kas.KASConfig) *kas.KASConfig {
+	if flagApplied(ctx, utils.ChainDataFetcherKASDBHostFlag.Name) {
+		kasConfig.DBHost = ctx.GlobalString(utils.ChainDataFetcherKASDBHostFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherKASDBPortFlag.Name) {
+		kasConfig.DBPort = ctx.GlobalString(utils.ChainDataFetcherKASDBPortFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherKASDBUserFlag.Name) {
+		kasConfig.DBUser = ctx.GlobalString(utils.ChainDataFetcherKASDBUserFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherKASDBPasswordFlag.Name) {
+		kasConfig.DBPassword = ctx.GlobalString(utils.ChainDataFetcherKASDBPasswordFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherKASDBNameFlag.Name) {
+		kasConfig.DBName = ctx.GlobalString(utils.ChainDataFetcherKASDBNameFlag.Name)
+	}
+	checkKASDBConfigs(kasConfig)
 
 	if ctx.GlobalBool(utils.ChainDataFetcherKASCacheUse.Name) {
-		checkKASCacheInvalidationConfigs(ctx)
 		kasConfig.CacheUse = true
-		kasConfig.CacheInvalidationURL = ctx.GlobalString(utils.ChainDataFetcherKASCacheURLFlag.Name)
-		kasConfig.BasicAuthParam = ctx.GlobalString(utils.ChainDataFetcherKASBasicAuthParamFlag.Name)
-		kasConfig.XChainId = ctx.GlobalString(utils.ChainDataFetcherKASXChainIdFlag.Name)
+	}
+	if kasConfig.CacheUse {
+		if flagApplied(ctx, utils.ChainDataFetcherKASCacheURLFlag.Name) {
+			kasConfig.CacheInvalidationURL = ctx.GlobalString(utils.ChainDataFetcherKASCacheURLFlag.Name)
+		}
+		if flagApplied(ctx, utils.ChainDataFetcherKASBasicAuthParamFlag.Name) {
+			kasConfig.BasicAuthParam = ctx.GlobalString(utils.ChainDataFetcherKASBasicAuthParamFlag.Name)
+		}
+		if flagApplied(ctx, utils.ChainDataFetcherKASXChainIdFlag.Name) {
+			kasConfig.XChainId = ctx.GlobalString(utils.ChainDataFetcherKASXChainIdFlag.Name)
+		}
+		checkKASCacheInvalidationConfigs(kasConfig)
 	}
 	return kasConfig
 }
 
-func makeKafkaConfig(ctx *cli.Context) *kafka.KafkaConfig {
-	kafkaConfig := kafka.GetDefaultKafkaConfig()
+func makeKafkaConfig(ctx *cli.Context, kafkaConfig *kafka.KafkaConfig) *kafka.KafkaConfig {
 	if ctx.GlobalIsSet(utils.ChainDataFetcherKafkaBrokersFlag.Name) {
 		kafkaConfig.Brokers = ctx.GlobalStringSlice(utils.ChainDataFetcherKafkaBrokersFlag.Name)
-	} else {
+	}
+	if len(kafkaConfig.Brokers) == 0 {
 		logger.Crit("The kafka brokers must be set")
 	}
-	kafkaConfig.TopicEnvironmentName = ctx.GlobalString(utils.ChainDataFetcherKafkaTopicEnvironmentFlag.Name)
-	kafkaConfig.TopicResourceName = ctx.GlobalString(utils.ChainDataFetcherKafkaTopicResourceFlag.Name)
-	kafkaConfig.Partitions = int32(ctx.GlobalInt64(utils.ChainDataFetcherKafkaPartitionsFlag.Name))
-	kafkaConfig.Replicas = int16(ctx.GlobalInt64(utils.ChainDataFetcherKafkaReplicasFlag.Name))
-	kafkaConfig.SaramaConfig.Producer.MaxMessageBytes = ctx.GlobalInt(utils.ChainDataFetcherKafkaMaxMessageBytesFlag.Name)
-	kafkaConfig.SegmentSizeBytes = ctx.GlobalInt(utils.ChainDataFetcherKafkaSegmentSizeBytesFlag.Name)
-	kafkaConfig.MsgVersion = ctx.GlobalString(utils.ChainDataFetcherKafkaMessageVersionFlag.Name)
-	kafkaConfig.ProducerId = ctx.GlobalString(utils.ChainDataFetcherKafkaProducerIdFlag.Name)
-	requiredAcks := sarama.RequiredAcks(ctx.GlobalInt(utils.ChainDataFetcherKafkaRequiredAcksFlag.Name))
-	if requiredAcks != sarama.NoResponse && requiredAcks != sarama.WaitForLocal && requiredAcks != sarama.WaitForAll {
-		logger.Crit("not supported requiredAcks. it must be NoResponse(0), WaitForLocal(1), or WaitForAll(-1)", "given", requiredAcks)
-	}
-	kafkaConfig.SaramaConfig.Producer.RequiredAcks = requiredAcks
+	if flagApplied(ctx, utils.ChainDataFetcherKafkaTopicEnvironmentFlag.Name) {
+		kafkaConfig.TopicEnvironmentName = ctx.GlobalString(utils.ChainDataFetcherKafkaTopicEnvironmentFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherKafkaTopicResourceFlag.Name) {
+		kafkaConfig.TopicResourceName = ctx.GlobalString(utils.ChainDataFetcherKafkaTopicResourceFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherKafkaPartitionsFlag.Name) {
+		kafkaConfig.Partitions = int32(ctx.GlobalInt64(utils.ChainDataFetcherKafkaPartitionsFlag.Name))
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherKafkaReplicasFlag.Name) {
+		kafkaConfig.Replicas = int16(ctx.GlobalInt64(utils.ChainDataFetcherKafkaReplicasFlag.Name))
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherKafkaMaxMessageBytesFlag.Name) {
+		kafkaConfig.SaramaConfig.Producer.MaxMessageBytes = ctx.GlobalInt(utils.ChainDataFetcherKafkaMaxMessageBytesFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherKafkaSegmentSizeBytesFlag.Name) {
+		kafkaConfig.SegmentSizeBytes = ctx.GlobalInt(utils.ChainDataFetcherKafkaSegmentSizeBytesFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherKafkaMessageVersionFlag.Name) {
+		kafkaConfig.MsgVersion = ctx.GlobalString(utils.ChainDataFetcherKafkaMessageVersionFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherKafkaProducerIdFlag.Name) {
+		kafkaConfig.ProducerId = ctx.GlobalString(utils.ChainDataFetcherKafkaProducerIdFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherKafkaMsgEncodingFlag.Name) {
+		kafkaConfig.MsgEncoding = ctx.GlobalString(utils.ChainDataFetcherKafkaMsgEncodingFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherKafkaSchemaRegistryFlag.Name) {
+		kafkaConfig.SchemaRegistryURL = ctx.GlobalString(utils.ChainDataFetcherKafkaSchemaRegistryFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherKafkaMaxMessagesPerSecondFlag.Name) {
+		kafkaConfig.MaxMessagesPerSecond = ctx.GlobalInt(utils.ChainDataFetcherKafkaMaxMessagesPerSecondFlag.Name)
+	}
+	switch kafkaConfig.MsgEncoding {
+	case kafka.MsgEncodingJSON:
+	case kafka.MsgEncodingAvro, kafka.MsgEncodingProtobuf:
+		if kafkaConfig.SchemaRegistryURL == "" {
+			logger.Crit("The schema registry must be set for the encoding", "encoding", kafkaConfig.MsgEncoding)
+		}
+	default:
+		logger.Crit("not supported kafka message encoding. it must be json, avro, or protobuf", "given", kafkaConfig.MsgEncoding)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherKafkaRequiredAcksFlag.Name) {
+		requiredAcks := sarama.RequiredAcks(ctx.GlobalInt(utils.ChainDataFetcherKafkaRequiredAcksFlag.Name))
+		if requiredAcks != sarama.NoResponse && requiredAcks != sarama.WaitForLocal && requiredAcks != sarama.WaitForAll {
+			logger.Crit("not supported requiredAcks. it must be NoResponse(0), WaitForLocal(1), or WaitForAll(-1)", "given", requiredAcks)
+		}
+		kafkaConfig.SaramaConfig.Producer.RequiredAcks = requiredAcks
+	}
 	return kafkaConfig
 }
 
-func makeDBSyncerConfig(ctx *cli.Context) dbsyncer.DBConfig {
-	cfg := dbsyncer.DefaultDBConfig
+func makePostgresConfig(ctx *cli.Context, postgresConfig *postgres.PostgresConfig) *postgres.PostgresConfig {
+	if flagApplied(ctx, utils.ChainDataFetcherPostgresDBHostFlag.Name) {
+		postgresConfig.DBHost = ctx.GlobalString(utils.ChainDataFetcherPostgresDBHostFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherPostgresDBPortFlag.Name) {
+		postgresConfig.DBPort = ctx.GlobalString(utils.ChainDataFetcherPostgresDBPortFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherPostgresDBNameFlag.Name) {
+		postgresConfig.DBName = ctx.GlobalString(utils.ChainDataFetcherPostgresDBNameFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherPostgresDBUserFlag.Name) {
+		postgresConfig.DBUser = ctx.GlobalString(utils.ChainDataFetcherPostgresDBUserFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherPostgresDBPasswordFlag.Name) {
+		postgresConfig.DBPassword = ctx.GlobalString(utils.ChainDataFetcherPostgresDBPasswordFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherPostgresSSLModeFlag.Name) {
+		postgresConfig.SSLMode = ctx.GlobalString(utils.ChainDataFetcherPostgresSSLModeFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherPostgresSchemaFlag.Name) {
+		postgresConfig.Schema = ctx.GlobalString(utils.ChainDataFetcherPostgresSchemaFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherPostgresTablePrefixFlag.Name) {
+		postgresConfig.TablePrefix = ctx.GlobalString(utils.ChainDataFetcherPostgresTablePrefixFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherPostgresTablesFlag.Name) {
+		postgresConfig.Tables = nil
+		for _, table := range strings.Split(ctx.GlobalString(utils.ChainDataFetcherPostgresTablesFlag.Name), ",") {
+			if table = strings.TrimSpace(table); table != "" {
+				postgresConfig.Tables = append(postgresConfig.Tables, table)
+			}
+		}
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherPostgresBatchSizeFlag.Name) {
+		postgresConfig.BatchSize = ctx.GlobalInt(utils.ChainDataFetcherPostgresBatchSizeFlag.Name)
+	}
+	for _, required := range []struct{ key, value string }{
+		{utils.ChainDataFetcherPostgresDBHostFlag.Name, postgresConfig.DBHost},
+		{utils.ChainDataFetcherPostgresDBNameFlag.Name, postgresConfig.DBName},
+		{utils.ChainDataFetcherPostgresDBUserFlag.Name, postgresConfig.DBUser},
+	} {
+		if required.value == "" {
+			logger.Crit("The postgres DB configuration must be set", "key", required.key)
+		}
+	}
+	if err := postgresConfig.Validate(); err != nil {
+		logger.Crit("Invalid postgres configuration", "err", err)
+	}
+	return postgresConfig
+}
+
+// splitList returns the non-empty trimmed items of the comma separated list.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func makeElasticsearchConfig(ctx *cli.Context, esConfig *elasticsearch.ElasticsearchConfig) *elasticsearch.ElasticsearchConfig {
+	if flagApplied(ctx, utils.ChainDataFetcherElasticsearchURLsFlag.Name) {
+		esConfig.URLs = splitList(ctx.GlobalString(utils.ChainDataFetcherElasticsearchURLsFlag.Name))
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherElasticsearchUserFlag.Name) {
+		esConfig.Username = ctx.GlobalString(utils.ChainDataFetcherElasticsearchUserFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherElasticsearchPasswordFlag.Name) {
+		esConfig.Password = ctx.GlobalString(utils.ChainDataFetcherElasticsearchPasswordFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherElasticsearchIndexPrefixFlag.Name) {
+		esConfig.IndexPrefix = ctx.GlobalString(utils.ChainDataFetcherElasticsearchIndexPrefixFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherElasticsearchIndexDateLayoutFlag.Name) {
+		esConfig.IndexDateLayout = ctx.GlobalString(utils.ChainDataFetcherElasticsearchIndexDateLayoutFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherElasticsearchTemplateFileFlag.Name) {
+		esConfig.TemplateFile = ctx.GlobalString(utils.ChainDataFetcherElasticsearchTemplateFileFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherElasticsearchILMPolicyFlag.Name) {
+		esConfig.ILMPolicy = ctx.GlobalString(utils.ChainDataFetcherElasticsearchILMPolicyFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherElasticsearchIndicesFlag.Name) {
+		esConfig.Indices = splitList(ctx.GlobalString(utils.ChainDataFetcherElasticsearchIndicesFlag.Name))
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherElasticsearchABIFilesFlag.Name) {
+		esConfig.ABIFiles = splitList(ctx.GlobalString(utils.ChainDataFetcherElasticsearchABIFilesFlag.Name))
+	}
+	if flagApplied(ctx, utils.ChainDataFetcherElasticsearchBulkSizeFlag.Name) {
+		esConfig.BulkSize = ctx.GlobalInt(utils.ChainDataFetcherElasticsearchBulkSizeFlag.Name)
+	}
+	if err := esConfig.Validate(); err != nil {
+		logger.Crit("Invalid elasticsearch configuration", "err", err)
+	}
+	return esConfig
+}
 
+func makeDBSyncerConfig(ctx *cli.Context, cfg dbsyncer.DBConfig) dbsyncer.DBConfig {
 	if ctx.GlobalBool(utils.EnableDBSyncerFlag.Name) {
 		cfg.EnabledDBSyncer = true
-
+	}
+	if cfg.EnabledDBSyncer {
 		if ctx.GlobalIsSet(utils.DBHostFlag.Name) {
 			dbhost := ctx.GlobalString(utils.DBHostFlag.Name)
 			cfg.DBHost = dbhost
-		} else {
+		} else if cfg.DBHost == "" {
 			logger.Crit("DBHost must be set !", "key", utils.DBHostFlag.Name)
 		}
 		if ctx.GlobalIsSet(utils.DBPortFlag.Name) {
@@ -258,19 +542,19 @@ func makeDBSyncerConfig(ctx *cli.Context) dbsyncer.DBConfig {
 		if ctx.GlobalIsSet(utils.DBUserFlag.Name) {
 			dbuser := ctx.GlobalString(utils.DBUserFlag.Name)
 			cfg.DBUser = dbuser
-		} else {
+		} else if cfg.DBUser == "" {
 			logger.Crit("DBUser must be set !", "key", utils.DBUserFlag.Name)
 		}
 		if ctx.GlobalIsSet(utils.DBPasswordFlag.Name) {
 			dbpasswd := ctx.GlobalString(utils.DBPasswordFlag.Name)
 			cfg.DBPassword = dbpasswd
-		} else {
+		} else if cfg.DBPassword == "" {
 			logger.Crit("DBPassword must be set !", "key", utils.DBPasswordFlag.Name)
 		}
 		if ctx.GlobalIsSet(utils.DBNameFlag.Name) {
 			dbname := ctx.GlobalString(utils.DBNameFlag.Name)
 			cfg.DBName = dbname
-		} else {
+		} else if cfg.DBName == "" {
 			logger.Crit("DBName must be set !", "key", utils.DBNameFlag.Name)
 		}
 		if ctx.GlobalBool(utils.EnabledLogModeFlag.Name) {
@@ -308,77 +592,169 @@ func makeDBSyncerConfig(ctx *cli.Context) dbsyncer.DBConfig {
 		}
 	}
 
-	return *cfg
+	return cfg
 }
 
-func makeServiceChainConfig(ctx *cli.Context) (config sc.SCConfig) {
-	cfg := sc.DefaultConfig
-
+func makeServiceChainConfig(ctx *cli.Context, cfg sc.SCConfig) sc.SCConfig {
 	// bridge service
 	if ctx.GlobalBool(utils.MainBridgeFlag.Name) {
 		cfg.EnabledMainBridge = true
+	}
+	if cfg.EnabledMainBridge && flagApplied(ctx, utils.MainBridgeListenPortFlag.Name) {
 		cfg.MainBridgePort = fmt.Sprintf(":%d", ctx.GlobalInt(utils.MainBridgeListenPortFlag.Name))
 	}
 
 	if ctx.GlobalBool(utils.SubBridgeFlag.Name) {
 		cfg.EnabledSubBridge = true
+	}
+	if cfg.EnabledSubBridge && flagApplied(ctx, utils.SubBridgeListenPortFlag.Name) {
 		cfg.SubBridgePort = fmt.Sprintf(":%d", ctx.GlobalInt(utils.SubBridgeListenPortFlag.Name))
 	}
 
-	cfg.Anchoring = ctx.GlobalBool(utils.ServiceChainAnchoringFlag.Name)
-	cfg.ChildChainIndexing = ctx.GlobalIsSet(utils.ChildChainIndexingFlag.Name)
-	cfg.AnchoringPeriod = ctx.GlobalUint64(utils.AnchoringPeriodFlag.Name)
-	cfg.SentChainTxsLimit = ctx.GlobalUint64(utils.SentChainTxsLimit.Name)
-	cfg.ParentChainID = ctx.GlobalUint64(utils.ParentChainIDFlag.Name)
-	cfg.VTRecovery = ctx.GlobalBool(utils.VTRecoveryFlag.Name)
-	cfg.VTRecoveryInterval = ctx.GlobalUint64(utils.VTRecoveryIntervalFlag.Name)
-	cfg.ServiceChainConsensus = utils.ServiceChainConsensusFlag.Value
-	cfg.ServiceChainParentOperatorGasLimit = ctx.GlobalUint64(utils.ServiceChainParentOperatorTxGasLimitFlag.Name)
-	cfg.ServiceChainChildOperatorGasLimit = ctx.GlobalUint64(utils.ServiceChainChildOperatorTxGasLimitFlag.Name)
+	if flagApplied(ctx, utils.ServiceChainAnchoringFlag.Name) {
+		cfg.Anchoring = ctx.GlobalBool(utils.ServiceChainAnchoringFlag.Name)
+	}
+	if flagApplied(ctx, utils.ChildChainIndexingFlag.Name) {
+		cfg.ChildChainIndexing = ctx.GlobalIsSet(utils.ChildChainIndexingFlag.Name)
+	}
+	if flagApplied(ctx, utils.AnchoringPeriodFlag.Name) {
+		cfg.AnchoringPeriod = ctx.GlobalUint64(utils.AnchoringPeriodFlag.Name)
+	}
+	if flagApplied(ctx, utils.SentChainTxsLimit.Name) {
+		cfg.SentChainTxsLimit = ctx.GlobalUint64(utils.SentChainTxsLimit.Name)
+	}
+	if flagApplied(ctx, utils.ParentChainIDFlag.Name) {
+		cfg.ParentChainID = ctx.GlobalUint64(utils.ParentChainIDFlag.Name)
+	}
+	if flagApplied(ctx, utils.VTRecoveryFlag.Name) {
+		cfg.VTRecovery = ctx.GlobalBool(utils.VTRecoveryFlag.Name)
+	}
+	if flagApplied(ctx, utils.VTRecoveryIntervalFlag.Name) {
+		cfg.VTRecoveryInterval = ctx.GlobalUint64(utils.VTRecoveryIntervalFlag.Name)
+	}
+	if flagApplied(ctx, utils.VTRecoveryRetryBackoffFlag.Name) {
+		cfg.VTRecoveryRetryBackoff = ctx.GlobalDuration(utils.VTRecoveryRetryBackoffFlag.Name)
+	}
+	if flagApplied(ctx, utils.VTRecoveryRetryMaxBackoffFlag.Name) {
+		cfg.VTRecoveryRetryMaxBackoff = ctx.GlobalDuration(utils.VTRecoveryRetryMaxBackoffFlag.Name)
+	}
+	if flagApplied(ctx, utils.VTRecoveryMaxAttemptsFlag.Name) {
+		cfg.VTRecoveryMaxAttempts = ctx.GlobalUint64(utils.VTRecoveryMaxAttemptsFlag.Name)
+	}
+	if cfg.ServiceChainConsensus == "" {
+		cfg.ServiceChainConsensus = utils.ServiceChainConsensusFlag.Value
+	}
+	if flagApplied(ctx, utils.ServiceChainParentOperatorTxGasLimitFlag.Name) {
+		cfg.ServiceChainParentOperatorGasLimit = ctx.GlobalUint64(utils.ServiceChainParentOperatorTxGasLimitFlag.Name)
+	}
+	if flagApplied(ctx, utils.ServiceChainChildOperatorTxGasLimitFlag.Name) {
+		cfg.ServiceChainChildOperatorGasLimit = ctx.GlobalUint64(utils.ServiceChainChildOperatorTxGasLimitFlag.Name)
+	}
+
+	if flagApplied(ctx, utils.ServiceChainAnchoringGasPriceStrategyFlag.Name) {
+		cfg.AnchoringGasPriceStrategy = ctx.GlobalString(utils.ServiceChainAnchoringGasPriceStrategyFlag.Name)
+	}
+	if flagApplied(ctx, utils.ServiceChainAnchoringGasPriceFlag.Name) {
+		cfg.AnchoringGasPrice = ctx.GlobalUint64(utils.ServiceChainAnchoringGasPriceFlag.Name)
+	}
+	if flagApplied(ctx, utils.ServiceChainAnchoringGasPricePremiumFlag.Name) {
+		cfg.AnchoringGasPricePremium = ctx.GlobalUint64(utils.ServiceChainAnchoringGasPricePremiumFlag.Name)
+	}
+	if flagApplied(ctx, utils.ServiceChainAnchoringGasPriceCapFlag.Name) {
+		cfg.AnchoringGasPriceCap = ctx.GlobalUint64(utils.ServiceChainAnchoringGasPriceCapFlag.Name)
+	}
+	if flagApplied(ctx, utils.ServiceChainAnchoringRetryGasBumpFlag.Name) {
+		cfg.AnchoringRetryGasBump = ctx.GlobalUint64(utils.ServiceChainAnchoringRetryGasBumpFlag.Name)
+	}
+	if flagApplied(ctx, utils.ServiceChainAnchoringRetryBackoffFlag.Name) {
+		cfg.AnchoringRetryBackoff = ctx.GlobalDuration(utils.ServiceChainAnchoringRetryBackoffFlag.Name)
+	}
+	if flagApplied(ctx, utils.ServiceChainAnchoringRetryMaxBackoffFlag.Name) {
+		cfg.AnchoringRetryMaxBackoff = ctx.GlobalDuration(utils.ServiceChainAnchoringRetryMaxBackoffFlag.Name)
+	}
+	if flagApplied(ctx, utils.ServiceChainAnchoringMaxRetriesFlag.Name) {
+		cfg.AnchoringMaxRetries = ctx.GlobalUint64(utils.ServiceChainAnchoringMaxRetriesFlag.Name)
+	}
+	if flagApplied(ctx, utils.ServiceChainAnchoringNonceRepairFlag.Name) {
+		cfg.AnchoringNonceRepair = ctx.GlobalBool(utils.ServiceChainAnchoringNonceRepairFlag.Name)
+	}
+	if flagApplied(ctx, utils.ServiceChainAnchoringBatchFlag.Name) {
+		cfg.AnchoringBatch = ctx.GlobalBool(utils.ServiceChainAnchoringBatchFlag.Name)
+	}
+	if flagApplied(ctx, utils.ServiceChainAnchoringBatchMaxBlocksFlag.Name) {
+		cfg.AnchoringBatchMaxBlocks = ctx.GlobalUint64(utils.ServiceChainAnchoringBatchMaxBlocksFlag.Name)
+	}
+	if flagApplied(ctx, utils.ServiceChainParentHealthCheckIntervalFlag.Name) {
+		cfg.ParentHealthCheckInterval = ctx.GlobalDuration(utils.ServiceChainParentHealthCheckIntervalFlag.Name)
+	}
+	if flagApplied(ctx, utils.ServiceChainParentHealthTimeoutFlag.Name) {
+		cfg.ParentHealthTimeout = ctx.GlobalDuration(utils.ServiceChainParentHealthTimeoutFlag.Name)
+	}
+	if flagApplied(ctx, utils.ServiceChainParentQuorumFlag.Name) {
+		cfg.ParentQuorum = ctx.GlobalInt(utils.ServiceChainParentQuorumFlag.Name)
+	}
+	if flagApplied(ctx, utils.ServiceChainFeeDelegationPolicyFlag.Name) {
+		cfg.FeeDelegationPolicyFile = ctx.GlobalString(utils.ServiceChainFeeDelegationPolicyFlag.Name)
+	}
 
-	cfg.KASAnchor = ctx.GlobalBool(utils.KASServiceChainAnchorFlag.Name)
+	if flagApplied(ctx, utils.KASServiceChainAnchorFlag.Name) {
+		cfg.KASAnchor = ctx.GlobalBool(utils.KASServiceChainAnchorFlag.Name)
+	}
 	if cfg.KASAnchor {
-		cfg.KASAnchorPeriod = ctx.GlobalUint64(utils.KASServiceChainAnchorPeriodFlag.Name)
+		if flagApplied(ctx, utils.KASServiceChainAnchorPeriodFlag.Name) {
+			cfg.KASAnchorPeriod = ctx.GlobalUint64(utils.KASServiceChainAnchorPeriodFlag.Name)
+		}
 		if cfg.KASAnchorPeriod == 0 {
 			cfg.KASAnchorPeriod = 1
 			logger.Warn("KAS anchor period is set by 1")
 		}
 
-		cfg.KASAnchorUrl = ctx.GlobalString(utils.KASServiceChainAnchorUrlFlag.Name)
+		if flagApplied(ctx, utils.KASServiceChainAnchorUrlFlag.Name) {
+			cfg.KASAnchorUrl = ctx.GlobalString(utils.KASServiceChainAnchorUrlFlag.Name)
+		}
 		if cfg.KASAnchorUrl == "" {
 			logger.Crit("KAS anchor url should be set", "key", utils.KASServiceChainAnchorUrlFlag.Name)
 		}
 
-		cfg.KASAnchorOperator = ctx.GlobalString(utils.KASServiceChainAnchorOperatorFlag.Name)
+		if flagApplied(ctx, utils.KASServiceChainAnchorOperatorFlag.Name) {
+			cfg.KASAnchorOperator = ctx.GlobalString(utils.KASServiceChainAnchorOperatorFlag.Name)
+		}
 		if cfg.KASAnchorOperator == "" {
 			logger.Crit("KAS anchor operator should be set", "key", utils.KASServiceChainAnchorOperatorFlag.Name)
 		}
 
-		cfg.KASAccessKey = ctx.GlobalString(utils.KASServiceChainAccessKeyFlag.Name)
+		if flagApplied(ctx, utils.KASServiceChainAccessKeyFlag.Name) {
+			cfg.KASAccessKey = ctx.GlobalString(utils.KASServiceChainAccessKeyFlag.Name)
+		}
 		if cfg.KASAccessKey == "" {
 			logger.Crit("KAS access key should be set", "key", utils.KASServiceChainAccessKeyFlag.Name)
 		}
 
-		cfg.KASSecretKey = ctx.GlobalString(utils.KASServiceChainSecretKeyFlag.Name)
+		if flagApplied(ctx, utils.KASServiceChainSecretKeyFlag.Name) {
+			cfg.KASSecretKey = ctx.GlobalString(utils.KASServiceChainSecretKeyFlag.Name)
+		}
 		if cfg.KASSecretKey == "" {
 			logger.Crit("KAS secret key should be set", "key", utils.KASServiceChainSecretKeyFlag.Name)
 		}
 
-		cfg.KASXChainId = ctx.GlobalString(utils.KASServiceChainXChainIdFlag.Name)
+		if flagApplied(ctx, utils.KASServiceChainXChainIdFlag.Name) {
+			cfg.KASXChainId = ctx.GlobalString(utils.KASServiceChainXChainIdFlag.Name)
+		}
 		if cfg.KASXChainId == "" {
 			logger.Crit("KAS x-chain-id should be set", "key", utils.KASServiceChainXChainIdFlag.Name)
 		}
 
-		cfg.KASAnchorRequestTimeout = ctx.GlobalDuration(utils.KASServiceChainAnchorRequestTimeoutFlag.Name)
+		if flagApplied(ctx, utils.KASServiceChainAnchorRequestTimeoutFlag.Name) {
+			cfg.KASAnchorRequestTimeout = ctx.GlobalDuration(utils.KASServiceChainAnchorRequestTimeoutFlag.Name)
+		}
 	}
 	return cfg
 }
 
 func MakeFullNode(ctx *cli.Context) *node.Node {
 	stack, cfg := makeConfigNode(ctx)
-	scfg := makeServiceChainConfig(ctx)
-	scfg.DataDir = cfg.Node.DataDir
-	scfg.Name = cfg.Node.Name
+	setServiceConfigs(ctx, &cfg)
+	scfg := cfg.ServiceChain
 
 	if utils.NetworkTypeFlag.Value == SCNNetworkType && scfg.EnabledSubBridge {
 		cfg.CN.NoAccountCreation = !ctx.GlobalBool(utils.ServiceChainNewAccountFlag.Name)
@@ -399,17 +775,28 @@ func MakeFullNode(ctx *cli.Context) *node.Node {
 	}
 	utils.RegisterService(stack, &scfg)
 
-	dbfg := makeDBSyncerConfig(ctx)
-	utils.RegisterDBSyncerService(stack, &dbfg)
+	utils.RegisterDBSyncerService(stack, &cfg.DBSyncer)
+	utils.RegisterChainDataFetcherService(stack, &cfg.ChainDataFetcher)
 
-	chaindataFetcherConfig := makeChainDataFetcherConfig(ctx)
-	utils.RegisterChainDataFetcherService(stack, &chaindataFetcherConfig)
+	if file := ctx.GlobalString(utils.ConfigFileFlag.Name); file != "" {
+		reloader, err := newConfigReloader(file, stack, &cfg)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		stack.SetConfigReloader(reloader.reload)
+	}
+	stack.SetConfigDumper(func() ([]byte, error) {
+		dumped := cfg
+		dumped.CN.Genesis = nil
+		return tomlSettings.Marshal(&dumped)
+	})
 
 	return stack
 }
 
 func dumpConfig(ctx *cli.Context) error {
 	_, cfg := makeConfigNode(ctx)
+	setServiceConfigs(ctx, &cfg)
 	comment := ""
 
 	if cfg.CN.Genesis != nil {
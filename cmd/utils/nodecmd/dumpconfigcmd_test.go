@@ -0,0 +1,101 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn/node/sc"
+)
+
+func TestLoadConfig_YAML(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klay-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tomlFile := filepath.Join(dir, "klay.toml")
+	yamlFile := filepath.Join(dir, "klay.yaml")
+	ioutil.WriteFile(tomlFile, []byte(`
+[CN]
+NetworkId = 1000
+
+[ServiceChain]
+EnabledSubBridge = true
+AnchoringPeriod = 10
+VTRecoveryRetryBackoff = 1000000000
+
+[ChainDataFetcher.KafkaConfig]
+Brokers = ["kafka:9092"]
+`), 0o600)
+	ioutil.WriteFile(yamlFile, []byte(`
+CN:
+  NetworkId: 1000
+ServiceChain:
+  EnabledSubBridge: true
+  AnchoringPeriod: 10
+  VTRecoveryRetryBackoff: 1000000000
+ChainDataFetcher:
+  KafkaConfig:
+    Brokers:
+      - kafka:9092
+`), 0o600)
+
+	for _, file := range []string{tomlFile, yamlFile} {
+		cfg := klayConfig{ServiceChain: sc.DefaultConfig, ChainDataFetcher: defaultChainDataFetcherConfig()}
+		if err := loadConfig(file, &cfg); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if cfg.CN.NetworkId != 1000 {
+			t.Errorf("%s: NetworkId mismatch: have %d, want 1000", file, cfg.CN.NetworkId)
+		}
+		if !cfg.ServiceChain.EnabledSubBridge || cfg.ServiceChain.AnchoringPeriod != 10 {
+			t.Errorf("%s: ServiceChain mismatch: have %v, %d", file, cfg.ServiceChain.EnabledSubBridge, cfg.ServiceChain.AnchoringPeriod)
+		}
+		if cfg.ServiceChain.VTRecoveryRetryBackoff != time.Second {
+			t.Errorf("%s: VTRecoveryRetryBackoff mismatch: have %v, want %v", file, cfg.ServiceChain.VTRecoveryRetryBackoff, time.Second)
+		}
+		if cfg.ServiceChain.MaxPeer != sc.DefaultConfig.MaxPeer {
+			t.Errorf("%s: the default MaxPeer is not kept: have %d", file, cfg.ServiceChain.MaxPeer)
+		}
+		if brokers := cfg.ChainDataFetcher.KafkaConfig.Brokers; len(brokers) != 1 || brokers[0] != "kafka:9092" {
+			t.Errorf("%s: Brokers mismatch: have %v", file, brokers)
+		}
+	}
+}
+
+func TestLoadConfig_UnknownField(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klay-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "klay.yml")
+	ioutil.WriteFile(file, []byte("ServiceChain:\n  UnknownField: 1\n"), 0o600)
+
+	err = loadConfig(file, &klayConfig{})
+	if err == nil || !strings.Contains(err.Error(), "UnknownField") {
+		t.Errorf("unknown field error mismatch: have %v", err)
+	}
+}
@@ -19,6 +19,7 @@ package nodecmd
 
 import (
 	"encoding/json"
+	"path/filepath"
 
 	"github.com/klaytn/klaytn/cmd/utils"
 	"github.com/klaytn/klaytn/storage/database"
@@ -39,6 +40,8 @@ var (
 		utils.DynamoDBReadCapacityFlag,
 		utils.DynamoDBWriteCapacityFlag,
 		utils.LevelDBCompressionTypeFlag,
+		utils.DBCompressionFlag,
+		utils.DBEncryptionKeyFlag,
 		utils.DataDirFlag,
 	}
 	dbMigrationFlags = append(dbFlags, DBMigrationFlags...)
@@ -70,6 +73,12 @@ If dst db is singleDB, you should set dst.datadir or db.dst.dynamo.tablename
 to the original db dir name.
 (e.g. Data dir : 'chaindata/klay/statetrie', Dynamo table name : 'klaytn-statetrie')
 
+Each db is copied by --db.migration.workers workers in parallel, and the copy rate
+can be limited by --db.migration.max-rate. The progress is stored in the src data
+dir. If the migration is interrupted, starting it again with the same dst db and
+the same number of workers continues from the progress. With --db.migration.verify,
+the migrated items are compared with the src db after copying.
+
 Note: This feature is only provided when srcDB is single LevelDB.`,
 			},
 		},
@@ -84,7 +93,16 @@ func startMigration(ctx *cli.Context) error {
 	defer srcDBManager.Close()
 	defer dstDBManager.Close()
 
-	return srcDBManager.StartDBMigration(dstDBManager)
+	return srcDBManager.StartDBMigration(dstDBManager, createDBMigrationConfig(ctx))
+}
+
+func createDBMigrationConfig(ctx *cli.Context) *database.DBMigrationConfig {
+	return &database.DBMigrationConfig{
+		Workers:      ctx.GlobalInt(utils.DBMigrationWorkersFlag.Name),
+		MaxRate:      ctx.GlobalInt(utils.DBMigrationMaxRateFlag.Name) * 1024 * 1024,
+		Verify:       ctx.GlobalBool(utils.DBMigrationVerifyFlag.Name),
+		ProgressFile: filepath.Join(ctx.GlobalString(utils.DataDirFlag.Name), "dbmigration.progress"),
+	}
 }
 
 func createDBManagerForMigration(ctx *cli.Context) (database.DBManager, database.DBManager, error) {
@@ -107,6 +125,23 @@ func createDBManagerForMigration(ctx *cli.Context) (database.DBManager, database
 }
 
 func createDBConfigForMigration(ctx *cli.Context) (*database.DBConfig, *database.DBConfig, error) {
+	srcCompression, err := database.ParseDBCompression(ctx.GlobalString(utils.DBCompressionFlag.Name))
+	if err != nil {
+		return nil, nil, err
+	}
+	dstCompression, err := database.ParseDBCompression(ctx.GlobalString(utils.DstDBCompressionFlag.Name))
+	if err != nil {
+		return nil, nil, err
+	}
+	srcEncryptionKey, err := loadEncryptionKey(ctx.GlobalString(utils.DBEncryptionKeyFlag.Name))
+	if err != nil {
+		return nil, nil, err
+	}
+	dstEncryptionKey, err := loadEncryptionKey(ctx.GlobalString(utils.DstDBEncryptionKeyFlag.Name))
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// srcDB
 	srcDBC := &database.DBConfig{
 		Dir:                ctx.GlobalString(utils.DataDirFlag.Name),
@@ -117,6 +152,8 @@ func createDBConfigForMigration(ctx *cli.Context) (*database.DBConfig, *database
 
 		LevelDBCacheSize:    ctx.GlobalInt(utils.LevelDBCacheSizeFlag.Name),
 		LevelDBCompression:  database.LevelDBCompressionType(ctx.GlobalInt(utils.LevelDBCompressionTypeFlag.Name)),
+		Compression:         srcCompression,
+		EncryptionKey:       srcEncryptionKey,
 		EnableDBPerfMetrics: !ctx.IsSet(utils.DBNoPerformanceMetricsFlag.Name),
 
 		DynamoDBConfig: &database.DynamoDBConfig{
@@ -142,6 +179,8 @@ func createDBConfigForMigration(ctx *cli.Context) (*database.DBConfig, *database
 
 		LevelDBCacheSize:    ctx.GlobalInt(utils.DstLevelDBCacheSizeFlag.Name),
 		LevelDBCompression:  database.LevelDBCompressionType(ctx.GlobalInt(utils.DstLevelDBCompressionTypeFlag.Name)),
+		Compression:         dstCompression,
+		EncryptionKey:       dstEncryptionKey,
 		EnableDBPerfMetrics: !ctx.IsSet(utils.DBNoPerformanceMetricsFlag.Name),
 
 		DynamoDBConfig: &database.DynamoDBConfig{
@@ -159,6 +198,3 @@ func createDBConfigForMigration(ctx *cli.Context) (*database.DBConfig, *database
 
 	return srcDBC, dstDBC, nil
 }
-
-// TODO When it is stopped, store previous db migration info.
-//      Continue migration on next call with the same setting.
@@ -52,11 +52,47 @@ var CommonNodeFlags = []cli.Flag{
 	utils.TxPoolLifetimeFlag,
 	utils.TxPoolKeepLocalsFlag,
 	utils.SyncModeFlag,
+	utils.SyncPivotDistanceFlag,
+	utils.SyncPivotPeersFlag,
+	utils.SyncCheckpointsFlag,
+	utils.SyncFromFlag,
 	utils.GCModeFlag,
 	utils.LightKDFFlag,
+	utils.KeyStoreKDFFlag,
+	utils.KeyStoreScryptNFlag,
+	utils.KeyStoreScryptRFlag,
+	utils.KeyStoreScryptPFlag,
+	utils.KeyStoreArgon2TimeFlag,
+	utils.KeyStoreArgon2MemoryFlag,
+	utils.KeyStoreArgon2ThreadsFlag,
+	utils.KMSAWSRegionFlag,
+	utils.KMSAWSEndpointFlag,
+	utils.KMSAWSAliasPrefixFlag,
+	utils.KMSGCPKeyRingFlag,
+	utils.KMSConsensusKeyFlag,
+	utils.VaultAddrFlag,
+	utils.VaultMountFlag,
+	utils.VaultPathFlag,
+	utils.VaultTokenFileFlag,
+	utils.VaultAppRoleMountFlag,
+	utils.VaultAppRoleIDFlag,
+	utils.VaultAppRoleSecretIDFileFlag,
+	utils.RemoteSignerURLFlag,
+	utils.RemoteSignerAttestationKeyFlag,
+	utils.RemoteSignerCAFileFlag,
+	utils.RemoteSignerCertFileFlag,
+	utils.RemoteSignerKeyFileFlag,
+	utils.RemoteSignerConsensusKeyFlag,
+	utils.PKCS11ModuleFlag,
+	utils.PKCS11SlotFlag,
+	utils.PKCS11TokenLabelFlag,
+	utils.PKCS11PINFileFlag,
+	utils.PKCS11ConsensusKeyFlag,
 	utils.SingleDBFlag,
 	utils.NumStateTrieShardsFlag,
 	utils.LevelDBCompressionTypeFlag,
+	utils.DBCompressionFlag,
+	utils.DBEncryptionKeyFlag,
 	utils.LevelDBNoBufferPoolFlag,
 	utils.DBNoPerformanceMetricsFlag,
 	utils.DynamoDBTableNameFlag,
@@ -65,16 +101,29 @@ var CommonNodeFlags = []cli.Flag{
 	utils.DynamoDBReadCapacityFlag,
 	utils.DynamoDBWriteCapacityFlag,
 	utils.DynamoDBReadOnlyFlag,
+	utils.DynamoDBWriteWorkersFlag,
+	utils.ColdStorageBucketFlag,
+	utils.ColdStorageRegionFlag,
+	utils.ColdStorageEndpointFlag,
+	utils.ColdStorageMinAgeFlag,
+	utils.ColdStorageMinSizeFlag,
+	utils.ColdStorageCacheSizeFlag,
+	utils.AncientSourceURLFlag,
+	utils.AncientSourceUntilFlag,
+	utils.AncientSourceCacheSizeFlag,
 	utils.LevelDBCacheSizeFlag,
 	utils.NoParallelDBWriteFlag,
 	utils.SenderTxHashIndexingFlag,
+	utils.SkipIntegrityCheckFlag,
 	utils.TrieMemoryCacheSizeFlag,
 	utils.TrieBlockIntervalFlag,
 	utils.TriesInMemoryFlag,
+	utils.TrieNodeRefCountFlag,
 	utils.CacheTypeFlag,
 	utils.CacheScaleFlag,
 	utils.CacheUsageLevelFlag,
 	utils.MemorySizeFlag,
+	utils.NodeProfileFlag,
 	utils.TrieNodeCacheTypeFlag,
 	utils.NumFetcherPrefetchWorkerFlag,
 	utils.UseSnapshotForPrefetchFlag,
@@ -90,12 +139,30 @@ var CommonNodeFlags = []cli.Flag{
 	utils.MaxConnectionsFlag,
 	utils.MaxRequestContentLengthFlag,
 	utils.MaxPendingPeersFlag,
+	utils.MaxInboundPerIPFlag,
+	utils.MaxInboundPerSubnetFlag,
+	utils.MaxPeerEgressBytesPerSecFlag,
+	utils.MaxEgressBytesPerSecFlag,
+	utils.CompressionThresholdFlag,
 	utils.TargetGasLimitFlag,
 	utils.NATFlag,
+	utils.IPPolicyFlag,
+	utils.ExtIPv6Flag,
+	utils.P2PTLSProfileFlag,
+	utils.P2PTLSCertFlag,
+	utils.P2PTLSKeyFlag,
+	utils.P2PTLSCAFlag,
+	utils.P2PTLSAllowedFlag,
 	utils.NoDiscoverFlag,
+	utils.DiscoveryTopicsFlag,
+	utils.DNSDiscoveryFlag,
 	utils.RWTimerWaitTimeFlag,
 	utils.RWTimerIntervalFlag,
 	utils.NetrestrictFlag,
+	utils.PeerListURLFlag,
+	utils.PeerListSignerFlag,
+	utils.PeerListKeyFileFlag,
+	utils.PeerListRefreshFlag,
 	utils.NodeKeyFileFlag,
 	utils.NodeKeyHexFlag,
 	utils.VMEnableDebugFlag,
@@ -108,11 +175,21 @@ var CommonNodeFlags = []cli.Flag{
 	utils.MetricsEnabledFlag,
 	utils.PrometheusExporterFlag,
 	utils.PrometheusExporterPortFlag,
+	utils.HealthEnabledFlag,
+	utils.HealthListenAddrFlag,
+	utils.HealthPortFlag,
+	utils.HealthMinPeersFlag,
+	utils.HealthMaxBlockAgeFlag,
+	utils.HealthAllowSyncingFlag,
+	utils.MultisigEnabledFlag,
+	utils.MultisigLifetimeFlag,
+	utils.MultisigMaxProposalsFlag,
 	utils.ExtraDataFlag,
 	utils.SrvTypeFlag,
 	utils.AutoRestartFlag,
 	utils.RestartTimeOutFlag,
 	utils.DaemonPathFlag,
+	utils.ShutdownGracePeriodFlag,
 	utils.ConfigFileFlag,
 	utils.APIFilterGetLogsMaxItemsFlag,
 	utils.APIFilterGetLogsDeadlineFlag,
@@ -156,6 +233,7 @@ var KCNFlags = []cli.Flag{
 	utils.BaobabFlag,
 	utils.BlockGenerationIntervalFlag,
 	utils.BlockGenerationTimeLimitFlag,
+	utils.SentryNodesFlag,
 }
 
 var KPNFlags = []cli.Flag{
@@ -165,6 +243,9 @@ var KPNFlags = []cli.Flag{
 	utils.CypressFlag,
 	utils.BaobabFlag,
 	utils.TxPoolSpamThrottlerDisableFlag,
+	utils.IstanbulHeaderOnlyVerificationFlag,
+	utils.SentryRelayFlag,
+	utils.ProxyUpstreamsFlag,
 }
 
 var KENFlags = []cli.Flag{
@@ -175,6 +256,7 @@ var KENFlags = []cli.Flag{
 	utils.MainBridgeFlag,
 	utils.MainBridgeListenPortFlag,
 	utils.KESNodeTypeServiceFlag,
+	utils.IstanbulHeaderOnlyVerificationFlag,
 	// ChainDataFetcher
 	utils.EnableChainDataFetcherFlag,
 	utils.ChainDataFetcherMode,
@@ -182,6 +264,9 @@ var KENFlags = []cli.Flag{
 	utils.ChainDataFetcherNumHandlers,
 	utils.ChainDataFetcherJobChannelSize,
 	utils.ChainDataFetcherChainEventSizeFlag,
+	utils.ChainDataFetcherFilterRulesFlag,
+	utils.ChainDataFetcherBackpressureFlag,
+	utils.ChainDataFetcherDeadLetterFlag,
 	utils.ChainDataFetcherKASDBHostFlag,
 	utils.ChainDataFetcherKASDBPortFlag,
 	utils.ChainDataFetcherKASDBNameFlag,
@@ -201,6 +286,29 @@ var KENFlags = []cli.Flag{
 	utils.ChainDataFetcherKafkaRequiredAcksFlag,
 	utils.ChainDataFetcherKafkaMessageVersionFlag,
 	utils.ChainDataFetcherKafkaProducerIdFlag,
+	utils.ChainDataFetcherKafkaMsgEncodingFlag,
+	utils.ChainDataFetcherKafkaSchemaRegistryFlag,
+	utils.ChainDataFetcherKafkaMaxMessagesPerSecondFlag,
+	utils.ChainDataFetcherPostgresDBHostFlag,
+	utils.ChainDataFetcherPostgresDBPortFlag,
+	utils.ChainDataFetcherPostgresDBNameFlag,
+	utils.ChainDataFetcherPostgresDBUserFlag,
+	utils.ChainDataFetcherPostgresDBPasswordFlag,
+	utils.ChainDataFetcherPostgresSSLModeFlag,
+	utils.ChainDataFetcherPostgresSchemaFlag,
+	utils.ChainDataFetcherPostgresTablePrefixFlag,
+	utils.ChainDataFetcherPostgresTablesFlag,
+	utils.ChainDataFetcherPostgresBatchSizeFlag,
+	utils.ChainDataFetcherElasticsearchURLsFlag,
+	utils.ChainDataFetcherElasticsearchUserFlag,
+	utils.ChainDataFetcherElasticsearchPasswordFlag,
+	utils.ChainDataFetcherElasticsearchIndexPrefixFlag,
+	utils.ChainDataFetcherElasticsearchIndexDateLayoutFlag,
+	utils.ChainDataFetcherElasticsearchTemplateFileFlag,
+	utils.ChainDataFetcherElasticsearchILMPolicyFlag,
+	utils.ChainDataFetcherElasticsearchIndicesFlag,
+	utils.ChainDataFetcherElasticsearchABIFilesFlag,
+	utils.ChainDataFetcherElasticsearchBulkSizeFlag,
 	// DBSyncer
 	utils.EnableDBSyncerFlag,
 	utils.DBHostFlag,
@@ -239,10 +347,28 @@ var KSCNFlags = []cli.Flag{
 	utils.ParentChainIDFlag,
 	utils.VTRecoveryFlag,
 	utils.VTRecoveryIntervalFlag,
+	utils.VTRecoveryRetryBackoffFlag,
+	utils.VTRecoveryRetryMaxBackoffFlag,
+	utils.VTRecoveryMaxAttemptsFlag,
 	utils.ServiceChainNewAccountFlag,
 	utils.ServiceChainAnchoringFlag,
 	utils.ServiceChainParentOperatorTxGasLimitFlag,
 	utils.ServiceChainChildOperatorTxGasLimitFlag,
+	utils.ServiceChainAnchoringGasPriceStrategyFlag,
+	utils.ServiceChainAnchoringGasPriceFlag,
+	utils.ServiceChainAnchoringGasPricePremiumFlag,
+	utils.ServiceChainAnchoringGasPriceCapFlag,
+	utils.ServiceChainAnchoringRetryGasBumpFlag,
+	utils.ServiceChainAnchoringRetryBackoffFlag,
+	utils.ServiceChainAnchoringRetryMaxBackoffFlag,
+	utils.ServiceChainAnchoringMaxRetriesFlag,
+	utils.ServiceChainAnchoringNonceRepairFlag,
+	utils.ServiceChainAnchoringBatchFlag,
+	utils.ServiceChainAnchoringBatchMaxBlocksFlag,
+	utils.ServiceChainParentHealthCheckIntervalFlag,
+	utils.ServiceChainParentHealthTimeoutFlag,
+	utils.ServiceChainParentQuorumFlag,
+	utils.ServiceChainFeeDelegationPolicyFlag,
 	// KAS
 	utils.KASServiceChainAnchorFlag,
 	utils.KASServiceChainAnchorPeriodFlag,
@@ -270,10 +396,28 @@ var KSPNFlags = []cli.Flag{
 	utils.ParentChainIDFlag,
 	utils.VTRecoveryFlag,
 	utils.VTRecoveryIntervalFlag,
+	utils.VTRecoveryRetryBackoffFlag,
+	utils.VTRecoveryRetryMaxBackoffFlag,
+	utils.VTRecoveryMaxAttemptsFlag,
 	utils.ServiceChainNewAccountFlag,
 	utils.ServiceChainAnchoringFlag,
 	utils.ServiceChainParentOperatorTxGasLimitFlag,
 	utils.ServiceChainChildOperatorTxGasLimitFlag,
+	utils.ServiceChainAnchoringGasPriceStrategyFlag,
+	utils.ServiceChainAnchoringGasPriceFlag,
+	utils.ServiceChainAnchoringGasPricePremiumFlag,
+	utils.ServiceChainAnchoringGasPriceCapFlag,
+	utils.ServiceChainAnchoringRetryGasBumpFlag,
+	utils.ServiceChainAnchoringRetryBackoffFlag,
+	utils.ServiceChainAnchoringRetryMaxBackoffFlag,
+	utils.ServiceChainAnchoringMaxRetriesFlag,
+	utils.ServiceChainAnchoringNonceRepairFlag,
+	utils.ServiceChainAnchoringBatchFlag,
+	utils.ServiceChainAnchoringBatchMaxBlocksFlag,
+	utils.ServiceChainParentHealthCheckIntervalFlag,
+	utils.ServiceChainParentHealthTimeoutFlag,
+	utils.ServiceChainParentQuorumFlag,
+	utils.ServiceChainFeeDelegationPolicyFlag,
 	// KAS
 	utils.KASServiceChainAnchorFlag,
 	utils.KASServiceChainAnchorPeriodFlag,
@@ -297,10 +441,28 @@ var KSENFlags = []cli.Flag{
 	utils.ParentChainIDFlag,
 	utils.VTRecoveryFlag,
 	utils.VTRecoveryIntervalFlag,
+	utils.VTRecoveryRetryBackoffFlag,
+	utils.VTRecoveryRetryMaxBackoffFlag,
+	utils.VTRecoveryMaxAttemptsFlag,
 	utils.ServiceChainAnchoringFlag,
 	utils.KESNodeTypeServiceFlag,
 	utils.ServiceChainParentOperatorTxGasLimitFlag,
 	utils.ServiceChainChildOperatorTxGasLimitFlag,
+	utils.ServiceChainAnchoringGasPriceStrategyFlag,
+	utils.ServiceChainAnchoringGasPriceFlag,
+	utils.ServiceChainAnchoringGasPricePremiumFlag,
+	utils.ServiceChainAnchoringGasPriceCapFlag,
+	utils.ServiceChainAnchoringRetryGasBumpFlag,
+	utils.ServiceChainAnchoringRetryBackoffFlag,
+	utils.ServiceChainAnchoringRetryMaxBackoffFlag,
+	utils.ServiceChainAnchoringMaxRetriesFlag,
+	utils.ServiceChainAnchoringNonceRepairFlag,
+	utils.ServiceChainAnchoringBatchFlag,
+	utils.ServiceChainAnchoringBatchMaxBlocksFlag,
+	utils.ServiceChainParentHealthCheckIntervalFlag,
+	utils.ServiceChainParentHealthTimeoutFlag,
+	utils.ServiceChainParentQuorumFlag,
+	utils.ServiceChainFeeDelegationPolicyFlag,
 	// ChainDataFetcher
 	utils.EnableChainDataFetcherFlag,
 	utils.ChainDataFetcherMode,
@@ -308,6 +470,9 @@ var KSENFlags = []cli.Flag{
 	utils.ChainDataFetcherNumHandlers,
 	utils.ChainDataFetcherJobChannelSize,
 	utils.ChainDataFetcherChainEventSizeFlag,
+	utils.ChainDataFetcherFilterRulesFlag,
+	utils.ChainDataFetcherBackpressureFlag,
+	utils.ChainDataFetcherDeadLetterFlag,
 	utils.ChainDataFetcherKASDBHostFlag,
 	utils.ChainDataFetcherKASDBPortFlag,
 	utils.ChainDataFetcherKASDBNameFlag,
@@ -327,6 +492,29 @@ var KSENFlags = []cli.Flag{
 	utils.ChainDataFetcherKafkaRequiredAcksFlag,
 	utils.ChainDataFetcherKafkaMessageVersionFlag,
 	utils.ChainDataFetcherKafkaProducerIdFlag,
+	utils.ChainDataFetcherKafkaMsgEncodingFlag,
+	utils.ChainDataFetcherKafkaSchemaRegistryFlag,
+	utils.ChainDataFetcherKafkaMaxMessagesPerSecondFlag,
+	utils.ChainDataFetcherPostgresDBHostFlag,
+	utils.ChainDataFetcherPostgresDBPortFlag,
+	utils.ChainDataFetcherPostgresDBNameFlag,
+	utils.ChainDataFetcherPostgresDBUserFlag,
+	utils.ChainDataFetcherPostgresDBPasswordFlag,
+	utils.ChainDataFetcherPostgresSSLModeFlag,
+	utils.ChainDataFetcherPostgresSchemaFlag,
+	utils.ChainDataFetcherPostgresTablePrefixFlag,
+	utils.ChainDataFetcherPostgresTablesFlag,
+	utils.ChainDataFetcherPostgresBatchSizeFlag,
+	utils.ChainDataFetcherElasticsearchURLsFlag,
+	utils.ChainDataFetcherElasticsearchUserFlag,
+	utils.ChainDataFetcherElasticsearchPasswordFlag,
+	utils.ChainDataFetcherElasticsearchIndexPrefixFlag,
+	utils.ChainDataFetcherElasticsearchIndexDateLayoutFlag,
+	utils.ChainDataFetcherElasticsearchTemplateFileFlag,
+	utils.ChainDataFetcherElasticsearchILMPolicyFlag,
+	utils.ChainDataFetcherElasticsearchIndicesFlag,
+	utils.ChainDataFetcherElasticsearchABIFilesFlag,
+	utils.ChainDataFetcherElasticsearchBulkSizeFlag,
 	// KAS
 	utils.KASServiceChainAnchorFlag,
 	utils.KASServiceChainAnchorPeriodFlag,
@@ -364,10 +552,15 @@ var DBMigrationFlags = []cli.Flag{
 	utils.DstDataDirFlag,
 	utils.DstSingleDBFlag,
 	utils.DstLevelDBCompressionTypeFlag,
+	utils.DstDBCompressionFlag,
+	utils.DstDBEncryptionKeyFlag,
 	utils.DstNumStateTrieShardsFlag,
 	utils.DstDynamoDBTableNameFlag,
 	utils.DstDynamoDBRegionFlag,
 	utils.DstDynamoDBIsProvisionedFlag,
 	utils.DstDynamoDBReadCapacityFlag,
 	utils.DstDynamoDBWriteCapacityFlag,
+	utils.DBMigrationWorkersFlag,
+	utils.DBMigrationMaxRateFlag,
+	utils.DBMigrationVerifyFlag,
 }
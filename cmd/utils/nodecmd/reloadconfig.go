@@ -0,0 +1,298 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/klaytn/klaytn/api/debug"
+	"github.com/klaytn/klaytn/cmd/utils"
+	"github.com/klaytn/klaytn/log"
+	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/node"
+	"github.com/klaytn/klaytn/node/cn"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// runtimeConfig is the configuration of the package level settings, which are
+// applied when the node starts and when the configuration file is reloaded.
+type runtimeConfig struct {
+	Verbosity int    // Log level of all the modules: 0=silent, 1=error, 2=warn, 3=info, 4=debug, 5=detail
+	Vmodule   string // Log levels of the modules, e.g. "klay/*=5,p2p=4"
+
+	WSMaxSubscriptionPerConn int32 // Maximum number of subscriptions of a websocket connection
+	WSMaxConnections         int32 // Maximum number of websocket connections
+}
+
+var defaultRuntimeConfig = runtimeConfig{
+	Verbosity:                int(log.LvlInfo),
+	WSMaxSubscriptionPerConn: rpc.MaxSubscriptionPerWSConn,
+	WSMaxConnections:         rpc.MaxWebsocketConnections,
+}
+
+// setRuntimeConfig applies the flags to the runtime configuration, and applies
+// it to the packages.
+func setRuntimeConfig(ctx *cli.Context, cfg *runtimeConfig) {
+	if flagApplied(ctx, debug.VerbosityFlagName) {
+		cfg.Verbosity = ctx.GlobalInt(debug.VerbosityFlagName)
+	}
+	if flagApplied(ctx, debug.VmoduleFlagName) {
+		cfg.Vmodule = ctx.GlobalString(debug.VmoduleFlagName)
+	}
+	if flagApplied(ctx, utils.WSMaxSubscriptionPerConn.Name) {
+		cfg.WSMaxSubscriptionPerConn = int32(ctx.GlobalInt(utils.WSMaxSubscriptionPerConn.Name))
+	}
+	if flagApplied(ctx, utils.WSMaxConnections.Name) {
+		cfg.WSMaxConnections = int32(ctx.GlobalInt(utils.WSMaxConnections.Name))
+	}
+	if err := applyLogConfig(cfg); err != nil {
+		log.Fatalf("Failed to apply the log levels: %v", err)
+	}
+	applyRPCConfig(cfg)
+}
+
+func applyLogConfig(cfg *runtimeConfig) error {
+	if err := debug.Handler.Verbosity(cfg.Verbosity); err != nil {
+		return err
+	}
+	return debug.Handler.Vmodule(cfg.Vmodule)
+}
+
+func applyRPCConfig(cfg *runtimeConfig) {
+	rpc.MaxSubscriptionPerWSConn = cfg.WSMaxSubscriptionPerConn
+	rpc.MaxWebsocketConnections = cfg.WSMaxConnections
+}
+
+// reloadableConfigs are the settings which can be changed at runtime. The keys
+// of a group are applied together if any of them is changed.
+var reloadableConfigs = []struct {
+	keys  []string
+	apply func(stack *node.Node, cfg *klayConfig) error
+}{
+	{
+		keys: []string{"Runtime.Verbosity", "Runtime.Vmodule"},
+		apply: func(stack *node.Node, cfg *klayConfig) error {
+			return applyLogConfig(&cfg.Runtime)
+		},
+	},
+	{
+		keys: []string{"Runtime.WSMaxSubscriptionPerConn", "Runtime.WSMaxConnections"},
+		apply: func(stack *node.Node, cfg *klayConfig) error {
+			applyRPCConfig(&cfg.Runtime)
+			return nil
+		},
+	},
+	{
+		keys: []string{"CN.TxPool.ExecSlotsAccount", "CN.TxPool.ExecSlotsAll", "CN.TxPool.NonExecSlotsAccount", "CN.TxPool.NonExecSlotsAll"},
+		apply: func(stack *node.Node, cfg *klayConfig) error {
+			var cnService *cn.CN
+			if err := stack.Service(&cnService); err != nil {
+				return err
+			}
+			pool, ok := cnService.TxPool().(interface {
+				SetSlotLimits(execAccount, execAll, nonExecAccount, nonExecAll uint64)
+			})
+			if !ok {
+				return errors.New("the txpool does not support changing the limits")
+			}
+			c := cfg.CN.TxPool
+			pool.SetSlotLimits(c.ExecSlotsAccount, c.ExecSlotsAll, c.NonExecSlotsAccount, c.NonExecSlotsAll)
+			return nil
+		},
+	},
+	{
+		keys: []string{"Node.P2P.MaxInboundPerIP", "Node.P2P.MaxInboundPerSubnet"},
+		apply: func(stack *node.Node, cfg *klayConfig) error {
+			server := stack.Server()
+			if server == nil {
+				return node.ErrNodeStopped
+			}
+			return server.SetInboundLimits(cfg.Node.P2P.MaxInboundPerIP, cfg.Node.P2P.MaxInboundPerSubnet)
+		},
+	},
+	{
+		keys: []string{"Node.P2P.MaxPeerEgressBytesPerSec", "Node.P2P.MaxEgressBytesPerSec"},
+		apply: func(stack *node.Node, cfg *klayConfig) error {
+			server := stack.Server()
+			if server == nil {
+				return node.ErrNodeStopped
+			}
+			return server.SetEgressLimits(cfg.Node.P2P.MaxPeerEgressBytesPerSec, cfg.Node.P2P.MaxEgressBytesPerSec)
+		},
+	},
+}
+
+// configReloader reloads the configuration file, and applies the changed
+// settings which can be changed at runtime. The other changed settings are
+// reported to require restart. The configuration is rebuilt in the same order
+// as when the node starts, i.e. the defaults, the file and then the flags, so
+// the settings given by the flags are kept and the changes of them in the file
+// are reported to be overridden.
+type configReloader struct {
+	mu     sync.Mutex
+	file   string
+	stack  *node.Node
+	values map[string]interface{} // the settings of the file applied to the node, keyed by the TOML path
+	flags  map[string]interface{} // the settings overridden by the flags, keyed by the TOML path
+}
+
+// newConfigReloader returns the reloader of the configuration file. The running
+// configuration is the one the node started with, from which the settings
+// overridden by the flags are found.
+func newConfigReloader(file string, stack *node.Node, running *klayConfig) (*configReloader, error) {
+	cfg := defaultKlayConfig()
+	if err := loadConfig(file, &cfg); err != nil {
+		return nil, err
+	}
+	values := flattenConfig(&cfg)
+	flags := make(map[string]interface{})
+	if running != nil {
+		for key, value := range flattenConfig(running) {
+			if !reflect.DeepEqual(values[key], value) {
+				flags[key] = value
+			}
+		}
+	}
+	return &configReloader{file: file, stack: stack, values: values, flags: flags}, nil
+}
+
+func (r *configReloader) reload() (*node.ConfigReloadResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg := defaultKlayConfig()
+	if err := loadConfig(r.file, &cfg); err != nil {
+		return nil, err
+	}
+	values := flattenConfig(&cfg)
+	for key, value := range r.flags {
+		if err := setConfigValue(&cfg, key, value); err != nil {
+			return nil, err
+		}
+	}
+	changed := make(map[string]bool)
+	for key, value := range values {
+		if old, ok := r.values[key]; !ok || !reflect.DeepEqual(old, value) {
+			changed[key] = true
+		}
+	}
+	for key := range r.values {
+		if _, ok := values[key]; !ok {
+			changed[key] = true
+		}
+	}
+
+	result := &node.ConfigReloadResult{Applied: []string{}, RestartRequired: []string{}, OverriddenByFlags: []string{}}
+	for key := range changed {
+		if _, ok := r.flags[key]; ok {
+			r.values[key] = values[key]
+			result.OverriddenByFlags = append(result.OverriddenByFlags, key)
+			delete(changed, key)
+		}
+	}
+	for _, group := range reloadableConfigs {
+		var keys []string
+		for _, key := range group.keys {
+			if changed[key] {
+				keys = append(keys, key)
+				delete(changed, key)
+			}
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		if err := group.apply(r.stack, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to apply %s: %v", strings.Join(keys, ", "), err)
+		}
+		for _, key := range keys {
+			r.values[key] = values[key]
+		}
+		result.Applied = append(result.Applied, keys...)
+	}
+	for key := range changed {
+		result.RestartRequired = append(result.RestartRequired, key)
+	}
+	sort.Strings(result.Applied)
+	sort.Strings(result.RestartRequired)
+	sort.Strings(result.OverriddenByFlags)
+	return result, nil
+}
+
+// setConfigValue sets the setting of the configuration keyed by the TOML path,
+// which is flattened by flattenConfig.
+func setConfigValue(cfg *klayConfig, key string, value interface{}) error {
+	v := reflect.ValueOf(cfg).Elem()
+	for _, name := range strings.Split(key, ".") {
+		if v.Kind() == reflect.Ptr && v.Type().Elem().Kind() == reflect.Struct {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return fmt.Errorf("invalid config key %q", key)
+		}
+		if v = v.FieldByName(name); !v.IsValid() {
+			return fmt.Errorf("invalid config key %q", key)
+		}
+	}
+	if value == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	v.Set(reflect.ValueOf(value))
+	return nil
+}
+
+// flattenConfig returns the settings of the configuration keyed by the TOML
+// paths, e.g. "CN.TxPool.ExecSlotsAll".
+func flattenConfig(cfg *klayConfig) map[string]interface{} {
+	values := make(map[string]interface{})
+	flattenValue("", reflect.ValueOf(cfg).Elem(), values)
+	return values
+}
+
+func flattenValue(key string, v reflect.Value, values map[string]interface{}) {
+	if v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		flattened := false
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" || field.Tag.Get("toml") == "-" {
+				continue
+			}
+			name := field.Name
+			if key != "" {
+				name = key + "." + name
+			}
+			flattenValue(name, v.Field(i), values)
+			flattened = true
+		}
+		if flattened {
+			return
+		}
+	}
+	// The values without exported fields, e.g. big.Int, are compared as a whole.
+	values[key] = v.Interface()
+}
@@ -0,0 +1,148 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/klaytn/klaytn/networks/rpc"
+)
+
+func TestConfigReloader_Reload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klay-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defer func(old int32) { rpc.MaxSubscriptionPerWSConn = old }(rpc.MaxSubscriptionPerWSConn)
+
+	file := filepath.Join(dir, "klay.toml")
+	ioutil.WriteFile(file, []byte(`
+[CN]
+NetworkId = 1000
+
+[Runtime]
+WSMaxSubscriptionPerConn = 100
+`), 0o600)
+
+	reloader, err := newConfigReloader(file, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is changed.
+	result, err := reloader.reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Applied) != 0 || len(result.RestartRequired) != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	ioutil.WriteFile(file, []byte(`
+[CN]
+NetworkId = 2000
+
+[Runtime]
+WSMaxSubscriptionPerConn = 200
+`), 0o600)
+
+	result, err = reloader.reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Runtime.WSMaxSubscriptionPerConn"}; !reflect.DeepEqual(result.Applied, want) {
+		t.Errorf("applied mismatch: have %v, want %v", result.Applied, want)
+	}
+	if want := []string{"CN.NetworkId"}; !reflect.DeepEqual(result.RestartRequired, want) {
+		t.Errorf("restart required mismatch: have %v, want %v", result.RestartRequired, want)
+	}
+	if rpc.MaxSubscriptionPerWSConn != 200 {
+		t.Errorf("subscription limit mismatch: have %d, want 200", rpc.MaxSubscriptionPerWSConn)
+	}
+
+	// The applied settings are not reported again, but the others keep requiring restart.
+	result, err = reloader.reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Applied) != 0 {
+		t.Errorf("unexpected applied settings: %v", result.Applied)
+	}
+	if want := []string{"CN.NetworkId"}; !reflect.DeepEqual(result.RestartRequired, want) {
+		t.Errorf("restart required mismatch: have %v, want %v", result.RestartRequired, want)
+	}
+}
+
+func TestConfigReloader_ReloadWithFlags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klay-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defer func(old int32) { rpc.MaxSubscriptionPerWSConn = old }(rpc.MaxSubscriptionPerWSConn)
+	defer func(old int32) { rpc.MaxWebsocketConnections = old }(rpc.MaxWebsocketConnections)
+
+	file := filepath.Join(dir, "klay.toml")
+	ioutil.WriteFile(file, []byte(`
+[Runtime]
+WSMaxSubscriptionPerConn = 100
+WSMaxConnections = 100
+`), 0o600)
+
+	// The node started with the connection limit given by the flag.
+	running := defaultKlayConfig()
+	if err := loadConfig(file, &running); err != nil {
+		t.Fatal(err)
+	}
+	running.Runtime.WSMaxConnections = 7
+
+	reloader, err := newConfigReloader(file, nil, &running)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ioutil.WriteFile(file, []byte(`
+[Runtime]
+WSMaxSubscriptionPerConn = 200
+WSMaxConnections = 300
+`), 0o600)
+
+	result, err := reloader.reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Runtime.WSMaxSubscriptionPerConn"}; !reflect.DeepEqual(result.Applied, want) {
+		t.Errorf("applied mismatch: have %v, want %v", result.Applied, want)
+	}
+	if want := []string{"Runtime.WSMaxConnections"}; !reflect.DeepEqual(result.OverriddenByFlags, want) {
+		t.Errorf("overridden mismatch: have %v, want %v", result.OverriddenByFlags, want)
+	}
+	// The setting given by the flag is kept while the other one of the group is applied.
+	if rpc.MaxSubscriptionPerWSConn != 200 {
+		t.Errorf("subscription limit mismatch: have %d, want 200", rpc.MaxSubscriptionPerWSConn)
+	}
+	if rpc.MaxWebsocketConnections != 7 {
+		t.Errorf("connection limit mismatch: have %d, want 7", rpc.MaxWebsocketConnections)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/state/pruner"
+	"github.com/klaytn/klaytn/cmd/utils"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var SnapshotCommand = cli.Command{
+	Name:     "snapshot",
+	Usage:    "Commands to roll the chain data back to a previous block",
+	Category: "BLOCKCHAIN COMMANDS",
+	Subcommands: []cli.Command{
+		{
+			Name:      "rewind",
+			Usage:     "Roll the chain back to a previous block",
+			ArgsUsage: "<blockNumber>",
+			Action:    utils.MigrateFlags(rewindChain),
+			Flags:     dbInspectFlags,
+			Description: `
+The rewind command rolls the chain back to the given block, for recovering from a
+bad import without re-initializing the database from the genesis. If the state of
+the block is not available, e.g. on a non-archive node, the chain is rolled back
+further to the latest block with its state.
+
+The canonical hashes, headers, bodies, receipts and transaction lookup entries of
+the following blocks are deleted, including the data moved to the cold storage.
+The blocks are fetched from peers again on the next start, and the state snapshot
+is regenerated if it is ahead of the new head block. The same can be done on a
+running node by debug.setHead in the console.
+
+Note: Do not rewind the chain while a node is executing.`,
+		},
+		{
+			Name:      "prune-state",
+			Usage:     "Delete the trie nodes not reachable from a recent state",
+			ArgsUsage: "[<root>]",
+			Action:    utils.MigrateFlags(pruneState),
+			Flags:     append(dbInspectFlags, utils.StatePruneBloomSizeFlag),
+			Description: `
+The prune-state command deletes the trie nodes of the stale states from the
+database of a stopped node. The state of the given root, or the most recent state
+found in the state snapshot if the root is not given, and the genesis state are
+kept, and every other trie node is deleted. The snapshot must have been generated
+by running the node with --snapshot.
+
+The nodes of the kept state are marked in a bloom filter of --bloomfilter.size
+megabytes, which is stored in the data directory before the sweeping begins. If
+the command is interrupted during the sweeping, running it again resumes from the
+last recorded position. The progress is logged periodically.
+
+If the kept state is older than the head block, the node rolls back to the block
+of the kept state on the next start.
+
+Note: Do not prune the state while a node is executing.`,
+		},
+	},
+}
+
+func rewindChain(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("invalid arguments, usage: %s", ctx.Command.ArgsUsage)
+	}
+	number, err := strconv.ParseUint(ctx.Args().First(), 0, 64)
+	if err != nil {
+		return fmt.Errorf("invalid block number %q: %v", ctx.Args().First(), err)
+	}
+	chainDB, err := openChainDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer chainDB.Close()
+
+	head, err := blockchain.RewindDatabase(chainDB, number)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("The head block is rewound to #%d. The following blocks will be fetched from peers on the next start.\n", head)
+	return nil
+}
+
+func pruneState(ctx *cli.Context) error {
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("invalid arguments, usage: %s", ctx.Command.ArgsUsage)
+	}
+	var root common.Hash
+	if ctx.NArg() == 1 {
+		b, err := hexutil.Decode(ctx.Args().First())
+		if err != nil || len(b) != common.HashLength {
+			return fmt.Errorf("invalid state root %q", ctx.Args().First())
+		}
+		root = common.BytesToHash(b)
+	}
+	stack, chainDB, err := openChainDBWithStack(ctx)
+	if err != nil {
+		return err
+	}
+	defer chainDB.Close()
+
+	p, err := pruner.NewPruner(chainDB, pruner.Config{
+		Datadir:   stack.InstanceDir(),
+		BloomSize: ctx.GlobalUint64(utils.StatePruneBloomSizeFlag.Name),
+	})
+	if err != nil {
+		return err
+	}
+	if err := p.Prune(root); err != nil {
+		return err
+	}
+	fmt.Println("The state is pruned.")
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nodecmd
+
+import (
+	"fmt"
+
+	"github.com/klaytn/klaytn/cmd/utils"
+	"github.com/klaytn/klaytn/node/cn"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// GetVerifyCommand returns the command re-executing the blocks of the data
+// directory. It takes the node flags to set up the chain as the node does.
+func GetVerifyCommand(nodeFlags []cli.Flag) cli.Command {
+	return cli.Command{
+		Action:   utils.MigrateFlags(verifyBlocks),
+		Name:     "verify",
+		Usage:    "Re-execute the blocks in the data directory and check the results",
+		Flags:    append(append([]cli.Flag{}, nodeFlags...), utils.VerifyBlocksFromFlag, utils.VerifyBlocksToFlag),
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The verify command re-executes the blocks from --from to --to on the state of the
+block before --from, and checks the header and the seal, the transaction root, the
+state root, the receipt root and the stored receipts of each block. It stops at
+the first block failing the check, and exits with an error. No network access is
+required, so it can be used for validating a backup of the data directory or for
+investigating a suspected local corruption.
+
+The state of the block before --from must be in the database. On a node without
+--gcmode archive, the states are kept only for some recent blocks and the blocks
+at the interval of --state.block-interval.
+
+The re-executed states are kept in memory and the database is not modified. Give
+the same configuration flags as the node, such as --datadir and --dbtype.
+
+Note: Do not verify the blocks while a node is executing.`,
+	}
+}
+
+func verifyBlocks(ctx *cli.Context) error {
+	stack, cfg := makeConfigNode(ctx)
+	setServiceConfigs(ctx, &cfg)
+
+	from, to := ctx.GlobalUint64(utils.VerifyBlocksFromFlag.Name), ctx.GlobalUint64(utils.VerifyBlocksToFlag.Name)
+	if err := cn.VerifyBlocks(stack.ServiceContext(), &cfg.CN, from, to); err != nil {
+		return err
+	}
+	fmt.Println("The blocks are verified.")
+	return nil
+}
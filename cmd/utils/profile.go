@@ -0,0 +1,179 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pbnjay/memory"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// nodeProfile is a preset of the flags for a type of node deployment. The sizes
+// are derived from the memory and the CPUs of the machine.
+type nodeProfile struct {
+	nodeType  string // Node type the profile is used for
+	gcMode    string // Blockchain garbage collection mode ("" = not set)
+	gcPercent int    // Garbage collection target percentage of the Go runtime
+
+	// Shares of the memory (%) for the trie node cache and the LevelDB cache
+	trieCacheShare, levelDBCacheShare int
+
+	// flags returns the values of the other flags of the profile
+	flags func(memGB, cpus int) map[string]int
+}
+
+// nodeProfiles are the profiles selectable by --profile.
+var nodeProfiles = map[string]nodeProfile{
+	"cn": {
+		nodeType: "cn", gcPercent: 100, trieCacheShare: 25, levelDBCacheShare: 5,
+		flags: func(memGB, cpus int) map[string]int {
+			return map[string]int{
+				MaxConnectionsFlag.Name:        100,
+				TxPoolExecSlotsAllFlag.Name:    16384,
+				TxPoolNonExecSlotsAllFlag.Name: 4096,
+				RPCConcurrencyLimit.Name:       cpus * 8,
+				WSMaxConnections.Name:          100,
+			}
+		},
+	},
+	"pn": {
+		nodeType: "pn", gcPercent: 100, trieCacheShare: 15, levelDBCacheShare: 5,
+		flags: func(memGB, cpus int) map[string]int {
+			return map[string]int{
+				MaxConnectionsFlag.Name:        200 + 50*(memGB/32),
+				TxPoolExecSlotsAllFlag.Name:    16384,
+				TxPoolNonExecSlotsAllFlag.Name: 4096,
+				RPCConcurrencyLimit.Name:       cpus * 8,
+				WSMaxConnections.Name:          100,
+			}
+		},
+	},
+	"en-archive": {
+		nodeType: "en", gcMode: "archive", gcPercent: 80, trieCacheShare: 30, levelDBCacheShare: 10,
+		flags: func(memGB, cpus int) map[string]int {
+			return map[string]int{
+				MaxConnectionsFlag.Name:        50,
+				TxPoolExecSlotsAllFlag.Name:    4096,
+				TxPoolNonExecSlotsAllFlag.Name: 1024,
+				RPCConcurrencyLimit.Name:       cpus * 32,
+				WSMaxConnections.Name:          1000,
+			}
+		},
+	},
+	"en-rpc": {
+		nodeType: "en", gcMode: "full", gcPercent: 150, trieCacheShare: 20, levelDBCacheShare: 8,
+		flags: func(memGB, cpus int) map[string]int {
+			return map[string]int{
+				MaxConnectionsFlag.Name:        50,
+				TxPoolExecSlotsAllFlag.Name:    8192,
+				TxPoolNonExecSlotsAllFlag.Name: 2048,
+				RPCConcurrencyLimit.Name:       cpus * 64,
+				WSMaxConnections.Name:          cpus * 250,
+			}
+		},
+	},
+}
+
+// nodeProfileNames returns the names of the profiles in the alphabetical order.
+func nodeProfileNames() []string {
+	names := make([]string, 0, len(nodeProfiles))
+	for name := range nodeProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// profileFlagValues returns the flag values of the profile for the machine.
+func profileFlagValues(profile nodeProfile, memGB, cpus int) map[string]string {
+	memMiB := memGB * 1024
+	values := map[string]string{
+		TrieNodeCacheLimitFlag.Name: strconv.Itoa(memMiB * profile.trieCacheShare / 100),
+		LevelDBCacheSizeFlag.Name:   strconv.Itoa(memMiB * profile.levelDBCacheShare / 100),
+	}
+	for name, value := range profile.flags(memGB, cpus) {
+		values[name] = strconv.Itoa(value)
+	}
+	if profile.gcMode != "" {
+		values[GCModeFlag.Name] = profile.gcMode
+	}
+	return values
+}
+
+// ApplyNodeProfile applies the profile given by --profile. The values of the
+// profile are set to the flags which are not given on the command line or by the
+// environment variables, so any of them can be overridden. The Go runtime GC
+// percent is set unless GOGC is given. The effective values are logged.
+func ApplyNodeProfile(ctx *cli.Context) error {
+	name := ctx.GlobalString(NodeProfileFlag.Name)
+	if name == "" {
+		return nil
+	}
+	profile, ok := nodeProfiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile %q, available profiles: %s", name, strings.Join(nodeProfileNames(), ", "))
+	}
+	nodeType := NodeTypeFlag.Value
+	if ctx.GlobalIsSet(NodeTypeFlag.Name) {
+		nodeType = ctx.GlobalString(NodeTypeFlag.Name)
+	}
+	if nodeType != profile.nodeType {
+		return fmt.Errorf("profile %q is for the node type %s, but the node type is %s", name, profile.nodeType, nodeType)
+	}
+
+	memGB := int(memory.TotalMemory() / 1024 / 1024 / 1024)
+	if ctx.GlobalIsSet(MemorySizeFlag.Name) {
+		memGB = ctx.GlobalInt(MemorySizeFlag.Name)
+	}
+	if memGB <= 0 {
+		return fmt.Errorf("failed to detect the memory size, give --%s", MemorySizeFlag.Name)
+	}
+	cpus := runtime.NumCPU()
+	logger.Info("Applying the node profile", "profile", name, "memory(GB)", memGB, "cpus", cpus)
+
+	values := profileFlagValues(profile, memGB, cpus)
+	names := make([]string, 0, len(values))
+	for flag := range values {
+		names = append(names, flag)
+	}
+	sort.Strings(names)
+	for _, flag := range names {
+		if ctx.GlobalIsSet(flag) {
+			logger.Info("Profile setting overridden", "flag", flag, "value", ctx.GlobalString(flag), "profile", values[flag])
+			continue
+		}
+		if err := ctx.GlobalSet(flag, values[flag]); err != nil {
+			return fmt.Errorf("failed to set --%s of profile %q: %v", flag, name, err)
+		}
+		logger.Info("Profile setting", "flag", flag, "value", values[flag])
+	}
+
+	if gogc, ok := os.LookupEnv("GOGC"); ok {
+		logger.Info("Profile setting overridden", "setting", "GC percent", "value", gogc, "profile", profile.gcPercent)
+	} else {
+		debug.SetGCPercent(profile.gcPercent)
+		logger.Info("Profile setting", "setting", "GC percent", "value", profile.gcPercent)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// TestApplyNodeProfile checks that the profile sets the flags which are not
+// given on the command line.
+func TestApplyNodeProfile(t *testing.T) {
+	// GOGC keeps the GC percent of the test process.
+	os.Setenv("GOGC", "100")
+	defer os.Unsetenv("GOGC")
+
+	run := func(args ...string) (map[string]string, error) {
+		values := make(map[string]string)
+		app := cli.NewApp()
+		app.Flags = []cli.Flag{
+			NodeProfileFlag, NodeTypeFlag, MemorySizeFlag, GCModeFlag,
+			TrieNodeCacheLimitFlag, LevelDBCacheSizeFlag, MaxConnectionsFlag,
+			TxPoolExecSlotsAllFlag, TxPoolNonExecSlotsAllFlag, RPCConcurrencyLimit, WSMaxConnections,
+		}
+		app.Before = ApplyNodeProfile
+		app.Action = func(ctx *cli.Context) error {
+			for _, name := range []string{GCModeFlag.Name, TrieNodeCacheLimitFlag.Name, MaxConnectionsFlag.Name} {
+				values[name] = ctx.GlobalString(name)
+			}
+			return nil
+		}
+		return values, app.Run(append([]string{"app"}, args...))
+	}
+
+	values, err := run("--profile", "en-archive", "--cache.memory", "64", "--maxconnections", "10")
+	assert.NoError(t, err)
+	assert.Equal(t, "archive", values[GCModeFlag.Name])
+	assert.Equal(t, "19660", values[TrieNodeCacheLimitFlag.Name]) // 30% of 64GiB
+	assert.Equal(t, "10", values[MaxConnectionsFlag.Name])
+
+	// No flag is changed without a profile.
+	values, err = run("--cache.memory", "64")
+	assert.NoError(t, err)
+	assert.Equal(t, GCModeFlag.Value, values[GCModeFlag.Name])
+	assert.Equal(t, "-1", values[TrieNodeCacheLimitFlag.Name])
+
+	_, err = run("--profile", "en-full")
+	assert.Error(t, err)
+	_, err = run("--profile", "cn", "--nodetype", "en")
+	assert.Error(t, err)
+}
@@ -0,0 +1,135 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/klaytn/klaytn/node"
+	"github.com/klaytn/klaytn/node/cn"
+)
+
+// The states sent to the service manager by the sd_notify protocol.
+const (
+	sdNotifyReady     = "READY=1"
+	sdNotifyReloading = "RELOADING=1"
+	sdNotifyStopping  = "STOPPING=1"
+	sdNotifyWatchdog  = "WATCHDOG=1"
+)
+
+// sdWatchdogProbeKey is read from the chain database to check that the node
+// is not hung before petting the watchdog.
+var sdWatchdogProbeKey = []byte("WatchdogProbe")
+
+var errWatchdogProbeTimeout = errors.New("watchdog probe timed out")
+
+// sdNotify sends the state to the service manager by the sd_notify protocol.
+// It returns false without an error if the node is not run by systemd with
+// Type=notify, which gives the socket by $NOTIFY_SOCKET.
+func sdNotify(state string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return false, nil
+	}
+	// A socket starting with @ is in the abstract namespace.
+	if socket[0] == '@' {
+		socket = "\x00" + socket[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// notifySystemd sends the state to the service manager, and logs the failure.
+func notifySystemd(state string) {
+	if _, err := sdNotify(state); err != nil {
+		logger.Warn("Failed to notify systemd", "state", state, "err", err)
+	}
+}
+
+// sdWatchdogInterval returns the watchdog interval given by systemd with
+// WatchdogSec, or 0 if the watchdog is not enabled for the process.
+func sdWatchdogInterval() time.Duration {
+	usec, err := strconv.ParseUint(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec == 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// startSystemdWatchdog pets the systemd watchdog at half of its interval while
+// the node is responsive. The chain database is read before petting, so that
+// the node is restarted when it is hung, e.g. on a stuck database. It stops
+// when the node is stopped.
+func startSystemdWatchdog(stack *node.Node, quit <-chan struct{}) {
+	interval := sdWatchdogInterval()
+	if interval == 0 {
+		return
+	}
+	probe := func() error { return nil }
+	var cnService *cn.CN
+	if err := stack.Service(&cnService); err == nil {
+		probe = func() error {
+			_, err := cnService.ChainDB().GetMiscDB().Has(sdWatchdogProbeKey)
+			return err
+		}
+	}
+
+	logger.Info("Systemd watchdog is enabled", "interval", interval)
+	go func() {
+		ticker := time.NewTicker(interval / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-quit:
+				return
+			case <-ticker.C:
+			}
+			if err := probeWithTimeout(probe, interval/2); err != nil {
+				logger.Warn("Skipped petting the systemd watchdog", "err", err)
+				continue
+			}
+			notifySystemd(sdNotifyWatchdog)
+		}
+	}()
+}
+
+// probeWithTimeout runs the probe, and returns an error if it does not return
+// within the timeout. The probe is left running if it times out.
+func probeWithTimeout(probe func() error, timeout time.Duration) error {
+	done := make(chan error, 1)
+	go func() { done <- probe() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		return errWatchdogProbeTimeout
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"sync"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// CacheStats is the effectiveness statistics of a cache.
+type CacheStats struct {
+	Hits      int64   `json:"hits"`
+	Misses    int64   `json:"misses"`
+	Evictions int64   `json:"evictions"`
+	HitRate   float64 `json:"hitRate"` // hits / (hits + misses), 0 if the cache is not read yet
+}
+
+// NewCacheStats returns the CacheStats with the hit rate calculated.
+func NewCacheStats(hits, misses, evictions int64) CacheStats {
+	stats := CacheStats{Hits: hits, Misses: misses, Evictions: evictions}
+	if hits+misses > 0 {
+		stats.HitRate = float64(hits) / float64(hits+misses)
+	}
+	return stats
+}
+
+var (
+	cacheStatsMu        sync.RWMutex
+	cacheStatsProviders = make(map[string]func() CacheStats)
+)
+
+// RegisterCacheStats registers a function returning the statistics of the cache
+// with the given name. A cache registered later replaces the one with the same name.
+func RegisterCacheStats(name string, provider func() CacheStats) {
+	cacheStatsMu.Lock()
+	defer cacheStatsMu.Unlock()
+	cacheStatsProviders[name] = provider
+}
+
+// GetCacheStats returns the statistics of all the registered caches by name.
+func GetCacheStats() map[string]CacheStats {
+	cacheStatsMu.RLock()
+	defer cacheStatsMu.RUnlock()
+
+	stats := make(map[string]CacheStats, len(cacheStatsProviders))
+	for name, provider := range cacheStatsProviders {
+		stats[name] = provider()
+	}
+	return stats
+}
+
+// statsCache counts the hits, misses and evictions of the wrapped cache. The
+// counters are registered in the metrics registry as klay/cache/<name>/*.
+type statsCache struct {
+	Cache
+
+	hits      metrics.Counter
+	misses    metrics.Counter
+	evictions metrics.Counter
+}
+
+// NewCacheWithStats creates a cache like NewCache, and registers its statistics
+// with the given name. Caches created with the same name share the statistics.
+func NewCacheWithStats(name string, config CacheConfiger) Cache {
+	prefix := "klay/cache/" + name + "/"
+	cache := &statsCache{
+		Cache:     NewCache(config),
+		hits:      metrics.GetOrRegisterCounter(prefix+"hits", nil),
+		misses:    metrics.GetOrRegisterCounter(prefix+"misses", nil),
+		evictions: metrics.GetOrRegisterCounter(prefix+"evictions", nil),
+	}
+	RegisterCacheStats(name, cache.stats)
+	return cache
+}
+
+func (cache *statsCache) Add(key CacheKey, value interface{}) (evicted bool) {
+	evicted = cache.Cache.Add(key, value)
+	if evicted {
+		cache.evictions.Inc(1)
+	}
+	return evicted
+}
+
+func (cache *statsCache) Get(key CacheKey) (value interface{}, ok bool) {
+	value, ok = cache.Cache.Get(key)
+	if ok {
+		cache.hits.Inc(1)
+	} else {
+		cache.misses.Inc(1)
+	}
+	return value, ok
+}
+
+func (cache *statsCache) stats() CacheStats {
+	return NewCacheStats(cache.hits.Count(), cache.misses.Count(), cache.evictions.Count())
+}
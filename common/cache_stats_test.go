@@ -0,0 +1,48 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"testing"
+)
+
+func TestNewCacheWithStats(t *testing.T) {
+	cache := NewCacheWithStats("test", LRUConfig{CacheSize: 2})
+
+	cache.Add(Hash{1}, 1)
+	cache.Add(Hash{2}, 2)
+	cache.Add(Hash{3}, 3) // evicts Hash{1}
+	cache.Get(Hash{1})
+	cache.Get(Hash{2})
+	cache.Get(Hash{3})
+	cache.Get(Hash{4})
+
+	stats, ok := GetCacheStats()["test"]
+	if !ok {
+		t.Fatal("cache stats are not registered")
+	}
+	want := CacheStats{Hits: 2, Misses: 2, Evictions: 1, HitRate: 0.5}
+	if stats != want {
+		t.Errorf("got %+v, want %+v", stats, want)
+	}
+}
+
+func TestNewCacheStats_NoRead(t *testing.T) {
+	if stats := NewCacheStats(0, 0, 3); stats.HitRate != 0 {
+		t.Errorf("hit rate of unread cache: got %v, want 0", stats.HitRate)
+	}
+}
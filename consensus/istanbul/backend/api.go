@@ -161,6 +161,34 @@ func (api *API) GetDemotedValidatorsAtHash(hash common.Hash) ([]common.Address,
 	}
 }
 
+// GetCouncilHistory retrieves the council membership changes between start and end (both inclusive)
+// together with the reason of each change. Only the changes processed by this node are returned,
+// so the blocks synced before enabling the council history are not included.
+func (api *API) GetCouncilHistory(start, end *rpc.BlockNumber) ([]*CouncilHistory, error) {
+	if start == nil || end == nil {
+		return nil, errRangeNil
+	}
+	if *start == rpc.PendingBlockNumber || *end == rpc.PendingBlockNumber {
+		return nil, errPendingNotAllowed
+	}
+
+	s, e := start.Int64(), end.Int64()
+	current := api.chain.CurrentHeader().Number.Int64()
+	if *start == rpc.LatestBlockNumber {
+		s = current
+	}
+	if *end == rpc.LatestBlockNumber || e > current {
+		e = current
+	}
+	if s < 0 {
+		return nil, errStartNotPositive
+	}
+	if s > e {
+		return nil, errStartLargerThanEnd
+	}
+	return loadCouncilHistories(api.istanbul.db, uint64(s), uint64(e))
+}
+
 // Candidates returns the current candidates the node tries to uphold and vote on.
 func (api *API) Candidates() map[common.Address]bool {
 	api.istanbul.candidatesLock.RLock()
@@ -52,6 +52,7 @@ var logger = log.NewModuleLogger(log.ConsensusIstanbulBackend)
 
 func New(rewardbase common.Address, config *istanbul.Config, privateKey *ecdsa.PrivateKey, db database.DBManager, governance governance.Engine, nodetype common.ConnType) consensus.Istanbul {
 	recents, _ := lru.NewARC(inmemorySnapshots)
+	derivedSnaps, _ := lru.NewARC(inmemorySnapshots)
 	recentMessages, _ := lru.NewARC(inmemoryPeers)
 	knownMessages, _ := lru.NewARC(inmemoryMessages)
 	backend := &backend{
@@ -63,6 +64,7 @@ func New(rewardbase common.Address, config *istanbul.Config, privateKey *ecdsa.P
 		db:                db,
 		commitCh:          make(chan *types.Result, 1),
 		recents:           recents,
+		derivedSnaps:      derivedSnaps,
 		candidates:        make(map[common.Address]bool),
 		coreStarted:       false,
 		recentMessages:    recentMessages,
@@ -83,6 +85,7 @@ type backend struct {
 	config           *istanbul.Config
 	istanbulEventMux *event.TypeMux
 	privateKey       *ecdsa.PrivateKey
+	signFn           SignFn // Signs the consensus messages instead of privateKey if set
 	address          common.Address
 	core             istanbulCore.Engine
 	logger           log.Logger
@@ -104,6 +107,8 @@ type backend struct {
 	candidatesLock sync.RWMutex
 	// Snapshots for recent block to speed up reorgs
 	recents *lru.ARCCache
+	// Snapshots derived by header-only verification, kept apart from the reconstructed ones
+	derivedSnaps *lru.ARCCache
 
 	// event subscription for ChainHeadEvent event
 	broadcaster consensus.Broadcaster
@@ -347,9 +352,25 @@ func (sb *backend) Verify(proposal istanbul.Proposal) (time.Duration, error) {
 	return 0, err
 }
 
+// SignFn signs the hash of a consensus message with the key of the validator,
+// e.g. by a remote signer. The signature is in the [R || S || V] format where V
+// is 0 or 1.
+type SignFn func(hash []byte) ([]byte, error)
+
+// SetSigner makes the node validate as the given address, signing the consensus
+// messages by signFn instead of the node key. The node key still identifies the
+// node on the p2p network.
+func (sb *backend) SetSigner(address common.Address, signFn SignFn) {
+	sb.address = address
+	sb.signFn = signFn
+}
+
 // Sign implements istanbul.Backend.Sign
 func (sb *backend) Sign(data []byte) ([]byte, error) {
 	hashData := crypto.Keccak256([]byte(data))
+	if sb.signFn != nil {
+		return sb.signFn(hashData)
+	}
 	return crypto.Sign(hashData, sb.privateKey)
 }
 
@@ -0,0 +1,148 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus/istanbul"
+	"github.com/klaytn/klaytn/governance"
+	"github.com/klaytn/klaytn/rlp"
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+// Types of council membership changes.
+const (
+	CouncilChangeJoin    = "join"    // a validator joined the council
+	CouncilChangeLeave   = "leave"   // a validator left the council
+	CouncilChangeDemote  = "demote"  // a validator is demoted but still in the council
+	CouncilChangePromote = "promote" // a demoted validator is promoted again
+)
+
+// Reasons of council membership changes.
+const (
+	CouncilChangeReasonVote    = "vote"    // the change is triggered by a governance vote
+	CouncilChangeReasonStaking = "staking" // the change is triggered by a staking update
+)
+
+// CouncilVote is the governance vote which triggered a council change.
+type CouncilVote struct {
+	Validator common.Address `json:"validator"`
+	Key       string         `json:"key"`
+}
+
+// CouncilChange is a change of the council membership of a single validator.
+type CouncilChange struct {
+	Address common.Address `json:"address"`
+	Type    string         `json:"type"`
+	Reason  string         `json:"reason"`
+	Vote    *CouncilVote   `json:"vote,omitempty"`
+}
+
+// CouncilHistory is the list of council changes caused by a block.
+type CouncilHistory struct {
+	Number  uint64          `json:"number"`
+	Hash    common.Hash     `json:"hash"`
+	Changes []CouncilChange `json:"changes"`
+}
+
+// store inserts the council history into the database. It is stored by the block
+// hash as well, so the histories of the side chain blocks do not replace the ones
+// of the canonical blocks.
+func (h *CouncilHistory) store(db database.DBManager) error {
+	blob, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	return db.WriteCouncilHistory(h.Number, h.Hash, blob)
+}
+
+// loadCouncilHistories loads the council histories of the canonical blocks stored
+// between start and end (both inclusive). The histories of the blocks replaced by
+// a reorg are skipped.
+func loadCouncilHistories(db database.DBManager, start, end uint64) ([]*CouncilHistory, error) {
+	blobs, err := db.ReadCouncilHistoryRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	histories := make([]*CouncilHistory, 0, len(blobs))
+	for _, blob := range blobs {
+		history := new(CouncilHistory)
+		if err := json.Unmarshal(blob, history); err != nil {
+			return nil, err
+		}
+		if db.ReadCanonicalHash(history.Number) != history.Hash {
+			continue
+		}
+		histories = append(histories, history)
+	}
+	return histories, nil
+}
+
+// councilMembers returns the council members of the given validator set.
+// The value is true for an active validator and false for a demoted one.
+func councilMembers(valSet istanbul.ValidatorSet) map[common.Address]bool {
+	members := make(map[common.Address]bool)
+	for _, v := range valSet.List() {
+		members[v.Address()] = true
+	}
+	for _, v := range valSet.DemotedList() {
+		members[v.Address()] = false
+	}
+	return members
+}
+
+// headerVote returns the governance vote included in the header, or nil if there is none.
+func headerVote(header *types.Header) *CouncilVote {
+	if len(header.Vote) == 0 {
+		return nil
+	}
+	gVote := new(governance.GovernanceVote)
+	if err := rlp.DecodeBytes(header.Vote, gVote); err != nil {
+		return nil
+	}
+	return &CouncilVote{Validator: gVote.Validator, Key: gVote.Key}
+}
+
+// diffCouncil returns the changes between two council memberships in ascending order of the address.
+func diffCouncil(before, after map[common.Address]bool, reason string, vote *CouncilVote) []CouncilChange {
+	var changes []CouncilChange
+	for addr, active := range after {
+		prev, ok := before[addr]
+		switch {
+		case !ok:
+			changes = append(changes, CouncilChange{Address: addr, Type: CouncilChangeJoin, Reason: reason, Vote: vote})
+		case prev && !active:
+			changes = append(changes, CouncilChange{Address: addr, Type: CouncilChangeDemote, Reason: reason, Vote: vote})
+		case !prev && active:
+			changes = append(changes, CouncilChange{Address: addr, Type: CouncilChangePromote, Reason: reason, Vote: vote})
+		}
+	}
+	for addr := range before {
+		if _, ok := after[addr]; !ok {
+			changes = append(changes, CouncilChange{Address: addr, Type: CouncilChangeLeave, Reason: reason, Vote: vote})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return bytes.Compare(changes[i].Address[:], changes[j].Address[:]) < 0
+	})
+	return changes
+}
@@ -0,0 +1,95 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"testing"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffCouncil(t *testing.T) {
+	var (
+		a = common.HexToAddress("0x1")
+		b = common.HexToAddress("0x2")
+		c = common.HexToAddress("0x3")
+		d = common.HexToAddress("0x4")
+		e = common.HexToAddress("0x5")
+	)
+	before := map[common.Address]bool{a: true, b: true, c: false, d: true}
+	after := map[common.Address]bool{a: true, b: false, c: true, e: true}
+	vote := &CouncilVote{Validator: a, Key: "governance.addvalidator"}
+
+	changes := diffCouncil(before, after, CouncilChangeReasonVote, vote)
+	assert.Equal(t, []CouncilChange{
+		{Address: b, Type: CouncilChangeDemote, Reason: CouncilChangeReasonVote, Vote: vote},
+		{Address: c, Type: CouncilChangePromote, Reason: CouncilChangeReasonVote, Vote: vote},
+		{Address: d, Type: CouncilChangeLeave, Reason: CouncilChangeReasonVote, Vote: vote},
+		{Address: e, Type: CouncilChangeJoin, Reason: CouncilChangeReasonVote, Vote: vote},
+	}, changes)
+
+	assert.Empty(t, diffCouncil(before, before, CouncilChangeReasonStaking, nil))
+}
+
+func TestCouncilHistory_StoreAndLoad(t *testing.T) {
+	db := database.NewMemoryDBManager()
+	addr := common.HexToAddress("0x1")
+	canonical := common.BigToHash(common.Big1)
+
+	for _, num := range []uint64{3, 10, 256, 1024} {
+		history := &CouncilHistory{
+			Number:  num,
+			Hash:    canonical,
+			Changes: []CouncilChange{{Address: addr, Type: CouncilChangeJoin, Reason: CouncilChangeReasonStaking}},
+		}
+		assert.NoError(t, history.store(db))
+		db.WriteCanonicalHash(canonical, num)
+	}
+
+	// The history of a side chain block does not replace the canonical one.
+	side := &CouncilHistory{
+		Number:  10,
+		Hash:    common.BigToHash(common.Big2),
+		Changes: []CouncilChange{{Address: addr, Type: CouncilChangeLeave, Reason: CouncilChangeReasonVote}},
+	}
+	assert.NoError(t, side.store(db))
+
+	histories, err := loadCouncilHistories(db, 4, 1024)
+	assert.NoError(t, err)
+	if assert.Len(t, histories, 3) {
+		assert.Equal(t, uint64(10), histories[0].Number)
+		assert.Equal(t, CouncilChangeJoin, histories[0].Changes[0].Type)
+		assert.Equal(t, uint64(256), histories[1].Number)
+		assert.Equal(t, uint64(1024), histories[2].Number)
+		assert.Equal(t, addr, histories[2].Changes[0].Address)
+	}
+
+	histories, err = loadCouncilHistories(db, 11, 255)
+	assert.NoError(t, err)
+	assert.Empty(t, histories)
+
+	// After a reorg, the history of the new canonical block is returned.
+	db.WriteCanonicalHash(side.Hash, side.Number)
+	histories, err = loadCouncilHistories(db, 10, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, histories, 1) {
+		assert.Equal(t, side.Hash, histories[0].Hash)
+		assert.Equal(t, CouncilChangeLeave, histories[0].Changes[0].Type)
+	}
+}
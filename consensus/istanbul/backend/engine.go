@@ -44,6 +44,7 @@ import (
 	"github.com/klaytn/klaytn/params"
 	"github.com/klaytn/klaytn/reward"
 	"github.com/klaytn/klaytn/rlp"
+	"github.com/rcrowley/go-metrics"
 )
 
 const (
@@ -101,6 +102,10 @@ var (
 	inmemoryBlocks             = 2048 // Number of blocks to precompute validators' addresses
 	inmemoryValidatorsPerBlock = 30   // Approximate number of validators' addresses from ecrecover
 	signatureAddresses, _      = lru.NewARC(inmemoryBlocks * inmemoryValidatorsPerBlock)
+
+	headerOnlyVerificationHitCounter      = metrics.NewRegisteredCounter("consensus/istanbul/backend/headeronly/hit", nil)
+	headerOnlyVerificationMissCounter     = metrics.NewRegisteredCounter("consensus/istanbul/backend/headeronly/miss", nil)
+	headerOnlyVerificationFallbackCounter = metrics.NewRegisteredCounter("consensus/istanbul/backend/headeronly/fallback", nil)
 )
 
 // cacheSignatureAddresses extracts the address from the given data and signature and cache them for later usage.
@@ -309,23 +314,20 @@ func (sb *backend) verifySigner(chain consensus.ChainReader, header *types.Heade
 		return errUnknownBlock
 	}
 
-	// Retrieve the snapshot needed to verify this header and cache it
-	snap, err := sb.snapshot(chain, number-1, header.ParentHash, parents, true)
-	if err != nil {
-		return err
-	}
-
 	// resolve the authorization key and check against signers
 	signer, err := ecrecover(header)
 	if err != nil {
 		return err
 	}
 
-	// Signer should be in the validator set of previous block's extraData.
-	if _, v := snap.ValSet.GetByAddress(signer); v == nil {
-		return errUnauthorized
-	}
-	return nil
+	// Retrieve the snapshot needed to verify this header and cache it
+	return sb.verifyWithSnapshot(chain, header, parents, func(snap *Snapshot) error {
+		// Signer should be in the validator set of previous block's extraData.
+		if _, v := snap.ValSet.GetByAddress(signer); v == nil {
+			return errUnauthorized
+		}
+		return nil
+	})
 }
 
 // verifyCommittedSeals checks whether every committed seal is signed by one of the parent's validators
@@ -336,12 +338,6 @@ func (sb *backend) verifyCommittedSeals(chain consensus.ChainReader, header *typ
 		return nil
 	}
 
-	// Retrieve the snapshot needed to verify this header and cache it
-	snap, err := sb.snapshot(chain, number-1, header.ParentHash, parents, true)
-	if err != nil {
-		return err
-	}
-
 	extra, err := types.ExtractIstanbulExtra(header)
 	if err != nil {
 		return err
@@ -351,6 +347,15 @@ func (sb *backend) verifyCommittedSeals(chain consensus.ChainReader, header *typ
 		return errEmptyCommittedSeals
 	}
 
+	// Retrieve the snapshot needed to verify this header and cache it
+	return sb.verifyWithSnapshot(chain, header, parents, func(snap *Snapshot) error {
+		return verifyCommittedSealsWithSnapshot(snap, header, extra)
+	})
+}
+
+// verifyCommittedSealsWithSnapshot checks whether every committed seal is signed by one of the
+// validators of the given snapshot of the parent.
+func verifyCommittedSealsWithSnapshot(snap *Snapshot, header *types.Header, extra *types.IstanbulExtra) error {
 	validators := snap.ValSet.Copy()
 	// Check whether the committed seals are generated by parent's validators
 	validSeal := 0
@@ -760,10 +765,15 @@ func (sb *backend) snapshot(chain consensus.ChainReader, number uint64, hash com
 	for i := 0; i < len(headers)/2; i++ {
 		headers[i], headers[len(headers)-1-i] = headers[len(headers)-1-i], headers[i]
 	}
-	snap, err := snap.apply(headers, sb.governance, sb.address, sb.governance.ProposerPolicy(), chain, writable)
+	snap, histories, err := snap.apply(headers, sb.governance, sb.address, sb.governance.ProposerPolicy(), chain, writable)
 	if err != nil {
 		return nil, err
 	}
+	for _, history := range histories {
+		if err := history.store(sb.db); err != nil {
+			logger.Warn("Failed to store council history", "number", history.Number, "err", err)
+		}
+	}
 
 	// If we've generated a new checkpoint snapshot, save to disk
 	if writable && snap.Number%checkpointInterval == 0 && len(headers) > 0 {
@@ -780,6 +790,116 @@ func (sb *backend) snapshot(chain consensus.ChainReader, number uint64, hash com
 	return snap, err
 }
 
+// verifyWithSnapshot runs the verification of the header against the snapshot of its parent.
+// If header-only verification is enabled and the verification fails against the snapshot derived
+// from the nearest known snapshot, the council may have changed in between in a way the header
+// does not reveal, so the snapshot is reconstructed in full and the verification is retried.
+func (sb *backend) verifyWithSnapshot(chain consensus.ChainReader, header *types.Header, parents []*types.Header, verify func(*Snapshot) error) error {
+	number := header.Number.Uint64()
+	snap, derived, err := sb.verificationSnapshot(chain, header, parents)
+	if err != nil {
+		return err
+	}
+	err = verify(snap)
+	if !derived {
+		return err
+	}
+	if err == nil {
+		sb.derivedSnaps.Add(snap.Hash, snap)
+		return nil
+	}
+
+	headerOnlyVerificationFallbackCounter.Inc(1)
+	sb.derivedSnaps.Remove(snap.Hash)
+	if snap, err = sb.snapshot(chain, number-1, header.ParentHash, parents, true); err != nil {
+		return err
+	}
+	return verify(snap)
+}
+
+// verificationSnapshot retrieves the snapshot used to verify the signer and the committed seals
+// of the given header, and whether it is derived by header-only verification.
+//
+// If header-only verification is enabled, the snapshot of the parent is derived from the nearest
+// snapshot known in memory or on disk, without applying the headers in between. The council of
+// the derived snapshot must match the council recorded in the extra of the header, which the
+// proposer takes from the snapshot of the parent and the committed seals attest. If they differ,
+// the council has changed since the nearest snapshot, and the snapshot is reconstructed in full.
+func (sb *backend) verificationSnapshot(chain consensus.ChainReader, header *types.Header, parents []*types.Header) (*Snapshot, bool, error) {
+	number := header.Number.Uint64()
+	// The snapshot of the parent already known in memory is not derived, but used as is.
+	if sb.config.HeaderOnlyVerification && sb.nodetype != common.CONSENSUSNODE && !sb.recents.Contains(header.ParentHash) {
+		if snap := sb.derivedSnapshot(chain, number-1, header.ParentHash, parents); snap != nil && councilProven(snap, header) {
+			headerOnlyVerificationHitCounter.Inc(1)
+			return snap, true, nil
+		}
+		headerOnlyVerificationMissCounter.Inc(1)
+	}
+	snap, err := sb.snapshot(chain, number-1, header.ParentHash, parents, true)
+	return snap, false, err
+}
+
+// derivedSnapshot returns the snapshot of the given block derived from the nearest snapshot known
+// in memory or on disk, with the council of the nearest snapshot. It returns nil if there is none
+// within checkpointInterval blocks.
+func (sb *backend) derivedSnapshot(chain consensus.ChainReader, number uint64, hash common.Hash, parents []*types.Header) *Snapshot {
+	nearest := sb.nearestSnapshot(chain, number, hash, parents)
+	if nearest == nil || nearest.Hash == hash {
+		return nearest
+	}
+	snap := nearest.copy()
+	snap.Number, snap.Hash = number, hash
+	return snap
+}
+
+// nearestSnapshot walks back at most checkpointInterval headers from the given block and returns
+// the first snapshot found in memory, among the derived snapshots of the verified headers, or in
+// the on-disk checkpoints. It returns nil if there is none.
+func (sb *backend) nearestSnapshot(chain consensus.ChainReader, number uint64, hash common.Hash, parents []*types.Header) *Snapshot {
+	for i := 0; i <= checkpointInterval; i++ {
+		if s, ok := sb.recents.Get(hash); ok {
+			return s.(*Snapshot)
+		}
+		if s, ok := sb.derivedSnaps.Get(hash); ok {
+			return s.(*Snapshot)
+		}
+		if number%checkpointInterval == 0 {
+			if s, err := loadSnapshot(sb.db, hash); err == nil {
+				sb.recents.Add(s.Hash, s)
+				return s
+			}
+		}
+		if number == 0 {
+			return nil
+		}
+		header := getPrevHeaderAndUpdateParents(chain, number, hash, &parents)
+		if header == nil {
+			return nil
+		}
+		number, hash = number-1, header.ParentHash
+	}
+	return nil
+}
+
+// councilProven reports whether the council of the snapshot is the one recorded in the extra of
+// the header built on top of it.
+func councilProven(snap *Snapshot, header *types.Header) bool {
+	extra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return false
+	}
+	validators := snap.validators()
+	if len(validators) != len(extra.Validators) {
+		return false
+	}
+	for i, addr := range sortValidatorArray(extra.Validators) {
+		if validators[i] != addr {
+			return false
+		}
+	}
+	return true
+}
+
 // FIXME: Need to update this for Istanbul
 // sigHash returns the hash which is used as input for the Istanbul
 // signing. It is the hash of the entire header apart from the 65 byte signature
@@ -534,6 +534,83 @@ OUT3:
 	}
 }
 
+func TestHeaderOnlyVerification_CouncilChange(t *testing.T) {
+	var configItems []interface{}
+	configItems = append(configItems, proposerPolicy(params.WeightedRandom))
+	configItems = append(configItems, proposerUpdateInterval(1))
+	configItems = append(configItems, epoch(3))
+	configItems = append(configItems, subGroupSize(4))
+	configItems = append(configItems, governanceMode("single"))
+	configItems = append(configItems, minimumStake(new(big.Int).SetUint64(4000000)))
+	configItems = append(configItems, istanbulCompatibleBlock(new(big.Int).SetUint64(0)))
+	configItems = append(configItems, blockPeriod(0)) // set block period to 0 to prevent creating future block
+	stakes := []uint64{4000000, 4000000, 4000000, 4000000}
+
+	chain, engine := newBlockChain(4, configItems...)
+	defer engine.Stop()
+
+	oldStakingManager := reward.GetStakingManager()
+	defer reward.SetTestStakingManager(oldStakingManager)
+	reward.SetTestStakingManagerWithStakingInfoCache(makeFakeStakingInfo(0, nodeKeys, stakes))
+
+	// The validator 3 leaves the council at block 2 and joins again at block 4.
+	allNodeKeys := make([]*ecdsa.PrivateKey, len(nodeKeys))
+	allAddrs := make([]common.Address, len(addrs))
+	copy(allNodeKeys, nodeKeys)
+	copy(allAddrs, addrs)
+
+	blocks := []*types.Block{chain.Genesis()}
+	for i := 0; i < 7; i++ {
+		switch i {
+		case 1:
+			engine.governance.AddVote("governance.removevalidator", allAddrs[3])
+		case 3:
+			engine.governance.AddVote("governance.addvalidator", allAddrs[3])
+		}
+		block := makeBlockWithSeal(chain, engine, blocks[len(blocks)-1])
+		_, err := chain.InsertChain(types.Blocks{block})
+		assert.NoError(t, err)
+		blocks = append(blocks, block)
+
+		switch i {
+		case 1:
+			excludeNodeByAddr(allAddrs[3])
+		case 3:
+			includeNode(allAddrs[3], allNodeKeys[3])
+		}
+	}
+
+	// Only the snapshots up to block 3, whose council lacks the validator 3, are known.
+	for _, block := range blocks[4:] {
+		engine.recents.Remove(block.Hash())
+	}
+	defer func(old bool) { engine.config.HeaderOnlyVerification = old }(engine.config.HeaderOnlyVerification)
+	engine.config.HeaderOnlyVerification = true
+	engine.nodetype = common.ENDPOINTNODE
+
+	// The header 5 is sealed by the validator 3 and records the new council, so the snapshot
+	// derived from block 3 is not used and the snapshot of block 4 is reconstructed.
+	assert.NoError(t, engine.VerifyHeader(chain, blocks[5].Header(), true))
+	assert.True(t, engine.recents.Contains(blocks[4].Hash()))
+	assert.False(t, engine.derivedSnaps.Contains(blocks[4].Hash()))
+
+	// The following headers record the same council, so the snapshots are derived and cached.
+	for _, block := range blocks[6:] {
+		assert.NoError(t, engine.VerifyHeader(chain, block.Header(), true))
+		assert.True(t, engine.derivedSnaps.Contains(block.ParentHash()))
+		assert.False(t, engine.recents.Contains(block.ParentHash()))
+	}
+
+	// A cached snapshot with a stale council is detected by the council recorded in the header.
+	stale, err := engine.snapshot(chain, 3, blocks[3].Hash(), nil, false)
+	assert.NoError(t, err)
+	stale = stale.copy()
+	stale.Number, stale.Hash = 6, blocks[6].Hash()
+	engine.derivedSnaps.Add(stale.Hash, stale)
+	assert.NoError(t, engine.VerifyHeader(chain, blocks[7].Header(), true))
+	assert.True(t, engine.recents.Contains(blocks[6].Hash()))
+}
+
 func TestPrepareExtra(t *testing.T) {
 	validators := make([]common.Address, 4)
 	validators[0] = common.BytesToAddress(hexutil.MustDecode("0x44add0ec310f115a0e603b2d7db9f067778eaf8a"))
@@ -43,8 +43,8 @@ var (
 	// TODO-Klaytn-Istanbul: define Versions and Lengths with correct values.
 	IstanbulProtocol = consensus.Protocol{
 		Name:     "istanbul",
-		Versions: []uint{65, 64},
-		Lengths:  []uint64{23, 21},
+		Versions: []uint{67, 66, 65, 64},
+		Lengths:  []uint64{24, 23, 23, 21},
 	}
 )
 
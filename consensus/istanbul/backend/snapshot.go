@@ -143,24 +143,26 @@ func (s *Snapshot) checkVote(address common.Address, authorize bool) bool {
 }
 
 // apply creates a new authorization snapshot by applying the given headers to
-// the original one.
-func (s *Snapshot) apply(headers []*types.Header, gov governance.Engine, addr common.Address, policy uint64, chain consensus.ChainReader, writable bool) (*Snapshot, error) {
+// the original one. If writable is true, it also returns the council membership
+// changes caused by the given headers.
+func (s *Snapshot) apply(headers []*types.Header, gov governance.Engine, addr common.Address, policy uint64, chain consensus.ChainReader, writable bool) (*Snapshot, []*CouncilHistory, error) {
 	// Allow passing in no headers for cleaner code
 	if len(headers) == 0 {
-		return s, nil
+		return s, nil, nil
 	}
 	// Sanity check that the headers can be applied
 	for i := 0; i < len(headers)-1; i++ {
 		if headers[i+1].Number.Uint64() != headers[i].Number.Uint64()+1 {
-			return nil, errInvalidVotingChain
+			return nil, nil, errInvalidVotingChain
 		}
 	}
 	if headers[0].Number.Uint64() != s.Number+1 {
-		return nil, errInvalidVotingChain
+		return nil, nil, errInvalidVotingChain
 	}
 
 	// Iterate through the headers and create a new snapshot
 	snap := s.copy()
+	var histories []*CouncilHistory
 
 	// Copy values which might be changed by governance vote
 	snap.Epoch, snap.Policy, snap.CommitteeSize = getGovernanceValue(gov, snap.Number)
@@ -172,10 +174,10 @@ func (s *Snapshot) apply(headers []*types.Header, gov governance.Engine, addr co
 		// Resolve the authorization key and check against validators
 		validator, err := ecrecover(header)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if _, v := snap.ValSet.GetByAddress(validator); v == nil {
-			return nil, errUnauthorized
+			return nil, nil, errUnauthorized
 		}
 
 		if number%snap.Epoch == 0 {
@@ -192,7 +194,14 @@ func (s *Snapshot) apply(headers []*types.Header, gov governance.Engine, addr co
 			snap.Tally = make([]governance.GovernanceTallyItem, 0)
 		}
 
+		var beforeVote, beforeStaking map[common.Address]bool
+		if writable {
+			beforeVote = councilMembers(snap.ValSet)
+		}
 		snap.ValSet, snap.Votes, snap.Tally = gov.HandleGovernanceVote(snap.ValSet, snap.Votes, snap.Tally, header, validator, addr, writable)
+		if writable {
+			beforeStaking = councilMembers(snap.ValSet)
+		}
 		if policy == uint64(params.WeightedRandom) {
 			// Snapshot of block N (Snapshot_N) should contain proposers for N+1 and following blocks.
 			// Validators for Block N+1 can be calculated based on the staking information from the previous stakingUpdateInterval block.
@@ -202,12 +211,12 @@ func (s *Snapshot) apply(headers []*types.Header, gov governance.Engine, addr co
 			// Refresh proposers in Snapshot_N using previous proposersUpdateInterval block for N+1, if not updated yet.
 			isSingle, govNode, err := gov.GetGoverningInfoAtNumber(number)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 			minStaking, err := gov.GetMinimumStakingAtNumber(number)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 			pHeader := chain.GetHeaderByNumber(params.CalcProposerBlockNumber(number + 1))
@@ -223,6 +232,14 @@ func (s *Snapshot) apply(headers []*types.Header, gov governance.Engine, addr co
 				logger.Trace("Can't refreshing proposers while creating snapshot due to lack of required header", "snap.Number", snap.Number)
 			}
 		}
+
+		if writable {
+			changes := diffCouncil(beforeVote, beforeStaking, CouncilChangeReasonVote, headerVote(header))
+			changes = append(changes, diffCouncil(beforeStaking, councilMembers(snap.ValSet), CouncilChangeReasonStaking, nil)...)
+			if len(changes) > 0 {
+				histories = append(histories, &CouncilHistory{Number: number, Hash: header.Hash(), Changes: changes})
+			}
+		}
 	}
 	snap.Number += uint64(len(headers))
 	snap.Hash = headers[len(headers)-1].Hash()
@@ -238,7 +255,7 @@ func (s *Snapshot) apply(headers []*types.Header, gov governance.Engine, addr co
 		gov.SetMyVotingPower(snap.getMyVotingPower(addr))
 	}
 
-	return snap, nil
+	return snap, histories, nil
 }
 
 func (s *Snapshot) getMyVotingPower(addr common.Address) uint64 {
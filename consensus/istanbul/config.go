@@ -34,6 +34,14 @@ type Config struct {
 	ProposerPolicy ProposerPolicy `toml:",omitempty"` // The policy for proposer selection
 	Epoch          uint64         `toml:",omitempty"` // The number of blocks after which to checkpoint and reset the pending votes
 	SubGroupSize   uint64         `toml:",omitempty"`
+
+	// HeaderOnlyVerification makes a non-validator verify the proposer seal and the committed seals
+	// of a header against the council of the nearest known snapshot instead of reconstructing the
+	// council state of its parent, as long as the council recorded in the header is the same. The
+	// votes and the staking changes in between are not replayed, and a header failing against the
+	// derived snapshot is verified again after the full reconstruction.
+	// Consensus nodes always perform the full verification regardless of this option.
+	HeaderOnlyVerification bool `toml:",omitempty"`
 }
 
 // TODO-Klaytn-Istanbul: Do not use DefaultConfig except for assigning new config
@@ -184,6 +184,16 @@ func (m *message) GetView() (*istanbul.View, error) {
 	return msgView, nil
 }
 
+// MessageView returns the view of the consensus message payload without
+// validating its signature.
+func MessageView(payload []byte) (*istanbul.View, error) {
+	msg := new(message)
+	if err := msg.FromPayload(payload, nil); err != nil {
+		return nil, err
+	}
+	return msg.GetView()
+}
+
 // ==============================================
 //
 // helper functions
@@ -45,6 +45,9 @@ var (
 	passwordRegexp = regexp.MustCompile(`personal.[nus]`)
 	onlyWhitespace = regexp.MustCompile(`^\s*$`)
 	exit           = regexp.MustCompile(`^\s*exit\s*;*\s*$`)
+	jsIdentifier   = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	jsPath         = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*(\.[A-Za-z_$][A-Za-z0-9_$]*)+$`)
+	jsCallOpening  = regexp.MustCompile(`([A-Za-z_$][A-Za-z0-9_$.]*)\($`)
 )
 
 // HistoryFile is the file within the data directory to store input scrollback.
@@ -69,13 +72,14 @@ type Config struct {
 // JavaScript console attached to a running node via an external or in-process RPC
 // client.
 type Console struct {
-	client   *rpc.Client  // RPC client to execute Klaytn requests through
-	jsre     *jsre.JSRE   // JavaScript runtime environment running the interpreter
-	prompt   string       // Input prompt prefix string
-	prompter UserPrompter // Input prompter to allow interactive user feedback
-	histPath string       // Absolute path to the console scrollback history
-	history  []string     // Scroll history maintained by the console
-	printer  io.Writer    // Output writer to serialize any display strings to
+	client   *rpc.Client         // RPC client to execute Klaytn requests through
+	jsre     *jsre.JSRE          // JavaScript runtime environment running the interpreter
+	prompt   string              // Input prompt prefix string
+	prompter UserPrompter        // Input prompter to allow interactive user feedback
+	histPath string              // Absolute path to the console scrollback history
+	history  []string            // Scroll history maintained by the console
+	printer  io.Writer           // Output writer to serialize any display strings to
+	methods  map[string][]string // Parameter types of the RPC methods of the node
 }
 
 func New(config Config) (*Console, error) {
@@ -159,6 +163,16 @@ func (c *Console) init(preload []string) error {
 	if _, err = c.jsre.Run(flatten); err != nil {
 		return fmt.Errorf("namespace flattening: %v", err)
 	}
+	// Load the parameter types of the RPC methods for the completion and help(),
+	// and bind the methods of the loaded namespaces which have no binding yet.
+	// The nodes without rpc_methods are served by the bindings only.
+	if methods, err := c.client.SupportedMethods(); err == nil {
+		c.methods = methods
+		if err := c.bindMethods(); err != nil {
+			return err
+		}
+	}
+	c.jsre.Set("help", c.help)
 	// Initialize the global name register (disabled for now)
 	// c.jsre.Run(`var GlobalRegistrar = klay.contract(` + registrar.GlobalRegistrarAbi + `);   registrar = GlobalRegistrar.at("` + registrar.GlobalRegistrarAddr + `");`)
 
@@ -268,13 +282,143 @@ func (c *Console) consoleOutput(call otto.FunctionCall) otto.Value {
 	return otto.Value{}
 }
 
+// bindMethods binds the RPC methods of the namespaces loaded in the JavaScript
+// runtime which have no binding in web3.js or the extensions.
+func (c *Console) bindMethods() error {
+	names := make([]string, 0, len(c.methods))
+	for name := range c.methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var (
+		namespaces []string
+		bindings   = make(map[string][]string)
+	)
+	for _, name := range names {
+		elems := strings.SplitN(name, "_", 2)
+		if len(elems) != 2 || !jsIdentifier.MatchString(elems[0]) || !jsIdentifier.MatchString(elems[1]) {
+			continue
+		}
+		namespace, method := elems[0], elems[1]
+		unbound, err := c.jsre.Run(fmt.Sprintf("typeof web3.%s === 'object' && !('%s' in web3.%s)", namespace, method, namespace))
+		if err != nil {
+			continue
+		}
+		if ok, _ := unbound.ToBoolean(); !ok {
+			continue
+		}
+		if _, ok := bindings[namespace]; !ok {
+			namespaces = append(namespaces, namespace)
+		}
+		bindings[namespace] = append(bindings[namespace],
+			fmt.Sprintf("new web3._extend.Method({name: '%s', call: '%s', params: %d})", method, name, len(c.methods[name])))
+	}
+	for _, namespace := range namespaces {
+		extension := fmt.Sprintf("web3._extend({property: '%s', methods: [%s]});", namespace, strings.Join(bindings[namespace], ", "))
+		if _, err := c.jsre.Run(extension); err != nil {
+			return fmt.Errorf("%s methods: %v", namespace, err)
+		}
+	}
+	return nil
+}
+
+// signature returns the RPC method and its parameter types, like
+// klay_getBlockByNumber(rpc.BlockNumber, bool).
+func (c *Console) signature(method string) string {
+	return fmt.Sprintf("%s(%s)", method, strings.Join(c.methods[method], ", "))
+}
+
+// rpcMethod returns the RPC method called by the JavaScript function of the
+// dotted path, like klay.getBlockByNumber, or "" if it is not an RPC method.
+// The path is resolved without invoking the property getters, which would send
+// RPC requests.
+func (c *Console) rpcMethod(path string) string {
+	if !jsPath.MatchString(path) {
+		return ""
+	}
+	v, err := c.jsre.Run(fmt.Sprintf(`(function(path) {
+		var obj = this;
+		for (var i = 0; i < path.length; i++) {
+			var d = (obj !== null && typeof obj === 'object' || typeof obj === 'function') ? Object.getOwnPropertyDescriptor(obj, path[i]) : undefined;
+			if (!d || !('value' in d)) { return ''; }
+			obj = d.value;
+		}
+		return typeof obj === 'function' && typeof obj.call === 'string' ? obj.call : '';
+	})(%q.split('.'))`, path))
+	if err != nil {
+		return ""
+	}
+	method, _ := v.ToString()
+	if _, ok := c.methods[method]; !ok {
+		return ""
+	}
+	return method
+}
+
+// help prints the signatures of the RPC methods. It is given a method like
+// klay.getBlockByNumber or "klay_getBlockByNumber", or a namespace like "klay"
+// to list its methods, or nothing to list the namespaces.
+func (c *Console) help(call otto.FunctionCall) otto.Value {
+	if c.methods == nil {
+		fmt.Fprintln(c.printer, "The node does not provide the RPC method signatures.")
+		return otto.UndefinedValue()
+	}
+	arg := call.Argument(0)
+	var method string
+	switch {
+	case arg.IsUndefined():
+		counts := make(map[string]int)
+		for name := range c.methods {
+			counts[strings.SplitN(name, "_", 2)[0]]++
+		}
+		namespaces := make([]string, 0, len(counts))
+		for namespace, count := range counts {
+			namespaces = append(namespaces, fmt.Sprintf("%s (%d methods)", namespace, count))
+		}
+		sort.Strings(namespaces)
+		fmt.Fprintln(c.printer, strings.Join(namespaces, "\n"))
+		return otto.UndefinedValue()
+	case arg.IsFunction():
+		if callName, err := arg.Object().Get("call"); err == nil && callName.IsString() {
+			method = callName.String()
+		}
+	case arg.IsString():
+		method = strings.Replace(arg.String(), ".", "_", 1)
+	}
+	if _, ok := c.methods[method]; ok {
+		fmt.Fprintln(c.printer, c.signature(method))
+		return otto.UndefinedValue()
+	}
+	var matches []string
+	for name := range c.methods {
+		if method != "" && strings.HasPrefix(name, method+"_") {
+			matches = append(matches, c.signature(name))
+		}
+	}
+	if len(matches) == 0 {
+		fmt.Fprintln(c.printer, "No RPC method or namespace is found.")
+		return otto.UndefinedValue()
+	}
+	sort.Strings(matches)
+	fmt.Fprintln(c.printer, strings.Join(matches, "\n"))
+	return otto.UndefinedValue()
+}
+
 // AutoCompleteInput is a pre-assembled word completer to be used by the user
 // input prompter to provide hints to the user about the methods available.
+// Right after the opening parenthesis of an RPC method, the parameter types
+// are completed as a comment.
 func (c *Console) AutoCompleteInput(line string, pos int) (string, []string, string) {
 	// No completions can be provided for empty inputs
 	if len(line) == 0 || pos == 0 {
 		return "", nil, ""
 	}
+	if match := jsCallOpening.FindStringSubmatch(line[:pos]); match != nil {
+		if method := c.rpcMethod(match[1]); method != "" {
+			return line[:pos], []string{"/* " + strings.Join(c.methods[method], ", ") + " */"}, line[pos:]
+		}
+	}
 	// Chunk data to relevant part for autocompletion
 	// E.g. in case of nested lines klay.getBalance(klay.coinb<tab><tab>
 	start := pos - 1
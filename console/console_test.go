@@ -276,6 +276,30 @@ func TestPrettyPrint(t *testing.T) {
 }
 
 // Tests that the JavaScript exceptions are properly formatted and colored.
+// Tests that the parameter types of the RPC methods are shown by help() and
+// completed after the opening parenthesis.
+func TestMethodHelp(t *testing.T) {
+	tester := newTester(t, nil)
+	defer tester.Close(t)
+
+	want := "klay_getBlockByNumber(rpc.BlockNumber, bool)"
+	tester.console.Evaluate("help(klay.getBlockByNumber)")
+	if output := tester.output.String(); !strings.Contains(output, want) {
+		t.Fatalf("method help mismatch: have %s, want %s", output, want)
+	}
+	tester.output.Reset()
+	tester.console.Evaluate("help('klay')")
+	if output := tester.output.String(); !strings.Contains(output, want) {
+		t.Fatalf("namespace help mismatch: have %s, want also %s", output, want)
+	}
+
+	line := "klay.getBlockByNumber("
+	head, completions, tail := tester.console.AutoCompleteInput(line, len(line))
+	if head != line || tail != "" || len(completions) != 1 || completions[0] != "/* rpc.BlockNumber, bool */" {
+		t.Fatalf("completion mismatch: have %q %q %q", head, completions, tail)
+	}
+}
+
 func TestPrettyError(t *testing.T) {
 	tester := newTester(t, nil)
 	defer tester.Close(t)
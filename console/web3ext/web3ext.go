@@ -36,6 +36,7 @@ var Modules = map[string]string{
 	"bootnode":         Bootnode_JS,
 	"chaindatafetcher": ChainDataFetcher_JS,
 	"eth":              Eth_JS,
+	"multisig":         Multisig_JS,
 }
 
 const Eth_JS = `
@@ -161,6 +162,35 @@ web3._extend({
 });
 `
 
+const Multisig_JS = `
+web3._extend({
+	property: 'multisig',
+	methods: [
+		new web3._extend.Method({
+			name: 'submit',
+			call: 'multisig_submit',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getProposal',
+			call: 'multisig_getProposal',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'discard',
+			call: 'multisig_discard',
+			params: 1
+		}),
+	],
+	properties: [
+		new web3._extend.Property({
+			name: 'proposals',
+			getter: 'multisig_proposals'
+		}),
+	]
+});
+`
+
 const ChainDataFetcher_JS = `
 web3._extend({
 	property: 'chaindatafetcher',
@@ -204,6 +234,81 @@ web3._extend({
 			name: 'getConfig',
 			call: 'chaindatafetcher_getConfig',
 			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getCheckpointStatus',
+			call: 'chaindatafetcher_getCheckpointStatus',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'requestReprocessing',
+			call: 'chaindatafetcher_requestReprocessing',
+			params: 3
+		}),
+		new web3._extend.Method({
+			name: 'getReprocessStatus',
+			call: 'chaindatafetcher_getReprocessStatus',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getReprocessStatuses',
+			call: 'chaindatafetcher_getReprocessStatuses',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'cancelReprocessing',
+			call: 'chaindatafetcher_cancelReprocessing',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getFilterRules',
+			call: 'chaindatafetcher_getFilterRules',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'setFilterRules',
+			call: 'chaindatafetcher_setFilterRules',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'reloadFilterRules',
+			call: 'chaindatafetcher_reloadFilterRules',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'startBackfill',
+			call: 'chaindatafetcher_startBackfill',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'stopBackfill',
+			call: 'chaindatafetcher_stopBackfill',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'resumeBackfill',
+			call: 'chaindatafetcher_resumeBackfill',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'getBackfillStatus',
+			call: 'chaindatafetcher_getBackfillStatus',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getDeadLetters',
+			call: 'chaindatafetcher_getDeadLetters',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'reprocessDeadLetter',
+			call: 'chaindatafetcher_reprocessDeadLetter',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'removeDeadLetter',
+			call: 'chaindatafetcher_removeDeadLetter',
+			params: 1
 		})
 	],
 	properties: []
@@ -281,6 +386,11 @@ web3._extend({
 			name: 'deleteAuthorizedNodes',
 			call: 'bootnode_deleteAuthorizedNodes',
 			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getTopicNodes',
+			call: 'bootnode_getTopicNodes',
+			params: 1
 		})
 	],
 	properties: []
@@ -374,6 +484,63 @@ web3._extend({
 			call: 'admin_removePeer',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'addTrustedPeer',
+			call: 'admin_addTrustedPeer',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'removeTrustedPeer',
+			call: 'admin_removeTrustedPeer',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'banPeer',
+			call: 'admin_banPeer',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+		new web3._extend.Method({
+			name: 'unbanPeer',
+			call: 'admin_unbanPeer',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'listBans',
+			call: 'admin_listBans',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'sentries',
+			call: 'admin_sentries',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'setActiveUpstream',
+			call: 'admin_setActiveUpstream',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'lookupTopic',
+			call: 'admin_lookupTopic',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+		new web3._extend.Method({
+			name: 'setInboundLimit',
+			call: 'admin_setInboundLimit',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'removeInboundLimit',
+			call: 'admin_removeInboundLimit',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'setEgressLimits',
+			call: 'admin_setEgressLimits',
+			params: 2
+		}),
 		new web3._extend.Method({
 			name: 'exportChain',
 			call: 'admin_exportChain',
@@ -431,6 +598,33 @@ web3._extend({
 			name: 'startStateMigration',
 			call: 'admin_startStateMigration',
 		}),
+		new web3._extend.Method({
+			name: 'reshardStateTrieDB',
+			call: 'admin_reshardStateTrieDB',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'pruneStateTries',
+			call: 'admin_pruneStateTries',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'createDbSnapshot',
+			call: 'admin_createDbSnapshot',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+		new web3._extend.Method({
+			name: 'freezeWrites',
+			call: 'admin_freezeWrites',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'thawWrites',
+			call: 'admin_thawWrites',
+		}),
 		new web3._extend.Method({
 			name: 'stopStateMigration',
 			call: 'admin_stopStateMigration',
@@ -444,6 +638,17 @@ web3._extend({
 			call: 'admin_setMaxSubscriptionPerWSConn',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'reloadConfig',
+			call: 'admin_reloadConfig',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'collectDiagnostics',
+			call: 'admin_collectDiagnostics',
+			params: 1,
+			inputFormatter: [null]
+		}),
 		new web3._extend.Method({
 			name: 'startSpamThrottler',
 			call: 'admin_startSpamThrottler',
@@ -471,6 +676,16 @@ web3._extend({
 			name: 'getSpamThrottlerCandidateList',
 			call: 'admin_getSpamThrottlerCandidateList',
 		}),
+		new web3._extend.Method({
+			name: 'setChannelWorkers',
+			call: 'admin_setChannelWorkers',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'setChannelQueueSize',
+			call: 'admin_setChannelQueueSize',
+			params: 1
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -481,6 +696,30 @@ web3._extend({
 			name: 'peers',
 			getter: 'admin_peers'
 		}),
+		new web3._extend.Property({
+			name: 'peerScores',
+			getter: 'admin_peerScores'
+		}),
+		new web3._extend.Property({
+			name: 'topology',
+			getter: 'admin_topology'
+		}),
+		new web3._extend.Property({
+			name: 'natStatus',
+			getter: 'admin_natStatus'
+		}),
+		new web3._extend.Property({
+			name: 'runtimePeers',
+			getter: 'admin_runtimePeers'
+		}),
+		new web3._extend.Property({
+			name: 'inboundLimits',
+			getter: 'admin_inboundLimits'
+		}),
+		new web3._extend.Property({
+			name: 'egressLimits',
+			getter: 'admin_egressLimits'
+		}),
 		new web3._extend.Property({
 			name: 'datadir',
 			getter: 'admin_datadir'
@@ -493,6 +732,18 @@ web3._extend({
 			name: 'spamThrottlerConfig',
 			getter: 'admin_spamThrottlerConfig'
 		}),
+		new web3._extend.Property({
+			name: 'channelConfig',
+			getter: 'admin_channelConfig'
+		}),
+		new web3._extend.Property({
+			name: 'channelBacklogs',
+			getter: 'admin_channelBacklogs'
+		}),
+		new web3._extend.Property({
+			name: 'proxyStatus',
+			getter: 'admin_proxyStatus'
+		}),
 	]
 });
 `
@@ -579,6 +830,15 @@ web3._extend({
 			call: 'debug_vmodule',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'setModuleLogLevel',
+			call: 'debug_setModuleLogLevel',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'getLogConfig',
+			call: 'debug_getLogConfig',
+		}),
 		new web3._extend.Method({
 			name: 'backtraceAt',
 			call: 'debug_backtraceAt',
@@ -750,6 +1010,26 @@ web3._extend({
 			call: 'debug_getBadBlocks',
 			params: 0,
 		}),
+		new web3._extend.Method({
+			name: 'propagationStats',
+			call: 'debug_propagationStats',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'cacheStats',
+			call: 'debug_cacheStats',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'dbStats',
+			call: 'debug_dbStats',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'dbRange',
+			call: 'debug_dbRange',
+			params: 4
+		}),
 		new web3._extend.Method({
 			name: 'storageRangeAt',
 			call: 'debug_storageRangeAt',
@@ -837,6 +1117,28 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputTransactionFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'buildAccountKey',
+			call: 'klay_buildAccountKey',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'buildAccountUpdate',
+			call: 'klay_buildAccountUpdate',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'signTypedData',
+			call: 'klay_signTypedData',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'verifyTypedData',
+			call: 'klay_verifyTypedData',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'getCouncil',
 			call: 'klay_getCouncil',
@@ -1090,6 +1392,11 @@ web3._extend({
 			call: 'personal_deriveAccount',
 			params: 3
 		}),
+		new web3._extend.Method({
+			name: 'importMnemonic',
+			call: 'personal_importMnemonic',
+			params: 3
+		}),
 		new web3._extend.Method({
 			name: 'sendValueTransfer',
 			call: 'personal_sendValueTransfer',
@@ -1108,6 +1415,24 @@ web3._extend({
 			params: 2,
 			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, null]
 		}),
+		new web3._extend.Method({
+			name: 'importRoleBasedKey',
+			call: 'personal_importRoleBasedKey',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'signTransactionWithRole',
+			call: 'personal_signTransactionWithRole',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'sendTransactionWithRole',
+			call: 'personal_sendTransactionWithRole',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, null, null]
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -1196,6 +1521,12 @@ web3._extend({
 			call: 'istanbul_getDemotedValidatorsAtHash',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'getCouncilHistory',
+			call: 'istanbul_getCouncilHistory',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'discard',
 			call: 'istanbul_discard',
@@ -1230,6 +1561,11 @@ web3._extend({
 			call: 'mainbridge_convertChildChainBlockHashToParentChainTxHash',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'getAnchoringReorgs',
+			call: 'mainbridge_getAnchoringReorgs',
+			params: 0
+		}),
 	],
     properties: [
 		new web3._extend.Property({
@@ -1269,6 +1605,41 @@ web3._extend({
 			call: 'subbridge_getAnchoringTxHashByBlockNumber',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'getPendingTransfers',
+			call: 'subbridge_getPendingTransfers',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'retryTransfer',
+			call: 'subbridge_retryTransfer',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'getFeeDelegationPolicy',
+			call: 'subbridge_getFeeDelegationPolicy',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'setFeeDelegationPolicy',
+			call: 'subbridge_setFeeDelegationPolicy',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getFeeDelegationUsage',
+			call: 'subbridge_getFeeDelegationUsage',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'sendRequestAsFeePayer',
+			call: 'subbridge_sendRequestAsFeePayer',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'reconcileBridge',
+			call: 'subbridge_reconcileBridge',
+			params: 3
+		}),
 		new web3._extend.Method({
 			name: 'registerOperator',
 			call: 'subbridge_registerOperator',
@@ -1539,6 +1910,10 @@ web3._extend({
 			name: 'parentKIP71Config',
 			getter: 'subbridge_getParentKIP71Config',
 		}),
+		new web3._extend.Property({
+			name: 'parentPeers',
+			getter: 'subbridge_getParentPeers',
+		}),
 	]
 });
 `
@@ -65,6 +65,129 @@ func (api *PublicChainDataFetcherAPI) WriteCheckpoint(checkpoint int64) error {
 	return api.f.checkpointDB.WriteCheckpoint(checkpoint)
 }
 
+// CheckpointStatus is the status of the checkpoint, which is the next block to
+// be exported. The blocks after the checkpoint may have been exported already.
+type CheckpointStatus struct {
+	Checkpoint       int64  `json:"checkpoint"`
+	StoredCheckpoint int64  `json:"storedCheckpoint"`
+	CurrentBlock     uint64 `json:"currentBlock"`
+	PendingBlocks    int    `json:"pendingBlocks"` // blocks exported after the checkpoint
+}
+
+// GetCheckpointStatus returns the checkpoint with the current block.
+func (api *PublicChainDataFetcherAPI) GetCheckpointStatus() (*CheckpointStatus, error) {
+	stored, err := api.f.checkpointDB.ReadCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+	api.f.checkpointMu.RLock()
+	defer api.f.checkpointMu.RUnlock()
+	return &CheckpointStatus{
+		Checkpoint:       api.f.checkpoint,
+		StoredCheckpoint: stored,
+		CurrentBlock:     api.f.blockchain.CurrentHeader().Number.Uint64(),
+		PendingBlocks:    len(api.f.checkpointMap),
+	}, nil
+}
+
+// RequestReprocessing queues the block range to be exported again without
+// updating the checkpoint, and returns the id of the request. The default types
+// of the mode are exported if reqType is not given.
+func (api *PublicChainDataFetcherAPI) RequestReprocessing(start, end uint64, reqType *uint) (uint64, error) {
+	var rt types.RequestType
+	if reqType != nil {
+		rt = types.RequestType(*reqType)
+	}
+	return api.f.requestReprocessing(start, end, rt)
+}
+
+// GetReprocessStatus returns the status of the reprocessing request.
+func (api *PublicChainDataFetcherAPI) GetReprocessStatus(id uint64) (*ReprocessStatus, error) {
+	return api.f.reprocessQueue.status(id)
+}
+
+// GetReprocessStatuses returns the statuses of the pending, running and recently
+// finished reprocessing requests.
+func (api *PublicChainDataFetcherAPI) GetReprocessStatuses() []ReprocessStatus {
+	return api.f.reprocessQueue.statuses()
+}
+
+// CancelReprocessing cancels the pending or running reprocessing request.
+func (api *PublicChainDataFetcherAPI) CancelReprocessing(id uint64) error {
+	return api.f.reprocessQueue.cancel(id)
+}
+
+// StartBackfill exports the historical block range with the parallel workers,
+// independently of the head-following fetching and its checkpoint.
+func (api *PublicChainDataFetcherAPI) StartBackfill(args BackfillArgs) error {
+	return api.f.startBackfill(args)
+}
+
+// StopBackfill stops the running backfill, which can be resumed later.
+func (api *PublicChainDataFetcherAPI) StopBackfill() error {
+	return api.f.stopBackfill()
+}
+
+// ResumeBackfill resumes the last backfill from its checkpoint, even after the
+// node is restarted. The last workers and rate are used if they are 0.
+func (api *PublicChainDataFetcherAPI) ResumeBackfill(workers, rate int) error {
+	return api.f.resumeBackfill(workers, rate)
+}
+
+// GetBackfillStatus returns the status of the last backfill.
+func (api *PublicChainDataFetcherAPI) GetBackfillStatus() (*BackfillStatus, error) {
+	return api.f.backfill.load()
+}
+
+// GetDroppedRanges returns the block ranges dropped by the drop backpressure,
+// which are not requested to be fetched again yet.
+func (api *PublicChainDataFetcherAPI) GetDroppedRanges() []DroppedRange {
+	return api.f.dropped.list()
+}
+
+// GetDeadLetters returns the blocks failed to be exported, which are skipped
+// by the fetching if the dead-letter queue is enabled.
+func (api *PublicChainDataFetcherAPI) GetDeadLetters() []DeadLetter {
+	return api.f.deadLetters.list()
+}
+
+// ReprocessDeadLetter exports the block of the dead letter again, and removes
+// the dead letter if it succeeds.
+func (api *PublicChainDataFetcherAPI) ReprocessDeadLetter(id uint64) error {
+	return api.f.reprocessDeadLetter(id)
+}
+
+// RemoveDeadLetter removes the dead letter without exporting it.
+func (api *PublicChainDataFetcherAPI) RemoveDeadLetter(id uint64) error {
+	return api.f.deadLetters.remove(id)
+}
+
+// GetFilterRules returns the filter rules of the exported data.
+func (api *PublicChainDataFetcherAPI) GetFilterRules() *FilterRules {
+	return api.f.filter.getRules()
+}
+
+// SetFilterRules replaces the filter rules of the exported data. Empty rules
+// export every block.
+func (api *PublicChainDataFetcherAPI) SetFilterRules(rules FilterRules) {
+	api.f.filter.setRules(&rules)
+	logger.Info("the filter rules are set", "addresses", len(rules.Addresses), "topics", len(rules.Topics), "txTypes", len(rules.TxTypes))
+}
+
+// ReloadFilterRules reloads the filter rules from the configured file.
+func (api *PublicChainDataFetcherAPI) ReloadFilterRules() (*FilterRules, error) {
+	if api.f.config.FilterRulesFile == "" {
+		return nil, errors.New("the filter rules file is not configured")
+	}
+	rules, err := LoadFilterRules(api.f.config.FilterRulesFile)
+	if err != nil {
+		return nil, err
+	}
+	api.f.filter.setRules(rules)
+	logger.Info("the filter rules are reloaded", "file", api.f.config.FilterRulesFile)
+	return rules, nil
+}
+
 // GetConfig returns the configuration setting of the launched chaindata fetcher.
 func (api *PublicChainDataFetcherAPI) GetConfig() *ChainDataFetcherConfig {
 	return api.f.config
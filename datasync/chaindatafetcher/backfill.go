@@ -0,0 +1,350 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package chaindatafetcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	cfTypes "github.com/klaytn/klaytn/datasync/chaindatafetcher/types"
+)
+
+const (
+	BackfillRunning = "running"
+	BackfillDone    = "done"
+	BackfillFailed  = "failed"
+	BackfillStopped = "stopped"
+)
+
+const (
+	DefaultBackfillWorkers = 4
+
+	// backfillStateFile is the file in the data directory keeping the progress of the backfill.
+	backfillStateFile     = "chaindatafetcher_backfill.json"
+	backfillStateInterval = 5 * time.Second
+)
+
+var (
+	errBackfillRunning    = errors.New("the backfill is already running")
+	errBackfillNotRunning = errors.New("the backfill is not running")
+	errNoBackfillToResume = errors.New("no backfill to resume")
+)
+
+// BackfillArgs are the arguments of a backfill exporting a historical block range.
+type BackfillArgs struct {
+	StartBlock uint64              `json:"startBlock"`
+	EndBlock   uint64              `json:"endBlock"`
+	Workers    int                 `json:"workers"`           // number of the blocks exported in parallel
+	Rate       int                 `json:"rate"`              // maximum blocks exported per second, 0 = unlimited
+	ReqType    cfTypes.RequestType `json:"reqType,omitempty"` // the default types of the mode are exported if 0
+}
+
+// BackfillStatus is the status of the backfill. All the blocks before the
+// checkpoint are exported, so the backfill is resumed from the checkpoint.
+type BackfillStatus struct {
+	BackfillArgs
+	State      string     `json:"state"`
+	Checkpoint uint64     `json:"checkpoint"`
+	Processed  uint64     `json:"processed"` // number of the blocks exported
+	Error      string     `json:"error,omitempty"`
+	ErrorBlock *uint64    `json:"errorBlock,omitempty"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// backfiller exports a historical block range with the parallel workers,
+// independently of the checkpoint of the head-following fetching.
+type backfiller struct {
+	mu        sync.Mutex
+	status    *BackfillStatus
+	exported  map[uint64]struct{} // the blocks exported after the checkpoint
+	stateFile string
+	lastSaved time.Time
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	doneCh   chan struct{} // closed when the backfill is finished
+	wg       sync.WaitGroup
+}
+
+func newBackfiller(stateFile string) *backfiller {
+	return &backfiller{stateFile: stateFile}
+}
+
+// begin starts the status of the backfill, if no backfill is running.
+func (b *backfiller) begin(args BackfillArgs) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.status != nil && b.status.State == BackfillRunning {
+		return errBackfillRunning
+	}
+	b.status = &BackfillStatus{
+		BackfillArgs: args,
+		State:        BackfillRunning,
+		Checkpoint:   args.StartBlock,
+		StartedAt:    time.Now(),
+	}
+	b.exported = make(map[uint64]struct{})
+	b.stopCh = make(chan struct{})
+	b.stopOnce = sync.Once{}
+	b.doneCh = make(chan struct{})
+	b.saveLocked()
+	return nil
+}
+
+// markExported records the exported block and moves the checkpoint over the
+// contiguous exported blocks.
+func (b *backfiller) markExported(num uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.status.Processed++
+	b.exported[num] = struct{}{}
+	for {
+		if _, ok := b.exported[b.status.Checkpoint]; !ok {
+			break
+		}
+		delete(b.exported, b.status.Checkpoint)
+		b.status.Checkpoint++
+	}
+	if time.Since(b.lastSaved) >= backfillStateInterval {
+		b.saveLocked()
+	}
+}
+
+// fail stops the backfill at the block failed to be exported.
+func (b *backfiller) fail(num uint64, err error) {
+	b.mu.Lock()
+	if b.status.Error == "" {
+		b.status.Error, b.status.ErrorBlock = err.Error(), &num
+	}
+	b.mu.Unlock()
+	b.stop()
+}
+
+func (b *backfiller) stop() {
+	b.stopOnce.Do(func() { close(b.stopCh) })
+}
+
+// finish sets the final state of the backfill after the workers are terminated.
+func (b *backfiller) finish(stopped bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.status.FinishedAt = &now
+	switch {
+	case b.status.Error != "":
+		b.status.State = BackfillFailed
+	case stopped:
+		b.status.State = BackfillStopped
+	default:
+		b.status.State = BackfillDone
+	}
+	b.saveLocked()
+	close(b.doneCh)
+}
+
+func (b *backfiller) getStatus() *BackfillStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.status == nil {
+		return nil
+	}
+	cpy := *b.status
+	return &cpy
+}
+
+// saveLocked writes the status into the state file, to resume the backfill
+// after the node is restarted. The status is not kept for the ephemeral nodes.
+func (b *backfiller) saveLocked() {
+	if b.stateFile == "" {
+		return
+	}
+	b.lastSaved = time.Now()
+	data, err := json.Marshal(b.status)
+	if err != nil {
+		logger.Error("failed to marshal the backfill status", "err", err)
+		return
+	}
+	tmp := b.stateFile + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0o600); err != nil {
+		logger.Error("failed to write the backfill status", "file", tmp, "err", err)
+		return
+	}
+	if err := os.Rename(tmp, b.stateFile); err != nil {
+		logger.Error("failed to write the backfill status", "file", b.stateFile, "err", err)
+	}
+}
+
+// load returns the status of the last backfill, kept in the state file.
+func (b *backfiller) load() (*BackfillStatus, error) {
+	if s := b.getStatus(); s != nil {
+		return s, nil
+	}
+	if b.stateFile == "" {
+		return nil, errNoBackfillToResume
+	}
+	data, err := ioutil.ReadFile(b.stateFile)
+	if os.IsNotExist(err) {
+		return nil, errNoBackfillToResume
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s BackfillStatus
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// startBackfill exports the block range with the parallel workers without
+// updating the checkpoint. The blocks are dispatched to the workers in order,
+// at most args.Rate blocks per second.
+func (f *ChainDataFetcher) startBackfill(args BackfillArgs) error {
+	if args.StartBlock > args.EndBlock {
+		return fmt.Errorf("invalid block range to backfill: start %d is greater than end %d", args.StartBlock, args.EndBlock)
+	}
+	if head := f.blockchain.CurrentHeader().Number.Uint64(); args.EndBlock > head {
+		return fmt.Errorf("invalid block range to backfill: end %d is greater than the current block %d", args.EndBlock, head)
+	}
+	if args.Workers <= 0 {
+		args.Workers = DefaultBackfillWorkers
+	}
+	if args.ReqType == 0 {
+		args.ReqType = f.defaultRequestType()
+	}
+	b := f.backfill
+	if err := b.begin(args); err != nil {
+		return err
+	}
+
+	numCh := make(chan uint64)
+	b.wg.Add(args.Workers)
+	for i := 0; i < args.Workers; i++ {
+		go f.backfillWorker(args.ReqType, numCh)
+	}
+	go func() {
+		stopped := !f.dispatchBackfill(args, numCh)
+		close(numCh)
+		b.wg.Wait()
+		b.finish(stopped)
+		s := b.getStatus()
+		logger.Info("backfill is finished", "startBlock", s.StartBlock, "endBlock", s.EndBlock, "state", s.State, "checkpoint", s.Checkpoint, "processed", s.Processed)
+	}()
+	logger.Info("backfill is started", "startBlock", args.StartBlock, "endBlock", args.EndBlock, "workers", args.Workers, "rate", args.Rate, "reqType", args.ReqType)
+	return nil
+}
+
+// dispatchBackfill sends the block numbers to the workers, and returns false
+// if the backfill is stopped before all the blocks are sent.
+func (f *ChainDataFetcher) dispatchBackfill(args BackfillArgs, numCh chan<- uint64) bool {
+	var tick <-chan time.Time
+	if args.Rate > 0 && args.Rate <= int(time.Second) {
+		ticker := time.NewTicker(time.Second / time.Duration(args.Rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for num := args.StartBlock; num <= args.EndBlock; num++ {
+		if tick != nil {
+			select {
+			case <-tick:
+			case <-f.backfill.stopCh:
+				return false
+			case <-f.stopCh:
+				return false
+			}
+		}
+		select {
+		case numCh <- num:
+		case <-f.backfill.stopCh:
+			return false
+		case <-f.stopCh:
+			return false
+		}
+	}
+	return true
+}
+
+func (f *ChainDataFetcher) backfillWorker(reqType cfTypes.RequestType, numCh <-chan uint64) {
+	defer f.backfill.wg.Done()
+	for num := range numCh {
+		select {
+		case <-f.stopCh:
+			return
+		case <-f.backfill.stopCh:
+			return
+		default:
+		}
+		ev, err := f.makeChainEvent(num)
+		if err == nil {
+			err = f.handleRequestByType(reqType, false, ev)
+		}
+		if err != nil {
+			logger.Error("backfilling the block is failed", "blockNumber", num, "err", err)
+			f.backfill.fail(num, err)
+			continue
+		}
+		backfilledBlocksCounter.Inc(1)
+		f.backfill.markExported(num)
+	}
+}
+
+func (f *ChainDataFetcher) stopBackfill() error {
+	b := f.backfill
+	b.mu.Lock()
+	if b.status == nil || b.status.State != BackfillRunning {
+		b.mu.Unlock()
+		return errBackfillNotRunning
+	}
+	doneCh := b.doneCh
+	b.mu.Unlock()
+
+	b.stop()
+	<-doneCh
+	logger.Info("backfill is stopped")
+	return nil
+}
+
+// resumeBackfill starts the last backfill again from its checkpoint. The
+// workers and the rate of the last backfill are used if not given.
+func (f *ChainDataFetcher) resumeBackfill(workers, rate int) error {
+	s, err := f.backfill.load()
+	if err != nil {
+		return err
+	}
+	if s.Checkpoint > s.EndBlock {
+		return errNoBackfillToResume
+	}
+	args := s.BackfillArgs
+	args.StartBlock = s.Checkpoint
+	if workers > 0 {
+		args.Workers = workers
+	}
+	if rate > 0 {
+		args.Rate = rate
+	}
+	return f.startBackfill(args)
+}
@@ -0,0 +1,106 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package chaindatafetcher
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/datasync/chaindatafetcher/mocks"
+	cfTypes "github.com/klaytn/klaytn/datasync/chaindatafetcher/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackfiller_Checkpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chaindatafetcher")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	b := newBackfiller(filepath.Join(dir, backfillStateFile))
+	assert.NoError(t, b.begin(BackfillArgs{StartBlock: 10, EndBlock: 20, Workers: 2}))
+	assert.Equal(t, errBackfillRunning, b.begin(BackfillArgs{StartBlock: 10, EndBlock: 20}))
+
+	// The checkpoint moves over the contiguous exported blocks only.
+	b.markExported(11)
+	b.markExported(13)
+	assert.Equal(t, uint64(10), b.getStatus().Checkpoint)
+	b.markExported(10)
+	assert.Equal(t, uint64(12), b.getStatus().Checkpoint)
+
+	b.fail(12, errors.New("test-error"))
+	b.finish(true)
+	s := b.getStatus()
+	assert.Equal(t, BackfillFailed, s.State)
+	assert.Equal(t, uint64(3), s.Processed)
+	assert.Equal(t, uint64(12), *s.ErrorBlock)
+
+	// The status is loaded from the state file after the node is restarted.
+	loaded, err := newBackfiller(b.stateFile).load()
+	assert.NoError(t, err)
+	assert.Equal(t, s.Checkpoint, loaded.Checkpoint)
+	assert.Equal(t, 2, loaded.Workers)
+
+	_, err = newBackfiller(filepath.Join(dir, "none.json")).load()
+	assert.Equal(t, errNoBackfillToResume, err)
+	_, err = newBackfiller("").load()
+	assert.Equal(t, errNoBackfillToResume, err)
+}
+
+func TestChainDataFetcher_Backfill(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	bc := mocks.NewMockBlockChain(ctrl)
+	repo := mocks.NewMockRepository(ctrl)
+	fetcher := newTestChainDataFetcher()
+	fetcher.config = &ChainDataFetcherConfig{Mode: ModeKafka}
+	fetcher.blockchain, fetcher.repo = bc, repo
+	fetcher.backfill = newBackfiller("")
+	defer close(fetcher.stopCh)
+
+	bc.EXPECT().CurrentHeader().Return(&types.Header{Number: big.NewInt(100)}).AnyTimes()
+	bc.EXPECT().GetBlockByNumber(gomock.Any()).DoAndReturn(func(num uint64) *types.Block {
+		return types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(num)})
+	}).AnyTimes()
+	bc.EXPECT().GetReceiptsByBlockHash(gomock.Any()).Return(types.Receipts{}).AnyTimes()
+	repo.EXPECT().HandleChainEvent(gomock.Any(), cfTypes.RequestTypeBlockGroup).Return(nil).Times(10)
+
+	assert.Error(t, fetcher.startBackfill(BackfillArgs{StartBlock: 5, EndBlock: 4}))
+	assert.Error(t, fetcher.startBackfill(BackfillArgs{StartBlock: 5, EndBlock: 101}))
+	assert.Equal(t, errBackfillNotRunning, fetcher.stopBackfill())
+
+	args := BackfillArgs{StartBlock: 11, EndBlock: 20, Workers: 3, Rate: 1000, ReqType: cfTypes.RequestTypeBlockGroup}
+	assert.NoError(t, fetcher.startBackfill(args))
+	assert.Eventually(t, func() bool {
+		return fetcher.backfill.getStatus().State == BackfillDone
+	}, 3*time.Second, 10*time.Millisecond)
+
+	s := fetcher.backfill.getStatus()
+	assert.Equal(t, uint64(10), s.Processed)
+	assert.Equal(t, uint64(21), s.Checkpoint)
+	assert.Equal(t, int64(0), fetcher.checkpoint) // The checkpoint of the fetching is not updated.
+
+	// Nothing is left to be resumed.
+	assert.Equal(t, errNoBackfillToResume, fetcher.resumeBackfill(0, 0))
+}
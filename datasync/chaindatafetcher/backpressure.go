@@ -0,0 +1,143 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package chaindatafetcher
+
+import (
+	"sync"
+
+	"github.com/klaytn/klaytn/blockchain"
+)
+
+const (
+	// BackpressureBlock blocks the chain event feed while the block channel is full.
+	BackpressureBlock = "block"
+	// BackpressureDrop drops the chain events while the block channel is full, and records
+	// the dropped blocks to fetch them again from the database.
+	BackpressureDrop = "drop"
+)
+
+// DroppedRange is a range of the blocks dropped from the full block channel.
+type DroppedRange struct {
+	StartBlock uint64 `json:"startBlock"`
+	EndBlock   uint64 `json:"endBlock"`
+}
+
+// droppedRanges keeps the dropped blocks until they are requested again.
+type droppedRanges struct {
+	mu     sync.Mutex
+	ranges []DroppedRange
+	wakeCh chan struct{}
+}
+
+func newDroppedRanges() *droppedRanges {
+	return &droppedRanges{wakeCh: make(chan struct{}, 1)}
+}
+
+// add records the dropped block, merging it into the last range if they are contiguous.
+func (d *droppedRanges) add(num uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if n := len(d.ranges); n > 0 && d.ranges[n-1].EndBlock+1 == num {
+		d.ranges[n-1].EndBlock = num
+	} else {
+		d.ranges = append(d.ranges, DroppedRange{num, num})
+	}
+	select {
+	case d.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+func (d *droppedRanges) pop() (DroppedRange, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.ranges) == 0 {
+		return DroppedRange{}, false
+	}
+	r := d.ranges[0]
+	d.ranges = d.ranges[1:]
+	return r, true
+}
+
+func (d *droppedRanges) list() []DroppedRange {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ranges := make([]DroppedRange, len(d.ranges))
+	copy(ranges, d.ranges)
+	return ranges
+}
+
+func (d *droppedRanges) reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.ranges = nil
+}
+
+// forwardChainEvents forwards the chain events to the block channel without blocking the
+// chain event feed. The events are dropped and recorded while the block channel is full.
+func (f *ChainDataFetcher) forwardChainEvents(eventCh <-chan blockchain.ChainEvent, stopCh chan struct{}) {
+	defer f.fetchingWg.Done()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case ev := <-eventCh:
+			select {
+			case f.chainCh <- ev:
+			default:
+				num := ev.Block.NumberU64()
+				f.dropped.add(num)
+				droppedBlocksCounter.Inc(1)
+				logger.Warn("the block channel is full. the block is dropped and will be fetched again", "blockNumber", num)
+			}
+			f.updateLag(ev.Block.Number().Int64())
+		}
+	}
+}
+
+// refetchDroppedBlocks requests the dropped blocks again, which are read from the database.
+// The requests update the checkpoint, so that no dropped block is skipped by the checkpoint.
+func (f *ChainDataFetcher) refetchDroppedBlocks(stopCh chan struct{}) {
+	defer f.fetchingWg.Done()
+	for {
+		r, ok := f.dropped.pop()
+		if !ok {
+			select {
+			case <-stopCh:
+				return
+			case <-f.dropped.wakeCh:
+				continue
+			}
+		}
+		refetchedBlocksCounter.Inc(int64(r.EndBlock - r.StartBlock + 1))
+		f.sendRequests(r.StartBlock, r.EndBlock, f.defaultRequestType(), true, stopCh)
+	}
+}
+
+// updateLag updates the number of the blocks between the given head block and the checkpoint.
+func (f *ChainDataFetcher) updateLag(head int64) {
+	f.checkpointMu.RLock()
+	lag := head - f.checkpoint
+	f.checkpointMu.RUnlock()
+	if lag < 0 {
+		lag = 0
+	}
+	checkpointLagGauge.Update(lag)
+}
@@ -0,0 +1,93 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package chaindatafetcher
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDroppedRanges(t *testing.T) {
+	d := newDroppedRanges()
+	for _, num := range []uint64{3, 4, 5, 7, 8, 10} {
+		d.add(num)
+	}
+	assert.Equal(t, []DroppedRange{{3, 5}, {7, 8}, {10, 10}}, d.list())
+
+	r, ok := d.pop()
+	assert.True(t, ok)
+	assert.Equal(t, DroppedRange{3, 5}, r)
+	assert.Equal(t, []DroppedRange{{7, 8}, {10, 10}}, d.list())
+
+	d.reset()
+	_, ok = d.pop()
+	assert.False(t, ok)
+}
+
+func TestChainDataFetcher_forwardChainEvents(t *testing.T) {
+	fetcher := newTestChainDataFetcher()
+	fetcher.chainCh = make(chan blockchain.ChainEvent, 2)
+	fetcher.dropped = newDroppedRanges()
+
+	eventCh := make(chan blockchain.ChainEvent)
+	stopCh := make(chan struct{})
+	fetcher.fetchingWg.Add(1)
+	go fetcher.forwardChainEvents(eventCh, stopCh)
+
+	// the events over the channel size are dropped without blocking the feed
+	for i := int64(1); i <= 5; i++ {
+		eventCh <- blockchain.ChainEvent{Block: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(i)})}
+	}
+	assert.Eventually(t, func() bool {
+		dropped := fetcher.dropped.list()
+		return len(dropped) == 1 && dropped[0].EndBlock == 5
+	}, time.Second, 10*time.Millisecond)
+	close(stopCh)
+	fetcher.fetchingWg.Wait()
+
+	assert.Equal(t, 2, len(fetcher.chainCh))
+	assert.Equal(t, uint64(1), (<-fetcher.chainCh).Block.NumberU64())
+	assert.Equal(t, []DroppedRange{{3, 5}}, fetcher.dropped.list())
+}
+
+func TestChainDataFetcher_refetchDroppedBlocks(t *testing.T) {
+	fetcher := newTestChainDataFetcher()
+	fetcher.dropped = newDroppedRanges()
+
+	stopCh := make(chan struct{})
+	fetcher.fetchingWg.Add(1)
+	go fetcher.refetchDroppedBlocks(stopCh)
+
+	fetcher.dropped.add(3)
+	fetcher.dropped.add(4)
+	for _, num := range []uint64{3, 4} {
+		select {
+		case req := <-fetcher.reqCh:
+			assert.Equal(t, num, req.BlockNumber)
+			assert.True(t, req.ShouldUpdateCheckpoint)
+		case <-time.After(time.Second):
+			t.Fatal("the dropped block is not requested", num)
+		}
+	}
+	close(stopCh)
+	fetcher.fetchingWg.Wait()
+}
@@ -28,8 +28,10 @@ import (
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/blockchain/vm"
 	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/datasync/chaindatafetcher/elasticsearch"
 	"github.com/klaytn/klaytn/datasync/chaindatafetcher/kafka"
 	"github.com/klaytn/klaytn/datasync/chaindatafetcher/kas"
+	"github.com/klaytn/klaytn/datasync/chaindatafetcher/postgres"
 	cfTypes "github.com/klaytn/klaytn/datasync/chaindatafetcher/types"
 	"github.com/klaytn/klaytn/event"
 	"github.com/klaytn/klaytn/log"
@@ -73,6 +75,9 @@ type ChainDataFetcher struct {
 
 	numHandlers int
 
+	filter         *eventFilter
+	reprocessQueue *reprocessQueue
+
 	checkpointMu  sync.RWMutex
 	checkpoint    int64
 	checkpointMap map[int64]struct{}
@@ -89,6 +94,12 @@ type ChainDataFetcher struct {
 	rangeFetchingStarted uint32
 	rangeFetchingStopCh  chan struct{}
 	rangeFetchingWg      sync.WaitGroup
+
+	dropped *droppedRanges // the blocks dropped by the drop backpressure, which are fetched again
+
+	backfill *backfiller
+
+	deadLetters *deadLetterQueue // the blocks failed to be exported, which are reprocessed by the API
 }
 
 func NewChainDataFetcher(ctx *node.ServiceContext, cfg *ChainDataFetcherConfig) (*ChainDataFetcher, error) {
@@ -109,20 +120,47 @@ func NewChainDataFetcher(ctx *node.ServiceContext, cfg *ChainDataFetcherConfig)
 		if err != nil {
 			return nil, err
 		}
+	case ModePostgres:
+		repo, checkpointDB, setters, err = getPostgresComponents(cfg.PostgresConfig)
+		if err != nil {
+			return nil, err
+		}
+	case ModeElasticsearch:
+		repo, checkpointDB, setters, err = getElasticsearchComponents(cfg.ElasticsearchConfig)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		logger.Error("the chaindatafetcher mode is not supported", "mode", cfg.Mode)
 		return nil, errUnsupportedMode
 	}
+	var rules *FilterRules
+	if cfg.FilterRulesFile != "" {
+		if rules, err = LoadFilterRules(cfg.FilterRulesFile); err != nil {
+			logger.Error("loading the filter rules is failed", "file", cfg.FilterRulesFile, "err", err)
+			return nil, err
+		}
+	}
+	var backfillFile, deadLetterPath string
+	if ctx != nil {
+		backfillFile = ctx.ResolvePath(backfillStateFile)
+		deadLetterPath = ctx.ResolvePath(deadLetterFile)
+	}
 	return &ChainDataFetcher{
-		config:        cfg,
-		chainCh:       make(chan blockchain.ChainEvent, cfg.BlockChannelSize),
-		reqCh:         make(chan *cfTypes.Request, cfg.JobChannelSize),
-		stopCh:        make(chan struct{}),
-		numHandlers:   cfg.NumHandlers,
-		checkpointMap: make(map[int64]struct{}),
-		repo:          repo,
-		checkpointDB:  checkpointDB,
-		setters:       setters,
+		config:         cfg,
+		chainCh:        make(chan blockchain.ChainEvent, cfg.BlockChannelSize),
+		reqCh:          make(chan *cfTypes.Request, cfg.JobChannelSize),
+		stopCh:         make(chan struct{}),
+		numHandlers:    cfg.NumHandlers,
+		filter:         newEventFilter(rules),
+		reprocessQueue: newReprocessQueue(),
+		checkpointMap:  make(map[int64]struct{}),
+		dropped:        newDroppedRanges(),
+		backfill:       newBackfiller(backfillFile),
+		deadLetters:    newDeadLetterQueue(deadLetterPath),
+		repo:           repo,
+		checkpointDB:   checkpointDB,
+		setters:        setters,
 	}, nil
 }
 
@@ -143,6 +181,22 @@ func getKafkaComponents(cfg *kafka.KafkaConfig) (Repository, CheckpointDB, []Com
 	return repo, checkpointDB, []ComponentSetter{repo, checkpointDB}, nil
 }
 
+func getPostgresComponents(cfg *postgres.PostgresConfig) (Repository, CheckpointDB, []ComponentSetter, error) {
+	repo, err := postgres.NewRepository(cfg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return repo, repo, nil, nil
+}
+
+func getElasticsearchComponents(cfg *elasticsearch.ElasticsearchConfig) (Repository, CheckpointDB, []ComponentSetter, error) {
+	repo, err := elasticsearch.NewRepository(cfg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return repo, repo, nil, nil
+}
+
 func (f *ChainDataFetcher) Protocols() []p2p.Protocol {
 	return []p2p.Protocol{}
 }
@@ -163,6 +217,8 @@ func (f *ChainDataFetcher) Start(server p2p.Server) error {
 	for i := 0; i < f.numHandlers; i++ {
 		go f.handleRequest()
 	}
+	f.wg.Add(1)
+	go f.reprocessLoop()
 
 	if !f.config.NoDefaultStart {
 		if err := f.startFetching(); err != nil {
@@ -202,12 +258,23 @@ func (f *ChainDataFetcher) startFetching() error {
 		return errors.New("fetching is already started")
 	}
 
+	f.fetchingStopCh = make(chan struct{})
+
 	// subscribe chain event in order to handle new blocks.
-	f.chainSub = f.blockchain.SubscribeChainEvent(f.chainCh)
+	if f.config.Backpressure == BackpressureDrop {
+		// the blocks dropped before are fetched again from the checkpoint below.
+		f.dropped.reset()
+		eventCh := make(chan blockchain.ChainEvent, f.config.BlockChannelSize)
+		f.chainSub = f.blockchain.SubscribeChainEvent(eventCh)
+		f.fetchingWg.Add(2)
+		go f.forwardChainEvents(eventCh, f.fetchingStopCh)
+		go f.refetchDroppedBlocks(f.fetchingStopCh)
+	} else {
+		f.chainSub = f.blockchain.SubscribeChainEvent(f.chainCh)
+	}
 	checkpoint := uint64(f.checkpoint)
 	currentBlock := f.blockchain.CurrentHeader().Number.Uint64()
 
-	f.fetchingStopCh = make(chan struct{})
 	f.fetchingWg.Add(1)
 
 	// lanuch a goroutine to handle from checkpoint to the head block.
@@ -216,7 +283,7 @@ func (f *ChainDataFetcher) startFetching() error {
 		switch f.config.Mode {
 		case ModeKAS:
 			f.sendRequests(uint64(f.checkpoint), currentBlock, cfTypes.RequestTypeAll, true, f.fetchingStopCh)
-		case ModeKafka:
+		case ModeKafka, ModePostgres, ModeElasticsearch:
 			f.sendRequests(uint64(f.checkpoint), currentBlock, cfTypes.RequestTypeGroupAll, true, f.fetchingStopCh)
 		default:
 			logger.Error("the chaindatafetcher mode is not supported", "mode", f.config.Mode, "checkpoint", f.checkpoint, "currentBlock", currentBlock)
@@ -277,28 +344,9 @@ func (f *ChainDataFetcher) makeChainEvent(blockNumber uint64) (blockchain.ChainE
 	for _, r := range receipts {
 		logs = append(logs, r.Logs...)
 	}
-	var internalTraces []*vm.InternalTxTrace
-	if block.Transactions().Len() > 0 {
-		fct := "fastCallTracer"
-		timeout := "24h"
-		results, err := f.debugAPI.TraceBlockByNumber(context.Background(), rpc.BlockNumber(block.Number().Int64()), &cn.TraceConfig{
-			Tracer:  &fct,
-			Timeout: &timeout,
-		})
-		if err != nil {
-			traceAPIErrorCounter.Inc(1)
-			logger.Error("Failed to call trace block by number", "err", err, "blockNumber", block.NumberU64())
-			return blockchain.ChainEvent{}, err
-		}
-		for _, r := range results {
-			if r.Result != nil {
-				internalTraces = append(internalTraces, r.Result.(*vm.InternalTxTrace))
-			} else {
-				traceAPIErrorCounter.Inc(1)
-				logger.Error("the trace result is nil", "err", r.Error, "blockNumber", blockNumber)
-				internalTraces = append(internalTraces, &vm.InternalTxTrace{Value: "0x0", Calls: []*vm.InternalTxTrace{}})
-			}
-		}
+	internalTraces, err := f.traceBlock(block)
+	if err != nil {
+		return blockchain.ChainEvent{}, err
 	}
 
 	return blockchain.ChainEvent{
@@ -310,6 +358,36 @@ func (f *ChainDataFetcher) makeChainEvent(blockNumber uint64) (blockchain.ChainE
 	}, nil
 }
 
+// traceBlock runs the call tracer on the transactions of the block, and returns
+// the traces in the order of the transactions.
+func (f *ChainDataFetcher) traceBlock(block *types.Block) ([]*vm.InternalTxTrace, error) {
+	var internalTraces []*vm.InternalTxTrace
+	if block.Transactions().Len() == 0 {
+		return nil, nil
+	}
+	fct := "fastCallTracer"
+	timeout := "24h"
+	results, err := f.debugAPI.TraceBlockByNumber(context.Background(), rpc.BlockNumber(block.Number().Int64()), &cn.TraceConfig{
+		Tracer:  &fct,
+		Timeout: &timeout,
+	})
+	if err != nil {
+		traceAPIErrorCounter.Inc(1)
+		logger.Error("Failed to call trace block by number", "err", err, "blockNumber", block.NumberU64())
+		return nil, err
+	}
+	for _, r := range results {
+		if r.Result != nil {
+			internalTraces = append(internalTraces, r.Result.(*vm.InternalTxTrace))
+		} else {
+			traceAPIErrorCounter.Inc(1)
+			logger.Error("the trace result is nil", "err", r.Error, "blockNumber", block.NumberU64())
+			internalTraces = append(internalTraces, &vm.InternalTxTrace{Value: "0x0", Calls: []*vm.InternalTxTrace{}})
+		}
+	}
+	return internalTraces, nil
+}
+
 func (f *ChainDataFetcher) Components() []interface{} {
 	return nil
 }
@@ -359,6 +437,13 @@ func (f *ChainDataFetcher) handleRequestByType(reqType cfTypes.RequestType, shou
 	now := time.Now()
 	// TODO-ChainDataFetcher parallelize handling data
 
+	ev, matched := f.filter.filter(ev)
+	if !matched {
+		// nothing is exported, but the checkpoint is updated
+		filteredBlocksCounter.Inc(1)
+		reqType = 0
+	}
+
 	// iterate over all types of requests
 	// - RequestTypeTransaction
 	// - RequestTypeTokenTransfer
@@ -366,10 +451,14 @@ func (f *ChainDataFetcher) handleRequestByType(reqType cfTypes.RequestType, shou
 	// - RequestTypeTrace
 	// - RequestTypeBlockGroup
 	// - RequestTypeTraceGroup
+	// - RequestTypeInternalTransferGroup
 	for targetType := cfTypes.RequestTypeTransaction; targetType < cfTypes.RequestTypeLength; targetType = targetType << 1 {
 		if cfTypes.CheckRequestType(reqType, targetType) {
 			if err := f.updateInsertionTimeGauge(f.retryFunc(f.repo.HandleChainEvent))(ev, targetType); err != nil {
 				logger.Error("handling chain event is failed", "blockNumber", ev.Block.NumberU64(), "err", err, "reqType", reqType, "targetType", targetType)
+				if f.deadLetter(ev, targetType, err) {
+					continue
+				}
 				return err
 			}
 		}
@@ -421,11 +510,18 @@ func (f *ChainDataFetcher) handleRequest() {
 			return
 		case ev := <-f.chainCh:
 			numChainEventGauge.Update(int64(len(f.chainCh)))
+			f.updateLag(ev.Block.Number().Int64())
 			var err error
+			// the chain events have no trace if the internal tx tracing of the node is disabled
+			if len(ev.InternalTxTraces) == 0 && ev.Block.Transactions().Len() > 0 && f.debugAPI != nil {
+				if ev.InternalTxTraces, err = f.traceBlock(ev.Block); err != nil {
+					logger.Error("tracing the block is failed. the traces are not exported", "blockNumber", ev.Block.NumberU64(), "err", err)
+				}
+			}
 			switch f.config.Mode {
 			case ModeKAS:
 				err = f.handleRequestByType(cfTypes.RequestTypeAll, true, ev)
-			case ModeKafka:
+			case ModeKafka, ModePostgres, ModeElasticsearch:
 				err = f.handleRequestByType(cfTypes.RequestTypeGroupAll, true, ev)
 			default:
 				logger.Error("the chaindatafetcher mode is not supported", "mode", f.config.Mode, "blockNumber", ev.Block.NumberU64())
@@ -490,6 +586,8 @@ func getInsertionTimeGauge(reqType cfTypes.RequestType) metrics.Gauge {
 		return blockGroupInsertionTimeGauge
 	case cfTypes.RequestTypeTraceGroup:
 		return traceGroupInsertionTimeGauge
+	case cfTypes.RequestTypeInternalTransferGroup:
+		return internalTransferGroupInsertionTimeGauge
 	default:
 		logger.Warn("the request type is not supported", "type", reqType)
 		return metrics.NilGauge{}
@@ -523,6 +621,8 @@ func getInsertionRetryGauge(reqType cfTypes.RequestType) metrics.Gauge {
 		return blockGroupInsertionRetryGauge
 	case cfTypes.RequestTypeTraceGroup:
 		return traceGroupInsertionRetryGauge
+	case cfTypes.RequestTypeInternalTransferGroup:
+		return internalTransferGroupInsertionRetryGauge
 	default:
 		logger.Warn("the request type is not supported", "type", reqType)
 		return metrics.NilGauge{}
@@ -533,6 +633,10 @@ func (f *ChainDataFetcher) retryFunc(insert HandleChainEventFn) HandleChainEvent
 	return func(event blockchain.ChainEvent, reqType cfTypes.RequestType) error {
 		i := 0
 		for err := insert(event, reqType); err != nil; err = insert(event, reqType) {
+			if errors.Is(err, cfTypes.ErrPoisonRecord) {
+				// retrying never succeeds
+				return err
+			}
 			select {
 			case <-f.stopCh:
 				return err
@@ -35,14 +35,16 @@ import (
 
 func newTestChainDataFetcher() *ChainDataFetcher {
 	return &ChainDataFetcher{
-		config:        DefaultChainDataFetcherConfig,
-		chainCh:       make(chan blockchain.ChainEvent),
-		reqCh:         make(chan *cfTypes.Request),
-		stopCh:        make(chan struct{}),
-		numHandlers:   3,
-		checkpoint:    0,
-		checkpointMap: make(map[int64]struct{}),
-		repo:          nil,
+		config:         DefaultChainDataFetcherConfig,
+		chainCh:        make(chan blockchain.ChainEvent),
+		reqCh:          make(chan *cfTypes.Request),
+		stopCh:         make(chan struct{}),
+		numHandlers:    3,
+		filter:         newEventFilter(nil),
+		reprocessQueue: newReprocessQueue(),
+		checkpoint:     0,
+		checkpointMap:  make(map[int64]struct{}),
+		repo:           nil,
 	}
 }
 
@@ -217,6 +219,7 @@ func TestChainDataFetcher_handleRequestByType_WhileRetrying(t *testing.T) {
 		checkpoint:    1,
 		checkpointMap: make(map[int64]struct{}), // in order to call CheckpointDB WriteCheckpoint method
 		stopCh:        make(chan struct{}),      // in order to stop retrying
+		filter:        newEventFilter(nil),
 	}
 	header1 := &types.Header{Number: big.NewInt(1)} // next block to be handled is 1
 	block1 := blockchain.ChainEvent{Block: types.NewBlockWithHeader(header1)}
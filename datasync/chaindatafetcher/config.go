@@ -17,8 +17,10 @@
 package chaindatafetcher
 
 import (
+	"github.com/klaytn/klaytn/datasync/chaindatafetcher/elasticsearch"
 	"github.com/klaytn/klaytn/datasync/chaindatafetcher/kafka"
 	"github.com/klaytn/klaytn/datasync/chaindatafetcher/kas"
+	"github.com/klaytn/klaytn/datasync/chaindatafetcher/postgres"
 )
 
 type ChainDataFetcherMode int
@@ -26,6 +28,8 @@ type ChainDataFetcherMode int
 const (
 	ModeKAS = ChainDataFetcherMode(iota)
 	ModeKafka
+	ModePostgres
+	ModeElasticsearch
 )
 
 const (
@@ -43,9 +47,15 @@ type ChainDataFetcherConfig struct {
 	NumHandlers             int
 	JobChannelSize          int
 	BlockChannelSize        int
+	FilterRulesFile         string // FilterRulesFile is the JSON file of the filter rules, reloaded by the API.
+	Backpressure            string // Backpressure is the behavior on the full block channel, "block" or "drop".
+	DeadLetter              bool   // DeadLetter moves the blocks failed to be exported to the dead-letter queue instead of pausing.
 
-	KasConfig   *kas.KASConfig `json:"-"` // Deprecated: This configuration is not used anymore.
-	KafkaConfig *kafka.KafkaConfig
+	KasConfig      *kas.KASConfig `json:"-"` // Deprecated: This configuration is not used anymore.
+	KafkaConfig    *kafka.KafkaConfig
+	PostgresConfig *postgres.PostgresConfig
+
+	ElasticsearchConfig *elasticsearch.ElasticsearchConfig
 }
 
 var DefaultChainDataFetcherConfig = &ChainDataFetcherConfig{
@@ -55,7 +65,11 @@ var DefaultChainDataFetcherConfig = &ChainDataFetcherConfig{
 	NumHandlers:             DefaultNumHandlers,
 	JobChannelSize:          DefaultJobChannelSize,
 	BlockChannelSize:        DefaultBlockChannelSize,
+	Backpressure:            BackpressureBlock,
+
+	KasConfig:      kas.DefaultKASConfig,
+	KafkaConfig:    kafka.GetDefaultKafkaConfig(),
+	PostgresConfig: postgres.GetDefaultPostgresConfig(),
 
-	KasConfig:   kas.DefaultKASConfig,
-	KafkaConfig: kafka.GetDefaultKafkaConfig(),
+	ElasticsearchConfig: elasticsearch.GetDefaultElasticsearchConfig(),
 }
@@ -0,0 +1,225 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package chaindatafetcher
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain"
+	cfTypes "github.com/klaytn/klaytn/datasync/chaindatafetcher/types"
+)
+
+const (
+	// deadLetterFile is the file in the data directory keeping the dead letters.
+	deadLetterFile = "chaindatafetcher_deadletter.json"
+
+	// maxDeadLetters is the maximum number of the dead letters. If it is full,
+	// the failed records pause the fetching as if the dead-letter queue is disabled.
+	maxDeadLetters = 10000
+)
+
+var errDeadLetterNotFound = errors.New("the dead letter is not found")
+
+// DeadLetter is a record of a block which failed to be exported, so that the
+// fetching is continued without it. It can be reprocessed later by the API.
+type DeadLetter struct {
+	ID          uint64              `json:"id"`
+	BlockNumber uint64              `json:"blockNumber"`
+	ReqType     cfTypes.RequestType `json:"reqType"`
+	Error       string              `json:"error"`
+	Poison      bool                `json:"poison"` // whether the record can never be exported as it is
+	Attempts    int                 `json:"attempts"`
+	CreatedAt   time.Time           `json:"createdAt"`
+	UpdatedAt   time.Time           `json:"updatedAt"`
+}
+
+// deadLetterQueue keeps the dead letters in memory and in the file.
+type deadLetterQueue struct {
+	mu      sync.Mutex
+	nextID  uint64
+	letters []*DeadLetter
+	file    string
+}
+
+func newDeadLetterQueue(file string) *deadLetterQueue {
+	q := &deadLetterQueue{nextID: 1, file: file}
+	if file == "" {
+		return q
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Error("failed to read the dead letters", "file", file, "err", err)
+		}
+		return q
+	}
+	if err := json.Unmarshal(data, &q.letters); err != nil {
+		logger.Error("failed to unmarshal the dead letters", "file", file, "err", err)
+		return q
+	}
+	for _, l := range q.letters {
+		if l.ID >= q.nextID {
+			q.nextID = l.ID + 1
+		}
+	}
+	deadLettersGauge.Update(int64(len(q.letters)))
+	return q
+}
+
+// add records a failed block, and returns false if the queue is full.
+func (q *deadLetterQueue) add(blockNumber uint64, reqType cfTypes.RequestType, err error) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.letters) >= maxDeadLetters {
+		return false
+	}
+	now := time.Now()
+	q.letters = append(q.letters, &DeadLetter{
+		ID:          q.nextID,
+		BlockNumber: blockNumber,
+		ReqType:     reqType,
+		Error:       err.Error(),
+		Poison:      errors.Is(err, cfTypes.ErrPoisonRecord),
+		Attempts:    1,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	q.nextID++
+	q.saveLocked()
+	return true
+}
+
+func (q *deadLetterQueue) find(id uint64) int {
+	for i, l := range q.letters {
+		if l.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (q *deadLetterQueue) get(id uint64) (DeadLetter, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := q.find(id)
+	if i < 0 {
+		return DeadLetter{}, errDeadLetterNotFound
+	}
+	return *q.letters[i], nil
+}
+
+// fail records another failure of the dead letter.
+func (q *deadLetterQueue) fail(id uint64, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if i := q.find(id); i >= 0 {
+		l := q.letters[i]
+		l.Error, l.Poison = err.Error(), errors.Is(err, cfTypes.ErrPoisonRecord)
+		l.Attempts++
+		l.UpdatedAt = time.Now()
+		q.saveLocked()
+	}
+}
+
+func (q *deadLetterQueue) remove(id uint64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := q.find(id)
+	if i < 0 {
+		return errDeadLetterNotFound
+	}
+	q.letters = append(q.letters[:i], q.letters[i+1:]...)
+	q.saveLocked()
+	return nil
+}
+
+func (q *deadLetterQueue) list() []DeadLetter {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	letters := make([]DeadLetter, len(q.letters))
+	for i, l := range q.letters {
+		letters[i] = *l
+	}
+	return letters
+}
+
+func (q *deadLetterQueue) saveLocked() {
+	deadLettersGauge.Update(int64(len(q.letters)))
+	if q.file == "" {
+		return
+	}
+	data, err := json.Marshal(q.letters)
+	if err != nil {
+		logger.Error("failed to marshal the dead letters", "err", err)
+		return
+	}
+	tmp := q.file + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0o600); err != nil {
+		logger.Error("failed to write the dead letters", "file", tmp, "err", err)
+		return
+	}
+	if err := os.Rename(tmp, q.file); err != nil {
+		logger.Error("failed to write the dead letters", "file", q.file, "err", err)
+	}
+}
+
+// deadLetter records the block failed to be exported if the dead-letter queue
+// is enabled, and returns true if the fetching can continue without it.
+func (f *ChainDataFetcher) deadLetter(ev blockchain.ChainEvent, reqType cfTypes.RequestType, err error) bool {
+	if !f.config.DeadLetter {
+		return false
+	}
+	if err != errMaxRetryExceeded && !errors.Is(err, cfTypes.ErrPoisonRecord) {
+		return false
+	}
+	blockNumber := ev.Block.NumberU64()
+	if !f.deadLetters.add(blockNumber, reqType, err) {
+		logger.Error("the dead-letter queue is full", "blockNumber", blockNumber, "reqType", reqType, "err", err)
+		return false
+	}
+	deadLetteredCounter.Inc(1)
+	logger.Warn("the block is moved to the dead-letter queue", "blockNumber", blockNumber, "reqType", reqType, "err", err)
+	return true
+}
+
+// reprocessDeadLetter exports the block of the dead letter again, and removes
+// the dead letter if it succeeds.
+func (f *ChainDataFetcher) reprocessDeadLetter(id uint64) error {
+	l, err := f.deadLetters.get(id)
+	if err != nil {
+		return err
+	}
+	ev, err := f.makeChainEvent(l.BlockNumber)
+	if err != nil {
+		return err
+	}
+	if err := f.updateInsertionTimeGauge(f.retryFunc(f.repo.HandleChainEvent))(ev, l.ReqType); err != nil {
+		f.deadLetters.fail(id, err)
+		return err
+	}
+	return f.deadLetters.remove(id)
+}
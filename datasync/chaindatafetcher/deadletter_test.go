@@ -0,0 +1,119 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package chaindatafetcher
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/datasync/chaindatafetcher/mocks"
+	cfTypes "github.com/klaytn/klaytn/datasync/chaindatafetcher/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadLetterQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chaindatafetcher")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	q := newDeadLetterQueue(filepath.Join(dir, deadLetterFile))
+	poison := fmt.Errorf("%w: test-error", cfTypes.ErrPoisonRecord)
+	assert.True(t, q.add(10, cfTypes.RequestTypeBlockGroup, errMaxRetryExceeded))
+	assert.True(t, q.add(11, cfTypes.RequestTypeTraceGroup, poison))
+
+	l, err := q.get(2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(11), l.BlockNumber)
+	assert.Equal(t, cfTypes.RequestTypeTraceGroup, l.ReqType)
+	assert.True(t, l.Poison)
+
+	q.fail(1, errors.New("test-error"))
+	l, err = q.get(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, l.Attempts)
+	assert.False(t, l.Poison)
+
+	// The dead letters are loaded from the file after the node is restarted.
+	assert.NoError(t, q.remove(1))
+	assert.Equal(t, errDeadLetterNotFound, q.remove(1))
+	loaded := newDeadLetterQueue(q.file)
+	letters := loaded.list()
+	assert.Equal(t, 1, len(letters))
+	assert.Equal(t, uint64(2), letters[0].ID)
+	assert.True(t, loaded.add(12, cfTypes.RequestTypeBlockGroup, errMaxRetryExceeded))
+	assert.Equal(t, uint64(3), loaded.list()[1].ID)
+
+	_, err = newDeadLetterQueue("").get(1)
+	assert.Equal(t, errDeadLetterNotFound, err)
+}
+
+func TestChainDataFetcher_DeadLetter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	bc := mocks.NewMockBlockChain(ctrl)
+	repo, checkpointDB := mocks.NewMockRepository(ctrl), mocks.NewMockCheckpointDB(ctrl)
+	fetcher := newTestChainDataFetcher()
+	fetcher.config = &ChainDataFetcherConfig{Mode: ModeKafka}
+	fetcher.blockchain, fetcher.repo, fetcher.checkpointDB = bc, repo, checkpointDB
+	fetcher.deadLetters = newDeadLetterQueue("")
+	defer close(fetcher.stopCh)
+
+	ev := blockchain.ChainEvent{Block: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0)})}
+	poison := fmt.Errorf("%w: test-error", cfTypes.ErrPoisonRecord)
+
+	// The poison record is not retried, and pauses the fetching if the dead-letter queue is disabled.
+	repo.EXPECT().HandleChainEvent(gomock.Any(), cfTypes.RequestTypeBlockGroup).Return(poison).Times(1)
+	assert.True(t, errors.Is(fetcher.handleRequestByType(cfTypes.RequestTypeBlockGroup, true, ev), cfTypes.ErrPoisonRecord))
+	assert.Equal(t, int64(0), fetcher.checkpoint)
+	assert.Equal(t, 0, len(fetcher.deadLetters.list()))
+
+	// The fetching continues without the block if the dead-letter queue is enabled.
+	fetcher.config.DeadLetter = true
+	repo.EXPECT().HandleChainEvent(gomock.Any(), cfTypes.RequestTypeBlockGroup).Return(poison).Times(1)
+	checkpointDB.EXPECT().WriteCheckpoint(int64(1)).Return(nil).Times(1)
+	assert.NoError(t, fetcher.handleRequestByType(cfTypes.RequestTypeBlockGroup, true, ev))
+	assert.Equal(t, int64(1), fetcher.checkpoint)
+	letters := fetcher.deadLetters.list()
+	assert.Equal(t, 1, len(letters))
+	assert.True(t, letters[0].Poison)
+
+	// The other errors are not dead-lettered.
+	assert.False(t, fetcher.deadLetter(ev, cfTypes.RequestTypeBlockGroup, errors.New("test-error")))
+
+	// The dead letter is removed after it is reprocessed successfully.
+	bc.EXPECT().GetBlockByNumber(uint64(0)).Return(ev.Block).Times(2)
+	bc.EXPECT().GetReceiptsByBlockHash(gomock.Any()).Return(types.Receipts{}).Times(2)
+	repo.EXPECT().HandleChainEvent(gomock.Any(), cfTypes.RequestTypeBlockGroup).Return(poison).Times(1)
+	assert.Error(t, fetcher.reprocessDeadLetter(letters[0].ID))
+	l, err := fetcher.deadLetters.get(letters[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, l.Attempts)
+
+	repo.EXPECT().HandleChainEvent(gomock.Any(), cfTypes.RequestTypeBlockGroup).Return(nil).Times(1)
+	assert.NoError(t, fetcher.reprocessDeadLetter(letters[0].ID))
+	assert.Equal(t, 0, len(fetcher.deadLetters.list()))
+	assert.Equal(t, errDeadLetterNotFound, fetcher.reprocessDeadLetter(letters[0].ID))
+}
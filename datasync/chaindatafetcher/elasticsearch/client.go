@@ -0,0 +1,158 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package elasticsearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/klaytn/klaytn/datasync/chaindatafetcher/types"
+)
+
+// client is a minimal client of the REST API shared by Elasticsearch and OpenSearch.
+type client struct {
+	urls     []string
+	username string
+	password string
+	http     *http.Client
+}
+
+func newClient(config *ElasticsearchConfig) *client {
+	urls := make([]string, len(config.URLs))
+	for i, url := range config.URLs {
+		urls[i] = strings.TrimRight(url, "/")
+	}
+	return &client{
+		urls:     urls,
+		username: config.Username,
+		password: config.Password,
+		http:     &http.Client{Timeout: config.RequestTimeout},
+	}
+}
+
+// do sends the request to the urls in order until one of them responds, and
+// returns the status code and the body of the response.
+func (c *client) do(method, path, contentType string, body []byte) (int, []byte, error) {
+	var lastErr error
+	for _, url := range c.urls {
+		req, err := http.NewRequest(method, url+path, bytes.NewReader(body))
+		if err != nil {
+			return 0, nil, err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if c.username != "" {
+			req.SetBasicAuth(c.username, c.password)
+		}
+		resp, err := c.http.Do(req)
+		if err != nil {
+			logger.Warn("Failed to send the elasticsearch request", "url", url, "path", path, "err", err)
+			lastErr = err
+			continue
+		}
+		respBody, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return resp.StatusCode, respBody, nil
+	}
+	return 0, nil, lastErr
+}
+
+// doJSON sends the JSON request and decodes the JSON response into the result if given.
+// The status codes other than 2xx are returned as errors.
+func (c *client) doJSON(method, path string, body interface{}, result interface{}) (int, error) {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return 0, err
+		}
+	}
+	status, respBody, err := c.do(method, path, "application/json", data)
+	if err != nil {
+		return status, err
+	}
+	if status < 200 || status >= 300 {
+		return status, fmt.Errorf("elasticsearch responded %d to %s %s: %s", status, method, path, respBody)
+	}
+	if result != nil {
+		return status, json.Unmarshal(respBody, result)
+	}
+	return status, nil
+}
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		ID     string          `json:"_id"`
+		Status int             `json:"status"`
+		Error  json.RawMessage `json:"error"`
+	} `json:"items"`
+}
+
+// bulk indexes the documents by a bulk request. The documents with the same
+// ids are replaced, so the blocks are indexed again without duplicates.
+func (c *client) bulk(docs []*document) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, doc := range docs {
+		action := map[string]interface{}{"index": map[string]string{"_index": doc.index, "_id": doc.id}}
+		if err := enc.Encode(action); err != nil {
+			return fmt.Errorf("%w: %v", types.ErrPoisonRecord, err)
+		}
+		if err := enc.Encode(doc.source); err != nil {
+			return fmt.Errorf("%w: %v", types.ErrPoisonRecord, err)
+		}
+	}
+	status, respBody, err := c.do(http.MethodPost, "/_bulk", "application/x-ndjson", buf.Bytes())
+	if err != nil {
+		return err
+	}
+	if status == http.StatusRequestEntityTooLarge {
+		return fmt.Errorf("%w: the bulk request is too large: %s", types.ErrPoisonRecord, respBody)
+	}
+	if status < 200 || status >= 300 {
+		return fmt.Errorf("elasticsearch responded %d to the bulk request: %s", status, respBody)
+	}
+	var resp bulkResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return err
+	}
+	if !resp.Errors {
+		return nil
+	}
+	for _, item := range resp.Items {
+		for _, result := range item {
+			if result.Status == http.StatusBadRequest {
+				// the document is rejected by the mappings
+				return fmt.Errorf("%w: failed to index the document %v: %s", types.ErrPoisonRecord, result.ID, result.Error)
+			}
+			if result.Status < 200 || result.Status >= 300 {
+				return fmt.Errorf("failed to index the document %v (status: %d): %s", result.ID, result.Status, result.Error)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package elasticsearch
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultURL             = "http://localhost:9200"
+	DefaultIndexPrefix     = "klaytn"
+	DefaultIndexDateLayout = "2006.01"
+	DefaultBulkSize        = 1000
+	DefaultRequestTimeout  = 30 * time.Second
+)
+
+const (
+	IndexBlocks       = "blocks"
+	IndexTransactions = "transactions"
+	IndexLogs         = "logs"
+
+	checkpointIndex = "checkpoint"
+)
+
+// AllIndices are the indices which can be loaded by the repository.
+var AllIndices = []string{IndexBlocks, IndexTransactions, IndexLogs}
+
+var (
+	errNoURLs    = errors.New("no elasticsearch url is given")
+	errNoIndices = errors.New("no index is enabled")
+)
+
+type ElasticsearchConfig struct {
+	URLs     []string // URLs are the endpoints of the cluster, tried in order until one responds.
+	Username string
+	Password string `json:"-"`
+
+	// IndexPrefix is prepended to the index names, which are "<prefix>-<index>-<date>".
+	IndexPrefix string
+	// IndexDateLayout is the Go time layout of the date suffix, formatted by the block time.
+	// The indices are not suffixed if it is empty.
+	IndexDateLayout string
+	// TemplateFile is the JSON file of the index templates keyed by the index, which
+	// replace the default templates of the given indices.
+	TemplateFile string
+	// ILMPolicy is the index lifecycle policy set to the indices by the default templates.
+	ILMPolicy string
+
+	Indices  []string // Indices are the indices loaded, among blocks, transactions and logs.
+	ABIFiles []string // ABIFiles are the JSON ABI files of the events decoded from the logs.
+	BulkSize int      // BulkSize is the maximum number of documents indexed by a bulk request.
+
+	RequestTimeout time.Duration
+}
+
+func GetDefaultElasticsearchConfig() *ElasticsearchConfig {
+	return &ElasticsearchConfig{
+		URLs:            []string{DefaultURL},
+		IndexPrefix:     DefaultIndexPrefix,
+		IndexDateLayout: DefaultIndexDateLayout,
+		Indices:         AllIndices,
+		BulkSize:        DefaultBulkSize,
+		RequestTimeout:  DefaultRequestTimeout,
+	}
+}
+
+// Validate checks that the urls are given and the indices are known.
+func (c *ElasticsearchConfig) Validate() error {
+	if len(c.URLs) == 0 {
+		return errNoURLs
+	}
+	if len(c.Indices) == 0 {
+		return errNoIndices
+	}
+	for _, index := range c.Indices {
+		if !isKnownIndex(index) {
+			return fmt.Errorf("unknown index %q (%s)", index, strings.Join(AllIndices, ", "))
+		}
+	}
+	return nil
+}
+
+func (c *ElasticsearchConfig) enabled(index string) bool {
+	for _, i := range c.Indices {
+		if i == index {
+			return true
+		}
+	}
+	return false
+}
+
+// indexName returns the name of the index of the documents made at the given time.
+// The date suffix lets the indices be rolled over and deleted by the lifecycle policies.
+func (c *ElasticsearchConfig) indexName(index string, t time.Time) string {
+	name := c.IndexPrefix + "-" + index
+	if c.IndexDateLayout != "" {
+		name += "-" + t.UTC().Format(c.IndexDateLayout)
+	}
+	return name
+}
+
+// indexPattern returns the pattern matching all the names of the index.
+func (c *ElasticsearchConfig) indexPattern(index string) string {
+	if c.IndexDateLayout == "" {
+		return c.IndexPrefix + "-" + index
+	}
+	return c.IndexPrefix + "-" + index + "-*"
+}
+
+func (c *ElasticsearchConfig) String() string {
+	return fmt.Sprintf("urls: %v, user: %v, indexPrefix: %v, indexDateLayout: %v, templateFile: %v, ilmPolicy: %v, indices: %v, abiFiles: %v, bulkSize: %v",
+		c.URLs, c.Username, c.IndexPrefix, c.IndexDateLayout, c.TemplateFile, c.ILMPolicy, c.Indices, c.ABIFiles, c.BulkSize)
+}
+
+func isKnownIndex(index string) bool {
+	for _, i := range AllIndices {
+		if i == index {
+			return true
+		}
+	}
+	return false
+}
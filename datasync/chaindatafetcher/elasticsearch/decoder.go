@@ -0,0 +1,133 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package elasticsearch
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+
+	"github.com/klaytn/klaytn/accounts/abi"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+)
+
+// decodedEvent is the event decoded from a log by the given ABIs.
+type decodedEvent struct {
+	Name      string                 `json:"name"`
+	Signature string                 `json:"signature"`
+	Args      map[string]interface{} `json:"args"`
+}
+
+// eventDecoder decodes the logs of the events whose ABIs are given, looked up by the first topic.
+type eventDecoder struct {
+	events map[common.Hash]abi.Event
+}
+
+func newEventDecoder(abiFiles []string) (*eventDecoder, error) {
+	d := &eventDecoder{events: make(map[common.Hash]abi.Event)}
+	for _, path := range abiFiles {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := abi.JSON(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the abi file %v: %v", path, err)
+		}
+		for _, event := range parsed.Events {
+			if !event.Anonymous {
+				d.events[event.ID] = event
+			}
+		}
+	}
+	return d, nil
+}
+
+// decode returns the decoded event of the log, or nil if the event is unknown or
+// the log does not match the ABI of the event.
+func (d *eventDecoder) decode(log *types.Log) *decodedEvent {
+	if len(log.Topics) == 0 {
+		return nil
+	}
+	event, ok := d.events[log.Topics[0]]
+	if !ok {
+		return nil
+	}
+	var indexed abi.Arguments
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	args := make(map[string]interface{})
+	if nonIndexed := event.Inputs.NonIndexed(); len(nonIndexed) > 0 {
+		if err := nonIndexed.UnpackIntoMap(args, log.Data); err != nil {
+			logger.Trace("Failed to decode the log data", "event", event.Sig, "tx", log.TxHash, "index", log.Index, "err", err)
+			return nil
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(args, indexed, log.Topics[1:]); err != nil {
+		logger.Trace("Failed to decode the log topics", "event", event.Sig, "tx", log.TxHash, "index", log.Index, "err", err)
+		return nil
+	}
+	for name, value := range args {
+		args[name] = jsonValue(value)
+	}
+	return &decodedEvent{Name: event.RawName, Signature: event.Sig, Args: args}
+}
+
+// jsonValue converts the decoded value to be indexed without the loss of precision.
+// The integers are indexed as decimal strings since they overflow the long fields.
+func jsonValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case *big.Int:
+		return value.String()
+	case common.Address:
+		return value.Hex()
+	case common.Hash:
+		return value.Hex()
+	case []byte:
+		return hexutil.Encode(value)
+	case bool, string:
+		return value
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()).String()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(rv.Uint()).String()
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return hexutil.Encode(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		values := make([]interface{}, rv.Len())
+		for i := range values {
+			values[i] = jsonValue(rv.Index(i).Interface())
+		}
+		return values
+	}
+	return v
+}
@@ -0,0 +1,28 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+/*
+Package elasticsearch implements the repository of chaindatafetcher indexing blocks, transactions and decoded logs into Elasticsearch or OpenSearch
+Source Files
+  - client.go     : implements the client of the REST API and the bulk requests
+  - config.go     : includes elasticsearch configurations and the index naming
+  - decoder.go    : decodes the logs by the given event ABIs
+  - documents.go  : makes the documents of a chain event
+  - repository.go : implements the repository and the checkpoint database
+  - templates.go  : includes the default index templates and loads the custom ones
+*/
+
+package elasticsearch
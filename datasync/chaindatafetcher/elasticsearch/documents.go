@@ -0,0 +1,135 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package elasticsearch
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+)
+
+// document is a document indexed by the bulk request.
+type document struct {
+	index  string
+	id     string
+	source map[string]interface{}
+}
+
+// address returns the lowercase hex of the address, or nil to be indexed as null.
+func address(addr *common.Address) interface{} {
+	if addr == nil {
+		return nil
+	}
+	return strings.ToLower(addr.Hex())
+}
+
+func blockTime(block *types.Block) time.Time {
+	return time.Unix(block.Time().Int64(), 0)
+}
+
+func blockDocuments(c *ElasticsearchConfig, event blockchain.ChainEvent) []*document {
+	block := event.Block
+	header := block.Header()
+	return []*document{{
+		index: c.indexName(IndexBlocks, blockTime(block)),
+		id:    strconv.FormatUint(block.NumberU64(), 10),
+		source: map[string]interface{}{
+			"number":      block.NumberU64(),
+			"hash":        block.Hash().Hex(),
+			"parent_hash": header.ParentHash.Hex(),
+			"timestamp":   header.Time.Int64(),
+			"rewardbase":  address(&header.Rewardbase),
+			"gas_used":    header.GasUsed,
+			"size":        uint64(block.Size()),
+			"tx_count":    len(block.Transactions()),
+		},
+	}}
+}
+
+func transactionDocuments(c *ElasticsearchConfig, event blockchain.ChainEvent) []*document {
+	block := event.Block
+	index := c.indexName(IndexTransactions, blockTime(block))
+	docs := make([]*document, 0, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		var from common.Address
+		if tx.IsEthereumTransaction() {
+			signer := types.LatestSignerForChainID(tx.ChainId())
+			from, _ = types.Sender(signer, tx)
+		} else {
+			from, _ = tx.From()
+		}
+		source := map[string]interface{}{
+			"hash":         tx.Hash().Hex(),
+			"block_number": block.NumberU64(),
+			"block_hash":   block.Hash().Hex(),
+			"timestamp":    block.Time().Int64(),
+			"tx_index":     i,
+			"type":         tx.Type().String(),
+			"type_int":     int(tx.Type()),
+			"from":         address(&from),
+			"to":           address(tx.To()),
+			"value":        tx.Value().String(),
+			"gas":          tx.Gas(),
+			"gas_price":    tx.GasPrice().String(),
+			"input":        hexutil.Encode(tx.Data()),
+		}
+		if i < len(event.Receipts) {
+			receipt := event.Receipts[i]
+			source["gas_used"] = receipt.GasUsed
+			source["status"] = receipt.Status
+			if tx.To() == nil {
+				source["contract_address"] = address(&receipt.ContractAddress)
+			}
+		}
+		docs = append(docs, &document{index: index, id: tx.Hash().Hex(), source: source})
+	}
+	return docs
+}
+
+func logDocuments(c *ElasticsearchConfig, decoder *eventDecoder, event blockchain.ChainEvent) []*document {
+	block := event.Block
+	index := c.indexName(IndexLogs, blockTime(block))
+	docs := make([]*document, 0, len(event.Logs))
+	for _, log := range event.Logs {
+		topics := make([]string, len(log.Topics))
+		for i, topic := range log.Topics {
+			topics[i] = topic.Hex()
+		}
+		source := map[string]interface{}{
+			"block_number": log.BlockNumber,
+			"block_hash":   log.BlockHash.Hex(),
+			"timestamp":    block.Time().Int64(),
+			"log_index":    log.Index,
+			"tx_hash":      log.TxHash.Hex(),
+			"tx_index":     log.TxIndex,
+			"address":      address(&log.Address),
+			"topics":       topics,
+			"data":         hexutil.Encode(log.Data),
+		}
+		if decoded := decoder.decode(log); decoded != nil {
+			source["event"] = decoded
+		}
+		id := strconv.FormatUint(log.BlockNumber, 10) + "-" + strconv.FormatUint(uint64(log.Index), 10)
+		docs = append(docs, &document{index: index, id: id, source: source})
+	}
+	return docs
+}
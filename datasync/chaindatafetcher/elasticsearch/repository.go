@@ -0,0 +1,144 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package elasticsearch
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/datasync/chaindatafetcher/types"
+	"github.com/klaytn/klaytn/log"
+)
+
+const (
+	maxConnectRetryCount = 20
+	connectRetryInterval = 1 * time.Second
+
+	checkpointDocID = "checkpoint"
+)
+
+var logger = log.NewModuleLogger(log.ChainDataFetcher)
+
+type repository struct {
+	config  *ElasticsearchConfig
+	client  *client
+	decoder *eventDecoder
+}
+
+func NewRepository(config *ElasticsearchConfig) (*repository, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	decoder, err := newEventDecoder(config.ABIFiles)
+	if err != nil {
+		return nil, err
+	}
+	r := &repository{config: config, client: newClient(config), decoder: decoder}
+
+	for i := 0; i < maxConnectRetryCount; i++ {
+		if _, err = r.client.doJSON(http.MethodGet, "/", nil, nil); err == nil {
+			break
+		}
+		logger.Warn("Retrying to connect elasticsearch", "urls", config.URLs, "err", err)
+		time.Sleep(connectRetryInterval)
+	}
+	if err != nil {
+		logger.Error("Failed to connect to elasticsearch", "urls", config.URLs, "err", err)
+		return nil, err
+	}
+	if err := r.putTemplates(); err != nil {
+		logger.Error("Failed to put the index templates", "err", err)
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *repository) HandleChainEvent(event blockchain.ChainEvent, reqType types.RequestType) error {
+	switch reqType {
+	case types.RequestTypeBlockGroup:
+		return r.indexDocuments(event)
+	case types.RequestTypeTraceGroup, types.RequestTypeInternalTransferGroup:
+		// the traces are not indexed into elasticsearch.
+		return nil
+	default:
+		return fmt.Errorf("not supported type. [blockNumber: %v, reqType: %v]", event.Block.NumberU64(), reqType)
+	}
+}
+
+// indexDocuments indexes the documents of the enabled indices by the bulk requests.
+func (r *repository) indexDocuments(event blockchain.ChainEvent) error {
+	var docs []*document
+	if r.config.enabled(IndexBlocks) {
+		docs = append(docs, blockDocuments(r.config, event)...)
+	}
+	if r.config.enabled(IndexTransactions) {
+		docs = append(docs, transactionDocuments(r.config, event)...)
+	}
+	if r.config.enabled(IndexLogs) {
+		docs = append(docs, logDocuments(r.config, r.decoder, event)...)
+	}
+	for _, batch := range batchDocuments(docs, r.config.BulkSize) {
+		if err := r.client.bulk(batch); err != nil {
+			logger.Error("Failed to index the documents", "blockNumber", event.Block.NumberU64(), "numDocs", len(batch), "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func batchDocuments(docs []*document, size int) [][]*document {
+	if size <= 0 {
+		size = DefaultBulkSize
+	}
+	var batches [][]*document
+	for len(docs) > size {
+		batches = append(batches, docs[:size])
+		docs = docs[size:]
+	}
+	if len(docs) > 0 {
+		batches = append(batches, docs)
+	}
+	return batches
+}
+
+func (r *repository) checkpointPath() string {
+	return "/" + r.config.IndexPrefix + "-" + checkpointIndex + "/_doc/" + checkpointDocID
+}
+
+func (r *repository) ReadCheckpoint() (int64, error) {
+	var result struct {
+		Found  bool `json:"found"`
+		Source struct {
+			Checkpoint int64 `json:"checkpoint"`
+		} `json:"_source"`
+	}
+	status, err := r.client.doJSON(http.MethodGet, r.checkpointPath(), nil, &result)
+	if status == http.StatusNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return result.Source.Checkpoint, nil
+}
+
+func (r *repository) WriteCheckpoint(checkpoint int64) error {
+	_, err := r.client.doJSON(http.MethodPut, r.checkpointPath(), map[string]int64{"checkpoint": checkpoint}, nil)
+	return err
+}
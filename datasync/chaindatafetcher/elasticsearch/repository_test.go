@@ -0,0 +1,197 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package elasticsearch
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	cfTypes "github.com/klaytn/klaytn/datasync/chaindatafetcher/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const transferABI = `[{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"value","type":"uint256"}],"name":"Transfer","type":"event"}]`
+
+func TestElasticsearchConfig_IndexName(t *testing.T) {
+	c := GetDefaultElasticsearchConfig()
+	blockTime := time.Date(2022, 5, 17, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "klaytn-logs-2022.05", c.indexName(IndexLogs, blockTime))
+	assert.Equal(t, "klaytn-logs-*", c.indexPattern(IndexLogs))
+
+	c.IndexDateLayout = ""
+	assert.Equal(t, "klaytn-logs", c.indexName(IndexLogs, blockTime))
+	assert.Equal(t, "klaytn-logs", c.indexPattern(IndexLogs))
+}
+
+func TestElasticsearchConfig_Validate(t *testing.T) {
+	c := GetDefaultElasticsearchConfig()
+	assert.NoError(t, c.Validate())
+
+	c.Indices = []string{IndexBlocks, "traces"}
+	assert.Error(t, c.Validate())
+
+	c.Indices = nil
+	assert.Equal(t, errNoIndices, c.Validate())
+
+	c.URLs = nil
+	assert.Equal(t, errNoURLs, c.Validate())
+}
+
+func TestElasticsearchConfig_LoadTemplates(t *testing.T) {
+	c := GetDefaultElasticsearchConfig()
+	c.ILMPolicy = "chaindata"
+
+	templates, err := c.loadTemplates()
+	assert.NoError(t, err)
+	assert.Len(t, templates, len(AllIndices))
+	assert.Equal(t, []string{"klaytn-blocks-*"}, templates[IndexBlocks]["index_patterns"])
+	settings := templates[IndexBlocks]["template"].(map[string]interface{})["settings"].(map[string]interface{})
+	assert.Equal(t, "chaindata", settings["index.lifecycle.name"])
+
+	// the custom template replaces the default one, with the index pattern set.
+	dir, err := ioutil.TempDir("", "elasticsearch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	c.TemplateFile = filepath.Join(dir, "templates.json")
+	assert.NoError(t, ioutil.WriteFile(c.TemplateFile, []byte(`{"logs": {"priority": 100}}`), 0o600))
+
+	templates, err = c.loadTemplates()
+	assert.NoError(t, err)
+	assert.Equal(t, float64(100), templates[IndexLogs]["priority"])
+	assert.Equal(t, []string{"klaytn-logs-*"}, templates[IndexLogs]["index_patterns"])
+	assert.NotNil(t, templates[IndexBlocks]["template"])
+
+	assert.NoError(t, ioutil.WriteFile(c.TemplateFile, []byte(`{"traces": {}}`), 0o600))
+	_, err = c.loadTemplates()
+	assert.Error(t, err)
+}
+
+func TestEventDecoder_Decode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "elasticsearch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	abiFile := filepath.Join(dir, "erc20.json")
+	assert.NoError(t, ioutil.WriteFile(abiFile, []byte(transferABI), 0o600))
+
+	decoder, err := newEventDecoder([]string{abiFile})
+	assert.NoError(t, err)
+
+	from, to := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	log := &types.Log{
+		Topics: []common.Hash{
+			crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")),
+			common.BytesToHash(from.Bytes()),
+			common.BytesToHash(to.Bytes()),
+		},
+		Data: common.LeftPadBytes(big.NewInt(1000).Bytes(), 32),
+	}
+	decoded := decoder.decode(log)
+	if assert.NotNil(t, decoded) {
+		assert.Equal(t, "Transfer", decoded.Name)
+		assert.Equal(t, "Transfer(address,address,uint256)", decoded.Signature)
+		assert.Equal(t, from.Hex(), decoded.Args["from"])
+		assert.Equal(t, to.Hex(), decoded.Args["to"])
+		assert.Equal(t, "1000", decoded.Args["value"])
+	}
+
+	// the unknown events and the malformed logs are not decoded.
+	assert.Nil(t, decoder.decode(&types.Log{Topics: []common.Hash{{0x1}}}))
+	log.Data = nil
+	assert.Nil(t, decoder.decode(log))
+}
+
+func TestBatchDocuments(t *testing.T) {
+	docs := make([]*document, 5)
+	assert.Len(t, batchDocuments(docs, 2), 3)
+	assert.Len(t, batchDocuments(docs, 10), 1)
+	assert.Len(t, batchDocuments(nil, 10), 0)
+}
+
+func TestRepository_HandleChainEvent(t *testing.T) {
+	var actions []map[string]map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_bulk" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		scanner := bufio.NewScanner(r.Body)
+		for i := 0; scanner.Scan(); i++ {
+			if i%2 == 0 {
+				var action map[string]map[string]string
+				assert.NoError(t, json.Unmarshal(scanner.Bytes(), &action))
+				actions = append(actions, action)
+			}
+		}
+		w.Write([]byte(`{"errors":false,"items":[]}`))
+	}))
+	defer server.Close()
+
+	c := GetDefaultElasticsearchConfig()
+	c.URLs = []string{server.URL}
+	c.Indices = []string{IndexBlocks, IndexLogs}
+	r := &repository{config: c, client: newClient(c), decoder: &eventDecoder{}}
+
+	header := &types.Header{Number: big.NewInt(10), Time: big.NewInt(time.Date(2022, 5, 17, 0, 0, 0, 0, time.UTC).Unix())}
+	event := blockchain.ChainEvent{
+		Block: types.NewBlockWithHeader(header),
+		Logs:  []*types.Log{{BlockNumber: 10, Index: 0}, {BlockNumber: 10, Index: 1}},
+	}
+	assert.NoError(t, r.HandleChainEvent(event, cfTypes.RequestTypeBlockGroup))
+
+	var ids []string
+	for _, action := range actions {
+		assert.True(t, strings.HasSuffix(action["index"]["_index"], "-2022.05"))
+		ids = append(ids, action["index"]["_id"])
+	}
+	assert.Equal(t, []string{"10", "10-0", "10-1"}, ids)
+
+	// the checkpoint is zero if it is not written yet.
+	checkpoint, err := r.ReadCheckpoint()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), checkpoint)
+}
+
+func TestClient_BulkErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"errors":true,"items":[{"index":{"_id":"1","status":201}},{"index":{"_id":"2","status":400,"error":{"type":"mapper_parsing_exception"}}}]}`))
+	}))
+	defer server.Close()
+
+	// the unreachable url is skipped for the next one.
+	c := GetDefaultElasticsearchConfig()
+	c.URLs = []string{"http://127.0.0.1:1", server.URL}
+	err := newClient(c).bulk([]*document{{index: "i", id: "1"}, {index: "i", id: "2"}})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "mapper_parsing_exception")
+		assert.True(t, errors.Is(err, cfTypes.ErrPoisonRecord)) // the rejected document is never retried
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+var (
+	keywordField = map[string]interface{}{"type": "keyword"}
+	longField    = map[string]interface{}{"type": "long"}
+	timeField    = map[string]interface{}{"type": "date", "format": "epoch_second"}
+)
+
+// defaultMappings are the mappings of the default index templates. The big
+// numbers such as the values are mapped to keywords to keep the precision.
+var defaultMappings = map[string]map[string]interface{}{
+	IndexBlocks: {
+		"number":      longField,
+		"hash":        keywordField,
+		"parent_hash": keywordField,
+		"timestamp":   timeField,
+		"rewardbase":  keywordField,
+		"gas_used":    longField,
+		"size":        longField,
+		"tx_count":    longField,
+	},
+	IndexTransactions: {
+		"hash":             keywordField,
+		"block_number":     longField,
+		"block_hash":       keywordField,
+		"timestamp":        timeField,
+		"tx_index":         longField,
+		"type":             keywordField,
+		"type_int":         longField,
+		"from":             keywordField,
+		"to":               keywordField,
+		"value":            keywordField,
+		"gas":              longField,
+		"gas_price":        keywordField,
+		"gas_used":         longField,
+		"status":           longField,
+		"contract_address": keywordField,
+		"input":            map[string]interface{}{"type": "keyword", "index": false, "doc_values": false},
+	},
+	IndexLogs: {
+		"block_number": longField,
+		"block_hash":   keywordField,
+		"timestamp":    timeField,
+		"log_index":    longField,
+		"tx_hash":      keywordField,
+		"tx_index":     longField,
+		"address":      keywordField,
+		"topics":       keywordField,
+		"data":         map[string]interface{}{"type": "keyword", "index": false, "doc_values": false},
+		"event": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"name":      keywordField,
+				"signature": keywordField,
+				"args":      map[string]interface{}{"type": "object", "dynamic": true},
+			},
+		},
+	},
+}
+
+// argsDynamicTemplates map the arguments of the decoded events to keywords,
+// since the arguments of the same name are of different types among the events.
+var argsDynamicTemplates = []interface{}{
+	map[string]interface{}{
+		"event_args": map[string]interface{}{
+			"path_match": "event.args.*",
+			"mapping":    keywordField,
+		},
+	},
+}
+
+// defaultTemplate returns the index template of the index, which applies the
+// mappings and the lifecycle policy to all the dated indices of the index.
+func (c *ElasticsearchConfig) defaultTemplate(index string) map[string]interface{} {
+	settings := map[string]interface{}{}
+	if c.ILMPolicy != "" {
+		settings["index.lifecycle.name"] = c.ILMPolicy
+	}
+	mappings := map[string]interface{}{"properties": defaultMappings[index]}
+	if index == IndexLogs {
+		mappings["dynamic_templates"] = argsDynamicTemplates
+	}
+	return map[string]interface{}{
+		"index_patterns": []string{c.indexPattern(index)},
+		"template": map[string]interface{}{
+			"settings": settings,
+			"mappings": mappings,
+		},
+	}
+}
+
+// loadTemplates returns the index templates of the enabled indices. The
+// templates in the template file replace the default ones, and their index
+// patterns are set if missing.
+func (c *ElasticsearchConfig) loadTemplates() (map[string]map[string]interface{}, error) {
+	custom := make(map[string]map[string]interface{})
+	if c.TemplateFile != "" {
+		data, err := ioutil.ReadFile(c.TemplateFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &custom); err != nil {
+			return nil, fmt.Errorf("failed to parse the template file %v: %v", c.TemplateFile, err)
+		}
+		for index := range custom {
+			if !isKnownIndex(index) {
+				return nil, fmt.Errorf("unknown index %q in the template file %v", index, c.TemplateFile)
+			}
+		}
+	}
+	templates := make(map[string]map[string]interface{})
+	for _, index := range c.Indices {
+		template, ok := custom[index]
+		if !ok {
+			template = c.defaultTemplate(index)
+		}
+		if _, ok := template["index_patterns"]; !ok {
+			template["index_patterns"] = []string{c.indexPattern(index)}
+		}
+		templates[index] = template
+	}
+	return templates, nil
+}
+
+// putTemplates creates or replaces the index templates before the indices are created.
+func (r *repository) putTemplates() error {
+	templates, err := r.config.loadTemplates()
+	if err != nil {
+		return err
+	}
+	for index, template := range templates {
+		name := r.config.IndexPrefix + "-" + index
+		if _, err := r.client.doJSON(http.MethodPut, "/_index_template/"+name, template, nil); err != nil {
+			return err
+		}
+		logger.Info("Put the elasticsearch index template", "name", name, "pattern", template["index_patterns"])
+	}
+	return nil
+}
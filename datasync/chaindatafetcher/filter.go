@@ -0,0 +1,217 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package chaindatafetcher
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sync"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+)
+
+// FilterRules selects the transactions to be exported. A transaction matches
+// the rules if it matches every non-empty rule:
+//   - Addresses: the sender, the recipient, the created contract, or the
+//     address of a log is one of the addresses
+//   - Topics: a topic of a log is one of the topics
+//   - TxTypes: the type of the transaction is one of the types
+//
+// Only the matched transactions with their receipts, logs and traces are
+// exported, and the blocks without a matched transaction are not exported. The
+// transaction indexes of the exported data are the ones in the filtered block.
+// Empty rules export every block as it is.
+type FilterRules struct {
+	Addresses []common.Address `json:"addresses,omitempty"`
+	Topics    []common.Hash    `json:"topics,omitempty"`
+	TxTypes   []types.TxType   `json:"txTypes,omitempty"`
+}
+
+// LoadFilterRules reads the filter rules from the JSON file.
+func LoadFilterRules(path string) (*FilterRules, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rules := new(FilterRules)
+	if err := json.Unmarshal(data, rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *FilterRules) isEmpty() bool {
+	return r == nil || (len(r.Addresses) == 0 && len(r.Topics) == 0 && len(r.TxTypes) == 0)
+}
+
+// eventFilter filters the chain events by the rules, which can be replaced
+// while the events are filtered.
+type eventFilter struct {
+	mu        sync.RWMutex
+	rules     *FilterRules
+	addresses map[common.Address]struct{}
+	topics    map[common.Hash]struct{}
+	txTypes   map[types.TxType]struct{}
+}
+
+func newEventFilter(rules *FilterRules) *eventFilter {
+	f := &eventFilter{}
+	f.setRules(rules)
+	return f
+}
+
+func (f *eventFilter) setRules(rules *FilterRules) {
+	if rules == nil {
+		rules = &FilterRules{}
+	}
+	addresses := make(map[common.Address]struct{}, len(rules.Addresses))
+	for _, addr := range rules.Addresses {
+		addresses[addr] = struct{}{}
+	}
+	topics := make(map[common.Hash]struct{}, len(rules.Topics))
+	for _, topic := range rules.Topics {
+		topics[topic] = struct{}{}
+	}
+	txTypes := make(map[types.TxType]struct{}, len(rules.TxTypes))
+	for _, txType := range rules.TxTypes {
+		txTypes[txType] = struct{}{}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules, f.addresses, f.topics, f.txTypes = rules, addresses, topics, txTypes
+}
+
+func (f *eventFilter) getRules() *FilterRules {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.rules
+}
+
+// filter returns the event with the matched transactions only, and false if no
+// transaction is matched.
+func (f *eventFilter) filter(ev blockchain.ChainEvent) (blockchain.ChainEvent, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.rules.isEmpty() {
+		return ev, true
+	}
+
+	var (
+		txs       types.Transactions
+		receipts  types.Receipts
+		logs      []*types.Log
+		traces    []*vm.InternalTxTrace
+		txLogs    = groupLogsByTx(ev.Logs)
+		hasTraces = len(ev.InternalTxTraces) == len(ev.Block.Transactions())
+	)
+	for i, tx := range ev.Block.Transactions() {
+		var receipt *types.Receipt
+		if i < len(ev.Receipts) {
+			receipt = ev.Receipts[i]
+		}
+		if !f.match(tx, receipt, txLogs[tx.Hash()]) {
+			continue
+		}
+		txs = append(txs, tx)
+		if receipt != nil {
+			receipts = append(receipts, receipt)
+		}
+		logs = append(logs, txLogs[tx.Hash()]...)
+		if hasTraces {
+			traces = append(traces, ev.InternalTxTraces[i])
+		}
+	}
+	filteredTxsCounter.Inc(int64(len(ev.Block.Transactions()) - len(txs)))
+	if len(txs) == 0 {
+		return ev, false
+	}
+
+	// The block keeps the header of the original one, so the hash is not changed.
+	return blockchain.ChainEvent{
+		Block:            types.NewBlockWithHeader(ev.Block.Header()).WithBody(txs),
+		Hash:             ev.Hash,
+		Receipts:         receipts,
+		Logs:             logs,
+		InternalTxTraces: traces,
+	}, true
+}
+
+func (f *eventFilter) match(tx *types.Transaction, receipt *types.Receipt, logs []*types.Log) bool {
+	if len(f.txTypes) > 0 {
+		if _, ok := f.txTypes[tx.Type()]; !ok {
+			return false
+		}
+	}
+	if len(f.addresses) > 0 && !f.matchAddress(tx, receipt, logs) {
+		return false
+	}
+	if len(f.topics) > 0 && !f.matchTopic(logs) {
+		return false
+	}
+	return true
+}
+
+func (f *eventFilter) matchAddress(tx *types.Transaction, receipt *types.Receipt, logs []*types.Log) bool {
+	var (
+		candidates []common.Address
+		from       common.Address
+	)
+	if tx.IsEthereumTransaction() {
+		from, _ = types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	} else {
+		from, _ = tx.From()
+	}
+	candidates = append(candidates, from)
+	if to := tx.To(); to != nil {
+		candidates = append(candidates, *to)
+	} else if receipt != nil {
+		candidates = append(candidates, receipt.ContractAddress)
+	}
+	for _, log := range logs {
+		candidates = append(candidates, log.Address)
+	}
+	for _, addr := range candidates {
+		if _, ok := f.addresses[addr]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *eventFilter) matchTopic(logs []*types.Log) bool {
+	for _, log := range logs {
+		for _, topic := range log.Topics {
+			if _, ok := f.topics[topic]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func groupLogsByTx(logs []*types.Log) map[common.Hash][]*types.Log {
+	txLogs := make(map[common.Hash][]*types.Log)
+	for _, log := range logs {
+		txLogs[log.TxHash] = append(txLogs[log.TxHash], log)
+	}
+	return txLogs
+}
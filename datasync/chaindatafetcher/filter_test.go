@@ -0,0 +1,99 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package chaindatafetcher
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestFilterEvent() blockchain.ChainEvent {
+	tx1 := types.NewTransaction(0, common.HexToAddress("0x01"), big.NewInt(1), 21000, big.NewInt(1), nil)
+	tx2 := types.NewTransaction(1, common.HexToAddress("0x02"), big.NewInt(1), 21000, big.NewInt(1), nil)
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(10)}).WithBody(types.Transactions{tx1, tx2})
+	log := &types.Log{Address: common.HexToAddress("0x03"), Topics: []common.Hash{common.HexToHash("0x04")}, TxHash: tx2.Hash()}
+	return blockchain.ChainEvent{
+		Block:            block,
+		Hash:             block.Hash(),
+		Receipts:         types.Receipts{{Status: types.ReceiptStatusSuccessful}, {Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{log}}},
+		Logs:             []*types.Log{log},
+		InternalTxTraces: []*vm.InternalTxTrace{{Type: "CALL"}, {Type: "STATICCALL"}},
+	}
+}
+
+func TestEventFilter(t *testing.T) {
+	ev := newTestFilterEvent()
+
+	// Empty rules export every block as it is.
+	f := newEventFilter(nil)
+	filtered, ok := f.filter(ev)
+	assert.True(t, ok)
+	assert.Equal(t, ev, filtered)
+
+	for _, rules := range []*FilterRules{
+		{Addresses: []common.Address{common.HexToAddress("0x02")}},
+		{Addresses: []common.Address{common.HexToAddress("0x03")}}, // address of the log
+		{Topics: []common.Hash{common.HexToHash("0x04")}},
+		{Addresses: []common.Address{common.HexToAddress("0x02")}, TxTypes: []types.TxType{types.TxTypeLegacyTransaction}},
+	} {
+		f.setRules(rules)
+		filtered, ok = f.filter(ev)
+		assert.True(t, ok)
+		assert.Equal(t, ev.Hash, filtered.Block.Hash())
+		assert.Equal(t, types.Transactions{ev.Block.Transactions()[1]}, filtered.Block.Transactions())
+		assert.Equal(t, ev.Receipts[1:], filtered.Receipts)
+		assert.Equal(t, ev.Logs, filtered.Logs)
+		assert.Equal(t, ev.InternalTxTraces[1:], filtered.InternalTxTraces)
+	}
+
+	// Every non-empty rule should be matched.
+	f.setRules(&FilterRules{Addresses: []common.Address{common.HexToAddress("0x01")}, Topics: []common.Hash{common.HexToHash("0x04")}})
+	_, ok = f.filter(ev)
+	assert.False(t, ok)
+
+	f.setRules(&FilterRules{TxTypes: []types.TxType{types.TxTypeValueTransfer}})
+	_, ok = f.filter(ev)
+	assert.False(t, ok)
+}
+
+func TestLoadFilterRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klaytn-test-chaindatafetcher-filter")
+	if err != nil {
+		t.Fatalf("cannot create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "rules.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"addresses": ["0x0000000000000000000000000000000000000001"], "txTypes": [48]}`), 0o644))
+	rules, err := LoadFilterRules(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []common.Address{common.HexToAddress("0x01")}, rules.Addresses)
+	assert.Equal(t, []types.TxType{types.TxTypeSmartContractExecution}, rules.TxTypes)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"addresses": "0x01"}`), 0o644))
+	_, err = LoadFilterRules(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,147 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package kafka
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+)
+
+const avroNamespace = "io.klaytn.chaindatafetcher"
+
+// avroSchema returns the Avro schema of the record in JSON. The optional fields
+// and the collections have the defaults, so that the fields can be added in a
+// backward compatible way.
+func avroSchema(rs *recordSchema) string {
+	enc, _ := json.Marshal(avroRecordType(rs, make(map[string]bool)))
+	return string(enc)
+}
+
+func avroRecordType(rs *recordSchema, defined map[string]bool) interface{} {
+	if defined[rs.name] {
+		return avroNamespace + "." + rs.name
+	}
+	defined[rs.name] = true
+
+	fields := make([]map[string]interface{}, len(rs.fields))
+	for i, f := range rs.fields {
+		field := map[string]interface{}{"name": f.name}
+		switch f.typ {
+		case longField:
+			field["type"] = "long"
+		case stringField:
+			field["type"] = "string"
+		case optionalStringField:
+			field["type"] = []interface{}{"null", "string"}
+			field["default"] = nil
+		case stringArrayField:
+			field["type"] = map[string]interface{}{"type": "array", "items": "string"}
+			field["default"] = []interface{}{}
+		case stringMapField:
+			field["type"] = map[string]interface{}{"type": "map", "values": "string"}
+			field["default"] = map[string]interface{}{}
+		case recordArrayField:
+			field["type"] = map[string]interface{}{"type": "array", "items": avroRecordType(f.record, defined)}
+			field["default"] = []interface{}{}
+		case optionalRecordField:
+			field["type"] = []interface{}{"null", avroRecordType(f.record, defined)}
+			field["default"] = nil
+		}
+		fields[i] = field
+	}
+	return map[string]interface{}{
+		"type":      "record",
+		"name":      rs.name,
+		"namespace": avroNamespace,
+		"fields":    fields,
+	}
+}
+
+// encodeAvro encodes the record value in the Avro binary encoding.
+func encodeAvro(rs *recordSchema, record []interface{}) []byte {
+	return appendAvroRecord(nil, rs, record)
+}
+
+func appendAvroLong(buf []byte, v int64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	return append(buf, tmp[:binary.PutVarint(tmp[:], v)]...) // zig-zag encoding as Avro
+}
+
+func appendAvroString(buf []byte, s string) []byte {
+	return append(appendAvroLong(buf, int64(len(s))), s...)
+}
+
+func appendAvroRecord(buf []byte, rs *recordSchema, record []interface{}) []byte {
+	for i, f := range rs.fields {
+		switch v := record[i]; f.typ {
+		case longField:
+			buf = appendAvroLong(buf, v.(int64))
+		case stringField:
+			buf = appendAvroString(buf, v.(string))
+		case optionalStringField:
+			if s := v.(*string); s != nil {
+				buf = appendAvroString(appendAvroLong(buf, 1), *s)
+			} else {
+				buf = appendAvroLong(buf, 0)
+			}
+		case stringArrayField:
+			items := v.([]string)
+			if len(items) > 0 {
+				buf = appendAvroLong(buf, int64(len(items)))
+				for _, item := range items {
+					buf = appendAvroString(buf, item)
+				}
+			}
+			buf = appendAvroLong(buf, 0)
+		case stringMapField:
+			m := v.(map[string]string)
+			if len(m) > 0 {
+				buf = appendAvroLong(buf, int64(len(m)))
+				for _, key := range sortedKeys(m) {
+					buf = appendAvroString(appendAvroString(buf, key), m[key])
+				}
+			}
+			buf = appendAvroLong(buf, 0)
+		case recordArrayField:
+			items := v.([][]interface{})
+			if len(items) > 0 {
+				buf = appendAvroLong(buf, int64(len(items)))
+				for _, item := range items {
+					buf = appendAvroRecord(buf, f.record, item)
+				}
+			}
+			buf = appendAvroLong(buf, 0)
+		case optionalRecordField:
+			if item, _ := v.([]interface{}); item != nil {
+				buf = appendAvroRecord(appendAvroLong(buf, 1), f.record, item)
+			} else {
+				buf = appendAvroLong(buf, 0)
+			}
+		}
+	}
+	return buf
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -26,8 +26,9 @@ import (
 )
 
 const (
-	EventBlockGroup = "blockgroup"
-	EventTraceGroup = "tracegroup"
+	EventBlockGroup            = "blockgroup"
+	EventTraceGroup            = "tracegroup"
+	EventInternalTransferGroup = "internaltransfergroup"
 )
 
 const (
@@ -48,6 +49,7 @@ const (
 	DefaultKafkaMessageVersion  = MsgVersion1_0
 	DefaultProducerIdPrefix     = "producer-"
 	DefaultExpirationTime       = time.Duration(0)
+	DefaultMsgEncoding          = MsgEncodingJSON
 )
 
 var (
@@ -56,7 +58,7 @@ var (
 )
 
 type KafkaConfig struct {
-	SaramaConfig         *sarama.Config `json:"-"` // kafka client configurations.
+	SaramaConfig         *sarama.Config `json:"-" toml:"-"` // kafka client configurations.
 	MsgVersion           string         // MsgVersion is the version of Kafka message.
 	ProducerId           string         // ProducerId is for the identification of the message publisher.
 	Brokers              []string       // Brokers is a list of broker URLs.
@@ -69,10 +71,15 @@ type KafkaConfig struct {
 	// default max number of messages is 100
 	MaxMessageNumber int // MaxMessageNumber is the maximum number of consumer messages.
 
+	MaxMessagesPerSecond int // MaxMessagesPerSecond limits the rate of the produced messages (0 = unlimited).
+
+	MsgEncoding       string // MsgEncoding is the encoding of the published data, one of json, avro and protobuf.
+	SchemaRegistryURL string // SchemaRegistryURL is the URL of the schema registry, required for avro and protobuf.
+
 	ExpirationTime time.Duration
-	ErrCallback    func(string) error
-	Setup          func(s sarama.ConsumerGroupSession) error
-	Cleanup        func(s sarama.ConsumerGroupSession) error
+	ErrCallback    func(string) error                        `toml:"-"`
+	Setup          func(s sarama.ConsumerGroupSession) error `toml:"-"`
+	Cleanup        func(s sarama.ConsumerGroupSession) error `toml:"-"`
 }
 
 func GetDefaultKafkaConfig() *KafkaConfig {
@@ -93,6 +100,7 @@ func GetDefaultKafkaConfig() *KafkaConfig {
 		SegmentSizeBytes:     DefaultSegmentSizeBytes,
 		MaxMessageNumber:     DefaultMaxMessageNumber,
 		MsgVersion:           DefaultKafkaMessageVersion,
+		MsgEncoding:          DefaultMsgEncoding,
 		ProducerId:           GetDefaultProducerId(),
 		ExpirationTime:       DefaultExpirationTime,
 		Setup:                DefaultSetup,
@@ -112,6 +120,6 @@ func (c *KafkaConfig) GetTopicName(event string) string {
 }
 
 func (c *KafkaConfig) String() string {
-	return fmt.Sprintf("brokers: %v, topicEnvironment: %v, topicResourceName: %v, partitions: %v, replicas: %v, maxMessageBytes: %v, requiredAcks: %v, segmentSize: %v, msgVersion: %v, producerId: %v",
-		c.Brokers, c.TopicEnvironmentName, c.TopicResourceName, c.Partitions, c.Replicas, c.SaramaConfig.Producer.MaxMessageBytes, c.SaramaConfig.Producer.RequiredAcks, c.SegmentSizeBytes, c.MsgVersion, c.ProducerId)
+	return fmt.Sprintf("brokers: %v, topicEnvironment: %v, topicResourceName: %v, partitions: %v, replicas: %v, maxMessageBytes: %v, requiredAcks: %v, segmentSize: %v, msgVersion: %v, producerId: %v, msgEncoding: %v, schemaRegistry: %v, maxMessagesPerSecond: %v",
+		c.Brokers, c.TopicEnvironmentName, c.TopicResourceName, c.Partitions, c.Replicas, c.SaramaConfig.Producer.MaxMessageBytes, c.SaramaConfig.Producer.RequiredAcks, c.SegmentSizeBytes, c.MsgVersion, c.ProducerId, c.MsgEncoding, c.SchemaRegistryURL, c.MaxMessagesPerSecond)
 }
@@ -40,7 +40,7 @@ type ConsumerGroupSession interface {
 }
 
 var (
-	eventNameErrorMsg          = "the event name must be either 'blockgroup' or 'tracegroup'"
+	eventNameErrorMsg          = "the event name must be one of 'blockgroup', 'tracegroup' and 'internaltransfergroup'"
 	nilConsumerMessageErrorMsg = "the given message should not be nil"
 	wrongHeaderNumberErrorMsg  = "the number of header is not expected"
 	wrongHeaderKeyErrorMsg     = "the header key is not expected"
@@ -158,7 +158,7 @@ func (c *Consumer) Close() error {
 
 // AddTopicAndHandler adds a topic associated the given event and its handler function to consume published messages of the topic.
 func (c *Consumer) AddTopicAndHandler(event string, handler TopicHandler) error {
-	if event != EventBlockGroup && event != EventTraceGroup {
+	if event != EventBlockGroup && event != EventTraceGroup && event != EventInternalTransferGroup {
 		return fmt.Errorf("%v [given: %v]", eventNameErrorMsg, event)
 	}
 	topic := c.config.GetTopicName(event)
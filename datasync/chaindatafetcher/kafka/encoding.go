@@ -0,0 +1,99 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package kafka
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	MsgEncodingJSON     = "json"
+	MsgEncodingAvro     = "avro"
+	MsgEncodingProtobuf = "protobuf"
+)
+
+// messageEncoder encodes the data published to a topic.
+type messageEncoder interface {
+	encode(topic string, data interface{}) ([]byte, error)
+}
+
+func newMessageEncoder(config *KafkaConfig) (messageEncoder, error) {
+	switch config.MsgEncoding {
+	case "", MsgEncodingJSON:
+		return jsonEncoder{}, nil
+	case MsgEncodingAvro, MsgEncodingProtobuf:
+		if config.SchemaRegistryURL == "" {
+			return nil, fmt.Errorf("the schema registry is required for the %s encoding", config.MsgEncoding)
+		}
+		return newSchemaEncoder(config.MsgEncoding, newSchemaRegistry(config.SchemaRegistryURL)), nil
+	default:
+		return nil, fmt.Errorf("not supported message encoding: %s", config.MsgEncoding)
+	}
+}
+
+type jsonEncoder struct{}
+
+func (jsonEncoder) encode(topic string, data interface{}) ([]byte, error) {
+	return json.Marshal(data)
+}
+
+// schemaEncoder encodes the typed records with Avro or Protobuf in the wire
+// format of the Confluent Schema Registry, which prefixes the id of the schema
+// registered under the subject of the topic, "<topic>-value".
+type schemaEncoder struct {
+	registry     *schemaRegistry
+	schemaType   string
+	schemaOf     func(rs *recordSchema) string
+	encodeRecord func(rs *recordSchema, record []interface{}) []byte
+}
+
+func newSchemaEncoder(encoding string, registry *schemaRegistry) *schemaEncoder {
+	if encoding == MsgEncodingAvro {
+		return &schemaEncoder{registry: registry, schemaType: schemaTypeAvro, schemaOf: avroSchema, encodeRecord: encodeAvro}
+	}
+	return &schemaEncoder{registry: registry, schemaType: schemaTypeProtobuf, schemaOf: protobufSchema, encodeRecord: encodeProtobuf}
+}
+
+// register registers the schema of the topic, to fail early if the schema is
+// not compatible with the registered one.
+func (e *schemaEncoder) register(topic string, rs *recordSchema) (int, error) {
+	return e.registry.register(topic+"-value", e.schemaType, e.schemaOf(rs))
+}
+
+func (e *schemaEncoder) encode(topic string, data interface{}) ([]byte, error) {
+	r, ok := data.(schemaRecord)
+	if !ok {
+		return nil, fmt.Errorf("%T can not be encoded with the %s schema", data, e.schemaType)
+	}
+	id, err := e.register(topic, r.schema())
+	if err != nil {
+		return nil, err
+	}
+	record, err := r.record()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 5, 64)
+	binary.BigEndian.PutUint32(buf[1:], uint32(id)) // buf[0] is the magic byte 0
+	if e.schemaType == schemaTypeProtobuf {
+		buf = append(buf, 0) // the message indexes of the first message
+	}
+	return append(buf, e.encodeRecord(r.schema(), record)...), nil
+}
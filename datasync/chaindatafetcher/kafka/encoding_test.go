@@ -0,0 +1,204 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package kafka
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRecordSchema = &recordSchema{
+	name: "Test",
+	fields: []fieldSchema{
+		{name: "number", typ: longField},
+		{name: "name", typ: stringField},
+		{name: "to", typ: optionalStringField},
+		{name: "tags", typ: stringArrayField},
+		{name: "extra", typ: stringMapField},
+	},
+}
+
+func TestEncodeAvro(t *testing.T) {
+	to := "b"
+	enc := encodeAvro(testRecordSchema, []interface{}{int64(-1), "a", &to, []string{"c"}, map[string]string{}})
+	// -1 in zig-zag, "a", the union index 1 and "b", one item "c" and the end, the empty map.
+	assert.Equal(t, []byte{0x01, 0x02, 'a', 0x02, 0x02, 'b', 0x02, 0x02, 'c', 0x00, 0x00}, enc)
+
+	var schema map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(avroSchema(testRecordSchema)), &schema))
+	assert.Equal(t, "record", schema["type"])
+	assert.Equal(t, avroNamespace, schema["namespace"])
+
+	// The recursive record is referred by its full name.
+	assert.Contains(t, avroSchema(traceGroupSchema), `"items":"`+avroNamespace+`.InternalTxTrace"`)
+}
+
+func TestEncodeProtobuf(t *testing.T) {
+	expected := []byte{
+		0x08, 0x01, // number = 1, the empty name and the absent to are omitted
+		0x22, 0x01, 'c', // tags
+		0x2a, 0x06, 0x0a, 0x01, 'k', 0x12, 0x01, 'v', // extra
+	}
+	assert.Equal(t, expected, encodeProtobuf(testRecordSchema, []interface{}{int64(1), "", (*string)(nil), []string{"c"}, map[string]string{"k": "v"}}))
+
+	schema := protobufSchema(traceGroupSchema)
+	assert.True(t, strings.HasPrefix(schema, "syntax = \"proto3\";\n\npackage "+protobufPackage+";\n\nmessage TraceGroup {\n"))
+	assert.Contains(t, schema, "  repeated InternalTxTrace calls = 11;\n")
+	assert.Equal(t, 1, strings.Count(schema, "message InternalTxTrace {"))
+}
+
+func TestBlockGroupResult_Record(t *testing.T) {
+	r := &blockGroupResult{
+		BlockNumber: big.NewInt(10),
+		Result: map[string]interface{}{
+			"number":     "0xa",
+			"hash":       "0x01",
+			"timestamp":  "0x64",
+			"committee":  []string{"0x02"},
+			"size":       json.Number("120"),
+			"blockScore": "0x1",
+			"transactions": []map[string]interface{}{
+				{"transactionHash": "0x03", "typeInt": 0, "from": "0x04", "to": nil, "gas": "0x5208", "input": "0x"},
+			},
+		},
+	}
+	record, err := r.record()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), record[0])
+	assert.Equal(t, "0x01", record[1])
+	assert.Equal(t, int64(100), record[3])
+	assert.Equal(t, []string{"0x02"}, record[5])
+	assert.Equal(t, int64(120), record[7])
+	assert.Equal(t, map[string]string{"blockScore": `"0x1"`}, record[9])
+
+	txs := record[8].([][]interface{})
+	assert.Len(t, txs, 1)
+	assert.Equal(t, "0x03", txs[0][0])
+	assert.Nil(t, txs[0][3])
+	assert.Equal(t, int64(21000), txs[0][5])
+	assert.Equal(t, map[string]string{"input": `"0x"`}, txs[0][9])
+
+	// The record is encoded by the schema without a panic.
+	assert.NotEmpty(t, encodeAvro(blockGroupSchema, record))
+	assert.NotEmpty(t, encodeProtobuf(blockGroupSchema, record))
+
+	r.Result["timestamp"] = true
+	_, err = r.record()
+	assert.Error(t, err)
+}
+
+func TestTraceGroupResult_Record(t *testing.T) {
+	from := common.HexToAddress("0x01")
+	r := &traceGroupResult{
+		BlockNumber: big.NewInt(10),
+		InternalTxTraces: []*vm.InternalTxTrace{
+			{Type: "CALL", From: &from, Calls: []*vm.InternalTxTrace{{Type: "STATICCALL"}}},
+		},
+	}
+	record, err := r.record()
+	assert.NoError(t, err)
+
+	traces := record[1].([][]interface{})
+	assert.Len(t, traces, 1)
+	assert.Equal(t, from.Hex(), *traces[0][1].(*string))
+	assert.Len(t, traces[0][10].([][]interface{}), 1)
+	assert.NotEmpty(t, encodeAvro(traceGroupSchema, record))
+	assert.NotEmpty(t, encodeProtobuf(traceGroupSchema, record))
+}
+
+func newTestSchemaRegistry(t *testing.T, compatible bool) (*httptest.Server, *int) {
+	registered := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req schemaRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/compatibility/subjects/new-value/"):
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(schemaRegistryError{ErrorCode: subjectNotFound, Message: "Subject not found."})
+		case strings.HasPrefix(r.URL.Path, "/compatibility/"):
+			json.NewEncoder(w).Encode(map[string]bool{"is_compatible": compatible})
+		case strings.HasSuffix(r.URL.Path, "/versions"):
+			registered++
+			json.NewEncoder(w).Encode(map[string]int{"id": 7})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server, &registered
+}
+
+func TestSchemaRegistry_Register(t *testing.T) {
+	server, registered := newTestSchemaRegistry(t, true)
+	defer server.Close()
+
+	registry := newSchemaRegistry(server.URL + "/")
+	for i := 0; i < 2; i++ {
+		id, err := registry.register("topic-value", schemaTypeAvro, "schema")
+		assert.NoError(t, err)
+		assert.Equal(t, 7, id)
+	}
+	assert.Equal(t, 1, *registered) // The id is cached.
+
+	// A new subject is registered without the compatibility check.
+	id, err := registry.register("new-value", schemaTypeAvro, "schema")
+	assert.NoError(t, err)
+	assert.Equal(t, 7, id)
+
+	incompatibleServer, _ := newTestSchemaRegistry(t, false)
+	defer incompatibleServer.Close()
+	_, err = newSchemaRegistry(incompatibleServer.URL).register("topic-value", schemaTypeAvro, "schema")
+	assert.True(t, errors.Is(err, errIncompatibleSchema))
+}
+
+func TestSchemaEncoder_Encode(t *testing.T) {
+	server, _ := newTestSchemaRegistry(t, true)
+	defer server.Close()
+
+	r := &traceGroupResult{BlockNumber: big.NewInt(1)}
+	for _, encoding := range []string{MsgEncodingAvro, MsgEncodingProtobuf} {
+		encoder, err := newMessageEncoder(&KafkaConfig{MsgEncoding: encoding, SchemaRegistryURL: server.URL})
+		assert.NoError(t, err)
+
+		enc, err := encoder.encode("topic", r)
+		assert.NoError(t, err)
+		assert.Equal(t, byte(0), enc[0])
+		assert.Equal(t, uint32(7), binary.BigEndian.Uint32(enc[1:5]))
+		if encoding == MsgEncodingAvro {
+			assert.Equal(t, encodeAvro(traceGroupSchema, []interface{}{int64(1), [][]interface{}{}}), enc[5:])
+		} else {
+			assert.Equal(t, []byte{0x00, 0x08, 0x01}, enc[5:])
+		}
+
+		_, err = encoder.encode("topic", map[string]interface{}{})
+		assert.Error(t, err)
+	}
+
+	_, err := newMessageEncoder(&KafkaConfig{MsgEncoding: MsgEncodingAvro})
+	assert.Error(t, err)
+	_, err = newMessageEncoder(&KafkaConfig{MsgEncoding: "xml"})
+	assert.Error(t, err)
+}
@@ -17,10 +17,12 @@
 package kafka
 
 import (
-	"encoding/json"
+	"errors"
+	"fmt"
 
 	"github.com/Shopify/sarama"
 	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/datasync/chaindatafetcher/types"
 	"github.com/klaytn/klaytn/log"
 )
 
@@ -57,9 +59,16 @@ type Kafka struct {
 	config   *KafkaConfig
 	producer sarama.SyncProducer
 	admin    sarama.ClusterAdmin
+	encoder  messageEncoder
+	limiter  *rateLimiter
 }
 
 func NewKafka(conf *KafkaConfig) (*Kafka, error) {
+	encoder, err := newMessageEncoder(conf)
+	if err != nil {
+		return nil, err
+	}
+
 	producer, err := sarama.NewSyncProducer(conf.Brokers, conf.SaramaConfig)
 	if err != nil {
 		logger.Error("Failed to create a new producer", "brokers", conf.Brokers)
@@ -76,6 +85,8 @@ func NewKafka(conf *KafkaConfig) (*Kafka, error) {
 		config:   conf,
 		producer: producer,
 		admin:    admin,
+		encoder:  encoder,
+		limiter:  newRateLimiter(conf.MaxMessagesPerSecond),
 	}
 
 	blockGroupTopic := conf.GetTopicName(EventBlockGroup)
@@ -87,6 +98,17 @@ func NewKafka(conf *KafkaConfig) (*Kafka, error) {
 	if err := kafka.setupTopic(traceGroupTopic); err != nil {
 		return nil, err
 	}
+
+	internalTransferGroupTopic := conf.GetTopicName(EventInternalTransferGroup)
+	if err := kafka.setupTopic(internalTransferGroupTopic); err != nil {
+		return nil, err
+	}
+
+	if se, ok := encoder.(*schemaEncoder); ok {
+		if err := kafka.registerSchemas(se); err != nil {
+			return nil, err
+		}
+	}
 	return kafka, nil
 }
 
@@ -113,6 +135,24 @@ func (k *Kafka) setupTopic(topicName string) error {
 	return nil
 }
 
+// registerSchemas registers the schemas of the topics to the schema registry, to
+// fail early if they are not compatible with the registered ones.
+func (k *Kafka) registerSchemas(se *schemaEncoder) error {
+	schemas := map[string]*recordSchema{
+		EventBlockGroup:            blockGroupSchema,
+		EventTraceGroup:            traceGroupSchema,
+		EventInternalTransferGroup: internalTransferGroupSchema,
+	}
+	for event, rs := range schemas {
+		topic := k.getTopicName(event)
+		if _, err := se.register(topic, rs); err != nil {
+			logger.Error("registering a schema is failed", "topicName", topic, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
 func (k *Kafka) Close() {
 	k.producer.Close()
 	k.admin.Close()
@@ -182,9 +222,9 @@ func (k *Kafka) makeProducerMessage(topic, key string, segment []byte, segmentId
 }
 
 func (k *Kafka) Publish(topic string, data interface{}) error {
-	dataBytes, err := json.Marshal(data)
+	dataBytes, err := k.encoder.encode(topic, data)
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: %v", types.ErrPoisonRecord, err)
 	}
 	key := ""
 	if v, ok := data.(IKey); ok {
@@ -193,9 +233,13 @@ func (k *Kafka) Publish(topic string, data interface{}) error {
 	segments, totalSegments := k.split(dataBytes)
 	for idx, segment := range segments {
 		msg := k.makeProducerMessage(topic, key, segment, uint64(idx), uint64(totalSegments))
+		k.limiter.wait()
 		_, _, err = k.producer.SendMessage(msg)
 		if err != nil {
 			logger.Error("sending kafka message is failed", "err", err, "segmentIdx", idx, "key", key)
+			if errors.Is(err, sarama.ErrMessageSizeTooLarge) {
+				return fmt.Errorf("%w: %v", types.ErrPoisonRecord, err)
+			}
 			return err
 		}
 	}
@@ -0,0 +1,135 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package kafka
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+const protobufPackage = "klaytn.chaindatafetcher"
+
+// protobufSchema returns the Protobuf schema of the record, whose field numbers
+// are the positions of the fields. The message of the record is the first one,
+// so its message index is 0 in the wire format of the schema registry.
+func protobufSchema(rs *recordSchema) string {
+	var (
+		b       strings.Builder
+		defined = make(map[string]bool)
+		queue   = []*recordSchema{rs}
+	)
+	fmt.Fprintf(&b, "syntax = \"proto3\";\n\npackage %s;\n", protobufPackage)
+	for len(queue) > 0 {
+		rs := queue[0]
+		queue = queue[1:]
+		if defined[rs.name] {
+			continue
+		}
+		defined[rs.name] = true
+
+		fmt.Fprintf(&b, "\nmessage %s {\n", rs.name)
+		for i, f := range rs.fields {
+			var typ string
+			switch f.typ {
+			case longField:
+				typ = "int64"
+			case stringField:
+				typ = "string"
+			case optionalStringField:
+				typ = "optional string"
+			case stringArrayField:
+				typ = "repeated string"
+			case stringMapField:
+				typ = "map<string, string>"
+			case recordArrayField:
+				typ = "repeated " + f.record.name
+				queue = append(queue, f.record)
+			case optionalRecordField:
+				typ = f.record.name
+				queue = append(queue, f.record)
+			}
+			fmt.Fprintf(&b, "  %s %s = %d;\n", typ, f.name, i+1)
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+// encodeProtobuf encodes the record value in the Protobuf binary encoding.
+func encodeProtobuf(rs *recordSchema, record []interface{}) []byte {
+	return appendProtobufRecord(nil, rs, record)
+}
+
+const (
+	protobufVarint          = 0
+	protobufLengthDelimited = 2
+)
+
+func appendProtobufVarint(buf []byte, v uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	return append(buf, tmp[:binary.PutUvarint(tmp[:], v)]...)
+}
+
+func appendProtobufTag(buf []byte, num int, wireType uint64) []byte {
+	return appendProtobufVarint(buf, uint64(num)<<3|wireType)
+}
+
+func appendProtobufBytes(buf []byte, num int, b []byte) []byte {
+	buf = appendProtobufTag(buf, num, protobufLengthDelimited)
+	return append(appendProtobufVarint(buf, uint64(len(b))), b...)
+}
+
+func appendProtobufRecord(buf []byte, rs *recordSchema, record []interface{}) []byte {
+	for i, f := range rs.fields {
+		num := i + 1
+		switch v := record[i]; f.typ {
+		case longField:
+			if n := v.(int64); n != 0 {
+				buf = appendProtobufVarint(appendProtobufTag(buf, num, protobufVarint), uint64(n))
+			}
+		case stringField:
+			if s := v.(string); s != "" {
+				buf = appendProtobufBytes(buf, num, []byte(s))
+			}
+		case optionalStringField:
+			if s := v.(*string); s != nil {
+				buf = appendProtobufBytes(buf, num, []byte(*s))
+			}
+		case stringArrayField:
+			for _, s := range v.([]string) {
+				buf = appendProtobufBytes(buf, num, []byte(s))
+			}
+		case stringMapField:
+			m := v.(map[string]string)
+			for _, key := range sortedKeys(m) {
+				entry := appendProtobufBytes(nil, 1, []byte(key))
+				entry = appendProtobufBytes(entry, 2, []byte(m[key]))
+				buf = appendProtobufBytes(buf, num, entry)
+			}
+		case recordArrayField:
+			for _, item := range v.([][]interface{}) {
+				buf = appendProtobufBytes(buf, num, appendProtobufRecord(nil, f.record, item))
+			}
+		case optionalRecordField:
+			if item, _ := v.([]interface{}); item != nil {
+				buf = appendProtobufBytes(buf, num, appendProtobufRecord(nil, f.record, item))
+			}
+		}
+	}
+	return buf
+}
@@ -0,0 +1,49 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	// no limit
+	limiter := newRateLimiter(0)
+	assert.Nil(t, limiter)
+	limiter.wait()
+
+	now := time.Unix(0, 0)
+	var slept []time.Duration
+	limiter = newRateLimiter(4)
+	limiter.now = func() time.Time { return now }
+	limiter.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+	// the messages are spaced by 250ms
+	for i := 0; i < 3; i++ {
+		limiter.wait()
+	}
+	assert.Equal(t, []time.Duration{250 * time.Millisecond, 500 * time.Millisecond}, slept)
+
+	// no wait after the idle time
+	now = now.Add(time.Second)
+	slept = nil
+	limiter.wait()
+	assert.Nil(t, slept)
+}
@@ -35,6 +35,15 @@ func (r *traceGroupResult) Key() string {
 	return r.BlockNumber.String()
 }
 
+type internalTransferGroupResult struct {
+	BlockNumber       *big.Int                  `json:"blockNumber"`
+	InternalTransfers []*types.InternalTransfer `json:"result"`
+}
+
+func (r *internalTransferGroupResult) Key() string {
+	return r.BlockNumber.String()
+}
+
 type blockGroupResult struct {
 	BlockNumber *big.Int               `json:"blockNumber"`
 	Result      map[string]interface{} `json:"result"`
@@ -84,6 +93,16 @@ func (r *repository) HandleChainEvent(event blockchain.ChainEvent, dataType type
 			return r.kafka.Publish(r.kafka.getTopicName(EventTraceGroup), result)
 		}
 		return nil
+	case types.RequestTypeInternalTransferGroup:
+		transfers := types.InternalTransfers(event.Block.Transactions(), event.InternalTxTraces)
+		if len(transfers) > 0 {
+			result := &internalTransferGroupResult{
+				BlockNumber:       event.Block.Number(),
+				InternalTransfers: transfers,
+			}
+			return r.kafka.Publish(r.kafka.getTopicName(EventInternalTransferGroup), result)
+		}
+		return nil
 	default:
 		return fmt.Errorf("not supported type. [blockNumber: %v, reqType: %v]", event.Block.NumberU64(), dataType)
 	}
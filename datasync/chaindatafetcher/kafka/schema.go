@@ -0,0 +1,357 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common/hexutil"
+)
+
+// fieldType is the type of a field of the typed records, which are encoded
+// with Avro or Protobuf.
+type fieldType int
+
+const (
+	longField           fieldType = iota // int64
+	stringField                          // string
+	optionalStringField                  // *string
+	stringArrayField                     // []string
+	stringMapField                       // map[string]string
+	recordArrayField                     // [][]interface{} of the record
+	optionalRecordField                  // []interface{} of the record, or nil
+)
+
+// recordSchema is the schema of a typed record, from which the Avro and the
+// Protobuf schemas are generated. A record value is a slice of the values of
+// the fields in order. New fields should be appended with a type allowing the
+// absence, since the field numbers of Protobuf are their positions.
+type recordSchema struct {
+	name   string
+	fields []fieldSchema
+}
+
+type fieldSchema struct {
+	name   string
+	typ    fieldType
+	record *recordSchema // schema of the record fields
+}
+
+// schemaRecord is the data published as a typed record.
+type schemaRecord interface {
+	schema() *recordSchema
+	record() ([]interface{}, error)
+}
+
+var transactionSchema = &recordSchema{
+	name: "Transaction",
+	fields: []fieldSchema{
+		{name: "transactionHash", typ: stringField},
+		{name: "typeInt", typ: longField},
+		{name: "from", typ: stringField},
+		{name: "to", typ: optionalStringField},
+		{name: "value", typ: optionalStringField},
+		{name: "gas", typ: longField},
+		{name: "gasUsed", typ: longField},
+		{name: "status", typ: longField},
+		{name: "contractAddress", typ: optionalStringField},
+		{name: "extra", typ: stringMapField}, // JSON encoded values of the other fields
+	},
+}
+
+var blockGroupSchema = &recordSchema{
+	name: "BlockGroup",
+	fields: []fieldSchema{
+		{name: "blockNumber", typ: longField},
+		{name: "hash", typ: stringField},
+		{name: "parentHash", typ: stringField},
+		{name: "timestamp", typ: longField},
+		{name: "proposer", typ: stringField},
+		{name: "committee", typ: stringArrayField},
+		{name: "gasUsed", typ: longField},
+		{name: "size", typ: longField},
+		{name: "transactions", typ: recordArrayField, record: transactionSchema},
+		{name: "extra", typ: stringMapField}, // JSON encoded values of the other fields
+	},
+}
+
+var revertedInfoSchema = &recordSchema{
+	name: "RevertedInfo",
+	fields: []fieldSchema{
+		{name: "contract", typ: optionalStringField},
+		{name: "message", typ: stringField},
+	},
+}
+
+var internalTxTraceSchema = &recordSchema{
+	name: "InternalTxTrace",
+	fields: []fieldSchema{
+		{name: "type", typ: stringField},
+		{name: "from", typ: optionalStringField},
+		{name: "to", typ: optionalStringField},
+		{name: "value", typ: stringField},
+		{name: "gas", typ: longField},
+		{name: "gasUsed", typ: longField},
+		{name: "input", typ: stringField},
+		{name: "output", typ: stringField},
+		{name: "error", typ: optionalStringField},
+		{name: "time", typ: longField}, // in nanoseconds
+		{name: "calls", typ: recordArrayField},
+		{name: "reverted", typ: optionalRecordField, record: revertedInfoSchema},
+	},
+}
+
+var traceGroupSchema = &recordSchema{
+	name: "TraceGroup",
+	fields: []fieldSchema{
+		{name: "blockNumber", typ: longField},
+		{name: "result", typ: recordArrayField, record: internalTxTraceSchema},
+	},
+}
+
+var internalTransferSchema = &recordSchema{
+	name: "InternalTransfer",
+	fields: []fieldSchema{
+		{name: "transactionHash", typ: stringField},
+		{name: "transactionIndex", typ: longField},
+		{name: "traceIndex", typ: longField},
+		{name: "depth", typ: longField},
+		{name: "type", typ: stringField},
+		{name: "from", typ: stringField},
+		{name: "to", typ: stringField},
+		{name: "value", typ: stringField}, // hex
+	},
+}
+
+var internalTransferGroupSchema = &recordSchema{
+	name: "InternalTransferGroup",
+	fields: []fieldSchema{
+		{name: "blockNumber", typ: longField},
+		{name: "result", typ: recordArrayField, record: internalTransferSchema},
+	},
+}
+
+func init() {
+	// The calls are the traces of the same type.
+	internalTxTraceSchema.fields[10].record = internalTxTraceSchema
+}
+
+func (r *blockGroupResult) schema() *recordSchema {
+	return blockGroupSchema
+}
+
+// record converts the block group into the typed record. The values of the
+// fields not in the schema are kept in extra as JSON.
+func (r *blockGroupResult) record() ([]interface{}, error) {
+	block, err := newJSONFields(r.Result)
+	if err != nil {
+		return nil, err
+	}
+	var txs []jsonFields
+	if raw, ok := block.fields["transactions"]; ok {
+		delete(block.fields, "transactions")
+		var outputs []map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &outputs); err != nil {
+			return nil, fmt.Errorf("invalid transactions: %v", err)
+		}
+		for _, output := range outputs {
+			txs = append(txs, jsonFields{fields: output})
+		}
+	}
+
+	txRecords := make([][]interface{}, len(txs))
+	for i, tx := range txs {
+		txRecords[i] = []interface{}{
+			tx.string("transactionHash"),
+			tx.long("typeInt"),
+			tx.string("from"),
+			tx.optionalString("to"),
+			tx.optionalString("value"),
+			tx.long("gas"),
+			tx.long("gasUsed"),
+			tx.long("status"),
+			tx.optionalString("contractAddress"),
+			tx.extra(),
+		}
+		if tx.err != nil {
+			return nil, fmt.Errorf("invalid transaction %d: %v", i, tx.err)
+		}
+	}
+	record := []interface{}{
+		r.BlockNumber.Int64(),
+		block.string("hash"),
+		block.string("parentHash"),
+		block.long("timestamp"),
+		block.string("proposer"),
+		block.stringArray("committee"),
+		block.long("gasUsed"),
+		block.long("size"),
+		txRecords,
+		nil, // extra, filled after the others are taken
+	}
+	delete(block.fields, "number")
+	record[9] = block.extra()
+	if block.err != nil {
+		return nil, fmt.Errorf("invalid block: %v", block.err)
+	}
+	return record, nil
+}
+
+func (r *traceGroupResult) schema() *recordSchema {
+	return traceGroupSchema
+}
+
+func (r *traceGroupResult) record() ([]interface{}, error) {
+	return []interface{}{r.BlockNumber.Int64(), internalTxTraceRecords(r.InternalTxTraces)}, nil
+}
+
+func (r *internalTransferGroupResult) schema() *recordSchema {
+	return internalTransferGroupSchema
+}
+
+func (r *internalTransferGroupResult) record() ([]interface{}, error) {
+	records := make([][]interface{}, len(r.InternalTransfers))
+	for i, t := range r.InternalTransfers {
+		records[i] = []interface{}{
+			t.TransactionHash.Hex(), int64(t.TransactionIndex), int64(t.TraceIndex), int64(t.Depth),
+			t.Type, t.From.Hex(), t.To.Hex(), t.Value.String(),
+		}
+	}
+	return []interface{}{r.BlockNumber.Int64(), records}, nil
+}
+
+func internalTxTraceRecords(traces []*vm.InternalTxTrace) [][]interface{} {
+	records := make([][]interface{}, len(traces))
+	for i, trace := range traces {
+		var (
+			from, to, errStr *string
+			reverted         []interface{}
+		)
+		if trace.From != nil {
+			s := trace.From.Hex()
+			from = &s
+		}
+		if trace.To != nil {
+			s := trace.To.Hex()
+			to = &s
+		}
+		if trace.Error != nil {
+			s := trace.Error.Error()
+			errStr = &s
+		}
+		if trace.Reverted != nil {
+			var contract *string
+			if trace.Reverted.Contract != nil {
+				s := trace.Reverted.Contract.Hex()
+				contract = &s
+			}
+			reverted = []interface{}{contract, trace.Reverted.Message}
+		}
+		records[i] = []interface{}{
+			trace.Type, from, to, trace.Value, int64(trace.Gas), int64(trace.GasUsed),
+			trace.Input, trace.Output, errStr, int64(trace.Time), internalTxTraceRecords(trace.Calls), reverted,
+		}
+	}
+	return records
+}
+
+// jsonFields takes the fields of a JSON object in the types of the schema. The
+// first error is kept, and the fields not taken are the extra fields.
+type jsonFields struct {
+	fields map[string]json.RawMessage
+	err    error
+}
+
+func newJSONFields(v interface{}) (*jsonFields, error) {
+	enc, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	f := &jsonFields{}
+	if err := json.Unmarshal(enc, &f.fields); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *jsonFields) take(key string, v interface{}) bool {
+	raw, ok := f.fields[key]
+	if !ok || f.err != nil {
+		return false
+	}
+	delete(f.fields, key)
+	if err := json.Unmarshal(raw, v); err != nil {
+		f.err = fmt.Errorf("field %s: %v", key, err)
+		return false
+	}
+	return true
+}
+
+func (f *jsonFields) string(key string) string {
+	var s string
+	f.take(key, &s)
+	return s
+}
+
+func (f *jsonFields) optionalString(key string) *string {
+	var s *string
+	f.take(key, &s)
+	return s
+}
+
+func (f *jsonFields) stringArray(key string) []string {
+	var s []string
+	f.take(key, &s)
+	return s
+}
+
+// long takes a number, which is a hex string or a JSON number.
+func (f *jsonFields) long(key string) int64 {
+	var v interface{}
+	if !f.take(key, &v) {
+		return 0
+	}
+	switch v := v.(type) {
+	case float64:
+		return int64(v)
+	case string:
+		n, err := hexutil.DecodeBig(v)
+		if err != nil {
+			f.err = fmt.Errorf("field %s: %v", key, err)
+			return 0
+		}
+		if !n.IsInt64() {
+			f.err = fmt.Errorf("field %s: %v overflows int64", key, n)
+			return 0
+		}
+		return n.Int64()
+	case nil:
+		return 0
+	}
+	f.err = fmt.Errorf("field %s: not a number", key)
+	return 0
+}
+
+func (f *jsonFields) extra() map[string]string {
+	extra := make(map[string]string, len(f.fields))
+	for key, raw := range f.fields {
+		extra[key] = string(raw)
+	}
+	return extra
+}
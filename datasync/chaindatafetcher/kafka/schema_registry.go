@@ -0,0 +1,136 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package kafka
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	schemaTypeAvro     = "AVRO"
+	schemaTypeProtobuf = "PROTOBUF"
+
+	schemaRegistryContentType = "application/vnd.schemaregistry.v1+json"
+	schemaRegistryTimeout     = 10 * time.Second
+)
+
+var (
+	errIncompatibleSchema = errors.New("the schema is not compatible with the latest version registered")
+	errSubjectNotFound    = errors.New("the subject is not found in the schema registry")
+)
+
+// schemaRegistry is a client of the Confluent Schema Registry. The schemas are
+// registered under the subjects named after the topics, and a new version of a
+// schema is registered only if it is compatible with the latest version by the
+// compatibility level of the subject.
+type schemaRegistry struct {
+	url    string
+	client *http.Client
+
+	mu  sync.Mutex
+	ids map[string]int // schema ids by the subject and the schema
+}
+
+func newSchemaRegistry(url string) *schemaRegistry {
+	return &schemaRegistry{
+		url:    strings.TrimSuffix(url, "/"),
+		client: &http.Client{Timeout: schemaRegistryTimeout},
+		ids:    make(map[string]int),
+	}
+}
+
+type schemaRequest struct {
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType"`
+}
+
+type schemaRegistryError struct {
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+// subjectNotFound is the error code of the schema registry for a subject which
+// does not exist.
+const subjectNotFound = 40401
+
+// register registers the schema under the subject if it is not registered yet,
+// and returns its id.
+func (r *schemaRegistry) register(subject, schemaType, schema string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id, ok := r.ids[subject+"\x00"+schema]; ok {
+		return id, nil
+	}
+	req := schemaRequest{Schema: schema, SchemaType: schemaType}
+
+	var compatibility struct {
+		IsCompatible bool `json:"is_compatible"`
+	}
+	err := r.post("/compatibility/subjects/"+url.PathEscape(subject)+"/versions/latest", req, &compatibility)
+	if err != nil && err != errSubjectNotFound {
+		return 0, err
+	}
+	if err == nil && !compatibility.IsCompatible {
+		return 0, fmt.Errorf("%w: subject %s", errIncompatibleSchema, subject)
+	}
+
+	var registered struct {
+		ID int `json:"id"`
+	}
+	if err := r.post("/subjects/"+url.PathEscape(subject)+"/versions", req, &registered); err != nil {
+		return 0, err
+	}
+	logger.Info("Registered a schema", "subject", subject, "type", schemaType, "id", registered.ID)
+	r.ids[subject+"\x00"+schema] = registered.ID
+	return registered.ID, nil
+}
+
+// post sends the request to the path and decodes the response.
+func (r *schemaRegistry) post(path string, req, resp interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	res, err := r.client.Post(r.url+path, schemaRegistryContentType, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		var regErr schemaRegistryError
+		if json.Unmarshal(data, &regErr) == nil && regErr.ErrorCode == subjectNotFound {
+			return errSubjectNotFound
+		}
+		return fmt.Errorf("schema registry responded %s: %s", res.Status, string(data))
+	}
+	return json.Unmarshal(data, resp)
+}
@@ -22,13 +22,29 @@ var (
 	blockGroupInsertionTimeGauge = metrics.NewRegisteredGauge("chaindatafetcher/insertion/time/blockgroup/gauge", nil)
 	traceGroupInsertionTimeGauge = metrics.NewRegisteredGauge("chaindatafetcher/insertion/time/tracegroup/gauge", nil)
 
+	internalTransferGroupInsertionTimeGauge = metrics.NewRegisteredGauge("chaindatafetcher/insertion/time/internaltransfergroup/gauge", nil)
+
 	blockGroupInsertionRetryGauge = metrics.NewRegisteredGauge("chaindatafetcher/insertion/retry/blockgroup/gauge", nil)
 	traceGroupInsertionRetryGauge = metrics.NewRegisteredGauge("chaindatafetcher/insertion/retry/tracegroup/gauge", nil)
 
+	internalTransferGroupInsertionRetryGauge = metrics.NewRegisteredGauge("chaindatafetcher/insertion/retry/internaltransfergroup/gauge", nil)
+
 	handledBlockNumberGauge = metrics.NewRegisteredGauge("chaindatafetcher/handle/blocknumber/gauge", nil)
 
 	numChainEventGauge = metrics.NewRegisteredGauge("chaindatafetcher/chainevent/gauge", nil)
 	numRequestsGauge   = metrics.NewRegisteredGauge("chaindatafetcher/requests/gauge", nil)
 
+	checkpointLagGauge     = metrics.NewRegisteredGauge("chaindatafetcher/checkpoint/lag/gauge", nil)
+	droppedBlocksCounter   = metrics.NewRegisteredCounter("chaindatafetcher/backpressure/dropped", nil)
+	refetchedBlocksCounter = metrics.NewRegisteredCounter("chaindatafetcher/backpressure/refetched", nil)
+
+	backfilledBlocksCounter = metrics.NewRegisteredCounter("chaindatafetcher/backfill/blocks", nil)
+
 	traceAPIErrorCounter = metrics.NewRegisteredCounter("chaindatafetcher/trace/error", nil)
+
+	filteredTxsCounter    = metrics.NewRegisteredCounter("chaindatafetcher/filter/txs", nil)
+	filteredBlocksCounter = metrics.NewRegisteredCounter("chaindatafetcher/filter/blocks", nil)
+
+	deadLetteredCounter = metrics.NewRegisteredCounter("chaindatafetcher/deadletter/added", nil)
+	deadLettersGauge    = metrics.NewRegisteredGauge("chaindatafetcher/deadletter/gauge", nil)
 )
@@ -0,0 +1,125 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	DefaultDBPort    = "5432"
+	DefaultSSLMode   = "disable"
+	DefaultSchema    = "public"
+	DefaultBatchSize = 1000
+)
+
+const (
+	TableBlocks       = "blocks"
+	TableTransactions = "transactions"
+	TableLogs         = "logs"
+	TableTraces       = "traces"
+
+	TableInternalTransfers = "internal_transfers"
+)
+
+// AllTables are the tables which can be loaded by the repository.
+var AllTables = []string{TableBlocks, TableTransactions, TableLogs, TableTraces, TableInternalTransfers}
+
+var errNoTables = errors.New("no table is enabled")
+
+type PostgresConfig struct {
+	DBHost     string
+	DBPort     string
+	DBName     string
+	DBUser     string
+	DBPassword string `json:"-"`
+	SSLMode    string
+
+	Schema      string   // Schema is the postgres schema in which the tables are created.
+	TablePrefix string   // TablePrefix is prepended to the names of the tables.
+	Tables      []string // Tables are the tables loaded, among blocks, transactions, logs, traces and internal_transfers.
+	BatchSize   int      // BatchSize is the maximum number of rows inserted by a statement.
+}
+
+func GetDefaultPostgresConfig() *PostgresConfig {
+	return &PostgresConfig{
+		DBPort:    DefaultDBPort,
+		SSLMode:   DefaultSSLMode,
+		Schema:    DefaultSchema,
+		Tables:    AllTables,
+		BatchSize: DefaultBatchSize,
+	}
+}
+
+// Validate checks that the tables are known and at least one is enabled.
+func (c *PostgresConfig) Validate() error {
+	if len(c.Tables) == 0 {
+		return errNoTables
+	}
+	for _, table := range c.Tables {
+		if !isKnownTable(table) {
+			return fmt.Errorf("unknown table %q (%s)", table, strings.Join(AllTables, ", "))
+		}
+	}
+	return nil
+}
+
+func (c *PostgresConfig) enabled(table string) bool {
+	for _, t := range c.Tables {
+		if t == table {
+			return true
+		}
+	}
+	return false
+}
+
+// tableName returns the quoted, schema-qualified name of the table.
+func (c *PostgresConfig) tableName(table string) string {
+	return quoteIdent(c.Schema) + "." + quoteIdent(c.TablePrefix+table)
+}
+
+func (c *PostgresConfig) dataSourceName() string {
+	u := &url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.DBUser, c.DBPassword),
+		Host:     c.DBHost + ":" + c.DBPort,
+		Path:     "/" + c.DBName,
+		RawQuery: url.Values{"sslmode": {c.SSLMode}}.Encode(),
+	}
+	return u.String()
+}
+
+func (c *PostgresConfig) String() string {
+	return fmt.Sprintf("host: %v, port: %v, name: %v, user: %v, sslmode: %v, schema: %v, tablePrefix: %v, tables: %v, batchSize: %v",
+		c.DBHost, c.DBPort, c.DBName, c.DBUser, c.SSLMode, c.Schema, c.TablePrefix, c.Tables, c.BatchSize)
+}
+
+func isKnownTable(table string) bool {
+	for _, t := range AllTables {
+		if t == table {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,28 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+/*
+Package postgres implements the repository of chaindatafetcher loading blocks, transactions, logs, traces and internal transfers into PostgreSQL
+Source Files
+  - config.go     : includes postgres configurations
+  - driver.go     : registers the postgres driver
+  - migration.go  : implements the migrations creating and upgrading the tables
+  - repository.go : implements the repository and the checkpoint database
+  - tables.go     : includes the table schemas and makes the rows of a chain event
+  - upsert.go     : implements the batched upserts of the rows
+*/
+
+package postgres
@@ -0,0 +1,243 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+)
+
+const (
+	migrationsTable = "schema_migrations"
+	metadataTable   = "fetcher_metadata"
+)
+
+// migration creates or upgrades a table. The migrations are applied in the order
+// of the versions, and each version is applied once. A migration of a table not
+// enabled is skipped, and applied when the table is enabled later. New changes
+// of the tables should be appended as new migrations, instead of modifying the
+// applied ones.
+type migration struct {
+	version     int
+	table       string // the table migrated, or empty for the migrations always applied
+	description string
+	statements  func(c *PostgresConfig) []string
+}
+
+var migrations = []migration{
+	{
+		version:     1,
+		description: "create the fetcher metadata table",
+		statements: func(c *PostgresConfig) []string {
+			return []string{
+				fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (key TEXT PRIMARY KEY, value BIGINT NOT NULL)`, c.tableName(metadataTable)),
+			}
+		},
+	},
+	{
+		version:     2,
+		table:       TableBlocks,
+		description: "create the blocks table",
+		statements: func(c *PostgresConfig) []string {
+			return []string{
+				fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	number BIGINT PRIMARY KEY,
+	hash TEXT NOT NULL,
+	parent_hash TEXT NOT NULL,
+	timestamp BIGINT NOT NULL,
+	rewardbase TEXT,
+	gas_used BIGINT NOT NULL,
+	size BIGINT NOT NULL,
+	tx_count INTEGER NOT NULL
+)`, c.tableName(TableBlocks)),
+				fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (hash)`, quoteIdent(c.TablePrefix+TableBlocks+"_hash_idx"), c.tableName(TableBlocks)),
+			}
+		},
+	},
+	{
+		version:     3,
+		table:       TableTransactions,
+		description: "create the transactions table",
+		statements: func(c *PostgresConfig) []string {
+			return []string{
+				fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	hash TEXT PRIMARY KEY,
+	block_number BIGINT NOT NULL,
+	tx_index INTEGER NOT NULL,
+	type_int INTEGER NOT NULL,
+	from_address TEXT,
+	to_address TEXT,
+	value NUMERIC,
+	gas BIGINT NOT NULL,
+	gas_price NUMERIC,
+	gas_used BIGINT NOT NULL,
+	status INTEGER NOT NULL,
+	contract_address TEXT,
+	input TEXT
+)`, c.tableName(TableTransactions)),
+				fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (block_number)`, quoteIdent(c.TablePrefix+TableTransactions+"_block_number_idx"), c.tableName(TableTransactions)),
+				fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (from_address)`, quoteIdent(c.TablePrefix+TableTransactions+"_from_address_idx"), c.tableName(TableTransactions)),
+				fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (to_address)`, quoteIdent(c.TablePrefix+TableTransactions+"_to_address_idx"), c.tableName(TableTransactions)),
+			}
+		},
+	},
+	{
+		version:     4,
+		table:       TableLogs,
+		description: "create the logs table",
+		statements: func(c *PostgresConfig) []string {
+			return []string{
+				fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	block_number BIGINT NOT NULL,
+	log_index INTEGER NOT NULL,
+	tx_hash TEXT NOT NULL,
+	tx_index INTEGER NOT NULL,
+	address TEXT NOT NULL,
+	topics TEXT[] NOT NULL,
+	data TEXT,
+	PRIMARY KEY (block_number, log_index)
+)`, c.tableName(TableLogs)),
+				fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (address, block_number)`, quoteIdent(c.TablePrefix+TableLogs+"_address_idx"), c.tableName(TableLogs)),
+				fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (tx_hash)`, quoteIdent(c.TablePrefix+TableLogs+"_tx_hash_idx"), c.tableName(TableLogs)),
+			}
+		},
+	},
+	{
+		version:     5,
+		table:       TableTraces,
+		description: "create the traces table",
+		statements: func(c *PostgresConfig) []string {
+			return []string{
+				fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	tx_hash TEXT NOT NULL,
+	trace_index INTEGER NOT NULL,
+	parent_index INTEGER,
+	depth INTEGER NOT NULL,
+	block_number BIGINT NOT NULL,
+	type TEXT NOT NULL,
+	from_address TEXT,
+	to_address TEXT,
+	value NUMERIC,
+	gas BIGINT NOT NULL,
+	gas_used BIGINT NOT NULL,
+	input TEXT,
+	output TEXT,
+	error TEXT,
+	PRIMARY KEY (tx_hash, trace_index)
+)`, c.tableName(TableTraces)),
+				fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (block_number)`, quoteIdent(c.TablePrefix+TableTraces+"_block_number_idx"), c.tableName(TableTraces)),
+			}
+		},
+	},
+	{
+		version:     6,
+		table:       TableInternalTransfers,
+		description: "create the internal transfers table",
+		statements: func(c *PostgresConfig) []string {
+			return []string{
+				fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	tx_hash TEXT NOT NULL,
+	trace_index INTEGER NOT NULL,
+	block_number BIGINT NOT NULL,
+	tx_index INTEGER NOT NULL,
+	depth INTEGER NOT NULL,
+	type TEXT NOT NULL,
+	from_address TEXT NOT NULL,
+	to_address TEXT NOT NULL,
+	value NUMERIC NOT NULL,
+	PRIMARY KEY (tx_hash, trace_index)
+)`, c.tableName(TableInternalTransfers)),
+				fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (from_address)`, quoteIdent(c.TablePrefix+TableInternalTransfers+"_from_address_idx"), c.tableName(TableInternalTransfers)),
+				fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (to_address)`, quoteIdent(c.TablePrefix+TableInternalTransfers+"_to_address_idx"), c.tableName(TableInternalTransfers)),
+			}
+		},
+	},
+}
+
+// pendingMigrations returns the migrations to be applied, which are not applied
+// yet and not of a disabled table.
+func pendingMigrations(c *PostgresConfig, applied map[int]bool) []migration {
+	var pending []migration
+	for _, m := range migrations {
+		if applied[m.version] || (m.table != "" && !c.enabled(m.table)) {
+			continue
+		}
+		pending = append(pending, m)
+	}
+	return pending
+}
+
+// migrationLockID returns the key of the advisory lock taken while migrating,
+// so that the fetchers sharing the tables do not migrate at the same time.
+func migrationLockID(c *PostgresConfig) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(c.tableName(migrationsTable)))
+	return int64(h.Sum64())
+}
+
+// migrate applies the pending migrations in a transaction, and records them in
+// the migrations table.
+func migrate(db *sql.DB, c *PostgresConfig) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, migrationLockID(c)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS %s`, quoteIdent(c.Schema))); err != nil {
+		return err
+	}
+	migrationsTableName := c.tableName(migrationsTable)
+	if _, err := tx.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (version INTEGER PRIMARY KEY, description TEXT NOT NULL, applied_at TIMESTAMPTZ NOT NULL DEFAULT now())`, migrationsTableName)); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(fmt.Sprintf(`SELECT version FROM %s`, migrationsTableName))
+	if err != nil {
+		return err
+	}
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			rows.Close()
+			return err
+		}
+		applied[version] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, m := range pendingMigrations(c, applied) {
+		for _, stmt := range m.statements(c) {
+			if _, err := tx.Exec(stmt); err != nil {
+				return fmt.Errorf("migration %d (%s) is failed: %w", m.version, m.description, err)
+			}
+		}
+		if _, err := tx.Exec(fmt.Sprintf(`INSERT INTO %s (version, description) VALUES ($1, $2)`, migrationsTableName), m.version, m.description); err != nil {
+			return err
+		}
+		logger.Info("Applied a chaindatafetcher postgres migration", "version", m.version, "description", m.description)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,141 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/datasync/chaindatafetcher/types"
+	"github.com/klaytn/klaytn/log"
+)
+
+const (
+	driverName = "postgres"
+
+	maxOpenConnection = 100
+	maxIdleConnection = 10
+	connMaxLifetime   = 24 * time.Hour
+	maxDBRetryCount   = 20
+	DBRetryInterval   = 1 * time.Second
+
+	checkpointKey = "checkpoint"
+)
+
+var logger = log.NewModuleLogger(log.ChainDataFetcher)
+
+type repository struct {
+	db     *sql.DB
+	config *PostgresConfig
+}
+
+func NewRepository(config *PostgresConfig) (*repository, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(driverName, config.dataSourceName())
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < maxDBRetryCount; i++ {
+		if err = db.Ping(); err == nil {
+			break
+		}
+		logger.Warn("Retrying to connect DB", "host", config.DBHost, "port", config.DBPort, "name", config.DBName, "err", err)
+		time.Sleep(DBRetryInterval)
+	}
+	if err != nil {
+		logger.Error("Failed to connect to the database", "host", config.DBHost, "port", config.DBPort, "name", config.DBName, "err", err)
+		db.Close()
+		return nil, err
+	}
+	db.SetMaxOpenConns(maxOpenConnection)
+	db.SetMaxIdleConns(maxIdleConnection)
+	db.SetConnMaxLifetime(connMaxLifetime)
+
+	if err := migrate(db, config); err != nil {
+		logger.Error("Failed to migrate the database", "err", err)
+		db.Close()
+		return nil, err
+	}
+	return &repository{db: db, config: config}, nil
+}
+
+func (r *repository) HandleChainEvent(event blockchain.ChainEvent, reqType types.RequestType) error {
+	switch reqType {
+	case types.RequestTypeBlockGroup:
+		return r.upsertTables(event, TableBlocks, TableTransactions, TableLogs)
+	case types.RequestTypeTraceGroup:
+		return r.upsertTables(event, TableTraces)
+	case types.RequestTypeInternalTransferGroup:
+		return r.upsertTables(event, TableInternalTransfers)
+	default:
+		return fmt.Errorf("not supported type. [blockNumber: %v, reqType: %v]", event.Block.NumberU64(), reqType)
+	}
+}
+
+// upsertTables upserts the rows of the enabled tables in a transaction, so the
+// rows of a block are loaded all together or not at all.
+func (r *repository) upsertTables(event blockchain.ChainEvent, tables ...string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range tables {
+		if !r.config.enabled(table) {
+			continue
+		}
+		var rows [][]interface{}
+		switch table {
+		case TableBlocks:
+			rows = blockRows(event)
+		case TableTransactions:
+			rows = transactionRows(event)
+		case TableLogs:
+			rows = logRows(event)
+		case TableTraces:
+			rows = traceRows(event)
+		case TableInternalTransfers:
+			rows = internalTransferRows(event)
+		}
+		if err := upsertRows(tx, r.config.tableName(table), tableSchemas[table], rows, r.config.BatchSize); err != nil {
+			logger.Error("Failed to upsert the rows", "table", table, "blockNumber", event.Block.NumberU64(), "numRows", len(rows), "err", err)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *repository) ReadCheckpoint() (int64, error) {
+	var checkpoint int64
+	err := r.db.QueryRow(fmt.Sprintf(`SELECT value FROM %s WHERE key = $1`, r.config.tableName(metadataTable)), checkpointKey).Scan(&checkpoint)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return checkpoint, err
+}
+
+func (r *repository) WriteCheckpoint(checkpoint int64) error {
+	_, err := r.db.Exec(fmt.Sprintf(`INSERT INTO %s (key, value) VALUES ($1, $2) ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value`,
+		r.config.tableName(metadataTable)), checkpointKey, checkpoint)
+	return err
+}
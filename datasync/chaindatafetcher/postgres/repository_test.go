@@ -0,0 +1,107 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package postgres
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpsertStatement(t *testing.T) {
+	schema := &tableSchema{name: "logs", columns: []string{"block_number", "log_index", "data"}, primaryKey: []string{"block_number", "log_index"}}
+	c := &PostgresConfig{Schema: "public", TablePrefix: "cypress_"}
+
+	expected := `INSERT INTO "public"."cypress_logs" (block_number, log_index, data) VALUES ($1, $2, $3), ($4, $5, $6) ` +
+		`ON CONFLICT (block_number, log_index) DO UPDATE SET data = EXCLUDED.data`
+	assert.Equal(t, expected, upsertStatement(c.tableName("logs"), schema, 2))
+}
+
+func TestBatchRows(t *testing.T) {
+	rows := make([][]interface{}, 5)
+	assert.Len(t, batchRows(rows, 3, 2), 3)
+	assert.Len(t, batchRows(rows, 3, 10), 1)
+	assert.Len(t, batchRows(nil, 3, 10), 0)
+
+	// The batch size is bound by the maximum number of the parameters.
+	rows = make([][]interface{}, 10*(maxPlaceholders/10))
+	batches := batchRows(rows, 10, 0)
+	assert.Len(t, batches, 10)
+	assert.Len(t, batches[0], maxPlaceholders/10)
+}
+
+func TestPendingMigrations(t *testing.T) {
+	c := GetDefaultPostgresConfig()
+	c.Tables = []string{TableBlocks, TableLogs}
+
+	var versions []int
+	for _, m := range pendingMigrations(c, map[int]bool{1: true}) {
+		versions = append(versions, m.version)
+	}
+	assert.Equal(t, []int{2, 4}, versions)
+
+	// The versions are unique and increasing.
+	for i := 1; i < len(migrations); i++ {
+		assert.Greater(t, migrations[i].version, migrations[i-1].version)
+	}
+}
+
+func TestPostgresConfig_Validate(t *testing.T) {
+	c := GetDefaultPostgresConfig()
+	assert.NoError(t, c.Validate())
+
+	c.Tables = nil
+	assert.Equal(t, errNoTables, c.Validate())
+
+	c.Tables = []string{TableBlocks, "receipts"}
+	assert.Error(t, c.Validate())
+}
+
+func TestTraceRows(t *testing.T) {
+	from := common.HexToAddress("0x01")
+	tx := types.NewTransaction(0, common.HexToAddress("0x02"), big.NewInt(1), 21000, big.NewInt(1), nil)
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(10)}).WithBody(types.Transactions{tx})
+	event := blockchain.ChainEvent{
+		Block: block,
+		InternalTxTraces: []*vm.InternalTxTrace{{
+			Type: "CALL", From: &from, Value: "0xa",
+			Calls: []*vm.InternalTxTrace{
+				{Type: "STATICCALL", Calls: []*vm.InternalTxTrace{{Type: "CALL"}}},
+				{Type: "DELEGATECALL"},
+			},
+		}},
+	}
+
+	rows := traceRows(event)
+	assert.Len(t, rows, 4)
+	for _, row := range rows {
+		assert.Len(t, row, len(tableSchemas[TableTraces].columns))
+	}
+	// trace_index, parent_index and depth in the depth-first order
+	assert.Equal(t, []interface{}{0, nil, 0}, rows[0][1:4])
+	assert.Equal(t, []interface{}{1, 0, 1}, rows[1][1:4])
+	assert.Equal(t, []interface{}{2, 1, 2}, rows[2][1:4])
+	assert.Equal(t, []interface{}{3, 0, 1}, rows[3][1:4])
+	assert.Equal(t, "10", rows[0][8])
+	assert.Equal(t, "0x0000000000000000000000000000000000000001", rows[0][6])
+	assert.Nil(t, rows[1][8])
+}
@@ -0,0 +1,203 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package postgres
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	cfTypes "github.com/klaytn/klaytn/datasync/chaindatafetcher/types"
+)
+
+// tableSchema is the schema of a table, whose rows are upserted by the primary
+// key. The columns are created by the migrations.
+type tableSchema struct {
+	name       string
+	columns    []string
+	primaryKey []string
+}
+
+var tableSchemas = map[string]*tableSchema{
+	TableBlocks: {
+		name:       TableBlocks,
+		columns:    []string{"number", "hash", "parent_hash", "timestamp", "rewardbase", "gas_used", "size", "tx_count"},
+		primaryKey: []string{"number"},
+	},
+	TableTransactions: {
+		name: TableTransactions,
+		columns: []string{
+			"hash", "block_number", "tx_index", "type_int", "from_address", "to_address", "value",
+			"gas", "gas_price", "gas_used", "status", "contract_address", "input",
+		},
+		primaryKey: []string{"hash"},
+	},
+	TableLogs: {
+		name:       TableLogs,
+		columns:    []string{"block_number", "log_index", "tx_hash", "tx_index", "address", "topics", "data"},
+		primaryKey: []string{"block_number", "log_index"},
+	},
+	TableTraces: {
+		name: TableTraces,
+		columns: []string{
+			"tx_hash", "trace_index", "parent_index", "depth", "block_number", "type", "from_address", "to_address",
+			"value", "gas", "gas_used", "input", "output", "error",
+		},
+		primaryKey: []string{"tx_hash", "trace_index"},
+	},
+	TableInternalTransfers: {
+		name:       TableInternalTransfers,
+		columns:    []string{"tx_hash", "trace_index", "block_number", "tx_index", "depth", "type", "from_address", "to_address", "value"},
+		primaryKey: []string{"tx_hash", "trace_index"},
+	},
+}
+
+// address returns the hex of the address, or nil to be stored as NULL.
+func address(addr *common.Address) interface{} {
+	if addr == nil {
+		return nil
+	}
+	return strings.ToLower(addr.Hex())
+}
+
+func hexBytes(b []byte) string {
+	return hexutil.Encode(b)
+}
+
+// numeric returns the decimal string of the number for the NUMERIC columns.
+func numeric(n *big.Int) interface{} {
+	if n == nil {
+		return nil
+	}
+	return n.String()
+}
+
+// textArray returns the array literal of the strings for the TEXT[] columns.
+func textArray(items []string) string {
+	return "{" + strings.Join(items, ",") + "}"
+}
+
+func blockRows(event blockchain.ChainEvent) [][]interface{} {
+	block := event.Block
+	header := block.Header()
+	return [][]interface{}{{
+		header.Number.Int64(), hexBytes(block.Hash().Bytes()), hexBytes(header.ParentHash.Bytes()), header.Time.Int64(),
+		address(&header.Rewardbase), int64(header.GasUsed), int64(block.Size()), len(block.Transactions()),
+	}}
+}
+
+func transactionRows(event blockchain.ChainEvent) [][]interface{} {
+	block := event.Block
+	rows := make([][]interface{}, 0, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		var from common.Address
+		if tx.IsEthereumTransaction() {
+			signer := types.LatestSignerForChainID(tx.ChainId())
+			from, _ = types.Sender(signer, tx)
+		} else {
+			from, _ = tx.From()
+		}
+		var (
+			gasUsed         uint64
+			status          uint
+			contractAddress interface{}
+		)
+		if i < len(event.Receipts) {
+			receipt := event.Receipts[i]
+			gasUsed, status = receipt.GasUsed, receipt.Status
+			if tx.To() == nil {
+				contractAddress = address(&receipt.ContractAddress)
+			}
+		}
+		rows = append(rows, []interface{}{
+			hexBytes(tx.Hash().Bytes()), block.Number().Int64(), i, int(tx.Type()), address(&from), address(tx.To()), numeric(tx.Value()),
+			int64(tx.Gas()), numeric(tx.GasPrice()), int64(gasUsed), int(status), contractAddress, hexBytes(tx.Data()),
+		})
+	}
+	return rows
+}
+
+func logRows(event blockchain.ChainEvent) [][]interface{} {
+	rows := make([][]interface{}, 0, len(event.Logs))
+	for _, log := range event.Logs {
+		topics := make([]string, len(log.Topics))
+		for i, topic := range log.Topics {
+			topics[i] = hexBytes(topic.Bytes())
+		}
+		rows = append(rows, []interface{}{
+			int64(log.BlockNumber), int(log.Index), hexBytes(log.TxHash.Bytes()), int(log.TxIndex),
+			address(&log.Address), textArray(topics), hexBytes(log.Data),
+		})
+	}
+	return rows
+}
+
+// traceRows flattens the internal transaction traces in the depth-first order.
+// The traces of the transactions are in the order of the transactions.
+func traceRows(event blockchain.ChainEvent) [][]interface{} {
+	var rows [][]interface{}
+	txs := event.Block.Transactions()
+	for i, trace := range event.InternalTxTraces {
+		if i >= len(txs) {
+			break
+		}
+		traceIndex := 0
+		rows = appendTraceRows(rows, event.Block.Number().Int64(), hexBytes(txs[i].Hash().Bytes()), trace, -1, 0, &traceIndex)
+	}
+	return rows
+}
+
+func appendTraceRows(rows [][]interface{}, blockNumber int64, txHash string, trace *vm.InternalTxTrace, parent, depth int, traceIndex *int) [][]interface{} {
+	index := *traceIndex
+	*traceIndex++
+
+	var parentIndex, errStr interface{}
+	if parent >= 0 {
+		parentIndex = parent
+	}
+	if trace.Error != nil {
+		errStr = trace.Error.Error()
+	}
+	var value interface{}
+	if v, err := hexutil.DecodeBig(trace.Value); err == nil {
+		value = v.String()
+	}
+	rows = append(rows, []interface{}{
+		txHash, index, parentIndex, depth, blockNumber, trace.Type, address(trace.From), address(trace.To),
+		value, int64(trace.Gas), int64(trace.GasUsed), trace.Input, trace.Output, errStr,
+	})
+	for _, call := range trace.Calls {
+		rows = appendTraceRows(rows, blockNumber, txHash, call, index, depth+1, traceIndex)
+	}
+	return rows
+}
+
+func internalTransferRows(event blockchain.ChainEvent) [][]interface{} {
+	transfers := cfTypes.InternalTransfers(event.Block.Transactions(), event.InternalTxTraces)
+	rows := make([][]interface{}, 0, len(transfers))
+	for _, t := range transfers {
+		rows = append(rows, []interface{}{
+			hexBytes(t.TransactionHash.Bytes()), t.TraceIndex, event.Block.Number().Int64(), t.TransactionIndex, t.Depth,
+			t.Type, address(&t.From), address(&t.To), numeric(t.Value.ToInt()),
+		})
+	}
+	return rows
+}
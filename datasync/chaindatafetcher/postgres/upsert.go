@@ -0,0 +1,110 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package postgres
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// maxPlaceholders is the maximum number of the parameters of a statement.
+const maxPlaceholders = 65535
+
+// upsertStatement returns the statement inserting the given number of rows into
+// the table, which updates the existing rows of the same primary key.
+func upsertStatement(tableName string, schema *tableSchema, numRows int) string {
+	var b strings.Builder
+	b.WriteString("INSERT INTO ")
+	b.WriteString(tableName)
+	b.WriteString(" (")
+	b.WriteString(strings.Join(schema.columns, ", "))
+	b.WriteString(") VALUES ")
+
+	param := 1
+	for i := 0; i < numRows; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for j := range schema.columns {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(param))
+			param++
+		}
+		b.WriteByte(')')
+	}
+
+	b.WriteString(" ON CONFLICT (")
+	b.WriteString(strings.Join(schema.primaryKey, ", "))
+	b.WriteString(") DO UPDATE SET ")
+	first := true
+	for _, column := range schema.columns {
+		if isPrimaryKey(schema, column) {
+			continue
+		}
+		if !first {
+			b.WriteString(", ")
+		}
+		first = false
+		b.WriteString(column + " = EXCLUDED." + column)
+	}
+	return b.String()
+}
+
+func isPrimaryKey(schema *tableSchema, column string) bool {
+	for _, key := range schema.primaryKey {
+		if key == column {
+			return true
+		}
+	}
+	return false
+}
+
+// batchRows splits the rows into the batches of at most batchSize rows, which
+// are bound by the maximum number of the parameters.
+func batchRows(rows [][]interface{}, numColumns, batchSize int) [][][]interface{} {
+	if max := maxPlaceholders / numColumns; batchSize <= 0 || batchSize > max {
+		batchSize = max
+	}
+	var batches [][][]interface{}
+	for len(rows) > batchSize {
+		batches = append(batches, rows[:batchSize])
+		rows = rows[batchSize:]
+	}
+	if len(rows) > 0 {
+		batches = append(batches, rows)
+	}
+	return batches
+}
+
+// upsertRows upserts the rows into the table in batches.
+func upsertRows(tx *sql.Tx, tableName string, schema *tableSchema, rows [][]interface{}, batchSize int) error {
+	for _, batch := range batchRows(rows, len(schema.columns), batchSize) {
+		args := make([]interface{}, 0, len(batch)*len(schema.columns))
+		for _, row := range batch {
+			args = append(args, row...)
+		}
+		if _, err := tx.Exec(upsertStatement(tableName, schema, len(batch)), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package chaindatafetcher
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	cfTypes "github.com/klaytn/klaytn/datasync/chaindatafetcher/types"
+)
+
+const (
+	ReprocessPending  = "pending"
+	ReprocessRunning  = "running"
+	ReprocessDone     = "done"
+	ReprocessFailed   = "failed"
+	ReprocessCanceled = "canceled"
+)
+
+// maxReprocessHistory is the number of the finished ranges kept for the status.
+const maxReprocessHistory = 100
+
+var (
+	errReprocessNotFound     = errors.New("the reprocessing request is not found")
+	errReprocessFinished     = errors.New("the reprocessing request is already finished")
+	errInvalidReprocessRange = errors.New("invalid block range to reprocess")
+)
+
+// ReprocessStatus is the status of a block range requested to be reprocessed.
+// The blocks are exported again without updating the checkpoint.
+type ReprocessStatus struct {
+	ID         uint64              `json:"id"`
+	StartBlock uint64              `json:"startBlock"`
+	EndBlock   uint64              `json:"endBlock"`
+	ReqType    cfTypes.RequestType `json:"reqType"`
+	State      string              `json:"state"`
+	Processed  uint64              `json:"processed"` // number of the blocks exported
+	Error      string              `json:"error,omitempty"`
+	ErrorBlock *uint64             `json:"errorBlock,omitempty"`
+	CreatedAt  time.Time           `json:"createdAt"`
+	StartedAt  *time.Time          `json:"startedAt,omitempty"`
+	FinishedAt *time.Time          `json:"finishedAt,omitempty"`
+}
+
+func (s *ReprocessStatus) finished() bool {
+	return s.State == ReprocessDone || s.State == ReprocessFailed || s.State == ReprocessCanceled
+}
+
+// reprocessQueue keeps the reprocessing requests, which are processed one by one
+// in the order of the requests.
+type reprocessQueue struct {
+	mu     sync.Mutex
+	nextID uint64
+	ranges []*ReprocessStatus
+	wakeCh chan struct{}
+}
+
+func newReprocessQueue() *reprocessQueue {
+	return &reprocessQueue{nextID: 1, wakeCh: make(chan struct{}, 1)}
+}
+
+func (q *reprocessQueue) push(start, end uint64, reqType cfTypes.RequestType) uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	id := q.nextID
+	q.nextID++
+	q.ranges = append(q.ranges, &ReprocessStatus{
+		ID:         id,
+		StartBlock: start,
+		EndBlock:   end,
+		ReqType:    reqType,
+		State:      ReprocessPending,
+		CreatedAt:  time.Now(),
+	})
+
+	select {
+	case q.wakeCh <- struct{}{}:
+	default:
+	}
+	return id
+}
+
+// trim drops the oldest finished ranges over the history limit.
+func (q *reprocessQueue) trim() {
+	numFinished := 0
+	for _, s := range q.ranges {
+		if s.finished() {
+			numFinished++
+		}
+	}
+	ranges := q.ranges[:0]
+	for _, s := range q.ranges {
+		if s.finished() && numFinished > maxReprocessHistory {
+			numFinished--
+			continue
+		}
+		ranges = append(ranges, s)
+	}
+	q.ranges = ranges
+}
+
+// next marks the first pending range as running and returns its copy.
+func (q *reprocessQueue) next() (ReprocessStatus, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, s := range q.ranges {
+		if s.State == ReprocessPending {
+			now := time.Now()
+			s.State, s.StartedAt = ReprocessRunning, &now
+			return *s, true
+		}
+	}
+	return ReprocessStatus{}, false
+}
+
+// progress records the block exported, and returns false if the range is
+// canceled.
+func (q *reprocessQueue) progress(id uint64) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	s := q.find(id)
+	if s == nil || s.State != ReprocessRunning {
+		return false
+	}
+	s.Processed++
+	return true
+}
+
+func (q *reprocessQueue) finish(id uint64, errBlock uint64, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	s := q.find(id)
+	if s == nil || s.finished() {
+		return
+	}
+	now := time.Now()
+	s.FinishedAt = &now
+	if err != nil {
+		s.State, s.Error, s.ErrorBlock = ReprocessFailed, err.Error(), &errBlock
+	} else {
+		s.State = ReprocessDone
+	}
+	q.trim()
+}
+
+func (q *reprocessQueue) cancel(id uint64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	s := q.find(id)
+	if s == nil {
+		return errReprocessNotFound
+	}
+	if s.finished() {
+		return errReprocessFinished
+	}
+	now := time.Now()
+	s.State, s.FinishedAt = ReprocessCanceled, &now
+	q.trim()
+	return nil
+}
+
+func (q *reprocessQueue) status(id uint64) (*ReprocessStatus, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	s := q.find(id)
+	if s == nil {
+		return nil, errReprocessNotFound
+	}
+	cpy := *s
+	return &cpy, nil
+}
+
+func (q *reprocessQueue) statuses() []ReprocessStatus {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	statuses := make([]ReprocessStatus, len(q.ranges))
+	for i, s := range q.ranges {
+		statuses[i] = *s
+	}
+	return statuses
+}
+
+func (q *reprocessQueue) find(id uint64) *ReprocessStatus {
+	for _, s := range q.ranges {
+		if s.ID == id {
+			return s
+		}
+	}
+	return nil
+}
+
+// defaultRequestType returns the types of the data exported by the mode.
+func (f *ChainDataFetcher) defaultRequestType() cfTypes.RequestType {
+	if f.config.Mode == ModeKAS {
+		return cfTypes.RequestTypeAll
+	}
+	return cfTypes.RequestTypeGroupAll
+}
+
+// requestReprocessing queues the block range to be exported again. The default
+// types of the mode are exported if reqType is 0.
+func (f *ChainDataFetcher) requestReprocessing(start, end uint64, reqType cfTypes.RequestType) (uint64, error) {
+	if start > end {
+		return 0, fmt.Errorf("%w: start %d is greater than end %d", errInvalidReprocessRange, start, end)
+	}
+	if head := f.blockchain.CurrentHeader().Number.Uint64(); end > head {
+		return 0, fmt.Errorf("%w: end %d is greater than the current block %d", errInvalidReprocessRange, end, head)
+	}
+	if reqType == 0 {
+		reqType = f.defaultRequestType()
+	}
+	id := f.reprocessQueue.push(start, end, reqType)
+	logger.Info("reprocessing is requested", "id", id, "startBlock", start, "endBlock", end, "reqType", reqType)
+	return id, nil
+}
+
+// reprocessLoop exports the requested ranges one by one until the fetcher is
+// stopped. A range is stopped at the first block failed to be exported.
+func (f *ChainDataFetcher) reprocessLoop() {
+	defer f.wg.Done()
+	for {
+		s, ok := f.reprocessQueue.next()
+		if !ok {
+			select {
+			case <-f.stopCh:
+				return
+			case <-f.reprocessQueue.wakeCh:
+				continue
+			}
+		}
+
+		logger.Info("reprocessing is started", "id", s.ID, "startBlock", s.StartBlock, "endBlock", s.EndBlock)
+		var (
+			errBlock uint64
+			err      error
+		)
+		for num := s.StartBlock; num <= s.EndBlock; num++ {
+			select {
+			case <-f.stopCh:
+				f.reprocessQueue.finish(s.ID, num, errors.New("the chaindatafetcher is stopped"))
+				return
+			default:
+			}
+			ev, evErr := f.makeChainEvent(num)
+			if evErr == nil {
+				evErr = f.handleRequestByType(s.ReqType, false, ev)
+			}
+			if evErr != nil {
+				errBlock, err = num, evErr
+				break
+			}
+			if !f.reprocessQueue.progress(s.ID) {
+				break // canceled
+			}
+		}
+		f.reprocessQueue.finish(s.ID, errBlock, err)
+		logger.Info("reprocessing is finished", "id", s.ID, "startBlock", s.StartBlock, "endBlock", s.EndBlock, "err", err)
+	}
+}
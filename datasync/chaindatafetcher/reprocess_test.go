@@ -0,0 +1,118 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package chaindatafetcher
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/datasync/chaindatafetcher/mocks"
+	cfTypes "github.com/klaytn/klaytn/datasync/chaindatafetcher/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReprocessQueue(t *testing.T) {
+	q := newReprocessQueue()
+	id1 := q.push(1, 10, cfTypes.RequestTypeBlockGroup)
+	id2 := q.push(11, 20, cfTypes.RequestTypeBlockGroup)
+
+	s, ok := q.next()
+	assert.True(t, ok)
+	assert.Equal(t, id1, s.ID)
+	assert.True(t, q.progress(id1))
+	q.finish(id1, 0, nil)
+
+	status, err := q.status(id1)
+	assert.NoError(t, err)
+	assert.Equal(t, ReprocessDone, status.State)
+	assert.Equal(t, uint64(1), status.Processed)
+	assert.Equal(t, errReprocessFinished, q.cancel(id1))
+
+	s, ok = q.next()
+	assert.True(t, ok)
+	assert.Equal(t, id2, s.ID)
+	assert.NoError(t, q.cancel(id2))
+	assert.False(t, q.progress(id2))
+	q.finish(id2, 11, errors.New("test-error")) // The canceled one is not failed.
+
+	status, _ = q.status(id2)
+	assert.Equal(t, ReprocessCanceled, status.State)
+	_, ok = q.next()
+	assert.False(t, ok)
+
+	_, err = q.status(100)
+	assert.Equal(t, errReprocessNotFound, err)
+
+	// The oldest finished ones are dropped over the history limit.
+	for i := 0; i < maxReprocessHistory; i++ {
+		id := q.push(1, 1, cfTypes.RequestTypeBlockGroup)
+		q.next()
+		q.finish(id, 0, nil)
+	}
+	statuses := q.statuses()
+	assert.Len(t, statuses, maxReprocessHistory)
+	assert.Equal(t, id2+1, statuses[0].ID)
+}
+
+func TestChainDataFetcher_Reprocess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	bc := mocks.NewMockBlockChain(ctrl)
+	repo := mocks.NewMockRepository(ctrl)
+	fetcher := newTestChainDataFetcher()
+	fetcher.config = &ChainDataFetcherConfig{Mode: ModeKafka}
+	fetcher.blockchain, fetcher.repo = bc, repo
+
+	bc.EXPECT().CurrentHeader().Return(&types.Header{Number: big.NewInt(10)}).AnyTimes()
+	bc.EXPECT().GetBlockByNumber(gomock.Any()).DoAndReturn(func(num uint64) *types.Block {
+		return types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(num)})
+	}).AnyTimes()
+	bc.EXPECT().GetReceiptsByBlockHash(gomock.Any()).Return(types.Receipts{}).AnyTimes()
+	repo.EXPECT().HandleChainEvent(gomock.Any(), cfTypes.RequestTypeBlockGroup).Return(nil).Times(3)
+	repo.EXPECT().HandleChainEvent(gomock.Any(), cfTypes.RequestTypeTraceGroup).Return(nil).Times(3)
+	repo.EXPECT().HandleChainEvent(gomock.Any(), cfTypes.RequestTypeInternalTransferGroup).Return(nil).Times(3)
+
+	_, err := fetcher.requestReprocessing(5, 4, 0)
+	assert.True(t, errors.Is(err, errInvalidReprocessRange))
+	_, err = fetcher.requestReprocessing(5, 11, 0)
+	assert.True(t, errors.Is(err, errInvalidReprocessRange))
+
+	id, err := fetcher.requestReprocessing(3, 5, 0)
+	assert.NoError(t, err)
+
+	fetcher.wg.Add(1)
+	go fetcher.reprocessLoop()
+	defer func() {
+		close(fetcher.stopCh)
+		fetcher.wg.Wait()
+	}()
+
+	assert.Eventually(t, func() bool {
+		status, _ := fetcher.reprocessQueue.status(id)
+		return status.State == ReprocessDone
+	}, 3*time.Second, 10*time.Millisecond)
+
+	status, _ := fetcher.reprocessQueue.status(id)
+	assert.Equal(t, uint64(3), status.Processed)
+	assert.Equal(t, cfTypes.RequestTypeGroupAll, status.ReqType)
+	assert.Equal(t, int64(0), fetcher.checkpoint) // The checkpoint is not updated.
+}
@@ -0,0 +1,98 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"math/big"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+)
+
+// InternalTransfer is a value transfer or a contract creation made by a call
+// inside a transaction, which is not shown by the transaction itself.
+type InternalTransfer struct {
+	TransactionHash  common.Hash    `json:"transactionHash"`
+	TransactionIndex int            `json:"transactionIndex"`
+	TraceIndex       int            `json:"traceIndex"` // index of the call in the depth-first order of the trace
+	Depth            int            `json:"depth"`
+	Type             string         `json:"type"`
+	From             common.Address `json:"from"`
+	To               common.Address `json:"to"`
+	Value            *hexutil.Big   `json:"value"`
+}
+
+// InternalTransfers returns the internal transfers of the transactions from
+// their call traces, which are in the order of the transactions. The top-level
+// calls are the transactions themselves, and the calls failed or reverted by a
+// parent call are excluded, since they transfer nothing.
+func InternalTransfers(txs types.Transactions, traces []*vm.InternalTxTrace) []*InternalTransfer {
+	var transfers []*InternalTransfer
+	for i, trace := range traces {
+		if i >= len(txs) || trace == nil || trace.Error != nil {
+			continue
+		}
+		traceIndex := 0
+		for _, call := range trace.Calls {
+			traceIndex++
+			transfers = appendInternalTransfers(transfers, txs[i].Hash(), i, call, 1, &traceIndex)
+		}
+	}
+	return transfers
+}
+
+func appendInternalTransfers(transfers []*InternalTransfer, txHash common.Hash, txIndex int, trace *vm.InternalTxTrace, depth int, traceIndex *int) []*InternalTransfer {
+	index := *traceIndex
+	if trace.Error != nil || trace.Reverted != nil {
+		// the indexes of the descendants are counted even if they are excluded
+		*traceIndex += countCalls(trace.Calls)
+		return transfers
+	}
+
+	value, err := hexutil.DecodeBig(trace.Value)
+	if err != nil {
+		value = new(big.Int)
+	}
+	isCreation := trace.Type == "CREATE" || trace.Type == "CREATE2"
+	if (value.Sign() > 0 || isCreation) && trace.From != nil && trace.To != nil {
+		transfers = append(transfers, &InternalTransfer{
+			TransactionHash:  txHash,
+			TransactionIndex: txIndex,
+			TraceIndex:       index,
+			Depth:            depth,
+			Type:             trace.Type,
+			From:             *trace.From,
+			To:               *trace.To,
+			Value:            (*hexutil.Big)(value),
+		})
+	}
+	for _, call := range trace.Calls {
+		*traceIndex++
+		transfers = appendInternalTransfers(transfers, txHash, txIndex, call, depth+1, traceIndex)
+	}
+	return transfers
+}
+
+func countCalls(calls []*vm.InternalTxTrace) int {
+	n := len(calls)
+	for _, call := range calls {
+		n += countCalls(call.Calls)
+	}
+	return n
+}
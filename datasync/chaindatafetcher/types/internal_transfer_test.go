@@ -0,0 +1,65 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInternalTransfers(t *testing.T) {
+	a, b, c := common.HexToAddress("0x0a"), common.HexToAddress("0x0b"), common.HexToAddress("0x0c")
+	tx := types.NewTransaction(0, b, big.NewInt(1), 21000, big.NewInt(1), nil)
+	trace := &vm.InternalTxTrace{
+		Type: "CALL", From: &a, To: &b, Value: "0x1",
+		Calls: []*vm.InternalTxTrace{
+			{Type: "CALL", From: &b, To: &c, Value: "0x0"}, // no value
+			{
+				Type: "CALL", From: &b, To: &c, Value: "0x2", Error: errors.New("execution reverted"),
+				Calls: []*vm.InternalTxTrace{{Type: "CALL", From: &c, To: &a, Value: "0x3"}}, // reverted by the parent
+			},
+			{
+				Type: "CREATE", From: &b, To: &c, Value: "0x0",
+				Calls: []*vm.InternalTxTrace{{Type: "CALL", From: &c, To: &a, Value: "0x4"}},
+			},
+		},
+	}
+
+	transfers := InternalTransfers(types.Transactions{tx}, []*vm.InternalTxTrace{trace})
+	assert.Len(t, transfers, 2)
+
+	assert.Equal(t, "CREATE", transfers[0].Type)
+	assert.Equal(t, 4, transfers[0].TraceIndex)
+	assert.Equal(t, 1, transfers[0].Depth)
+	assert.Equal(t, tx.Hash(), transfers[0].TransactionHash)
+
+	assert.Equal(t, 5, transfers[1].TraceIndex)
+	assert.Equal(t, 2, transfers[1].Depth)
+	assert.Equal(t, c, transfers[1].From)
+	assert.Equal(t, a, transfers[1].To)
+	assert.Equal(t, big.NewInt(4), transfers[1].Value.ToInt())
+
+	// The transaction failed transfers nothing.
+	trace.Error = errors.New("execution reverted")
+	assert.Len(t, InternalTransfers(types.Transactions{tx}, []*vm.InternalTxTrace{trace}), 0)
+}
@@ -16,6 +16,13 @@
 
 package types
 
+import "errors"
+
+// ErrPoisonRecord is wrapped by the errors of the data which can never be
+// exported as it is (e.g. failed to be encoded or too large), so that it is
+// not retried.
+var ErrPoisonRecord = errors.New("the record cannot be exported")
+
 // RequestType informs which data should be exported such as block, transaction, transaction log, etc.
 type RequestType uint
 
@@ -29,13 +36,14 @@ const (
 	// RequestTypes for Kafka
 	RequestTypeBlockGroup
 	RequestTypeTraceGroup
+	RequestTypeInternalTransferGroup
 
 	RequestTypeLength
 )
 
 const (
 	RequestTypeAll      = RequestTypeTransaction | RequestTypeTokenTransfer | RequestTypeContract | RequestTypeTrace
-	RequestTypeGroupAll = RequestTypeBlockGroup | RequestTypeTraceGroup
+	RequestTypeGroupAll = RequestTypeBlockGroup | RequestTypeTraceGroup | RequestTypeInternalTransferGroup
 )
 
 // Request contains a blockNumber which should be handled and the type of data which should be exported.
@@ -23,6 +23,7 @@ package downloader
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/klaytn/klaytn"
 	"github.com/klaytn/klaytn/event"
@@ -38,6 +39,10 @@ type PublicDownloaderAPI struct {
 	uninstallSyncSubscription chan *uninstallSyncSubscriptionRequest
 }
 
+// syncProgressInterval is the interval of the progress updates sent to the
+// syncing subscriptions while a sync is running.
+var syncProgressInterval = 10 * time.Second
+
 type downloadProgress interface {
 	Progress() klaytn.SyncProgress
 }
@@ -65,7 +70,10 @@ func (api *PublicDownloaderAPI) eventLoop() {
 	var (
 		sub               = api.mux.Subscribe(StartEvent{}, DoneEvent{}, FailedEvent{})
 		syncSubscriptions = make(map[chan interface{}]struct{})
+		ticker            = time.NewTicker(syncProgressInterval)
+		syncing           bool
 	)
+	defer ticker.Stop()
 
 	for {
 		select {
@@ -82,22 +90,37 @@ func (api *PublicDownloaderAPI) eventLoop() {
 			var notification interface{}
 			switch event.Data.(type) {
 			case StartEvent:
+				syncing = true
 				notification = &SyncingResult{
 					Syncing: true,
 					Status:  api.d.Progress(),
 				}
 			case DoneEvent, FailedEvent:
+				syncing = false
 				notification = false
 			}
 			// broadcast
 			for c := range syncSubscriptions {
 				c <- notification
 			}
+		case <-ticker.C:
+			// Report the progress periodically while syncing
+			if !syncing || len(syncSubscriptions) == 0 {
+				continue
+			}
+			notification := &SyncingResult{
+				Syncing: true,
+				Status:  api.d.Progress(),
+			}
+			for c := range syncSubscriptions {
+				c <- notification
+			}
 		}
 	}
 }
 
 // Syncing provides information when this nodes starts synchronising with the Klaytn network and when it's finished.
+// While synchronising, the progress is also sent periodically.
 func (api *PublicDownloaderAPI) Syncing(ctx context.Context) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
@@ -36,6 +36,7 @@ import (
 	"github.com/klaytn/klaytn/node/cn/snap"
 	"github.com/klaytn/klaytn/params"
 	"github.com/klaytn/klaytn/reward"
+	"github.com/klaytn/klaytn/rlp"
 	"github.com/klaytn/klaytn/snapshot"
 	"github.com/klaytn/klaytn/storage/database"
 	"github.com/klaytn/klaytn/storage/statedb"
@@ -113,7 +114,9 @@ type Downloader struct {
 	syncStatsChainOrigin uint64 // Origin block number where syncing started at
 	syncStatsChainHeight uint64 // Highest block number known when syncing started
 	syncStatsState       stateSyncStats
+	syncStatsResumed     bool         // Whether the sync resumed the pivot of an interrupted fast sync
 	syncStatsLock        sync.RWMutex // Lock protecting the sync stats fields
+	syncProgress         *syncProgressTracker
 
 	lightchain LightChain
 	blockchain BlockChain
@@ -143,8 +146,15 @@ type Downloader struct {
 
 	// for stateFetcher
 	pivotHeader *types.Header
+	pivotFixed  bool // Whether the pivot is a trusted checkpoint which must not be moved
 	pivotLock   sync.RWMutex
 
+	// Pivot selection and verification depth
+	pivotDistance uint64                 // Number of blocks below the remote head to select the pivot at
+	pivotMinPeers int                    // Number of peers which must agree on the pivot header
+	checkpoints   map[uint64]common.Hash // Trusted headers which the synced chain must match
+	syncFrom      uint64                 // Trusted checkpoint from which a full sync executes the blocks
+
 	stateSyncStart chan *stateSync
 	trackStateReq  chan *stateReq
 	stateCh        chan dataPack // [klay/63] Channel receiving inbound node state data
@@ -250,6 +260,8 @@ func New(mode SyncMode, stateDB database.DBManager, stateBloom *statedb.SyncBloo
 			processed: stateDB.ReadFastTrieProgress(),
 		},
 		trackStateReq: make(chan *stateReq),
+		syncProgress:  newSyncProgressTracker(),
+		pivotDistance: DefaultPivotDistance,
 	}
 	go dl.qosTuner()
 	go dl.stateFetcher()
@@ -263,12 +275,16 @@ func New(mode SyncMode, stateDB database.DBManager, stateBloom *statedb.SyncBloo
 // In addition, during the state download phase of fast synchronisation the number
 // of processed and the total number of known states are also returned. Otherwise
 // these are zero.
+//
+// The phase the sync is waiting for, the number of items downloaded since the
+// sync began, their current download rates and an estimate of the remaining time
+// are reported as well.
 func (d *Downloader) Progress() klaytn.SyncProgress {
 	// Lock the current stats and return the progress
 	d.syncStatsLock.RLock()
 	defer d.syncStatsLock.RUnlock()
 
-	current := uint64(0)
+	current, pivot := uint64(0), uint64(0)
 	mode := d.getMode()
 	switch mode {
 	case FullSync:
@@ -280,12 +296,50 @@ func (d *Downloader) Progress() klaytn.SyncProgress {
 	case LightSync:
 		current = d.lightchain.CurrentHeader().Number.Uint64()
 	}
+	if mode == FastSync || mode == SnapSync {
+		d.pivotLock.RLock()
+		if d.pivotHeader != nil {
+			pivot = d.pivotHeader.Number.Uint64()
+		}
+		d.pivotLock.RUnlock()
+	}
+	var (
+		now       = time.Now()
+		height    = d.syncStatsChainHeight
+		blockRate = d.syncProgress.sample(syncItemBlocks, current, now)
+		stateRate = d.syncProgress.sample(syncItemStates, d.syncStatsState.processed, now)
+		phase     = d.syncPhase(mode, current, pivot, height)
+		eta       uint64
+	)
+	if current < height {
+		eta = estimateETA(height-current, blockRate)
+	}
+	if phase == SyncPhaseState {
+		if stateETA := estimateETA(d.syncStatsState.pending, stateRate); stateETA > eta {
+			eta = stateETA
+		}
+	}
+	pulledHeaders := d.syncProgress.count(syncItemHeaders)
+	pulledBodies := d.syncProgress.count(syncItemBodies)
+	pulledReceipts := d.syncProgress.count(syncItemReceipts)
 	return klaytn.SyncProgress{
-		StartingBlock: d.syncStatsChainOrigin,
-		CurrentBlock:  current,
-		HighestBlock:  d.syncStatsChainHeight,
-		PulledStates:  d.syncStatsState.processed,
-		KnownStates:   d.syncStatsState.processed + d.syncStatsState.pending,
+		StartingBlock:  d.syncStatsChainOrigin,
+		CurrentBlock:   current,
+		HighestBlock:   height,
+		PulledStates:   d.syncStatsState.processed,
+		KnownStates:    d.syncStatsState.processed + d.syncStatsState.pending,
+		PivotBlock:     pivot,
+		Resumed:        d.syncStatsResumed,
+		Phase:          phase,
+		PulledHeaders:  pulledHeaders,
+		PulledBodies:   pulledBodies,
+		PulledReceipts: pulledReceipts,
+		HeaderRate:     d.syncProgress.sample(syncItemHeaders, pulledHeaders, now),
+		BodyRate:       d.syncProgress.sample(syncItemBodies, pulledBodies, now),
+		ReceiptRate:    d.syncProgress.sample(syncItemReceipts, pulledReceipts, now),
+		StateRate:      stateRate,
+		BlockRate:      blockRate,
+		ETA:            eta,
 	}
 }
 
@@ -474,6 +528,13 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 	}
 	mode := d.getMode()
 
+	// A full sync from a trusted checkpoint fast syncs up to the checkpoint
+	fixedPivot := mode == FullSync && d.syncFrom > 0 && d.blockchain.CurrentBlock().NumberU64() < d.syncFrom
+	if fixedPivot {
+		mode = FastSync
+		atomic.StoreUint32(&d.mode, uint32(mode))
+	}
+
 	logger.Debug("Synchronising with the network", "peer", p.id, "klay", p.version, "head", hash, "td", td, "mode", mode)
 	defer func(start time.Time) {
 		logger.Debug("Synchronisation terminated", "elapsed", time.Since(start))
@@ -493,20 +554,53 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 	}
 	height := latest.Number.Uint64()
 
+	if fixedPivot {
+		if height < d.syncFrom {
+			return fmt.Errorf("%w: remote head %d is below the sync starting block %d", errBadPeer, height, d.syncFrom)
+		}
+		if pivot, err = d.fetchHeaderByNumber(p, d.syncFrom); err != nil {
+			return err
+		}
+		if err := d.verifyCheckpoints([]*types.Header{pivot}); err != nil {
+			return err
+		}
+		logger.Info("Syncing from the trusted checkpoint", "number", d.syncFrom, "hash", pivot.Hash())
+	} else if (mode == FastSync || mode == SnapSync) && height > d.pivotDistance {
+		if err := d.verifyPivot(p, pivot); err != nil {
+			return err
+		}
+	}
+
 	origin, err := d.findAncestor(p, height)
 	if err != nil {
 		return err
 	}
+	// Resume the pivot of an interrupted fast sync, so that the state already
+	// downloaded for it is not thrown away for a new pivot
+	var status *fastSyncStatus
+	if (mode == FastSync || mode == SnapSync) && !fixedPivot {
+		if status = d.readFastSyncStatus(); status != nil && status.resumable(height, d.pivotDistance) {
+			logger.Info("Resuming interrupted fast sync", "origin", status.Origin, "pivot", status.Pivot.Number, "remotePivot", pivot.Number)
+			pivot = status.Pivot
+		} else {
+			status = nil
+		}
+	}
 	d.syncStatsLock.Lock()
 	if d.syncStatsChainHeight <= origin || d.syncStatsChainOrigin > origin {
 		d.syncStatsChainOrigin = origin
 	}
+	if status != nil && status.Origin < d.syncStatsChainOrigin {
+		d.syncStatsChainOrigin = status.Origin
+	}
 	d.syncStatsChainHeight = height
+	d.syncStatsResumed = status != nil
 	d.syncStatsLock.Unlock()
+	d.syncProgress.reset()
 
 	// Ensure our origin point is below any fast sync pivot point
 	if mode == FastSync || mode == SnapSync {
-		if height <= uint64(fsMinFullBlocks) {
+		if height <= d.pivotDistance && !fixedPivot {
 			origin = 0
 		} else {
 			pivotNumber := pivot.Number.Uint64()
@@ -535,7 +629,11 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 	if mode == FastSync || mode == SnapSync {
 		d.pivotLock.Lock()
 		d.pivotHeader = pivot
+		d.pivotFixed = fixedPivot
 		d.pivotLock.Unlock()
+		if d.committed == 0 {
+			d.writeFastSyncStatus(pivot)
+		}
 		fetchers = append(fetchers, func() error { return d.processFastSyncContent() })
 	} else if mode == FullSync {
 		fetchers = append(fetchers, d.processFullSyncContent)
@@ -629,7 +727,7 @@ func (d *Downloader) fetchHead(p *peerConnection) (head *types.Header, pivot *ty
 	if mode == FastSync || mode == SnapSync {
 		fetch = 2 // head + pivot headers
 	}
-	go p.peer.RequestHeadersByHash(latest, fetch, fsMinFullBlocks-1, true)
+	go p.peer.RequestHeadersByHash(latest, fetch, int(d.pivotDistance)-1, true)
 
 	ttl := d.requestTTL()
 	timeout := time.After(ttl)
@@ -654,7 +752,7 @@ func (d *Downloader) fetchHead(p *peerConnection) (head *types.Header, pivot *ty
 			// or there was not one requested.
 			head := headers[0]
 			if len(headers) == 1 {
-				if (mode == FastSync || mode == SnapSync) && head.Number.Uint64() > uint64(fsMinFullBlocks) {
+				if (mode == FastSync || mode == SnapSync) && head.Number.Uint64() > d.pivotDistance {
 					return nil, nil, fmt.Errorf("%w: no pivot included along head header", errBadPeer)
 				}
 				p.logger.Debug("Remote head identified, no pivot", "number", head.Number, "hash", head.Hash())
@@ -663,8 +761,8 @@ func (d *Downloader) fetchHead(p *peerConnection) (head *types.Header, pivot *ty
 			// At this point we have 2 headers in total and the first is the
 			// validated head of the chain. Check the pivot number and return.
 			pivot := headers[1]
-			if pivot.Number.Uint64() != head.Number.Uint64()-uint64(fsMinFullBlocks) {
-				return nil, nil, fmt.Errorf("%w: remote pivot %d != requested %d", errInvalidChain, pivot.Number, head.Number.Uint64()-uint64(fsMinFullBlocks))
+			if pivot.Number.Uint64() != head.Number.Uint64()-d.pivotDistance {
+				return nil, nil, fmt.Errorf("%w: remote pivot %d != requested %d", errInvalidChain, pivot.Number, head.Number.Uint64()-d.pivotDistance)
 			}
 			return head, pivot, nil
 
@@ -900,8 +998,8 @@ func (d *Downloader) fetchHeaders(p *peerConnection, from uint64) error {
 		pivotNumber := d.pivotHeader.Number.Uint64()
 		d.pivotLock.RUnlock()
 
-		p.logger.Trace("Fetching next pivot header", "number", pivotNumber+uint64(fsMinFullBlocks))
-		go p.peer.RequestHeadersByNumber(pivotNumber+uint64(fsMinFullBlocks), 2, fsMinFullBlocks-9, false) // move +64 when it's 2x64-8 deep
+		p.logger.Trace("Fetching next pivot header", "number", pivotNumber+d.pivotDistance)
+		go p.peer.RequestHeadersByNumber(pivotNumber+d.pivotDistance, 2, int(d.pivotDistance)-9, false) // move +64 when it's 2x64-8 deep
 	}
 	// Start pulling the header chain skeleton until all is done
 	getHeaders(from)
@@ -921,12 +1019,15 @@ func (d *Downloader) fetchHeaders(p *peerConnection, from uint64) error {
 			timeout.Stop()
 
 			// If the pivot is being checked, move if it became stale and run the real retrieval
-			var pivot uint64
-
+			var (
+				pivot      uint64
+				pivotFixed bool
+			)
 			d.pivotLock.RLock()
 			if d.pivotHeader != nil {
 				pivot = d.pivotHeader.Number.Uint64()
 			}
+			pivotFixed = d.pivotFixed
 			d.pivotLock.RUnlock()
 
 			if pivoting {
@@ -934,11 +1035,11 @@ func (d *Downloader) fetchHeaders(p *peerConnection, from uint64) error {
 					// Retrieve the headers and do some sanity checks, just in case
 					headers := packet.(*headerPack).headers
 
-					if have, want := headers[0].Number.Uint64(), pivot+uint64(fsMinFullBlocks); have != want {
+					if have, want := headers[0].Number.Uint64(), pivot+d.pivotDistance; have != want {
 						logger.Warn("Peer sent invalid next pivot", "have", have, "want", want)
 						return fmt.Errorf("%w: next pivot number %d != requested %d", errInvalidChain, have, want)
 					}
-					if have, want := headers[1].Number.Uint64(), pivot+2*uint64(fsMinFullBlocks)-8; have != want {
+					if have, want := headers[1].Number.Uint64(), pivot+2*d.pivotDistance-8; have != want {
 						logger.Warn("Peer sent invalid pivot confirmer", "have", have, "want", want)
 						return fmt.Errorf("%w: next pivot confirmer number %d != requested %d", errInvalidChain, have, want)
 					}
@@ -947,7 +1048,7 @@ func (d *Downloader) fetchHeaders(p *peerConnection, from uint64) error {
 					d.pivotLock.Lock()
 					d.pivotHeader = headers[0]
 					d.pivotLock.Unlock()
-
+					d.writeFastSyncStatus(headers[0])
 				}
 				pivoting = false
 				getHeaders(from)
@@ -1006,7 +1107,7 @@ func (d *Downloader) fetchHeaders(p *peerConnection, from uint64) error {
 			}
 			// If we're still skeleton filling fast sync, check pivot staleness
 			// before continuing to the next skeleton filling
-			if skeleton && pivot > 0 {
+			if skeleton && pivot > 0 && !pivotFixed {
 				getNextPivot()
 			} else {
 				getHeaders(from)
@@ -1210,6 +1311,12 @@ func (d *Downloader) fetchParts(deliveryCh chan dataPack, deliver func(dataPack)
 				if errors.Is(err, errInvalidChain) {
 					return err
 				}
+				switch kind {
+				case "bodies":
+					d.syncProgress.add(syncItemBodies, accepted)
+				case "receipts":
+					d.syncProgress.add(syncItemReceipts, accepted)
+				}
 				// Unless a peer delivered something completely else than requested (usually
 				// caused by a timed out request which came through in the end), set it to
 				// idle. If the delivery's stale, the peer should have already been idled.
@@ -1466,6 +1573,11 @@ func (d *Downloader) processHeaders(origin uint64, td *big.Int) error {
 				}
 				chunk := headers[:limit]
 
+				if err := d.verifyCheckpoints(chunk); err != nil {
+					rollbackErr = err
+					return fmt.Errorf("%w: %v", errInvalidChain, err)
+				}
+
 				// In case of header only syncing, validate the chunk immediately
 				if mode == SnapSync || mode == FastSync || mode == LightSync {
 					// Collect the yet unknown headers to mark them as uncertain
@@ -1523,6 +1635,7 @@ func (d *Downloader) processHeaders(origin uint64, td *big.Int) error {
 				}
 				headers = headers[limit:]
 				origin += uint64(limit)
+				d.syncProgress.add(syncItemHeaders, limit)
 			}
 
 			// Update the highest block number we know if a higher one is found.
@@ -1643,7 +1756,7 @@ func (d *Downloader) processFastSyncContent() error {
 		// If we haven't downloaded the pivot block yet, check pivot staleness
 		// notifications from the header downloader
 		d.pivotLock.RLock()
-		pivot := d.pivotHeader
+		pivot, pivotFixed := d.pivotHeader, d.pivotFixed
 		d.pivotLock.RUnlock()
 
 		if oldPivot == nil {
@@ -1662,13 +1775,14 @@ func (d *Downloader) processFastSyncContent() error {
 			// If the height is above the pivot block by 2 sets, it means the pivot
 			// become stale in the network and it was garbage collected, move to a
 			// new pivot.
-			if height := latest.Number.Uint64(); height >= pivot.Number.Uint64()+2*uint64(fsMinFullBlocks) {
-				logger.Warn("Pivot became stale, moving", "old", pivot.Number.Uint64(), "new", height-uint64(fsMinFullBlocks))
-				pivot = results[len(results)-1-fsMinFullBlocks].Header // must exist as lower old pivot is uncommitted
+			if height := latest.Number.Uint64(); height >= pivot.Number.Uint64()+2*d.pivotDistance && !pivotFixed {
+				logger.Warn("Pivot became stale, moving", "old", pivot.Number.Uint64(), "new", height-d.pivotDistance)
+				pivot = results[len(results)-1-int(d.pivotDistance)].Header // must exist as lower old pivot is uncommitted
 
 				d.pivotLock.Lock()
 				d.pivotHeader = pivot
 				d.pivotLock.Unlock()
+				d.writeFastSyncStatus(pivot)
 			}
 		}
 		P, beforeP, afterP := splitAroundPivot(pivot.Number.Uint64(), results)
@@ -1781,6 +1895,7 @@ func (d *Downloader) commitPivotBlock(result *fetchResult) error {
 		return err
 	}
 	atomic.StoreInt32(&d.committed, 1)
+	d.stateDB.DeleteFastSyncStatus()
 
 	// If we had a bloom filter for the state sync, deallocate it now. Note, we only
 	// deallocate internally, but keep the empty wrapper. This ensures that if we do
@@ -1793,6 +1908,56 @@ func (d *Downloader) commitPivotBlock(result *fetchResult) error {
 	return nil
 }
 
+// fastSyncStatus is the downloader progress persisted in the database while the
+// pivot block is not committed. The headers, bodies and receipts below the pivot
+// are already stored in the chain and the downloaded state trie nodes are skipped
+// by the state scheduler, so keeping the pivot is enough to resume the sync.
+type fastSyncStatus struct {
+	Origin uint64        // Origin block number where the interrupted sync started at
+	Pivot  *types.Header // Pivot block whose state was being downloaded
+}
+
+// resumable returns whether the persisted pivot can be used for a new sync
+// cycle towards the remote head at height. The pivot is dropped if it became
+// stale, the same way as a moving pivot at the given distance from the head.
+func (s *fastSyncStatus) resumable(height, distance uint64) bool {
+	if s.Pivot == nil || s.Pivot.Number == nil {
+		return false
+	}
+	number := s.Pivot.Number.Uint64()
+	return number != 0 && number <= height && height < number+2*distance
+}
+
+// readFastSyncStatus retrieves the progress of an interrupted fast sync, or nil
+// if there is none.
+func (d *Downloader) readFastSyncStatus() *fastSyncStatus {
+	blob := d.stateDB.ReadFastSyncStatus()
+	if len(blob) == 0 {
+		return nil
+	}
+	status := new(fastSyncStatus)
+	if err := rlp.DecodeBytes(blob, status); err != nil {
+		logger.Warn("Failed to decode fast sync status", "err", err)
+		return nil
+	}
+	return status
+}
+
+// writeFastSyncStatus persists the given pivot along with the sync origin to
+// resume the fast sync after an interruption.
+func (d *Downloader) writeFastSyncStatus(pivot *types.Header) {
+	d.syncStatsLock.RLock()
+	status := &fastSyncStatus{Origin: d.syncStatsChainOrigin, Pivot: pivot}
+	d.syncStatsLock.RUnlock()
+
+	blob, err := rlp.EncodeToBytes(status)
+	if err != nil {
+		logger.Warn("Failed to encode fast sync status", "err", err)
+		return
+	}
+	d.stateDB.WriteFastSyncStatus(blob)
+}
+
 // DeliverHeaders injects a new batch of block headers received from a remote
 // node into the download schedule.
 func (d *Downloader) DeliverHeaders(id string, headers []*types.Header) (err error) {
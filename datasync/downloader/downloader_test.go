@@ -1894,3 +1894,59 @@ func testDeliverHeadersHang(t *testing.T, protocol int, mode SyncMode) {
 		tester.downloader.peers.peers["peer"].peer.(*floodingTestPeer).pend.Wait()
 	}
 }
+
+// Tests that the pivot of an interrupted fast sync is resumed only while it is not stale.
+func TestFastSyncStatusResumable(t *testing.T) {
+	pivot := &types.Header{Number: big.NewInt(1000)}
+	status := &fastSyncStatus{Origin: 10, Pivot: pivot}
+
+	tests := []struct {
+		height uint64
+		want   bool
+	}{
+		{999, false},
+		{1000, true},
+		{1000 + uint64(fsMinFullBlocks), true},
+		{1000 + 2*uint64(fsMinFullBlocks) - 1, true},
+		{1000 + 2*uint64(fsMinFullBlocks), false},
+	}
+	for i, tt := range tests {
+		if have := status.resumable(tt.height, uint64(fsMinFullBlocks)); have != tt.want {
+			t.Errorf("test %d: resumable mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+	if (&fastSyncStatus{}).resumable(1000, uint64(fsMinFullBlocks)) {
+		t.Errorf("status without pivot should not be resumable")
+	}
+}
+
+// Tests that the fast sync status is persisted while the pivot is downloaded,
+// and removed once the pivot block is committed.
+func TestFastSyncStatusPersistence(t *testing.T) {
+	tester := newTester()
+	defer tester.terminate()
+
+	// The persisted pivot is above the remote head, so it is not resumed
+	pivot := &types.Header{Number: big.NewInt(5000)}
+	tester.downloader.syncStatsChainOrigin = 10
+	tester.downloader.writeFastSyncStatus(pivot)
+
+	status := tester.downloader.readFastSyncStatus()
+	if status == nil {
+		t.Fatalf("fast sync status not persisted")
+	}
+	if status.Origin != 10 || status.Pivot.Hash() != pivot.Hash() {
+		t.Errorf("fast sync status mismatch: have origin %d pivot %x, want origin 10 pivot %x", status.Origin, status.Pivot.Hash(), pivot.Hash())
+	}
+
+	targetBlocks := blockCacheMaxItems - 15
+	hashes, headers, blocks, receipts, stakingInfos := tester.makeChain(targetBlocks, 0, tester.genesis, nil, false)
+	tester.newPeer("peer", 65, hashes, headers, blocks, receipts, stakingInfos)
+
+	if err := tester.sync("peer", nil, FastSync); err != nil {
+		t.Fatalf("failed to synchronise blocks: %v", err)
+	}
+	if status := tester.downloader.readFastSyncStatus(); status != nil {
+		t.Errorf("fast sync status not removed after the pivot commit: pivot %d", status.Pivot.Number)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+)
+
+const (
+	// DefaultPivotDistance is the default distance of the fast sync pivot from
+	// the remote head, the number of blocks retrieved fully (fsMinFullBlocks).
+	DefaultPivotDistance = 64
+
+	// MinPivotDistance is the minimum distance of the fast sync pivot from the
+	// remote head. The pivot confirmer is requested 8 blocks below the next pivot.
+	MinPivotDistance = 16
+)
+
+var (
+	errPivotDisagreement  = errors.New("not enough peers agree on the pivot header")
+	errCheckpointMismatch = errors.New("header does not match the trusted checkpoint")
+)
+
+// Checkpoint is a trusted block header hash which the synced chain must contain.
+type Checkpoint struct {
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+}
+
+// LoadCheckpoints reads the trusted checkpoints from a JSON file, which is a list
+// of objects having the number and the hash of a block.
+func LoadCheckpoints(file string) ([]Checkpoint, error) {
+	blob, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var checkpoints []Checkpoint
+	if err := json.Unmarshal(blob, &checkpoints); err != nil {
+		return nil, fmt.Errorf("invalid checkpoints file %s: %v", file, err)
+	}
+	return checkpoints, nil
+}
+
+// PivotConfig is the configuration of the pivot selection and the verification
+// depth of a sync.
+type PivotConfig struct {
+	Distance    uint64       // Number of blocks below the remote head to select the fast sync pivot at
+	MinPeers    int          // Number of peers, including the syncing peer, which must agree on the pivot header
+	Checkpoints []Checkpoint // Trusted headers which the synced chain must match
+	From        uint64       // Block from which a full sync executes the blocks, which must be a trusted checkpoint (0 = genesis)
+}
+
+// SetPivotConfig sets the pivot selection and the trusted checkpoints. It must
+// be called before any sync starts.
+func (d *Downloader) SetPivotConfig(cfg PivotConfig) error {
+	if cfg.Distance == 0 {
+		cfg.Distance = DefaultPivotDistance
+	}
+	if cfg.Distance < MinPivotDistance {
+		return fmt.Errorf("pivot distance %d is less than %d", cfg.Distance, MinPivotDistance)
+	}
+	checkpoints := make(map[uint64]common.Hash, len(cfg.Checkpoints))
+	for _, cp := range cfg.Checkpoints {
+		if hash, ok := checkpoints[cp.Number]; ok && hash != cp.Hash {
+			return fmt.Errorf("conflicting checkpoints of block %d", cp.Number)
+		}
+		checkpoints[cp.Number] = cp.Hash
+	}
+	if _, ok := checkpoints[cfg.From]; cfg.From > 0 && !ok {
+		return fmt.Errorf("no trusted checkpoint of the sync starting block %d", cfg.From)
+	}
+	d.pivotDistance = cfg.Distance
+	d.pivotMinPeers = cfg.MinPeers
+	d.checkpoints = checkpoints
+	d.syncFrom = cfg.From
+	return nil
+}
+
+// verifyCheckpoints checks the headers against the trusted checkpoints.
+func (d *Downloader) verifyCheckpoints(headers []*types.Header) error {
+	if len(d.checkpoints) == 0 {
+		return nil
+	}
+	for _, header := range headers {
+		number := header.Number.Uint64()
+		if hash, ok := d.checkpoints[number]; ok && header.Hash() != hash {
+			logger.Warn("Header does not match the trusted checkpoint", "number", number, "have", header.Hash(), "want", hash)
+			return fmt.Errorf("%w: block %d %x != %x", errCheckpointMismatch, number, header.Hash(), hash)
+		}
+	}
+	return nil
+}
+
+// fetchHeaderByNumber retrieves a single header of the given number from the peer.
+func (d *Downloader) fetchHeaderByNumber(p *peerConnection, number uint64) (*types.Header, error) {
+	go p.peer.RequestHeadersByNumber(number, 1, 0, false)
+
+	ttl := d.requestTTL()
+	timeout := time.After(ttl)
+	for {
+		select {
+		case <-d.cancelCh:
+			return nil, errCanceled
+
+		case packet := <-d.headerCh:
+			if packet.PeerId() != p.id {
+				logger.Debug("Received headers from incorrect peer", "peer", packet.PeerId())
+				break
+			}
+			headers := packet.(*headerPack).headers
+			if len(headers) != 1 || headers[0].Number.Uint64() != number {
+				return nil, fmt.Errorf("%w: requested header %d not delivered", errBadPeer, number)
+			}
+			return headers[0], nil
+
+		case <-timeout:
+			p.logger.Debug("Waiting for header timed out", "number", number, "elapsed", ttl)
+			return nil, errTimeout
+		}
+	}
+}
+
+// verifyPivot requests the pivot header from the other peers and checks that
+// at least pivotMinPeers peers, including the syncing one, agree on it.
+func (d *Downloader) verifyPivot(p *peerConnection, pivot *types.Header) error {
+	if d.pivotMinPeers <= 1 {
+		return nil
+	}
+	number, hash := pivot.Number.Uint64(), pivot.Hash()
+
+	asked := make(map[string]bool)
+	for _, peer := range d.peers.AllPeers() {
+		if peer.id == p.id {
+			continue
+		}
+		asked[peer.id] = true
+		go peer.peer.RequestHeadersByNumber(number, 1, 0, false)
+	}
+	if len(asked)+1 < d.pivotMinPeers {
+		return fmt.Errorf("%w: %d peers available, %d required", errPivotDisagreement, len(asked)+1, d.pivotMinPeers)
+	}
+	agreed := 1
+
+	ttl := d.requestTTL()
+	timeout := time.After(ttl)
+	for len(asked) > 0 && agreed < d.pivotMinPeers {
+		select {
+		case <-d.cancelCh:
+			return errCanceled
+
+		case packet := <-d.headerCh:
+			if !asked[packet.PeerId()] {
+				break
+			}
+			delete(asked, packet.PeerId())
+
+			headers := packet.(*headerPack).headers
+			if len(headers) == 1 && headers[0].Number.Uint64() == number && headers[0].Hash() == hash {
+				agreed++
+			} else {
+				logger.Debug("Peer disagrees on the pivot", "peer", packet.PeerId(), "number", number)
+			}
+
+		case <-timeout:
+			asked = nil
+		}
+	}
+	if agreed < d.pivotMinPeers {
+		return fmt.Errorf("%w: %d of %d peers", errPivotDisagreement, agreed, d.pivotMinPeers)
+	}
+	logger.Debug("Pivot agreed by peers", "number", number, "hash", hash, "peers", agreed)
+	return nil
+}
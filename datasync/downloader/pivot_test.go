@@ -0,0 +1,105 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+)
+
+func TestSetPivotConfig(t *testing.T) {
+	hash := common.HexToHash("0x01")
+
+	d := &Downloader{pivotDistance: MinPivotDistance}
+	if err := d.SetPivotConfig(PivotConfig{}); err != nil {
+		t.Fatalf("failed to set the default config: %v", err)
+	}
+	if d.pivotDistance != DefaultPivotDistance {
+		t.Errorf("pivot distance mismatch: have %d, want %d", d.pivotDistance, DefaultPivotDistance)
+	}
+	if err := d.SetPivotConfig(PivotConfig{Distance: MinPivotDistance - 1}); err == nil {
+		t.Errorf("too short pivot distance should be rejected")
+	}
+	if err := d.SetPivotConfig(PivotConfig{From: 100}); err == nil {
+		t.Errorf("sync starting block without a checkpoint should be rejected")
+	}
+	conflicting := []Checkpoint{{Number: 100, Hash: hash}, {Number: 100, Hash: common.HexToHash("0x02")}}
+	if err := d.SetPivotConfig(PivotConfig{Checkpoints: conflicting}); err == nil {
+		t.Errorf("conflicting checkpoints should be rejected")
+	}
+	cfg := PivotConfig{Distance: 128, MinPeers: 3, Checkpoints: []Checkpoint{{Number: 100, Hash: hash}}, From: 100}
+	if err := d.SetPivotConfig(cfg); err != nil {
+		t.Fatalf("failed to set the config: %v", err)
+	}
+	if d.pivotDistance != 128 || d.pivotMinPeers != 3 || d.syncFrom != 100 || d.checkpoints[100] != hash {
+		t.Errorf("config mismatch: distance %d, peers %d, from %d, checkpoints %v", d.pivotDistance, d.pivotMinPeers, d.syncFrom, d.checkpoints)
+	}
+}
+
+func TestVerifyCheckpoints(t *testing.T) {
+	headers := []*types.Header{
+		{Number: big.NewInt(1)},
+		{Number: big.NewInt(2)},
+	}
+	d := &Downloader{}
+	if err := d.verifyCheckpoints(headers); err != nil {
+		t.Fatalf("headers without checkpoints should pass: %v", err)
+	}
+	d.checkpoints = map[uint64]common.Hash{2: headers[1].Hash(), 3: common.HexToHash("0x03")}
+	if err := d.verifyCheckpoints(headers); err != nil {
+		t.Fatalf("matching headers should pass: %v", err)
+	}
+	d.checkpoints[1] = common.HexToHash("0x01")
+	if err := d.verifyCheckpoints(headers); !errors.Is(err, errCheckpointMismatch) {
+		t.Fatalf("error mismatch: have %v, want %v", err, errCheckpointMismatch)
+	}
+}
+
+func TestLoadCheckpoints(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klay-checkpoints")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "checkpoints.json")
+	blob := `[{"number": 100, "hash": "0x0000000000000000000000000000000000000000000000000000000000000001"}]`
+	if err := ioutil.WriteFile(file, []byte(blob), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	checkpoints, err := LoadCheckpoints(file)
+	if err != nil {
+		t.Fatalf("failed to load the checkpoints: %v", err)
+	}
+	if len(checkpoints) != 1 || checkpoints[0].Number != 100 || checkpoints[0].Hash != common.HexToHash("0x01") {
+		t.Errorf("checkpoints mismatch: %v", checkpoints)
+	}
+
+	if err := ioutil.WriteFile(file, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCheckpoints(file); err == nil {
+		t.Errorf("invalid checkpoints file should be rejected")
+	}
+}
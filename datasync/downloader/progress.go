@@ -0,0 +1,149 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Phases of a sync reported by Progress.
+const (
+	SyncPhaseHeaders  = "headers"  // Downloading and verifying block headers
+	SyncPhaseBodies   = "bodies"   // Downloading block bodies of the scheduled headers
+	SyncPhaseReceipts = "receipts" // Downloading receipts of the scheduled headers (fast sync)
+	SyncPhaseState    = "state"    // Downloading the state of the pivot block (fast sync)
+	SyncPhaseImport   = "import"   // Importing the downloaded blocks
+)
+
+type syncItem int
+
+const (
+	syncItemHeaders syncItem = iota
+	syncItemBodies
+	syncItemReceipts
+	syncItemStates
+	syncItemBlocks
+	numSyncItems
+)
+
+const (
+	minRateSampleInterval = time.Second // Minimum interval between the samples of a rate
+	rateSmoothingFactor   = 0.3         // Weight of the latest sample in the moving average of a rate
+)
+
+// rateSample is the exponential moving average of the rate of a growing count.
+type rateSample struct {
+	time  time.Time
+	count uint64
+	rate  float64
+	valid bool
+}
+
+// syncProgressTracker counts the items downloaded by the current sync and
+// estimates their download rates.
+type syncProgressTracker struct {
+	lock   sync.Mutex
+	counts [numSyncItems]uint64
+	rates  [numSyncItems]rateSample
+}
+
+func newSyncProgressTracker() *syncProgressTracker {
+	return &syncProgressTracker{}
+}
+
+// reset clears the counts and the rates when a new sync starts.
+func (t *syncProgressTracker) reset() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.counts = [numSyncItems]uint64{}
+	t.rates = [numSyncItems]rateSample{}
+}
+
+// add increases the number of the downloaded items.
+func (t *syncProgressTracker) add(item syncItem, n int) {
+	if n <= 0 {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.counts[item] += uint64(n)
+}
+
+// count returns the number of the downloaded items.
+func (t *syncProgressTracker) count(item syncItem) uint64 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.counts[item]
+}
+
+// sample updates the rate of the item with the given total count and returns
+// it in items per second. The rate is only updated once a second at most, so
+// that frequent calls do not make it jumpy.
+func (t *syncProgressTracker) sample(item syncItem, count uint64, now time.Time) float64 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	s := &t.rates[item]
+	if s.time.IsZero() || count < s.count {
+		*s = rateSample{time: now, count: count}
+		return 0
+	}
+	elapsed := now.Sub(s.time)
+	if elapsed < minRateSampleInterval {
+		return s.rate
+	}
+	current := float64(count-s.count) / elapsed.Seconds()
+	if s.valid {
+		s.rate = rateSmoothingFactor*current + (1-rateSmoothingFactor)*s.rate
+	} else {
+		s.rate, s.valid = current, true
+	}
+	s.time, s.count = now, count
+	return s.rate
+}
+
+// estimateETA returns the seconds to download the remaining items at the given
+// rate, or 0 if it cannot be estimated.
+func estimateETA(remaining uint64, rate float64) uint64 {
+	if remaining == 0 || rate <= 0 {
+		return 0
+	}
+	return uint64(float64(remaining)/rate + 0.5)
+}
+
+// syncPhase returns the phase which the sync is waiting for.
+func (d *Downloader) syncPhase(mode SyncMode, current, pivot, height uint64) string {
+	fastSync := mode == FastSync || mode == SnapSync
+	if fastSync && pivot > 0 && current+1 >= pivot && atomic.LoadInt32(&d.committed) == 0 {
+		return SyncPhaseState
+	}
+	if d.lightchain.CurrentHeader().Number.Uint64() < height || mode == LightSync {
+		return SyncPhaseHeaders
+	}
+	if d.queue.PendingBlocks() > 0 || d.queue.InFlightBlocks() {
+		return SyncPhaseBodies
+	}
+	if fastSync && (d.queue.PendingReceipts() > 0 || d.queue.InFlightReceipts()) {
+		return SyncPhaseReceipts
+	}
+	return SyncPhaseImport
+}
@@ -0,0 +1,81 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSyncProgressTrackerRate(t *testing.T) {
+	var (
+		tracker = newSyncProgressTracker()
+		start   = time.Now()
+	)
+	// The first sample only initializes the rate
+	if rate := tracker.sample(syncItemBodies, 0, start); rate != 0 {
+		t.Fatalf("initial rate mismatch: have %v, want 0", rate)
+	}
+	tracker.add(syncItemBodies, 100)
+	if count := tracker.count(syncItemBodies); count != 100 {
+		t.Fatalf("count mismatch: have %v, want 100", count)
+	}
+	// Samples within a second are ignored
+	if rate := tracker.sample(syncItemBodies, 100, start.Add(500*time.Millisecond)); rate != 0 {
+		t.Fatalf("early rate mismatch: have %v, want 0", rate)
+	}
+	if rate := tracker.sample(syncItemBodies, 100, start.Add(2*time.Second)); rate != 50 {
+		t.Fatalf("rate mismatch: have %v, want 50", rate)
+	}
+	// Later samples are smoothed
+	want := rateSmoothingFactor*100 + (1-rateSmoothingFactor)*50
+	if rate := tracker.sample(syncItemBodies, 200, start.Add(3*time.Second)); math.Abs(rate-want) > 1e-9 {
+		t.Fatalf("smoothed rate mismatch: have %v, want %v", rate, want)
+	}
+	// The rates of the other items are independent
+	if rate := tracker.sample(syncItemHeaders, 0, start.Add(3*time.Second)); rate != 0 {
+		t.Fatalf("header rate mismatch: have %v, want 0", rate)
+	}
+	// Reset clears everything
+	tracker.reset()
+	if count := tracker.count(syncItemBodies); count != 0 {
+		t.Fatalf("count mismatch after reset: have %v, want 0", count)
+	}
+	if rate := tracker.sample(syncItemBodies, 0, start.Add(4*time.Second)); rate != 0 {
+		t.Fatalf("rate mismatch after reset: have %v, want 0", rate)
+	}
+}
+
+func TestEstimateETA(t *testing.T) {
+	tests := []struct {
+		remaining uint64
+		rate      float64
+		want      uint64
+	}{
+		{0, 10, 0},
+		{100, 0, 0},
+		{100, 10, 10},
+		{100, 3, 33},
+		{5, 2, 3},
+	}
+	for i, tt := range tests {
+		if eta := estimateETA(tt.remaining, tt.rate); eta != tt.want {
+			t.Errorf("test %d: eta mismatch: have %v, want %v", i, eta, tt.want)
+		}
+	}
+}
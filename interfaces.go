@@ -105,6 +105,19 @@ type SyncProgress struct {
 	HighestBlock  uint64 // Highest alleged block number in the chain
 	PulledStates  uint64 // Number of state trie entries already downloaded
 	KnownStates   uint64 // Total number of state trie entries known about
+	PivotBlock    uint64 // Pivot block number whose state is downloaded in fast sync
+	Resumed       bool   // Whether the sync resumed the pivot of an interrupted fast sync
+
+	Phase          string  // Phase which the sync is waiting for (headers, bodies, receipts, state or import)
+	PulledHeaders  uint64  // Number of block headers downloaded since the sync began
+	PulledBodies   uint64  // Number of block bodies downloaded since the sync began
+	PulledReceipts uint64  // Number of block receipts downloaded since the sync began
+	HeaderRate     float64 // Current download rate of block headers per second
+	BodyRate       float64 // Current download rate of block bodies per second
+	ReceiptRate    float64 // Current download rate of block receipts per second
+	StateRate      float64 // Current download rate of state trie entries per second
+	BlockRate      float64 // Current rate of the blocks the sync advances per second
+	ETA            uint64  // Estimated seconds until the sync completes (0 if unknown)
 }
 
 // ChainSyncReader wraps access to the node's current sync status. If there's no
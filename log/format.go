@@ -254,6 +254,72 @@ func JsonFormatEx(pretty, lineSeparated bool) Format {
 	})
 }
 
+// levelNames are the full names of the levels for StructuredJsonFormat.
+var levelNames = map[Lvl]string{
+	LvlCrit:  "crit",
+	LvlError: "error",
+	LvlWarn:  "warn",
+	LvlInfo:  "info",
+	LvlDebug: "debug",
+	LvlTrace: "trace",
+}
+
+// StructuredJsonFormat formats log records as JSON objects separated by newlines
+// for log collectors like Loki or ELK. Each object has the time, the level, the
+// module name, the caller location, the message and the context fields:
+//
+//     {"time":"2006-01-02T15:04:05.000+09:00","level":"info","module":"node/cn","caller":"node/cn/handler.go:123","msg":"Imported new chain segment","fields":{"blocks":1}}
+//
+func StructuredJsonFormat() Format {
+	return FormatFunc(func(r *Record) []byte {
+		ctx := r.Ctx
+		module := ""
+		if len(ctx) >= 2 && ctx[0] == "module" {
+			if mi, ok := ctx[1].(ModuleID); ok && mi >= 0 && mi < ModuleNameLen {
+				module = GetModuleName(mi)
+			} else {
+				module = fmt.Sprintf("%v", ctx[1])
+			}
+			ctx = ctx[2:]
+		}
+		caller := fmt.Sprintf("%+v", r.Call)
+		for _, prefix := range locationTrims {
+			caller = strings.TrimPrefix(caller, prefix)
+		}
+
+		fields := make(map[string]interface{}, len(ctx)/2)
+		for i := 0; i+1 < len(ctx); i += 2 {
+			k, ok := ctx[i].(string)
+			if !ok {
+				k = fmt.Sprintf("%+v", ctx[i])
+			}
+			fields[k] = formatJsonValue(ctx[i+1])
+		}
+		record := struct {
+			Time   string                 `json:"time"`
+			Level  string                 `json:"level"`
+			Module string                 `json:"module,omitempty"`
+			Caller string                 `json:"caller"`
+			Msg    string                 `json:"msg"`
+			Fields map[string]interface{} `json:"fields,omitempty"`
+		}{
+			Time:   r.Time.Format(time.RFC3339Nano),
+			Level:  levelNames[r.Lvl],
+			Module: module,
+			Caller: caller,
+			Msg:    r.Msg,
+			Fields: fields,
+		}
+		b, err := json.Marshal(record)
+		if err != nil {
+			b, _ = json.Marshal(map[string]string{
+				errorKey: err.Error(),
+			})
+		}
+		return append(b, '\n')
+	})
+}
+
 func formatShared(value interface{}) (result interface{}) {
 	defer func() {
 		if err := recover(); err != nil {
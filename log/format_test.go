@@ -0,0 +1,50 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package log
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-stack/stack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructuredJsonFormat(t *testing.T) {
+	r := &Record{
+		Time: time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC),
+		Lvl:  LvlWarn,
+		Msg:  "Imported new chain segment",
+		Ctx:  []interface{}{"module", NodeCN, "blocks", 3, "err", "timeout"},
+		Call: stack.Caller(0),
+	}
+	b := StructuredJsonFormat().Format(r)
+	assert.True(t, strings.HasSuffix(string(b), "}\n"))
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(b, &record); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "2022-10-01T12:00:00Z", record["time"])
+	assert.Equal(t, "warn", record["level"])
+	assert.Equal(t, "node/cn", record["module"])
+	assert.True(t, strings.HasSuffix(record["caller"].(string), "format_test.go:35"))
+	assert.Equal(t, "Imported new chain segment", record["msg"])
+	assert.Equal(t, map[string]interface{}{"blocks": float64(3), "err": "timeout"}, record["fields"])
+}
@@ -51,6 +51,10 @@ type GlogHandler struct {
 	siteCache map[uintptr]Lvl // Cache of callsite pattern evaluations
 	location  string          // file:line location where to do a stackdump at
 	lock      sync.RWMutex    // Lock protecting the override pattern list
+
+	vmodule        string           // Ruleset of the current patterns
+	moduleOverride uint32           // Number of the module levels, atomically accessible
+	moduleLevels   map[ModuleID]Lvl // Levels of the modules overriding the other filters
 }
 
 // NewGlogHandler creates a new log handler with filtering functionality similar
@@ -137,11 +141,59 @@ func (h *GlogHandler) Vmodule(ruleset string) error {
 
 	h.patterns = filter
 	h.siteCache = make(map[uintptr]Lvl)
+	h.vmodule = ruleset
 	atomic.StoreUint32(&h.override, uint32(len(filter)))
 
 	return nil
 }
 
+// SetModuleLevel sets the log level of the module. It overrides the global
+// level and the vmodule patterns for the records of the module.
+func (h *GlogHandler) SetModuleLevel(mi ModuleID, level Lvl) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.moduleLevels == nil {
+		h.moduleLevels = make(map[ModuleID]Lvl)
+	}
+	h.moduleLevels[mi] = level
+	atomic.StoreUint32(&h.moduleOverride, uint32(len(h.moduleLevels)))
+}
+
+// ResetModuleLevel removes the log level of the module set by SetModuleLevel.
+func (h *GlogHandler) ResetModuleLevel(mi ModuleID) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	delete(h.moduleLevels, mi)
+	atomic.StoreUint32(&h.moduleOverride, uint32(len(h.moduleLevels)))
+}
+
+// ModuleLevels returns the log levels of the modules set by SetModuleLevel.
+func (h *GlogHandler) ModuleLevels() map[ModuleID]Lvl {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	levels := make(map[ModuleID]Lvl, len(h.moduleLevels))
+	for mi, level := range h.moduleLevels {
+		levels[mi] = level
+	}
+	return levels
+}
+
+// Level returns the global log level.
+func (h *GlogHandler) Level() Lvl {
+	return Lvl(atomic.LoadUint32(&h.level))
+}
+
+// VmoduleRuleset returns the ruleset given to Vmodule.
+func (h *GlogHandler) VmoduleRuleset() string {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	return h.vmodule
+}
+
 // BacktraceAt sets the glog backtrace location. When set to a file and line
 // number holding a logging statement, a stack trace will be written to the Info
 // log whenever execution hits that statement.
@@ -196,6 +248,21 @@ func (h *GlogHandler) Log(r *Record) error {
 			r.Msg += "\n\n" + string(buf)
 		}
 	}
+	// If the level of the module is set, it decides alone
+	if atomic.LoadUint32(&h.moduleOverride) > 0 && len(r.Ctx) >= 2 && r.Ctx[0] == module {
+		if mi, ok := r.Ctx[1].(ModuleID); ok {
+			h.lock.RLock()
+			lvl, ok := h.moduleLevels[mi]
+			h.lock.RUnlock()
+
+			if ok {
+				if lvl >= r.Lvl {
+					return h.origin.Log(r)
+				}
+				return nil
+			}
+		}
+	}
 	// If the global log level allows, fast track logging
 	if atomic.LoadUint32(&h.level) >= uint32(r.Lvl) {
 		return h.origin.Log(r)
@@ -0,0 +1,67 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlogHandler_ModuleLevel(t *testing.T) {
+	var logged []string
+	h := NewGlogHandler(FuncHandler(func(r *Record) error {
+		logged = append(logged, r.Msg)
+		return nil
+	}))
+	h.Verbosity(LvlInfo)
+	record := func(mi ModuleID, lvl Lvl, msg string) *Record {
+		return &Record{Lvl: lvl, Msg: msg, Ctx: []interface{}{module, mi}}
+	}
+
+	// The module level raises or lowers the global level for the module only.
+	h.SetModuleLevel(ConsensusIstanbul, LvlTrace)
+	h.SetModuleLevel(NodeCN, LvlError)
+	h.Log(record(ConsensusIstanbul, LvlTrace, "istanbul trace"))
+	h.Log(record(Blockchain, LvlTrace, "blockchain trace"))
+	h.Log(record(Blockchain, LvlInfo, "blockchain info"))
+	h.Log(record(NodeCN, LvlInfo, "cn info"))
+	assert.Equal(t, []string{"istanbul trace", "blockchain info"}, logged)
+	assert.Equal(t, map[ModuleID]Lvl{ConsensusIstanbul: LvlTrace, NodeCN: LvlError}, h.ModuleLevels())
+
+	// The global level applies again after the reset.
+	logged = nil
+	h.ResetModuleLevel(NodeCN)
+	h.Log(record(NodeCN, LvlInfo, "cn info"))
+	assert.Equal(t, []string{"cn info"}, logged)
+	assert.Equal(t, map[ModuleID]Lvl{ConsensusIstanbul: LvlTrace}, h.ModuleLevels())
+}
+
+func TestLvlFromString(t *testing.T) {
+	for name, want := range map[string]Lvl{
+		"trace": LvlTrace, "trce": LvlTrace, "DEBUG": LvlDebug, "info": LvlInfo,
+		"warn": LvlWarn, "error": LvlError, "eror": LvlError, "crit": LvlCrit, "3": LvlInfo,
+	} {
+		lvl, err := LvlFromString(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, want, lvl, name)
+	}
+	for _, name := range []string{"", "verbose", "6", "-1"} {
+		_, err := LvlFromString(name)
+		assert.Error(t, err, name)
+	}
+}
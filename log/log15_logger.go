@@ -6,8 +6,11 @@
 package log
 
 import (
+	"fmt"
 	"os"
 	"runtime"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-stack/stack"
@@ -72,6 +75,21 @@ func (l Lvl) String() string {
 	}
 }
 
+// LvlFromString returns the Lvl of the name, like "trace" or "trce", or of the
+// number, like "5".
+func LvlFromString(name string) (Lvl, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for lvl := LvlCrit; lvl < LvlEnd; lvl++ {
+		if name == levelNames[lvl] || name == lvl.String() {
+			return lvl, nil
+		}
+	}
+	if n, err := strconv.Atoi(name); err == nil && n >= int(LvlCrit) && n < int(LvlEnd) {
+		return Lvl(n), nil
+	}
+	return LvlCrit, fmt.Errorf("unknown log level %q", name)
+}
+
 type Record struct {
 	Time     time.Time
 	Lvl      Lvl
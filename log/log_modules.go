@@ -126,6 +126,12 @@ const (
 	KAS
 	FORK
 	NodeCnGasPrice
+	AccountsKMS
+	AccountsVault
+	AccountsRemote
+	CMDKSIGNER
+	AccountsHDWallet
+	AccountsPKCS11
 
 	// ModuleNameLen should be placed at the end of the list.
 	ModuleNameLen
@@ -203,4 +209,10 @@ var moduleNames = [ModuleNameLen]string{
 	"kas",
 	"fork",
 	"node/cn/gasprice",
+	"accounts/kms",
+	"accounts/vault",
+	"accounts/remote",
+	"cmd/ksigner",
+	"accounts/hdwallet",
+	"accounts/pkcs11",
 }
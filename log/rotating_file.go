@@ -0,0 +1,272 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package log
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the format of the time in the names of the rotated files.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+var errRotatingFileClosed = errors.New("rotating file is closed")
+
+// RotationConfig is the rotation and retention policy of a log file.
+type RotationConfig struct {
+	MaxSize    int64         // Size in bytes to rotate the file at (0 = no size based rotation)
+	Interval   time.Duration // Interval to rotate the file at (0 = no time based rotation)
+	MaxBackups int           // Number of the rotated files to keep (0 = all)
+	MaxAge     time.Duration // Age of the rotated files to remove (0 = no removal by age)
+	Compress   bool          // Whether to gzip the rotated files
+}
+
+// RotatingFile is a log file which is rotated by its size or age. The rotated
+// file is renamed with the rotation time, like node-2006-01-02T15-04-05.000.log
+// for node.log, and the old ones are removed by the retention policy. Since the
+// file is reopened by itself, the rotation doesn't race with the open handle as
+// an external logrotate does.
+type RotatingFile struct {
+	path   string
+	config RotationConfig
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	closed   bool
+
+	cleanupMu sync.Mutex     // serializes the compression and the removal of the rotated files
+	wg        sync.WaitGroup // waits for the background compression and removal
+	now       func() time.Time
+}
+
+// NewRotatingFile opens the log file in the append mode, creating it if it
+// doesn't exist, and rotates it by the config.
+func NewRotatingFile(path string, config RotationConfig) (*RotatingFile, error) {
+	f := &RotatingFile{path: path, config: config, now: time.Now}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file, f.size, f.openedAt = file, info.Size(), f.now()
+	return nil
+}
+
+// Write writes p to the file, rotating the file first if the write exceeds the
+// size limit or the rotation interval has passed.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return 0, errRotatingFileClosed
+	}
+	sizeExceeded := f.config.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.config.MaxSize
+	intervalPassed := f.config.Interval > 0 && f.now().Sub(f.openedAt) >= f.config.Interval
+	if sizeExceeded || intervalPassed {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Rotate rotates the file regardless of the policy.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return errRotatingFileClosed
+	}
+	return f.rotate()
+}
+
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	now := f.now()
+	ext := filepath.Ext(f.path)
+	backup := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(f.path, ext), now.Format(backupTimeFormat), ext)
+	if err := os.Rename(f.path, backup); err != nil {
+		// Keep writing to the file rather than losing the logs.
+		if openErr := f.open(); openErr != nil {
+			return openErr
+		}
+		return err
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		f.cleanupMu.Lock()
+		defer f.cleanupMu.Unlock()
+
+		// The file may have been removed already by a later rotation.
+		if f.config.Compress {
+			if err := compressFile(backup); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "Failed to compress the rotated log file %s: %v\n", backup, err)
+			}
+		}
+		if err := f.removeOldBackups(now); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to remove the old log files of %s: %v\n", f.path, err)
+		}
+	}()
+	return nil
+}
+
+// backups returns the rotated files of the file from the newest one.
+func (f *RotatingFile) backups() ([]string, []time.Time, error) {
+	dir, base := filepath.Split(f.path)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+
+	entries, err := ioutil.ReadDir(filepath.Clean(dir))
+	if err != nil {
+		return nil, nil, err
+	}
+	type backup struct {
+		path string
+		time time.Time
+	}
+	var found []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimPrefix(name, prefix)
+		switch {
+		case strings.HasSuffix(stamp, ext+".gz"):
+			stamp = strings.TrimSuffix(stamp, ext+".gz")
+		case strings.HasSuffix(stamp, ext):
+			stamp = strings.TrimSuffix(stamp, ext)
+		default:
+			continue
+		}
+		t, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		found = append(found, backup{filepath.Join(dir, name), t})
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].time.After(found[j].time) })
+
+	paths, times := make([]string, len(found)), make([]time.Time, len(found))
+	for i, b := range found {
+		paths[i], times[i] = b.path, b.time
+	}
+	return paths, times, nil
+}
+
+// removeOldBackups removes the rotated files beyond MaxBackups or older than
+// MaxAge at the given time.
+func (f *RotatingFile) removeOldBackups(now time.Time) error {
+	if f.config.MaxBackups <= 0 && f.config.MaxAge <= 0 {
+		return nil
+	}
+	paths, times, err := f.backups()
+	if err != nil {
+		return err
+	}
+	for i, path := range paths {
+		tooMany := f.config.MaxBackups > 0 && i >= f.config.MaxBackups
+		tooOld := f.config.MaxAge > 0 && now.Sub(times[i]) > f.config.MaxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// compressFile gzips the file into the file with the .gz suffix, and removes it.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	src.Close()
+	return os.Remove(path)
+}
+
+// Close closes the file, and waits for the compression and the removal of the
+// rotated files in progress.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return nil
+	}
+	f.closed = true
+	err := f.file.Close()
+	f.mu.Unlock()
+
+	f.wg.Wait()
+	return err
+}
@@ -0,0 +1,124 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package log
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// listDir returns the sorted names of the files in the directory.
+func listDir(t *testing.T, dir string) []string {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestRotatingFile_Size(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klaytn-rotating-file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2022, 10, 1, 0, 0, 0, 0, time.Local)
+	path := filepath.Join(dir, "node.log")
+	f, err := NewRotatingFile(path, RotationConfig{MaxSize: 10, MaxBackups: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.now = func() time.Time { return now }
+
+	// Each write exceeding the size rotates the file except the first one.
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		now = now.Add(time.Second)
+		_, err := f.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, f.Close())
+
+	// The oldest rotated file is removed by MaxBackups.
+	assert.Equal(t, []string{
+		"node-2022-10-01T00-00-03.000.log",
+		"node-2022-10-01T00-00-04.000.log",
+		"node.log",
+	}, listDir(t, dir))
+	content, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "fourth\n", string(content))
+	content, _ = ioutil.ReadFile(filepath.Join(dir, "node-2022-10-01T00-00-04.000.log"))
+	assert.Equal(t, "third\n", string(content))
+
+	_, err = f.Write([]byte("closed\n"))
+	assert.Equal(t, errRotatingFileClosed, err)
+}
+
+func TestRotatingFile_IntervalAndCompress(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klaytn-rotating-file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2022, 10, 1, 0, 0, 0, 0, time.Local)
+	path := filepath.Join(dir, "node.log")
+	f, err := NewRotatingFile(path, RotationConfig{Interval: time.Hour, MaxAge: 90 * time.Minute, Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.now = func() time.Time { return now }
+	f.openedAt = now
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := f.Write([]byte(line))
+		assert.NoError(t, err)
+		now = now.Add(time.Hour)
+	}
+	assert.NoError(t, f.Close())
+
+	// The file rotated at 01:00 is older than MaxAge at 03:00.
+	assert.Equal(t, []string{
+		"node-2022-10-01T02-00-00.000.log.gz",
+		"node-2022-10-01T03-00-00.000.log.gz",
+		"node.log",
+	}, listDir(t, dir))
+
+	gz, err := os.Open(filepath.Join(dir, "node-2022-10-01T02-00-00.000.log.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gz.Close()
+	zr, err := gzip.NewReader(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ := ioutil.ReadAll(zr)
+	assert.Equal(t, "second\n", string(content))
+}
@@ -0,0 +1,174 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+)
+
+// BanInfo describes a ban on a node ID or an IP network set by the operator.
+// Either ID or Network is set.
+type BanInfo struct {
+	ID      string     `json:"id,omitempty"`      // banned node ID
+	Network string     `json:"network,omitempty"` // banned IP network in CIDR notation
+	Expiry  *time.Time `json:"expiry,omitempty"`  // nil if the ban is permanent
+}
+
+type ipBan struct {
+	network *net.IPNet
+	expiry  time.Time
+}
+
+// banList holds the bans on node IDs and IP networks. A zero expiry means the
+// ban is permanent. The expired bans are removed lazily.
+type banList struct {
+	mu  sync.Mutex
+	ids map[discover.NodeID]time.Time
+	ips map[string]*ipBan // keyed by the CIDR notation of the network
+
+	now func() time.Time // for testing
+}
+
+func newBanList() *banList {
+	return &banList{
+		ids: make(map[discover.NodeID]time.Time),
+		ips: make(map[string]*ipBan),
+		now: time.Now,
+	}
+}
+
+func (b *banList) expired(expiry time.Time) bool {
+	return !expiry.IsZero() && !expiry.After(b.now())
+}
+
+func (b *banList) banID(id discover.NodeID, expiry time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.ids[id] = expiry
+}
+
+// unbanID lifts the ban on the node ID. It returns false if the ID is not banned.
+func (b *banList) unbanID(id discover.NodeID) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	expiry, ok := b.ids[id]
+	delete(b.ids, id)
+	return ok && !b.expired(expiry)
+}
+
+// banIP bans the IP network given as an IP address or in CIDR notation.
+// It returns the banned network.
+func (b *banList) banIP(cidr string, expiry time.Time) (*net.IPNet, error) {
+	network, err := parseCIDROrIP(cidr)
+	if err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.ips[network.String()] = &ipBan{network: network, expiry: expiry}
+	return network, nil
+}
+
+func (b *banList) unbanIP(cidr string) error {
+	network, err := parseCIDROrIP(cidr)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ban, ok := b.ips[network.String()]
+	if !ok || b.expired(ban.expiry) {
+		delete(b.ips, network.String())
+		return fmt.Errorf("%s is not banned", network)
+	}
+	delete(b.ips, network.String())
+	return nil
+}
+
+func (b *banList) isIDBanned(id discover.NodeID) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	expiry, ok := b.ids[id]
+	if ok && b.expired(expiry) {
+		delete(b.ids, id)
+		return false
+	}
+	return ok
+}
+
+func (b *banList) isIPBanned(ip net.IP) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for key, ban := range b.ips {
+		if b.expired(ban.expiry) {
+			delete(b.ips, key)
+			continue
+		}
+		if ban.network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBanned returns true if either the ID or the IP of the node is banned.
+func (b *banList) isBanned(n *discover.Node) bool {
+	return b.isIDBanned(n.ID) || (n.IP != nil && b.isIPBanned(n.IP))
+}
+
+// list returns the bans in effect, the node ID bans first.
+func (b *banList) list() []*BanInfo {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	expiryOf := func(expiry time.Time) *time.Time {
+		if expiry.IsZero() {
+			return nil
+		}
+		return &expiry
+	}
+	var ids, ips []*BanInfo
+	for id, expiry := range b.ids {
+		if b.expired(expiry) {
+			delete(b.ids, id)
+			continue
+		}
+		ids = append(ids, &BanInfo{ID: id.String(), Expiry: expiryOf(expiry)})
+	}
+	for key, ban := range b.ips {
+		if b.expired(ban.expiry) {
+			delete(b.ips, key)
+			continue
+		}
+		ips = append(ips, &BanInfo{Network: key, Expiry: expiryOf(ban.expiry)})
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].ID < ids[j].ID })
+	sort.Slice(ips, func(i, j int) bool { return ips[i].Network < ips[j].Network })
+	return append(ids, ips...)
+}
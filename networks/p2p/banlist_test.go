@@ -0,0 +1,126 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+)
+
+func newTestBanList() (*banList, *time.Time) {
+	now := time.Unix(1600000000, 0)
+	b := newBanList()
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestBanList_ID(t *testing.T) {
+	b, now := newTestBanList()
+	permanent, temporary := uintID(1), uintID(2)
+
+	b.banID(permanent, time.Time{})
+	b.banID(temporary, now.Add(time.Hour))
+	if !b.isIDBanned(permanent) || !b.isIDBanned(temporary) {
+		t.Fatal("banned IDs should be banned")
+	}
+	if b.isIDBanned(uintID(3)) {
+		t.Fatal("unbanned ID should not be banned")
+	}
+
+	*now = now.Add(time.Hour)
+	if !b.isIDBanned(permanent) {
+		t.Fatal("permanent ban should not expire")
+	}
+	if b.isIDBanned(temporary) {
+		t.Fatal("temporary ban should expire")
+	}
+	if b.unbanID(temporary) {
+		t.Fatal("unbanID should return false for an expired ban")
+	}
+	if !b.unbanID(permanent) || b.isIDBanned(permanent) {
+		t.Fatal("ID should not be banned after unbanID")
+	}
+}
+
+func TestBanList_IP(t *testing.T) {
+	b, now := newTestBanList()
+
+	if _, err := b.banIP("10.0.0.0/8", time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.banIP("192.168.0.1", now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.banIP("invalid", time.Time{}); err == nil {
+		t.Fatal("expected an error for an invalid network")
+	}
+	tests := []struct {
+		ip     string
+		banned bool
+	}{
+		{"10.1.2.3", true},
+		{"192.168.0.1", true},
+		{"192.168.0.2", false},
+		{"127.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if banned := b.isIPBanned(net.ParseIP(tt.ip)); banned != tt.banned {
+			t.Errorf("isIPBanned(%s) = %v, want %v", tt.ip, banned, tt.banned)
+		}
+	}
+	n := discover.NewNode(uintID(1), net.ParseIP("10.0.0.1"), 30303, 30303, nil, discover.NodeTypeUnknown)
+	if !b.isBanned(n) {
+		t.Error("node in the banned network should be banned")
+	}
+
+	*now = now.Add(time.Minute)
+	if b.isIPBanned(net.ParseIP("192.168.0.1")) {
+		t.Error("temporary ban should expire")
+	}
+	if err := b.unbanIP("192.168.0.1"); err == nil {
+		t.Error("expected an error for unbanning an expired network")
+	}
+	if err := b.unbanIP("10.0.0.0/8"); err != nil {
+		t.Fatal(err)
+	}
+	if b.isIPBanned(net.ParseIP("10.1.2.3")) {
+		t.Error("network should not be banned after unbanIP")
+	}
+}
+
+func TestBanList_List(t *testing.T) {
+	b, now := newTestBanList()
+	expiry := now.Add(time.Hour)
+
+	b.banID(uintID(1), expiry)
+	b.banID(uintID(2), now.Add(-time.Second))
+	b.banIP("10.0.0.1/8", time.Time{})
+
+	bans := b.list()
+	if len(bans) != 2 {
+		t.Fatalf("got %d bans, want 2", len(bans))
+	}
+	if bans[0].ID != uintID(1).String() || bans[0].Expiry == nil || !bans[0].Expiry.Equal(expiry) {
+		t.Errorf("unexpected ID ban %+v", bans[0])
+	}
+	if bans[1].Network != "10.0.0.0/8" || bans[1].Expiry != nil {
+		t.Errorf("unexpected IP ban %+v", bans[1])
+	}
+}
@@ -108,6 +108,11 @@ type dialstate struct {
 	bootnodes []*discover.Node // default dials when there are no peers
 
 	tsMap map[dialType]typedStatic // tsMap holds typedStaticDial per dialType(discovery name)
+
+	scorer   *peerScorer      // prioritizes dynamic dials by peer scores if set
+	bans     *banList         // skips the banned nodes if set
+	ipPolicy netutil.IPPolicy // skips the nodes of the address families not allowed
+	dns      *dnsNodeSource   // provides dial candidates from DNS node trees if set
 }
 
 // the dial history remembers recent dials.
@@ -275,7 +280,11 @@ func (s *dialstate) newTasks(nRunning int, peers map[discover.NodeID]*Peer, now
 	var newtasks []task
 	addDialTask := func(flag connFlag, n *discover.Node) bool {
 		logger.Trace("[Dial] Try to add dialTask", "connFlag", flag, "node", n)
-		if err := s.checkDial(n, peers); err != nil {
+		err := s.checkDial(n, peers)
+		if err == nil && s.scorer != nil && s.scorer.isBanned(n.ID) {
+			err = errBanned
+		}
+		if err != nil {
 			logger.Trace("[Dial] Skipping dial candidate from discovery nodes", "id", n.ID,
 				"addr", &net.TCPAddr{IP: n.IP, Port: int(n.TCP)}, "err", err)
 			return false
@@ -341,7 +350,7 @@ func (s *dialstate) newTasks(nRunning int, peers map[discover.NodeID]*Peer, now
 		for id, t := range s.static {
 			err := checkStaticDial(t, peers)
 			switch err {
-			case errNotWhitelisted, errSelf:
+			case errNotWhitelisted, errIPPolicy, errSelf:
 				logger.Info("[Dial] Removing static dial candidate from static nodes", "id",
 					t.dest.ID, "addr", &net.TCPAddr{IP: t.dest.IP, Port: int(t.dest.TCP)}, "err", err)
 				delete(s.static, t.dest.ID)
@@ -394,7 +403,7 @@ func (s *dialstate) newTasks(nRunning int, peers map[discover.NodeID]*Peer, now
 	// Use random nodes from the table for half of the necessary
 	// dynamic dials.
 	randomCandidates := needDynDials / 2
-	if randomCandidates > 0 {
+	if randomCandidates > 0 && s.ntab != nil {
 		n := s.ntab.ReadRandomNodes(s.randomNodes, discover.NodeTypeEN)
 		for i := 0; i < randomCandidates && i < n; i++ {
 			if addDialTask(dynDialedConn, s.randomNodes[i]) {
@@ -403,7 +412,10 @@ func (s *dialstate) newTasks(nRunning int, peers map[discover.NodeID]*Peer, now
 		}
 	}
 	// Create dynamic dials from random lookup results, removing tried
-	// items from the result buffer.
+	// items from the result buffer. Nodes with higher scores are dialed first.
+	if s.scorer != nil {
+		s.scorer.sortByScore(s.lookupBuf)
+	}
 	i := 0
 	for ; i < len(s.lookupBuf) && needDynDials > 0; i++ {
 		if addDialTask(dynDialedConn, s.lookupBuf[i]) {
@@ -411,8 +423,16 @@ func (s *dialstate) newTasks(nRunning int, peers map[discover.NodeID]*Peer, now
 		}
 	}
 	s.lookupBuf = s.lookupBuf[:copy(s.lookupBuf, s.lookupBuf[i:])]
+	// Fill the rest with the nodes from the DNS node trees.
+	if s.dns != nil && needDynDials > 0 {
+		for _, n := range s.dns.randomNodes(needDynDials) {
+			if addDialTask(dynDialedConn, n) {
+				needDynDials--
+			}
+		}
+	}
 	// Launch a discovery lookup if more candidates are needed.
-	if len(s.lookupBuf) < needDynDials && !s.lookupRunning {
+	if len(s.lookupBuf) < needDynDials && !s.lookupRunning && s.ntab != nil {
 		s.lookupRunning = true
 		newtasks = append(newtasks, &discoverTask{})
 	}
@@ -434,9 +454,12 @@ var (
 	errAlreadyConnected   = errors.New("already connected")
 	errRecentlyDialed     = errors.New("recently dialed")
 	errNotWhitelisted     = errors.New("not contained in netrestrict whitelist")
+	errIPPolicy           = errors.New("not allowed by the IP policy")
 	errExpired            = errors.New("is expired")
 	errExceedMaxTypedDial = errors.New("exceeded max typed dial")
 	errUpdateDial         = errors.New("updated to be multichannel peer")
+	errBanned             = errors.New("is banned by low score")
+	errBannedByOperator   = errors.New("is banned by the operator")
 )
 
 func (s *dialstate) checkDial(n *discover.Node, peers map[discover.NodeID]*Peer) error {
@@ -450,8 +473,12 @@ func (s *dialstate) checkDial(n *discover.Node, peers map[discover.NodeID]*Peer)
 		return errSelf
 	case s.netrestrict != nil && !s.netrestrict.Contains(n.IP):
 		return errNotWhitelisted
+	case !s.ipPolicy.Allows(n.IP):
+		return errIPPolicy
 	case s.hist.contains(n.ID):
 		return errRecentlyDialed
+	case s.bans != nil && s.bans.isBanned(n):
+		return errBannedByOperator
 	}
 	return nil
 }
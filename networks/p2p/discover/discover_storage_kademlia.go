@@ -35,6 +35,11 @@ import (
 const (
 	tableIPLimit, tableSubnet   = 10, 24
 	bucketIPLimit, bucketSubnet = 2, 24 // at most 2 addresses from the same /24
+
+	// IPv6 hosts usually get a /64 or larger from a /48 or /56 site allocation,
+	// so the IPv6 limits apply to a /48 rather than a /24 which contains whole ISPs.
+	tableSubnet6, bucketSubnet6 = 48, 48
+
 	// We keep buckets for the upper 1/15 of distances because
 	// it's very unlikely we'll ever encounter a node that's closer.
 	hashBits          = len(common.Hash{}) * 8
@@ -56,12 +61,12 @@ type KademliaStorage struct {
 
 func (s *KademliaStorage) init() {
 	s.localLogger = logger.NewWith("Discover", "Kademlia")
-	s.ips = netutil.DistinctNetSet{Subnet: tableSubnet, Limit: tableIPLimit}
+	s.ips = netutil.DistinctNetSet{Subnet: tableSubnet, Subnet6: tableSubnet6, Limit: tableIPLimit}
 	s.bucketsMu.Lock()
 	defer s.bucketsMu.Unlock()
 	for i := range s.buckets {
 		s.buckets[i] = &bucket{
-			ips: netutil.DistinctNetSet{Subnet: bucketSubnet, Limit: bucketIPLimit},
+			ips: netutil.DistinctNetSet{Subnet: bucketSubnet, Subnet6: bucketSubnet6, Limit: bucketIPLimit},
 		}
 	}
 }
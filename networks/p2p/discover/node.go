@@ -38,6 +38,7 @@ import (
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/crypto"
 	"github.com/klaytn/klaytn/crypto/secp256k1"
+	"github.com/klaytn/klaytn/networks/p2p/netutil"
 )
 
 const NodeIDBits = 512
@@ -142,6 +143,15 @@ func (n *Node) String() string {
 var incompleteNodeURL = regexp.MustCompile("(?i)^(?:kni://|enode://)?([0-9a-f]+)$")
 var lookupIPFunc = net.LookupIP
 
+// resolvePolicy selects the address of the host names in node URLs.
+var resolvePolicy = netutil.DualStack
+
+// SetResolvePolicy sets the policy selecting the address among the resolved
+// addresses of the host names in node URLs parsed afterwards.
+func SetResolvePolicy(policy netutil.IPPolicy) {
+	resolvePolicy = policy
+}
+
 // ParseNode parses a node designator.
 //
 // There are two basic forms of node designators
@@ -205,7 +215,9 @@ func parseComplete(rawurl string) (*Node, error) {
 		if err != nil {
 			return nil, err
 		}
-		ip = ips[0]
+		if ip = resolvePolicy.Select(ips); ip == nil {
+			return nil, fmt.Errorf("no %v address of host %s", resolvePolicy, u.Hostname())
+		}
 	}
 
 	// Ensure the IP is 4 bytes long for IPv4 addresses.
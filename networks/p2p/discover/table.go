@@ -84,10 +84,11 @@ type Discovery interface {
 }
 
 type Table struct {
-	nursery []*Node     // bootstrap nodes
-	rand    *mrand.Rand // source of randomness, periodically reseeded
-	randMu  sync.Mutex
-	ips     netutil.DistinctNetSet
+	nursery   []*Node // bootstrap nodes
+	nurseryMu sync.RWMutex
+	rand      *mrand.Rand // source of randomness, periodically reseeded
+	randMu    sync.Mutex
+	ips       netutil.DistinctNetSet
 
 	db         *nodeDB // database of known nodes
 	refreshReq chan chan struct{}
@@ -107,6 +108,9 @@ type Table struct {
 	storages   map[NodeType]discoverStorage
 	storagesMu sync.RWMutex
 
+	topics   map[Topic]bool // topics advertised by the local node
+	topicsMu sync.Mutex
+
 	localLogger log.Logger
 }
 
@@ -145,6 +149,7 @@ func newTable(cfg *Config) (Discovery, error) {
 		closed:      make(chan struct{}),
 		rand:        mrand.New(mrand.NewSource(0)),
 		storages:    make(map[NodeType]discoverStorage),
+		topics:      make(map[Topic]bool),
 		localLogger: logger.NewWith("Discover", "Table"),
 	}
 
@@ -202,14 +207,35 @@ func (tab *Table) setFallbackNodes(nodes []*Node) error {
 			return fmt.Errorf("bad bootstrap/fallback node %q (%v)", n, err)
 		}
 	}
-	tab.nursery = make([]*Node, 0, len(nodes))
+	nursery := make([]*Node, 0, len(nodes))
 	for _, n := range nodes {
 		cpy := *n
 		// Recompute cpy.sha because the node might not have been
 		// created by NewNode or ParseNode.
 		cpy.sha = crypto.Keccak256Hash(n.ID[:])
-		tab.nursery = append(tab.nursery, &cpy)
+		nursery = append(nursery, &cpy)
+	}
+	tab.nurseryMu.Lock()
+	tab.nursery = nursery
+	tab.nurseryMu.Unlock()
+	return nil
+}
+
+// bootnodes returns the initial points of contact set by setFallbackNodes.
+func (tab *Table) bootnodes() []*Node {
+	tab.nurseryMu.RLock()
+	defer tab.nurseryMu.RUnlock()
+
+	return append([]*Node{}, tab.nursery...)
+}
+
+// SetBootnodes replaces the initial points of contact of the running table
+// and refreshes the table to bond with them.
+func (tab *Table) SetBootnodes(nodes []*Node) error {
+	if err := tab.setFallbackNodes(nodes); err != nil {
+		return err
 	}
+	go tab.refresh()
 	return nil
 }
 
@@ -429,6 +455,7 @@ func (tab *Table) loop() {
 		revalidate     = time.NewTimer(tab.nextRevalidateTime())
 		refresh        = time.NewTicker(refreshInterval)
 		copyNodes      = time.NewTicker(copyNodesInterval)
+		advertise      = time.NewTicker(topicAdvertiseInterval)
 		revalidateDone = make(chan struct{})
 		refreshDone    = make(chan struct{})           // where doRefresh reports completion
 		waiting        = []chan struct{}{tab.initDone} // holds waiting callers while doRefresh runs
@@ -436,6 +463,7 @@ func (tab *Table) loop() {
 	defer refresh.Stop()
 	defer revalidate.Stop()
 	defer copyNodes.Stop()
+	defer advertise.Stop()
 
 	// Start initial refresh.
 	go tab.doRefresh(refreshDone)
@@ -467,6 +495,8 @@ loop:
 			revalidate.Reset(tt)
 		case <-copyNodes.C:
 			go tab.copyBondedNodes()
+		case <-advertise.C:
+			go tab.advertiseTopics()
 		case <-tab.closeReq:
 			break loop
 		}
@@ -508,7 +538,7 @@ func (tab *Table) loadSeedNodes(bond bool) {
 	// TODO-Klaytn-Node Separate logic to storages.
 	seeds := tab.db.querySeeds(seedCount, seedMaxAge)
 	seeds = removeBn(seeds)
-	seeds = append(seeds, tab.nursery...)
+	seeds = append(seeds, tab.bootnodes()...)
 	if bond {
 		seeds = tab.bondall(seeds)
 	}
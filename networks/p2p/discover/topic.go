@@ -0,0 +1,280 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package discover
+
+import (
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Topic is a capability advertised through the discovery, e.g. "servicechain",
+// "archive" or "bootnode". Nodes looking for a specific kind of peers can
+// query the nodes registered with the topic instead of dialing arbitrary nodes.
+//
+// The topic advertisement follows the idea of discovery v5 topic tables, but
+// it is not discovery v5: the topics are registered at a few registrars (boot
+// nodes preferred) over the existing discovery wire protocol, and the dialer
+// does not select peers by topic. The advertised nodes are only reported by
+// LookupTopic and TopicNodes.
+// TODO-Klaytn-P2P Migrate to the discovery v5 wire protocol.
+type Topic string
+
+const (
+	maxTopicLength         = 64
+	maxTopicsPerRegister   = 8                // maximum number of topics in a register packet
+	maxTopics              = 256              // maximum number of topics in a topic table
+	maxTopicNodes          = 128              // maximum number of nodes registered with a topic
+	topicTTL               = 20 * time.Minute // registrations expire after topicTTL
+	topicAdvertiseInterval = 10 * time.Minute // must be shorter than topicTTL
+	maxTopicRegistrars     = 8                // maximum number of nodes the topics are registered at
+)
+
+// TopicDiscovery is implemented by the discoveries which support the topic advertisement.
+type TopicDiscovery interface {
+	// RegisterTopic advertises the local node with the topic until UnregisterTopic is called.
+	RegisterTopic(topic Topic)
+	// UnregisterTopic stops advertising the topic.
+	UnregisterTopic(topic Topic)
+	// LookupTopic returns at most max nodes advertised with the topic.
+	LookupTopic(topic Topic, max int) []*Node
+	// LocalTopics returns the topics advertised by the local node.
+	LocalTopics() []Topic
+	// TopicNodes returns the nodes registered with the topic at the local node.
+	TopicNodes(topic Topic) []*Node
+}
+
+// topicTransport is implemented by the transports which support the topic advertisement.
+type topicTransport interface {
+	registerTopics(toid NodeID, toaddr *net.UDPAddr, topics []Topic) error
+	topicQuery(toid NodeID, toaddr *net.UDPAddr, topic Topic, max int) ([]*Node, error)
+	topicNodes(topic Topic) []*Node
+}
+
+type topicEntry struct {
+	node    *Node
+	expires time.Time
+}
+
+// topicTable keeps the nodes registered with topics by the remote nodes.
+type topicTable struct {
+	mu      sync.Mutex
+	entries map[Topic]map[NodeID]*topicEntry
+
+	now func() time.Time // for testing
+}
+
+func newTopicTable() *topicTable {
+	return &topicTable{
+		entries: make(map[Topic]map[NodeID]*topicEntry),
+		now:     time.Now,
+	}
+}
+
+// add registers n with the topic. It returns false if the table is full.
+func (tt *topicTable) add(topic Topic, n *Node) bool {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+
+	now := tt.now()
+	nodes, ok := tt.entries[topic]
+	if !ok {
+		if len(tt.entries) >= maxTopics {
+			tt.expire(now)
+			if len(tt.entries) >= maxTopics {
+				return false
+			}
+		}
+		nodes = make(map[NodeID]*topicEntry)
+		tt.entries[topic] = nodes
+	}
+	if _, exist := nodes[n.ID]; !exist && len(nodes) >= maxTopicNodes {
+		// Evict the registration which expires first.
+		var oldest *topicEntry
+		for _, e := range nodes {
+			if oldest == nil || e.expires.Before(oldest.expires) {
+				oldest = e
+			}
+		}
+		delete(nodes, oldest.node.ID)
+	}
+	nodes[n.ID] = &topicEntry{node: n, expires: now.Add(topicTTL)}
+	return true
+}
+
+// get returns at most max nodes registered with the topic, the latest registration first.
+func (tt *topicTable) get(topic Topic, max int) []*Node {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+
+	now := tt.now()
+	entries := make([]*topicEntry, 0, len(tt.entries[topic]))
+	for id, e := range tt.entries[topic] {
+		if !e.expires.After(now) {
+			delete(tt.entries[topic], id)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].expires.After(entries[j].expires)
+	})
+	if len(entries) > max {
+		entries = entries[:max]
+	}
+	nodes := make([]*Node, len(entries))
+	for i, e := range entries {
+		nodes[i] = e.node
+	}
+	return nodes
+}
+
+// expire removes the expired registrations. The caller must hold tt.mu.
+func (tt *topicTable) expire(now time.Time) {
+	for topic, nodes := range tt.entries {
+		for id, e := range nodes {
+			if !e.expires.After(now) {
+				delete(nodes, id)
+			}
+		}
+		if len(nodes) == 0 {
+			delete(tt.entries, topic)
+		}
+	}
+}
+
+func validTopic(topic Topic) bool {
+	return len(topic) > 0 && len(topic) <= maxTopicLength
+}
+
+// RegisterTopic advertises the local node with the topic until UnregisterTopic is called.
+func (tab *Table) RegisterTopic(topic Topic) {
+	if !validTopic(topic) {
+		tab.localLogger.Warn("Ignoring invalid topic", "topic", topic)
+		return
+	}
+	tab.topicsMu.Lock()
+	tab.topics[topic] = true
+	tab.topicsMu.Unlock()
+
+	go tab.advertiseTopics()
+}
+
+// UnregisterTopic stops advertising the topic. The registrations at the remote
+// nodes remain until they expire.
+func (tab *Table) UnregisterTopic(topic Topic) {
+	tab.topicsMu.Lock()
+	defer tab.topicsMu.Unlock()
+
+	delete(tab.topics, topic)
+}
+
+// LocalTopics returns the topics advertised by the local node.
+func (tab *Table) LocalTopics() []Topic {
+	tab.topicsMu.Lock()
+	defer tab.topicsMu.Unlock()
+
+	topics := make([]Topic, 0, len(tab.topics))
+	for topic := range tab.topics {
+		topics = append(topics, topic)
+	}
+	sort.Slice(topics, func(i, j int) bool { return topics[i] < topics[j] })
+	return topics
+}
+
+// TopicNodes returns the nodes registered with the topic at the local node.
+func (tab *Table) TopicNodes(topic Topic) []*Node {
+	tt, ok := tab.net.(topicTransport)
+	if !ok {
+		return nil
+	}
+	return tt.topicNodes(topic)
+}
+
+// LookupTopic queries the topic registrars for the nodes advertised with the
+// topic and returns at most max nodes. max is capped at maxTopicNodes, the
+// number of nodes a registrar keeps for a topic.
+func (tab *Table) LookupTopic(topic Topic, max int) []*Node {
+	tt, ok := tab.net.(topicTransport)
+	if !ok || !validTopic(topic) || max <= 0 {
+		return nil
+	}
+	if max > maxTopicNodes {
+		max = maxTopicNodes
+	}
+	var (
+		registrars = tab.topicRegistrars()
+		reply      = make(chan []*Node, len(registrars))
+		seen       = map[NodeID]bool{tab.self.ID: true}
+		result     []*Node
+	)
+	for _, r := range registrars {
+		go func(r *Node) {
+			nodes, err := tt.topicQuery(r.ID, r.addr(), topic, max)
+			if err != nil {
+				tab.localLogger.Trace("Topic query failed", "topic", topic, "registrar", r.ID, "err", err)
+			}
+			reply <- nodes
+		}(r)
+	}
+	for range registrars {
+		for _, n := range <-reply {
+			if !seen[n.ID] && len(result) < max {
+				seen[n.ID] = true
+				result = append(result, n)
+			}
+		}
+	}
+	return result
+}
+
+// advertiseTopics registers the local topics at the topic registrars.
+func (tab *Table) advertiseTopics() {
+	tt, ok := tab.net.(topicTransport)
+	if !ok {
+		return
+	}
+	topics := tab.LocalTopics()
+	if len(topics) == 0 {
+		return
+	}
+	for _, r := range tab.topicRegistrars() {
+		for start := 0; start < len(topics); start += maxTopicsPerRegister {
+			end := start + maxTopicsPerRegister
+			if end > len(topics) {
+				end = len(topics)
+			}
+			if err := tt.registerTopics(r.ID, r.addr(), topics[start:end]); err != nil {
+				tab.localLogger.Debug("Failed to register topics", "registrar", r.ID, "err", err)
+			}
+		}
+	}
+}
+
+// topicRegistrars returns the nodes which keep the topic tables. Boot nodes are
+// preferred because every node type bonds with them.
+func (tab *Table) topicRegistrars() []*Node {
+	registrars := tab.GetNodes(NodeTypeBN, maxTopicRegistrars)
+	if len(registrars) == 0 {
+		registrars = append(registrars, tab.bootnodes()...)
+	}
+	if len(registrars) > maxTopicRegistrars {
+		registrars = registrars[:maxTopicRegistrars]
+	}
+	return registrars
+}
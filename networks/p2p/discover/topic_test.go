@@ -0,0 +1,165 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package discover
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestTopicTable(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	tt := newTopicTable()
+	tt.now = func() time.Time { return now }
+
+	n1 := NewNode(NodeID{1}, net.IP{10, 0, 0, 1}, 32323, 32323, nil, NodeTypeEN)
+	n2 := NewNode(NodeID{2}, net.IP{10, 0, 0, 2}, 32323, 32323, nil, NodeTypeEN)
+
+	tt.add("archive", n1)
+	now = now.Add(time.Minute)
+	tt.add("archive", n2)
+	tt.add("servicechain", n2)
+
+	// The latest registration comes first.
+	if nodes := tt.get("archive", 10); len(nodes) != 2 || nodes[0].ID != n2.ID || nodes[1].ID != n1.ID {
+		t.Errorf("unexpected archive nodes: %v", nodes)
+	}
+	if nodes := tt.get("archive", 1); len(nodes) != 1 || nodes[0].ID != n2.ID {
+		t.Errorf("unexpected limited archive nodes: %v", nodes)
+	}
+	if nodes := tt.get("unknown", 10); len(nodes) != 0 {
+		t.Errorf("unexpected nodes of unknown topic: %v", nodes)
+	}
+
+	// The registration of n1 expires first.
+	now = now.Add(topicTTL - 30*time.Second)
+	if nodes := tt.get("archive", 10); len(nodes) != 1 || nodes[0].ID != n2.ID {
+		t.Errorf("unexpected archive nodes after expiration: %v", nodes)
+	}
+}
+
+func TestTopicTable_Limits(t *testing.T) {
+	tt := newTopicTable()
+
+	for i := 0; i < maxTopicNodes+1; i++ {
+		var id NodeID
+		id[0], id[1] = byte(i>>8), byte(i)
+		tt.add("archive", NewNode(id, net.IP{10, 0, 0, 1}, 32323, 32323, nil, NodeTypeEN))
+	}
+	if nodes := tt.get("archive", maxTopicNodes*2); len(nodes) != maxTopicNodes {
+		t.Errorf("unexpected number of nodes: have %d, want %d", len(nodes), maxTopicNodes)
+	}
+
+	n := NewNode(NodeID{1}, net.IP{10, 0, 0, 1}, 32323, 32323, nil, NodeTypeEN)
+	for i := 0; len(tt.entries) < maxTopics; i++ {
+		tt.add(Topic(fmt.Sprintf("topic-%d", i)), n)
+	}
+	if tt.add("one-more", n) {
+		t.Error("topic table should be full")
+	}
+}
+
+func TestUDP_topicRegisterAndQuery(t *testing.T) {
+	test := newUDPTest(t)
+	defer test.table.Close()
+
+	// Registrations from the unbonded nodes are rejected.
+	test.packetIn(errUnknownNode, topicRegisterPacket, &topicRegister{Topics: []Topic{"archive"}, TCP: 32323, Expiration: futureExp})
+
+	remoteID := PubkeyID(&test.remotekey.PublicKey)
+	test.table.db.updateBondTime(remoteID, time.Now())
+	test.packetIn(nil, topicRegisterPacket, &topicRegister{Topics: []Topic{"archive"}, TCP: 32323, NType: NodeTypeEN, Expiration: futureExp})
+
+	nodes := test.udp.topicNodes("archive")
+	if len(nodes) != 1 || nodes[0].ID != remoteID || nodes[0].TCP != 32323 || nodes[0].NType != NodeTypeEN {
+		t.Fatalf("unexpected registered nodes: %v", nodes)
+	}
+
+	// The querying node itself is excluded from the reply.
+	test.packetIn(nil, topicQueryPacket, &topicQuery{Topic: "archive", Expiration: futureExp})
+	test.waitPacketOut(func(p *topicNodes) {
+		if p.Topic != "archive" || len(p.Nodes) != 0 {
+			t.Errorf("unexpected topic nodes: %v", p)
+		}
+	})
+}
+
+func TestUDP_topicQuery(t *testing.T) {
+	test := newUDPTest(t)
+	defer test.table.Close()
+
+	resultc, errc := make(chan []*Node), make(chan error)
+	go func() {
+		rid := PubkeyID(&test.remotekey.PublicKey)
+		ns, err := test.udp.topicQuery(rid, test.remoteaddr, "servicechain", 10)
+		if err != nil {
+			errc <- err
+		} else {
+			resultc <- ns
+		}
+	}()
+	test.waitPacketOut(func(p *topicQuery) {
+		if p.Topic != "servicechain" {
+			t.Errorf("wrong topic: got %v, want servicechain", p.Topic)
+		}
+	})
+
+	n := MustParseNode("kni://1b5b4aa662d7cb44a7221bfba67302590b643028197a7d5214790f3bac7aaa4a3241be9e83c09cf1f6c69d007c634faae3dc1b1221793e8446c0b3a09de65960@10.0.1.19:30303")
+	// The reply of another topic is not matched.
+	test.packetIn(errUnsolicitedReply, topicNodesPacket, &topicNodes{Topic: "archive", Expiration: futureExp, Nodes: []rpcNode{nodeToRPC(n)}})
+	test.packetIn(nil, topicNodesPacket, &topicNodes{Topic: "servicechain", Expiration: futureExp, Nodes: []rpcNode{nodeToRPC(n)}})
+
+	select {
+	case result := <-resultc:
+		if len(result) != 1 || result[0].ID != n.ID {
+			t.Errorf("unexpected result: %v", result)
+		}
+	case err := <-errc:
+		t.Errorf("topicQuery error: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Error("topicQuery did not return within 5 seconds")
+	}
+}
+
+func TestUDP_topicQuery_MaxCapped(t *testing.T) {
+	test := newUDPTest(t)
+	defer test.table.Close()
+
+	resultc := make(chan []*Node, 1)
+	go func() {
+		rid := PubkeyID(&test.remotekey.PublicKey)
+		// An unbounded max must not be used as the capacity of the result.
+		ns, _ := test.udp.topicQuery(rid, test.remoteaddr, "archive", math.MaxInt32)
+		resultc <- ns
+	}()
+	test.waitPacketOut(func(p *topicQuery) {})
+
+	n := MustParseNode("kni://1b5b4aa662d7cb44a7221bfba67302590b643028197a7d5214790f3bac7aaa4a3241be9e83c09cf1f6c69d007c634faae3dc1b1221793e8446c0b3a09de65960@10.0.1.19:30303")
+	test.packetIn(nil, topicNodesPacket, &topicNodes{Topic: "archive", Expiration: futureExp, Nodes: []rpcNode{nodeToRPC(n)}})
+
+	select {
+	case result := <-resultc:
+		if len(result) != 1 || cap(result) > maxTopicNodes {
+			t.Errorf("unexpected result: len %d, cap %d", len(result), cap(result))
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("topicQuery did not return within 5 seconds")
+	}
+}
@@ -49,6 +49,7 @@ var (
 	errClosed           = errors.New("socket closed")
 	errUnauthorized     = errors.New("unauthorized node")
 	errMismatchNetwork  = errors.New("mismatch network id")
+	errTooManyTopics    = errors.New("too many topics")
 )
 
 // Timeouts
@@ -67,6 +68,9 @@ const (
 	pongPacket
 	findnodePacket
 	neighborsPacket
+	topicRegisterPacket
+	topicQueryPacket
+	topicNodesPacket
 )
 
 // Node types
@@ -120,6 +124,33 @@ type (
 		Rest []rlp.RawValue `rlp:"tail"`
 	}
 
+	// topicRegister advertises the sender with the topics.
+	topicRegister struct {
+		Topics     []Topic
+		TCP        uint16 // for RLPx protocol
+		NType      NodeType
+		Expiration uint64
+		// Ignore additional fields (for forward compatibility).
+		Rest []rlp.RawValue `rlp:"tail"`
+	}
+
+	// topicQuery is a query for nodes registered with the topic.
+	topicQuery struct {
+		Topic      Topic
+		Expiration uint64
+		// Ignore additional fields (for forward compatibility).
+		Rest []rlp.RawValue `rlp:"tail"`
+	}
+
+	// reply to topicQuery
+	topicNodes struct {
+		Topic      Topic
+		Nodes      []rpcNode
+		Expiration uint64
+		// Ignore additional fields (for forward compatibility).
+		Rest []rlp.RawValue `rlp:"tail"`
+	}
+
 	rpcNode struct {
 		IP    net.IP // len 4 for IPv4 or 16 for IPv6
 		UDP   uint16 // for discovery protocol
@@ -157,6 +188,9 @@ func (t *udp) nodeFromRPC(sender *net.UDPAddr, rn rpcNode) (*Node, error) {
 	if t.netrestrict != nil && !t.netrestrict.Contains(rn.IP) {
 		return nil, errors.New("not contained in netrestrict whitelist")
 	}
+	if !t.ipPolicy.Allows(rn.IP) {
+		return nil, errors.New("not allowed by the IP policy")
+	}
 	n := NewNode(rn.ID, rn.IP, rn.UDP, rn.TCP, nil, rn.NType)
 	err := n.validateComplete()
 	return n, err
@@ -183,6 +217,7 @@ type udp struct {
 	networkID   uint64
 	conn        conn
 	netrestrict *netutil.Netlist
+	ipPolicy    netutil.IPPolicy
 	priv        *ecdsa.PrivateKey
 	ourEndpoint rpcEndpoint
 
@@ -192,6 +227,8 @@ type udp struct {
 	closing chan struct{}
 	nat     nat.Interface
 
+	topics *topicTable // topics registered by the remote nodes
+
 	Discovery
 }
 
@@ -209,6 +246,7 @@ type pending struct {
 	from       NodeID
 	ptype      byte
 	targetType NodeType
+	topic      Topic // only for topicNodesPacket
 
 	// time when the request must complete
 	deadline time.Time
@@ -235,6 +273,8 @@ func (p *pending) String() string {
 		typeStr = "FINDNODE"
 	case neighborsPacket:
 		typeStr = "NEIGHBORS"
+	case topicNodesPacket:
+		typeStr = "TOPICNODES"
 	default:
 		typeStr = "UNKNOWN"
 	}
@@ -266,6 +306,7 @@ type Config struct {
 	AnnounceAddr *net.UDPAddr      // local address announced in the DHT
 	NodeDBPath   string            // if set, the node database is stored at this filesystem location
 	NetRestrict  *netutil.Netlist  // network whitelist
+	IPPolicy     netutil.IPPolicy  // address families of the nodes added to the table
 	Bootnodes    []*Node           // list of bootstrap nodes
 	Unhandled    chan<- ReadPacket // unhandled packets are sent on this channel
 
@@ -297,9 +338,11 @@ func newUDP(cfg *Config) (Discovery, *udp, error) {
 		conn:        cfg.Conn,
 		priv:        cfg.PrivateKey,
 		netrestrict: cfg.NetRestrict,
+		ipPolicy:    cfg.IPPolicy,
 		closing:     make(chan struct{}),
 		gotreply:    make(chan reply),
 		addpending:  make(chan *pending),
+		topics:      newTopicTable(),
 	}
 	realaddr := cfg.Addr
 	if cfg.AnnounceAddr != nil {
@@ -394,11 +437,70 @@ func (t *udp) findnode(toid NodeID, toaddr *net.UDPAddr, target NodeID, targetNT
 	return nodes, err
 }
 
+// registerTopics advertises the local node with the topics at the given node.
+// The remote node does not reply to the registration.
+func (t *udp) registerTopics(toid NodeID, toaddr *net.UDPAddr, topics []Topic) error {
+	_, err := t.send(toaddr, topicRegisterPacket, &topicRegister{
+		Topics:     topics,
+		TCP:        t.ourEndpoint.TCP,
+		NType:      t.ourEndpoint.NType,
+		Expiration: uint64(time.Now().Add(expiration).Unix()),
+	})
+	return err
+}
+
+// topicQuery sends a topic query to the given node and waits until
+// the node has sent up to max nodes registered with the topic. max is capped
+// at maxTopicNodes since the node never keeps more nodes for a topic.
+func (t *udp) topicQuery(toid NodeID, toaddr *net.UDPAddr, topic Topic, max int) ([]*Node, error) {
+	if max <= 0 || max > maxTopicNodes {
+		max = maxTopicNodes
+	}
+	nodes := make([]*Node, 0, max)
+	nreceived := 0
+	errc := t.pendingTopicNodes(toid, topic, func(r interface{}) bool {
+		reply := r.(*topicNodes)
+		for _, rn := range reply.Nodes {
+			nreceived++
+			n, err := t.nodeFromRPC(toaddr, rn)
+			if err != nil {
+				logger.Trace("Invalid topic node received", "ip", rn.IP, "addr", toaddr, "err", err)
+				continue
+			}
+			nodes = append(nodes, n)
+		}
+		// A reply smaller than maxTopicNodesPerPacket is the last one.
+		return nreceived >= max || len(reply.Nodes) < maxTopicNodesPerPacket
+	})
+	if _, err := t.send(toaddr, topicQueryPacket, &topicQuery{
+		Topic:      topic,
+		Expiration: uint64(time.Now().Add(expiration).Unix()),
+	}); err != nil {
+		logger.Debug("[udp] topicQuery: failed to send TOPICQUERY", "err", err)
+	}
+	err := <-errc
+	return nodes, err
+}
+
+// topicNodes returns the nodes registered with the topic at the local node.
+func (t *udp) topicNodes(topic Topic) []*Node {
+	return t.topics.get(topic, maxTopicNodes)
+}
+
 // pending adds a reply callback to the pending reply queue.
 // see the documentation of type pending for a detailed explanation.
 func (t *udp) pending(id NodeID, ptype byte, targetType NodeType, callback func(interface{}) bool) <-chan error {
+	return t.addPending(&pending{from: id, ptype: ptype, targetType: targetType, callback: callback})
+}
+
+// pendingTopicNodes adds a reply callback of the topic query to the pending reply queue.
+func (t *udp) pendingTopicNodes(id NodeID, topic Topic, callback func(interface{}) bool) <-chan error {
+	return t.addPending(&pending{from: id, ptype: topicNodesPacket, topic: topic, callback: callback})
+}
+
+func (t *udp) addPending(p *pending) <-chan error {
 	ch := make(chan error, 1)
-	p := &pending{from: id, ptype: ptype, targetType: targetType, callback: callback, errc: ch}
+	p.errc = ch
 	select {
 	case t.addpending <- p:
 		// loop will handle it
@@ -485,6 +587,10 @@ func (t *udp) loop() {
 							continue
 						}
 						pendingNeighborsCounter.Dec(1)
+					} else if p.ptype == topicNodesPacket {
+						if r.data.(*topicNodes).Topic != p.topic {
+							continue
+						}
 					}
 					matched = true
 					// Remove the matcher if its callback indicates
@@ -538,6 +644,9 @@ var (
 	// stay below the 1280 byte limit. We compute the maximum number
 	// of entries by stuffing a packet until it grows too large.
 	maxNeighbors int
+
+	// Topic nodes replies leave the room for the topic.
+	maxTopicNodesPerPacket int
 )
 
 func init() {
@@ -555,6 +664,7 @@ func init() {
 			break
 		}
 	}
+	maxTopicNodesPerPacket = maxNeighbors - 1
 }
 
 func (t *udp) send(toaddr *net.UDPAddr, ptype byte, req packet) ([]byte, error) {
@@ -661,6 +771,12 @@ func decodePacket(buf []byte) (packet, NodeID, []byte, error) {
 		req = new(findnode)
 	case neighborsPacket:
 		req = new(neighbors)
+	case topicRegisterPacket:
+		req = new(topicRegister)
+	case topicQueryPacket:
+		req = new(topicQuery)
+	case topicNodesPacket:
+		req = new(topicNodes)
 	default:
 		return nil, fromID, hash, fmt.Errorf("unknown type: %d", ptype)
 	}
@@ -769,6 +885,75 @@ func (req *neighbors) handle(t *udp, from *net.UDPAddr, fromID NodeID, mac []byt
 
 func (req *neighbors) name() string { return "NEIGHBORS/v4" }
 
+func (req *topicRegister) handle(t *udp, from *net.UDPAddr, fromID NodeID, mac []byte) error {
+	if expired(req.Expiration) {
+		return errExpired
+	}
+	if !t.HasBond(fromID) {
+		// Only the bonded nodes can register topics not to be
+		// filled with the spoofed endpoints.
+		return errUnknownNode
+	}
+	if len(req.Topics) > maxTopicsPerRegister {
+		return errTooManyTopics
+	}
+	n := NewNode(fromID, from.IP, uint16(from.Port), req.TCP, nil, req.NType)
+	if err := n.validateComplete(); err != nil {
+		return err
+	}
+	for _, topic := range req.Topics {
+		if !validTopic(topic) {
+			continue
+		}
+		if !t.topics.add(topic, n) {
+			logger.Debug("Topic table is full", "topic", topic, "from", fromID)
+		}
+	}
+	return nil
+}
+
+func (req *topicRegister) name() string { return "TOPICREGISTER/v4" }
+
+func (req *topicQuery) handle(t *udp, from *net.UDPAddr, fromID NodeID, mac []byte) error {
+	if expired(req.Expiration) {
+		return errExpired
+	}
+	if !t.HasBond(fromID) {
+		// See the comment in findnode.handle.
+		return errUnknownNode
+	}
+	p := topicNodes{Topic: req.Topic, Expiration: uint64(time.Now().Add(expiration).Unix())}
+	// Send the nodes in chunks with at most maxTopicNodesPerPacket per packet
+	// to stay below the 1280 byte limit. A chunk smaller than
+	// maxTopicNodesPerPacket notifies the end of the reply.
+	for _, n := range t.topics.get(req.Topic, maxTopicNodes) {
+		if n.ID == fromID || netutil.CheckRelayIP(from.IP, n.IP) != nil {
+			continue
+		}
+		p.Nodes = append(p.Nodes, nodeToRPC(n))
+		if len(p.Nodes) == maxTopicNodesPerPacket {
+			t.send(from, topicNodesPacket, &p)
+			p.Nodes = p.Nodes[:0]
+		}
+	}
+	t.send(from, topicNodesPacket, &p)
+	return nil
+}
+
+func (req *topicQuery) name() string { return "TOPICQUERY/v4" }
+
+func (req *topicNodes) handle(t *udp, from *net.UDPAddr, fromID NodeID, mac []byte) error {
+	if expired(req.Expiration) {
+		return errExpired
+	}
+	if !t.handleReply(fromID, topicNodesPacket, req) {
+		return errUnsolicitedReply
+	}
+	return nil
+}
+
+func (req *topicNodes) name() string { return "TOPICNODES/v4" }
+
 func expired(ts uint64) bool {
 	return time.Unix(int64(ts), 0).Before(time.Now())
 }
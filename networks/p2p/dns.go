@@ -0,0 +1,101 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"context"
+	"fmt"
+	mrand "math/rand"
+	"sync"
+	"time"
+
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+	"github.com/klaytn/klaytn/networks/p2p/dnsdisc"
+)
+
+// dnsRecheckInterval is the interval between the syncs of the DNS node trees.
+const dnsRecheckInterval = 30 * time.Minute
+
+// dnsNodeSource syncs the DNS node trees periodically and provides their nodes
+// as dynamic dial candidates.
+type dnsNodeSource struct {
+	client *dnsdisc.Client
+	urls   []string
+
+	mu    sync.Mutex
+	nodes []*discover.Node
+}
+
+func newDNSNodeSource(urls []string) (*dnsNodeSource, error) {
+	for _, url := range urls {
+		if _, _, err := dnsdisc.ParseURL(url); err != nil {
+			return nil, fmt.Errorf("invalid DNS discovery URL %q: %v", url, err)
+		}
+	}
+	return &dnsNodeSource{client: dnsdisc.NewClient(dnsdisc.Config{}), urls: urls}, nil
+}
+
+// loop syncs the trees until quit is closed.
+func (s *dnsNodeSource) loop(quit <-chan struct{}, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			nodes, err := s.client.Nodes(ctx, s.urls...)
+			if err != nil {
+				logger.Warn("Failed to sync DNS node trees", "err", err)
+			}
+			if len(nodes) > 0 {
+				s.mu.Lock()
+				s.nodes = nodes
+				s.mu.Unlock()
+			}
+			logger.Debug("Synced DNS node trees", "nodes", len(nodes))
+			timer.Reset(dnsRecheckInterval)
+		case <-quit:
+			return
+		}
+	}
+}
+
+// randomNodes returns at most max nodes randomly chosen from the trees.
+func (s *dnsNodeSource) randomNodes(max int) []*discover.Node {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if max > len(s.nodes) {
+		max = len(s.nodes)
+	}
+	nodes := make([]*discover.Node, 0, max)
+	for _, i := range mrand.Perm(len(s.nodes))[:max] {
+		nodes = append(nodes, s.nodes[i])
+	}
+	return nodes
+}
@@ -0,0 +1,243 @@
+// Modifications Copyright 2022 The klaytn Authors
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+//
+// This file is derived from p2p/dnsdisc/client.go (2022/06/29).
+// Modified and improved for the klaytn development.
+
+package dnsdisc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+)
+
+// Client discovers nodes by querying DNS servers.
+type Client struct {
+	cfg     Config
+	entries *lru.Cache
+}
+
+// Config holds configuration options for the DNS client.
+type Config struct {
+	Timeout    time.Duration // timeout used for DNS lookups (default 5s)
+	CacheLimit int           // maximum number of cached records (default 1000)
+	MaxEntries int           // maximum number of entries synced from a tree (default 10000)
+	MaxLinks   int           // maximum number of linked trees followed (default 16)
+	Resolver   Resolver      // the DNS resolver to use (defaults to system DNS)
+}
+
+// Resolver is a DNS resolver that can query TXT records.
+type Resolver interface {
+	LookupTXT(ctx context.Context, domain string) ([]string, error)
+}
+
+func (cfg Config) withDefaults() Config {
+	const (
+		defaultTimeout    = 5 * time.Second
+		defaultCache      = 1000
+		defaultMaxEntries = 10000
+		defaultMaxLinks   = 16
+	)
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.CacheLimit == 0 {
+		cfg.CacheLimit = defaultCache
+	}
+	if cfg.MaxEntries == 0 {
+		cfg.MaxEntries = defaultMaxEntries
+	}
+	if cfg.MaxLinks == 0 {
+		cfg.MaxLinks = defaultMaxLinks
+	}
+	if cfg.Resolver == nil {
+		cfg.Resolver = new(net.Resolver)
+	}
+	return cfg
+}
+
+// NewClient creates a client.
+func NewClient(cfg Config) *Client {
+	cfg = cfg.withDefaults()
+	cache, err := lru.New(cfg.CacheLimit)
+	if err != nil {
+		panic(err)
+	}
+	return &Client{cfg: cfg, entries: cache}
+}
+
+// SyncTree downloads the entire node tree at the given URL.
+func (c *Client) SyncTree(url string) (*Tree, error) {
+	return c.syncTree(context.Background(), url)
+}
+
+// Nodes downloads the trees at the given URLs and the trees linked from them,
+// and returns the nodes of all trees. The nodes of the trees synced successfully
+// are returned even if some trees fail to sync, along with the last error.
+func (c *Client) Nodes(ctx context.Context, urls ...string) ([]*discover.Node, error) {
+	var (
+		nodes   []*discover.Node
+		lastErr error
+		seen    = make(map[discover.NodeID]bool)
+		visited = make(map[string]bool)
+		queue   = append([]string{}, urls...)
+	)
+	for len(queue) > 0 {
+		url := queue[0]
+		queue = queue[1:]
+		if visited[url] {
+			continue
+		}
+		if len(visited) > len(urls)+c.cfg.MaxLinks {
+			lastErr = errLinkLoop
+			break
+		}
+		visited[url] = true
+
+		t, err := c.syncTree(ctx, url)
+		if err != nil {
+			lastErr = fmt.Errorf("%s: %v", url, err)
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		for _, n := range t.Nodes() {
+			if !seen[n.ID] {
+				seen[n.ID] = true
+				nodes = append(nodes, n)
+			}
+		}
+		queue = append(queue, t.Links()...)
+	}
+	return nodes, lastErr
+}
+
+func (c *Client) syncTree(ctx context.Context, url string) (*Tree, error) {
+	le, err := parseLink(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tree URL: %v", err)
+	}
+	if le.domain == "" || strings.ContainsAny(le.domain, " \t\r\n") {
+		return nil, errInvalidDomain
+	}
+	root, err := c.resolveRoot(ctx, le)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tree{root: &root, entries: make(map[string]entry)}
+	if err := c.syncAll(ctx, le.domain, root.eroot, t.entries); err != nil {
+		return nil, err
+	}
+	if err := c.syncAll(ctx, le.domain, root.lroot, t.entries); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// syncAll downloads the entry with the given hash and all its descendants.
+// The entries are content addressed, so the tree cannot contain a loop.
+func (c *Client) syncAll(ctx context.Context, domain, hash string, dest map[string]entry) error {
+	if _, ok := dest[hash]; ok {
+		return nil
+	}
+	if len(dest) >= c.cfg.MaxEntries {
+		return errTooManyNodes
+	}
+	e, err := c.resolveEntry(ctx, domain, hash)
+	if err != nil {
+		return err
+	}
+	dest[hash] = e
+	if branch, ok := e.(*branchEntry); ok {
+		for _, child := range branch.children {
+			if err := c.syncAll(ctx, domain, child, dest); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolveRoot retrieves a root entry via DNS.
+func (c *Client) resolveRoot(ctx context.Context, loc *linkEntry) (rootEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	txts, err := c.cfg.Resolver.LookupTXT(ctx, loc.domain)
+	if err != nil {
+		return rootEntry{}, err
+	}
+	for _, txt := range txts {
+		if strings.HasPrefix(txt, rootPrefix) {
+			e, err := parseRoot(txt)
+			if err != nil {
+				return e, nameError{loc.domain, err}
+			}
+			if !e.verifySignature(loc.pubkey) {
+				return e, nameError{loc.domain, entryError{typ: "root", err: errInvalidSig}}
+			}
+			return e, nil
+		}
+	}
+	return rootEntry{}, nameError{loc.domain, errNoRoot}
+}
+
+// resolveEntry retrieves an entry from the cache or fetches it from the network
+// if it isn't cached.
+func (c *Client) resolveEntry(ctx context.Context, domain, hash string) (entry, error) {
+	cacheKey := truncateHash(hash) + "." + domain
+	if e, ok := c.entries.Get(cacheKey); ok {
+		return e.(entry), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	name := hash + "." + domain
+	wantHash, err := b32format.DecodeString(hash)
+	if err != nil {
+		return nil, nameError{name, errInvalidChild}
+	}
+	txts, err := c.cfg.Resolver.LookupTXT(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, txt := range txts {
+		e, err := parseEntry(txt)
+		if err == errUnknownEntry {
+			continue
+		}
+		if !bytes.HasPrefix(crypto.Keccak256([]byte(txt)), wantHash) {
+			return nil, nameError{name, errHashMismatch}
+		}
+		if err != nil {
+			return nil, nameError{name, err}
+		}
+		c.entries.Add(cacheKey, e)
+		return e, nil
+	}
+	return nil, nameError{name, errNoEntry}
+}
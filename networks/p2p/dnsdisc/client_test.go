@@ -0,0 +1,187 @@
+// Modifications Copyright 2022 The klaytn Authors
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+//
+// This file is derived from p2p/dnsdisc/client_test.go (2022/06/29).
+// Modified and improved for the klaytn development.
+
+package dnsdisc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"net"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+)
+
+// mapResolver is a DNS resolver serving the records from a map.
+type mapResolver map[string]string
+
+func (mr mapResolver) add(m map[string]string) {
+	for k, v := range m {
+		mr[k] = v
+	}
+}
+
+func (mr mapResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if record, ok := mr[name]; ok {
+		return []string{record}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func testNodes(seed byte, n int) []*discover.Node {
+	nodes := make([]*discover.Node, n)
+	for i := range nodes {
+		key, _ := crypto.ToECDSA(crypto.Keccak256([]byte{seed, byte(i)}))
+		nodes[i] = discover.NewNode(discover.PubkeyID(&key.PublicKey), net.IP{10, seed, 0, byte(i)}, 32323, 32323, nil, discover.NodeTypeEN)
+	}
+	return nodes
+}
+
+func testKey(seed byte) *ecdsa.PrivateKey {
+	key, _ := crypto.ToECDSA(crypto.Keccak256([]byte("key"), []byte{seed}))
+	return key
+}
+
+func nodeIDs(nodes []*discover.Node) []string {
+	ids := make([]string, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.ID.String()
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestClientSyncTree(t *testing.T) {
+	nodes := testNodes(1, 30)
+	tree, err := MakeTree(3, nodes, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	url, err := tree.Sign(testKey(1), "n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := mapResolver{}
+	r.add(tree.ToTXT("n"))
+
+	c := NewClient(Config{Resolver: r})
+	synced, err := c.SyncTree(url)
+	if err != nil {
+		t.Fatal("sync error:", err)
+	}
+	if !reflect.DeepEqual(nodeIDs(synced.Nodes()), nodeIDs(nodes)) {
+		t.Errorf("wrong nodes in synced tree: %v", synced.Nodes())
+	}
+	if synced.Seq() != 3 {
+		t.Errorf("wrong seq: have %d, want 3", synced.Seq())
+	}
+}
+
+func TestClientSyncTreeBadSignature(t *testing.T) {
+	tree, _ := MakeTree(1, testNodes(1, 3), nil)
+	if _, err := tree.Sign(testKey(1), "n"); err != nil {
+		t.Fatal(err)
+	}
+	r := mapResolver{}
+	r.add(tree.ToTXT("n"))
+
+	// The URL has the key of a different signer.
+	url := newLinkEntry("n", &testKey(2).PublicKey).String()
+	c := NewClient(Config{Resolver: r})
+	if _, err := c.SyncTree(url); err == nil {
+		t.Fatal("expected error for the tree signed by an untrusted key")
+	}
+}
+
+func TestClientSyncTreeHashMismatch(t *testing.T) {
+	nodes := testNodes(1, 3)
+	tree, _ := MakeTree(1, nodes, nil)
+	url, _ := tree.Sign(testKey(1), "n")
+	r := mapResolver{}
+	r.add(tree.ToTXT("n"))
+
+	// Replace a node record with a different node.
+	for name, record := range r {
+		if record == nodes[0].String() {
+			r[name] = testNodes(2, 1)[0].String()
+		}
+	}
+	c := NewClient(Config{Resolver: r})
+	if _, err := c.SyncTree(url); err == nil {
+		t.Fatal("expected error for the modified record")
+	}
+}
+
+func TestClientNodesFollowsLinks(t *testing.T) {
+	r := mapResolver{}
+
+	nodes2 := testNodes(2, 5)
+	tree2, _ := MakeTree(1, nodes2, nil)
+	url2, _ := tree2.Sign(testKey(2), "n2")
+	r.add(tree2.ToTXT("n2"))
+
+	nodes1 := testNodes(1, 5)
+	tree1, _ := MakeTree(1, nodes1, []string{url2})
+	url1, _ := tree1.Sign(testKey(1), "n1")
+	r.add(tree1.ToTXT("n1"))
+
+	c := NewClient(Config{Resolver: r})
+	nodes, err := c.Nodes(context.Background(), url1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append(append([]*discover.Node{}, nodes1...), nodes2...)
+	if !reflect.DeepEqual(nodeIDs(nodes), nodeIDs(want)) {
+		t.Errorf("wrong nodes: have %d nodes, want %d", len(nodes), len(want))
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	tree, _ := MakeTree(7, testNodes(1, 20), []string{newLinkEntry("n2", &testKey(2).PublicKey).String()})
+	if _, err := tree.Sign(testKey(1), "n"); err != nil {
+		t.Fatal(err)
+	}
+	for name, record := range tree.ToTXT("n") {
+		if name == "n" {
+			root, err := parseRoot(record)
+			if err != nil {
+				t.Fatalf("invalid root: %v", err)
+			}
+			if !root.verifySignature(&testKey(1).PublicKey) {
+				t.Error("root signature does not verify")
+			}
+			continue
+		}
+		e, err := parseEntry(record)
+		if err != nil {
+			t.Fatalf("invalid entry %q: %v", record, err)
+		}
+		if e.String() != record {
+			t.Errorf("entry does not round-trip: have %q, want %q", e.String(), record)
+		}
+		if subdomain(e)+".n" != name {
+			t.Errorf("wrong subdomain for %q", record)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+// Modifications Copyright 2022 The klaytn Authors
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package dnsdisc implements node discovery via DNS (EIP-1459).
+//
+// A node list is published as a merkle tree of DNS TXT records under a domain.
+// The root record is signed by the publisher, so that a client knowing the tree
+// URL (kntree://<base32 public key>@<domain>) can verify all records of the tree
+// no matter which DNS servers or caches serve them. The leaves of the tree are
+// kni:// node URLs and links to other trees.
+//
+// Klaytn uses the record format of EIP-1459 with kntree prefixes and kni node
+// URLs in place of ENRs.
+package dnsdisc
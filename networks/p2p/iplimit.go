@@ -0,0 +1,251 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+)
+
+const (
+	// Inbound connections from the same subnet of these sizes are limited together.
+	inboundSubnetPrefixV4 = 24
+	inboundSubnetPrefixV6 = 64
+
+	maxBusiestInboundIPs = 10 // maximum number of the IPs reported by InboundLimits
+)
+
+var (
+	errTooManyConnsPerIP     = errors.New("too many inbound connections from the IP")
+	errTooManyConnsPerSubnet = errors.New("too many inbound connections from the subnet")
+)
+
+// InboundLimitInfo represents the inbound connection limits and the current usage.
+type InboundLimitInfo struct {
+	MaxPerIP     int            `json:"maxPerIP"`     // 0 means unlimited
+	MaxPerSubnet int            `json:"maxPerSubnet"` // 0 means unlimited
+	Overrides    map[string]int `json:"overrides"`    // per-IP limits overridden for CIDRs
+	Busiest      map[string]int `json:"busiest"`      // the IPs with the most inbound connections
+}
+
+type inboundLimitOverride struct {
+	network *net.IPNet
+	limit   int
+}
+
+// inboundLimiter limits the number of the concurrent inbound connections
+// from a single IP and from a single subnet.
+type inboundLimiter struct {
+	mu           sync.Mutex
+	maxPerIP     int
+	maxPerSubnet int
+	overrides    []*inboundLimitOverride
+	ipConns      map[string]int
+	subnetConns  map[string]int
+}
+
+func newInboundLimiter(maxPerIP, maxPerSubnet int) *inboundLimiter {
+	return &inboundLimiter{
+		maxPerIP:     maxPerIP,
+		maxPerSubnet: maxPerSubnet,
+		ipConns:      make(map[string]int),
+		subnetConns:  make(map[string]int),
+	}
+}
+
+func inboundSubnet(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return (&net.IPNet{IP: ip4.Mask(net.CIDRMask(inboundSubnetPrefixV4, 32)), Mask: net.CIDRMask(inboundSubnetPrefixV4, 32)}).String()
+	}
+	return (&net.IPNet{IP: ip.Mask(net.CIDRMask(inboundSubnetPrefixV6, 128)), Mask: net.CIDRMask(inboundSubnetPrefixV6, 128)}).String()
+}
+
+// override returns the overridden per-IP limit of ip.
+// The most specific override is applied if multiple overrides match.
+func (l *inboundLimiter) override(ip net.IP) (int, bool) {
+	var (
+		found   *inboundLimitOverride
+		foundSz int
+	)
+	for _, o := range l.overrides {
+		if !o.network.Contains(ip) {
+			continue
+		}
+		if size, _ := o.network.Mask.Size(); found == nil || size > foundSz {
+			found, foundSz = o, size
+		}
+	}
+	if found == nil {
+		return 0, false
+	}
+	return found.limit, true
+}
+
+// acquire reserves an inbound connection slot for ip.
+// The returned release function must be called when the connection is closed.
+func (l *inboundLimiter) acquire(ip net.IP) (release func(), err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ipKey, subnetKey := ip.String(), inboundSubnet(ip)
+	if limit, ok := l.override(ip); ok {
+		// The overridden IPs are not limited by the subnet limit.
+		if limit > 0 && l.ipConns[ipKey] >= limit {
+			inboundRejectedPerIPCounter.Inc(1)
+			return nil, errTooManyConnsPerIP
+		}
+		subnetKey = ""
+	} else {
+		if l.maxPerIP > 0 && l.ipConns[ipKey] >= l.maxPerIP {
+			inboundRejectedPerIPCounter.Inc(1)
+			return nil, errTooManyConnsPerIP
+		}
+		if l.maxPerSubnet > 0 && l.subnetConns[subnetKey] >= l.maxPerSubnet {
+			inboundRejectedPerSubnetCounter.Inc(1)
+			return nil, errTooManyConnsPerSubnet
+		}
+	}
+	l.ipConns[ipKey]++
+	if subnetKey != "" {
+		l.subnetConns[subnetKey]++
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			if l.ipConns[ipKey]--; l.ipConns[ipKey] <= 0 {
+				delete(l.ipConns, ipKey)
+			}
+			if subnetKey != "" {
+				if l.subnetConns[subnetKey]--; l.subnetConns[subnetKey] <= 0 {
+					delete(l.subnetConns, subnetKey)
+				}
+			}
+		})
+	}, nil
+}
+
+// setLimits changes the default per-IP and per-subnet limits. A limit of 0
+// means unlimited.
+func (l *inboundLimiter) setLimits(maxPerIP, maxPerSubnet int) error {
+	if maxPerIP < 0 || maxPerSubnet < 0 {
+		return fmt.Errorf("invalid limits %d, %d", maxPerIP, maxPerSubnet)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.maxPerIP, l.maxPerSubnet = maxPerIP, maxPerSubnet
+	return nil
+}
+
+// setOverride overrides the per-IP limit of the IPs in cidr. A limit of 0
+// means unlimited. A single IP address is regarded as a /32 or /128 network.
+func (l *inboundLimiter) setOverride(cidr string, limit int) error {
+	if limit < 0 {
+		return fmt.Errorf("invalid limit %d", limit)
+	}
+	network, err := parseCIDROrIP(cidr)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, o := range l.overrides {
+		if o.network.String() == network.String() {
+			o.limit = limit
+			return nil
+		}
+	}
+	l.overrides = append(l.overrides, &inboundLimitOverride{network: network, limit: limit})
+	return nil
+}
+
+// removeOverride removes the per-IP limit override of cidr.
+func (l *inboundLimiter) removeOverride(cidr string) error {
+	network, err := parseCIDROrIP(cidr)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i, o := range l.overrides {
+		if o.network.String() == network.String() {
+			l.overrides = append(l.overrides[:i], l.overrides[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("no override for %s", network)
+}
+
+// info returns the limits and at most maxBusiest IPs with the most connections.
+func (l *inboundLimiter) info(maxBusiest int) *InboundLimitInfo {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	info := &InboundLimitInfo{
+		MaxPerIP:     l.maxPerIP,
+		MaxPerSubnet: l.maxPerSubnet,
+		Overrides:    make(map[string]int, len(l.overrides)),
+		Busiest:      make(map[string]int),
+	}
+	for _, o := range l.overrides {
+		info.Overrides[o.network.String()] = o.limit
+	}
+	ips := make([]string, 0, len(l.ipConns))
+	for ip := range l.ipConns {
+		ips = append(ips, ip)
+	}
+	sort.Slice(ips, func(i, j int) bool { return l.ipConns[ips[i]] > l.ipConns[ips[j]] })
+	for i := 0; i < len(ips) && i < maxBusiest; i++ {
+		info.Busiest[ips[i]] = l.ipConns[ips[i]]
+	}
+	return info
+}
+
+func parseCIDROrIP(s string) (*net.IPNet, error) {
+	if ip := net.ParseIP(s); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+	_, network, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IP or CIDR %q", s)
+	}
+	return network, nil
+}
+
+// limitedConn releases the inbound connection slot when it is closed.
+type limitedConn struct {
+	net.Conn
+	release func()
+}
+
+func (c *limitedConn) Close() error {
+	err := c.Conn.Close()
+	c.release()
+	return err
+}
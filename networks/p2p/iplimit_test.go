@@ -0,0 +1,151 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"net"
+	"testing"
+)
+
+func TestInboundLimiter_PerIP(t *testing.T) {
+	l := newInboundLimiter(2, 0)
+	ip := net.IP{10, 0, 0, 1}
+
+	release1, err := l.acquire(ip)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := l.acquire(ip); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := l.acquire(ip); err != errTooManyConnsPerIP {
+		t.Fatalf("unexpected error: have %v, want %v", err, errTooManyConnsPerIP)
+	}
+	// Another IP is not affected.
+	if _, err := l.acquire(net.IP{10, 0, 0, 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Releasing twice frees only one slot.
+	release1()
+	release1()
+	if _, err := l.acquire(ip); err != nil {
+		t.Fatalf("unexpected error after release: %v", err)
+	}
+	if _, err := l.acquire(ip); err != errTooManyConnsPerIP {
+		t.Fatalf("unexpected error: have %v, want %v", err, errTooManyConnsPerIP)
+	}
+}
+
+func TestInboundLimiter_PerSubnet(t *testing.T) {
+	l := newInboundLimiter(0, 2)
+
+	if _, err := l.acquire(net.IP{10, 0, 0, 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := l.acquire(net.IP{10, 0, 0, 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := l.acquire(net.IP{10, 0, 0, 3}); err != errTooManyConnsPerSubnet {
+		t.Fatalf("unexpected error: have %v, want %v", err, errTooManyConnsPerSubnet)
+	}
+	if _, err := l.acquire(net.IP{10, 0, 1, 1}); err != nil {
+		t.Fatalf("unexpected error from another subnet: %v", err)
+	}
+	if _, err := l.acquire(net.ParseIP("2001:db8::1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := l.acquire(net.ParseIP("2001:db8::2")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := l.acquire(net.ParseIP("2001:db8::3")); err != errTooManyConnsPerSubnet {
+		t.Fatalf("unexpected error: have %v, want %v", err, errTooManyConnsPerSubnet)
+	}
+}
+
+func TestInboundLimiter_Override(t *testing.T) {
+	l := newInboundLimiter(1, 1)
+
+	if err := l.setOverride("10.0.0.0/24", 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := l.setOverride("10.0.0.1", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := l.setOverride("invalid", 1); err == nil {
+		t.Error("invalid CIDR should be rejected")
+	}
+
+	// The most specific override allows unlimited connections from 10.0.0.1.
+	for i := 0; i < 5; i++ {
+		if _, err := l.acquire(net.IP{10, 0, 0, 1}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// The /24 override applies to the other IPs in the subnet, ignoring the subnet limit.
+	for i := 0; i < 3; i++ {
+		if _, err := l.acquire(net.IP{10, 0, 0, 2}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := l.acquire(net.IP{10, 0, 0, 2}); err != errTooManyConnsPerIP {
+		t.Fatalf("unexpected error: have %v, want %v", err, errTooManyConnsPerIP)
+	}
+
+	info := l.info(1)
+	if len(info.Overrides) != 2 || info.Overrides["10.0.0.1/32"] != 0 || info.Overrides["10.0.0.0/24"] != 3 {
+		t.Errorf("unexpected overrides: %v", info.Overrides)
+	}
+	if len(info.Busiest) != 1 || info.Busiest["10.0.0.1"] != 5 {
+		t.Errorf("unexpected busiest IPs: %v", info.Busiest)
+	}
+
+	if err := l.removeOverride("10.0.0.0/24"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := l.removeOverride("10.0.0.0/24"); err == nil {
+		t.Error("removing a nonexistent override should fail")
+	}
+	if _, err := l.acquire(net.IP{10, 0, 0, 3}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := l.acquire(net.IP{10, 0, 0, 4}); err != errTooManyConnsPerSubnet {
+		t.Fatalf("unexpected error: have %v, want %v", err, errTooManyConnsPerSubnet)
+	}
+}
+
+func TestInboundLimiter_SetLimits(t *testing.T) {
+	l := newInboundLimiter(1, 0)
+	ip := net.IP{10, 0, 0, 1}
+
+	if _, err := l.acquire(ip); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := l.acquire(ip); err != errTooManyConnsPerIP {
+		t.Fatalf("unexpected error: have %v, want %v", err, errTooManyConnsPerIP)
+	}
+	if err := l.setLimits(-1, 0); err == nil {
+		t.Fatal("negative limit should be rejected")
+	}
+	// The raised limit is applied to the next connections.
+	if err := l.setLimits(2, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := l.acquire(ip); err != nil {
+		t.Fatalf("unexpected error after raising the limit: %v", err)
+	}
+}
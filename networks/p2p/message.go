@@ -46,6 +46,8 @@ type Msg struct {
 	Size       uint32 // size of the paylod
 	Payload    io.Reader
 	ReceivedAt time.Time
+
+	compressible bool // set by the protocol if the message is worth compressing
 }
 
 // Decode parses the RLP content of a message into
@@ -51,6 +51,20 @@ var (
 	dialFailCounter = metrics.NewRegisteredCounter("p2p/DialFailCounter", nil)
 
 	writeMsgTimeOutCounter = metrics.NewRegisteredCounter("p2p/WriteMsgTimeOutCounter", nil)
+
+	peerBanCounter = metrics.NewRegisteredCounter("p2p/PeerBanCounter", nil)
+
+	sentryPeersGauge = metrics.NewRegisteredGauge("p2p/SentryPeersGauge", nil)
+
+	tlsHandshakeFailCounter = metrics.NewRegisteredCounter("p2p/TLSHandshakeFailCounter", nil)
+
+	inboundRejectedPerIPCounter     = metrics.NewRegisteredCounter("p2p/InboundRejectedPerIPCounter", nil)
+	inboundRejectedPerSubnetCounter = metrics.NewRegisteredCounter("p2p/InboundRejectedPerSubnetCounter", nil)
+
+	egressThrottleTimer = metrics.NewRegisteredTimer("p2p/EgressThrottleTimer", nil)
+
+	uncompressedMsgCounter       = metrics.NewRegisteredCounter("p2p/UncompressedMsgCounter", nil)
+	compressionSavedBytesCounter = metrics.NewRegisteredCounter("p2p/CompressionSavedBytesCounter", nil)
 )
 
 // meteredConn is a wrapper around a network TCP connection that meters both the
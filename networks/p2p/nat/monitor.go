@@ -0,0 +1,159 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nat
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// extIPCheckInterval is the interval of the external IP address checks.
+const extIPCheckInterval = 5 * time.Minute
+
+// Status represents the detected external endpoint and the health of the port mappings.
+type Status struct {
+	Mechanism    string           `json:"mechanism"`
+	ExternalIP   string           `json:"externalIP,omitempty"`
+	LastCheck    time.Time        `json:"lastCheck"`
+	LastCheckErr string           `json:"lastCheckError,omitempty"`
+	Mappings     []*MappingStatus `json:"mappings"`
+}
+
+// MappingStatus represents the health of a port mapping.
+type MappingStatus struct {
+	Protocol    string    `json:"protocol"`
+	ExtPort     int       `json:"externalPort"`
+	IntPort     int       `json:"internalPort"`
+	Healthy     bool      `json:"healthy"`
+	LastRefresh time.Time `json:"lastRefresh"`
+	LastErr     string    `json:"lastError,omitempty"`
+}
+
+// Monitor keeps the port mappings of a NAT interface alive and periodically
+// checks the external IP address, so that the status can be reported.
+type Monitor struct {
+	m Interface
+
+	mu        sync.Mutex
+	extIP     net.IP
+	lastCheck time.Time
+	lastErr   error
+	mappings  map[string]*MappingStatus
+}
+
+// NewMonitor creates a monitor of the given NAT interface.
+func NewMonitor(m Interface) *Monitor {
+	return &Monitor{m: m, mappings: make(map[string]*MappingStatus)}
+}
+
+// Run checks the external IP address every extIPCheckInterval until c is closed.
+// The onChange callback, if not nil, is called when the external IP address changes.
+// This function is typically invoked in its own goroutine.
+func (mon *Monitor) Run(c chan struct{}, onChange func(net.IP)) {
+	check := time.NewTicker(extIPCheckInterval)
+	defer check.Stop()
+
+	for {
+		select {
+		case <-c:
+			return
+		case <-check.C:
+			if ip, changed := mon.CheckExternalIP(); changed && onChange != nil {
+				onChange(ip)
+			}
+		}
+	}
+}
+
+// CheckExternalIP queries the external IP address and returns it with
+// whether it has been changed since the last successful check.
+func (mon *Monitor) CheckExternalIP() (net.IP, bool) {
+	ip, err := mon.m.ExternalIP()
+
+	mon.mu.Lock()
+	defer mon.mu.Unlock()
+
+	mon.lastCheck, mon.lastErr = time.Now(), err
+	if err != nil {
+		logger.Debug("Couldn't get external IP", "interface", mon.m, "err", err)
+		return mon.extIP, false
+	}
+	changed := mon.extIP != nil && !mon.extIP.Equal(ip)
+	if changed {
+		logger.Info("External IP changed", "interface", mon.m, "old", mon.extIP, "new", ip)
+	}
+	mon.extIP = ip
+	return ip, changed
+}
+
+// Map adds a port mapping on the monitored interface and keeps it alive
+// until c is closed, recording the health of the mapping.
+// This function is typically invoked in its own goroutine.
+func (mon *Monitor) Map(c chan struct{}, protocol string, extport, intport int, name string) {
+	key := fmt.Sprintf("%s:%d", protocol, extport)
+	mon.mu.Lock()
+	mon.mappings[key] = &MappingStatus{Protocol: protocol, ExtPort: extport, IntPort: intport}
+	mon.mu.Unlock()
+
+	defer func() {
+		mon.mu.Lock()
+		delete(mon.mappings, key)
+		mon.mu.Unlock()
+	}()
+	mapPort(mon.m, c, protocol, extport, intport, name, func(err error) {
+		mon.mu.Lock()
+		defer mon.mu.Unlock()
+
+		ms := mon.mappings[key]
+		ms.Healthy, ms.LastRefresh, ms.LastErr = err == nil, time.Now(), ""
+		if err != nil {
+			ms.LastErr = err.Error()
+		}
+	})
+}
+
+// Status returns the current status of the monitored interface.
+func (mon *Monitor) Status() *Status {
+	mon.mu.Lock()
+	defer mon.mu.Unlock()
+
+	status := &Status{
+		Mechanism: mon.m.String(),
+		LastCheck: mon.lastCheck,
+		Mappings:  make([]*MappingStatus, 0, len(mon.mappings)),
+	}
+	if mon.extIP != nil {
+		status.ExternalIP = mon.extIP.String()
+	}
+	if mon.lastErr != nil {
+		status.LastCheckErr = mon.lastErr.Error()
+	}
+	for _, ms := range mon.mappings {
+		copied := *ms
+		status.Mappings = append(status.Mappings, &copied)
+	}
+	sort.Slice(status.Mappings, func(i, j int) bool {
+		if status.Mappings[i].Protocol != status.Mappings[j].Protocol {
+			return status.Mappings[i].Protocol < status.Mappings[j].Protocol
+		}
+		return status.Mappings[i].ExtPort < status.Mappings[j].ExtPort
+	})
+	return status
+}
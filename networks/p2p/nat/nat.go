@@ -64,12 +64,20 @@ type Interface interface {
 //     "upnp"               uses the Universal Plug and Play protocol
 //     "pmp"                uses NAT-PMP with an auto-detected gateway address
 //     "pmp:192.168.0.1"    uses NAT-PMP with the given gateway address
+//     "stun"               uses STUN with the default STUN server
+//     "stun:host:port"     uses STUN with the given STUN server
 func Parse(spec string) (Interface, error) {
 	var (
 		parts = strings.SplitN(spec, ":", 2)
 		mech  = strings.ToLower(parts[0])
 		ip    net.IP
 	)
+	if mech == "stun" {
+		if len(parts) > 1 {
+			return STUN(parts[1]), nil
+		}
+		return STUN(""), nil
+	}
 	if len(parts) > 1 {
 		ip = net.ParseIP(parts[1])
 		if ip == nil {
@@ -103,6 +111,11 @@ const (
 // Map adds a port mapping on m and keeps it alive until c is closed.
 // This function is typically invoked in its own goroutine.
 func Map(m Interface, c chan struct{}, protocol string, extport, intport int, name string) {
+	mapPort(m, c, protocol, extport, intport, name, nil)
+}
+
+// mapPort works like Map, but calls onRefresh with the result of every mapping attempt.
+func mapPort(m Interface, c chan struct{}, protocol string, extport, intport int, name string, onRefresh func(error)) {
 	localLogger := logger.NewWith("protobuf", protocol, "extport", extport, "intport", intport, "interface", m)
 	refresh := time.NewTimer(mapUpdateInterval)
 	defer func() {
@@ -110,11 +123,15 @@ func Map(m Interface, c chan struct{}, protocol string, extport, intport int, na
 		localLogger.Debug("Deleting port mapping")
 		m.DeleteMapping(protocol, extport, intport)
 	}()
-	if err := m.AddMapping(protocol, extport, intport, name, mapTimeout); err != nil {
+	err := m.AddMapping(protocol, extport, intport, name, mapTimeout)
+	if err != nil {
 		localLogger.Debug("Couldn't add port mapping", "err", err)
 	} else {
 		localLogger.Info("Mapped network port")
 	}
+	if onRefresh != nil {
+		onRefresh(err)
+	}
 	for {
 		select {
 		case _, ok := <-c:
@@ -123,8 +140,12 @@ func Map(m Interface, c chan struct{}, protocol string, extport, intport int, na
 			}
 		case <-refresh.C:
 			localLogger.Trace("Refreshing port mapping")
-			if err := m.AddMapping(protocol, extport, intport, name, mapTimeout); err != nil {
-				localLogger.Debug("Couldn't add port mapping", "err", err)
+			err := m.AddMapping(protocol, extport, intport, name, mapTimeout)
+			if err != nil {
+				localLogger.Warn("Couldn't refresh port mapping", "err", err)
+			}
+			if onRefresh != nil {
+				onRefresh(err)
 			}
 			refresh.Reset(mapUpdateInterval)
 		}
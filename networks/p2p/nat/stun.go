@@ -0,0 +1,198 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nat
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// DefaultSTUNServer is used when no STUN server is given.
+const DefaultSTUNServer = "stun.l.google.com:19302"
+
+const (
+	stunBindingRequest  = 0x0001
+	stunBindingResponse = 0x0101
+	stunMagicCookie     = 0x2112A442
+	stunHeaderSize      = 20
+
+	stunAttrMappedAddress    = 0x0001
+	stunAttrXorMappedAddress = 0x0020
+
+	stunTimeout = 3 * time.Second
+	stunRetries = 3
+)
+
+var errNoMappedAddress = errors.New("no mapped address in STUN response")
+
+// STUN returns a NAT interface which discovers the external IP address by
+// sending STUN binding requests (RFC 5389) to the given server. It can't
+// create port mappings, so the ports must be forwarded by other means
+// (e.g. cloud NAT rules), or the NAT must preserve the ports.
+func STUN(server string) Interface {
+	if server == "" {
+		server = DefaultSTUNServer
+	}
+	return &stun{server: server}
+}
+
+type stun struct {
+	server string
+}
+
+func (n *stun) String() string {
+	return fmt.Sprintf("STUN(%s)", n.server)
+}
+
+// These do nothing.
+func (*stun) AddMapping(string, int, int, string, time.Duration) error { return nil }
+func (*stun) DeleteMapping(string, int, int) error                     { return nil }
+
+func (n *stun) ExternalIP() (net.IP, error) {
+	addr, err := net.ResolveUDPAddr("udp", n.server)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.DialUDP("udp", nil, addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	for i := 0; i < stunRetries; i++ {
+		var mapped *net.UDPAddr
+		if mapped, err = stunRequest(conn); err == nil {
+			return mapped.IP, nil
+		}
+	}
+	return nil, err
+}
+
+// stunRequest sends a binding request over conn and returns the mapped address.
+func stunRequest(conn net.Conn) (*net.UDPAddr, error) {
+	var txID [12]byte
+	if _, err := rand.Read(txID[:]); err != nil {
+		return nil, err
+	}
+	req := make([]byte, stunHeaderSize)
+	binary.BigEndian.PutUint16(req[0:], stunBindingRequest)
+	binary.BigEndian.PutUint32(req[4:], stunMagicCookie)
+	copy(req[8:], txID[:])
+
+	if err := conn.SetDeadline(time.Now().Add(stunTimeout)); err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(req); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 1024)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		// Ignore stale responses of the previous requests.
+		if n >= stunHeaderSize && bytes.Equal(buf[8:stunHeaderSize], txID[:]) {
+			return parseSTUNResponse(buf[:n], txID)
+		}
+	}
+}
+
+// parseSTUNResponse extracts the mapped address from a binding response.
+// XOR-MAPPED-ADDRESS is preferred to MAPPED-ADDRESS.
+func parseSTUNResponse(msg []byte, txID [12]byte) (*net.UDPAddr, error) {
+	if len(msg) < stunHeaderSize {
+		return nil, errors.New("too short STUN message")
+	}
+	if typ := binary.BigEndian.Uint16(msg[0:]); typ != stunBindingResponse {
+		return nil, fmt.Errorf("unexpected STUN message type %#04x", typ)
+	}
+	if cookie := binary.BigEndian.Uint32(msg[4:]); cookie != stunMagicCookie {
+		return nil, fmt.Errorf("invalid STUN magic cookie %#08x", cookie)
+	}
+	length := int(binary.BigEndian.Uint16(msg[2:]))
+	if len(msg) < stunHeaderSize+length {
+		return nil, errors.New("truncated STUN message")
+	}
+
+	var mapped *net.UDPAddr
+	attrs := msg[stunHeaderSize : stunHeaderSize+length]
+	for len(attrs) >= 4 {
+		typ := binary.BigEndian.Uint16(attrs[0:])
+		size := int(binary.BigEndian.Uint16(attrs[2:]))
+		if len(attrs) < 4+size {
+			return nil, errors.New("truncated STUN attribute")
+		}
+		value := attrs[4 : 4+size]
+		switch typ {
+		case stunAttrXorMappedAddress:
+			if addr, err := parseSTUNAddress(value, true, txID); err == nil {
+				return addr, nil
+			}
+		case stunAttrMappedAddress:
+			if addr, err := parseSTUNAddress(value, false, txID); err == nil {
+				mapped = addr
+			}
+		}
+		// Attributes are padded to a multiple of 4 bytes.
+		padded := (size + 3) &^ 3
+		if len(attrs) < 4+padded {
+			break
+		}
+		attrs = attrs[4+padded:]
+	}
+	if mapped == nil {
+		return nil, errNoMappedAddress
+	}
+	return mapped, nil
+}
+
+func parseSTUNAddress(value []byte, xor bool, txID [12]byte) (*net.UDPAddr, error) {
+	if len(value) < 4 {
+		return nil, errors.New("too short STUN address")
+	}
+	var ipLen int
+	switch value[1] {
+	case 0x01:
+		ipLen = net.IPv4len
+	case 0x02:
+		ipLen = net.IPv6len
+	default:
+		return nil, fmt.Errorf("unknown STUN address family %d", value[1])
+	}
+	if len(value) < 4+ipLen {
+		return nil, errors.New("too short STUN address")
+	}
+	port := binary.BigEndian.Uint16(value[2:])
+	ip := make(net.IP, ipLen)
+	copy(ip, value[4:4+ipLen])
+	if xor {
+		var key [16]byte
+		binary.BigEndian.PutUint32(key[0:], stunMagicCookie)
+		copy(key[4:], txID[:])
+		port ^= stunMagicCookie >> 16
+		for i := range ip {
+			ip[i] ^= key[i]
+		}
+	}
+	return &net.UDPAddr{IP: ip, Port: int(port)}, nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package nat
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+)
+
+// runTestSTUNServer answers binding requests with the XOR-MAPPED-ADDRESS
+// of the given external address until the connection is closed.
+func runTestSTUNServer(conn *net.UDPConn, ext *net.UDPAddr) {
+	buf := make([]byte, 1024)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		if n < stunHeaderSize {
+			continue
+		}
+		var txID [12]byte
+		copy(txID[:], buf[8:stunHeaderSize])
+
+		ip := ext.IP.To4()
+		attr := make([]byte, 12)
+		binary.BigEndian.PutUint16(attr[0:], stunAttrXorMappedAddress)
+		binary.BigEndian.PutUint16(attr[2:], 8)
+		attr[5] = 0x01
+		binary.BigEndian.PutUint16(attr[6:], uint16(ext.Port)^uint16(stunMagicCookie>>16))
+		binary.BigEndian.PutUint32(attr[8:], binary.BigEndian.Uint32(ip)^stunMagicCookie)
+
+		resp := make([]byte, stunHeaderSize, stunHeaderSize+len(attr))
+		binary.BigEndian.PutUint16(resp[0:], stunBindingResponse)
+		binary.BigEndian.PutUint16(resp[2:], uint16(len(attr)))
+		binary.BigEndian.PutUint32(resp[4:], stunMagicCookie)
+		copy(resp[8:], txID[:])
+		resp = append(resp, attr...)
+		conn.WriteToUDP(resp, from)
+	}
+}
+
+func TestSTUN_ExternalIP(t *testing.T) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	go runTestSTUNServer(conn, &net.UDPAddr{IP: net.IPv4(33, 44, 55, 66), Port: 32323})
+
+	ip, err := STUN(conn.LocalAddr().String()).ExternalIP()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := net.IPv4(33, 44, 55, 66); !ip.Equal(want) {
+		t.Errorf("got IP %v, want %v", ip, want)
+	}
+}
+
+func TestParseSTUNResponse(t *testing.T) {
+	var txID [12]byte
+	header := func(length int) []byte {
+		msg := make([]byte, stunHeaderSize)
+		binary.BigEndian.PutUint16(msg[0:], stunBindingResponse)
+		binary.BigEndian.PutUint16(msg[2:], uint16(length))
+		binary.BigEndian.PutUint32(msg[4:], stunMagicCookie)
+		return msg
+	}
+
+	// MAPPED-ADDRESS is used if XOR-MAPPED-ADDRESS does not exist.
+	msg := append(header(12), 0x00, 0x01, 0x00, 0x08, 0x00, 0x01, 0x7e, 0x43, 1, 2, 3, 4)
+	addr, err := parseSTUNResponse(msg, txID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !addr.IP.Equal(net.IPv4(1, 2, 3, 4)) || addr.Port != 32323 {
+		t.Errorf("unexpected address %v", addr)
+	}
+
+	// A response without addresses is an error.
+	if _, err := parseSTUNResponse(header(0), txID); err != errNoMappedAddress {
+		t.Errorf("unexpected error: have %v, want %v", err, errNoMappedAddress)
+	}
+
+	// Truncated messages are rejected.
+	if _, err := parseSTUNResponse(header(12), txID); err == nil {
+		t.Error("truncated message should be rejected")
+	}
+}
+
+func TestParseSTUN(t *testing.T) {
+	for spec, want := range map[string]string{
+		"stun":                   "STUN(" + DefaultSTUNServer + ")",
+		"STUN:stun.example:3478": "STUN(stun.example:3478)",
+	} {
+		m, err := Parse(spec)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", spec, err)
+		}
+		if m.String() != want {
+			t.Errorf("%q: got %v, want %v", spec, m, want)
+		}
+	}
+}
+
+func TestMonitor_Status(t *testing.T) {
+	mon := NewMonitor(ExtIP(net.IPv4(33, 44, 55, 66)))
+	if status := mon.Status(); status.ExternalIP != "" {
+		t.Errorf("external IP should be empty before the check, got %v", status.ExternalIP)
+	}
+
+	if ip, changed := mon.CheckExternalIP(); changed || !ip.Equal(net.IPv4(33, 44, 55, 66)) {
+		t.Errorf("unexpected check result: ip %v, changed %v", ip, changed)
+	}
+
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		mon.Map(quit, "tcp", 32323, 32323, "test")
+		close(done)
+	}()
+	for {
+		status := mon.Status()
+		if len(status.Mappings) == 1 && status.Mappings[0].Healthy {
+			if status.ExternalIP != "33.44.55.66" {
+				t.Errorf("unexpected external IP %v", status.ExternalIP)
+			}
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	close(quit)
+	<-done
+	if status := mon.Status(); len(status.Mappings) != 0 {
+		t.Errorf("mapping should be removed, got %v", status.Mappings)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package netutil
+
+import (
+	"fmt"
+	"net"
+)
+
+// IPPolicy selects the IP address families used for peering.
+type IPPolicy uint8
+
+const (
+	// DualStack uses both address families. Host names resolve to the first address.
+	DualStack IPPolicy = iota
+	// PreferIPv4 uses both address families. Host names resolve to IPv4 addresses if any.
+	PreferIPv4
+	// PreferIPv6 uses both address families. Host names resolve to IPv6 addresses if any.
+	PreferIPv6
+	// IPv4Only uses IPv4 addresses only.
+	IPv4Only
+	// IPv6Only uses IPv6 addresses only.
+	IPv6Only
+)
+
+var ipPolicyNames = []string{"dual", "prefer-ipv4", "prefer-ipv6", "ipv4-only", "ipv6-only"}
+
+// ParseIPPolicy parses the name of an IP policy. The empty string is DualStack.
+func ParseIPPolicy(s string) (IPPolicy, error) {
+	if s == "" {
+		return DualStack, nil
+	}
+	for i, name := range ipPolicyNames {
+		if s == name {
+			return IPPolicy(i), nil
+		}
+	}
+	return DualStack, fmt.Errorf("unknown IP policy %q, want one of %v", s, ipPolicyNames)
+}
+
+func (p IPPolicy) String() string {
+	if int(p) < len(ipPolicyNames) {
+		return ipPolicyNames[p]
+	}
+	return fmt.Sprintf("IPPolicy(%d)", p)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p IPPolicy) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *IPPolicy) UnmarshalText(text []byte) error {
+	policy, err := ParseIPPolicy(string(text))
+	if err != nil {
+		return err
+	}
+	*p = policy
+	return nil
+}
+
+// PrefersIPv6 reports whether IPv6 addresses are preferred or required.
+func (p IPPolicy) PrefersIPv6() bool {
+	return p == PreferIPv6 || p == IPv6Only
+}
+
+// Allows reports whether the address family of ip can be used.
+func (p IPPolicy) Allows(ip net.IP) bool {
+	switch p {
+	case IPv4Only:
+		return ip.To4() != nil
+	case IPv6Only:
+		return ip.To4() == nil
+	default:
+		return true
+	}
+}
+
+// Select returns the address to use among the resolved addresses of a host.
+// It returns nil if no address is allowed.
+func (p IPPolicy) Select(ips []net.IP) net.IP {
+	var v4, v6 net.IP
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			if v4 == nil {
+				v4 = ip
+			}
+		} else if v6 == nil {
+			v6 = ip
+		}
+	}
+	switch p {
+	case IPv4Only:
+		return v4
+	case IPv6Only:
+		return v6
+	case PreferIPv4:
+		if v4 != nil {
+			return v4
+		}
+		return v6
+	case PreferIPv6:
+		if v6 != nil {
+			return v6
+		}
+		return v4
+	default:
+		if len(ips) == 0 {
+			return nil
+		}
+		return ips[0]
+	}
+}
+
+// Network returns the network name ("tcp", "udp") restricted to the address
+// family of the policy, e.g. "udp6" for IPv6Only.
+func (p IPPolicy) Network(network string) string {
+	switch p {
+	case IPv4Only:
+		return network + "4"
+	case IPv6Only:
+		return network + "6"
+	default:
+		return network
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package netutil
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIPPolicySelect(t *testing.T) {
+	v4, v6 := net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1")
+	tests := []struct {
+		policy IPPolicy
+		ips    []net.IP
+		want   net.IP
+	}{
+		{DualStack, []net.IP{v6, v4}, v6},
+		{DualStack, nil, nil},
+		{PreferIPv4, []net.IP{v6, v4}, v4},
+		{PreferIPv4, []net.IP{v6}, v6},
+		{PreferIPv6, []net.IP{v4, v6}, v6},
+		{PreferIPv6, []net.IP{v4}, v4},
+		{IPv4Only, []net.IP{v6}, nil},
+		{IPv6Only, []net.IP{v4, v6}, v6},
+		{IPv6Only, []net.IP{v4}, nil},
+	}
+	for _, test := range tests {
+		if got := test.policy.Select(test.ips); !got.Equal(test.want) {
+			t.Errorf("%v.Select(%v) = %v, want %v", test.policy, test.ips, got, test.want)
+		}
+	}
+}
+
+func TestIPPolicyAllows(t *testing.T) {
+	v4, v6 := net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1")
+	if !IPv4Only.Allows(v4) || IPv4Only.Allows(v6) {
+		t.Error("IPv4Only should allow IPv4 addresses only")
+	}
+	if IPv6Only.Allows(v4) || !IPv6Only.Allows(v6) {
+		t.Error("IPv6Only should allow IPv6 addresses only")
+	}
+	if !PreferIPv6.Allows(v4) || !DualStack.Allows(v6) {
+		t.Error("dual-stack policies should allow both families")
+	}
+	if got := IPv6Only.Network("udp"); got != "udp6" {
+		t.Errorf("IPv6Only.Network(udp) = %q, want udp6", got)
+	}
+	if got := PreferIPv4.Network("tcp"); got != "tcp" {
+		t.Errorf("PreferIPv4.Network(tcp) = %q, want tcp", got)
+	}
+}
+
+func TestParseIPPolicy(t *testing.T) {
+	for i, name := range ipPolicyNames {
+		var policy IPPolicy
+		if err := policy.UnmarshalText([]byte(name)); err != nil || policy != IPPolicy(i) {
+			t.Errorf("UnmarshalText(%q) = %v, %v", name, policy, err)
+		}
+		if text, _ := policy.MarshalText(); string(text) != name {
+			t.Errorf("MarshalText() = %q, want %q", text, name)
+		}
+	}
+	if policy, err := ParseIPPolicy(""); err != nil || policy != DualStack {
+		t.Errorf("ParseIPPolicy(\"\") = %v, %v", policy, err)
+	}
+	if _, err := ParseIPPolicy("ipv5"); err == nil {
+		t.Error("ParseIPPolicy should fail for an unknown policy")
+	}
+}
+
+func TestDistinctNetSetSubnet6(t *testing.T) {
+	set := DistinctNetSet{Subnet: 24, Subnet6: 48, Limit: 1}
+	if !set.Add(parseIP("2001:db8:1::1")) {
+		t.Fatal("first address should be added")
+	}
+	if set.Add(parseIP("2001:db8:1:ffff::1")) {
+		t.Error("address in the same /48 should not be added")
+	}
+	if !set.Add(parseIP("2001:db8:2::1")) {
+		t.Error("address in another /48 should be added")
+	}
+	if !set.Add(parseIP("192.0.2.1")) || set.Add(parseIP("192.0.2.2")) {
+		t.Error("IPv4 addresses should be limited per /24")
+	}
+}
@@ -217,8 +217,9 @@ func sameNet(bits uint, ip, other net.IP) bool {
 // DistinctNetSet tracks IPs, ensuring that at most N of them
 // fall into the same network range.
 type DistinctNetSet struct {
-	Subnet uint // number of common prefix bits
-	Limit  uint // maximum number of IPs in each subnet
+	Subnet  uint // number of common prefix bits
+	Subnet6 uint // number of common prefix bits of IPv6 addresses, Subnet is used if zero
+	Limit   uint // maximum number of IPs in each subnet
 
 	members map[string]uint
 	buf     net.IP
@@ -280,6 +281,9 @@ func (s *DistinctNetSet) key(ip net.IP) net.IP {
 		typ, ip = '4', ip4
 	}
 	bits := s.Subnet
+	if typ == '6' && s.Subnet6 != 0 {
+		bits = s.Subnet6
+	}
 	if bits > uint(len(ip)*8) {
 		bits = uint(len(ip) * 8)
 	}
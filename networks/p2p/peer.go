@@ -26,6 +26,7 @@ import (
 	"io"
 	"net"
 	"sort"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -72,6 +73,10 @@ type protoHandshake struct {
 	ID           discover.NodeID
 	Multichannel bool
 
+	// CompressionThreshold is non-zero if the node supports the selective compression.
+	// The messages smaller than the threshold are sent uncompressed.
+	CompressionThreshold uint64 `rlp:"optional"`
+
 	// Ignore additional fields (for forward compatibility).
 	Rest []rlp.RawValue `rlp:"tail"`
 }
@@ -122,6 +127,14 @@ type Peer struct {
 
 	// events receives message send / receive events if set
 	events *event.Feed
+
+	// scorer tracks the score of the peer if set
+	scorer *peerScorer
+
+	// egress throttles the messages sent to the peer if set
+	egress *egressLimiter
+	// egressExempt is the message codes sent to the peer without throttling
+	egressExempt egressExemptions
 }
 
 // NewPeer returns a peer for testing purposes.
@@ -182,6 +195,28 @@ func (p *Peer) Disconnect(reason DiscReason) {
 	}
 }
 
+// AddScore applies the given event to the score of the peer.
+// If the score drops below the ban threshold, the peer is disconnected.
+func (p *Peer) AddScore(ev PeerScoreEvent) {
+	if p == nil || p.scorer == nil {
+		return
+	}
+	if p.scorer.add(p.ID(), ev) {
+		p.logger.Info("Disconnecting peer banned by low score", "event", ev)
+		p.Disconnect(DiscUselessPeer)
+	}
+}
+
+// ExemptFromEgressLimits sends the messages of the given code to the peer
+// without the egress bandwidth throttling, e.g. the consensus messages which
+// must not be delayed behind the block and transaction propagation.
+func (p *Peer) ExemptFromEgressLimits(msgCode uint64) {
+	if p == nil {
+		return
+	}
+	p.egressExempt.add(msgCode)
+}
+
 // String implements fmt.Stringer.
 func (p *Peer) String() string {
 	return fmt.Sprintf("Peer %x %v", p.rws[ConnDefault].id[:8], p.RemoteAddr())
@@ -192,6 +227,11 @@ func (p *Peer) Inbound() bool {
 	return p.rws[ConnDefault].flags&inboundConn != 0
 }
 
+// Trusted returns true if the peer is one of the trusted nodes.
+func (p *Peer) Trusted() bool {
+	return p.rws[ConnDefault].is(trustedConn)
+}
+
 // GetNumberInboundAndOutbound returns the number of
 // inbound and outbound connections connected to the peer.
 func (p *Peer) GetNumberInboundAndOutbound() (int, int) {
@@ -492,6 +532,7 @@ outer:
 
 func (p *Peer) startProtocols(writeStart <-chan struct{}, writeErr chan<- error) {
 	p.wg.Add(len(p.running))
+	egressBucket := newTokenBucket()
 	for _, protos := range p.running {
 		if len(protos) != 1 {
 			p.logger.Error("The size of protos should be 1", "size", len(protos))
@@ -504,6 +545,9 @@ func (p *Peer) startProtocols(writeStart <-chan struct{}, writeErr chan<- error)
 		proto.werr = writeErr
 		proto.tc = defaultRWTimerConfig
 		var rw MsgReadWriter = proto
+		if p.egress != nil {
+			rw = newThrottledRW(rw, p.egress, egressBucket, &p.egressExempt, p.closed)
+		}
 		if p.events != nil {
 			rw = newMsgEventer(rw, p.events, p.ID(), proto.Name)
 		}
@@ -528,6 +572,7 @@ func (p *Peer) startProtocols(writeStart <-chan struct{}, writeErr chan<- error)
 // startProtocolsWithRWs run the protocol using several RWs.
 func (p *Peer) startProtocolsWithRWs(writeStarts []chan struct{}, writeErrs []chan error) {
 	p.wg.Add(len(p.running))
+	egressBucket := newTokenBucket()
 
 	for _, protos := range p.running {
 		rws := make([]MsgReadWriter, 0, len(protos))
@@ -542,6 +587,9 @@ func (p *Peer) startProtocolsWithRWs(writeStarts []chan struct{}, writeErrs []ch
 			proto.werr = writeErrs[i]
 
 			var rw MsgReadWriter = proto
+			if p.egress != nil {
+				rw = newThrottledRW(rw, p.egress, egressBucket, &p.egressExempt, p.closed)
+			}
 			if p.events != nil {
 				rw = newMsgEventer(rw, p.events, p.ID(), proto.Name)
 			}
@@ -600,6 +648,7 @@ func (rw *protoRW) WriteMsg(msg Msg) (err error) {
 	if msg.Code >= rw.Length {
 		return newPeerError(errInvalidMsgCode, "not handled, (code %x) (size %d)", msg.Code, msg.Size)
 	}
+	msg.compressible = rw.Compressible == nil || rw.Compressible(msg.Code)
 	msg.Code += rw.offset
 	rwCount := atomic.AddUint64(&rw.count, 1)
 	if rwCount%rw.tc.Interval == 0 {
@@ -688,18 +737,7 @@ func (p *Peer) Info() *PeerInfo {
 		network.Inbound = rw.is(inboundConn)
 		network.Trusted = rw.is(trustedConn)
 		network.Static = rw.is(staticDialedConn)
-		switch rw.conntype {
-		case common.CONSENSUSNODE:
-			network.NodeType = "cn"
-		case common.ENDPOINTNODE:
-			network.NodeType = "en"
-		case common.PROXYNODE:
-			network.NodeType = "pn"
-		case common.BOOTNODE:
-			network.NodeType = "bn"
-		default:
-			network.NodeType = "unknown"
-		}
+		network.NodeType = nodeTypeName(rw.conntype)
 		info.Networks = append(info.Networks, network)
 	}
 
@@ -722,6 +760,37 @@ func (p *Peer) ConnType() common.ConnType {
 	return p.rws[ConnDefault].conntype
 }
 
+// ListenAddrs returns the listen addresses advertised by the peer in the protocol
+// handshake, combined with the IP address the peer is connected from.
+func (p *Peer) ListenAddrs() []string {
+	var ip string
+	if addr, ok := p.RemoteAddr().(*net.TCPAddr); ok {
+		ip = addr.IP.String()
+	} else if host, _, err := net.SplitHostPort(p.RemoteAddr().String()); err == nil {
+		ip = host
+	}
+	addrs := make([]string, 0, len(p.rws[ConnDefault].listenPorts))
+	for _, port := range p.rws[ConnDefault].listenPorts {
+		addrs = append(addrs, net.JoinHostPort(ip, strconv.FormatUint(port, 10)))
+	}
+	return addrs
+}
+
+func nodeTypeName(t common.ConnType) string {
+	switch t {
+	case common.CONSENSUSNODE:
+		return "cn"
+	case common.ENDPOINTNODE:
+		return "en"
+	case common.PROXYNODE:
+		return "pn"
+	case common.BOOTNODE:
+		return "bn"
+	default:
+		return "unknown"
+	}
+}
+
 type PeerTypeValidator interface {
 	// ValidatePeerType returns nil if successful. Otherwise, it returns an error object.
 	ValidatePeerType(addr common.Address) error
@@ -65,6 +65,11 @@ type Protocol struct {
 	// about a certain peer in the network. If an info retrieval function is set,
 	// but returns nil, it is assumed that the protocol handshake is still running.
 	PeerInfo func(id discover.NodeID) interface{}
+
+	// Compressible is an optional helper method to report whether a message of the
+	// given code is worth compressing. If it is not set, all messages larger than
+	// the compression threshold are compressed.
+	Compressible func(code uint64) bool
 }
 
 func (p Protocol) cap() Cap {
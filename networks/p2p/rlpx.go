@@ -75,12 +75,20 @@ const (
 	// This is shorter than the usual timeout because we don't want
 	// to wait if the connection is known to be bad anyway.
 	discWriteTimeout = 1 * time.Second
+
+	// The first byte of the payload tells whether the payload is compressed
+	// if the selective compression is negotiated.
+	payloadUncompressed = 0x00
+	payloadSnappy       = 0x01
 )
 
 // errPlainMessageTooLarge is returned if a decompressed message length exceeds
 // the allowed 24 bits (i.e. length >= 16MB).
 var errPlainMessageTooLarge = errors.New("message length >= 16MB")
 
+// errInvalidCompressionFlag is returned if the compression flag of a payload is unknown.
+var errInvalidCompressionFlag = errors.New("invalid compression flag")
+
 // rlpx is the transport protocol used by actual (non-test) connections.
 // It wraps the frame encoder with locks and read/write deadlines.
 type rlpx struct {
@@ -183,6 +191,11 @@ func (t *rlpx) doProtoHandshake(our *protoHandshake) (their *protoHandshake, err
 	}
 	// If the protocol version supports Snappy encoding, upgrade immediately
 	t.rw.snappy = their.Version >= snappyProtocolVersion
+	// If both sides support the selective compression, only the large messages
+	// worth compressing are compressed with our threshold.
+	if t.rw.snappy && our.CompressionThreshold > 0 && their.CompressionThreshold > 0 {
+		t.rw.compressThreshold = uint32(our.CompressionThreshold)
+	}
 
 	return their, nil
 }
@@ -619,6 +632,10 @@ type rlpxFrameRW struct {
 	ingressMAC hash.Hash
 
 	snappy bool
+
+	// compressThreshold is the minimum size of the compressed messages if the
+	// selective compression is negotiated. It is 0 if all messages are compressed.
+	compressThreshold uint32
 }
 
 func newRLPXFrameRW(conn io.ReadWriter, s secrets) *rlpxFrameRW {
@@ -652,7 +669,11 @@ func (rw *rlpxFrameRW) WriteMsg(msg Msg) error {
 			return errPlainMessageTooLarge
 		}
 		payload, _ := ioutil.ReadAll(msg.Payload)
-		payload = snappy.Encode(nil, payload)
+		if rw.compressThreshold == 0 {
+			payload = snappy.Encode(nil, payload)
+		} else {
+			payload = rw.compressSelectively(msg, payload)
+		}
 
 		msg.Payload = bytes.NewReader(payload)
 		msg.Size = uint32(len(payload))
@@ -749,6 +770,21 @@ func (rw *rlpxFrameRW) ReadMsg() (msg Msg, err error) {
 		if err != nil {
 			return msg, err
 		}
+		if rw.compressThreshold > 0 {
+			if len(payload) == 0 {
+				return msg, errInvalidCompressionFlag
+			}
+			flag := payload[0]
+			payload = payload[1:]
+			switch flag {
+			case payloadUncompressed:
+				msg.Size, msg.Payload = uint32(len(payload)), bytes.NewReader(payload)
+				return msg, nil
+			case payloadSnappy:
+			default:
+				return msg, errInvalidCompressionFlag
+			}
+		}
 		size, err := snappy.DecodedLen(payload)
 		if err != nil {
 			return msg, err
@@ -765,6 +801,19 @@ func (rw *rlpxFrameRW) ReadMsg() (msg Msg, err error) {
 	return msg, nil
 }
 
+// compressSelectively compresses the payload only if the message is compressible
+// and not smaller than the threshold. The returned payload is prefixed with the
+// compression flag.
+func (rw *rlpxFrameRW) compressSelectively(msg Msg, payload []byte) []byte {
+	if !msg.compressible || uint32(len(payload)) < rw.compressThreshold {
+		uncompressedMsgCounter.Inc(1)
+		return append([]byte{payloadUncompressed}, payload...)
+	}
+	compressed := snappy.Encode(nil, payload)
+	compressionSavedBytesCounter.Inc(int64(len(payload)) - int64(len(compressed)))
+	return append([]byte{payloadSnappy}, compressed...)
+}
+
 // updateMAC reseeds the given hash with encrypted seed.
 // it returns the first 16 bytes of the hash sum after seeding.
 func updateMAC(mac hash.Hash, block cipher.Block, seed []byte) []byte {
@@ -382,6 +382,62 @@ func TestRLPXFrameRW(t *testing.T) {
 	}
 }
 
+func TestRLPXFrameRW_SelectiveCompression(t *testing.T) {
+	var (
+		aesSecret      = make([]byte, 16)
+		macSecret      = make([]byte, 16)
+		egressMACinit  = make([]byte, 32)
+		ingressMACinit = make([]byte, 32)
+	)
+	for _, s := range [][]byte{aesSecret, macSecret, egressMACinit, ingressMACinit} {
+		rand.Read(s)
+	}
+	conn := new(bytes.Buffer)
+
+	s1 := secrets{AES: aesSecret, MAC: macSecret, EgressMAC: sha3.NewKeccak256(), IngressMAC: sha3.NewKeccak256()}
+	s1.EgressMAC.Write(egressMACinit)
+	s1.IngressMAC.Write(ingressMACinit)
+	rw1 := newRLPXFrameRW(conn, s1)
+
+	s2 := secrets{AES: aesSecret, MAC: macSecret, EgressMAC: sha3.NewKeccak256(), IngressMAC: sha3.NewKeccak256()}
+	s2.EgressMAC.Write(ingressMACinit)
+	s2.IngressMAC.Write(egressMACinit)
+	rw2 := newRLPXFrameRW(conn, s2)
+
+	rw1.snappy, rw1.compressThreshold = true, 256
+	rw2.snappy, rw2.compressThreshold = true, 256
+
+	tests := []struct {
+		payload      []byte
+		compressible bool
+		compressed   bool
+	}{
+		{bytes.Repeat([]byte{0x01}, 100), true, false},   // smaller than the threshold
+		{bytes.Repeat([]byte{0x01}, 4096), true, true},   // compressed
+		{bytes.Repeat([]byte{0x01}, 4096), false, false}, // not compressible
+	}
+	for i, test := range tests {
+		msg := Msg{Code: uint64(i), Size: uint32(len(test.payload)), Payload: bytes.NewReader(test.payload), compressible: test.compressible}
+		if err := rw1.WriteMsg(msg); err != nil {
+			t.Fatalf("WriteMsg error (i=%d): %v", i, err)
+		}
+		if compressed := conn.Len() < len(test.payload); compressed != test.compressed {
+			t.Errorf("compression mismatch (i=%d): got %v, want %v", i, compressed, test.compressed)
+		}
+
+		read, err := rw2.ReadMsg()
+		if err != nil {
+			t.Fatalf("ReadMsg error (i=%d): %v", i, err)
+		}
+		if read.Code != uint64(i) || read.Size != uint32(len(test.payload)) {
+			t.Fatalf("msg mismatch (i=%d): code %d, size %d", i, read.Code, read.Size)
+		}
+		if payload, _ := ioutil.ReadAll(read.Payload); !bytes.Equal(payload, test.payload) {
+			t.Fatalf("msg payload mismatch (i=%d):\ngot  %x\nwant %x", i, payload, test.payload)
+		}
+	}
+}
+
 type handshakeAuthTest struct {
 	input       string
 	isPlain     bool
@@ -0,0 +1,246 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+)
+
+const (
+	maxPeerScore = 100
+	minPeerScore = -100
+
+	// A score loses half of its value every peerScoreHalfLife, so that
+	// a peer can recover from old misbehaviors and can't live on old merits.
+	peerScoreHalfLife = 10 * time.Minute
+
+	defaultPeerScoreBanThreshold = -50
+	defaultPeerScoreBanDuration  = time.Hour
+
+	// maxScoredPeers is the maximum number of peers whose scores are tracked.
+	maxScoredPeers = 4096
+)
+
+// PeerScoreEvent is an event which changes the score of a peer.
+type PeerScoreEvent uint8
+
+const (
+	PeerScoreValidBlock        PeerScoreEvent = iota // the peer delivered a new block
+	PeerScoreValidTx                                 // the peer delivered a transaction
+	PeerScoreTimeout                                 // the peer did not respond in time
+	PeerScoreProtocolViolation                       // the peer violated the protocol
+)
+
+var peerScoreEventDeltas = map[PeerScoreEvent]float64{
+	PeerScoreValidBlock:        2,
+	PeerScoreValidTx:           0.1,
+	PeerScoreTimeout:           -5,
+	PeerScoreProtocolViolation: -20,
+}
+
+var peerScoreEventToString = map[PeerScoreEvent]string{
+	PeerScoreValidBlock:        "validBlock",
+	PeerScoreValidTx:           "validTx",
+	PeerScoreTimeout:           "timeout",
+	PeerScoreProtocolViolation: "protocolViolation",
+}
+
+func (e PeerScoreEvent) String() string {
+	if s, ok := peerScoreEventToString[e]; ok {
+		return s
+	}
+	return "unknown"
+}
+
+// PeerScoreInfo represents the score of a peer.
+type PeerScoreInfo struct {
+	ID          string            `json:"id"`
+	Score       float64           `json:"score"`
+	Events      map[string]uint64 `json:"events"`
+	BannedUntil *time.Time        `json:"bannedUntil,omitempty"`
+}
+
+type peerScore struct {
+	value       float64
+	updated     time.Time
+	events      map[PeerScoreEvent]uint64
+	bannedUntil time.Time
+}
+
+// decay applies the exponential decay of the score until now.
+func (ps *peerScore) decay(now time.Time) {
+	if elapsed := now.Sub(ps.updated); elapsed > 0 {
+		ps.value *= math.Pow(0.5, float64(elapsed)/float64(peerScoreHalfLife))
+	}
+	ps.updated = now
+}
+
+// peerScorer tracks the usefulness of peers and decides which peers are banned.
+type peerScorer struct {
+	mu          sync.Mutex
+	scores      map[discover.NodeID]*peerScore
+	threshold   float64
+	banDuration time.Duration
+
+	now func() time.Time // for testing
+}
+
+func newPeerScorer(threshold float64, banDuration time.Duration) *peerScorer {
+	if threshold == 0 {
+		threshold = defaultPeerScoreBanThreshold
+	}
+	if banDuration == 0 {
+		banDuration = defaultPeerScoreBanDuration
+	}
+	return &peerScorer{
+		scores:      make(map[discover.NodeID]*peerScore),
+		threshold:   threshold,
+		banDuration: banDuration,
+		now:         time.Now,
+	}
+}
+
+// add applies the event to the score of the peer. It returns true if
+// the peer has been banned by this event.
+func (s *peerScorer) add(id discover.NodeID, ev PeerScoreEvent) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	ps, ok := s.scores[id]
+	if !ok {
+		if len(s.scores) >= maxScoredPeers {
+			s.prune(now)
+		}
+		ps = &peerScore{updated: now, events: make(map[PeerScoreEvent]uint64)}
+		s.scores[id] = ps
+	}
+	ps.decay(now)
+	ps.value = math.Max(minPeerScore, math.Min(maxPeerScore, ps.value+peerScoreEventDeltas[ev]))
+	ps.events[ev]++
+
+	if ps.value <= s.threshold && !ps.bannedUntil.After(now) {
+		ps.bannedUntil = now.Add(s.banDuration)
+		peerBanCounter.Inc(1)
+		return true
+	}
+	return false
+}
+
+// prune removes the scores which are not meaningful anymore.
+// If nothing can be removed, the score closest to zero is removed.
+func (s *peerScorer) prune(now time.Time) {
+	var (
+		closest    discover.NodeID
+		closestAbs = math.MaxFloat64
+	)
+	for id, ps := range s.scores {
+		if ps.bannedUntil.After(now) {
+			continue
+		}
+		ps.decay(now)
+		abs := math.Abs(ps.value)
+		if abs < 0.01 {
+			delete(s.scores, id)
+			continue
+		}
+		if abs < closestAbs {
+			closest, closestAbs = id, abs
+		}
+	}
+	if len(s.scores) >= maxScoredPeers && closestAbs != math.MaxFloat64 {
+		delete(s.scores, closest)
+	}
+}
+
+// score returns the current score of the peer.
+func (s *peerScorer) score(id discover.NodeID) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ps, ok := s.scores[id]
+	if !ok {
+		return 0
+	}
+	ps.decay(s.now())
+	return ps.value
+}
+
+// isBanned returns true if the peer is banned now.
+func (s *peerScorer) isBanned(id discover.NodeID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ps, ok := s.scores[id]
+	return ok && ps.bannedUntil.After(s.now())
+}
+
+// unban lifts the score-based ban of the peer.
+func (s *peerScorer) unban(id discover.NodeID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ps, ok := s.scores[id]; ok {
+		ps.bannedUntil = time.Time{}
+	}
+}
+
+// sortByScore sorts the nodes in descending order of the score.
+// The order of the nodes with the same score is preserved.
+func (s *peerScorer) sortByScore(nodes []*discover.Node) {
+	scores := make(map[discover.NodeID]float64, len(nodes))
+	for _, n := range nodes {
+		scores[n.ID] = s.score(n.ID)
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return scores[nodes[i].ID] > scores[nodes[j].ID]
+	})
+}
+
+// infos returns the scores of all tracked peers.
+func (s *peerScorer) infos() []*PeerScoreInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	infos := make([]*PeerScoreInfo, 0, len(s.scores))
+	for id, ps := range s.scores {
+		ps.decay(now)
+		info := &PeerScoreInfo{
+			ID:     id.String(),
+			Score:  ps.value,
+			Events: make(map[string]uint64, len(ps.events)),
+		}
+		for ev, cnt := range ps.events {
+			info.Events[ev.String()] = cnt
+		}
+		if ps.bannedUntil.After(now) {
+			bannedUntil := ps.bannedUntil
+			info.BannedUntil = &bannedUntil
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Score > infos[j].Score
+	})
+	return infos
+}
@@ -0,0 +1,153 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+)
+
+func newTestPeerScorer() (*peerScorer, *time.Time) {
+	now := time.Unix(1600000000, 0)
+	s := newPeerScorer(0, 0)
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func TestPeerScorer_Ban(t *testing.T) {
+	s, now := newTestPeerScorer()
+	id := uintID(1)
+
+	// Two violations do not reach the default threshold.
+	for i := 0; i < 2; i++ {
+		if s.add(id, PeerScoreProtocolViolation) {
+			t.Fatalf("peer banned after %d violations", i+1)
+		}
+	}
+	if s.isBanned(id) {
+		t.Fatal("peer should not be banned yet")
+	}
+
+	// The third one bans the peer only once.
+	if !s.add(id, PeerScoreProtocolViolation) {
+		t.Fatal("peer should be banned by the third violation")
+	}
+	if s.add(id, PeerScoreProtocolViolation) {
+		t.Fatal("already banned peer should not be reported again")
+	}
+	if !s.isBanned(id) {
+		t.Fatal("peer should be banned")
+	}
+
+	// The ban expires after the ban duration.
+	*now = now.Add(defaultPeerScoreBanDuration + time.Second)
+	if s.isBanned(id) {
+		t.Fatal("ban should have expired")
+	}
+}
+
+func TestPeerScorer_Decay(t *testing.T) {
+	s, now := newTestPeerScorer()
+	id := uintID(1)
+
+	s.add(id, PeerScoreTimeout)
+	if score := s.score(id); score != -5 {
+		t.Fatalf("score mismatch: have %v, want -5", score)
+	}
+
+	*now = now.Add(peerScoreHalfLife)
+	if score := s.score(id); math.Abs(score+2.5) > 1e-9 {
+		t.Fatalf("score mismatch after half-life: have %v, want -2.5", score)
+	}
+
+	// Unknown peers have a neutral score.
+	if score := s.score(uintID(2)); score != 0 {
+		t.Fatalf("score of unknown peer mismatch: have %v, want 0", score)
+	}
+}
+
+func TestPeerScorer_Bounds(t *testing.T) {
+	s, _ := newTestPeerScorer()
+	id := uintID(1)
+
+	for i := 0; i < 100; i++ {
+		s.add(id, PeerScoreValidBlock)
+	}
+	if score := s.score(id); score != maxPeerScore {
+		t.Fatalf("score mismatch: have %v, want %v", score, maxPeerScore)
+	}
+}
+
+func TestPeerScorer_SortByScore(t *testing.T) {
+	s, _ := newTestPeerScorer()
+
+	s.add(uintID(1), PeerScoreTimeout)
+	s.add(uintID(3), PeerScoreValidBlock)
+
+	nodes := []*discover.Node{{ID: uintID(1)}, {ID: uintID(2)}, {ID: uintID(3)}, {ID: uintID(4)}}
+	s.sortByScore(nodes)
+
+	want := []discover.NodeID{uintID(3), uintID(2), uintID(4), uintID(1)}
+	for i, n := range nodes {
+		if n.ID != want[i] {
+			t.Errorf("node %d mismatch: have %v, want %v", i, n.ID, want[i])
+		}
+	}
+}
+
+func TestPeerScorer_Infos(t *testing.T) {
+	s, _ := newTestPeerScorer()
+
+	for i := 0; i < 3; i++ {
+		s.add(uintID(1), PeerScoreProtocolViolation)
+	}
+	s.add(uintID(2), PeerScoreValidTx)
+
+	infos := s.infos()
+	if len(infos) != 2 {
+		t.Fatalf("infos length mismatch: have %d, want 2", len(infos))
+	}
+	if infos[0].ID != uintID(2).String() || infos[0].BannedUntil != nil {
+		t.Errorf("unexpected first info: %+v", infos[0])
+	}
+	if infos[1].ID != uintID(1).String() || infos[1].BannedUntil == nil {
+		t.Errorf("unexpected second info: %+v", infos[1])
+	}
+	if cnt := infos[1].Events[PeerScoreProtocolViolation.String()]; cnt != 3 {
+		t.Errorf("event count mismatch: have %d, want 3", cnt)
+	}
+}
+
+func TestPeerScorer_Unban(t *testing.T) {
+	s, _ := newTestPeerScorer()
+	id := uintID(1)
+
+	// unban lifts the score-based ban.
+	for i := 0; i < 3; i++ {
+		s.add(id, PeerScoreProtocolViolation)
+	}
+	if !s.isBanned(id) {
+		t.Fatal("peer should be banned by its score")
+	}
+	s.unban(id)
+	if s.isBanned(id) {
+		t.Fatal("peer should not be banned after unban")
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+)
+
+const sentryCheckInterval = 30 * time.Second
+
+// SentryInfo is the connection state of a sentry node.
+type SentryInfo struct {
+	ID        string     `json:"id"`
+	Address   string     `json:"address"`
+	Connected bool       `json:"connected"`
+	LastSeen  *time.Time `json:"lastSeen,omitempty"` // last time the sentry was found connected
+}
+
+// sentrySet is the set of the sentry nodes of a server running in sentry mode.
+type sentrySet struct {
+	nodes []*discover.Node
+
+	mu       sync.Mutex
+	lastSeen map[discover.NodeID]time.Time
+	healthy  bool // false if no sentry was connected at the last check
+}
+
+func newSentrySet(nodes []*discover.Node) *sentrySet {
+	return &sentrySet{nodes: nodes, lastSeen: make(map[discover.NodeID]time.Time), healthy: true}
+}
+
+func (s *sentrySet) contains(id discover.NodeID) bool {
+	for _, n := range s.nodes {
+		if n.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// check records the connected sentries and returns their number. It returns
+// whether the health of the sentry set changed, i.e. whether no sentry is
+// connected anymore or some sentry is connected again.
+func (s *sentrySet) check(connected map[discover.NodeID]bool, now time.Time) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, n := range s.nodes {
+		if connected[n.ID] {
+			s.lastSeen[n.ID] = now
+			count++
+		}
+	}
+	healthy := count > 0
+	changed := healthy != s.healthy
+	s.healthy = healthy
+	return count, changed
+}
+
+func (s *sentrySet) infos(connected map[discover.NodeID]bool) []*SentryInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	infos := make([]*SentryInfo, 0, len(s.nodes))
+	for _, n := range s.nodes {
+		info := &SentryInfo{ID: n.ID.String(), Address: net.JoinHostPort(n.IP.String(), strconv.Itoa(int(n.TCP))), Connected: connected[n.ID]}
+		if t, ok := s.lastSeen[n.ID]; ok {
+			info.LastSeen = &t
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// applySentryMode configures the server to connect only to the sentry nodes.
+// The sentries are dialed as static nodes and trusted so that they are always
+// accepted, and the discovery is disabled not to advertise the IP address.
+func applySentryMode(config *Config) {
+	config.NoDiscovery = true
+	config.DNSDiscovery = nil
+	config.BootstrapNodes = nil
+	for _, n := range config.SentryNodes {
+		if !containsNodeID(config.StaticNodes, n.ID) {
+			config.StaticNodes = append(config.StaticNodes, n)
+		}
+		if !containsNodeID(config.TrustedNodes, n.ID) {
+			config.TrustedNodes = append(config.TrustedNodes, n)
+		}
+	}
+}
+
+func containsNodeID(nodes []*discover.Node, id discover.NodeID) bool {
+	for _, n := range nodes {
+		if n.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// connectedIDs returns the IDs of the connected peers.
+func (srv *BaseServer) connectedIDs() map[discover.NodeID]bool {
+	connected := make(map[discover.NodeID]bool)
+	for _, p := range srv.Peers() {
+		connected[p.ID()] = true
+	}
+	return connected
+}
+
+// sentryLoop checks the connections to the sentry nodes periodically and warns
+// if the server is isolated from all of them.
+func (srv *BaseServer) sentryLoop() {
+	defer srv.loopWG.Done()
+
+	ticker := time.NewTicker(sentryCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-srv.quit:
+			return
+		case now := <-ticker.C:
+			count, changed := srv.sentries.check(srv.connectedIDs(), now)
+			sentryPeersGauge.Update(int64(count))
+			switch {
+			case count == 0:
+				srv.logger.Warn("No sentry node is connected", "sentries", len(srv.sentries.nodes))
+			case changed:
+				srv.logger.Info("Sentry node connection recovered", "connected", count, "sentries", len(srv.sentries.nodes))
+			}
+		}
+	}
+}
+
+// Sentries returns the connection states of the sentry nodes. It returns nil
+// if the server is not in sentry mode.
+func (srv *BaseServer) Sentries() []*SentryInfo {
+	if srv.sentries == nil {
+		return nil
+	}
+	return srv.sentries.infos(srv.connectedIDs())
+}
@@ -0,0 +1,79 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+)
+
+func TestApplySentryMode(t *testing.T) {
+	sentry1 := &discover.Node{ID: uintID(1), IP: net.IP{127, 0, 0, 1}, TCP: 30301}
+	sentry2 := &discover.Node{ID: uintID(2), IP: net.IP{127, 0, 0, 2}, TCP: 30302}
+	config := Config{
+		DNSDiscovery:   []string{"kntree://key@nodes.example.org"},
+		BootstrapNodes: []*discover.Node{{ID: uintID(3)}},
+		StaticNodes:    []*discover.Node{sentry1},
+		SentryNodes:    []*discover.Node{sentry1, sentry2},
+	}
+	applySentryMode(&config)
+
+	if !config.NoDiscovery || config.DNSDiscovery != nil || config.BootstrapNodes != nil {
+		t.Errorf("discovery should be disabled: nodiscover %v, dns %v, bootnodes %v",
+			config.NoDiscovery, config.DNSDiscovery, config.BootstrapNodes)
+	}
+	if len(config.StaticNodes) != 2 || !containsNodeID(config.StaticNodes, sentry2.ID) {
+		t.Errorf("sentries should be static nodes once: %v", config.StaticNodes)
+	}
+	if len(config.TrustedNodes) != 2 || !containsNodeID(config.TrustedNodes, sentry1.ID) {
+		t.Errorf("sentries should be trusted nodes: %v", config.TrustedNodes)
+	}
+}
+
+func TestSentrySet_Check(t *testing.T) {
+	sentry1 := &discover.Node{ID: uintID(1), IP: net.IP{127, 0, 0, 1}, TCP: 30301}
+	sentry2 := &discover.Node{ID: uintID(2), IP: net.IP{127, 0, 0, 2}, TCP: 30302}
+	s := newSentrySet([]*discover.Node{sentry1, sentry2})
+	now := time.Unix(1600000000, 0)
+
+	if !s.contains(sentry1.ID) || s.contains(uintID(3)) {
+		t.Fatal("contains returned a wrong result")
+	}
+	if count, changed := s.check(map[discover.NodeID]bool{sentry1.ID: true, uintID(3): true}, now); count != 1 || changed {
+		t.Errorf("check returned count %d, changed %v; want 1, false", count, changed)
+	}
+	if count, changed := s.check(nil, now.Add(time.Minute)); count != 0 || !changed {
+		t.Errorf("check returned count %d, changed %v; want 0, true", count, changed)
+	}
+	if count, changed := s.check(map[discover.NodeID]bool{sentry2.ID: true}, now.Add(2*time.Minute)); count != 1 || !changed {
+		t.Errorf("check returned count %d, changed %v; want 1, true", count, changed)
+	}
+
+	infos := s.infos(map[discover.NodeID]bool{sentry2.ID: true})
+	if len(infos) != 2 {
+		t.Fatalf("got %d sentry infos, want 2", len(infos))
+	}
+	if infos[0].Connected || infos[0].LastSeen == nil || !infos[0].LastSeen.Equal(now) {
+		t.Errorf("wrong info of sentry1: %+v", infos[0])
+	}
+	if !infos[1].Connected || infos[1].Address != "127.0.0.2:30302" {
+		t.Errorf("wrong info of sentry2: %+v", infos[1])
+	}
+}
@@ -22,6 +22,7 @@ package p2p
 
 import (
 	"crypto/ecdsa"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
@@ -36,6 +37,7 @@ import (
 	"github.com/klaytn/klaytn/networks/p2p/discover"
 	"github.com/klaytn/klaytn/networks/p2p/nat"
 	"github.com/klaytn/klaytn/networks/p2p/netutil"
+	"github.com/klaytn/klaytn/params"
 
 	"github.com/klaytn/klaytn/common"
 )
@@ -48,6 +50,9 @@ const (
 	defaultMaxPendingPeers = 50
 	defaultDialRatio       = 3
 
+	// Messages smaller than this are not worth compressing.
+	defaultCompressionThreshold = 1024
+
 	// Maximum time allowed for reading a complete message.
 	// This is effectively the amount of time a connection can be idle.
 	frameReadTimeout = 30 * time.Second
@@ -87,6 +92,14 @@ type Config struct {
 	// Disabling is useful for protocol debugging (manual topology).
 	NoDiscovery bool
 
+	// DiscoveryTopics are advertised through the peer discovery, so that the
+	// nodes looking for specific capabilities can find this node selectively.
+	DiscoveryTopics []string `toml:",omitempty"`
+
+	// DNSDiscovery are the URLs of the DNS node trees (kntree://<key>@<domain>)
+	// whose nodes are used as dial candidates in addition to the discovered nodes.
+	DNSDiscovery []string `toml:",omitempty"`
+
 	// Name sets the node name of this server.
 	// Use common.MakeName to create a name that follows existing conventions.
 	Name string `toml:"-"`
@@ -108,11 +121,30 @@ type Config struct {
 	// allowed to connect, even above the peer limit.
 	TrustedNodes []*discover.Node
 
+	// SentryNodes makes the server run in sentry mode, which is used by validators
+	// hiding their IP addresses behind the sentry nodes. The server connects only
+	// to the sentry nodes, which are always maintained and trusted, and the
+	// discovery is disabled.
+	SentryNodes []*discover.Node `toml:",omitempty"`
+
 	// Connectivity can be restricted to certain IP networks.
 	// If this option is set to a non-nil value, only hosts which match one of the
 	// IP networks contained in the list are considered.
 	NetRestrict *netutil.Netlist `toml:",omitempty"`
 
+	// IPPolicy selects the IP address families used for listening, discovery and
+	// dialing, and the address of host names resolving to both families.
+	IPPolicy netutil.IPPolicy `toml:",omitempty"`
+
+	// ExtIPv6 is the public IPv6 address announced through the discovery in place
+	// of the listening or NAT address if IPPolicy prefers IPv6.
+	ExtIPv6 net.IP `toml:",omitempty"`
+
+	// TLS wraps the connections in mutually-authenticated TLS if set. It is meant
+	// for the private networks which require transport encryption by a standard
+	// protocol in addition to RLPx. Peers without TLS cannot connect.
+	TLS *TLSConfig `toml:",omitempty"`
+
 	// NodeDatabase is the path to the database containing the previously seen
 	// live nodes in the network.
 	NodeDatabase string `toml:",omitempty"`
@@ -164,12 +196,51 @@ type Config struct {
 
 	// NetworkID to use for selecting peers to connect to
 	NetworkID uint64
+
+	// PeerScoreBanThreshold is the score at or below which a peer is disconnected and banned.
+	// Zero defaults to preset values.
+	PeerScoreBanThreshold float64 `toml:",omitempty"`
+
+	// PeerScoreBanDuration is the duration a peer is banned for.
+	// Zero defaults to preset values.
+	PeerScoreBanDuration time.Duration `toml:",omitempty"`
+
+	// MaxInboundPerIP is the maximum number of concurrent inbound connections from a single IP.
+	// Zero means unlimited.
+	MaxInboundPerIP int `toml:",omitempty"`
+
+	// MaxInboundPerSubnet is the maximum number of concurrent inbound connections from
+	// a single /24 IPv4 or /64 IPv6 subnet. Zero means unlimited.
+	MaxInboundPerSubnet int `toml:",omitempty"`
+
+	// MaxPeerEgressBytesPerSec is the maximum egress bandwidth to a single peer in bytes
+	// per second. The consensus messages are not limited. Zero means unlimited.
+	MaxPeerEgressBytesPerSec int `toml:",omitempty"`
+
+	// MaxEgressBytesPerSec is the maximum egress bandwidth to all peers in bytes per second.
+	// The consensus messages are not limited. Zero means unlimited.
+	MaxEgressBytesPerSec int `toml:",omitempty"`
+
+	// CompressionThreshold is the minimum size of the messages compressed for the peers
+	// supporting the selective compression. Zero defaults to preset values and a
+	// negative value disables the selective compression, compressing all messages.
+	CompressionThreshold int `toml:",omitempty"`
 }
 
 // NewServer returns a new Server interface.
 func NewServer(config Config) Server {
+	var sentries *sentrySet
+	if len(config.SentryNodes) > 0 {
+		applySentryMode(&config)
+		sentries = newSentrySet(config.SentryNodes)
+	}
 	bServer := &BaseServer{
-		Config: config,
+		Config:         config,
+		scorer:         newPeerScorer(config.PeerScoreBanThreshold, config.PeerScoreBanDuration),
+		bans:           newBanList(),
+		inboundLimiter: newInboundLimiter(config.MaxInboundPerIP, config.MaxInboundPerSubnet),
+		egressLimiter:  newEgressLimiter(config.MaxPeerEgressBytesPerSec, config.MaxEgressBytesPerSec),
+		sentries:       sentries,
 	}
 
 	if config.EnableMultiChannelServer {
@@ -242,6 +313,36 @@ type Server interface {
 	// RemovePeer disconnects from the given node.
 	RemovePeer(node *discover.Node)
 
+	// AddTrustedPeer adds the given node to a reserved whitelist which allows the
+	// node to always connect, even if the slot are full.
+	AddTrustedPeer(node *discover.Node)
+
+	// RemoveTrustedPeer removes the given node from the trusted peer set.
+	RemoveTrustedPeer(node *discover.Node)
+
+	// BanPeer disconnects from the given node and rejects the connections
+	// from/to the node until UnbanPeer is called or the expiry is reached.
+	// A zero expiry means the ban is permanent.
+	BanPeer(id discover.NodeID, expiry time.Time)
+
+	// UnbanPeer lifts the ban on the given node set by BanPeer and the ban by its low score.
+	UnbanPeer(id discover.NodeID)
+
+	// BanIP disconnects from the peers in the IP network given as an IP address
+	// or in CIDR notation, and rejects the connections from/to the network until
+	// UnbanIP is called or the expiry is reached. A zero expiry means the ban is permanent.
+	BanIP(cidr string, expiry time.Time) error
+
+	// UnbanIP lifts the ban on the IP network set by BanIP.
+	UnbanIP(cidr string) error
+
+	// Bans returns the bans on node IDs and IP networks in effect.
+	Bans() []*BanInfo
+
+	// Sentries returns the connection states of the sentry nodes. It returns nil
+	// if the server is not in sentry mode.
+	Sentries() []*SentryInfo
+
 	// SubscribePeers subscribes the given channel to peer events.
 	SubscribeEvents(ch chan *PeerEvent) event.Subscription
 
@@ -272,6 +373,41 @@ type Server interface {
 	// Peers returns all connected peers.
 	Peers() []*Peer
 
+	// PeerScores returns the scores of the peers which have been scored.
+	PeerScores() []*PeerScoreInfo
+
+	// NATStatus returns the detected external endpoint and the health of
+	// the port mappings. It returns nil if NAT is not configured.
+	NATStatus() *nat.Status
+
+	// LookupTopic returns at most max nodes advertised with the topic through the discovery.
+	LookupTopic(topic string, max int) []*discover.Node
+
+	// SetBootnodes replaces the boot nodes of the running discovery.
+	SetBootnodes(nodes []*discover.Node) error
+
+	// InboundLimits returns the inbound connection limits and the IPs with the most inbound connections.
+	InboundLimits() *InboundLimitInfo
+
+	// SetInboundLimit overrides the per-IP inbound connection limit of the IPs in the CIDR.
+	SetInboundLimit(cidr string, limit int) error
+
+	// RemoveInboundLimit removes the per-IP inbound connection limit override of the CIDR.
+	RemoveInboundLimit(cidr string) error
+
+	// SetInboundLimits changes the default inbound connection limits per IP and per subnet.
+	SetInboundLimits(maxPerIP, maxPerSubnet int) error
+
+	// EgressLimits returns the egress bandwidth limits in bytes per second.
+	EgressLimits() *EgressLimitInfo
+
+	// SetEgressLimits changes the egress bandwidth limits per peer and of all peers in bytes per second.
+	SetEgressLimits(peerBytesPerSec, globalBytesPerSec int) error
+
+	// Topology returns this node and its connected peers with their advertised
+	// listen addresses, node types and connection directions.
+	Topology() *Topology
+
 	// NodeDialer is used to connect to nodes in the network, typically by using
 	// an underlying net.Dialer but also using net.Pipe in tests.
 	NodeDialer
@@ -312,6 +448,11 @@ func (srv *MultiChannelServer) Start() (err error) {
 	if srv.newTransport == nil {
 		srv.newTransport = newRLPX
 	}
+	if srv.TLS != nil {
+		if srv.tlsConfig, err = newTLSConfig(srv.TLS); err != nil {
+			return err
+		}
+	}
 	if srv.Dialer == nil {
 		srv.Dialer = TCPDialer{&net.Dialer{Timeout: defaultDialTimeout}}
 	}
@@ -321,10 +462,20 @@ func (srv *MultiChannelServer) Start() (err error) {
 	srv.posthandshake = make(chan *conn)
 	srv.addstatic = make(chan *discover.Node)
 	srv.removestatic = make(chan *discover.Node)
+	srv.addtrusted = make(chan *discover.Node)
+	srv.removetrusted = make(chan *discover.Node)
 	srv.peerOp = make(chan peerOpFunc)
 	srv.peerOpDone = make(chan struct{})
 	srv.discpeer = make(chan discover.NodeID)
 
+	if srv.NAT != nil {
+		srv.natMonitor = nat.NewMonitor(srv.NAT)
+		go srv.natMonitor.Run(srv.quit, nil)
+		if srv.NoDiscovery {
+			go srv.natMonitor.CheckExternalIP()
+		}
+	}
+
 	var (
 		conn      *net.UDPConn
 		realaddr  *net.UDPAddr
@@ -332,24 +483,25 @@ func (srv *MultiChannelServer) Start() (err error) {
 	)
 
 	if !srv.NoDiscovery {
-		addr, err := net.ResolveUDPAddr("udp", srv.ListenAddrs[ConnDefault])
+		addr, err := net.ResolveUDPAddr(srv.IPPolicy.Network("udp"), srv.ListenAddrs[ConnDefault])
 		if err != nil {
 			return err
 		}
-		conn, err = net.ListenUDP("udp", addr)
+		conn, err = net.ListenUDP(srv.IPPolicy.Network("udp"), addr)
 		if err != nil {
 			return err
 		}
 		realaddr = conn.LocalAddr().(*net.UDPAddr)
 		if srv.NAT != nil {
 			if !realaddr.IP.IsLoopback() {
-				go nat.Map(srv.NAT, srv.quit, "udp", realaddr.Port, realaddr.Port, "klaytn discovery")
+				go srv.natMonitor.Map(srv.quit, "udp", realaddr.Port, realaddr.Port, "klaytn discovery")
 			}
 			// TODO: react to external IP changes over time.
-			if ext, err := srv.NAT.ExternalIP(); err == nil {
+			if ext, _ := srv.natMonitor.CheckExternalIP(); ext != nil {
 				realaddr = &net.UDPAddr{IP: ext, Port: realaddr.Port}
 			}
 		}
+		realaddr = &net.UDPAddr{IP: srv.advertisedIP(realaddr.IP), Port: realaddr.Port}
 	}
 
 	// node table
@@ -359,6 +511,7 @@ func (srv *MultiChannelServer) Start() (err error) {
 			AnnounceAddr: realaddr,
 			NodeDBPath:   srv.NodeDatabase,
 			NetRestrict:  srv.NetRestrict,
+			IPPolicy:     srv.IPPolicy,
 			Bootnodes:    srv.BootstrapNodes,
 			Unhandled:    unhandled,
 			Conn:         conn,
@@ -373,19 +526,30 @@ func (srv *MultiChannelServer) Start() (err error) {
 			return err
 		}
 		srv.ntab = ntab
+		srv.registerTopics()
 	}
 
 	dialer := newDialState(srv.StaticNodes, srv.BootstrapNodes, srv.ntab, srv.maxDialedConns(), srv.NetRestrict, srv.PrivateKey, srv.getTypeStatics())
+	dialer.scorer = srv.scorer
+	dialer.bans = srv.bans
+	dialer.ipPolicy = srv.IPPolicy
+	if len(srv.DNSDiscovery) > 0 {
+		dns, err := newDNSNodeSource(srv.DNSDiscovery)
+		if err != nil {
+			return err
+		}
+		dialer.dns = dns
+	}
 
 	// handshake
-	srv.ourHandshake = &protoHandshake{Version: baseProtocolVersion, Name: srv.Name(), ID: discover.PubkeyID(&srv.PrivateKey.PublicKey), Multichannel: true}
+	srv.ourHandshake = &protoHandshake{Version: baseProtocolVersion, Name: srv.Name(), ID: discover.PubkeyID(&srv.PrivateKey.PublicKey), Multichannel: true, CompressionThreshold: srv.compressionThreshold()}
 	for _, p := range srv.Protocols {
 		srv.ourHandshake.Caps = append(srv.ourHandshake.Caps, p.cap())
 	}
 	for _, l := range srv.ListenAddrs {
-		s := strings.Split(l, ":")
-		if len(s) == 2 {
-			if port, err := strconv.Atoi(s[1]); err == nil {
+		// SplitHostPort handles the bracketed IPv6 hosts such as [::]:32323.
+		if _, p, err := net.SplitHostPort(l); err == nil {
+			if port, err := strconv.Atoi(p); err == nil {
 				srv.ourHandshake.ListenPort = append(srv.ourHandshake.ListenPort, uint64(port))
 			}
 		}
@@ -405,6 +569,14 @@ func (srv *MultiChannelServer) Start() (err error) {
 		}
 	}
 
+	if dialer.dns != nil {
+		srv.loopWG.Add(1)
+		go dialer.dns.loop(srv.quit, &srv.loopWG)
+	}
+	if srv.sentries != nil {
+		srv.loopWG.Add(1)
+		go srv.sentryLoop()
+	}
 	srv.loopWG.Add(1)
 	go srv.run(dialer)
 	srv.running = true
@@ -416,7 +588,7 @@ func (srv *MultiChannelServer) Start() (err error) {
 func (srv *MultiChannelServer) startListening() error {
 	// Launch the TCP listener.
 	for i, listenAddr := range srv.ListenAddrs {
-		listener, err := net.Listen("tcp", listenAddr)
+		listener, err := net.Listen(srv.IPPolicy.Network("tcp"), listenAddr)
 		if err != nil {
 			return err
 		}
@@ -429,7 +601,7 @@ func (srv *MultiChannelServer) startListening() error {
 		if !laddr.IP.IsLoopback() && srv.NAT != nil {
 			srv.loopWG.Add(1)
 			go func() {
-				nat.Map(srv.NAT, srv.quit, "tcp", laddr.Port, laddr.Port, "klaytn p2p")
+				srv.natMonitor.Map(srv.quit, "tcp", laddr.Port, laddr.Port, "klaytn p2p")
 				srv.loopWG.Done()
 			}()
 		}
@@ -481,7 +653,29 @@ func (srv *MultiChannelServer) listenLoop(listener net.Listener) {
 			}
 		}
 
+		// Reject connections from the banned IP networks.
+		if tcp, ok := fd.RemoteAddr().(*net.TCPAddr); ok && srv.bans != nil && srv.bans.isIPBanned(tcp.IP) {
+			srv.logger.Debug("Rejected conn (banned IP)", "addr", fd.RemoteAddr())
+			fd.Close()
+			slots <- struct{}{}
+			continue
+		}
+
+		var release func()
+		if tcp, ok := fd.RemoteAddr().(*net.TCPAddr); ok && srv.inboundLimiter != nil {
+			var err error
+			if release, err = srv.inboundLimiter.acquire(tcp.IP); err != nil {
+				srv.logger.Debug("Rejected conn", "addr", fd.RemoteAddr(), "err", err)
+				fd.Close()
+				slots <- struct{}{}
+				continue
+			}
+		}
+
 		fd = newMeteredConn(fd, true)
+		if release != nil {
+			fd = &limitedConn{Conn: fd, release: release}
+		}
 		srv.logger.Trace("Accepted connection", "addr", fd.RemoteAddr())
 		go func() {
 			srv.SetupConn(fd, inboundConn, nil)
@@ -498,6 +692,13 @@ func (srv *MultiChannelServer) SetupConn(fd net.Conn, flags connFlag, dialDest *
 	if self == nil {
 		return errors.New("shutdown")
 	}
+	tlsConn, err := srv.wrapTLS(fd, flags)
+	if err != nil {
+		srv.logger.Debug("TLS handshake failed", "addr", fd.RemoteAddr(), "err", err)
+		fd.Close()
+		return err
+	}
+	fd = tlsConn
 
 	c := &conn{fd: fd, transport: srv.newTransport(fd), flags: flags, conntype: common.ConnTypeUndefined, cont: make(chan error), portOrder: PortOrderUndefined}
 	if dialDest != nil {
@@ -513,7 +714,7 @@ func (srv *MultiChannelServer) SetupConn(fd net.Conn, flags connFlag, dialDest *
 		}
 	}
 
-	err := srv.setupConn(c, flags, dialDest)
+	err = srv.setupConn(c, flags, dialDest)
 	if err != nil {
 		c.close(err)
 		srv.logger.Trace("close connection", "id", c.id, "err", err)
@@ -569,7 +770,7 @@ func (srv *MultiChannelServer) setupConn(c *conn, flags connFlag, dialDest *disc
 		clog.Trace("Wrong devp2p handshake identity", "err", phs.ID)
 		return DiscUnexpectedIdentity
 	}
-	c.caps, c.name, c.multiChannel = phs.Caps, phs.Name, phs.Multichannel
+	c.caps, c.name, c.multiChannel, c.listenPorts = phs.Caps, phs.Name, phs.Multichannel, phs.ListenPort
 
 	if c.multiChannel && dialDest != nil && (dialDest.TCPs == nil || len(dialDest.TCPs) < 2) && len(dialDest.TCPs) < len(phs.ListenPort) {
 		logger.Debug("[Dial] update and retry the dial candidate as a multichannel",
@@ -607,8 +808,7 @@ func (srv *MultiChannelServer) run(dialstate dialer) {
 		queuedTasks   []task // tasks that can't run yet
 	)
 	// Put trusted nodes into a map to speed up checks.
-	// Trusted peers are loaded on startup and cannot be
-	// modified while the server is running.
+	// Trusted peers are loaded on startup or added via AddTrustedPeer RPC.
 	for _, n := range srv.TrustedNodes {
 		trusted[n.ID] = true
 	}
@@ -666,6 +866,16 @@ running:
 			if p, ok := peers[n.ID]; ok {
 				p.Disconnect(DiscRequested)
 			}
+		case n := <-srv.addtrusted:
+			// This channel is used by AddTrustedPeer to add a node
+			// to the trusted node set.
+			srv.logger.Debug("Adding trusted node", "node", n)
+			trusted[n.ID] = true
+		case n := <-srv.removetrusted:
+			// This channel is used by RemoveTrustedPeer to remove a node
+			// from the trusted node set.
+			srv.logger.Debug("Removing trusted node", "node", n)
+			delete(trusted, n.ID)
 		case op := <-srv.peerOp:
 			// This channel is used by Peers and PeerCount.
 			op(peers)
@@ -732,6 +942,8 @@ running:
 					if srv.EnableMsgEvents {
 						p.events = &srv.peerFeed
 					}
+					p.scorer = srv.scorer
+					p.egress = srv.egressLimiter
 					name := truncateName(c.name)
 					srv.logger.Debug("Adding p2p peer", "name", name, "addr", c.fd.RemoteAddr(), "peers", len(peers)+1)
 					go srv.runPeer(p)
@@ -754,6 +966,7 @@ running:
 			d := common.PrettyDuration(mclock.Now() - pd.created)
 			pd.logger.Debug("Removing p2p peer", "duration", d, "peers", len(peers)-1, "req", pd.requested, "err", pd.err)
 			delete(peers, pd.ID())
+			srv.scoreDroppedPeer(pd)
 
 			peerCountGauge.Update(int64(len(peers)))
 			inboundCount, outboundCount = decreasesConnectionMetric(inboundCount, outboundCount, pd.Peer)
@@ -906,6 +1119,7 @@ type BaseServer struct {
 	// Hooks for testing. These are useful because we can inhibit
 	// the whole protocol stack.
 	newTransport func(net.Conn) transport
+	tlsConfig    *tls.Config // nil if TLS is not configured
 	newPeerHook  func(*Peer)
 
 	lock    sync.Mutex // protects running
@@ -925,6 +1139,8 @@ type BaseServer struct {
 	quit          chan struct{}
 	addstatic     chan *discover.Node
 	removestatic  chan *discover.Node
+	addtrusted    chan *discover.Node
+	removetrusted chan *discover.Node
 	posthandshake chan *conn
 	addpeer       chan *conn
 	delpeer       chan peerDrop
@@ -932,6 +1148,14 @@ type BaseServer struct {
 	loopWG        sync.WaitGroup // loop, listenLoop
 	peerFeed      event.Feed
 	logger        log.Logger
+
+	scorer     *peerScorer  // tracks the usefulness of peers
+	bans       *banList     // bans on node IDs and IP networks set by the operator
+	sentries   *sentrySet   // nil if the server is not in sentry mode
+	natMonitor *nat.Monitor // reports the NAT status, nil if NAT is not configured
+
+	inboundLimiter *inboundLimiter // limits the inbound connections per IP and subnet
+	egressLimiter  *egressLimiter  // throttles the messages sent to peers
 }
 
 type peerOpFunc func(map[discover.NodeID]*Peer)
@@ -970,6 +1194,7 @@ type conn struct {
 	name         string          // valid after the protocol handshake
 	portOrder    PortOrder       // portOrder is the order of the ports that should be connected in multi-channel.
 	multiChannel bool            // multiChannel is whether the peer is using multi-channel.
+	listenPorts  []uint64        // valid after the protocol handshake
 }
 
 type transport interface {
@@ -1099,6 +1324,83 @@ func (srv *BaseServer) RemovePeer(node *discover.Node) {
 	}
 }
 
+// AddTrustedPeer adds the given node to a reserved whitelist which allows the
+// node to always connect, even if the slot are full.
+func (srv *BaseServer) AddTrustedPeer(node *discover.Node) {
+	select {
+	case srv.addtrusted <- node:
+	case <-srv.quit:
+	}
+}
+
+// RemoveTrustedPeer removes the given node from the trusted peer set.
+func (srv *BaseServer) RemoveTrustedPeer(node *discover.Node) {
+	select {
+	case srv.removetrusted <- node:
+	case <-srv.quit:
+	}
+}
+
+// BanPeer disconnects from the given node and rejects the connections
+// from/to the node until UnbanPeer is called or the expiry is reached.
+// A zero expiry means the ban is permanent.
+func (srv *BaseServer) BanPeer(id discover.NodeID, expiry time.Time) {
+	srv.bans.banID(id, expiry)
+	srv.disconnect(id)
+}
+
+// UnbanPeer lifts the ban on the given node set by BanPeer and the ban by its low score.
+func (srv *BaseServer) UnbanPeer(id discover.NodeID) {
+	srv.bans.unbanID(id)
+	srv.scorer.unban(id)
+}
+
+// BanIP disconnects from the peers in the IP network given as an IP address
+// or in CIDR notation, and rejects the connections from/to the network until
+// UnbanIP is called or the expiry is reached. A zero expiry means the ban is permanent.
+func (srv *BaseServer) BanIP(cidr string, expiry time.Time) error {
+	network, err := srv.bans.banIP(cidr, expiry)
+	if err != nil {
+		return err
+	}
+	for _, p := range srv.Peers() {
+		if tcp, ok := p.RemoteAddr().(*net.TCPAddr); ok && network.Contains(tcp.IP) {
+			srv.disconnect(p.ID())
+		}
+	}
+	return nil
+}
+
+// UnbanIP lifts the ban on the IP network set by BanIP.
+func (srv *BaseServer) UnbanIP(cidr string) error {
+	return srv.bans.unbanIP(cidr)
+}
+
+// advertisedIP returns the IP address announced through the discovery. ExtIPv6
+// is announced instead of ip if the IP policy prefers IPv6.
+func (srv *BaseServer) advertisedIP(ip net.IP) net.IP {
+	if srv.ExtIPv6 != nil && srv.IPPolicy.PrefersIPv6() {
+		return srv.ExtIPv6
+	}
+	if !ip.IsUnspecified() && !srv.IPPolicy.Allows(ip) {
+		srv.logger.Warn("Announced IP address is not allowed by the IP policy", "ip", ip, "policy", srv.IPPolicy)
+	}
+	return ip
+}
+
+// Bans returns the bans on node IDs and IP networks in effect.
+func (srv *BaseServer) Bans() []*BanInfo {
+	return srv.bans.list()
+}
+
+// disconnect requests the run loop to disconnect the peer unless the server is stopped.
+func (srv *BaseServer) disconnect(id discover.NodeID) {
+	select {
+	case srv.discpeer <- id:
+	case <-srv.quit:
+	}
+}
+
 // SubscribePeers subscribes the given channel to peer events.
 func (srv *BaseServer) SubscribeEvents(ch chan *PeerEvent) event.Subscription {
 	return srv.peerFeed.Subscribe(ch)
@@ -1210,6 +1512,11 @@ func (srv *BaseServer) Start() (err error) {
 	if srv.newTransport == nil {
 		srv.newTransport = newRLPX
 	}
+	if srv.TLS != nil {
+		if srv.tlsConfig, err = newTLSConfig(srv.TLS); err != nil {
+			return err
+		}
+	}
 	if srv.Dialer == nil {
 		srv.Dialer = TCPDialer{&net.Dialer{Timeout: defaultDialTimeout}}
 	}
@@ -1219,10 +1526,20 @@ func (srv *BaseServer) Start() (err error) {
 	srv.posthandshake = make(chan *conn)
 	srv.addstatic = make(chan *discover.Node)
 	srv.removestatic = make(chan *discover.Node)
+	srv.addtrusted = make(chan *discover.Node)
+	srv.removetrusted = make(chan *discover.Node)
 	srv.peerOp = make(chan peerOpFunc)
 	srv.peerOpDone = make(chan struct{})
 	srv.discpeer = make(chan discover.NodeID)
 
+	if srv.NAT != nil {
+		srv.natMonitor = nat.NewMonitor(srv.NAT)
+		go srv.natMonitor.Run(srv.quit, nil)
+		if srv.NoDiscovery {
+			go srv.natMonitor.CheckExternalIP()
+		}
+	}
+
 	var (
 		conn      *net.UDPConn
 		realaddr  *net.UDPAddr
@@ -1230,24 +1547,25 @@ func (srv *BaseServer) Start() (err error) {
 	)
 
 	if !srv.NoDiscovery {
-		addr, err := net.ResolveUDPAddr("udp", srv.ListenAddr)
+		addr, err := net.ResolveUDPAddr(srv.IPPolicy.Network("udp"), srv.ListenAddr)
 		if err != nil {
 			return err
 		}
-		conn, err = net.ListenUDP("udp", addr)
+		conn, err = net.ListenUDP(srv.IPPolicy.Network("udp"), addr)
 		if err != nil {
 			return err
 		}
 		realaddr = conn.LocalAddr().(*net.UDPAddr)
 		if srv.NAT != nil {
 			if !realaddr.IP.IsLoopback() {
-				go nat.Map(srv.NAT, srv.quit, "udp", realaddr.Port, realaddr.Port, "klaytn discovery")
+				go srv.natMonitor.Map(srv.quit, "udp", realaddr.Port, realaddr.Port, "klaytn discovery")
 			}
 			// TODO: react to external IP changes over time.
-			if ext, err := srv.NAT.ExternalIP(); err == nil {
+			if ext, _ := srv.natMonitor.CheckExternalIP(); ext != nil {
 				realaddr = &net.UDPAddr{IP: ext, Port: realaddr.Port}
 			}
 		}
+		realaddr = &net.UDPAddr{IP: srv.advertisedIP(realaddr.IP), Port: realaddr.Port}
 	}
 
 	// node table
@@ -1257,6 +1575,7 @@ func (srv *BaseServer) Start() (err error) {
 			AnnounceAddr: realaddr,
 			NodeDBPath:   srv.NodeDatabase,
 			NetRestrict:  srv.NetRestrict,
+			IPPolicy:     srv.IPPolicy,
 			Bootnodes:    srv.BootstrapNodes,
 			Unhandled:    unhandled,
 			Conn:         conn,
@@ -1276,12 +1595,23 @@ func (srv *BaseServer) Start() (err error) {
 			return err
 		}
 		srv.ntab = ntab
+		srv.registerTopics()
 	}
 
 	dialer := newDialState(srv.StaticNodes, srv.BootstrapNodes, srv.ntab, srv.maxDialedConns(), srv.NetRestrict, srv.PrivateKey, srv.getTypeStatics())
+	dialer.scorer = srv.scorer
+	dialer.bans = srv.bans
+	dialer.ipPolicy = srv.IPPolicy
+	if len(srv.DNSDiscovery) > 0 {
+		dns, err := newDNSNodeSource(srv.DNSDiscovery)
+		if err != nil {
+			return err
+		}
+		dialer.dns = dns
+	}
 
 	// handshake
-	srv.ourHandshake = &protoHandshake{Version: baseProtocolVersion, Name: srv.Name(), ID: discover.PubkeyID(&srv.PrivateKey.PublicKey), Multichannel: false}
+	srv.ourHandshake = &protoHandshake{Version: baseProtocolVersion, Name: srv.Name(), ID: discover.PubkeyID(&srv.PrivateKey.PublicKey), Multichannel: false, CompressionThreshold: srv.compressionThreshold()}
 	for _, p := range srv.Protocols {
 		srv.ourHandshake.Caps = append(srv.ourHandshake.Caps, p.cap())
 	}
@@ -1299,6 +1629,14 @@ func (srv *BaseServer) Start() (err error) {
 		}
 	}
 
+	if dialer.dns != nil {
+		srv.loopWG.Add(1)
+		go dialer.dns.loop(srv.quit, &srv.loopWG)
+	}
+	if srv.sentries != nil {
+		srv.loopWG.Add(1)
+		go srv.sentryLoop()
+	}
 	srv.loopWG.Add(1)
 	go srv.run(dialer)
 	srv.running = true
@@ -1308,7 +1646,7 @@ func (srv *BaseServer) Start() (err error) {
 
 func (srv *BaseServer) startListening() error {
 	// Launch the TCP listener.
-	listener, err := net.Listen("tcp", srv.ListenAddr)
+	listener, err := net.Listen(srv.IPPolicy.Network("tcp"), srv.ListenAddr)
 	if err != nil {
 		return err
 	}
@@ -1321,7 +1659,7 @@ func (srv *BaseServer) startListening() error {
 	if !laddr.IP.IsLoopback() && srv.NAT != nil {
 		srv.loopWG.Add(1)
 		go func() {
-			nat.Map(srv.NAT, srv.quit, "tcp", laddr.Port, laddr.Port, "klaytn p2p")
+			srv.natMonitor.Map(srv.quit, "tcp", laddr.Port, laddr.Port, "klaytn p2p")
 			srv.loopWG.Done()
 		}()
 	}
@@ -1346,8 +1684,7 @@ func (srv *BaseServer) run(dialstate dialer) {
 		queuedTasks  []task // tasks that can't run yet
 	)
 	// Put trusted nodes into a map to speed up checks.
-	// Trusted peers are loaded on startup and cannot be
-	// modified while the server is running.
+	// Trusted peers are loaded on startup or added via AddTrustedPeer RPC.
 	for _, n := range srv.TrustedNodes {
 		trusted[n.ID] = true
 	}
@@ -1405,6 +1742,16 @@ running:
 			if p, ok := peers[n.ID]; ok {
 				p.Disconnect(DiscRequested)
 			}
+		case n := <-srv.addtrusted:
+			// This channel is used by AddTrustedPeer to add a node
+			// to the trusted node set.
+			srv.logger.Debug("Adding trusted node", "node", n)
+			trusted[n.ID] = true
+		case n := <-srv.removetrusted:
+			// This channel is used by RemoveTrustedPeer to remove a node
+			// from the trusted node set.
+			srv.logger.Debug("Removing trusted node", "node", n)
+			delete(trusted, n.ID)
 		case op := <-srv.peerOp:
 			// This channel is used by Peers and PeerCount.
 			op(peers)
@@ -1445,6 +1792,8 @@ running:
 					if srv.EnableMsgEvents {
 						p.events = &srv.peerFeed
 					}
+					p.scorer = srv.scorer
+					p.egress = srv.egressLimiter
 					name := truncateName(c.name)
 					srv.logger.Debug("Adding p2p peer", "name", name, "addr", c.fd.RemoteAddr(), "peers", len(peers)+1)
 					go srv.runPeer(p)
@@ -1471,6 +1820,7 @@ running:
 			d := common.PrettyDuration(mclock.Now() - pd.created)
 			pd.logger.Debug("Removing p2p peer", "duration", d, "peers", len(peers)-1, "req", pd.requested, "err", pd.err)
 			delete(peers, pd.ID())
+			srv.scoreDroppedPeer(pd)
 
 			if pd.Inbound() {
 				inboundCount--
@@ -1530,11 +1880,154 @@ func (srv *BaseServer) encHandshakeChecks(peers map[discover.NodeID]*Peer, inbou
 		return DiscAlreadyConnected
 	case c.id == srv.Self().ID:
 		return DiscSelf
+	case !c.is(trustedConn) && srv.scorer != nil && srv.scorer.isBanned(c.id):
+		return DiscUselessPeer
+	case srv.bans != nil && srv.bans.isIDBanned(c.id):
+		return DiscUselessPeer
+	case srv.sentries != nil && !srv.sentries.contains(c.id):
+		return DiscUnexpectedIdentity
 	default:
 		return nil
 	}
 }
 
+// scoreDroppedPeer lowers the score of the dropped peer if it is dropped due to its fault.
+func (srv *BaseServer) scoreDroppedPeer(pd peerDrop) {
+	if srv.scorer == nil || pd.requested || pd.err == nil {
+		return
+	}
+	if nerr, ok := pd.err.(net.Error); ok && nerr.Timeout() {
+		srv.scorer.add(pd.ID(), PeerScoreTimeout)
+		return
+	}
+	switch discReasonForError(pd.err) {
+	case DiscProtocolError:
+		srv.scorer.add(pd.ID(), PeerScoreProtocolViolation)
+	case DiscReadTimeout:
+		srv.scorer.add(pd.ID(), PeerScoreTimeout)
+	}
+}
+
+// PeerScores returns the scores of the peers which have been scored.
+func (srv *BaseServer) PeerScores() []*PeerScoreInfo {
+	if srv.scorer == nil {
+		return nil
+	}
+	return srv.scorer.infos()
+}
+
+// registerTopics advertises the configured topics through the discovery.
+func (srv *BaseServer) registerTopics() {
+	td, ok := srv.ntab.(discover.TopicDiscovery)
+	if !ok {
+		if len(srv.DiscoveryTopics) > 0 {
+			srv.logger.Warn("Discovery does not support topics", "topics", srv.DiscoveryTopics)
+		}
+		return
+	}
+	for _, topic := range srv.DiscoveryTopics {
+		td.RegisterTopic(discover.Topic(topic))
+	}
+}
+
+// SetBootnodes replaces the boot nodes of the running discovery. The discovery
+// table is refreshed with the new boot nodes.
+func (srv *BaseServer) SetBootnodes(nodes []*discover.Node) error {
+	bs, ok := srv.ntab.(interface {
+		SetBootnodes(nodes []*discover.Node) error
+	})
+	if !ok {
+		return errors.New("discovery is not running or does not support changing the boot nodes")
+	}
+	return bs.SetBootnodes(nodes)
+}
+
+// LookupTopic returns at most max nodes advertised with the topic through the discovery.
+func (srv *BaseServer) LookupTopic(topic string, max int) []*discover.Node {
+	td, ok := srv.ntab.(discover.TopicDiscovery)
+	if !ok {
+		return nil
+	}
+	return td.LookupTopic(discover.Topic(topic), max)
+}
+
+// NATStatus returns the detected external endpoint and the health of
+// the port mappings. It returns nil if NAT is not configured.
+func (srv *BaseServer) NATStatus() *nat.Status {
+	if srv.natMonitor == nil {
+		return nil
+	}
+	return srv.natMonitor.Status()
+}
+
+// InboundLimits returns the inbound connection limits and the IPs with the most inbound connections.
+func (srv *BaseServer) InboundLimits() *InboundLimitInfo {
+	if srv.inboundLimiter == nil {
+		return nil
+	}
+	return srv.inboundLimiter.info(maxBusiestInboundIPs)
+}
+
+// SetInboundLimit overrides the per-IP inbound connection limit of the IPs in the CIDR.
+// The overridden IPs are not limited by the per-subnet limit. A limit of 0 means unlimited.
+func (srv *BaseServer) SetInboundLimit(cidr string, limit int) error {
+	if srv.inboundLimiter == nil {
+		return errors.New("inbound limiter is not initialized")
+	}
+	return srv.inboundLimiter.setOverride(cidr, limit)
+}
+
+// RemoveInboundLimit removes the per-IP inbound connection limit override of the CIDR.
+func (srv *BaseServer) RemoveInboundLimit(cidr string) error {
+	if srv.inboundLimiter == nil {
+		return errors.New("inbound limiter is not initialized")
+	}
+	return srv.inboundLimiter.removeOverride(cidr)
+}
+
+// SetInboundLimits changes the default inbound connection limits per IP and
+// per subnet. A limit of 0 means unlimited. The connected peers are not dropped
+// even if they exceed the new limits.
+func (srv *BaseServer) SetInboundLimits(maxPerIP, maxPerSubnet int) error {
+	if srv.inboundLimiter == nil {
+		return errors.New("inbound limiter is not initialized")
+	}
+	return srv.inboundLimiter.setLimits(maxPerIP, maxPerSubnet)
+}
+
+// EgressLimits returns the egress bandwidth limits in bytes per second.
+func (srv *BaseServer) EgressLimits() *EgressLimitInfo {
+	if srv.egressLimiter == nil {
+		return nil
+	}
+	return srv.egressLimiter.limits()
+}
+
+// SetEgressLimits changes the egress bandwidth limits per peer and of all peers
+// in bytes per second. A limit of 0 means unlimited. The new limits are applied
+// to the connected peers as well.
+func (srv *BaseServer) SetEgressLimits(peerBytesPerSec, globalBytesPerSec int) error {
+	if srv.egressLimiter == nil {
+		return errors.New("egress limiter is not initialized")
+	}
+	return srv.egressLimiter.setLimits(peerBytesPerSec, globalBytesPerSec)
+}
+
+// compressionThreshold returns the compression threshold advertised in the
+// protocol handshake. It returns 0 if the selective compression is disabled.
+func (srv *BaseServer) compressionThreshold() uint64 {
+	switch {
+	case srv.CompressionThreshold < 0:
+		return 0
+	case srv.CompressionThreshold == 0:
+		return defaultCompressionThreshold
+	case srv.CompressionThreshold > int(maxUint24):
+		return uint64(maxUint24)
+	default:
+		return uint64(srv.CompressionThreshold)
+	}
+}
+
 func (srv *BaseServer) maxInboundConns() int {
 	return srv.Config.MaxPhysicalConnections - srv.maxDialedConns()
 }
@@ -1546,7 +2039,7 @@ func (srv *BaseServer) maxDialedConns() int {
 	case common.PROXYNODE:
 		return 0
 	case common.ENDPOINTNODE:
-		if srv.NoDiscovery || srv.NoDial {
+		if (srv.NoDiscovery && len(srv.DNSDiscovery) == 0) || srv.NoDial {
 			return 0
 		}
 		r := srv.DialRatio
@@ -1633,7 +2126,29 @@ func (srv *BaseServer) listenLoop() {
 			}
 		}
 
+		// Reject connections from the banned IP networks.
+		if tcp, ok := fd.RemoteAddr().(*net.TCPAddr); ok && srv.bans != nil && srv.bans.isIPBanned(tcp.IP) {
+			srv.logger.Debug("Rejected conn (banned IP)", "addr", fd.RemoteAddr())
+			fd.Close()
+			slots <- struct{}{}
+			continue
+		}
+
+		var release func()
+		if tcp, ok := fd.RemoteAddr().(*net.TCPAddr); ok && srv.inboundLimiter != nil {
+			var err error
+			if release, err = srv.inboundLimiter.acquire(tcp.IP); err != nil {
+				srv.logger.Debug("Rejected conn", "addr", fd.RemoteAddr(), "err", err)
+				fd.Close()
+				slots <- struct{}{}
+				continue
+			}
+		}
+
 		fd = newMeteredConn(fd, true)
+		if release != nil {
+			fd = &limitedConn{Conn: fd, release: release}
+		}
 		srv.logger.Trace("Accepted connection", "addr", fd.RemoteAddr())
 		go func() {
 			srv.SetupConn(fd, inboundConn, nil)
@@ -1650,9 +2165,16 @@ func (srv *BaseServer) SetupConn(fd net.Conn, flags connFlag, dialDest *discover
 	if self == nil {
 		return errors.New("shutdown")
 	}
+	tlsConn, err := srv.wrapTLS(fd, flags)
+	if err != nil {
+		srv.logger.Debug("TLS handshake failed", "addr", fd.RemoteAddr(), "err", err)
+		fd.Close()
+		return err
+	}
+	fd = tlsConn
 
 	c := &conn{fd: fd, transport: srv.newTransport(fd), flags: flags, conntype: common.ConnTypeUndefined, cont: make(chan error), portOrder: ConnDefault}
-	err := srv.setupConn(c, flags, dialDest)
+	err = srv.setupConn(c, flags, dialDest)
 	if err != nil {
 		c.close(err)
 		srv.logger.Trace("Setting up connection failed", "id", c.id, "err", err)
@@ -1705,7 +2227,7 @@ func (srv *BaseServer) setupConn(c *conn, flags connFlag, dialDest *discover.Nod
 		clog.Trace("Wrong devp2p handshake identity", "err", phs.ID)
 		return DiscUnexpectedIdentity
 	}
-	c.caps, c.name, c.multiChannel = phs.Caps, phs.Name, phs.Multichannel
+	c.caps, c.name, c.multiChannel, c.listenPorts = phs.Caps, phs.Name, phs.Multichannel, phs.ListenPort
 
 	err = srv.checkpoint(c, srv.addpeer)
 	if err != nil {
@@ -1782,6 +2304,7 @@ type NodeInfo struct {
 	} `json:"ports"`
 	ListenAddr string                 `json:"listenAddr"`
 	Protocols  map[string]interface{} `json:"protocols"`
+	Version    *params.VersionInfo    `json:"version"` // Build information of the running binary
 }
 
 // NodeInfo gathers and returns a collection of metadata known about the host.
@@ -1796,6 +2319,7 @@ func (srv *BaseServer) NodeInfo() *NodeInfo {
 		IP:         node.IP.String(),
 		ListenAddr: srv.ListenAddr,
 		Protocols:  make(map[string]interface{}),
+		Version:    params.GetVersionInfo(),
 	}
 	info.Ports.Discovery = int(node.UDP)
 	info.Ports.Listener = int(node.TCP)
@@ -0,0 +1,181 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var errEgressShuttingDown = errors.New("shutting down")
+
+// EgressLimitInfo represents the egress bandwidth limits in bytes per second.
+type EgressLimitInfo struct {
+	PeerBytesPerSec   int `json:"peerBytesPerSec"`   // 0 means unlimited
+	GlobalBytesPerSec int `json:"globalBytesPerSec"` // 0 means unlimited
+}
+
+// tokenBucket is a token bucket filled with rate tokens per second up to one
+// second worth of tokens. Reservations larger than the remaining tokens are
+// allowed by putting the bucket into debt, so that messages bigger than the
+// bucket are delayed rather than rejected.
+type tokenBucket struct {
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+
+	now func() time.Time // for testing
+}
+
+func newTokenBucket() *tokenBucket {
+	return &tokenBucket{now: time.Now}
+}
+
+// reserve takes n tokens from the bucket filled with rate tokens per second
+// and returns how long the caller should wait before sending n bytes.
+// A rate of 0 means unlimited.
+func (b *tokenBucket) reserve(n int, rate int) time.Duration {
+	if rate <= 0 {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if b.last.IsZero() {
+		b.tokens = float64(rate)
+	} else {
+		b.tokens += now.Sub(b.last).Seconds() * float64(rate)
+	}
+	if b.tokens > float64(rate) {
+		b.tokens = float64(rate)
+	}
+	b.last = now
+
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / float64(rate) * float64(time.Second))
+}
+
+// egressLimiter limits the egress bandwidth of each peer and of all peers.
+// The limits in bytes per second can be changed at runtime.
+type egressLimiter struct {
+	mu         sync.RWMutex
+	peerRate   int
+	globalRate int
+	global     *tokenBucket
+}
+
+func newEgressLimiter(peerRate, globalRate int) *egressLimiter {
+	return &egressLimiter{
+		peerRate:   peerRate,
+		globalRate: globalRate,
+		global:     newTokenBucket(),
+	}
+}
+
+func (l *egressLimiter) setLimits(peerRate, globalRate int) error {
+	if peerRate < 0 || globalRate < 0 {
+		return fmt.Errorf("invalid egress limits: peer %d, global %d", peerRate, globalRate)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.peerRate, l.globalRate = peerRate, globalRate
+	return nil
+}
+
+func (l *egressLimiter) limits() *EgressLimitInfo {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return &EgressLimitInfo{PeerBytesPerSec: l.peerRate, GlobalBytesPerSec: l.globalRate}
+}
+
+// delay reserves n bytes from the peer bucket and the global bucket and
+// returns how long the caller should wait before sending them.
+func (l *egressLimiter) delay(peer *tokenBucket, n int) time.Duration {
+	l.mu.RLock()
+	peerRate, globalRate := l.peerRate, l.globalRate
+	l.mu.RUnlock()
+
+	d := peer.reserve(n, peerRate)
+	if gd := l.global.reserve(n, globalRate); gd > d {
+		d = gd
+	}
+	return d
+}
+
+// egressExemptions is the set of message codes of a peer which are sent
+// without throttling, e.g. the consensus messages. The zero value is empty.
+type egressExemptions struct {
+	mu    sync.RWMutex
+	codes map[uint64]bool
+}
+
+func (e *egressExemptions) add(code uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.codes == nil {
+		e.codes = make(map[uint64]bool)
+	}
+	e.codes[code] = true
+}
+
+func (e *egressExemptions) has(code uint64) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.codes[code]
+}
+
+// throttledRW delays the messages written to the underlying MsgReadWriter
+// to keep the egress bandwidth within the limits. The exempted messages are
+// neither delayed nor counted against the limits.
+type throttledRW struct {
+	MsgReadWriter
+	limiter *egressLimiter
+	bucket  *tokenBucket      // shared by all protocols of a peer
+	exempt  *egressExemptions // shared by all protocols of a peer
+	closed  <-chan struct{}
+}
+
+func newThrottledRW(rw MsgReadWriter, limiter *egressLimiter, bucket *tokenBucket, exempt *egressExemptions, closed <-chan struct{}) *throttledRW {
+	return &throttledRW{MsgReadWriter: rw, limiter: limiter, bucket: bucket, exempt: exempt, closed: closed}
+}
+
+func (rw *throttledRW) WriteMsg(msg Msg) error {
+	if rw.exempt.has(msg.Code) {
+		return rw.MsgReadWriter.WriteMsg(msg)
+	}
+	if d := rw.limiter.delay(rw.bucket, int(msg.Size)); d > 0 {
+		egressThrottleTimer.Update(d)
+		timer := time.NewTimer(d)
+		select {
+		case <-timer.C:
+		case <-rw.closed:
+			timer.Stop()
+			return errEgressShuttingDown
+		}
+	}
+	return rw.MsgReadWriter.WriteMsg(msg)
+}
@@ -0,0 +1,131 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	b := newTokenBucket()
+	b.now = func() time.Time { return now }
+
+	// Unlimited rate never delays.
+	if d := b.reserve(1<<20, 0); d != 0 {
+		t.Errorf("unexpected delay with unlimited rate: %v", d)
+	}
+
+	// The bucket starts full with one second worth of tokens.
+	if d := b.reserve(1000, 1000); d != 0 {
+		t.Errorf("unexpected delay: %v", d)
+	}
+	// The bucket goes into debt for the next reservation.
+	if d := b.reserve(500, 1000); d != 500*time.Millisecond {
+		t.Errorf("unexpected delay: have %v, want %v", d, 500*time.Millisecond)
+	}
+	// The debt is repaid over time.
+	now = now.Add(time.Second)
+	if d := b.reserve(500, 1000); d != 0 {
+		t.Errorf("unexpected delay after refill: %v", d)
+	}
+	// The bucket does not exceed one second worth of tokens.
+	now = now.Add(time.Hour)
+	if d := b.reserve(2000, 1000); d != time.Second {
+		t.Errorf("unexpected delay: have %v, want %v", d, time.Second)
+	}
+}
+
+func TestEgressLimiter(t *testing.T) {
+	l := newEgressLimiter(0, 1000)
+	peer1, peer2 := newTokenBucket(), newTokenBucket()
+
+	// The global limit is shared by all peers.
+	if d := l.delay(peer1, 1000); d != 0 {
+		t.Errorf("unexpected delay: %v", d)
+	}
+	if d := l.delay(peer2, 1000); d < 900*time.Millisecond {
+		t.Errorf("global limit is not applied, delay %v", d)
+	}
+
+	if err := l.setLimits(-1, 0); err == nil {
+		t.Error("negative limits should be rejected")
+	}
+	if err := l.setLimits(1000, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info := l.limits(); info.PeerBytesPerSec != 1000 || info.GlobalBytesPerSec != 0 {
+		t.Errorf("unexpected limits: %+v", info)
+	}
+	// The peer limit applies to each peer separately.
+	if d := l.delay(peer1, 1000); d != 0 {
+		t.Errorf("unexpected delay: %v", d)
+	}
+	if d := l.delay(peer2, 1000); d != 0 {
+		t.Errorf("unexpected delay: %v", d)
+	}
+	if d := l.delay(peer1, 1000); d < 900*time.Millisecond {
+		t.Errorf("peer limit is not applied, delay %v", d)
+	}
+}
+
+func TestThrottledRW_Closed(t *testing.T) {
+	closed := make(chan struct{})
+	close(closed)
+	rw := newThrottledRW(nil, newEgressLimiter(1, 0), newTokenBucket(), &egressExemptions{}, closed)
+
+	// The first write exceeding the bucket waits until the peer is closed.
+	if err := rw.WriteMsg(Msg{Size: 1000}); err != errEgressShuttingDown {
+		t.Errorf("unexpected error: have %v, want %v", err, errEgressShuttingDown)
+	}
+}
+
+type countingRW struct {
+	MsgReadWriter
+	writes int
+}
+
+func (rw *countingRW) WriteMsg(msg Msg) error {
+	rw.writes++
+	return nil
+}
+
+func TestThrottledRW_Exempt(t *testing.T) {
+	closed := make(chan struct{})
+	close(closed)
+	var (
+		exempt egressExemptions
+		w      = &countingRW{}
+		rw     = newThrottledRW(w, newEgressLimiter(1, 1), newTokenBucket(), &exempt, closed)
+	)
+	exempt.add(0x11)
+
+	// The exempted messages are sent without waiting and use no tokens.
+	for i := 0; i < 3; i++ {
+		if err := rw.WriteMsg(Msg{Code: 0x11, Size: 1000}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if w.writes != 3 {
+		t.Errorf("unexpected writes: have %d, want 3", w.writes)
+	}
+	// The other messages are still throttled.
+	if err := rw.WriteMsg(Msg{Code: 0x10, Size: 1000}); err != errEgressShuttingDown {
+		t.Errorf("unexpected error: have %v, want %v", err, errEgressShuttingDown)
+	}
+}
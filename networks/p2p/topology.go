@@ -0,0 +1,91 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import "sort"
+
+// Topology describes a node and its connected peers. The topologies exported
+// from the nodes of a fleet can be merged into a graph by the node IDs.
+type Topology struct {
+	Self  TopologyNode   `json:"self"`
+	Peers []TopologyPeer `json:"peers"`
+}
+
+// TopologyNode is a vertex of the topology graph.
+type TopologyNode struct {
+	ID          string   `json:"id"`
+	NodeType    string   `json:"nodeType"`    // cn, pn, en, bn or unknown
+	ListenAddrs []string `json:"listenAddrs"` // advertised listen addresses
+}
+
+// TopologyPeer is an edge from this node to a connected peer.
+type TopologyPeer struct {
+	TopologyNode
+	Name          string `json:"name"`
+	Direction     string `json:"direction"` // inbound or outbound
+	RemoteAddress string `json:"remoteAddress"`
+	Trusted       bool   `json:"trusted"`
+	Static        bool   `json:"static"`
+}
+
+// Topology returns this node and its connected peers with their advertised
+// listen addresses, node types and connection directions.
+func (srv *BaseServer) Topology() *Topology {
+	return srv.topology(srv.GetListenAddress())
+}
+
+// Topology returns this node and its connected peers with their advertised
+// listen addresses, node types and connection directions.
+func (srv *MultiChannelServer) Topology() *Topology {
+	return srv.topology(srv.GetListenAddress())
+}
+
+func (srv *BaseServer) topology(listenAddrs []string) *Topology {
+	topology := &Topology{
+		Self: TopologyNode{
+			ID:          srv.Self().ID.String(),
+			NodeType:    nodeTypeName(srv.ConnectionType),
+			ListenAddrs: listenAddrs,
+		},
+		Peers: make([]TopologyPeer, 0, srv.PeerCount()),
+	}
+	for _, p := range srv.Peers() {
+		if p == nil {
+			continue
+		}
+		direction := "outbound"
+		if p.Inbound() {
+			direction = "inbound"
+		}
+		topology.Peers = append(topology.Peers, TopologyPeer{
+			TopologyNode: TopologyNode{
+				ID:          p.ID().String(),
+				NodeType:    nodeTypeName(p.ConnType()),
+				ListenAddrs: p.ListenAddrs(),
+			},
+			Name:          p.Name(),
+			Direction:     direction,
+			RemoteAddress: p.RemoteAddr().String(),
+			Trusted:       p.rws[ConnDefault].is(trustedConn),
+			Static:        p.rws[ConnDefault].is(staticDialedConn),
+		})
+	}
+	sort.Slice(topology.Peers, func(i, j int) bool {
+		return topology.Peers[i].ID < topology.Peers[j].ID
+	})
+	return topology
+}
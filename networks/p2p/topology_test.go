@@ -0,0 +1,57 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/klaytn/klaytn/common"
+)
+
+// tcpAddrConn is a net.Conn reporting the given TCP remote address.
+type tcpAddrConn struct {
+	net.Conn
+	remote *net.TCPAddr
+}
+
+func (c *tcpAddrConn) RemoteAddr() net.Addr { return c.remote }
+
+func TestPeer_ListenAddrs(t *testing.T) {
+	fd, _ := net.Pipe()
+	defer fd.Close()
+
+	remote := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 51234}
+	c := &conn{
+		fd:          &tcpAddrConn{Conn: fd, remote: remote},
+		conntype:    common.PROXYNODE,
+		listenPorts: []uint64{32323, 32324},
+	}
+	p, err := newPeer([]*conn{c}, nil, defaultRWTimerConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"10.0.0.1:32323", "10.0.0.1:32324"}
+	if addrs := p.ListenAddrs(); !reflect.DeepEqual(addrs, want) {
+		t.Errorf("listen addrs mismatch: have %v, want %v", addrs, want)
+	}
+	if name := nodeTypeName(p.ConnType()); name != "pn" {
+		t.Errorf("node type mismatch: have %s, want pn", name)
+	}
+}
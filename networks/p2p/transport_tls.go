@@ -0,0 +1,163 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	"time"
+)
+
+// Transport security profiles of TLSConfig.
+const (
+	// TLSProfileCA requires mutually-authenticated TLS whose certificates are
+	// issued by the configured certificate authorities.
+	TLSProfileCA = "tls"
+	// TLSProfilePinned requires mutually-authenticated TLS whose certificates are
+	// in the allowlist of SHA-256 fingerprints, e.g. self-signed certificates.
+	TLSProfilePinned = "tls-pinned"
+)
+
+const tlsHandshakeTimeout = 10 * time.Second
+
+var errTLSCertNotAllowed = errors.New("TLS certificate is not in the allowlist")
+
+// TLSConfig configures the TLS layer wrapping the p2p connections under RLPx.
+// All peers of the node must use the same profile.
+type TLSConfig struct {
+	Profile  string // TLSProfileCA or TLSProfilePinned
+	CertFile string // PEM encoded certificate of the node
+	KeyFile  string // PEM encoded private key of the certificate
+
+	// CAFile contains the PEM encoded certificates of the authorities issuing
+	// the peer certificates. It is required by TLSProfileCA.
+	CAFile string `toml:",omitempty"`
+
+	// AllowedCerts are the hex encoded SHA-256 fingerprints of the DER encoded
+	// peer certificates. It is required by TLSProfilePinned.
+	AllowedCerts []string `toml:",omitempty"`
+}
+
+// newTLSConfig creates the tls.Config used for both inbound and outbound connections.
+// Since peers are dialed by IP address, the peer certificates are verified
+// without the host name.
+func newTLSConfig(cfg *TLSConfig) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %v", err)
+	}
+	var verify func(certs []*x509.Certificate) error
+
+	switch cfg.Profile {
+	case TLSProfileCA:
+		if cfg.CAFile == "" {
+			return nil, errors.New("TLS profile tls requires the CA file")
+		}
+		pem, err := ioutil.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate in the CA file %s", cfg.CAFile)
+		}
+		verify = func(certs []*x509.Certificate) error {
+			intermediates := x509.NewCertPool()
+			for _, c := range certs[1:] {
+				intermediates.AddCert(c)
+			}
+			_, err := certs[0].Verify(x509.VerifyOptions{
+				Roots:         roots,
+				Intermediates: intermediates,
+				KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+			})
+			return err
+		}
+	case TLSProfilePinned:
+		if len(cfg.AllowedCerts) == 0 {
+			return nil, errors.New("TLS profile tls-pinned requires the allowed certificates")
+		}
+		allowed := make(map[[sha256.Size]byte]bool)
+		for _, s := range cfg.AllowedCerts {
+			b, err := hex.DecodeString(strings.TrimPrefix(strings.ReplaceAll(s, ":", ""), "0x"))
+			if err != nil || len(b) != sha256.Size {
+				return nil, fmt.Errorf("invalid certificate fingerprint %q", s)
+			}
+			var fp [sha256.Size]byte
+			copy(fp[:], b)
+			allowed[fp] = true
+		}
+		verify = func(certs []*x509.Certificate) error {
+			if !allowed[sha256.Sum256(certs[0].Raw)] {
+				return errTLSCertNotAllowed
+			}
+			return nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown TLS profile %q", cfg.Profile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		// The standard verification is replaced by VerifyPeerCertificate,
+		// which doesn't depend on the host name.
+		InsecureSkipVerify: true,
+		ClientAuth:         tls.RequireAnyClientCert,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return errors.New("no TLS certificate from peer")
+			}
+			certs := make([]*x509.Certificate, len(rawCerts))
+			for i, raw := range rawCerts {
+				c, err := x509.ParseCertificate(raw)
+				if err != nil {
+					return err
+				}
+				certs[i] = c
+			}
+			return verify(certs)
+		},
+	}, nil
+}
+
+// wrapTLS runs the TLS handshake on the connection if TLS is configured.
+func (srv *BaseServer) wrapTLS(fd net.Conn, flags connFlag) (net.Conn, error) {
+	if srv.tlsConfig == nil {
+		return fd, nil
+	}
+	var tc *tls.Conn
+	if flags&inboundConn != 0 {
+		tc = tls.Server(fd, srv.tlsConfig)
+	} else {
+		tc = tls.Client(fd, srv.tlsConfig)
+	}
+	fd.SetDeadline(time.Now().Add(tlsHandshakeTimeout))
+	if err := tc.Handshake(); err != nil {
+		tlsHandshakeFailCounter.Inc(1)
+		return nil, err
+	}
+	fd.SetDeadline(time.Time{})
+	return tc, nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestCert writes a self-signed certificate and its key, and returns
+// the paths and the fingerprint of the certificate.
+func writeTestCert(t *testing.T, dir, name string) (string, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	fp := sha256.Sum256(der)
+	return certFile, keyFile, hex.EncodeToString(fp[:])
+}
+
+func tlsHandshake(t *testing.T, server, client *BaseServer) (error, error) {
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := server.wrapTLS(c1, inboundConn)
+		if err != nil {
+			c1.Close()
+		}
+		errc <- err
+	}()
+	_, err := client.wrapTLS(c2, dynDialedConn)
+	if err != nil {
+		c2.Close()
+	}
+	return <-errc, err
+}
+
+func TestWrapTLS_Pinned(t *testing.T) {
+	dir, err := ioutil.TempDir("", "p2p-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cert1, key1, fp1 := writeTestCert(t, dir, "node1")
+	cert2, key2, fp2 := writeTestCert(t, dir, "node2")
+	cert3, key3, _ := writeTestCert(t, dir, "node3")
+
+	newServer := func(cert, key string) *BaseServer {
+		config, err := newTLSConfig(&TLSConfig{Profile: TLSProfilePinned, CertFile: cert, KeyFile: key, AllowedCerts: []string{fp1, fp2}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &BaseServer{tlsConfig: config}
+	}
+	node1, node2, node3 := newServer(cert1, key1), newServer(cert2, key2), newServer(cert3, key3)
+
+	if serr, cerr := tlsHandshake(t, node1, node2); serr != nil || cerr != nil {
+		t.Errorf("handshake between allowed nodes failed: %v, %v", serr, cerr)
+	}
+	if serr, _ := tlsHandshake(t, node1, node3); serr == nil {
+		t.Error("handshake with a node not in the allowlist should fail")
+	}
+}
+
+func TestNewTLSConfig_Invalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "p2p-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cert, key, _ := writeTestCert(t, dir, "node")
+	for _, cfg := range []*TLSConfig{
+		{Profile: "noise", CertFile: cert, KeyFile: key},
+		{Profile: TLSProfileCA, CertFile: cert, KeyFile: key},
+		{Profile: TLSProfilePinned, CertFile: cert, KeyFile: key},
+		{Profile: TLSProfilePinned, CertFile: cert, KeyFile: key, AllowedCerts: []string{"00"}},
+		{Profile: TLSProfilePinned, CertFile: filepath.Join(dir, "missing"), KeyFile: key},
+	} {
+		if _, err := newTLSConfig(cfg); err == nil {
+			t.Errorf("newTLSConfig(%+v) should fail", cfg)
+		}
+	}
+}
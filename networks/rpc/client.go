@@ -267,6 +267,16 @@ func (c *Client) SupportedModules() (map[string]string, error) {
 	return result, err
 }
 
+// SupportedMethods returns the parameter types of the RPC methods of the server,
+// keyed by the method names like klay_getBlockByNumber.
+func (c *Client) SupportedMethods() (map[string][]string, error) {
+	var result map[string][]string
+	ctx, cancel := context.WithTimeout(context.Background(), subscribeTimeout)
+	defer cancel()
+	err := c.CallContext(ctx, &result, "rpc_methods")
+	return result, err
+}
+
 // Close closes the client, aborting any in-flight requests.
 func (c *Client) Close() {
 	if c.isHTTP {
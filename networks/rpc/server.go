@@ -28,6 +28,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"gopkg.in/fatih/set.v0"
 )
@@ -46,6 +47,9 @@ const (
 
 	// pendingRequestLimit is a limit for concurrent RPC method calls
 	pendingRequestLimit = 200000
+
+	// drainPollInterval is the interval of checking the requests being executed while draining
+	drainPollInterval = 10 * time.Millisecond
 )
 
 var (
@@ -106,6 +110,22 @@ func (s *RPCService) Modules() map[string]string {
 	return modules
 }
 
+// Methods returns the parameter types of the RPC methods, keyed by the method
+// names like klay_getBlockByNumber. The subscriptions are not included.
+func (s *RPCService) Methods() map[string][]string {
+	methods := make(map[string][]string)
+	for name, svc := range s.server.services {
+		for method, cb := range svc.callbacks {
+			params := make([]string, len(cb.argTypes))
+			for i, typ := range cb.argTypes {
+				params[i] = typ.String()
+			}
+			methods[name+serviceMethodSeparator+method] = params
+		}
+	}
+	return methods
+}
+
 func (s *Server) GetServices() serviceRegistry {
 	return s.services
 }
@@ -245,6 +265,8 @@ func (s *Server) serveRequest(ctx context.Context, codec ServerCodec, singleShot
 			} else {
 				codec.Write(codec.CreateErrorResponse(&reqs[0].id, err))
 			}
+			// Let the requests being executed finish while the server is draining.
+			pend.Wait()
 			return nil
 		}
 
@@ -255,6 +277,8 @@ func (s *Server) serveRequest(ctx context.Context, codec ServerCodec, singleShot
 
 		// If a single shot request is executing, run and return immediately
 		if singleShot {
+			atomic.AddInt64(&s.inflight, 1)
+			defer atomic.AddInt64(&s.inflight, -1)
 			if batch {
 				s.execBatch(ctx, codec, reqs, &subscriptionCount)
 			} else {
@@ -265,10 +289,12 @@ func (s *Server) serveRequest(ctx context.Context, codec ServerCodec, singleShot
 		// For multi-shot connections, start a goroutine to serve and loop back
 		pend.Add(1)
 		atomic.AddInt64(&pendingRequestCount, 1)
+		atomic.AddInt64(&s.inflight, 1)
 		rpcPendingRequestsCount.Inc(int64(len(reqs)))
 		go func(reqs []*serverRequest, batch bool) {
 			defer func() {
 				atomic.AddInt64(&pendingRequestCount, -1)
+				atomic.AddInt64(&s.inflight, -1)
 				if err := recover(); err != nil {
 					const size = 64 << 10
 					buf := make([]byte, size)
@@ -308,15 +334,35 @@ func (s *Server) ServeSingleRequest(ctx context.Context, codec ServerCodec, opti
 func (s *Server) Stop() {
 	if atomic.CompareAndSwapInt32(&s.run, 1, 0) {
 		logger.Debug("RPC Server shutdown initiatied")
-		s.codecsMu.Lock()
-		defer s.codecsMu.Unlock()
-		s.codecs.Each(func(c interface{}) bool {
-			c.(ServerCodec).Close()
-			return true
-		})
+		s.closeCodecs()
 	}
 }
 
+// Drain stops reading new requests, and waits for the requests being executed
+// to finish up to the timeout before closing all codecs. It returns the number
+// of the requests which have not finished in time.
+func (s *Server) Drain(timeout time.Duration) int64 {
+	if !atomic.CompareAndSwapInt32(&s.run, 1, 0) {
+		return 0
+	}
+	logger.Debug("RPC Server draining initiated", "inflight", atomic.LoadInt64(&s.inflight), "timeout", timeout)
+	deadline := time.Now().Add(timeout)
+	for atomic.LoadInt64(&s.inflight) > 0 && time.Now().Before(deadline) {
+		time.Sleep(drainPollInterval)
+	}
+	s.closeCodecs()
+	return atomic.LoadInt64(&s.inflight)
+}
+
+func (s *Server) closeCodecs() {
+	s.codecsMu.Lock()
+	defer s.codecsMu.Unlock()
+	s.codecs.Each(func(c interface{}) bool {
+		c.(ServerCodec).Close()
+		return true
+	})
+}
+
 // createSubscription will call the subscription callback and returns the subscription id or error.
 func (s *Server) createSubscription(ctx context.Context, c ServerCodec, req *serverRequest) (ID, error) {
 	// subscription have as first argument the context following optional arguments
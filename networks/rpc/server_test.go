@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"net"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -101,6 +102,27 @@ func TestServerRegisterName(t *testing.T) {
 	}
 }
 
+func TestRPCServiceMethods(t *testing.T) {
+	server := NewServer()
+	if err := server.RegisterName("calc", new(Service)); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	methods := (&RPCService{server}).Methods()
+	if params, ok := methods["calc_echo"]; !ok || !reflect.DeepEqual(params, []string{"string", "int", "*rpc.Args"}) {
+		t.Errorf("Expected the params of calc_echo, got %v", params)
+	}
+	if params, ok := methods["calc_echoWithCtx"]; !ok || !reflect.DeepEqual(params, []string{"string", "int", "*rpc.Args"}) {
+		t.Errorf("Expected the params of calc_echoWithCtx without the context, got %v", params)
+	}
+	if _, ok := methods["calc_subscription"]; ok {
+		t.Errorf("Expected no subscription")
+	}
+	if _, ok := methods["rpc_methods"]; !ok {
+		t.Errorf("Expected rpc_methods itself")
+	}
+}
+
 func testServerMethodExecution(t *testing.T, method string) {
 	server := NewServer()
 	service := new(Service)
@@ -160,3 +182,48 @@ func TestServerMethodExecution(t *testing.T) {
 func TestServerMethodWithCtx(t *testing.T) {
 	testServerMethodExecution(t, "echoWithCtx")
 }
+
+func TestServerDrain(t *testing.T) {
+	server := NewServer()
+	if err := server.RegisterName("test", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+	client := DialInProc(server)
+	defer client.Close()
+
+	errc := make(chan error, 1)
+	go func() { errc <- client.Call(nil, "test_sleep", 100*time.Millisecond) }()
+	for atomic.LoadInt64(&server.inflight) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The request being executed finishes while draining.
+	if left := server.Drain(5 * time.Second); left != 0 {
+		t.Fatalf("%d requests are not finished", left)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("the request being executed failed: %v", err)
+	}
+
+	// No request is accepted after draining.
+	if err := client.Call(nil, "test_sleep", time.Millisecond); err == nil {
+		t.Fatal("a request is accepted after draining")
+	}
+}
+
+func TestServerDrain_Timeout(t *testing.T) {
+	server := NewServer()
+	if err := server.RegisterName("test", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+	client := DialInProc(server)
+	defer client.Close()
+
+	go client.Call(nil, "test_sleep", time.Second)
+	for atomic.LoadInt64(&server.inflight) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if left := server.Drain(10 * time.Millisecond); left != 1 {
+		t.Fatalf("unfinished requests mismatch: have %d, want 1", left)
+	}
+}
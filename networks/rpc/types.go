@@ -82,6 +82,7 @@ type Server struct {
 	run      int32
 	codecsMu sync.Mutex
 	codecs   *set.Set
+	inflight int64 // number of the requests being executed, waited for by Drain
 
 	wsConnCount int32
 }
@@ -23,6 +23,7 @@ package node
 import (
 	"context"
 	"fmt"
+	"net"
 	"strings"
 	"time"
 
@@ -30,10 +31,15 @@ import (
 	"github.com/klaytn/klaytn/crypto"
 	"github.com/klaytn/klaytn/networks/p2p"
 	"github.com/klaytn/klaytn/networks/p2p/discover"
+	"github.com/klaytn/klaytn/networks/p2p/nat"
 	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/params"
 	"github.com/rcrowley/go-metrics"
 )
 
+// defaultTopicLookupLimit is the default maximum number of nodes returned by LookupTopic.
+const defaultTopicLookupLimit = 16
+
 // PrivateAdminAPI is the collection of administrative API methods exposed only
 // over a secure RPC channel.
 type PrivateAdminAPI struct {
@@ -66,11 +72,14 @@ func (api *PrivateAdminAPI) AddPeer(url string) (bool, error) {
 		return false, ErrNodeStopped
 	}
 	// TODO-Klaytn Refactoring this to check whether the url is valid or not by dialing and return it.
-	if _, err := addPeerInternal(server, url, false); err != nil {
+	node, err := addPeerInternal(server, url, false)
+	if err != nil {
 		return false, err
-	} else {
-		return true, nil
 	}
+	if err := api.node.runtimePeers.addStatic(node); err != nil {
+		return false, fmt.Errorf("failed to persist the static peer: %v", err)
+	}
+	return true, nil
 }
 
 // RemovePeer disconnects from a a remote node if the connection exists
@@ -86,6 +95,252 @@ func (api *PrivateAdminAPI) RemovePeer(url string) (bool, error) {
 		return false, fmt.Errorf("invalid kni: %v", err)
 	}
 	server.RemovePeer(node)
+	if err := api.node.runtimePeers.removeStatic(node); err != nil {
+		return false, fmt.Errorf("failed to persist the static peer removal: %v", err)
+	}
+	return true, nil
+}
+
+// AddTrustedPeer allows a remote node to always connect, even if slots are full.
+// The trusted peer is kept across restarts.
+func (api *PrivateAdminAPI) AddTrustedPeer(url string) (bool, error) {
+	server := api.node.Server()
+	if server == nil {
+		return false, ErrNodeStopped
+	}
+	node, err := discover.ParseNode(url)
+	if err != nil {
+		return false, fmt.Errorf("invalid kni: %v", err)
+	}
+	server.UnbanPeer(node.ID)
+	server.AddTrustedPeer(node)
+	if err := api.node.runtimePeers.addTrusted(node); err != nil {
+		return false, fmt.Errorf("failed to persist the trusted peer: %v", err)
+	}
+	return true, nil
+}
+
+// RemoveTrustedPeer removes a remote node from the trusted peer set, but it
+// does not disconnect it automatically.
+func (api *PrivateAdminAPI) RemoveTrustedPeer(url string) (bool, error) {
+	server := api.node.Server()
+	if server == nil {
+		return false, ErrNodeStopped
+	}
+	node, err := discover.ParseNode(url)
+	if err != nil {
+		return false, fmt.Errorf("invalid kni: %v", err)
+	}
+	server.RemoveTrustedPeer(node)
+	if err := api.node.runtimePeers.removeTrusted(node); err != nil {
+		return false, fmt.Errorf("failed to persist the trusted peer removal: %v", err)
+	}
+	return true, nil
+}
+
+// BanPeer disconnects from the peers matching the target and rejects any
+// connection from/to them until UnbanPeer is called or the optional duration
+// (e.g. "24h") elapses. The target is a kni URL, a hex node ID, an IP address
+// or an IP network in CIDR notation. A banned node is removed from the static
+// and trusted peers added at runtime. The ban is kept across restarts.
+func (api *PrivateAdminAPI) BanPeer(target string, duration *string) (bool, error) {
+	server := api.node.Server()
+	if server == nil {
+		return false, ErrNodeStopped
+	}
+	var expiry time.Time
+	if duration != nil && *duration != "" {
+		d, err := time.ParseDuration(*duration)
+		if err != nil {
+			return false, fmt.Errorf("invalid duration: %v", err)
+		}
+		if d <= 0 {
+			return false, fmt.Errorf("duration must be positive")
+		}
+		expiry = time.Now().Add(d)
+	}
+	id, network, err := parseBanTarget(target)
+	if err != nil {
+		return false, err
+	}
+	if network != nil {
+		if err := server.BanIP(network.String(), expiry); err != nil {
+			return false, err
+		}
+		if err := api.node.runtimePeers.banIP(network.String(), expiry); err != nil {
+			return false, fmt.Errorf("failed to persist the banned network: %v", err)
+		}
+		return true, nil
+	}
+	node := &discover.Node{ID: id}
+	server.RemoveTrustedPeer(node)
+	server.RemovePeer(node)
+	server.BanPeer(id, expiry)
+	if err := api.node.runtimePeers.banID(id, expiry); err != nil {
+		return false, fmt.Errorf("failed to persist the banned peer: %v", err)
+	}
+	return true, nil
+}
+
+// UnbanPeer lifts the ban on the target set by BanPeer. For a node, the ban by
+// its low score is lifted as well.
+func (api *PrivateAdminAPI) UnbanPeer(target string) (bool, error) {
+	server := api.node.Server()
+	if server == nil {
+		return false, ErrNodeStopped
+	}
+	id, network, err := parseBanTarget(target)
+	if err != nil {
+		return false, err
+	}
+	if network != nil {
+		if err := server.UnbanIP(network.String()); err != nil {
+			return false, err
+		}
+		if err := api.node.runtimePeers.unbanIP(network.String()); err != nil {
+			return false, fmt.Errorf("failed to persist the network unban: %v", err)
+		}
+		return true, nil
+	}
+	server.UnbanPeer(id)
+	if err := api.node.runtimePeers.unbanID(id); err != nil {
+		return false, fmt.Errorf("failed to persist the peer unban: %v", err)
+	}
+	return true, nil
+}
+
+// ListBans returns the bans on node IDs and IP networks in effect.
+func (api *PrivateAdminAPI) ListBans() ([]*p2p.BanInfo, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	return server.Bans(), nil
+}
+
+// Sentries returns the connection states of the sentry nodes if the node is
+// running in sentry mode.
+func (api *PrivateAdminAPI) Sentries() ([]*p2p.SentryInfo, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	return server.Sentries(), nil
+}
+
+// parseBanTarget parses the target of BanPeer and UnbanPeer. Either the node ID
+// or the IP network is returned.
+func parseBanTarget(target string) (discover.NodeID, *net.IPNet, error) {
+	if strings.HasPrefix(target, "kni://") {
+		node, err := discover.ParseNode(target)
+		if err != nil {
+			return discover.NodeID{}, nil, fmt.Errorf("invalid kni: %v", err)
+		}
+		return node.ID, nil, nil
+	}
+	if id, err := discover.HexID(target); err == nil {
+		return id, nil, nil
+	}
+	if ip := net.ParseIP(target); ip != nil {
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		return discover.NodeID{}, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	if _, network, err := net.ParseCIDR(target); err == nil {
+		return discover.NodeID{}, network, nil
+	}
+	return discover.NodeID{}, nil, fmt.Errorf("invalid target %q: must be a kni URL, a node ID, an IP address or a CIDR", target)
+}
+
+// RuntimePeers returns the static, trusted and banned peers managed via admin
+// APIs. The peers configured by static-nodes.json and trusted-nodes.json are
+// not included.
+func (api *PrivateAdminAPI) RuntimePeers() (*RuntimePeers, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	peers := api.node.runtimePeers.list()
+	return &peers, nil
+}
+
+// LookupTopic returns the nodes advertised with the topic through the discovery.
+// At most max nodes are returned, and max is capped by the discovery at the
+// number of nodes a registrar keeps for a topic. The returned nodes are not
+// dialed; add them with AddPeer to connect.
+func (api *PrivateAdminAPI) LookupTopic(topic string, max *int) ([]string, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	limit := defaultTopicLookupLimit
+	if max != nil && *max > 0 {
+		limit = *max
+	}
+	var urls []string
+	for _, n := range server.LookupTopic(topic, limit) {
+		urls = append(urls, n.String())
+	}
+	return urls, nil
+}
+
+// InboundLimits retrieves the inbound connection limits per IP and subnet,
+// the overridden limits and the IPs with the most inbound connections.
+func (api *PrivateAdminAPI) InboundLimits() (*p2p.InboundLimitInfo, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	return server.InboundLimits(), nil
+}
+
+// SetInboundLimit overrides the per-IP inbound connection limit of the given IP or CIDR.
+// The overridden IPs are exempted from the per-subnet limit. A limit of 0 means unlimited.
+func (api *PrivateAdminAPI) SetInboundLimit(cidr string, limit int) (bool, error) {
+	server := api.node.Server()
+	if server == nil {
+		return false, ErrNodeStopped
+	}
+	if err := server.SetInboundLimit(cidr, limit); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RemoveInboundLimit removes the per-IP inbound connection limit override of the given IP or CIDR.
+func (api *PrivateAdminAPI) RemoveInboundLimit(cidr string) (bool, error) {
+	server := api.node.Server()
+	if server == nil {
+		return false, ErrNodeStopped
+	}
+	if err := server.RemoveInboundLimit(cidr); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// EgressLimits retrieves the egress bandwidth limits per peer and of all peers in bytes per second.
+func (api *PrivateAdminAPI) EgressLimits() (*p2p.EgressLimitInfo, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	return server.EgressLimits(), nil
+}
+
+// SetEgressLimits changes the egress bandwidth limits per peer and of all peers
+// in bytes per second. A limit of 0 means unlimited. The consensus messages are
+// not limited.
+func (api *PrivateAdminAPI) SetEgressLimits(peerBytesPerSec int, globalBytesPerSec int) (bool, error) {
+	server := api.node.Server()
+	if server == nil {
+		return false, ErrNodeStopped
+	}
+	if err := server.SetEgressLimits(peerBytesPerSec, globalBytesPerSec); err != nil {
+		return false, err
+	}
 	return true, nil
 }
 
@@ -283,6 +538,29 @@ func (api *PrivateAdminAPI) SetMaxSubscriptionPerWSConn(num int32) {
 	rpc.MaxSubscriptionPerWSConn = num
 }
 
+// ReloadConfig reloads the configuration file, and applies the reloadable
+// settings such as log levels, RPC, txpool and peer limits. The settings given
+// by the command line flags keep overriding the file. It returns the changed
+// keys which are applied, which require restart and which are overridden by the flags.
+func (api *PrivateAdminAPI) ReloadConfig() (*ConfigReloadResult, error) {
+	return api.node.ReloadConfig()
+}
+
+// CollectDiagnostics gathers the goroutine dump, the heap and CPU profiles, the
+// recent logs, the configuration, the peers, the sync status and the database
+// stats into an archive in the data directory, and returns its path. The CPU
+// profile is taken for cpuSeconds, 10 seconds by default, or skipped if 0.
+func (api *PrivateAdminAPI) CollectDiagnostics(cpuSeconds *int) (string, error) {
+	cpuProfile := defaultDiagnosticsCPUProfile
+	if cpuSeconds != nil {
+		if *cpuSeconds < 0 {
+			return "", fmt.Errorf("negative CPU profile duration %d", *cpuSeconds)
+		}
+		cpuProfile = time.Duration(*cpuSeconds) * time.Second
+	}
+	return api.node.CollectDiagnostics(cpuProfile)
+}
+
 // PublicAdminAPI is the collection of administrative API methods exposed over
 // both secure and unsecure RPC channels.
 type PublicAdminAPI struct {
@@ -305,6 +583,36 @@ func (api *PublicAdminAPI) Peers() ([]*p2p.PeerInfo, error) {
 	return server.PeersInfo(), nil
 }
 
+// Topology retrieves this node and its connected peers with their advertised
+// listen addresses, node types and connection directions.
+func (api *PublicAdminAPI) Topology() (*p2p.Topology, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	return server.Topology(), nil
+}
+
+// PeerScores retrieves the scores of the peers tracked by the node,
+// including the peers banned because of their low scores.
+func (api *PublicAdminAPI) PeerScores() ([]*p2p.PeerScoreInfo, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	return server.PeerScores(), nil
+}
+
+// NATStatus retrieves the external endpoint detected by the NAT mechanism and
+// the health of the port mappings. It returns nil if NAT is not configured.
+func (api *PublicAdminAPI) NATStatus() (*nat.Status, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	return server.NATStatus(), nil
+}
+
 // NodeInfo retrieves all the information we know about the host node at the
 // protocol granularity.
 func (api *PublicAdminAPI) NodeInfo() (*p2p.NodeInfo, error) {
@@ -445,9 +753,26 @@ func NewPublicKlayAPI(stack *Node) *PublicKlayAPI {
 	return &PublicKlayAPI{stack}
 }
 
-// ClientVersion returns the node name
-func (s *PublicKlayAPI) ClientVersion() string {
-	return s.stack.Server().Name()
+// ClientVersionInfo is the verbose result of klay_clientVersion.
+type ClientVersionInfo struct {
+	Name string `json:"name"`
+	*params.VersionInfo
+	Protocols map[string]interface{} `json:"protocols"` // Chain configuration, enabled forks and database version
+}
+
+// ClientVersion returns the node name. If verbose is true, it returns the build
+// information and the protocol metadata as ClientVersionInfo instead.
+func (s *PublicKlayAPI) ClientVersion(verbose *bool) interface{} {
+	server := s.stack.Server()
+	if verbose == nil || !*verbose {
+		return server.Name()
+	}
+	info := server.NodeInfo()
+	return &ClientVersionInfo{
+		Name:        info.Name,
+		VersionInfo: info.Version,
+		Protocols:   info.Protocols,
+	}
 }
 
 // Sha3 applies the Klaytn sha3 implementation on the input.
@@ -36,9 +36,11 @@ import (
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
 	"github.com/klaytn/klaytn/networks/rpc"
 	"github.com/klaytn/klaytn/params"
 	"github.com/klaytn/klaytn/rlp"
+	"github.com/klaytn/klaytn/storage/database"
 	"github.com/klaytn/klaytn/storage/statedb"
 	"github.com/klaytn/klaytn/work"
 )
@@ -172,6 +174,54 @@ func (api *PrivateAdminAPI) StartStateMigration() error {
 	return api.cn.blockchain.PrepareStateMigration()
 }
 
+// ReshardStateTrieDB starts state migration to a new state trie database with
+// the given number of shards.
+func (api *PrivateAdminAPI) ReshardStateTrieDB(numShards uint) error {
+	if err := api.cn.ChainDB().SetStateTrieMigrationShards(numShards); err != nil {
+		return err
+	}
+	return api.cn.blockchain.PrepareStateMigration()
+}
+
+// PruneStateTries starts deleting the trie nodes only used by the state of the
+// blocks before the given block number. It requires trie node reference counting.
+func (api *PrivateAdminAPI) PruneStateTries(to rpc.BlockNumber) error {
+	if to < 0 {
+		return fmt.Errorf("invalid block number %d", to)
+	}
+	return api.cn.blockchain.StartPruningStateTries(uint64(to))
+}
+
+// CreateDbSnapshot writes a consistent snapshot of the databases at the current
+// block to the given directory in the background. If url is given, the snapshot
+// is streamed to the url as a tar archive over HTTP and removed from the directory,
+// to clone the node without downtime.
+func (api *PrivateAdminAPI) CreateDbSnapshot(dir string, url *string) error {
+	if dir == "" {
+		return errors.New("snapshot directory is not given")
+	}
+	if url == nil {
+		return api.cn.blockchain.StartDBSnapshot(dir, "")
+	}
+	return api.cn.blockchain.StartDBSnapshot(dir, *url)
+}
+
+// FreezeWrites stops the database writes at a consistent block boundary, and
+// syncs the databases to the disk, so that a filesystem snapshot such as EBS or
+// ZFS can be taken. The writes are thawed by ThawWrites, or automatically after
+// the given timeout in seconds, which is capped by blockchain.MaxWriteFreezeTimeout.
+func (api *PrivateAdminAPI) FreezeWrites(timeout *uint64) error {
+	if timeout == nil {
+		return api.cn.blockchain.FreezeWrites(blockchain.MaxWriteFreezeTimeout)
+	}
+	return api.cn.blockchain.FreezeWrites(time.Duration(*timeout) * time.Second)
+}
+
+// ThawWrites resumes the database writes frozen by FreezeWrites.
+func (api *PrivateAdminAPI) ThawWrites() error {
+	return api.cn.blockchain.ThawWrites()
+}
+
 // StopStateMigration stops state migration and removes stateMigrationDB.
 func (api *PrivateAdminAPI) StopStateMigration() error {
 	return api.cn.BlockChain().StopStateMigration()
@@ -197,6 +247,53 @@ func (api *PrivateAdminAPI) StateMigrationStatus() map[string]interface{} {
 	}
 }
 
+// ChannelConfig returns the number of message processing goroutines of each
+// connection and the message queue size of multichannel peers connected from
+// now on. The peers already connected keep the configuration they were
+// connected with; see ChannelBacklogs for the queue size of each peer.
+func (api *PrivateAdminAPI) ChannelConfig() ChannelConfig {
+	return api.cn.protocolManager.ChannelConfig()
+}
+
+// SetChannelWorkers sets the number of message processing goroutines of the
+// given connection (0: default, 1: tx). It applies only to the peers connected
+// afterwards; the peers already connected keep their goroutines until they
+// reconnect.
+func (api *PrivateAdminAPI) SetChannelWorkers(conn, workers int) error {
+	return api.cn.protocolManager.SetChannelWorkers(conn, workers)
+}
+
+// SetChannelQueueSize sets the message queue size of multichannel peers.
+// It applies only to the peers connected afterwards; the peers already
+// connected keep their queues until they reconnect.
+func (api *PrivateAdminAPI) SetChannelQueueSize(size int) error {
+	return api.cn.protocolManager.SetChannelQueueSize(size)
+}
+
+// ChannelBacklogs returns the number of queued messages of each multichannel peer.
+func (api *PrivateAdminAPI) ChannelBacklogs() map[string]ChannelBacklog {
+	return api.cn.protocolManager.ChannelBacklogs()
+}
+
+// ProxyStatus returns the upstream CNs of the validator proxy and which one is active.
+func (api *PrivateAdminAPI) ProxyStatus() (*ProxyStatus, error) {
+	status := api.cn.protocolManager.ProxyStatus()
+	if status == nil {
+		return nil, errNotProxy
+	}
+	return status, nil
+}
+
+// SetActiveUpstream switches the active upstream CN of the validator proxy to
+// the given connected upstream, e.g. before a maintenance of the active one.
+func (api *PrivateAdminAPI) SetActiveUpstream(id string) error {
+	nodeID, err := discover.HexID(id)
+	if err != nil {
+		return fmt.Errorf("invalid node ID: %v", err)
+	}
+	return api.cn.protocolManager.SetActiveUpstream(nodeID)
+}
+
 func (api *PrivateAdminAPI) SaveTrieNodeCacheToDisk() error {
 	return api.cn.BlockChain().SaveTrieNodeCacheToDisk()
 }
@@ -377,6 +474,75 @@ func NewPrivateDebugAPI(config *params.ChainConfig, cn *CN) *PrivateDebugAPI {
 	return &PrivateDebugAPI{config: config, cn: cn}
 }
 
+// PropagationStats returns the percentiles of the block and transaction
+// propagation latencies in milliseconds, overall and per peer.
+func (api *PrivateDebugAPI) PropagationStats() *PropagationStats {
+	return api.cn.protocolManager.PropagationStats()
+}
+
+// CacheStats returns the hits, misses, evictions and hit rates of the internal
+// caches such as trie nodes, state objects, receipts and headers.
+func (api *PrivateDebugAPI) CacheStats() map[string]common.CacheStats {
+	return common.GetCacheStats()
+}
+
+// DbStats returns the size on disk, the number of the keys, the compaction
+// backlog and the write stalls of each database, such as header and statetrie.
+// The number of the keys is estimated for a large database.
+func (api *PrivateDebugAPI) DbStats() []database.DBStats {
+	return api.cn.ChainDB().Stats()
+}
+
+// maxDBRangeLimit is the maximum number of the entries returned by DbRange.
+const maxDBRangeLimit = 1024
+
+type DBRangeResult struct {
+	Entries []DBRangeEntry `json:"entries"`
+	NextKey hexutil.Bytes  `json:"nextKey"` // nil if Entries includes the last key with the prefix.
+}
+
+type DBRangeEntry struct {
+	Key   hexutil.Bytes `json:"key"`
+	Value hexutil.Bytes `json:"value"`
+}
+
+// DbRange returns a page of the raw key/value pairs in the database of the given
+// name, such as header and statetrie, whose keys have the given prefix and are
+// not less than the prefix followed by the start. The next page starts at the
+// returned next key, which does not include the prefix.
+func (api *PrivateDebugAPI) DbRange(name string, prefix, start hexutil.Bytes, limit int) (DBRangeResult, error) {
+	entryType, ok := database.DBEntryTypeByName(name)
+	if !ok {
+		return DBRangeResult{}, fmt.Errorf("unknown database %q", name)
+	}
+	db := api.cn.ChainDB().GetDatabase(entryType)
+	if db == nil {
+		return DBRangeResult{}, fmt.Errorf("database %q does not exist", name)
+	}
+	if limit <= 0 || limit > maxDBRangeLimit {
+		return DBRangeResult{}, fmt.Errorf("limit should be between 1 and %d", maxDBRangeLimit)
+	}
+	return dbRange(db, prefix, start, limit)
+}
+
+func dbRange(db database.Database, prefix, start []byte, limit int) (DBRangeResult, error) {
+	it := db.NewIterator(prefix, start)
+	defer it.Release()
+
+	result := DBRangeResult{Entries: []DBRangeEntry{}}
+	for i := 0; i < limit && it.Next(); i++ {
+		result.Entries = append(result.Entries, DBRangeEntry{
+			Key:   common.CopyBytes(it.Key()),
+			Value: common.CopyBytes(it.Value()),
+		})
+	}
+	// Add the 'next key' so clients can continue downloading.
+	if it.Next() {
+		result.NextKey = common.CopyBytes(it.Key()[len(prefix):])
+	}
+	return result, it.Error()
+}
+
 // Preimage is a debug API function that returns the preimage for a sha3 hash, if known.
 func (api *PrivateDebugAPI) Preimage(ctx context.Context, hash common.Hash) (hexutil.Bytes, error) {
 	if preimage := api.cn.ChainDB().ReadPreimage(hash); preimage != nil {
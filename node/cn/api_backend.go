@@ -78,7 +78,9 @@ func (b *CNAPIBackend) CurrentBlock() *types.Block {
 func (b *CNAPIBackend) SetHead(number uint64) {
 	b.cn.protocolManager.Downloader().Cancel()
 	b.cn.protocolManager.SetSyncStop(true)
-	b.cn.blockchain.SetHead(number)
+	if err := b.cn.blockchain.SetHead(number); err != nil {
+		logger.Error("Failed to rewind the chain", "number", number, "err", err)
+	}
 	b.cn.protocolManager.SetSyncStop(false)
 }
 
@@ -91,3 +91,43 @@ func TestStorageRangeAt(t *testing.T) {
 		}
 	}
 }
+
+func TestDBRange(t *testing.T) {
+	db := database.NewMemDB()
+	for _, key := range []string{"a1", "a2", "a3", "b1"} {
+		db.Put([]byte(key), []byte("v"+key))
+	}
+
+	tests := []struct {
+		prefix, start []byte
+		limit         int
+		want          DBRangeResult
+	}{
+		{
+			prefix: []byte("a"), start: nil, limit: 2,
+			want: DBRangeResult{[]DBRangeEntry{{[]byte("a1"), []byte("va1")}, {[]byte("a2"), []byte("va2")}}, []byte("3")},
+		},
+		{
+			prefix: []byte("a"), start: []byte("3"), limit: 2,
+			want: DBRangeResult{[]DBRangeEntry{{[]byte("a3"), []byte("va3")}}, nil},
+		},
+		{
+			prefix: nil, start: []byte("a4"), limit: 10,
+			want: DBRangeResult{[]DBRangeEntry{{[]byte("b1"), []byte("vb1")}}, nil},
+		},
+		{
+			prefix: []byte("c"), start: nil, limit: 10,
+			want: DBRangeResult{[]DBRangeEntry{}, nil},
+		},
+	}
+	for _, test := range tests {
+		result, err := dbRange(db, test.prefix, test.start, test.limit)
+		if err != nil {
+			t.Error(err)
+		}
+		if !reflect.DeepEqual(result, test.want) {
+			t.Fatalf("wrong result for prefix %q, start %q, limit %d:\ngot %s\nwant %s",
+				test.prefix, test.start, test.limit, dumper.Sdump(result), dumper.Sdump(&test.want))
+		}
+	}
+}
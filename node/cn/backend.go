@@ -25,12 +25,16 @@ import (
 	"fmt"
 	"math/big"
 	"os/exec"
+	"reflect"
 	"runtime"
 	"sync"
 	"time"
 
 	"github.com/klaytn/klaytn"
 	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/accounts/kms"
+	"github.com/klaytn/klaytn/accounts/pkcs11"
+	"github.com/klaytn/klaytn/accounts/remote"
 	"github.com/klaytn/klaytn/api"
 	"github.com/klaytn/klaytn/blockchain"
 	"github.com/klaytn/klaytn/blockchain/bloombits"
@@ -46,6 +50,7 @@ import (
 	"github.com/klaytn/klaytn/event"
 	"github.com/klaytn/klaytn/governance"
 	"github.com/klaytn/klaytn/networks/p2p"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
 	"github.com/klaytn/klaytn/networks/rpc"
 	"github.com/klaytn/klaytn/node"
 	"github.com/klaytn/klaytn/node/cn/filters"
@@ -91,10 +96,18 @@ type BackendProtocolManager interface {
 	SetAcceptTxs()
 	SetRewardbase(addr common.Address)
 	SetRewardbaseWallet(wallet accounts.Wallet)
+	SetValidatorAddress(id discover.NodeID, addr common.Address, signFn func(data []byte) ([]byte, error)) error
 	NodeType() common.ConnType
 	Start(maxPeers int)
 	Stop()
 	SetSyncStop(flag bool)
+	ChannelConfig() ChannelConfig
+	SetChannelWorkers(conn, workers int) error
+	SetChannelQueueSize(size int) error
+	ChannelBacklogs() map[string]ChannelBacklog
+	PropagationStats() *PropagationStats
+	ProxyStatus() *ProxyStatus
+	SetActiveUpstream(id discover.NodeID) error
 }
 
 // CN implements the Klaytn consensus node service.
@@ -134,6 +147,10 @@ type CN struct {
 	components []interface{}
 
 	governance governance.Engine
+
+	health *healthChecker // nil if the health endpoint is disabled
+
+	multisig *multisigCoordinator // nil if the multisig coordinator is disabled
 }
 
 func (s *CN) AddLesServer(ls LesServer) {
@@ -245,9 +262,10 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 		governance:        governance,
 	}
 
-	// istanbul BFT. Derive and set node's address using nodekey
+	// istanbul BFT. Set node's address, the validator address of the consensus engine
+	validator := validatorAddress(ctx, cn.engine)
 	if cn.chainConfig.Istanbul != nil {
-		governance.SetNodeAddress(crypto.PubkeyToAddress(ctx.NodeKey().PublicKey))
+		governance.SetNodeAddress(validator)
 	}
 
 	logger.Info("Initialising Klaytn protocol", "versions", cn.engine.Protocol().Versions, "network", config.NetworkId)
@@ -257,11 +275,16 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 			return nil, err
 		}
 	}
+	if !config.SkipIntegrityCheck {
+		if err := blockchain.CheckIntegrity(chainDB, genesisHash); err != nil {
+			return nil, err
+		}
+	}
 	var (
 		vmConfig    = config.getVMConfig()
 		cacheConfig = &blockchain.CacheConfig{
 			ArchiveMode: config.NoPruning, CacheSize: config.TrieCacheSize,
-			BlockInterval: config.TrieBlockInterval, TriesInMemory: config.TriesInMemory,
+			BlockInterval: config.TrieBlockInterval, TriesInMemory: config.TriesInMemory, TrieNodeRefCount: config.TrieNodeRefCount,
 			TrieNodeCacheConfig: &config.TrieNodeCacheConfig, SenderTxHashIndexing: config.SenderTxHashIndexing, SnapshotCacheSize: config.SnapshotCacheSize,
 		}
 	)
@@ -316,6 +339,14 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 
 	cn.protocolManager.SetWsEndPoint(config.WsEndpoint)
 
+	// The peers learn the validator address from its signature if it is not the node key's.
+	if validator != crypto.PubkeyToAddress(ctx.NodeKey().PublicKey) {
+		sign := cn.engine.(interface{ Sign([]byte) ([]byte, error) }).Sign
+		if err := cn.protocolManager.SetValidatorAddress(discover.PubkeyID(&ctx.NodeKey().PublicKey), validator, sign); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := cn.setRewardWallet(); err != nil {
 		logger.Error("Error happened while setting the reward wallet", "err", err)
 	}
@@ -336,7 +367,7 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 		}
 	} else {
 		// TODO-Klaytn improve to handle drop transaction on network traffic in PN and EN
-		cn.miner = work.New(cn, cn.chainConfig, cn.EventMux(), cn.engine, ctx.NodeType(), crypto.PubkeyToAddress(ctx.NodeKey().PublicKey), cn.config.TxResendUseLegacy)
+		cn.miner = work.New(cn, cn.chainConfig, cn.EventMux(), cn.engine, ctx.NodeType(), validator, cn.config.TxResendUseLegacy)
 	}
 
 	// istanbul BFT
@@ -351,6 +382,10 @@ func New(ctx *node.ServiceContext, config *Config) (*CN, error) {
 	gpoParams.Default = config.GasPrice
 
 	cn.APIBackend.gpo = gasprice.NewOracle(cn.APIBackend, gpoParams, cn.txPool)
+
+	if config.Multisig.Enabled {
+		cn.multisig = newMultisigCoordinator(config.Multisig, cn.APIBackend, chainDB.GetMiscDB())
+	}
 	//@TODO Klaytn add core component
 	cn.addComponent(cn.blockchain)
 	cn.addComponent(cn.txPool)
@@ -457,10 +492,22 @@ func makeExtraData(extra []byte) []byte {
 
 // CreateDB creates the chain database.
 func CreateDB(ctx *node.ServiceContext, config *Config, name string) database.DBManager {
+	compression, err := database.ParseDBCompression(config.DBCompression)
+	if err != nil {
+		logger.Crit("Invalid database compression", "err", err)
+	}
+	var encryptionKey []byte
+	if config.DBEncryptionKey != "" {
+		if encryptionKey, err = database.LoadEncryptionKey(config.DBEncryptionKey); err != nil {
+			logger.Crit("Failed to load the database encryption key", "err", err)
+		}
+	}
 	dbc := &database.DBConfig{
 		Dir: name, DBType: config.DBType, ParallelDBWrite: config.ParallelDBWrite, SingleDB: config.SingleDB, NumStateTrieShards: config.NumStateTrieShards,
 		LevelDBCacheSize: config.LevelDBCacheSize, OpenFilesLimit: database.GetOpenFilesLimit(), LevelDBCompression: config.LevelDBCompression,
 		LevelDBBufferPool: config.LevelDBBufferPool, EnableDBPerfMetrics: config.EnableDBPerfMetrics, DynamoDBConfig: &config.DynamoDBConfig,
+		ColdStorageConfig: &config.ColdStorageConfig, AncientSourceConfig: &config.AncientSourceConfig,
+		Compression: compression, EncryptionKey: encryptionKey,
 	}
 	return ctx.OpenDatabase(dbc)
 }
@@ -471,7 +518,60 @@ func CreateConsensusEngine(ctx *node.ServiceContext, config *Config, chainConfig
 	if chainConfig.Governance == nil {
 		chainConfig.Governance = params.GetDefaultGovernanceConfig()
 	}
-	return istanbulBackend.New(config.Rewardbase, &config.Istanbul, ctx.NodeKey(), db, gov, nodetype)
+	engine := istanbulBackend.New(config.Rewardbase, &config.Istanbul, ctx.NodeKey(), db, gov, nodetype)
+	setExternalConsensusSigner(ctx, engine)
+	return engine
+}
+
+// consensusSigner is an accounts backend which may sign the consensus messages
+// with a key held outside of the node.
+type consensusSigner interface {
+	ConsensusSigner() (common.Address, func(hash []byte) ([]byte, error), error)
+}
+
+// setExternalConsensusSigner makes the node validate as the consensus key of
+// the KMS, of the remote signer or of the PKCS#11 token, if it is configured.
+// The node key is still the identity of the p2p connections.
+func setExternalConsensusSigner(ctx *node.ServiceContext, engine consensus.Istanbul) {
+	if ctx.AccountManager == nil {
+		return
+	}
+	signers := []struct {
+		name string
+		typ  reflect.Type
+	}{
+		{"KMS", kms.BackendType},
+		{"remote signer", remote.BackendType},
+		{"PKCS#11 token", pkcs11.BackendType},
+	}
+	for _, signer := range signers {
+		backends := ctx.AccountManager.Backends(signer.typ)
+		if len(backends) == 0 {
+			continue
+		}
+		addr, signFn, err := backends[0].(consensusSigner).ConsensusSigner()
+		if err != nil {
+			logger.Crit("Failed to get the consensus key of the "+signer.name, "err", err)
+		}
+		if signFn == nil {
+			continue
+		}
+		engine.(interface {
+			SetSigner(common.Address, istanbulBackend.SignFn)
+		}).SetSigner(addr, signFn)
+		logger.Info("Signing the consensus messages by the "+signer.name, "validator", addr,
+			"node", crypto.PubkeyToAddress(ctx.NodeKey().PublicKey))
+		return
+	}
+}
+
+// validatorAddress returns the address the node validates as, which is the
+// address of the node key unless an external consensus signer is configured.
+func validatorAddress(ctx *node.ServiceContext, engine consensus.Engine) common.Address {
+	if e, ok := engine.(interface{ Address() common.Address }); ok {
+		return e.Address()
+	}
+	return crypto.PubkeyToAddress(ctx.NodeKey().PublicKey)
 }
 
 // APIs returns the collection of RPC services the ethereum package offers.
@@ -491,6 +591,21 @@ func (s *CN) APIs() []rpc.API {
 	ethAPI.SetGovernanceKlayAPI(governanceKlayAPI)
 	ethAPI.SetPublicGovernanceAPI(publicGovernanceAPI)
 
+	if s.multisig != nil {
+		apis = append(apis, []rpc.API{
+			{
+				Namespace: "multisig",
+				Version:   "1.0",
+				Service:   &PublicMultisigAPI{s.multisig},
+				Public:    true,
+			}, {
+				Namespace: "multisig",
+				Version:   "1.0",
+				Service:   &PrivateMultisigAPI{s.multisig},
+			},
+		}...)
+	}
+
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -661,6 +776,17 @@ func (s *CN) Start(srvr p2p.Server) error {
 	if s.lesServer != nil {
 		s.lesServer.Start(srvr)
 	}
+	if s.config.Health.Enabled {
+		s.health = newHealthChecker(s.config.Health, s.Progress, srvr.PeerCount, s.blockchain.CurrentHeader, s.chainDB)
+		if err := s.health.start(); err != nil {
+			return err
+		}
+	}
+	// The upstream CNs of the validator proxy are always kept connected.
+	for _, upstream := range s.config.ProxyUpstreams {
+		srvr.AddTrustedPeer(upstream)
+		srvr.AddPeer(upstream)
+	}
 
 	reward.StakingManagerSubscribe()
 
@@ -670,6 +796,9 @@ func (s *CN) Start(srvr p2p.Server) error {
 // Stop implements node.Service, terminating all internal goroutines used by the
 // Klaytn protocol.
 func (s *CN) Stop() error {
+	if s.health != nil {
+		s.health.stop()
+	}
 	// Stop all the peer-related stuff first.
 	s.protocolManager.Stop()
 	if s.lesServer != nil {
@@ -682,7 +811,12 @@ func (s *CN) Stop() error {
 	s.txPool.Stop()
 	s.miner.Stop()
 	reward.StakingManagerUnsubscribe()
+	// The block import is stopped at a block boundary, and the cached states are
+	// written before the databases are flushed and closed.
 	s.blockchain.Stop()
+	if err := s.chainDB.Sync(); err != nil {
+		logger.Error("Failed to flush the databases", "err", err)
+	}
 	s.chainDB.Close()
 	s.eventMux.Stop()
 
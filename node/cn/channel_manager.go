@@ -18,6 +18,7 @@ package cn
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/klaytn/klaytn/networks/p2p"
 )
@@ -31,6 +32,7 @@ const (
 )
 
 type ChannelManager struct {
+	mu          sync.RWMutex
 	msgChannels [][]chan p2p.Msg
 	msgCodes    map[uint64]uint
 }
@@ -60,6 +62,9 @@ func NewChannelManager(channelSize int) *ChannelManager {
 	channelMgr.RegisterMsgCode(BlockChannel, NewBlockMsg)
 
 	channelMgr.RegisterMsgCode(TxChannel, TxMsg)
+	channelMgr.RegisterMsgCode(TxChannel, NewPooledTransactionHashesMsg)
+	channelMgr.RegisterMsgCode(TxChannel, PooledTransactionsRequestMsg)
+	channelMgr.RegisterMsgCode(TxChannel, PooledTransactionsMsg)
 
 	channelMgr.RegisterMsgCode(MiscChannel, ReceiptsRequestMsg)
 	channelMgr.RegisterMsgCode(MiscChannel, ReceiptsMsg)
@@ -68,25 +73,51 @@ func NewChannelManager(channelSize int) *ChannelManager {
 	channelMgr.RegisterMsgCode(MiscChannel, NodeDataMsg)
 	channelMgr.RegisterMsgCode(MiscChannel, StakingInfoRequestMsg)
 	channelMgr.RegisterMsgCode(MiscChannel, StakingInfoMsg)
+	channelMgr.RegisterMsgCode(MiscChannel, ValidatorAddressMsg)
 
 	return channelMgr
 }
 
 // RegisterChannelWithIndex registers the channel corresponding to network and channel ID.
 func (cm *ChannelManager) RegisterChannelWithIndex(idx int, channelId uint, channel chan p2p.Msg) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
 	cm.msgChannels[idx][channelId] = channel
 }
 
 // RegisterMsgCode registers the channel id corresponding to msgCode.
 func (cm *ChannelManager) RegisterMsgCode(channelId uint, msgCode uint64) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
 	cm.msgCodes[msgCode] = channelId
 }
 
 // GetChannelWithMsgCode returns the channel corresponding to msgCode.
 func (cm *ChannelManager) GetChannelWithMsgCode(idx int, msgCode uint64) (chan p2p.Msg, error) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
 	if channelID, ok := cm.msgCodes[msgCode]; ok {
 		return cm.msgChannels[idx][channelID], nil
 	} else {
 		return nil, fmt.Errorf("there is no channel for idx:%v, msgCode:%v", idx, msgCode)
 	}
 }
+
+// Backlog returns the number of messages queued in the channels of each connection.
+// The block, tx and misc channels of a connection share a queue.
+func (cm *ChannelManager) Backlog() ChannelBacklog {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	backlog := ChannelBacklog{Connections: make([]int, len(cm.msgChannels))}
+	for idx, channels := range cm.msgChannels {
+		if ch := channels[BlockChannel]; ch != nil {
+			backlog.Connections[idx] = len(ch)
+			backlog.QueueSize = cap(ch)
+		}
+		if ch := channels[ConsensusChannel]; ch != nil {
+			backlog.Consensus = len(ch)
+		}
+	}
+	return backlog
+}
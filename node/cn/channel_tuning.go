@@ -0,0 +1,130 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"fmt"
+	"sync"
+)
+
+const (
+	maxChannelWorkers   = 32
+	maxChannelQueueSize = 4096
+)
+
+// ChannelConfig is the message processing configuration of multichannel peers.
+type ChannelConfig struct {
+	// Workers is the number of goroutines processing the messages received
+	// through each connection, indexed by p2p.ConnDefault, p2p.ConnTxMsg, ...
+	Workers []int `json:"workers"`
+	// QueueSize is the capacity of the message queue of each connection.
+	QueueSize int `json:"queueSize"`
+}
+
+// ChannelBacklog is the number of messages waiting to be processed for a peer.
+type ChannelBacklog struct {
+	Connections []int `json:"connections"` // backlog of each connection
+	Consensus   int   `json:"consensus"`   // backlog of the consensus messages
+	QueueSize   int   `json:"queueSize"`   // capacity of each queue
+}
+
+// channelTuning holds the ChannelConfig applied to newly connected multichannel
+// peers. The zero value uses ConcurrentOfChannel and channelSizePerPeer.
+type channelTuning struct {
+	mu        sync.RWMutex
+	workers   []int
+	queueSize int
+}
+
+func (t *channelTuning) config() ChannelConfig {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	config := ChannelConfig{Workers: make([]int, len(ConcurrentOfChannel)), QueueSize: t.queueSize}
+	copy(config.Workers, ConcurrentOfChannel)
+	copy(config.Workers, t.workers)
+	if config.QueueSize == 0 {
+		config.QueueSize = channelSizePerPeer
+	}
+	return config
+}
+
+func (t *channelTuning) setWorkers(conn, workers int) error {
+	if conn < 0 || conn >= len(ConcurrentOfChannel) {
+		return fmt.Errorf("invalid connection index %d", conn)
+	}
+	if workers < 1 || workers > maxChannelWorkers {
+		return fmt.Errorf("workers must be between 1 and %d", maxChannelWorkers)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.workers == nil {
+		t.workers = make([]int, len(ConcurrentOfChannel))
+		copy(t.workers, ConcurrentOfChannel)
+	}
+	t.workers[conn] = workers
+	return nil
+}
+
+func (t *channelTuning) setQueueSize(size int) error {
+	if size < 1 || size > maxChannelQueueSize {
+		return fmt.Errorf("queue size must be between 1 and %d", maxChannelQueueSize)
+	}
+	t.mu.Lock()
+	t.queueSize = size
+	t.mu.Unlock()
+	return nil
+}
+
+// ChannelConfig returns the message processing configuration of multichannel peers.
+func (pm *ProtocolManager) ChannelConfig() ChannelConfig {
+	return pm.channels.config()
+}
+
+// SetChannelWorkers sets the number of goroutines processing the messages
+// received through the given connection. It applies only to the peers connected
+// afterwards, since the goroutines of a peer are started when it connects.
+func (pm *ProtocolManager) SetChannelWorkers(conn, workers int) error {
+	if err := pm.channels.setWorkers(conn, workers); err != nil {
+		return err
+	}
+	logger.Info("Changed the channel workers of the peers connected afterwards", "conn", conn, "workers", workers, "unchangedPeers", pm.peers.Len())
+	return nil
+}
+
+// SetChannelQueueSize sets the capacity of the message queues. It applies only
+// to the peers connected afterwards, since the queues of a peer are made when
+// it connects.
+func (pm *ProtocolManager) SetChannelQueueSize(size int) error {
+	if err := pm.channels.setQueueSize(size); err != nil {
+		return err
+	}
+	logger.Info("Changed the channel queue size of the peers connected afterwards", "size", size, "unchangedPeers", pm.peers.Len())
+	return nil
+}
+
+// ChannelBacklogs returns the message queue backlogs of the multichannel peers.
+func (pm *ProtocolManager) ChannelBacklogs() map[string]ChannelBacklog {
+	backlogs := make(map[string]ChannelBacklog)
+	for id, p := range pm.peers.Peers() {
+		if mp, ok := p.(*multiChannelPeer); ok {
+			backlogs[id] = mp.chMgr.Backlog()
+		}
+	}
+	return backlogs
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"testing"
+
+	"github.com/klaytn/klaytn/networks/p2p"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelTuning(t *testing.T) {
+	var tuning channelTuning
+
+	// The zero value uses the defaults.
+	config := tuning.config()
+	assert.Equal(t, ConcurrentOfChannel, config.Workers)
+	assert.Equal(t, channelSizePerPeer, config.QueueSize)
+
+	assert.NoError(t, tuning.setWorkers(p2p.ConnTxMsg, 5))
+	assert.NoError(t, tuning.setQueueSize(100))
+	config = tuning.config()
+	assert.Equal(t, ConcurrentOfChannel[p2p.ConnDefault], config.Workers[p2p.ConnDefault])
+	assert.Equal(t, 5, config.Workers[p2p.ConnTxMsg])
+	assert.Equal(t, 100, config.QueueSize)
+
+	// The defaults are not modified.
+	assert.Equal(t, 3, ConcurrentOfChannel[p2p.ConnTxMsg])
+
+	assert.Error(t, tuning.setWorkers(len(ConcurrentOfChannel), 1))
+	assert.Error(t, tuning.setWorkers(p2p.ConnDefault, 0))
+	assert.Error(t, tuning.setWorkers(p2p.ConnDefault, maxChannelWorkers+1))
+	assert.Error(t, tuning.setQueueSize(0))
+	assert.Error(t, tuning.setQueueSize(maxChannelQueueSize+1))
+}
+
+func TestChannelManager_Backlog(t *testing.T) {
+	cm := NewChannelManager(2)
+	channels := []chan p2p.Msg{make(chan p2p.Msg, 4), make(chan p2p.Msg, 4)}
+	consensusChannel := make(chan p2p.Msg, 4)
+	for idx, ch := range channels {
+		cm.RegisterChannelWithIndex(idx, BlockChannel, ch)
+		cm.RegisterChannelWithIndex(idx, ConsensusChannel, consensusChannel)
+	}
+
+	channels[1] <- p2p.Msg{}
+	channels[1] <- p2p.Msg{}
+	consensusChannel <- p2p.Msg{}
+
+	backlog := cm.Backlog()
+	assert.Equal(t, []int{0, 2}, backlog.Connections)
+	assert.Equal(t, 1, backlog.Consensus)
+	assert.Equal(t, 4, backlog.QueueSize)
+}
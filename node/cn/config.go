@@ -35,6 +35,7 @@ import (
 	"github.com/klaytn/klaytn/consensus/istanbul"
 	"github.com/klaytn/klaytn/datasync/downloader"
 	"github.com/klaytn/klaytn/log"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
 	"github.com/klaytn/klaytn/node/cn/gasprice"
 	"github.com/klaytn/klaytn/params"
 	"github.com/klaytn/klaytn/storage/database"
@@ -46,6 +47,8 @@ var logger = log.NewModuleLogger(log.NodeCN)
 func GetDefaultConfig() *Config {
 	return &Config{
 		SyncMode:            downloader.FullSync,
+		SyncPivotDistance:   downloader.DefaultPivotDistance,
+		SyncPivotPeers:      1,
 		NetworkId:           params.CypressNetworkId,
 		LevelDBCacheSize:    768,
 		TrieCacheSize:       512,
@@ -63,6 +66,8 @@ func GetDefaultConfig() *Config {
 			MaxBlockHistory:  1024,
 		},
 		WsEndpoint: "localhost:8546",
+		Health:     DefaultHealthConfig,
+		Multisig:   DefaultMultisigConfig,
 
 		Istanbul: *istanbul.DefaultConfig,
 	}
@@ -90,6 +95,12 @@ type Config struct {
 	NoPruning     bool
 	WorkerDisable bool // disables worker and does not start istanbul
 
+	// Sync verification depth
+	SyncPivotDistance uint64                  // Number of blocks below the remote head to select the fast sync pivot at
+	SyncPivotPeers    int                     // Number of peers which must agree on the fast sync pivot header
+	SyncCheckpoints   []downloader.Checkpoint `toml:",omitempty"` // Trusted headers which the synced chain must match
+	SyncFrom          uint64                  // Trusted checkpoint block from which a full sync executes the blocks
+
 	// KES options
 	DownloaderDisable bool
 	FetcherDisable    bool
@@ -109,17 +120,23 @@ type Config struct {
 	// Database options
 	DBType               database.DBType
 	SkipBcVersionCheck   bool `toml:"-"`
+	SkipIntegrityCheck   bool `toml:"-"` // Skip the startup check of the chain data
 	SingleDB             bool
 	NumStateTrieShards   uint
 	EnableDBPerfMetrics  bool
 	LevelDBCompression   database.LevelDBCompressionType
+	DBCompression        string
+	DBEncryptionKey      string // Source of the database encryption key, not the key itself
 	LevelDBBufferPool    bool
 	LevelDBCacheSize     int
 	DynamoDBConfig       database.DynamoDBConfig
+	ColdStorageConfig    database.ColdStorageConfig
+	AncientSourceConfig  database.AncientSourceConfig
 	TrieCacheSize        int
 	TrieTimeout          time.Duration
 	TrieBlockInterval    uint
 	TriesInMemory        uint64
+	TrieNodeRefCount     bool
 	SenderTxHashIndexing bool
 	ParallelDBWrite      bool
 	TrieNodeCacheConfig  statedb.TrieNodeCacheConfig
@@ -151,11 +168,25 @@ type Config struct {
 
 	WsEndpoint string `toml:",omitempty"`
 
+	// Health endpoint options
+	Health HealthConfig
+
+	// Multisig coordinator options
+	Multisig MultisigConfig
+
 	// Tx Resending options
 	TxResendInterval  uint64
 	TxResendCount     int
 	TxResendUseLegacy bool
 
+	// Sentry options
+	SentryMode     bool // the node is a validator connected only to its sentry nodes
+	ConsensusRelay bool // the node is a sentry relaying consensus messages of its validators
+
+	// ProxyUpstreams are the CNs of the validator behind the node running as a
+	// validator proxy, in the order of priority. The proxy fails over between them.
+	ProxyUpstreams []*discover.Node `toml:",omitempty"`
+
 	// Service Chain
 	NoAccountCreation bool
 
@@ -0,0 +1,79 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/networks/p2p"
+)
+
+const maxScoredTxDeliveries = 65536 // Maximum number of delivered transactions waiting for the pool admission
+
+// scoredPeer is a peer whose score can be changed.
+type scoredPeer interface {
+	AddScore(ev p2p.PeerScoreEvent)
+}
+
+// deliveryScorer awards the score of a delivered transaction to the peer which
+// delivered it first, only after the transaction is admitted into the pool, so
+// that a peer can't earn the score by flooding invalid transactions. The methods
+// of a nil scorer do nothing.
+type deliveryScorer struct {
+	txs *lru.Cache // tx hash -> scoredPeer delivered the tx first
+}
+
+func newDeliveryScorer() *deliveryScorer {
+	txs, _ := lru.New(maxScoredTxDeliveries)
+	return &deliveryScorer{txs: txs}
+}
+
+// txsDelivered records the peer as the deliverer of the transactions which
+// have not been delivered by another peer.
+func (s *deliveryScorer) txsDelivered(p scoredPeer, txs types.Transactions) {
+	if s == nil || p == nil {
+		return
+	}
+	for _, tx := range txs {
+		s.txs.ContainsOrAdd(tx.Hash(), p)
+	}
+}
+
+// txsAdmitted awards the score to the deliverers of the transactions admitted
+// into the pool.
+func (s *deliveryScorer) txsAdmitted(txs []*types.Transaction) {
+	if s == nil {
+		return
+	}
+	for _, tx := range txs {
+		hash := tx.Hash()
+		if v, ok := s.txs.Get(hash); ok {
+			s.txs.Remove(hash)
+			v.(scoredPeer).AddScore(p2p.PeerScoreValidTx)
+		}
+	}
+}
+
+// blocksImported awards the score to the peers which propagated the blocks
+// imported into the chain.
+func blocksImported(blocks types.Blocks) {
+	for _, block := range blocks {
+		if p, ok := block.ReceivedFrom.(Peer); ok {
+			p.GetP2PPeer().AddScore(p2p.PeerScoreValidBlock)
+		}
+	}
+}
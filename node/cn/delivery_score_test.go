@@ -0,0 +1,61 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/networks/p2p"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeScoredPeer struct {
+	events []p2p.PeerScoreEvent
+}
+
+func (p *fakeScoredPeer) AddScore(ev p2p.PeerScoreEvent) {
+	p.events = append(p.events, ev)
+}
+
+func TestDeliveryScorer(t *testing.T) {
+	scorer := newDeliveryScorer()
+	peer1, peer2 := &fakeScoredPeer{}, &fakeScoredPeer{}
+
+	tx1 := types.NewTransaction(1, common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil)
+	tx2 := types.NewTransaction(2, common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil)
+
+	scorer.txsDelivered(peer1, types.Transactions{tx1, tx2})
+	scorer.txsDelivered(peer2, types.Transactions{tx1})
+	assert.Empty(t, peer1.events, "no score before the pool admission")
+
+	// Only tx1 is admitted, and only the first deliverer gets the score.
+	scorer.txsAdmitted([]*types.Transaction{tx1})
+	assert.Equal(t, []p2p.PeerScoreEvent{p2p.PeerScoreValidTx}, peer1.events)
+	assert.Empty(t, peer2.events)
+
+	// The score is awarded only once per transaction.
+	scorer.txsAdmitted([]*types.Transaction{tx1})
+	assert.Len(t, peer1.events, 1)
+
+	// The methods of a nil scorer do nothing.
+	var nilScorer *deliveryScorer
+	nilScorer.txsDelivered(peer1, types.Transactions{tx1})
+	nilScorer.txsAdmitted([]*types.Transaction{tx1})
+}
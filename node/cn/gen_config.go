@@ -11,6 +11,7 @@ import (
 	"github.com/klaytn/klaytn/common/hexutil"
 	"github.com/klaytn/klaytn/consensus/istanbul"
 	"github.com/klaytn/klaytn/datasync/downloader"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
 	"github.com/klaytn/klaytn/node/cn/gasprice"
 	"github.com/klaytn/klaytn/storage/database"
 	"github.com/klaytn/klaytn/storage/statedb"
@@ -26,6 +27,10 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		SyncMode                downloader.SyncMode
 		NoPruning               bool
 		WorkerDisable           bool
+		SyncPivotDistance       uint64
+		SyncPivotPeers          int
+		SyncCheckpoints         []downloader.Checkpoint `toml:",omitempty"`
+		SyncFrom                uint64
 		DownloaderDisable       bool
 		FetcherDisable          bool
 		ParentOperatorAddr      *common.Address `toml:",omitempty"`
@@ -35,17 +40,23 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		StartBlockNumber        uint64
 		DBType                  database.DBType
 		SkipBcVersionCheck      bool `toml:"-"`
+		SkipIntegrityCheck      bool `toml:"-"`
 		SingleDB                bool
 		NumStateTrieShards      uint
 		EnableDBPerfMetrics     bool
 		LevelDBCompression      database.LevelDBCompressionType
+		DBCompression           string
+		DBEncryptionKey         string
 		LevelDBBufferPool       bool
 		LevelDBCacheSize        int
 		DynamoDBConfig          database.DynamoDBConfig
+		ColdStorageConfig       database.ColdStorageConfig
+		AncientSourceConfig     database.AncientSourceConfig
 		TrieCacheSize           int
 		TrieTimeout             time.Duration
 		TrieBlockInterval       uint
 		TriesInMemory           uint64
+		TrieNodeRefCount        bool
 		SenderTxHashIndexing    bool
 		ParallelDBWrite         bool
 		TrieNodeCacheConfig     statedb.TrieNodeCacheConfig
@@ -60,9 +71,13 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		Istanbul                istanbul.Config
 		DocRoot                 string `toml:"-"`
 		WsEndpoint              string `toml:",omitempty"`
+		Health                  HealthConfig
 		TxResendInterval        uint64
 		TxResendCount           int
 		TxResendUseLegacy       bool
+		SentryMode              bool
+		ConsensusRelay          bool
+		ProxyUpstreams          []*discover.Node `toml:",omitempty"`
 		NoAccountCreation       bool
 		IsPrivate               bool
 		AutoRestartFlag         bool
@@ -76,6 +91,10 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.SyncMode = c.SyncMode
 	enc.NoPruning = c.NoPruning
 	enc.WorkerDisable = c.WorkerDisable
+	enc.SyncPivotDistance = c.SyncPivotDistance
+	enc.SyncPivotPeers = c.SyncPivotPeers
+	enc.SyncCheckpoints = c.SyncCheckpoints
+	enc.SyncFrom = c.SyncFrom
 	enc.DownloaderDisable = c.DownloaderDisable
 	enc.FetcherDisable = c.FetcherDisable
 	enc.ParentOperatorAddr = c.ParentOperatorAddr
@@ -85,17 +104,23 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.StartBlockNumber = c.StartBlockNumber
 	enc.DBType = c.DBType
 	enc.SkipBcVersionCheck = c.SkipBcVersionCheck
+	enc.SkipIntegrityCheck = c.SkipIntegrityCheck
 	enc.SingleDB = c.SingleDB
 	enc.NumStateTrieShards = c.NumStateTrieShards
 	enc.EnableDBPerfMetrics = c.EnableDBPerfMetrics
 	enc.LevelDBCompression = c.LevelDBCompression
+	enc.DBCompression = c.DBCompression
+	enc.DBEncryptionKey = c.DBEncryptionKey
 	enc.LevelDBBufferPool = c.LevelDBBufferPool
 	enc.LevelDBCacheSize = c.LevelDBCacheSize
 	enc.DynamoDBConfig = c.DynamoDBConfig
+	enc.ColdStorageConfig = c.ColdStorageConfig
+	enc.AncientSourceConfig = c.AncientSourceConfig
 	enc.TrieCacheSize = c.TrieCacheSize
 	enc.TrieTimeout = c.TrieTimeout
 	enc.TrieBlockInterval = c.TrieBlockInterval
 	enc.TriesInMemory = c.TriesInMemory
+	enc.TrieNodeRefCount = c.TrieNodeRefCount
 	enc.SenderTxHashIndexing = c.SenderTxHashIndexing
 	enc.ParallelDBWrite = c.ParallelDBWrite
 	enc.TrieNodeCacheConfig = c.TrieNodeCacheConfig
@@ -110,9 +135,13 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.Istanbul = c.Istanbul
 	enc.DocRoot = c.DocRoot
 	enc.WsEndpoint = c.WsEndpoint
+	enc.Health = c.Health
 	enc.TxResendInterval = c.TxResendInterval
 	enc.TxResendCount = c.TxResendCount
 	enc.TxResendUseLegacy = c.TxResendUseLegacy
+	enc.SentryMode = c.SentryMode
+	enc.ConsensusRelay = c.ConsensusRelay
+	enc.ProxyUpstreams = c.ProxyUpstreams
 	enc.NoAccountCreation = c.NoAccountCreation
 	enc.IsPrivate = c.IsPrivate
 	enc.AutoRestartFlag = c.AutoRestartFlag
@@ -130,6 +159,10 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		SyncMode                *downloader.SyncMode
 		NoPruning               *bool
 		WorkerDisable           *bool
+		SyncPivotDistance       *uint64
+		SyncPivotPeers          *int
+		SyncCheckpoints         []downloader.Checkpoint `toml:",omitempty"`
+		SyncFrom                *uint64
 		DownloaderDisable       *bool
 		FetcherDisable          *bool
 		ParentOperatorAddr      *common.Address `toml:",omitempty"`
@@ -139,17 +172,23 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		StartBlockNumber        *uint64
 		DBType                  *database.DBType
 		SkipBcVersionCheck      *bool `toml:"-"`
+		SkipIntegrityCheck      *bool `toml:"-"`
 		SingleDB                *bool
 		NumStateTrieShards      *uint
 		EnableDBPerfMetrics     *bool
 		LevelDBCompression      *database.LevelDBCompressionType
+		DBCompression           *string
+		DBEncryptionKey         *string
 		LevelDBBufferPool       *bool
 		LevelDBCacheSize        *int
 		DynamoDBConfig          *database.DynamoDBConfig
+		ColdStorageConfig       *database.ColdStorageConfig
+		AncientSourceConfig     *database.AncientSourceConfig
 		TrieCacheSize           *int
 		TrieTimeout             *time.Duration
 		TrieBlockInterval       *uint
 		TriesInMemory           *uint64
+		TrieNodeRefCount        *bool
 		SenderTxHashIndexing    *bool
 		ParallelDBWrite         *bool
 		TrieNodeCacheConfig     *statedb.TrieNodeCacheConfig
@@ -164,9 +203,13 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		Istanbul                *istanbul.Config
 		DocRoot                 *string `toml:"-"`
 		WsEndpoint              *string `toml:",omitempty"`
+		Health                  *HealthConfig
 		TxResendInterval        *uint64
 		TxResendCount           *int
 		TxResendUseLegacy       *bool
+		SentryMode              *bool
+		ConsensusRelay          *bool
+		ProxyUpstreams          []*discover.Node `toml:",omitempty"`
 		NoAccountCreation       *bool
 		IsPrivate               *bool
 		AutoRestartFlag         *bool
@@ -193,6 +236,18 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.WorkerDisable != nil {
 		c.WorkerDisable = *dec.WorkerDisable
 	}
+	if dec.SyncPivotDistance != nil {
+		c.SyncPivotDistance = *dec.SyncPivotDistance
+	}
+	if dec.SyncPivotPeers != nil {
+		c.SyncPivotPeers = *dec.SyncPivotPeers
+	}
+	if dec.SyncCheckpoints != nil {
+		c.SyncCheckpoints = dec.SyncCheckpoints
+	}
+	if dec.SyncFrom != nil {
+		c.SyncFrom = *dec.SyncFrom
+	}
 	if dec.DownloaderDisable != nil {
 		c.DownloaderDisable = *dec.DownloaderDisable
 	}
@@ -220,6 +275,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.SkipBcVersionCheck != nil {
 		c.SkipBcVersionCheck = *dec.SkipBcVersionCheck
 	}
+	if dec.SkipIntegrityCheck != nil {
+		c.SkipIntegrityCheck = *dec.SkipIntegrityCheck
+	}
 	if dec.SingleDB != nil {
 		c.SingleDB = *dec.SingleDB
 	}
@@ -232,6 +290,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.LevelDBCompression != nil {
 		c.LevelDBCompression = *dec.LevelDBCompression
 	}
+	if dec.DBCompression != nil {
+		c.DBCompression = *dec.DBCompression
+	}
+	if dec.DBEncryptionKey != nil {
+		c.DBEncryptionKey = *dec.DBEncryptionKey
+	}
 	if dec.LevelDBBufferPool != nil {
 		c.LevelDBBufferPool = *dec.LevelDBBufferPool
 	}
@@ -241,6 +305,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.DynamoDBConfig != nil {
 		c.DynamoDBConfig = *dec.DynamoDBConfig
 	}
+	if dec.ColdStorageConfig != nil {
+		c.ColdStorageConfig = *dec.ColdStorageConfig
+	}
+	if dec.AncientSourceConfig != nil {
+		c.AncientSourceConfig = *dec.AncientSourceConfig
+	}
 	if dec.TrieCacheSize != nil {
 		c.TrieCacheSize = *dec.TrieCacheSize
 	}
@@ -253,6 +323,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.TriesInMemory != nil {
 		c.TriesInMemory = *dec.TriesInMemory
 	}
+	if dec.TrieNodeRefCount != nil {
+		c.TrieNodeRefCount = *dec.TrieNodeRefCount
+	}
 	if dec.SenderTxHashIndexing != nil {
 		c.SenderTxHashIndexing = *dec.SenderTxHashIndexing
 	}
@@ -295,6 +368,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.WsEndpoint != nil {
 		c.WsEndpoint = *dec.WsEndpoint
 	}
+	if dec.Health != nil {
+		c.Health = *dec.Health
+	}
 	if dec.TxResendInterval != nil {
 		c.TxResendInterval = *dec.TxResendInterval
 	}
@@ -304,6 +380,15 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.TxResendUseLegacy != nil {
 		c.TxResendUseLegacy = *dec.TxResendUseLegacy
 	}
+	if dec.SentryMode != nil {
+		c.SentryMode = *dec.SentryMode
+	}
+	if dec.ConsensusRelay != nil {
+		c.ConsensusRelay = *dec.ConsensusRelay
+	}
+	if dec.ProxyUpstreams != nil {
+		c.ProxyUpstreams = dec.ProxyUpstreams
+	}
 	if dec.NoAccountCreation != nil {
 		c.NoAccountCreation = *dec.NoAccountCreation
 	}
@@ -39,6 +39,7 @@ import (
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/consensus"
 	"github.com/klaytn/klaytn/consensus/istanbul"
+	"github.com/klaytn/klaytn/consensus/istanbul/backend"
 	"github.com/klaytn/klaytn/crypto"
 	"github.com/klaytn/klaytn/datasync/downloader"
 	"github.com/klaytn/klaytn/datasync/fetcher"
@@ -101,6 +102,7 @@ type ProtocolManager struct {
 	txpool      work.TxPool
 	blockchain  work.BlockChain
 	chainconfig *params.ChainConfig
+	chainDB     database.DBManager
 	maxPeers    int
 
 	downloader ProtocolManagerDownloader
@@ -138,6 +140,28 @@ type ProtocolManager struct {
 
 	// syncStop is a flag to stop peer sync
 	syncStop int32
+
+	// channels is the message processing configuration of multichannel peers
+	channels channelTuning
+
+	// propagation records the block and transaction propagation latencies
+	propagation *propagationTracker
+
+	// deliveries awards the peer scores of the delivered transactions after the pool admission
+	deliveries *deliveryScorer
+
+	// txRequests dedups the requests of the announced transactions and re-requests them from the alternate announcers
+	txRequests *txRequestTracker
+
+	// sentryMode is set if the node is a validator connected only to its sentry nodes
+	sentryMode bool
+	// consensusRelay is not nil if the node relays the consensus messages of validators
+	consensusRelay *consensusRelay
+	// proxy is not nil if the node is a validator proxy of upstream CNs
+	proxy *validatorProxy
+
+	// validatorAddress is sent to the peers to identify the node as a validator
+	validatorAddress *validatorAddressData
 }
 
 // NewProtocolManager returns a new Klaytn sub protocol manager. The Klaytn sub protocol manages peers capable
@@ -153,6 +177,7 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 		txpool:            txpool,
 		blockchain:        blockchain,
 		chainconfig:       config,
+		chainDB:           chainDB,
 		peers:             newPeerSet(),
 		newPeerCh:         make(chan Peer),
 		noMorePeers:       make(chan struct{}),
@@ -162,6 +187,17 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 		engine:            engine,
 		nodetype:          nodetype,
 		txResendUseLegacy: cnconfig.TxResendUseLegacy,
+		propagation:       newPropagationTracker(),
+		deliveries:        newDeliveryScorer(),
+		txRequests:        newTxRequestTracker(),
+		sentryMode:        cnconfig.SentryMode,
+		validatorAddress:  &validatorAddressData{},
+	}
+	if (cnconfig.ConsensusRelay || len(cnconfig.ProxyUpstreams) > 0) && nodetype == common.PROXYNODE {
+		manager.consensusRelay = newConsensusRelay()
+		if len(cnconfig.ProxyUpstreams) > 0 {
+			manager.proxy = newValidatorProxy(cnconfig.ProxyUpstreams)
+		}
 	}
 
 	// istanbul BFT
@@ -255,6 +291,7 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 				}
 				return nil
 			},
+			Compressible: compressibleMsg,
 		})
 
 		if cnconfig.SnapshotCacheSize > 0 {
@@ -284,6 +321,7 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 						}
 						return nil
 					},
+					Compressible: snap.CompressibleMsg,
 				})
 			}
 		}
@@ -313,7 +351,16 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 		if config.Istanbul != nil {
 			proposerPolicy = config.Istanbul.ProposerPolicy
 		}
-		manager.downloader = downloader.New(mode, chainDB, stateBloom, manager.eventMux, blockchain, nil, manager.removePeer, proposerPolicy)
+		d := downloader.New(mode, chainDB, stateBloom, manager.eventMux, blockchain, nil, manager.removePeer, proposerPolicy)
+		if err := d.SetPivotConfig(downloader.PivotConfig{
+			Distance:    cnconfig.SyncPivotDistance,
+			MinPeers:    cnconfig.SyncPivotPeers,
+			Checkpoints: cnconfig.SyncCheckpoints,
+			From:        cnconfig.SyncFrom,
+		}); err != nil {
+			return nil, err
+		}
+		manager.downloader = d
 	}
 
 	// Create and set fetcher
@@ -333,7 +380,11 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 				return 0, nil
 			}
 			atomic.StoreUint32(&manager.acceptTxs, 1) // Mark initial sync done on any fetcher import
-			return manager.blockchain.InsertChain(blocks)
+			n, err := manager.blockchain.InsertChain(blocks)
+			if err == nil {
+				blocksImported(blocks)
+			}
+			return n, err
 		}
 		manager.fetcher = fetcher.New(blockchain.GetBlockByHash, validator, manager.BroadcastBlock, manager.BroadcastBlockHash, heighter, inserter, manager.removePeer)
 	}
@@ -361,6 +412,18 @@ func (pm *ProtocolManager) SetRewardbaseWallet(wallet accounts.Wallet) {
 	pm.rewardwallet = wallet
 }
 
+// SetValidatorAddress makes the node identify itself to the peers as the
+// validator of the given address, whose key is not the node key. signFn signs
+// the data with the validator key after hashing it.
+func (pm *ProtocolManager) SetValidatorAddress(id discover.NodeID, addr common.Address, signFn func(data []byte) ([]byte, error)) error {
+	data, err := signValidatorAddress(id, addr, signFn)
+	if err != nil {
+		return err
+	}
+	pm.validatorAddress = data
+	return nil
+}
+
 func (pm *ProtocolManager) removePeer(id string) {
 	// Short circuit if the peer was already removed
 	peer := pm.peers.Peer(id)
@@ -377,6 +440,11 @@ func (pm *ProtocolManager) removePeer(id string) {
 	if err := pm.peers.Unregister(id); err != nil {
 		logger.Error("Peer removal failed", "peer", id, "err", err)
 	}
+	pm.propagation.removePeer(id)
+	pm.proxy.removePeer(peer)
+	if pm.consensusRelay != nil {
+		pm.consensusRelay.removePeer(peer.GetP2PPeerID())
+	}
 	// Hard disconnect at the networking layer
 	if peer != nil {
 		peer.GetP2PPeer().Disconnect(p2p.DiscUselessPeer)
@@ -403,6 +471,7 @@ func (pm *ProtocolManager) Start(maxPeers int) {
 	// start sync handlers
 	go pm.syncer()
 	go pm.txsyncLoop()
+	go pm.txRequestLoop()
 }
 
 func (pm *ProtocolManager) Stop() {
@@ -509,6 +578,10 @@ func (pm *ProtocolManager) handle(p Peer) error {
 		p.GetP2PPeer().Log().Debug("Klaytn peer handshake failed", "err", err)
 		return err
 	}
+	if err := p.HandshakeValidatorAddress(pm.validatorAddress); err != nil {
+		p.GetP2PPeer().Log().Debug("Klaytn peer validator address handshake failed", "err", err)
+		return err
+	}
 	reject := false
 	if atomic.LoadUint32(&pm.snapSync) == 1 {
 		if snap == nil {
@@ -553,14 +626,11 @@ func (pm *ProtocolManager) handle(p Peer) error {
 	// Propagate existing transactions. new transactions appearing
 	// after this will be sent via broadcasts.
 	pm.syncTransactions(p)
+	pm.proxy.addPeer(p)
 
 	p.GetP2PPeer().Log().Info("Added a single channel P2P Peer", "peerID", p.GetP2PPeerID())
 
-	pubKey, err := p.GetP2PPeerID().Pubkey()
-	if err != nil {
-		return err
-	}
-	addr := crypto.PubkeyToAddress(*pubKey)
+	addr := p.GetAddr()
 
 	// TODO-Klaytn check global worker and peer worker
 	messageChannel := make(chan p2p.Msg, channelSizePerPeer)
@@ -617,6 +687,7 @@ func (pm *ProtocolManager) processMsg(msgCh <-chan p2p.Msg, p Peer, addr common.
 		for msg := range msgCh {
 			if err := pm.handleMsg(p, addr, msg); err != nil {
 				p.GetP2PPeer().Log().Error("ProtocolManager failed to handle message", "msg", msg, "err", err)
+				p.GetP2PPeer().AddScore(p2p.PeerScoreProtocolViolation)
 				errCh <- err
 				return
 			}
@@ -630,6 +701,14 @@ func (pm *ProtocolManager) processMsg(msgCh <-chan p2p.Msg, p Peer, addr common.
 // processConsensusMsg processes the consensus message.
 func (pm *ProtocolManager) processConsensusMsg(msgCh <-chan p2p.Msg, p Peer, addr common.Address, errCh chan<- error) {
 	for msg := range msgCh {
+		if pm.consensusRelay != nil {
+			if err := pm.relayConsensusMsg(p, msg); err != nil {
+				errCh <- err
+				return
+			}
+			msg.Discard()
+			continue
+		}
 		if handler, ok := pm.engine.(consensus.Handler); ok {
 			_, err := handler.HandleMsg(addr, msg)
 			// if msg is istanbul msg, handled is true and err is nil if handle msg is successful.
@@ -658,6 +737,10 @@ func (pm *ProtocolManager) handleMsg(p Peer, addr common.Address, msg p2p.Msg) e
 	//}
 	//defer msg.Discard()
 
+	if pm.consensusRelay != nil && msg.Code == backend.IstanbulMsg {
+		return pm.relayConsensusMsg(p, msg)
+	}
+
 	// istanbul BFT
 	if handler, ok := pm.engine.(consensus.Handler); ok {
 		//pubKey, err := p.ID().Pubkey()
@@ -678,6 +761,10 @@ func (pm *ProtocolManager) handleMsg(p Peer, addr common.Address, msg p2p.Msg) e
 		// Status messages should never arrive after the handshake
 		return errResp(ErrExtraStatusMsg, "uncontrolled status message")
 
+	case p.GetVersion() >= klay67 && msg.Code == ValidatorAddressMsg:
+		// The validator address is exchanged only once right after the handshake
+		return errResp(ErrInvalidValidatorAddress, "uncontrolled validator address message")
+
 		// Block header query, collect the requested headers and reply
 	case msg.Code == BlockHeadersRequestMsg:
 		if err := handleBlockHeadersRequestMsg(pm, p, msg); err != nil {
@@ -764,6 +851,22 @@ func (pm *ProtocolManager) handleMsg(p Peer, addr common.Address, msg p2p.Msg) e
 			return err
 		}
 
+	case p.GetVersion() >= klay66 && msg.Code == NewPooledTransactionHashesMsg:
+		if err := handleNewPooledTransactionHashesMsg(pm, p, msg); err != nil {
+			return err
+		}
+
+	case p.GetVersion() >= klay66 && msg.Code == PooledTransactionsRequestMsg:
+		if err := handlePooledTransactionsRequestMsg(pm, p, msg); err != nil {
+			return err
+		}
+
+	case p.GetVersion() >= klay66 && msg.Code == PooledTransactionsMsg:
+		// The requested transactions are handled in the same way as the broadcast ones.
+		if err := handleTxMsg(pm, p, msg); err != nil {
+			return err
+		}
+
 	default:
 		return errResp(ErrInvalidMsgCode, "%v", msg.Code)
 	}
@@ -1106,6 +1209,9 @@ func handleNewBlockHashesMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
 	// Schedule all the unknown hashes for retrieval
 	for _, block := range announces {
 		p.AddToKnownBlocks(block.Hash)
+		if pm.propagation != nil {
+			pm.propagation.blockAnnounced(p.GetID(), block.Hash)
+		}
 
 		if maxTD < block.Number {
 			maxTD = block.Number
@@ -1200,6 +1306,9 @@ func handleNewBlockMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
 
 	// Mark the peer as owning the block and schedule it for import
 	p.AddToKnownBlocks(request.Block.Hash())
+	if pm.propagation != nil {
+		pm.propagation.blockReceived(p.GetID(), request.Block.Hash())
+	}
 	pm.fetcher.Enqueue(p.GetID(), request.Block)
 
 	// Assuming the block is importable by the peer, but possibly not yet done so,
@@ -1236,6 +1345,7 @@ func handleTxMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
 	}
 	// Only valid txs should be pushed into the pool.
 	validTxs := make(types.Transactions, 0, len(txs))
+	hashes := make([]common.Hash, 0, len(txs))
 	var err error
 	for i, tx := range txs {
 		// Validate and mark the remote transaction
@@ -1245,8 +1355,16 @@ func handleTxMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
 		}
 		p.AddToKnownTxs(tx.Hash())
 		validTxs = append(validTxs, tx)
+		hashes = append(hashes, tx.Hash())
 		txReceiveCounter.Inc(1)
 	}
+	if pm.propagation != nil {
+		pm.propagation.txsReceived(p.GetID(), hashes)
+	}
+	pm.txRequests.delivered(hashes)
+	if p2pPeer := p.GetP2PPeer(); p2pPeer != nil {
+		pm.deliveries.txsDelivered(p2pPeer, validTxs)
+	}
 	pm.txpool.HandleTxMsg(validTxs)
 	return err
 }
@@ -1371,7 +1489,7 @@ func (pm *ProtocolManager) broadcastTxsFromPN(txs types.Transactions) {
 
 	propTxPeersGauge.Update(int64(len(peersWithoutTxs) + len(cnPeersWithoutTxs)))
 	sendTransactions(cnPeersWithoutTxs)
-	sendTransactions(peersWithoutTxs)
+	sendOrAnnounceTransactions(peersWithoutTxs)
 }
 
 func (pm *ProtocolManager) broadcastTxsFromEN(txs types.Transactions) {
@@ -1384,7 +1502,7 @@ func (pm *ProtocolManager) broadcastTxsFromEN(txs types.Transactions) {
 	}
 
 	propTxPeersGauge.Update(int64(len(peersWithoutTxs)))
-	sendTransactions(peersWithoutTxs)
+	sendOrAnnounceTransactions(peersWithoutTxs)
 }
 
 // ReBroadcastTxs sends transactions, not considering whether the peer has the transaction or not.
@@ -1456,6 +1574,7 @@ func (pm *ProtocolManager) txBroadcastLoop() {
 	for {
 		select {
 		case event := <-pm.txsCh:
+			pm.deliveries.txsAdmitted(event.Txs)
 			pm.BroadcastTxs(event.Txs)
 			// Err() channel will be closed when unsubscribing.
 		case <-pm.txsSub.Err():
@@ -1509,18 +1628,31 @@ type NodeInfo struct {
 	Genesis    common.Hash         `json:"genesis"`    // SHA3 hash of the host's genesis block
 	Config     *params.ChainConfig `json:"config"`     // Chain configuration for the fork rules
 	Head       common.Hash         `json:"head"`       // SHA3 hash of the host's best owned block
+	Forks      map[string]bool     `json:"forks"`      // Whether the forks of the chain configuration are enabled at the head
+	DBVersion  *uint64             `json:"dbVersion"`  // Schema version of the chain database
 }
 
 // NodeInfo retrieves some protocol metadata about the running host node.
 func (pm *ProtocolManager) NodeInfo() *NodeInfo {
 	currentBlock := pm.blockchain.CurrentBlock()
-	return &NodeInfo{
+	config := pm.blockchain.Config()
+	info := &NodeInfo{
 		Network:    pm.networkId,
 		BlockScore: pm.blockchain.GetTd(currentBlock.Hash(), currentBlock.NumberU64()),
 		Genesis:    pm.blockchain.Genesis().Hash(),
-		Config:     pm.blockchain.Config(),
+		Config:     config,
 		Head:       currentBlock.Hash(),
+		Forks: map[string]bool{
+			"istanbul":  config.IsIstanbulForkEnabled(currentBlock.Number()),
+			"london":    config.IsLondonForkEnabled(currentBlock.Number()),
+			"ethTxType": config.IsEthTxTypeForkEnabled(currentBlock.Number()),
+			"magma":     config.IsMagmaForkEnabled(currentBlock.Number()),
+		},
 	}
+	if pm.chainDB != nil {
+		info.DBVersion = pm.chainDB.ReadDatabaseVersion()
+	}
+	return info
 }
 
 // Below functions are used in Istanbul BFT consensus.
@@ -1549,6 +1681,9 @@ func (pm *ProtocolManager) FindPeers(targets map[common.Address]bool) map[common
 }
 
 func (pm *ProtocolManager) GetCNPeers() map[common.Address]consensus.Peer {
+	if pm.sentryMode {
+		return pm.sentryPeers()
+	}
 	m := make(map[common.Address]consensus.Peer)
 	for addr, p := range pm.peers.CNPeers() {
 		m[addr] = p
@@ -1557,6 +1692,9 @@ func (pm *ProtocolManager) GetCNPeers() map[common.Address]consensus.Peer {
 }
 
 func (pm *ProtocolManager) FindCNPeers(targets map[common.Address]bool) map[common.Address]consensus.Peer {
+	if pm.sentryMode {
+		return pm.sentryPeers()
+	}
 	m := make(map[common.Address]consensus.Peer)
 	for addr, p := range pm.peers.CNPeers() {
 		if targets[addr] {
@@ -61,6 +61,7 @@ func prepareTestHandleNewBlockMsg(t *testing.T, mockCtrl *gomock.Controller, blo
 
 	mockPeer.EXPECT().AddToKnownBlocks(newBlock.Hash()).Times(1)
 	mockPeer.EXPECT().GetID().Return(nodeids[0].String()).AnyTimes()
+	mockPeer.EXPECT().GetP2PPeer().Return(nil).AnyTimes()
 
 	mockFetcher := mocks2.NewMockProtocolManagerFetcher(mockCtrl)
 	mockFetcher.EXPECT().Enqueue(nodeids[0].String(), newBlock).Times(1)
@@ -218,8 +219,12 @@ func TestHandleNewBlockMsg_LargeLocalPeerBlockScore(t *testing.T) {
 
 	_, msg, mockPeer, mockFetcher := prepareTestHandleNewBlockMsg(t, mockCtrl, blockNum1)
 
+	mockBlockChain := mocks.NewMockBlockChain(mockCtrl)
+	mockBlockChain.EXPECT().HasBlock(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+
 	pm := &ProtocolManager{}
 	pm.fetcher = mockFetcher
+	pm.blockchain = mockBlockChain
 
 	mockPeer.EXPECT().Head().Return(hash1, big.NewInt(blockNum1+1)).AnyTimes()
 
@@ -242,6 +247,7 @@ func TestHandleNewBlockMsg_SmallLocalPeerBlockScore_NoSynchronise(t *testing.T)
 	mockBlockChain := mocks.NewMockBlockChain(mockCtrl)
 	mockBlockChain.EXPECT().CurrentBlock().Return(currBlock).Times(1)
 	mockBlockChain.EXPECT().GetTd(currBlock.Hash(), currBlock.NumberU64()).Return(big.NewInt(blockNum1)).Times(1)
+	mockBlockChain.EXPECT().HasBlock(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
 
 	pm.blockchain = mockBlockChain
 
@@ -273,6 +279,7 @@ func TestHandleTxMsg(t *testing.T) {
 		pm.txpool = mockTxPool
 
 		mockPeer.EXPECT().AddToKnownTxs(txs[0].Hash()).Times(1)
+		mockPeer.EXPECT().GetP2PPeer().Return(nil).AnyTimes()
 		assert.NoError(t, pm.handleMsg(mockPeer, addrs[0], msg))
 	}
 }
@@ -794,6 +794,7 @@ func TestNodeInfo(t *testing.T) {
 		Genesis:    genesis.Hash(),
 		Config:     config,
 		Head:       block.Hash(),
+		Forks:      map[string]bool{"istanbul": false, "london": false, "ethTxType": false, "magma": false},
 	}
 
 	assert.Equal(t, *expected, *pm.NodeInfo())
@@ -1171,5 +1172,8 @@ func createAndRegisterPeers(mockCtrl *gomock.Controller, peers *peerSet) (*MockP
 	peers.peers[fmt.Sprintf("%x", nodeids[1][:8])] = pnPeer
 	peers.peers[fmt.Sprintf("%x", nodeids[2][:8])] = enPeer
 
+	for _, peer := range []*MockPeer{cnPeer, pnPeer, enPeer} {
+		peer.EXPECT().GetVersion().Return(klay65).AnyTimes()
+	}
 	return cnPeer, pnPeer, enPeer
 }
@@ -0,0 +1,170 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/klaytn/klaytn"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+const (
+	DefaultHealthHost = "localhost" // Default host interface for the health endpoint
+	DefaultHealthPort = 8554        // Default TCP port for the health endpoint
+)
+
+// healthCheckKey is read from the database to check that it is readable.
+var healthCheckKey = []byte("HealthCheck")
+
+// HealthConfig is the configuration of the HTTP health endpoint, which serves
+// the liveness at /health/live and the readiness at /health.
+type HealthConfig struct {
+	Enabled bool
+	Host    string
+	Port    int
+
+	// Readiness criteria
+	MinPeers     int           // Minimum number of peers to be ready
+	MaxBlockAge  time.Duration // Maximum age of the head block to be ready, 0 to disable the check
+	AllowSyncing bool          // Whether the node is ready while syncing
+}
+
+var DefaultHealthConfig = HealthConfig{
+	Host:        DefaultHealthHost,
+	Port:        DefaultHealthPort,
+	MinPeers:    1,
+	MaxBlockAge: time.Minute,
+}
+
+// HealthStatus is the health of the node reported by the health endpoint.
+type HealthStatus struct {
+	Live         bool     `json:"live"`
+	Ready        bool     `json:"ready"`
+	Syncing      bool     `json:"syncing"`
+	CurrentBlock uint64   `json:"currentBlock"`
+	HighestBlock uint64   `json:"highestBlock"`
+	Peers        int      `json:"peers"`
+	BlockAge     float64  `json:"blockAge"` // Age of the head block in seconds
+	DBError      string   `json:"dbError,omitempty"`
+	Failures     []string `json:"failures,omitempty"` // Readiness criteria which are not met
+}
+
+// healthChecker serves the health of the node over HTTP on a separate port, so
+// that load balancers and orchestrators can gate the traffic without JSON-RPC.
+type healthChecker struct {
+	config   HealthConfig
+	progress func() klaytn.SyncProgress
+	peers    func() int
+	head     func() *types.Header
+	chainDB  database.DBManager
+
+	server   *http.Server
+	listener net.Listener
+}
+
+func newHealthChecker(config HealthConfig, progress func() klaytn.SyncProgress, peers func() int, head func() *types.Header, chainDB database.DBManager) *healthChecker {
+	return &healthChecker{
+		config:   config,
+		progress: progress,
+		peers:    peers,
+		head:     head,
+		chainDB:  chainDB,
+	}
+}
+
+// status checks the health of the node by the readiness criteria.
+func (h *healthChecker) status(now time.Time) *HealthStatus {
+	status := &HealthStatus{Live: true}
+
+	if _, err := h.chainDB.GetMiscDB().Has(healthCheckKey); err != nil {
+		status.Live = false
+		status.DBError = err.Error()
+		status.Failures = append(status.Failures, "database is not readable")
+	}
+
+	progress := h.progress()
+	status.CurrentBlock, status.HighestBlock = progress.CurrentBlock, progress.HighestBlock
+	status.Syncing = progress.CurrentBlock < progress.HighestBlock
+	if status.Syncing && !h.config.AllowSyncing {
+		status.Failures = append(status.Failures, fmt.Sprintf("syncing (%d/%d)", progress.CurrentBlock, progress.HighestBlock))
+	}
+
+	status.Peers = h.peers()
+	if status.Peers < h.config.MinPeers {
+		status.Failures = append(status.Failures, fmt.Sprintf("too few peers (%d < %d)", status.Peers, h.config.MinPeers))
+	}
+
+	if head := h.head(); head != nil {
+		status.BlockAge = now.Sub(time.Unix(head.Time.Int64(), 0)).Seconds()
+		if h.config.MaxBlockAge > 0 && status.BlockAge > h.config.MaxBlockAge.Seconds() {
+			status.Failures = append(status.Failures, fmt.Sprintf("head block is too old (%.0fs > %v)", status.BlockAge, h.config.MaxBlockAge))
+		}
+	}
+
+	status.Ready = len(status.Failures) == 0
+	return status
+}
+
+func (h *healthChecker) handleReady(w http.ResponseWriter, r *http.Request) {
+	status := h.status(time.Now())
+	writeHealthStatus(w, status, status.Ready)
+}
+
+func (h *healthChecker) handleLive(w http.ResponseWriter, r *http.Request) {
+	status := h.status(time.Now())
+	writeHealthStatus(w, status, status.Live)
+}
+
+func writeHealthStatus(w http.ResponseWriter, status *HealthStatus, healthy bool) {
+	w.Header().Set("Content-Type", "application/json")
+	if healthy {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(status)
+}
+
+func (h *healthChecker) start() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", h.handleReady)
+	mux.HandleFunc("/health/live", h.handleLive)
+
+	endpoint := net.JoinHostPort(h.config.Host, fmt.Sprint(h.config.Port))
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		return err
+	}
+	h.listener = listener
+	h.server = &http.Server{Handler: mux, ReadTimeout: 5 * time.Second, WriteTimeout: 5 * time.Second}
+	go h.server.Serve(listener)
+	logger.Info("Health endpoint opened", "url", fmt.Sprintf("http://%s/health", listener.Addr()))
+	return nil
+}
+
+func (h *healthChecker) stop() {
+	if h.server != nil {
+		h.server.Close()
+		logger.Info("Health endpoint closed", "url", fmt.Sprintf("http://%s/health", h.listener.Addr()))
+	}
+}
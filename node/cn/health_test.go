@@ -0,0 +1,94 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthChecker_Status(t *testing.T) {
+	now := time.Unix(1000, 0)
+	var (
+		progress = klaytn.SyncProgress{CurrentBlock: 100, HighestBlock: 100}
+		peers    = 1
+		head     = &types.Header{Number: big.NewInt(100), Time: big.NewInt(now.Unix() - 5)}
+	)
+	h := newHealthChecker(DefaultHealthConfig,
+		func() klaytn.SyncProgress { return progress },
+		func() int { return peers },
+		func() *types.Header { return head },
+		database.NewMemoryDBManager())
+
+	status := h.status(now)
+	assert.True(t, status.Live)
+	assert.True(t, status.Ready)
+	assert.Empty(t, status.Failures)
+	assert.Equal(t, 5.0, status.BlockAge)
+
+	// Syncing, no peers and an old head block make the node not ready, but still live.
+	progress.HighestBlock = 200
+	peers = 0
+	head = &types.Header{Number: big.NewInt(100), Time: big.NewInt(now.Unix() - 120)}
+	status = h.status(now)
+	assert.True(t, status.Live)
+	assert.False(t, status.Ready)
+	assert.True(t, status.Syncing)
+	assert.Len(t, status.Failures, 3)
+
+	// The readiness criteria are configurable.
+	h.config.AllowSyncing = true
+	h.config.MinPeers = 0
+	h.config.MaxBlockAge = 0
+	assert.True(t, h.status(now).Ready)
+}
+
+func TestHealthChecker_Handlers(t *testing.T) {
+	peers := 0
+	h := newHealthChecker(DefaultHealthConfig,
+		func() klaytn.SyncProgress { return klaytn.SyncProgress{} },
+		func() int { return peers },
+		func() *types.Header { return &types.Header{Number: big.NewInt(0), Time: big.NewInt(time.Now().Unix())} },
+		database.NewMemoryDBManager())
+
+	rec := httptest.NewRecorder()
+	h.handleReady(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var status HealthStatus
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.False(t, status.Ready)
+	assert.Equal(t, 0, status.Peers)
+
+	rec = httptest.NewRecorder()
+	h.handleLive(rec, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	peers = 1
+	rec = httptest.NewRecorder()
+	h.handleReady(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
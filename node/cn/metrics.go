@@ -64,6 +64,8 @@ var (
 	txReceiveCounter                     = metrics.NewRegisteredCounter("klay/tx/recv/counter", nil)
 	txResendCounter                      = metrics.NewRegisteredCounter("klay/tx/resend/counter", nil)
 	txSendCounter                        = metrics.NewRegisteredCounter("klay/tx/send/counter", nil)
+	txAnnounceCounter                    = metrics.NewRegisteredCounter("klay/tx/announce/counter", nil)
+	txRerequestCounter                   = metrics.NewRegisteredCounter("klay/tx/rerequest/counter", nil)
 	txResendRoutineGauge                 = metrics.NewRegisteredGauge("klay/tx/resend/routine/gauge", nil)
 	cnPeerCountGauge                     = metrics.NewRegisteredGauge("p2p/CNPeerCountGauge", nil)
 	pnPeerCountGauge                     = metrics.NewRegisteredGauge("p2p/PNPeerCountGauge", nil)
@@ -72,6 +74,16 @@ var (
 	propConsensusIstanbulInTrafficMeter  = metrics.NewRegisteredMeter("klay/prop/consensus/istanbul/in/traffic", nil)
 	propConsensusIstanbulOutPacketsMeter = metrics.NewRegisteredMeter("klay/prop/consensus/istanbul/out/packets", nil)
 	propConsensusIstanbulOutTrafficMeter = metrics.NewRegisteredMeter("klay/prop/consensus/istanbul/out/traffic", nil)
+	channelQueueFullCounter              = metrics.NewRegisteredCounter("klay/channel/queue/full/counter", nil)
+	consensusRelayCounter                = metrics.NewRegisteredCounter("klay/consensus/relay/counter", nil)
+	consensusRelayDropCounter            = metrics.NewRegisteredCounter("klay/consensus/relay/drop/counter", nil)
+	consensusRelayFailCounter            = metrics.NewRegisteredCounter("klay/consensus/relay/fail/counter", nil)
+	consensusRelayUntrustedCounter       = metrics.NewRegisteredCounter("klay/consensus/relay/untrusted/counter", nil)
+	proxyFailoverCounter                 = metrics.NewRegisteredCounter("klay/proxy/failover/counter", nil)
+	proxyFencedCounter                   = metrics.NewRegisteredCounter("klay/proxy/fenced/counter", nil)
+	blockPropAnnounceToReceiveTimer      = metrics.NewRegisteredTimer("klay/prop/block/announce2recv", nil)
+	blockPropDelayTimer                  = metrics.NewRegisteredTimer("klay/prop/block/delay", nil)
+	txPropDelayTimer                     = metrics.NewRegisteredTimer("klay/prop/tx/delay", nil)
 )
 
 // meteredMsgReadWriter is a wrapper around a p2p.MsgReadWriter, capable of
@@ -0,0 +1,483 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/types/accountkey"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/rlp"
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+// MultisigConfig is the configuration of the multisig coordinator, which keeps
+// the partially-signed transactions until the signatures of the co-signers
+// satisfy the account keys, then sends them.
+type MultisigConfig struct {
+	Enabled      bool
+	Lifetime     time.Duration // Time a proposal is kept after it is created
+	MaxProposals int           // Maximum number of the proposals kept
+}
+
+var DefaultMultisigConfig = MultisigConfig{
+	Lifetime:     24 * time.Hour,
+	MaxProposals: 1024,
+}
+
+// multisigProposalPrefix is the prefix of the proposals in the misc database.
+var multisigProposalPrefix = []byte("MultisigProposal-")
+
+var (
+	errMultisigEthereumTx    = errors.New("the Ethereum transactions have no multiple signatures, use a Klaytn transaction type")
+	errMultisigTooMany       = errors.New("too many multisig proposals")
+	errMultisigUnknown       = errors.New("unknown multisig proposal")
+	errMultisigUnsignableKey = errors.New("the account key cannot sign the role")
+)
+
+// MultisigSignerStatus is the signatures collected for an account, the sender or
+// the fee payer of a proposal.
+type MultisigSignerStatus struct {
+	Address   common.Address   `json:"address"`
+	Signers   []common.Address `json:"signers"` // Addresses of the keys which have signed
+	Weight    uint             `json:"weight"`  // Weighted sum of the keys which have signed
+	Threshold uint             `json:"threshold"`
+}
+
+// MultisigStatus is the status of a proposal.
+type MultisigStatus struct {
+	Hash     common.Hash            `json:"hash"` // Signing hash of the sender, identifying the proposal
+	Tx       map[string]interface{} `json:"tx"`
+	Raw      hexutil.Bytes          `json:"raw"`
+	Sender   MultisigSignerStatus   `json:"sender"`
+	FeePayer *MultisigSignerStatus  `json:"feePayer,omitempty"`
+	Complete bool                   `json:"complete"`
+	TxHash   *common.Hash           `json:"txHash,omitempty"` // Set once the transaction is sent
+	Expires  hexutil.Uint64         `json:"expires"`          // Unix time when the proposal is dropped
+}
+
+// multisigBackend is the part of the API backend used by the coordinator.
+type multisigBackend interface {
+	ChainConfig() *params.ChainConfig
+	StateAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*state.StateDB, *types.Header, error)
+	SendTx(ctx context.Context, signedTx *types.Transaction) error
+}
+
+// multisigProposal is a partially-signed transaction.
+type multisigProposal struct {
+	Tx      *types.Transaction
+	Created uint64      // Unix time
+	TxHash  common.Hash // Hash of the sent transaction, empty until it is sent
+}
+
+type multisigCoordinator struct {
+	config  MultisigConfig
+	backend multisigBackend
+	db      database.Database
+
+	mu        sync.Mutex
+	proposals map[common.Hash]*multisigProposal
+}
+
+func newMultisigCoordinator(config MultisigConfig, backend multisigBackend, db database.Database) *multisigCoordinator {
+	c := &multisigCoordinator{
+		config:    config,
+		backend:   backend,
+		db:        db,
+		proposals: make(map[common.Hash]*multisigProposal),
+	}
+	it := db.NewIterator(multisigProposalPrefix, nil)
+	defer it.Release()
+	for it.Next() {
+		p := new(multisigProposal)
+		if err := rlp.DecodeBytes(it.Value(), p); err != nil {
+			logger.Warn("Dropped an undecodable multisig proposal", "key", common.Bytes2Hex(it.Key()), "err", err)
+			continue
+		}
+		c.proposals[common.BytesToHash(it.Key()[len(multisigProposalPrefix):])] = p
+	}
+	c.expire(time.Now())
+	return c
+}
+
+func (c *multisigCoordinator) signer() types.Signer {
+	return types.LatestSignerForChainID(c.backend.ChainConfig().ChainID)
+}
+
+// expire drops the proposals older than the lifetime.
+func (c *multisigCoordinator) expire(now time.Time) {
+	for hash, p := range c.proposals {
+		if now.Sub(time.Unix(int64(p.Created), 0)) > c.config.Lifetime {
+			c.delete(hash)
+		}
+	}
+}
+
+func (c *multisigCoordinator) store(hash common.Hash, p *multisigProposal) error {
+	enc, err := rlp.EncodeToBytes(p)
+	if err != nil {
+		return err
+	}
+	c.proposals[hash] = p
+	return c.db.Put(append(common.CopyBytes(multisigProposalPrefix), hash[:]...), enc)
+}
+
+func (c *multisigCoordinator) delete(hash common.Hash) {
+	delete(c.proposals, hash)
+	if err := c.db.Delete(append(common.CopyBytes(multisigProposalPrefix), hash[:]...)); err != nil {
+		logger.Warn("Failed to delete a multisig proposal", "hash", hash, "err", err)
+	}
+}
+
+// submit adds the signatures of the transaction to its proposal, creating it if
+// needed, and sends the transaction once the signatures satisfy the keys of the
+// sender and the fee payer.
+func (c *multisigCoordinator) submit(ctx context.Context, tx *types.Transaction) (*MultisigStatus, error) {
+	if tx.IsEthereumTransaction() {
+		return nil, errMultisigEthereumTx
+	}
+	signer := c.signer()
+	hash := signer.Hash(tx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.expire(now)
+	p, ok := c.proposals[hash]
+	if !ok {
+		if len(c.proposals) >= c.config.MaxProposals {
+			return nil, errMultisigTooMany
+		}
+		p = &multisigProposal{Tx: tx, Created: uint64(now.Unix())}
+		tx = nil // The proposal starts with its signatures
+	}
+	statedb, header, err := c.backend.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if p.TxHash != (common.Hash{}) {
+		return c.status(hash, p, statedb, header)
+	}
+
+	// Merge the signatures, keeping the valid ones of the keys not signed yet.
+	merged, err := copyTx(p.Tx)
+	if err != nil {
+		return nil, err
+	}
+	sigs := merged.RawSignatureValues()
+	if tx != nil {
+		sigs = append(sigs, tx.RawSignatureValues()...)
+	}
+	if sigs, _, err = c.senderSignatures(merged, statedb, sigs); err != nil {
+		return nil, err
+	}
+	merged.SetSignature(sigs)
+
+	if merged.IsFeeDelegatedTransaction() {
+		sigs, _ := merged.GetFeePayerSignatures()
+		if tx != nil {
+			incoming, _ := tx.GetFeePayerSignatures()
+			sigs = append(sigs, incoming...)
+		}
+		if sigs, _, err = c.feePayerSignatures(merged, statedb, sigs); err != nil {
+			return nil, err
+		}
+		if err := merged.SetFeePayerSignatures(sigs); err != nil {
+			return nil, err
+		}
+	}
+	if p.Tx, err = copyTx(merged); err != nil {
+		return nil, err
+	}
+
+	if c.complete(p.Tx, statedb, header) {
+		if err := c.backend.SendTx(ctx, p.Tx); err != nil {
+			return nil, fmt.Errorf("the signatures are complete but the transaction is not sent: %v", err)
+		}
+		p.TxHash = p.Tx.Hash()
+		logger.Info("Sent a multisig transaction", "hash", hash, "txHash", p.TxHash)
+	}
+	if err := c.store(hash, p); err != nil {
+		return nil, err
+	}
+	return c.status(hash, p, statedb, header)
+}
+
+// complete returns true if the signatures of the transaction are valid for the
+// account keys of the sender and the fee payer.
+func (c *multisigCoordinator) complete(tx *types.Transaction, statedb *state.StateDB, header *types.Header) bool {
+	signer := c.signer()
+	if _, err := tx.ValidateSender(signer, statedb, header.Number.Uint64()); err != nil {
+		return false
+	}
+	if tx.IsFeeDelegatedTransaction() {
+		if _, err := tx.ValidateFeePayer(signer, statedb, header.Number.Uint64()); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// status returns the status of the proposal.
+func (c *multisigCoordinator) status(hash common.Hash, p *multisigProposal, statedb *state.StateDB, header *types.Header) (*MultisigStatus, error) {
+	raw, err := rlp.EncodeToBytes(p.Tx)
+	if err != nil {
+		return nil, err
+	}
+	status := &MultisigStatus{
+		Hash:    hash,
+		Tx:      p.Tx.MakeRPCOutput(),
+		Raw:     raw,
+		Expires: hexutil.Uint64(p.Created + uint64(c.config.Lifetime/time.Second)),
+	}
+	if _, status.Sender, err = c.senderSignatures(p.Tx, statedb, p.Tx.RawSignatureValues()); err != nil {
+		return nil, err
+	}
+	if p.Tx.IsFeeDelegatedTransaction() {
+		sigs, _ := p.Tx.GetFeePayerSignatures()
+		_, feePayerStatus, err := c.feePayerSignatures(p.Tx, statedb, sigs)
+		if err != nil {
+			return nil, err
+		}
+		status.FeePayer = &feePayerStatus
+	}
+	if p.TxHash != (common.Hash{}) {
+		txHash := p.TxHash
+		status.TxHash, status.Complete = &txHash, true
+	} else {
+		status.Complete = c.complete(p.Tx, statedb, header)
+	}
+	return status, nil
+}
+
+func (c *multisigCoordinator) get(ctx context.Context, hash common.Hash) (*MultisigStatus, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expire(time.Now())
+	p, ok := c.proposals[hash]
+	if !ok {
+		return nil, errMultisigUnknown
+	}
+	statedb, header, err := c.backend.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return c.status(hash, p, statedb, header)
+}
+
+func (c *multisigCoordinator) list() []common.Hash {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expire(time.Now())
+	hashes := make([]common.Hash, 0, len(c.proposals))
+	for hash := range c.proposals {
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		return c.proposals[hashes[i]].Created < c.proposals[hashes[j]].Created
+	})
+	return hashes
+}
+
+func (c *multisigCoordinator) discard(hash common.Hash) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.proposals[hash]; !ok {
+		return false
+	}
+	c.delete(hash)
+	return true
+}
+
+// senderSignatures returns the valid signatures of the sender among sigs with
+// their status.
+func (c *multisigCoordinator) senderSignatures(tx *types.Transaction, statedb *state.StateDB, sigs types.TxSignatures) (types.TxSignatures, MultisigSignerStatus, error) {
+	from, err := tx.From()
+	if err != nil {
+		return nil, MultisigSignerStatus{}, err
+	}
+	signer := c.signer()
+	return collectSignatures(statedb.GetKey(from), tx.GetRoleTypeForValidation(), from, sigs, func(sig *types.TxSignature) ([]*ecdsa.PublicKey, error) {
+		cpy, err := copyTx(tx)
+		if err != nil {
+			return nil, err
+		}
+		cpy.SetSignature(types.TxSignatures{sig})
+		return signer.SenderPubkey(cpy)
+	})
+}
+
+// feePayerSignatures returns the valid signatures of the fee payer among sigs
+// with their status.
+func (c *multisigCoordinator) feePayerSignatures(tx *types.Transaction, statedb *state.StateDB, sigs types.TxSignatures) (types.TxSignatures, MultisigSignerStatus, error) {
+	feePayer, err := tx.FeePayer()
+	if err != nil {
+		return nil, MultisigSignerStatus{}, err
+	}
+	signer := c.signer()
+	return collectSignatures(statedb.GetKey(feePayer), accountkey.RoleFeePayer, feePayer, sigs, func(sig *types.TxSignature) ([]*ecdsa.PublicKey, error) {
+		cpy, err := copyTx(tx)
+		if err != nil {
+			return nil, err
+		}
+		if err := cpy.SetFeePayerSignatures(types.TxSignatures{sig}); err != nil {
+			return nil, err
+		}
+		return signer.SenderFeePayer(cpy)
+	})
+}
+
+// copyTx returns a copy of the transaction sharing no data with it.
+func copyTx(tx *types.Transaction) (*types.Transaction, error) {
+	enc, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	cpy := new(types.Transaction)
+	return cpy, rlp.DecodeBytes(enc, cpy)
+}
+
+// roleKey returns the key of the role in the account key.
+func roleKey(key accountkey.AccountKey, role accountkey.RoleType) accountkey.AccountKey {
+	if roleBased, ok := key.(*accountkey.AccountKeyRoleBased); ok {
+		if len(*roleBased) > int(role) {
+			return (*roleBased)[role]
+		}
+		return (*roleBased)[accountkey.RoleTransaction]
+	}
+	return key
+}
+
+// collectSignatures returns the signatures of the distinct keys of the role,
+// dropping the invalid and the duplicated ones, with their status.
+func collectSignatures(key accountkey.AccountKey, role accountkey.RoleType, addr common.Address, sigs types.TxSignatures,
+	recover func(sig *types.TxSignature) ([]*ecdsa.PublicKey, error)) (types.TxSignatures, MultisigSignerStatus, error) {
+	status := MultisigSignerStatus{Address: addr, Signers: []common.Address{}}
+
+	key = roleKey(key, role)
+	weightOf := func(pub *ecdsa.PublicKey) uint { return 0 }
+	switch k := key.(type) {
+	case *accountkey.AccountKeyLegacy:
+		status.Threshold = 1
+		weightOf = func(pub *ecdsa.PublicKey) uint {
+			if crypto.PubkeyToAddress(*pub) == addr {
+				return 1
+			}
+			return 0
+		}
+	case *accountkey.AccountKeyPublic:
+		status.Threshold = 1
+		weightOf = func(pub *ecdsa.PublicKey) uint {
+			if k.PublicKeySerializable.Equal((*accountkey.PublicKeySerializable)(pub)) {
+				return 1
+			}
+			return 0
+		}
+	case *accountkey.AccountKeyWeightedMultiSig:
+		status.Threshold = k.Threshold
+		weightOf = func(pub *ecdsa.PublicKey) uint {
+			for _, wk := range k.Keys {
+				if wk.Key.Equal((*accountkey.PublicKeySerializable)(pub)) {
+					return wk.Weight
+				}
+			}
+			return 0
+		}
+	default:
+		return nil, status, fmt.Errorf("%w: key type %d", errMultisigUnsignableKey, key.Type())
+	}
+
+	var valid types.TxSignatures
+	for _, sig := range sigs {
+		pubs, err := recover(sig)
+		if err != nil || len(pubs) != 1 {
+			continue
+		}
+		signer := crypto.PubkeyToAddress(*pubs[0])
+		weight := weightOf(pubs[0])
+		if weight == 0 || containsAddress(status.Signers, signer) {
+			continue
+		}
+		valid = append(valid, sig)
+		status.Signers = append(status.Signers, signer)
+		status.Weight += weight
+	}
+	return valid, status, nil
+}
+
+func containsAddress(addrs []common.Address, addr common.Address) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// PublicMultisigAPI collects the signatures of the co-signers of the weighted
+// multisig accounts, and sends the transactions once they are complete.
+type PublicMultisigAPI struct {
+	c *multisigCoordinator
+}
+
+// Submit adds the signatures of the RLP-encoded transaction, signed by some of
+// the keys of the sender or the fee payer, to its proposal. The proposal is
+// created by the first submission, and the transaction is sent when the
+// signatures satisfy the account keys.
+func (api *PublicMultisigAPI) Submit(ctx context.Context, raw hexutil.Bytes) (*MultisigStatus, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(raw, tx); err != nil {
+		return nil, err
+	}
+	return api.c.submit(ctx, tx)
+}
+
+// GetProposal returns the status of the proposal.
+func (api *PublicMultisigAPI) GetProposal(ctx context.Context, hash common.Hash) (*MultisigStatus, error) {
+	return api.c.get(ctx, hash)
+}
+
+// Proposals returns the hashes of the proposals from the oldest.
+func (api *PublicMultisigAPI) Proposals() []common.Hash {
+	return api.c.list()
+}
+
+// PrivateMultisigAPI manages the proposals of the multisig coordinator.
+type PrivateMultisigAPI struct {
+	c *multisigCoordinator
+}
+
+// Discard drops the proposal, returning false if it is unknown.
+func (api *PrivateMultisigAPI) Discard(hash common.Hash) bool {
+	return api.c.discard(hash)
+}
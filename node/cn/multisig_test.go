@@ -0,0 +1,135 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/types/accountkey"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/fork"
+	"github.com/klaytn/klaytn/networks/rpc"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/rlp"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testMultisigBackend struct {
+	config  *params.ChainConfig
+	statedb *state.StateDB
+	sent    []*types.Transaction
+}
+
+func (b *testMultisigBackend) ChainConfig() *params.ChainConfig { return b.config }
+
+func (b *testMultisigBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	return b.statedb, &types.Header{Number: big.NewInt(1)}, nil
+}
+
+func (b *testMultisigBackend) SendTx(ctx context.Context, tx *types.Transaction) error {
+	b.sent = append(b.sent, tx)
+	return nil
+}
+
+func TestMultisigCoordinator_Submit(t *testing.T) {
+	fork.SetHardForkBlockNumberConfig(&params.ChainConfig{})
+	defer fork.ClearHardForkBlockNumberConfig()
+
+	var keys []*ecdsa.PrivateKey
+	var weighted accountkey.WeightedPublicKeys
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		keys = append(keys, key)
+		weighted = append(weighted, accountkey.NewWeightedPublicKey(1, (*accountkey.PublicKeySerializable)(&key.PublicKey)))
+	}
+	from := common.HexToAddress("0x1234")
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(database.NewMemoryDBManager()), nil)
+	require.NoError(t, err)
+	statedb.CreateEOA(from, false, accountkey.NewAccountKeyWeightedMultiSigWithValues(2, weighted))
+
+	backend := &testMultisigBackend{config: &params.ChainConfig{ChainID: big.NewInt(1001)}, statedb: statedb}
+	db := database.NewMemoryDBManager().GetMiscDB()
+	c := newMultisigCoordinator(DefaultMultisigConfig, backend, db)
+	signer := types.LatestSignerForChainID(backend.config.ChainID)
+
+	// signed returns the transaction signed by the keys, as a co-signer submits it.
+	signed := func(keys ...*ecdsa.PrivateKey) *types.Transaction {
+		tx, err := types.NewTransactionWithMap(types.TxTypeValueTransfer, map[types.TxValueKeyType]interface{}{
+			types.TxValueKeyNonce:    uint64(0),
+			types.TxValueKeyFrom:     from,
+			types.TxValueKeyTo:       common.HexToAddress("0x5678"),
+			types.TxValueKeyAmount:   big.NewInt(1),
+			types.TxValueKeyGasLimit: uint64(100000),
+			types.TxValueKeyGasPrice: big.NewInt(25e9),
+		})
+		require.NoError(t, err)
+		require.NoError(t, tx.SignWithKeys(signer, keys))
+		enc, err := rlp.EncodeToBytes(tx)
+		require.NoError(t, err)
+		decoded := new(types.Transaction)
+		require.NoError(t, rlp.DecodeBytes(enc, decoded))
+		return decoded
+	}
+
+	// The first signature creates the proposal.
+	status, err := c.submit(context.Background(), signed(keys[0]))
+	require.NoError(t, err)
+	assert.False(t, status.Complete)
+	assert.Equal(t, uint(1), status.Sender.Weight)
+	assert.Equal(t, uint(2), status.Sender.Threshold)
+	assert.Equal(t, []common.Hash{status.Hash}, c.list())
+
+	// A duplicated signature and the signature of an unknown key are dropped.
+	stranger, _ := crypto.GenerateKey()
+	status, err = c.submit(context.Background(), signed(keys[0], stranger))
+	require.NoError(t, err)
+	assert.False(t, status.Complete)
+	assert.Equal(t, []common.Address{crypto.PubkeyToAddress(keys[0].PublicKey)}, status.Sender.Signers)
+	assert.Empty(t, backend.sent)
+
+	// The proposal is persisted.
+	reloaded := newMultisigCoordinator(DefaultMultisigConfig, backend, db)
+	assert.Equal(t, []common.Hash{status.Hash}, reloaded.list())
+
+	// The second signature satisfies the threshold, sending the transaction.
+	status, err = c.submit(context.Background(), signed(keys[2]))
+	require.NoError(t, err)
+	assert.True(t, status.Complete)
+	assert.Equal(t, uint(2), status.Sender.Weight)
+	require.Len(t, backend.sent, 1)
+	require.NotNil(t, status.TxHash)
+	assert.Equal(t, backend.sent[0].Hash(), *status.TxHash)
+	assert.Len(t, backend.sent[0].RawSignatureValues(), 2)
+
+	// The sent transaction is not sent again.
+	_, err = c.submit(context.Background(), signed(keys[1]))
+	require.NoError(t, err)
+	assert.Len(t, backend.sent, 1)
+
+	assert.True(t, c.discard(status.Hash))
+	assert.Empty(t, c.list())
+	_, err = c.get(context.Background(), status.Hash)
+	assert.Equal(t, errMultisigUnknown, err)
+}
@@ -124,6 +124,17 @@ type Peer interface {
 	// AsyncSendTransactions sends transactions asynchronously to the peer.
 	AsyncSendTransactions(txs types.Transactions)
 
+	// SendPooledTransactionHashes announces the availability of transactions
+	// through hash notifications and includes the hashes in its transaction
+	// hash set for future reference.
+	SendPooledTransactionHashes(hashes []common.Hash) error
+
+	// RequestPooledTransactions fetches the announced transactions from the peer.
+	RequestPooledTransactions(hashes []common.Hash) error
+
+	// SendPooledTransactions sends the transactions requested by the peer.
+	SendPooledTransactions(txs types.Transactions) error
+
 	// SendNewBlockHashes announces the availability of a number of blocks through
 	// a hash notification.
 	SendNewBlockHashes(hashes []common.Hash, numbers []uint64) error
@@ -182,6 +193,10 @@ type Peer interface {
 	// network IDs, difficulties, head, and genesis blocks and returning error.
 	Handshake(network uint64, chainID, td *big.Int, head common.Hash, genesis common.Hash) error
 
+	// HandshakeValidatorAddress exchanges the validator addresses with the peer
+	// and sets the verified validator address of the peer.
+	HandshakeValidatorAddress(local *validatorAddressData) error
+
 	// ConnType returns the conntype of the peer.
 	ConnType() common.ConnType
 
@@ -325,6 +340,14 @@ var ChannelOfMessage = map[uint64]int{
 	// Protocol messages belonging to klay/65
 	StakingInfoRequestMsg: p2p.ConnDefault,
 	StakingInfoMsg:        p2p.ConnDefault,
+
+	// Protocol messages belonging to klay/66
+	NewPooledTransactionHashesMsg: p2p.ConnTxMsg,
+	PooledTransactionsRequestMsg:  p2p.ConnTxMsg,
+	PooledTransactionsMsg:         p2p.ConnTxMsg,
+
+	// Protocol messages belonging to klay/67
+	ValidatorAddressMsg: p2p.ConnDefault,
 }
 
 var ConcurrentOfChannel = []int{
@@ -487,6 +510,30 @@ func (p *basePeer) AsyncSendTransactions(txs types.Transactions) {
 	}
 }
 
+// SendPooledTransactionHashes announces the availability of transactions
+// through hash notifications and includes the hashes in its transaction
+// hash set for future reference.
+func (p *basePeer) SendPooledTransactionHashes(hashes []common.Hash) error {
+	for _, hash := range hashes {
+		p.AddToKnownTxs(hash)
+	}
+	return p2p.Send(p.rw, NewPooledTransactionHashesMsg, hashes)
+}
+
+// RequestPooledTransactions fetches the announced transactions from the peer.
+func (p *basePeer) RequestPooledTransactions(hashes []common.Hash) error {
+	p.Log().Debug("Fetching batch of pooled transactions", "count", len(hashes))
+	return p2p.Send(p.rw, PooledTransactionsRequestMsg, hashes)
+}
+
+// SendPooledTransactions sends the transactions requested by the peer.
+func (p *basePeer) SendPooledTransactions(txs types.Transactions) error {
+	for _, tx := range txs {
+		p.AddToKnownTxs(tx.Hash())
+	}
+	return p2p.Send(p.rw, PooledTransactionsMsg, txs)
+}
+
 // SendNewBlockHashes announces the availability of a number of blocks through
 // a hash notification.
 func (p *basePeer) SendNewBlockHashes(hashes []common.Hash, numbers []uint64) error {
@@ -695,6 +742,85 @@ func (p *basePeer) readStatus(network uint64, status *statusData, genesis common
 	return nil
 }
 
+// HandshakeValidatorAddress exchanges the validator addresses with the peer
+// after the Klaytn protocol handshake. If the peer validates with a key other
+// than its node key, the verified validator address is set as the address of
+// the peer. The peers older than klay/67 are identified by their node keys.
+func (p *basePeer) HandshakeValidatorAddress(local *validatorAddressData) error {
+	if p.version < klay67 {
+		return nil
+	}
+	errc := make(chan error, 2)
+	var remote validatorAddressData // safe to read after two values have been received from errc
+
+	go func() {
+		errc <- p2p.Send(p.rw, ValidatorAddressMsg, local)
+	}()
+	go func() {
+		errc <- p.readValidatorAddress(&remote)
+	}()
+	timeout := time.NewTimer(handshakeTimeout)
+	defer timeout.Stop()
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errc:
+			if err != nil {
+				return err
+			}
+		case <-timeout.C:
+			return p2p.DiscReadTimeout
+		}
+	}
+	if remote.Address != (common.Address{}) {
+		p.addr = remote.Address
+	}
+	return nil
+}
+
+func (p *basePeer) readValidatorAddress(data *validatorAddressData) error {
+	msg, err := p.rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	if msg.Code != ValidatorAddressMsg {
+		return errResp(ErrNoValidatorAddressMsg, "msg has code %x (!= %x)", msg.Code, ValidatorAddressMsg)
+	}
+	if msg.Size > ProtocolMaxMsgSize {
+		return errResp(ErrMsgTooLarge, "%v > %v", msg.Size, ProtocolMaxMsgSize)
+	}
+	if err := msg.Decode(data); err != nil {
+		return errResp(ErrDecode, "msg %v: %v", msg, err)
+	}
+	return verifyValidatorAddress(p.GetP2PPeerID(), data)
+}
+
+// signValidatorAddress returns the validator address packet of the node of the
+// given ID, signed by the validator key with signFn. signFn hashes the data
+// before signing like istanbul.Backend.Sign.
+func signValidatorAddress(id discover.NodeID, addr common.Address, signFn func(data []byte) ([]byte, error)) (*validatorAddressData, error) {
+	sig, err := signFn(append(id[:], addr[:]...))
+	if err != nil {
+		return nil, err
+	}
+	return &validatorAddressData{Address: addr, Signature: sig}, nil
+}
+
+// verifyValidatorAddress checks that the validator address is signed by its
+// key over the node ID. An empty address stands for the node key itself.
+func verifyValidatorAddress(id discover.NodeID, data *validatorAddressData) error {
+	if data.Address == (common.Address{}) {
+		return nil
+	}
+	pubKey, err := crypto.SigToPub(crypto.Keccak256(id[:], data.Address[:]), data.Signature)
+	if err != nil {
+		return errResp(ErrInvalidValidatorAddress, "%v", err)
+	}
+	if signer := crypto.PubkeyToAddress(*pubKey); signer != data.Address {
+		return errResp(ErrInvalidValidatorAddress, "signed by %x (!= %x)", signer, data.Address)
+	}
+	return nil
+}
+
 // String implements fmt.Stringer.
 func (p *basePeer) String() string {
 	return fmt.Sprintf("Peer %s [%s]", p.id,
@@ -810,9 +936,11 @@ func (p *multiChannelPeer) RegisterMsgCode(channelId uint, msgCode uint64) {
 	p.chMgr.RegisterMsgCode(channelId, msgCode)
 }
 
-// RegisterConsensusMsgCode registers the channel of consensus msg.
+// RegisterConsensusMsgCode registers the channel of consensus msg and sends
+// the consensus msg without the egress bandwidth throttling.
 func (p *multiChannelPeer) RegisterConsensusMsgCode(msgCode uint64) error {
 	p.chMgr.RegisterMsgCode(ConsensusChannel, msgCode)
+	p.GetP2PPeer().ExemptFromEgressLimits(msgCode)
 	return nil
 }
 
@@ -877,6 +1005,30 @@ func (p *multiChannelPeer) ReSendTransactions(txs types.Transactions) error {
 	return p.msgSender(TxMsg, txs)
 }
 
+// SendPooledTransactionHashes announces the availability of transactions
+// through hash notifications and includes the hashes in its transaction
+// hash set for future reference.
+func (p *multiChannelPeer) SendPooledTransactionHashes(hashes []common.Hash) error {
+	for _, hash := range hashes {
+		p.AddToKnownTxs(hash)
+	}
+	return p.msgSender(NewPooledTransactionHashesMsg, hashes)
+}
+
+// RequestPooledTransactions fetches the announced transactions from the peer.
+func (p *multiChannelPeer) RequestPooledTransactions(hashes []common.Hash) error {
+	p.Log().Debug("Fetching batch of pooled transactions", "count", len(hashes))
+	return p.msgSender(PooledTransactionsRequestMsg, hashes)
+}
+
+// SendPooledTransactions sends the transactions requested by the peer.
+func (p *multiChannelPeer) SendPooledTransactions(txs types.Transactions) error {
+	for _, tx := range txs {
+		p.AddToKnownTxs(tx.Hash())
+	}
+	return p.msgSender(PooledTransactionsMsg, txs)
+}
+
 // SendNewBlockHashes announces the availability of a number of blocks through
 // a hash notification.
 func (p *multiChannelPeer) SendNewBlockHashes(hashes []common.Hash, numbers []uint64) error {
@@ -1071,6 +1223,9 @@ func (p *multiChannelPeer) ReadMsg(rw p2p.MsgReadWriter, connectionOrder int, er
 			errCh <- err
 			return
 		}
+		if len(msgCh) == cap(msgCh) {
+			channelQueueFullCounter.Inc(1)
+		}
 		select {
 		case msgCh <- msg:
 		case <-closed:
@@ -1112,6 +1267,10 @@ func (p *multiChannelPeer) Handle(pm *ProtocolManager) error {
 		p.GetP2PPeer().Log().Debug("Klaytn peer handshake failed", "err", err)
 		return err
 	}
+	if err := p.HandshakeValidatorAddress(pm.validatorAddress); err != nil {
+		p.GetP2PPeer().Log().Debug("Klaytn peer validator address handshake failed", "err", err)
+		return err
+	}
 	reject := false
 	if atomic.LoadUint32(&pm.snapSync) == 1 {
 		if snap == nil {
@@ -1154,31 +1313,31 @@ func (p *multiChannelPeer) Handle(pm *ProtocolManager) error {
 	// Propagate existing transactions. new transactions appearing
 	// after this will be sent via broadcasts.
 	pm.syncTransactions(p)
+	pm.proxy.addPeer(p)
 
 	p.GetP2PPeer().Log().Info("Added a multichannel P2P Peer", "peerID", p.GetP2PPeerID())
 
-	pubKey, err := p.GetP2PPeerID().Pubkey()
-	if err != nil {
-		return err
-	}
-	addr := crypto.PubkeyToAddress(*pubKey)
+	addr := p.GetAddr()
 	lenRWs := len(p.rws)
 
+	// The configuration changed at runtime applies to the peers connected afterwards.
+	config := pm.channels.config()
+
 	var wg sync.WaitGroup
 	// TODO-GX check global worker and peer worker
 	messageChannels := make([]chan p2p.Msg, 0, lenRWs)
 	var consensusChannel chan p2p.Msg
 	isCN := false
 
-	if _, ok := pm.engine.(consensus.Handler); ok && pm.nodetype == common.CONSENSUSNODE {
-		consensusChannel = make(chan p2p.Msg, channelSizePerPeer)
+	if _, ok := pm.engine.(consensus.Handler); ok && (pm.nodetype == common.CONSENSUSNODE || pm.consensusRelay != nil) {
+		consensusChannel = make(chan p2p.Msg, config.QueueSize)
 		defer close(consensusChannel)
 		pm.engine.(consensus.Handler).RegisterConsensusMsgCode(p)
 		isCN = true
 	}
 
 	for idx := range p.rws {
-		channel := make(chan p2p.Msg, channelSizePerPeer)
+		channel := make(chan p2p.Msg, config.QueueSize)
 		defer close(channel)
 		messageChannels = append(messageChannels, channel)
 
@@ -1193,7 +1352,7 @@ func (p *multiChannelPeer) Handle(pm *ProtocolManager) error {
 
 	sumOfGoroutineForProcessMessage := 1 // 1 is for consensusChannel
 	for connIdx := range messageChannels {
-		sumOfGoroutineForProcessMessage += ConcurrentOfChannel[connIdx]
+		sumOfGoroutineForProcessMessage += config.Workers[connIdx]
 	}
 	errChannel := make(chan error, lenRWs+sumOfGoroutineForProcessMessage) // errChannel size should be set to count of goroutine use errChannel
 	closed := make(chan struct{})
@@ -1203,7 +1362,7 @@ func (p *multiChannelPeer) Handle(pm *ProtocolManager) error {
 	}
 
 	for connIdx, messageChannel := range messageChannels {
-		for i := 0; i < ConcurrentOfChannel[connIdx]; i++ {
+		for i := 0; i < config.Workers[connIdx]; i++ {
 			go pm.processMsg(messageChannel, p, addr, errChannel)
 		}
 	}
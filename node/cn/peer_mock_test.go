@@ -330,6 +330,20 @@ func (mr *MockPeerMockRecorder) Handshake(arg0, arg1, arg2, arg3, arg4 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Handshake", reflect.TypeOf((*MockPeer)(nil).Handshake), arg0, arg1, arg2, arg3, arg4)
 }
 
+// HandshakeValidatorAddress mocks base method
+func (m *MockPeer) HandshakeValidatorAddress(arg0 *validatorAddressData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandshakeValidatorAddress", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandshakeValidatorAddress indicates an expected call of HandshakeValidatorAddress
+func (mr *MockPeerMockRecorder) HandshakeValidatorAddress(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandshakeValidatorAddress", reflect.TypeOf((*MockPeer)(nil).HandshakeValidatorAddress), arg0)
+}
+
 // Head mocks base method
 func (m *MockPeer) Head() (common.Hash, *big.Int) {
 	m.ctrl.T.Helper()
@@ -471,6 +485,20 @@ func (mr *MockPeerMockRecorder) RequestNodeData(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestNodeData", reflect.TypeOf((*MockPeer)(nil).RequestNodeData), arg0)
 }
 
+// RequestPooledTransactions mocks base method
+func (m *MockPeer) RequestPooledTransactions(arg0 []common.Hash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestPooledTransactions", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestPooledTransactions indicates an expected call of RequestPooledTransactions
+func (mr *MockPeerMockRecorder) RequestPooledTransactions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestPooledTransactions", reflect.TypeOf((*MockPeer)(nil).RequestPooledTransactions), arg0)
+}
+
 // RequestReceipts mocks base method
 func (m *MockPeer) RequestReceipts(arg0 []common.Hash) error {
 	m.ctrl.T.Helper()
@@ -639,6 +667,34 @@ func (mr *MockPeerMockRecorder) SendNodeData(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendNodeData", reflect.TypeOf((*MockPeer)(nil).SendNodeData), arg0)
 }
 
+// SendPooledTransactionHashes mocks base method
+func (m *MockPeer) SendPooledTransactionHashes(arg0 []common.Hash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendPooledTransactionHashes", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendPooledTransactionHashes indicates an expected call of SendPooledTransactionHashes
+func (mr *MockPeerMockRecorder) SendPooledTransactionHashes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendPooledTransactionHashes", reflect.TypeOf((*MockPeer)(nil).SendPooledTransactionHashes), arg0)
+}
+
+// SendPooledTransactions mocks base method
+func (m *MockPeer) SendPooledTransactions(arg0 types.Transactions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendPooledTransactions", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendPooledTransactions indicates an expected call of SendPooledTransactions
+func (mr *MockPeerMockRecorder) SendPooledTransactions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendPooledTransactions", reflect.TypeOf((*MockPeer)(nil).SendPooledTransactions), arg0)
+}
+
 // SendReceiptsRLP mocks base method
 func (m *MockPeer) SendReceiptsRLP(arg0 []rlp.RawValue) error {
 	m.ctrl.T.Helper()
@@ -170,6 +170,50 @@ func TestBasePeer_GetAndSetAddr(t *testing.T) {
 	assert.Equal(t, addrs[1], basePeer.GetAddr())
 }
 
+// handshakeValidatorAddress runs the validator address handshake of a klay/67
+// peer of the node 0 against the given packet of the remote.
+func handshakeValidatorAddress(t *testing.T, remote *validatorAddressData) (Peer, error) {
+	pipe1, pipe2 := p2p.MsgPipe()
+	peer := newPeer(klay67, p2pPeers[0], pipe1)
+	peer.SetAddr(addrs[0])
+
+	go func() {
+		if err := p2p.Send(pipe2, ValidatorAddressMsg, remote); err != nil {
+			t.Error(err)
+		}
+	}()
+	go func() {
+		if err := p2p.ExpectMsg(pipe2, ValidatorAddressMsg, &validatorAddressData{}); err != nil {
+			t.Error(err)
+		}
+	}()
+	return peer, peer.HandshakeValidatorAddress(&validatorAddressData{})
+}
+
+func TestBasePeer_HandshakeValidatorAddress(t *testing.T) {
+	// The peer validating with its node key is identified by the node key.
+	peer, err := handshakeValidatorAddress(t, &validatorAddressData{})
+	assert.NoError(t, err)
+	assert.Equal(t, addrs[0], peer.GetAddr())
+
+	// The peer validating with another key is identified by the signed validator address.
+	remote, err := signValidatorAddress(nodeids[0], addrs[1], func(data []byte) ([]byte, error) {
+		return crypto.Sign(crypto.Keccak256(data), keys[1])
+	})
+	assert.NoError(t, err)
+	peer, err = handshakeValidatorAddress(t, remote)
+	assert.NoError(t, err)
+	assert.Equal(t, addrs[1], peer.GetAddr())
+
+	// The validator address signed for another node is rejected.
+	remote, err = signValidatorAddress(nodeids[2], addrs[1], func(data []byte) ([]byte, error) {
+		return crypto.Sign(crypto.Keccak256(data), keys[1])
+	})
+	assert.NoError(t, err)
+	_, err = handshakeValidatorAddress(t, remote)
+	assert.Error(t, err)
+}
+
 func TestBasePeer_GetVersion(t *testing.T) {
 	basePeer, _, _ := newBasePeer()
 	assert.Equal(t, version, basePeer.GetVersion())
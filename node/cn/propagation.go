@@ -0,0 +1,239 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/klaytn/klaytn/common"
+	"github.com/rcrowley/go-metrics"
+)
+
+const (
+	propagationTrackedBlocks = 1024  // number of recent blocks whose first-seen times are kept
+	propagationTrackedTxs    = 65536 // number of recent transactions whose first-seen times are kept
+	propagationSampleSize    = 1028
+	propagationSampleAlpha   = 0.015
+)
+
+// LatencyStats is the distribution of propagation latencies in milliseconds.
+type LatencyStats struct {
+	Count int64   `json:"count"`
+	Mean  float64 `json:"mean"`
+	P50   float64 `json:"p50"`
+	P95   float64 `json:"p95"`
+	P99   float64 `json:"p99"`
+	Max   float64 `json:"max"`
+}
+
+// PeerPropagationStats is the delay of a peer in delivering blocks and
+// transactions after they are first seen from any peer.
+type PeerPropagationStats struct {
+	BlockDelay LatencyStats `json:"blockDelay"`
+	TxDelay    LatencyStats `json:"txDelay"`
+}
+
+// PropagationStats is the block and transaction propagation latencies observed by the node.
+type PropagationStats struct {
+	// BlockAnnounceToReceive is the time from the first announcement of a block
+	// hash to the first reception of the block.
+	BlockAnnounceToReceive LatencyStats `json:"blockAnnounceToReceive"`
+	// BlockDelay and TxDelay are the delays of the peers in announcing or
+	// sending blocks and transactions after they are first seen.
+	BlockDelay LatencyStats                     `json:"blockDelay"`
+	TxDelay    LatencyStats                     `json:"txDelay"`
+	Peers      map[string]*PeerPropagationStats `json:"peers"`
+}
+
+type latencySnapshot interface {
+	Count() int64
+	Mean() float64
+	Max() int64
+	Percentiles([]float64) []float64
+}
+
+func newLatencyStats(s latencySnapshot) LatencyStats {
+	const ms = float64(time.Millisecond)
+	ps := s.Percentiles([]float64{0.5, 0.95, 0.99})
+	return LatencyStats{
+		Count: s.Count(),
+		Mean:  s.Mean() / ms,
+		P50:   ps[0] / ms,
+		P95:   ps[1] / ms,
+		P99:   ps[2] / ms,
+		Max:   float64(s.Max()) / ms,
+	}
+}
+
+type blockSighting struct {
+	firstSeen time.Time
+	announced bool                // the block was announced before it was received
+	received  bool                // the block was received
+	peers     map[string]struct{} // peers that announced or sent the block
+}
+
+type peerPropagation struct {
+	blockDelay metrics.Histogram
+	txDelay    metrics.Histogram
+}
+
+func newPeerPropagation() *peerPropagation {
+	return &peerPropagation{
+		blockDelay: metrics.NewHistogram(metrics.NewExpDecaySample(propagationSampleSize, propagationSampleAlpha)),
+		txDelay:    metrics.NewHistogram(metrics.NewExpDecaySample(propagationSampleSize, propagationSampleAlpha)),
+	}
+}
+
+// propagationTracker records when blocks and transactions are first seen and
+// how late each peer delivers them. The statistics of a nil tracker are empty.
+type propagationTracker struct {
+	mu     sync.Mutex
+	blocks *lru.Cache // block hash -> *blockSighting
+	txs    *lru.Cache // tx hash -> first-seen time.Time
+	peers  map[string]*peerPropagation
+
+	now func() time.Time // for testing
+}
+
+func newPropagationTracker() *propagationTracker {
+	blocks, _ := lru.New(propagationTrackedBlocks)
+	txs, _ := lru.New(propagationTrackedTxs)
+	return &propagationTracker{
+		blocks: blocks,
+		txs:    txs,
+		peers:  make(map[string]*peerPropagation),
+		now:    time.Now,
+	}
+}
+
+func (t *propagationTracker) peer(id string) *peerPropagation {
+	pp, ok := t.peers[id]
+	if !ok {
+		pp = newPeerPropagation()
+		t.peers[id] = pp
+	}
+	return pp
+}
+
+// sightBlock returns the sighting of the block and records the delay of the
+// peer if it is the first time the peer delivers the block.
+func (t *propagationTracker) sightBlock(id string, hash common.Hash, now time.Time) *blockSighting {
+	var s *blockSighting
+	if v, ok := t.blocks.Get(hash); ok {
+		s = v.(*blockSighting)
+	} else {
+		s = &blockSighting{firstSeen: now, peers: make(map[string]struct{})}
+		t.blocks.Add(hash, s)
+	}
+	if _, ok := s.peers[id]; !ok {
+		s.peers[id] = struct{}{}
+		delay := now.Sub(s.firstSeen)
+		blockPropDelayTimer.Update(delay)
+		t.peer(id).blockDelay.Update(int64(delay))
+	}
+	return s
+}
+
+// blockAnnounced records the announcement of the block hash by the peer.
+func (t *propagationTracker) blockAnnounced(id string, hash common.Hash) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.sightBlock(id, hash, t.now())
+	if !s.received {
+		s.announced = true
+	}
+}
+
+// blockReceived records the reception of the block from the peer.
+func (t *propagationTracker) blockReceived(id string, hash common.Hash) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	s := t.sightBlock(id, hash, now)
+	if !s.received {
+		s.received = true
+		if s.announced {
+			blockPropAnnounceToReceiveTimer.Update(now.Sub(s.firstSeen))
+		}
+	}
+}
+
+// txsReceived records the reception of the transactions from the peer.
+func (t *propagationTracker) txsReceived(id string, hashes []common.Hash) {
+	if len(hashes) == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	pp := t.peer(id)
+	for _, hash := range hashes {
+		firstSeen := now
+		if v, ok := t.txs.Get(hash); ok {
+			firstSeen = v.(time.Time)
+		} else {
+			t.txs.Add(hash, now)
+		}
+		delay := now.Sub(firstSeen)
+		txPropDelayTimer.Update(delay)
+		pp.txDelay.Update(int64(delay))
+	}
+}
+
+// removePeer drops the statistics of the disconnected peer.
+func (t *propagationTracker) removePeer(id string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.peers, id)
+}
+
+func (t *propagationTracker) stats() *PropagationStats {
+	stats := &PropagationStats{
+		BlockAnnounceToReceive: newLatencyStats(blockPropAnnounceToReceiveTimer.Snapshot()),
+		BlockDelay:             newLatencyStats(blockPropDelayTimer.Snapshot()),
+		TxDelay:                newLatencyStats(txPropDelayTimer.Snapshot()),
+		Peers:                  make(map[string]*PeerPropagationStats),
+	}
+	if t == nil {
+		return stats
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for id, pp := range t.peers {
+		stats.Peers[id] = &PeerPropagationStats{
+			BlockDelay: newLatencyStats(pp.blockDelay.Snapshot()),
+			TxDelay:    newLatencyStats(pp.txDelay.Snapshot()),
+		}
+	}
+	return stats
+}
+
+// PropagationStats returns the block and transaction propagation latencies
+// observed from the connected peers.
+func (pm *ProtocolManager) PropagationStats() *PropagationStats {
+	return pm.propagation.stats()
+}
@@ -0,0 +1,60 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropagationTracker(t *testing.T) {
+	tracker := newPropagationTracker()
+	now := time.Unix(1600000000, 0)
+	tracker.now = func() time.Time { return now }
+
+	block := common.Hash{1}
+	tracker.blockAnnounced("peer1", block)
+	now = now.Add(100 * time.Millisecond)
+	tracker.blockAnnounced("peer2", block)
+	now = now.Add(100 * time.Millisecond)
+	tracker.blockReceived("peer1", block) // already counted by the announcement
+
+	tx := common.Hash{2}
+	tracker.txsReceived("peer1", []common.Hash{tx})
+	now = now.Add(50 * time.Millisecond)
+	tracker.txsReceived("peer2", []common.Hash{tx})
+
+	stats := tracker.stats()
+	if assert.Contains(t, stats.Peers, "peer1") && assert.Contains(t, stats.Peers, "peer2") {
+		assert.Equal(t, int64(1), stats.Peers["peer1"].BlockDelay.Count)
+		assert.Equal(t, 0.0, stats.Peers["peer1"].BlockDelay.Max)
+		assert.Equal(t, 100.0, stats.Peers["peer2"].BlockDelay.Max)
+		assert.Equal(t, 0.0, stats.Peers["peer1"].TxDelay.Max)
+		assert.Equal(t, 50.0, stats.Peers["peer2"].TxDelay.Max)
+	}
+
+	tracker.removePeer("peer2")
+	assert.NotContains(t, tracker.stats().Peers, "peer2")
+
+	// The statistics of a nil tracker are empty.
+	var nilTracker *propagationTracker
+	nilTracker.removePeer("peer1")
+	assert.Empty(t, nilTracker.stats().Peers)
+}
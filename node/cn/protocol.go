@@ -42,16 +42,18 @@ const (
 	klay63 = 63
 	klay64 = 64
 	klay65 = 65
+	klay66 = 66
+	klay67 = 67
 )
 
 // ProtocolName is the official short name of the protocol used during capability negotiation.
 var ProtocolName = "klay"
 
 // ProtocolVersions are the upported versions of the klay protocol (first is primary).
-var ProtocolVersions = []uint{klay65, klay64, klay63, klay62}
+var ProtocolVersions = []uint{klay67, klay66, klay65, klay64, klay63, klay62}
 
 // ProtocolLengths are the number of implemented message corresponding to different protocol versions.
-var ProtocolLengths = []uint64{21, 19, 17, 8}
+var ProtocolLengths = []uint64{24, 23, 21, 19, 17, 8}
 
 const ProtocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
 
@@ -86,9 +88,28 @@ const (
 	StakingInfoRequestMsg = 0x12
 	StakingInfoMsg        = 0x13
 
-	MsgCodeEnd = 0x14
+	// Protocol messages belonging to klay/66
+	NewPooledTransactionHashesMsg = 0x14
+	PooledTransactionsRequestMsg  = 0x15
+	PooledTransactionsMsg         = 0x16
+
+	// Protocol messages belonging to klay/67
+	ValidatorAddressMsg = 0x17
+
+	MsgCodeEnd = 0x18
 )
 
+// compressibleMsg reports whether the message of the given code carries
+// large payloads worth compressing, e.g. block bodies and receipts.
+func compressibleMsg(code uint64) bool {
+	switch code {
+	case BlockHeaderFetchResponseMsg, BlockBodiesFetchResponseMsg, BlockHeadersMsg, BlockBodiesMsg,
+		NewBlockMsg, NodeDataMsg, ReceiptsMsg, StakingInfoMsg:
+		return true
+	}
+	return false
+}
+
 type errCode int
 
 const (
@@ -105,6 +126,8 @@ const (
 	ErrUnexpectedTxType
 	ErrFailedToGetStateDB
 	ErrUnsupportedEnginePolicy
+	ErrNoValidatorAddressMsg
+	ErrInvalidValidatorAddress
 )
 
 func (e errCode) String() string {
@@ -126,6 +149,8 @@ var errorToString = map[int]string{
 	ErrUnexpectedTxType:        "Unexpected tx type",
 	ErrFailedToGetStateDB:      "Failed to get stateDB",
 	ErrUnsupportedEnginePolicy: "Unsupported engine or policy",
+	ErrNoValidatorAddressMsg:   "No validator address message",
+	ErrInvalidValidatorAddress: "Invalid validator address",
 }
 
 //go:generate mockgen -destination=node/cn/mocks/downloader_mock.go -package=mocks github.com/klaytn/klaytn/node/cn ProtocolManagerDownloader
@@ -170,6 +195,14 @@ type statusData struct {
 	ChainID         *big.Int // ChainID to sign a transaction.
 }
 
+// validatorAddressData is the network packet for the validator address of a
+// node. The signature by the validator key over the node ID and the address is
+// omitted if the validator key is the node key.
+type validatorAddressData struct {
+	Address   common.Address
+	Signature []byte
+}
+
 // newBlockHashesData is the network packet for the block announcements.
 type newBlockHashesData []struct {
 	Hash   common.Hash // Hash of one particular block being announced
@@ -12,6 +12,7 @@ import (
 	types "github.com/klaytn/klaytn/blockchain/types"
 	common "github.com/klaytn/klaytn/common"
 	p2p "github.com/klaytn/klaytn/networks/p2p"
+	discover "github.com/klaytn/klaytn/networks/p2p/discover"
 )
 
 // MockBackendProtocolManager is a mock of BackendProtocolManager interface.
@@ -37,6 +38,34 @@ func (m *MockBackendProtocolManager) EXPECT() *MockBackendProtocolManagerMockRec
 	return m.recorder
 }
 
+// ChannelBacklogs mocks base method.
+func (m *MockBackendProtocolManager) ChannelBacklogs() map[string]ChannelBacklog {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChannelBacklogs")
+	ret0, _ := ret[0].(map[string]ChannelBacklog)
+	return ret0
+}
+
+// ChannelBacklogs indicates an expected call of ChannelBacklogs.
+func (mr *MockBackendProtocolManagerMockRecorder) ChannelBacklogs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChannelBacklogs", reflect.TypeOf((*MockBackendProtocolManager)(nil).ChannelBacklogs))
+}
+
+// ChannelConfig mocks base method.
+func (m *MockBackendProtocolManager) ChannelConfig() ChannelConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChannelConfig")
+	ret0, _ := ret[0].(ChannelConfig)
+	return ret0
+}
+
+// ChannelConfig indicates an expected call of ChannelConfig.
+func (mr *MockBackendProtocolManagerMockRecorder) ChannelConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChannelConfig", reflect.TypeOf((*MockBackendProtocolManager)(nil).ChannelConfig))
+}
+
 // Downloader mocks base method.
 func (m *MockBackendProtocolManager) Downloader() ProtocolManagerDownloader {
 	m.ctrl.T.Helper()
@@ -79,6 +108,20 @@ func (mr *MockBackendProtocolManagerMockRecorder) NodeType() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeType", reflect.TypeOf((*MockBackendProtocolManager)(nil).NodeType))
 }
 
+// PropagationStats mocks base method.
+func (m *MockBackendProtocolManager) PropagationStats() *PropagationStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PropagationStats")
+	ret0, _ := ret[0].(*PropagationStats)
+	return ret0
+}
+
+// PropagationStats indicates an expected call of PropagationStats.
+func (mr *MockBackendProtocolManagerMockRecorder) PropagationStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PropagationStats", reflect.TypeOf((*MockBackendProtocolManager)(nil).PropagationStats))
+}
+
 // ProtocolVersion mocks base method.
 func (m *MockBackendProtocolManager) ProtocolVersion() int {
 	m.ctrl.T.Helper()
@@ -93,6 +136,20 @@ func (mr *MockBackendProtocolManagerMockRecorder) ProtocolVersion() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProtocolVersion", reflect.TypeOf((*MockBackendProtocolManager)(nil).ProtocolVersion))
 }
 
+// ProxyStatus mocks base method.
+func (m *MockBackendProtocolManager) ProxyStatus() *ProxyStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProxyStatus")
+	ret0, _ := ret[0].(*ProxyStatus)
+	return ret0
+}
+
+// ProxyStatus indicates an expected call of ProxyStatus.
+func (mr *MockBackendProtocolManagerMockRecorder) ProxyStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProxyStatus", reflect.TypeOf((*MockBackendProtocolManager)(nil).ProxyStatus))
+}
+
 // ReBroadcastTxs mocks base method.
 func (m *MockBackendProtocolManager) ReBroadcastTxs(arg0 types.Transactions) {
 	m.ctrl.T.Helper()
@@ -117,6 +174,48 @@ func (mr *MockBackendProtocolManagerMockRecorder) SetAcceptTxs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAcceptTxs", reflect.TypeOf((*MockBackendProtocolManager)(nil).SetAcceptTxs))
 }
 
+// SetActiveUpstream mocks base method.
+func (m *MockBackendProtocolManager) SetActiveUpstream(arg0 discover.NodeID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetActiveUpstream", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetActiveUpstream indicates an expected call of SetActiveUpstream.
+func (mr *MockBackendProtocolManagerMockRecorder) SetActiveUpstream(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetActiveUpstream", reflect.TypeOf((*MockBackendProtocolManager)(nil).SetActiveUpstream), arg0)
+}
+
+// SetChannelQueueSize mocks base method.
+func (m *MockBackendProtocolManager) SetChannelQueueSize(arg0 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetChannelQueueSize", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetChannelQueueSize indicates an expected call of SetChannelQueueSize.
+func (mr *MockBackendProtocolManagerMockRecorder) SetChannelQueueSize(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetChannelQueueSize", reflect.TypeOf((*MockBackendProtocolManager)(nil).SetChannelQueueSize), arg0)
+}
+
+// SetChannelWorkers mocks base method.
+func (m *MockBackendProtocolManager) SetChannelWorkers(arg0 int, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetChannelWorkers", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetChannelWorkers indicates an expected call of SetChannelWorkers.
+func (mr *MockBackendProtocolManagerMockRecorder) SetChannelWorkers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetChannelWorkers", reflect.TypeOf((*MockBackendProtocolManager)(nil).SetChannelWorkers), arg0, arg1)
+}
+
 // SetRewardbase mocks base method.
 func (m *MockBackendProtocolManager) SetRewardbase(arg0 common.Address) {
 	m.ctrl.T.Helper()
@@ -153,6 +252,20 @@ func (mr *MockBackendProtocolManagerMockRecorder) SetTmpStop(arg0 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSyncStop", reflect.TypeOf((*MockBackendProtocolManager)(nil).SetSyncStop), arg0)
 }
 
+// SetValidatorAddress mocks base method.
+func (m *MockBackendProtocolManager) SetValidatorAddress(arg0 discover.NodeID, arg1 common.Address, arg2 func([]byte) ([]byte, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetValidatorAddress", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetValidatorAddress indicates an expected call of SetValidatorAddress.
+func (mr *MockBackendProtocolManagerMockRecorder) SetValidatorAddress(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetValidatorAddress", reflect.TypeOf((*MockBackendProtocolManager)(nil).SetValidatorAddress), arg0, arg1, arg2)
+}
+
 // SetWsEndPoint mocks base method.
 func (m *MockBackendProtocolManager) SetWsEndPoint(arg0 string) {
 	m.ctrl.T.Helper()
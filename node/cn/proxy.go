@@ -0,0 +1,325 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/klaytn/klaytn/consensus/istanbul"
+	"github.com/klaytn/klaytn/consensus/istanbul/backend"
+	"github.com/klaytn/klaytn/consensus/istanbul/core"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+)
+
+const (
+	proxyHandoffMsgs = 256              // maximum number of consensus messages handed off to a new active upstream
+	proxyHandoffAge  = 30 * time.Second // maximum age of the consensus messages handed off
+)
+
+var (
+	errNotUpstream          = errors.New("not an upstream of the proxy")
+	errUpstreamNotConnected = errors.New("upstream is not connected")
+	errNotProxy             = errors.New("node is not running as a validator proxy")
+)
+
+// UpstreamInfo is the state of an upstream CN of the validator proxy.
+type UpstreamInfo struct {
+	ID        string `json:"id"`
+	Connected bool   `json:"connected"`
+	Active    bool   `json:"active"`
+}
+
+// ProxyStatus is the state of the validator proxy.
+type ProxyStatus struct {
+	Upstreams  []UpstreamInfo `json:"upstreams"` // in the order of priority
+	Failovers  uint64         `json:"failovers"`
+	Epoch      uint64         `json:"epoch"`      // fencing token incremented on every activation
+	LastSigned *istanbul.View `json:"lastSigned"` // latest view signed by the validator through the proxy
+	Fence      *istanbul.View `json:"fence"`      // the active upstream can sign only the views after it
+}
+
+type handoffMsg struct {
+	msg  *istanbul.ConsensusMsg
+	time time.Time
+}
+
+// validatorProxy terminates the p2p connections on behalf of a validator which
+// has multiple upstream CNs, e.g. an active one and standbys. Only the active
+// upstream exchanges the consensus messages through the proxy. If it disconnects,
+// the proxy fails over to the connected upstream of the highest priority and
+// hands off the recent consensus messages so that the new upstream can take part
+// in the current round. The active upstream can also be switched by the operator
+// before a maintenance.
+//
+// Only the consensus messages of the active upstream are relayed, and every
+// activation starts a new epoch fenced by the last view signed in the previous
+// epochs: the messages of the new active upstream at or before the fence are
+// dropped. A previous upstream which is still running can't make the validator
+// sign a view twice, and the new one joins from the next round.
+type validatorProxy struct {
+	upstreams []discover.NodeID // in the order of priority
+
+	mu         sync.Mutex
+	connected  map[discover.NodeID]Peer
+	active     *discover.NodeID // nil if no upstream is connected
+	recent     []handoffMsg
+	failovers  uint64
+	epoch      uint64         // fencing token incremented on every activation
+	lastSigned *istanbul.View // nil if no message has been signed
+	fence      *istanbul.View // nil if no message was signed before the epoch
+	now        func() time.Time
+}
+
+func newValidatorProxy(upstreams []*discover.Node) *validatorProxy {
+	proxy := &validatorProxy{
+		connected: make(map[discover.NodeID]Peer),
+		now:       time.Now,
+	}
+	for _, n := range upstreams {
+		proxy.upstreams = append(proxy.upstreams, n.ID)
+	}
+	return proxy
+}
+
+func (vp *validatorProxy) isUpstream(id discover.NodeID) bool {
+	if vp == nil {
+		return false
+	}
+	for _, upstream := range vp.upstreams {
+		if upstream == id {
+			return true
+		}
+	}
+	return false
+}
+
+// isStandby returns true if the peer is an upstream other than the active one.
+// The consensus messages are not exchanged with the standby upstreams.
+func (vp *validatorProxy) isStandby(id discover.NodeID) bool {
+	if !vp.isUpstream(id) {
+		return false
+	}
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+	return vp.active == nil || *vp.active != id
+}
+
+// admit returns true if the consensus message of the upstream can be relayed,
+// i.e. the upstream is active and the message is signed after the fence of the
+// epoch. The view of the admitted message becomes the last signed one.
+func (vp *validatorProxy) admit(id discover.NodeID, msg *istanbul.ConsensusMsg) bool {
+	view, err := core.MessageView(msg.Payload)
+	if err != nil {
+		logger.Debug("Dropped undecodable consensus message of the upstream", "id", id, "err", err)
+		return false
+	}
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+
+	if vp.active == nil || *vp.active != id {
+		return false
+	}
+	if vp.fence != nil && view.Cmp(vp.fence) <= 0 {
+		proxyFencedCounter.Inc(1)
+		logger.Debug("Dropped consensus message of the upstream at or before the fence", "id", id, "view", view, "fence", vp.fence, "epoch", vp.epoch)
+		return false
+	}
+	if vp.lastSigned == nil || view.Cmp(vp.lastSigned) > 0 {
+		vp.lastSigned = view
+	}
+	return true
+}
+
+// addPeer activates the connected upstream if no upstream is active.
+func (vp *validatorProxy) addPeer(p Peer) {
+	if vp == nil {
+		return
+	}
+	id := p.GetP2PPeerID()
+	if !vp.isUpstream(id) {
+		return
+	}
+	vp.mu.Lock()
+	vp.connected[id] = p
+	var handoff []*istanbul.ConsensusMsg
+	if vp.active == nil {
+		handoff = vp.activate(id)
+	}
+	vp.mu.Unlock()
+
+	vp.handoff(p, handoff)
+}
+
+// removePeer fails over to another upstream if the active upstream disconnects.
+func (vp *validatorProxy) removePeer(p Peer) {
+	if vp == nil {
+		return
+	}
+	id := p.GetP2PPeerID()
+	if !vp.isUpstream(id) {
+		return
+	}
+	vp.mu.Lock()
+	delete(vp.connected, id)
+	if vp.active == nil || *vp.active != id {
+		vp.mu.Unlock()
+		return
+	}
+	vp.active = nil
+	var (
+		next    Peer
+		handoff []*istanbul.ConsensusMsg
+	)
+	for _, upstream := range vp.upstreams {
+		if p, ok := vp.connected[upstream]; ok {
+			next, handoff = p, vp.activate(upstream)
+			vp.failovers++
+			break
+		}
+	}
+	vp.mu.Unlock()
+
+	if next == nil {
+		logger.Warn("No upstream CN is connected to the validator proxy", "disconnected", id)
+		return
+	}
+	proxyFailoverCounter.Inc(1)
+	logger.Warn("Validator proxy failed over", "from", id, "to", next.GetP2PPeerID())
+	vp.handoff(next, handoff)
+}
+
+// setActive switches the active upstream to the given connected upstream.
+func (vp *validatorProxy) setActive(id discover.NodeID) error {
+	if vp == nil {
+		return errNotProxy
+	}
+	if !vp.isUpstream(id) {
+		return errNotUpstream
+	}
+	vp.mu.Lock()
+	p, ok := vp.connected[id]
+	if !ok {
+		vp.mu.Unlock()
+		return errUpstreamNotConnected
+	}
+	if vp.active != nil && *vp.active == id {
+		vp.mu.Unlock()
+		return nil
+	}
+	handoff := vp.activate(id)
+	vp.mu.Unlock()
+
+	logger.Info("Switched the active upstream of the validator proxy", "id", id, "epoch", vp.epoch)
+	vp.handoff(p, handoff)
+	return nil
+}
+
+// activate makes the upstream active in a new epoch fenced by the last signed
+// view, and returns the consensus messages to hand off. The caller must hold vp.mu.
+func (vp *validatorProxy) activate(id discover.NodeID) []*istanbul.ConsensusMsg {
+	vp.active = &id
+	vp.epoch++
+	vp.fence = vp.lastSigned
+	if vp.fence != nil {
+		logger.Info("Fenced the new active upstream of the validator proxy", "id", id, "epoch", vp.epoch,
+			"lastSignedNumber", vp.fence.Sequence, "lastSignedRound", vp.fence.Round)
+	}
+	vp.prune()
+	msgs := make([]*istanbul.ConsensusMsg, len(vp.recent))
+	for i, m := range vp.recent {
+		msgs[i] = m.msg
+	}
+	return msgs
+}
+
+func (vp *validatorProxy) handoff(p Peer, msgs []*istanbul.ConsensusMsg) {
+	if len(msgs) == 0 {
+		return
+	}
+	logger.Info("Handing off recent consensus messages to the upstream", "id", p.GetP2PPeerID(), "count", len(msgs))
+	go func() {
+		for _, msg := range msgs {
+			if err := p.Send(backend.IstanbulMsg, msg); err != nil {
+				logger.Debug("Failed to hand off consensus message", "id", p.GetP2PPeerID(), "err", err)
+				return
+			}
+		}
+	}()
+}
+
+// record keeps the relayed consensus message to hand it off to the next active upstream.
+func (vp *validatorProxy) record(msg *istanbul.ConsensusMsg) {
+	if vp == nil {
+		return
+	}
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+
+	vp.recent = append(vp.recent, handoffMsg{msg: msg, time: vp.now()})
+	vp.prune()
+}
+
+// prune drops the handoff messages which are too old or exceed the limit.
+// The caller must hold vp.mu.
+func (vp *validatorProxy) prune() {
+	cutoff := vp.now().Add(-proxyHandoffAge)
+	start := 0
+	for start < len(vp.recent) && vp.recent[start].time.Before(cutoff) {
+		start++
+	}
+	if len(vp.recent)-start > proxyHandoffMsgs {
+		start = len(vp.recent) - proxyHandoffMsgs
+	}
+	vp.recent = append(vp.recent[:0], vp.recent[start:]...)
+}
+
+func (vp *validatorProxy) status() *ProxyStatus {
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+
+	status := &ProxyStatus{
+		Upstreams:  make([]UpstreamInfo, 0, len(vp.upstreams)),
+		Failovers:  vp.failovers,
+		Epoch:      vp.epoch,
+		LastSigned: vp.lastSigned,
+		Fence:      vp.fence,
+	}
+	for _, id := range vp.upstreams {
+		_, connected := vp.connected[id]
+		status.Upstreams = append(status.Upstreams, UpstreamInfo{
+			ID:        id.String(),
+			Connected: connected,
+			Active:    vp.active != nil && *vp.active == id,
+		})
+	}
+	return status
+}
+
+// ProxyStatus returns the state of the validator proxy, or nil if the node is not a validator proxy.
+func (pm *ProtocolManager) ProxyStatus() *ProxyStatus {
+	if pm.proxy == nil {
+		return nil
+	}
+	return pm.proxy.status()
+}
+
+// SetActiveUpstream switches the active upstream CN of the validator proxy.
+func (pm *ProtocolManager) SetActiveUpstream(id discover.NodeID) error {
+	return pm.proxy.setActive(id)
+}
@@ -0,0 +1,148 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus/istanbul"
+	"github.com/klaytn/klaytn/consensus/istanbul/backend"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+	"github.com/klaytn/klaytn/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatorProxy_Failover(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	primaryID, standbyID := discover.NodeID{1}, discover.NodeID{2}
+	proxy := newValidatorProxy([]*discover.Node{{ID: primaryID}, {ID: standbyID}})
+	now := time.Unix(1600000000, 0)
+	proxy.now = func() time.Time { return now }
+
+	primary, standby := NewMockPeer(mockCtrl), NewMockPeer(mockCtrl)
+	primary.EXPECT().GetP2PPeerID().Return(primaryID).AnyTimes()
+	standby.EXPECT().GetP2PPeerID().Return(standbyID).AnyTimes()
+
+	// The expired message is not handed off.
+	proxy.record(&istanbul.ConsensusMsg{PrevHash: common.Hash{1}})
+	now = now.Add(proxyHandoffAge + time.Second)
+	msg := &istanbul.ConsensusMsg{PrevHash: common.Hash{2}}
+	proxy.record(msg)
+
+	// The first connected upstream becomes active and receives the recent message.
+	sent := make(chan struct{}, 1)
+	standby.EXPECT().Send(uint64(backend.IstanbulMsg), msg).Do(func(uint64, interface{}) { sent <- struct{}{} }).Return(nil).Times(1)
+	proxy.addPeer(standby)
+	<-sent
+	assert.False(t, proxy.isStandby(standbyID))
+
+	// The upstream of higher priority doesn't preempt the active one.
+	proxy.addPeer(primary)
+	assert.True(t, proxy.isStandby(primaryID))
+
+	// The proxy fails over if the active upstream disconnects.
+	primary.EXPECT().Send(uint64(backend.IstanbulMsg), msg).Do(func(uint64, interface{}) { sent <- struct{}{} }).Return(nil).Times(1)
+	proxy.removePeer(standby)
+	<-sent
+	assert.False(t, proxy.isStandby(primaryID))
+
+	status := proxy.status()
+	assert.Equal(t, uint64(1), status.Failovers)
+	assert.Equal(t, []UpstreamInfo{
+		{ID: primaryID.String(), Connected: true, Active: true},
+		{ID: standbyID.String(), Connected: false, Active: false},
+	}, status.Upstreams)
+}
+
+func TestValidatorProxy_SetActive(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	primaryID, standbyID := discover.NodeID{1}, discover.NodeID{2}
+	proxy := newValidatorProxy([]*discover.Node{{ID: primaryID}, {ID: standbyID}})
+
+	primary, standby := NewMockPeer(mockCtrl), NewMockPeer(mockCtrl)
+	primary.EXPECT().GetP2PPeerID().Return(primaryID).AnyTimes()
+	standby.EXPECT().GetP2PPeerID().Return(standbyID).AnyTimes()
+
+	assert.Equal(t, errNotUpstream, proxy.setActive(discover.NodeID{3}))
+	assert.Equal(t, errUpstreamNotConnected, proxy.setActive(standbyID))
+
+	proxy.addPeer(primary)
+	proxy.addPeer(standby)
+	assert.NoError(t, proxy.setActive(standbyID))
+	assert.True(t, proxy.isStandby(primaryID))
+	assert.False(t, proxy.isStandby(standbyID))
+
+	// Non-upstream peers are never standby.
+	assert.False(t, proxy.isStandby(discover.NodeID{3}))
+
+	var nilProxy *validatorProxy
+	assert.Equal(t, errNotProxy, nilProxy.setActive(primaryID))
+}
+
+// consensusMsgAt returns a prepare message at the given view.
+func consensusMsgAt(t *testing.T, number, round int64) *istanbul.ConsensusMsg {
+	subject, err := rlp.EncodeToBytes(&istanbul.Subject{View: &istanbul.View{Round: big.NewInt(round), Sequence: big.NewInt(number)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The fields of the message of the istanbul core, of which the code 1 is the prepare.
+	payload, err := rlp.EncodeToBytes([]interface{}{common.Hash{}, uint64(1), subject, common.Address{}, []byte{}, []byte{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &istanbul.ConsensusMsg{Payload: payload}
+}
+
+func TestValidatorProxy_Fence(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	primaryID, standbyID := discover.NodeID{1}, discover.NodeID{2}
+	proxy := newValidatorProxy([]*discover.Node{{ID: primaryID}, {ID: standbyID}})
+
+	primary, standby := NewMockPeer(mockCtrl), NewMockPeer(mockCtrl)
+	primary.EXPECT().GetP2PPeerID().Return(primaryID).AnyTimes()
+	standby.EXPECT().GetP2PPeerID().Return(standbyID).AnyTimes()
+	proxy.addPeer(primary)
+	proxy.addPeer(standby)
+
+	// Only the messages of the active upstream are admitted.
+	assert.False(t, proxy.admit(standbyID, consensusMsgAt(t, 10, 0)))
+	assert.True(t, proxy.admit(primaryID, consensusMsgAt(t, 10, 0)))
+	assert.True(t, proxy.admit(primaryID, consensusMsgAt(t, 10, 1)))
+	assert.False(t, proxy.admit(primaryID, &istanbul.ConsensusMsg{Payload: []byte{1}}))
+
+	// The new active upstream is fenced by the last view signed by the previous one.
+	assert.NoError(t, proxy.setActive(standbyID))
+	assert.False(t, proxy.admit(primaryID, consensusMsgAt(t, 10, 2)))
+	assert.False(t, proxy.admit(standbyID, consensusMsgAt(t, 10, 0)))
+	assert.False(t, proxy.admit(standbyID, consensusMsgAt(t, 10, 1)))
+	assert.True(t, proxy.admit(standbyID, consensusMsgAt(t, 10, 2)))
+
+	status := proxy.status()
+	assert.Equal(t, uint64(2), status.Epoch)
+	assert.Equal(t, 0, status.Fence.Cmp(&istanbul.View{Round: big.NewInt(1), Sequence: big.NewInt(10)}))
+	assert.Equal(t, 0, status.LastSigned.Cmp(&istanbul.View{Round: big.NewInt(2), Sequence: big.NewInt(10)}))
+}
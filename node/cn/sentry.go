@@ -0,0 +1,185 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus"
+	"github.com/klaytn/klaytn/consensus/istanbul"
+	"github.com/klaytn/klaytn/consensus/istanbul/backend"
+	"github.com/klaytn/klaytn/networks/p2p"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+)
+
+const (
+	relayedConsensusMsgs = 4096                   // number of recent consensus messages remembered not to relay twice
+	relayQueueSize       = 256                    // maximum number of consensus messages queued for a relay target
+	relayRetries         = 2                      // number of retries to send a consensus message to a relay target
+	relayRetryDelay      = 100 * time.Millisecond // delay between the retries
+)
+
+// consensusRelay relays the consensus messages of the validators running in
+// sentry mode. A sentry PN forwards the consensus messages received from the
+// trusted peers, which are its validators and the other sentries, to the CN
+// peers and the other sentries, so that the validators behind different
+// sentries can reach each other. The messages are sent through a bounded queue
+// per relay target, so a slow target doesn't hold the others up.
+type consensusRelay struct {
+	known *lru.Cache // hash of the consensus message payload -> struct{}
+
+	mu     sync.Mutex
+	queues map[discover.NodeID]*relayQueue
+}
+
+func newConsensusRelay() *consensusRelay {
+	known, _ := lru.New(relayedConsensusMsgs)
+	return &consensusRelay{known: known, queues: make(map[discover.NodeID]*relayQueue)}
+}
+
+// relayQueue sends the queued consensus messages to a relay target.
+type relayQueue struct {
+	peer Peer
+	msgs chan *istanbul.ConsensusMsg
+	quit chan struct{}
+}
+
+// enqueue queues the consensus message for the peer. The message is dropped
+// if the queue of the peer is full.
+func (r *consensusRelay) enqueue(p Peer, msg *istanbul.ConsensusMsg) {
+	id := p.GetP2PPeerID()
+	r.mu.Lock()
+	q, ok := r.queues[id]
+	if !ok {
+		q = &relayQueue{peer: p, msgs: make(chan *istanbul.ConsensusMsg, relayQueueSize), quit: make(chan struct{})}
+		r.queues[id] = q
+		go q.loop()
+	}
+	r.mu.Unlock()
+
+	select {
+	case q.msgs <- msg:
+	default:
+		consensusRelayDropCounter.Inc(1)
+		logger.Debug("Dropped consensus message to relay, the queue is full", "id", id)
+	}
+}
+
+// removePeer stops the queue of the disconnected peer.
+func (r *consensusRelay) removePeer(id discover.NodeID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if q, ok := r.queues[id]; ok {
+		close(q.quit)
+		delete(r.queues, id)
+	}
+}
+
+func (q *relayQueue) loop() {
+	for {
+		select {
+		case msg := <-q.msgs:
+			q.send(msg)
+		case <-q.quit:
+			return
+		}
+	}
+}
+
+// send sends the consensus message, retrying on a failure.
+func (q *relayQueue) send(msg *istanbul.ConsensusMsg) {
+	for attempt := 0; ; attempt++ {
+		err := q.peer.Send(backend.IstanbulMsg, msg)
+		if err == nil {
+			consensusRelayCounter.Inc(1)
+			return
+		}
+		if attempt == relayRetries {
+			consensusRelayFailCounter.Inc(1)
+			logger.Warn("Failed to relay consensus message", "id", q.peer.GetP2PPeerID(), "err", err)
+			return
+		}
+		select {
+		case <-time.After(relayRetryDelay):
+		case <-q.quit:
+			return
+		}
+	}
+}
+
+// isRelaySource returns true if the consensus message of the peer can be
+// relayed, i.e. the peer is a trusted node or the message is admitted by the
+// validator proxy from its active upstream.
+func (pm *ProtocolManager) isRelaySource(p Peer, msg *istanbul.ConsensusMsg) bool {
+	if id := p.GetP2PPeerID(); pm.proxy.isUpstream(id) {
+		return pm.proxy.admit(id, msg)
+	}
+	p2pPeer := p.GetP2PPeer()
+	return p2pPeer != nil && p2pPeer.Trusted()
+}
+
+// relayConsensusMsg forwards the consensus message received from the trusted
+// peer to the relay targets except the peer. The messages from the other peers
+// and the messages already relayed are dropped.
+func (pm *ProtocolManager) relayConsensusMsg(p Peer, msg p2p.Msg) error {
+	var cmsg istanbul.ConsensusMsg
+	if err := msg.Decode(&cmsg); err != nil {
+		return errResp(ErrDecode, "msg %v: %v", msg, err)
+	}
+	if !pm.isRelaySource(p, &cmsg) {
+		consensusRelayUntrustedCounter.Inc(1)
+		return nil
+	}
+	if known, _ := pm.consensusRelay.known.ContainsOrAdd(istanbul.RLPHash(cmsg.Payload), struct{}{}); known {
+		return nil
+	}
+	pm.proxy.record(&cmsg)
+	for _, peer := range pm.relayTargets(p) {
+		pm.consensusRelay.enqueue(peer, &cmsg)
+	}
+	return nil
+}
+
+// relayTargets returns the CN peers and the trusted PN peers other than the given peer.
+func (pm *ProtocolManager) relayTargets(from Peer) []Peer {
+	var targets []Peer
+	for _, p := range pm.peers.CNPeers() {
+		if p != from && (pm.proxy == nil || !pm.proxy.isStandby(p.GetP2PPeerID())) {
+			targets = append(targets, p)
+		}
+	}
+	for _, p := range pm.peers.PNPeers() {
+		if p != from && p.GetP2PPeer().Trusted() {
+			targets = append(targets, p)
+		}
+	}
+	return targets
+}
+
+// sentryPeers returns all peers, which are the sentries of the validator running
+// in sentry mode, as the consensus peers. The sentries relay the consensus
+// messages to the other validators.
+func (pm *ProtocolManager) sentryPeers() map[common.Address]consensus.Peer {
+	m := make(map[common.Address]consensus.Peer)
+	for _, p := range pm.peers.Peers() {
+		m[p.GetAddr()] = p
+	}
+	return m
+}
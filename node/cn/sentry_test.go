@@ -0,0 +1,73 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus/istanbul"
+	"github.com/klaytn/klaytn/consensus/istanbul/backend"
+	"github.com/klaytn/klaytn/networks/p2p"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsensusRelay_RetryAndRemove(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	relay := newConsensusRelay()
+	id := discover.NodeID{1}
+	peer := NewMockPeer(mockCtrl)
+	peer.EXPECT().GetP2PPeerID().Return(id).AnyTimes()
+
+	// The message is sent again after a failure.
+	msg := &istanbul.ConsensusMsg{PrevHash: common.Hash{1}}
+	sent := make(chan struct{}, 1)
+	gomock.InOrder(
+		peer.EXPECT().Send(uint64(backend.IstanbulMsg), msg).Return(errors.New("write failed")).Times(1),
+		peer.EXPECT().Send(uint64(backend.IstanbulMsg), msg).Do(func(uint64, interface{}) { sent <- struct{}{} }).Return(nil).Times(1),
+	)
+	relay.enqueue(peer, msg)
+	<-sent
+
+	// The queue of the removed peer is stopped.
+	relay.removePeer(id)
+	relay.mu.Lock()
+	assert.Empty(t, relay.queues)
+	relay.mu.Unlock()
+}
+
+func TestConsensusRelay_UntrustedSource(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pm := &ProtocolManager{consensusRelay: newConsensusRelay()}
+	id := discover.NodeID{1}
+	peer := NewMockPeer(mockCtrl)
+	peer.EXPECT().GetP2PPeerID().Return(id).AnyTimes()
+	peer.EXPECT().GetP2PPeer().Return(p2p.NewPeer(id, "untrusted", []p2p.Cap{})).AnyTimes()
+
+	// The message of an untrusted peer is neither remembered nor relayed.
+	payload := []byte{1, 2, 3}
+	msg := generateMsg(t, backend.IstanbulMsg, &istanbul.ConsensusMsg{Payload: payload})
+	assert.NoError(t, pm.relayConsensusMsg(peer, msg))
+	assert.False(t, pm.consensusRelay.known.Contains(istanbul.RLPHash(payload)))
+}
@@ -57,6 +57,16 @@ const (
 	TrieNodesMsg        = 0x07
 )
 
+// CompressibleMsg reports whether the message of the given code carries
+// state ranges worth compressing.
+func CompressibleMsg(code uint64) bool {
+	switch code {
+	case AccountRangeMsg, StorageRangesMsg, ByteCodesMsg, TrieNodesMsg:
+		return true
+	}
+	return false
+}
+
 var (
 	errMsgTooLarge    = errors.New("message too long")
 	errDecode         = errors.New("invalid message")
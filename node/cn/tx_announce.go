@@ -0,0 +1,264 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/networks/p2p"
+)
+
+const (
+	maxTxAnnounces       = 4096            // Maximum number of transaction hashes in an announcement or a request
+	txFetchTimeout       = 5 * time.Second // Time allowed for an announcer to deliver the requested transactions
+	maxTrackedTxRequests = 65536           // Maximum number of transaction requests to keep track of
+	maxTxAlternates      = 16              // Maximum number of alternate announcers kept for a transaction
+)
+
+// txRequest is the state of a requested transaction.
+type txRequest struct {
+	requestedAt time.Time
+	from        Peer   // announcer the transaction is requested from
+	alternates  []Peer // announcers to request the transaction from if it is not delivered in time
+}
+
+// txRequestTracker dedups the requests of announced transactions so that a
+// transaction announced by many peers is requested from only one of them. The
+// other announcers are kept as alternates, and if the transaction is not
+// delivered within txFetchTimeout, it is requested from the next alternate.
+type txRequestTracker struct {
+	mu        sync.Mutex
+	requested *lru.Cache // tx hash -> *txRequest
+
+	now func() time.Time // for testing
+}
+
+func newTxRequestTracker() *txRequestTracker {
+	requested, _ := lru.New(maxTrackedTxRequests)
+	return &txRequestTracker{requested: requested, now: time.Now}
+}
+
+// announced returns the hashes to request from the announcer, which are not
+// being requested from another peer, and marks them as requested. The peer is
+// kept as an alternate of the other hashes.
+func (t *txRequestTracker) announced(p Peer, hashes []common.Hash) []common.Hash {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	unrequested := hashes[:0]
+	for _, hash := range hashes {
+		if v, ok := t.requested.Get(hash); ok {
+			req := v.(*txRequest)
+			if now.Sub(req.requestedAt) < txFetchTimeout {
+				if p != req.from && len(req.alternates) < maxTxAlternates && !hasPeer(req.alternates, p) {
+					req.alternates = append(req.alternates, p)
+				}
+				continue
+			}
+			req.requestedAt, req.from = now, p
+		} else {
+			t.requested.Add(hash, &txRequest{requestedAt: now, from: p})
+		}
+		unrequested = append(unrequested, hash)
+	}
+	return unrequested
+}
+
+// delivered stops tracking the requests of the delivered transactions.
+func (t *txRequestTracker) delivered(hashes []common.Hash) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, hash := range hashes {
+		t.requested.Remove(hash)
+	}
+}
+
+// expired returns the transactions not delivered in time grouped by the
+// alternates to request them from next. The transactions without any
+// alternate are no longer tracked.
+func (t *txRequestTracker) expired() map[Peer][]common.Hash {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	requests := make(map[Peer][]common.Hash)
+	for _, key := range t.requested.Keys() {
+		v, ok := t.requested.Peek(key)
+		if !ok {
+			continue
+		}
+		req := v.(*txRequest)
+		if now.Sub(req.requestedAt) < txFetchTimeout {
+			continue
+		}
+		hash := key.(common.Hash)
+		if len(req.alternates) == 0 {
+			t.requested.Remove(hash)
+			continue
+		}
+		next := req.alternates[0]
+		req.alternates, req.requestedAt, req.from = req.alternates[1:], now, next
+		requests[next] = append(requests[next], hash)
+	}
+	return requests
+}
+
+// hasPeer reports whether p is one of the given peers.
+func hasPeer(peers []Peer, p Peer) bool {
+	for _, peer := range peers {
+		if peer == p {
+			return true
+		}
+	}
+	return false
+}
+
+// txRequestLoop requests the transactions not delivered in time from their
+// alternate announcers.
+func (pm *ProtocolManager) txRequestLoop() {
+	ticker := time.NewTicker(txFetchTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for peer, hashes := range pm.txRequests.expired() {
+				txRerequestCounter.Inc(int64(len(hashes)))
+				for len(hashes) > 0 {
+					n := len(hashes)
+					if n > maxTxAnnounces {
+						n = maxTxAnnounces
+					}
+					if err := peer.RequestPooledTransactions(hashes[:n]); err != nil {
+						logger.Debug("Failed to request txs from alternate announcer", "peer", peer.GetID(), "numTxs", n, "err", err)
+						break
+					}
+					hashes = hashes[n:]
+				}
+			}
+		case <-pm.quitSync:
+			return
+		}
+	}
+}
+
+// handleNewPooledTransactionHashesMsg handles transaction announcement message.
+// It requests the announced transactions unknown to the node and not being
+// requested from other peers.
+func handleNewPooledTransactionHashesMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
+	if atomic.LoadUint32(&pm.acceptTxs) == 0 {
+		return nil
+	}
+	var hashes []common.Hash
+	if err := msg.Decode(&hashes); err != nil {
+		return errResp(ErrDecode, "msg %v: %v", msg, err)
+	}
+	if len(hashes) > maxTxAnnounces {
+		return errResp(ErrMsgTooLarge, "%d announced transactions > %d", len(hashes), maxTxAnnounces)
+	}
+	unknown := make([]common.Hash, 0, len(hashes))
+	for _, hash := range hashes {
+		p.AddToKnownTxs(hash)
+		if pm.txpool.Get(hash) == nil {
+			unknown = append(unknown, hash)
+		}
+	}
+	if pm.txRequests != nil {
+		unknown = pm.txRequests.announced(p, unknown)
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	return p.RequestPooledTransactions(unknown)
+}
+
+// handlePooledTransactionsRequestMsg handles transaction request message.
+// It sends the requested transactions in the pool.
+func handlePooledTransactionsRequestMsg(pm *ProtocolManager, p Peer, msg p2p.Msg) error {
+	var hashes []common.Hash
+	if err := msg.Decode(&hashes); err != nil {
+		return errResp(ErrDecode, "msg %v: %v", msg, err)
+	}
+	if len(hashes) > maxTxAnnounces {
+		return errResp(ErrMsgTooLarge, "%d requested transactions > %d", len(hashes), maxTxAnnounces)
+	}
+	var (
+		bytes common.StorageSize
+		txs   types.Transactions
+	)
+	for _, hash := range hashes {
+		if bytes >= softResponseLimit {
+			break
+		}
+		if tx := pm.txpool.Get(hash); tx != nil {
+			txs = append(txs, tx)
+			bytes += tx.Size()
+		}
+	}
+	return p.SendPooledTransactions(txs)
+}
+
+// sendOrAnnounceTransactions sends the transactions in full to the peers not
+// supporting klay66 and to the square root of the peers supporting it, and
+// announces only the hashes to the rest of the peers, which pull the
+// transactions they don't have.
+func sendOrAnnounceTransactions(txsSet map[Peer]types.Transactions) {
+	var announcees []Peer
+	for peer := range txsSet {
+		if peer.GetVersion() >= klay66 {
+			announcees = append(announcees, peer)
+		}
+	}
+	rand.Shuffle(len(announcees), func(i, j int) {
+		announcees[i], announcees[j] = announcees[j], announcees[i]
+	})
+	direct := int(math.Sqrt(float64(len(announcees))))
+	for _, peer := range announcees[direct:] {
+		txs := txsSet[peer]
+		delete(txsSet, peer)
+
+		hashes := make([]common.Hash, len(txs))
+		for i, tx := range txs {
+			hashes[i] = tx.Hash()
+		}
+		for len(hashes) > 0 {
+			n := len(hashes)
+			if n > maxTxAnnounces {
+				n = maxTxAnnounces
+			}
+			if err := peer.SendPooledTransactionHashes(hashes[:n]); err != nil {
+				logger.Error("Failed to announce txs", "peer", peer.GetAddr(), "peerType", peer.ConnType(), "numTxs", n, "err", err)
+				break
+			}
+			hashes = hashes[n:]
+		}
+		txAnnounceCounter.Inc(int64(len(txs)))
+	}
+	sendTransactions(txsSet)
+}
@@ -0,0 +1,139 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/work/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxRequestTracker(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tracker := newTxRequestTracker()
+	now := time.Unix(1600000000, 0)
+	tracker.now = func() time.Time { return now }
+
+	peer1, peer2, peer3 := NewMockPeer(mockCtrl), NewMockPeer(mockCtrl), NewMockPeer(mockCtrl)
+	h1, h2, h3 := common.Hash{1}, common.Hash{2}, common.Hash{3}
+	assert.Equal(t, []common.Hash{h1}, tracker.announced(peer1, []common.Hash{h1}))
+
+	// h1 is being requested from peer1, so peer2 and peer3 are kept as the alternates.
+	assert.Equal(t, []common.Hash{h2}, tracker.announced(peer2, []common.Hash{h1, h2}))
+	assert.Empty(t, tracker.announced(peer3, []common.Hash{h1}))
+	assert.Equal(t, []common.Hash{h3}, tracker.announced(peer1, []common.Hash{h3}))
+	assert.Empty(t, tracker.expired())
+
+	// h2 is delivered, and h3 has no alternate.
+	tracker.delivered([]common.Hash{h2})
+
+	// h1 is requested from the alternates in turn if it is not delivered in time.
+	now = now.Add(txFetchTimeout)
+	assert.Equal(t, map[Peer][]common.Hash{peer2: {h1}}, tracker.expired())
+	assert.False(t, tracker.requested.Contains(h2))
+	assert.False(t, tracker.requested.Contains(h3))
+	now = now.Add(txFetchTimeout)
+	assert.Equal(t, map[Peer][]common.Hash{peer3: {h1}}, tracker.expired())
+	now = now.Add(txFetchTimeout)
+	assert.Empty(t, tracker.expired())
+	assert.False(t, tracker.requested.Contains(h1))
+
+	// The timed-out transaction announced again is requested from the announcer.
+	assert.Equal(t, []common.Hash{h2}, tracker.announced(peer1, []common.Hash{h2}))
+	now = now.Add(txFetchTimeout)
+	assert.Equal(t, []common.Hash{h2}, tracker.announced(peer2, []common.Hash{h2}))
+
+	var nilTracker *txRequestTracker
+	nilTracker.delivered([]common.Hash{h1})
+}
+
+func TestHandleNewPooledTransactionHashesMsg(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockTxPool := mocks.NewMockTxPool(mockCtrl)
+	pm := &ProtocolManager{txpool: mockTxPool, txRequests: newTxRequestTracker()}
+	atomic.StoreUint32(&pm.acceptTxs, 1)
+
+	known, unknown := common.Hash{1}, common.Hash{2}
+	mockTxPool.EXPECT().Get(known).Return(tx1).AnyTimes()
+	mockTxPool.EXPECT().Get(unknown).Return(nil).AnyTimes()
+
+	peer1, peer2 := NewMockPeer(mockCtrl), NewMockPeer(mockCtrl)
+	for _, peer := range []*MockPeer{peer1, peer2} {
+		peer.EXPECT().GetVersion().Return(klay66).AnyTimes()
+		peer.EXPECT().AddToKnownTxs(gomock.Any()).Times(2)
+	}
+	// The unknown transaction is requested only from the first announcer.
+	peer1.EXPECT().RequestPooledTransactions([]common.Hash{unknown}).Return(nil).Times(1)
+	peer2.EXPECT().RequestPooledTransactions(gomock.Any()).Times(0)
+
+	msg := generateMsg(t, NewPooledTransactionHashesMsg, []common.Hash{known, unknown})
+	assert.NoError(t, pm.handleMsg(peer1, addrs[0], msg))
+	msg = generateMsg(t, NewPooledTransactionHashesMsg, []common.Hash{known, unknown})
+	assert.NoError(t, pm.handleMsg(peer2, addrs[1], msg))
+}
+
+func TestHandlePooledTransactionsRequestMsg(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockTxPool := mocks.NewMockTxPool(mockCtrl)
+	pm := &ProtocolManager{txpool: mockTxPool}
+
+	mockTxPool.EXPECT().Get(hash1).Return(tx1).Times(1)
+	mockTxPool.EXPECT().Get(common.Hash{2}).Return(nil).Times(1)
+
+	mockPeer := NewMockPeer(mockCtrl)
+	mockPeer.EXPECT().GetVersion().Return(klay66).AnyTimes()
+	mockPeer.EXPECT().SendPooledTransactions(types.Transactions{tx1}).Return(nil).Times(1)
+
+	msg := generateMsg(t, PooledTransactionsRequestMsg, []common.Hash{hash1, {2}})
+	assert.NoError(t, pm.handleMsg(mockPeer, addrs[0], msg))
+}
+
+func TestSendOrAnnounceTransactions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	txs := types.Transactions{tx1}
+	legacy := NewMockPeer(mockCtrl)
+	legacy.EXPECT().GetVersion().Return(klay65).AnyTimes()
+	legacy.EXPECT().SendTransactions(txs).Return(nil).Times(1)
+
+	// Out of 4 peers supporting klay66, 2 receive the transactions and the others receive the hashes.
+	txsSet := map[Peer]types.Transactions{legacy: txs}
+	sent, announced := 0, 0
+	for i := 0; i < 4; i++ {
+		peer := NewMockPeer(mockCtrl)
+		peer.EXPECT().GetVersion().Return(klay66).AnyTimes()
+		peer.EXPECT().SendTransactions(txs).DoAndReturn(func(types.Transactions) error { sent++; return nil }).MaxTimes(1)
+		peer.EXPECT().SendPooledTransactionHashes([]common.Hash{hash1}).DoAndReturn(func([]common.Hash) error { announced++; return nil }).MaxTimes(1)
+		txsSet[peer] = txs
+	}
+	sendOrAnnounceTransactions(txsSet)
+	assert.Equal(t, 2, sent)
+	assert.Equal(t, 2, announced)
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package cn
+
+import (
+	"fmt"
+
+	"github.com/klaytn/klaytn/blockchain"
+	"github.com/klaytn/klaytn/consensus"
+	"github.com/klaytn/klaytn/consensus/istanbul"
+	"github.com/klaytn/klaytn/governance"
+	"github.com/klaytn/klaytn/node"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/reward"
+)
+
+// VerifyBlocks re-executes the blocks from `from` to `to`, or to the head block if
+// `to` is 0, in the chain database of a stopped node, and checks them by
+// BlockChain.VerifyBlocks. The chain and the
+// consensus engine are set up as New does, but the networking, the transaction
+// pool and the block generation are not started.
+func VerifyBlocks(ctx *node.ServiceContext, config *Config, from, to uint64) error {
+	chainDB := CreateDB(ctx, config, "chaindata")
+	defer chainDB.Close()
+
+	chainConfig, _, genesisErr := blockchain.SetupGenesisBlock(chainDB, config.Genesis, config.NetworkId, config.IsPrivate, false)
+	if _, ok := genesisErr.(*params.ConfigCompatError); genesisErr != nil && !ok {
+		return genesisErr
+	}
+	setEngineType(chainConfig)
+
+	gov := governance.NewMixedEngine(chainConfig, chainDB)
+	chainConfig.UnitPrice = gov.UnitPrice()
+	chainConfig.Governance.KIP71 = &params.KIP71Config{
+		LowerBoundBaseFee:         gov.LowerBoundBaseFee(),
+		UpperBoundBaseFee:         gov.UpperBoundBaseFee(),
+		GasTarget:                 gov.GasTarget(),
+		MaxBlockGasUsedForBaseFee: gov.MaxBlockGasUsedForBaseFee(),
+		BaseFeeDenominator:        gov.BaseFeeDenominator(),
+	}
+	engine := CreateConsensusEngine(ctx, config, chainConfig, chainDB, gov, ctx.NodeType())
+
+	cacheConfig := &blockchain.CacheConfig{
+		ArchiveMode: config.NoPruning, CacheSize: config.TrieCacheSize,
+		BlockInterval: config.TrieBlockInterval, TriesInMemory: config.TriesInMemory, TrieNodeRefCount: config.TrieNodeRefCount,
+		TrieNodeCacheConfig: &config.TrieNodeCacheConfig, SnapshotCacheSize: config.SnapshotCacheSize,
+	}
+	bc, err := blockchain.NewBlockChain(chainDB, cacheConfig, chainConfig, engine, config.getVMConfig())
+	if err != nil {
+		return fmt.Errorf("failed to load the chain: %v", err)
+	}
+	defer bc.Stop()
+
+	gov.SetBlockchain(bc)
+	if bc.Config().Istanbul != nil {
+		bc.Config().Istanbul.ProposerPolicy = gov.ProposerPolicy()
+	}
+	if bc.Config().Governance.Reward != nil {
+		bc.Config().Governance.Reward.UseGiniCoeff = gov.UseGiniCoeff()
+	}
+	if gov.ProposerPolicy() == uint64(istanbul.WeightedRandom) {
+		reward.NewStakingManager(bc, gov, chainDB)
+	}
+	if istBackend, ok := engine.(consensus.Istanbul); ok {
+		istBackend.SetChain(bc)
+	}
+
+	if to == 0 {
+		to = bc.CurrentBlock().NumberU64()
+	}
+	return bc.VerifyBlocks(from, to)
+}
@@ -28,9 +28,15 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/klaytn/klaytn/accounts"
+	"github.com/klaytn/klaytn/accounts/hdwallet"
 	"github.com/klaytn/klaytn/accounts/keystore"
+	"github.com/klaytn/klaytn/accounts/kms"
+	"github.com/klaytn/klaytn/accounts/pkcs11"
+	"github.com/klaytn/klaytn/accounts/remote"
+	"github.com/klaytn/klaytn/accounts/vault"
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/crypto"
 	"github.com/klaytn/klaytn/log"
@@ -46,6 +52,8 @@ const (
 	datadirStaticNodes     = "static-nodes.json"  // Path within the datadir to the static node list
 	datadirTrustedNodes    = "trusted-nodes.json" // Path within the datadir to the trusted node list
 	datadirNodeDatabase    = "nodes"              // Path within the datadir to store the node infos
+	datadirRuntimePeers    = "runtime-peers.json" // Path within the datadir to the peers managed at runtime
+	datadirPeerList        = "peer-list.json"     // Path within the datadir to the last fetched peer list
 )
 
 // Config represents a small collection of configuration values to fine tune the
@@ -90,6 +98,27 @@ type Config struct {
 	// scrypt KDF at the expense of security.
 	UseLightweightKDF bool `toml:",omitempty"`
 
+	// KeyStoreKDF chooses the KDF of the key store, scrypt or Argon2id, and its
+	// parameters. The parameters set here override the ones of UseLightweightKDF.
+	KeyStoreKDF keystore.KDFConfig
+
+	// KMS configures the accounts whose keys are kept in the cloud key management
+	// services, AWS KMS or Google Cloud KMS. They are discovered at the startup.
+	KMS kms.Config
+
+	// Vault configures the accounts whose encrypted keystores are kept in the
+	// KV secrets engine of HashiCorp Vault.
+	Vault vault.Config
+
+	// RemoteSigner configures the accounts whose keys are held by a remote signer,
+	// e.g. an MPC signing service, which may also sign the consensus messages.
+	RemoteSigner remote.Config
+
+	// PKCS11 configures the accounts whose keys are kept in a hardware security
+	// module reached by its PKCS#11 module, which may also sign the consensus
+	// messages.
+	PKCS11 pkcs11.Config
+
 	// IPCPath is the requested location to place the IPC endpoint. If the path is
 	// a simple file name, it is placed inside the data directory (or on the root
 	// pipe path on Windows), whereas if it's a resolvable path name (absolute or
@@ -131,6 +160,11 @@ type Config struct {
 	// interface.
 	HTTPTimeouts rpc.HTTPTimeouts
 
+	// ShutdownGracePeriod is the maximum time to wait for the RPC requests being
+	// executed to finish on shutdown, after the RPC endpoints stop accepting
+	// requests. The requests are cancelled immediately if it is zero.
+	ShutdownGracePeriod time.Duration
+
 	// WSHost is the host interface on which to start the websocket RPC server. If
 	// this field is empty, no websocket API endpoint will be started.
 	WSHost string `toml:",omitempty"`
@@ -166,6 +200,21 @@ type Config struct {
 	// ephemeral nodes).
 	GRPCPort int `toml:",omitempty"`
 
+	// PeerListURL is the URL of the signed list of boot nodes and static peers.
+	// If it is empty, the peer list is not fetched.
+	PeerListURL string `toml:",omitempty"`
+
+	// PeerListSigner is the address of the key signing the peer list.
+	PeerListSigner common.Address `toml:",omitempty"`
+
+	// PeerListKeyFile is the path to the hex encoded 32-byte key decrypting
+	// the peer list. It is required only if the peer list is encrypted.
+	PeerListKeyFile string `toml:",omitempty"`
+
+	// PeerListRefreshInterval is the interval of fetching the peer list.
+	// Zero defaults to DefaultPeerListRefreshInterval.
+	PeerListRefreshInterval time.Duration `toml:",omitempty"`
+
 	// Logger is a custom logger to use with the p2p.Server.
 	Logger log.Logger `toml:",omitempty"`
 }
@@ -294,6 +343,8 @@ var isKlaytnResource = map[string]bool{
 	"nodekey":            true,
 	"static-nodes.json":  true,
 	"trusted-nodes.json": true,
+	"runtime-peers.json": true,
+	"peer-list.json":     true,
 }
 
 // ResolvePath resolves path in the instance directory.
@@ -406,13 +457,19 @@ func (c *Config) parsePersistentNodes(path string) []*discover.Node {
 	return nodes
 }
 
-// AccountConfig determines the settings for scrypt and keydirectory
-func (c *Config) AccountConfig() (int, int, string, error) {
-	scryptN := keystore.StandardScryptN
-	scryptP := keystore.StandardScryptP
+// AccountConfig determines the settings for the KDF and keydirectory
+func (c *Config) AccountConfig() (keystore.KDFConfig, string, error) {
+	kdf := c.KeyStoreKDF
 	if c.UseLightweightKDF {
-		scryptN = keystore.LightScryptN
-		scryptP = keystore.LightScryptP
+		if kdf.ScryptN == 0 {
+			kdf.ScryptN = keystore.LightScryptN
+		}
+		if kdf.ScryptP == 0 {
+			kdf.ScryptP = keystore.LightScryptP
+		}
+	}
+	if err := kdf.Validate(); err != nil {
+		return kdf, "", err
 	}
 
 	var (
@@ -431,11 +488,11 @@ func (c *Config) AccountConfig() (int, int, string, error) {
 	case c.KeyStoreDir != "":
 		keydir, err = filepath.Abs(c.KeyStoreDir)
 	}
-	return scryptN, scryptP, keydir, err
+	return kdf, keydir, err
 }
 
 func makeAccountManager(conf *Config) (*accounts.Manager, string, error) {
-	scryptN, scryptP, keydir, err := conf.AccountConfig()
+	kdf, keydir, err := conf.AccountConfig()
 	var ephemeral string
 	if keydir == "" {
 		// There is no datadir.
@@ -450,8 +507,41 @@ func makeAccountManager(conf *Config) (*accounts.Manager, string, error) {
 		return nil, "", err
 	}
 	// Assemble the account manager and supported backends
+	hdBackend, err := hdwallet.NewBackend(filepath.Join(keydir, "hd"), kdf)
+	if err != nil {
+		return nil, "", err
+	}
 	backends := []accounts.Backend{
-		keystore.NewKeyStore(keydir, scryptN, scryptP),
+		keystore.NewKeyStoreWithKDF(keydir, kdf),
+		hdBackend,
+	}
+	if conf.KMS.Enabled() {
+		backend, err := kms.NewBackend(conf.KMS)
+		if err != nil {
+			return nil, "", err
+		}
+		backends = append(backends, backend)
+	}
+	if conf.Vault.Enabled() {
+		backend, err := vault.NewBackend(conf.Vault)
+		if err != nil {
+			return nil, "", err
+		}
+		backends = append(backends, backend)
+	}
+	if conf.RemoteSigner.Enabled() {
+		backend, err := remote.NewBackend(conf.RemoteSigner)
+		if err != nil {
+			return nil, "", err
+		}
+		backends = append(backends, backend)
+	}
+	if conf.PKCS11.Enabled() {
+		backend, err := pkcs11.NewBackend(conf.PKCS11)
+		if err != nil {
+			return nil, "", err
+		}
+		backends = append(backends, backend)
 	}
 	return accounts.NewManager(backends...), ephemeral, nil
 }
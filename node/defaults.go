@@ -27,6 +27,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/klaytn/klaytn/networks/rpc"
 
@@ -46,6 +47,8 @@ const (
 	DefaultP2PPort                = 32323
 	DefaultP2PSubPort             = 32324
 	DefaultMaxPhysicalConnections = 10 // Default the max number of node's physical connections
+
+	DefaultShutdownGracePeriod = 10 * time.Second // Default time to finish the RPC requests on shutdown
 )
 
 // DefaultConfig contains reasonable default settings.
@@ -59,6 +62,9 @@ var DefaultConfig = Config{
 	WSPort:           DefaultWSPort,
 	WSModules:        []string{"net", "web3"},
 	GRPCPort:         DefaultGRPCPort,
+
+	ShutdownGracePeriod: DefaultShutdownGracePeriod,
+
 	P2P: p2p.Config{
 		ListenAddr:             fmt.Sprintf(":%d", DefaultP2PPort),
 		MaxPhysicalConnections: DefaultMaxPhysicalConnections,
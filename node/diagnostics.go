@@ -0,0 +1,237 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime/pprof"
+	"time"
+
+	"github.com/klaytn/klaytn/api/debug"
+)
+
+const (
+	datadirDiagnostics = "diagnostics" // Path within the instance directory to store the diagnostics bundles
+
+	defaultDiagnosticsCPUProfile = 10 * time.Second
+	maxDiagnosticsCPUProfile     = 5 * time.Minute
+	diagnosticsLogTail           = 16 * 1024 * 1024 // Size of the end of the log file put into the bundle
+)
+
+// ConfigDumper returns the current configuration of the node in the format of
+// the configuration file, for the diagnostics bundle.
+type ConfigDumper func() ([]byte, error)
+
+// secretConfigLine matches the configuration lines holding the secrets, which
+// are redacted from the diagnostics bundle.
+var secretConfigLine = regexp.MustCompile(`(?i)^(\s*[A-Za-z0-9_]*(secret|password|passphrase|accesskey|encryptionkey|token|privatekey)[A-Za-z0-9_]*\s*[=:]\s*)(.+)$`)
+
+// SetConfigDumper sets the function dumping the configuration of the node for
+// the diagnostics bundle.
+func (n *Node) SetConfigDumper(dumper ConfigDumper) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.configDumper = dumper
+}
+
+// diagnosticsRPCs are the RPC methods whose results are put into the bundle. The
+// methods not provided by the node are recorded in errors.txt.
+var diagnosticsRPCs = map[string]string{
+	"admin_nodeInfo.json":   "admin_nodeInfo",
+	"admin_peers.json":      "admin_peers",
+	"klay_syncing.json":     "klay_syncing",
+	"klay_blockNumber.json": "klay_blockNumber",
+	"debug_dbStats.json":    "debug_dbStats",
+	"debug_metrics.json":    "debug_metrics",
+}
+
+// CollectDiagnostics writes a gzipped tar archive for the support escalations
+// into the diagnostics directory of the instance directory, and returns its
+// path. The archive has the goroutine dump, the heap profile, the CPU profile
+// taken for cpuProfile, the end of the log file, the configuration with the
+// secrets redacted, the node and peer information, the sync status, the
+// database stats and the metrics. The items which can't be collected are
+// listed in errors.txt instead of failing the whole bundle.
+func (n *Node) CollectDiagnostics(cpuProfile time.Duration) (string, error) {
+	if cpuProfile > maxDiagnosticsCPUProfile {
+		return "", fmt.Errorf("CPU profile duration %v exceeds %v", cpuProfile, maxDiagnosticsCPUProfile)
+	}
+	dir := n.config.ResolvePath(datadirDiagnostics)
+	if dir == "" {
+		return "", fmt.Errorf("no data directory to store the diagnostics bundle")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	path := filepath.Join(dir, fmt.Sprintf("diagnostics-%s.tar.gz", now.Format("20060102-150405")))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var (
+		gw     = gzip.NewWriter(f)
+		tw     = tar.NewWriter(gw)
+		errs   bytes.Buffer
+		prefix = fmt.Sprintf("diagnostics-%s/", now.Format("20060102-150405"))
+	)
+	add := func(name string, data []byte) error {
+		hdr := &tar.Header{Name: prefix + name, Mode: 0o600, Size: int64(len(data)), ModTime: now}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	collect := func(name string, fn func(w io.Writer) error) error {
+		var buf bytes.Buffer
+		if err := fn(&buf); err != nil {
+			fmt.Fprintf(&errs, "%s: %v\n", name, err)
+			return nil
+		}
+		return add(name, buf.Bytes())
+	}
+
+	if err := collect("goroutines.txt", func(w io.Writer) error {
+		return pprof.Lookup("goroutine").WriteTo(w, 2)
+	}); err != nil {
+		return "", err
+	}
+	if err := collect("heap.pprof", pprof.WriteHeapProfile); err != nil {
+		return "", err
+	}
+	if cpuProfile > 0 {
+		if err := collect("cpu.pprof", func(w io.Writer) error {
+			if err := pprof.StartCPUProfile(w); err != nil {
+				return err
+			}
+			time.Sleep(cpuProfile)
+			pprof.StopCPUProfile()
+			return nil
+		}); err != nil {
+			return "", err
+		}
+	}
+	if logFile := debug.Handler.LogFilePath(); logFile != "" {
+		if err := collect("node.log", func(w io.Writer) error {
+			return copyFileTail(w, logFile, diagnosticsLogTail)
+		}); err != nil {
+			return "", err
+		}
+	} else {
+		fmt.Fprintf(&errs, "node.log: the logs are written to the standard error, give --log.file to include them\n")
+	}
+
+	n.lock.RLock()
+	dumper := n.configDumper
+	n.lock.RUnlock()
+	if err := collect("config.toml", func(w io.Writer) error {
+		if dumper == nil {
+			return fmt.Errorf("no configuration dumper")
+		}
+		config, err := dumper()
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(redactConfig(config))
+		return err
+	}); err != nil {
+		return "", err
+	}
+
+	client, err := n.Attach()
+	if err != nil {
+		fmt.Fprintf(&errs, "rpc: %v\n", err)
+	} else {
+		defer client.Close()
+		for name, method := range diagnosticsRPCs {
+			var args []interface{}
+			if method == "debug_metrics" {
+				args = append(args, false)
+			}
+			if err := collect(name, func(w io.Writer) error {
+				var result json.RawMessage
+				if err := client.Call(&result, method, args...); err != nil {
+					return err
+				}
+				var out bytes.Buffer
+				if err := json.Indent(&out, result, "", "  "); err != nil {
+					return err
+				}
+				_, err := out.WriteTo(w)
+				return err
+			}); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if errs.Len() > 0 {
+		if err := add("errors.txt", errs.Bytes()); err != nil {
+			return "", err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return "", err
+	}
+	if err := gw.Close(); err != nil {
+		return "", err
+	}
+	n.logger.Info("Collected the diagnostics bundle", "path", path)
+	return path, f.Close()
+}
+
+// copyFileTail copies the last size bytes of the file to w.
+func copyFileTail(w io.Writer, path string, size int64) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() > size {
+		if _, err := f.Seek(info.Size()-size, io.SeekStart); err != nil {
+			return err
+		}
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// redactConfig replaces the values of the secret keys of the configuration.
+func redactConfig(config []byte) []byte {
+	lines := bytes.Split(config, []byte("\n"))
+	for i, line := range lines {
+		lines[i] = secretConfigLine.ReplaceAll(line, []byte(`${1}"<redacted>"`))
+	}
+	return bytes.Join(lines, []byte("\n"))
+}
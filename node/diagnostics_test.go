@@ -0,0 +1,94 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectDiagnostics(t *testing.T) {
+	dir, err := ioutil.TempDir("", "node-diagnostics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := testNodeConfig()
+	config.DataDir = dir
+	stack, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stack.SetConfigDumper(func() ([]byte, error) {
+		return []byte("[Node]\nName = \"test node\"\nAccessKey = \"AKIA\"\n\n[CN.KASConfig]\nSecretKey = \"secret\"\n"), nil
+	})
+
+	// The node is not started, so the RPC results are recorded as errors.
+	path, err := stack.CollectDiagnostics(0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, filepath.Join(dir, "test node", datadirDiagnostics), filepath.Dir(path))
+
+	files := readDiagnostics(t, path)
+	assert.Contains(t, files, "goroutines.txt")
+	assert.Contains(t, files, "heap.pprof")
+	assert.NotContains(t, files, "cpu.pprof")
+	assert.Contains(t, files["goroutines.txt"], "TestCollectDiagnostics")
+	assert.Equal(t, "[Node]\nName = \"test node\"\nAccessKey = \"<redacted>\"\n\n[CN.KASConfig]\nSecretKey = \"<redacted>\"\n", files["config.toml"])
+	assert.Contains(t, files["errors.txt"], "rpc: "+ErrNodeStopped.Error())
+
+	_, err = stack.CollectDiagnostics(maxDiagnosticsCPUProfile + 1)
+	assert.Error(t, err)
+}
+
+// readDiagnostics returns the contents of the files in the diagnostics bundle by
+// their names without the directory.
+func readDiagnostics(t *testing.T, path string) map[string]string {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gr)
+	files := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[hdr.Name[strings.Index(hdr.Name, "/")+1:]] = string(data)
+	}
+	return files
+}
@@ -30,6 +30,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/klaytn/klaytn/accounts"
 	"github.com/klaytn/klaytn/api/debug"
@@ -38,6 +39,7 @@ import (
 	metricutils "github.com/klaytn/klaytn/metrics/utils"
 	"github.com/klaytn/klaytn/networks/grpc"
 	"github.com/klaytn/klaytn/networks/p2p"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
 	"github.com/klaytn/klaytn/networks/rpc"
 	"github.com/klaytn/klaytn/storage/database"
 	"github.com/prometheus/client_golang/prometheus"
@@ -57,6 +59,8 @@ type Node struct {
 
 	serverConfig p2p.Config
 	server       p2p.Server
+	runtimePeers *runtimePeerStore // peers added or removed via admin APIs
+	peerList     *peerListFetcher  // nil if the peer list URL is not configured
 
 	coreServiceFuncs []ServiceConstructor
 	serviceFuncs     []ServiceConstructor
@@ -87,6 +91,9 @@ type Node struct {
 	stop chan struct{} // Channel to wait for termination notifications
 	lock sync.RWMutex
 
+	configReloader ConfigReloader // Function reloading the configuration file (nil = not reloadable)
+	configDumper   ConfigDumper   // Function dumping the configuration for the diagnostics (nil = not available)
+
 	logger log.Logger
 }
 
@@ -190,6 +197,29 @@ func (n *Node) Start() error {
 	if n.serverConfig.NodeDatabase == "" {
 		n.serverConfig.NodeDatabase = n.config.NodeDB()
 	}
+	// Restore the peers managed at runtime before the last shutdown.
+	n.runtimePeers = newRuntimePeerStore(n.config.ResolvePath(datadirRuntimePeers))
+	runtimePeers := n.runtimePeers.list()
+	n.serverConfig.StaticNodes = mergeNodes(n.serverConfig.StaticNodes, runtimePeers.Static)
+	n.serverConfig.TrustedNodes = mergeNodes(n.serverConfig.TrustedNodes, runtimePeers.Trusted)
+	configuredStatics := n.serverConfig.StaticNodes
+
+	// Apply the distributed peer list, falling back to the cached one.
+	n.peerList = nil
+	if n.config.PeerListURL != "" {
+		peerList, err := newPeerListFetcher(n.config)
+		if err != nil {
+			return err
+		}
+		if list := peerList.init(); list != nil {
+			n.logger.Info("Applying peer list", "version", list.Version, "bootnodes", len(list.Bootnodes), "static", len(list.Static))
+			if len(list.Bootnodes) > 0 {
+				n.serverConfig.BootstrapNodes = list.Bootnodes
+			}
+			n.serverConfig.StaticNodes = mergeNodes(n.serverConfig.StaticNodes, list.Static)
+		}
+		n.peerList = peerList
+	}
 
 	p2pServer := p2p.NewServer(n.serverConfig)
 	n.logger.Info("Starting peer-to-peer node", "instance", n.serverConfig.Name)
@@ -218,6 +248,22 @@ func (n *Node) Start() error {
 	if err := p2pServer.Start(); err != nil {
 		return convertFileLockError(err)
 	}
+	for _, ban := range runtimePeers.Bans {
+		var expiry time.Time
+		if ban.Expiry != nil {
+			expiry = *ban.Expiry
+		}
+		if ban.ID != "" {
+			id, err := discover.HexID(ban.ID)
+			if err != nil {
+				logger.Warn("Ignoring invalid banned node ID", "id", ban.ID, "err", err)
+				continue
+			}
+			p2pServer.BanPeer(id, expiry)
+		} else if err := p2pServer.BanIP(ban.Network, expiry); err != nil {
+			logger.Warn("Ignoring invalid banned network", "network", ban.Network, "err", err)
+		}
+	}
 
 	// Start each of the coreservices
 	coreStarted := []reflect.Type{}
@@ -271,6 +317,15 @@ func (n *Node) Start() error {
 	n.server = p2pServer
 	n.stop = make(chan struct{})
 
+	if n.peerList != nil {
+		// The static peers configured locally or added via admin APIs are kept
+		// even if they are removed from the peer list.
+		runtimePeerStore := n.runtimePeers
+		go n.peerList.run(p2pServer, func(node *discover.Node) bool {
+			return containsNode(configuredStatics, node) || containsNode(runtimePeerStore.list().Static, node)
+		})
+	}
+
 	// Register a labeled metric containing version and build information
 	// e.g.) klaytn_build_info{version="v1.8.4+b3ab199674" cpu_arch="darwin-arm64" go_version="go1.18.2"} 1
 	if metricutils.Enabled {
@@ -419,16 +474,20 @@ func (n *Node) startIPC(apis []rpc.API) error {
 
 // stopIPC terminates the IPC RPC endpoint.
 func (n *Node) stopIPC() {
+	n.closeIPCListener()
+	if n.ipcHandler != nil {
+		n.ipcHandler.Stop()
+		n.ipcHandler = nil
+	}
+}
+
+func (n *Node) closeIPCListener() {
 	if n.ipcListener != nil {
 		n.ipcListener.Close()
 		n.ipcListener = nil
 
 		n.logger.Info("IPC endpoint closed", "endpoint", n.ipcEndpoint)
 	}
-	if n.ipcHandler != nil {
-		n.ipcHandler.Stop()
-		n.ipcHandler = nil
-	}
 }
 
 // startgRPC initializes and starts the gRPC endpoint.
@@ -497,16 +556,20 @@ func (n *Node) startFastHTTP(endpoint string, apis []rpc.API, modules []string,
 
 // stopHTTP terminates the HTTP RPC endpoint.
 func (n *Node) stopHTTP() {
+	n.closeHTTPListener()
+	if n.httpHandler != nil {
+		n.httpHandler.Stop()
+		n.httpHandler = nil
+	}
+}
+
+func (n *Node) closeHTTPListener() {
 	if n.httpListener != nil {
 		n.httpListener.Close()
 		n.httpListener = nil
 
 		n.logger.Info("HTTP endpoint closed", "url", fmt.Sprintf("http://%s", n.httpEndpoint))
 	}
-	if n.httpHandler != nil {
-		n.httpHandler.Stop()
-		n.httpHandler = nil
-	}
 }
 
 // startWS initializes and starts the websocket RPC endpoint.
@@ -549,16 +612,49 @@ func (n *Node) startFastWS(endpoint string, apis []rpc.API, modules []string, ws
 
 // stopWS terminates the websocket RPC endpoint.
 func (n *Node) stopWS() {
+	n.closeWSListener()
+	if n.wsHandler != nil {
+		n.wsHandler.Stop()
+		n.wsHandler = nil
+	}
+}
+
+func (n *Node) closeWSListener() {
 	if n.wsListener != nil {
 		n.wsListener.Close()
 		n.wsListener = nil
 
 		n.logger.Info("WebSocket endpoint closed", "url", fmt.Sprintf("ws://%s", n.wsEndpoint))
 	}
-	if n.wsHandler != nil {
-		n.wsHandler.Stop()
-		n.wsHandler = nil
+}
+
+// drainRPC stops accepting the RPC requests, and lets the requests being
+// executed finish within the shutdown grace period.
+func (n *Node) drainRPC() {
+	n.closeWSListener()
+	n.closeHTTPListener()
+	n.closeIPCListener()
+
+	grace := n.config.ShutdownGracePeriod
+	if grace <= 0 {
+		return
 	}
+	n.logger.Info("Draining the RPC requests", "grace", grace)
+	deadline := time.Now().Add(grace)
+	var wg sync.WaitGroup
+	for name, handler := range map[string]*rpc.Server{"http": n.httpHandler, "ws": n.wsHandler, "ipc": n.ipcHandler} {
+		if handler == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(name string, handler *rpc.Server) {
+			defer wg.Done()
+			if left := handler.Drain(time.Until(deadline)); left > 0 {
+				n.logger.Warn("RPC requests are not finished in the grace period", "endpoint", name, "requests", left)
+			}
+		}(name, handler)
+	}
+	wg.Wait()
 }
 
 func (n *Node) stopgRPC() {
@@ -586,7 +682,9 @@ func (n *Node) Stop() error {
 		return ErrNodeStopped
 	}
 
-	// Terminate the API, services and the p2p server.
+	// Terminate the API, services and the p2p server. The RPC requests being
+	// executed are finished first, so that the services are stopped at rest.
+	n.drainRPC()
 	n.stopWS()
 	n.stopHTTP()
 	n.stopIPC()
@@ -608,6 +706,10 @@ func (n *Node) Stop() error {
 			failure.Services[kind] = err
 		}
 	}
+	if n.peerList != nil {
+		n.peerList.stop()
+		n.peerList = nil
+	}
 	n.server.Stop()
 	n.services = nil
 	n.server = nil
@@ -696,6 +798,39 @@ func (n *Node) Server() p2p.Server {
 	return n.server
 }
 
+// ConfigReloadResult is the result of reloading the configuration file.
+type ConfigReloadResult struct {
+	Applied         []string `json:"applied"`         // the changed keys applied to the running node
+	RestartRequired []string `json:"restartRequired"` // the changed keys which are applied after restart
+
+	OverriddenByFlags []string `json:"overriddenByFlags"` // the changed keys not applied since they are set by the flags
+}
+
+// ConfigReloader reloads the configuration file, and applies the reloadable
+// settings to the running node.
+type ConfigReloader func() (*ConfigReloadResult, error)
+
+// SetConfigReloader sets the function reloading the configuration file, which
+// is called by SIGHUP and admin_reloadConfig.
+func (n *Node) SetConfigReloader(reloader ConfigReloader) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.configReloader = reloader
+}
+
+// ReloadConfig reloads the configuration file, and returns the changed keys.
+func (n *Node) ReloadConfig() (*ConfigReloadResult, error) {
+	n.lock.RLock()
+	reloader := n.configReloader
+	n.lock.RUnlock()
+
+	if reloader == nil {
+		return nil, ErrNoConfigReloader
+	}
+	return reloader()
+}
+
 // Service retrieves a currently running service registered of a specific type.
 func (n *Node) Service(service interface{}) error {
 	n.lock.RLock()
@@ -725,6 +860,13 @@ func (n *Node) InstanceDir() string {
 	return n.config.instanceDir()
 }
 
+// ServiceContext returns a context for constructing a service or its parts
+// outside of the node, like the offline commands on the data directory. No
+// service is registered to the context.
+func (n *Node) ServiceContext() *ServiceContext {
+	return NewServiceContext(n.config, make(map[reflect.Type]Service), n.eventmux, n.accman)
+}
+
 // AccountManager retrieves the account manager used by the protocol stack.
 func (n *Node) AccountManager() *accounts.Manager {
 	return n.accman
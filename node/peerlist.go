@@ -0,0 +1,355 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/networks/p2p"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+)
+
+const (
+	DefaultPeerListRefreshInterval = 10 * time.Minute
+
+	peerListFetchTimeout = 30 * time.Second
+	maxPeerListSize      = 1024 * 1024
+)
+
+var (
+	errPeerListSigner     = errors.New("peer list is not signed by the trusted signer")
+	errPeerListEncrypted  = errors.New("peer list is encrypted but no decryption key is given")
+	errPeerListKeyLength  = errors.New("peer list key must be 32 bytes")
+	errPeerListCiphertext = errors.New("peer list ciphertext is too short")
+)
+
+// PeerList is the list of boot nodes and static peers distributed to a fleet of nodes.
+type PeerList struct {
+	// Version must increase whenever the list is changed. A list whose version is
+	// lower than the applied one, or equal to it with different contents, is
+	// rejected to prevent replaying old lists.
+	Version   uint64           `json:"version"`
+	Bootnodes []*discover.Node `json:"bootnodes"`
+	Static    []*discover.Node `json:"static"`
+}
+
+// SignedPeerList is the envelope of a peer list fetched from a URL.
+type SignedPeerList struct {
+	// Payload is the JSON encoded PeerList. If Encrypted is set, it is encrypted
+	// with AES-256-GCM and prefixed with the nonce.
+	Payload   hexutil.Bytes `json:"payload"`
+	Encrypted bool          `json:"encrypted,omitempty"`
+	// Signature is the signature of keccak256(Payload) by the trusted signer.
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// SignPeerList encodes, optionally encrypts and signs the peer list.
+// The list is encrypted if key is not nil.
+func SignPeerList(list *PeerList, prv *ecdsa.PrivateKey, key []byte) (*SignedPeerList, error) {
+	payload, err := json.Marshal(list)
+	if err != nil {
+		return nil, err
+	}
+	signed := &SignedPeerList{Payload: payload}
+	if key != nil {
+		if signed.Payload, err = encryptPeerList(payload, key); err != nil {
+			return nil, err
+		}
+		signed.Encrypted = true
+	}
+	if signed.Signature, err = crypto.Sign(crypto.Keccak256(signed.Payload), prv); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// VerifyPeerList verifies the signature of the peer list, decrypts it with
+// key if it is encrypted and decodes it.
+func VerifyPeerList(signed *SignedPeerList, signer common.Address, key []byte) (*PeerList, error) {
+	pub, err := crypto.SigToPub(crypto.Keccak256(signed.Payload), signed.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if crypto.PubkeyToAddress(*pub) != signer {
+		return nil, errPeerListSigner
+	}
+	payload := []byte(signed.Payload)
+	if signed.Encrypted {
+		if key == nil {
+			return nil, errPeerListEncrypted
+		}
+		if payload, err = decryptPeerList(payload, key); err != nil {
+			return nil, err
+		}
+	}
+	list := new(PeerList)
+	if err := json.Unmarshal(payload, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func newPeerListCipher(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, errPeerListKeyLength
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encryptPeerList(plaintext, key []byte) ([]byte, error) {
+	gcm, err := newPeerListCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func decryptPeerList(ciphertext, key []byte) ([]byte, error) {
+	gcm, err := newPeerListCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errPeerListCiphertext
+	}
+	return gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], nil)
+}
+
+// peerListFetcher fetches the peer list from a URL periodically and applies
+// the changes of the static peers to the p2p server. The last verified list is
+// cached in the data directory so that it is used if the URL is unreachable.
+type peerListFetcher struct {
+	url       string
+	signer    common.Address
+	key       []byte
+	interval  time.Duration
+	cachePath string // empty if the node has no data directory
+	client    *http.Client
+
+	mu      sync.Mutex
+	current *PeerList
+	quit    chan struct{}
+}
+
+func newPeerListFetcher(config *Config) (*peerListFetcher, error) {
+	f := &peerListFetcher{
+		url:       config.PeerListURL,
+		signer:    config.PeerListSigner,
+		interval:  config.PeerListRefreshInterval,
+		cachePath: config.ResolvePath(datadirPeerList),
+		client:    &http.Client{Timeout: peerListFetchTimeout},
+		quit:      make(chan struct{}),
+	}
+	if f.interval <= 0 {
+		f.interval = DefaultPeerListRefreshInterval
+	}
+	if config.PeerListKeyFile != "" {
+		hex, err := ioutil.ReadFile(config.PeerListKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if f.key, err = hexutil.Decode(strings.TrimSpace(string(hex))); err != nil {
+			return nil, fmt.Errorf("invalid peer list key: %v", err)
+		}
+		if len(f.key) != 32 {
+			return nil, errPeerListKeyLength
+		}
+	}
+	return f, nil
+}
+
+// init returns the peer list fetched from the URL, or the cached one if
+// fetching fails. It returns nil if neither is available.
+func (f *peerListFetcher) init() *PeerList {
+	if f.cachePath != "" {
+		if signed, err := f.loadCache(); err == nil {
+			if list, err := VerifyPeerList(signed, f.signer, f.key); err == nil {
+				f.current = list
+			} else {
+				logger.Warn("Ignoring invalid cached peer list", "path", f.cachePath, "err", err)
+			}
+		}
+	}
+	if list, err := f.fetch(); err != nil {
+		logger.Warn("Failed to fetch peer list", "url", f.url, "err", err)
+	} else {
+		f.current = list
+	}
+	return f.current
+}
+
+// fetch fetches and verifies the peer list. It rejects the lists older than
+// the current one and the lists of the current version with different
+// contents, and caches the new list.
+func (f *peerListFetcher) fetch() (*PeerList, error) {
+	resp, err := f.client.Get(f.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxPeerListSize))
+	if err != nil {
+		return nil, err
+	}
+	signed := new(SignedPeerList)
+	if err := json.Unmarshal(body, signed); err != nil {
+		return nil, err
+	}
+	list, err := VerifyPeerList(signed, f.signer, f.key)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.current != nil && list.Version <= f.current.Version {
+		if list.Version < f.current.Version || peerListHash(list) != peerListHash(f.current) {
+			return nil, fmt.Errorf("stale peer list: version %d, current %d", list.Version, f.current.Version)
+		}
+	}
+	if f.cachePath != "" {
+		if err := writeJSONFile(f.cachePath, signed); err != nil {
+			logger.Warn("Failed to cache peer list", "path", f.cachePath, "err", err)
+		}
+	}
+	return list, nil
+}
+
+func (f *peerListFetcher) loadCache() (*SignedPeerList, error) {
+	if _, err := os.Stat(f.cachePath); err != nil {
+		return nil, err
+	}
+	signed := new(SignedPeerList)
+	if err := common.LoadJSON(f.cachePath, signed); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// run refreshes the peer list every interval until stop is called. The static
+// peers added to or removed from the list are added to or removed from the
+// server, except the removed peers for which keep returns true. The changed
+// boot nodes replace the boot nodes of the running discovery.
+func (f *peerListFetcher) run(server p2p.Server, keep func(*discover.Node) bool) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.quit:
+			return
+		case <-ticker.C:
+			list, err := f.fetch()
+			if err != nil {
+				logger.Warn("Failed to refresh peer list", "url", f.url, "err", err)
+				continue
+			}
+			f.mu.Lock()
+			old := f.current
+			f.current = list
+			f.mu.Unlock()
+
+			if old == nil || list.Version != old.Version {
+				logger.Info("Applying refreshed peer list", "version", list.Version,
+					"bootnodes", len(list.Bootnodes), "static", len(list.Static))
+				f.apply(server, old, list, keep)
+			}
+		}
+	}
+}
+
+// apply adds the new static peers, removes the static peers not in the list
+// anymore and sets the boot nodes of the discovery if they are changed.
+func (f *peerListFetcher) apply(server p2p.Server, old, list *PeerList, keep func(*discover.Node) bool) {
+	if len(list.Bootnodes) > 0 && (old == nil || !sameNodes(old.Bootnodes, list.Bootnodes)) {
+		if err := server.SetBootnodes(list.Bootnodes); err != nil {
+			logger.Warn("Failed to apply the boot nodes of the peer list", "err", err)
+		}
+	}
+	if old != nil {
+		for _, n := range old.Static {
+			if !containsNode(list.Static, n) && !keep(n) {
+				server.RemovePeer(n)
+			}
+		}
+	}
+	for _, n := range list.Static {
+		if old == nil || !containsNode(old.Static, n) {
+			server.AddPeer(n)
+		}
+	}
+}
+
+func (f *peerListFetcher) stop() {
+	close(f.quit)
+}
+
+// peerListHash returns the hash of the contents of the peer list.
+func peerListHash(list *PeerList) common.Hash {
+	enc, _ := json.Marshal(list)
+	return crypto.Keccak256Hash(enc)
+}
+
+func sameNodes(a, b []*discover.Node) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, n := range a {
+		if !containsNode(b, n) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsNode(nodes []*discover.Node, n *discover.Node) bool {
+	for _, m := range nodes {
+		if m.ID == n.ID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignPeerList(t *testing.T) {
+	prv, _ := crypto.GenerateKey()
+	signer := crypto.PubkeyToAddress(prv.PublicKey)
+	key := common.Hex2Bytes("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+	list := &PeerList{Version: 3, Bootnodes: []*discover.Node{newTestNode(1)}, Static: []*discover.Node{newTestNode(2)}}
+
+	// Plain list
+	signed, err := SignPeerList(list, prv, nil)
+	assert.NoError(t, err)
+	verified, err := VerifyPeerList(signed, signer, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), verified.Version)
+	assert.Equal(t, []discover.NodeID{newTestNode(1).ID}, nodeIDs(verified.Bootnodes))
+	assert.Equal(t, []discover.NodeID{newTestNode(2).ID}, nodeIDs(verified.Static))
+
+	// Untrusted signer
+	_, err = VerifyPeerList(signed, common.Address{1}, nil)
+	assert.Equal(t, errPeerListSigner, err)
+
+	// Encrypted list
+	signed, err = SignPeerList(list, prv, key)
+	assert.NoError(t, err)
+	assert.True(t, signed.Encrypted)
+	_, err = VerifyPeerList(signed, signer, nil)
+	assert.Equal(t, errPeerListEncrypted, err)
+	verified, err = VerifyPeerList(signed, signer, key)
+	assert.NoError(t, err)
+	assert.Equal(t, []discover.NodeID{newTestNode(2).ID}, nodeIDs(verified.Static))
+
+	// Tampered payload
+	signed.Payload[len(signed.Payload)-1] ^= 0xff
+	_, err = VerifyPeerList(signed, signer, key)
+	assert.Error(t, err)
+}
+
+func TestPeerListFetcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "peer-list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	prv, _ := crypto.GenerateKey()
+	var served *SignedPeerList
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(served)
+	}))
+	defer server.Close()
+
+	config := &Config{Name: "test", DataDir: dir, PeerListURL: server.URL, PeerListSigner: crypto.PubkeyToAddress(prv.PublicKey)}
+	assert.NoError(t, os.MkdirAll(config.instanceDir(), 0700))
+	f, err := newPeerListFetcher(config)
+	assert.NoError(t, err)
+
+	served, _ = SignPeerList(&PeerList{Version: 2, Static: []*discover.Node{newTestNode(1)}}, prv, nil)
+	list := f.init()
+	if assert.NotNil(t, list) {
+		assert.Equal(t, uint64(2), list.Version)
+	}
+
+	// Older lists are rejected.
+	served, _ = SignPeerList(&PeerList{Version: 1}, prv, nil)
+	_, err = f.fetch()
+	assert.Error(t, err)
+
+	// A list of the current version is accepted only if the contents are the same.
+	served, _ = SignPeerList(&PeerList{Version: 2, Static: []*discover.Node{newTestNode(1)}}, prv, nil)
+	_, err = f.fetch()
+	assert.NoError(t, err)
+	served, _ = SignPeerList(&PeerList{Version: 2, Static: []*discover.Node{newTestNode(2)}}, prv, nil)
+	_, err = f.fetch()
+	assert.Error(t, err)
+
+	// The cached list is used if the URL is unreachable.
+	server.Close()
+	f, err = newPeerListFetcher(config)
+	assert.NoError(t, err)
+	list = f.init()
+	if assert.NotNil(t, list) {
+		assert.Equal(t, uint64(2), list.Version)
+		assert.Equal(t, []discover.NodeID{newTestNode(1).ID}, nodeIDs(list.Static))
+	}
+}
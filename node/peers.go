@@ -0,0 +1,238 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/networks/p2p"
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+)
+
+// RuntimePeers is the list of peers managed at runtime via admin APIs.
+type RuntimePeers struct {
+	Static  []*discover.Node `json:"static"`
+	Trusted []*discover.Node `json:"trusted"`
+	Bans    []*p2p.BanInfo   `json:"bans"`
+
+	// Banned is the list of banned nodes written by the older versions.
+	// It is converted to Bans on load.
+	Banned []*discover.Node `json:"banned,omitempty"`
+}
+
+// runtimePeerStore keeps the runtime peer modifications and persists them in
+// the data directory so that they survive restarts.
+type runtimePeerStore struct {
+	path  string // empty if the node has no data directory
+	mu    sync.Mutex
+	peers RuntimePeers
+}
+
+// newRuntimePeerStore loads the runtime peer modifications from the given path.
+func newRuntimePeerStore(path string) *runtimePeerStore {
+	s := &runtimePeerStore{path: path}
+	if path == "" {
+		return s
+	}
+	if _, err := os.Stat(path); err != nil {
+		return s
+	}
+	if err := common.LoadJSON(path, &s.peers); err != nil {
+		logger.Error("Failed to load runtime peers", "path", path, "err", err)
+		s.peers = RuntimePeers{}
+	}
+	for _, n := range s.peers.Banned {
+		s.peers.Bans = addIDBan(s.peers.Bans, n.ID, time.Time{})
+	}
+	s.peers.Banned = nil
+	return s
+}
+
+// list returns a copy of the runtime peer modifications. The expired bans are excluded.
+func (s *runtimePeerStore) list() RuntimePeers {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	peers := RuntimePeers{
+		Static:  append([]*discover.Node{}, s.peers.Static...),
+		Trusted: append([]*discover.Node{}, s.peers.Trusted...),
+		Bans:    []*p2p.BanInfo{},
+	}
+	now := time.Now()
+	for _, ban := range s.peers.Bans {
+		if ban.Expiry == nil || ban.Expiry.After(now) {
+			peers.Bans = append(peers.Bans, ban)
+		}
+	}
+	return peers
+}
+
+func (s *runtimePeerStore) addStatic(n *discover.Node) error {
+	return s.update(func(p *RuntimePeers) {
+		p.Static = addNode(p.Static, n)
+		p.Bans = removeBan(p.Bans, &p2p.BanInfo{ID: n.ID.String()})
+	})
+}
+
+func (s *runtimePeerStore) removeStatic(n *discover.Node) error {
+	return s.update(func(p *RuntimePeers) { p.Static = removeNode(p.Static, n) })
+}
+
+func (s *runtimePeerStore) addTrusted(n *discover.Node) error {
+	return s.update(func(p *RuntimePeers) {
+		p.Trusted = addNode(p.Trusted, n)
+		p.Bans = removeBan(p.Bans, &p2p.BanInfo{ID: n.ID.String()})
+	})
+}
+
+func (s *runtimePeerStore) removeTrusted(n *discover.Node) error {
+	return s.update(func(p *RuntimePeers) { p.Trusted = removeNode(p.Trusted, n) })
+}
+
+// banID adds the ban on the node ID. A zero expiry means the ban is permanent.
+// A banned node can't be static or trusted.
+func (s *runtimePeerStore) banID(id discover.NodeID, expiry time.Time) error {
+	return s.update(func(p *RuntimePeers) {
+		n := &discover.Node{ID: id}
+		p.Static = removeNode(p.Static, n)
+		p.Trusted = removeNode(p.Trusted, n)
+		p.Bans = addIDBan(p.Bans, id, expiry)
+	})
+}
+
+func (s *runtimePeerStore) unbanID(id discover.NodeID) error {
+	return s.update(func(p *RuntimePeers) { p.Bans = removeBan(p.Bans, &p2p.BanInfo{ID: id.String()}) })
+}
+
+// banIP adds the ban on the IP network in CIDR notation. A zero expiry means
+// the ban is permanent.
+func (s *runtimePeerStore) banIP(network string, expiry time.Time) error {
+	return s.update(func(p *RuntimePeers) {
+		ban := &p2p.BanInfo{Network: network, Expiry: banExpiry(expiry)}
+		p.Bans = append(removeBan(p.Bans, ban), ban)
+	})
+}
+
+func (s *runtimePeerStore) unbanIP(network string) error {
+	return s.update(func(p *RuntimePeers) { p.Bans = removeBan(p.Bans, &p2p.BanInfo{Network: network}) })
+}
+
+// update applies fn to the runtime peers and writes them to the disk.
+func (s *runtimePeerStore) update(fn func(*RuntimePeers)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fn(&s.peers)
+	s.peers.Bans = pruneBans(s.peers.Bans, time.Now())
+	if s.path == "" {
+		return nil
+	}
+	return writeJSONFile(s.path, s.peers)
+}
+
+// writeJSONFile writes v to the file in JSON. It writes to a temporary file
+// first not to leave a broken file on crash.
+func writeJSONFile(path string, v interface{}) error {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, content, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// addNode appends n to nodes if a node with the same ID does not exist.
+// If exists, the node is replaced with n to reflect the new endpoint.
+func addNode(nodes []*discover.Node, n *discover.Node) []*discover.Node {
+	for i, node := range nodes {
+		if node.ID == n.ID {
+			nodes[i] = n
+			return nodes
+		}
+	}
+	return append(nodes, n)
+}
+
+// removeNode removes the node with the same ID as n from nodes.
+func removeNode(nodes []*discover.Node, n *discover.Node) []*discover.Node {
+	for i, node := range nodes {
+		if node.ID == n.ID {
+			return append(nodes[:i], nodes[i+1:]...)
+		}
+	}
+	return nodes
+}
+
+// mergeNodes returns the nodes in base followed by the nodes in extra
+// whose IDs don't appear in base.
+func mergeNodes(base, extra []*discover.Node) []*discover.Node {
+	merged := append([]*discover.Node{}, base...)
+	for _, n := range extra {
+		dup := false
+		for _, b := range base {
+			if b.ID == n.ID {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			merged = append(merged, n)
+		}
+	}
+	return merged
+}
+
+func banExpiry(expiry time.Time) *time.Time {
+	if expiry.IsZero() {
+		return nil
+	}
+	return &expiry
+}
+
+func addIDBan(bans []*p2p.BanInfo, id discover.NodeID, expiry time.Time) []*p2p.BanInfo {
+	ban := &p2p.BanInfo{ID: id.String(), Expiry: banExpiry(expiry)}
+	return append(removeBan(bans, ban), ban)
+}
+
+// removeBan removes the ban on the same node ID or IP network as target from bans.
+func removeBan(bans []*p2p.BanInfo, target *p2p.BanInfo) []*p2p.BanInfo {
+	for i, ban := range bans {
+		if ban.ID == target.ID && ban.Network == target.Network {
+			return append(bans[:i], bans[i+1:]...)
+		}
+	}
+	return bans
+}
+
+// pruneBans removes the bans expired at now.
+func pruneBans(bans []*p2p.BanInfo, now time.Time) []*p2p.BanInfo {
+	pruned := bans[:0]
+	for _, ban := range bans {
+		if ban.Expiry == nil || ban.Expiry.After(now) {
+			pruned = append(pruned, ban)
+		}
+	}
+	return pruned
+}
@@ -0,0 +1,138 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn/networks/p2p/discover"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestNode(idx byte) *discover.Node {
+	var id discover.NodeID
+	id[0] = idx
+	return discover.NewNode(id, []byte{127, 0, 0, 1}, 32323, 32323, nil, discover.NodeTypeUnknown)
+}
+
+func TestRuntimePeerStore_Persistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "runtime-peers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, datadirRuntimePeers)
+
+	n1, n2, n3 := newTestNode(1), newTestNode(2), newTestNode(3)
+
+	s := newRuntimePeerStore(path)
+	assert.NoError(t, s.addStatic(n1))
+	assert.NoError(t, s.addStatic(n2))
+	assert.NoError(t, s.addTrusted(n2))
+	assert.NoError(t, s.addTrusted(n3))
+	assert.NoError(t, s.removeStatic(n1))
+	assert.NoError(t, s.banID(n3.ID, time.Time{}))
+	assert.NoError(t, s.banIP("10.0.0.0/8", time.Now().Add(time.Hour)))
+	assert.NoError(t, s.banIP("10.1.0.0/16", time.Now().Add(-time.Hour)))
+
+	// The modifications are restored from the file.
+	peers := newRuntimePeerStore(path).list()
+	assert.Equal(t, []discover.NodeID{n2.ID}, nodeIDs(peers.Static))
+	assert.Equal(t, []discover.NodeID{n2.ID}, nodeIDs(peers.Trusted))
+	if assert.Len(t, peers.Bans, 2) {
+		assert.Equal(t, n3.ID.String(), peers.Bans[0].ID)
+		assert.Nil(t, peers.Bans[0].Expiry)
+		assert.Equal(t, "10.0.0.0/8", peers.Bans[1].Network)
+		assert.NotNil(t, peers.Bans[1].Expiry)
+	}
+
+	// Adding a banned node as a trusted peer lifts the ban.
+	assert.NoError(t, s.addTrusted(n3))
+	assert.NoError(t, s.unbanIP("10.0.0.0/8"))
+	peers = newRuntimePeerStore(path).list()
+	assert.Equal(t, []discover.NodeID{n2.ID, n3.ID}, nodeIDs(peers.Trusted))
+	assert.Empty(t, peers.Bans)
+}
+
+func TestRuntimePeerStore_LegacyBanned(t *testing.T) {
+	dir, err := ioutil.TempDir("", "runtime-peers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, datadirRuntimePeers)
+
+	n1 := newTestNode(1)
+	assert.NoError(t, writeJSONFile(path, RuntimePeers{Banned: []*discover.Node{n1}}))
+
+	peers := newRuntimePeerStore(path).list()
+	if assert.Len(t, peers.Bans, 1) {
+		assert.Equal(t, n1.ID.String(), peers.Bans[0].ID)
+	}
+	assert.Empty(t, peers.Banned)
+}
+
+func TestParseBanTarget(t *testing.T) {
+	n1 := newTestNode(1)
+
+	id, network, err := parseBanTarget(n1.String())
+	assert.NoError(t, err)
+	assert.Equal(t, n1.ID, id)
+	assert.Nil(t, network)
+
+	id, network, err = parseBanTarget(n1.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, n1.ID, id)
+	assert.Nil(t, network)
+
+	_, network, err = parseBanTarget("192.168.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.0.1/32", network.String())
+
+	_, network, err = parseBanTarget("192.168.1.1/24")
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.0/24", network.String())
+
+	_, _, err = parseBanTarget("invalid")
+	assert.Error(t, err)
+}
+
+func TestRuntimePeerStore_NoDataDir(t *testing.T) {
+	s := newRuntimePeerStore("")
+	assert.NoError(t, s.addStatic(newTestNode(1)))
+	assert.Len(t, s.list().Static, 1)
+}
+
+func TestMergeNodes(t *testing.T) {
+	n1, n2, n3 := newTestNode(1), newTestNode(2), newTestNode(3)
+
+	merged := mergeNodes([]*discover.Node{n1, n2}, []*discover.Node{n2, n3})
+	assert.Equal(t, []discover.NodeID{n1.ID, n2.ID, n3.ID}, nodeIDs(merged))
+	assert.Equal(t, []discover.NodeID{n3.ID}, nodeIDs(mergeNodes(nil, []*discover.Node{n3})))
+}
+
+func nodeIDs(nodes []*discover.Node) []discover.NodeID {
+	ids := make([]discover.NodeID, 0, len(nodes))
+	for _, n := range nodes {
+		ids = append(ids, n.ID)
+	}
+	return ids
+}
@@ -0,0 +1,266 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package sc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+)
+
+// Gas price strategies of anchoring transactions.
+const (
+	AnchoringGasPriceParent  = "parent"  // Use the gas price of the parent chain
+	AnchoringGasPriceFixed   = "fixed"   // Use the configured gas price
+	AnchoringGasPricePremium = "premium" // Add the configured premium to the gas price of the parent chain
+)
+
+const (
+	DefaultAnchoringRetryGasBump    = 10 // Percentage of the gas price increased for each retry
+	DefaultAnchoringRetryBackoff    = 10 * time.Second
+	DefaultAnchoringRetryMaxBackoff = 10 * time.Minute
+	DefaultAnchoringMaxRetries      = 10
+)
+
+var errUnknownAnchoringGasPriceStrategy = errors.New("unknown anchoring gas price strategy")
+
+// anchoringRetry is an anchoring transaction rejected by the parent chain,
+// waiting to be signed again with the same nonce and a higher gas price.
+type anchoringRetry struct {
+	tx        *types.Transaction // Last signed anchoring transaction for the nonce
+	attempts  uint64             // Number of retries done for the nonce
+	nextRetry time.Time          // Time to sign and send the transaction again
+}
+
+// validateAnchoringConfig checks the gas price strategy of anchoring transactions.
+func validateAnchoringConfig(config *SCConfig) error {
+	switch config.AnchoringGasPriceStrategy {
+	case "", AnchoringGasPriceParent, AnchoringGasPricePremium:
+	case AnchoringGasPriceFixed:
+		if config.AnchoringGasPrice == 0 {
+			return fmt.Errorf("gas price should be set for the %q anchoring gas price strategy", AnchoringGasPriceFixed)
+		}
+	default:
+		return fmt.Errorf("%w: %q", errUnknownAnchoringGasPriceStrategy, config.AnchoringGasPriceStrategy)
+	}
+	return nil
+}
+
+// anchoringGasPrice returns the gas price of an anchoring transaction retried
+// for the given number of times. The price follows the configured strategy, is
+// bumped for each retry, and is limited by the configured cap.
+func (sbh *SubBridgeHandler) anchoringGasPrice(attempts uint64) *big.Int {
+	config := sbh.subbridge.config
+
+	var gasPrice *big.Int
+	switch config.AnchoringGasPriceStrategy {
+	case AnchoringGasPriceFixed:
+		gasPrice = new(big.Int).SetUint64(config.AnchoringGasPrice)
+	case AnchoringGasPricePremium:
+		gasPrice = new(big.Int).SetUint64(sbh.remoteGasPrice)
+		gasPrice.Mul(gasPrice, big.NewInt(int64(100+config.AnchoringGasPricePremium)))
+		gasPrice.Div(gasPrice, big.NewInt(100))
+	default:
+		gasPrice = new(big.Int).SetUint64(sbh.remoteGasPrice)
+	}
+	for i := uint64(0); i < attempts; i++ {
+		gasPrice.Mul(gasPrice, big.NewInt(int64(100+config.AnchoringRetryGasBump)))
+		gasPrice.Div(gasPrice, big.NewInt(100))
+	}
+	if limit := config.AnchoringGasPriceCap; limit != 0 && gasPrice.Cmp(new(big.Int).SetUint64(limit)) > 0 {
+		gasPrice.SetUint64(limit)
+	}
+	return gasPrice
+}
+
+// anchoringRetryBackoff returns the time to wait before the given retry. It is
+// doubled for each retry and limited by the configured maximum.
+func (sbh *SubBridgeHandler) anchoringRetryBackoff(attempts uint64) time.Duration {
+	config := sbh.subbridge.config
+
+	backoff := config.AnchoringRetryBackoff
+	for i := uint64(1); i < attempts; i++ {
+		backoff *= 2
+		if config.AnchoringRetryMaxBackoff != 0 && backoff >= config.AnchoringRetryMaxBackoff {
+			return config.AnchoringRetryMaxBackoff
+		}
+	}
+	return backoff
+}
+
+// newUnsignedAnchoringTx generates an unsigned anchoring transaction, which type is TxTypeChainDataAnchoring.
+func (sbh *SubBridgeHandler) newUnsignedAnchoringTx(nonce uint64, gasPrice *big.Int, anchoredData []byte) (*types.Transaction, error) {
+	values := map[types.TxValueKeyType]interface{}{
+		types.TxValueKeyNonce:        nonce,
+		types.TxValueKeyFrom:         *sbh.GetParentOperatorAddr(),
+		types.TxValueKeyGasLimit:     anchoringGasLimit(anchoredData),
+		types.TxValueKeyGasPrice:     gasPrice,
+		types.TxValueKeyAnchoredData: anchoredData,
+	}
+
+	txType := types.TxTypeChainDataAnchoring
+
+	if feePayer := sbh.subbridge.bridgeAccounts.GetParentOperatorFeePayer(); feePayer != (common.Address{}) {
+		values[types.TxValueKeyFeePayer] = feePayer
+		txType = types.TxTypeFeeDelegatedChainDataAnchoring
+	}
+	return types.NewTransactionWithMap(txType, values)
+}
+
+// resignAnchoringTx signs the anchored data of the given anchoring transaction
+// again with the given nonce and the gas price for the given number of retries.
+func (sbh *SubBridgeHandler) resignAnchoringTx(tx *types.Transaction, nonce uint64, attempts uint64) (*types.Transaction, error) {
+	data, err := tx.AnchoredData()
+	if err != nil {
+		return nil, err
+	}
+	unsignedTx, err := sbh.newUnsignedAnchoringTx(nonce, sbh.anchoringGasPrice(attempts), data)
+	if err != nil {
+		return nil, err
+	}
+	return sbh.subbridge.bridgeAccounts.pAccount.SignTx(unsignedTx)
+}
+
+// scheduleAnchoringRetry removes the anchoring transaction rejected by the parent
+// chain from the bridge tx pool and schedules to send it again with a higher gas
+// price. If the retries are exhausted, the anchoring is counted as failed.
+func (sbh *SubBridgeHandler) scheduleAnchoringRetry(tx *types.Transaction, reason string) {
+	sbh.LockParentOperator()
+	defer sbh.UnLockParentOperator()
+
+	if err := sbh.subbridge.GetBridgeTxPool().RemoveTx(tx); err != nil {
+		logger.Error("Failed to remove rejected anchoring tx", "txNonce", tx.Nonce(), "txHash", tx.Hash().String(), "err", err)
+	}
+
+	retry := sbh.anchoringRetries[tx.Nonce()]
+	if retry == nil {
+		retry = &anchoringRetry{}
+		sbh.anchoringRetries[tx.Nonce()] = retry
+	}
+	retry.tx = tx
+	retry.attempts++
+
+	if retry.attempts > sbh.subbridge.config.AnchoringMaxRetries {
+		delete(sbh.anchoringRetries, tx.Nonce())
+		failedAnchoringCounter.Inc(1)
+		logger.Error("Anchoring tx failed after retries", "txNonce", tx.Nonce(), "txHash", tx.Hash().String(),
+			"retries", retry.attempts-1, "reason", reason)
+		return
+	}
+	retry.nextRetry = time.Now().Add(sbh.anchoringRetryBackoff(retry.attempts))
+	logger.Warn("Scheduled anchoring tx retry", "txNonce", tx.Nonce(), "txHash", tx.Hash().String(),
+		"attempts", retry.attempts, "nextRetry", retry.nextRetry, "reason", reason)
+}
+
+// retryAnchoringTxs signs the scheduled anchoring transactions again with a
+// higher gas price and adds them into the bridge tx pool.
+func (sbh *SubBridgeHandler) retryAnchoringTxs() {
+	sbh.LockParentOperator()
+	defer sbh.UnLockParentOperator()
+
+	now := time.Now()
+	for nonce, retry := range sbh.anchoringRetries {
+		if now.Before(retry.nextRetry) {
+			continue
+		}
+		if nonce < sbh.parentNonce {
+			// The nonce is already used in the parent chain, leave it to the nonce gap repair
+			delete(sbh.anchoringRetries, nonce)
+			continue
+		}
+		tx, err := sbh.resignAnchoringTx(retry.tx, nonce, retry.attempts)
+		if err != nil {
+			logger.Error("Failed to sign anchoring tx for retry", "txNonce", nonce, "err", err)
+			continue
+		}
+		if err := sbh.subbridge.GetBridgeTxPool().AddLocal(tx); err != nil {
+			logger.Error("Failed to add retried anchoring tx into bridge txpool", "txNonce", nonce, "err", err)
+			continue
+		}
+		delete(sbh.anchoringRetries, nonce)
+		retriedAnchoringCounter.Inc(1)
+		logger.Info("Retried anchoring tx", "txNonce", nonce, "txHash", tx.Hash().String(),
+			"gasPrice", tx.GasPrice(), "attempts", retry.attempts)
+	}
+}
+
+// repairAnchoringNonceGap fills the gap between the nonce of the parent chain
+// operator in the parent chain and the lowest nonce of the pending transactions,
+// which makes all the pending transactions stuck. The pending anchoring
+// transactions are signed again with consecutive nonces from the parent chain
+// nonce. The caller should hold the parent operator lock.
+func (sbh *SubBridgeHandler) repairAnchoringNonceGap(parentNonce uint64) {
+	pool := sbh.subbridge.GetBridgeTxPool()
+	pending := pool.PendingTxsByAddress(sbh.GetParentOperatorAddr(), int(sbh.GetSentChainTxsLimit()))
+
+	var txs types.Transactions
+	for _, tx := range pending {
+		if tx.Nonce() >= parentNonce {
+			txs = append(txs, tx)
+		}
+	}
+	for nonce, retry := range sbh.anchoringRetries {
+		if nonce >= parentNonce {
+			txs = append(txs, retry.tx)
+		}
+	}
+	if len(txs) == 0 {
+		return
+	}
+	sort.Sort(types.TxByNonce(txs))
+	if txs[0].Nonce() == parentNonce {
+		return // No gap
+	}
+
+	logger.Warn("Repairing the nonce gap of anchoring txs", "parentNonce", parentNonce, "lowestNonce", txs[0].Nonce(), "txs", len(txs))
+	nonce, repaired := parentNonce, 0
+	for _, tx := range txs {
+		if !tx.Type().IsChainDataAnchoring() {
+			// Only anchoring txs can be signed again, the rest is left to the value transfer recovery
+			logger.Warn("Stopped repairing the nonce gap at a non-anchoring tx", "txNonce", tx.Nonce(), "txHash", tx.Hash().String())
+			break
+		}
+		newTx, err := sbh.resignAnchoringTx(tx, nonce, 0)
+		if err != nil {
+			logger.Error("Failed to sign anchoring tx for the nonce gap repair", "txNonce", tx.Nonce(), "err", err)
+			break
+		}
+		if pool.Get(tx.Hash()) != nil {
+			if err := pool.RemoveTx(tx); err != nil {
+				break
+			}
+		}
+		delete(sbh.anchoringRetries, tx.Nonce())
+		if err := pool.AddLocal(newTx); err != nil {
+			logger.Error("Failed to add repaired anchoring tx into bridge txpool", "txNonce", nonce, "err", err)
+			break
+		}
+		logger.Info("Repaired anchoring tx nonce", "oldNonce", tx.Nonce(), "newNonce", nonce, "txHash", newTx.Hash().String())
+		nonce++
+		repaired++
+	}
+	repairedAnchoringNonceCounter.Inc(int64(repaired))
+	if repaired == len(txs) {
+		// All the pending txs are renumbered, so the next tx follows them
+		sbh.setParentOperatorNonce(nonce)
+	}
+}
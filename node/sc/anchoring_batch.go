@@ -0,0 +1,105 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package sc
+
+import (
+	"fmt"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/rlp"
+)
+
+// DefaultAnchoringBatchMaxBlocks is the default maximum number of the blocks
+// anchored by a batched anchoring transaction, which keeps the transaction far
+// below the size limit of the tx pool.
+const DefaultAnchoringBatchMaxBlocks = 128
+
+const (
+	defaultAnchoringGasLimit = uint64(100000)
+	anchoringGasLimitMargin  = uint64(50000) // Gas for the signature validation and the fee delegation
+)
+
+// anchoringGasLimit returns the gas limit of an anchoring transaction, which
+// covers the intrinsic gas of the anchored data.
+func anchoringGasLimit(anchoredData []byte) uint64 {
+	gas := params.TxChainDataAnchoringGas + uint64(len(anchoredData))*params.ChainDataAnchoringGas + anchoringGasLimitMargin
+	if gas < defaultAnchoringGasLimit {
+		return defaultAnchoringGasLimit
+	}
+	return gas
+}
+
+// anchoringBatchPayloads returns the anchored data of all the blocks of the
+// anchoring period ending at the given block. The blocks are split into the
+// batches of at most AnchoringBatchMaxBlocks blocks, each compressed into the
+// type1 anchoring data.
+func (sbh *SubBridgeHandler) anchoringBatchPayloads(block *types.Block) ([][]byte, error) {
+	maxBlocks := sbh.subbridge.config.AnchoringBatchMaxBlocks
+	if maxBlocks == 0 {
+		maxBlocks = DefaultAnchoringBatchMaxBlocks
+	}
+	start := sbh.txCountStartingBlockNumber
+	if start == 0 || start > block.NumberU64() {
+		start = block.NumberU64()
+	}
+
+	var (
+		payloads [][]byte
+		blocks   []*types.AnchoringDataInternalType0
+	)
+	for num := start; num <= block.NumberU64(); num++ {
+		b := block
+		if num != block.NumberU64() {
+			if b = sbh.subbridge.blockchain.GetBlockByNumber(num); b == nil {
+				return nil, fmt.Errorf("missing block %d to anchor", num)
+			}
+		}
+		blocks = append(blocks, types.NewAnchoringDataInternalType0(b, 1, uint64(b.Transactions().Len())))
+		if uint64(len(blocks)) < maxBlocks && num != block.NumberU64() {
+			continue
+		}
+		anchoringData, err := types.NewAnchoringDataType1(blocks)
+		if err != nil {
+			return nil, err
+		}
+		payload, err := rlp.EncodeToBytes(anchoringData)
+		if err != nil {
+			return nil, err
+		}
+		payloads = append(payloads, payload)
+		blocks = nil
+	}
+	return payloads, nil
+}
+
+// genUnsignedBatchAnchoringTxs generates the unsigned batched anchoring
+// transactions of the anchoring period with the consecutive nonces.
+func (sbh *SubBridgeHandler) genUnsignedBatchAnchoringTxs(block *types.Block) ([]*types.Transaction, error) {
+	payloads, err := sbh.anchoringBatchPayloads(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce, gasPrice := sbh.getParentOperatorNonce(), sbh.anchoringGasPrice(0)
+	txs := make([]*types.Transaction, len(payloads))
+	for i, payload := range payloads {
+		if txs[i], err = sbh.newUnsignedAnchoringTx(nonce+uint64(i), gasPrice, payload); err != nil {
+			return nil, err
+		}
+	}
+	return txs, nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package sc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+)
+
+// maxAnchoringReorgEvents is the number of recent anchoring reorg events kept for the API.
+const maxAnchoringReorgEvents = 256
+
+// AnchoringReorgEvent describes an anchoring transaction dropped from the canonical chain
+// by a parent chain reorganization.
+type AnchoringReorgEvent struct {
+	Time             time.Time   `json:"time"`
+	BlockNumber      uint64      `json:"blockNumber"` // number of the parent chain block which has been reorganized out
+	BlockHash        common.Hash `json:"blockHash"`
+	TxHash           common.Hash `json:"txHash"`
+	ChildBlockNumber uint64      `json:"childBlockNumber"`
+	ChildBlockHash   common.Hash `json:"childBlockHash"`
+	Reanchored       bool        `json:"reanchored"` // whether the transaction is pending again to be anchored
+	Error            string      `json:"error,omitempty"`
+}
+
+// anchoringReorgs keeps the recent anchoring reorg events.
+type anchoringReorgs struct {
+	mu     sync.RWMutex
+	events []*AnchoringReorgEvent
+}
+
+func (r *anchoringReorgs) add(ev *AnchoringReorgEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, ev)
+	if len(r.events) > maxAnchoringReorgEvents {
+		r.events = r.events[len(r.events)-maxAnchoringReorgEvents:]
+	}
+}
+
+func (r *anchoringReorgs) list() []*AnchoringReorgEvent {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events := make([]*AnchoringReorgEvent, len(r.events))
+	copy(events, r.events)
+	return events
+}
+
+// HandleChainSideEvent finds the anchoring transactions of the block which has been reorganized out
+// of the canonical chain, and adds the ones not included in the new canonical chain into the tx pool again.
+func (mce *MainChainEventHandler) HandleChainSideEvent(block *types.Block) {
+	for _, tx := range block.Transactions() {
+		if !tx.Type().IsChainDataAnchoring() {
+			continue
+		}
+		txHash := tx.Hash()
+		if canonicalTx, _, _, _ := mce.mainbridge.blockchain.GetTxAndLookupInfo(txHash); canonicalTx != nil {
+			// The anchoring transaction is included in the new canonical chain as well.
+			continue
+		}
+
+		ev := &AnchoringReorgEvent{
+			Time:        time.Now(),
+			BlockNumber: block.NumberU64(),
+			BlockHash:   block.Hash(),
+			TxHash:      txHash,
+		}
+		if data, err := tx.AnchoredData(); err == nil {
+			if decodedData, err := types.DecodeAnchoringData(data); err == nil {
+				ev.ChildBlockNumber = decodedData.GetBlockNumber().Uint64()
+				ev.ChildBlockHash = decodedData.GetBlockHash()
+			}
+		}
+
+		// The tx pool may have injected the dropped transaction again while resetting its state.
+		if mce.mainbridge.txPool.Get(txHash) != nil {
+			ev.Reanchored = true
+		} else if err := mce.mainbridge.txPool.AddLocal(tx); err != nil {
+			ev.Error = err.Error()
+		} else {
+			ev.Reanchored = true
+		}
+
+		anchoringReorgCounter.Inc(1)
+		if ev.Reanchored {
+			reanchoredCounter.Inc(1)
+			logger.Warn("Anchoring tx dropped by a parent chain reorg is re-anchored", "blockNumber", ev.BlockNumber,
+				"txHash", txHash.String(), "childBlockNumber", ev.ChildBlockNumber)
+		} else {
+			logger.Error("Failed to re-anchor a tx dropped by a parent chain reorg", "blockNumber", ev.BlockNumber,
+				"txHash", txHash.String(), "childBlockNumber", ev.ChildBlockNumber, "err", ev.Error)
+		}
+		mce.reorgs.add(ev)
+	}
+}
+
+// GetAnchoringReorgs returns the recent anchoring transactions dropped by parent chain reorganizations.
+func (mce *MainChainEventHandler) GetAnchoringReorgs() []*AnchoringReorgEvent {
+	return mce.reorgs.list()
+}
@@ -0,0 +1,41 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package sc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAnchoringReorgs checks that only the recent anchoring reorg events are kept.
+func TestAnchoringReorgs(t *testing.T) {
+	var reorgs anchoringReorgs
+	assert.Empty(t, reorgs.list())
+
+	for i := 0; i < maxAnchoringReorgEvents+10; i++ {
+		reorgs.add(&AnchoringReorgEvent{BlockNumber: uint64(i)})
+	}
+	events := reorgs.list()
+	assert.Equal(t, maxAnchoringReorgEvents, len(events))
+	assert.Equal(t, uint64(10), events[0].BlockNumber)
+	assert.Equal(t, uint64(maxAnchoringReorgEvents+9), events[len(events)-1].BlockNumber)
+
+	// the returned list is not changed by the following events
+	reorgs.add(&AnchoringReorgEvent{BlockNumber: 1000})
+	assert.Equal(t, uint64(10), events[0].BlockNumber)
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package sc
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAnchoringTestHandler(config *SCConfig, remoteGasPrice uint64) *SubBridgeHandler {
+	return &SubBridgeHandler{
+		subbridge:        &SubBridge{config: config},
+		remoteGasPrice:   remoteGasPrice,
+		anchoringRetries: make(map[uint64]*anchoringRetry),
+	}
+}
+
+func TestValidateAnchoringConfig(t *testing.T) {
+	assert.NoError(t, validateAnchoringConfig(&SCConfig{}))
+	assert.NoError(t, validateAnchoringConfig(&SCConfig{AnchoringGasPriceStrategy: AnchoringGasPriceParent}))
+	assert.NoError(t, validateAnchoringConfig(&SCConfig{AnchoringGasPriceStrategy: AnchoringGasPricePremium}))
+	assert.NoError(t, validateAnchoringConfig(&SCConfig{AnchoringGasPriceStrategy: AnchoringGasPriceFixed, AnchoringGasPrice: 1}))
+
+	assert.Error(t, validateAnchoringConfig(&SCConfig{AnchoringGasPriceStrategy: AnchoringGasPriceFixed}))
+	err := validateAnchoringConfig(&SCConfig{AnchoringGasPriceStrategy: "unknown"})
+	assert.True(t, errors.Is(err, errUnknownAnchoringGasPriceStrategy))
+}
+
+func TestAnchoringGasPrice(t *testing.T) {
+	tests := []struct {
+		config   SCConfig
+		attempts uint64
+		expected int64
+	}{
+		{SCConfig{AnchoringGasPriceStrategy: AnchoringGasPriceParent}, 0, 1000},
+		{SCConfig{AnchoringGasPriceStrategy: AnchoringGasPriceFixed, AnchoringGasPrice: 3000}, 0, 3000},
+		{SCConfig{AnchoringGasPriceStrategy: AnchoringGasPricePremium, AnchoringGasPricePremium: 20}, 0, 1200},
+		{SCConfig{AnchoringGasPriceStrategy: AnchoringGasPriceParent, AnchoringRetryGasBump: 10}, 2, 1210},
+		{SCConfig{AnchoringGasPriceStrategy: AnchoringGasPriceParent, AnchoringRetryGasBump: 10, AnchoringGasPriceCap: 1100}, 2, 1100},
+	}
+	for _, tc := range tests {
+		config := tc.config
+		sbh := newAnchoringTestHandler(&config, 1000)
+		assert.Equal(t, big.NewInt(tc.expected), sbh.anchoringGasPrice(tc.attempts))
+	}
+}
+
+func TestAnchoringRetryBackoff(t *testing.T) {
+	sbh := newAnchoringTestHandler(&SCConfig{
+		AnchoringRetryBackoff:    time.Second,
+		AnchoringRetryMaxBackoff: 5 * time.Second,
+	}, 0)
+
+	assert.Equal(t, time.Second, sbh.anchoringRetryBackoff(1))
+	assert.Equal(t, 2*time.Second, sbh.anchoringRetryBackoff(2))
+	assert.Equal(t, 4*time.Second, sbh.anchoringRetryBackoff(3))
+	assert.Equal(t, 5*time.Second, sbh.anchoringRetryBackoff(4))
+	assert.Equal(t, 5*time.Second, sbh.anchoringRetryBackoff(100))
+}
+
+func TestAnchoringGasLimit(t *testing.T) {
+	assert.Equal(t, defaultAnchoringGasLimit, anchoringGasLimit(make([]byte, 100)))
+
+	// The gas limit covers the intrinsic gas of the large batched anchored data.
+	data := make([]byte, 10000)
+	assert.Equal(t, params.TxChainDataAnchoringGas+10000*params.ChainDataAnchoringGas+anchoringGasLimitMargin, anchoringGasLimit(data))
+}
+
+func TestAnchoringBatchPayloads(t *testing.T) {
+	sbh := newAnchoringTestHandler(&SCConfig{AnchoringBatch: true}, 0)
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(10)})
+	sbh.txCountStartingBlockNumber = 10
+
+	payloads, err := sbh.anchoringBatchPayloads(block)
+	assert.NoError(t, err)
+	assert.Len(t, payloads, 1)
+
+	decoded, err := types.DecodeAnchoringData(payloads[0])
+	assert.NoError(t, err)
+	batch, ok := decoded.(*types.AnchoringDataInternalType1)
+	assert.True(t, ok)
+	assert.Len(t, batch.Blocks, 1)
+	assert.Equal(t, block.Hash(), batch.GetBlockHash())
+	assert.Equal(t, uint64(1), batch.Blocks[0].BlockCount.Uint64())
+}
@@ -21,14 +21,17 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"time"
 
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
 	"github.com/klaytn/klaytn/contracts/bridge"
 	"github.com/klaytn/klaytn/networks/p2p"
 	"github.com/klaytn/klaytn/networks/p2p/discover"
 	"github.com/klaytn/klaytn/node"
 	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/rlp"
 	"github.com/pkg/errors"
 )
 
@@ -71,6 +74,12 @@ func (mb *MainBridgeAPI) ConvertChildChainBlockHashToParentChainTxHash(scBlockHa
 	return mb.mainBridge.eventhandler.ConvertChildChainBlockHashToParentChainTxHash(scBlockHash)
 }
 
+// GetAnchoringReorgs returns the recent anchoring transactions dropped by parent chain reorganizations
+// and whether they have been re-anchored.
+func (mb *MainBridgeAPI) GetAnchoringReorgs() []*AnchoringReorgEvent {
+	return mb.mainBridge.eventhandler.GetAnchoringReorgs()
+}
+
 // Peers retrieves all the information we know about each individual peer at the
 // protocol granularity.
 func (mb *MainBridgeAPI) Peers() ([]*p2p.PeerInfo, error) {
@@ -291,6 +300,51 @@ func (sb *SubBridgeAPI) GetBridgeInformation(bridgeAddr common.Address) (map[str
 	}, nil
 }
 
+// GetPendingTransfers returns the value transfers found by the value transfer recovery,
+// which are not handled by the counterpart bridge yet.
+func (sb *SubBridgeAPI) GetPendingTransfers() []*PendingTransfer {
+	return sb.subBridge.bridgeManager.GetPendingTransfers()
+}
+
+// RetryTransfer resends the pending value transfer requested on the given bridge with the given nonce,
+// even if it is dead-lettered after the maximum attempts.
+func (sb *SubBridgeAPI) RetryTransfer(bridgeAddr common.Address, requestNonce uint64) error {
+	return sb.subBridge.bridgeManager.RetryTransfer(bridgeAddr, requestNonce)
+}
+
+// GetFeeDelegationPolicy returns the policy to fee-delegate user value transfer requests.
+func (sb *SubBridgeAPI) GetFeeDelegationPolicy() *FeeDelegationPolicy {
+	return sb.subBridge.feeDelegation.getPolicy()
+}
+
+// SetFeeDelegationPolicy replaces the policy to fee-delegate user value transfer requests.
+// A nil policy disables the fee delegation.
+func (sb *SubBridgeAPI) SetFeeDelegationPolicy(policy *FeeDelegationPolicy) {
+	sb.subBridge.feeDelegation.setPolicy(policy)
+}
+
+// GetFeeDelegationUsage returns the fee delegation usage of today (UTC).
+func (sb *SubBridgeAPI) GetFeeDelegationUsage() *FeeDelegationUsage {
+	return sb.subBridge.feeDelegation.usage()
+}
+
+// SendRequestAsFeePayer signs the given fee-delegated value transfer request as the child operator fee payer
+// and sends it, if the fee delegation policy allows it.
+func (sb *SubBridgeAPI) SendRequestAsFeePayer(encodedTx hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	return sb.subBridge.sendBridgeRequestAsFeePayer(tx)
+}
+
+// ReconcileBridge re-scans the value transfer requests of the bridge pair in the given block ranges
+// and reports the requests not handled by the counterpart bridges. The handle transactions of
+// the unhandled requests are submitted again if args.Resubmit is set.
+func (sb *SubBridgeAPI) ReconcileBridge(cBridgeAddr, pBridgeAddr common.Address, args ReconcileArgs) (*ReconcileReport, error) {
+	return sb.subBridge.bridgeManager.ReconcileBridge(cBridgeAddr, pBridgeAddr, args)
+}
+
 func (sb *SubBridgeAPI) KASAnchor(blkNum uint64) error {
 	block := sb.subBridge.blockchain.GetBlockByNumber(blkNum)
 	if block != nil {
@@ -706,6 +760,11 @@ func (sb *SubBridgeAPI) GetParentKIP71Config() params.KIP71Config {
 	return sb.subBridge.bridgeAccounts.GetParentKIP71Config()
 }
 
+// GetParentPeers returns the health of the connected parent chain peers.
+func (sb *SubBridgeAPI) GetParentPeers() []ParentPeerStatus {
+	return sb.subBridge.parentMonitor.status(time.Now())
+}
+
 // RequestParentSync request to synchronize the parent chain values
 func (sb *SubBridgeAPI) RequestParentSync() {
 	sb.subBridge.handler.SyncNonceAndGasPrice()
@@ -276,19 +276,24 @@ func (acc *accountInfo) SignTx(tx *types.Transaction) (*types.Transaction, error
 	}
 
 	if tx.Type().IsFeeDelegatedTransaction() {
-		// Look up the wallet containing the requested signer
-		account := accounts.Account{Address: acc.feePayer}
-
-		wallet, err := acc.am.Find(account)
-		if err != nil {
-			return nil, err
-		}
-		// Request the wallet to sign the transaction
-		return wallet.SignTxAsFeePayer(account, tx, acc.chainID)
+		return acc.SignTxAsFeePayer(tx)
 	}
 	return tx, nil
 }
 
+// SignTxAsFeePayer signs the fee-delegated transaction with the fee payer of the account.
+func (acc *accountInfo) SignTxAsFeePayer(tx *types.Transaction) (*types.Transaction, error) {
+	// Look up the wallet containing the requested signer
+	account := accounts.Account{Address: acc.feePayer}
+
+	wallet, err := acc.am.Find(account)
+	if err != nil {
+		return nil, err
+	}
+	// Request the wallet to sign the transaction
+	return wallet.SignTxAsFeePayer(account, tx, acc.chainID)
+}
+
 // SetChainID sets the chain ID of the chain of the account.
 func (acc *accountInfo) SetChainID(cID *big.Int) {
 	acc.chainID = cID
@@ -817,6 +817,25 @@ func (bm *BridgeManager) AddRecovery(localAddress, remoteAddress common.Address)
 	return nil
 }
 
+// GetPendingTransfers returns the pending value transfers found by all the value transfer recoveries.
+func (bm *BridgeManager) GetPendingTransfers() []*PendingTransfer {
+	transfers := make([]*PendingTransfer, 0)
+	for _, recovery := range bm.recoveries {
+		transfers = append(transfers, recovery.PendingTransfers()...)
+	}
+	return transfers
+}
+
+// RetryTransfer resends the pending value transfer requested on the given bridge with the given nonce.
+func (bm *BridgeManager) RetryTransfer(bridgeAddr common.Address, requestNonce uint64) error {
+	for _, recovery := range bm.recoveries {
+		if recovery.cBridgeInfo.address == bridgeAddr || recovery.pBridgeInfo.address == bridgeAddr {
+			return recovery.RetryTransfer(bridgeAddr, requestNonce)
+		}
+	}
+	return ErrNoRecovery
+}
+
 // DeleteRecovery deletes the journal and stop the value transfer recovery for a given address pair.
 func (bm *BridgeManager) DeleteRecovery(localAddress, remoteAddress common.Address) error {
 	// Stop the recovery.
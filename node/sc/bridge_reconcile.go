@@ -0,0 +1,165 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package sc
+
+import (
+	"errors"
+
+	"github.com/klaytn/klaytn/accounts/abi/bind"
+	"github.com/klaytn/klaytn/common"
+)
+
+var ErrInvalidReconcileRange = errors.New("invalid block range to reconcile")
+
+// ReconcileArgs is the block ranges of both chains to reconcile a bridge pair.
+// A zero end block number means the current block.
+type ReconcileArgs struct {
+	ChildFrom  uint64 `json:"childFrom"`
+	ChildTo    uint64 `json:"childTo"`
+	ParentFrom uint64 `json:"parentFrom"`
+	ParentTo   uint64 `json:"parentTo"`
+	Resubmit   bool   `json:"resubmit"` // whether to submit the handle transactions of the unhandled requests again
+}
+
+// UnhandledRequest is a value transfer request not handled by the counterpart bridge.
+type UnhandledRequest struct {
+	RequestNonce uint64      `json:"requestNonce"`
+	BlockNumber  uint64      `json:"blockNumber"`
+	TxHash       common.Hash `json:"txHash"`
+	Resubmitted  bool        `json:"resubmitted"`
+}
+
+// ReconcileResult is the result of reconciling the requests of a bridge with its counterpart bridge.
+type ReconcileResult struct {
+	Bridge            common.Address      `json:"bridge"`
+	FromBlock         uint64              `json:"fromBlock"`
+	ToBlock           uint64              `json:"toBlock"`
+	RequestNonce      uint64              `json:"requestNonce"`      // the request nonce of the bridge
+	LowerHandleNonce  uint64              `json:"lowerHandleNonce"`  // the lower handle nonce of the counterpart bridge
+	UpperHandleNonce  uint64              `json:"upperHandleNonce"`  // the upper handle nonce of the counterpart bridge
+	Requests          uint64              `json:"requests"`          // the number of requests in the range
+	Handled           uint64              `json:"handled"`           // the number of handled requests in the range
+	UnhandledRequests []*UnhandledRequest `json:"unhandledRequests"` // the requests not handled in the range
+}
+
+// ReconcileReport is the result of reconciling a bridge pair in both directions.
+type ReconcileReport struct {
+	ChildToParent *ReconcileResult `json:"childToParent"`
+	ParentToChild *ReconcileResult `json:"parentToChild"`
+}
+
+// ReconcileBridge re-scans the value transfer requests of both bridges of the pair in the given ranges,
+// and reports the requests not handled by the counterpart bridges.
+func (bm *BridgeManager) ReconcileBridge(cBridgeAddr, pBridgeAddr common.Address, args ReconcileArgs) (*ReconcileReport, error) {
+	if !bm.IsValidBridgePair(cBridgeAddr, pBridgeAddr) {
+		return nil, ErrInvalidBridgePair
+	}
+	cBridgeInfo, _ := bm.GetBridgeInfo(cBridgeAddr)
+	pBridgeInfo, _ := bm.GetBridgeInfo(pBridgeAddr)
+
+	c2p, err := reconcileRequests(cBridgeInfo, pBridgeInfo, args.ChildFrom, args.ChildTo, args.Resubmit)
+	if err != nil {
+		return nil, err
+	}
+	p2c, err := reconcileRequests(pBridgeInfo, cBridgeInfo, args.ParentFrom, args.ParentTo, args.Resubmit)
+	if err != nil {
+		return nil, err
+	}
+	return &ReconcileReport{ChildToParent: c2p, ParentToChild: p2c}, nil
+}
+
+// reconcileRequests compares the requests of the from bridge in the given range with the handled nonces of
+// the to bridge. The unhandled requests are added to the to bridge again if resubmit is set.
+func reconcileRequests(from, to *BridgeInfo, start, end uint64, resubmit bool) (*ReconcileResult, error) {
+	if from.bridge == nil || to.bridge == nil {
+		return nil, errors.New("bridge is nil")
+	}
+	curBlkNum, err := from.GetCurrentBlockNumber()
+	if err != nil {
+		return nil, err
+	}
+	if end == 0 || end > curBlkNum {
+		end = curBlkNum
+	}
+	if start > end {
+		return nil, ErrInvalidReconcileRange
+	}
+
+	result := &ReconcileResult{Bridge: from.address, FromBlock: start, ToBlock: end, UnhandledRequests: []*UnhandledRequest{}}
+	if result.RequestNonce, err = from.bridge.RequestNonce(nil); err != nil {
+		return nil, err
+	}
+	if result.LowerHandleNonce, err = to.bridge.LowerHandleNonce(nil); err != nil {
+		return nil, err
+	}
+	if result.UpperHandleNonce, err = to.bridge.UpperHandleNonce(nil); err != nil {
+		return nil, err
+	}
+
+	var unhandled []IRequestValueTransferEvent
+	check := func(ev IRequestValueTransferEvent) {
+		result.Requests++
+		if isHandledEvent(to, ev) {
+			result.Handled++
+			return
+		}
+		raw := ev.GetRaw()
+		result.UnhandledRequests = append(result.UnhandledRequests, &UnhandledRequest{
+			RequestNonce: ev.GetRequestNonce(),
+			BlockNumber:  raw.BlockNumber,
+			TxHash:       raw.TxHash,
+			Resubmitted:  resubmit,
+		})
+		unhandled = append(unhandled, ev)
+	}
+
+	for startBlkNum := start; startBlkNum <= end; {
+		endBlkNum := startBlkNum + filterLogsStride
+		if endBlkNum > end {
+			endBlkNum = end
+		}
+		opts := &bind.FilterOpts{Start: startBlkNum, End: &endBlkNum}
+		reqVTevIt, err := from.bridge.FilterRequestValueTransfer(opts, nil, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		for reqVTevIt.Next() {
+			check(RequestValueTransferEvent{reqVTevIt.Event})
+		}
+		reqVTevIt.Close()
+
+		reqVTencodedDataIt, err := from.bridge.FilterRequestValueTransferEncoded(opts, nil, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		for reqVTencodedDataIt.Next() {
+			check(RequestValueTransferEncodedEvent{reqVTencodedDataIt.Event})
+		}
+		reqVTencodedDataIt.Close()
+
+		startBlkNum = endBlkNum + 1
+	}
+
+	if len(unhandled) > 0 {
+		logger.Warn("Found unhandled value transfer requests", "bridge", from.address.String(),
+			"from", start, "to", end, "unhandled", len(unhandled), "resubmit", resubmit)
+		if resubmit {
+			to.AddRequestValueTransferEvents(unhandled)
+		}
+	}
+	return result, nil
+}
@@ -46,6 +46,17 @@ var logger = log.NewModuleLogger(log.ServiceChain)
 var DefaultConfig = SCConfig{
 	NetworkId: 1,
 	MaxPeer:   1, // Only a single main-bridge and sub-bridge pair is allowed.
+
+	AnchoringGasPriceStrategy: AnchoringGasPriceParent,
+	AnchoringRetryGasBump:     DefaultAnchoringRetryGasBump,
+	AnchoringRetryBackoff:     DefaultAnchoringRetryBackoff,
+	AnchoringRetryMaxBackoff:  DefaultAnchoringRetryMaxBackoff,
+	AnchoringMaxRetries:       DefaultAnchoringMaxRetries,
+	AnchoringBatchMaxBlocks:   DefaultAnchoringBatchMaxBlocks,
+
+	ParentHealthCheckInterval: DefaultParentHealthCheckInterval,
+	ParentHealthTimeout:       DefaultParentHealthTimeout,
+	ParentQuorum:              1,
 }
 
 func init() {
@@ -85,6 +96,11 @@ type SCConfig struct {
 	SubBridgePort  string
 	MaxPeer        int
 
+	// Parent chain connectivity
+	ParentHealthCheckInterval time.Duration // Interval of the health checks of the parent chain peers
+	ParentHealthTimeout       time.Duration // Time without a response before a parent chain peer is unhealthy
+	ParentQuorum              int           // Number of parent chain peers which must agree on the synced parent chain info
+
 	// ServiceChain
 	ServiceChainConsensus string
 	AnchoringPeriod       uint64
@@ -93,10 +109,29 @@ type SCConfig struct {
 	ParentChainID                      uint64
 	VTRecovery                         bool
 	VTRecoveryInterval                 uint64
+	VTRecoveryRetryBackoff             time.Duration // Time to wait before retrying a pending transfer, doubled for each attempt
+	VTRecoveryRetryMaxBackoff          time.Duration // Maximum time to wait between retries of a pending transfer
+	VTRecoveryMaxAttempts              uint64        // Number of attempts before a pending transfer is dead-lettered (0 = unlimited)
 	Anchoring                          bool
 	ServiceChainParentOperatorGasLimit uint64
 	ServiceChainChildOperatorGasLimit  uint64
 
+	// Anchoring transaction
+	AnchoringGasPriceStrategy string        // Gas price strategy, one of "parent", "fixed" and "premium"
+	AnchoringGasPrice         uint64        // Gas price used by the "fixed" strategy
+	AnchoringGasPricePremium  uint64        // Percentage added to the parent chain gas price by the "premium" strategy
+	AnchoringGasPriceCap      uint64        // Maximum gas price of anchoring transactions (0 = no limit)
+	AnchoringRetryGasBump     uint64        // Percentage of the gas price increased for each retry
+	AnchoringRetryBackoff     time.Duration // Time to wait before the first retry, doubled for each retry
+	AnchoringRetryMaxBackoff  time.Duration // Maximum time to wait between retries
+	AnchoringMaxRetries       uint64        // Number of retries before an anchoring is counted as failed
+	AnchoringNonceRepair      bool          // Whether to sign pending anchoring transactions again to fill a nonce gap
+	AnchoringBatch            bool          // Whether to anchor all the blocks of an anchoring period by compressed transactions
+	AnchoringBatchMaxBlocks   uint64        // Maximum number of blocks anchored by a batched anchoring transaction
+
+	// Fee delegation
+	FeeDelegationPolicyFile string // JSON file of the policy to fee-delegate user value transfer requests
+
 	// KAS
 	KASAnchor               bool
 	KASAnchorUrl            string
@@ -0,0 +1,285 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package sc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klaytn/klaytn/accounts/abi"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/math"
+	"github.com/klaytn/klaytn/contracts/bridge"
+	sctoken "github.com/klaytn/klaytn/contracts/sc_erc20"
+	scnft "github.com/klaytn/klaytn/contracts/sc_erc721"
+)
+
+var (
+	ErrFeeDelegationDisabled     = errors.New("fee delegation policy is not set")
+	ErrNotFeeDelegatedTx         = errors.New("transaction is not fee-delegated")
+	ErrNotOperatorFeePayer       = errors.New("fee payer of the transaction is not the child operator fee payer")
+	ErrNotBridgeRequest          = errors.New("transaction is not a value transfer request")
+	ErrTokenNotFeeDelegated      = errors.New("token is not fee-delegated by the policy")
+	ErrFeeDelegationAmountLimit  = errors.New("requested amount exceeds the fee delegation limit")
+	ErrFeeDelegationAddressQuota = errors.New("daily fee delegation quota of the sender is exhausted")
+	ErrFeeDelegationDailyFeeCap  = errors.New("daily fee delegation cap is exhausted")
+)
+
+// FeeDelegationTokenPolicy limits the fee-delegated requests of a token.
+type FeeDelegationTokenPolicy struct {
+	// MaxAmount is the maximum amount of a request, which is the value for KLAY and ERC20
+	// and the number of tokens for ERC721. No limit if nil.
+	MaxAmount *math.HexOrDecimal256 `json:"maxAmount,omitempty"`
+}
+
+// FeeDelegationPolicy decides which user value transfer requests are fee-delegated by the child operator.
+// KLAY is denoted by the zero address in Tokens.
+type FeeDelegationPolicy struct {
+	Tokens            map[common.Address]*FeeDelegationTokenPolicy `json:"tokens"`
+	AddressDailyQuota uint64                                       `json:"addressDailyQuota,omitempty"` // Requests of an address per day (0 = unlimited)
+	DailyFeeCap       *math.HexOrDecimal256                        `json:"dailyFeeCap,omitempty"`       // Fees paid by the operator per day (nil = unlimited)
+}
+
+// LoadFeeDelegationPolicy reads the fee delegation policy from the given JSON file.
+func LoadFeeDelegationPolicy(path string) (*FeeDelegationPolicy, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	policy := new(FeeDelegationPolicy)
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// FeeDelegationUsage is the usage of the fee delegation in a day (UTC).
+type FeeDelegationUsage struct {
+	Day      string                    `json:"day"`
+	Fees     *big.Int                  `json:"fees"`
+	Requests map[common.Address]uint64 `json:"requests"`
+}
+
+// feeDelegationPolicer checks user requests against the policy and keeps the daily usage.
+type feeDelegationPolicer struct {
+	mu       sync.Mutex
+	policy   *FeeDelegationPolicy
+	day      int64 // days since the unix epoch of the current usage
+	fees     *big.Int
+	requests map[common.Address]uint64
+
+	now func() time.Time
+}
+
+func newFeeDelegationPolicer(policy *FeeDelegationPolicy) *feeDelegationPolicer {
+	return &feeDelegationPolicer{
+		policy:   policy,
+		fees:     new(big.Int),
+		requests: make(map[common.Address]uint64),
+		now:      time.Now,
+	}
+}
+
+// setPolicy replaces the policy. The usage of the day is kept.
+func (p *feeDelegationPolicer) setPolicy(policy *FeeDelegationPolicy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.policy = policy
+}
+
+func (p *feeDelegationPolicer) getPolicy() *FeeDelegationPolicy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.policy
+}
+
+// rotate resets the usage when a new day begins. The caller should hold the lock.
+func (p *feeDelegationPolicer) rotate() {
+	day := p.now().UTC().Unix() / int64(24*time.Hour/time.Second)
+	if day != p.day {
+		p.day = day
+		p.fees = new(big.Int)
+		p.requests = make(map[common.Address]uint64)
+	}
+}
+
+// allow checks the request against the policy and reserves the quota and the fee for it.
+// The reservation should be released if the request is not fee-delegated after all.
+func (p *feeDelegationPolicer) allow(sender, token common.Address, amount, fee *big.Int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.policy == nil {
+		return ErrFeeDelegationDisabled
+	}
+	tokenPolicy, ok := p.policy.Tokens[token]
+	if !ok {
+		return ErrTokenNotFeeDelegated
+	}
+	if tokenPolicy != nil && tokenPolicy.MaxAmount != nil && amount.Cmp((*big.Int)(tokenPolicy.MaxAmount)) > 0 {
+		return ErrFeeDelegationAmountLimit
+	}
+
+	p.rotate()
+	if p.policy.AddressDailyQuota != 0 && p.requests[sender] >= p.policy.AddressDailyQuota {
+		return ErrFeeDelegationAddressQuota
+	}
+	fees := new(big.Int).Add(p.fees, fee)
+	if p.policy.DailyFeeCap != nil && fees.Cmp((*big.Int)(p.policy.DailyFeeCap)) > 0 {
+		return ErrFeeDelegationDailyFeeCap
+	}
+	p.fees = fees
+	p.requests[sender]++
+	return nil
+}
+
+// release gives back the reservation made by allow.
+func (p *feeDelegationPolicer) release(sender common.Address, fee *big.Int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rotate()
+	if p.requests[sender] > 0 {
+		p.requests[sender]--
+		p.fees.Sub(p.fees, fee)
+	}
+}
+
+func (p *feeDelegationPolicer) usage() *FeeDelegationUsage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rotate()
+	requests := make(map[common.Address]uint64, len(p.requests))
+	for addr, n := range p.requests {
+		requests[addr] = n
+	}
+	return &FeeDelegationUsage{
+		Day:      time.Unix(p.day*int64(24*time.Hour/time.Second), 0).UTC().Format("2006-01-02"),
+		Fees:     new(big.Int).Set(p.fees),
+		Requests: requests,
+	}
+}
+
+var bridgeRequestABIs = func() map[string]abi.ABI {
+	abis := make(map[string]abi.ABI)
+	for name, def := range map[string]string{
+		"bridge": bridge.BridgeABI,
+		"erc20":  sctoken.ERC20ServiceChainABI,
+		"erc721": scnft.ERC721ServiceChainABI,
+	} {
+		parsed, err := abi.JSON(strings.NewReader(def))
+		if err != nil {
+			panic(fmt.Sprintf("failed to parse %v ABI: %v", name, err))
+		}
+		abis[name] = parsed
+	}
+	return abis
+}()
+
+// unpackBridgeRequest returns the arguments if the data calls the given method.
+func unpackBridgeRequest(contract, method string, data []byte) ([]interface{}, bool) {
+	m, ok := bridgeRequestABIs[contract].Methods[method]
+	if !ok || len(data) < 4 || !bytes.Equal(data[:4], m.ID) {
+		return nil, false
+	}
+	args, err := m.Inputs.UnpackValues(data[4:])
+	if err != nil {
+		return nil, false
+	}
+	return args, true
+}
+
+// parseBridgeRequest returns the token and the amount of the value transfer requested by the transaction.
+// isBridge tells whether the given address is a child bridge.
+func parseBridgeRequest(tx *types.Transaction, isBridge func(common.Address) bool) (common.Address, *big.Int, error) {
+	to := tx.To()
+	if to == nil {
+		return common.Address{}, nil, ErrNotBridgeRequest
+	}
+	data := tx.Data()
+
+	if isBridge(*to) {
+		if _, ok := unpackBridgeRequest("bridge", "requestKLAYTransfer", data); ok {
+			return common.Address{}, tx.Value(), nil
+		}
+		if args, ok := unpackBridgeRequest("bridge", "requestERC20Transfer", data); ok {
+			return args[0].(common.Address), args[2].(*big.Int), nil
+		}
+		if args, ok := unpackBridgeRequest("bridge", "requestERC721Transfer", data); ok {
+			return args[0].(common.Address), big.NewInt(1), nil
+		}
+		return common.Address{}, nil, ErrNotBridgeRequest
+	}
+	if args, ok := unpackBridgeRequest("erc20", "requestValueTransfer", data); ok {
+		return *to, args[0].(*big.Int), nil
+	}
+	if _, ok := unpackBridgeRequest("erc721", "requestValueTransfer", data); ok {
+		return *to, big.NewInt(1), nil
+	}
+	return common.Address{}, nil, ErrNotBridgeRequest
+}
+
+// sendBridgeRequestAsFeePayer signs the user value transfer request as the child operator fee payer
+// if the policy allows it, and adds it to the tx pool.
+func (sb *SubBridge) sendBridgeRequestAsFeePayer(tx *types.Transaction) (common.Hash, error) {
+	if !tx.Type().IsFeeDelegatedTransaction() {
+		return common.Hash{}, ErrNotFeeDelegatedTx
+	}
+	feePayer, err := tx.FeePayer()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if feePayer != sb.bridgeAccounts.cAccount.feePayer {
+		return common.Hash{}, ErrNotOperatorFeePayer
+	}
+	sender, err := tx.From()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	token, amount, err := parseBridgeRequest(tx, sb.bridgeManager.IsInChildAddrs)
+	if err != nil {
+		rejectedRequestCounter.Inc(1)
+		return common.Hash{}, err
+	}
+
+	fee := new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas()))
+	if err := sb.feeDelegation.allow(sender, token, amount, fee); err != nil {
+		logger.Debug("Rejected a fee delegation request", "sender", sender, "token", token.String(), "amount", amount, "err", err)
+		rejectedRequestCounter.Inc(1)
+		return common.Hash{}, err
+	}
+	signedTx, err := sb.bridgeAccounts.cAccount.SignTxAsFeePayer(tx)
+	if err == nil {
+		// The pool validates the signature of the sender as well.
+		err = sb.txPool.AddLocal(signedTx)
+	}
+	if err != nil {
+		sb.feeDelegation.release(sender, fee)
+		return common.Hash{}, err
+	}
+	feeDelegatedRequestCounter.Inc(1)
+	return signedTx.Hash(), nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package sc
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/math"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFeeDelegationPolicer checks the per-token limits, the per-address quotas and the daily fee cap.
+func TestFeeDelegationPolicer(t *testing.T) {
+	var (
+		klay   = common.Address{}
+		token  = common.HexToAddress("0x1")
+		alice  = common.HexToAddress("0xa")
+		bob    = common.HexToAddress("0xb")
+		fee    = big.NewInt(100)
+		policy = &FeeDelegationPolicy{
+			Tokens: map[common.Address]*FeeDelegationTokenPolicy{
+				klay:  {MaxAmount: (*math.HexOrDecimal256)(big.NewInt(1000))},
+				token: nil,
+			},
+			AddressDailyQuota: 2,
+			DailyFeeCap:       (*math.HexOrDecimal256)(big.NewInt(300)),
+		}
+	)
+
+	p := newFeeDelegationPolicer(nil)
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	p.now = func() time.Time { return now }
+	assert.Equal(t, ErrFeeDelegationDisabled, p.allow(alice, klay, big.NewInt(1), fee))
+
+	p.setPolicy(policy)
+	assert.Equal(t, ErrTokenNotFeeDelegated, p.allow(alice, common.HexToAddress("0x2"), big.NewInt(1), fee))
+	assert.Equal(t, ErrFeeDelegationAmountLimit, p.allow(alice, klay, big.NewInt(1001), fee))
+
+	// per-address quota
+	assert.NoError(t, p.allow(alice, klay, big.NewInt(1000), fee))
+	assert.NoError(t, p.allow(alice, token, new(big.Int).Lsh(big.NewInt(1), 128), fee))
+	assert.Equal(t, ErrFeeDelegationAddressQuota, p.allow(alice, klay, big.NewInt(1), fee))
+
+	// daily fee cap
+	assert.Equal(t, ErrFeeDelegationDailyFeeCap, p.allow(bob, klay, big.NewInt(1), big.NewInt(101)))
+	assert.NoError(t, p.allow(bob, klay, big.NewInt(1), fee))
+	assert.Equal(t, ErrFeeDelegationDailyFeeCap, p.allow(bob, klay, big.NewInt(1), big.NewInt(1)))
+
+	// released reservation
+	p.release(bob, fee)
+	usage := p.usage()
+	assert.Equal(t, "2022-01-01", usage.Day)
+	assert.Equal(t, big.NewInt(200), usage.Fees)
+	assert.Equal(t, map[common.Address]uint64{alice: 2, bob: 0}, usage.Requests)
+
+	// the usage is reset on the next day
+	now = now.Add(24 * time.Hour)
+	assert.NoError(t, p.allow(alice, klay, big.NewInt(1), fee))
+	usage = p.usage()
+	assert.Equal(t, "2022-01-02", usage.Day)
+	assert.Equal(t, fee, usage.Fees)
+}
+
+// TestParseBridgeRequest checks the token and the amount of the value transfer requests.
+func TestParseBridgeRequest(t *testing.T) {
+	var (
+		bridgeAddr = common.HexToAddress("0xb")
+		tokenAddr  = common.HexToAddress("0x1")
+		to         = common.HexToAddress("0xa")
+		isBridge   = func(addr common.Address) bool { return addr == bridgeAddr }
+	)
+	pack := func(contract, method string, args ...interface{}) []byte {
+		data, err := bridgeRequestABIs[contract].Pack(method, args...)
+		assert.NoError(t, err)
+		return data
+	}
+
+	tests := []struct {
+		to     common.Address
+		value  *big.Int
+		data   []byte
+		token  common.Address
+		amount *big.Int
+	}{
+		{bridgeAddr, big.NewInt(10), pack("bridge", "requestKLAYTransfer", to, big.NewInt(10), []byte{}), common.Address{}, big.NewInt(10)},
+		{bridgeAddr, common.Big0, pack("bridge", "requestERC20Transfer", tokenAddr, to, big.NewInt(20), big.NewInt(1), []byte{}), tokenAddr, big.NewInt(20)},
+		{bridgeAddr, common.Big0, pack("bridge", "requestERC721Transfer", tokenAddr, to, big.NewInt(7), []byte{}), tokenAddr, big.NewInt(1)},
+		{tokenAddr, common.Big0, pack("erc20", "requestValueTransfer", big.NewInt(30), to, big.NewInt(1), []byte{}), tokenAddr, big.NewInt(30)},
+		{tokenAddr, common.Big0, pack("erc721", "requestValueTransfer", big.NewInt(7), to, []byte{}), tokenAddr, big.NewInt(1)},
+	}
+	for _, tt := range tests {
+		tx := types.NewTransaction(0, tt.to, tt.value, 100000, big.NewInt(1), tt.data)
+		token, amount, err := parseBridgeRequest(tx, isBridge)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.token, token)
+		assert.Equal(t, tt.amount, amount)
+	}
+
+	// other calls are not fee-delegated
+	tx := types.NewTransaction(0, bridgeAddr, common.Big0, 100000, big.NewInt(1), pack("erc20", "requestValueTransfer", big.NewInt(30), to, big.NewInt(1), []byte{}))
+	_, _, err := parseBridgeRequest(tx, isBridge)
+	assert.Equal(t, ErrNotBridgeRequest, err)
+	tx = types.NewTransaction(0, tokenAddr, common.Big0, 100000, big.NewInt(1), pack("erc20", "transfer", to, big.NewInt(1)))
+	_, _, err = parseBridgeRequest(tx, isBridge)
+	assert.Equal(t, ErrNotBridgeRequest, err)
+}
+
+// TestLoadFeeDelegationPolicy checks the policy is loaded from a JSON file.
+func TestLoadFeeDelegationPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fee_delegation_policy")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := path.Join(dir, "policy.json")
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`{
+		"tokens": {
+			"0x0000000000000000000000000000000000000000": {"maxAmount": "1000000000000000000"},
+			"0x0000000000000000000000000000000000000001": {}
+		},
+		"addressDailyQuota": 10,
+		"dailyFeeCap": "0x3e8"
+	}`), 0o600))
+
+	policy, err := LoadFeeDelegationPolicy(file)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), policy.AddressDailyQuota)
+	assert.Equal(t, big.NewInt(1000), (*big.Int)(policy.DailyFeeCap))
+	assert.Equal(t, "1000000000000000000", (*big.Int)(policy.Tokens[common.Address{}].MaxAmount).String())
+	assert.Nil(t, policy.Tokens[common.HexToAddress("0x1")].MaxAmount)
+}
@@ -29,6 +29,8 @@ type MainChainEventHandler struct {
 	mainbridge *MainBridge
 
 	handler *MainBridgeHandler
+
+	reorgs anchoringReorgs
 }
 
 func NewMainChainEventHandler(bridge *MainBridge, handler *MainBridgeHandler) (*MainChainEventHandler, error) {
@@ -92,7 +94,13 @@ func (mce *MainChainEventHandler) decodeAndWriteAnchoringTx(tx *types.Transactio
 		logger.Error("failed to decode anchoring tx", "txHash", tx.Hash().String(), "err", err)
 		return
 	}
-	mce.mainbridge.chainDB.WriteChildChainTxHash(decodedData.GetBlockHash(), tx.Hash())
+	if batch, ok := decodedData.(*types.AnchoringDataInternalType1); ok {
+		for _, anchored := range batch.Blocks {
+			mce.mainbridge.chainDB.WriteChildChainTxHash(anchored.BlockHash, tx.Hash())
+		}
+	} else {
+		mce.mainbridge.chainDB.WriteChildChainTxHash(decodedData.GetBlockHash(), tx.Hash())
+	}
 	logger.Trace("Write anchoring data on chainDB", "blockHash", decodedData.GetBlockNumber().String(), "anchoring txHash", tx.Hash().String())
 }
 
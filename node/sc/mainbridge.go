@@ -98,6 +98,8 @@ type MainBridge struct {
 
 	chainHeadCh  chan blockchain.ChainHeadEvent
 	chainHeadSub event.Subscription
+	chainSideCh  chan blockchain.ChainSideEvent
+	chainSideSub event.Subscription
 	logsCh       chan []*types.Log
 	logsSub      event.Subscription
 	txCh         chan blockchain.NewTxsEvent
@@ -128,6 +130,7 @@ func NewMainBridge(ctx *node.ServiceContext, config *SCConfig) (*MainBridge, err
 		networkId:      config.NetworkId,
 		ctx:            ctx,
 		chainHeadCh:    make(chan blockchain.ChainHeadEvent, chainEventChanSize),
+		chainSideCh:    make(chan blockchain.ChainSideEvent, chainEventChanSize),
 		logsCh:         make(chan []*types.Log, chainLogChanSize),
 		txCh:           make(chan blockchain.NewTxsEvent, transactionChanSize),
 		quitSync:       make(chan struct{}),
@@ -229,6 +232,7 @@ func (mb *MainBridge) SetComponents(components []interface{}) {
 			mb.blockchain = v
 			// event from core-service
 			mb.chainHeadSub = mb.blockchain.SubscribeChainHeadEvent(mb.chainHeadCh)
+			mb.chainSideSub = mb.blockchain.SubscribeChainSideEvent(mb.chainSideCh)
 			mb.logsSub = mb.blockchain.SubscribeLogsEvent(mb.logsCh)
 		case *blockchain.TxPool:
 			mb.txPool = v
@@ -420,6 +424,13 @@ func (mb *MainBridge) loop() {
 			} else {
 				logger.Error("mainbridge block event is nil")
 			}
+		// Handle ChainSideEvent
+		case ev := <-mb.chainSideCh:
+			if ev.Block != nil {
+				mb.eventhandler.HandleChainSideEvent(ev.Block)
+			} else {
+				logger.Error("mainbridge side block event is nil")
+			}
 		// Handle NewTxsEvent
 		case ev := <-mb.txCh:
 			if ev.Txs != nil {
@@ -437,6 +448,11 @@ func (mb *MainBridge) loop() {
 				logger.Error("mainbridge block subscription ", "err", err)
 			}
 			return
+		case err := <-mb.chainSideSub.Err():
+			if err != nil {
+				logger.Error("mainbridge side block subscription ", "err", err)
+			}
+			return
 		case err := <-mb.txSub.Err():
 			if err != nil {
 				logger.Error("mainbridge tx subscription ", "err", err)
@@ -523,6 +539,7 @@ func (mb *MainBridge) Stop() error {
 	close(mb.quitSync)
 
 	mb.chainHeadSub.Unsubscribe()
+	mb.chainSideSub.Unsubscribe()
 	mb.txSub.Unsubscribe()
 	mb.logsSub.Unsubscribe()
 	mb.eventMux.Stop()
@@ -46,12 +46,26 @@ var (
 	vtRecoveredRequestEventMeter = metrics.NewRegisteredMeter("klay/bridge/vt/event/recovery/request", nil)
 	vtPendingRequestEventCounter = metrics.NewRegisteredCounter("klay/bridge/vt/event/pend/request", nil)
 
+	vtDeadLetteredTransferCounter = metrics.NewRegisteredCounter("klay/bridge/vt/deadletter", nil)
+
 	vtRequestNonceCount     = metrics.NewRegisteredCounter("klay/bridge/vt/nonce/request", nil)
 	vtHandleNonceCount      = metrics.NewRegisteredCounter("klay/bridge/vt/nonce/handle", nil)
 	vtLowerHandleNonceCount = metrics.NewRegisteredCounter("klay/bridge/vt/nonce/lowerhandle", nil)
 
 	lastAnchoredBlockNumGauge = metrics.NewRegisteredGauge("klay/bridge/anchroing/blocknumber", nil)
 
+	failedAnchoringCounter        = metrics.NewRegisteredCounter("klay/bridge/anchoring/failed", nil)
+	retriedAnchoringCounter       = metrics.NewRegisteredCounter("klay/bridge/anchoring/retried", nil)
+	repairedAnchoringNonceCounter = metrics.NewRegisteredCounter("klay/bridge/anchoring/repairednonce", nil)
+	anchoringReorgCounter         = metrics.NewRegisteredCounter("klay/bridge/anchoring/reorg", nil)
+	reanchoredCounter             = metrics.NewRegisteredCounter("klay/bridge/anchoring/reanchored", nil)
+
+	parentFailoverCounter   = metrics.NewRegisteredCounter("klay/bridge/parent/failover", nil)
+	healthyParentPeersGauge = metrics.NewRegisteredGauge("klay/bridge/parent/healthy", nil)
+
+	feeDelegatedRequestCounter = metrics.NewRegisteredCounter("klay/bridge/feedelegation/delegated", nil)
+	rejectedRequestCounter     = metrics.NewRegisteredCounter("klay/bridge/feedelegation/rejected", nil)
+
 	// TODO-Klaytn-Servicechain need to add below metrics
 	// txReceiveCounter     = metrics.NewRegisteredCounter("klay/bridge/tx/recv/counter", nil)
 	// txResendCounter      = metrics.NewRegisteredCounter("klay/bridge/tx/resend/counter", nil)
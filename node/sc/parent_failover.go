@@ -0,0 +1,288 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package sc
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultParentHealthCheckInterval = 10 * time.Second
+	DefaultParentHealthTimeout       = 30 * time.Second
+)
+
+// ParentPeerStatus is the health of a connected parent chain bridge peer.
+type ParentPeerStatus struct {
+	ID       string    `json:"id"`
+	Healthy  bool      `json:"healthy"`
+	Active   bool      `json:"active"`
+	LastSeen time.Time `json:"lastSeen"`
+	Nonce    *uint64   `json:"nonce,omitempty"`
+	GasPrice *uint64   `json:"gasPrice,omitempty"`
+}
+
+type parentPeerState struct {
+	connected time.Time
+	lastSeen  time.Time
+	info      *parentChainInfo
+	infoTime  time.Time
+}
+
+// parentPeerMonitor tracks the health of the parent chain bridge peers.
+// The RPC pipe to the parent chain is bound to a single active peer, which is
+// switched to another healthy peer when it stops responding or disconnects.
+type parentPeerMonitor struct {
+	mu      sync.Mutex
+	timeout time.Duration
+	quorum  int
+
+	peers  map[string]*parentPeerState
+	active string
+}
+
+func newParentPeerMonitor(timeout time.Duration, quorum int) *parentPeerMonitor {
+	if timeout <= 0 {
+		timeout = DefaultParentHealthTimeout
+	}
+	return &parentPeerMonitor{
+		timeout: timeout,
+		quorum:  quorum,
+		peers:   make(map[string]*parentPeerState),
+	}
+}
+
+// add registers a newly connected peer.
+func (m *parentPeerMonitor) add(id string, now time.Time) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.peers[id] = &parentPeerState{connected: now, lastSeen: now}
+}
+
+// remove unregisters a disconnected peer. It returns true if the removed peer
+// was the active one, so that another peer has to take over.
+func (m *parentPeerMonitor) remove(id string) bool {
+	if m == nil {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.peers, id)
+	// The removed active peer is kept to detect the switch by activePeer.
+	return m.active == id
+}
+
+// touch records that a message has been received from the peer.
+func (m *parentPeerMonitor) touch(id string, now time.Time) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if s, ok := m.peers[id]; ok {
+		s.lastSeen = now
+	}
+}
+
+func (m *parentPeerMonitor) healthyLocked(s *parentPeerState, now time.Time) bool {
+	return now.Sub(s.lastSeen) <= m.timeout
+}
+
+// sortedIDsLocked returns the peer IDs in the order of their connection time,
+// so that the same peer is preferred while it stays healthy.
+func (m *parentPeerMonitor) sortedIDsLocked() []string {
+	ids := make([]string, 0, len(m.peers))
+	for id := range m.peers {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		ci, cj := m.peers[ids[i]].connected, m.peers[ids[j]].connected
+		if ci.Equal(cj) {
+			return ids[i] < ids[j]
+		}
+		return ci.Before(cj)
+	})
+	return ids
+}
+
+// activePeer returns the peer which the RPC pipe is bound to. If the active
+// peer is not healthy anymore, the first healthy peer takes over and
+// switched is set to true. An unhealthy peer is kept if there is no healthy one.
+func (m *parentPeerMonitor) activePeer(now time.Time) (id string, switched bool) {
+	if m == nil {
+		return "", false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if s, ok := m.peers[m.active]; ok && m.healthyLocked(s, now) {
+		return m.active, false
+	}
+	ids := m.sortedIDsLocked()
+	next := ""
+	for _, candidate := range ids {
+		if m.healthyLocked(m.peers[candidate], now) {
+			next = candidate
+			break
+		}
+	}
+	if next == "" {
+		if _, ok := m.peers[m.active]; ok {
+			return m.active, false
+		}
+		if len(ids) == 0 {
+			return "", false
+		}
+		next = ids[0]
+	}
+	prev := m.active
+	m.active = next
+	return next, prev != "" && prev != next
+}
+
+// healthyCount returns the number of peers which responded recently.
+func (m *parentPeerMonitor) healthyCount(now time.Time) int {
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := 0
+	for _, s := range m.peers {
+		if m.healthyLocked(s, now) {
+			count++
+		}
+	}
+	return count
+}
+
+// agreeParentChainInfo records the parent chain info reported by a peer and
+// returns true if at least quorum healthy peers report the same operator nonce.
+// With a quorum less than or equal to 1, every report is accepted.
+func (m *parentPeerMonitor) agreeParentChainInfo(id string, info parentChainInfo, now time.Time) bool {
+	if m == nil {
+		return true
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if s, ok := m.peers[id]; ok {
+		s.info = &info
+		s.infoTime = now
+	}
+	if m.quorum <= 1 {
+		return true
+	}
+	agreed := 0
+	for _, s := range m.peers {
+		if s.info == nil || now.Sub(s.infoTime) > m.timeout {
+			continue
+		}
+		if s.info.Nonce == info.Nonce {
+			agreed++
+		}
+	}
+	return agreed >= m.quorum
+}
+
+// status returns the health of all connected peers.
+func (m *parentPeerMonitor) status(now time.Time) []ParentPeerStatus {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := m.sortedIDsLocked()
+	statuses := make([]ParentPeerStatus, 0, len(ids))
+	for _, id := range ids {
+		s := m.peers[id]
+		status := ParentPeerStatus{
+			ID:       id,
+			Healthy:  m.healthyLocked(s, now),
+			Active:   id == m.active,
+			LastSeen: s.lastSeen,
+		}
+		if s.info != nil {
+			nonce, gasPrice := s.info.Nonce, s.info.GasPrice
+			status.Nonce, status.GasPrice = &nonce, &gasPrice
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// activeParentPeer returns the parent chain peer which the RPC pipe is bound to.
+// Bridge event subscriptions are made again if the active peer has been switched.
+func (sb *SubBridge) activeParentPeer() BridgePeer {
+	id, switched := sb.parentMonitor.activePeer(time.Now())
+	if switched {
+		sb.notifyParentFailover(id)
+	}
+	if id == "" {
+		return nil
+	}
+	return sb.peers.Peer(id)
+}
+
+func (sb *SubBridge) notifyParentFailover(id string) {
+	if id == "" {
+		return
+	}
+	parentFailoverCounter.Inc(1)
+	logger.Warn("Switched the parent chain peer of the rpc connection", "peer", id)
+	select {
+	case sb.parentFailoverCh <- struct{}{}:
+	default:
+	}
+}
+
+// parentHealthCheckLoop periodically requests the parent chain info to all the
+// parent chain peers, which also works as a health check, and fails over the
+// RPC connection to a healthy peer if needed.
+func (sb *SubBridge) parentHealthCheckLoop() {
+	defer sb.pmwg.Done()
+
+	interval := sb.config.ParentHealthCheckInterval
+	if interval <= 0 {
+		interval = DefaultParentHealthCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sb.quitSync:
+			return
+		case <-ticker.C:
+			// Health checking is only meaningful with multiple parent chain peers.
+			if sb.peers.Len() > 1 {
+				sb.handler.SyncNonceAndGasPrice()
+			}
+			sb.activeParentPeer()
+			healthyParentPeersGauge.Update(int64(sb.parentMonitor.healthyCount(time.Now())))
+		}
+	}
+}
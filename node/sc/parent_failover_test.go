@@ -0,0 +1,107 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package sc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParentPeerMonitor_Failover(t *testing.T) {
+	now := time.Now()
+	m := newParentPeerMonitor(time.Minute, 1)
+
+	id, switched := m.activePeer(now)
+	assert.Equal(t, "", id)
+	assert.False(t, switched)
+
+	m.add("a", now)
+	m.add("b", now.Add(time.Second))
+
+	// the first connected peer is selected without a failover
+	id, switched = m.activePeer(now.Add(time.Second))
+	assert.Equal(t, "a", id)
+	assert.False(t, switched)
+
+	// the active peer is kept while it is healthy
+	m.touch("b", now.Add(30*time.Second))
+	id, switched = m.activePeer(now.Add(30 * time.Second))
+	assert.Equal(t, "a", id)
+	assert.False(t, switched)
+
+	// the active peer becomes unhealthy
+	id, switched = m.activePeer(now.Add(80 * time.Second))
+	assert.Equal(t, "b", id)
+	assert.True(t, switched)
+	assert.Equal(t, 1, m.healthyCount(now.Add(80*time.Second)))
+
+	// the active peer is kept if no peer is healthy
+	id, switched = m.activePeer(now.Add(time.Hour))
+	assert.Equal(t, "b", id)
+	assert.False(t, switched)
+
+	// the active peer is disconnected
+	m.touch("a", now.Add(time.Hour))
+	assert.True(t, m.remove("b"))
+	id, switched = m.activePeer(now.Add(time.Hour))
+	assert.Equal(t, "a", id)
+	assert.True(t, switched)
+
+	assert.False(t, m.remove("c"))
+}
+
+func TestParentPeerMonitor_Quorum(t *testing.T) {
+	now := time.Now()
+
+	m := newParentPeerMonitor(time.Minute, 1)
+	m.add("a", now)
+	assert.True(t, m.agreeParentChainInfo("a", parentChainInfo{Nonce: 1}, now))
+
+	m = newParentPeerMonitor(time.Minute, 2)
+	m.add("a", now)
+	m.add("b", now)
+	m.add("c", now)
+
+	assert.False(t, m.agreeParentChainInfo("a", parentChainInfo{Nonce: 1}, now))
+	assert.False(t, m.agreeParentChainInfo("b", parentChainInfo{Nonce: 2}, now))
+	assert.True(t, m.agreeParentChainInfo("c", parentChainInfo{Nonce: 2}, now))
+
+	// stale reports are not counted
+	assert.False(t, m.agreeParentChainInfo("a", parentChainInfo{Nonce: 3}, now.Add(2*time.Minute)))
+
+	statuses := m.status(now)
+	assert.Equal(t, 3, len(statuses))
+	assert.Equal(t, "a", statuses[0].ID)
+	assert.Equal(t, uint64(3), *statuses[0].Nonce)
+	assert.True(t, statuses[0].Healthy)
+	assert.False(t, statuses[0].Active)
+}
+
+func TestParentPeerMonitor_Nil(t *testing.T) {
+	var m *parentPeerMonitor
+
+	m.add("a", time.Now())
+	m.touch("a", time.Now())
+	assert.False(t, m.remove("a"))
+	id, switched := m.activePeer(time.Now())
+	assert.Equal(t, "", id)
+	assert.False(t, switched)
+	assert.True(t, m.agreeParentChainInfo("a", parentChainInfo{}, time.Now()))
+	assert.Nil(t, m.status(time.Now()))
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/klaytn/klaytn/blockchain"
 	"github.com/klaytn/klaytn/blockchain/types"
@@ -61,6 +62,7 @@ type SubBridgeHandler struct {
 	// Therefore, for now, it is only used by child chain side.
 	remoteGasPrice        uint64
 	mainChainAccountNonce uint64
+	parentNonce           uint64 // nonce of the parent chain operator received from parent chain
 	nonceSynced           bool
 	chainTxPeriod         uint64
 
@@ -72,9 +74,15 @@ type SubBridgeHandler struct {
 	sentServiceChainTxsLimit uint64
 
 	skipSyncBlockCount int32
+
+	// anchoringRetries holds the rejected anchoring txs by nonce, protected by the parent operator lock.
+	anchoringRetries map[uint64]*anchoringRetry
 }
 
 func NewSubBridgeHandler(main *SubBridge) (*SubBridgeHandler, error) {
+	if err := validateAnchoringConfig(main.config); err != nil {
+		return nil, err
+	}
 	return &SubBridgeHandler{
 		subbridge:                     main,
 		parentChainID:                 new(big.Int).SetUint64(main.config.ParentChainID),
@@ -84,6 +92,7 @@ func NewSubBridgeHandler(main *SubBridge) (*SubBridgeHandler, error) {
 		chainTxPeriod:                 main.config.AnchoringPeriod,
 		latestTxCountAddedBlockNumber: uint64(0),
 		sentServiceChainTxsLimit:      main.config.SentChainTxsLimit,
+		anchoringRetries:              make(map[uint64]*anchoringRetry),
 	}, nil
 }
 
@@ -274,6 +283,10 @@ func (sbh *SubBridgeHandler) handleParentChainInfoResponseMsg(p BridgePeer, msg
 		logger.Error("failed to decode", "err", err)
 		return errResp(ErrDecode, "msg %v: %v", msg, err)
 	}
+	if !sbh.subbridge.parentMonitor.agreeParentChainInfo(p.GetID(), pcInfo, time.Now()) {
+		logger.Debug("Waiting for a quorum of the parent chain info", "peer", p.GetID(), "receivedNonce", pcInfo.Nonce)
+		return nil
+	}
 	sbh.LockParentOperator()
 	defer sbh.UnLockParentOperator()
 
@@ -299,8 +312,12 @@ func (sbh *SubBridgeHandler) handleParentChainInfoResponseMsg(p BridgePeer, msg
 		// there is no tx in bridgetTxPool, so parent-chain's nonce is used
 		sbh.setParentOperatorNonce(pcInfo.Nonce)
 	}
+	sbh.parentNonce = pcInfo.Nonce
 	sbh.setParentOperatorNonceSynced(true)
 	sbh.setRemoteChainValues(pcInfo)
+	if sbh.subbridge.config.AnchoringNonceRepair {
+		sbh.repairAnchoringNonceGap(pcInfo.Nonce)
+	}
 	logger.Info("ParentChainNonceResponse", "receivedNonce", pcInfo.Nonce, "gasPrice", pcInfo.GasPrice, "mainChainAccountNonce", sbh.getParentOperatorNonce())
 	return nil
 }
@@ -331,26 +348,8 @@ func (sbh *SubBridgeHandler) genUnsignedChainDataAnchoringTx(block *types.Block)
 		return nil, err
 	}
 
-	values := map[types.TxValueKeyType]interface{}{
-		types.TxValueKeyNonce:        sbh.getParentOperatorNonce(), // parent chain operator nonce will be increased after signing a transaction.
-		types.TxValueKeyFrom:         *sbh.GetParentOperatorAddr(),
-		types.TxValueKeyGasLimit:     uint64(100000), // TODO-Klaytn-ServiceChain should define proper gas limit
-		types.TxValueKeyGasPrice:     new(big.Int).SetUint64(sbh.remoteGasPrice),
-		types.TxValueKeyAnchoredData: encodedCCTxData,
-	}
-
-	txType := types.TxTypeChainDataAnchoring
-
-	if feePayer := sbh.subbridge.bridgeAccounts.GetParentOperatorFeePayer(); feePayer != (common.Address{}) {
-		values[types.TxValueKeyFeePayer] = feePayer
-		txType = types.TxTypeFeeDelegatedChainDataAnchoring
-	}
-
-	if tx, err := types.NewTransactionWithMap(txType, values); err != nil {
-		return nil, err
-	} else {
-		return tx, nil
-	}
+	// parent chain operator nonce will be increased after signing a transaction.
+	return sbh.newUnsignedAnchoringTx(sbh.getParentOperatorNonce(), sbh.anchoringGasPrice(0), encodedCCTxData)
 }
 
 // LocalChainHeadEvent deals with servicechain feature to generate/broadcast service chain transactions and request receipts.
@@ -359,6 +358,7 @@ func (sbh *SubBridgeHandler) LocalChainHeadEvent(block *types.Block) {
 		// TODO-Klaytn if other feature use below chainTx, this condition should be refactored to use it for other feature.
 		if sbh.subbridge.GetAnchoringTx() {
 			sbh.blockAnchoringManager(block)
+			sbh.retryAnchoringTxs()
 		}
 		sbh.broadcastServiceChainTx()
 		sbh.broadcastServiceChainReceiptRequest()
@@ -389,7 +389,11 @@ func (sbh *SubBridgeHandler) handleParentChainInvalidTxResponseMsg(msg p2p.Msg)
 			logger.Error("A bridge tx was not executed", "err", invalidTx.ErrStr,
 				"txHash", invalidTx.TxHash.String(),
 				"txGasPrice", tx.GasPrice().Uint64())
-			if invalidTx.ErrStr == blockchain.ErrGasPriceBelowBaseFee.Error() {
+			if tx.Type().IsChainDataAnchoring() {
+				// Retry the anchoring tx with the same nonce not to leave a nonce gap
+				sbh.SyncNonceAndGasPrice()
+				sbh.scheduleAnchoringRetry(tx, invalidTx.ErrStr)
+			} else if invalidTx.ErrStr == blockchain.ErrGasPriceBelowBaseFee.Error() {
 				logger.Info("[SC][HandleTxDropped] Request gasPrice and Magma values to parent chain")
 				sbh.SyncNonceAndGasPrice()
 
@@ -446,7 +450,13 @@ func (sbh *SubBridgeHandler) writeServiceChainTxReceipts(bc *blockchain.BlockCha
 					logger.Error("failed to decode anchoring tx", "txHash", txHash.String(), "err", err)
 					continue
 				}
-				sbh.WriteReceiptFromParentChain(decodedData.GetBlockHash(), (*types.Receipt)(receipt))
+				if batch, ok := decodedData.(*types.AnchoringDataInternalType1); ok {
+					for _, anchored := range batch.Blocks {
+						sbh.WriteReceiptFromParentChain(anchored.BlockHash, (*types.Receipt)(receipt))
+					}
+				} else {
+					sbh.WriteReceiptFromParentChain(decodedData.GetBlockHash(), (*types.Receipt)(receipt))
+				}
 				sbh.WriteAnchoredBlockNumber(decodedData.GetBlockNumber().Uint64())
 			}
 			// TODO-Klaytn-ServiceChain: support other tx types if needed.
@@ -534,30 +544,45 @@ func (sbh *SubBridgeHandler) generateAndAddAnchoringTxIntoTxPool(block *types.Bl
 	sbh.LockParentOperator()
 	defer sbh.UnLockParentOperator()
 
-	unsignedTx, err := sbh.genUnsignedChainDataAnchoringTx(block)
-	if err != nil {
-		logger.Error("Failed to generate service chain transaction", "blockNum", block.NumberU64(), "err", err)
-		return err
+	var unsignedTxs []*types.Transaction
+	if sbh.subbridge.config.AnchoringBatch {
+		txs, err := sbh.genUnsignedBatchAnchoringTxs(block)
+		if err != nil {
+			failedAnchoringCounter.Inc(1)
+			logger.Error("Failed to generate batched anchoring transactions", "blockNum", block.NumberU64(), "err", err)
+			return err
+		}
+		unsignedTxs = txs
+	} else {
+		unsignedTx, err := sbh.genUnsignedChainDataAnchoringTx(block)
+		if err != nil {
+			failedAnchoringCounter.Inc(1)
+			logger.Error("Failed to generate service chain transaction", "blockNum", block.NumberU64(), "err", err)
+			return err
+		}
+		unsignedTxs = []*types.Transaction{unsignedTx}
 	}
 	txCount := sbh.txCount
 	// Reset for the next anchoring period.
 	sbh.txCount = 0
 	sbh.txCountStartingBlockNumber = block.NumberU64() + 1
 
-	signedTx, err := sbh.subbridge.bridgeAccounts.pAccount.SignTx(unsignedTx)
-	if err != nil {
-		logger.Error("failed signing tx", "err", err)
-		return err
-	}
-	if err := sbh.subbridge.GetBridgeTxPool().AddLocal(signedTx); err == nil {
-		sbh.addParentOperatorNonce(1)
-	} else {
-		logger.Debug("failed to add tx into bridge txpool", "err", err)
-		return err
+	for _, unsignedTx := range unsignedTxs {
+		signedTx, err := sbh.subbridge.bridgeAccounts.pAccount.SignTx(unsignedTx)
+		if err != nil {
+			failedAnchoringCounter.Inc(1)
+			logger.Error("failed signing tx", "err", err)
+			return err
+		}
+		if err := sbh.subbridge.GetBridgeTxPool().AddLocal(signedTx); err == nil {
+			sbh.addParentOperatorNonce(1)
+		} else {
+			failedAnchoringCounter.Inc(1)
+			logger.Debug("failed to add tx into bridge txpool", "err", err)
+			return err
+		}
+		logger.Info("Generate an anchoring tx", "blockNum", block.NumberU64(), "blockhash", block.Hash().String(), "txCount", txCount, "txHash", signedTx.Hash().String(), "payloadSize", len(signedTx.Data()))
 	}
-
-	logger.Info("Generate an anchoring tx", "blockNum", block.NumberU64(), "blockhash", block.Hash().String(), "txCount", txCount, "txHash", signedTx.Hash().String())
-
 	return nil
 }
 
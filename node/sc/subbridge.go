@@ -147,6 +147,10 @@ type SubBridge struct {
 	handler      *SubBridgeHandler
 	eventhandler *ChildChainEventHandler
 
+	// health of the parent chain peers and failover of the rpc connection
+	parentMonitor    *parentPeerMonitor
+	parentFailoverCh chan struct{}
+
 	// bridgemanager for value exchange
 	localBackend  Backend
 	remoteBackend Backend
@@ -171,6 +175,9 @@ type SubBridge struct {
 
 	// KAS Anchor
 	kasAnchor *kas.Anchor
+
+	// fee delegation of user value transfer requests
+	feeDelegation *feeDelegationPolicer
 }
 
 // New creates a new CN object (including the
@@ -201,6 +208,8 @@ func NewSubBridge(ctx *node.ServiceContext, config *SCConfig) (*SubBridge, error
 		onAnchoringTx:      config.Anchoring,
 		bootFail:           false,
 		rpcSendCh:          make(chan []byte),
+		parentMonitor:      newParentPeerMonitor(config.ParentHealthTimeout, config.ParentQuorum),
+		parentFailoverCh:   make(chan struct{}, 1),
 	}
 	// TODO-Klaytn change static config to user define config
 	bridgetxConfig := bridgepool.BridgeTxPoolConfig{
@@ -230,6 +239,14 @@ func NewSubBridge(ctx *node.ServiceContext, config *SCConfig) (*SubBridge, error
 	}
 	sb.bridgeAccounts.pAccount.SetChainID(new(big.Int).SetUint64(config.ParentChainID))
 
+	var policy *FeeDelegationPolicy
+	if config.FeeDelegationPolicyFile != "" {
+		if policy, err = LoadFeeDelegationPolicy(config.FeeDelegationPolicyFile); err != nil {
+			return nil, err
+		}
+	}
+	sb.feeDelegation = newFeeDelegationPolicer(policy)
+
 	return sb, nil
 }
 
@@ -257,6 +274,15 @@ func (sb *SubBridge) SetRPCConn(conn net.Conn) {
 }
 
 func (sb *SubBridge) SendRPCData(data []byte) error {
+	if peer := sb.activeParentPeer(); peer != nil {
+		logger.Trace("send rpc message from the subbridge", "len", len(data), "peer", peer.GetID())
+		err := peer.SendRequestRPC(data)
+		if err != nil {
+			logger.Error("SendRPCData Error", "err", err)
+		}
+		return err
+	}
+
 	peers := sb.BridgePeerSet().peers
 	logger.Trace("send rpc message from the subbridge", "len", len(data), "peers", len(peers))
 	for _, peer := range peers {
@@ -386,6 +412,9 @@ func (sb *SubBridge) SetComponents(components []interface{}) {
 	sb.pmwg.Add(1)
 	go sb.resetBridgeLoop()
 
+	sb.pmwg.Add(1)
+	go sb.parentHealthCheckLoop()
+
 	sb.bridgeAccounts.cAccount.SetNonce(sb.txPool.GetPendingNonce(sb.bridgeAccounts.cAccount.address))
 
 	sb.pmwg.Add(1)
@@ -430,6 +459,12 @@ func (sb *SubBridge) Start(srvr p2p.Server) error {
 		return errors.New("subBridge node fail to start")
 	}
 
+	// connect to all the parent chain bridges to fail over between them
+	mainBridges := sb.config.MainBridges()
+	if len(mainBridges) > sb.maxPeers {
+		sb.maxPeers = len(mainBridges)
+	}
+
 	serverConfig := p2p.Config{}
 	serverConfig.PrivateKey = sb.ctx.NodeKey()
 	serverConfig.Name = sb.ctx.NodeType().String()
@@ -440,7 +475,7 @@ func (sb *SubBridge) Start(srvr p2p.Server) error {
 	serverConfig.EnableMultiChannelServer = false
 
 	// connect to mainbridge as outbound
-	serverConfig.StaticNodes = sb.config.MainBridges()
+	serverConfig.StaticNodes = mainBridges
 
 	p2pServer := p2p.NewServer(serverConfig)
 
@@ -538,6 +573,7 @@ func (sb *SubBridge) handle(p BridgePeer) error {
 		return err
 	}
 	defer sb.removePeer(p.GetID())
+	sb.parentMonitor.add(p.GetID(), time.Now())
 
 	sb.handler.RegisterNewPeer(p)
 
@@ -573,6 +609,9 @@ func (sb *SubBridge) resetBridgeLoop() {
 				needResetSubscription = true
 				sb.handler.setParentOperatorNonceSynced(false)
 			}
+		case <-sb.parentFailoverCh:
+			// subscriptions are kept by the previous parent chain peer
+			needResetSubscription = true
 		case <-ticker.C:
 			if needResetSubscription && peerCount > 0 {
 				err := sb.bridgeManager.ResetAllSubscribedEvents()
@@ -696,6 +735,9 @@ func (sb *SubBridge) removePeer(id string) {
 		return
 	}
 	logger.Debug("Removing Klaytn peer", "peer", id)
+	if sb.parentMonitor.remove(id) {
+		sb.activeParentPeer()
+	}
 
 	if err := sb.peers.Unregister(id); err != nil {
 		logger.Error("Peer removal failed", "peer", id, "err", err)
@@ -716,6 +758,7 @@ func (sb *SubBridge) handleMsg(p BridgePeer) error {
 		p.GetP2PPeer().Log().Warn("ProtocolManager failed to read msg", "err", err)
 		return err
 	}
+	sb.parentMonitor.touch(p.GetID(), time.Now())
 	if msg.Size > ProtocolMaxMsgSize {
 		err := errResp(ErrMsgTooLarge, "%v > %v", msg.Size, ProtocolMaxMsgSize)
 		p.GetP2PPeer().Log().Warn("ProtocolManager over max msg size", "err", err)
@@ -17,10 +17,13 @@
 package sc
 
 import (
+	"math/big"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/klaytn/klaytn/accounts/abi/bind"
+	"github.com/klaytn/klaytn/common"
 	"github.com/pkg/errors"
 )
 
@@ -52,11 +55,45 @@ type valueTransferRecovery struct {
 	config      *SCConfig
 	cBridgeInfo *BridgeInfo
 	pBridgeInfo *BridgeInfo
+
+	transfers   map[pendingTransferKey]*PendingTransfer // pending transfers found by the recovery
+	transfersMu sync.Mutex
+}
+
+// PendingTransfer is a value transfer requested on the source bridge, which is
+// not handled on the target bridge yet. It is retried by the recovery with an
+// exponential backoff, and dead-lettered after the maximum attempts.
+type PendingTransfer struct {
+	SourceBridge   common.Address `json:"sourceBridge"`
+	TargetBridge   common.Address `json:"targetBridge"`
+	RequestNonce   uint64         `json:"requestNonce"`
+	RequestTxHash  common.Hash    `json:"requestTxHash"`
+	BlockNumber    uint64         `json:"blockNumber"`
+	TokenType      uint8          `json:"tokenType"`
+	TokenAddress   common.Address `json:"tokenAddress"`
+	From           common.Address `json:"from"`
+	To             common.Address `json:"to"`
+	ValueOrTokenId *big.Int       `json:"valueOrTokenId"`
+	Attempts       uint64         `json:"attempts"`
+	FirstSeen      time.Time      `json:"firstSeen"`
+	LastAttempt    time.Time      `json:"lastAttempt"`
+	NextRetry      time.Time      `json:"nextRetry"`
+	DeadLettered   bool           `json:"deadLettered"`
+
+	event IRequestValueTransferEvent
+}
+
+type pendingTransferKey struct {
+	bridge common.Address
+	nonce  uint64
 }
 
 var (
 	ErrVtrDisabled       = errors.New("VTR is disabled")
 	ErrVtrAlreadyStarted = errors.New("VTR is already started")
+
+	ErrNoPendingTransfer      = errors.New("pending transfer does not exist")
+	ErrTransferAlreadyHandled = errors.New("transfer is already handled")
 )
 
 func isHandledEvent(to *BridgeInfo, ev IRequestValueTransferEvent) bool {
@@ -81,6 +118,7 @@ func NewValueTransferRecovery(config *SCConfig, cBridgeInfo, pBridgeInfo *Bridge
 		config:           config,
 		cBridgeInfo:      cBridgeInfo,
 		pBridgeInfo:      pBridgeInfo,
+		transfers:        make(map[pendingTransferKey]*PendingTransfer),
 	}
 }
 
@@ -185,6 +223,9 @@ func (vtr *valueTransferRecovery) updateRecoveryHint() error {
 		return err
 	}
 
+	vtr.prunePendingTransfers(vtr.cBridgeInfo, vtr.child2parentHint.handleNonce)
+	vtr.prunePendingTransfers(vtr.pBridgeInfo, vtr.parent2childHint.handleNonce)
+
 	// Update the hint for the initial status.
 	if !vtr.isRunning {
 		vtr.child2parentHint.prevHandleNonce = vtr.child2parentHint.handleNonce
@@ -376,37 +417,172 @@ func checkRecoveryCondition(hint *valueTransferHint) bool {
 	return false
 }
 
-// recoverPendingEvents recovers all pending events by resending them.
+// recoverPendingEvents recovers the pending events, which are due to be retried, by resending them.
 func (vtr *valueTransferRecovery) recoverPendingEvents() error {
 	defer func() {
 		vtr.childEvents = []IRequestValueTransferEvent{}
 		vtr.parentEvents = []IRequestValueTransferEvent{}
 	}()
 
-	if len(vtr.childEvents) > 0 {
-		logger.Warn("VT Recovery : Child -> Parent Chain", "cBridge", vtr.cBridgeInfo.address.String(), "events", len(vtr.childEvents))
+	events := vtr.dueEvents(vtr.childEvents, vtr.cBridgeInfo, vtr.pBridgeInfo)
+	if len(events) > 0 {
+		logger.Warn("VT Recovery : Child -> Parent Chain", "cBridge", vtr.cBridgeInfo.address.String(), "events", len(events))
 	}
 
-	vtRequestEventMeter.Mark(int64(len(vtr.childEvents)))
-	vtRecoveredRequestEventMeter.Mark(int64(len(vtr.childEvents)))
+	vtRequestEventMeter.Mark(int64(len(events)))
+	vtRecoveredRequestEventMeter.Mark(int64(len(events)))
+	vtr.pBridgeInfo.AddRequestValueTransferEvents(events)
 
-	events := make([]IRequestValueTransferEvent, len(vtr.childEvents))
-	for i, event := range vtr.childEvents {
-		events[i] = event
+	events = vtr.dueEvents(vtr.parentEvents, vtr.pBridgeInfo, vtr.cBridgeInfo)
+	if len(events) > 0 {
+		logger.Warn("VT Recovery : Parent -> Child Chain", "pBridge", vtr.pBridgeInfo.address.String(), "events", len(events))
 	}
-	vtr.pBridgeInfo.AddRequestValueTransferEvents(events)
 
-	if len(vtr.parentEvents) > 0 {
-		logger.Warn("VT Recovery : Parent -> Child Chain", "pBridge", vtr.pBridgeInfo.address.String(), "events", len(vtr.parentEvents))
+	vtHandleEventMeter.Mark(int64(len(events)))
+	vtr.cBridgeInfo.AddRequestValueTransferEvents(events)
+
+	return nil
+}
+
+// retryBackoff returns the time to wait after the given attempts of a pending
+// transfer. It is doubled for each attempt and limited by the configured maximum.
+func (vtr *valueTransferRecovery) retryBackoff(attempts uint64) time.Duration {
+	backoff := vtr.config.VTRecoveryRetryBackoff
+	for i := uint64(1); i < attempts; i++ {
+		backoff *= 2
+		if vtr.config.VTRecoveryRetryMaxBackoff != 0 && backoff >= vtr.config.VTRecoveryRetryMaxBackoff {
+			return vtr.config.VTRecoveryRetryMaxBackoff
+		}
+	}
+	return backoff
+}
+
+// dueEvents tracks the given pending events from the source bridge and returns
+// the ones due to be retried. The events exceeding the maximum attempts are
+// dead-lettered and only retried by RetryTransfer.
+func (vtr *valueTransferRecovery) dueEvents(evs []IRequestValueTransferEvent, from, to *BridgeInfo) []IRequestValueTransferEvent {
+	vtr.transfersMu.Lock()
+	defer vtr.transfersMu.Unlock()
+
+	now := time.Now()
+	due := make([]IRequestValueTransferEvent, 0, len(evs))
+	for _, ev := range evs {
+		key := pendingTransferKey{from.address, ev.GetRequestNonce()}
+		transfer, ok := vtr.transfers[key]
+		if !ok {
+			raw := ev.GetRaw()
+			transfer = &PendingTransfer{
+				SourceBridge:   from.address,
+				TargetBridge:   to.address,
+				RequestNonce:   ev.GetRequestNonce(),
+				RequestTxHash:  raw.TxHash,
+				BlockNumber:    raw.BlockNumber,
+				TokenType:      ev.GetTokenType(),
+				TokenAddress:   ev.GetTokenAddress(),
+				From:           ev.GetFrom(),
+				To:             ev.GetTo(),
+				ValueOrTokenId: ev.GetValueOrTokenId(),
+				FirstSeen:      now,
+				event:          ev,
+			}
+			vtr.transfers[key] = transfer
+		}
+		if transfer.DeadLettered || now.Before(transfer.NextRetry) {
+			continue
+		}
+		if max := vtr.config.VTRecoveryMaxAttempts; max != 0 && transfer.Attempts >= max {
+			transfer.DeadLettered = true
+			vtDeadLetteredTransferCounter.Inc(1)
+			logger.Error("VT Recovery : value transfer is dead-lettered", "bridge", from.address.String(),
+				"requestNonce", transfer.RequestNonce, "txHash", transfer.RequestTxHash.String(), "attempts", transfer.Attempts)
+			continue
+		}
+		transfer.Attempts++
+		transfer.LastAttempt = now
+		transfer.NextRetry = now.Add(vtr.retryBackoff(transfer.Attempts))
+		due = append(due, ev)
 	}
+	return due
+}
+
+// prunePendingTransfers removes the pending transfers from the given source
+// bridge, which are handled on the counterpart bridge.
+func (vtr *valueTransferRecovery) prunePendingTransfers(from *BridgeInfo, lowerHandleNonce uint64) {
+	vtr.transfersMu.Lock()
+	defer vtr.transfersMu.Unlock()
 
-	vtHandleEventMeter.Mark(int64(len(vtr.parentEvents)))
-	events = make([]IRequestValueTransferEvent, len(vtr.parentEvents))
-	for i, event := range vtr.parentEvents {
-		events[i] = event
+	for key, transfer := range vtr.transfers {
+		if key.bridge == from.address && key.nonce < lowerHandleNonce {
+			if transfer.DeadLettered {
+				vtDeadLetteredTransferCounter.Dec(1)
+			}
+			delete(vtr.transfers, key)
+		}
 	}
-	vtr.cBridgeInfo.AddRequestValueTransferEvents(events)
+}
+
+// PendingTransfers returns the pending transfers found by the recovery sorted
+// by the source bridge and the request nonce.
+func (vtr *valueTransferRecovery) PendingTransfers() []*PendingTransfer {
+	vtr.transfersMu.Lock()
+	defer vtr.transfersMu.Unlock()
+
+	transfers := make([]*PendingTransfer, 0, len(vtr.transfers))
+	for _, transfer := range vtr.transfers {
+		cpy := *transfer
+		transfers = append(transfers, &cpy)
+	}
+	sort.Slice(transfers, func(i, j int) bool {
+		if transfers[i].SourceBridge != transfers[j].SourceBridge {
+			return transfers[i].SourceBridge.Hex() < transfers[j].SourceBridge.Hex()
+		}
+		return transfers[i].RequestNonce < transfers[j].RequestNonce
+	})
+	return transfers
+}
 
+// RetryTransfer resends the pending transfer requested on the given source bridge
+// with the given nonce, even if it is dead-lettered.
+func (vtr *valueTransferRecovery) RetryTransfer(bridgeAddr common.Address, requestNonce uint64) error {
+	var from, to *BridgeInfo
+	switch bridgeAddr {
+	case vtr.cBridgeInfo.address:
+		from, to = vtr.cBridgeInfo, vtr.pBridgeInfo
+	case vtr.pBridgeInfo.address:
+		from, to = vtr.pBridgeInfo, vtr.cBridgeInfo
+	default:
+		return ErrNoPendingTransfer
+	}
+
+	vtr.transfersMu.Lock()
+	key := pendingTransferKey{bridgeAddr, requestNonce}
+	transfer, ok := vtr.transfers[key]
+	if !ok {
+		vtr.transfersMu.Unlock()
+		return ErrNoPendingTransfer
+	}
+	if isHandledEvent(to, transfer.event) {
+		if transfer.DeadLettered {
+			vtDeadLetteredTransferCounter.Dec(1)
+		}
+		delete(vtr.transfers, key)
+		vtr.transfersMu.Unlock()
+		return ErrTransferAlreadyHandled
+	}
+	if transfer.DeadLettered {
+		transfer.DeadLettered = false
+		vtDeadLetteredTransferCounter.Dec(1)
+	}
+	now := time.Now()
+	transfer.Attempts++
+	transfer.LastAttempt = now
+	transfer.NextRetry = now.Add(vtr.retryBackoff(transfer.Attempts))
+	ev, attempts := transfer.event, transfer.Attempts
+	vtr.transfersMu.Unlock()
+
+	logger.Info("VT Recovery : retry value transfer", "bridge", from.address.String(), "requestNonce", requestNonce, "attempts", attempts)
+	vtRecoveredRequestEventMeter.Mark(1)
+	to.AddRequestValueTransferEvents([]IRequestValueTransferEvent{ev})
 	return nil
 }
 
@@ -30,6 +30,7 @@ import (
 	"github.com/klaytn/klaytn/blockchain"
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/contracts/bridge"
 	sctoken "github.com/klaytn/klaytn/contracts/sc_erc20"
 	scnft "github.com/klaytn/klaytn/contracts/sc_erc721"
 	"github.com/klaytn/klaytn/crypto"
@@ -987,3 +988,73 @@ func dummyHandleRequestNFTTransfer(info *testInfo, bi *BridgeInfo) {
 	}
 	info.sim.Commit()
 }
+
+// TestPendingTransferRetryPolicy checks the backoff and the dead-lettering of the pending transfers.
+func TestPendingTransferRetryPolicy(t *testing.T) {
+	config := &SCConfig{
+		VTRecoveryRetryBackoff:    time.Hour,
+		VTRecoveryRetryMaxBackoff: 4 * time.Hour,
+		VTRecoveryMaxAttempts:     1,
+	}
+	from := &BridgeInfo{address: common.HexToAddress("0x1")}
+	to := &BridgeInfo{address: common.HexToAddress("0x2")}
+	vtr := NewValueTransferRecovery(config, from, to)
+
+	assert.Equal(t, time.Hour, vtr.retryBackoff(1))
+	assert.Equal(t, 2*time.Hour, vtr.retryBackoff(2))
+	assert.Equal(t, 4*time.Hour, vtr.retryBackoff(3))
+	assert.Equal(t, 4*time.Hour, vtr.retryBackoff(10))
+
+	ev := RequestValueTransferEvent{&bridge.BridgeRequestValueTransfer{
+		RequestNonce:   3,
+		ValueOrTokenId: big.NewInt(100),
+		Raw:            types.Log{BlockNumber: 10},
+	}}
+	evs := []IRequestValueTransferEvent{ev}
+
+	// The first attempt is due immediately.
+	assert.Equal(t, 1, len(vtr.dueEvents(evs, from, to)))
+	// The next attempt waits for the backoff.
+	assert.Equal(t, 0, len(vtr.dueEvents(evs, from, to)))
+
+	// The transfer is dead-lettered after the maximum attempts.
+	vtr.transfers[pendingTransferKey{from.address, 3}].NextRetry = time.Time{}
+	assert.Equal(t, 0, len(vtr.dueEvents(evs, from, to)))
+
+	transfers := vtr.PendingTransfers()
+	assert.Equal(t, 1, len(transfers))
+	assert.Equal(t, uint64(3), transfers[0].RequestNonce)
+	assert.Equal(t, uint64(10), transfers[0].BlockNumber)
+	assert.Equal(t, uint64(1), transfers[0].Attempts)
+	assert.True(t, transfers[0].DeadLettered)
+
+	// The handled transfers are pruned.
+	vtr.prunePendingTransfers(from, 4)
+	assert.Equal(t, 0, len(vtr.PendingTransfers()))
+	assert.Equal(t, ErrNoPendingTransfer, vtr.RetryTransfer(from.address, 3))
+}
+
+// TestReconcileRequests checks the unhandled requests found by the reconciliation.
+func TestReconcileRequests(t *testing.T) {
+	info := prepare(t, func(info *testInfo) {
+		for i := 0; i < testTxCount; i++ {
+			ops[KLAY].request(info, info.localInfo)
+		}
+	})
+	defer info.sim.Close()
+
+	result, err := reconcileRequests(info.localInfo, info.remoteInfo, 0, 0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(testTxCount), result.RequestNonce)
+	assert.Equal(t, uint64(testTxCount), result.Requests)
+	assert.Equal(t, uint64(testTxCount-testPendingCount), result.Handled)
+	assert.Equal(t, testPendingCount, len(result.UnhandledRequests))
+	for _, req := range result.UnhandledRequests {
+		assert.True(t, req.RequestNonce >= uint64(testTxCount-testPendingCount))
+		assert.False(t, req.Resubmitted)
+	}
+
+	// an empty range
+	_, err = reconcileRequests(info.localInfo, info.remoteInfo, 1000, 0, false)
+	assert.Equal(t, ErrInvalidReconcileRange, err)
+}
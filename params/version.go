@@ -20,7 +20,10 @@
 
 package params
 
-import "fmt"
+import (
+	"fmt"
+	"runtime"
+)
 
 const (
 	ReleaseNum   = 0
@@ -29,6 +32,12 @@ const (
 	VersionPatch = 0 // Patch version component of the current release
 )
 
+// GitCommit and BuildDate are set by the linker flags of the release builds.
+var (
+	GitCommit = ""
+	BuildDate = ""
+)
+
 // Version holds the textual version string.
 var Version = func() string {
 	v := fmt.Sprintf("v%d.%d.%d", VersionMajor, VersionMinor, VersionPatch)
@@ -42,3 +51,25 @@ func VersionWithCommit(gitCommit string) string {
 	}
 	return vsn
 }
+
+// VersionInfo describes the build of the running binary.
+type VersionInfo struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"gitCommit"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+}
+
+// GetVersionInfo returns the build information of the running binary.
+func GetVersionInfo() *VersionInfo {
+	return &VersionInfo{
+		Version:   VersionWithCommit(GitCommit),
+		GitCommit: GitCommit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+}
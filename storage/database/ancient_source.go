@@ -0,0 +1,235 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/log"
+	"github.com/klaytn/klaytn/rlp"
+	"github.com/rcrowley/go-metrics"
+)
+
+var errReadOnlyFileDB = errors.New("the file database is read-only")
+
+var (
+	ancientSourceReadMeter     = metrics.NewRegisteredMeter("klay/db/ancient/read", nil)
+	ancientSourceMissMeter     = metrics.NewRegisteredMeter("klay/db/ancient/miss", nil)
+	ancientSourceCacheHitMeter = metrics.NewRegisteredMeter("klay/db/ancient/cache/hit", nil)
+)
+
+// AncientSourceConfig handles the configurations of the remote read-only source
+// of the ancient blocks and receipts, such as an S3 bucket served over HTTP. The
+// value of a key of a database is fetched from the URL followed by the database
+// name and the key in hex with the 0x prefix, e.g. <URL>/body/0x62..., which is
+// the object name of the cold storage. If the URL contains "{db}", it is
+// replaced by the database name instead, e.g. for the bucket of each database.
+type AncientSourceConfig struct {
+	URL       string
+	Until     uint64        // the source serves the blocks before this number
+	CacheSize int           // number of the values cached in memory
+	Timeout   time.Duration // timeout of a request to the source
+}
+
+func GetDefaultAncientSourceConfig() *AncientSourceConfig {
+	return &AncientSourceConfig{
+		CacheSize: 1024,
+		Timeout:   10 * time.Second,
+	}
+}
+
+// httpFileDB is a read-only fileDB which reads the items over HTTP.
+type httpFileDB struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPFileDB(url string, timeout time.Duration) *httpFileDB {
+	return &httpFileDB{url: strings.TrimSuffix(url, "/"), client: &http.Client{Timeout: timeout}}
+}
+
+func (fdb *httpFileDB) write(item item) (string, error) {
+	return "", errReadOnlyFileDB
+}
+
+// read gets the data of the given key, or returns dataNotFoundErr if the source
+// does not have it.
+func (fdb *httpFileDB) read(key []byte) ([]byte, error) {
+	resp, err := fdb.client.Get(fdb.url + "/" + hexutil.Encode(key))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return ioutil.ReadAll(resp.Body)
+	case http.StatusNotFound, http.StatusForbidden: // S3 returns 403 for a missing object without the list permission
+		return nil, dataNotFoundErr
+	default:
+		return nil, fmt.Errorf("failed to read data over HTTP. key: %v, status: %v", hexutil.Encode(key), resp.Status)
+	}
+}
+
+func (fdb *httpFileDB) delete(key []byte) error {
+	return errReadOnlyFileDB
+}
+
+func (fdb *httpFileDB) deleteBucket() {}
+
+// ancientSourceDB wraps the local database of the chain data, and reads the
+// values not found locally from the remote source, so that a new node can serve
+// the ancient blocks before syncing them. Only the values of the blocks before
+// the configured number are read from the source, since the data of the other
+// blocks would make the node regard them as synced. The iterators only iterate
+// the local database.
+type ancientSourceDB struct {
+	Database // local database
+
+	until  uint64
+	fdb    fileDB
+	cache  *lru.Cache
+	logger log.Logger
+}
+
+func newAncientSourceDB(local Database, config *AncientSourceConfig, entryType DBEntryType, fdb fileDB) (*ancientSourceDB, error) {
+	cacheSize := config.CacheSize
+	if cacheSize <= 0 {
+		cacheSize = GetDefaultAncientSourceConfig().CacheSize
+	}
+	cache, err := lru.New(cacheSize)
+	if err != nil {
+		return nil, err
+	}
+	return &ancientSourceDB{
+		Database: local,
+		until:    config.Until,
+		fdb:      fdb,
+		cache:    cache,
+		logger:   logger.NewWith("entry", entryType.String()),
+	}, nil
+}
+
+// Get returns the value from the local database, or from the remote source if
+// it is not found locally.
+func (db *ancientSourceDB) Get(key []byte) ([]byte, error) {
+	val, err := db.Database.Get(key)
+	if err != dataNotFoundErr {
+		return val, err
+	}
+	if number, ok := ancientKeyNumber(key); ok && number >= db.until {
+		return nil, dataNotFoundErr
+	}
+
+	if cached, ok := db.cache.Get(string(key)); ok {
+		ancientSourceCacheHitMeter.Mark(1)
+		return common.CopyBytes(cached.([]byte)), nil
+	}
+	ancientSourceReadMeter.Mark(1)
+	val, err = db.fdb.read(key)
+	if err == dataNotFoundErr {
+		ancientSourceMissMeter.Mark(1)
+		return nil, err
+	}
+	if err != nil {
+		db.logger.Error("failed to read data from the ancient source", "key", common.Bytes2Hex(key), "err", err)
+		return nil, dataNotFoundErr
+	}
+	if number, ok := ancientValueNumber(key, val); ok && number >= db.until {
+		return nil, dataNotFoundErr
+	}
+	db.cache.Add(string(key), common.CopyBytes(val))
+	return val, nil
+}
+
+func (db *ancientSourceDB) Has(key []byte) (bool, error) {
+	if _, err := db.Get(key); err != nil {
+		if err == dataNotFoundErr {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ancientKeyNumber returns the block number embedded in the key.
+func ancientKeyNumber(key []byte) (uint64, bool) {
+	for _, prefix := range [][]byte{headerPrefix, blockBodyPrefix, blockReceiptsPrefix} {
+		if bytes.HasPrefix(key, prefix) && len(key) >= len(prefix)+8 {
+			return binary.BigEndian.Uint64(key[len(prefix):]), true
+		}
+	}
+	return 0, false
+}
+
+// ancientValueNumber returns the block number in the value of the key which maps
+// a hash to its block. A malformed value is regarded as of a future block, not
+// to be served.
+func ancientValueNumber(key, val []byte) (uint64, bool) {
+	switch {
+	case bytes.HasPrefix(key, headerNumberPrefix):
+		if len(val) != 8 {
+			return math.MaxUint64, true
+		}
+		return binary.BigEndian.Uint64(val), true
+	case bytes.HasPrefix(key, txLookupPrefix):
+		var entry TxLookupEntry
+		if err := rlp.DecodeBytes(val, &entry); err != nil {
+			return math.MaxUint64, true
+		}
+		return entry.BlockIndex, true
+	}
+	return 0, false
+}
+
+// ancientSourceURL returns the URL of the database of the given name.
+func ancientSourceURL(url, name string) string {
+	if strings.Contains(url, "{db}") {
+		return strings.ReplaceAll(url, "{db}", name)
+	}
+	return strings.TrimSuffix(url, "/") + "/" + name
+}
+
+// enableAncientSource wraps the databases of the blocks, receipts and transaction
+// lookups with the remote source of the ancient data.
+func (dbm *databaseManager) enableAncientSource(config *AncientSourceConfig) error {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = GetDefaultAncientSourceConfig().Timeout
+	}
+	for _, entryType := range []DBEntryType{headerDB, BodyDB, ReceiptsDB, TxLookUpEntryDB} {
+		fdb := newHTTPFileDB(ancientSourceURL(config.URL, dbBaseDirs[entryType]), timeout)
+		db, err := newAncientSourceDB(dbm.dbs[entryType], config, entryType, fdb)
+		if err != nil {
+			return err
+		}
+		dbm.setDatabase(entryType, db)
+	}
+	logger.Info("Ancient data source is enabled", "url", config.URL, "until", config.Until)
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAncientSourceDB(t *testing.T) {
+	hash := common.HexToHash("0x01")
+	remote := map[string][]byte{
+		"/header/" + hexutil.Encode(headerKey(10, hash)):            []byte("header10"),
+		"/header/" + hexutil.Encode(headerKey(100, hash)):           []byte("header100"),
+		"/header/" + hexutil.Encode(headerNumberKey(hash)):          common.Int64ToByteBigEndian(100),
+		"/header/" + hexutil.Encode(headerNumberKey(common.Hash{})): common.Int64ToByteBigEndian(10),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if val, ok := remote[r.URL.Path]; ok {
+			w.Write(val)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	local := NewMemDB()
+	local.Put(headerKey(20, hash), []byte("local20"))
+
+	config := &AncientSourceConfig{URL: server.URL, Until: 50}
+	db, err := newAncientSourceDB(local, config, headerDB, newHTTPFileDB(ancientSourceURL(config.URL, "header"), 0))
+	assert.NoError(t, err)
+
+	val, err := db.Get(headerKey(20, hash))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("local20"), val)
+
+	val, err = db.Get(headerKey(10, hash))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("header10"), val)
+
+	// The values of the blocks after the configured number are not served.
+	_, err = db.Get(headerKey(100, hash))
+	assert.Equal(t, dataNotFoundErr, err)
+	has, err := db.Has(headerNumberKey(hash))
+	assert.NoError(t, err)
+	assert.False(t, has)
+	has, err = db.Has(headerNumberKey(common.Hash{}))
+	assert.NoError(t, err)
+	assert.True(t, has)
+
+	_, err = db.Get(headerKey(11, hash))
+	assert.Equal(t, dataNotFoundErr, err)
+}
+
+func TestAncientSourceURL(t *testing.T) {
+	assert.Equal(t, "https://example.com/ancient/body", ancientSourceURL("https://example.com/ancient/", "body"))
+	assert.Equal(t, "https://klaytn-body.s3.amazonaws.com", ancientSourceURL("https://klaytn-{db}.s3.amazonaws.com", "body"))
+}
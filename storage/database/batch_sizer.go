@@ -0,0 +1,130 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/pbnjay/memory"
+	"github.com/rcrowley/go-metrics"
+)
+
+const (
+	minAdaptiveBatchSize = IdealBatchSize / 4
+	maxAdaptiveBatchSize = IdealBatchSize * 40
+
+	// batchWriteLatencyTarget is the latency of a batch write the size converges
+	// to. The size shrinks by half if a write is slower than the target, and
+	// grows by a quarter if a write is faster than the half of it.
+	batchWriteLatencyTarget = 50 * time.Millisecond
+
+	// memoryPressureRatio is the ratio of the heap to the physical memory, over
+	// which the size drops to the minimum until the pressure is relieved.
+	memoryPressureRatio = 0.8
+	memoryCheckInterval = time.Second
+)
+
+var (
+	adaptiveBatchSizeGauge    = metrics.NewRegisteredGauge("klay/db/batch/adaptive/size", nil)
+	batchWriteLatencyGauge    = metrics.NewRegisteredGauge("klay/db/batch/adaptive/latency", nil)
+	batchMemoryPressureMeter  = metrics.NewRegisteredMeter("klay/db/batch/adaptive/memorypressure", nil)
+	defaultAdaptiveBatchSizer = newBatchSizer(heapInUse, memory.TotalMemory())
+)
+
+// batchSizer adapts the size threshold of the write batches during the block
+// import, to the latency of the batch writes and the memory pressure. It
+// smooths out the write stalls on slow disks, which grow the latency of the
+// large batches, while the batches grow on fast disks.
+type batchSizer struct {
+	mu   sync.Mutex
+	size int
+
+	heapInUse     func() uint64
+	totalMemory   uint64
+	lastCheck     time.Time
+	underPressure bool
+}
+
+func newBatchSizer(heapInUse func() uint64, totalMemory uint64) *batchSizer {
+	adaptiveBatchSizeGauge.Update(IdealBatchSize)
+	return &batchSizer{size: IdealBatchSize, heapInUse: heapInUse, totalMemory: totalMemory}
+}
+
+func heapInUse() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapInuse
+}
+
+// Size returns the current size threshold of a batch.
+func (s *batchSizer) Size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+// observe adjusts the size by the latency of a batch write.
+func (s *batchSizer) observe(elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	batchWriteLatencyGauge.Update(elapsed.Milliseconds())
+	if now := time.Now(); s.totalMemory > 0 && now.Sub(s.lastCheck) >= memoryCheckInterval {
+		s.lastCheck = now
+		s.underPressure = float64(s.heapInUse()) > float64(s.totalMemory)*memoryPressureRatio
+	}
+
+	switch {
+	case s.underPressure:
+		batchMemoryPressureMeter.Mark(1)
+		s.size = minAdaptiveBatchSize
+	case elapsed > batchWriteLatencyTarget:
+		s.size /= 2
+	case elapsed < batchWriteLatencyTarget/2:
+		s.size += s.size / 4
+	}
+	if s.size < minAdaptiveBatchSize {
+		s.size = minAdaptiveBatchSize
+	} else if s.size > maxAdaptiveBatchSize {
+		s.size = maxAdaptiveBatchSize
+	}
+	adaptiveBatchSizeGauge.Update(int64(s.size))
+}
+
+// writeBatch writes the batch and adjusts the size by its latency.
+func (s *batchSizer) writeBatch(batch Batch) error {
+	start := time.Now()
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	s.observe(time.Since(start))
+	return nil
+}
+
+// AdaptiveBatchSize returns the size threshold of the write batches during the
+// block import, which adapts to the latency of the writes and the memory pressure.
+func AdaptiveBatchSize() int {
+	return defaultAdaptiveBatchSizer.Size()
+}
+
+// WriteAdaptiveBatch writes the batch, and adapts the batch size threshold to the
+// latency of the write.
+func WriteAdaptiveBatch(batch Batch) error {
+	return defaultAdaptiveBatchSizer.writeBatch(batch)
+}
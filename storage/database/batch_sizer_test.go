@@ -0,0 +1,64 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchSizer(t *testing.T) {
+	heap := uint64(0)
+	s := newBatchSizer(func() uint64 { return heap }, 100)
+	assert.Equal(t, IdealBatchSize, s.Size())
+
+	// Fast writes grow the size up to the maximum.
+	for i := 0; i < 100; i++ {
+		s.observe(time.Millisecond)
+	}
+	assert.Equal(t, maxAdaptiveBatchSize, s.Size())
+
+	// Slow writes shrink the size by half down to the minimum.
+	s.observe(batchWriteLatencyTarget * 2)
+	assert.Equal(t, maxAdaptiveBatchSize/2, s.Size())
+	for i := 0; i < 100; i++ {
+		s.observe(batchWriteLatencyTarget * 2)
+	}
+	assert.Equal(t, minAdaptiveBatchSize, s.Size())
+
+	// The size is kept if the latency is around the target.
+	s.observe(batchWriteLatencyTarget * 3 / 4)
+	assert.Equal(t, minAdaptiveBatchSize, s.Size())
+}
+
+func TestBatchSizer_MemoryPressure(t *testing.T) {
+	heap := uint64(90)
+	s := newBatchSizer(func() uint64 { return heap }, 100)
+	s.observe(time.Millisecond)
+	assert.Equal(t, minAdaptiveBatchSize, s.Size())
+
+	// The memory is checked once in the interval.
+	heap = 10
+	s.observe(time.Millisecond)
+	assert.Equal(t, minAdaptiveBatchSize, s.Size())
+
+	s.lastCheck = time.Time{}
+	s.observe(time.Millisecond)
+	assert.Equal(t, minAdaptiveBatchSize+minAdaptiveBatchSize/4, s.Size())
+}
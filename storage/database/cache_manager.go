@@ -75,6 +75,22 @@ const (
 	cacheKeySize
 )
 
+// cacheNames are the names of the caches used for the cache statistics.
+var cacheNames = [cacheKeySize]string{
+	headerCacheIndex:      "header",
+	tdCacheIndex:          "td",
+	blockNumberCacheIndex: "blocknumber",
+	canonicalCacheIndex:   "canonicalhash",
+
+	bodyCacheIndex:             "body",
+	bodyRLPCacheIndex:          "bodyrlp",
+	blockCacheIndex:            "block",
+	recentTxAndLookupInfoIndex: "txlookup",
+	recentBlockReceiptsIndex:   "blockreceipts",
+	recentTxReceiptIndex:       "txreceipt",
+	senderTxHashToTxHashIndex:  "sendertxhash",
+}
+
 var lruCacheConfig = [cacheKeySize]common.CacheConfiger{
 	headerCacheIndex:      common.LRUConfig{CacheSize: maxHeaderCache, IsScaled: true},
 	tdCacheIndex:          common.LRUConfig{CacheSize: maxTdCache, IsScaled: true},
@@ -123,15 +139,16 @@ var fifoCacheConfig = [cacheKeySize]common.CacheConfiger{
 func newCache(cacheNameKey cacheKey, cacheType common.CacheType) common.Cache {
 	var cache common.Cache
 
+	name := cacheNames[cacheNameKey]
 	switch cacheType {
 	case common.FIFOCacheType:
-		cache = common.NewCache(fifoCacheConfig[cacheNameKey])
+		cache = common.NewCacheWithStats(name, fifoCacheConfig[cacheNameKey])
 	case common.LRUCacheType:
-		cache = common.NewCache(lruCacheConfig[cacheNameKey])
+		cache = common.NewCacheWithStats(name, lruCacheConfig[cacheNameKey])
 	case common.LRUShardCacheType:
-		cache = common.NewCache(lruShardCacheConfig[cacheNameKey])
+		cache = common.NewCacheWithStats(name, lruShardCacheConfig[cacheNameKey])
 	default:
-		cache = common.NewCache(fifoCacheConfig[cacheNameKey])
+		cache = common.NewCacheWithStats(name, fifoCacheConfig[cacheNameKey])
 	}
 	return cache
 }
@@ -178,7 +195,7 @@ func newCacheManager() *cacheManager {
 		recentBlockReceipts:   newCache(recentBlockReceiptsIndex, common.DefaultCacheType),
 		recentTxReceipt:       newCache(recentTxReceiptIndex, common.DefaultCacheType),
 
-		senderTxHashToTxHashCache: newCache(recentTxReceiptIndex, common.DefaultCacheType),
+		senderTxHashToTxHashCache: newCache(senderTxHashToTxHashIndex, common.DefaultCacheType),
 	}
 	return cm
 }
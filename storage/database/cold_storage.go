@@ -0,0 +1,321 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+// Cold storage tier of the ancient chain data.
+//
+// [WARN] Using the cold storage may cause pricing in your AWS or GCP account.
+//
+// The block bodies and receipts older than the age threshold are moved to an
+// object storage bucket, and their local values are replaced by a marker which
+// works as the local index of the migrated data. Google Cloud Storage can be
+// used through its S3 compatible endpoint (https://storage.googleapis.com) with
+// HMAC keys given as the AWS credentials.
+//    $ export AWS_ACCESS_KEY_ID=YOUR_ACCESS_KEY
+//    $ export AWS_SECRET_ACCESS_KEY=YOUR_SECRET
+
+package database
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/log"
+	"github.com/rcrowley/go-metrics"
+)
+
+// coldStorageMarker replaces the local value of the data moved to the cold storage.
+var coldStorageMarker = []byte("klay-cold-storage")
+
+// coldStorageProgressKey stores the number of the next block to move to the cold storage.
+var coldStorageProgressKey = []byte("ColdStorageNextBlockNumber")
+
+var (
+	coldStorageMigratedItemCounter = metrics.NewRegisteredCounter("klay/db/cold/migrated/items", nil)
+	coldStorageMigratedSizeCounter = metrics.NewRegisteredCounter("klay/db/cold/migrated/bytes", nil)
+	coldStorageReadMeter           = metrics.NewRegisteredMeter("klay/db/cold/read", nil)
+	coldStorageCacheHitMeter       = metrics.NewRegisteredMeter("klay/db/cold/cache/hit", nil)
+)
+
+// ColdStorageConfig handles the configurations of the cold storage tier.
+type ColdStorageConfig struct {
+	Enabled  bool
+	Bucket   string // bucket of the object storage
+	Region   string
+	Endpoint string // S3 compatible endpoint, empty for AWS S3
+
+	MinAge      uint64        // blocks behind the head to be moved to the cold storage
+	MinSize     int           // values smaller than this stay in the local storage
+	CacheSize   int           // number of the cold values cached in memory
+	MigrateTick time.Duration // interval of the migration
+}
+
+func GetDefaultColdStorageConfig() *ColdStorageConfig {
+	return &ColdStorageConfig{
+		Region:      "ap-northeast-2",
+		MinAge:      1000000,
+		MinSize:     1024,
+		CacheSize:   1024,
+		MigrateTick: 10 * time.Minute,
+	}
+}
+
+// coldStorageDB wraps the local database of the block bodies or receipts, and
+// reads the values moved to the cold storage through an in-memory cache.
+// The keys of the wrapped database must be a prefix followed by the block number.
+type coldStorageDB struct {
+	Database // local database
+
+	config *ColdStorageConfig
+	prefix []byte // key prefix of the migrated data, e.g. blockBodyPrefix
+	fdb    fileDB
+	cache  *lru.Cache
+	head   func() uint64 // returns the current head block number
+
+	quit   chan struct{}
+	wg     sync.WaitGroup
+	logger log.Logger
+}
+
+func newColdStorageDB(local Database, config *ColdStorageConfig, entryType DBEntryType, prefix []byte, fdb fileDB, head func() uint64) (*coldStorageDB, error) {
+	defaults := GetDefaultColdStorageConfig()
+	if config.CacheSize <= 0 {
+		config.CacheSize = defaults.CacheSize
+	}
+	if config.MigrateTick <= 0 {
+		config.MigrateTick = defaults.MigrateTick
+	}
+	cache, err := lru.New(config.CacheSize)
+	if err != nil {
+		return nil, err
+	}
+	return &coldStorageDB{
+		Database: local,
+		config:   config,
+		prefix:   prefix,
+		fdb:      fdb,
+		cache:    cache,
+		head:     head,
+		quit:     make(chan struct{}),
+		logger:   logger.NewWith("entry", entryType.String(), "bucket", config.Bucket),
+	}, nil
+}
+
+// Get returns the value from the local database, or from the cold storage if
+// the value has been moved.
+func (db *coldStorageDB) Get(key []byte) ([]byte, error) {
+	val, err := db.Database.Get(key)
+	if err != nil || !bytes.Equal(val, coldStorageMarker) {
+		return val, err
+	}
+
+	if cached, ok := db.cache.Get(string(key)); ok {
+		coldStorageCacheHitMeter.Mark(1)
+		return common.CopyBytes(cached.([]byte)), nil
+	}
+	coldStorageReadMeter.Mark(1)
+	val, err = db.fdb.read(key)
+	if err != nil {
+		db.logger.Error("failed to read data from the cold storage", "key", common.Bytes2Hex(key), "err", err)
+		return nil, err
+	}
+	db.cache.Add(string(key), common.CopyBytes(val))
+	return val, nil
+}
+
+// Delete deletes the key from the local database and the cold storage.
+func (db *coldStorageDB) Delete(key []byte) error {
+	if val, err := db.Database.Get(key); err == nil && bytes.Equal(val, coldStorageMarker) {
+		db.cache.Remove(string(key))
+		if err := db.fdb.delete(key); err != nil {
+			return err
+		}
+	}
+	return db.Database.Delete(key)
+}
+
+func (db *coldStorageDB) start() {
+	db.wg.Add(1)
+	go db.loop()
+}
+
+func (db *coldStorageDB) Close() {
+	close(db.quit)
+	db.wg.Wait()
+	db.Database.Close()
+}
+
+func (db *coldStorageDB) loop() {
+	defer db.wg.Done()
+
+	ticker := time.NewTicker(db.config.MigrateTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			head := db.head()
+			if head < db.config.MinAge {
+				continue
+			}
+			if err := db.migrate(head - db.config.MinAge); err != nil {
+				db.logger.Error("failed to move data to the cold storage", "err", err)
+			}
+		case <-db.quit:
+			return
+		}
+	}
+}
+
+// migrate moves the values of the blocks before the given block number to the
+// cold storage. Each value is written to the cold storage before its local
+// value is replaced, so the data is never lost if the migration is interrupted.
+func (db *coldStorageDB) migrate(until uint64) error {
+	from := uint64(0)
+	if data, err := db.Database.Get(coldStorageProgressKey); err == nil && len(data) == 8 {
+		from = binary.BigEndian.Uint64(data)
+	}
+	if from >= until {
+		return nil
+	}
+
+	it := db.Database.NewIterator(db.prefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	var (
+		batch   = db.Database.NewBatch()
+		moved   int
+		size    int
+		next    = from
+		started = time.Now()
+	)
+	for it.Next() {
+		key := it.Key()
+		if len(key) < len(db.prefix)+8 {
+			continue
+		}
+		number := binary.BigEndian.Uint64(key[len(db.prefix):])
+		if number >= until {
+			break
+		}
+		select {
+		case <-db.quit:
+			return db.writeProgress(batch, next)
+		default:
+		}
+		next = number
+
+		val := it.Value()
+		if len(val) < db.config.MinSize || bytes.Equal(val, coldStorageMarker) {
+			continue
+		}
+		if _, err := db.fdb.write(item{key: common.CopyBytes(key), val: common.CopyBytes(val)}); err != nil {
+			if perr := db.writeProgress(batch, next); perr != nil {
+				return perr
+			}
+			return err
+		}
+		if err := batch.Put(common.CopyBytes(key), coldStorageMarker); err != nil {
+			return err
+		}
+		moved++
+		size += len(val)
+		coldStorageMigratedItemCounter.Inc(1)
+		coldStorageMigratedSizeCounter.Inc(int64(len(val)))
+
+		if batch.ValueSize() > IdealBatchSize {
+			if err := db.writeProgress(batch, next); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := db.writeProgress(batch, until); err != nil {
+		return err
+	}
+	if moved > 0 {
+		db.logger.Info("Moved data to the cold storage", "from", from, "until", until, "items", moved,
+			"size", common.StorageSize(size), "elapsed", common.PrettyDuration(time.Since(started)))
+	}
+	return nil
+}
+
+// writeProgress writes the replaced local values with the number of the next
+// block to migrate.
+func (db *coldStorageDB) writeProgress(batch Batch, next uint64) error {
+	if err := batch.Put(coldStorageProgressKey, encodeBlockNumber(next)); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// RewindColdStorage lowers the number of the next block to move to the cold
+// storage to the given number, so that the blocks written again after a rewind
+// of the chain are moved as well. The values of the deleted blocks are removed
+// from the cold storage by Delete.
+func (dbm *databaseManager) RewindColdStorage(next uint64) error {
+	for _, entryType := range []DBEntryType{BodyDB, ReceiptsDB} {
+		db := dbm.getDatabase(entryType)
+		if db == nil {
+			continue
+		}
+		data, err := db.Get(coldStorageProgressKey)
+		if err != nil || len(data) != 8 || binary.BigEndian.Uint64(data) <= next {
+			continue
+		}
+		if err := db.Put(coldStorageProgressKey, encodeBlockNumber(next)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enableColdStorage wraps the block body and receipts databases with the cold
+// storage tier. A bucket is created for each of them.
+func (dbm *databaseManager) enableColdStorage(config *ColdStorageConfig) error {
+	head := func() uint64 {
+		if number := dbm.ReadHeaderNumber(dbm.ReadHeadBlockHash()); number != nil {
+			return *number
+		}
+		return 0
+	}
+	for _, entry := range []struct {
+		entryType DBEntryType
+		prefix    []byte
+	}{
+		{BodyDB, blockBodyPrefix},
+		{ReceiptsDB, blockReceiptsPrefix},
+	} {
+		fdb, err := newS3FileDB(config.Region, config.Endpoint, config.Bucket+"-"+dbBaseDirs[entry.entryType])
+		if err != nil {
+			return err
+		}
+		db, err := newColdStorageDB(dbm.dbs[entry.entryType], config, entry.entryType, entry.prefix, fdb, head)
+		if err != nil {
+			return err
+		}
+		db.start()
+		dbm.setDatabase(entry.entryType, db)
+	}
+	logger.Info("Cold storage tier is enabled", "bucket", config.Bucket, "minAge", config.MinAge, "minSize", config.MinSize)
+	return nil
+}
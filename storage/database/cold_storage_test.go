@@ -0,0 +1,151 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// memFileDB is a fileDB keeping the items in memory.
+type memFileDB struct {
+	mu    sync.Mutex
+	items map[string][]byte
+	reads int
+}
+
+func newMemFileDB() *memFileDB {
+	return &memFileDB{items: make(map[string][]byte)}
+}
+
+func (f *memFileDB) write(item item) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items[string(item.key)] = common.CopyBytes(item.val)
+	return common.Bytes2Hex(item.key), nil
+}
+
+func (f *memFileDB) read(key []byte) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reads++
+	val, ok := f.items[string(key)]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return common.CopyBytes(val), nil
+}
+
+func (f *memFileDB) delete(key []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.items, string(key))
+	return nil
+}
+
+func (f *memFileDB) deleteBucket() {}
+
+func TestColdStorageDB_Migrate(t *testing.T) {
+	local := NewMemDB()
+	fdb := newMemFileDB()
+	config := GetDefaultColdStorageConfig()
+	config.MinSize = 10
+
+	db, err := newColdStorageDB(local, config, BodyDB, blockBodyPrefix, fdb, func() uint64 { return 0 })
+	assert.NoError(t, err)
+
+	large := common.MakeRandomBytes(100)
+	small := common.MakeRandomBytes(5)
+	for number := uint64(0); number < 10; number++ {
+		val := large
+		if number == 3 {
+			val = small
+		}
+		assert.NoError(t, db.Put(blockBodyKey(number, common.Hash{byte(number)}), val))
+	}
+
+	assert.NoError(t, db.migrate(5))
+
+	// The large values of the blocks before 5 are moved, and their local values are replaced.
+	assert.Equal(t, 4, len(fdb.items))
+	for number := uint64(0); number < 10; number++ {
+		key := blockBodyKey(number, common.Hash{byte(number)})
+		localVal, _ := local.Get(key)
+		moved := number < 5 && number != 3
+		assert.Equal(t, moved, string(localVal) == string(coldStorageMarker), "block %d", number)
+
+		val, err := db.Get(key)
+		assert.NoError(t, err)
+		if number == 3 {
+			assert.Equal(t, small, val)
+		} else {
+			assert.Equal(t, large, val)
+		}
+	}
+	assert.Equal(t, 4, fdb.reads)
+
+	// The values read from the cold storage are cached.
+	_, err = db.Get(blockBodyKey(0, common.Hash{0}))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, fdb.reads)
+
+	// The migration continues from the last progress.
+	assert.NoError(t, db.migrate(8))
+	assert.Equal(t, 7, len(fdb.items))
+
+	// Deleting a moved value deletes it from the cold storage.
+	assert.NoError(t, db.Delete(blockBodyKey(0, common.Hash{0})))
+	assert.Equal(t, 6, len(fdb.items))
+	has, _ := db.Has(blockBodyKey(0, common.Hash{0}))
+	assert.False(t, has)
+}
+
+func TestDatabaseManager_RewindColdStorage(t *testing.T) {
+	dbm := NewMemoryDBManager().(*databaseManager)
+	fdb := newMemFileDB()
+	config := GetDefaultColdStorageConfig()
+	config.MinSize = 10
+
+	db, err := newColdStorageDB(dbm.dbs[0], config, BodyDB, blockBodyPrefix, fdb, func() uint64 { return 0 })
+	assert.NoError(t, err)
+	dbm.setDatabase(0, db)
+
+	for number := uint64(0); number < 10; number++ {
+		assert.NoError(t, db.Put(blockBodyKey(number, common.Hash{byte(number)}), common.MakeRandomBytes(100)))
+	}
+	assert.NoError(t, db.migrate(8))
+	assert.Equal(t, 8, len(fdb.items))
+
+	// The blocks from 6 are deleted by a rewind, and the progress is lowered to 6.
+	for number := uint64(6); number < 10; number++ {
+		dbm.DeleteBody(common.Hash{byte(number)}, number)
+	}
+	assert.NoError(t, dbm.RewindColdStorage(6))
+	assert.Equal(t, 6, len(fdb.items))
+	progress, _ := db.Get(coldStorageProgressKey)
+	assert.Equal(t, uint64(6), binary.BigEndian.Uint64(progress))
+
+	// The progress is not raised.
+	assert.NoError(t, dbm.RewindColdStorage(8))
+	progress, _ = db.Get(coldStorageProgressKey)
+	assert.Equal(t, uint64(6), binary.BigEndian.Uint64(progress))
+}
@@ -0,0 +1,296 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+// CompressionCodec is the codec compressing the data of a database on disk.
+type CompressionCodec string
+
+const (
+	NoCompressionCodec CompressionCodec = "none"
+	SnappyCodec        CompressionCodec = "snappy"
+	ZstdCodec          CompressionCodec = "zstd"
+)
+
+// DefaultZstdLevel is the zstd compression level used if it is not given.
+const DefaultZstdLevel = 3
+
+// CompressionConfig configures the compression of a database.
+//
+// No compression and snappy are applied to the blocks of the tables by LevelDB.
+// Zstd is applied to each value instead, since the LevelDB does not support it.
+// It compresses large values such as block bodies and receipts well, but not
+// small values such as trie nodes.
+type CompressionConfig struct {
+	Codec CompressionCodec
+	Level int // Compression level of zstd, from 1 (fastest) to 22 (smallest)
+}
+
+func (c CompressionConfig) String() string {
+	if c.Codec == ZstdCodec {
+		return string(c.Codec) + ":" + strconv.Itoa(c.Level)
+	}
+	return string(c.Codec)
+}
+
+var (
+	errInvalidCompression = errors.New("invalid compression configuration")
+	errValueCodecChanged  = errors.New("the value compression of an existing database can not be changed, migrate the database instead")
+)
+
+// ParseDBCompression parses the compression configurations of the databases
+// given in the form of "<database>=<codec>[:<level>],...", such as
+// "body=zstd:9,receipts=zstd,statetrie=snappy". The database is one of header,
+// body, receipts, statetrie, txlookup, bridgeservice and snapshot.
+func ParseDBCompression(s string) (map[DBEntryType]CompressionConfig, error) {
+	configs := make(map[DBEntryType]CompressionConfig)
+	if s == "" {
+		return configs, nil
+	}
+	for _, item := range strings.Split(s, ",") {
+		kv := strings.SplitN(strings.TrimSpace(item), "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("%w: %q", errInvalidCompression, item)
+		}
+		entryType, ok := compressibleDBEntryType(kv[0])
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown database %q", errInvalidCompression, kv[0])
+		}
+		config, err := parseCompressionConfig(kv[1])
+		if err != nil {
+			return nil, err
+		}
+		configs[entryType] = config
+	}
+	return configs, nil
+}
+
+// compressibleDBEntryType returns the entry type of the given database name. The
+// misc database is excluded, since it records the compression of the others.
+func compressibleDBEntryType(name string) (DBEntryType, bool) {
+	for et := MiscDB + 1; et < databaseEntryTypeSize; et++ {
+		if et != StateTrieMigrationDB && dbBaseDirs[et] == name {
+			return et, true
+		}
+	}
+	return 0, false
+}
+
+func parseCompressionConfig(s string) (CompressionConfig, error) {
+	codecLevel := strings.SplitN(s, ":", 2)
+	config := CompressionConfig{Codec: CompressionCodec(strings.ToLower(codecLevel[0]))}
+	switch config.Codec {
+	case NoCompressionCodec, SnappyCodec:
+		if len(codecLevel) > 1 {
+			return config, fmt.Errorf("%w: %s does not have levels", errInvalidCompression, config.Codec)
+		}
+	case ZstdCodec:
+		config.Level = DefaultZstdLevel
+		if len(codecLevel) > 1 {
+			level, err := strconv.Atoi(codecLevel[1])
+			if err != nil || level < 1 || level > 22 {
+				return config, fmt.Errorf("%w: invalid zstd level %q", errInvalidCompression, codecLevel[1])
+			}
+			config.Level = level
+		}
+	default:
+		return config, fmt.Errorf("%w: unknown codec %q", errInvalidCompression, codecLevel[0])
+	}
+	return config, nil
+}
+
+// getCompressionConfig returns the compression configuration of the given
+// database, if it is configured.
+func getCompressionConfig(dbc *DBConfig, entryType DBEntryType) (CompressionConfig, bool) {
+	if entryType == StateTrieMigrationDB {
+		entryType = StateTrieDB
+	}
+	config, ok := dbc.Compression[entryType]
+	return config, ok
+}
+
+// getBlockCompression returns the block compression of LevelDB. The
+// compression configured for the database overrides LevelDBCompression.
+func getBlockCompression(dbc *DBConfig, entryType DBEntryType) opt.Compression {
+	config, ok := getCompressionConfig(dbc, entryType)
+	if !ok {
+		return getCompressionType(dbc.LevelDBCompression, entryType)
+	}
+	if config.Codec == SnappyCodec {
+		return opt.SnappyCompression
+	}
+	return opt.NoCompression
+}
+
+// valueCompressor compresses each value stored in a database.
+type valueCompressor interface {
+	compress(value []byte) []byte
+	decompress(value []byte) ([]byte, error)
+}
+
+// withValueCompression wraps the database to compress the values with the
+// configured value codec, if any.
+func withValueCompression(db Database, dbc *DBConfig, entryType DBEntryType) (Database, error) {
+	config, ok := getCompressionConfig(dbc, entryType)
+	if !ok || config.Codec != ZstdCodec {
+		return db, nil
+	}
+	c, err := newZstdCompressor(config.Level)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &compressedDB{Database: db, c: c}, nil
+}
+
+// valueCodec returns the codec applied to the values of the given database.
+func valueCodec(dbc *DBConfig, entryType DBEntryType) CompressionCodec {
+	if config, ok := getCompressionConfig(dbc, entryType); ok && config.Codec == ZstdCodec {
+		return ZstdCodec
+	}
+	return NoCompressionCodec
+}
+
+// checkValueCodec checks that the values of the database in the given directory
+// have been compressed with the configured codec. The codec is recorded when
+// the database is empty.
+func (dbm *databaseManager) checkValueCodec(dir string, entryType DBEntryType, db Database) error {
+	miscDB := dbm.getDatabase(MiscDB)
+	configured := valueCodec(dbm.config, entryType)
+
+	recorded, _ := miscDB.Get(databaseCodecKey(dir))
+	if len(recorded) == 0 {
+		// The values of an existing database are not compressed, so only a
+		// database configured to compress needs to be checked to be empty.
+		if configured != NoCompressionCodec && !isEmptyDatabase(db) {
+			recorded = []byte(NoCompressionCodec)
+		} else if err := miscDB.Put(databaseCodecKey(dir), []byte(configured)); err != nil {
+			return err
+		} else {
+			recorded = []byte(configured)
+		}
+	}
+	if CompressionCodec(recorded) != configured {
+		return fmt.Errorf("%w (dir: %s, stored: %s, configured: %s)", errValueCodecChanged, dir, recorded, configured)
+	}
+	return nil
+}
+
+// deleteValueCodec deletes the codec recorded for the database in the given directory.
+func (dbm *databaseManager) deleteValueCodec(dir string) {
+	if err := dbm.getDatabase(MiscDB).Delete(databaseCodecKey(dir)); err != nil {
+		logger.Error("Failed to delete the value codec of a database", "dir", dir, "err", err)
+	}
+}
+
+// compressedDB compresses the values of the wrapped database.
+type compressedDB struct {
+	Database
+	c valueCompressor
+}
+
+func (db *compressedDB) Put(key []byte, value []byte) error {
+	return db.Database.Put(key, db.c.compress(value))
+}
+
+func (db *compressedDB) Get(key []byte) ([]byte, error) {
+	value, err := db.Database.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return db.c.decompress(value)
+}
+
+func (db *compressedDB) NewBatch() Batch {
+	return &compressedBatch{Batch: db.Database.NewBatch(), c: db.c}
+}
+
+func (db *compressedDB) NewIterator(prefix []byte, start []byte) Iterator {
+	return &compressedIterator{Iterator: db.Database.NewIterator(prefix, start), c: db.c}
+}
+
+type compressedBatch struct {
+	Batch
+	c valueCompressor
+}
+
+func (b *compressedBatch) Put(key []byte, value []byte) error {
+	return b.Batch.Put(key, b.c.compress(value))
+}
+
+func (b *compressedBatch) Replay(w KeyValueWriter) error {
+	return b.Batch.Replay(&decompressingWriter{KeyValueWriter: w, c: b.c})
+}
+
+// decompressingWriter writes the decompressed values replayed from a compressedBatch.
+type decompressingWriter struct {
+	KeyValueWriter
+	c valueCompressor
+}
+
+func (w *decompressingWriter) Put(key []byte, value []byte) error {
+	value, err := w.c.decompress(value)
+	if err != nil {
+		return err
+	}
+	return w.KeyValueWriter.Put(key, value)
+}
+
+type compressedIterator struct {
+	Iterator
+	c     valueCompressor
+	value []byte
+	err   error
+}
+
+func (it *compressedIterator) Next() bool {
+	if it.err != nil || !it.Iterator.Next() {
+		return false
+	}
+	it.value, it.err = it.c.decompress(it.Iterator.Value())
+	return it.err == nil
+}
+
+func (it *compressedIterator) Value() []byte {
+	return it.value
+}
+
+func (it *compressedIterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.Iterator.Error()
+}
+
+// isEmptyDatabase returns true if the database has no entries. A database
+// which can't be iterated, e.g. DynamoDB, is not regarded as empty.
+func isEmptyDatabase(db Database) bool {
+	it := db.NewIterator(nil, nil)
+	if it == nil {
+		return false
+	}
+	defer it.Release()
+	return !it.Next()
+}
@@ -0,0 +1,132 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+func TestParseDBCompression(t *testing.T) {
+	configs, err := ParseDBCompression("body=zstd:9, receipts=zstd,statetrie=SNAPPY,header=none")
+	assert.NoError(t, err)
+	assert.Equal(t, map[DBEntryType]CompressionConfig{
+		BodyDB:      {Codec: ZstdCodec, Level: 9},
+		ReceiptsDB:  {Codec: ZstdCodec, Level: DefaultZstdLevel},
+		StateTrieDB: {Codec: SnappyCodec},
+		headerDB:    {Codec: NoCompressionCodec},
+	}, configs)
+
+	configs, err = ParseDBCompression("")
+	assert.NoError(t, err)
+	assert.Empty(t, configs)
+
+	for _, invalid := range []string{"body", "misc=zstd", "unknown=zstd", "body=lz4", "body=zstd:0", "body=zstd:23", "body=snappy:1"} {
+		_, err := ParseDBCompression(invalid)
+		assert.True(t, errors.Is(err, errInvalidCompression), invalid)
+	}
+}
+
+func TestGetBlockCompression(t *testing.T) {
+	dbc := &DBConfig{
+		LevelDBCompression: AllSnappyCompression,
+		Compression:        map[DBEntryType]CompressionConfig{StateTrieDB: {Codec: NoCompressionCodec}, BodyDB: {Codec: ZstdCodec}},
+	}
+	assert.Equal(t, opt.SnappyCompression, getBlockCompression(dbc, ReceiptsDB))
+	assert.Equal(t, opt.NoCompression, getBlockCompression(dbc, StateTrieDB))
+	assert.Equal(t, opt.NoCompression, getBlockCompression(dbc, StateTrieMigrationDB))
+	assert.Equal(t, opt.NoCompression, getBlockCompression(dbc, BodyDB)) // zstd compresses the values instead
+}
+
+// testCompressor "compresses" a value by prepending a marker.
+type testCompressor struct{}
+
+func (testCompressor) compress(value []byte) []byte {
+	return append([]byte{0xcc}, value...)
+}
+
+func (testCompressor) decompress(value []byte) ([]byte, error) {
+	if len(value) == 0 || value[0] != 0xcc {
+		return nil, errors.New("not compressed")
+	}
+	return value[1:], nil
+}
+
+func TestCompressedDB(t *testing.T) {
+	raw := NewMemDB()
+	db := &compressedDB{Database: raw, c: testCompressor{}}
+
+	assert.NoError(t, db.Put([]byte("a"), []byte("1")))
+	batch := db.NewBatch()
+	assert.NoError(t, batch.Put([]byte("b"), []byte("2")))
+	assert.NoError(t, batch.Write())
+
+	stored, _ := raw.Get([]byte("b"))
+	assert.Equal(t, []byte{0xcc, '2'}, stored)
+	value, err := db.Get([]byte("b"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("2"), value)
+
+	it := db.NewIterator(nil, nil)
+	var values [][]byte
+	for it.Next() {
+		values = append(values, it.Value())
+	}
+	assert.NoError(t, it.Error())
+	it.Release()
+	assert.Equal(t, [][]byte{[]byte("1"), []byte("2")}, values)
+
+	// The replayed values are decompressed.
+	replayed := NewMemDB()
+	assert.NoError(t, batch.Replay(replayed))
+	value, _ = replayed.Get([]byte("b"))
+	assert.Equal(t, []byte("2"), value)
+
+	// The values not compressed fail the iteration.
+	assert.NoError(t, raw.Put([]byte("c"), []byte("3")))
+	it = db.NewIterator([]byte("c"), nil)
+	assert.False(t, it.Next())
+	assert.Error(t, it.Error())
+	it.Release()
+}
+
+func TestDatabaseManager_CheckValueCodec(t *testing.T) {
+	dbm := NewMemoryDBManager().(*databaseManager)
+
+	// The codec is recorded for the empty database.
+	dbm.config.Compression = map[DBEntryType]CompressionConfig{BodyDB: {Codec: ZstdCodec, Level: 1}}
+	assert.NoError(t, dbm.checkValueCodec("body", BodyDB, NewMemDB()))
+	dbm.config.Compression = nil
+	assert.True(t, errors.Is(dbm.checkValueCodec("body", BodyDB, NewMemDB()), errValueCodecChanged))
+
+	// The database with data is regarded as not compressed.
+	db := NewMemDB()
+	assert.NoError(t, db.Put([]byte("key"), []byte("value")))
+	assert.NoError(t, dbm.checkValueCodec("receipts", ReceiptsDB, db))
+	dbm.config.Compression = map[DBEntryType]CompressionConfig{ReceiptsDB: {Codec: ZstdCodec, Level: 1}}
+	assert.True(t, errors.Is(dbm.checkValueCodec("receipts", ReceiptsDB, db), errValueCodecChanged))
+
+	// The codec of a removed database is forgotten.
+	dbm.deleteValueCodec("receipts")
+	assert.NoError(t, dbm.checkValueCodec("receipts", ReceiptsDB, NewMemDB()))
+	recorded, _ := dbm.getDatabase(MiscDB).Get(databaseCodecKey("receipts"))
+	assert.True(t, bytes.Equal([]byte(ZstdCodec), recorded))
+}
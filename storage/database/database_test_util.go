@@ -51,7 +51,7 @@ func NewLevelDBManagerForTest(dbc *DBConfig, levelDBOption *opt.Options) (DBMana
 			return nil, fmt.Errorf("failed to create new LevelDB with options. err: %v", err)
 		}
 
-		dbm.dbs[i] = ldb
+		dbm.setDatabase(DBEntryType(i), ldb)
 	}
 
 	return dbm, nil
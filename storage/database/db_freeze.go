@@ -0,0 +1,82 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import "sync"
+
+// FreezeWrites blocks the writes through the DBManager until ThawWrites is
+// called, after the writes in progress are done. The writes of all writers,
+// e.g. the snapshot generator, the pruning and the governance, are blocked,
+// while the reads are served as usual.
+func (dbm *databaseManager) FreezeWrites() {
+	dbm.writes.Lock()
+}
+
+// ThawWrites resumes the writes blocked by FreezeWrites.
+func (dbm *databaseManager) ThawWrites() {
+	dbm.writes.Unlock()
+}
+
+// setDatabase sets the database of the entry type, and wraps it once into the
+// freezable database returned by the getters of the DBManager.
+func (dbm *databaseManager) setDatabase(entryType DBEntryType, db Database) {
+	dbm.dbs[entryType] = db
+	dbm.freezableDBs[entryType] = dbm.freezable(db)
+}
+
+// freezable returns the database whose writes are blocked while the writes of
+// the DBManager are frozen.
+func (dbm *databaseManager) freezable(db Database) Database {
+	if db == nil {
+		return nil
+	}
+	return &freezableDB{Database: db, writes: &dbm.writes}
+}
+
+// freezableDB is a database whose writes hold the read lock of the writes of
+// the DBManager, which is held for writing while the writes are frozen.
+type freezableDB struct {
+	Database
+	writes *sync.RWMutex
+}
+
+func (db *freezableDB) Put(key []byte, value []byte) error {
+	db.writes.RLock()
+	defer db.writes.RUnlock()
+	return db.Database.Put(key, value)
+}
+
+func (db *freezableDB) Delete(key []byte) error {
+	db.writes.RLock()
+	defer db.writes.RUnlock()
+	return db.Database.Delete(key)
+}
+
+func (db *freezableDB) NewBatch() Batch {
+	return &freezableBatch{Batch: db.Database.NewBatch(), writes: db.writes}
+}
+
+type freezableBatch struct {
+	Batch
+	writes *sync.RWMutex
+}
+
+func (b *freezableBatch) Write() error {
+	b.writes.RLock()
+	defer b.writes.RUnlock()
+	return b.Batch.Write()
+}
@@ -0,0 +1,209 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/klaytn/klaytn/common"
+)
+
+// ErrInspectInterrupted is returned when the inspection is interrupted by the quit channel.
+var ErrInspectInterrupted = errors.New("database inspection is interrupted")
+
+const unknownKeyKind = "unknown"
+
+// KeyStats is the number and the size of the keys of a kind in the database
+// schema, such as header or tx-lookup.
+type KeyStats struct {
+	Kind      string `json:"kind"`
+	Count     uint64 `json:"count"`
+	KeySize   uint64 `json:"keySize"`   // Total size of the keys in bytes
+	ValueSize uint64 `json:"valueSize"` // Total size of the values in bytes
+}
+
+// DBKeyStats is the number and the size of the keys of a logical database,
+// grouped by their kinds.
+type DBKeyStats struct {
+	Name  string     `json:"name"`
+	Total KeyStats   `json:"total"`
+	Kinds []KeyStats `json:"kinds"`
+}
+
+// HeadPointer is a head block pointer stored in the database.
+type HeadPointer struct {
+	Hash   common.Hash `json:"hash"`
+	Number *uint64     `json:"number"` // nil if the header of the hash is missing
+}
+
+// ColdStorageBoundary is the number of the next block to be moved to the cold
+// storage. The data of the blocks before it are stored in the cold storage.
+type ColdStorageBoundary struct {
+	Name      string `json:"name"`
+	NextBlock uint64 `json:"nextBlock"`
+	Enabled   bool   `json:"enabled"` // false if the cold storage is not configured for the database
+}
+
+// DBStatus is the metadata of the databases, which is used to diagnose a node
+// from its data directory.
+type DBStatus struct {
+	Version             *uint64               `json:"version"` // nil if the database is not initialized
+	HeadHeader          HeadPointer           `json:"headHeader"`
+	HeadBlock           HeadPointer           `json:"headBlock"`
+	HeadFastBlock       HeadPointer           `json:"headFastBlock"`
+	SnapshotRoot        common.Hash           `json:"snapshotRoot"`
+	StateTrieMigration  *uint64               `json:"stateTrieMigration,omitempty"` // Block number of the state migration in progress
+	ColdStorage         []ColdStorageBoundary `json:"coldStorage"`
+	StateTrieShards     uint                  `json:"stateTrieShards"` // 0 if it is not recorded
+	SingleDB            bool                  `json:"singleDB"`
+	DBType              DBType                `json:"dbType"`
+	DatabaseDirectories map[string]string     `json:"databaseDirectories"`
+}
+
+// KeyStats iterates all the keys of each logical database, and returns their
+// number and size grouped by their kinds in the database schema. It reads the
+// whole databases, so it should be used while a node is not executing. If a
+// single database is used, the stats of the single database is returned.
+func (dbm *databaseManager) KeyStats(quit <-chan struct{}) ([]DBKeyStats, error) {
+	if dbm.config.SingleDB || dbm.config.DBType == MemoryDB {
+		stats, err := collectKeyStats(singleDBStatsName, dbm.dbs[0], quit)
+		if err != nil {
+			return nil, err
+		}
+		return []DBKeyStats{stats}, nil
+	}
+
+	result := make([]DBKeyStats, 0, databaseEntryTypeSize)
+	for et := MiscDB; et < databaseEntryTypeSize; et++ {
+		db := dbm.getDatabase(et)
+		if db == nil {
+			continue
+		}
+		stats, err := collectKeyStats(dbBaseDirs[et], db, quit)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, stats)
+	}
+	return result, nil
+}
+
+func collectKeyStats(name string, db Database, quit <-chan struct{}) (DBKeyStats, error) {
+	stats := DBKeyStats{Name: name, Total: KeyStats{Kind: "total"}}
+	kinds := make(map[string]*KeyStats)
+
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+
+	for it.Next() {
+		if stats.Total.Count%10000 == 0 {
+			select {
+			case <-quit:
+				return stats, ErrInspectInterrupted
+			default:
+			}
+		}
+		kind := unknownKeyKind
+		if e, _ := describeKey(it.Key()); e != nil {
+			kind = e.Kind
+		}
+		ks, ok := kinds[kind]
+		if !ok {
+			ks = &KeyStats{Kind: kind}
+			kinds[kind] = ks
+		}
+		keySize, valueSize := uint64(len(it.Key())), uint64(len(it.Value()))
+		ks.Count, ks.KeySize, ks.ValueSize = ks.Count+1, ks.KeySize+keySize, ks.ValueSize+valueSize
+		stats.Total.Count, stats.Total.KeySize, stats.Total.ValueSize = stats.Total.Count+1, stats.Total.KeySize+keySize, stats.Total.ValueSize+valueSize
+	}
+	if err := it.Error(); err != nil {
+		return stats, err
+	}
+
+	stats.Kinds = make([]KeyStats, 0, len(kinds))
+	for _, ks := range kinds {
+		stats.Kinds = append(stats.Kinds, *ks)
+	}
+	sort.Slice(stats.Kinds, func(i, j int) bool { return stats.Kinds[i].Kind < stats.Kinds[j].Kind })
+	return stats, nil
+}
+
+// Status returns the metadata of the databases, such as the head block pointers,
+// the database version and the boundaries of the cold storage.
+func (dbm *databaseManager) Status() *DBStatus {
+	status := &DBStatus{
+		Version:             dbm.ReadDatabaseVersion(),
+		HeadHeader:          dbm.headPointer(dbm.ReadHeadHeaderHash()),
+		HeadBlock:           dbm.headPointer(dbm.ReadHeadBlockHash()),
+		HeadFastBlock:       dbm.headPointer(dbm.ReadHeadFastBlockHash()),
+		SnapshotRoot:        dbm.ReadSnapshotRoot(),
+		ColdStorage:         []ColdStorageBoundary{},
+		SingleDB:            dbm.config.SingleDB,
+		DBType:              dbm.config.DBType,
+		DatabaseDirectories: make(map[string]string),
+	}
+	if dbm.InMigration() {
+		number := dbm.MigrationBlockNumber()
+		status.StateTrieMigration = &number
+	}
+	if dbm.config.SingleDB || dbm.config.DBType == MemoryDB {
+		return status
+	}
+
+	status.StateTrieShards = dbm.getNumShards(dbm.getDBDir(StateTrieDB))
+	for et := MiscDB; et < databaseEntryTypeSize; et++ {
+		if dbm.getDatabase(et) != nil {
+			status.DatabaseDirectories[dbBaseDirs[et]] = dbm.getDBDir(et)
+		}
+	}
+	for _, et := range []DBEntryType{BodyDB, ReceiptsDB} {
+		db := dbm.getDatabase(et)
+		if db == nil {
+			continue
+		}
+		if data, err := db.Get(coldStorageProgressKey); err == nil && len(data) == 8 {
+			enabled := isColdStorageDB(dbm.dbs[et])
+			status.ColdStorage = append(status.ColdStorage, ColdStorageBoundary{Name: dbBaseDirs[et], NextBlock: binary.BigEndian.Uint64(data), Enabled: enabled})
+		}
+	}
+	return status
+}
+
+// isColdStorageDB returns true if the database is the cold storage tier, or is
+// wrapped over it.
+func isColdStorageDB(db Database) bool {
+	for {
+		switch d := db.(type) {
+		case *coldStorageDB:
+			return true
+		case *ancientSourceDB:
+			db = d.Database
+		default:
+			return false
+		}
+	}
+}
+
+func (dbm *databaseManager) headPointer(hash common.Hash) HeadPointer {
+	p := HeadPointer{Hash: hash}
+	if hash != (common.Hash{}) {
+		p.Number = dbm.ReadHeaderNumber(hash)
+	}
+	return p
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBManager_KeyStats(t *testing.T) {
+	dbm := NewMemoryDBManager()
+	defer dbm.Close()
+
+	for i := int64(0); i < 10; i++ {
+		header := &types.Header{Number: big.NewInt(i)}
+		dbm.WriteHeader(header)
+		dbm.WriteCanonicalHash(header.Hash(), uint64(i))
+	}
+	assert.NoError(t, dbm.GetMiscDB().Put([]byte("unknown-key"), []byte("value")))
+
+	stats, err := dbm.KeyStats(nil)
+	assert.NoError(t, err)
+	assert.Len(t, stats, 1)
+
+	kinds := make(map[string]KeyStats)
+	for _, ks := range stats[0].Kinds {
+		kinds[ks.Kind] = ks
+	}
+	assert.Equal(t, uint64(10), kinds["header"].Count)
+	assert.Equal(t, uint64(10), kinds["header-number"].Count)
+	assert.Equal(t, uint64(10), kinds["canonical-hash"].Count)
+	assert.Equal(t, uint64(10*(len(headerPrefix)+8+len(headerHashSuffix))), kinds["canonical-hash"].KeySize)
+	assert.Equal(t, uint64(10*common.HashLength), kinds["canonical-hash"].ValueSize)
+	assert.Equal(t, uint64(1), kinds[unknownKeyKind].Count)
+
+	var total uint64
+	for _, ks := range stats[0].Kinds {
+		total += ks.Count
+	}
+	assert.Equal(t, total, stats[0].Total.Count)
+
+	// The inspection stops when the quit channel is closed.
+	quit := make(chan struct{})
+	close(quit)
+	_, err = dbm.KeyStats(quit)
+	assert.Equal(t, ErrInspectInterrupted, err)
+}
+
+func TestDBManager_Status(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klaytn-test-db-status")
+	if err != nil {
+		t.Fatalf("cannot create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	dbm := NewDBManager(&DBConfig{Dir: dir, DBType: LevelDB, LevelDBCacheSize: 32, OpenFilesLimit: 32, NumStateTrieShards: 4})
+	defer dbm.Close()
+
+	status := dbm.Status()
+	assert.Nil(t, status.Version)
+	assert.Equal(t, common.Hash{}, status.HeadBlock.Hash)
+	assert.Nil(t, status.HeadBlock.Number)
+	assert.Empty(t, status.ColdStorage)
+	assert.Equal(t, dbBaseDirs[headerDB], status.DatabaseDirectories[dbBaseDirs[headerDB]])
+
+	header := &types.Header{Number: big.NewInt(5)}
+	dbm.WriteHeader(header)
+	dbm.WriteHeadBlockHash(header.Hash())
+	dbm.WriteDatabaseVersion(3)
+	assert.NoError(t, dbm.GetDatabase(BodyDB).Put(coldStorageProgressKey, encodeBlockNumber(100)))
+
+	status = dbm.Status()
+	if assert.NotNil(t, status.Version) {
+		assert.Equal(t, uint64(3), *status.Version)
+	}
+	assert.Equal(t, header.Hash(), status.HeadBlock.Hash)
+	if assert.NotNil(t, status.HeadBlock.Number) {
+		assert.Equal(t, uint64(5), *status.HeadBlock.Number)
+	}
+	assert.Equal(t, []ColdStorageBoundary{{Name: dbBaseDirs[BodyDB], NextBlock: 100}}, status.ColdStorage)
+	assert.Equal(t, uint(4), status.StateTrieShards)
+}
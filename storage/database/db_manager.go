@@ -61,10 +61,21 @@ type DBManager interface {
 	getDatabase(DBEntryType) Database
 	CreateMigrationDBAndSetStatus(blockNum uint64) error
 	FinishStateMigration(succeed bool) chan struct{}
+	SetStateTrieMigrationShards(numShards uint) error
+	ReshardStateTrieDB(numShards uint, quit <-chan struct{}) error
 	GetStateTrieDB() Database
 	GetStateTrieMigrationDB() Database
 	GetMiscDB() Database
+	GetDatabase(dbEntryType DBEntryType) Database
 	GetSnapshotDB() Database
+	Stats() []DBStats
+	KeyStats(quit <-chan struct{}) ([]DBKeyStats, error)
+	Status() *DBStatus
+	CreateSnapshot(dir string) (*DBSnapshot, error)
+	Sync() error
+	RewindColdStorage(next uint64) error
+	FreezeWrites()
+	ThawWrites()
 
 	// from accessors_chain.go
 	ReadCanonicalHash(number uint64) common.Hash
@@ -84,6 +95,10 @@ type DBManager interface {
 	ReadFastTrieProgress() uint64
 	WriteFastTrieProgress(count uint64)
 
+	ReadFastSyncStatus() []byte
+	WriteFastSyncStatus(status []byte)
+	DeleteFastSyncStatus()
+
 	HasHeader(hash common.Hash, number uint64) bool
 	ReadHeader(hash common.Hash, number uint64) *types.Header
 	ReadHeaderRLP(hash common.Hash, number uint64) rlp.RawValue
@@ -164,6 +179,10 @@ type DBManager interface {
 
 	WritePreimages(number uint64, preimages map[common.Hash][]byte)
 
+	// Trie node reference counting related operations
+	ReadTrieNodeRefRange() (tail, head uint64, ok bool)
+	WriteTrieNodeRefRange(tail, head uint64)
+
 	// from accessors_indexes.go
 	ReadTxLookupEntry(hash common.Hash) (common.Hash, uint64, uint64)
 	WriteTxLookupEntries(block *types.Block)
@@ -278,8 +297,13 @@ type DBManager interface {
 	ReadStakingInfo(blockNum uint64) ([]byte, error)
 	WriteStakingInfo(blockNum uint64, stakingInfo []byte) error
 
+	// CouncilHistory related functions
+	ReadCouncilHistory(blockNum uint64, hash common.Hash) ([]byte, error)
+	WriteCouncilHistory(blockNum uint64, hash common.Hash, history []byte) error
+	ReadCouncilHistoryRange(start, end uint64) ([][]byte, error)
+
 	// DB migration related function
-	StartDBMigration(DBManager) error
+	StartDBMigration(DBManager, *DBMigrationConfig) error
 
 	// ChainDataFetcher checkpoint function
 	WriteChainDataFetcherCheckpoint(checkpoint uint64) error
@@ -323,6 +347,17 @@ var dbBaseDirs = [databaseEntryTypeSize]string{
 	"snapshot",
 }
 
+// DBEntryTypeByName returns the entry type of the database of the given name,
+// which is the base directory of the database such as header and statetrie.
+func DBEntryTypeByName(name string) (DBEntryType, bool) {
+	for et := MiscDB; et < databaseEntryTypeSize; et++ {
+		if dbBaseDirs[et] == name {
+			return et, true
+		}
+	}
+	return 0, false
+}
+
 // Sum of dbConfigRatio should be 100.
 // Otherwise, logger.Crit will be called at checkDBEntryConfigRatio.
 var dbConfigRatio = [databaseEntryTypeSize]int{
@@ -371,26 +406,33 @@ func getDBEntryConfig(originalDBC *DBConfig, i DBEntryType, dbDir string) *DBCon
 }
 
 type databaseManager struct {
-	config *DBConfig
-	dbs    []Database
-	cm     *cacheManager
+	config       *DBConfig
+	dbs          []Database
+	freezableDBs []Database // dbs wrapped by freezable, set with dbs by setDatabase
+	cm           *cacheManager
 
 	// TODO-Klaytn need to refine below.
 	// -merge status variable
 	lockInMigration      sync.RWMutex
 	inMigration          bool
 	migrationBlockNumber uint64
+	migrationShards      uint // number of shards of the next migration db, 0 for the configured one
+
+	statsQuit chan struct{} // quit channel of the storage stats sampler
+
+	writes sync.RWMutex // held for writing while the writes are frozen
 }
 
 func NewMemoryDBManager() DBManager {
 	dbc := &DBConfig{DBType: MemoryDB}
 
 	dbm := databaseManager{
-		config: dbc,
-		dbs:    make([]Database, 1, 1),
-		cm:     newCacheManager(),
+		config:       dbc,
+		dbs:          make([]Database, 1, 1),
+		freezableDBs: make([]Database, 1, 1),
+		cm:           newCacheManager(),
 	}
-	dbm.dbs[0] = NewMemDB()
+	dbm.setDatabase(0, NewMemDB())
 
 	return &dbm
 }
@@ -411,8 +453,20 @@ type DBConfig struct {
 	LevelDBCompression LevelDBCompressionType
 	LevelDBBufferPool  bool
 
+	// Compression of each database, which overrides LevelDBCompression
+	Compression map[DBEntryType]CompressionConfig
+
+	// Key encrypting the values of all databases with AES-256-GCM, if not empty
+	EncryptionKey []byte `json:"-"`
+
 	// DynamoDB related configurations
 	DynamoDBConfig *DynamoDBConfig
+
+	// Cold storage tier of the ancient block bodies and receipts
+	ColdStorageConfig *ColdStorageConfig
+
+	// Remote read-only source of the ancient blocks and receipts
+	AncientSourceConfig *AncientSourceConfig
 }
 
 const dbMetricPrefix = "klay/db/chaindata/"
@@ -428,8 +482,13 @@ func singleDatabaseDBManager(dbc *DBConfig) (DBManager, error) {
 
 	db.Meter(dbMetricPrefix)
 	for i := 0; i < int(databaseEntryTypeSize); i++ {
-		dbm.dbs[i] = db
+		dbm.setDatabase(DBEntryType(i), db)
 	}
+	if err := dbm.checkEncryption(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	dbm.startStatsSampler()
 	return dbm, nil
 }
 
@@ -454,7 +513,11 @@ func databaseDBManager(dbc *DBConfig) (*databaseManager, error) {
 
 	// Create Misc DB first to get the DB directory of stateTrieDB.
 	miscDB := newMiscDB(dbc)
-	dbm.dbs[MiscDB] = miscDB
+	dbm.setDatabase(MiscDB, miscDB)
+	if err := dbm.checkEncryption(); err != nil {
+		miscDB.Close()
+		return nil, err
+	}
 
 	// Create other DBs
 	for et := int(MiscDB) + 1; et < int(databaseEntryTypeSize); et++ {
@@ -469,22 +532,20 @@ func databaseDBManager(dbc *DBConfig) (*databaseManager, error) {
 			}
 			fallthrough
 		case StateTrieDB:
-			newDBC := getDBEntryConfig(dbc, entryType, dir)
-			if dbc.NumStateTrieShards > 1 && !dbc.DBType.selfShardable() { // make non-sharding db if the db is sharding itself
-				db, err = newShardedDB(newDBC, entryType, dbc.NumStateTrieShards)
-			} else {
-				db, err = newDatabase(newDBC, entryType)
-			}
+			db, err = newStateTrieDB(dbc, entryType, dir, dbm.stateTrieShards(dir))
 		default:
 			newDBC := getDBEntryConfig(dbc, entryType, dir)
 			db, err = newDatabase(newDBC, entryType)
 		}
 
+		if err == nil {
+			err = dbm.checkValueCodec(dir, entryType, db)
+		}
 		if err != nil {
 			logger.Crit("Failed while generating databases", "DBType", dbBaseDirs[et], "err", err)
 		}
 
-		dbm.dbs[et] = db
+		dbm.setDatabase(entryType, db)
 		db.Meter(dbMetricPrefix + dbBaseDirs[et] + "/") // Each database collects metrics independently.
 	}
 	return dbm, nil
@@ -492,6 +553,17 @@ func databaseDBManager(dbc *DBConfig) (*databaseManager, error) {
 
 // newDatabase returns Database interface with given DBConfig.
 func newDatabase(dbc *DBConfig, entryType DBEntryType) (Database, error) {
+	db, err := newBackendDatabase(dbc, entryType)
+	if err != nil {
+		return nil, err
+	}
+	if db, err = withEncryption(db, dbc); err != nil {
+		return nil, err
+	}
+	return withValueCompression(db, dbc, entryType)
+}
+
+func newBackendDatabase(dbc *DBConfig, entryType DBEntryType) (Database, error) {
 	switch dbc.DBType {
 	case LevelDB:
 		return NewLevelDB(dbc, entryType)
@@ -510,9 +582,10 @@ func newDatabase(dbc *DBConfig, entryType DBEntryType) (Database, error) {
 // newDatabaseManager returns the pointer of databaseManager with default configuration.
 func newDatabaseManager(dbc *DBConfig) *databaseManager {
 	return &databaseManager{
-		config: dbc,
-		dbs:    make([]Database, databaseEntryTypeSize),
-		cm:     newCacheManager(),
+		config:       dbc,
+		dbs:          make([]Database, databaseEntryTypeSize),
+		freezableDBs: make([]Database, databaseEntryTypeSize),
+		cm:           newCacheManager(),
 	}
 }
 
@@ -522,6 +595,15 @@ func newDatabaseManager(dbc *DBConfig) *databaseManager {
 func NewDBManager(dbc *DBConfig) DBManager {
 	if dbc.SingleDB {
 		logger.Info("Single database is used for persistent storage", "DBType", dbc.DBType)
+		if dbc.ColdStorageConfig != nil && dbc.ColdStorageConfig.Enabled {
+			logger.Warn("Cold storage is not supported with a single database, and is ignored")
+		}
+		if dbc.AncientSourceConfig != nil && dbc.AncientSourceConfig.URL != "" {
+			logger.Warn("Ancient data source is not supported with a single database, and is ignored")
+		}
+		if len(dbc.Compression) > 0 {
+			logger.Warn("The compression of each database is not supported with a single database, and is ignored")
+		}
 		if dbm, err := singleDatabaseDBManager(dbc); err != nil {
 			logger.Crit("Failed to create a single database", "DBType", dbc.DBType, "err", err)
 		} else {
@@ -534,6 +616,20 @@ func NewDBManager(dbc *DBConfig) DBManager {
 		if err != nil {
 			logger.Crit("Failed to create databases", "DBType", dbc.DBType, "err", err)
 		}
+		if csc := dbc.ColdStorageConfig; csc != nil && csc.Enabled {
+			// The migration iterates the local database, which is not supported by DynamoDB.
+			if dbc.DBType != LevelDB {
+				logger.Crit("Cold storage requires LevelDB as the local database", "DBType", dbc.DBType)
+			}
+			if err := dbm.enableColdStorage(csc); err != nil {
+				logger.Crit("Failed to enable the cold storage", "bucket", csc.Bucket, "err", err)
+			}
+		}
+		if asc := dbc.AncientSourceConfig; asc != nil && asc.URL != "" {
+			if err := dbm.enableAncientSource(asc); err != nil {
+				logger.Crit("Failed to enable the ancient data source", "url", asc.URL, "err", err)
+			}
+		}
 		if migrationBlockNum := dbm.getStateTrieMigrationInfo(); migrationBlockNum > 0 {
 			mdb := dbm.getDatabase(StateTrieMigrationDB)
 			if mdb == nil {
@@ -543,6 +639,7 @@ func NewDBManager(dbc *DBConfig) DBManager {
 				dbm.migrationBlockNumber = migrationBlockNum
 			}
 		}
+		dbm.startStatsSampler()
 		return dbm
 	}
 	logger.Crit("Must not reach here!")
@@ -691,22 +788,15 @@ func (dbm *databaseManager) setStateTrieMigrationStatus(blockNum uint64) {
 	dbm.inMigration, dbm.migrationBlockNumber = true, blockNum
 }
 
-func newStateTrieMigrationDB(dbc *DBConfig, blockNum uint64) (Database, string) {
+func newStateTrieMigrationDB(dbc *DBConfig, blockNum uint64, numShards uint) (Database, string) {
 	dbDir := dbBaseDirs[StateTrieMigrationDB] + "_" + strconv.FormatUint(blockNum, 10)
-	newDBConfig := getDBEntryConfig(dbc, StateTrieMigrationDB, dbDir)
-	var newDB Database
-	var err error
-	if newDBConfig.NumStateTrieShards > 1 {
-		newDB, err = newShardedDB(newDBConfig, StateTrieMigrationDB, newDBConfig.NumStateTrieShards)
-	} else {
-		newDB, err = newDatabase(newDBConfig, StateTrieMigrationDB)
-	}
+	newDB, err := newStateTrieDB(dbc, StateTrieMigrationDB, dbDir, numShards)
 	if err != nil {
 		logger.Crit("Failed to create a new database for state trie migration", "err", err)
 	}
 
 	newDB.Meter(dbMetricPrefix + dbBaseDirs[StateTrieMigrationDB] + "/") // Each database collects metrics independently.
-	logger.Info("Created a new database for state trie migration", "newStateTrieDB", newDBConfig.Dir)
+	logger.Info("Created a new database for state trie migration", "newStateTrieDB", dbDir, "numShards", numShards)
 
 	return newDB, dbDir
 }
@@ -725,7 +815,19 @@ func (dbm *databaseManager) CreateMigrationDBAndSetStatus(blockNum uint64) error
 	logger.Info("Start setting a new database for state trie migration", "blockNum", blockNum)
 
 	// Create a new database for migration process.
-	newDB, newDBDir := newStateTrieMigrationDB(dbm.config, blockNum)
+	// The migration db keeps the number of shards of the current db unless resharding is requested.
+	numShards := dbm.stateTrieShards(dbm.getDBDir(StateTrieDB))
+	if dbm.migrationShards > 0 {
+		numShards = dbm.migrationShards
+	}
+	newDB, newDBDir := newStateTrieMigrationDB(dbm.config, blockNum, numShards)
+	if err := dbm.checkValueCodec(newDBDir, StateTrieMigrationDB, newDB); err != nil {
+		newDB.Close()
+		return err
+	}
+	if !dbm.config.DBType.selfShardable() {
+		dbm.setNumShards(newDBDir, numShards)
+	}
 
 	// lock to prevent from a conflict of reading state DB and changing state DB
 	dbm.lockInMigration.Lock()
@@ -735,7 +837,7 @@ func (dbm *databaseManager) CreateMigrationDBAndSetStatus(blockNum uint64) error
 	dbm.setDBDir(StateTrieMigrationDB, newDBDir)
 
 	// Set migration db
-	dbm.dbs[StateTrieMigrationDB] = newDB
+	dbm.setDatabase(StateTrieMigrationDB, newDB)
 
 	// Store the migration status
 	dbm.setStateTrieMigrationStatus(blockNum)
@@ -765,12 +867,15 @@ func (dbm *databaseManager) FinishStateMigration(succeed bool) chan struct{} {
 
 	// Replace StateTrieDB with new one
 	dbm.setDBDir(StateTrieDB, dbDirToBeUsed)
-	dbm.dbs[StateTrieDB] = dbToBeUsed
+	dbm.setDatabase(StateTrieDB, dbToBeUsed)
 
 	dbm.setStateTrieMigrationStatus(0)
 
-	dbm.dbs[StateTrieMigrationDB] = nil
+	dbm.setDatabase(StateTrieMigrationDB, nil)
 	dbm.setDBDir(StateTrieMigrationDB, "")
+	dbm.deleteNumShards(dbDirToBeRemoved)
+	dbm.deleteValueCodec(dbDirToBeRemoved)
+	dbm.migrationShards = 0
 
 	dbPathToBeRemoved := filepath.Join(dbm.config.Dir, dbDirToBeRemoved)
 	dbToBeRemoved.Close()
@@ -794,15 +899,21 @@ func removeDB(dbPath string, endCheck chan struct{}) {
 }
 
 func (dbm *databaseManager) GetStateTrieDB() Database {
-	return dbm.dbs[StateTrieDB]
+	return dbm.getDatabase(StateTrieDB)
 }
 
 func (dbm *databaseManager) GetStateTrieMigrationDB() Database {
-	return dbm.dbs[StateTrieMigrationDB]
+	return dbm.getDatabase(StateTrieMigrationDB)
 }
 
 func (dbm *databaseManager) GetMiscDB() Database {
-	return dbm.dbs[MiscDB]
+	return dbm.getDatabase(MiscDB)
+}
+
+// GetDatabase returns the database of the given entry type, which is shared by
+// all entry types if a single database is used.
+func (dbm *databaseManager) GetDatabase(dbEntryType DBEntryType) Database {
+	return dbm.getDatabase(dbEntryType)
 }
 
 func (dbm *databaseManager) GetSnapshotDB() Database {
@@ -829,13 +940,18 @@ func (dbm *databaseManager) GetDBConfig() *DBConfig {
 
 func (dbm *databaseManager) getDatabase(dbEntryType DBEntryType) Database {
 	if dbm.config.DBType == MemoryDB {
-		return dbm.dbs[0]
+		return dbm.freezableDBs[0]
 	} else {
-		return dbm.dbs[dbEntryType]
+		return dbm.freezableDBs[dbEntryType]
 	}
 }
 
 func (dbm *databaseManager) Close() {
+	if dbm.statsQuit != nil {
+		close(dbm.statsQuit)
+		dbm.statsQuit = nil
+	}
+
 	// If single DB, only close the first database.
 	if dbm.config.SingleDB {
 		dbm.dbs[0].Close()
@@ -982,6 +1098,32 @@ func (dbm *databaseManager) WriteFastTrieProgress(count uint64) {
 	}
 }
 
+// ReadFastSyncStatus retrieves the serialized downloader status of the fast sync
+// which has not been finished yet.
+func (dbm *databaseManager) ReadFastSyncStatus() []byte {
+	db := dbm.getDatabase(MiscDB)
+	data, _ := db.Get(fastSyncStatusKey)
+	return data
+}
+
+// WriteFastSyncStatus stores the serialized downloader status to resume the fast
+// sync across restarts.
+func (dbm *databaseManager) WriteFastSyncStatus(status []byte) {
+	db := dbm.getDatabase(MiscDB)
+	if err := db.Put(fastSyncStatusKey, status); err != nil {
+		logger.Crit("Failed to store fast sync status", "err", err)
+	}
+}
+
+// DeleteFastSyncStatus deletes the serialized downloader status once the fast
+// sync has committed its pivot block.
+func (dbm *databaseManager) DeleteFastSyncStatus() {
+	db := dbm.getDatabase(MiscDB)
+	if err := db.Delete(fastSyncStatusKey); err != nil {
+		logger.Crit("Failed to remove fast sync status", "err", err)
+	}
+}
+
 // (Block)Header operations.
 // HasHeader verifies the existence of a block header corresponding to the hash.
 func (dbm *databaseManager) HasHeader(hash common.Hash, number uint64) bool {
@@ -1734,6 +1876,27 @@ func (dbm *databaseManager) HasStateTrieNode(key []byte) (bool, error) {
 	return true, nil
 }
 
+// ReadTrieNodeRefRange retrieves the range of blocks whose state roots are
+// reference counted. The roots of the blocks from tail to head are referenced.
+func (dbm *databaseManager) ReadTrieNodeRefRange() (tail, head uint64, ok bool) {
+	db := dbm.getDatabase(MiscDB)
+	data, _ := db.Get(trieNodeRefRangeKey)
+	if len(data) != 16 {
+		return 0, 0, false
+	}
+	return binary.BigEndian.Uint64(data[:8]), binary.BigEndian.Uint64(data[8:]), true
+}
+
+// WriteTrieNodeRefRange stores the range of blocks whose state roots are
+// reference counted.
+func (dbm *databaseManager) WriteTrieNodeRefRange(tail, head uint64) {
+	db := dbm.getDatabase(MiscDB)
+	data := append(common.Int64ToByteBigEndian(tail), common.Int64ToByteBigEndian(head)...)
+	if err := db.Put(trieNodeRefRangeKey, data); err != nil {
+		logger.Crit("Failed to store the trie node reference range", "err", err)
+	}
+}
+
 // ReadPreimage retrieves a single preimage of the provided hash.
 func (dbm *databaseManager) ReadPreimage(hash common.Hash) []byte {
 	dbm.lockInMigration.RLock()
@@ -0,0 +1,60 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"encoding/binary"
+
+	"github.com/klaytn/klaytn/common"
+)
+
+// ReadCouncilHistory reads the council membership changes of the given block
+// from database. CouncilHistory is stored in MiscDB.
+func (dbm *databaseManager) ReadCouncilHistory(blockNum uint64, hash common.Hash) ([]byte, error) {
+	db := dbm.getDatabase(MiscDB)
+	return db.Get(councilHistoryKey(blockNum, hash))
+}
+
+// WriteCouncilHistory writes the council membership changes of the given block
+// to database. CouncilHistory is stored in MiscDB.
+func (dbm *databaseManager) WriteCouncilHistory(blockNum uint64, hash common.Hash, history []byte) error {
+	db := dbm.getDatabase(MiscDB)
+	return db.Put(councilHistoryKey(blockNum, hash), history)
+}
+
+// ReadCouncilHistoryRange reads all council membership changes stored for the
+// blocks between start and end (both inclusive) in ascending order of the block number.
+// The changes of the blocks which are not canonical any more are included as well.
+func (dbm *databaseManager) ReadCouncilHistoryRange(start, end uint64) ([][]byte, error) {
+	db := dbm.getDatabase(MiscDB)
+
+	it := db.NewIterator(councilHistoryPrefix, encodeBlockNumber(start))
+	defer it.Release()
+
+	var histories [][]byte
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(councilHistoryPrefix)+8+common.HashLength {
+			continue
+		}
+		if binary.BigEndian.Uint64(key[len(councilHistoryPrefix):]) > end {
+			break
+		}
+		histories = append(histories, common.CopyBytes(it.Value()))
+	}
+	return histories, it.Error()
+}
@@ -203,6 +203,23 @@ func TestDBManager_FastTrieProgress(t *testing.T) {
 	}
 }
 
+// TestDBManager_FastSyncStatus tests read, write and delete operations of fast sync status.
+func TestDBManager_FastSyncStatus(t *testing.T) {
+	log.EnableLogForTest(log.LvlCrit, log.LvlTrace)
+	for _, dbm := range dbManagers {
+		assert.Empty(t, dbm.ReadFastSyncStatus())
+
+		dbm.WriteFastSyncStatus([]byte("status1"))
+		assert.Equal(t, []byte("status1"), dbm.ReadFastSyncStatus())
+
+		dbm.WriteFastSyncStatus([]byte("status2"))
+		assert.Equal(t, []byte("status2"), dbm.ReadFastSyncStatus())
+
+		dbm.DeleteFastSyncStatus()
+		assert.Empty(t, dbm.ReadFastSyncStatus())
+	}
+}
+
 // TestDBManager_Header tests read, write and delete operations of blockchain headers.
 func TestDBManager_Header(t *testing.T) {
 	log.EnableLogForTest(log.LvlCrit, log.LvlTrace)
@@ -17,86 +17,359 @@
 package database
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"os/signal"
 	"path"
+	"strconv"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
 	"github.com/pkg/errors"
 )
 
 const (
 	reportCycle = IdealBatchSize * 20
+
+	// maxReportedMismatches is the number of mismatched keys logged by the verification.
+	maxReportedMismatches = 10
 )
 
-// copyDB migrates a DB to another DB.
+// ErrDBMigrationInterrupted is returned if the DB migration is stopped before it
+// finishes. The migration continues from the progress if it is run again.
+var ErrDBMigrationInterrupted = errors.New("db migration is interrupted")
+
+// DBMigrationConfig handles the options of the DB migration.
+type DBMigrationConfig struct {
+	Workers      int    // number of the copy workers for each DB
+	MaxRate      int    // maximum bytes copied per second over all DBs, 0 for no limit
+	Verify       bool   // compare the migrated items with the src DB after copying
+	ProgressFile string // file storing the progress to resume the migration, empty for no resume
+}
+
+func GetDefaultDBMigrationConfig() *DBMigrationConfig {
+	return &DBMigrationConfig{
+		Workers: 4,
+	}
+}
+
+// migrationRange returns the key range copied by the index-th worker. The key
+// space is split by the first byte of the keys, and the start of the first range
+// and the end of the last range are nil to include every key.
+func migrationRange(index, workers int) (start, end []byte) {
+	if index > 0 {
+		start = []byte{byte(256 * index / workers)}
+	}
+	if index < workers-1 {
+		end = []byte{byte(256 * (index + 1) / workers)}
+	}
+	return start, end
+}
+
+// migrationThrottle limits the bytes copied per second.
+// A nil migrationThrottle does not limit the rate.
+type migrationThrottle struct {
+	mu       sync.Mutex
+	interval time.Duration // time to copy a byte
+	next     time.Time     // time when the next copy is allowed
+}
+
+func newMigrationThrottle(maxRate int) *migrationThrottle {
+	if maxRate <= 0 {
+		return nil
+	}
+	return &migrationThrottle{interval: time.Second / time.Duration(maxRate)}
+}
+
+// wait blocks until the given bytes can be copied under the rate limit.
+func (t *migrationThrottle) wait(size int) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	now := time.Now()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	delay := t.next.Sub(now)
+	t.next = t.next.Add(t.interval * time.Duration(size))
+	t.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// migrationProgress stores the last copied key of each range in a file, so an
+// interrupted migration continues from the keys. A nil migrationProgress does
+// not store anything.
+type migrationProgress struct {
+	mu   sync.Mutex
+	path string
+
+	Dst      string                   `json:"dst"`     // description of the dst DB
+	Workers  int                      `json:"workers"` // ranges are different with a different number of workers
+	LastKeys map[string]hexutil.Bytes `json:"lastKeys"`
+	Finished map[string]bool          `json:"finished"`
+}
+
+// loadMigrationProgress loads the progress of the migration to the given dst DB.
+// The progress of a migration with a different dst DB or number of workers is discarded.
+func loadMigrationProgress(path, dst string, workers int) (*migrationProgress, error) {
+	if path == "" {
+		return nil, nil
+	}
+	progress := &migrationProgress{
+		path:     path,
+		Dst:      dst,
+		Workers:  workers,
+		LastKeys: make(map[string]hexutil.Bytes),
+		Finished: make(map[string]bool),
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return progress, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var prev migrationProgress
+	if err := json.Unmarshal(data, &prev); err != nil {
+		return nil, errors.WithMessage(err, "failed to decode the db migration progress")
+	}
+	if prev.Dst != dst || prev.Workers != workers {
+		logger.Warn("Discard the progress of the previous db migration with different settings",
+			"dst", prev.Dst, "workers", prev.Workers)
+		return progress, nil
+	}
+	if prev.LastKeys != nil {
+		progress.LastKeys = prev.LastKeys
+	}
+	if prev.Finished != nil {
+		progress.Finished = prev.Finished
+	}
+	logger.Info("Continue the previous db migration", "finishedRanges", len(progress.Finished), "startedRanges", len(progress.LastKeys))
+	return progress, nil
+}
+
+// resume returns whether the range is finished and the last copied key of it.
+func (p *migrationProgress) resume(id string) (bool, []byte) {
+	if p == nil {
+		return false, nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.Finished[id], common.CopyBytes(p.LastKeys[id])
+}
+
+func (p *migrationProgress) update(id string, lastKey []byte, finished bool) error {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if lastKey != nil {
+		p.LastKeys[id] = common.CopyBytes(lastKey)
+	}
+	if finished {
+		p.Finished[id] = true
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	// Write a temporary file first not to break the progress by a crash.
+	tmp := p.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p.path)
+}
+
+func (p *migrationProgress) remove() {
+	if p == nil {
+		return
+	}
+	if err := os.Remove(p.path); err != nil && !os.IsNotExist(err) {
+		logger.Error("Failed to remove the db migration progress", "path", p.path, "err", err)
+	}
+}
+
+// copyDB migrates a DB to another DB with the configured number of workers, each
+// of which copies a range of the keys.
 // This feature uses Iterator. A src DB should have implementation of Iteratee to use this function.
-func copyDB(name string, srcDB, dstDB Database, quit chan struct{}) error {
+func copyDB(name string, srcDB, dstDB Database, config *DBMigrationConfig, progress *migrationProgress, throttle *migrationThrottle, quit <-chan struct{}) error {
+	errCh := make(chan error, config.Workers)
+	for i := 0; i < config.Workers; i++ {
+		go func(index int) {
+			start, end := migrationRange(index, config.Workers)
+			errCh <- copyRange(name+"/"+strconv.Itoa(index), srcDB, dstDB, start, end, progress, throttle, quit)
+		}(i)
+	}
+
+	var err error
+	for i := 0; i < config.Workers; i++ {
+		if rangeErr := <-errCh; rangeErr != nil {
+			err = rangeErr
+		}
+	}
+	if err != nil || !config.Verify {
+		return err
+	}
+	return verifyDB(name, srcDB, dstDB, throttle, quit)
+}
+
+// copyRange copies the items from start (inclusive) to end (exclusive), and
+// stores the last copied key after each batch.
+func copyRange(id string, srcDB, dstDB Database, start, end []byte, progress *migrationProgress, throttle *migrationThrottle, quit <-chan struct{}) error {
+	finished, lastKey := progress.resume(id)
+	if finished {
+		logger.Info("Skip the migrated range", "range", id)
+		return nil
+	}
+	if lastKey != nil {
+		start = lastKey
+	}
+
 	// create src iterator and dst batch
-	srcIter := srcDB.NewIterator(nil, nil)
+	srcIter := srcDB.NewIterator(nil, start)
+	defer srcIter.Release()
 	dstBatch := dstDB.NewBatch()
 
 	// vars for log
-	start := time.Now()
+	begin := time.Now()
 	fetched := 0
 
-	for fetched = 0; srcIter.Next(); fetched++ {
-		// fetch keys and values
+	writeBatch := func(finished bool) error {
+		if err := dstBatch.Write(); err != nil {
+			return errors.WithMessage(err, "failed to write items")
+		}
+		dstBatch.Reset()
+		return progress.update(id, lastKey, finished)
+	}
+
+	for ; srcIter.Next(); fetched++ {
+		if end != nil && bytes.Compare(srcIter.Key(), end) >= 0 {
+			break
+		}
 		// Contents of srcIter.Key() and srcIter.Value() should not be modified, and
 		// only valid until the next call to Next.
-		key := make([]byte, len(srcIter.Key()))
-		val := make([]byte, len(srcIter.Value()))
-		copy(key, srcIter.Key())
-		copy(val, srcIter.Value())
+		key := common.CopyBytes(srcIter.Key())
+		val := common.CopyBytes(srcIter.Value())
+		throttle.wait(len(key) + len(val))
 
 		// write fetched keys and values to DB
 		// If dstDB is dynamoDB, Put will Write when the number items reach dynamoBatchSize.
 		if err := dstBatch.Put(key, val); err != nil {
 			return errors.WithMessage(err, "failed to put batch")
 		}
+		lastKey = key
 
 		if dstBatch.ValueSize() > IdealBatchSize {
-			if err := dstBatch.Write(); err != nil {
+			if err := writeBatch(false); err != nil {
 				return err
 			}
-			dstBatch.Reset()
+			// check for quit signal from OS
+			select {
+			case <-quit:
+				logger.Warn("exit called", "range", id, "fetchedTotal", fetched, "elapsedTotal", time.Since(begin))
+				return ErrDBMigrationInterrupted
+			default:
+			}
 		}
 
 		// make a report
 		if fetched%reportCycle == 0 {
-			logger.Info("DB migrated",
-				"db", name, "fetchedTotal", fetched, "elapsedTotal", time.Since(start))
-		}
-
-		// check for quit signal from OS
-		select {
-		case <-quit:
-			logger.Warn("exit called", "db", name, "fetchedTotal", fetched, "elapsedTotal", time.Since(start))
-			return nil
-		default:
+			logger.Info("DB migrated", "range", id, "fetchedTotal", fetched, "elapsedTotal", time.Since(begin))
 		}
 	}
-
-	if err := dstBatch.Write(); err != nil {
-		return errors.WithMessage(err, "failed to write items")
+	if err := srcIter.Error(); err != nil { // any accumulated error from iterator
+		return errors.WithMessage(err, "failed to iterate")
+	}
+	if err := writeBatch(true); err != nil {
+		return err
 	}
-	dstBatch.Reset()
 
-	logger.Info("Finish DB migration", "db", name, "fetchedTotal", fetched, "elapsedTotal", time.Since(start))
+	logger.Info("Finish DB migration", "range", id, "fetchedTotal", fetched, "elapsedTotal", time.Since(begin))
+	return nil
+}
 
-	srcIter.Release()
-	if err := srcIter.Error(); err != nil { // any accumulated error from iterator
+// verifyDB checks that every item of the src DB is stored in the dst DB with the same value.
+func verifyDB(name string, srcDB, dstDB Database, throttle *migrationThrottle, quit <-chan struct{}) error {
+	srcIter := srcDB.NewIterator(nil, nil)
+	defer srcIter.Release()
+
+	start := time.Now()
+	checked, mismatched := 0, 0
+	for ; srcIter.Next(); checked++ {
+		throttle.wait(len(srcIter.Key()) + len(srcIter.Value()))
+
+		val, err := dstDB.Get(srcIter.Key())
+		if err != nil || !bytes.Equal(val, srcIter.Value()) {
+			if mismatched < maxReportedMismatches {
+				logger.Error("Found an item not migrated correctly", "db", name, "key", common.Bytes2Hex(srcIter.Key()), "err", err)
+			}
+			mismatched++
+		}
+
+		if checked%reportCycle == 0 {
+			select {
+			case <-quit:
+				return ErrDBMigrationInterrupted
+			default:
+			}
+			logger.Info("DB verified", "db", name, "checkedTotal", checked, "elapsedTotal", time.Since(start))
+		}
+	}
+	if err := srcIter.Error(); err != nil {
 		return errors.WithMessage(err, "failed to iterate")
 	}
-
+	if mismatched > 0 {
+		return fmt.Errorf("%d of %d items of %s are not migrated correctly", mismatched, checked, name)
+	}
+	logger.Info("Finish DB verification", "db", name, "checkedTotal", checked, "elapsedTotal", time.Since(start))
 	return nil
 }
 
+// dstDescription describes the dst DB to detect the progress of a different migration.
+func dstDescription(dbc *DBConfig) string {
+	desc := fmt.Sprintf("%s:%s:single=%t", dbc.DBType, dbc.Dir, dbc.SingleDB)
+	if dbc.DBType == DynamoDB && dbc.DynamoDBConfig != nil {
+		desc += ":" + dbc.DynamoDBConfig.TableName
+	}
+	return desc
+}
+
 // StartDBMigration migrates a DB to another DB.
 // (e.g. LevelDB -> LevelDB, LevelDB -> BadgerDB, LevelDB -> DynamoDB)
+// With config.ProgressFile, the progress is stored, and an interrupted migration
+// continues from it if it is started again with the same dst DB.
 // Do not migrate db while a node is executing.
-func (dbm *databaseManager) StartDBMigration(dstdbm DBManager) error {
+func (dbm *databaseManager) StartDBMigration(dstdbm DBManager, config *DBMigrationConfig) error {
+	// The migration iterates the src DB in key order.
+	// TODO-Klaytn-Storage Add RocksDB when the RocksDB backend is introduced.
+	if dbm.config.DBType != LevelDB && dbm.config.DBType != MemoryDB {
+		return fmt.Errorf("db migration from %s is not supported", dbm.config.DBType)
+	}
+	if config == nil {
+		config = GetDefaultDBMigrationConfig()
+	}
+	if config.Workers <= 0 {
+		config.Workers = 1
+	}
+	progress, err := loadMigrationProgress(config.ProgressFile, dstDescription(dstdbm.GetDBConfig()), config.Workers)
+	if err != nil {
+		return err
+	}
+	throttle := newMigrationThrottle(config.MaxRate)
+
 	// settings for quit signal from os
 	quit := make(chan struct{})
 	go func() {
@@ -139,20 +412,25 @@ func (dbm *databaseManager) StartDBMigration(dstdbm DBManager) error {
 
 			dbIdx := et
 			go func() {
-				errChan <- copyDB(dbBaseDirs[dbIdx], srcDB, dstDB, quit)
+				errChan <- copyDB(dbBaseDirs[dbIdx], srcDB, dstDB, config, progress, throttle, quit)
 			}()
 		}
 
+		var copyErr error
 		for et := MiscDB; et < databaseEntryTypeSize; et++ {
-			err := <-errChan
-			if err != nil {
+			if err := <-errChan; err != nil {
 				logger.Error("copyDB got an error", "err", err)
+				copyErr = err
 			}
 		}
+		if copyErr != nil {
+			return copyErr
+		}
 
 		// Reset state trie DB path if migrated state trie path ("statetrie_migrated_XXXXXX") is set
 		dstdbm.setDBDir(DBEntryType(StateTrieDB), "")
 
+		progress.remove()
 		return nil
 	}
 
@@ -160,7 +438,7 @@ func (dbm *databaseManager) StartDBMigration(dstdbm DBManager) error {
 	srcDB := dbm.getDatabase(0)
 	dstDB := dstdbm.getDatabase(0)
 
-	if err := copyDB("single", srcDB, dstDB, quit); err != nil {
+	if err := copyDB("single", srcDB, dstDB, config, progress, throttle, quit); err != nil {
 		return err
 	}
 
@@ -172,5 +450,6 @@ func (dbm *databaseManager) StartDBMigration(dstdbm DBManager) error {
 		}
 	}
 
+	progress.remove()
 	return nil
 }
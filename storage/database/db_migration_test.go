@@ -0,0 +1,86 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationRange(t *testing.T) {
+	for _, workers := range []int{1, 3, 4, 256} {
+		start, _ := migrationRange(0, workers)
+		assert.Nil(t, start)
+		_, end := migrationRange(workers-1, workers)
+		assert.Nil(t, end)
+
+		// The ranges are contiguous.
+		for i := 1; i < workers; i++ {
+			_, prevEnd := migrationRange(i-1, workers)
+			start, _ := migrationRange(i, workers)
+			assert.Equal(t, prevEnd, start)
+		}
+	}
+}
+
+func TestCopyDB_Resume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klaytn-test-db-migration")
+	if err != nil {
+		t.Fatalf("cannot create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	progressFile := filepath.Join(dir, "progress")
+
+	srcDB, dstDB := NewMemDB(), NewMemDB()
+	for i := 0; i < 3*IdealBatchSize/1024; i++ {
+		assert.NoError(t, srcDB.Put(common.MakeRandomBytes(32), common.MakeRandomBytes(1024)))
+	}
+	config := &DBMigrationConfig{Workers: 2, Verify: true, ProgressFile: progressFile}
+
+	// The interrupted migration stores the progress.
+	progress, err := loadMigrationProgress(progressFile, "dst", config.Workers)
+	assert.NoError(t, err)
+	quit := make(chan struct{})
+	close(quit)
+	assert.Equal(t, ErrDBMigrationInterrupted, copyDB("test", srcDB, dstDB, config, progress, nil, quit))
+	assert.True(t, dstDB.Len() < srcDB.Len())
+
+	// The migration continues from the progress, and the migrated items are verified.
+	progress, err = loadMigrationProgress(progressFile, "dst", config.Workers)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(progress.LastKeys))
+	assert.NoError(t, copyDB("test", srcDB, dstDB, config, progress, nil, nil))
+	assert.Equal(t, srcDB.Len(), dstDB.Len())
+	assert.Equal(t, 2, len(progress.Finished))
+
+	// The progress of a different migration is discarded.
+	progress, err = loadMigrationProgress(progressFile, "other", config.Workers)
+	assert.NoError(t, err)
+	assert.Empty(t, progress.Finished)
+
+	// The verification finds the items not migrated correctly.
+	it := srcDB.NewIterator(nil, nil)
+	it.Next()
+	assert.NoError(t, dstDB.Put(it.Key(), []byte("wrong")))
+	it.Release()
+	assert.Error(t, verifyDB("test", srcDB, dstDB, nil, nil))
+}
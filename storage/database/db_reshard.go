@@ -0,0 +1,259 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/klaytn/klaytn/common"
+)
+
+var (
+	errReshardSingleDB      = errors.New("resharding is not supported with a single database")
+	errReshardSelfShardable = errors.New("resharding is not supported with a self-shardable database")
+	errReshardInMigration   = errors.New("state trie migration is in progress")
+
+	// ErrReshardInterrupted is returned if the resharding is stopped before it
+	// finishes. The resharding continues from the progress if it is run again.
+	ErrReshardInterrupted = errors.New("resharding is interrupted")
+)
+
+// validateNumShards checks if the number of shards can be used for a sharded database.
+func validateNumShards(numShards uint) error {
+	if numShards == 0 || numShards > numShardsLimit || !IsPow2(numShards) {
+		return fmt.Errorf("the number of shards should be a power of two between 1 and %d, but it is %d", numShardsLimit, numShards)
+	}
+	return nil
+}
+
+// newStateTrieDB creates the state trie database in the given directory,
+// sharded if numShards is greater than 1.
+func newStateTrieDB(dbc *DBConfig, entryType DBEntryType, dir string, numShards uint) (Database, error) {
+	newDBC := getDBEntryConfig(dbc, entryType, dir)
+	if numShards > 1 && !dbc.DBType.selfShardable() { // make non-sharding db if the db is sharding itself
+		return newShardedDB(newDBC, entryType, numShards)
+	}
+	return newDatabase(newDBC, entryType)
+}
+
+// getNumShards returns the number of shards recorded for the database in the
+// given directory, or 0 if it is not recorded.
+func (dbm *databaseManager) getNumShards(dir string) uint {
+	enc, _ := dbm.getDatabase(MiscDB).Get(databaseShardsKey(dir))
+	if len(enc) != 8 {
+		return 0
+	}
+	return uint(binary.BigEndian.Uint64(enc))
+}
+
+func (dbm *databaseManager) setNumShards(dir string, numShards uint) {
+	if err := dbm.getDatabase(MiscDB).Put(databaseShardsKey(dir), common.Int64ToByteBigEndian(uint64(numShards))); err != nil {
+		logger.Crit("Failed to put the number of DB shards", "err", err)
+	}
+}
+
+func (dbm *databaseManager) deleteNumShards(dir string) {
+	if err := dbm.getDatabase(MiscDB).Delete(databaseShardsKey(dir)); err != nil {
+		logger.Error("Failed to delete the number of DB shards", "dir", dir, "err", err)
+	}
+}
+
+// stateTrieShards returns the number of shards of the state trie database in
+// the given directory. Once a database is created, its number of shards is
+// recorded and takes precedence over the configured one, because the keys are
+// distributed to the shards by the number.
+func (dbm *databaseManager) stateTrieShards(dir string) uint {
+	numShards := dbm.config.NumStateTrieShards
+	if dbm.config.DBType.selfShardable() {
+		return numShards
+	}
+	if recorded := dbm.getNumShards(dir); recorded > 0 {
+		if recorded != numShards {
+			logger.Warn("The number of state trie shards is different from the configured one. Use `db reshard` to change it",
+				"dir", dir, "numShards", recorded, "configured", numShards)
+		}
+		return recorded
+	}
+	if numShards == 0 {
+		numShards = 1
+	}
+	dbm.setNumShards(dir, numShards)
+	return numShards
+}
+
+func (dbm *databaseManager) checkReshardable(numShards uint) error {
+	if err := validateNumShards(numShards); err != nil {
+		return err
+	}
+	if dbm.config.SingleDB {
+		return errReshardSingleDB
+	}
+	if dbm.config.DBType.selfShardable() {
+		return errReshardSelfShardable
+	}
+	if dbm.InMigration() {
+		return errReshardInMigration
+	}
+	return nil
+}
+
+// SetStateTrieMigrationShards sets the number of shards of the database created
+// by the next state trie migration. The state trie database is resharded in the
+// background while the node is running, but only the state of the migration
+// block is copied, as the state trie migration does.
+func (dbm *databaseManager) SetStateTrieMigrationShards(numShards uint) error {
+	if err := dbm.checkReshardable(numShards); err != nil {
+		return err
+	}
+	dbm.lockInMigration.Lock()
+	defer dbm.lockInMigration.Unlock()
+
+	dbm.migrationShards = numShards
+	logger.Info("The next state trie migration reshards the state trie database", "numShards", numShards)
+	return nil
+}
+
+func reshardDir(numShards uint) string {
+	return dbBaseDirs[StateTrieDB] + "_reshard_" + strconv.FormatUint(uint64(numShards), 10)
+}
+
+// ReshardStateTrieDB copies all the data of the state trie database to a new
+// database with the given number of shards, and replaces the old one with it.
+// The progress is stored after each batch, so an interrupted resharding
+// continues from the last copied key if it is run again with the same number.
+// Do not reshard the database while a node is executing.
+func (dbm *databaseManager) ReshardStateTrieDB(numShards uint, quit <-chan struct{}) error {
+	if err := dbm.checkReshardable(numShards); err != nil {
+		return err
+	}
+	miscDB := dbm.getDatabase(MiscDB)
+
+	srcDir := dbm.getDBDir(StateTrieDB)
+	srcShards := dbm.stateTrieShards(srcDir)
+	if srcShards == numShards {
+		logger.Info("The state trie database already has the given number of shards", "dir", srcDir, "numShards", numShards)
+		return nil
+	}
+
+	// Continue the previous resharding, or clean up the one to a different number of shards.
+	var start []byte
+	if enc, _ := miscDB.Get(reshardProgressKey); len(enc) >= 8 {
+		if prevShards := uint(binary.BigEndian.Uint64(enc)); prevShards == numShards {
+			start = common.CopyBytes(enc[8:])
+			logger.Info("Continue the previous resharding", "numShards", numShards, "lastKey", common.Bytes2Hex(start))
+		} else {
+			logger.Warn("Remove the unfinished resharding to a different number of shards", "numShards", prevShards)
+			removeDB(filepath.Join(dbm.config.Dir, reshardDir(prevShards)), nil)
+			dbm.deleteNumShards(reshardDir(prevShards))
+			dbm.deleteValueCodec(reshardDir(prevShards))
+		}
+	}
+
+	dstDir := reshardDir(numShards)
+	dstDB, err := newStateTrieDB(dbm.config, StateTrieDB, dstDir, numShards)
+	if err != nil {
+		return err
+	}
+	if err := dbm.checkValueCodec(dstDir, StateTrieDB, dstDB); err != nil {
+		dstDB.Close()
+		return err
+	}
+	dbm.setNumShards(dstDir, numShards)
+
+	logger.Info("Start resharding the state trie database", "from", srcShards, "to", numShards, "dir", dstDir)
+	if err := reshardCopy(dbm.getDatabase(StateTrieDB), dstDB, miscDB, numShards, start, quit); err != nil {
+		dstDB.Close()
+		return err
+	}
+
+	dbm.lockInMigration.Lock()
+	defer dbm.lockInMigration.Unlock()
+
+	srcDB := dbm.dbs[StateTrieDB]
+	dbm.setDBDir(StateTrieDB, dstDir)
+	dbm.setDatabase(StateTrieDB, dstDB)
+	if err := miscDB.Delete(reshardProgressKey); err != nil {
+		logger.Error("Failed to delete the resharding progress", "err", err)
+	}
+
+	srcDB.Close()
+	removeDB(filepath.Join(dbm.config.Dir, srcDir), nil)
+	dbm.deleteNumShards(srcDir)
+	dbm.deleteValueCodec(srcDir)
+
+	logger.Info("Finished resharding the state trie database", "numShards", numShards, "dir", dstDir)
+	return nil
+}
+
+// reshardCopy copies the items of srcDB from the start key to dstDB, and stores
+// the last copied key with the number of shards after each batch.
+func reshardCopy(srcDB, dstDB, miscDB Database, numShards uint, start []byte, quit <-chan struct{}) error {
+	it := srcDB.NewIterator(nil, start)
+	defer it.Release()
+
+	var (
+		batch   = dstDB.NewBatch()
+		copied  int
+		lastKey []byte
+		started = time.Now()
+		logged  = time.Now()
+	)
+	writeProgress := func() error {
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+		if lastKey == nil {
+			return nil
+		}
+		progress := append(common.Int64ToByteBigEndian(uint64(numShards)), lastKey...)
+		return miscDB.Put(reshardProgressKey, progress)
+	}
+
+	for it.Next() {
+		lastKey = common.CopyBytes(it.Key())
+		if err := batch.Put(lastKey, common.CopyBytes(it.Value())); err != nil {
+			return err
+		}
+		copied++
+
+		if batch.ValueSize() > IdealBatchSize {
+			if err := writeProgress(); err != nil {
+				return err
+			}
+			select {
+			case <-quit:
+				logger.Warn("Resharding is interrupted", "copied", copied, "elapsed", common.PrettyDuration(time.Since(started)))
+				return ErrReshardInterrupted
+			default:
+			}
+		}
+		if time.Since(logged) > 8*time.Second {
+			logger.Info("Resharding the state trie database", "copied", copied, "elapsed", common.PrettyDuration(time.Since(started)))
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return writeProgress()
+}
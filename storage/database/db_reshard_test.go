@@ -0,0 +1,78 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabaseManager_ReshardStateTrieDB(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klaytn-test-reshard")
+	if err != nil {
+		t.Fatalf("cannot create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	dbc := &DBConfig{Dir: dir, DBType: LevelDB, LevelDBCacheSize: 32, OpenFilesLimit: 32, NumStateTrieShards: 4}
+	dbm := NewDBManager(dbc)
+
+	// Write more items than a batch to interrupt the resharding in the middle.
+	items := make(map[string][]byte)
+	batch := dbm.NewBatch(StateTrieDB)
+	for i := 0; i < 3*IdealBatchSize/1024; i++ {
+		key, val := common.MakeRandomBytes(32), common.MakeRandomBytes(1024)
+		items[string(key)] = val
+		assert.NoError(t, batch.Put(key, val))
+	}
+	assert.NoError(t, batch.Write())
+
+	assert.Error(t, dbm.ReshardStateTrieDB(3, nil))
+	assert.NoError(t, dbm.ReshardStateTrieDB(4, nil)) // no-op
+
+	// The interrupted resharding keeps the old database.
+	quit := make(chan struct{})
+	close(quit)
+	assert.Equal(t, ErrReshardInterrupted, dbm.ReshardStateTrieDB(2, quit))
+	assert.Equal(t, dbBaseDirs[StateTrieDB], dbm.getDBDir(StateTrieDB))
+
+	// The resharding continues from the progress.
+	assert.NoError(t, dbm.ReshardStateTrieDB(2, nil))
+	assert.Equal(t, reshardDir(2), dbm.getDBDir(StateTrieDB))
+	_, err = os.Stat(filepath.Join(dir, dbBaseDirs[StateTrieDB]))
+	assert.True(t, os.IsNotExist(err))
+	dbm.Close()
+
+	// The recorded number of shards is used regardless of the configured one.
+	dbm = NewDBManager(dbc)
+	defer dbm.Close()
+
+	sdb, ok := dbm.(*databaseManager).dbs[StateTrieDB].(*shardedDB)
+	if assert.True(t, ok) {
+		assert.Equal(t, uint(2), sdb.numShards)
+	}
+	for key, val := range items {
+		stored, err := dbm.GetStateTrieDB().Get([]byte(key))
+		assert.NoError(t, err)
+		assert.Equal(t, val, stored)
+	}
+}
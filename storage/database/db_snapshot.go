@@ -0,0 +1,261 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+var (
+	errSnapshotNotSupported    = errors.New("database snapshot is not supported")
+	errSnapshotDirNotEmpty     = errors.New("snapshot directory is not empty")
+	errSnapshotInterrupted     = errors.New("writing database snapshot is interrupted")
+	errSnapshotStreamingFailed = errors.New("streaming database snapshot failed")
+)
+
+// DBSnapshot is a consistent point-in-time view of all databases, which can be
+// written to a directory while the databases are being updated. The directory
+// has the same layout as the original data directory, so it can be used as the
+// data directory of another node.
+//
+// LevelDB is captured by its snapshot and copied into new databases when the
+// snapshot is written.
+//
+// The data migrated to the cold storage is not included.
+type DBSnapshot struct {
+	dir     string
+	writers []dbSnapshotWriter
+}
+
+// dbSnapshotWriter writes a captured view of a database.
+type dbSnapshotWriter interface {
+	// write writes the view and releases it.
+	write(quit <-chan struct{}) error
+	release()
+}
+
+// Dir returns the directory where the snapshot is written.
+func (s *DBSnapshot) Dir() string {
+	return s.dir
+}
+
+// Write writes the snapshot to its directory. The snapshot is released after
+// written or if it fails.
+func (s *DBSnapshot) Write(quit <-chan struct{}) error {
+	defer s.Release()
+	for len(s.writers) > 0 {
+		w := s.writers[0]
+		s.writers = s.writers[1:]
+		if err := w.write(quit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Release releases the views of the snapshot not written yet.
+func (s *DBSnapshot) Release() {
+	for _, w := range s.writers {
+		w.release()
+	}
+	s.writers = nil
+}
+
+// CreateSnapshot captures a point-in-time view of all databases to be written to
+// the given directory, which must be empty. The caller should stop writing to
+// the databases while capturing, to make the views of the databases consistent.
+func (dbm *databaseManager) CreateSnapshot(dir string) (*DBSnapshot, error) {
+	if entries, err := ioutil.ReadDir(dir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("%w: %s", errSnapshotDirNotEmpty, dir)
+	}
+	dstConfig := *dbm.config
+	dstConfig.Dir = dir
+
+	snapshot := &DBSnapshot{dir: dir}
+	if dbm.config.SingleDB {
+		writers, err := captureDatabase(dbm.dbs[0], &dstConfig, 0)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.writers = writers
+		return snapshot, nil
+	}
+
+	for et := MiscDB; et < databaseEntryTypeSize; et++ {
+		db := dbm.getDatabase(et)
+		if db == nil {
+			continue
+		}
+		writers, err := captureDatabase(db, getDBEntryConfig(&dstConfig, et, dbm.getDBDir(et)), et)
+		if err != nil {
+			snapshot.Release()
+			return nil, err
+		}
+		snapshot.writers = append(snapshot.writers, writers...)
+	}
+	return snapshot, nil
+}
+
+// captureDatabase captures a view of the database to be written to a new
+// database of the given configuration. The shards of a sharded database are
+// captured into the sub-directories as newShardedDB creates them.
+func captureDatabase(db Database, dbc *DBConfig, entryType DBEntryType) ([]dbSnapshotWriter, error) {
+	switch db := unwrapDatabase(db).(type) {
+	case *shardedDB:
+		var writers []dbSnapshotWriter
+		for i, shard := range db.shards {
+			shardDBC := *dbc
+			shardDBC.Dir = path.Join(dbc.Dir, strconv.Itoa(i))
+			w, err := captureDatabase(shard, &shardDBC, entryType)
+			if err != nil {
+				for _, w := range writers {
+					w.release()
+				}
+				return nil, err
+			}
+			writers = append(writers, w...)
+		}
+		return writers, nil
+	case *levelDB:
+		snap, err := db.db.GetSnapshot()
+		if err != nil {
+			return nil, err
+		}
+		return []dbSnapshotWriter{&levelDBSnapshot{snap: snap, dbc: dbc, entryType: entryType}}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errSnapshotNotSupported, db.Type())
+	}
+}
+
+// levelDBSnapshot copies a LevelDB snapshot into a new LevelDB.
+type levelDBSnapshot struct {
+	snap      *leveldb.Snapshot
+	dbc       *DBConfig
+	entryType DBEntryType
+}
+
+func (s *levelDBSnapshot) write(quit <-chan struct{}) error {
+	defer s.release()
+
+	dst, err := NewLevelDB(s.dbc, s.entryType)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	it := s.snap.NewIterator(nil, nil)
+	defer it.Release()
+
+	batch := dst.NewBatch()
+	for it.Next() {
+		select {
+		case <-quit:
+			return errSnapshotInterrupted
+		default:
+		}
+		if err := batch.Put(it.Key(), it.Value()); err != nil {
+			return err
+		}
+		if batch.ValueSize() > IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	logger.Info("Wrote database snapshot", "dir", s.dbc.Dir)
+	return nil
+}
+
+func (s *levelDBSnapshot) release() {
+	s.snap.Release()
+}
+
+// StreamSnapshot sends the snapshot written in the given directory to the given
+// URL as a tar archive in the body of a POST request. The archive is extracted
+// into the data directory to restore the databases.
+func StreamSnapshot(dir, url string) error {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeTar(pw, dir))
+	}()
+
+	resp, err := http.Post(url, "application/x-tar", pr)
+	if err != nil {
+		pr.CloseWithError(err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: %s", errSnapshotStreamingFailed, resp.Status)
+	}
+	return nil
+}
+
+// writeTar writes the files in the given directory to w as a tar archive.
+func writeTar(w io.Writer, dir string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, file)
+		if err != nil || name == "." {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
@@ -0,0 +1,118 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"archive/tar"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBManager_CreateSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klaytn-test-db-snapshot")
+	if err != nil {
+		t.Fatalf("cannot create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	dbc := &DBConfig{Dir: filepath.Join(dir, "chaindata"), DBType: LevelDB, LevelDBCacheSize: 32, OpenFilesLimit: 32, NumStateTrieShards: 4}
+	dbm := NewDBManager(dbc)
+	defer dbm.Close()
+
+	keys := [][]byte{{0x00}, {0x01}, {0x02}, {0x03}, {0xff}}
+	for _, key := range keys {
+		assert.NoError(t, dbm.getDatabase(headerDB).Put(key, []byte("header")))
+		assert.NoError(t, dbm.getDatabase(StateTrieDB).Put(key, []byte("node")))
+	}
+
+	snapshotDir := filepath.Join(dir, "snapshot")
+	snapshot, err := dbm.CreateSnapshot(snapshotDir)
+	assert.NoError(t, err)
+
+	// The data written after captured is not included in the snapshot.
+	assert.NoError(t, dbm.getDatabase(headerDB).Put([]byte("after"), []byte("header")))
+	assert.NoError(t, dbm.getDatabase(StateTrieDB).Delete(keys[0]))
+
+	assert.NoError(t, snapshot.Write(nil))
+	assert.Equal(t, snapshotDir, snapshot.Dir())
+
+	_, err = dbm.CreateSnapshot(snapshotDir)
+	assert.True(t, errors.Is(err, errSnapshotDirNotEmpty))
+
+	snapshotDBC := *dbc
+	snapshotDBC.Dir = snapshotDir
+	snapshotDBM := NewDBManager(&snapshotDBC)
+	defer snapshotDBM.Close()
+
+	for _, key := range keys {
+		value, err := snapshotDBM.getDatabase(headerDB).Get(key)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("header"), value)
+
+		value, err = snapshotDBM.getDatabase(StateTrieDB).Get(key)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("node"), value)
+	}
+	has, err := snapshotDBM.getDatabase(headerDB).Has([]byte("after"))
+	assert.NoError(t, err)
+	assert.False(t, has)
+}
+
+func TestDBManager_CreateSnapshot_NotSupported(t *testing.T) {
+	_, err := NewMemoryDBManager().CreateSnapshot("")
+	assert.True(t, errors.Is(err, errSnapshotNotSupported))
+}
+
+func TestStreamSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klaytn-test-db-snapshot-stream")
+	if err != nil {
+		t.Fatalf("cannot create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "header"), 0o755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "header", "000001.ldb"), []byte("table"), 0o644))
+
+	files := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tr := tar.NewReader(r.Body)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			content, _ := ioutil.ReadAll(tr)
+			files[header.Name] = string(content)
+		}
+	}))
+	defer server.Close()
+
+	assert.NoError(t, StreamSnapshot(dir, server.URL))
+	assert.Equal(t, map[string]string{"header": "", "header/000001.ldb": "table"}, files)
+}
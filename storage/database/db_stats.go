@@ -0,0 +1,306 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/klaytn/klaytn/common"
+	metricutils "github.com/klaytn/klaytn/metrics/utils"
+	"github.com/rcrowley/go-metrics"
+)
+
+const (
+	dbStatsInterval   = time.Minute // Interval of sampling the storage stats for the metrics
+	dbStatsSampleKeys = 10000       // Number of the keys iterated to estimate the number of the keys
+	singleDBStatsName = "all"       // Name of the stats of the single database
+)
+
+// DBStats is the storage stats of a logical database, such as header or statetrie.
+type DBStats struct {
+	Name              string        `json:"name"`
+	Dir               string        `json:"dir"`
+	Size              uint64        `json:"size"`              // Size of the files on disk in bytes
+	Keys              uint64        `json:"keys"`              // Number of the keys, estimated if KeysEstimated is true
+	KeysEstimated     bool          `json:"keysEstimated"`     // Whether Keys is estimated from the sampled keys
+	PendingCompaction uint64        `json:"pendingCompaction"` // Size of the data waiting for compaction in bytes
+	WriteStalls       uint64        `json:"writeStalls"`       // Number of the writes stalled by compactions
+	WriteStallTime    time.Duration `json:"writeStallTime"`    // Total time spent in write stalls
+	Error             string        `json:"error,omitempty"`
+}
+
+// statsDatabase is implemented by the databases reporting their internal stats.
+type statsDatabase interface {
+	backendStats() (backendStats, error)
+	// approximateSize returns the approximate size of the files on disk storing
+	// the keys in the given range.
+	approximateSize(start, limit []byte) (uint64, error)
+}
+
+// backendStats is the internal stats of a database backend.
+//
+// The pending compaction is the size of the level 0 tables for LevelDB.
+type backendStats struct {
+	pendingCompaction uint64
+	writeStalls       uint64
+	writeStallTime    time.Duration
+}
+
+// Stats returns the storage stats of each logical database. If a single
+// database is used, the stats of the single database is returned.
+func (dbm *databaseManager) Stats() []DBStats {
+	if dbm.config.SingleDB || dbm.config.DBType == MemoryDB {
+		return []DBStats{collectDBStats(singleDBStatsName, dbm.config.Dir, dbm.config.DBType, dbm.dbs[0])}
+	}
+
+	stats := make([]DBStats, 0, databaseEntryTypeSize)
+	for et := MiscDB; et < databaseEntryTypeSize; et++ {
+		db := dbm.getDatabase(et)
+		if db == nil {
+			continue
+		}
+		dir := filepath.Join(dbm.config.Dir, dbm.getDBDir(et))
+		stats = append(stats, collectDBStats(dbBaseDirs[et], dir, dbm.config.DBType, db))
+	}
+	return stats
+}
+
+func collectDBStats(name, dir string, dbType DBType, db Database) DBStats {
+	stats := DBStats{Name: name}
+
+	// The files of DynamoDB and MemoryDB are not on the local disk, and iterating
+	// DynamoDB costs as much as reading the items.
+	if dbType != LevelDB && dbType != BadgerDB {
+		return stats
+	}
+	stats.Dir = dir
+
+	var err error
+	if stats.Size, err = dirSize(dir); err != nil {
+		stats.Error = err.Error()
+		return stats
+	}
+	if stats.Keys, stats.KeysEstimated, err = countKeys(db, stats.Size, dbStatsSampleKeys); err != nil {
+		stats.Error = err.Error()
+		return stats
+	}
+	if bs, err := databaseBackendStats(db); err != nil {
+		stats.Error = err.Error()
+	} else {
+		stats.PendingCompaction = bs.pendingCompaction
+		stats.WriteStalls = bs.writeStalls
+		stats.WriteStallTime = bs.writeStallTime
+	}
+	return stats
+}
+
+// dirSize returns the total size of the files in the given directory. The files
+// removed by compactions while walking the directory are ignored.
+func dirSize(dir string) (uint64, error) {
+	var size uint64
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			size += uint64(info.Size())
+		}
+		return nil
+	})
+	return size, err
+}
+
+// unwrapDatabase returns the database wrapped by the value compression or the
+// cold storage.
+func unwrapDatabase(db Database) Database {
+	for {
+		switch d := db.(type) {
+		case *freezableDB:
+			db = d.Database
+		case *compressedDB:
+			db = d.Database
+		case *coldStorageDB:
+			db = d.Database
+		case *ancientSourceDB:
+			db = d.Database
+		default:
+			return db
+		}
+	}
+}
+
+// databaseBackendStats returns the internal stats of the database, summing up
+// the stats of the shards if it is sharded.
+func databaseBackendStats(db Database) (backendStats, error) {
+	switch db := unwrapDatabase(db).(type) {
+	case *shardedDB:
+		var sum backendStats
+		for _, shard := range db.shards {
+			s, err := databaseBackendStats(shard)
+			if err != nil {
+				return sum, err
+			}
+			sum.pendingCompaction += s.pendingCompaction
+			sum.writeStalls += s.writeStalls
+			sum.writeStallTime += s.writeStallTime
+		}
+		return sum, nil
+	case statsDatabase:
+		return db.backendStats()
+	default:
+		return backendStats{}, nil
+	}
+}
+
+// countKeys counts the keys of the database whose files take the given size.
+// If there are more keys than the given limit, it extrapolates the number of
+// the sampled keys by the ratio of the size to the size of the sampled range.
+// If the size of the sampled range is not known, such as when the keys are
+// still in the memtable, the number of the sampled keys is returned.
+func countKeys(db Database, size uint64, limit int) (uint64, bool, error) {
+	keys, sampledSize, complete, err := sampleKeys(db, limit)
+	if err != nil || complete {
+		return keys, false, err
+	}
+	if sampledSize == 0 {
+		return keys, true, nil
+	}
+	return keys * size / sampledSize, true, nil
+}
+
+// sampleKeys iterates up to the given number of the keys from the start of the
+// database. It returns the number of the iterated keys, the approximate size of
+// the iterated range, and whether all keys have been iterated. The keys of a
+// sharded database are sampled from each shard.
+func sampleKeys(db Database, limit int) (uint64, uint64, bool, error) {
+	db = unwrapDatabase(db)
+	if sdb, ok := db.(*shardedDB); ok {
+		var keys, sampledSize uint64
+		complete := true
+		shardLimit := limit / len(sdb.shards)
+		if shardLimit == 0 {
+			shardLimit = 1
+		}
+		for _, shard := range sdb.shards {
+			k, s, c, err := sampleKeys(shard, shardLimit)
+			if err != nil {
+				return 0, 0, false, err
+			}
+			keys, sampledSize, complete = keys+k, sampledSize+s, complete && c
+		}
+		return keys, sampledSize, complete, nil
+	}
+
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+
+	var (
+		keys        uint64
+		first, last []byte
+		complete    = true
+	)
+	for it.Next() {
+		if keys == uint64(limit) {
+			complete = false
+			break
+		}
+		if first == nil {
+			first = common.CopyBytes(it.Key())
+		}
+		last = append(last[:0], it.Key()...)
+		keys++
+	}
+	if err := it.Error(); err != nil {
+		return 0, 0, false, err
+	}
+
+	sdb, ok := db.(statsDatabase)
+	if complete || !ok {
+		return keys, 0, complete, nil
+	}
+	// The limit of the range is exclusive, so the key right after the last one is given.
+	sampledSize, err := sdb.approximateSize(first, append(last, 0))
+	return keys, sampledSize, false, err
+}
+
+// dbStatsGauges reports the storage stats of a logical database to the metrics.
+type dbStatsGauges struct {
+	size, keys, pendingCompaction, writeStalls, writeStallTime metrics.Gauge
+}
+
+func newDBStatsGauges(prefix string) *dbStatsGauges {
+	return &dbStatsGauges{
+		size:              metrics.GetOrRegisterGauge(prefix+"stats/size", nil),
+		keys:              metrics.GetOrRegisterGauge(prefix+"stats/keys", nil),
+		pendingCompaction: metrics.GetOrRegisterGauge(prefix+"stats/compaction/pending", nil),
+		writeStalls:       metrics.GetOrRegisterGauge(prefix+"stats/writestall/count", nil),
+		writeStallTime:    metrics.GetOrRegisterGauge(prefix+"stats/writestall/time", nil),
+	}
+}
+
+func (g *dbStatsGauges) update(stats DBStats) {
+	g.size.Update(int64(stats.Size))
+	g.keys.Update(int64(stats.Keys))
+	g.pendingCompaction.Update(int64(stats.PendingCompaction))
+	g.writeStalls.Update(int64(stats.WriteStalls))
+	g.writeStallTime.Update(int64(stats.WriteStallTime))
+}
+
+// startStatsSampler periodically samples the storage stats and reports them to
+// the metrics, if the metrics system is enabled. It is stopped by Close.
+func (dbm *databaseManager) startStatsSampler() {
+	if !metricutils.Enabled {
+		return
+	}
+	dbm.statsQuit = make(chan struct{})
+	go dbm.sampleStats(dbStatsInterval, dbm.statsQuit)
+}
+
+func (dbm *databaseManager) sampleStats(interval time.Duration, quit <-chan struct{}) {
+	gauges := make(map[string]*dbStatsGauges)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, stats := range dbm.Stats() {
+			if stats.Error != "" {
+				logger.Debug("Failed to sample the storage stats", "name", stats.Name, "err", stats.Error)
+			}
+			g, ok := gauges[stats.Name]
+			if !ok {
+				prefix := dbMetricPrefix + stats.Name + "/"
+				if stats.Name == singleDBStatsName {
+					prefix = dbMetricPrefix
+				}
+				g = newDBStatsGauges(prefix)
+				gauges[stats.Name] = g
+			}
+			g.update(stats)
+		}
+
+		select {
+		case <-quit:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBManager_Stats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klaytn-test-db-stats")
+	if err != nil {
+		t.Fatalf("cannot create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	dbc := &DBConfig{Dir: dir, DBType: LevelDB, LevelDBCacheSize: 32, OpenFilesLimit: 32, NumStateTrieShards: 4}
+	dbm := NewDBManager(dbc)
+	defer dbm.Close()
+
+	hdb := dbm.getDatabase(headerDB)
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, hdb.Put(common.Int64ToByteBigEndian(uint64(i)), []byte("header")))
+	}
+	sdb := dbm.getDatabase(StateTrieDB)
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, sdb.Put(common.Int64ToByteBigEndian(uint64(i)), []byte("node")))
+	}
+
+	stats := make(map[string]DBStats)
+	for _, s := range dbm.Stats() {
+		assert.Empty(t, s.Error, s.Name)
+		stats[s.Name] = s
+	}
+	assert.Len(t, stats, int(databaseEntryTypeSize)-1) // Except the migration database
+
+	assert.Equal(t, filepath.Join(dir, dbBaseDirs[headerDB]), stats[dbBaseDirs[headerDB]].Dir)
+	assert.NotZero(t, stats[dbBaseDirs[headerDB]].Size)
+	assert.Equal(t, uint64(100), stats[dbBaseDirs[headerDB]].Keys)
+	assert.False(t, stats[dbBaseDirs[headerDB]].KeysEstimated)
+	assert.Equal(t, uint64(100), stats[dbBaseDirs[StateTrieDB]].Keys) // Sum of the shards
+	assert.Zero(t, stats[dbBaseDirs[BodyDB]].Keys)
+}
+
+func TestCountKeys(t *testing.T) {
+	db := NewMemDB()
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, db.Put(common.Int64ToByteBigEndian(uint64(i)), []byte("value")))
+	}
+
+	keys, estimated, err := countKeys(db, 0, 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), keys)
+	assert.False(t, estimated)
+
+	// The size of the sampled range is not known for MemDB.
+	keys, estimated, err = countKeys(db, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), keys)
+	assert.True(t, estimated)
+}
@@ -0,0 +1,58 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+// syncMarkerKey is deleted with the sync option to flush the journal of LevelDB,
+// which is never stored.
+var syncMarkerKey = []byte("databaseSyncMarker")
+
+// syncDatabase is a database which can flush the written data to the stable
+// storage on demand.
+type syncDatabase interface {
+	sync() error
+}
+
+// Sync flushes the data written to the local databases to the stable storage,
+// so that a snapshot of the filesystem taken while no data is written can be
+// opened without recovery. The databases not stored locally are skipped.
+func (dbm *databaseManager) Sync() error {
+	synced := make(map[Database]bool)
+	for _, db := range dbm.dbs {
+		if db == nil || synced[db] {
+			continue
+		}
+		synced[db] = true
+		if err := syncBackendDatabase(db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func syncBackendDatabase(db Database) error {
+	switch db := unwrapDatabase(db).(type) {
+	case *shardedDB:
+		for _, shard := range db.shards {
+			if err := syncBackendDatabase(shard); err != nil {
+				return err
+			}
+		}
+	case syncDatabase:
+		return db.sync()
+	}
+	return nil
+}
@@ -70,7 +70,6 @@ const (
 
 // batch write
 const WorkerNum = 10
-const itemChanSize = WorkerNum * 2
 
 var (
 	dynamoDBClient    *dynamodb.DynamoDB          // handles dynamoDB connections
@@ -89,12 +88,14 @@ type DynamoDBConfig struct {
 	WriteCapacityUnits int64  // write capacity when provisioned
 	ReadOnly           bool   // disables write
 	PerfCheck          bool
+	WriteWorkers       int // number of parallel batch write workers, WorkerNum if not set
 }
 
 type batchWriteWorkerInput struct {
-	tableName string
-	items     []*dynamodb.WriteRequest
-	wg        *sync.WaitGroup
+	tableName   string
+	items       []*dynamodb.WriteRequest
+	wg          *sync.WaitGroup
+	provisioned bool // billing mode of the table
 }
 
 // TODO-Klaytn refactor the structure : there are common configs that are placed separated
@@ -103,6 +104,8 @@ type dynamoDB struct {
 	fdb    fileDB     // where over size items are stored
 	logger log.Logger // Contextual logger tracking the database path
 
+	getCalls *dynamoGetGroup // coalesces the concurrent reads of the same key
+
 	// metrics
 	getTimer klaytnmetrics.HybridTimer
 	putTimer klaytnmetrics.HybridTimer
@@ -144,6 +147,7 @@ func GetDefaultDynamoDBConfig() *DynamoDBConfig {
 		WriteCapacityUnits: 10000,
 		ReadOnly:           false,
 		PerfCheck:          true,
+		WriteWorkers:       WorkerNum,
 	}
 }
 
@@ -191,8 +195,9 @@ func newDynamoDB(config *DynamoDBConfig) (*dynamoDB, error) {
 		})))
 	}
 	dynamoDB := &dynamoDB{
-		config: *config,
-		fdb:    s3FileDB,
+		config:   *config,
+		fdb:      s3FileDB,
+		getCalls: newDynamoGetGroup(),
 	}
 
 	dynamoDB.logger = logger.NewWith("region", config.Region, "tableName", dynamoDB.config.TableName)
@@ -215,12 +220,13 @@ func newDynamoDB(config *DynamoDBConfig) (*dynamoDB, error) {
 
 		switch tableStatus {
 		case dynamodb.TableStatusActive:
+			dynamoDB.checkBillingMode()
 			if !dynamoDB.config.ReadOnly {
 				// count successful table creating
 				dynamoOpenedDBNum++
 				// create workers on the first successful table creation
 				dynamoOnceWorker.Do(func() {
-					createBatchWriteWorkerPool()
+					createBatchWriteWorkerPool(dynamoDB.config.WriteWorkers)
 				})
 			}
 			dynamoDB.logger.Info("successfully created dynamoDB session")
@@ -271,6 +277,38 @@ func (dynamo *dynamoDB) createTable() error {
 	return nil
 }
 
+// checkBillingMode compares the billing mode of the existing table with the
+// configuration. The billing mode of the table is used since it can't be changed
+// by opening the table, and the provisioned capacity is reported as metrics.
+func (dynamo *dynamoDB) checkBillingMode() {
+	desc, err := dynamo.tableDescription()
+	if err != nil || desc == nil {
+		dynamo.logger.Warn("unable to check the billing mode of the DynamoDB table", "err", err)
+		return
+	}
+
+	// The billing mode summary is omitted for the tables created as provisioned.
+	provisioned := desc.BillingModeSummary == nil ||
+		aws.StringValue(desc.BillingModeSummary.BillingMode) == dynamodb.BillingModeProvisioned
+	if provisioned != dynamo.config.IsProvisioned {
+		dynamo.logger.Warn("billing mode of the DynamoDB table differs from the configuration",
+			"tableProvisioned", provisioned, "configProvisioned", dynamo.config.IsProvisioned)
+		dynamo.config.IsProvisioned = provisioned
+	}
+	if !provisioned || desc.ProvisionedThroughput == nil {
+		return
+	}
+
+	rcu := aws.Int64Value(desc.ProvisionedThroughput.ReadCapacityUnits)
+	wcu := aws.Int64Value(desc.ProvisionedThroughput.WriteCapacityUnits)
+	dynamoProvisionedRCUGauge.Update(rcu)
+	dynamoProvisionedWCUGauge.Update(wcu)
+	if rcu != dynamo.config.ReadCapacityUnits || wcu != dynamo.config.WriteCapacityUnits {
+		dynamo.logger.Warn("provisioned capacity of the DynamoDB table differs from the configuration",
+			"tableRCU", rcu, "tableWCU", wcu, "configRCU", dynamo.config.ReadCapacityUnits, "configWCU", dynamo.config.WriteCapacityUnits)
+	}
+}
+
 func (dynamo *dynamoDB) deleteTable() error {
 	if _, err := dynamoDBClient.DeleteTable(&dynamodb.DeleteTableInput{TableName: &dynamo.config.TableName}); err != nil {
 		dynamo.logger.Error("Error while deleting the DynamoDB table", "tableName", dynamo.config.TableName)
@@ -304,6 +342,7 @@ func (dynamo *dynamoDB) Type() DBType {
 
 // Put inserts the given key and value pair to the database.
 func (dynamo *dynamoDB) Put(key []byte, val []byte) error {
+	defer dynamo.getCalls.forget(key)
 	if dynamo.config.PerfCheck {
 		start := time.Now()
 		err := dynamo.put(key, val)
@@ -333,15 +372,17 @@ func (dynamo *dynamoDB) put(key []byte, val []byte) error {
 	}
 
 	params := &dynamodb.PutItemInput{
-		TableName: aws.String(dynamo.config.TableName),
-		Item:      marshaledData,
+		TableName:              aws.String(dynamo.config.TableName),
+		Item:                   marshaledData,
+		ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityTotal),
 	}
 
-	_, err = dynamoDBClient.PutItem(params)
+	output, err := dynamoDBClient.PutItem(params)
 	if err != nil {
 		dynamo.logger.Crit("failed to put an item", "err", err, "key", hexutil.Encode(data.Key))
 		return err
 	}
+	markConsumedCapacity(dynamoConsumedWCUMeter, output.ConsumedCapacity)
 
 	return nil
 }
@@ -369,6 +410,12 @@ func (dynamo *dynamoDB) Get(key []byte) ([]byte, error) {
 }
 
 func (dynamo *dynamoDB) get(key []byte) ([]byte, error) {
+	return dynamo.getCalls.do(key, func() ([]byte, error) {
+		return dynamo.getItem(key)
+	})
+}
+
+func (dynamo *dynamoDB) getItem(key []byte) ([]byte, error) {
 	params := &dynamodb.GetItemInput{
 		TableName: aws.String(dynamo.config.TableName),
 		Key: map[string]*dynamodb.AttributeValue{
@@ -376,7 +423,8 @@ func (dynamo *dynamoDB) get(key []byte) ([]byte, error) {
 				B: key,
 			},
 		},
-		ConsistentRead: aws.Bool(true),
+		ConsistentRead:         aws.Bool(true),
+		ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityTotal),
 	}
 
 	result, err := dynamoDBClient.GetItem(params)
@@ -384,6 +432,7 @@ func (dynamo *dynamoDB) get(key []byte) ([]byte, error) {
 		dynamo.logger.Crit("failed to get an item", "err", err, "key", hexutil.Encode(key))
 		return nil, err
 	}
+	markConsumedCapacity(dynamoConsumedRCUMeter, result.ConsumedCapacity)
 
 	if result.Item == nil {
 		return nil, dataNotFoundErr
@@ -412,6 +461,7 @@ func (dynamo *dynamoDB) get(key []byte) ([]byte, error) {
 
 // Delete deletes the key from the queue and database
 func (dynamo *dynamoDB) Delete(key []byte) error {
+	defer dynamo.getCalls.forget(key)
 	params := &dynamodb.DeleteItemInput{
 		TableName: aws.String(dynamo.config.TableName),
 		Key: map[string]*dynamodb.AttributeValue{
@@ -449,73 +499,37 @@ func (dynamo *dynamoDB) NewIterator(prefix []byte, start []byte) Iterator {
 	return nil
 }
 
-func createBatchWriteWorkerPool() {
-	dynamoWriteCh = make(chan *batchWriteWorkerInput, itemChanSize)
-	for i := 0; i < WorkerNum; i++ {
+func createBatchWriteWorkerPool(workerNum int) {
+	if workerNum <= 0 {
+		workerNum = WorkerNum
+	}
+	dynamoWriteCh = make(chan *batchWriteWorkerInput, workerNum*2)
+	for i := 0; i < workerNum; i++ {
 		go createBatchWriteWorker(dynamoWriteCh)
 	}
-	logger.Info("made dynamo batch write workers", "workerNum", WorkerNum)
+	logger.Info("made dynamo batch write workers", "workerNum", workerNum)
 }
 
 func createBatchWriteWorker(writeCh <-chan *batchWriteWorkerInput) {
-	failCount := 0
 	logger.Debug("generate a dynamoDB batchWrite worker")
 
 	for batchInput := range writeCh {
-		batchWriteInput := &dynamodb.BatchWriteItemInput{
-			RequestItems: map[string][]*dynamodb.WriteRequest{},
-		}
-		batchWriteInput.RequestItems[batchInput.tableName] = batchInput.items
-
-		BatchWriteItemOutput, err := dynamoDBClient.BatchWriteItem(batchWriteInput)
-		numUnprocessed := len(BatchWriteItemOutput.UnprocessedItems[batchInput.tableName])
-		for err != nil || numUnprocessed != 0 {
-			if err != nil {
-				// ValidationException occurs when a required parameter is missing, a value is out of range,
-				// or data types mismatch and so on. If this is the case, check if there is a duplicated key,
-				// batch length out of range, null value and so on.
-				// When ValidationException occurs, retrying won't fix the problem.
-				if strings.Contains(err.Error(), "ValidationException") {
-					logger.Crit("Invalid input for dynamoDB BatchWrite",
-						"err", err, "tableName", batchInput.tableName, "itemNum", len(batchInput.items))
-				}
-				failCount++
-				logger.Warn("dynamoDB failed to write batch items",
-					"tableName", batchInput.tableName, "err", err, "failCnt", failCount)
-				if failCount > dynamoMaxRetry {
-					logger.Error("dynamoDB failed many times. sleep a second and retry",
-						"tableName", batchInput.tableName, "failCnt", failCount)
-					time.Sleep(time.Second)
-				}
-			}
-
-			if numUnprocessed != 0 {
-				logger.Debug("dynamoDB batchWrite remains unprocessedItem",
-					"tableName", batchInput.tableName, "numUnprocessedItem", numUnprocessed)
-				batchWriteInput.RequestItems[batchInput.tableName] = BatchWriteItemOutput.UnprocessedItems[batchInput.tableName]
-			}
-
-			start := time.Now()
-			BatchWriteItemOutput, err = dynamoDBClient.BatchWriteItem(batchWriteInput)
-			dynamoBatchWriteTimeMeter.Mark(int64(time.Since(start)))
-			numUnprocessed = len(BatchWriteItemOutput.UnprocessedItems)
-		}
-
-		failCount = 0
+		writeBatchItems(dynamoDBClient, batchInput.tableName, batchInput.items, batchInput.provisioned)
 		batchInput.wg.Done()
 	}
 	logger.Debug("close a dynamoDB batchWrite worker")
 }
 
 func (dynamo *dynamoDB) NewBatch() Batch {
-	return &dynamoBatch{db: dynamo, tableName: dynamo.config.TableName, wg: &sync.WaitGroup{}, keyMap: map[string]struct{}{}}
+	return &dynamoBatch{db: dynamo, tableName: dynamo.config.TableName, wg: &sync.WaitGroup{}, keyMap: map[string]int{}}
 }
 
 type dynamoBatch struct {
 	db         *dynamoDB
 	tableName  string
 	batchItems []*dynamodb.WriteRequest
-	keyMap     map[string]struct{} // checks duplication of keys
+	itemSizes  []int
+	keyMap     map[string]int // index of the item of the key in batchItems
 	size       int
 	wg         *sync.WaitGroup
 }
@@ -524,14 +538,9 @@ type dynamoBatch struct {
 // If the number of items in batch reaches dynamoBatchSize, a write request to dynamoDB is made.
 // Each batch write is executed in thread. (There is an worker pool for dynamo batch write)
 //
-// Note: If there is a duplicated key in a batch, only the first value is written.
+// Note: If a key is put again before its item is sent, the item is replaced
+// not to write the key twice, so the last value is written.
 func (batch *dynamoBatch) Put(key, val []byte) error {
-	// if there is an duplicated key in batch, skip
-	if _, exist := batch.keyMap[string(key)]; exist {
-		return nil
-	}
-	batch.keyMap[string(key)] = struct{}{}
-
 	data := DynamoData{Key: key, Val: val}
 	dataSize := len(val)
 
@@ -564,14 +573,22 @@ func (batch *dynamoBatch) Put(key, val []byte) error {
 		return err
 	}
 
-	batch.batchItems = append(batch.batchItems, &dynamodb.WriteRequest{
-		PutRequest: &dynamodb.PutRequest{Item: marshaledData},
-	})
+	request := &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: marshaledData}}
+	if idx, exist := batch.keyMap[string(key)]; exist {
+		dynamoCoalescedPutCounter.Inc(1)
+		batch.batchItems[idx] = request
+		batch.size += dataSize - batch.itemSizes[idx]
+		batch.itemSizes[idx] = dataSize
+		return nil
+	}
+	batch.keyMap[string(key)] = len(batch.batchItems)
+	batch.batchItems = append(batch.batchItems, request)
+	batch.itemSizes = append(batch.itemSizes, dataSize)
 	batch.size += dataSize
 
 	if len(batch.batchItems) == dynamoBatchSize {
 		batch.wg.Add(1)
-		dynamoWriteCh <- &batchWriteWorkerInput{batch.tableName, batch.batchItems, batch.wg}
+		dynamoWriteCh <- &batchWriteWorkerInput{batch.tableName, batch.batchItems, batch.wg, batch.db.config.IsProvisioned}
 		batch.Reset()
 	}
 	return nil
@@ -595,11 +612,12 @@ func (batch *dynamoBatch) Write() error {
 			writeRequest = batch.batchItems
 		}
 		batch.wg.Add(1)
-		dynamoWriteCh <- &batchWriteWorkerInput{batch.tableName, writeRequest, batch.wg}
+		dynamoWriteCh <- &batchWriteWorkerInput{batch.tableName, writeRequest, batch.wg, batch.db.config.IsProvisioned}
 		numRemainedItems -= len(writeRequest)
 	}
 
 	batch.wg.Wait()
+	batch.db.getCalls.forgetAll()
 	return nil
 }
 
@@ -609,7 +627,8 @@ func (batch *dynamoBatch) ValueSize() int {
 
 func (batch *dynamoBatch) Reset() {
 	batch.batchItems = []*dynamodb.WriteRequest{}
-	batch.keyMap = map[string]struct{}{}
+	batch.itemSizes = []int{}
+	batch.keyMap = map[string]int{}
 	batch.size = 0
 }
 
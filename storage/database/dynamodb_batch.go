@@ -0,0 +1,208 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/klaytn/klaytn/common"
+	"github.com/rcrowley/go-metrics"
+)
+
+const (
+	dynamoBackoffBase = 50 * time.Millisecond // delay before the first retry of a batch write
+	dynamoBackoffMax  = 5 * time.Second       // maximum delay between the retries of a batch write
+)
+
+// The metrics are named after the CloudWatch metrics of DynamoDB, so that they
+// can be compared with the ones reported by AWS.
+var (
+	dynamoConsumedWCUMeter        = metrics.NewRegisteredMeter("klay/db/dynamo/ConsumedWriteCapacityUnits", nil)
+	dynamoConsumedRCUMeter        = metrics.NewRegisteredMeter("klay/db/dynamo/ConsumedReadCapacityUnits", nil)
+	dynamoThrottledRequestCounter = metrics.NewRegisteredCounter("klay/db/dynamo/ThrottledRequests", nil)
+	dynamoUnprocessedItemCounter  = metrics.NewRegisteredCounter("klay/db/dynamo/UnprocessedItems", nil)
+	dynamoBatchWriteRetryCounter  = metrics.NewRegisteredCounter("klay/db/dynamo/BatchWriteRetries", nil)
+	dynamoCoalescedGetCounter     = metrics.NewRegisteredCounter("klay/db/dynamo/CoalescedGets", nil)
+	dynamoCoalescedPutCounter     = metrics.NewRegisteredCounter("klay/db/dynamo/CoalescedPuts", nil)
+	dynamoProvisionedWCUGauge     = metrics.NewRegisteredGauge("klay/db/dynamo/ProvisionedWriteCapacityUnits", nil)
+	dynamoProvisionedRCUGauge     = metrics.NewRegisteredGauge("klay/db/dynamo/ProvisionedReadCapacityUnits", nil)
+)
+
+// dynamoBatchWriter is the part of the DynamoDB client used to write batches.
+type dynamoBatchWriter interface {
+	BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error)
+}
+
+// dynamoBackoff returns the delay before the given retry (starting from 1) of
+// a batch write. The delay doubles on every retry up to dynamoBackoffMax, and
+// is jittered so that the workers throttled at the same time don't retry at
+// the same time.
+func dynamoBackoff(retry int) time.Duration {
+	delay := dynamoBackoffMax
+	if retry < 1 {
+		retry = 1
+	}
+	if shift := uint(retry - 1); shift < 16 && dynamoBackoffBase<<shift < delay {
+		delay = dynamoBackoffBase << shift
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// isDynamoThrottled returns true if the error is caused by exceeding the
+// provisioned throughput or the request rate of the table.
+func isDynamoThrottled(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, dynamodb.ErrCodeProvisionedThroughputExceededException) ||
+		strings.Contains(msg, dynamodb.ErrCodeRequestLimitExceeded) ||
+		strings.Contains(msg, "ThrottlingException")
+}
+
+// markConsumedCapacity reports the capacity units consumed by a request.
+func markConsumedCapacity(meter metrics.Meter, capacities ...*dynamodb.ConsumedCapacity) {
+	var units float64
+	for _, c := range capacities {
+		if c != nil {
+			units += aws.Float64Value(c.CapacityUnits)
+		}
+	}
+	if units > 0 {
+		meter.Mark(int64(math.Ceil(units)))
+	}
+}
+
+// writeBatchItems writes the items with BatchWriteItem until all of them are
+// processed. Failed requests and unprocessed items are retried with exponential
+// backoff instead of immediately, since both are mostly caused by throttling
+// and immediate retries consume the capacity without progress.
+func writeBatchItems(client dynamoBatchWriter, tableName string, items []*dynamodb.WriteRequest, provisioned bool) {
+	input := &dynamodb.BatchWriteItemInput{
+		RequestItems:           map[string][]*dynamodb.WriteRequest{tableName: items},
+		ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityTotal),
+	}
+
+	for retry := 0; ; retry++ {
+		if retry > 0 {
+			dynamoBatchWriteRetryCounter.Inc(1)
+			time.Sleep(dynamoBackoff(retry))
+		}
+
+		start := time.Now()
+		output, err := client.BatchWriteItem(input)
+		dynamoBatchWriteTimeMeter.Mark(int64(time.Since(start)))
+
+		if err != nil {
+			// ValidationException occurs when a required parameter is missing, a value is out of range,
+			// or data types mismatch and so on. If this is the case, check if there is a duplicated key,
+			// batch length out of range, null value and so on.
+			// When ValidationException occurs, retrying won't fix the problem.
+			if strings.Contains(err.Error(), "ValidationException") {
+				logger.Crit("Invalid input for dynamoDB BatchWrite",
+					"err", err, "tableName", tableName, "itemNum", len(input.RequestItems[tableName]))
+			}
+			if isDynamoThrottled(err) {
+				dynamoThrottledRequestCounter.Inc(1)
+				// Throttling is expected when the write rate exceeds the provisioned capacity,
+				// while it means a hot partition on on-demand tables.
+				if provisioned {
+					logger.Debug("dynamoDB batchWrite is throttled", "tableName", tableName, "retry", retry)
+					continue
+				}
+			}
+			logger.Warn("dynamoDB failed to write batch items", "tableName", tableName, "err", err, "retry", retry)
+			continue
+		}
+
+		markConsumedCapacity(dynamoConsumedWCUMeter, output.ConsumedCapacity...)
+		unprocessed := output.UnprocessedItems[tableName]
+		if len(unprocessed) == 0 {
+			return
+		}
+		dynamoUnprocessedItemCounter.Inc(int64(len(unprocessed)))
+		logger.Debug("dynamoDB batchWrite remains unprocessedItem",
+			"tableName", tableName, "numUnprocessedItem", len(unprocessed), "retry", retry)
+		input.RequestItems[tableName] = unprocessed
+	}
+}
+
+type dynamoGetCall struct {
+	wg  sync.WaitGroup
+	val []byte
+	err error
+}
+
+// dynamoGetGroup coalesces the concurrent reads of the same key into a single
+// request, e.g. when several goroutines look up the same trie node. The reads in
+// flight are forgotten on the writes, so that a read started after a write
+// never gets the value read before the write.
+type dynamoGetGroup struct {
+	mu    sync.Mutex
+	calls map[string]*dynamoGetCall
+}
+
+func newDynamoGetGroup() *dynamoGetGroup {
+	return &dynamoGetGroup{calls: make(map[string]*dynamoGetCall)}
+}
+
+// do calls get unless a read of the same key is in flight, in which case it
+// waits for the result of that read.
+func (g *dynamoGetGroup) do(key []byte, get func() ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[string(key)]; ok {
+		g.mu.Unlock()
+		dynamoCoalescedGetCounter.Inc(1)
+		call.wg.Wait()
+		return common.CopyBytes(call.val), call.err
+	}
+	call := new(dynamoGetCall)
+	call.wg.Add(1)
+	g.calls[string(key)] = call
+	g.mu.Unlock()
+
+	val, err := get()
+	// The waiters copy the value after the read, so they are given a separate
+	// copy not to race with the caller modifying the returned value.
+	call.val, call.err = common.CopyBytes(val), err
+	call.wg.Done()
+
+	g.mu.Lock()
+	if g.calls[string(key)] == call {
+		delete(g.calls, string(key))
+	}
+	g.mu.Unlock()
+
+	return val, err
+}
+
+// forget makes the later reads of the key not wait for the read in flight.
+func (g *dynamoGetGroup) forget(key []byte) {
+	g.mu.Lock()
+	delete(g.calls, string(key))
+	g.mu.Unlock()
+}
+
+// forgetAll makes the later reads not wait for any read in flight.
+func (g *dynamoGetGroup) forgetAll() {
+	g.mu.Lock()
+	g.calls = make(map[string]*dynamoGetCall)
+	g.mu.Unlock()
+}
@@ -0,0 +1,186 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamoBackoff(t *testing.T) {
+	for retry := 1; retry < 100; retry++ {
+		delay := dynamoBackoff(retry)
+
+		expected := dynamoBackoffMax
+		if retry < 8 && dynamoBackoffBase<<uint(retry-1) < expected {
+			expected = dynamoBackoffBase << uint(retry-1)
+		}
+		assert.True(t, delay >= expected/2 && delay <= expected, "retry %d: delay %v out of [%v, %v]", retry, delay, expected/2, expected)
+	}
+}
+
+// testBatchWriter returns the results in order, and records the number of
+// items in each request.
+type testBatchWriter struct {
+	results  []func(items []*dynamodb.WriteRequest) (*dynamodb.BatchWriteItemOutput, error)
+	requests []int
+}
+
+func (w *testBatchWriter) BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+	items := input.RequestItems["table"]
+	w.requests = append(w.requests, len(items))
+	result := w.results[0]
+	w.results = w.results[1:]
+	return result(items)
+}
+
+func TestWriteBatchItems_Retry(t *testing.T) {
+	items := make([]*dynamodb.WriteRequest, dynamoBatchSize)
+	for i := range items {
+		items[i] = &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{}}
+	}
+
+	writer := &testBatchWriter{results: []func([]*dynamodb.WriteRequest) (*dynamodb.BatchWriteItemOutput, error){
+		// throttled request
+		func([]*dynamodb.WriteRequest) (*dynamodb.BatchWriteItemOutput, error) {
+			return nil, errors.New(dynamodb.ErrCodeProvisionedThroughputExceededException)
+		},
+		// the last 5 items are unprocessed
+		func(items []*dynamodb.WriteRequest) (*dynamodb.BatchWriteItemOutput, error) {
+			return &dynamodb.BatchWriteItemOutput{
+				UnprocessedItems: map[string][]*dynamodb.WriteRequest{"table": items[20:]},
+				ConsumedCapacity: []*dynamodb.ConsumedCapacity{{CapacityUnits: aws.Float64(20)}},
+			}, nil
+		},
+		// all items are processed
+		func([]*dynamodb.WriteRequest) (*dynamodb.BatchWriteItemOutput, error) {
+			return &dynamodb.BatchWriteItemOutput{}, nil
+		},
+	}}
+
+	throttled := dynamoThrottledRequestCounter.Count()
+	unprocessed := dynamoUnprocessedItemCounter.Count()
+
+	writeBatchItems(writer, "table", items, true)
+
+	assert.Equal(t, []int{25, 25, 5}, writer.requests)
+	assert.Equal(t, int64(1), dynamoThrottledRequestCounter.Count()-throttled)
+	assert.Equal(t, int64(5), dynamoUnprocessedItemCounter.Count()-unprocessed)
+}
+
+func TestDynamoGetGroup_Coalesce(t *testing.T) {
+	group := newDynamoGetGroup()
+
+	var (
+		calls   int32
+		release = make(chan struct{})
+		started = make(chan struct{})
+	)
+	get := func() ([]byte, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+		}
+		<-release
+		return []byte("value"), nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([][]byte, 5)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _ = group.do([]byte("key"), get)
+	}()
+	<-started
+
+	for i := 1; i < len(results); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = group.do([]byte("key"), get)
+		}(i)
+	}
+	// Wait for the readers to join the call in flight.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for _, result := range results {
+		assert.Equal(t, []byte("value"), result)
+	}
+
+	// The coalesced readers get separate copies of the value.
+	results[1][0] = 'x'
+	assert.Equal(t, []byte("value"), results[0])
+
+	// A later read is not coalesced with the finished one.
+	_, _ = group.do([]byte("key"), func() ([]byte, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, dataNotFoundErr
+	})
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestDynamoGetGroup_Forget(t *testing.T) {
+	group := newDynamoGetGroup()
+
+	release, started := make(chan struct{}), make(chan struct{})
+	done := make(chan []byte)
+	go func() {
+		val, _ := group.do([]byte("key"), func() ([]byte, error) {
+			close(started)
+			<-release
+			return []byte("old"), nil
+		})
+		done <- val
+	}()
+	<-started
+
+	// A read after the write doesn't wait for the read started before the write.
+	group.forget([]byte("key"))
+	val, _ := group.do([]byte("key"), func() ([]byte, error) {
+		return []byte("new"), nil
+	})
+	assert.Equal(t, []byte("new"), val)
+
+	close(release)
+	assert.Equal(t, []byte("old"), <-done)
+}
+
+func TestDynamoBatch_CoalescePut(t *testing.T) {
+	batch := (&dynamoDB{config: DynamoDBConfig{TableName: "table"}}).NewBatch().(*dynamoBatch)
+	coalesced := dynamoCoalescedPutCounter.Count()
+
+	assert.NoError(t, batch.Put([]byte("key1"), []byte("old value")))
+	assert.NoError(t, batch.Put([]byte("key2"), []byte("value")))
+	assert.NoError(t, batch.Put([]byte("key1"), []byte("new")))
+
+	// The key is written once with the last value.
+	assert.Len(t, batch.batchItems, 2)
+	assert.Equal(t, []byte("new"), batch.batchItems[0].PutRequest.Item["Val"].B)
+	assert.Equal(t, len("new")+len("value"), batch.ValueSize())
+	assert.Equal(t, int64(1), dynamoCoalescedPutCounter.Count()-coalesced)
+}
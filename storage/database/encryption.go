@@ -0,0 +1,191 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+// EncryptionKeySize is the size of the key encrypting the databases, for AES-256.
+const EncryptionKeySize = 32
+
+var (
+	errInvalidEncryptionKey   = errors.New("invalid database encryption key")
+	errWrongEncryptionKey     = errors.New("the database is encrypted with another key")
+	errEncryptionKeyRequired  = errors.New("the database is encrypted, but no encryption key is given")
+	errEncryptionNotSupported = errors.New("the encryption of an existing database can not be enabled, migrate the database instead")
+)
+
+// encryptionCheckValue is stored encrypted in the misc database, to check the
+// key when the databases are opened.
+var encryptionCheckValue = []byte("klaytn-database-encryption")
+
+// LoadEncryptionKey loads the key encrypting the databases from the given
+// source, which is one of:
+//   - file:<path>  a file containing the hex encoded key
+//   - env:<name>   an environment variable containing the hex encoded key
+//   - kms:<path>   a file containing the key encrypted by AWS KMS
+//
+// The key encrypted by AWS KMS is decrypted with the credentials and the region
+// of the environment.
+func LoadEncryptionKey(source string) ([]byte, error) {
+	kindValue := strings.SplitN(source, ":", 2)
+	if len(kindValue) != 2 || kindValue[1] == "" {
+		return nil, fmt.Errorf("%w: invalid key source %q", errInvalidEncryptionKey, source)
+	}
+
+	var (
+		key []byte
+		err error
+	)
+	switch kindValue[0] {
+	case "file":
+		var enc []byte
+		if enc, err = ioutil.ReadFile(kindValue[1]); err == nil {
+			key, err = hex.DecodeString(strings.TrimSpace(string(enc)))
+		}
+	case "env":
+		enc, ok := os.LookupEnv(kindValue[1])
+		if !ok {
+			return nil, fmt.Errorf("%w: environment variable %s is not set", errInvalidEncryptionKey, kindValue[1])
+		}
+		key, err = hex.DecodeString(strings.TrimSpace(enc))
+	case "kms":
+		key, err = decryptKMSKey(kindValue[1])
+	default:
+		return nil, fmt.Errorf("%w: unknown key source %q", errInvalidEncryptionKey, kindValue[0])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidEncryptionKey, err)
+	}
+	if len(key) != EncryptionKeySize {
+		return nil, fmt.Errorf("%w: the key should be %d bytes, but %d bytes", errInvalidEncryptionKey, EncryptionKeySize, len(key))
+	}
+	return key, nil
+}
+
+// decryptKMSKey decrypts the key in the given file by AWS KMS.
+func decryptKMSKey(path string) ([]byte, error) {
+	blob, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{SharedConfigState: session.SharedConfigEnable})
+	if err != nil {
+		return nil, err
+	}
+	out, err := kms.New(sess).Decrypt(&kms.DecryptInput{CiphertextBlob: blob})
+	if err != nil {
+		return nil, err
+	}
+	return out.Plaintext, nil
+}
+
+// aesGCMCipher encrypts each value with AES-GCM and a random nonce, which is
+// prepended to the encrypted value. It transforms the values as the value
+// compression does, so the databases are wrapped by compressedDB.
+type aesGCMCipher struct {
+	aead cipher.AEAD
+}
+
+func newAESGCMCipher(key []byte) (*aesGCMCipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &aesGCMCipher{aead: aead}, nil
+}
+
+func (c *aesGCMCipher) compress(value []byte) []byte {
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(value)+c.aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		logger.Crit("Failed to generate a nonce for the database encryption", "err", err)
+	}
+	return c.aead.Seal(nonce, nonce, value, nil)
+}
+
+func (c *aesGCMCipher) decompress(value []byte) ([]byte, error) {
+	if len(value) < c.aead.NonceSize() {
+		return nil, errWrongEncryptionKey
+	}
+	nonce, sealed := value[:c.aead.NonceSize()], value[c.aead.NonceSize():]
+	return c.aead.Open(nil, nonce, sealed, nil)
+}
+
+// withEncryption wraps the database to encrypt the values with the configured
+// key, if any. The keys are not encrypted to keep their order.
+func withEncryption(db Database, dbc *DBConfig) (Database, error) {
+	if len(dbc.EncryptionKey) == 0 {
+		return db, nil
+	}
+	c, err := newAESGCMCipher(dbc.EncryptionKey)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &compressedDB{Database: db, c: c}, nil
+}
+
+// checkEncryption checks that the databases have been encrypted with the
+// configured key, by decrypting the check value stored in the misc database. The
+// check value is stored when the misc database is empty.
+func (dbm *databaseManager) checkEncryption() error {
+	miscDB := dbm.dbs[MiscDB]
+	rawDB := miscDB
+	if cdb, ok := miscDB.(*compressedDB); ok {
+		rawDB = cdb.Database
+	}
+
+	stored, _ := rawDB.Has(databaseEncryptionKey)
+	switch {
+	case stored && len(dbm.config.EncryptionKey) == 0:
+		return errEncryptionKeyRequired
+	case stored:
+		value, err := miscDB.Get(databaseEncryptionKey)
+		if err != nil || !bytes.Equal(value, encryptionCheckValue) {
+			return errWrongEncryptionKey
+		}
+		return nil
+	case len(dbm.config.EncryptionKey) == 0:
+		return nil
+	}
+
+	it := rawDB.NewIterator(nil, nil)
+	empty := !it.Next()
+	it.Release()
+	if !empty {
+		return errEncryptionNotSupported
+	}
+	return miscDB.Put(databaseEncryptionKey, encryptionCheckValue)
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testEncryptionKeyHex = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+func TestLoadEncryptionKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klaytn-test-db-encryption-key")
+	if err != nil {
+		t.Fatalf("cannot create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	keyFile := filepath.Join(dir, "key")
+	assert.NoError(t, ioutil.WriteFile(keyFile, []byte(testEncryptionKeyHex+"\n"), 0o600))
+	key, err := LoadEncryptionKey("file:" + keyFile)
+	assert.NoError(t, err)
+	assert.Len(t, key, EncryptionKeySize)
+
+	os.Setenv("KLAYTN_TEST_DB_ENCRYPTION_KEY", testEncryptionKeyHex)
+	defer os.Unsetenv("KLAYTN_TEST_DB_ENCRYPTION_KEY")
+	envKey, err := LoadEncryptionKey("env:KLAYTN_TEST_DB_ENCRYPTION_KEY")
+	assert.NoError(t, err)
+	assert.Equal(t, key, envKey)
+
+	for _, invalid := range []string{"", "file:", "unknown:key", "env:KLAYTN_TEST_DB_ENCRYPTION_KEY_NOT_SET", "file:" + filepath.Join(dir, "none")} {
+		_, err := LoadEncryptionKey(invalid)
+		assert.True(t, errors.Is(err, errInvalidEncryptionKey), invalid)
+	}
+
+	// The key should be 32 bytes.
+	os.Setenv("KLAYTN_TEST_DB_ENCRYPTION_KEY", testEncryptionKeyHex[:32])
+	_, err = LoadEncryptionKey("env:KLAYTN_TEST_DB_ENCRYPTION_KEY")
+	assert.True(t, errors.Is(err, errInvalidEncryptionKey))
+}
+
+func TestAESGCMCipher(t *testing.T) {
+	c, err := newAESGCMCipher(bytes.Repeat([]byte{0x01}, EncryptionKeySize))
+	assert.NoError(t, err)
+
+	value := []byte("value")
+	enc1, enc2 := c.compress(value), c.compress(value)
+	assert.NotEqual(t, enc1, enc2) // Random nonces
+
+	dec, err := c.decompress(enc1)
+	assert.NoError(t, err)
+	assert.Equal(t, value, dec)
+
+	other, _ := newAESGCMCipher(bytes.Repeat([]byte{0x02}, EncryptionKeySize))
+	_, err = other.decompress(enc1)
+	assert.Error(t, err)
+}
+
+func TestDBManager_Encryption(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klaytn-test-db-encryption")
+	if err != nil {
+		t.Fatalf("cannot create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	key := bytes.Repeat([]byte{0x01}, EncryptionKeySize)
+	dbc := &DBConfig{Dir: dir, DBType: LevelDB, LevelDBCacheSize: 32, OpenFilesLimit: 32, EncryptionKey: key}
+	dbm, err := databaseDBManager(dbc)
+	assert.NoError(t, err)
+	assert.NoError(t, dbm.getDatabase(headerDB).Put([]byte("key"), []byte("value")))
+	value, err := dbm.getDatabase(headerDB).Get([]byte("key"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+	dbm.Close()
+
+	// The value is encrypted on disk.
+	rawDB, err := NewLevelDB(&DBConfig{Dir: filepath.Join(dir, dbBaseDirs[headerDB])}, headerDB)
+	assert.NoError(t, err)
+	raw, err := rawDB.Get([]byte("key"))
+	assert.NoError(t, err)
+	assert.False(t, bytes.Contains(raw, []byte("value")))
+	rawDB.Close()
+
+	_, err = databaseDBManager(&DBConfig{Dir: dir, DBType: LevelDB, LevelDBCacheSize: 32, OpenFilesLimit: 32})
+	assert.True(t, errors.Is(err, errEncryptionKeyRequired))
+
+	wrongKey := bytes.Repeat([]byte{0x02}, EncryptionKeySize)
+	_, err = databaseDBManager(&DBConfig{Dir: dir, DBType: LevelDB, LevelDBCacheSize: 32, OpenFilesLimit: 32, EncryptionKey: wrongKey})
+	assert.True(t, errors.Is(err, errWrongEncryptionKey))
+
+	dbm, err = databaseDBManager(dbc)
+	assert.NoError(t, err)
+	value, err = dbm.getDatabase(headerDB).Get([]byte("key"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+	dbm.Close()
+}
+
+func TestDBManager_Encryption_ExistingDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "klaytn-test-db-encryption-existing")
+	if err != nil {
+		t.Fatalf("cannot create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	dbc := &DBConfig{Dir: dir, DBType: LevelDB, LevelDBCacheSize: 32, OpenFilesLimit: 32}
+	dbm, err := databaseDBManager(dbc)
+	assert.NoError(t, err)
+	dbm.Close()
+
+	dbc.EncryptionKey = bytes.Repeat([]byte{0x01}, EncryptionKeySize)
+	_, err = databaseDBManager(dbc)
+	assert.True(t, errors.Is(err, errEncryptionNotSupported))
+}
@@ -123,11 +123,12 @@ func WriteBatchesParallel(batches ...Batch) (int, error) {
 	return bytes, nil
 }
 
+// WriteBatchesOverThreshold writes the batches over the adaptive batch size.
 func WriteBatchesOverThreshold(batches ...Batch) (int, error) {
 	bytes := 0
 	for _, batch := range batches {
-		if batch.ValueSize() >= IdealBatchSize {
-			if err := batch.Write(); err != nil {
+		if batch.ValueSize() >= AdaptiveBatchSize() {
+			if err := WriteAdaptiveBatch(batch); err != nil {
 				return 0, err
 			}
 			bytes += batch.ValueSize()
@@ -137,13 +138,15 @@ func WriteBatchesOverThreshold(batches ...Batch) (int, error) {
 	return bytes, nil
 }
 
+// PutAndWriteBatchesOverThreshold puts the entry to the batch, and writes the
+// batch if it is over the adaptive batch size.
 func PutAndWriteBatchesOverThreshold(batch Batch, key, val []byte) error {
 	if err := batch.Put(key, val); err != nil {
 		return err
 	}
 
-	if batch.ValueSize() >= IdealBatchSize {
-		if err := batch.Write(); err != nil {
+	if batch.ValueSize() >= AdaptiveBatchSize() {
+		if err := WriteAdaptiveBatch(batch); err != nil {
 			return err
 		}
 		batch.Reset()
@@ -159,7 +159,7 @@ func NewLevelDB(dbc *DBConfig, entryType DBEntryType) (*levelDB, error) {
 	}
 
 	ldbOpts := getLevelDBOptions(dbc)
-	ldbOpts.Compression = getCompressionType(dbc.LevelDBCompression, entryType)
+	ldbOpts.Compression = getBlockCompression(dbc, entryType)
 
 	localLogger.Info("LevelDB configurations",
 		"levelDBCacheSize", (ldbOpts.WriteBuffer+ldbOpts.BlockCacheCapacity)/opt.MiB, "openFilesLimit", ldbOpts.OpenFilesCacheCapacity,
@@ -344,6 +344,37 @@ func (db *levelDB) LDB() *leveldb.DB {
 	return db.db
 }
 
+// backendStats returns the size of the level 0 tables as the pending compaction.
+func (db *levelDB) backendStats() (backendStats, error) {
+	var s leveldb.DBStats
+	if err := db.db.Stats(&s); err != nil {
+		return backendStats{}, err
+	}
+	var pending uint64
+	if len(s.LevelSizes) > 0 {
+		pending = uint64(s.LevelSizes[0])
+	}
+	return backendStats{
+		pendingCompaction: pending,
+		writeStalls:       uint64(s.WriteDelayCount),
+		writeStallTime:    s.WriteDelayDuration,
+	}, nil
+}
+
+func (db *levelDB) approximateSize(start, limit []byte) (uint64, error) {
+	sizes, err := db.db.SizeOf([]util.Range{{Start: start, Limit: limit}})
+	if err != nil {
+		return 0, err
+	}
+	return uint64(sizes.Sum()), nil
+}
+
+// sync flushes the journal to the stable storage, by deleting the sync marker
+// with the sync option. The journal is synced with all the preceding writes.
+func (db *levelDB) sync() error {
+	return db.db.Delete(syncMarkerKey, &opt.WriteOptions{Sync: true})
+}
+
 // Meter configures the database metrics collectors and
 func (db *levelDB) Meter(prefix string) {
 	db.prefix = prefix
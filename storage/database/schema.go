@@ -45,6 +45,9 @@ var (
 	// fastTrieProgressKey tracks the number of trie entries imported during fast sync.
 	fastTrieProgressKey = []byte("TrieSync")
 
+	// fastSyncStatusKey tracks the pivot and origin of an interrupted fast sync across restarts.
+	fastSyncStatusKey = []byte("FastSyncStatus")
+
 	validSectionKey = []byte("count")
 
 	sectionHeadKeyPrefix = []byte("shead")
@@ -90,6 +93,8 @@ var (
 	preimagePrefix = []byte("secure-key-")  // preimagePrefix + hash -> preimage
 	configPrefix   = []byte("klay-config-") // config prefix for the db
 
+	trieNodeRefCountPrefix = []byte("trieNodeRef-") // trieNodeRefCountPrefix + hash -> reference count (uint64 big endian)
+
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
 
@@ -118,8 +123,20 @@ var (
 	databaseDirPrefix  = []byte("databaseDirectory")
 	migrationStatusKey = []byte("migrationStatus")
 
+	databaseShardsPrefix = []byte("databaseShards")  // databaseShardsPrefix + dir -> number of shards (uint64 big endian)
+	databaseCodecPrefix  = []byte("databaseCodec")   // databaseCodecPrefix + dir -> codec compressing the values
+	reshardProgressKey   = []byte("reshardProgress") // number of shards (uint64 big endian) + last copied key
+
+	// databaseEncryptionKey stores a check value encrypted with the key encrypting the databases.
+	databaseEncryptionKey = []byte("databaseEncryption")
+
+	// trieNodeRefRangeKey tracks the range of blocks whose state roots are reference counted.
+	trieNodeRefRangeKey = []byte("TrieNodeRefRange")
+
 	stakingInfoPrefix = []byte("stakingInfo")
 
+	councilHistoryPrefix = []byte("istanbul-council-history") // councilHistoryPrefix + num (uint64 big endian) + hash -> council changes
+
 	chaindatafetcherCheckpointKey = []byte("chaindatafetcherCheckpoint")
 )
 
@@ -256,6 +273,26 @@ func makeKey(prefix []byte, num uint64) []byte {
 	return append(prefix, byteKey...)
 }
 
+// councilHistoryKey = councilHistoryPrefix + num (uint64 big endian) + hash
+func councilHistoryKey(number uint64, hash common.Hash) []byte {
+	return append(append(councilHistoryPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
 func databaseDirKey(dbEntryType uint64) []byte {
 	return append(databaseDirPrefix, common.Int64ToByteBigEndian(dbEntryType)...)
 }
+
+// TrieNodeRefCountKey = trieNodeRefCountPrefix + hash
+func TrieNodeRefCountKey(hash common.Hash) []byte {
+	return append(append([]byte{}, trieNodeRefCountPrefix...), hash.Bytes()...)
+}
+
+// databaseCodecKey = databaseCodecPrefix + dir
+func databaseCodecKey(dir string) []byte {
+	return append(append([]byte{}, databaseCodecPrefix...), dir...)
+}
+
+// databaseShardsKey = databaseShardsPrefix + dir
+func databaseShardsKey(dir string) []byte {
+	return append(append([]byte{}, databaseShardsPrefix...), dir...)
+}
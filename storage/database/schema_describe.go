@@ -0,0 +1,191 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/rlp"
+)
+
+// SchemaEntry is the meaning of a key and its value in the database schema,
+// which is used to inspect the databases.
+type SchemaEntry struct {
+	Kind   string       `json:"kind"`
+	Number *uint64      `json:"number,omitempty"`
+	Hash   *common.Hash `json:"hash,omitempty"`
+	Name   string       `json:"name,omitempty"`  // Name embedded in the key, such as a database directory
+	Value  interface{}  `json:"value,omitempty"` // Decoded value, or the raw value if its format is not known
+}
+
+// valueDecoder decodes a value of the database schema.
+type valueDecoder func(value []byte) (interface{}, error)
+
+func decodeRaw(value []byte) (interface{}, error) {
+	return hexutil.Bytes(value), nil
+}
+
+func decodeHash(value []byte) (interface{}, error) {
+	return common.BytesToHash(value), nil
+}
+
+func decodeString(value []byte) (interface{}, error) {
+	return string(value), nil
+}
+
+func decodeUint64(value []byte) (interface{}, error) {
+	if len(value) != 8 {
+		return hexutil.Bytes(value), nil
+	}
+	return binary.BigEndian.Uint64(value), nil
+}
+
+func decodeBigInt(value []byte) (interface{}, error) {
+	return new(big.Int).SetBytes(value).Uint64(), nil
+}
+
+func decodeJSON(value []byte) (interface{}, error) {
+	return json.RawMessage(value), nil
+}
+
+// decodeRLP returns a decoder of the RLP encoded values of the given type.
+func decodeRLP(newValue func() interface{}) valueDecoder {
+	return func(value []byte) (interface{}, error) {
+		v := newValue()
+		if err := rlp.DecodeBytes(value, v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
+// schemaKeys are the keys having a fixed value.
+var schemaKeys = []struct {
+	key    []byte
+	kind   string
+	decode valueDecoder
+}{
+	{databaseVerisionKey, "database-version", decodeRLP(func() interface{} { return new(uint64) })},
+	{headHeaderKey, "head-header-hash", decodeHash},
+	{headBlockKey, "head-block-hash", decodeHash},
+	{headFastBlockKey, "head-fast-block-hash", decodeHash},
+	{fastTrieProgressKey, "fast-trie-progress", decodeBigInt},
+	{snapshotRootKey, "snapshot-root", decodeHash},
+	{badBlockKey, "bad-blocks", decodeRaw},
+	{migrationStatusKey, "migration-status", decodeUint64},
+	{trieNodeRefRangeKey, "trie-node-ref-range", decodeRaw},
+	{databaseEncryptionKey, "database-encryption-check", decodeRaw},
+	{coldStorageProgressKey, "cold-storage-progress", decodeUint64},
+	{governanceHistoryKey, "governance-history", decodeJSON},
+	{governanceStateKey, "governance-state", decodeJSON},
+}
+
+// schemaPrefixes are the keys made of a prefix and a fixed length suffix. The
+// suffix of the variable length is denoted by -1.
+var schemaPrefixes = []struct {
+	prefix []byte
+	length int // Length of the key after the prefix
+	kind   string
+	decode valueDecoder
+	parse  func(e *SchemaEntry, suffix []byte)
+}{
+	{trieNodeRefCountPrefix, common.HashLength, "trie-node-refcount", decodeUint64, parseHash},
+	{preimagePrefix, common.HashLength, "preimage", decodeRaw, parseHash},
+	{configPrefix, common.HashLength, "chain-config", decodeJSON, parseHash},
+	{databaseDirPrefix, 8, "database-dir", decodeString, parseNumber},
+	{databaseShardsPrefix, -1, "database-shards", decodeUint64, parseName},
+	{databaseCodecPrefix, -1, "database-codec", decodeString, parseName},
+	{headerPrefix, 8 + common.HashLength, "header", decodeRLP(func() interface{} { return new(types.Header) }), parseNumberHash},
+	{headerPrefix, 8 + common.HashLength + len(headerTDSuffix), "total-difficulty", decodeRLP(func() interface{} { return new(big.Int) }), parseNumberHash},
+	{headerPrefix, 8 + len(headerHashSuffix), "canonical-hash", decodeHash, parseNumber},
+	{headerNumberPrefix, common.HashLength, "header-number", decodeUint64, parseHash},
+	{blockBodyPrefix, 8 + common.HashLength, "body", decodeRLP(func() interface{} { return new(types.Body) }), parseNumberHash},
+	{blockReceiptsPrefix, 8 + common.HashLength, "receipts", decodeRLP(func() interface{} { return new([]*types.ReceiptForStorage) }), parseNumberHash},
+	{txLookupPrefix, common.HashLength, "tx-lookup", decodeRLP(func() interface{} { return new(TxLookupEntry) }), parseHash},
+	{codePrefix, common.HashLength, "code", decodeRaw, parseHash},
+	{SnapshotAccountPrefix, common.HashLength, "snapshot-account", decodeRaw, parseHash},
+	{SnapshotStoragePrefix, 2 * common.HashLength, "snapshot-storage", decodeRaw, parseHash},
+	{councilHistoryPrefix, 8 + common.HashLength, "council-history", decodeJSON, parseNumberHash},
+}
+
+func parseHash(e *SchemaEntry, suffix []byte) {
+	hash := common.BytesToHash(suffix[:common.HashLength])
+	e.Hash = &hash
+}
+
+func parseNumber(e *SchemaEntry, suffix []byte) {
+	number := binary.BigEndian.Uint64(suffix[:8])
+	e.Number = &number
+}
+
+func parseNumberHash(e *SchemaEntry, suffix []byte) {
+	parseNumber(e, suffix)
+	parseHash(e, suffix[8:])
+}
+
+func parseName(e *SchemaEntry, suffix []byte) {
+	e.Name = string(suffix)
+}
+
+// DescribeEntry interprets the given key by the database schema, and decodes
+// the given value if it is not nil. It returns false if the key is not known.
+// A key of 32 bytes is regarded as a trie node, which is stored by its hash.
+func DescribeEntry(key, value []byte) (*SchemaEntry, bool) {
+	e, decode := describeKey(key)
+	if e == nil {
+		return nil, false
+	}
+	if value != nil {
+		if decoded, err := decode(value); err == nil {
+			e.Value = decoded
+		} else {
+			e.Value = hexutil.Bytes(value)
+		}
+	}
+	return e, true
+}
+
+func describeKey(key []byte) (*SchemaEntry, valueDecoder) {
+	for _, k := range schemaKeys {
+		if bytes.Equal(key, k.key) {
+			return &SchemaEntry{Kind: k.kind}, k.decode
+		}
+	}
+	for _, p := range schemaPrefixes {
+		if !bytes.HasPrefix(key, p.prefix) {
+			continue
+		}
+		suffix := key[len(p.prefix):]
+		if p.length >= 0 && len(suffix) != p.length {
+			continue
+		}
+		e := &SchemaEntry{Kind: p.kind}
+		p.parse(e, suffix)
+		return e, p.decode
+	}
+	if len(key) == common.HashLength {
+		hash := common.BytesToHash(key)
+		return &SchemaEntry{Kind: "trie-node", Hash: &hash}, decodeRaw
+	}
+	return nil, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribeEntry(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(10), BlockScore: big.NewInt(1)}
+	hash := header.Hash()
+	enc, err := rlp.EncodeToBytes(header)
+	assert.NoError(t, err)
+
+	entry, ok := DescribeEntry(headerKey(10, hash), enc)
+	assert.True(t, ok)
+	assert.Equal(t, "header", entry.Kind)
+	assert.Equal(t, uint64(10), *entry.Number)
+	assert.Equal(t, hash, *entry.Hash)
+	assert.Equal(t, hash, entry.Value.(*types.Header).Hash())
+
+	entry, ok = DescribeEntry(headerHashKey(10), hash[:])
+	assert.True(t, ok)
+	assert.Equal(t, "canonical-hash", entry.Kind)
+	assert.Equal(t, uint64(10), *entry.Number)
+	assert.Equal(t, hash, entry.Value)
+
+	entry, ok = DescribeEntry(headHeaderKey, nil)
+	assert.True(t, ok)
+	assert.Equal(t, "head-header-hash", entry.Kind)
+	assert.Nil(t, entry.Value)
+
+	entry, ok = DescribeEntry(databaseShardsKey("statetrie"), common.Int64ToByteBigEndian(4))
+	assert.True(t, ok)
+	assert.Equal(t, "database-shards", entry.Kind)
+	assert.Equal(t, "statetrie", entry.Name)
+	assert.Equal(t, uint64(4), entry.Value)
+
+	// A malformed value is printed as it is.
+	entry, ok = DescribeEntry(blockBodyKey(10, hash), []byte{0x01, 0x02})
+	assert.True(t, ok)
+	assert.Equal(t, "body", entry.Kind)
+	assert.Equal(t, hexutil.Bytes{0x01, 0x02}, entry.Value)
+
+	entry, ok = DescribeEntry(hash[:], []byte{0x01})
+	assert.True(t, ok)
+	assert.Equal(t, "trie-node", entry.Kind)
+	assert.Equal(t, hash, *entry.Hash)
+
+	_, ok = DescribeEntry([]byte("unknown"), nil)
+	assert.False(t, ok)
+}
@@ -0,0 +1,39 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+package database
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZstdCompressor(t *testing.T) {
+	c, err := newZstdCompressor(DefaultZstdLevel)
+	assert.NoError(t, err)
+
+	value := bytes.Repeat([]byte("klaytn"), 1000)
+	compressed := c.compress(value)
+	assert.True(t, len(compressed) < len(value))
+
+	decompressed, err := c.decompress(compressed)
+	assert.NoError(t, err)
+	assert.Equal(t, value, decompressed)
+
+	_, err = c.decompress(value)
+	assert.Error(t, err)
+}
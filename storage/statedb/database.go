@@ -66,6 +66,13 @@ var (
 	memcacheNodesGauge = metrics.NewRegisteredGauge("trie/memcache/nodes", nil)
 )
 
+func init() {
+	// The trie node cache does not report its evictions.
+	common.RegisterCacheStats("trienode", func() common.CacheStats {
+		return common.NewCacheStats(memcacheCleanHitMeter.Count(), memcacheCleanMissMeter.Count(), 0)
+	})
+}
+
 // secureKeyPrefix is the database key prefix used to store trie node preimages.
 var secureKeyPrefix = []byte("secure-key-")
 
@@ -116,6 +123,9 @@ type Database struct {
 
 	lock sync.RWMutex
 
+	refLock  sync.Mutex   // Lock for the persistent reference counts, held during the commit
+	leafRefs LeafRefsFunc // Resolves the tries referenced by leaves if reference counting is enabled
+
 	trieNodeCache                TrieNodeCache        // GC friendly memory cache of trie node RLPs
 	trieNodeCacheConfig          *TrieNodeCacheConfig // Configuration of trieNodeCache
 	savingTrieNodeCacheTriggered bool                 // Whether saving trie node cache has been triggered or not
@@ -892,8 +902,8 @@ func (db *Database) writeBatchNodes(node common.Hash) error {
 		if err := batch.Put(result.key, result.val); err != nil {
 			return err
 		}
-		if batch.ValueSize() > database.IdealBatchSize {
-			if err := batch.Write(); err != nil {
+		if batch.ValueSize() > database.AdaptiveBatchSize() {
+			if err := database.WriteAdaptiveBatch(batch); err != nil {
 				return err
 			}
 			batch.Reset()
@@ -927,6 +937,11 @@ func (db *Database) concurrentCommit(hash common.Hash, resultCh chan<- commitRes
 //
 // As a side effect, all pre-images accumulated up to this point are also written.
 func (db *Database) Commit(node common.Hash, report bool, blockNum uint64) error {
+	// The reference counting of the committed nodes should not be interleaved with
+	// the deletion of the nodes not referenced, which may have the same hashes.
+	db.refLock.Lock()
+	defer db.refLock.Unlock()
+
 	// Create a database batch to flush persistent data out. It is important that
 	// outside code doesn't see an inconsistent state (referenced data removed from
 	// memory cache during commit but not yet in persistent database). This is ensured
@@ -948,6 +963,13 @@ func (db *Database) Commit(node common.Hash, report bool, blockNum uint64) error
 
 	db.lock.RUnlock()
 
+	if db.leafRefs != nil {
+		if err := db.referenceRoot(node); err != nil {
+			logger.Error("Failed to reference the committed trie", "root", node, "err", err)
+			return err
+		}
+	}
+
 	// Write successful, clear out the flushed data
 	db.lock.Lock()
 	defer db.lock.Unlock()
@@ -0,0 +1,240 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package statedb
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+// Trie nodes are stored by their hashes, so the identical subtries of the tries
+// of different blocks are stored only once. The persistent reference count of a
+// trie node is the number of the stored parent nodes and the referenced roots
+// pointing to it. It allows deleting the nodes which are not reachable from any
+// referenced root anymore, without iterating the whole database.
+//
+// A trie node is counted only once when it is stored for the first time, when
+// its children are counted as well. Thus the counting is proportional to the
+// number of newly stored nodes, not to the size of the trie.
+
+var ErrRefCountDisabled = errors.New("trie node reference counting is disabled")
+
+// LeafRefsFunc returns the roots of the tries referenced by a leaf value of a
+// trie, such as the storage trie root of an account. The leaves of the
+// referenced tries are not examined.
+type LeafRefsFunc func(leaf []byte) []common.Hash
+
+// EnableRefCount makes Commit maintain the persistent reference counts of the
+// committed trie nodes. The committed root is referenced once per Commit.
+func (db *Database) EnableRefCount(leafRefs LeafRefsFunc) {
+	db.refLock.Lock()
+	defer db.refLock.Unlock()
+
+	db.leafRefs = leafRefs
+}
+
+// ReferenceRoot increments the persistent reference count of the given root.
+// If the root is newly referenced, its children are referenced recursively.
+// The trie must be already stored in the persistent database.
+func (db *Database) ReferenceRoot(root common.Hash) error {
+	db.refLock.Lock()
+	defer db.refLock.Unlock()
+
+	return db.referenceRoot(root)
+}
+
+func (db *Database) referenceRoot(root common.Hash) error {
+	if db.leafRefs == nil {
+		return ErrRefCountDisabled
+	}
+	batch := newRefCountBatch(db.diskDB)
+	if err := batch.reference(root, db.leafRefs); err != nil {
+		return err
+	}
+	return batch.write()
+}
+
+// DereferenceRoot decrements the persistent reference count of the given root.
+// If the root is not referenced anymore, it is deleted from the persistent
+// database and its children are dereferenced recursively. It returns the
+// number of the deleted nodes.
+//
+// The deleted nodes may be still served from the trie node cache, which is
+// harmless since the nodes are looked up by the hashes of their contents.
+func (db *Database) DereferenceRoot(root common.Hash) (int, error) {
+	db.refLock.Lock()
+	defer db.refLock.Unlock()
+
+	if db.leafRefs == nil {
+		return 0, ErrRefCountDisabled
+	}
+	batch := newRefCountBatch(db.diskDB)
+	if err := batch.dereference(root, db.leafRefs); err != nil {
+		return batch.deleted, err
+	}
+	return batch.deleted, batch.write()
+}
+
+// RefCount returns the persistent reference count of the given trie node.
+func (db *Database) RefCount(hash common.Hash) uint64 {
+	return newRefCountBatch(db.diskDB).count(hash)
+}
+
+// refCountBatch accumulates the updates of the reference counts and the
+// deletions of the trie nodes.
+type refCountBatch struct {
+	diskDB  database.DBManager
+	batch   database.Batch
+	counts  map[common.Hash]uint64 // Counts updated in the batch but not written yet
+	deleted int                    // Number of the nodes deleted
+}
+
+func newRefCountBatch(diskDB database.DBManager) *refCountBatch {
+	return &refCountBatch{
+		diskDB: diskDB,
+		batch:  diskDB.NewBatch(database.StateTrieDB),
+		counts: make(map[common.Hash]uint64),
+	}
+}
+
+func (b *refCountBatch) count(hash common.Hash) uint64 {
+	if count, ok := b.counts[hash]; ok {
+		return count
+	}
+	enc, _ := b.diskDB.ReadStateTrieNode(database.TrieNodeRefCountKey(hash))
+	if len(enc) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(enc)
+}
+
+func (b *refCountBatch) setCount(hash common.Hash, count uint64) error {
+	b.counts[hash] = count
+	if count == 0 {
+		if err := b.batch.Delete(database.TrieNodeRefCountKey(hash)); err != nil {
+			return err
+		}
+	} else if err := b.batch.Put(database.TrieNodeRefCountKey(hash), common.Int64ToByteBigEndian(count)); err != nil {
+		return err
+	}
+	if b.batch.ValueSize() > database.IdealBatchSize {
+		return b.write()
+	}
+	return nil
+}
+
+func (b *refCountBatch) write() error {
+	if err := b.batch.Write(); err != nil {
+		return err
+	}
+	b.batch.Reset()
+	b.counts = make(map[common.Hash]uint64)
+	return nil
+}
+
+// node retrieves the stored trie node of the given hash.
+func (b *refCountBatch) node(hash common.Hash) (node, error) {
+	enc, _ := b.diskDB.ReadStateTrieNode(hash[:])
+	if len(enc) == 0 {
+		return nil, &MissingNodeError{NodeHash: hash}
+	}
+	return decodeNode(hash[:], enc)
+}
+
+func (b *refCountBatch) reference(hash common.Hash, leafRefs LeafRefsFunc) error {
+	if hash == emptyRoot || hash == (common.Hash{}) {
+		return nil
+	}
+	count := b.count(hash)
+	if err := b.setCount(hash, count+1); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	n, err := b.node(hash)
+	if err != nil {
+		return err
+	}
+	return forEachRef(n, leafRefs, func(child common.Hash, leafRef bool) error {
+		if leafRef {
+			return b.reference(child, nil)
+		}
+		return b.reference(child, leafRefs)
+	})
+}
+
+func (b *refCountBatch) dereference(hash common.Hash, leafRefs LeafRefsFunc) error {
+	if hash == emptyRoot || hash == (common.Hash{}) {
+		return nil
+	}
+	count := b.count(hash)
+	if count == 0 {
+		logger.Warn("Dereferencing a trie node not referenced", "hash", hash)
+		return nil
+	}
+	if count > 1 {
+		return b.setCount(hash, count-1)
+	}
+	n, err := b.node(hash)
+	if err != nil {
+		return err
+	}
+	if err := b.batch.Delete(hash[:]); err != nil {
+		return err
+	}
+	b.deleted++
+	if err := b.setCount(hash, 0); err != nil {
+		return err
+	}
+	return forEachRef(n, leafRefs, func(child common.Hash, leafRef bool) error {
+		if leafRef {
+			return b.dereference(child, nil)
+		}
+		return b.dereference(child, leafRefs)
+	})
+}
+
+// forEachRef calls fn for the hashes of the nodes referenced by the given node,
+// and for the roots referenced by its leaves if leafRefs is given.
+func forEachRef(n node, leafRefs LeafRefsFunc, fn func(hash common.Hash, leafRef bool) error) error {
+	switch n := n.(type) {
+	case *shortNode:
+		return forEachRef(n.Val, leafRefs, fn)
+	case *fullNode:
+		for _, child := range n.Children {
+			if err := forEachRef(child, leafRefs, fn); err != nil {
+				return err
+			}
+		}
+	case hashNode:
+		return fn(common.BytesToHash(n), false)
+	case valueNode:
+		if leafRefs == nil {
+			return nil
+		}
+		for _, root := range leafRefs(n) {
+			if err := fn(root, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+// Copyright 2022 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package statedb
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/storage/database"
+	"github.com/stretchr/testify/assert"
+)
+
+// refCountTestLeafRefs treats the leaf values of hash length as the roots of
+// the referenced tries, like the storage trie roots in accounts.
+func refCountTestLeafRefs(leaf []byte) []common.Hash {
+	if len(leaf) == common.HashLength {
+		return []common.Hash{common.BytesToHash(leaf)}
+	}
+	return nil
+}
+
+// refCountTestOp updates a key of a subtrie, or of the top trie if sub is negative.
+type refCountTestOp struct {
+	sub   int
+	key   byte
+	value byte // Deletes the key if zero
+}
+
+func randomRefCountTestOps(rnd *rand.Rand) []refCountTestOp {
+	ops := make([]refCountTestOp, 1+rnd.Intn(4))
+	for i := range ops {
+		// The small key and value spaces make the same subtries appear repeatedly.
+		ops[i] = refCountTestOp{sub: rnd.Intn(5) - 1, key: byte(rnd.Intn(16)), value: byte(rnd.Intn(4))}
+	}
+	return ops
+}
+
+// commitRefCountTestBlock applies the operations to the trie of the given root,
+// and commits the updated trie like the state of a block.
+func commitRefCountTestBlock(t *testing.T, db *Database, root common.Hash, ops []refCountTestOp) common.Hash {
+	top, err := NewTrie(root, db)
+	assert.NoError(t, err)
+
+	for _, op := range ops {
+		if op.sub < 0 {
+			key := []byte{0xff, op.key}
+			if op.value == 0 {
+				top.Delete(key)
+			} else {
+				top.Update(key, []byte{op.value})
+			}
+			continue
+		}
+
+		subKey := []byte{byte(op.sub)}
+		subRoot := emptyRoot
+		if enc := top.Get(subKey); enc != nil {
+			subRoot = common.BytesToHash(enc)
+		}
+		sub, err := NewTrie(subRoot, db)
+		assert.NoError(t, err)
+		if op.value == 0 {
+			sub.Delete([]byte{op.key})
+		} else {
+			sub.Update([]byte{op.key}, []byte{op.value})
+		}
+		subRoot, err = sub.Commit(nil)
+		assert.NoError(t, err)
+		if subRoot == emptyRoot {
+			top.Delete(subKey)
+		} else {
+			top.Update(subKey, subRoot[:])
+		}
+	}
+
+	root, err = top.Commit(func(_ [][]byte, _ []byte, leaf []byte, parent common.Hash, _ int) error {
+		for _, ref := range refCountTestLeafRefs(leaf) {
+			db.Reference(ref, parent)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, db.Commit(root, false, 0))
+	return root
+}
+
+// dumpRefCountTestTrie returns the contents of the trie including the subtries.
+func dumpRefCountTestTrie(t *testing.T, db *Database, root common.Hash) map[string]string {
+	contents := make(map[string]string)
+	top, err := NewTrie(root, db)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	it := NewIterator(top.NodeIterator(nil))
+	for it.Next() {
+		contents[string(it.Key)] = string(it.Value)
+		for _, subRoot := range refCountTestLeafRefs(it.Value) {
+			sub, err := NewTrie(subRoot, db)
+			assert.NoError(t, err)
+			subIt := NewIterator(sub.NodeIterator(nil))
+			for subIt.Next() {
+				contents[string(it.Key)+"/"+string(subIt.Key)] = string(subIt.Value)
+			}
+			assert.NoError(t, subIt.Err)
+		}
+	}
+	assert.NoError(t, it.Err)
+	return contents
+}
+
+// expectedRefCounts returns the reference counts of the nodes reachable from
+// the given roots, calculated from the stored nodes of the given database.
+func expectedRefCounts(t *testing.T, diskDB database.DBManager, roots []common.Hash) map[common.Hash]uint64 {
+	counts := make(map[common.Hash]uint64)
+	var count func(hash common.Hash, leafRefs LeafRefsFunc)
+	count = func(hash common.Hash, leafRefs LeafRefsFunc) {
+		if hash == emptyRoot {
+			return
+		}
+		counts[hash]++
+		if counts[hash] > 1 {
+			return
+		}
+		enc, err := diskDB.ReadStateTrieNode(hash[:])
+		assert.NoError(t, err)
+		n, err := decodeNode(hash[:], enc)
+		assert.NoError(t, err)
+		forEachRef(n, leafRefs, func(child common.Hash, leafRef bool) error {
+			if leafRef {
+				count(child, nil)
+			} else {
+				count(child, leafRefs)
+			}
+			return nil
+		})
+	}
+	for _, root := range roots {
+		count(root, refCountTestLeafRefs)
+	}
+	return counts
+}
+
+// checkRefCounts checks that the database stores exactly the nodes reachable
+// from the given roots with the correct reference counts.
+func checkRefCounts(t *testing.T, db *Database, expected map[common.Hash]uint64) {
+	it := db.DiskDB().GetStateTrieDB().NewIterator(nil, nil)
+	defer it.Release()
+
+	stored := make(map[common.Hash]bool)
+	for it.Next() {
+		if len(it.Key()) == common.HashLength {
+			stored[common.BytesToHash(it.Key())] = true
+		}
+	}
+	assert.Equal(t, len(expected), len(stored))
+	for hash, count := range expected {
+		assert.True(t, stored[hash], "missing node %x", hash)
+		assert.Equal(t, count, db.RefCount(hash), "wrong reference count of node %x", hash)
+	}
+}
+
+func TestDatabase_RefCountFuzz(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		rnd := rand.New(rand.NewSource(seed))
+
+		// The reference database keeps all the nodes in the current layout.
+		refDB := NewDatabase(database.NewMemoryDBManager())
+		db := NewDatabase(database.NewMemoryDBManager())
+		db.EnableRefCount(refCountTestLeafRefs)
+
+		var roots []common.Hash
+		refRoot, root, pruned := emptyRoot, emptyRoot, 0
+		for round := 0; round < 3; round++ {
+			for i := 0; i < 30; i++ {
+				ops := randomRefCountTestOps(rnd)
+				refRoot = commitRefCountTestBlock(t, refDB, refRoot, ops)
+				root = commitRefCountTestBlock(t, db, root, ops)
+				assert.Equal(t, refRoot, root)
+				roots = append(roots, root)
+			}
+
+			// Prune the state of the old blocks, and check the remaining state.
+			for to := len(roots) - 10; pruned < to; pruned++ {
+				_, err := db.DereferenceRoot(roots[pruned])
+				assert.NoError(t, err)
+			}
+			for _, root := range roots[pruned:] {
+				assert.Equal(t, dumpRefCountTestTrie(t, refDB, root), dumpRefCountTestTrie(t, db, root))
+			}
+			checkRefCounts(t, db, expectedRefCounts(t, refDB.DiskDB(), roots[pruned:]))
+		}
+	}
+}
+
+func TestDatabase_ReferenceRoot(t *testing.T) {
+	db := NewDatabase(database.NewMemoryDBManager())
+	assert.Equal(t, ErrRefCountDisabled, db.ReferenceRoot(emptyRoot))
+
+	// The trie committed without reference counting can be referenced later.
+	ops := []refCountTestOp{{sub: 0, key: 1, value: 1}, {sub: 1, key: 1, value: 1}, {sub: -1, key: 1, value: 1}}
+	root := commitRefCountTestBlock(t, db, emptyRoot, ops)
+
+	db.EnableRefCount(refCountTestLeafRefs)
+	assert.NoError(t, db.ReferenceRoot(root))
+	expected := expectedRefCounts(t, db.DiskDB(), []common.Hash{root})
+	checkRefCounts(t, db, expected)
+
+	// The identical leaves holding the identical subtries are stored once and
+	// referenced twice.
+	shared := 0
+	for _, count := range expected {
+		if count == 2 {
+			shared++
+		}
+	}
+	assert.Equal(t, 1, shared)
+
+	deleted, err := db.DereferenceRoot(root)
+	assert.NoError(t, err)
+	assert.True(t, deleted > 0)
+	checkRefCounts(t, db, nil)
+
+	assert.IsType(t, &MissingNodeError{}, db.ReferenceRoot(root))
+}
@@ -8,6 +8,7 @@ import (
 	io "io"
 	big "math/big"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	accounts "github.com/klaytn/klaytn/accounts"
@@ -323,6 +324,20 @@ func (mr *MockBlockChainMockRecorder) FastSyncCommitHead(hash interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FastSyncCommitHead", reflect.TypeOf((*MockBlockChain)(nil).FastSyncCommitHead), hash)
 }
 
+// FreezeWrites mocks base method.
+func (m *MockBlockChain) FreezeWrites(arg0 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FreezeWrites", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FreezeWrites indicates an expected call of FreezeWrites.
+func (mr *MockBlockChainMockRecorder) FreezeWrites(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreezeWrites", reflect.TypeOf((*MockBlockChain)(nil).FreezeWrites), arg0)
+}
+
 // Genesis mocks base method.
 func (m *MockBlockChain) Genesis() *types.Block {
 	m.ctrl.T.Helper()
@@ -939,6 +954,34 @@ func (mr *MockBlockChainMockRecorder) StartContractWarmUp(contractAddr interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartContractWarmUp", reflect.TypeOf((*MockBlockChain)(nil).StartContractWarmUp), contractAddr)
 }
 
+// StartDBSnapshot mocks base method.
+func (m *MockBlockChain) StartDBSnapshot(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartDBSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartDBSnapshot indicates an expected call of StartDBSnapshot.
+func (mr *MockBlockChainMockRecorder) StartDBSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartDBSnapshot", reflect.TypeOf((*MockBlockChain)(nil).StartDBSnapshot), arg0, arg1)
+}
+
+// StartPruningStateTries mocks base method.
+func (m *MockBlockChain) StartPruningStateTries(arg0 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartPruningStateTries", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartPruningStateTries indicates an expected call of StartPruningStateTries.
+func (mr *MockBlockChainMockRecorder) StartPruningStateTries(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartPruningStateTries", reflect.TypeOf((*MockBlockChain)(nil).StartPruningStateTries), arg0)
+}
+
 // StartStateMigration mocks base method.
 func (m *MockBlockChain) StartStateMigration(arg0 uint64, arg1 common.Hash) error {
 	m.ctrl.T.Helper()
@@ -1171,6 +1214,20 @@ func (mr *MockBlockChainMockRecorder) SubscribeRemovedLogsEvent(ch interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeRemovedLogsEvent", reflect.TypeOf((*MockBlockChain)(nil).SubscribeRemovedLogsEvent), ch)
 }
 
+// ThawWrites mocks base method.
+func (m *MockBlockChain) ThawWrites() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ThawWrites")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ThawWrites indicates an expected call of ThawWrites.
+func (mr *MockBlockChainMockRecorder) ThawWrites() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ThawWrites", reflect.TypeOf((*MockBlockChain)(nil).ThawWrites))
+}
+
 // TrieNode mocks base method.
 func (m *MockBlockChain) TrieNode(hash common.Hash) ([]byte, error) {
 	m.ctrl.T.Helper()
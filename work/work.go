@@ -25,6 +25,7 @@ import (
 	"io"
 	"math/big"
 	"sync/atomic"
+	"time"
 
 	"github.com/klaytn/klaytn/accounts"
 	"github.com/klaytn/klaytn/blockchain"
@@ -303,6 +304,14 @@ type BlockChain interface {
 	StopStateMigration() error
 	StateMigrationStatus() (bool, uint64, int, int, int, float64, error)
 
+	// Prune state tries
+	StartPruningStateTries(to uint64) error
+
+	// Database snapshot
+	StartDBSnapshot(dir, url string) error
+	FreezeWrites(timeout time.Duration) error
+	ThawWrites() error
+
 	// Warm up
 	StartWarmUp() error
 	StartContractWarmUp(contractAddr common.Address) error